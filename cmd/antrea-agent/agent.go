@@ -17,20 +17,35 @@ package main
 import (
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent"
 	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver"
+	"github.com/vmware-tanzu/antrea/pkg/agent/capacity"
 	"github.com/vmware-tanzu/antrea/pkg/agent/cniserver"
+	"github.com/vmware-tanzu/antrea/pkg/agent/cniserver/ipam"
 	_ "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/ipam"
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/egress"
 	"github.com/vmware-tanzu/antrea/pkg/agent/controller/networkpolicy"
 	"github.com/vmware-tanzu/antrea/pkg/agent/controller/noderoute"
 	"github.com/vmware-tanzu/antrea/pkg/agent/controller/traceflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/dhcp"
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/flowrecords"
@@ -41,6 +56,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/querier"
 	"github.com/vmware-tanzu/antrea/pkg/agent/route"
 	"github.com/vmware-tanzu/antrea/pkg/agent/stats"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
 	"github.com/vmware-tanzu/antrea/pkg/features"
@@ -49,6 +65,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/monitor"
 	ofconfig "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
 	"github.com/vmware-tanzu/antrea/pkg/signals"
 	"github.com/vmware-tanzu/antrea/pkg/version"
 )
@@ -58,6 +75,22 @@ import (
 // https://github.com/kubernetes/kubernetes/blob/release-1.17/pkg/controller/apis/config/v1alpha1/defaults.go#L120
 const informerDefaultResync = 12 * time.Hour
 
+// flowRestoreCompleteTimeout is the maximum time to wait for the flows of the initial Node routes, NetworkPolicies and
+// Services to be installed before removing flow-restore-wait, which blocks new connections after an OVS restart.
+const flowRestoreCompleteTimeout = 2 * time.Minute
+
+// flowExportBaselineFile is where the state of the exported flow records is saved, so that the connections which
+// survive a restart of antrea-agent are exported with the right delta counts. It is on the host so that it persists
+// across restarts of the antrea-agent container, and is cleared with conntrack when the Node reboots.
+const flowExportBaselineFile = "/var/run/antrea/flow-exporter/baseline.json"
+
+const (
+	// antreaCNIConfFile is the CNI configuration file mounted from the antrea-config ConfigMap.
+	antreaCNIConfFile = "/etc/antrea/antrea-cni.conflist"
+	// hostCNIConfFile is where the CNI configuration file is installed for the container runtime.
+	hostCNIConfFile = "/host/etc/cni/net.d/10-antrea.conflist"
+)
+
 // run starts Antrea agent with the given options and waits for termination signal.
 func run(o *Options) error {
 	klog.Infof("Starting Antrea agent (version %s)", version.GetFullVersion())
@@ -67,6 +100,9 @@ func run(o *Options) error {
 		return fmt.Errorf("error creating K8s clients: %v", err)
 	}
 	informerFactory := informers.NewSharedInformerFactory(k8sClient, informerDefaultResync)
+	// serviceInformerFactory is used for the Services, Endpoints and EndpointSlices consumed by AntreaProxy, the
+	// objects it doesn't handle are filtered out by the apiserver and never cached.
+	serviceInformerFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerDefaultResync, informers.WithTweakListOptions(proxy.TweakListOptions))
 	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, informerDefaultResync)
 	traceflowInformer := crdInformerFactory.Ops().V1alpha1().Traceflows()
 
@@ -89,12 +125,17 @@ func run(o *Options) error {
 
 	ovsBridgeClient := ovsconfig.NewOVSBridge(o.config.OVSBridge, o.config.OVSDatapathType, ovsdbConnection)
 	ovsBridgeMgmtAddr := ofconfig.GetMgmtAddress(o.config.OVSRunDir, o.config.OVSBridge)
+	// The connections denied by NetworkPolicies are reported by the packet-in messages sent from the drop flows, which
+	// are only received by antrea-agent.
+	enableDenyFlowExport := features.DefaultFeatureGate.Enabled(features.FlowExporter) &&
+		o.config.FlowExportDeniedConnections && !o.config.FlowExporterStandalone
 	ofClient := openflow.NewClient(o.config.OVSBridge, ovsBridgeMgmtAddr,
 		features.DefaultFeatureGate.Enabled(features.AntreaProxy),
-		features.DefaultFeatureGate.Enabled(features.AntreaPolicy))
+		features.DefaultFeatureGate.Enabled(features.AntreaPolicy),
+		enableDenyFlowExport)
 
 	// statsCollector collects stats and reports to the antrea-controller periodically. For now it's only used for
-	// NetworkPolicy stats.
+	// NetworkPolicy stats and the summaries of the connections of the local Pods.
 	var statsCollector *stats.Collector
 	if features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
 		statsCollector = stats.NewCollector(antreaClientProvider, ofClient)
@@ -103,11 +144,16 @@ func run(o *Options) error {
 	_, serviceCIDRNet, _ := net.ParseCIDR(o.config.ServiceCIDR)
 	_, encapMode := config.GetTrafficEncapModeFromStr(o.config.TrafficEncapMode)
 	networkConfig := &config.NetworkConfig{
-		TunnelType:        ovsconfig.TunnelType(o.config.TunnelType),
-		TrafficEncapMode:  encapMode,
-		EnableIPSecTunnel: o.config.EnableIPSecTunnel}
-
-	routeClient, err := route.NewClient(serviceCIDRNet, encapMode)
+		TunnelType:          ovsconfig.TunnelType(o.config.TunnelType),
+		TrafficEncapMode:    encapMode,
+		EnableIPSecTunnel:   o.config.EnableIPSecTunnel,
+		HybridNoEncapNodeOS: o.config.HybridNoEncapNodeOS,
+		TunnelPort:          int32(o.config.TunnelPort),
+		TunnelTTL:           o.config.TunnelTTL,
+		TunnelClearDF:       o.config.TunnelClearDF,
+		TunnelInheritTOS:    o.config.TunnelInheritTOS}
+
+	routeClient, err := route.NewClient(serviceCIDRNet, encapMode, o.config.AntreaProxy.NodePort, o.config.AntreaProxy.HostNetwork, o.config.NoEncapNeighborProxy)
 	if err != nil {
 		return fmt.Errorf("error creating route client: %v", err)
 	}
@@ -127,13 +173,25 @@ func run(o *Options) error {
 		o.config.DefaultMTU,
 		serviceCIDRNet,
 		networkConfig,
-		features.DefaultFeatureGate.Enabled(features.AntreaProxy))
+		features.DefaultFeatureGate.Enabled(features.AntreaProxy),
+		o.config.FailOnMissingOVSFeatures)
 	err = agentInitializer.Initialize()
 	if err != nil {
 		return fmt.Errorf("error initializing agent: %v", err)
 	}
 	nodeConfig := agentInitializer.GetNodeConfig()
 
+	// eventRecorder is shared by all the modules, so that the datapath programming failures are reported as Events on
+	// the affected objects with the same source.
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k8sClient.CoreV1().Events("")})
+	defer eventBroadcaster.Shutdown()
+	eventRecorder := events.NewRecorder(k8sClient, eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "antrea-agent", Host: nodeConfig.Name}))
+
+	// flowRestoreCompleteWait is used to wait for the flows of the initial Node routes, NetworkPolicies and Services
+	// to be installed before removing the flow-restore-wait config.
+	flowRestoreCompleteWait := &sync.WaitGroup{}
+
 	nodeRouteController := noderoute.NewNodeRouteController(
 		k8sClient,
 		informerFactory,
@@ -142,34 +200,92 @@ func run(o *Options) error {
 		routeClient,
 		ifaceStore,
 		networkConfig,
-		nodeConfig)
+		nodeConfig,
+		flowRestoreCompleteWait)
 
+	// Traceflow relies on Geneve options to tag its packets across Nodes.
+	enableTraceflow := features.DefaultFeatureGate.Enabled(features.Traceflow) &&
+		nodeConfig.OVSFeatureSupported(config.OVSFeatureGeneveOptions)
 	var traceflowController *traceflow.Controller
-	if features.DefaultFeatureGate.Enabled(features.Traceflow) {
+	if enableTraceflow {
+		// Traceflow looks up the destination Service by name, including the Services
+		// filtered out of serviceInformerFactory, so it uses the unfiltered informerFactory.
 		traceflowController = traceflow.NewTraceflowController(
 			k8sClient,
 			informerFactory,
+			informerFactory.Core().V1().Nodes(),
 			crdClient,
 			traceflowInformer,
 			ofClient,
 			ovsBridgeClient,
+			routeClient,
 			ifaceStore,
 			networkConfig,
 			nodeConfig,
 			serviceCIDRNet)
 	}
 
+	// localPodInformerFactory only watches the Pods running on this Node.
+	localPodInformerFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerDefaultResync, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeConfig.Name).String()
+	}))
+	var egressController *egress.EgressController
+	if features.DefaultFeatureGate.Enabled(features.Egress) {
+		ipAssigner, err := egress.NewIPAssigner(nodeConfig.NodeIPAddr.IP)
+		if err != nil {
+			return fmt.Errorf("error creating IPAssigner for Egress: %v", err)
+		}
+		egressController = egress.NewEgressController(
+			ofClient,
+			routeClient,
+			ipAssigner,
+			nodeConfig.Name,
+			k8sClient,
+			crdClient,
+			crdInformerFactory.Core().V1alpha1().Egresses(),
+			localPodInformerFactory.Core().V1().Pods(),
+			informerFactory.Core().V1().Namespaces(),
+			informerFactory.Core().V1().Nodes(),
+			crdInformerFactory.Core().V1alpha1().ExternalIPPools(),
+			eventRecorder,
+			o.config.EgressSNATPortsPerPod)
+	}
+
+	var antreaIPAM *ipam.AntreaIPAM
+	if features.DefaultFeatureGate.Enabled(features.AntreaIPAM) {
+		antreaIPAM = ipam.RegisterAntreaIPAM(k8sClient, crdClient, crdInformerFactory.Core().V1alpha1().IPPools())
+	}
+	// The DHCP responder serves the IPs allocated by AntreaIPAM to the Pods which insist on DHCP-based configuration.
+	var dhcpResponder *dhcp.Responder
+	enableDHCPResponder := features.DefaultFeatureGate.Enabled(features.AntreaIPAM) && o.config.AntreaIPAMDHCPResponder
+	if enableDHCPResponder {
+		dhcpResponder = dhcp.NewResponder(ofClient, ifaceStore, nodeConfig.GatewayConfig.MAC, crdInformerFactory.Core().V1alpha1().IPPools())
+	}
+
 	// podUpdates is a channel for receiving Pod updates from CNIServer and
 	// notifying NetworkPolicyController to reconcile rules related to the
 	// updated Pods.
 	podUpdates := make(chan v1beta1.PodReference, 100)
-	networkPolicyController := networkpolicy.NewNetworkPolicyController(
+	// dnsInformerFactory only watches the kube-dns Service and its Endpoints, which are used to validate the source of
+	// the DNS responses used to resolve the FQDNs of Antrea-native policy rules.
+	dnsInformerFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerDefaultResync, informers.WithNamespace("kube-system"), informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", "kube-dns").String()
+	}))
+	networkPolicyController, err := networkpolicy.NewNetworkPolicyController(
 		antreaClientProvider,
 		ofClient,
 		ifaceStore,
 		nodeConfig.Name,
 		podUpdates,
-		features.DefaultFeatureGate.Enabled(features.AntreaPolicy))
+		features.DefaultFeatureGate.Enabled(features.AntreaPolicy),
+		o.auditLogConfig,
+		eventRecorder,
+		dnsInformerFactory.Core().V1().Services(),
+		dnsInformerFactory.Core().V1().Endpoints(),
+		flowRestoreCompleteWait)
+	if err != nil {
+		return fmt.Errorf("error creating new NetworkPolicy controller: %v", err)
+	}
 
 	isChaining := false
 	if networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() {
@@ -177,7 +293,22 @@ func run(o *Options) error {
 	}
 	var proxier proxy.Proxier
 	if features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
-		proxier = proxy.New(nodeConfig.Name, informerFactory, ofClient)
+		var nodePortAddresses []net.IP
+		if o.config.AntreaProxy.NodePort {
+			var cidrs []*net.IPNet
+			for _, cidr := range o.config.AntreaProxy.NodePortAddresses {
+				_, ipNet, _ := net.ParseCIDR(cidr)
+				cidrs = append(cidrs, ipNet)
+			}
+			nodePortAddresses, err = util.GetLocalIPv4Addrs(cidrs)
+			if err != nil {
+				return fmt.Errorf("error getting the Node addresses for NodePort Services: %v", err)
+			}
+			klog.Infof("NodePort Services are served on the Node addresses %v", nodePortAddresses)
+		}
+		loadBalancerDSR := o.config.AntreaProxy.LoadBalancerMode == loadBalancerModeDSR
+		endpointSliceEnabled := features.DefaultFeatureGate.Enabled(features.EndpointSlice)
+		proxier = proxy.New(nodeConfig.Name, informerFactory, serviceInformerFactory, ofClient, routeClient, nodePortAddresses, loadBalancerDSR, o.config.AntreaProxy.LoadBalancerLocalFastPath, endpointSliceEnabled, o.endpointDrainTimeout, eventRecorder, flowRestoreCompleteWait)
 	}
 	cniServer := cniserver.New(
 		o.config.CNISocket,
@@ -186,18 +317,13 @@ func run(o *Options) error {
 		k8sClient,
 		podUpdates,
 		isChaining,
-		routeClient)
+		routeClient,
+		eventRecorder)
 	err = cniServer.Initialize(ovsBridgeClient, ofClient, ifaceStore, o.config.OVSDatapathType)
 	if err != nil {
 		return fmt.Errorf("error initializing CNI server: %v", err)
 	}
 
-	// TODO: we should call this after installing flows for initial node routes
-	//  and initial NetworkPolicies so that no packets will be mishandled.
-	if err := agentInitializer.FlowRestoreComplete(); err != nil {
-		return err
-	}
-
 	if err := antreaClientProvider.RunOnce(); err != nil {
 		return err
 	}
@@ -211,6 +337,9 @@ func run(o *Options) error {
 	go cniServer.Run(stopCh)
 
 	informerFactory.Start(stopCh)
+	serviceInformerFactory.Start(stopCh)
+	localPodInformerFactory.Start(stopCh)
+	dnsInformerFactory.Start(stopCh)
 	crdInformerFactory.Start(stopCh)
 
 	go antreaClientProvider.Run(stopCh)
@@ -219,14 +348,47 @@ func run(o *Options) error {
 
 	go networkPolicyController.Run(stopCh)
 
-	if features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
-		go statsCollector.Run(stopCh)
+	// Remove flow-restore-wait after installing the flows for the initial Node routes, NetworkPolicies and Services,
+	// so that no packets will be mishandled after an OVS restart.
+	go agentInitializer.WaitForFlowRestore(flowRestoreCompleteWait, flowRestoreCompleteTimeout, stopCh)
+
+	// Publish the CNI configuration file only after the initial Node routes, NetworkPolicies and Services have been
+	// realized, so that no Pod is attached to the Node before its NetworkPolicies are enforced.
+	if o.config.CNIReadinessGate {
+		go agent.InstallCNIConfAfterSync(flowRestoreCompleteWait, antreaCNIConfFile, hostCNIConfFile, stopCh)
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.Egress) {
+		go egressController.Run(stopCh)
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.AntreaIPAM) {
+		go antreaIPAM.Run(stopCh)
+	}
+
+	if enableDHCPResponder {
+		go dhcpResponder.Run(stopCh)
 	}
 
-	if features.DefaultFeatureGate.Enabled(features.Traceflow) {
+	if enableTraceflow {
 		go traceflowController.Run(stopCh)
 	}
 
+	if features.DefaultFeatureGate.Enabled(features.AntreaProxy) && (o.config.AntreaProxy.NodePort || o.config.AntreaProxy.HostNetwork) {
+		go proxy.ReportKubeProxyCoexistence(nodeConfig.Name, eventRecorder.EventRecorder(), o.config.AntreaProxy.NodePort, o.config.AntreaProxy.HostNetwork)
+	}
+	capacityMonitor := capacity.NewMonitor(
+		nodeConfig.Name,
+		ofClient,
+		ovsctl.NewClient(nodeConfig.OVSBridge),
+		eventRecorder.EventRecorder(),
+		capacity.Thresholds{
+			ConntrackUsagePercent: o.config.NodeCapacityWarningThresholds.ConntrackUsagePercent,
+			OVSFlowCount:          o.config.NodeCapacityWarningThresholds.OVSFlowCount,
+			OVSGroupCount:         o.config.NodeCapacityWarningThresholds.OVSGroupCount,
+		})
+	go capacityMonitor.Run(stopCh)
+
 	agentQuerier := querier.NewAgentQuerier(
 		nodeConfig,
 		ifaceStore,
@@ -234,9 +396,10 @@ func run(o *Options) error {
 		ofClient,
 		ovsBridgeClient,
 		networkPolicyController,
+		capacityMonitor,
 		o.config.APIPort)
 
-	agentMonitor := monitor.NewAgentMonitor(crdClient, agentQuerier)
+	agentMonitor := monitor.NewAgentMonitor(crdClient, agentQuerier, o.agentInfoReportInterval)
 
 	go agentMonitor.Run(stopCh)
 
@@ -244,9 +407,92 @@ func run(o *Options) error {
 		go proxier.Run(stopCh)
 	}
 
+	// Collect OVS datapath stats periodically as metrics if EnablePrometheusMetrics is set.
+	if o.config.EnablePrometheusMetrics {
+		go metrics.NewOVSDatapathStatsCollector(agentQuerier.GetOVSCtlClient()).Run(stopCh)
+	}
+
+	// Initialize flow exporter to start go routines to poll conntrack flows and export IPFIX flow records, unless it
+	// runs in a separate container.
+	var flowRecordsQuerier querier.FlowRecordsQuerier
+	if features.DefaultFeatureGate.Enabled(features.FlowExporter) && !o.config.FlowExporterStandalone {
+		// The labels of the local Pods are only needed when connections are filtered by a Pod label selector.
+		var podLister corelisters.PodLister
+		if o.flowExportPodSelector != nil {
+			podInformer := coreinformers.NewFilteredPodInformer(k8sClient, metav1.NamespaceAll, informerDefaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeConfig.Name).String()
+			})
+			podLister = corelisters.NewPodLister(podInformer.GetIndexer())
+			go podInformer.Run(stopCh)
+		}
+		exportFilter := connections.NewExportFilter(
+			o.config.FlowSamplingRate,
+			o.config.FlowExportFilter.Namespaces,
+			o.flowExportPodSelector,
+			podLister,
+			o.flowExportCIDRs,
+			o.config.FlowExportFilter.PodFlowsOnly,
+			o.interNodeFlowExporter)
+		var rttDumper connections.TCPRTTDumper
+		if o.config.FlowRTT {
+			rttDumper = connections.NewTCPRTTDumper()
+		}
+		// The connections from local Pods which leave the cluster are SNAT'd by their Egress when the Egress feature
+		// is enabled, and masqueraded by the Node otherwise, except in networkPolicyOnly mode where SNAT is managed by
+		// the primary CNI.
+		var egressQuerier connections.EgressQuerier
+		if egressController != nil {
+			egressQuerier = egressController
+		} else if !networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() {
+			egressQuerier = connections.NewNodeSNATQuerier(nodeConfig.Name, nodeConfig.NodeIPAddr.IP)
+		}
+		connStore := connections.NewConnectionStore(
+			connections.InitializeConnTrackDumper(nodeConfig, serviceCIDRNet, agentQuerier.GetOVSCtlClient(), o.config.OVSDatapathType, o.config.FlowExportFilter.HostNetworkFlows),
+			ifaceStore,
+			serviceCIDRNet,
+			proxier,
+			nodeConfig.Name,
+			nodeRouteController,
+			egressQuerier,
+			o.pollInterval,
+			o.resyncInterval,
+			o.config.FlowPollCPUBudget,
+			exportFilter,
+			rttDumper)
+		pollDone := make(chan struct{})
+		go connStore.Run(stopCh, pollDone)
+		if statsCollector != nil {
+			// The connections of the local Pods are summarized in the stats reported to the antrea-controller.
+			statsCollector.SetConnectionStore(connStore)
+		}
+
+		// Connections denied by NetworkPolicies are reported by the packet-in messages sent from the drop flows.
+		denyConnStore := connections.NewDenyConnectionStore(ifaceStore, nodeConfig.Name, nodeRouteController, exportFilter)
+		if enableDenyFlowExport {
+			ofClient.RegisterPacketInHandler("denyflow", denyConnStore)
+		}
+
+		flowExporter := exporter.NewFlowExporter(
+			flowrecords.NewFlowRecords(connStore, o.activeFlowTimeout, o.idleFlowTimeout),
+			flowrecords.NewFlowRecords(denyConnStore, o.activeFlowTimeout, o.idleFlowTimeout),
+			o.flowCollectorTLS,
+			o.flowClickHouse,
+			o.flowArchive,
+			flowExportBaselineFile)
+		flowRecordsQuerier = flowExporter
+		go flowExporter.RunClickHouseWriter(stopCh)
+		go flowExporter.RunArchiveWriter(stopCh)
+		go wait.Until(func() { flowExporter.Export(o.flowCollector, stopCh, pollDone) }, 0, stopCh)
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
+		go statsCollector.Run(stopCh)
+	}
+
 	apiServer, err := apiserver.New(
 		agentQuerier,
 		networkPolicyController,
+		flowRecordsQuerier,
 		o.config.APIPort,
 		o.config.EnablePrometheusMetrics,
 		o.config.ClientConnection.Kubeconfig)
@@ -255,25 +501,16 @@ func run(o *Options) error {
 	}
 	go apiServer.Run(stopCh)
 
-	if features.DefaultFeatureGate.Enabled(features.Traceflow) {
-		go ofClient.StartPacketInHandler(stopCh)
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		// The DNS responses sent to local Pods are used to resolve the FQDNs of Antrea-native policy rules, and the
+		// packets matched by the rules with logging enabled are audit logged.
+		ofClient.RegisterPacketInHandler("networkpolicy", networkPolicyController)
 	}
 
-	// Initialize flow exporter to start go routines to poll conntrack flows and export IPFIX flow records
-	if features.DefaultFeatureGate.Enabled(features.FlowExporter) {
-		connStore := connections.NewConnectionStore(
-			connections.InitializeConnTrackDumper(nodeConfig, serviceCIDRNet, agentQuerier.GetOVSCtlClient(), o.config.OVSDatapathType),
-			ifaceStore,
-			serviceCIDRNet,
-			proxier,
-			o.pollInterval)
-		pollDone := make(chan struct{})
-		go connStore.Run(stopCh, pollDone)
-
-		flowExporter := exporter.NewFlowExporter(
-			flowrecords.NewFlowRecords(connStore),
-			o.config.FlowExportFrequency)
-		go wait.Until(func() { flowExporter.Export(o.flowCollector, stopCh, pollDone) }, 0, stopCh)
+	// The PacketIn handlers must be registered before the packet-in messages are processed.
+	if enableTraceflow || features.DefaultFeatureGate.Enabled(features.FlowExporter) ||
+		features.DefaultFeatureGate.Enabled(features.AntreaPolicy) || enableDHCPResponder {
+		go ofClient.StartPacketInHandler(stopCh)
 	}
 
 	<-stopCh
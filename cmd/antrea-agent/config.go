@@ -39,6 +39,12 @@ type AgentConfig struct {
 	// 'system' is the default value and corresponds to the kernel datapath. Use 'netdev' to run
 	// OVS in userspace mode. Userspace mode requires the tun device driver to be available.
 	OVSDatapathType string `yaml:"ovsDatapathType,omitempty"`
+	// Fail the startup of antrea-agent if an optional feature of the OVS datapath, e.g. meters or Geneve options,
+	// is not supported by the Node. By default, an alternative flow design is used or the
+	// functionality depending on the feature is disabled, and the unsupported features are reported in the
+	// OVSFeaturesSupported condition of the AntreaAgentInfo. The features required by Antrea always fail the startup
+	// when they are not supported. Defaults to false.
+	FailOnMissingOVSFeatures bool `yaml:"failOnMissingOVSFeatures,omitempty"`
 	// Runtime data directory used by Open vSwitch.
 	// Default value:
 	// - On Linux platform: /var/run/openvswitch
@@ -54,6 +60,21 @@ type AgentConfig struct {
 	// - gre
 	// - stt
 	TunnelType string `yaml:"tunnelType,omitempty"`
+	// Destination UDP port of the Geneve or VXLAN tunnels, for environments which firewall the default ports. It must
+	// be the same on all Nodes. Defaults to 0, which means 6081 for Geneve and 4789 for VXLAN.
+	TunnelPort int `yaml:"tunnelPort,omitempty"`
+	// TTL of the outer IP header of the tunneled packets: a number between 1 and 255, or "inherit" to copy the TTL of
+	// the inner packet. It can be overridden for a Node with the "tunnel.antrea.tanzu.vmware.com/ttl" annotation.
+	// Defaults to "", which means that the OVS default (64) is used.
+	TunnelTTL string `yaml:"tunnelTTL,omitempty"`
+	// Clear the DF bit of the outer IP header of the tunneled packets, so that they can be fragmented by the underlay
+	// network. It can be overridden for a Node with the "tunnel.antrea.tanzu.vmware.com/clear-df" annotation.
+	// Defaults to false, which means that the DF bit is set.
+	TunnelClearDF bool `yaml:"tunnelClearDF,omitempty"`
+	// Copy the ToS byte of the inner packet, i.e. the DSCP and ECN bits, to the outer IP header of the tunneled
+	// packets, so that ECN is propagated. It can be overridden for a Node with the
+	// "tunnel.antrea.tanzu.vmware.com/inherit-tos" annotation. Defaults to false.
+	TunnelInheritTOS bool `yaml:"tunnelInheritTOS,omitempty"`
 	// Default MTU to use for the host gateway interface and the network interface of each
 	// Pod. If omitted, antrea-agent will default this value to 1450 to accommodate for tunnel
 	// encapsulate overhead.
@@ -65,9 +86,11 @@ type AgentConfig struct {
 	HostProcPathPrefix string `yaml:"hostProcPathPrefix,omitempty"`
 	// ClusterIP CIDR range for Services. It's required when AntreaProxy is not enabled, and should be
 	// set to the same value as the one specified by --service-cluster-ip-range for kube-apiserver. When
-	// AntreaProxy is enabled, this parameter is not needed and will be ignored if provided.
+	// AntreaProxy is enabled, this parameter is only used by the hostNetwork option of AntreaProxy.
 	// Default is 10.96.0.0/12
 	ServiceCIDR string `yaml:"serviceCIDR,omitempty"`
+	// Provide the configuration of AntreaProxy. It is only used when the AntreaProxy feature is enabled.
+	AntreaProxy AntreaProxyConfig `yaml:"antreaProxy,omitempty"`
 	// Whether or not to enable IPSec (ESP) encryption for Pod traffic across Nodes. IPSec encryption
 	// is supported only for the GRE tunnel type. Antrea uses Preshared Key (PSK) for IKE
 	// authentication. When IPSec tunnel is enabled, the PSK value must be passed to Antrea Agent
@@ -81,24 +104,272 @@ type AgentConfig struct {
 	// Hybrid: noEncap if worker Nodes on same subnet, otherwise encap.
 	// NetworkPolicyOnly: Antrea enforces NetworkPolicy only, and utilizes CNI chaining and delegates Pod IPAM and connectivity to primary CNI.
 	TrafficEncapMode string `yaml:"trafficEncapMode,omitempty"`
+	// The OS of the Nodes which use host-gateway routing instead of encapsulation in Hybrid mode, which
+	// can be linux or windows. Inter-node Pod traffic is not encapsulated if either Node runs this OS, and
+	// is always encapsulated otherwise. Nodes using host-gateway routing must be on the same subnet.
+	// This is useful for clusters where encapsulation is problematic on Windows Nodes.
+	// Defaults to "", which means that Hybrid mode decides encapsulation based on the Node subnets only.
+	HybridNoEncapNodeOS string `yaml:"hybridNoEncapNodeOS,omitempty"`
+	// Answer the ARP requests and IPv6 neighbor solicitations received from the Node network for the IPs of the local
+	// Pods, with a proxy neighbor entry per local Pod IP on the Node transport interface, e.g. when Pods are on
+	// routable VLANs. Only the IPs of the local Pods are answered. It may only be enabled in noEncap and hybrid modes,
+	// and is not supported on Windows Nodes.
+	// Defaults to false.
+	NoEncapNeighborProxy bool `yaml:"noEncapNeighborProxy,omitempty"`
+	// Install the CNI configuration file only after the flows of the initial Node routes, NetworkPolicies and
+	// Services have been installed. Until then, the container runtime reports the Node network as not ready and no
+	// Pod is attached to the Node, so that Pods never run without their NetworkPolicies enforced, e.g. after a Node
+	// reboot. Pod attachment on the Node is blocked until antrea-agent can sync with antrea-controller. It is not
+	// supported in networkPolicyOnly mode and on Windows Nodes.
+	// Defaults to false.
+	CNIReadinessGate bool `yaml:"cniReadinessGate,omitempty"`
+	// Answer the DHCP requests of the Pods whose IPs are allocated from an IPPool by AntreaIPAM with their allocated
+	// IP, and the subnet and gateway of their IPPool, for the Pods and VMs which insist on DHCP-based configuration.
+	// It requires the AntreaIPAM feature.
+	// Defaults to false.
+	AntreaIPAMDHCPResponder bool `yaml:"antreaIPAMDHCPResponder,omitempty"`
 	// APIPort is the port for the antrea-agent APIServer to serve on.
 	// Defaults to 10350.
 	APIPort int `yaml:"apiPort,omitempty"`
 	// Enable metrics exposure via Prometheus. Initializes Prometheus metrics listener
 	// Defaults to false.
 	EnablePrometheusMetrics bool `yaml:"enablePrometheusMetrics,omitempty"`
-	// Provide the flow collector address as string with format <IP>:<port>[:<proto>], where proto is tcp or udp. This also
-	// enables the flow exporter that sends IPFIX flow records of conntrack flows on OVS bridge. If no L4 transport proto
-	// is given, we consider tcp as default.
+	// Provide the flow collector address as string with format <IP>:<port>[:<proto>], where proto is tcp, udp or tls.
+	// This also enables the flow exporter that sends IPFIX flow records of conntrack flows on OVS bridge. If no L4
+	// transport proto is given, we consider tcp as default. With tls, flow records are sent over TCP with mutual TLS,
+	// using the certificates provided in flowCollectorTLS.
 	// Defaults to "".
 	FlowCollectorAddr string `yaml:"flowCollectorAddr,omitempty"`
+	// Provide the certificates used to send flow records to the flow collector over mutual TLS. Only used when the
+	// proto of flowCollectorAddr is tls.
+	FlowCollectorTLS FlowCollectorTLSConfig `yaml:"flowCollectorTLS,omitempty"`
 	// Provide flow poll interval in format "0s". This determines how often flow exporter dumps connections in conntrack module.
 	// Flow poll interval should be greater than or equal to 1s(one second).
 	// Defaults to "5s". Follow the time units of duration.
 	FlowPollInterval string `yaml:"flowPollInterval,omitempty"`
-	// Provide flow export frequency, which is the number of poll cycles elapsed before flow exporter exports flow records to
-	// the flow collector.
-	// Flow export frequency should be greater than or equal to 1.
-	// Defaults to "12".
+	// Provide the interval at which the conntrack table is fully dumped to resync the connections of the flow exporter,
+	// when they are tracked with conntrack events instead of being dumped at every poll cycle. This is only supported
+	// on Linux with the OVS system datapath. The stats of active connections are only refreshed when the conntrack
+	// table is dumped, while closed connections are reported with their final stats. "0s" disables conntrack events.
+	// Defaults to "60s". Follow the time units of duration.
+	FlowResyncInterval string `yaml:"flowResyncInterval,omitempty"`
+	// Provide the CPU budget of the conntrack polls, as the percentage of the poll interval a poll may take. When polls
+	// exceed it on busy Nodes, the poll interval is increased up to 8 times flowPollInterval, after which only 1 out of
+	// up to 16 new connections is added at each poll, so the exported flows are less accurate. The adjustments are
+	// reported with Prometheus metrics, and are reverted when polls take less than half of the budget. This only applies
+	// when the conntrack table is dumped at every poll cycle. Defaults to 0, which disables the budget.
+	FlowPollCPUBudget uint32 `yaml:"flowPollCPUBudget,omitempty"`
+	// Deprecated. Use activeFlowExportTimeout instead. Flow export frequency was the number of poll cycles elapsed
+	// before the flow exporter exported flow records to the flow collector. When it is set and activeFlowExportTimeout
+	// is not, the active flow export timeout is set to flowExportFrequency times flowPollInterval.
 	FlowExportFrequency uint `yaml:"flowExportFrequency,omitempty"`
+	// Provide the active flow export timeout, which is the timeout after which a flow record is sent to the collector
+	// for active flows. Thus, for flows with a continuous stream of packets, a flow record will be exported to the
+	// collector once the elapsed time since the last export event is equal to the value of this timeout.
+	// Defaults to "60s". Follow the time units of duration.
+	ActiveFlowExportTimeout string `yaml:"activeFlowExportTimeout,omitempty"`
+	// Provide the idle flow export timeout, which is the timeout after which a flow record is sent to the collector
+	// for idle flows. A flow is considered idle if no packet matching this flow has been observed since the last
+	// export event. The flow record of an idle flow whose connection is no longer present in conntrack table is
+	// expired after it is exported.
+	// Defaults to "15s". Follow the time units of duration.
+	IdleFlowExportTimeout string `yaml:"idleFlowExportTimeout,omitempty"`
+	// Provide the flow sampling rate N, to export the flow records of 1 out of every N connections. Connections are
+	// sampled based on their 5-tuple. Defaults to 0, which means that all connections are exported.
+	FlowSamplingRate uint32 `yaml:"flowSamplingRate,omitempty"`
+	// Enable collecting the smoothed RTT measured by the TCP stack of local Pods for their TCP connections, which is
+	// exported in flow records. This is only supported for IPv4 connections on Linux. Defaults to false.
+	FlowRTT bool `yaml:"flowRTT,omitempty"`
+	// Enable exporting the connections denied by NetworkPolicies. The packets dropped by NetworkPolicies are sent to
+	// antrea-agent through an OVS meter, which limits them to 500 packets per second when OVS supports meters. It is
+	// not supported with flowExporterStandalone. Defaults to false.
+	FlowExportDeniedConnections bool `yaml:"flowExportDeniedConnections,omitempty"`
+	// Provide the criteria to select the connections whose flow records are exported. A connection is exported only
+	// if it satisfies all the provided criteria. By default, all connections are exported.
+	FlowExportFilter FlowExportFilterConfig `yaml:"flowExportFilter,omitempty"`
+	// Provide the ClickHouse database to which flow records are written directly, so that they can be consumed by
+	// Grafana without an intermediate IPFIX collector. It can be enabled with or without flowCollectorAddr.
+	FlowClickHouse FlowClickHouseConfig `yaml:"flowClickHouse,omitempty"`
+	// Provide the local files to which flow records are written, as a hash-chained audit trail of the connections
+	// which does not require a collector. It can be enabled with or without flowCollectorAddr.
+	FlowArchive FlowArchiveConfig `yaml:"flowArchive,omitempty"`
+	// Run the flow exporter in a separate container with the "antrea-agent flow-exporter" command instead of in
+	// antrea-agent. The standalone flow exporter reads the local Pod interfaces from the antrea-agent API, so that it
+	// can be upgraded and scaled independently of the datapath. Connections denied by NetworkPolicies are not exported
+	// in this mode. Defaults to false.
+	FlowExporterStandalone bool `yaml:"flowExporterStandalone,omitempty"`
+	// Provide the configuration of the audit logging of the connections matched by the Antrea-native policy rules
+	// with enableLogging set. It is only used when the AntreaPolicy feature is enabled.
+	NetworkPolicyAuditLog NetworkPolicyAuditLogConfig `yaml:"networkPolicyAuditLog,omitempty"`
+	// Provide the thresholds of the usage of the datapath resources of the Node above which a warning Event is
+	// emitted for the Node. The usage is reported in the AntreaAgentInfo and as Prometheus metrics.
+	NodeCapacityWarningThresholds NodeCapacityWarningThresholdsConfig `yaml:"nodeCapacityWarningThresholds,omitempty"`
+	// Interval at which the health and the runtime information of the agent are reported in the AntreaAgentInfo.
+	// Each report is delayed by a random jitter of up to 20% of the interval, so that the updates from the agents
+	// of a large cluster are spread over time. It must be at least "10s". Defaults to "60s".
+	AgentInfoReportInterval string `yaml:"agentInfoReportInterval,omitempty"`
+	// Number of source ports allocated to each Pod SNAT'd with an Egress IP held by the Node. The source port range
+	// 1024-65535 of each Egress IP is partitioned into blocks of this size, which are allocated to the Pods in the order
+	// of their IPs, like cloud NAT gateways do, so that a Pod cannot exhaust the ports of the other Pods. The Pods
+	// which are not allocated a block when all blocks are allocated share the whole port range, and are reported in
+	// the antrea_agent_egress_snat_port_block_exhausted_pod_count metric. It must be between 64 and 32768, and it is
+	// not supported on Windows. Defaults to 0, which means that all Pods share the whole port range.
+	EgressSNATPortsPerPod int `yaml:"egressSNATPortsPerPod,omitempty"`
+}
+
+type AntreaProxyConfig struct {
+	// Enable the support of NodePort Services in AntreaProxy, so that kube-proxy is no longer required. The traffic
+	// to the NodePorts is forwarded to OVS through the host gateway, and its client IP is preserved for the
+	// Services with the Local external traffic policy. It is only supported on Linux Nodes in encap mode.
+	// Defaults to false.
+	NodePort bool `yaml:"nodePort,omitempty"`
+	// CIDR ranges of the Node addresses on which the NodePort Services are served, e.g. "192.168.0.0/16". Defaults to
+	// [], which means all the addresses of the Node, except the loopback ones.
+	NodePortAddresses []string `yaml:"nodePortAddresses,omitempty"`
+	// Enable AntreaProxy to serve the ClusterIPs for the traffic originated from the Node network namespace, e.g. from
+	// the hostNetwork Pods and the kubelet probes, so that kube-proxy is no longer required. The Service CIDR is
+	// routed to OVS through the host gateway and the traffic is masqueraded with the IP of the host gateway. It is only
+	// supported on Linux Nodes in encap mode. Defaults to false.
+	HostNetwork bool `yaml:"hostNetwork,omitempty"`
+	// How the traffic from outside the cluster to the LoadBalancer IPs is forwarded to remote Endpoints, which
+	// can be nat or dsr. With dsr (Direct Server Return), the traffic is forwarded to the Node of the selected
+	// Endpoint without DNAT, and the replies are sent to the clients from there, preserving the client IP. It
+	// requires nodePort to be enabled. Defaults to nat.
+	LoadBalancerMode string `yaml:"loadBalancerMode,omitempty"`
+	// Enable the fast path for the traffic from outside the cluster to the LoadBalancer IPs of the Services with the
+	// Local external traffic policy. The LoadBalancer IPs are routed to OVS through the host gateway without DNAT,
+	// and their traffic is not tracked by the host network, so that it is only DNAT'd once, by OVS, to the local
+	// Endpoints, while the client IP is preserved. It requires nodePort to be enabled, and is not supported with the
+	// dsr loadBalancerMode. Defaults to false.
+	LoadBalancerLocalFastPath bool `yaml:"loadBalancerLocalFastPath,omitempty"`
+	// How long the Endpoints removed from a Service, e.g. because their Pods are terminating, are drained before
+	// their flows are removed. Draining Endpoints are no longer selected for new connections, while their established
+	// connections, including the hairpin ones, keep being served. "0s" disables draining. Defaults to "30s".
+	EndpointDrainTimeout string `yaml:"endpointDrainTimeout,omitempty"`
+}
+
+type FlowCollectorTLSConfig struct {
+	// Path of the CA bundle used to verify the certificate of the flow collector. If not set, the system root CAs
+	// are used.
+	CAFile string `yaml:"caFile,omitempty"`
+	// Paths of the client certificate and private key presented to the flow collector. They are required with tls.
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+	// Name used to verify the certificate of the flow collector. Defaults to the IP of flowCollectorAddr.
+	ServerName string `yaml:"serverName,omitempty"`
+}
+
+type FlowExportFilterConfig struct {
+	// Only export connections whose source or destination Pod is in one of these Namespaces.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// Only export connections whose source or destination Pod matches this label selector, e.g. "app=web,tier!=db".
+	PodSelector string `yaml:"podSelector,omitempty"`
+	// Only export connections whose source or destination IP is in one of these CIDRs.
+	CIDRs []string `yaml:"cidrs,omitempty"`
+	// Export the connections of the Node and the hostNetwork Pods, which are tracked in the default conntrack zone of
+	// the host instead of the conntrack zone of Antrea, as well as the connections between the Node and the Pods
+	// through the gateway interface.
+	HostNetworkFlows bool `yaml:"hostNetworkFlows,omitempty"`
+	// Only export Pod-to-Pod and Pod-to-Service connections.
+	PodFlowsOnly bool `yaml:"podFlowsOnly,omitempty"`
+	// Also export the inter-Node connections from the destination Node, in addition to the source Node. The two
+	// records of a connection can be correlated with the flowKeyHash and flowDirection fields.
+	// Deprecated: use InterNodeFlowsExportedBy "both" instead.
+	DestinationNodeFlows bool `yaml:"destinationNodeFlows,omitempty"`
+	// The Node(s) from which the connections between Pods on different Nodes are exported: "source", "destination"
+	// or "both". Exporting them from a single Node avoids duplicated flow records at the collector when the flow
+	// aggregator is not used. Defaults to "source".
+	InterNodeFlowsExportedBy string `yaml:"interNodeFlowsExportedBy,omitempty"`
+}
+
+type FlowClickHouseConfig struct {
+	// Enable writing flow records to ClickHouse.
+	Enable bool `yaml:"enable,omitempty"`
+	// URL of the HTTP interface of ClickHouse, e.g. "http://clickhouse.flow-visibility.svc:8123".
+	URL string `yaml:"url,omitempty"`
+	// Names of the database and of the table to which flow records are written. They are created with the expected
+	// schema if they do not exist. Default to "default" and "flows".
+	Database string `yaml:"database,omitempty"`
+	Table    string `yaml:"table,omitempty"`
+	// Credentials of the ClickHouse user. The password should be provided through the
+	// ANTREA_CLICKHOUSE_PASSWORD environment variable, populated from a Secret.
+	Username string `yaml:"username,omitempty"`
+	// Deprecated: stored in plaintext in the ConfigMap, only used when ANTREA_CLICKHOUSE_PASSWORD is not set.
+	Password string `yaml:"password,omitempty"`
+	// Number of flow records after which a batch is inserted, even if commitInterval has not elapsed.
+	// Defaults to 1000.
+	BatchSize int `yaml:"batchSize,omitempty"`
+	// Interval after which the buffered flow records are inserted. Defaults to "8s".
+	CommitInterval string `yaml:"commitInterval,omitempty"`
+	// Duration after which flow records are deleted by ClickHouse. "0s" means that they are never deleted.
+	// Defaults to "12h".
+	TTL string `yaml:"ttl,omitempty"`
+}
+
+type FlowArchiveConfig struct {
+	// Enable writing flow records to archive files.
+	Enable bool `yaml:"enable,omitempty"`
+	// Directory in which the archive files are created, as JSON lines.
+	// Default value:
+	// - On Linux platform: /var/log/antrea/flows
+	// - On Windows platform: C:\k\antrea\logs\flows
+	Directory string `yaml:"directory,omitempty"`
+	// Size in megabytes after which a new archive file is started. Defaults to 100.
+	MaxSize int `yaml:"maxSize,omitempty"`
+	// Duration after which the archive files are deleted. "0s" means that they are never deleted. Defaults to "720h".
+	MaxAge string `yaml:"maxAge,omitempty"`
+	// Interval after which the buffered flow records are written and synced to the archive file. Defaults to "10s".
+	CommitInterval string `yaml:"commitInterval,omitempty"`
+	// Path of the file holding the key used to compute the HMAC-SHA256 digests of the hash chain of the archive,
+	// typically mounted from a Secret. If not set, the chain uses plain SHA-256 digests, which can be recomputed by
+	// anyone who can modify the files.
+	HMACKeyFile string `yaml:"hmacKeyFile,omitempty"`
+}
+
+type NetworkPolicyAuditLogConfig struct {
+	// Path of the file to which the audit log entries are written, as JSON lines.
+	// Default value:
+	// - On Linux platform: /var/log/antrea/networkpolicy/np.log
+	// - On Windows platform: C:\k\antrea\logs\networkpolicy\np.log
+	File string `yaml:"file,omitempty"`
+	// Size in megabytes after which the file is rotated. Defaults to 100.
+	MaxSize int `yaml:"maxSize,omitempty"`
+	// Maximum number of rotated files which are kept. Defaults to 3.
+	MaxBackups int `yaml:"maxBackups,omitempty"`
+	// Provide the syslog server to which the audit log entries are also sent as RFC 5424 messages, with format
+	// <IP>:<port>[:<proto>], where proto is udp, tcp or tls. If no L4 transport proto is given, we consider udp as
+	// default. With tls, the messages are sent over TCP with TLS, using the certificates provided in syslogTLS.
+	// Defaults to "", which means that the entries are not sent to syslog.
+	SyslogAddr string `yaml:"syslogAddr,omitempty"`
+	// Provide the certificates used to send the audit log entries to the syslog server over TLS. Only used when the
+	// proto of syslogAddr is tls.
+	SyslogTLS SyslogTLSConfig `yaml:"syslogTLS,omitempty"`
+	// Provide the URL of a webhook to which the audit log entries are also POSTed in batches, as JSON lines.
+	// Defaults to "", which means that the entries are not sent to a webhook.
+	WebhookURL string `yaml:"webhookURL,omitempty"`
+	// Maximum number of audit log entries written per second for each rule. The entries exceeding the limit are
+	// dropped, and counted in the suppressedEntries field of the next entry of the rule. Defaults to 0, which means
+	// that the entries are not rate limited.
+	RateLimit int `yaml:"rateLimit,omitempty"`
+}
+
+type SyslogTLSConfig struct {
+	// Path of the CA bundle used to verify the certificate of the syslog server. If not set, the system root CAs
+	// are used.
+	CAFile string `yaml:"caFile,omitempty"`
+	// Paths of the client certificate and private key presented to the syslog server. They are optional.
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+	// Name used to verify the certificate of the syslog server. Defaults to the IP of syslogAddr.
+	ServerName string `yaml:"serverName,omitempty"`
+}
+
+type NodeCapacityWarningThresholdsConfig struct {
+	// Percentage of the size of the conntrack table (nf_conntrack_max) used by connections. It is ignored on
+	// Windows. Defaults to 0, which disables the warning.
+	ConntrackUsagePercent int `yaml:"conntrackUsagePercent,omitempty"`
+	// Total number of flows in the OVS flow tables. Defaults to 0, which disables the warning.
+	OVSFlowCount int `yaml:"ovsFlowCount,omitempty"`
+	// Number of OVS groups. Defaults to 0, which disables the warning.
+	OVSGroupCount int `yaml:"ovsGroupCount,omitempty"`
 }
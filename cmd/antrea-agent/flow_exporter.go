@@ -0,0 +1,210 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/egress"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/flowrecords"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/standalone"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+	"github.com/vmware-tanzu/antrea/pkg/features"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
+	"github.com/vmware-tanzu/antrea/pkg/signals"
+	"github.com/vmware-tanzu/antrea/pkg/version"
+)
+
+// podInterfacesSyncInterval is how often the standalone flow exporter syncs the local Pod interfaces from
+// antrea-agent. The connections of a Pod created since the last sync are attributed to it at the next poll.
+const podInterfacesSyncInterval = 10 * time.Second
+
+// runFlowExporter runs the flow exporter in a container separate from antrea-agent and waits for termination signal.
+func runFlowExporter(o *Options) error {
+	klog.Infof("Starting Antrea flow exporter (version %s)", version.GetFullVersion())
+	if !features.DefaultFeatureGate.Enabled(features.FlowExporter) || !o.config.FlowExporterStandalone {
+		return fmt.Errorf("the FlowExporter feature gate and flowExporterStandalone must be enabled")
+	}
+	k8sClient, _, crdClient, err := k8s.CreateClients(o.config.ClientConnection)
+	if err != nil {
+		return fmt.Errorf("error creating K8s clients: %v", err)
+	}
+	agentClient, err := standalone.NewAgentClient(o.config.APIPort)
+	if err != nil {
+		return err
+	}
+	nodeConfig, err := getNodeConfigFromAgent(agentClient)
+	if err != nil {
+		return err
+	}
+
+	stopCh := signals.RegisterSignalHandlers()
+
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ifaceSyncer := standalone.NewInterfaceSyncer(agentClient, ifaceStore)
+	if err := ifaceSyncer.Sync(); err != nil {
+		klog.Errorf("Failed to sync Pod interfaces from antrea-agent: %v", err)
+	}
+	go ifaceSyncer.Run(podInterfacesSyncInterval, stopCh)
+
+	informerFactory := informers.NewSharedInformerFactory(k8sClient, informerDefaultResync)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	nodeQuerier := standalone.NewNodeQuerier(nodeConfig.Name, nodeInformer.Lister())
+	// The destination Services are only known when Services are load balanced by AntreaProxy, which is what
+	// serviceQuerier mirrors.
+	cacheSyncs := []cache.InformerSynced{nodeInformer.Informer().HasSynced}
+	var serviceQuerier connections.ServiceQuerier
+	if features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
+		serviceInformer := informerFactory.Core().V1().Services()
+		serviceQuerier, err = standalone.NewServiceQuerier(serviceInformer)
+		if err != nil {
+			return fmt.Errorf("error creating Service querier: %v", err)
+		}
+		cacheSyncs = append(cacheSyncs, serviceInformer.Informer().HasSynced)
+	}
+	_, serviceCIDRNet, _ := net.ParseCIDR(o.config.ServiceCIDR)
+	_, encapMode := config.GetTrafficEncapModeFromStr(o.config.TrafficEncapMode)
+	egressEnabled := features.DefaultFeatureGate.Enabled(features.Egress)
+	// The labels of the local Pods are only needed when connections are filtered by a Pod label selector, or to look
+	// up the Egresses of the Pods.
+	var podLister corelisters.PodLister
+	if o.flowExportPodSelector != nil || egressEnabled {
+		podInformer := coreinformers.NewFilteredPodInformer(k8sClient, metav1.NamespaceAll, informerDefaultResync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeConfig.Name).String()
+		})
+		podLister = corelisters.NewPodLister(podInformer.GetIndexer())
+		cacheSyncs = append(cacheSyncs, podInformer.HasSynced)
+		go podInformer.Run(stopCh)
+	}
+	// The connections from local Pods which leave the cluster are SNAT'd by their Egress when the Egress feature is
+	// enabled, and masqueraded by the Node otherwise, except in networkPolicyOnly mode where SNAT is managed by the
+	// primary CNI.
+	var egressQuerier connections.EgressQuerier
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, informerDefaultResync)
+	if egressEnabled {
+		egressInformer := crdInformerFactory.Core().V1alpha1().Egresses()
+		externalIPPoolInformer := crdInformerFactory.Core().V1alpha1().ExternalIPPools()
+		namespaceInformer := informerFactory.Core().V1().Namespaces()
+		egressQuerier = egress.NewEgressQuerier(
+			nodeConfig.Name,
+			egressInformer.Lister(),
+			podLister,
+			namespaceInformer.Lister(),
+			nodeInformer.Lister(),
+			externalIPPoolInformer.Lister())
+		cacheSyncs = append(cacheSyncs, egressInformer.Informer().HasSynced, externalIPPoolInformer.Informer().HasSynced, namespaceInformer.Informer().HasSynced)
+	} else if !encapMode.IsNetworkPolicyOnly() {
+		egressQuerier = connections.NewNodeSNATQuerier(nodeConfig.Name, nodeConfig.NodeIPAddr.IP)
+	}
+	informerFactory.Start(stopCh)
+	crdInformerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, cacheSyncs...) {
+		return fmt.Errorf("error waiting for informer caches to sync")
+	}
+
+	exportFilter := connections.NewExportFilter(
+		o.config.FlowSamplingRate,
+		o.config.FlowExportFilter.Namespaces,
+		o.flowExportPodSelector,
+		podLister,
+		o.flowExportCIDRs,
+		o.config.FlowExportFilter.PodFlowsOnly,
+		o.interNodeFlowExporter)
+	var rttDumper connections.TCPRTTDumper
+	if o.config.FlowRTT {
+		rttDumper = connections.NewTCPRTTDumper()
+	}
+	connStore := connections.NewConnectionStore(
+		connections.InitializeConnTrackDumper(nodeConfig, serviceCIDRNet, ovsctl.NewClient(o.config.OVSBridge), o.config.OVSDatapathType, o.config.FlowExportFilter.HostNetworkFlows),
+		ifaceStore,
+		serviceCIDRNet,
+		serviceQuerier,
+		nodeConfig.Name,
+		nodeQuerier,
+		egressQuerier,
+		o.pollInterval,
+		o.resyncInterval,
+		o.config.FlowPollCPUBudget,
+		exportFilter,
+		rttDumper)
+	pollDone := make(chan struct{})
+	go connStore.Run(stopCh, pollDone)
+
+	// The denied connections are reported by the packet-in messages received by antrea-agent, so none is exported.
+	denyConnStore := connections.NewDenyConnectionStore(ifaceStore, nodeConfig.Name, nodeQuerier, exportFilter)
+	flowExporter := exporter.NewFlowExporter(
+		flowrecords.NewFlowRecords(connStore, o.activeFlowTimeout, o.idleFlowTimeout),
+		flowrecords.NewFlowRecords(denyConnStore, o.activeFlowTimeout, o.idleFlowTimeout),
+		o.flowCollectorTLS,
+		o.flowClickHouse,
+		o.flowArchive,
+		flowExportBaselineFile)
+	go flowExporter.RunClickHouseWriter(stopCh)
+	go flowExporter.RunArchiveWriter(stopCh)
+	go wait.Until(func() { flowExporter.Export(o.flowCollector, stopCh, pollDone) }, 0, stopCh)
+
+	<-stopCh
+	klog.Info("Stopping Antrea flow exporter")
+	return nil
+}
+
+// getNodeConfigFromAgent returns the NodeConfig discovered by antrea-agent, waiting for its API to be available.
+func getNodeConfigFromAgent(agentClient standalone.AgentClient) (*config.NodeConfig, error) {
+	nodeConfig := &config.NodeConfig{}
+	if err := wait.PollImmediate(2*time.Second, 2*time.Minute, func() (bool, error) {
+		resp, err := agentClient.GetNodeConfig()
+		if err != nil {
+			klog.Warningf("Failed to get the Node config from antrea-agent, retrying: %v", err)
+			return false, nil
+		}
+		nodeConfig.Name = resp.NodeName
+		if resp.NodeIP != "" {
+			ip, ipNet, err := net.ParseCIDR(resp.NodeIP)
+			if err != nil {
+				return false, fmt.Errorf("invalid Node IP %s: %v", resp.NodeIP, err)
+			}
+			ipNet.IP = ip
+			nodeConfig.NodeIPAddr = ipNet
+		}
+		if resp.PodCIDR != "" {
+			_, podCIDR, err := net.ParseCIDR(resp.PodCIDR)
+			if err != nil {
+				return false, fmt.Errorf("invalid PodCIDR %s: %v", resp.PodCIDR, err)
+			}
+			nodeConfig.PodCIDR = podCIDR
+		}
+		nodeConfig.GatewayConfig = &config.GatewayConfig{Name: resp.GatewayName, IP: net.ParseIP(resp.GatewayIP)}
+		return true, nil
+	}); err != nil {
+		return nil, fmt.Errorf("error getting the Node config from antrea-agent: %v", err)
+	}
+	return nodeConfig, nil
+}
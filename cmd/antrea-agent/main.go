@@ -66,5 +66,35 @@ func newAgentCommand() *cobra.Command {
 	log.AddFlags(flags)
 	// Install log flags
 	flags.AddGoFlagSet(flag.CommandLine)
+	cmd.AddCommand(newFlowExporterCommand())
+	return cmd
+}
+
+func newFlowExporterCommand() *cobra.Command {
+	opts := newOptions()
+
+	cmd := &cobra.Command{
+		Use:   "flow-exporter",
+		Short: "Run the flow exporter in a container separate from antrea-agent",
+		Long: "Run the flow exporter in a container separate from antrea-agent. It reads the same configuration " +
+			"file as antrea-agent, which must have flowExporterStandalone set.",
+		Run: func(cmd *cobra.Command, args []string) {
+			log.InitLogFileLimits(cmd.Flags())
+			if err := opts.complete(args); err != nil {
+				klog.Fatalf("Failed to complete: %v", err)
+			}
+			if err := opts.validate(args); err != nil {
+				klog.Fatalf("Failed to validate: %v", err)
+			}
+			if err := runFlowExporter(opts); err != nil {
+				klog.Fatalf("Error running flow exporter: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.addFlags(flags)
+	log.AddFlags(flags)
+	flags.AddGoFlagSet(flag.CommandLine)
 	return cmd
 }
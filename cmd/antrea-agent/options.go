@@ -15,16 +15,26 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/networkpolicy"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
 	"github.com/vmware-tanzu/antrea/pkg/apis"
 	"github.com/vmware-tanzu/antrea/pkg/cni"
 	"github.com/vmware-tanzu/antrea/pkg/features"
@@ -32,15 +42,43 @@ import (
 )
 
 const (
-	defaultOVSBridge           = "br-int"
-	defaultHostGateway         = "antrea-gw0"
-	defaultHostProcPathPrefix  = "/host"
-	defaultServiceCIDR         = "10.96.0.0/12"
-	defaultTunnelType          = ovsconfig.GeneveTunnel
-	defaultFlowPollInterval    = 5 * time.Second
-	defaultFlowExportFrequency = 12
+	defaultOVSBridge                = "br-int"
+	defaultHostGateway              = "antrea-gw0"
+	defaultHostProcPathPrefix       = "/host"
+	defaultServiceCIDR              = "10.96.0.0/12"
+	defaultTunnelType               = ovsconfig.GeneveTunnel
+	defaultFlowPollInterval         = 5 * time.Second
+	defaultActiveFlowExportTimeout  = 60 * time.Second
+	defaultIdleFlowExportTimeout    = 15 * time.Second
+	defaultFlowResyncInterval       = 60 * time.Second
+	defaultClickHouseDatabase       = "default"
+	defaultClickHouseTable          = "flows"
+	defaultClickHouseBatchSize      = 1000
+	defaultClickHouseCommitInterval = 8 * time.Second
+	defaultClickHouseTTL            = 12 * time.Hour
+	defaultArchiveMaxSize           = 100
+	defaultArchiveMaxAge            = 30 * 24 * time.Hour
+	defaultArchiveCommitInterval    = 10 * time.Second
+	defaultAuditLogMaxSize          = 100
+	defaultAuditLogMaxBackups       = 3
+	defaultEndpointDrainTimeout     = 30 * time.Second
+	defaultAgentInfoReportInterval  = 60 * time.Second
+	minAgentInfoReportInterval      = 10 * time.Second
+	minEgressSNATPortsPerPod        = 64
+	maxEgressSNATPortsPerPod        = 32768
+
+	// clickHousePasswordEnvKey is the environment variable providing the password of the ClickHouse user,
+	// which is expected to be populated from a Secret.
+	clickHousePasswordEnvKey = "ANTREA_CLICKHOUSE_PASSWORD"
+
+	// loadBalancerModeNAT and loadBalancerModeDSR are the supported values of the AntreaProxy LoadBalancerMode.
+	loadBalancerModeNAT = "nat"
+	loadBalancerModeDSR = "dsr"
 )
 
+// clickHouseIdentifierRegexp matches the names of the ClickHouse database and table.
+var clickHouseIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 type Options struct {
 	// The path of configuration file.
 	configFile string
@@ -48,8 +86,32 @@ type Options struct {
 	config *AgentConfig
 	// IPFIX flow collector
 	flowCollector net.Addr
+	// TLS configuration of the IPFIX flow collector, nil if flow records are not sent over TLS
+	flowCollectorTLS *exporter.TLSConfig
+	// ClickHouse configuration, nil if flow records are not written to ClickHouse
+	flowClickHouse *exporter.ClickHouseConfig
+	// Archive configuration, nil if flow records are not written to archive files
+	flowArchive *exporter.ArchiveConfig
 	// Flow exporter poll interval
 	pollInterval time.Duration
+	// Interval at which the conntrack table is dumped when conntrack events are used, zero if they are not used
+	resyncInterval time.Duration
+	// Active flow timeout to export records of active flows
+	activeFlowTimeout time.Duration
+	// Idle flow timeout to export records of idle flows
+	idleFlowTimeout time.Duration
+	// Label selector of the Pods whose connections are exported
+	flowExportPodSelector labels.Selector
+	// CIDRs of the IPs whose connections are exported
+	flowExportCIDRs []*net.IPNet
+	// Node(s) from which inter-Node connections are exported
+	interNodeFlowExporter connections.InterNodeFlowExporter
+	// Audit logging configuration of Antrea-native policy rules
+	auditLogConfig *networkpolicy.AuditLogConfig
+	// How long AntreaProxy drains the Endpoints removed from a Service
+	endpointDrainTimeout time.Duration
+	// Interval at which the AntreaAgentInfo is updated
+	agentInfoReportInterval time.Duration
 }
 
 func newOptions() *Options {
@@ -90,6 +152,9 @@ func (o *Options) validate(args []string) error {
 		o.config.TunnelType != ovsconfig.GRETunnel && o.config.TunnelType != ovsconfig.STTTunnel {
 		return fmt.Errorf("tunnel type %s is invalid", o.config.TunnelType)
 	}
+	if err := o.validateTunnelConfig(); err != nil {
+		return err
+	}
 	if o.config.EnableIPSecTunnel && o.config.TunnelType != ovsconfig.GRETunnel {
 		return fmt.Errorf("IPSec encyption is supported only for GRE tunnel")
 	}
@@ -100,20 +165,212 @@ func (o *Options) validate(args []string) error {
 	if !ok {
 		return fmt.Errorf("TrafficEncapMode %s is unknown", o.config.TrafficEncapMode)
 	}
-	if encapMode.SupportsNoEncap() {
-		if !features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
-			return fmt.Errorf("Mode %s requires AntreaProxy to be enabled", o.config.TrafficEncapMode)
+	if encapMode.SupportsNoEncap() && o.config.EnableIPSecTunnel {
+		return fmt.Errorf("IPSec tunnel may only be enabled on %s mode", config.TrafficEncapModeEncap)
+	}
+	if o.config.HybridNoEncapNodeOS != "" {
+		if encapMode != config.TrafficEncapModeHybrid {
+			return fmt.Errorf("HybridNoEncapNodeOS may only be set on %s mode", config.TrafficEncapModeHybrid)
 		}
-		if o.config.EnableIPSecTunnel {
-			return fmt.Errorf("IPSec tunnel may only be enabled on %s mode", config.TrafficEncapModeEncap)
+		if o.config.HybridNoEncapNodeOS != "linux" && o.config.HybridNoEncapNodeOS != "windows" {
+			return fmt.Errorf("HybridNoEncapNodeOS %s is invalid, it should be linux or windows", o.config.HybridNoEncapNodeOS)
+		}
+	}
+	if o.config.NoEncapNeighborProxy {
+		if !encapMode.SupportsNoEncap() {
+			return fmt.Errorf("NoEncapNeighborProxy may only be enabled on %s or %s mode", config.TrafficEncapModeNoEncap, config.TrafficEncapModeHybrid)
+		}
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("NoEncapNeighborProxy is not supported on Windows")
+		}
+	}
+	if o.config.CNIReadinessGate {
+		if encapMode.IsNetworkPolicyOnly() {
+			return fmt.Errorf("CNIReadinessGate is not supported in %s mode", config.TrafficEncapModeNetworkPolicyOnly)
+		}
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("CNIReadinessGate is not supported on Windows")
 		}
 	}
+	if err := o.validateFeatureDependencies(encapMode); err != nil {
+		return err
+	}
+	if err := o.validateAntreaProxyConfig(encapMode); err != nil {
+		return err
+	}
 	if err := o.validateFlowExporterConfig(); err != nil {
 		return fmt.Errorf("Failed to validate flow exporter config: %v", err)
 	}
+	if err := o.validateNetworkPolicyAuditLogConfig(); err != nil {
+		return fmt.Errorf("Failed to validate NetworkPolicy audit log config: %v", err)
+	}
+	if err := o.validateNodeCapacityWarningThresholds(); err != nil {
+		return fmt.Errorf("Failed to validate Node capacity warning thresholds: %v", err)
+	}
+	if err := o.validateAgentInfoReportInterval(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateFeatureDependencies checks that the enabled features are consistent with each other and with the traffic
+// encapsulation mode, so that antrea-agent fails to start with a precise message instead of a feature misbehaving at
+// runtime. The combinations in which a feature only loses some information or has no effect are logged as warnings.
+func (o *Options) validateFeatureDependencies(encapMode config.TrafficEncapModeType) error {
+	antreaProxyEnabled := features.DefaultFeatureGate.Enabled(features.AntreaProxy)
+	if encapMode.SupportsNoEncap() && !antreaProxyEnabled {
+		return fmt.Errorf("Mode %s requires AntreaProxy to be enabled", encapMode)
+	}
+	if features.DefaultFeatureGate.Enabled(features.Egress) {
+		// The traffic of the Pods selected by an Egress is forwarded to the Egress Node through the flow based
+		// tunnel.
+		if encapMode != config.TrafficEncapModeEncap {
+			return fmt.Errorf("Egress is only supported in %s mode", config.TrafficEncapModeEncap)
+		}
+		if o.config.EnableIPSecTunnel {
+			return fmt.Errorf("Egress is not supported with IPSec tunnel")
+		}
+		if o.config.EgressSNATPortsPerPod != 0 {
+			if runtime.GOOS == "windows" {
+				return fmt.Errorf("EgressSNATPortsPerPod is not supported on Windows")
+			}
+			if o.config.EgressSNATPortsPerPod < minEgressSNATPortsPerPod || o.config.EgressSNATPortsPerPod > maxEgressSNATPortsPerPod {
+				return fmt.Errorf("EgressSNATPortsPerPod should be between %d and %d", minEgressSNATPortsPerPod, maxEgressSNATPortsPerPod)
+			}
+		}
+	}
+	if features.DefaultFeatureGate.Enabled(features.FlowExporter) {
+		if o.config.FlowRTT && runtime.GOOS == "windows" {
+			return fmt.Errorf("FlowRTT is not supported on Windows")
+		}
+		if o.config.FlowExportDeniedConnections && o.config.FlowExporterStandalone {
+			return fmt.Errorf("FlowExportDeniedConnections is not supported with FlowExporterStandalone")
+		}
+		if !antreaProxyEnabled {
+			klog.Warningf("AntreaProxy is not enabled, the flow records of the connections to Services will not include their destination Service")
+		}
+	} else {
+		if o.config.FlowExporterStandalone {
+			return fmt.Errorf("FlowExporterStandalone requires FlowExporter to be enabled")
+		}
+		if o.config.FlowClickHouse.Enable || o.config.FlowArchive.Enable {
+			klog.Warningf("FlowExporter is not enabled, flow records will not be written to ClickHouse or to the archive")
+		}
+	}
+	if features.DefaultFeatureGate.Enabled(features.AntreaIPAM) {
+		// The IPs of the Pods using an IPPool are routed to the gateway by the host network.
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("AntreaIPAM is not supported on Windows")
+		}
+		if encapMode.IsNetworkPolicyOnly() {
+			return fmt.Errorf("AntreaIPAM is not supported in %s mode", encapMode)
+		}
+	} else if o.config.AntreaIPAMDHCPResponder {
+		klog.Warningf("AntreaIPAM is not enabled, AntreaIPAMDHCPResponder will have no effect")
+	}
+	if features.DefaultFeatureGate.Enabled(features.EndpointSlice) && !antreaProxyEnabled {
+		klog.Warningf("AntreaProxy is not enabled, EndpointSlice will have no effect")
+	}
+	if features.DefaultFeatureGate.Enabled(features.Traceflow) && (!encapMode.SupportsEncap() || o.config.TunnelType != ovsconfig.GeneveTunnel) {
+		// Traceflow tags its packets with Geneve options across Nodes.
+		klog.Warningf("Traceflow only supports tracing packets to the Pods of other Nodes when they are sent through the %s tunnel", ovsconfig.GeneveTunnel)
+	}
+	return nil
+}
+
+func (o *Options) validateAntreaProxyConfig(encapMode config.TrafficEncapModeType) error {
+	proxyConfig := o.config.AntreaProxy
+	o.endpointDrainTimeout = defaultEndpointDrainTimeout
+	if proxyConfig.EndpointDrainTimeout != "" {
+		timeout, err := time.ParseDuration(proxyConfig.EndpointDrainTimeout)
+		if err != nil {
+			return fmt.Errorf("AntreaProxy EndpointDrainTimeout is not provided in right format: %v", err)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("AntreaProxy EndpointDrainTimeout cannot be negative")
+		}
+		o.endpointDrainTimeout = timeout
+	}
+	switch proxyConfig.LoadBalancerMode {
+	case "", loadBalancerModeNAT:
+	case loadBalancerModeDSR:
+		if !proxyConfig.NodePort {
+			return fmt.Errorf("AntreaProxy LoadBalancerMode %s requires NodePort to be enabled", loadBalancerModeDSR)
+		}
+	default:
+		return fmt.Errorf("AntreaProxy LoadBalancerMode %s is not supported", proxyConfig.LoadBalancerMode)
+	}
+	if proxyConfig.LoadBalancerLocalFastPath {
+		if !proxyConfig.NodePort {
+			return fmt.Errorf("AntreaProxy LoadBalancerLocalFastPath requires NodePort to be enabled")
+		}
+		if proxyConfig.LoadBalancerMode == loadBalancerModeDSR {
+			return fmt.Errorf("AntreaProxy LoadBalancerLocalFastPath is not supported with LoadBalancerMode %s", loadBalancerModeDSR)
+		}
+	}
+	var option string
+	if proxyConfig.NodePort {
+		option = "NodePort"
+	} else if proxyConfig.HostNetwork {
+		option = "HostNetwork"
+	} else {
+		return nil
+	}
+	if !features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
+		return fmt.Errorf("AntreaProxy %s requires AntreaProxy to be enabled", option)
+	}
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("AntreaProxy %s is not supported on Windows", option)
+	}
+	if encapMode != config.TrafficEncapModeEncap {
+		return fmt.Errorf("AntreaProxy %s is only supported in %s mode", option, config.TrafficEncapModeEncap)
+	}
+	for _, cidr := range proxyConfig.NodePortAddresses {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("AntreaProxy NodePortAddresses %s is not a valid CIDR", cidr)
+		}
+	}
+	return nil
+}
+
+func (o *Options) validateNodeCapacityWarningThresholds() error {
+	thresholds := o.config.NodeCapacityWarningThresholds
+	if thresholds.ConntrackUsagePercent < 0 || thresholds.ConntrackUsagePercent > 100 {
+		return fmt.Errorf("NodeCapacityWarningThresholds ConntrackUsagePercent %d should be between 0 and 100", thresholds.ConntrackUsagePercent)
+	}
+	if thresholds.OVSFlowCount < 0 || thresholds.OVSGroupCount < 0 {
+		return fmt.Errorf("NodeCapacityWarningThresholds OVSFlowCount and OVSGroupCount should not be negative")
+	}
 	return nil
 }
 
+func (o *Options) validateAgentInfoReportInterval() error {
+	o.agentInfoReportInterval = defaultAgentInfoReportInterval
+	if o.config.AgentInfoReportInterval != "" {
+		var err error
+		o.agentInfoReportInterval, err = time.ParseDuration(o.config.AgentInfoReportInterval)
+		if err != nil {
+			return fmt.Errorf("AgentInfoReportInterval is not provided in right format: %v", err)
+		}
+		if o.agentInfoReportInterval < minAgentInfoReportInterval {
+			return fmt.Errorf("AgentInfoReportInterval should be greater than or equal to %v", minAgentInfoReportInterval)
+		}
+	}
+	return nil
+}
+
+func (o *Options) validateTunnelConfig() error {
+	if o.config.TunnelPort != 0 {
+		if o.config.TunnelType != ovsconfig.VXLANTunnel && o.config.TunnelType != ovsconfig.GeneveTunnel {
+			return fmt.Errorf("TunnelPort is supported only for %s and %s tunnels", ovsconfig.GeneveTunnel, ovsconfig.VXLANTunnel)
+		}
+		if o.config.TunnelPort < 0 || o.config.TunnelPort > 65535 {
+			return fmt.Errorf("TunnelPort %d is invalid", o.config.TunnelPort)
+		}
+	}
+	return config.ValidateTunnelTTL(o.config.TunnelTTL)
+}
+
 func (o *Options) loadConfigFromFile(file string) (*AgentConfig, error) {
 	data, err := ioutil.ReadFile(file)
 	if err != nil {
@@ -159,14 +416,28 @@ func (o *Options) setDefaults() {
 	if o.config.APIPort == 0 {
 		o.config.APIPort = apis.AntreaAgentAPIPort
 	}
+	if o.config.NetworkPolicyAuditLog.File == "" {
+		o.config.NetworkPolicyAuditLog.File = networkpolicy.DefaultAuditLogFile
+	}
+	if o.config.NetworkPolicyAuditLog.MaxSize == 0 {
+		o.config.NetworkPolicyAuditLog.MaxSize = defaultAuditLogMaxSize
+	}
+	if o.config.NetworkPolicyAuditLog.MaxBackups == 0 {
+		o.config.NetworkPolicyAuditLog.MaxBackups = defaultAuditLogMaxBackups
+	}
 
 	if o.config.FeatureGates[string(features.FlowExporter)] {
 		if o.config.FlowPollInterval == "" {
 			o.pollInterval = defaultFlowPollInterval
 		}
-		if o.config.FlowExportFrequency == 0 {
-			// This frequency value makes flow export interval as 60s by default.
-			o.config.FlowExportFrequency = defaultFlowExportFrequency
+		if o.config.ActiveFlowExportTimeout == "" {
+			o.activeFlowTimeout = defaultActiveFlowExportTimeout
+		}
+		if o.config.IdleFlowExportTimeout == "" {
+			o.idleFlowTimeout = defaultIdleFlowExportTimeout
+		}
+		if o.config.FlowResyncInterval == "" {
+			o.resyncInterval = defaultFlowResyncInterval
 		}
 	}
 }
@@ -174,7 +445,9 @@ func (o *Options) setDefaults() {
 func (o *Options) validateFlowExporterConfig() error {
 	if features.DefaultFeatureGate.Enabled(features.FlowExporter) {
 		if o.config.FlowCollectorAddr == "" {
-			return fmt.Errorf("IPFIX flow collector address should be provided")
+			if !o.config.FlowClickHouse.Enable && !o.config.FlowArchive.Enable {
+				return fmt.Errorf("IPFIX flow collector address should be provided if neither FlowClickHouse nor FlowArchive is enabled")
+			}
 		} else {
 			// Check if it is TCP or UDP
 			strSlice := strings.Split(o.config.FlowCollectorAddr, ":")
@@ -183,7 +456,7 @@ func (o *Options) validateFlowExporterConfig() error {
 				// If no separator ":" and proto is given, then default to TCP.
 				proto = "tcp"
 			} else if len(strSlice) > 2 {
-				if (strSlice[2] != "udp") && (strSlice[2] != "tcp") {
+				if (strSlice[2] != "udp") && (strSlice[2] != "tcp") && (strSlice[2] != "tls") {
 					return fmt.Errorf("IPFIX flow collector over %s proto is not supported", strSlice[2])
 				}
 				proto = strSlice[2]
@@ -198,6 +471,10 @@ func (o *Options) validateFlowExporterConfig() error {
 				return fmt.Errorf("IPFIX flow collector is given in invalid format: %v", err)
 			}
 			if proto == "udp" {
+				// The flow records can only be sent over TLS with TCP, DTLS is not supported.
+				if o.config.FlowCollectorTLS != (FlowCollectorTLSConfig{}) {
+					return fmt.Errorf("FlowCollectorTLS is not supported for IPFIX flow collector over UDP proto, use tls proto instead")
+				}
 				o.flowCollector, err = net.ResolveUDPAddr("udp", hostPortAddr)
 				if err != nil {
 					return fmt.Errorf("IPFIX flow collector over UDP proto cannot be resolved: %v", err)
@@ -208,6 +485,18 @@ func (o *Options) validateFlowExporterConfig() error {
 					return fmt.Errorf("IPFIX flow collector over TCP proto cannot be resolved: %v", err)
 				}
 			}
+			if proto == "tls" {
+				tlsConfig := o.config.FlowCollectorTLS
+				if tlsConfig.CertFile == "" || tlsConfig.KeyFile == "" {
+					return fmt.Errorf("FlowCollectorTLS CertFile and KeyFile should be provided for IPFIX flow collector over TLS")
+				}
+				o.flowCollectorTLS = &exporter.TLSConfig{
+					CAFile:     tlsConfig.CAFile,
+					CertFile:   tlsConfig.CertFile,
+					KeyFile:    tlsConfig.KeyFile,
+					ServerName: tlsConfig.ServerName,
+				}
+			}
 		}
 		if o.config.FlowPollInterval != "" {
 			var err error
@@ -219,6 +508,267 @@ func (o *Options) validateFlowExporterConfig() error {
 				return fmt.Errorf("FlowPollInterval should be greater than or equal to one second")
 			}
 		}
+		if o.config.FlowResyncInterval != "" {
+			var err error
+			o.resyncInterval, err = time.ParseDuration(o.config.FlowResyncInterval)
+			if err != nil {
+				return fmt.Errorf("FlowResyncInterval is not provided in right format: %v", err)
+			}
+			if o.resyncInterval != 0 && o.resyncInterval < o.pollInterval {
+				return fmt.Errorf("FlowResyncInterval should be greater than or equal to FlowPollInterval")
+			}
+		}
+		if o.config.FlowPollCPUBudget > 100 {
+			return fmt.Errorf("FlowPollCPUBudget should be a percentage between 0 and 100")
+		}
+		if o.config.ActiveFlowExportTimeout != "" {
+			var err error
+			o.activeFlowTimeout, err = time.ParseDuration(o.config.ActiveFlowExportTimeout)
+			if err != nil {
+				return fmt.Errorf("ActiveFlowExportTimeout is not provided in right format: %v", err)
+			}
+			if o.activeFlowTimeout < o.pollInterval {
+				klog.Warningf("ActiveFlowExportTimeout is lesser than FlowPollInterval, records of active flows will be exported at every poll cycle")
+			}
+		}
+		if o.config.FlowExportFrequency != 0 {
+			if o.config.ActiveFlowExportTimeout != "" {
+				klog.Warningf("FlowExportFrequency is deprecated and ignored as ActiveFlowExportTimeout is set")
+			} else {
+				o.activeFlowTimeout = time.Duration(o.config.FlowExportFrequency) * o.pollInterval
+				klog.Warningf("FlowExportFrequency is deprecated, please use ActiveFlowExportTimeout instead. ActiveFlowExportTimeout is set to %v (FlowExportFrequency times FlowPollInterval)", o.activeFlowTimeout)
+			}
+		}
+		if o.config.IdleFlowExportTimeout != "" {
+			var err error
+			o.idleFlowTimeout, err = time.ParseDuration(o.config.IdleFlowExportTimeout)
+			if err != nil {
+				return fmt.Errorf("IdleFlowExportTimeout is not provided in right format: %v", err)
+			}
+			if o.idleFlowTimeout < o.pollInterval {
+				klog.Warningf("IdleFlowExportTimeout is lesser than FlowPollInterval, flows will be considered idle if they are not updated in one poll cycle")
+			}
+		}
+		if o.config.FlowExportFilter.PodSelector != "" {
+			var err error
+			o.flowExportPodSelector, err = labels.Parse(o.config.FlowExportFilter.PodSelector)
+			if err != nil {
+				return fmt.Errorf("FlowExportFilter PodSelector is not provided in right format: %v", err)
+			}
+		}
+		o.flowExportCIDRs = nil
+		for _, cidr := range o.config.FlowExportFilter.CIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("FlowExportFilter CIDR %s is not provided in right format: %v", cidr, err)
+			}
+			o.flowExportCIDRs = append(o.flowExportCIDRs, ipNet)
+		}
+		if o.config.FlowExportFilter.HostNetworkFlows && o.config.FlowExportFilter.PodFlowsOnly {
+			return fmt.Errorf("FlowExportFilter HostNetworkFlows cannot be enabled when PodFlowsOnly is enabled")
+		}
+		if err := o.validateInterNodeFlowExporter(); err != nil {
+			return err
+		}
+		if err := o.validateFlowClickHouseConfig(); err != nil {
+			return err
+		}
+		if err := o.validateFlowArchiveConfig(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) validateInterNodeFlowExporter() error {
+	filterConfig := o.config.FlowExportFilter
+	o.interNodeFlowExporter = connections.InterNodeFlowExporter(filterConfig.InterNodeFlowsExportedBy)
+	switch o.interNodeFlowExporter {
+	case "":
+		o.interNodeFlowExporter = connections.InterNodeFlowExporterSource
+		if filterConfig.DestinationNodeFlows {
+			o.interNodeFlowExporter = connections.InterNodeFlowExporterBoth
+		}
+	case connections.InterNodeFlowExporterSource, connections.InterNodeFlowExporterDestination, connections.InterNodeFlowExporterBoth:
+		if filterConfig.DestinationNodeFlows && o.interNodeFlowExporter != connections.InterNodeFlowExporterBoth {
+			return fmt.Errorf("FlowExportFilter DestinationNodeFlows cannot be enabled when InterNodeFlowsExportedBy is %s", o.interNodeFlowExporter)
+		}
+	default:
+		return fmt.Errorf("FlowExportFilter InterNodeFlowsExportedBy %s is not supported, it should be one of source, destination or both", filterConfig.InterNodeFlowsExportedBy)
+	}
+	return nil
+}
+
+func (o *Options) validateNetworkPolicyAuditLogConfig() error {
+	auditLogConfig := o.config.NetworkPolicyAuditLog
+	if auditLogConfig.MaxSize < 0 || auditLogConfig.MaxBackups < 0 {
+		return fmt.Errorf("NetworkPolicyAuditLog MaxSize and MaxBackups should not be negative")
+	}
+	if auditLogConfig.RateLimit < 0 {
+		return fmt.Errorf("NetworkPolicyAuditLog RateLimit should not be negative")
+	}
+	o.auditLogConfig = &networkpolicy.AuditLogConfig{
+		File:       auditLogConfig.File,
+		MaxSize:    auditLogConfig.MaxSize,
+		MaxBackups: auditLogConfig.MaxBackups,
+		RateLimit:  auditLogConfig.RateLimit,
+	}
+	if auditLogConfig.WebhookURL != "" {
+		webhookURL, err := url.Parse(auditLogConfig.WebhookURL)
+		if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+			return fmt.Errorf("NetworkPolicyAuditLog WebhookURL %q is not provided in right format, it should be http(s)://<host>[:<port>]/<path>", auditLogConfig.WebhookURL)
+		}
+		o.auditLogConfig.WebhookURL = auditLogConfig.WebhookURL
+	}
+	if auditLogConfig.SyslogAddr == "" {
+		return nil
+	}
+	strSlice := strings.Split(auditLogConfig.SyslogAddr, ":")
+	network := "udp"
+	if len(strSlice) > 2 {
+		if strSlice[2] != "udp" && strSlice[2] != "tcp" && strSlice[2] != "tls" {
+			return fmt.Errorf("syslog server over %s proto is not supported", strSlice[2])
+		}
+		network = strSlice[2]
+	} else if len(strSlice) < 2 {
+		return fmt.Errorf("syslog server address is given in invalid format")
+	}
+	hostPortAddr := strSlice[0] + ":" + strSlice[1]
+	if _, _, err := net.SplitHostPort(hostPortAddr); err != nil {
+		return fmt.Errorf("syslog server address is given in invalid format: %v", err)
+	}
+	o.auditLogConfig.SyslogNetwork = network
+	o.auditLogConfig.SyslogAddress = hostPortAddr
+	if network == "tls" {
+		tlsConfig := auditLogConfig.SyslogTLS
+		if (tlsConfig.CertFile == "") != (tlsConfig.KeyFile == "") {
+			return fmt.Errorf("NetworkPolicyAuditLog SyslogTLS CertFile and KeyFile should be provided together")
+		}
+		o.auditLogConfig.SyslogTLS = &networkpolicy.AuditLogTLSConfig{
+			CAFile:     tlsConfig.CAFile,
+			CertFile:   tlsConfig.CertFile,
+			KeyFile:    tlsConfig.KeyFile,
+			ServerName: tlsConfig.ServerName,
+		}
+	}
+	return nil
+}
+
+func (o *Options) validateFlowClickHouseConfig() error {
+	o.flowClickHouse = nil
+	chConfig := o.config.FlowClickHouse
+	if !chConfig.Enable {
+		return nil
+	}
+	u, err := url.Parse(chConfig.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("FlowClickHouse URL %q is not provided in right format, it should be http(s)://<host>:<port>", chConfig.URL)
+	}
+	clickHouse := &exporter.ClickHouseConfig{
+		URL:            chConfig.URL,
+		Database:       chConfig.Database,
+		Table:          chConfig.Table,
+		Username:       chConfig.Username,
+		Password:       os.Getenv(clickHousePasswordEnvKey),
+		BatchSize:      chConfig.BatchSize,
+		CommitInterval: defaultClickHouseCommitInterval,
+		TTL:            defaultClickHouseTTL,
+	}
+	if chConfig.Password != "" {
+		klog.Warningf("FlowClickHouse Password is deprecated as it is stored in plaintext in the ConfigMap, use the %s environment variable instead", clickHousePasswordEnvKey)
+		if clickHouse.Password == "" {
+			clickHouse.Password = chConfig.Password
+		}
+	}
+	if clickHouse.Database == "" {
+		clickHouse.Database = defaultClickHouseDatabase
+	}
+	if clickHouse.Table == "" {
+		clickHouse.Table = defaultClickHouseTable
+	}
+	// The names are interpolated in the queries sent to ClickHouse, so only plain identifiers are accepted.
+	if !clickHouseIdentifierRegexp.MatchString(clickHouse.Database) {
+		return fmt.Errorf("FlowClickHouse Database %q is not a valid identifier, it should match %s", clickHouse.Database, clickHouseIdentifierRegexp)
+	}
+	if !clickHouseIdentifierRegexp.MatchString(clickHouse.Table) {
+		return fmt.Errorf("FlowClickHouse Table %q is not a valid identifier, it should match %s", clickHouse.Table, clickHouseIdentifierRegexp)
+	}
+	if clickHouse.BatchSize == 0 {
+		clickHouse.BatchSize = defaultClickHouseBatchSize
+	} else if clickHouse.BatchSize < 0 {
+		return fmt.Errorf("FlowClickHouse BatchSize should be greater than zero")
+	}
+	if chConfig.CommitInterval != "" {
+		clickHouse.CommitInterval, err = time.ParseDuration(chConfig.CommitInterval)
+		if err != nil {
+			return fmt.Errorf("FlowClickHouse CommitInterval is not provided in right format: %v", err)
+		}
+		if clickHouse.CommitInterval < time.Second {
+			return fmt.Errorf("FlowClickHouse CommitInterval should be greater than or equal to one second")
+		}
+	}
+	if chConfig.TTL != "" {
+		clickHouse.TTL, err = time.ParseDuration(chConfig.TTL)
+		if err != nil {
+			return fmt.Errorf("FlowClickHouse TTL is not provided in right format: %v", err)
+		}
+		if clickHouse.TTL < 0 {
+			return fmt.Errorf("FlowClickHouse TTL should not be negative")
+		}
+	}
+	o.flowClickHouse = clickHouse
+	return nil
+}
+
+func (o *Options) validateFlowArchiveConfig() error {
+	o.flowArchive = nil
+	archiveConfig := o.config.FlowArchive
+	if !archiveConfig.Enable {
+		return nil
+	}
+	archive := &exporter.ArchiveConfig{
+		Directory:      archiveConfig.Directory,
+		MaxSize:        int64(archiveConfig.MaxSize) * 1024 * 1024,
+		MaxAge:         defaultArchiveMaxAge,
+		CommitInterval: defaultArchiveCommitInterval,
+	}
+	if archive.Directory == "" {
+		archive.Directory = exporter.DefaultArchiveDirectory
+	}
+	if archiveConfig.MaxSize == 0 {
+		archive.MaxSize = defaultArchiveMaxSize * 1024 * 1024
+	} else if archiveConfig.MaxSize < 0 {
+		return fmt.Errorf("FlowArchive MaxSize should be greater than zero")
+	}
+	var err error
+	if archiveConfig.MaxAge != "" {
+		archive.MaxAge, err = time.ParseDuration(archiveConfig.MaxAge)
+		if err != nil {
+			return fmt.Errorf("FlowArchive MaxAge is not provided in right format: %v", err)
+		}
+		if archive.MaxAge < 0 {
+			return fmt.Errorf("FlowArchive MaxAge should not be negative")
+		}
+	}
+	if archiveConfig.CommitInterval != "" {
+		archive.CommitInterval, err = time.ParseDuration(archiveConfig.CommitInterval)
+		if err != nil {
+			return fmt.Errorf("FlowArchive CommitInterval is not provided in right format: %v", err)
+		}
+		if archive.CommitInterval < time.Second {
+			return fmt.Errorf("FlowArchive CommitInterval should be greater than or equal to one second")
+		}
+	}
+	if archiveConfig.HMACKeyFile != "" {
+		key, err := ioutil.ReadFile(archiveConfig.HMACKeyFile)
+		if err != nil {
+			return fmt.Errorf("error reading FlowArchive HMACKeyFile: %v", err)
+		}
+		archive.HMACKey = bytes.TrimSpace(key)
+		if len(archive.HMACKey) == 0 {
+			return fmt.Errorf("FlowArchive HMACKeyFile should not be empty")
+		}
 	}
+	o.flowArchive = archive
 	return nil
 }
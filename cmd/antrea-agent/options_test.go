@@ -2,11 +2,22 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/networkpolicy"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/exporter"
 	"github.com/vmware-tanzu/antrea/pkg/features"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
 )
 
 func TestOptions_validateFlowExporterConfig(t *testing.T) {
@@ -51,3 +62,636 @@ func TestOptions_validateFlowExporterConfig(t *testing.T) {
 	}
 
 }
+
+func TestOptions_validateFlowExportTimeouts(t *testing.T) {
+	// Enable flow exporter
+	enableFlowExporter := map[string]bool{
+		"FlowExporter": true,
+	}
+	features.DefaultMutableFeatureGate.SetFromMap(enableFlowExporter)
+	testcases := []struct {
+		// input
+		activeFlowTimeout   string
+		idleFlowTimeout     string
+		flowExportFrequency uint
+		// expectations
+		expActiveFlowTimeoutStr string
+		expIdleFlowTimeoutStr   string
+		expError                error
+	}{
+		{activeFlowTimeout: "60s", idleFlowTimeout: "15s", expActiveFlowTimeoutStr: "1m0s", expIdleFlowTimeoutStr: "15s", expError: nil},
+		{activeFlowTimeout: "10s", idleFlowTimeout: "1s", expActiveFlowTimeoutStr: "10s", expIdleFlowTimeoutStr: "1s", expError: nil},
+		{activeFlowTimeout: "60ss", idleFlowTimeout: "15s", expError: fmt.Errorf("ActiveFlowExportTimeout is not provided in right format: ")},
+		{activeFlowTimeout: "60s", idleFlowTimeout: "15", expError: fmt.Errorf("IdleFlowExportTimeout is not provided in right format: ")},
+		{idleFlowTimeout: "15s", flowExportFrequency: 6, expActiveFlowTimeoutStr: "30s", expIdleFlowTimeoutStr: "15s", expError: nil},
+		{activeFlowTimeout: "60s", idleFlowTimeout: "15s", flowExportFrequency: 6, expActiveFlowTimeoutStr: "1m0s", expIdleFlowTimeoutStr: "15s", expError: nil},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.FlowCollectorAddr = "192.168.1.100:2002"
+		testOptions.config.FlowPollInterval = "5s"
+		testOptions.config.ActiveFlowExportTimeout = tc.activeFlowTimeout
+		testOptions.config.IdleFlowExportTimeout = tc.idleFlowTimeout
+		testOptions.config.FlowExportFrequency = tc.flowExportFrequency
+		err := testOptions.validateFlowExporterConfig()
+
+		if tc.expError != nil {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expActiveFlowTimeoutStr, testOptions.activeFlowTimeout.String())
+			assert.Equal(t, tc.expIdleFlowTimeoutStr, testOptions.idleFlowTimeout.String())
+		}
+	}
+}
+
+func TestOptions_validateFlowResyncInterval(t *testing.T) {
+	// Enable flow exporter
+	enableFlowExporter := map[string]bool{
+		"FlowExporter": true,
+	}
+	features.DefaultMutableFeatureGate.SetFromMap(enableFlowExporter)
+	testcases := []struct {
+		// input
+		resyncInterval string
+		// expectations
+		expResyncInterval time.Duration
+		expError          bool
+	}{
+		{resyncInterval: "2m", expResyncInterval: 2 * time.Minute},
+		{resyncInterval: "0s", expResyncInterval: 0},
+		{resyncInterval: "1s", expError: true},
+		{resyncInterval: "60", expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.FlowCollectorAddr = "192.168.1.100:2002"
+		testOptions.config.FlowPollInterval = "5s"
+		testOptions.config.FlowResyncInterval = tc.resyncInterval
+		err := testOptions.validateFlowExporterConfig()
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expResyncInterval, testOptions.resyncInterval)
+		}
+	}
+}
+
+func TestOptions_validateFlowExportFilter(t *testing.T) {
+	// Enable flow exporter
+	enableFlowExporter := map[string]bool{
+		"FlowExporter": true,
+	}
+	features.DefaultMutableFeatureGate.SetFromMap(enableFlowExporter)
+	testcases := []struct {
+		// input
+		podSelector string
+		cidrs       []string
+		// expectations
+		expPodSelectorStr string
+		expCIDRsLen       int
+		expError          bool
+	}{
+		{podSelector: "", cidrs: nil, expCIDRsLen: 0},
+		{podSelector: "app=web,tier!=db", cidrs: []string{"10.10.0.0/16", "fd00::/64"}, expPodSelectorStr: "app=web,tier!=db", expCIDRsLen: 2},
+		{podSelector: "app==web==", expError: true},
+		{cidrs: []string{"10.10.0.0"}, expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.FlowCollectorAddr = "192.168.1.100:2002"
+		testOptions.config.FlowExportFilter.PodSelector = tc.podSelector
+		testOptions.config.FlowExportFilter.CIDRs = tc.cidrs
+		err := testOptions.validateFlowExporterConfig()
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			if tc.expPodSelectorStr != "" {
+				assert.Equal(t, tc.expPodSelectorStr, testOptions.flowExportPodSelector.String())
+			} else {
+				assert.Nil(t, testOptions.flowExportPodSelector)
+			}
+			assert.Len(t, testOptions.flowExportCIDRs, tc.expCIDRsLen)
+		}
+	}
+}
+
+func TestOptions_validateInterNodeFlowExporter(t *testing.T) {
+	testcases := []struct {
+		// input
+		exportedBy           string
+		destinationNodeFlows bool
+		// expectations
+		expExporter connections.InterNodeFlowExporter
+		expError    bool
+	}{
+		{exportedBy: "", expExporter: connections.InterNodeFlowExporterSource},
+		{exportedBy: "", destinationNodeFlows: true, expExporter: connections.InterNodeFlowExporterBoth},
+		{exportedBy: "destination", expExporter: connections.InterNodeFlowExporterDestination},
+		{exportedBy: "both", destinationNodeFlows: true, expExporter: connections.InterNodeFlowExporterBoth},
+		{exportedBy: "source", destinationNodeFlows: true, expError: true},
+		{exportedBy: "aggregator", expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.FlowExportFilter.InterNodeFlowsExportedBy = tc.exportedBy
+		testOptions.config.FlowExportFilter.DestinationNodeFlows = tc.destinationNodeFlows
+		err := testOptions.validateInterNodeFlowExporter()
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expExporter, testOptions.interNodeFlowExporter)
+		}
+	}
+}
+
+func TestOptions_validateFlowCollectorTLS(t *testing.T) {
+	// Enable flow exporter
+	enableFlowExporter := map[string]bool{
+		"FlowExporter": true,
+	}
+	features.DefaultMutableFeatureGate.SetFromMap(enableFlowExporter)
+	testcases := []struct {
+		// input
+		collector string
+		tlsConfig FlowCollectorTLSConfig
+		// expectations
+		expTLS   bool
+		expError bool
+	}{
+		{collector: "192.168.1.100:2002:tls", tlsConfig: FlowCollectorTLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}, expTLS: true},
+		{collector: "192.168.1.100:2002:tls", tlsConfig: FlowCollectorTLSConfig{CertFile: "tls.crt"}, expError: true},
+		{collector: "192.168.1.100:2002:tcp", tlsConfig: FlowCollectorTLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}, expTLS: false},
+		{collector: "192.168.1.100:2002:dtls", expError: true},
+		{collector: "192.168.1.100:2002:udp", tlsConfig: FlowCollectorTLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"}, expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.FlowCollectorAddr = tc.collector
+		testOptions.config.FlowCollectorTLS = tc.tlsConfig
+		err := testOptions.validateFlowExporterConfig()
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, "tcp", testOptions.flowCollector.Network())
+			assert.Equal(t, tc.expTLS, testOptions.flowCollectorTLS != nil)
+		}
+	}
+}
+
+func TestOptions_validateFlowClickHouseConfig(t *testing.T) {
+	// Enable flow exporter
+	enableFlowExporter := map[string]bool{
+		"FlowExporter": true,
+	}
+	features.DefaultMutableFeatureGate.SetFromMap(enableFlowExporter)
+	testcases := []struct {
+		// input
+		collector  string
+		clickHouse FlowClickHouseConfig
+		password   string
+		// expectations
+		expClickHouse *exporter.ClickHouseConfig
+		expError      bool
+	}{
+		{collector: "", expError: true},
+		{collector: "192.168.1.100:2002", clickHouse: FlowClickHouseConfig{URL: "http://clickhouse:8123"}},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123"}, expClickHouse: &exporter.ClickHouseConfig{
+			URL:            "http://clickhouse:8123",
+			Database:       "default",
+			Table:          "flows",
+			BatchSize:      1000,
+			CommitInterval: 8 * time.Second,
+			TTL:            12 * time.Hour,
+		}},
+		{collector: "192.168.1.100:2002", clickHouse: FlowClickHouseConfig{Enable: true, URL: "https://clickhouse:8443", Database: "antrea", Table: "records", Username: "user", Password: "pass", BatchSize: 100, CommitInterval: "1m", TTL: "0s"}, expClickHouse: &exporter.ClickHouseConfig{
+			URL:            "https://clickhouse:8443",
+			Database:       "antrea",
+			Table:          "records",
+			Username:       "user",
+			Password:       "pass",
+			BatchSize:      100,
+			CommitInterval: time.Minute,
+			TTL:            0,
+		}},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123", Password: "pass"}, password: "secret", expClickHouse: &exporter.ClickHouseConfig{
+			URL:            "http://clickhouse:8123",
+			Database:       "default",
+			Table:          "flows",
+			Password:       "secret",
+			BatchSize:      1000,
+			CommitInterval: 8 * time.Second,
+			TTL:            12 * time.Hour,
+		}},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "clickhouse:8123"}, expError: true},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123", BatchSize: -1}, expError: true},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123", CommitInterval: "100ms"}, expError: true},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123", Database: "default; DROP DATABASE system"}, expError: true},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123", Table: "1flows"}, expError: true},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123", Table: "flows.v2"}, expError: true},
+		{collector: "", clickHouse: FlowClickHouseConfig{Enable: true, URL: "http://clickhouse:8123", TTL: "12"}, expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.FlowCollectorAddr = tc.collector
+		testOptions.config.FlowClickHouse = tc.clickHouse
+		os.Setenv(clickHousePasswordEnvKey, tc.password)
+		err := testOptions.validateFlowExporterConfig()
+		os.Unsetenv(clickHousePasswordEnvKey)
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expClickHouse, testOptions.flowClickHouse)
+		}
+	}
+}
+
+func TestOptions_validateFlowArchiveConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	hmacKeyFile := filepath.Join(dir, "hmac.key")
+	require.NoError(t, ioutil.WriteFile(hmacKeyFile, []byte("archive-key\n"), 0600))
+
+	// Enable flow exporter
+	enableFlowExporter := map[string]bool{
+		"FlowExporter": true,
+	}
+	features.DefaultMutableFeatureGate.SetFromMap(enableFlowExporter)
+	testcases := []struct {
+		// input
+		collector string
+		archive   FlowArchiveConfig
+		// expectations
+		expArchive *exporter.ArchiveConfig
+		expError   bool
+	}{
+		{collector: "192.168.1.100:2002", archive: FlowArchiveConfig{Directory: "/var/log/flows"}},
+		{collector: "", archive: FlowArchiveConfig{Enable: true}, expArchive: &exporter.ArchiveConfig{
+			Directory:      exporter.DefaultArchiveDirectory,
+			MaxSize:        100 * 1024 * 1024,
+			MaxAge:         720 * time.Hour,
+			CommitInterval: 10 * time.Second,
+		}},
+		{collector: "192.168.1.100:2002", archive: FlowArchiveConfig{Enable: true, Directory: "/var/log/flows", MaxSize: 10, MaxAge: "0s", CommitInterval: "1m"}, expArchive: &exporter.ArchiveConfig{
+			Directory:      "/var/log/flows",
+			MaxSize:        10 * 1024 * 1024,
+			MaxAge:         0,
+			CommitInterval: time.Minute,
+		}},
+		{collector: "", archive: FlowArchiveConfig{Enable: true, MaxSize: -1}, expError: true},
+		{collector: "", archive: FlowArchiveConfig{Enable: true, MaxAge: "30d"}, expError: true},
+		{collector: "", archive: FlowArchiveConfig{Enable: true, MaxAge: "-1h"}, expError: true},
+		{collector: "", archive: FlowArchiveConfig{Enable: true, CommitInterval: "100ms"}, expError: true},
+		{collector: "", archive: FlowArchiveConfig{Enable: true, HMACKeyFile: hmacKeyFile}, expArchive: &exporter.ArchiveConfig{
+			Directory:      exporter.DefaultArchiveDirectory,
+			MaxSize:        100 * 1024 * 1024,
+			MaxAge:         720 * time.Hour,
+			CommitInterval: 10 * time.Second,
+			HMACKey:        []byte("archive-key"),
+		}},
+		{collector: "", archive: FlowArchiveConfig{Enable: true, HMACKeyFile: hmacKeyFile + ".missing"}, expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.FlowCollectorAddr = tc.collector
+		testOptions.config.FlowArchive = tc.archive
+		err := testOptions.validateFlowExporterConfig()
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expArchive, testOptions.flowArchive)
+		}
+	}
+}
+
+func TestOptions_validateNetworkPolicyAuditLogConfig(t *testing.T) {
+	testcases := []struct {
+		auditLog          NetworkPolicyAuditLogConfig
+		expError          bool
+		expAuditLogConfig *networkpolicy.AuditLogConfig
+	}{
+		{auditLog: NetworkPolicyAuditLogConfig{}, expAuditLogConfig: &networkpolicy.AuditLogConfig{}},
+		{auditLog: NetworkPolicyAuditLogConfig{SyslogAddr: "192.168.1.100:514"}, expAuditLogConfig: &networkpolicy.AuditLogConfig{
+			SyslogNetwork: "udp",
+			SyslogAddress: "192.168.1.100:514",
+		}},
+		{auditLog: NetworkPolicyAuditLogConfig{SyslogAddr: "192.168.1.100:601:tcp", RateLimit: 10}, expAuditLogConfig: &networkpolicy.AuditLogConfig{
+			SyslogNetwork: "tcp",
+			SyslogAddress: "192.168.1.100:601",
+			RateLimit:     10,
+		}},
+		{auditLog: NetworkPolicyAuditLogConfig{SyslogAddr: "192.168.1.100:6514:tls", SyslogTLS: SyslogTLSConfig{CAFile: "/etc/ca.crt"}}, expAuditLogConfig: &networkpolicy.AuditLogConfig{
+			SyslogNetwork: "tls",
+			SyslogAddress: "192.168.1.100:6514",
+			SyslogTLS:     &networkpolicy.AuditLogTLSConfig{CAFile: "/etc/ca.crt"},
+		}},
+		{auditLog: NetworkPolicyAuditLogConfig{WebhookURL: "https://siem.example.com/antrea"}, expAuditLogConfig: &networkpolicy.AuditLogConfig{
+			WebhookURL: "https://siem.example.com/antrea",
+		}},
+		{auditLog: NetworkPolicyAuditLogConfig{SyslogAddr: "192.168.1.100:514:sctp"}, expError: true},
+		{auditLog: NetworkPolicyAuditLogConfig{SyslogAddr: "192.168.1.100"}, expError: true},
+		{auditLog: NetworkPolicyAuditLogConfig{SyslogAddr: "192.168.1.100:6514:tls", SyslogTLS: SyslogTLSConfig{CertFile: "/etc/tls.crt"}}, expError: true},
+		{auditLog: NetworkPolicyAuditLogConfig{WebhookURL: "siem.example.com/antrea"}, expError: true},
+		{auditLog: NetworkPolicyAuditLogConfig{RateLimit: -1}, expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.NetworkPolicyAuditLog = tc.auditLog
+		testOptions.setDefaults()
+		err := testOptions.validateNetworkPolicyAuditLogConfig()
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			tc.expAuditLogConfig.File = networkpolicy.DefaultAuditLogFile
+			tc.expAuditLogConfig.MaxSize = defaultAuditLogMaxSize
+			tc.expAuditLogConfig.MaxBackups = defaultAuditLogMaxBackups
+			assert.Equal(t, tc.expAuditLogConfig, testOptions.auditLogConfig)
+		}
+	}
+}
+
+func TestOptions_validateNodeCapacityWarningThresholds(t *testing.T) {
+	testcases := []struct {
+		thresholds NodeCapacityWarningThresholdsConfig
+		expError   bool
+	}{
+		{thresholds: NodeCapacityWarningThresholdsConfig{}},
+		{thresholds: NodeCapacityWarningThresholdsConfig{ConntrackUsagePercent: 90, OVSFlowCount: 100000, OVSGroupCount: 1000}},
+		{thresholds: NodeCapacityWarningThresholdsConfig{ConntrackUsagePercent: 101}, expError: true},
+		{thresholds: NodeCapacityWarningThresholdsConfig{ConntrackUsagePercent: -1}, expError: true},
+		{thresholds: NodeCapacityWarningThresholdsConfig{OVSFlowCount: -1}, expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: &AgentConfig{NodeCapacityWarningThresholds: tc.thresholds},
+		}
+		err := testOptions.validateNodeCapacityWarningThresholds()
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func TestOptions_validateAgentInfoReportInterval(t *testing.T) {
+	testcases := []struct {
+		interval    string
+		expInterval time.Duration
+		expError    bool
+	}{
+		{interval: "", expInterval: defaultAgentInfoReportInterval},
+		{interval: "5m", expInterval: 5 * time.Minute},
+		{interval: "10s", expInterval: 10 * time.Second},
+		{interval: "5s", expError: true},
+		{interval: "1x", expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: &AgentConfig{AgentInfoReportInterval: tc.interval},
+		}
+		err := testOptions.validateAgentInfoReportInterval()
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expInterval, testOptions.agentInfoReportInterval)
+		}
+	}
+}
+
+func TestOptions_validateTunnelConfig(t *testing.T) {
+	testcases := []struct {
+		tunnelType string
+		tunnelPort int
+		tunnelTTL  string
+		expError   bool
+	}{
+		{tunnelType: "geneve", tunnelPort: 6082, tunnelTTL: "inherit"},
+		{tunnelType: "vxlan", tunnelPort: 8472, tunnelTTL: "32"},
+		{tunnelType: "gre", tunnelTTL: "255"},
+		{tunnelType: "gre", tunnelPort: 6082, expError: true},
+		{tunnelType: "geneve", tunnelPort: 65536, expError: true},
+		{tunnelType: "geneve", tunnelTTL: "0", expError: true},
+	}
+	for _, tc := range testcases {
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.TunnelType = tc.tunnelType
+		testOptions.config.TunnelPort = tc.tunnelPort
+		testOptions.config.TunnelTTL = tc.tunnelTTL
+		err := testOptions.validateTunnelConfig()
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func TestOptions_validateFeatureDependencies(t *testing.T) {
+	testcases := []struct {
+		name         string
+		featureGates map[string]bool
+		encapMode    config.TrafficEncapModeType
+		mutateConfig func(c *AgentConfig)
+		expError     bool
+	}{
+		{
+			name:         "noEncap with AntreaProxy",
+			featureGates: map[string]bool{"AntreaProxy": true},
+			encapMode:    config.TrafficEncapModeNoEncap,
+		},
+		{
+			name:      "noEncap without AntreaProxy",
+			encapMode: config.TrafficEncapModeNoEncap,
+			expError:  true,
+		},
+		{
+			name:         "Egress in encap mode",
+			featureGates: map[string]bool{"Egress": true},
+			encapMode:    config.TrafficEncapModeEncap,
+		},
+		{
+			name:         "Egress in hybrid mode",
+			featureGates: map[string]bool{"AntreaProxy": true, "Egress": true},
+			encapMode:    config.TrafficEncapModeHybrid,
+			expError:     true,
+		},
+		{
+			name:         "Egress with IPSec",
+			featureGates: map[string]bool{"Egress": true},
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) { c.EnableIPSecTunnel = true },
+			expError:     true,
+		},
+		{
+			name:         "Egress SNAT ports per Pod",
+			featureGates: map[string]bool{"Egress": true},
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) { c.EgressSNATPortsPerPod = 1024 },
+			expError:     runtime.GOOS == "windows",
+		},
+		{
+			name:         "Egress SNAT ports per Pod too small",
+			featureGates: map[string]bool{"Egress": true},
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) { c.EgressSNATPortsPerPod = 32 },
+			expError:     true,
+		},
+		{
+			name:         "Egress SNAT ports per Pod too large",
+			featureGates: map[string]bool{"Egress": true},
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) { c.EgressSNATPortsPerPod = 65536 },
+			expError:     true,
+		},
+		{
+			name:         "FlowExporter without AntreaProxy",
+			featureGates: map[string]bool{"FlowExporter": true},
+			encapMode:    config.TrafficEncapModeEncap,
+		},
+		{
+			name:         "FlowRTT",
+			featureGates: map[string]bool{"AntreaProxy": true, "FlowExporter": true},
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) { c.FlowRTT = true },
+			expError:     runtime.GOOS == "windows",
+		},
+		{
+			name:         "denied connections with standalone flow exporter",
+			featureGates: map[string]bool{"AntreaProxy": true, "FlowExporter": true},
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) {
+				c.FlowExportDeniedConnections = true
+				c.FlowExporterStandalone = true
+			},
+			expError: true,
+		},
+		{
+			name:         "standalone flow exporter without FlowExporter",
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) { c.FlowExporterStandalone = true },
+			expError:     true,
+		},
+		{
+			name:         "AntreaIPAM in noEncap mode",
+			featureGates: map[string]bool{"AntreaProxy": true, "AntreaIPAM": true},
+			encapMode:    config.TrafficEncapModeNoEncap,
+			expError:     runtime.GOOS == "windows",
+		},
+		{
+			name:         "AntreaIPAM in networkPolicyOnly mode",
+			featureGates: map[string]bool{"AntreaProxy": true, "AntreaIPAM": true},
+			encapMode:    config.TrafficEncapModeNetworkPolicyOnly,
+			expError:     true,
+		},
+		{
+			name:         "Traceflow with VXLAN tunnel",
+			featureGates: map[string]bool{"Traceflow": true},
+			encapMode:    config.TrafficEncapModeEncap,
+			mutateConfig: func(c *AgentConfig) { c.TunnelType = ovsconfig.VXLANTunnel },
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			featureGates := map[string]bool{"AntreaProxy": false, "Egress": false, "FlowExporter": false, "Traceflow": false, "AntreaIPAM": false}
+			for feature, enabled := range tc.featureGates {
+				featureGates[feature] = enabled
+			}
+			features.DefaultMutableFeatureGate.SetFromMap(featureGates)
+			testOptions := &Options{
+				config: &AgentConfig{TunnelType: ovsconfig.GeneveTunnel},
+			}
+			if tc.mutateConfig != nil {
+				tc.mutateConfig(testOptions.config)
+			}
+			err := testOptions.validateFeatureDependencies(tc.encapMode)
+
+			if tc.expError {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestOptions_validateAntreaProxyConfig(t *testing.T) {
+	testcases := []struct {
+		antreaProxy       bool
+		nodePort          bool
+		hostNetwork       bool
+		nodePortAddresses []string
+		loadBalancerMode  string
+		localFastPath     bool
+		encapMode         config.TrafficEncapModeType
+		expError          bool
+	}{
+		{antreaProxy: true, nodePort: true, nodePortAddresses: []string{"192.168.0.0/16"}, encapMode: config.TrafficEncapModeEncap},
+		{antreaProxy: true, nodePort: true, encapMode: config.TrafficEncapModeNoEncap, expError: true},
+		{antreaProxy: true, nodePort: true, nodePortAddresses: []string{"192.168.0.1"}, encapMode: config.TrafficEncapModeEncap, expError: true},
+		{antreaProxy: false, nodePort: true, encapMode: config.TrafficEncapModeEncap, expError: true},
+		{antreaProxy: true, hostNetwork: true, encapMode: config.TrafficEncapModeEncap},
+		{antreaProxy: true, hostNetwork: true, encapMode: config.TrafficEncapModeHybrid, expError: true},
+		{antreaProxy: false, hostNetwork: true, encapMode: config.TrafficEncapModeEncap, expError: true},
+		{antreaProxy: false, encapMode: config.TrafficEncapModeNoEncap},
+		{antreaProxy: true, nodePort: true, loadBalancerMode: "dsr", encapMode: config.TrafficEncapModeEncap},
+		{antreaProxy: true, hostNetwork: true, loadBalancerMode: "dsr", encapMode: config.TrafficEncapModeEncap, expError: true},
+		{antreaProxy: true, nodePort: true, loadBalancerMode: "ipvs", encapMode: config.TrafficEncapModeEncap, expError: true},
+		{antreaProxy: true, nodePort: true, localFastPath: true, encapMode: config.TrafficEncapModeEncap},
+		{antreaProxy: true, hostNetwork: true, localFastPath: true, encapMode: config.TrafficEncapModeEncap, expError: true},
+		{antreaProxy: true, nodePort: true, loadBalancerMode: "dsr", localFastPath: true, encapMode: config.TrafficEncapModeEncap, expError: true},
+	}
+	for _, tc := range testcases {
+		features.DefaultMutableFeatureGate.SetFromMap(map[string]bool{"AntreaProxy": tc.antreaProxy})
+		testOptions := &Options{
+			config: new(AgentConfig),
+		}
+		testOptions.config.AntreaProxy.NodePort = tc.nodePort
+		testOptions.config.AntreaProxy.HostNetwork = tc.hostNetwork
+		testOptions.config.AntreaProxy.NodePortAddresses = tc.nodePortAddresses
+		testOptions.config.AntreaProxy.LoadBalancerMode = tc.loadBalancerMode
+		testOptions.config.AntreaProxy.LoadBalancerLocalFastPath = tc.localFastPath
+		err := testOptions.validateAntreaProxyConfig(tc.encapMode)
+
+		if tc.expError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
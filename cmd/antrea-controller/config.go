@@ -39,4 +39,45 @@ type ControllerConfig struct {
 	// antrea-controller container.
 	// Defaults to true.
 	SelfSignedCert bool `yaml:"selfSignedCert,omitempty"`
+	// The heap memory in MiB above which antrea-controller enters degraded mode, in which non-critical work
+	// (re-evaluation of rule schedules, stats aggregation) is slowed down and the ControllerDegraded condition
+	// of the AntreaControllerInfo is set to True. It should be lower than the memory limit of the
+	// antrea-controller container. Defaults to 0, which disables the memory watermark.
+	MemoryWatermarkMiB int `yaml:"memoryWatermarkMiB,omitempty"`
+	// The total number of items waiting in the NetworkPolicy work queues above which antrea-controller enters
+	// degraded mode. Defaults to 0, which disables the queue watermark.
+	QueueWatermark int `yaml:"queueWatermark,omitempty"`
+	// List of Namespaces whose Pods and ExternalEntities are exempt from Antrea-native policies
+	// (ClusterNetworkPolicies and Antrea NetworkPolicies), regardless of the selectors of the policies.
+	// K8s NetworkPolicies are still enforced in these Namespaces.
+	ExemptNamespaces []string `yaml:"exemptNamespaces,omitempty"`
+	// List of Namespaces to which an implicit default-deny ingress and egress policy is applied, so that their
+	// Pods only allow the traffic explicitly allowed by NetworkPolicies. The policy can also be enabled or
+	// disabled for a Namespace with the "policy.antrea.tanzu.vmware.com/default-deny" annotation set to "true"
+	// or "false", which overrides this list.
+	DefaultDenyNamespaces []string `yaml:"defaultDenyNamespaces,omitempty"`
+	// The number of OVS flows the ipBlocks of a single K8s NetworkPolicy may generate on a Node, which grows
+	// with the number of except CIDRs. Above it, the ipBlocks are aggregated into fewer prefixes, and if that's
+	// not enough, the ipBlocks of the rules generating the most flows are not enforced, so the traffic they
+	// allow is dropped. Both cases are reported with an Event on the NetworkPolicy. Defaults to 10000. 0
+	// disables the limit.
+	IPBlockFlowLimit int `yaml:"ipBlockFlowLimit,omitempty"`
+	// NodeIPAM configures the NodeIPAM controller of antrea-controller, which allocates the PodCIDRs of the
+	// Nodes like kube-controller-manager does when it's started with "--allocate-node-cidrs".
+	NodeIPAM NodeIPAMConfig `yaml:"nodeIPAM"`
+}
+
+type NodeIPAMConfig struct {
+	// Enable the NodeIPAM controller. It should only be enabled when kube-controller-manager doesn't allocate
+	// the PodCIDRs of the Nodes, otherwise both may allocate different PodCIDRs to the same Node.
+	// Defaults to false.
+	EnableNodeIPAM bool `yaml:"enableNodeIPAM,omitempty"`
+	// The CIDR ranges the PodCIDRs of the Nodes are allocated from. At most one IPv4 and one IPv6 CIDR can be
+	// provided, in which case each Node is allocated a PodCIDR of each IP family. Required when the NodeIPAM
+	// controller is enabled.
+	ClusterCIDRs []string `yaml:"clusterCIDRs,omitempty"`
+	// The mask size of the IPv4 PodCIDRs allocated to the Nodes. Defaults to 24.
+	NodeCIDRMaskSizeIPv4 int `yaml:"nodeCIDRMaskSizeIPv4,omitempty"`
+	// The mask size of the IPv6 PodCIDRs allocated to the Nodes. Defaults to 64.
+	NodeCIDRMaskSizeIPv6 int `yaml:"nodeCIDRMaskSizeIPv6,omitempty"`
 }
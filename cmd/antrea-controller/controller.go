@@ -22,25 +22,33 @@ import (
 	"path"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	genericopenapi "k8s.io/apiserver/pkg/endpoints/openapi"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	genericoptions "k8s.io/apiserver/pkg/server/options"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	aggregatorclientset "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 
 	"github.com/vmware-tanzu/antrea/pkg/apiserver"
+	antreaauthorization "github.com/vmware-tanzu/antrea/pkg/apiserver/authorization"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/certificate"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/openapi"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/storage"
 	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+	"github.com/vmware-tanzu/antrea/pkg/controller/egress"
 	"github.com/vmware-tanzu/antrea/pkg/controller/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy"
 	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
+	"github.com/vmware-tanzu/antrea/pkg/controller/nodeipam"
 	"github.com/vmware-tanzu/antrea/pkg/controller/querier"
 	"github.com/vmware-tanzu/antrea/pkg/controller/stats"
 	"github.com/vmware-tanzu/antrea/pkg/controller/traceflow"
+	"github.com/vmware-tanzu/antrea/pkg/controller/watermark"
 	"github.com/vmware-tanzu/antrea/pkg/features"
 	"github.com/vmware-tanzu/antrea/pkg/k8s"
 	"github.com/vmware-tanzu/antrea/pkg/log"
@@ -73,6 +81,7 @@ var allowedPaths = []string{
 	"/validate/tier",
 	"/validate/acnp",
 	"/validate/anp",
+	"/validate/group",
 }
 
 // run starts Antrea Controller with the given options and waits for termination signal.
@@ -91,33 +100,58 @@ func run(o *Options) error {
 	namespaceInformer := informerFactory.Core().V1().Namespaces()
 	networkPolicyInformer := informerFactory.Networking().V1().NetworkPolicies()
 	nodeInformer := informerFactory.Core().V1().Nodes()
+	serviceInformer := informerFactory.Core().V1().Services()
 	cnpInformer := crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
 	externalEntityInformer := crdInformerFactory.Core().V1alpha1().ExternalEntities()
 	anpInformer := crdInformerFactory.Security().V1alpha1().NetworkPolicies()
 	tierInformer := crdInformerFactory.Security().V1alpha1().Tiers()
+	groupInformer := crdInformerFactory.Core().V1alpha1().Groups()
 	traceflowInformer := crdInformerFactory.Ops().V1alpha1().Traceflows()
+	egressInformer := crdInformerFactory.Core().V1alpha1().Egresses()
+	externalIPPoolInformer := crdInformerFactory.Core().V1alpha1().ExternalIPPools()
 
 	// Create Antrea object storage.
 	addressGroupStore := store.NewAddressGroupStore()
 	appliedToGroupStore := store.NewAppliedToGroupStore()
 	networkPolicyStore := store.NewNetworkPolicyStore()
 
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	defer eventBroadcaster.Shutdown()
+	eventRecorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "antrea-controller"})
+
+	// watermarkMonitor puts antrea-controller in degraded mode when the configured watermarks are exceeded, so that
+	// non-critical work is slowed down. It's nil if no watermark is configured.
+	var watermarkMonitor *watermark.Monitor
+	if o.config.MemoryWatermarkMiB > 0 || o.config.QueueWatermark > 0 {
+		watermarkMonitor = watermark.NewMonitor(uint64(o.config.MemoryWatermarkMiB)<<20, o.config.QueueWatermark)
+	}
+
 	networkPolicyController := networkpolicy.NewNetworkPolicyController(client,
 		crdClient,
 		podInformer,
 		namespaceInformer,
+		nodeInformer,
+		serviceInformer,
 		externalEntityInformer,
 		networkPolicyInformer,
 		cnpInformer,
 		anpInformer,
 		tierInformer,
+		groupInformer,
 		addressGroupStore,
 		appliedToGroupStore,
-		networkPolicyStore)
+		networkPolicyStore,
+		watermarkMonitor,
+		o.config.ExemptNamespaces,
+		o.config.DefaultDenyNamespaces,
+		o.config.IPBlockFlowLimit,
+		eventRecorder)
+	watermarkMonitor.AddQueue("networkpolicy", networkPolicyController.GetQueueLength)
 
 	endpointQuerier := networkpolicy.NewEndpointQuerier(networkPolicyController)
 
-	controllerQuerier := querier.NewControllerQuerier(networkPolicyController, o.config.APIPort)
+	controllerQuerier := querier.NewControllerQuerier(networkPolicyController, o.config.APIPort, watermarkMonitor)
 
 	controllerMonitor := monitor.NewControllerMonitor(crdClient, nodeInformer, controllerQuerier)
 
@@ -126,11 +160,38 @@ func run(o *Options) error {
 		traceflowController = traceflow.NewTraceflowController(crdClient, podInformer, traceflowInformer)
 	}
 
+	// egressController allocates the IPs of the Egresses from their ExternalIPPools.
+	var egressController *egress.Controller
+	if features.DefaultFeatureGate.Enabled(features.Egress) {
+		egressController = egress.NewEgressController(crdClient, egressInformer, externalIPPoolInformer)
+	}
+
+	// nodeIPAMController allocates the PodCIDRs of the Nodes when kube-controller-manager doesn't.
+	var nodeIPAMController *nodeipam.Controller
+	if o.config.NodeIPAM.EnableNodeIPAM {
+		var clusterCIDRs []*net.IPNet
+		for _, cidr := range o.config.NodeIPAM.ClusterCIDRs {
+			_, clusterCIDR, _ := net.ParseCIDR(cidr)
+			clusterCIDRs = append(clusterCIDRs, clusterCIDR)
+		}
+		nodeIPAMController, err = nodeipam.NewNodeIPAMController(client, nodeInformer, clusterCIDRs, o.config.NodeIPAM.NodeCIDRMaskSizeIPv4, o.config.NodeIPAM.NodeCIDRMaskSizeIPv6)
+		if err != nil {
+			return fmt.Errorf("error creating NodeIPAM controller: %v", err)
+		}
+	}
+
+	// networkPolicyStatusController aggregates the realization statuses reported by antrea-agents and updates the status
+	// of the Antrea-native policies.
+	var networkPolicyStatusController *networkpolicy.StatusController
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		networkPolicyStatusController = networkpolicy.NewStatusController(crdClient, networkPolicyStore, cnpInformer, anpInformer, eventRecorder)
+	}
+
 	// statsAggregator takes stats summaries from antrea-agents, aggregates them, and serves the Stats APIs with the
 	// aggregated data. For now it's only used for NetworkPolicy stats.
 	var statsAggregator *stats.Aggregator
 	if features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
-		statsAggregator = stats.NewAggregator(networkPolicyInformer, cnpInformer, anpInformer)
+		statsAggregator = stats.NewAggregator(networkPolicyInformer, cnpInformer, anpInformer, watermarkMonitor)
 	}
 
 	apiServerConfig, err := createAPIServerConfig(o.config.ClientConnection.Kubeconfig,
@@ -145,6 +206,7 @@ func run(o *Options) error {
 		endpointQuerier,
 		networkPolicyController,
 		statsAggregator,
+		networkPolicyStatusController,
 		o.config.EnablePrometheusMetrics)
 	if err != nil {
 		return fmt.Errorf("error creating API server config: %v", err)
@@ -171,8 +233,16 @@ func run(o *Options) error {
 
 	go controllerMonitor.Run(stopCh)
 
+	if watermarkMonitor != nil {
+		go watermarkMonitor.Run(stopCh)
+	}
+
 	go networkPolicyController.Run(stopCh)
 
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		go networkPolicyStatusController.Run(stopCh)
+	}
+
 	go apiServer.Run(stopCh)
 
 	if features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
@@ -187,6 +257,14 @@ func run(o *Options) error {
 		go traceflowController.Run(stopCh)
 	}
 
+	if features.DefaultFeatureGate.Enabled(features.Egress) {
+		go egressController.Run(stopCh)
+	}
+
+	if o.config.NodeIPAM.EnableNodeIPAM {
+		go nodeIPAMController.Run(stopCh)
+	}
+
 	<-stopCh
 	klog.Info("Stopping Antrea controller")
 	return nil
@@ -204,6 +282,7 @@ func createAPIServerConfig(kubeconfig string,
 	endpointQuerier networkpolicy.EndpointQuerier,
 	npController *networkpolicy.NetworkPolicyController,
 	statsAggregator *stats.Aggregator,
+	networkPolicyStatusController *networkpolicy.StatusController,
 	enableMetrics bool) (*apiserver.Config, error) {
 	secureServing := genericoptions.NewSecureServingOptions().WithLoopback()
 	authentication := genericoptions.NewDelegatingAuthenticationOptions()
@@ -232,6 +311,7 @@ func createAPIServerConfig(kubeconfig string,
 	if err := authorization.ApplyTo(&serverConfig.Authorization); err != nil {
 		return nil, err
 	}
+	serverConfig.Authorization.Authorizer = antreaauthorization.NewDebugAuthorizer(serverConfig.Authorization.Authorizer)
 
 	if err := os.MkdirAll(path.Dir(apiserver.TokenPath), os.ModeDir); err != nil {
 		return nil, fmt.Errorf("error when creating dirs of token file: %v", err)
@@ -255,5 +335,6 @@ func createAPIServerConfig(kubeconfig string,
 		statsAggregator,
 		controllerQuerier,
 		endpointQuerier,
-		npController), nil
+		npController,
+		networkPolicyStatusController), nil
 }
@@ -16,15 +16,24 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"net"
 
 	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis"
 	"github.com/vmware-tanzu/antrea/pkg/features"
 )
 
+const (
+	defaultIPBlockFlowLimit     = 10000
+	defaultNodeCIDRMaskSizeIPv4 = 24
+	defaultNodeCIDRMaskSizeIPv6 = 64
+)
+
 type Options struct {
 	// The path of configuration file.
 	configFile string
@@ -61,6 +70,77 @@ func (o *Options) validate(args []string) error {
 	if len(args) != 0 {
 		return errors.New("no positional arguments are supported")
 	}
+	if o.config.MemoryWatermarkMiB < 0 {
+		return fmt.Errorf("MemoryWatermarkMiB %d should not be negative", o.config.MemoryWatermarkMiB)
+	}
+	if o.config.QueueWatermark < 0 {
+		return fmt.Errorf("QueueWatermark %d should not be negative", o.config.QueueWatermark)
+	}
+	if o.config.IPBlockFlowLimit < 0 {
+		return fmt.Errorf("IPBlockFlowLimit %d should not be negative", o.config.IPBlockFlowLimit)
+	}
+	for _, ns := range o.config.ExemptNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("ExemptNamespaces contains an invalid Namespace name %q: %v", ns, errs)
+		}
+	}
+	for _, ns := range o.config.DefaultDenyNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("DefaultDenyNamespaces contains an invalid Namespace name %q: %v", ns, errs)
+		}
+	}
+	if o.config.NodeIPAM.EnableNodeIPAM {
+		if err := validateNodeIPAMConfig(&o.config.NodeIPAM); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateNodeIPAMConfig(c *NodeIPAMConfig) error {
+	if len(c.ClusterCIDRs) == 0 {
+		return errors.New("ClusterCIDRs must be provided when NodeIPAM is enabled")
+	}
+	if len(c.ClusterCIDRs) > 2 {
+		return fmt.Errorf("at most one IPv4 and one IPv6 CIDR can be provided in ClusterCIDRs, got %d CIDRs", len(c.ClusterCIDRs))
+	}
+	hasIPv4, hasIPv6 := false, false
+	for _, cidr := range c.ClusterCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("ClusterCIDRs contains an invalid CIDR %q: %v", cidr, err)
+		}
+		clusterMaskSize, _ := ipNet.Mask.Size()
+		if ipNet.IP.To4() != nil {
+			if hasIPv4 {
+				return errors.New("ClusterCIDRs contains more than one IPv4 CIDR")
+			}
+			hasIPv4 = true
+			if err := validateNodeCIDRMaskSize(cidr, clusterMaskSize, c.NodeCIDRMaskSizeIPv4, 32); err != nil {
+				return err
+			}
+		} else {
+			if hasIPv6 {
+				return errors.New("ClusterCIDRs contains more than one IPv6 CIDR")
+			}
+			hasIPv6 = true
+			if err := validateNodeCIDRMaskSize(cidr, clusterMaskSize, c.NodeCIDRMaskSizeIPv6, 128); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateNodeCIDRMaskSize checks that the Node mask size fits in the cluster CIDR. Like kube-controller-manager,
+// it limits the number of PodCIDRs of a cluster CIDR to 2^16.
+func validateNodeCIDRMaskSize(cidr string, clusterMaskSize, nodeMaskSize, bits int) error {
+	if nodeMaskSize < clusterMaskSize || nodeMaskSize > bits {
+		return fmt.Errorf("Node CIDR mask size %d is invalid for cluster CIDR %s", nodeMaskSize, cidr)
+	}
+	if nodeMaskSize-clusterMaskSize > 16 {
+		return fmt.Errorf("Node CIDR mask size %d is too large for cluster CIDR %s, the difference between the mask sizes must be at most 16", nodeMaskSize, cidr)
+	}
 	return nil
 }
 
@@ -71,7 +151,8 @@ func (o *Options) loadConfigFromFile(file string) (*ControllerConfig, error) {
 	}
 
 	c := ControllerConfig{
-		SelfSignedCert: true,
+		SelfSignedCert:   true,
+		IPBlockFlowLimit: defaultIPBlockFlowLimit,
 	}
 	err = yaml.UnmarshalStrict(data, &c)
 	if err != nil {
@@ -84,4 +165,10 @@ func (o *Options) setDefaults() {
 	if o.config.APIPort == 0 {
 		o.config.APIPort = apis.AntreaControllerAPIPort
 	}
+	if o.config.NodeIPAM.NodeCIDRMaskSizeIPv4 == 0 {
+		o.config.NodeIPAM.NodeCIDRMaskSizeIPv4 = defaultNodeCIDRMaskSizeIPv4
+	}
+	if o.config.NodeIPAM.NodeCIDRMaskSizeIPv6 == 0 {
+		o.config.NodeIPAM.NodeCIDRMaskSizeIPv6 = defaultNodeCIDRMaskSizeIPv6
+	}
 }
@@ -28,6 +28,7 @@ require (
 	github.com/golang/protobuf v1.3.2
 	github.com/google/uuid v1.1.1
 	github.com/kevinburke/ssh_config v0.0.0-20190725054713-01f96b0aa0cd
+	github.com/mdlayher/netlink v1.1.0
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/common v0.4.1
 	github.com/rakelkar/gonetsh v0.0.0-20190930180311-e5c5ffe4bdf0
@@ -39,16 +40,18 @@ require (
 	github.com/streamrail/concurrent-map v0.0.0-20160823150647-8bf1e9bacbf6 // indirect
 	github.com/stretchr/testify v1.5.1
 	github.com/ti-mo/conntrack v0.3.0
+	github.com/ti-mo/netfilter v0.3.1
 	github.com/vishvananda/netlink v1.1.0
 	github.com/vmware/go-ipfix v0.2.1
 	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 	golang.org/x/exp v0.0.0-20190312203227-4b39c73a6495
-	golang.org/x/net v0.0.0-20200822124328-c89045814202 // indirect
+	golang.org/x/net v0.0.0-20200822124328-c89045814202
 	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208
 	golang.org/x/sys v0.0.0-20200331124033-c3d80250170d
 	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 	google.golang.org/grpc v1.26.0
+	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 	gopkg.in/yaml.v2 v2.2.8
 	k8s.io/api v0.18.4
 	k8s.io/apimachinery v0.18.4
@@ -59,6 +62,7 @@ require (
 	k8s.io/kube-aggregator v0.18.4
 	k8s.io/kube-openapi v0.0.0-20200410145947-61e04a5be9a6
 	k8s.io/utils v0.0.0-20200410111917-5770800c2500
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
@@ -71,5 +75,8 @@ replace (
 	// There is an optimization https://github.com/kubernetes/kubernetes/pull/89575 but will only be
 	// available from 1.19.0 and later releases. Use this commit before Antrea bumps up its K8s
 	// dependency version.
+	// The Antrea Information Elements of the flow exporter which are not released in the registry of go-ipfix yet
+	// are added to a copy of go-ipfix, see third_party/go-ipfix/README. Use the released version once they are.
+	github.com/vmware/go-ipfix => ./third_party/go-ipfix
 	k8s.io/client-go => github.com/tnqn/client-go v0.18.4-1
 )
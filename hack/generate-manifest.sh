@@ -20,7 +20,7 @@ function echoerr {
     >&2 echo "$@"
 }
 
-_usage="Usage: $0 [--mode (dev|release)] [--encap-mode] [--kind] [--ipsec] [--proxy] [--np] [--keep] [--tun (geneve|vxlan|gre|stt)] [--verbose-log] [--help|-h]
+_usage="Usage: $0 [--mode (dev|release)] [--encap-mode] [--kind] [--ipsec] [--proxy] [--np] [--flow-exporter-standalone] [--keep] [--tun (geneve|vxlan|gre|stt)] [--verbose-log] [--help|-h]
 Generate a YAML manifest for Antrea using Kustomize and print it to stdout.
         --mode (dev|release)          Choose the configuration variant that you need (default is 'dev')
         --encap-mode                  Traffic encapsulation mode. (default is 'encap')
@@ -30,6 +30,7 @@ Generate a YAML manifest for Antrea using Kustomize and print it to stdout.
         --proxy                       Generate a manifest with Antrea proxy enabled
         --np                          Generate a manifest with ClusterNetworkPolicy and Antrea NetworkPolicy features enabled
         --prometheus                  Generate a manifest with Antrea Controller and Agent Prometheus metrics listener enabled
+        --flow-exporter-standalone    Generate a manifest with the flow exporter enabled and running in a separate container
         --keep                        Debug flag which will preserve the generated kustomization.yml
         --tun (geneve|vxlan|gre|stt)  Choose encap tunnel type from geneve, gre, stt and vxlan (default is geneve)
         --verbose-log                 Generate a manifest with increased log-level (level 4) for Antrea agent and controller.
@@ -68,6 +69,7 @@ VERBOSE_LOG=false
 ON_DELETE=false
 COVERAGE=false
 PROMETHEUS=false
+FLOW_EXPORTER_STANDALONE=false
 
 while [[ $# -gt 0 ]]
 do
@@ -106,6 +108,10 @@ case $key in
     PROMETHEUS=true
     shift
     ;;
+    --flow-exporter-standalone)
+    FLOW_EXPORTER_STANDALONE=true
+    shift
+    ;;
     --keep)
     KEEP=true
     shift
@@ -228,6 +234,11 @@ if $PROMETHEUS; then
     sed -i.bak -E "s/^[[:space:]]*#[[:space:]]*enablePrometheusMetrics[[:space:]]*:[[:space:]]*[a-z]+[[:space:]]*$/enablePrometheusMetrics: true/" antrea-agent.conf
 fi
 
+if $FLOW_EXPORTER_STANDALONE; then
+    sed -i.bak -E "s/^[[:space:]]*#[[:space:]]*FlowExporter[[:space:]]*:[[:space:]]*[a-z]+[[:space:]]*$/  FlowExporter: true/" antrea-agent.conf
+    sed -i.bak -E "s/^[[:space:]]*#[[:space:]]*flowExporterStandalone[[:space:]]*:[[:space:]]*[a-z]+[[:space:]]*$/flowExporterStandalone: true/" antrea-agent.conf
+fi
+
 if [[ $ENCAP_MODE != "" ]]; then
     sed -i.bak -E "s/^[[:space:]]*#[[:space:]]*trafficEncapMode[[:space:]]*:[[:space:]]*[a-z]+[[:space:]]*$/trafficEncapMode: $ENCAP_MODE/" antrea-agent.conf
 fi
@@ -270,6 +281,17 @@ if $IPSEC; then
     cd ..
 fi
 
+if $FLOW_EXPORTER_STANDALONE; then
+    mkdir flowexporter && cd flowexporter
+    cp ../../patches/flowexporter/*.yml .
+    touch kustomization.yml
+    $KUSTOMIZE edit add base $BASE
+    # add a container to the Agent DaemonSet that runs the flow exporter.
+    $KUSTOMIZE edit add patch flowExporterContainer.yml
+    BASE=../flowexporter
+    cd ..
+fi
+
 if $COVERAGE; then
     mkdir coverage && cd coverage
     cp ../../patches/coverage/*.yml .
@@ -365,6 +387,9 @@ if [ "$MODE" == "dev" ]; then
     if $IPSEC; then
         $KUSTOMIZE edit add patch agentIpsecImagePullPolicy.yml
     fi
+    if $FLOW_EXPORTER_STANDALONE; then
+        $KUSTOMIZE edit add patch agentFlowExporterImagePullPolicy.yml
+    fi
 fi
 
 if [ "$MODE" == "release" ]; then
@@ -98,10 +98,10 @@ MOCKGEN_TARGETS=(
   "pkg/ovs/ovsconfig OVSBridgeClient"
   "pkg/ovs/ovsctl OVSCtlClient"
   "pkg/agent/querier AgentQuerier"
-  "pkg/controller/networkpolicy EndpointQuerier"
+  "pkg/controller/networkpolicy EndpointQuerier,PolicySimulator"
   "pkg/controller/querier ControllerQuerier"
   "pkg/querier AgentNetworkPolicyInfoQuerier"
-  "pkg/agent/flowexporter/connections ConnTrackDumper,NetFilterConnTrack"
+  "pkg/agent/flowexporter/connections ConnTrackDumper,NetFilterConnTrack,NodeQuerier"
   "pkg/agent/flowexporter/ipfix IPFIXExportingProcess,IPFIXRecord,IPFIXRegistry"
   "pkg/agent/proxy Proxier"
 )
@@ -17,12 +17,15 @@ package agent
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/containernetworking/plugins/pkg/ip"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	clientset "k8s.io/client-go/kubernetes"
@@ -39,6 +42,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/features"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
 	"github.com/vmware-tanzu/antrea/pkg/util/env"
 )
 
@@ -53,6 +57,15 @@ const (
 	maxRetryForRoundNumSave = 5
 )
 
+// Annotations of the Node which override the tunnel configuration of the Agent
+// running on the Node. The tunnel port cannot be overridden, as all Nodes must
+// use the same port.
+const (
+	tunnelTTLAnnotation        = "tunnel.antrea.tanzu.vmware.com/ttl"
+	tunnelClearDFAnnotation    = "tunnel.antrea.tanzu.vmware.com/clear-df"
+	tunnelInheritTOSAnnotation = "tunnel.antrea.tanzu.vmware.com/inherit-tos"
+)
+
 // Initializer knows how to setup host networking, OpenVSwitch, and Openflow.
 type Initializer struct {
 	client          clientset.Interface
@@ -67,6 +80,10 @@ type Initializer struct {
 	networkConfig   *config.NetworkConfig
 	nodeConfig      *config.NodeConfig
 	enableProxy     bool
+	ovsCtlClient    ovsctl.OVSCtlClient
+	// Whether the initialization fails when an optional OVS feature is not
+	// supported by the Node.
+	failOnMissingOVSFeatures bool
 }
 
 func NewInitializer(
@@ -80,19 +97,22 @@ func NewInitializer(
 	mtu int,
 	serviceCIDR *net.IPNet,
 	networkConfig *config.NetworkConfig,
-	enableProxy bool) *Initializer {
+	enableProxy bool,
+	failOnMissingOVSFeatures bool) *Initializer {
 	return &Initializer{
-		ovsBridgeClient: ovsBridgeClient,
-		client:          k8sClient,
-		ifaceStore:      ifaceStore,
-		ofClient:        ofClient,
-		routeClient:     routeClient,
-		ovsBridge:       ovsBridge,
-		hostGateway:     hostGateway,
-		mtu:             mtu,
-		serviceCIDR:     serviceCIDR,
-		networkConfig:   networkConfig,
-		enableProxy:     enableProxy,
+		ovsBridgeClient:          ovsBridgeClient,
+		client:                   k8sClient,
+		ifaceStore:               ifaceStore,
+		ofClient:                 ofClient,
+		routeClient:              routeClient,
+		ovsBridge:                ovsBridge,
+		hostGateway:              hostGateway,
+		mtu:                      mtu,
+		serviceCIDR:              serviceCIDR,
+		networkConfig:            networkConfig,
+		enableProxy:              enableProxy,
+		failOnMissingOVSFeatures: failOnMissingOVSFeatures,
+		ovsCtlClient:             ovsctl.NewClient(ovsBridge),
 	}
 }
 
@@ -210,10 +230,15 @@ func (i *Initializer) Initialize() error {
 		return err
 	}
 
+	if err := i.detectOVSFeatures(); err != nil {
+		return err
+	}
+
 	// Install OpenFlow entries on OVS bridge.
 	if err := i.initOpenFlowPipeline(); err != nil {
 		return err
 	}
+	i.updateOVSFeatureMetrics()
 
 	if err := i.routeClient.Initialize(i.nodeConfig); err != nil {
 		return err
@@ -249,14 +274,15 @@ func persistRoundNum(num uint64, bridgeClient ovsconfig.OVSBridgeClient, interva
 
 // initOpenFlowPipeline sets up necessary Openflow entries, including pipeline, classifiers, conn_track, and gateway flows
 // Every time the agent is (re)started, we go through the following sequence:
-//   1. agent determines the new round number (this is done by incrementing the round number
-//   persisted in OVSDB, or if it's not available by picking round 1).
-//   2. any existing flow for which the round number matches the round number obtained from step 1
-//   is deleted.
-//   3. all required flows are installed, using the round number obtained from step 1.
-//   4. after convergence, all existing flows for which the round number matches the previous round
-//   number (i.e. the round number which was persisted in OVSDB, if any) are deleted.
-//   5. the new round number obtained from step 1 is persisted to OVSDB.
+//  1. agent determines the new round number (this is done by incrementing the round number
+//     persisted in OVSDB, or if it's not available by picking round 1).
+//  2. any existing flow for which the round number matches the round number obtained from step 1
+//     is deleted.
+//  3. all required flows are installed, using the round number obtained from step 1.
+//  4. after convergence, all existing flows for which the round number matches the previous round
+//     number (i.e. the round number which was persisted in OVSDB, if any) are deleted.
+//  5. the new round number obtained from step 1 is persisted to OVSDB.
+//
 // The rationale for not persisting the new round number until after all previous flows have been
 // deleted is to avoid a situation in which some stale flows are never deleted because of successive
 // agent restarts (with the agent crashing before step 4 can be completed). With the sequence
@@ -295,9 +321,13 @@ func (i *Initializer) initOpenFlowPipeline() error {
 		if features.DefaultFeatureGate.Enabled(features.Traceflow) {
 			// Set up Traceflow TLV map. This command is Nicira extensions to OpenFlow and require Open
 			// vSwitch 2.5 or later.
+			// If the Geneve options cannot be mapped, the tunnel flows don't
+			// carry the Traceflow tag, and Traceflow is disabled.
 			if err := i.ofClient.InitialTLVMap(); err != nil {
 				klog.Errorf("Error during Openflow TLV map initialization: %v", err)
-				return err
+				if err := i.handleMissingOVSFeature(config.OVSFeatureGeneveOptions); err != nil {
+					return err
+				}
 			}
 		}
 		// Set up flow entries for the default tunnel port interface.
@@ -393,6 +423,74 @@ func (i *Initializer) FlowRestoreComplete() error {
 	return nil
 }
 
+// WaitForFlowRestore waits for the flows of the initial Node routes, NetworkPolicies and Services to be installed, as
+// notified by flowRestoreCompleteWait, or for the timeout to expire, before removing the flow-restore-wait config.
+// Removing it earlier would make ovs-vswitchd revalidate the datapath flows kept across an OVS restart (e.g. when the
+// OVS container is upgraded) against an incomplete pipeline, and disrupt the existing connections. The removal is
+// retried until it succeeds or stopCh is closed, as ovs-vswitchd doesn't handle any new connection until then.
+func (i *Initializer) WaitForFlowRestore(flowRestoreCompleteWait *sync.WaitGroup, timeout time.Duration, stopCh <-chan struct{}) {
+	waitCh := make(chan struct{})
+	go func() {
+		flowRestoreCompleteWait.Wait()
+		close(waitCh)
+	}()
+	select {
+	case <-waitCh:
+		klog.Info("Flows for the initial Node routes, NetworkPolicies and Services have been installed")
+	case <-time.After(timeout):
+		klog.Warningf("Timed out after %v waiting for the flows of the initial Node routes, NetworkPolicies and Services to be installed", timeout)
+	case <-stopCh:
+		return
+	}
+	wait.PollImmediateUntil(time.Second, func() (done bool, err error) {
+		if err := i.FlowRestoreComplete(); err != nil {
+			klog.Errorf("Failed to clean up flow-restore-wait config: %v", err)
+			return false, nil
+		}
+		return true, nil
+	}, stopCh)
+}
+
+// InstallCNIConfAfterSync waits for the flows of the initial Node routes, NetworkPolicies and Services to be
+// installed, as notified by flowRestoreCompleteWait, before installing the CNI configuration file confSrc at confDst.
+// Until then, the container runtime reports the Node network as not ready and no Pod is attached to the Node, so that
+// Pods never run without their NetworkPolicies enforced. Unlike WaitForFlowRestore, there is no timeout as it would
+// defeat the purpose of the gate. The installation is retried until it succeeds or stopCh is closed.
+func InstallCNIConfAfterSync(flowRestoreCompleteWait *sync.WaitGroup, confSrc, confDst string, stopCh <-chan struct{}) {
+	waitCh := make(chan struct{})
+	go func() {
+		flowRestoreCompleteWait.Wait()
+		close(waitCh)
+	}()
+	select {
+	case <-waitCh:
+	case <-stopCh:
+		return
+	}
+	wait.PollImmediateUntil(time.Second, func() (done bool, err error) {
+		if err := installCNIConf(confSrc, confDst); err != nil {
+			klog.Errorf("Failed to install CNI configuration file %s: %v", confDst, err)
+			return false, nil
+		}
+		klog.Infof("Installed CNI configuration file %s", confDst)
+		return true, nil
+	}, stopCh)
+}
+
+// installCNIConf copies the CNI configuration file src to dst. The file is written to a temporary file first and then
+// renamed, so that the container runtime never reads a partially written configuration.
+func installCNIConf(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	tmpFile := dst + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, dst)
+}
+
 // setupGatewayInterface creates the host gateway interface which is an internal port on OVS. The ofport for host
 // gateway interface is predefined, so invoke CreateInternalPort with a specific ofport_request
 func (i *Initializer) setupGatewayInterface() error {
@@ -412,6 +510,10 @@ func (i *Initializer) setupGatewayInterface() error {
 		klog.V(2).Infof("Gateway port %s already exists on OVS bridge", i.hostGateway)
 	}
 
+	if err := i.persistGatewayMAC(gatewayIface, portExists); err != nil {
+		return err
+	}
+
 	// Idempotent operation to set the gateway's MTU: we perform this operation regardless of
 	// whether or not the gateway interface already exists, as the desired MTU may change across
 	// restarts.
@@ -430,10 +532,16 @@ func (i *Initializer) configureGatewayInterface(gatewayIface *interfacestore.Int
 	var gwLinkIdx int
 	var err error
 	// Host link might not be queried at once after creating OVS internal port; retry max 5 times with 1s
-	// delay each time to ensure the link is ready.
+	// delay each time to ensure the link is ready. If a MAC address is persisted for the gateway, also wait for
+	// OVS to apply it to the link.
 	for retry := 0; retry < maxRetryForHostLink; retry++ {
 		gwMAC, gwLinkIdx, err = util.SetLinkUp(i.hostGateway)
 		if err == nil {
+			if gatewayIface.MAC != nil && gwMAC.String() != gatewayIface.MAC.String() {
+				klog.V(2).Infof("MAC address of gateway %s is not updated to %s yet, retry after 1s", i.hostGateway, gatewayIface.MAC)
+				time.Sleep(1 * time.Second)
+				continue
+			}
 			break
 		}
 		if _, ok := err.(util.LinkNotFound); ok {
@@ -506,7 +614,7 @@ func (i *Initializer) setupDefaultTunnelInterface() error {
 				}
 				tunnelIface.TunnelInterfaceConfig.Csum = true
 			}
-			return nil
+			return i.setTunnelConfigOptions(tunnelPortName)
 		}
 
 		if err := i.ovsBridgeClient.DeletePort(tunnelIface.PortUUID); err != nil {
@@ -537,6 +645,69 @@ func (i *Initializer) setupDefaultTunnelInterface() error {
 		tunnelIface = interfacestore.NewTunnelInterface(tunnelPortName, i.networkConfig.TunnelType, localIP, shouldEnableCsum)
 		tunnelIface.OVSPortConfig = &interfacestore.OVSPortConfig{PortUUID: tunnelPortUUID, OFPort: config.DefaultTunOFPort}
 		i.ifaceStore.AddInterface(tunnelIface)
+		return i.setTunnelConfigOptions(tunnelPortName)
+	}
+	return nil
+}
+
+// setTunnelConfigOptions updates the options of the tunnel port which are set
+// from the NetworkConfig, i.e. the destination port, TTL, DF bit, and ToS, if
+// they don't match the configuration. The other options are kept.
+func (i *Initializer) setTunnelConfigOptions(tunnelPortName string) error {
+	options, err := i.ovsBridgeClient.GetInterfaceOptions(tunnelPortName)
+	if err != nil {
+		return fmt.Errorf("error getting interface options of tunnel port %s: %w", tunnelPortName, err)
+	}
+	desiredOptions := i.networkConfig.TunnelOptions()
+	updatedOptions := make(map[string]interface{})
+	changed := false
+	for k, v := range options {
+		updatedOptions[k] = v
+	}
+	for _, k := range config.TunnelConfigOptions {
+		desired, exists := desiredOptions[k]
+		if !exists {
+			if _, exists := options[k]; exists {
+				delete(updatedOptions, k)
+				changed = true
+			}
+			continue
+		}
+		if options[k] != desired {
+			updatedOptions[k] = desired
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	klog.Infof("Setting options %v of tunnel port %s", desiredOptions, tunnelPortName)
+	if err := i.ovsBridgeClient.SetInterfaceOptions(tunnelPortName, updatedOptions); err != nil {
+		return fmt.Errorf("error setting interface options of tunnel port %s: %w", tunnelPortName, err)
+	}
+	return nil
+}
+
+// applyNodeTunnelConfig overrides the tunnel TTL, DF bit, and ToS settings of
+// the NetworkConfig with the annotations of the Node.
+func (i *Initializer) applyNodeTunnelConfig(node *v1.Node) error {
+	if ttl, exists := node.Annotations[tunnelTTLAnnotation]; exists {
+		if err := config.ValidateTunnelTTL(ttl); err != nil {
+			return fmt.Errorf("invalid annotation %s of Node %s: %v", tunnelTTLAnnotation, node.Name, err)
+		}
+		i.networkConfig.TunnelTTL = ttl
+	}
+	for annotation, value := range map[string]*bool{
+		tunnelClearDFAnnotation:    &i.networkConfig.TunnelClearDF,
+		tunnelInheritTOSAnnotation: &i.networkConfig.TunnelInheritTOS,
+	} {
+		if str, exists := node.Annotations[annotation]; exists {
+			b, err := strconv.ParseBool(str)
+			if err != nil {
+				return fmt.Errorf("invalid annotation %s of Node %s: %v", annotation, node.Name, err)
+			}
+			*value = b
+		}
 	}
 	return nil
 }
@@ -568,6 +739,10 @@ func (i *Initializer) initNodeLocalConfig() error {
 		return err
 	}
 
+	if err := i.applyNodeTunnelConfig(node); err != nil {
+		return err
+	}
+
 	ipAddr, err := noderoute.GetNodeAddr(node)
 	if err != nil {
 		return fmt.Errorf("failed to obtain local IP address from k8s: %w", err)
@@ -18,8 +18,17 @@ package agent
 
 import (
 	"net"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 )
 
+// bridgeHWAddrKey is the key of the OVS bridge other_config which sets the MAC
+// address of the bridge local port.
+const bridgeHWAddrKey = "hwaddr"
+
 // setupExternalConnectivity returns immediately on Linux. The corresponding functions are provided in routeClient.
 func (i *Initializer) setupExternalConnectivity() error {
 	return nil
@@ -30,11 +39,68 @@ func (i *Initializer) prepareHostNetwork() error {
 	return nil
 }
 
-// prepareOVSBridge returns immediately on Linux.
+// prepareOVSBridge persists the MAC address of the OVS bridge local port on Linux, so that it does not change
+// across agent restarts and Node reboots. A MAC address which is already persisted is kept.
 func (i *Initializer) prepareOVSBridge() error {
+	otherConfig, err := i.ovsBridgeClient.GetBridgeOtherConfig()
+	if err != nil {
+		klog.Errorf("Failed to get other_config of OVS bridge: %v", err)
+		return err
+	}
+	brName := i.ovsBridgeClient.GetBridgeName()
+	if hwaddr, ok := otherConfig[bridgeHWAddrKey]; ok {
+		klog.V(2).Infof("MAC address %s of OVS bridge %s is already persisted", hwaddr, brName)
+		return nil
+	}
+	mac := i.generateStableMAC(brName)
+	klog.Infof("Persisting MAC address %s of OVS bridge %s", mac, brName)
+	if err := i.ovsBridgeClient.AddBridgeOtherConfig(map[string]interface{}{bridgeHWAddrKey: mac.String()}); err != nil {
+		klog.Errorf("Failed to persist MAC address of OVS bridge %s: %v", brName, err)
+		return err
+	}
+	return nil
+}
+
+// persistGatewayMAC persists the MAC address of the gateway interface in OVSDB, so that OVS applies the same
+// address every time the internal port is re-created, and neighbor caches of the peers and of the local Pods
+// remain valid. When no address is persisted yet, the current address of an existing gateway link is persisted, as
+// it has already been learned by the peers, and an address is generated only for a gateway which is being created.
+// The persisted address is set as the expected MAC of gatewayIface.
+func (i *Initializer) persistGatewayMAC(gatewayIface *interfacestore.InterfaceConfig, portExists bool) error {
+	mac, err := i.ovsBridgeClient.GetInterfaceMAC(i.hostGateway)
+	if err != nil {
+		klog.Errorf("Failed to get MAC address of gateway interface %s: %v", i.hostGateway, err)
+		return err
+	}
+	if mac != nil {
+		gatewayIface.MAC = mac
+		return nil
+	}
+	if portExists {
+		if link, err := net.InterfaceByName(i.hostGateway); err == nil {
+			mac = link.HardwareAddr
+		} else {
+			klog.Warningf("Failed to get MAC address of existing gateway link %s, generating a new one: %v", i.hostGateway, err)
+		}
+	}
+	if mac == nil {
+		mac = i.generateStableMAC(i.hostGateway)
+	}
+	klog.Infof("Persisting MAC address %s of gateway interface %s", mac, i.hostGateway)
+	if err := i.ovsBridgeClient.SetInterfaceMAC(i.hostGateway, mac); err != nil {
+		klog.Errorf("Failed to persist MAC address of gateway interface %s: %v", i.hostGateway, err)
+		return err
+	}
+	gatewayIface.MAC = mac
 	return nil
 }
 
+// generateStableMAC generates the MAC address of an OVS internal interface from the Node name and the interface
+// name. The same address is generated if the OVSDB file is lost, e.g. after a Node reboot when it is stored in tmpfs.
+func (i *Initializer) generateStableMAC(ifName string) net.HardwareAddr {
+	return util.GenerateStableMAC(i.nodeConfig.Name + "/" + ifName)
+}
+
 // initHostNetworkFlows returns immediately on Linux.
 func (i *Initializer) initHostNetworkFlows() error {
 	return nil
@@ -16,12 +16,20 @@ package agent
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	mock "github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/cniserver"
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
@@ -134,3 +142,127 @@ func TestGetRoundInfo(t *testing.T) {
 	roundInfo = getRoundInfo(mockOVSBridgeClient)
 	assert.Equal(t, uint64(initialRoundNum), roundInfo.RoundNum, "Unexpected round number")
 }
+
+func TestWaitForFlowRestore(t *testing.T) {
+	controller := mock.NewController(t)
+	defer controller.Finish()
+	mockOVSBridgeClient := ovsconfigtest.NewMockOVSBridgeClient(controller)
+	initializer := newAgentInitializer(mockOVSBridgeClient, interfacestore.NewInterfaceStore())
+
+	flowRestoreCompleteWait := &sync.WaitGroup{}
+	flowRestoreCompleteWait.Add(2)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	doneCh := make(chan struct{})
+	go func() {
+		initializer.WaitForFlowRestore(flowRestoreCompleteWait, time.Minute, stopCh)
+		close(doneCh)
+	}()
+
+	// flow-restore-wait must not be removed until all the initial flows have been installed.
+	flowRestoreCompleteWait.Done()
+	select {
+	case <-doneCh:
+		t.Fatal("flow-restore-wait was removed before the initial flows were installed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The removal is retried when it fails.
+	transactionError := ovsconfig.NewTransactionError(fmt.Errorf("Failed to delete other_config"), true)
+	flowRestoreWaitConfig := map[string]interface{}{"flow-restore-wait": "true"}
+	firstCall := mockOVSBridgeClient.EXPECT().DeleteOVSOtherConfig(flowRestoreWaitConfig).Return(transactionError)
+	mockOVSBridgeClient.EXPECT().DeleteOVSOtherConfig(flowRestoreWaitConfig).Return(nil).After(firstCall)
+	flowRestoreCompleteWait.Done()
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("flow-restore-wait was not removed after the initial flows were installed")
+	}
+}
+
+func TestWaitForFlowRestoreTimeout(t *testing.T) {
+	controller := mock.NewController(t)
+	defer controller.Finish()
+	mockOVSBridgeClient := ovsconfigtest.NewMockOVSBridgeClient(controller)
+	initializer := newAgentInitializer(mockOVSBridgeClient, interfacestore.NewInterfaceStore())
+
+	// flow-restore-wait is removed after the timeout even if the initial flows are never installed.
+	flowRestoreCompleteWait := &sync.WaitGroup{}
+	flowRestoreCompleteWait.Add(1)
+	mockOVSBridgeClient.EXPECT().DeleteOVSOtherConfig(map[string]interface{}{"flow-restore-wait": "true"}).Return(nil)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	initializer.WaitForFlowRestore(flowRestoreCompleteWait, 100*time.Millisecond, stopCh)
+}
+
+func TestInstallCNIConfAfterSync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "antrea-cni-conf")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	confSrc := filepath.Join(dir, "antrea-cni.conflist")
+	confDst := filepath.Join(dir, "10-antrea.conflist")
+	conf := []byte(`{"cniVersion":"0.3.0","name":"antrea","plugins":[{"type":"antrea"}]}`)
+	require.NoError(t, ioutil.WriteFile(confSrc, conf, 0644))
+
+	flowRestoreCompleteWait := &sync.WaitGroup{}
+	flowRestoreCompleteWait.Add(1)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	doneCh := make(chan struct{})
+	go func() {
+		InstallCNIConfAfterSync(flowRestoreCompleteWait, confSrc, confDst, stopCh)
+		close(doneCh)
+	}()
+
+	// The CNI configuration file must not be installed until all the initial flows have been installed.
+	select {
+	case <-doneCh:
+		t.Fatal("CNI configuration file was installed before the initial flows were installed")
+	case <-time.After(100 * time.Millisecond):
+	}
+	_, err = os.Stat(confDst)
+	assert.True(t, os.IsNotExist(err))
+
+	flowRestoreCompleteWait.Done()
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("CNI configuration file was not installed after the initial flows were installed")
+	}
+	installed, err := ioutil.ReadFile(confDst)
+	require.NoError(t, err)
+	assert.Equal(t, conf, installed)
+}
+
+func TestSetTunnelConfigOptions(t *testing.T) {
+	controller := mock.NewController(t)
+	defer controller.Finish()
+	mockOVSBridgeClient := ovsconfigtest.NewMockOVSBridgeClient(controller)
+	initializer := newAgentInitializer(mockOVSBridgeClient, interfacestore.NewInterfaceStore())
+	initializer.networkConfig = &config.NetworkConfig{TunnelType: ovsconfig.GeneveTunnel, TunnelPort: 6082, TunnelTTL: "inherit"}
+
+	// The options which are not configured anymore are removed, and the other options are kept.
+	mockOVSBridgeClient.EXPECT().GetInterfaceOptions("antrea-tun0").Return(map[string]string{"key": "flow", "remote_ip": "flow", "csum": "true", "tos": "inherit"}, nil)
+	mockOVSBridgeClient.EXPECT().SetInterfaceOptions("antrea-tun0", map[string]interface{}{"key": "flow", "remote_ip": "flow", "csum": "true", "dst_port": "6082", "ttl": "inherit"}).Return(nil)
+	assert.NoError(t, initializer.setTunnelConfigOptions("antrea-tun0"))
+
+	// The options are not updated if they match the configuration.
+	mockOVSBridgeClient.EXPECT().GetInterfaceOptions("antrea-tun0").Return(map[string]string{"key": "flow", "remote_ip": "flow", "dst_port": "6082", "ttl": "inherit"}, nil)
+	assert.NoError(t, initializer.setTunnelConfigOptions("antrea-tun0"))
+}
+
+func TestApplyNodeTunnelConfig(t *testing.T) {
+	initializer := &Initializer{networkConfig: &config.NetworkConfig{TunnelType: ovsconfig.GeneveTunnel, TunnelPort: 6082, TunnelClearDF: true}}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Annotations: map[string]string{
+		tunnelTTLAnnotation:        "inherit",
+		tunnelClearDFAnnotation:    "false",
+		tunnelInheritTOSAnnotation: "true",
+	}}}
+	assert.NoError(t, initializer.applyNodeTunnelConfig(node))
+	assert.Equal(t, &config.NetworkConfig{TunnelType: ovsconfig.GeneveTunnel, TunnelPort: 6082, TunnelTTL: "inherit", TunnelInheritTOS: true}, initializer.networkConfig)
+
+	node.Annotations = map[string]string{tunnelTTLAnnotation: "300"}
+	assert.Error(t, initializer.applyNodeTunnelConfig(node))
+	node.Annotations = map[string]string{tunnelClearDFAnnotation: "yes"}
+	assert.Error(t, initializer.applyNodeTunnelConfig(node))
+}
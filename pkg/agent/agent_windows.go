@@ -186,6 +186,11 @@ func (i *Initializer) prepareOVSBridge() error {
 	return nil
 }
 
+// persistGatewayMAC returns immediately on Windows.
+func (i *Initializer) persistGatewayMAC(gatewayIface *interfacestore.InterfaceConfig, portExists bool) error {
+	return nil
+}
+
 // initHostNetworkFlows installs Openflow flows between bridge local port and uplink port to support
 // host networking. These flows are only needed on windows platform.
 func (i *Initializer) initHostNetworkFlows() error {
@@ -37,6 +37,7 @@ type AntreaAgentInfoResponse struct {
 	NetworkPolicyControllerInfo v1beta1.NetworkPolicyControllerInfo `json:"networkPolicyControllerInfo,omitempty"` // Antrea Agent NetworkPolicy information
 	LocalPodNum                 int32                               `json:"localPodNum,omitempty"`                 // The number of Pods which the agent is in charge of
 	AgentConditions             []v1beta1.AgentCondition            `json:"agentConditions,omitempty"`             // Agent condition contains types like AgentHealthy
+	NodeCapacity                v1beta1.NodeCapacityInfo            `json:"nodeCapacity,omitempty"`                // The usage of the datapath resources of the Node
 }
 
 // HandleFunc returns the function which can handle queries issued by agentinfo commands.
@@ -54,6 +55,7 @@ func HandleFunc(aq querier.AgentQuerier) http.HandlerFunc {
 			LocalPodNum:                 agentInfo.LocalPodNum,
 			AgentConditions:             agentInfo.AgentConditions,
 			NodeSubnet:                  agentInfo.NodeSubnet,
+			NodeCapacity:                agentInfo.NodeCapacity,
 		}
 		err := json.NewEncoder(w).Encode(info)
 		if err != nil {
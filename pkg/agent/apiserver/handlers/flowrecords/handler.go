@@ -0,0 +1,147 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowrecords
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/querier"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/common"
+)
+
+// Response is the response struct of flowrecords command.
+type Response struct {
+	SourceIP             string `json:"sourceIP,omitempty"`
+	SourcePort           uint16 `json:"sourcePort,omitempty"`
+	DestinationIP        string `json:"destinationIP,omitempty"`
+	DestinationPort      uint16 `json:"destinationPort,omitempty"`
+	Protocol             uint8  `json:"protocol,omitempty"`
+	SourcePod            string `json:"sourcePod,omitempty"`
+	DestinationPod       string `json:"destinationPod,omitempty"`
+	DestinationService   string `json:"destinationService,omitempty"`
+	Packets              uint64 `json:"packets"`
+	Bytes                uint64 `json:"bytes"`
+	ReversePackets       uint64 `json:"reversePackets"`
+	ReverseBytes         uint64 `json:"reverseBytes"`
+	TCPState             string `json:"tcpState,omitempty"`
+	Denied               bool   `json:"denied,omitempty"`
+	Hairpin              bool   `json:"hairpin,omitempty"`
+	LastExportTimeSecond int64  `json:"lastExportTimeSecond,omitempty"`
+}
+
+func podName(namespace, name string) string {
+	if name == "" {
+		return ""
+	}
+	return namespace + "/" + name
+}
+
+func generateResponse(record *flowexporter.FlowRecord) Response {
+	conn := record.Conn
+	return Response{
+		SourceIP:             conn.TupleOrig.SourceAddress.String(),
+		SourcePort:           conn.TupleOrig.SourcePort,
+		DestinationIP:        conn.TupleReply.SourceAddress.String(),
+		DestinationPort:      conn.TupleReply.SourcePort,
+		Protocol:             conn.TupleOrig.Protocol,
+		SourcePod:            podName(conn.SourcePodNamespace, conn.SourcePodName),
+		DestinationPod:       podName(conn.DestinationPodNamespace, conn.DestinationPodName),
+		DestinationService:   conn.DestinationServicePortName,
+		Packets:              conn.OriginalPackets,
+		Bytes:                conn.OriginalBytes,
+		ReversePackets:       conn.ReversePackets,
+		ReverseBytes:         conn.ReverseBytes,
+		TCPState:             conn.TCPState,
+		Denied:               conn.IsDenied,
+		Hairpin:              conn.IsHairpin,
+		LastExportTimeSecond: record.LastExportTime.Unix(),
+	}
+}
+
+// matchPod returns true if the source or the destination of the connection is the provided local Pod. The Namespace
+// is ignored if it is empty.
+func matchPod(conn *flowexporter.Connection, pod, namespace string) bool {
+	return (conn.SourcePodName == pod && (namespace == "" || conn.SourcePodNamespace == namespace)) ||
+		(conn.DestinationPodName == pod && (namespace == "" || conn.DestinationPodNamespace == namespace))
+}
+
+// HandleFunc returns the function which can handle queries issued by the flowrecords command. frq is nil when the
+// Flow Exporter is not enabled.
+func HandleFunc(frq querier.FlowRecordsQuerier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if frq == nil {
+			http.Error(w, "FlowExporter feature is not enabled", http.StatusNotFound)
+			return
+		}
+		pod := r.URL.Query().Get("pod")
+		namespace := r.URL.Query().Get("namespace")
+		srcIPStr := r.URL.Query().Get("srcip")
+		var srcIP net.IP
+		if srcIPStr != "" {
+			srcIP = net.ParseIP(srcIPStr)
+			if srcIP == nil {
+				http.Error(w, "invalid source IP "+srcIPStr, http.StatusBadRequest)
+				return
+			}
+		}
+
+		resps := []Response{}
+		for _, record := range frq.GetFlowRecords() {
+			if pod != "" && !matchPod(record.Conn, pod, namespace) {
+				continue
+			}
+			if srcIP != nil && !srcIP.Equal(record.Conn.TupleOrig.SourceAddress) {
+				continue
+			}
+			resps = append(resps, generateResponse(&record))
+		}
+
+		err := json.NewEncoder(w).Encode(resps)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+var _ common.TableOutput = new(Response)
+
+func (r Response) GetTableHeader() []string {
+	return []string{"SOURCE", "DESTINATION", "PROTOCOL", "SOURCE-POD", "DESTINATION-POD", "SERVICE", "PACKETS", "BYTES", "REVERSE-PACKETS", "REVERSE-BYTES", "TCP-STATE", "DENIED"}
+}
+
+func (r Response) GetTableRow(maxColumnLength int) []string {
+	return []string{
+		net.JoinHostPort(r.SourceIP, strconv.Itoa(int(r.SourcePort))),
+		net.JoinHostPort(r.DestinationIP, strconv.Itoa(int(r.DestinationPort))),
+		strconv.Itoa(int(r.Protocol)),
+		r.SourcePod,
+		r.DestinationPod,
+		r.DestinationService,
+		strconv.FormatUint(r.Packets, 10),
+		strconv.FormatUint(r.Bytes, 10),
+		strconv.FormatUint(r.ReversePackets, 10),
+		strconv.FormatUint(r.ReverseBytes, 10),
+		r.TCPState,
+		strconv.FormatBool(r.Denied),
+	}
+}
+
+func (r Response) SortRows() bool {
+	return true
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowrecords
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+type fakeFlowRecordsQuerier struct {
+	records []flowexporter.FlowRecord
+}
+
+func (q *fakeFlowRecordsQuerier) GetFlowRecords() []flowexporter.FlowRecord {
+	return q.records
+}
+
+var records = []flowexporter.FlowRecord{
+	{
+		Conn: &flowexporter.Connection{
+			TupleOrig:                  flowexporter.Tuple{SourceAddress: net.ParseIP("10.10.0.2"), DestinationAddress: net.ParseIP("10.96.0.10"), Protocol: 6, SourcePort: 40000, DestinationPort: 80},
+			TupleReply:                 flowexporter.Tuple{SourceAddress: net.ParseIP("10.10.0.3"), DestinationAddress: net.ParseIP("10.10.0.2"), Protocol: 6, SourcePort: 8080, DestinationPort: 40000},
+			SourcePodNamespace:         "ns1",
+			SourcePodName:              "client",
+			DestinationPodNamespace:    "ns1",
+			DestinationPodName:         "server",
+			DestinationServicePortName: "ns1/server:http",
+			OriginalPackets:            10,
+			OriginalBytes:              1000,
+			TCPState:                   "ESTABLISHED",
+		},
+	},
+	{
+		Conn: &flowexporter.Connection{
+			TupleOrig:               flowexporter.Tuple{SourceAddress: net.ParseIP("10.10.1.2"), DestinationAddress: net.ParseIP("10.10.0.3"), Protocol: 17, SourcePort: 50000, DestinationPort: 53},
+			TupleReply:              flowexporter.Tuple{SourceAddress: net.ParseIP("10.10.0.3"), DestinationAddress: net.ParseIP("10.10.1.2"), Protocol: 17, SourcePort: 53, DestinationPort: 50000},
+			DestinationPodNamespace: "ns1",
+			DestinationPodName:      "server",
+			OriginalPackets:         1,
+			OriginalBytes:           60,
+			IsDenied:                true,
+		},
+	},
+}
+
+func TestFlowRecordsQuery(t *testing.T) {
+	testcases := map[string]struct {
+		query             string
+		expectedStatus    int
+		expectedResponses []Response
+	}{
+		"All": {
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedResponses: []Response{
+				generateResponse(&records[0]),
+				generateResponse(&records[1]),
+			},
+		},
+		"Pod": {
+			query:             "?pod=client&namespace=ns1",
+			expectedStatus:    http.StatusOK,
+			expectedResponses: []Response{generateResponse(&records[0])},
+		},
+		"PodOtherNamespace": {
+			query:             "?pod=client&namespace=ns2",
+			expectedStatus:    http.StatusOK,
+			expectedResponses: []Response{},
+		},
+		"SourceIP": {
+			query:             "?srcip=10.10.1.2",
+			expectedStatus:    http.StatusOK,
+			expectedResponses: []Response{generateResponse(&records[1])},
+		},
+		"PodAndSourceIP": {
+			query:             "?pod=server&srcip=10.10.0.2",
+			expectedStatus:    http.StatusOK,
+			expectedResponses: []Response{generateResponse(&records[0])},
+		},
+		"InvalidSourceIP": {
+			query:          "?srcip=10.10.1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	handler := HandleFunc(&fakeFlowRecordsQuerier{records: records})
+	for k, tc := range testcases {
+		req, err := http.NewRequest(http.MethodGet, tc.query, nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(t, tc.expectedStatus, recorder.Code, k)
+		if tc.expectedStatus != http.StatusOK {
+			continue
+		}
+		var received []Response
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		assert.Equal(t, tc.expectedResponses, received, k)
+	}
+}
+
+func TestFlowRecordsQueryDisabled(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	HandleFunc(nil).ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGenerateResponse(t *testing.T) {
+	resp := generateResponse(&records[0])
+	assert.Equal(t, "ns1/client", resp.SourcePod)
+	assert.Equal(t, "ns1/server", resp.DestinationPod)
+	assert.Equal(t, []string{"10.10.0.2:40000", "10.10.0.3:8080", "6", "ns1/client", "ns1/server", "ns1/server:http", "10", "1000", "0", "0", "ESTABLISHED", "false"}, resp.GetTableRow(32))
+}
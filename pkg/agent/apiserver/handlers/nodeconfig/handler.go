@@ -0,0 +1,60 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeconfig
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/querier"
+)
+
+// Response describes the response of the nodeconfig endpoint. It includes the configuration of the Node discovered
+// by antrea-agent which is needed by the components running alongside it, e.g. the standalone flow exporter.
+type Response struct {
+	NodeName string `json:"nodeName,omitempty"`
+	// NodeIP is the IP of the Node with its prefix length, e.g. "192.168.1.10/24".
+	NodeIP string `json:"nodeIP,omitempty"`
+	// PodCIDR is empty in networkPolicyOnly mode.
+	PodCIDR     string `json:"podCIDR,omitempty"`
+	GatewayName string `json:"gatewayName,omitempty"`
+	GatewayIP   string `json:"gatewayIP,omitempty"`
+}
+
+// HandleFunc returns the function which can handle queries issued to the nodeconfig endpoint.
+func HandleFunc(aq querier.AgentQuerier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		nodeConfig := aq.GetNodeConfig()
+		resp := Response{NodeName: nodeConfig.Name}
+		if nodeConfig.NodeIPAddr != nil {
+			resp.NodeIP = nodeConfig.NodeIPAddr.String()
+		}
+		if nodeConfig.PodCIDR != nil {
+			resp.PodCIDR = nodeConfig.PodCIDR.String()
+		}
+		if nodeConfig.GatewayConfig != nil {
+			resp.GatewayName = nodeConfig.GatewayConfig.Name
+			if nodeConfig.GatewayConfig.IP != nil {
+				resp.GatewayIP = nodeConfig.GatewayConfig.IP.String()
+			}
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			klog.Errorf("Error when encoding node config to json: %v", err)
+		}
+	}
+}
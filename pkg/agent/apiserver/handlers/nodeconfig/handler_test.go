@@ -0,0 +1,85 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeconfig
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	queriertest "github.com/vmware-tanzu/antrea/pkg/agent/querier/testing"
+)
+
+func TestNodeConfigQuery(t *testing.T) {
+	_, podCIDR, _ := net.ParseCIDR("10.10.1.0/24")
+	testcases := map[string]struct {
+		nodeConfig       *config.NodeConfig
+		expectedResponse Response
+	}{
+		"Encap": {
+			nodeConfig: &config.NodeConfig{
+				Name:          "node1",
+				NodeIPAddr:    &net.IPNet{IP: net.ParseIP("192.168.1.10"), Mask: net.CIDRMask(24, 32)},
+				PodCIDR:       podCIDR,
+				GatewayConfig: &config.GatewayConfig{Name: "antrea-gw0", IP: net.ParseIP("10.10.1.1")},
+			},
+			expectedResponse: Response{
+				NodeName:    "node1",
+				NodeIP:      "192.168.1.10/24",
+				PodCIDR:     "10.10.1.0/24",
+				GatewayName: "antrea-gw0",
+				GatewayIP:   "10.10.1.1",
+			},
+		},
+		"NetworkPolicyOnly": {
+			nodeConfig: &config.NodeConfig{
+				Name:          "node1",
+				NodeIPAddr:    &net.IPNet{IP: net.ParseIP("192.168.1.10"), Mask: net.CIDRMask(24, 32)},
+				GatewayConfig: &config.GatewayConfig{Name: "antrea-gw0"},
+			},
+			expectedResponse: Response{
+				NodeName:    "node1",
+				NodeIP:      "192.168.1.10/24",
+				GatewayName: "antrea-gw0",
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			q := queriertest.NewMockAgentQuerier(ctrl)
+			q.EXPECT().GetNodeConfig().Return(tc.nodeConfig)
+			handler := HandleFunc(q)
+
+			req, err := http.NewRequest(http.MethodGet, "", nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+
+			var received Response
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+			assert.Equal(t, tc.expectedResponse, received)
+		})
+	}
+}
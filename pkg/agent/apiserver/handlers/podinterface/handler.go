@@ -33,6 +33,9 @@ type Response struct {
 	PortUUID      string `json:"portUUID,omitempty"`
 	OFPort        int32  `json:"ofPort,omitempty"`
 	ContainerID   string `json:"containerID,omitempty"`
+	// NetNS is the path of the network namespace of the Pod. It's not displayed by antctl, but it's used by the
+	// standalone flow exporter to query the sockets of the Pod.
+	NetNS string `json:"netNS,omitempty"`
 }
 
 func generateResponse(i *interfacestore.InterfaceConfig) Response {
@@ -45,6 +48,7 @@ func generateResponse(i *interfacestore.InterfaceConfig) Response {
 		PortUUID:      i.OVSPortConfig.PortUUID,
 		OFPort:        i.OVSPortConfig.OFPort,
 		ContainerID:   i.ContainerInterfaceConfig.ContainerID,
+		NetNS:         i.ContainerInterfaceConfig.NetNS,
 	}
 }
 
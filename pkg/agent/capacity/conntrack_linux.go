@@ -0,0 +1,35 @@
+// +build linux
+
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/sysctl"
+)
+
+// getConntrackUsage returns the number of connections in the conntrack table
+// of the host and the size of the table.
+func getConntrackUsage() (int, int, error) {
+	count, err := sysctl.GetSysctlNet("nf_conntrack_count")
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := sysctl.GetSysctlNet("nf_conntrack_max")
+	if err != nil {
+		return 0, 0, err
+	}
+	return count, max, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package capacity reports the usage of the datapath resources of the Node,
+// for capacity planning.
+package capacity
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
+)
+
+const (
+	// collectInterval is the interval at which the usage of the datapath
+	// resources is collected. It matches the interval at which the
+	// AntreaAgentInfo is updated.
+	collectInterval = time.Minute
+
+	reasonCapacityWarning   = "NodeCapacityWarning"
+	reasonCapacityRecovered = "NodeCapacityRecovered"
+
+	resourceConntrack = "conntrack"
+	resourceOVSFlows  = "ovs_flows"
+	resourceOVSGroups = "ovs_groups"
+)
+
+var errConntrackUsageNotSupported = errors.New("conntrack usage is not supported on this platform")
+
+// Thresholds are the usages of the datapath resources of the Node above which
+// a warning Event is emitted. A threshold of 0 disables the warning.
+type Thresholds struct {
+	// Percentage of the size of the conntrack table.
+	ConntrackUsagePercent int
+	// Total number of flows in the OVS flow tables.
+	OVSFlowCount int
+	// Number of OVS groups.
+	OVSGroupCount int
+}
+
+// Monitor periodically collects the usage of the datapath resources of the
+// Node: the conntrack table, the OVS flows and the OVS groups. The usage is
+// exposed as Prometheus metrics and reported in the AntreaAgentInfo. A warning
+// Event is emitted for the Node when the usage of a resource goes above its
+// threshold, and a normal Event when it goes back below it.
+type Monitor struct {
+	nodeName     string
+	ofClient     openflow.Client
+	ovsCtlClient ovsctl.OVSCtlClient
+	recorder     record.EventRecorder
+	thresholds   Thresholds
+	// getConntrackUsage can be overridden in tests.
+	getConntrackUsage func() (int, int, error)
+
+	mutex    sync.RWMutex
+	capacity v1beta1.NodeCapacityInfo
+	// exceeded stores the resources whose usage was above their threshold
+	// at the last collection.
+	exceeded map[string]bool
+}
+
+func NewMonitor(nodeName string, ofClient openflow.Client, ovsCtlClient ovsctl.OVSCtlClient, recorder record.EventRecorder, thresholds Thresholds) *Monitor {
+	return &Monitor{
+		nodeName:          nodeName,
+		ofClient:          ofClient,
+		ovsCtlClient:      ovsCtlClient,
+		recorder:          recorder,
+		thresholds:        thresholds,
+		getConntrackUsage: getConntrackUsage,
+		exceeded:          map[string]bool{},
+	}
+}
+
+// GetNodeCapacity returns the usage of the datapath resources collected last.
+func (m *Monitor) GetNodeCapacity() v1beta1.NodeCapacityInfo {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.capacity
+}
+
+func (m *Monitor) Run(stopCh <-chan struct{}) {
+	klog.Info("Starting Node capacity monitor")
+	wait.Until(m.collect, collectInterval, stopCh)
+}
+
+func (m *Monitor) collect() {
+	var capacity v1beta1.NodeCapacityInfo
+	count, max, err := m.getConntrackUsage()
+	if err != nil {
+		if err != errConntrackUsageNotSupported {
+			klog.Errorf("Failed to get the usage of the conntrack table: %v", err)
+		}
+	} else {
+		capacity.ConntrackCount = int32(count)
+		capacity.ConntrackMax = int32(max)
+		if max > 0 {
+			metrics.ConntrackUsageRatio.Set(float64(count) / float64(max))
+		}
+	}
+	for _, tableStatus := range m.ofClient.GetFlowTableStatus() {
+		capacity.OVSFlowCount += int32(tableStatus.FlowCount)
+	}
+	if groups, err := m.ovsCtlClient.DumpGroups(); err != nil {
+		klog.Errorf("Failed to dump OVS groups: %v", err)
+	} else {
+		capacity.OVSGroupCount = int32(len(groups))
+		metrics.OVSGroupCount.Set(float64(capacity.OVSGroupCount))
+	}
+
+	m.mutex.Lock()
+	m.capacity = capacity
+	m.mutex.Unlock()
+
+	conntrackExceeded := m.thresholds.ConntrackUsagePercent > 0 && capacity.ConntrackMax > 0 &&
+		int64(capacity.ConntrackCount)*100 >= int64(m.thresholds.ConntrackUsagePercent)*int64(capacity.ConntrackMax)
+	m.checkThreshold(resourceConntrack, conntrackExceeded, "%d connections in the conntrack table of size %d, above the threshold of %d%%",
+		capacity.ConntrackCount, capacity.ConntrackMax, m.thresholds.ConntrackUsagePercent)
+	ovsFlowsExceeded := m.thresholds.OVSFlowCount > 0 && int(capacity.OVSFlowCount) >= m.thresholds.OVSFlowCount
+	m.checkThreshold(resourceOVSFlows, ovsFlowsExceeded, "%d OVS flows, above the threshold of %d",
+		capacity.OVSFlowCount, m.thresholds.OVSFlowCount)
+	ovsGroupsExceeded := m.thresholds.OVSGroupCount > 0 && int(capacity.OVSGroupCount) >= m.thresholds.OVSGroupCount
+	m.checkThreshold(resourceOVSGroups, ovsGroupsExceeded, "%d OVS groups, above the threshold of %d",
+		capacity.OVSGroupCount, m.thresholds.OVSGroupCount)
+}
+
+// checkThreshold emits an Event for the Node when the usage of the resource
+// crosses its threshold, and updates the warning metric of the resource.
+func (m *Monitor) checkThreshold(resource string, exceeded bool, messageFmt string, args ...interface{}) {
+	if exceeded {
+		metrics.NodeCapacityWarning.WithLabelValues(resource).Set(1)
+	} else {
+		metrics.NodeCapacityWarning.WithLabelValues(resource).Set(0)
+	}
+	if exceeded == m.exceeded[resource] {
+		return
+	}
+	m.exceeded[resource] = exceeded
+	// The UID of a Node reference is its name, as in the Events emitted by
+	// kubelet.
+	nodeRef := &corev1.ObjectReference{Kind: "Node", Name: m.nodeName, UID: types.UID(m.nodeName)}
+	if exceeded {
+		klog.Warningf("Usage of %s is above its threshold", resource)
+		m.recorder.Eventf(nodeRef, corev1.EventTypeWarning, reasonCapacityWarning, messageFmt, args...)
+	} else {
+		klog.Infof("Usage of %s is back below its threshold", resource)
+		m.recorder.Eventf(nodeRef, corev1.EventTypeNormal, reasonCapacityRecovered, "Usage of %s is back below its threshold", resource)
+	}
+}
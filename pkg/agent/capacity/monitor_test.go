@@ -0,0 +1,76 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/record"
+
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+	ovsctltest "github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl/testing"
+)
+
+func TestMonitorCollect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ofClient := openflowtest.NewMockClient(ctrl)
+	ovsCtlClient := ovsctltest.NewMockOVSCtlClient(ctrl)
+	recorder := record.NewFakeRecorder(10)
+	m := NewMonitor("node1", ofClient, ovsCtlClient, recorder, Thresholds{ConntrackUsagePercent: 80, OVSGroupCount: 3})
+
+	collect := func(conntrackCount int, flowCounts []uint, groupCount int) {
+		m.getConntrackUsage = func() (int, int, error) {
+			return conntrackCount, 1000, nil
+		}
+		var tableStatus []binding.TableStatus
+		for i, flowCount := range flowCounts {
+			tableStatus = append(tableStatus, binding.TableStatus{ID: uint(i), FlowCount: flowCount})
+		}
+		ofClient.EXPECT().GetFlowTableStatus().Return(tableStatus)
+		ovsCtlClient.EXPECT().DumpGroups().Return(make([][]string, groupCount), nil)
+		m.collect()
+	}
+	expectEvents := func(expected ...string) {
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		assert.Equal(t, expected, events)
+	}
+
+	collect(500, []uint{10, 20}, 2)
+	assert.Equal(t, v1beta1.NodeCapacityInfo{ConntrackCount: 500, ConntrackMax: 1000, OVSFlowCount: 30, OVSGroupCount: 2}, m.GetNodeCapacity())
+	expectEvents()
+
+	// A warning Event is emitted once when a usage goes above its threshold.
+	collect(800, []uint{10, 20}, 3)
+	expectEvents(
+		"Warning NodeCapacityWarning 800 connections in the conntrack table of size 1000, above the threshold of 80%",
+		"Warning NodeCapacityWarning 3 OVS groups, above the threshold of 3",
+	)
+	collect(900, []uint{10, 20}, 3)
+	expectEvents()
+
+	// A normal Event is emitted when it goes back below it. The OVS flows
+	// never trigger an Event as their threshold is disabled.
+	collect(100, []uint{10000}, 3)
+	assert.Equal(t, v1beta1.NodeCapacityInfo{ConntrackCount: 100, ConntrackMax: 1000, OVSFlowCount: 10000, OVSGroupCount: 3}, m.GetNodeCapacity())
+	expectEvents("Normal NodeCapacityRecovered Usage of conntrack is back below its threshold")
+}
@@ -136,7 +136,17 @@ func (ic *ifConfigurator) configureContainerLinkSriov(
 		return err
 	}
 	hostIface.Mac = link.Attrs().HardwareAddr.String()
-	// 6. Move VF to Container namespace
+	// 6. Save the name of the VF netdevice as its alias, so that the name can be restored when the VF is released.
+	vfLink, err := netlink.LinkByName(vfNetdevice)
+	if err != nil {
+		return fmt.Errorf("failed to find VF netdevice %s: %v", vfNetdevice, err)
+	}
+	if vfLink.Attrs().Alias == "" {
+		if err := netlink.LinkSetAlias(vfLink, vfNetdevice); err != nil {
+			return fmt.Errorf("failed to set alias for VF netdevice %s: %v", vfNetdevice, err)
+		}
+	}
+	// 7. Move VF to Container namespace
 	netns, err := ns.GetNS(containerNetNS)
 	if err != nil {
 		return fmt.Errorf("failed to open netns %s: %v", containerNetNS, err)
@@ -179,6 +189,17 @@ func (ic *ifConfigurator) configureContainerLinkSriov(
 	return nil
 }
 
+// getHostNS returns the host network namespace.
+// This is a workaround for issue #1113, which is caused by https://github.com/containernetworking/plugins/issues/524.
+// Instead of using the provided netns argument of ns.WithNetNSPath, which might not be the real hostNS, it fixes it by
+// getting the hostNS in advance with the OS thread locked.
+// TODO: remove this once the upstream issue is fixed.
+func getHostNS() (ns.NetNS, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	return ns.GetCurrentNS()
+}
+
 // configureContainerLinkVeth creates a veth pair: one in the container netns and one in the host netns, and configures IP
 // address and routes to the container veth.
 func (ic *ifConfigurator) configureContainerLinkVeth(
@@ -196,15 +217,7 @@ func (ic *ifConfigurator) configureContainerLinkVeth(
 	containerIface := &current.Interface{Name: containerIfaceName, Sandbox: containerNetNS}
 	result.Interfaces = []*current.Interface{hostIface, containerIface}
 
-	// This is a workaround for issue #1113, which is caused by https://github.com/containernetworking/plugins/issues/524.
-	// Instead of using the provided netns argument, which might not be the real hostNS, it fixes it by getting the
-	// hostNS in advance with the OS thread locked.
-	// TODO: remove this once the upstream issue is fixed.
-	hostNS, err := func() (ns.NetNS, error) {
-		runtime.LockOSThread()
-		defer runtime.UnlockOSThread()
-		return ns.GetCurrentNS()
-	}()
+	hostNS, err := getHostNS()
 	if err != nil {
 		return fmt.Errorf("failed to get host netns: %v", err)
 	}
@@ -320,6 +333,64 @@ func (ic *ifConfigurator) removeContainerLink(containerID, hostInterfaceName str
 	return nil
 }
 
+// releaseContainerVF moves the SR-IOV VF back from the container network namespace to the host network namespace,
+// and restores the original name of the VF netdevice, which is saved as its alias by configureContainerLinkSriov.
+// If the container network namespace has been deleted, the VF has already been moved back by the kernel, with the
+// name it had in the container. The VF representor is kept as it cannot be deleted, it will be renamed when the VF
+// is assigned to another container.
+func (ic *ifConfigurator) releaseContainerVF(containerID, containerNetNS, containerIfaceName, sriovVFDeviceID string) error {
+	klog.V(2).Infof("Moving SR-IOV %s device of container %s back to host network namespace", sriovVFDeviceID, containerID)
+	if containerNetNS != "" {
+		hostNS, err := getHostNS()
+		if err != nil {
+			return fmt.Errorf("failed to get host netns: %v", err)
+		}
+		defer hostNS.Close()
+		if err := ns.WithNetNSPath(containerNetNS, func(_ ns.NetNS) error {
+			link, err := netlink.LinkByName(containerIfaceName)
+			if err != nil {
+				if _, ok := err.(netlink.LinkNotFoundError); ok {
+					return nil
+				}
+				return fmt.Errorf("failed to find VF netdevice %s: %v", containerIfaceName, err)
+			}
+			if err := netlink.LinkSetDown(link); err != nil {
+				return fmt.Errorf("failed to set link down to VF netdevice %s: %v", containerIfaceName, err)
+			}
+			if err := netlink.LinkSetNsFd(link, int(hostNS.Fd())); err != nil {
+				return fmt.Errorf("failed to move VF netdevice %s to host netns: %v", containerIfaceName, err)
+			}
+			return nil
+		}); err != nil {
+			if _, ok := err.(ns.NSPathNotExistErr); !ok {
+				return err
+			}
+		}
+	}
+
+	vfNetdevices, err := sriovnet.GetNetDevicesFromPci(sriovVFDeviceID)
+	if err != nil {
+		return err
+	}
+	if len(vfNetdevices) != 1 {
+		return fmt.Errorf("failed to get one netdevice interface per %s", sriovVFDeviceID)
+	}
+	link, err := netlink.LinkByName(vfNetdevices[0])
+	if err != nil {
+		return fmt.Errorf("failed to find VF netdevice %s: %v", vfNetdevices[0], err)
+	}
+	if alias := link.Attrs().Alias; alias != "" && alias != link.Attrs().Name {
+		if err := netlink.LinkSetDown(link); err != nil {
+			return fmt.Errorf("failed to set link down to VF netdevice %s: %v", link.Attrs().Name, err)
+		}
+		if err := netlink.LinkSetName(link, alias); err != nil {
+			return fmt.Errorf("failed to rename VF netdevice %s to %s: %v", link.Attrs().Name, alias, err)
+		}
+	}
+	klog.V(2).Infof("Released SR-IOV %s device of container %s", sriovVFDeviceID, containerID)
+	return nil
+}
+
 func parseContainerIfaceFromResults(cfgArgs *cnipb.CniCmdArgs, prevResult *current.Result) *current.Interface {
 	for _, intf := range prevResult.Interfaces {
 		if intf.Name == cfgArgs.Ifname {
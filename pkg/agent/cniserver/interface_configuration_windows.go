@@ -204,6 +204,11 @@ func (ic *ifConfigurator) advertiseContainerAddr(containerNetNS string, containe
 	return nil
 }
 
+// releaseContainerVF returns an error as SR-IOV is not supported on Windows.
+func (ic *ifConfigurator) releaseContainerVF(containerID, containerNetNS, containerIFDev, sriovVFDeviceID string) error {
+	return fmt.Errorf("SR-IOV is not supported on Windows")
+}
+
 // removeContainerLink removes the HNSEndpoint attached on the Pod.
 func (ic *ifConfigurator) removeContainerLink(containerID, epName string) error {
 	ep, found := ic.getEndpoint(epName)
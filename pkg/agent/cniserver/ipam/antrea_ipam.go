@@ -0,0 +1,549 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/invoke"
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	"github.com/containernetworking/cni/pkg/types/current"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+
+	argtypes "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/types"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	coreinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha1"
+	corelisters "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/util/ip"
+)
+
+const (
+	// IPPoolAnnotation is the annotation of the Namespaces and Pods whose
+	// IPs are allocated from an IPPool by Antrea IPAM. The annotation of a
+	// Pod takes precedence over the one of its Namespace.
+	IPPoolAnnotation = "ipam.antrea.tanzu.vmware.com/ippool"
+	// IPAddressAnnotation is the annotation of the Pods requesting a
+	// specific IP from their IPPool.
+	IPAddressAnnotation = "ipam.antrea.tanzu.vmware.com/ip-address"
+	// IPRangeAnnotation is the annotation of the Pods and StatefulSets whose
+	// IPs are allocated from a fixed range of their IPPool, either a CIDR or
+	// a "<start IP>-<end IP>" range. The StatefulSet Pod with ordinal i gets
+	// the i-th IP of the range.
+	IPRangeAnnotation = "ipam.antrea.tanzu.vmware.com/ip-range"
+	// reservedIPsGCInterval is how often the IPs reserved for the
+	// StatefulSet Pods which will not be recreated are released.
+	reservedIPsGCInterval = 5 * time.Minute
+)
+
+// ipRequest describes the IP requested by a Pod from its IPPool.
+type ipRequest struct {
+	poolName string
+	// ip is the specific IP requested by the Pod. It's nil if any IP of the
+	// pool, or of the range, can be allocated.
+	ip net.IP
+	// first and last are the IPs of the range the IP must be allocated
+	// from. They are 0 if the Pod doesn't request a range.
+	first, last uint32
+	// statefulSet is the name of the StatefulSet of the Pod, empty if the
+	// Pod doesn't belong to a StatefulSet.
+	statefulSet string
+	// ordinal is the ordinal of the Pod in its StatefulSet.
+	ordinal int
+}
+
+// AntreaIPAM allocates the IPs of the Pods annotated with an IPPool, or running
+// in a Namespace annotated with an IPPool, from the pool. The allocated IPs are
+// persisted in the status of the pool, which serializes the allocations of all
+// the Nodes with the optimistic concurrency of the K8s API. The other Pods are
+// left to the next IPAM driver.
+type AntreaIPAM struct {
+	kubeClient   clientset.Interface
+	crdClient    versioned.Interface
+	ipPoolLister corelisters.IPPoolLister
+	// ipPoolListerSynced is checked before looking up the IPs allocated to
+	// a container in the lister.
+	ipPoolListerSynced cache.InformerSynced
+	// mutex serializes the allocations of this Node, to avoid conflicts
+	// between the concurrent CNI requests, and protects allocations.
+	mutex sync.Mutex
+	// allocations is a map of the container IDs to the names of the
+	// IPPools their IPs are allocated from by this agent, which may not be
+	// in the lister yet.
+	allocations map[string]string
+}
+
+// RegisterAntreaIPAM registers Antrea IPAM for the host-local IPAM type, ahead
+// of the host-local plugin, so that only the Pods which don't use an IPPool get
+// their IPs from the PodCIDR of the Node.
+func RegisterAntreaIPAM(kubeClient clientset.Interface, crdClient versioned.Interface, ipPoolInformer coreinformers.IPPoolInformer) *AntreaIPAM {
+	d := &AntreaIPAM{
+		kubeClient:         kubeClient,
+		crdClient:          crdClient,
+		ipPoolLister:       ipPoolInformer.Lister(),
+		ipPoolListerSynced: ipPoolInformer.Informer().HasSynced,
+		allocations:        map[string]string{},
+	}
+	if ipamDrivers == nil {
+		ipamDrivers = make(map[string][]IPAMDriver)
+	}
+	ipamDrivers[ipamHostLocal] = append([]IPAMDriver{d}, ipamDrivers[ipamHostLocal]...)
+	return d
+}
+
+// getIPRequest returns the IP requested by the Pod, or nil if the Pod doesn't
+// use an IPPool. The annotations of a Pod take precedence over the ones of its
+// StatefulSet, which take precedence over the ones of its Namespace.
+func (d *AntreaIPAM) getIPRequest(k8sArgs *argtypes.K8sArgs) (*ipRequest, error) {
+	namespace, name := string(k8sArgs.K8S_POD_NAMESPACE), string(k8sArgs.K8S_POD_NAME)
+	pod, err := d.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting Pod %s/%s: %v", namespace, name, err)
+	}
+	req := &ipRequest{}
+	annotationSets := []map[string]string{pod.Annotations}
+	if statefulSet, ordinal, isStatefulSetPod := getStatefulSetOrdinal(pod); isStatefulSetPod {
+		sts, err := d.kubeClient.AppsV1().StatefulSets(namespace).Get(context.TODO(), statefulSet, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting StatefulSet %s/%s: %v", namespace, statefulSet, err)
+		}
+		req.statefulSet, req.ordinal = statefulSet, ordinal
+		annotationSets = append(annotationSets, sts.Annotations)
+	}
+	getAnnotation := func(key string) (string, bool) {
+		for _, annotations := range annotationSets {
+			if value, exists := annotations[key]; exists {
+				return value, true
+			}
+		}
+		return "", false
+	}
+
+	poolName, exists := getAnnotation(IPPoolAnnotation)
+	if !exists {
+		ns, err := d.kubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting Namespace %s: %v", namespace, err)
+		}
+		poolName = ns.Annotations[IPPoolAnnotation]
+	}
+	if poolName == "" {
+		return nil, nil
+	}
+	req.poolName = poolName
+	if value, exists := pod.Annotations[IPAddressAnnotation]; exists {
+		req.ip = net.ParseIP(value)
+		if req.ip == nil || req.ip.To4() == nil {
+			return nil, fmt.Errorf("invalid IP %q in annotation %s of Pod %s/%s", value, IPAddressAnnotation, namespace, name)
+		}
+	} else if value, exists := getAnnotation(IPRangeAnnotation); exists {
+		req.first, req.last, err = parseIPRangeAnnotation(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP range %q in annotation %s of Pod %s/%s: %v", value, IPRangeAnnotation, namespace, name, err)
+		}
+	}
+	return req, nil
+}
+
+// getStatefulSetOrdinal returns the name of the StatefulSet of the Pod and the
+// ordinal of the Pod in the StatefulSet, which is the suffix of its name.
+func getStatefulSetOrdinal(pod *corev1.Pod) (string, int, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "StatefulSet" {
+		return "", 0, false
+	}
+	if !strings.HasPrefix(pod.Name, owner.Name+"-") {
+		return "", 0, false
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(pod.Name, owner.Name+"-"))
+	if err != nil || ordinal < 0 {
+		return "", 0, false
+	}
+	return owner.Name, ordinal, true
+}
+
+// parseIPRangeAnnotation parses a CIDR or a "<start IP>-<end IP>" range.
+func parseIPRangeAnnotation(value string) (uint32, uint32, error) {
+	ipRange := corev1alpha1.IPRange{CIDR: value}
+	if parts := strings.Split(value, "-"); len(parts) == 2 {
+		ipRange = corev1alpha1.IPRange{Start: strings.TrimSpace(parts[0]), End: strings.TrimSpace(parts[1])}
+	}
+	return ip.ParseIPRange(ipRange)
+}
+
+// getAllocatedIPPool returns the name of the IPPool an IP is allocated from to
+// the container, or an empty string if the container has no IP allocated by
+// Antrea IPAM.
+func (d *AntreaIPAM) getAllocatedIPPool(containerID string) (string, error) {
+	d.mutex.Lock()
+	poolName, exists := d.allocations[containerID]
+	d.mutex.Unlock()
+	if exists {
+		return poolName, nil
+	}
+	if !d.ipPoolListerSynced() {
+		return "", fmt.Errorf("IPPool cache is not synced yet")
+	}
+	pools, err := d.ipPoolLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	for _, pool := range pools {
+		for _, address := range pool.Status.IPAddresses {
+			if address.Owner.ContainerID == containerID {
+				return pool.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (d *AntreaIPAM) Add(args *invoke.Args, k8sArgs *argtypes.K8sArgs, networkConfig []byte) (bool, *current.Result, error) {
+	req, err := d.getIPRequest(k8sArgs)
+	if err != nil {
+		return true, nil, err
+	}
+	if req == nil {
+		return false, nil, nil
+	}
+	owner := corev1alpha1.IPAddressOwner{
+		Namespace:   string(k8sArgs.K8S_POD_NAMESPACE),
+		Pod:         string(k8sArgs.K8S_POD_NAME),
+		ContainerID: args.ContainerID,
+		StatefulSet: req.statefulSet,
+	}
+	allocatedIP, pool, err := d.allocateIP(req, owner)
+	if err != nil {
+		return true, nil, err
+	}
+	klog.Infof("Allocated IP %s from IPPool %s to Pod %s/%s", allocatedIP, req.poolName, owner.Namespace, owner.Pod)
+	gateway := net.ParseIP(pool.Spec.Gateway)
+	_, defaultRouteDst, _ := net.ParseCIDR("0.0.0.0/0")
+	return true, &current.Result{
+		IPs: []*current.IPConfig{{
+			Version: "4",
+			Address: net.IPNet{IP: allocatedIP, Mask: net.CIDRMask(int(pool.Spec.PrefixLength), 32)},
+			Gateway: gateway,
+		}},
+		Routes: []*cnitypes.Route{{Dst: *defaultRouteDst, GW: gateway}},
+	}, nil
+}
+
+func (d *AntreaIPAM) Del(args *invoke.Args, k8sArgs *argtypes.K8sArgs, networkConfig []byte) (bool, error) {
+	poolName, err := d.getAllocatedIPPool(args.ContainerID)
+	if err != nil {
+		return true, err
+	}
+	if poolName == "" {
+		return false, nil
+	}
+	return true, d.releaseIP(poolName, args.ContainerID)
+}
+
+func (d *AntreaIPAM) Check(args *invoke.Args, k8sArgs *argtypes.K8sArgs, networkConfig []byte) (bool, error) {
+	poolName, err := d.getAllocatedIPPool(args.ContainerID)
+	if err != nil {
+		return true, err
+	}
+	if poolName == "" {
+		return false, nil
+	}
+	return true, nil
+}
+
+// allocateIP allocates the IP requested by the container, or the first
+// available IP of the pool or of the requested range. It returns the IP already
+// allocated to the container if any. The IP reserved for a StatefulSet Pod is
+// allocated to the new container of the Pod, unless the Pod requests another IP.
+func (d *AntreaIPAM) allocateIP(req *ipRequest, owner corev1alpha1.IPAddressOwner) (net.IP, *corev1alpha1.IPPool, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	poolName := req.poolName
+	requestedIP := req.ip
+	if requestedIP == nil && req.statefulSet != "" && req.first != 0 {
+		candidate := req.first + uint32(req.ordinal)
+		if candidate < req.first || candidate > req.last {
+			return nil, nil, fmt.Errorf("ordinal %d of Pod %s/%s is out of the requested IP range", req.ordinal, owner.Namespace, owner.Pod)
+		}
+		requestedIP = ip.Uint32ToIP(candidate)
+	}
+	var allocatedIP net.IP
+	var pool *corev1alpha1.IPPool
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		pool, err = d.crdClient.CoreV1alpha1().IPPools().Get(context.TODO(), poolName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		allocatedIP = nil
+		var addresses []corev1alpha1.IPAddressState
+		allocatedIPs := make(map[string]bool, len(pool.Status.IPAddresses))
+		for _, address := range pool.Status.IPAddresses {
+			if address.Owner.ContainerID == owner.ContainerID {
+				allocatedIP = net.ParseIP(address.IPAddress)
+				return nil
+			}
+			// The IP previously allocated to the StatefulSet Pod is
+			// either taken over or released.
+			if owner.StatefulSet != "" && address.Owner.Namespace == owner.Namespace && address.Owner.Pod == owner.Pod {
+				if requestedIP == nil || requestedIP.String() == address.IPAddress {
+					allocatedIP = net.ParseIP(address.IPAddress)
+				}
+				continue
+			}
+			addresses = append(addresses, address)
+			allocatedIPs[address.IPAddress] = true
+		}
+		if allocatedIP == nil && requestedIP != nil {
+			if !poolContains(pool, ip.IPToUint32(requestedIP)) {
+				return fmt.Errorf("IP %s is not available in IPPool %s", requestedIP, poolName)
+			}
+			if allocatedIPs[requestedIP.String()] {
+				return fmt.Errorf("IP %s of IPPool %s is already allocated", requestedIP, poolName)
+			}
+			allocatedIP = requestedIP
+		} else if allocatedIP == nil {
+			allocatedIP = findAvailableIP(pool, req, allocatedIPs)
+			if allocatedIP == nil {
+				return fmt.Errorf("no IP available in IPPool %s", poolName)
+			}
+		}
+		update := pool.DeepCopy()
+		update.Status.IPAddresses = append(addresses, corev1alpha1.IPAddressState{
+			IPAddress: allocatedIP.String(),
+			Owner:     owner,
+		})
+		_, err = d.crdClient.CoreV1alpha1().IPPools().UpdateStatus(context.TODO(), update, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	d.allocations[owner.ContainerID] = poolName
+	return allocatedIP, pool, nil
+}
+
+// poolContains returns true if the IP is in a range of the pool and is not its
+// gateway.
+func poolContains(pool *corev1alpha1.IPPool, candidate uint32) bool {
+	if gateway := net.ParseIP(pool.Spec.Gateway); gateway != nil && ip.IPToUint32(gateway) == candidate {
+		return false
+	}
+	for _, ipRange := range pool.Spec.IPRanges {
+		first, last, err := ip.ParseIPRange(ipRange)
+		if err != nil {
+			klog.Errorf("Invalid IP range of IPPool %s: %v", pool.Name, err)
+			continue
+		}
+		if candidate >= first && candidate <= last {
+			return true
+		}
+	}
+	return false
+}
+
+// findAvailableIP returns the first IP of the pool, or of the requested range,
+// which is not allocated yet, or nil if there is none.
+func findAvailableIP(pool *corev1alpha1.IPPool, req *ipRequest, allocatedIPs map[string]bool) net.IP {
+	ranges := [][2]uint32{{req.first, req.last}}
+	if req.first == 0 {
+		ranges = nil
+		for _, ipRange := range pool.Spec.IPRanges {
+			first, last, err := ip.ParseIPRange(ipRange)
+			if err != nil {
+				klog.Errorf("Invalid IP range of IPPool %s: %v", pool.Name, err)
+				continue
+			}
+			ranges = append(ranges, [2]uint32{first, last})
+		}
+	}
+	for _, r := range ranges {
+		for i := r[0]; i <= r[1] && i >= r[0]; i++ {
+			candidate := ip.Uint32ToIP(i)
+			if !allocatedIPs[candidate.String()] && poolContains(pool, i) {
+				return candidate
+			}
+		}
+	}
+	return nil
+}
+
+// releaseIP releases the IP allocated from the pool to the container. The IP
+// of a StatefulSet Pod is kept reserved for the Pod as long as the StatefulSet
+// may recreate it, i.e. until it's deleted or scaled down below the ordinal of
+// the Pod, after which it's released by releaseStaleReservedIPs.
+func (d *AntreaIPAM) releaseIP(poolName string, containerID string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := d.crdClient.CoreV1alpha1().IPPools().Get(context.TODO(), poolName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// The IPs are released with the pool.
+				return nil
+			}
+			return err
+		}
+		update := pool.DeepCopy()
+		update.Status.IPAddresses = nil
+		released := false
+		for _, address := range pool.Status.IPAddresses {
+			if address.Owner.ContainerID == containerID {
+				released = true
+				reserved, err := d.isStatefulSetPodKept(address.Owner)
+				if err != nil {
+					return err
+				}
+				if reserved {
+					klog.Infof("Reserved IP %s from IPPool %s for Pod %s/%s of StatefulSet %s", address.IPAddress, poolName, address.Owner.Namespace, address.Owner.Pod, address.Owner.StatefulSet)
+					address.Owner.ContainerID = ""
+					update.Status.IPAddresses = append(update.Status.IPAddresses, address)
+					continue
+				}
+				klog.Infof("Released IP %s from IPPool %s of Pod %s/%s", address.IPAddress, poolName, address.Owner.Namespace, address.Owner.Pod)
+				continue
+			}
+			update.Status.IPAddresses = append(update.Status.IPAddresses, address)
+		}
+		if !released {
+			return nil
+		}
+		_, err = d.crdClient.CoreV1alpha1().IPPools().UpdateStatus(context.TODO(), update, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	delete(d.allocations, containerID)
+	return nil
+}
+
+// isStatefulSetPodKept returns true if the owner is a StatefulSet Pod which the
+// StatefulSet may recreate.
+func (d *AntreaIPAM) isStatefulSetPodKept(owner corev1alpha1.IPAddressOwner) (bool, error) {
+	if owner.StatefulSet == "" {
+		return false, nil
+	}
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(owner.Pod, owner.StatefulSet+"-"))
+	if err != nil {
+		return false, nil
+	}
+	sts, err := d.kubeClient.AppsV1().StatefulSets(owner.Namespace).Get(context.TODO(), owner.StatefulSet, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error getting StatefulSet %s/%s: %v", owner.Namespace, owner.StatefulSet, err)
+	}
+	if sts.DeletionTimestamp != nil {
+		return false, nil
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	return int32(ordinal) < replicas, nil
+}
+
+// Run releases the IPs reserved for the StatefulSet Pods which will not be
+// recreated every reservedIPsGCInterval, as the StatefulSet can be deleted or
+// scaled down after the containers of its Pods are deleted. The pools are
+// garbage collected by all the agents, the concurrent updates are serialized by
+// the K8s API.
+func (d *AntreaIPAM) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, d.ipPoolListerSynced) {
+		klog.Error("Unable to sync IPPool cache for Antrea IPAM")
+		return
+	}
+	wait.JitterUntil(d.releaseStaleReservedIPs, reservedIPsGCInterval, 0.5, true, stopCh)
+}
+
+// releaseStaleReservedIPs releases the reserved IPs of all the pools whose
+// StatefulSet Pods will not be recreated.
+func (d *AntreaIPAM) releaseStaleReservedIPs() {
+	pools, err := d.ipPoolLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list IPPools: %v", err)
+		return
+	}
+	for _, pool := range pools {
+		for _, address := range pool.Status.IPAddresses {
+			if isReserved(address.Owner) {
+				if err := d.releaseReservedIPs(pool.Name); err != nil {
+					klog.Errorf("Failed to release the reserved IPs of IPPool %s: %v", pool.Name, err)
+				}
+				break
+			}
+		}
+	}
+}
+
+// releaseReservedIPs releases the IPs of the pool reserved for the StatefulSet
+// Pods which the StatefulSets will not recreate.
+func (d *AntreaIPAM) releaseReservedIPs(poolName string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := d.crdClient.CoreV1alpha1().IPPools().Get(context.TODO(), poolName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		update := pool.DeepCopy()
+		update.Status.IPAddresses = nil
+		released := false
+		for _, address := range pool.Status.IPAddresses {
+			if isReserved(address.Owner) {
+				kept, err := d.isStatefulSetPodKept(address.Owner)
+				if err != nil {
+					return err
+				}
+				if !kept {
+					klog.Infof("Released IP %s from IPPool %s reserved for Pod %s/%s of StatefulSet %s", address.IPAddress, poolName, address.Owner.Namespace, address.Owner.Pod, address.Owner.StatefulSet)
+					released = true
+					continue
+				}
+			}
+			update.Status.IPAddresses = append(update.Status.IPAddresses, address)
+		}
+		if !released {
+			return nil
+		}
+		_, err = d.crdClient.CoreV1alpha1().IPPools().UpdateStatus(context.TODO(), update, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// isReserved returns true if the IP of the owner is reserved for a StatefulSet
+// Pod whose container was deleted.
+func isReserved(owner corev1alpha1.IPAddressOwner) bool {
+	return owner.StatefulSet != "" && owner.ContainerID == ""
+}
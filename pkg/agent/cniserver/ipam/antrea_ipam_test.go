@@ -0,0 +1,243 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containernetworking/cni/pkg/invoke"
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	argtypes "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/types"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+)
+
+func newAntreaIPAM(pool *corev1alpha1.IPPool, objects ...runtime.Object) (*AntreaIPAM, func()) {
+	kubeClient := fake.NewSimpleClientset(append([]runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Annotations: map[string]string{IPPoolAnnotation: "pool1"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod1"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "pod2"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "pod3", Annotations: map[string]string{IPPoolAnnotation: "pool1"}}},
+	}, objects...)...)
+	crdClient := fakeversioned.NewSimpleClientset(pool)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	ipPoolInformer := crdInformerFactory.Core().V1alpha1().IPPools()
+	d := &AntreaIPAM{
+		kubeClient:         kubeClient,
+		crdClient:          crdClient,
+		ipPoolLister:       ipPoolInformer.Lister(),
+		ipPoolListerSynced: ipPoolInformer.Informer().HasSynced,
+		allocations:        map[string]string{},
+	}
+	stopCh := make(chan struct{})
+	crdInformerFactory.Start(stopCh)
+	crdInformerFactory.WaitForCacheSync(stopCh)
+	return d, func() { close(stopCh) }
+}
+
+func newK8sArgs(namespace, name string) *argtypes.K8sArgs {
+	return &argtypes.K8sArgs{
+		K8S_POD_NAMESPACE: cnitypes.UnmarshallableString(namespace),
+		K8S_POD_NAME:      cnitypes.UnmarshallableString(name),
+	}
+}
+
+func TestAntreaIPAM(t *testing.T) {
+	pool := &corev1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool1"},
+		Spec: corev1alpha1.IPPoolSpec{
+			IPRanges:     []corev1alpha1.IPRange{{Start: "10.10.10.1", End: "10.10.10.3"}},
+			Gateway:      "10.10.10.1",
+			PrefixLength: 24,
+		},
+	}
+	d, cleanup := newAntreaIPAM(pool)
+	defer cleanup()
+
+	// The IP of a Pod in an annotated Namespace is allocated from the pool, skipping the gateway.
+	owns, result, err := d.Add(&invoke.Args{ContainerID: "c1"}, newK8sArgs("ns1", "pod1"), nil)
+	require.NoError(t, err)
+	require.True(t, owns)
+	require.Len(t, result.IPs, 1)
+	assert.Equal(t, "10.10.10.2/24", result.IPs[0].Address.String())
+	assert.Equal(t, "10.10.10.1", result.IPs[0].Gateway.String())
+	assert.Equal(t, "10.10.10.1", result.Routes[0].GW.String())
+
+	// The allocation is idempotent.
+	_, result, err = d.Add(&invoke.Args{ContainerID: "c1"}, newK8sArgs("ns1", "pod1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.10.2/24", result.IPs[0].Address.String())
+
+	// The Pods of the other Namespaces are left to the next driver, unless they are annotated.
+	owns, _, err = d.Add(&invoke.Args{ContainerID: "c2"}, newK8sArgs("ns2", "pod2"), nil)
+	require.NoError(t, err)
+	assert.False(t, owns)
+	owns, result, err = d.Add(&invoke.Args{ContainerID: "c3"}, newK8sArgs("ns2", "pod3"), nil)
+	require.NoError(t, err)
+	assert.True(t, owns)
+	assert.Equal(t, "10.10.10.3/24", result.IPs[0].Address.String())
+
+	// The pool is exhausted.
+	_, _, err = d.Add(&invoke.Args{ContainerID: "c4"}, newK8sArgs("ns1", "pod1"), nil)
+	assert.Error(t, err)
+
+	pool, err = d.crdClient.CoreV1alpha1().IPPools().Get(context.TODO(), "pool1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1alpha1.IPAddressState{
+		{IPAddress: "10.10.10.2", Owner: corev1alpha1.IPAddressOwner{Namespace: "ns1", Pod: "pod1", ContainerID: "c1"}},
+		{IPAddress: "10.10.10.3", Owner: corev1alpha1.IPAddressOwner{Namespace: "ns2", Pod: "pod3", ContainerID: "c3"}},
+	}, pool.Status.IPAddresses)
+
+	owns, err = d.Del(&invoke.Args{ContainerID: "c1"}, newK8sArgs("ns1", "pod1"), nil)
+	require.NoError(t, err)
+	assert.True(t, owns)
+	pool, err = d.crdClient.CoreV1alpha1().IPPools().Get(context.TODO(), "pool1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pool.Status.IPAddresses, 1)
+
+	// The containers which have no IP allocated by Antrea IPAM are left to the next driver.
+	owns, err = d.Del(&invoke.Args{ContainerID: "c2"}, newK8sArgs("ns2", "pod2"), nil)
+	require.NoError(t, err)
+	assert.False(t, owns)
+	owns, err = d.Check(&invoke.Args{ContainerID: "c3"}, newK8sArgs("ns2", "pod3"), nil)
+	require.NoError(t, err)
+	assert.True(t, owns)
+}
+
+func TestAntreaIPAMStaticIPs(t *testing.T) {
+	pool := &corev1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool1"},
+		Spec: corev1alpha1.IPPoolSpec{
+			IPRanges:     []corev1alpha1.IPRange{{Start: "10.10.10.1", End: "10.10.10.10"}},
+			Gateway:      "10.10.10.1",
+			PrefixLength: 24,
+		},
+	}
+	replicas := int32(2)
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "db", Annotations: map[string]string{
+			IPPoolAnnotation:  "pool1",
+			IPRangeAnnotation: "10.10.10.5-10.10.10.6",
+		}},
+		Spec: appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+	newStatefulSetPod := func(name string) *corev1.Pod {
+		controller := true
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns2",
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db", Controller: &controller}},
+		}}
+	}
+	d, cleanup := newAntreaIPAM(pool,
+		sts,
+		newStatefulSetPod("db-1"),
+		newStatefulSetPod("db-2"),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod4", Annotations: map[string]string{IPAddressAnnotation: "10.10.10.8"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod5", Annotations: map[string]string{IPAddressAnnotation: "10.10.10.8"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod6", Annotations: map[string]string{IPAddressAnnotation: "10.10.10.1"}}},
+	)
+	defer cleanup()
+	getIPAddresses := func() []corev1alpha1.IPAddressState {
+		pool, err := d.crdClient.CoreV1alpha1().IPPools().Get(context.TODO(), "pool1", metav1.GetOptions{})
+		require.NoError(t, err)
+		return pool.Status.IPAddresses
+	}
+
+	// The StatefulSet Pod with ordinal i gets the i-th IP of the range of its StatefulSet.
+	_, result, err := d.Add(&invoke.Args{ContainerID: "c1"}, newK8sArgs("ns2", "db-1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.10.6/24", result.IPs[0].Address.String())
+	_, _, err = d.Add(&invoke.Args{ContainerID: "c2"}, newK8sArgs("ns2", "db-2"), nil)
+	assert.Error(t, err)
+
+	// A specific IP can only be allocated once, and must be available in the pool.
+	_, result, err = d.Add(&invoke.Args{ContainerID: "c4"}, newK8sArgs("ns1", "pod4"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.10.8/24", result.IPs[0].Address.String())
+	_, _, err = d.Add(&invoke.Args{ContainerID: "c5"}, newK8sArgs("ns1", "pod5"), nil)
+	assert.Error(t, err)
+	_, _, err = d.Add(&invoke.Args{ContainerID: "c6"}, newK8sArgs("ns1", "pod6"), nil)
+	assert.Error(t, err)
+
+	// The IP of a deleted StatefulSet Pod is reserved, and allocated to the Pod again when it's recreated.
+	_, err = d.Del(&invoke.Args{ContainerID: "c1"}, newK8sArgs("ns2", "db-1"), nil)
+	require.NoError(t, err)
+	reservedOwner := corev1alpha1.IPAddressOwner{Namespace: "ns2", Pod: "db-1", StatefulSet: "db"}
+	assert.Contains(t, getIPAddresses(), corev1alpha1.IPAddressState{IPAddress: "10.10.10.6", Owner: reservedOwner})
+	_, result, err = d.Add(&invoke.Args{ContainerID: "c1-new"}, newK8sArgs("ns2", "db-1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.10.6/24", result.IPs[0].Address.String())
+	reservedOwner.ContainerID = "c1-new"
+	assert.Equal(t, []corev1alpha1.IPAddressState{
+		{IPAddress: "10.10.10.8", Owner: corev1alpha1.IPAddressOwner{Namespace: "ns1", Pod: "pod4", ContainerID: "c4"}},
+		{IPAddress: "10.10.10.6", Owner: reservedOwner},
+	}, getIPAddresses())
+
+	// The IP is released when the StatefulSet is scaled down below the ordinal of the Pod.
+	replicas = 1
+	_, err = d.kubeClient.AppsV1().StatefulSets("ns2").Update(context.TODO(), sts, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = d.Del(&invoke.Args{ContainerID: "c1-new"}, newK8sArgs("ns2", "db-1"), nil)
+	require.NoError(t, err)
+	assert.Len(t, getIPAddresses(), 1)
+}
+
+func TestAntreaIPAMReleaseStaleReservedIPs(t *testing.T) {
+	replicas := int32(2)
+	reservedOwner := func(pod, statefulSet string) corev1alpha1.IPAddressOwner {
+		return corev1alpha1.IPAddressOwner{Namespace: "ns2", Pod: pod, StatefulSet: statefulSet}
+	}
+	pool := &corev1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool1"},
+		Spec: corev1alpha1.IPPoolSpec{
+			IPRanges:     []corev1alpha1.IPRange{{Start: "10.10.10.1", End: "10.10.10.10"}},
+			Gateway:      "10.10.10.1",
+			PrefixLength: 24,
+		},
+		Status: corev1alpha1.IPPoolStatus{IPAddresses: []corev1alpha1.IPAddressState{
+			{IPAddress: "10.10.10.2", Owner: corev1alpha1.IPAddressOwner{Namespace: "ns1", Pod: "pod1", ContainerID: "c1"}},
+			{IPAddress: "10.10.10.3", Owner: reservedOwner("db-1", "db")},
+			{IPAddress: "10.10.10.4", Owner: reservedOwner("db-2", "db")},
+			{IPAddress: "10.10.10.5", Owner: reservedOwner("web-0", "web")},
+		}},
+	}
+	d, cleanup := newAntreaIPAM(pool, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "db"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	})
+	defer cleanup()
+
+	// The IPs reserved for the Pods of a deleted StatefulSet, or beyond the replicas of their StatefulSet, are
+	// released.
+	d.releaseStaleReservedIPs()
+	pool, err := d.crdClient.CoreV1alpha1().IPPools().Get(context.TODO(), "pool1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1alpha1.IPAddressState{
+		{IPAddress: "10.10.10.2", Owner: corev1alpha1.IPAddressOwner{Namespace: "ns1", Pod: "pod1", ContainerID: "c1"}},
+		{IPAddress: "10.10.10.3", Owner: reservedOwner("db-1", "db")},
+	}, pool.Status.IPAddresses)
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/containernetworking/cni/pkg/types"
 	"github.com/containernetworking/cni/pkg/types/current"
 	"k8s.io/klog"
+
+	argtypes "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/types"
 )
 
 const (
@@ -34,7 +36,7 @@ type IPAMDelegator struct {
 	pluginType string
 }
 
-func (d *IPAMDelegator) Add(args *invoke.Args, networkConfig []byte) (*current.Result, error) {
+func (d *IPAMDelegator) Add(args *invoke.Args, k8sArgs *argtypes.K8sArgs, networkConfig []byte) (bool, *current.Result, error) {
 	var success = false
 	defer func() {
 		if !success {
@@ -48,32 +50,32 @@ func (d *IPAMDelegator) Add(args *invoke.Args, networkConfig []byte) (*current.R
 	args.Command = "ADD"
 	r, err := delegateWithResult(d.pluginType, networkConfig, args)
 	if err != nil {
-		return nil, err
+		return true, nil, err
 	}
 
 	ipamResult, err := current.NewResultFromResult(r)
 	if err != nil {
-		return nil, err
+		return true, nil, err
 	}
 	success = true
-	return ipamResult, nil
+	return true, ipamResult, nil
 }
 
-func (d *IPAMDelegator) Del(args *invoke.Args, networkConfig []byte) error {
+func (d *IPAMDelegator) Del(args *invoke.Args, k8sArgs *argtypes.K8sArgs, networkConfig []byte) (bool, error) {
 	args.Command = "DEL"
 	if err := delegateNoResult(d.pluginType, networkConfig, args); err != nil {
-		return err
+		return true, err
 	}
 
-	return nil
+	return true, nil
 }
 
-func (d *IPAMDelegator) Check(args *invoke.Args, networkConfig []byte) error {
+func (d *IPAMDelegator) Check(args *invoke.Args, k8sArgs *argtypes.K8sArgs, networkConfig []byte) (bool, error) {
 	args.Command = "CHECK"
 	if err := delegateNoResult(d.pluginType, networkConfig, args); err != nil {
-		return err
+		return true, err
 	}
-	return nil
+	return true, nil
 }
 
 var defaultExec = &invoke.DefaultExec{
@@ -40,19 +40,14 @@ type vethPair struct {
 	peerIndex int
 }
 
-type k8sArgs struct {
-	cnitypes.CommonArgs
-	K8S_POD_NAME               cnitypes.UnmarshallableString
-	K8S_POD_NAMESPACE          cnitypes.UnmarshallableString
-	K8S_POD_INFRA_CONTAINER_ID cnitypes.UnmarshallableString
-}
-
 const (
 	ovsExternalIDMAC          = "attached-mac"
 	ovsExternalIDIP           = "ip-address"
 	ovsExternalIDContainerID  = "container-id"
 	ovsExternalIDPodName      = "pod-name"
 	ovsExternalIDPodNamespace = "pod-namespace"
+	ovsExternalIDSriovVFID    = "sriov-vf-device-id"
+	ovsExternalIDNetNS        = "netns"
 )
 
 const (
@@ -64,6 +59,7 @@ type interfaceConfigurator interface {
 	configureContainerLink(podName, podNameSpace, containerID, containerNetNS, containerIFDev string, mtu int, sriovVFDeviceID string, result *current.Result) error
 	advertiseContainerAddr(containerNetNS string, containerIfaceName string, result *current.Result) error
 	removeContainerLink(containerID, hostInterfaceName string) error
+	releaseContainerVF(containerID, containerNetNS, containerIFDev, sriovVFDeviceID string) error
 	checkContainerInterface(containerNetns, containerID string, containerIface *current.Interface, containerIPs []*current.IPConfig, containerRoutes []*cnitypes.Route, sriovVFDeviceID string) (interface{}, error)
 	validateVFRepInterface(sriovVFDeviceID string) (string, error)
 	validateContainerPeerInterface(interfaces []*current.Interface, containerVeth *vethPair) (*vethPair, error)
@@ -77,7 +73,10 @@ type podConfigurator struct {
 	routeClient     route.Interface
 	ifaceStore      interfacestore.InterfaceStore
 	gatewayMAC      net.HardwareAddr
-	ifConfigurator  interfaceConfigurator
+	// podCIDR is the PodCIDR of the Node, the Pods with IPs out of it get
+	// their IPs from an IPPool. It's nil in policy-only mode.
+	podCIDR        *net.IPNet
+	ifConfigurator interfaceConfigurator
 }
 
 func newPodConfigurator(
@@ -86,6 +85,7 @@ func newPodConfigurator(
 	routeClient route.Interface,
 	ifaceStore interfacestore.InterfaceStore,
 	gatewayMAC net.HardwareAddr,
+	podCIDR *net.IPNet,
 	ovsDatapathType string,
 	isOvsHardwareOffloadEnabled bool,
 ) (*podConfigurator, error) {
@@ -99,10 +99,17 @@ func newPodConfigurator(
 		routeClient:     routeClient,
 		ifaceStore:      ifaceStore,
 		gatewayMAC:      gatewayMAC,
+		podCIDR:         podCIDR,
 		ifConfigurator:  ifConfigurator,
 	}, nil
 }
 
+// isAntreaIPAMPod returns true if the IP of the Pod is allocated by Antrea IPAM
+// from an IPPool, in which case the Pod IP is routed to the gateway by the host.
+func (pc *podConfigurator) isAntreaIPAMPod(podIP net.IP) bool {
+	return pc.podCIDR != nil && podIP != nil && !pc.podCIDR.Contains(podIP)
+}
+
 func findContainerIPConfig(ips []*current.IPConfig) (*current.IPConfig, error) {
 	for _, ipc := range ips {
 		if ipc.Version == "4" {
@@ -130,13 +137,15 @@ func buildContainerConfig(
 	}
 	// containerIface.Mac should be a valid MAC string, otherwise it should throw error before
 	containerMAC, _ := net.ParseMAC(containerIface.Mac)
-	return interfacestore.NewContainerInterface(
+	containerConfig := interfacestore.NewContainerInterface(
 		interfaceName,
 		containerID,
 		podName,
 		podNamespace,
 		containerMAC,
 		containerIP)
+	containerConfig.NetNS = containerIface.Sandbox
+	return containerConfig
 }
 
 // BuildOVSPortExternalIDs parses OVS port external_ids from InterfaceConfig.
@@ -148,6 +157,12 @@ func BuildOVSPortExternalIDs(containerConfig *interfacestore.InterfaceConfig) ma
 	externalIDs[ovsExternalIDIP] = containerConfig.IP.String()
 	externalIDs[ovsExternalIDPodName] = containerConfig.PodName
 	externalIDs[ovsExternalIDPodNamespace] = containerConfig.PodNamespace
+	if containerConfig.SriovVFDeviceID != "" {
+		externalIDs[ovsExternalIDSriovVFID] = containerConfig.SriovVFDeviceID
+	}
+	if containerConfig.NetNS != "" {
+		externalIDs[ovsExternalIDNetNS] = containerConfig.NetNS
+	}
 	return externalIDs
 }
 
@@ -182,6 +197,8 @@ func ParseOVSPortInterfaceConfig(portData *ovsconfig.OVSPortData, portConfig *in
 		podNamespace,
 		containerMAC,
 		containerIP)
+	interfaceConfig.SriovVFDeviceID = portData.ExternalIDs[ovsExternalIDSriovVFID]
+	interfaceConfig.NetNS = portData.ExternalIDs[ovsExternalIDNetNS]
 	interfaceConfig.OVSPortConfig = portConfig
 	return interfaceConfig
 }
@@ -208,11 +225,11 @@ func (pc *podConfigurator) configureInterfaces(
 		return nil
 	}
 
-	// Delete veth pair if any failure occurs in later manipulation.
+	// Delete veth pair or release SR-IOV VF if any failure occurs in later manipulation.
 	success := false
 	defer func() {
 		if !success {
-			_ = pc.ifConfigurator.removeContainerLink(containerID, hostIface.Name)
+			_ = pc.removeContainerLink(containerID, hostIface.Name, containerNetNS, containerIFDev, sriovVFDeviceID)
 		}
 	}()
 
@@ -230,7 +247,7 @@ func (pc *podConfigurator) configureInterfaces(
 	}
 
 	var containerConfig *interfacestore.InterfaceConfig
-	if containerConfig, err = pc.connectInterfaceToOVS(podName, podNameSpace, containerID, hostIface, containerIface, result.IPs); err != nil {
+	if containerConfig, err = pc.connectInterfaceToOVS(podName, podNameSpace, containerID, hostIface, containerIface, result.IPs, sriovVFDeviceID); err != nil {
 		return fmt.Errorf("failed to connect to ovs for container %s: %v", containerID, err)
 	}
 	defer func() {
@@ -267,7 +284,19 @@ func (pc *podConfigurator) createOVSPort(ovsPortName string, ovsAttachInfo map[s
 	}
 }
 
-func (pc *podConfigurator) removeInterfaces(containerID string) error {
+// removeContainerLink deletes the veth pair of the container, or releases the SR-IOV VF if it is used as the
+// container interface, as the VF representor attached to OVS cannot be deleted.
+func (pc *podConfigurator) removeContainerLink(containerID, hostInterfaceName, containerNetNS, containerIFDev, sriovVFDeviceID string) error {
+	if sriovVFDeviceID != "" {
+		return pc.ifConfigurator.releaseContainerVF(containerID, containerNetNS, containerIFDev, sriovVFDeviceID)
+	}
+	return pc.ifConfigurator.removeContainerLink(containerID, hostInterfaceName)
+}
+
+// removeInterfaces removes the container interface and its OVS configuration. containerNetNS and containerIFDev
+// are only needed to release the SR-IOV VF used as the container interface, they can be empty if the network
+// namespace of the container does not exist anymore.
+func (pc *podConfigurator) removeInterfaces(containerID, containerNetNS, containerIFDev string) error {
 	containerConfig, found := pc.ifaceStore.GetContainerInterface(containerID)
 	if !found {
 		klog.V(2).Infof("Did not find the port for container %s in local cache", containerID)
@@ -288,7 +317,7 @@ func (pc *podConfigurator) removeInterfaces(containerID string) error {
 		return err
 	}
 
-	if err := pc.ifConfigurator.removeContainerLink(containerID, containerConfig.InterfaceName); err != nil {
+	if err := pc.removeContainerLink(containerID, containerConfig.InterfaceName, containerNetNS, containerIFDev, containerConfig.SriovVFDeviceID); err != nil {
 		return err
 	}
 	return nil
@@ -427,10 +456,18 @@ func (pc *podConfigurator) reconcile(pods []corev1.Pod) error {
 			); err != nil {
 				klog.Errorf("Error when re-installing flows for Pod %s", namespacedName)
 			}
+			if pc.isAntreaIPAMPod(containerConfig.IP) {
+				if err := pc.routeClient.AddLocalPodRoute(containerConfig.IP); err != nil {
+					klog.Errorf("Error when re-installing route for Pod %s: %v", namespacedName, err)
+				}
+			}
+			if err := pc.routeClient.AddPodNeighborProxy(containerConfig.IP); err != nil {
+				klog.Errorf("Error when re-installing neighbor proxy for Pod %s: %v", namespacedName, err)
+			}
 		} else {
 			// clean-up and delete interface
 			klog.V(4).Infof("Deleting interface %s", containerConfig.InterfaceName)
-			if err := pc.removeInterfaces(containerConfig.ContainerID); err != nil {
+			if err := pc.removeInterfaces(containerConfig.ContainerID, "", ""); err != nil {
 				klog.Errorf("Failed to delete interface %s: %v", containerConfig.InterfaceName, err)
 			}
 			// interface should no longer be in store after the call to removeInterfaces
@@ -460,10 +497,12 @@ func (pc *podConfigurator) connectInterfaceToOVS(
 	hostIface *current.Interface,
 	containerIface *current.Interface,
 	ips []*current.IPConfig,
+	sriovVFDeviceID string,
 ) (*interfacestore.InterfaceConfig, error) {
-	// Use the outer veth interface name as the OVS port name.
+	// Use the outer veth interface name or the VF representor name as the OVS port name.
 	ovsPortName := hostIface.Name
 	containerConfig := buildContainerConfig(ovsPortName, containerID, podName, podNameSpace, containerIface, ips)
+	containerConfig.SriovVFDeviceID = sriovVFDeviceID
 
 	// create OVS Port and add attach container configuration into external_ids
 	klog.V(2).Infof("Adding OVS port %s for container %s", ovsPortName, containerID)
@@ -490,6 +529,19 @@ func (pc *podConfigurator) connectInterfaceToOVS(
 	if err != nil {
 		return nil, fmt.Errorf("failed to add Openflow entries for container %s: %v", containerID, err)
 	}
+	if pc.isAntreaIPAMPod(containerConfig.IP) {
+		if err = pc.routeClient.AddLocalPodRoute(containerConfig.IP); err != nil {
+			_ = pc.ofClient.UninstallPodFlows(ovsPortName)
+			return nil, err
+		}
+	}
+	if err = pc.routeClient.AddPodNeighborProxy(containerConfig.IP); err != nil {
+		if pc.isAntreaIPAMPod(containerConfig.IP) {
+			_ = pc.routeClient.DeleteLocalPodRoute(containerConfig.IP)
+		}
+		_ = pc.ofClient.UninstallPodFlows(ovsPortName)
+		return nil, err
+	}
 	containerConfig.OVSPortConfig = &interfacestore.OVSPortConfig{PortUUID: portUUID, OFPort: ofPort}
 	// Add containerConfig into local cache
 	pc.ifaceStore.AddInterface(containerConfig)
@@ -499,6 +551,14 @@ func (pc *podConfigurator) connectInterfaceToOVS(
 // disconnectInterfaceFromOVS disconnects an existing interface from ovs br-int.
 func (pc *podConfigurator) disconnectInterfaceFromOVS(containerConfig *interfacestore.InterfaceConfig) error {
 	containerID := containerConfig.ContainerID
+	if err := pc.routeClient.DeletePodNeighborProxy(containerConfig.IP); err != nil {
+		return err
+	}
+	if pc.isAntreaIPAMPod(containerConfig.IP) {
+		if err := pc.routeClient.DeleteLocalPodRoute(containerConfig.IP); err != nil {
+			return err
+		}
+	}
 	klog.V(2).Infof("Deleting Openflow entries for container %s", containerID)
 	if err := pc.ofClient.UninstallPodFlows(containerConfig.InterfaceName); err != nil {
 		return fmt.Errorf("failed to delete Openflow entries for container %s: %v", containerID, err)
@@ -540,7 +600,7 @@ func (pc *podConfigurator) connectInterceptedInterface(
 		return fmt.Errorf("connectInterceptedInterface failed to migrate: %w", err)
 	}
 	_, err = pc.connectInterfaceToOVS(podName, podNameSpace, containerID, hostIface,
-		containerIface, containerIPs)
+		containerIface, containerIPs, "")
 	return err
 }
 
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 
@@ -33,7 +34,9 @@ import (
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/cniserver/ipam"
+	argtypes "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/types"
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
 	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/agent/route"
@@ -42,6 +45,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	"github.com/vmware-tanzu/antrea/pkg/cni"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	antreaversion "github.com/vmware-tanzu/antrea/pkg/version"
 )
 
 // containerAccessArbitrator is used to ensure that concurrent goroutines cannot perfom operations
@@ -100,6 +104,8 @@ type CNIServer struct {
 	podUpdates  chan<- v1beta1.PodReference
 	isChaining  bool
 	routeClient route.Interface
+	// eventRecorder reports the failures to set up the network of the Pods as Events on the Pods.
+	eventRecorder *events.Recorder
 }
 
 var supportedCNIVersionSet map[string]bool
@@ -131,7 +137,7 @@ type NetworkConfig struct {
 type CNIConfig struct {
 	*NetworkConfig
 	*cnipb.CniCmdArgs
-	*k8sArgs
+	*argtypes.K8sArgs
 }
 
 // updateResultIfaceConfig processes the result from the IPAM plugin and does the following:
@@ -174,8 +180,8 @@ func (s *CNIServer) loadNetworkConfig(request *cnipb.CniCmdRequest) (*CNIConfig,
 	if err := json.Unmarshal(request.CniArgs.NetworkConfiguration, cniConfig); err != nil {
 		return cniConfig, err
 	}
-	cniConfig.k8sArgs = &k8sArgs{}
-	if err := cnitypes.LoadArgs(request.CniArgs.Args, cniConfig.k8sArgs); err != nil {
+	cniConfig.K8sArgs = &argtypes.K8sArgs{}
+	if err := cnitypes.LoadArgs(request.CniArgs.Args, cniConfig.K8sArgs); err != nil {
 		return cniConfig, err
 	}
 	if !s.isChaining {
@@ -319,7 +325,7 @@ func (s *CNIServer) parsePrevResultFromRequest(networkConfig *NetworkConfig) (*c
 
 // validatePrevResult validates container and host interfaces configuration
 // the return value is nil if prevResult is valid
-func (s *CNIServer) validatePrevResult(cfgArgs *cnipb.CniCmdArgs, k8sCNIArgs *k8sArgs, prevResult *current.Result, sriovVFDeviceID string) *cnipb.CniCmdResponse {
+func (s *CNIServer) validatePrevResult(cfgArgs *cnipb.CniCmdArgs, k8sCNIArgs *argtypes.K8sArgs, prevResult *current.Result, sriovVFDeviceID string) *cnipb.CniCmdResponse {
 	containerID := cfgArgs.ContainerId
 	netNS := s.hostNetNsPath(cfgArgs.Netns)
 
@@ -390,9 +396,10 @@ func (s *CNIServer) CmdAdd(ctx context.Context, request *cnipb.CniCmdRequest) (*
 		}
 	} else {
 		// Request IP Address from IPAM driver.
-		ipamResult, err = ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, infraContainer)
+		ipamResult, err = ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, infraContainer)
 		if err != nil {
 			klog.Errorf("Failed to add IP addresses from IPAM driver: %v", err)
+			s.eventRecorder.PodFailure(string(cniConfig.K8S_POD_NAMESPACE), string(cniConfig.K8S_POD_NAME), events.ReasonPodNetworkSetupFailed, "Failed to allocate IP addresses", err)
 			return s.ipamFailureResponse(err), nil
 		}
 	}
@@ -417,6 +424,7 @@ func (s *CNIServer) CmdAdd(ctx context.Context, request *cnipb.CniCmdRequest) (*
 		isInfraContainer,
 	); err != nil {
 		klog.Errorf("Failed to configure interfaces for container %s: %v", cniConfig.ContainerId, err)
+		s.eventRecorder.PodFailure(podNamespace, podName, events.ReasonPodNetworkSetupFailed, "Failed to configure the network interfaces", err)
 		return s.configInterfaceFailureResponse(err), nil
 	}
 
@@ -448,13 +456,13 @@ func (s *CNIServer) CmdDel(_ context.Context, request *cnipb.CniCmdRequest) (
 		return s.interceptDel(cniConfig)
 	}
 	// Release IP to IPAM driver
-	if err := ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, infraContainer); err != nil {
+	if err := ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, infraContainer); err != nil {
 		klog.Errorf("Failed to delete IP addresses by IPAM driver: %v", err)
 		return s.ipamFailureResponse(err), nil
 	}
 	klog.Info("Deleted IP addresses by IPAM driver")
 	// Remove host interface and OVS configuration
-	if err := s.podConfigurator.removeInterfaces(cniConfig.ContainerId); err != nil {
+	if err := s.podConfigurator.removeInterfaces(cniConfig.ContainerId, s.hostNetNsPath(cniConfig.Netns), cniConfig.Ifname); err != nil {
 		klog.Errorf("Failed to remove interfaces for container %s: %v", cniConfig.ContainerId, err)
 		return s.configInterfaceFailureResponse(err), nil
 	}
@@ -478,7 +486,7 @@ func (s *CNIServer) CmdCheck(_ context.Context, request *cnipb.CniCmdRequest) (
 		return s.interceptCheck(cniConfig)
 	}
 
-	if err := ipam.ExecIPAMCheck(cniConfig.CniCmdArgs, cniConfig.IPAM.Type); err != nil {
+	if err := ipam.ExecIPAMCheck(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type); err != nil {
 		klog.Errorf("Failed to check IPAM configuration: %v", err)
 		return s.ipamFailureResponse(err), nil
 	}
@@ -487,7 +495,7 @@ func (s *CNIServer) CmdCheck(_ context.Context, request *cnipb.CniCmdRequest) (
 	if valid, _ := version.GreaterThanOrEqualTo(cniVersion, "0.4.0"); valid {
 		if prevResult, response := s.parsePrevResultFromRequest(cniConfig.NetworkConfig); response != nil {
 			return response, nil
-		} else if response := s.validatePrevResult(cniConfig.CniCmdArgs, cniConfig.k8sArgs, prevResult, cniConfig.DeviceID); response != nil {
+		} else if response := s.validatePrevResult(cniConfig.CniCmdArgs, cniConfig.K8sArgs, prevResult, cniConfig.DeviceID); response != nil {
 			return response, nil
 		}
 	}
@@ -495,6 +503,36 @@ func (s *CNIServer) CmdCheck(_ context.Context, request *cnipb.CniCmdRequest) (
 	return &cnipb.CniCmdResponse{CniResult: []byte("")}, nil
 }
 
+// Version returns the version of the Agent, the version of the CNI gRPC API and the CNI spec versions supported by
+// the server.
+func (s *CNIServer) Version(_ context.Context, _ *cnipb.CniVersionRequest) (*cnipb.CniVersionResponse, error) {
+	cniVersions := make([]string, 0, len(s.supportedCNIVersions))
+	for v := range s.supportedCNIVersions {
+		cniVersions = append(cniVersions, v)
+	}
+	sort.Strings(cniVersions)
+	return &cnipb.CniVersionResponse{
+		AgentVersion:         antreaversion.GetFullVersion(),
+		ApiVersion:           s.serverVersion,
+		SupportedCniVersions: cniVersions,
+	}, nil
+}
+
+// Health returns whether the server is ready to process CNI requests, i.e. whether it has been initialized and both
+// the OVSDB and the OpenFlow connections to the bridge are up.
+func (s *CNIServer) Health(_ context.Context, _ *cnipb.CniHealthRequest) (*cnipb.CniHealthResponse, error) {
+	if s.podConfigurator == nil {
+		return &cnipb.CniHealthResponse{Healthy: false, Message: "CNI server is not initialized"}, nil
+	}
+	if _, err := s.podConfigurator.ovsBridgeClient.GetOVSVersion(); err != nil {
+		return &cnipb.CniHealthResponse{Healthy: false, Message: fmt.Sprintf("OVSDB connection is down: %v", err)}, nil
+	}
+	if !s.podConfigurator.ofClient.IsConnected() {
+		return &cnipb.CniHealthResponse{Healthy: false, Message: "OpenFlow connection to the OVS bridge is down"}, nil
+	}
+	return &cnipb.CniHealthResponse{Healthy: true}, nil
+}
+
 func New(
 	cniSocket, hostProcPathPrefix string,
 	nodeConfig *config.NodeConfig,
@@ -502,6 +540,7 @@ func New(
 	podUpdates chan<- v1beta1.PodReference,
 	isChaining bool,
 	routeClient route.Interface,
+	eventRecorder *events.Recorder,
 ) *CNIServer {
 	return &CNIServer{
 		cniSocket:            cniSocket,
@@ -514,6 +553,7 @@ func New(
 		podUpdates:           podUpdates,
 		isChaining:           isChaining,
 		routeClient:          routeClient,
+		eventRecorder:        eventRecorder,
 	}
 }
 
@@ -524,7 +564,11 @@ func (s *CNIServer) Initialize(
 	ovsDatapathType string,
 ) error {
 	var err error
-	s.podConfigurator, err = newPodConfigurator(ovsBridgeClient, ofClient, s.routeClient, ifaceStore, s.nodeConfig.GatewayConfig.MAC, ovsDatapathType, ovsBridgeClient.IsHardwareOffloadEnabled())
+	var podCIDR *net.IPNet
+	if !s.isChaining {
+		podCIDR = s.nodeConfig.PodCIDR
+	}
+	s.podConfigurator, err = newPodConfigurator(ovsBridgeClient, ofClient, s.routeClient, ifaceStore, s.nodeConfig.GatewayConfig.MAC, podCIDR, ovsDatapathType, ovsBridgeClient.IsHardwareOffloadEnabled())
 	if err != nil {
 		return fmt.Errorf("error during initialize podConfigurator: %v", err)
 	}
@@ -31,9 +31,11 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/cniserver/ipam"
 	ipamtest "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/ipam/testing"
 	cniservertest "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/testing"
+	argtypes "github.com/vmware-tanzu/antrea/pkg/agent/cniserver/types"
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
 	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	routetest "github.com/vmware-tanzu/antrea/pkg/agent/route/testing"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	cnipb "github.com/vmware-tanzu/antrea/pkg/apis/cni/v1beta1"
 	"github.com/vmware-tanzu/antrea/pkg/cni"
@@ -131,8 +133,8 @@ func TestIPAMService(t *testing.T) {
 	requestMsg, _ := newRequest(args, networkCfg, "", t)
 
 	t.Run("Error on ADD", func(t *testing.T) {
-		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("IPAM add error"))
-		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil)
+		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil, fmt.Errorf("IPAM add error"))
+		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
 		response, err := cniServer.CmdAdd(cxt, &requestMsg)
 		require.Nil(t, err, "expected no rpc error")
 		checkErrorResponse(t, response, cnipb.ErrorCode_IPAM_FAILURE, "IPAM add error")
@@ -140,63 +142,63 @@ func TestIPAMService(t *testing.T) {
 
 	t.Run("Error on DEL", func(t *testing.T) {
 		// Prepare cached IPAM result which will be deleted later.
-		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any()).Times(1)
+		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil, nil).Times(1)
 		cniConfig, _ := cniServer.checkRequestMessage(&requestMsg)
-		_, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		_, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no Add error")
 
-		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any()).Return(fmt.Errorf("IPAM delete error"))
+		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, fmt.Errorf("IPAM delete error"))
 		response, err := cniServer.CmdDel(cxt, &requestMsg)
 		require.Nil(t, err, "expected no rpc error")
 		checkErrorResponse(t, response, cnipb.ErrorCode_IPAM_FAILURE, "IPAM delete error")
 
 		// Cached result would be removed after a successful retry of IPAM DEL.
-		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil)
-		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no Del error")
 
 	})
 
 	t.Run("Error on CHECK", func(t *testing.T) {
-		ipamMock.EXPECT().Check(gomock.Any(), gomock.Any()).Return(fmt.Errorf("IPAM check error"))
+		ipamMock.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, fmt.Errorf("IPAM check error"))
 		response, err := cniServer.CmdCheck(cxt, &requestMsg)
 		require.Nil(t, err, "expected no rpc error")
 		checkErrorResponse(t, response, cnipb.ErrorCode_IPAM_FAILURE, "IPAM check error")
 	})
 
 	t.Run("Idempotent Call of IPAM ADD/DEL for the same Pod", func(t *testing.T) {
-		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any()).Times(1)
-		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any()).Times(2)
+		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil, nil).Times(1)
+		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
 		cniConfig, response := cniServer.checkRequestMessage(&requestMsg)
 		require.Nil(t, response, "expected no rpc error")
-		ipamResult, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		ipamResult, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM add error")
-		ipamResult2, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		ipamResult2, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM add error")
 		assert.Equal(t, ipamResult, ipamResult2)
-		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM del error")
-		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM del error")
 	})
 
 	t.Run("Idempotent Call of IPAM ADD/DEL for the same Pod with different containers", func(t *testing.T) {
-		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any()).Times(2)
-		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any()).Times(2)
+		ipamMock.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil, nil).Times(2)
+		ipamMock.EXPECT().Del(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
 		cniConfig, response := cniServer.checkRequestMessage(&requestMsg)
 		require.Nil(t, response, "expected no rpc error")
-		_, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		_, err := ipam.ExecIPAMAdd(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM add error")
 		workerContainerID := "test-infra-2222222"
 		args2 := cniservertest.GenerateCNIArgs(testPodName, testPodNamespace, workerContainerID)
 		requestMsg2, _ := newRequest(args2, networkCfg, "", t)
 		cniConfig2, response := cniServer.checkRequestMessage(&requestMsg2)
 		require.Nil(t, response, "expected no rpc error")
-		_, err = ipam.ExecIPAMAdd(cniConfig2.CniCmdArgs, cniConfig.IPAM.Type, cniConfig2.getInfraContainer())
+		_, err = ipam.ExecIPAMAdd(cniConfig2.CniCmdArgs, cniConfig2.K8sArgs, cniConfig.IPAM.Type, cniConfig2.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM add error")
-		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
+		err = ipam.ExecIPAMDelete(cniConfig.CniCmdArgs, cniConfig.K8sArgs, cniConfig.IPAM.Type, cniConfig.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM del error")
-		err = ipam.ExecIPAMDelete(cniConfig2.CniCmdArgs, cniConfig.IPAM.Type, cniConfig2.getInfraContainer())
+		err = ipam.ExecIPAMDelete(cniConfig2.CniCmdArgs, cniConfig2.K8sArgs, cniConfig.IPAM.Type, cniConfig2.getInfraContainer())
 		require.Nil(t, err, "expected no IPAM del error")
 	})
 }
@@ -224,7 +226,7 @@ func TestValidatePrevResult(t *testing.T) {
 	cniServer := newCNIServer(t)
 	cniVersion := "0.4.0"
 	networkCfg := generateNetworkConfiguration("testCfg", cniVersion)
-	k8sPodArgs := &k8sArgs{}
+	k8sPodArgs := &argtypes.K8sArgs{}
 	cnitypes.LoadArgs(args, k8sPodArgs)
 	networkCfg.PrevResult = nil
 	ips := []string{"10.1.2.100/24,10.1.2.1,4"}
@@ -275,7 +277,7 @@ func TestValidatePrevResult(t *testing.T) {
 		cniConfig.Netns = "invalid_netns"
 		sriovVFDeviceID := ""
 		prevResult.Interfaces = []*current.Interface{hostIface, containerIface}
-		cniServer.podConfigurator, _ = newPodConfigurator(nil, nil, nil, nil, nil, "", false)
+		cniServer.podConfigurator, _ = newPodConfigurator(nil, nil, nil, nil, nil, nil, "", false)
 		response := cniServer.validatePrevResult(cniConfig.CniCmdArgs, k8sPodArgs, prevResult, sriovVFDeviceID)
 		checkErrorResponse(t, response, cnipb.ErrorCode_CHECK_INTERFACE_FAILURE, "")
 	})
@@ -286,7 +288,7 @@ func TestValidatePrevResult(t *testing.T) {
 		cniConfig.Netns = "invalid_netns"
 		sriovVFDeviceID := "0000:03:00.6"
 		prevResult.Interfaces = []*current.Interface{hostIface, containerIface}
-		cniServer.podConfigurator, _ = newPodConfigurator(nil, nil, nil, nil, nil, "", true)
+		cniServer.podConfigurator, _ = newPodConfigurator(nil, nil, nil, nil, nil, nil, "", true)
 		response := cniServer.validatePrevResult(cniConfig.CniCmdArgs, k8sPodArgs, prevResult, sriovVFDeviceID)
 		checkErrorResponse(t, response, cnipb.ErrorCode_CHECK_INTERFACE_FAILURE, "")
 	})
@@ -402,9 +404,10 @@ func TestRemoveInterface(t *testing.T) {
 	defer controller.Finish()
 	mockOVSBridgeClient := ovsconfigtest.NewMockOVSBridgeClient(controller)
 	mockOFClient := openflowtest.NewMockClient(controller)
+	routeMock := routetest.NewMockInterface(controller)
 	ifaceStore := interfacestore.NewInterfaceStore()
 	gwMAC, _ := net.ParseMAC("00:00:11:11:11:11")
-	podConfigurator, err := newPodConfigurator(mockOVSBridgeClient, mockOFClient, nil, ifaceStore, gwMAC, "system", false)
+	podConfigurator, err := newPodConfigurator(mockOVSBridgeClient, mockOFClient, routeMock, ifaceStore, gwMAC, nil, "system", false)
 	require.Nil(t, err, "No error expected in podConfigurator constructor")
 
 	containerMAC, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
@@ -443,10 +446,11 @@ func TestRemoveInterface(t *testing.T) {
 		setup("test1")
 		ifaceStore.AddInterface(containerConfig)
 
+		routeMock.EXPECT().DeletePodNeighborProxy(containerIP).Return(nil)
 		mockOFClient.EXPECT().UninstallPodFlows(hostIfaceName).Return(nil)
 		mockOVSBridgeClient.EXPECT().DeletePort(fakePortUUID).Return(nil)
 
-		err := podConfigurator.removeInterfaces(containerID)
+		err := podConfigurator.removeInterfaces(containerID, "", "")
 		require.Nil(t, err, "Failed to remove interface")
 		_, found := ifaceStore.GetContainerInterface(containerID)
 		assert.False(t, found, "Interface should not be in the local cache anymore")
@@ -456,10 +460,11 @@ func TestRemoveInterface(t *testing.T) {
 		setup("test2")
 		ifaceStore.AddInterface(containerConfig)
 
+		routeMock.EXPECT().DeletePodNeighborProxy(containerIP).Return(nil)
 		mockOVSBridgeClient.EXPECT().DeletePort(fakePortUUID).Return(ovsconfig.NewTransactionError(fmt.Errorf("error while deleting OVS port"), true))
 		mockOFClient.EXPECT().UninstallPodFlows(hostIfaceName).Return(nil)
 
-		err := podConfigurator.removeInterfaces(containerID)
+		err := podConfigurator.removeInterfaces(containerID, "", "")
 		require.NotNil(t, err, "Expected interface remove to fail")
 		_, found := ifaceStore.GetContainerInterface(containerID)
 		assert.True(t, found, "Interface should still be in local cache because of port deletion failure")
@@ -469,15 +474,55 @@ func TestRemoveInterface(t *testing.T) {
 		setup("test3")
 		ifaceStore.AddInterface(containerConfig)
 
+		routeMock.EXPECT().DeletePodNeighborProxy(containerIP).Return(nil)
 		mockOFClient.EXPECT().UninstallPodFlows(hostIfaceName).Return(fmt.Errorf("failed to delete openflow entry"))
 
-		err := podConfigurator.removeInterfaces(containerID)
+		err := podConfigurator.removeInterfaces(containerID, "", "")
 		require.NotNil(t, err, "Expected interface remove to fail")
 		_, found := ifaceStore.GetContainerInterface(containerID)
 		assert.True(t, found, "Interface should still be in local cache because of flow deletion failure")
 	})
 }
 
+func TestVersion(t *testing.T) {
+	cniServer := newCNIServer(t)
+	resp, err := cniServer.Version(context.Background(), &cnipb.CniVersionRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, cni.AntreaCNIVersion, resp.ApiVersion)
+	assert.NotEmpty(t, resp.AgentVersion)
+	assert.Contains(t, resp.SupportedCniVersions, supportedCNIVersion)
+	assert.NotContains(t, resp.SupportedCniVersions, unsupportedCNIVersion)
+}
+
+func TestHealth(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOVSBridgeClient := ovsconfigtest.NewMockOVSBridgeClient(controller)
+	mockOFClient := openflowtest.NewMockClient(controller)
+	cniServer := newCNIServer(t)
+
+	resp, err := cniServer.Health(context.Background(), &cnipb.CniHealthRequest{})
+	require.NoError(t, err)
+	assert.False(t, resp.Healthy, "CNI server should not be healthy before initialization")
+
+	cniServer.podConfigurator = &podConfigurator{ovsBridgeClient: mockOVSBridgeClient, ofClient: mockOFClient}
+	mockOVSBridgeClient.EXPECT().GetOVSVersion().Return("", ovsconfig.NewTransactionError(fmt.Errorf("connection refused"), true))
+	resp, err = cniServer.Health(context.Background(), &cnipb.CniHealthRequest{})
+	require.NoError(t, err)
+	assert.False(t, resp.Healthy, "CNI server should not be healthy when OVSDB is down")
+
+	mockOVSBridgeClient.EXPECT().GetOVSVersion().Return("2.14.0", nil).Times(2)
+	mockOFClient.EXPECT().IsConnected().Return(false)
+	resp, err = cniServer.Health(context.Background(), &cnipb.CniHealthRequest{})
+	require.NoError(t, err)
+	assert.False(t, resp.Healthy, "CNI server should not be healthy when OpenFlow connection is down")
+
+	mockOFClient.EXPECT().IsConnected().Return(true)
+	resp, err = cniServer.Health(context.Background(), &cnipb.CniHealthRequest{})
+	require.NoError(t, err)
+	assert.True(t, resp.Healthy)
+}
+
 func TestBuildOVSPortExternalIDs(t *testing.T) {
 	containerID := uuid.New().String()
 	containerMAC, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
@@ -496,6 +541,44 @@ func TestBuildOVSPortExternalIDs(t *testing.T) {
 	if !existed || parsedID != containerID {
 		t.Errorf("Failed to parse container configuration")
 	}
+	_, existed = externalIds[ovsExternalIDSriovVFID]
+	assert.False(t, existed, "SR-IOV VF device ID should not be set for veth interface")
+}
+
+func TestSriovVFOVSPortExternalIDs(t *testing.T) {
+	containerID := uuid.New().String()
+	containerMAC, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	containerIP := net.ParseIP("10.1.2.100")
+	containerConfig := interfacestore.NewContainerInterface("pod1-abcd", containerID, "test-1", "t1", containerMAC, containerIP)
+	containerConfig.SriovVFDeviceID = "0000:03:00.2"
+	externalIDs := make(map[string]string)
+	for k, v := range BuildOVSPortExternalIDs(containerConfig) {
+		externalIDs[k] = v.(string)
+	}
+	assert.Equal(t, "0000:03:00.2", externalIDs[ovsExternalIDSriovVFID])
+
+	portConfig := &interfacestore.OVSPortConfig{PortUUID: "12345678", OFPort: 10}
+	parsedConfig := ParseOVSPortInterfaceConfig(&ovsconfig.OVSPortData{Name: "pod1-abcd", ExternalIDs: externalIDs}, portConfig)
+	assert.Equal(t, containerConfig.ContainerInterfaceConfig, parsedConfig.ContainerInterfaceConfig)
+}
+
+func TestNetNSOVSPortExternalIDs(t *testing.T) {
+	containerID := uuid.New().String()
+	containerMAC, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	containerIP := net.ParseIP("10.1.2.100")
+	containerConfig := buildContainerConfig("pod1-abcd", containerID, "test-1", "t1",
+		&current.Interface{Mac: containerMAC.String(), Sandbox: "/host/proc/1234/ns/net"},
+		[]*current.IPConfig{{Version: "4", Address: net.IPNet{IP: containerIP, Mask: net.CIDRMask(24, 32)}}})
+	assert.Equal(t, "/host/proc/1234/ns/net", containerConfig.NetNS)
+	externalIDs := make(map[string]string)
+	for k, v := range BuildOVSPortExternalIDs(containerConfig) {
+		externalIDs[k] = v.(string)
+	}
+	assert.Equal(t, "/host/proc/1234/ns/net", externalIDs[ovsExternalIDNetNS])
+
+	portConfig := &interfacestore.OVSPortConfig{PortUUID: "12345678", OFPort: 10}
+	parsedConfig := ParseOVSPortInterfaceConfig(&ovsconfig.OVSPortData{Name: "pod1-abcd", ExternalIDs: externalIDs}, portConfig)
+	assert.Equal(t, containerConfig.ContainerInterfaceConfig, parsedConfig.ContainerInterfaceConfig)
 }
 
 func translateRawPrevResult(prevResult *current.Result, cniVersion string) (map[string]interface{}, error) {
@@ -17,6 +17,7 @@ package config
 import (
 	"fmt"
 	"net"
+	"strconv"
 
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
 )
@@ -42,6 +43,11 @@ const (
 	IpsecESPOverhead = 38
 )
 
+// NodePortVirtualIP is the virtual IP to which the NodePort Service traffic is DNAT'd in the host network when
+// AntreaProxy serves the NodePort Services. The traffic is routed to OVS through the host gateway, where it is
+// load-balanced like the traffic to the ClusterIP of the Service.
+var NodePortVirtualIP = net.ParseIP("169.254.169.110").To4()
+
 type GatewayConfig struct {
 	// Name is the name of host gateway, e.g. antrea-gw0.
 	Name string
@@ -87,6 +93,31 @@ type NodeConfig struct {
 	GatewayConfig *GatewayConfig
 	// The config of the OVS bridge uplink interface. Only for Windows Node.
 	UplinkNetConfig *AdapterNetConfig
+	// The optional OVS features which are not supported by the Node. They are
+	// detected when the Agent starts.
+	UnsupportedOVSFeatures []string
+}
+
+// Optional OVS features detected by the Agent. When one of them is not
+// supported by the Node, an alternative flow design is used or the
+// functionality depending on it is disabled.
+const (
+	// OVSFeatureMeters is the support of OpenFlow meters by the OVS datapath.
+	OVSFeatureMeters = "meters"
+	// OVSFeatureGeneveOptions is the mapping of Geneve options to
+	// tun_metadata fields, used by Traceflow to tag its packets across Nodes.
+	OVSFeatureGeneveOptions = "geneve_options"
+)
+
+// OVSFeatureSupported returns whether the optional OVS feature is supported by
+// the Node.
+func (n *NodeConfig) OVSFeatureSupported(feature string) bool {
+	for _, f := range n.UnsupportedOVSFeatures {
+		if f == feature {
+			return false
+		}
+	}
+	return true
 }
 
 func (n *NodeConfig) String() string {
@@ -100,4 +131,74 @@ type NetworkConfig struct {
 	TunnelType        ovsconfig.TunnelType
 	EnableIPSecTunnel bool
 	IPSecPSK          string
+	// The OS of the Nodes which use host-gateway routing instead of encapsulation
+	// in Hybrid mode. Empty means that the encapsulation decision is based on the
+	// Node subnets only.
+	HybridNoEncapNodeOS string
+	// Destination UDP port of the Geneve and VXLAN tunnels. 0 means that the
+	// IANA port of the tunnel type is used.
+	TunnelPort int32
+	// TTL of the outer IP header of the tunneled packets, either a number
+	// between 1 and 255 or "inherit". Empty means that the OVS default is used.
+	TunnelTTL string
+	// Whether the DF bit of the outer IP header of the tunneled packets is
+	// cleared, so that they can be fragmented by the underlay network.
+	TunnelClearDF bool
+	// Whether the ToS byte of the inner packet, including the ECN bits, is
+	// copied to the outer IP header of the tunneled packets.
+	TunnelInheritTOS bool
+}
+
+// TunnelOptionInherit is the value of the ttl and tos tunnel options which
+// copies the field of the inner packet to the outer IP header.
+const TunnelOptionInherit = "inherit"
+
+// TunnelConfigOptions are the OVS interface options of the default tunnel port
+// which are set from NetworkConfig.
+var TunnelConfigOptions = []string{"dst_port", "ttl", "df_default", "tos"}
+
+// ValidateTunnelTTL returns an error if the TTL is neither empty, "inherit",
+// nor a number between 1 and 255.
+func ValidateTunnelTTL(ttl string) error {
+	if ttl == "" || ttl == TunnelOptionInherit {
+		return nil
+	}
+	if v, err := strconv.Atoi(ttl); err != nil || v < 1 || v > 255 {
+		return fmt.Errorf("tunnel TTL %s is invalid, it should be inherit or a number between 1 and 255", ttl)
+	}
+	return nil
+}
+
+// TunnelOptions returns the OVS interface options of the default tunnel port
+// which are set from the configuration. The TunnelConfigOptions which are not
+// returned use the OVS defaults.
+func (nc *NetworkConfig) TunnelOptions() map[string]string {
+	options := map[string]string{}
+	if nc.TunnelPort != 0 && (nc.TunnelType == ovsconfig.GeneveTunnel || nc.TunnelType == ovsconfig.VXLANTunnel) {
+		options["dst_port"] = strconv.Itoa(int(nc.TunnelPort))
+	}
+	if nc.TunnelTTL != "" {
+		options["ttl"] = nc.TunnelTTL
+	}
+	if nc.TunnelClearDF {
+		options["df_default"] = "false"
+	}
+	if nc.TunnelInheritTOS {
+		options["tos"] = TunnelOptionInherit
+	}
+	return options
+}
+
+// EncapModeToPeer returns the traffic encapsulation mode to use between the
+// local Node and a peer Node, given the OS of both Nodes. In Hybrid mode with
+// HybridNoEncapNodeOS set, traffic is routed without encapsulation if either
+// Node runs HybridNoEncapNodeOS, and is always encapsulated otherwise.
+func (nc *NetworkConfig) EncapModeToPeer(localOS, peerOS string) TrafficEncapModeType {
+	if nc.TrafficEncapMode != TrafficEncapModeHybrid || nc.HybridNoEncapNodeOS == "" {
+		return nc.TrafficEncapMode
+	}
+	if localOS == nc.HybridNoEncapNodeOS || peerOS == nc.HybridNoEncapNodeOS {
+		return TrafficEncapModeNoEncap
+	}
+	return TrafficEncapModeEncap
 }
@@ -0,0 +1,69 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+)
+
+func TestNetworkConfigEncapModeToPeer(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          TrafficEncapModeType
+		noEncapNodeOS string
+		localOS       string
+		peerOS        string
+		expMode       TrafficEncapModeType
+	}{
+		{"encap-mode", TrafficEncapModeEncap, "", "linux", "windows", TrafficEncapModeEncap},
+		{"no-encap-mode", TrafficEncapModeNoEncap, "", "linux", "linux", TrafficEncapModeNoEncap},
+		{"hybrid-mode", TrafficEncapModeHybrid, "", "linux", "windows", TrafficEncapModeHybrid},
+		{"hybrid-mode-linux-to-linux", TrafficEncapModeHybrid, "windows", "linux", "linux", TrafficEncapModeEncap},
+		{"hybrid-mode-linux-to-windows", TrafficEncapModeHybrid, "windows", "linux", "windows", TrafficEncapModeNoEncap},
+		{"hybrid-mode-windows-to-linux", TrafficEncapModeHybrid, "windows", "windows", "linux", TrafficEncapModeNoEncap},
+		{"hybrid-mode-unknown-peer-os", TrafficEncapModeHybrid, "linux", "windows", "", TrafficEncapModeEncap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nc := &NetworkConfig{TrafficEncapMode: tt.mode, HybridNoEncapNodeOS: tt.noEncapNodeOS}
+			assert.Equal(t, tt.expMode, nc.EncapModeToPeer(tt.localOS, tt.peerOS), "EncapModeToPeer did not return correct mode")
+		})
+	}
+}
+
+func TestValidateTunnelTTL(t *testing.T) {
+	for _, ttl := range []string{"", "inherit", "1", "64", "255"} {
+		assert.NoError(t, ValidateTunnelTTL(ttl), "TTL %s should be valid", ttl)
+	}
+	for _, ttl := range []string{"0", "256", "-1", "copy"} {
+		assert.Error(t, ValidateTunnelTTL(ttl), "TTL %s should be invalid", ttl)
+	}
+}
+
+func TestNetworkConfigTunnelOptions(t *testing.T) {
+	nc := &NetworkConfig{TunnelType: ovsconfig.GeneveTunnel}
+	assert.Empty(t, nc.TunnelOptions())
+
+	nc = &NetworkConfig{TunnelType: ovsconfig.VXLANTunnel, TunnelPort: 8472, TunnelTTL: "inherit", TunnelClearDF: true, TunnelInheritTOS: true}
+	assert.Equal(t, map[string]string{"dst_port": "8472", "ttl": "inherit", "df_default": "false", "tos": "inherit"}, nc.TunnelOptions())
+
+	// The destination port is only set for Geneve and VXLAN tunnels.
+	nc = &NetworkConfig{TunnelType: ovsconfig.GRETunnel, TunnelPort: 8472, TunnelTTL: "32"}
+	assert.Equal(t, map[string]string{"ttl": "32"}, nc.TunnelOptions())
+}
@@ -0,0 +1,990 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/noderoute"
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
+	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/route"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	clientset "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	coreinformersv1alpha1 "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha1"
+	corelistersv1alpha1 "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha1"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+const (
+	controllerName = "AntreaAgentEgressController"
+	// Set resyncPeriod to 0 to disable resyncing.
+	resyncPeriod time.Duration = 0
+	// How long to wait before retrying the processing of an Egress.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+	// Egresses are processed by a single worker, as a Pod can be selected by multiple Egresses and the datapath of
+	// the Pod is moved from one Egress to another.
+	defaultWorkers = 1
+	// defaultEgressAnnotation is the Namespace annotation whose value is the name of the default Egress of the
+	// Namespace. The Pods of the Namespace which are not selected by any Egress are SNAT'd by the default Egress.
+	defaultEgressAnnotation = "egress.antrea.tanzu.vmware.com/default-egress"
+	// How often the connections SNAT'd with the Egress IPs are counted.
+	snatConnectionsUpdateInterval = 30 * time.Second
+	// How long to wait for the Pods selected by an Egress held by this Node to be listed.
+	podWatchSyncTimeout = 30 * time.Second
+	// The range of source ports partitioned into blocks among the Pods SNAT'd with an Egress IP, when
+	// snatPortsPerPod is set. The well-known ports are excluded, as they are by cloud NAT gateways.
+	snatPortRangeStart = 1024
+	snatPortRangeEnd   = 65535
+)
+
+// egressState records the datapath realized on this Node for an Egress.
+type egressState struct {
+	egressIP string
+	// egressNode is the Node which owns the Egress IP, empty if no Node is eligible.
+	egressNode string
+	// snatPodIPs are the IPs of the Pods whose traffic is SNAT'd with the Egress IP on this Node, when this Node is
+	// the Egress Node.
+	snatPodIPs sets.String
+	// portBlocks is a map of the Pod IPs of snatPodIPs to the index of the block of source ports allocated to them,
+	// when the SNAT port range is partitioned. The Pods which are not allocated a block share the whole port range.
+	portBlocks map[string]int
+	// localPodIPs are the IPs of the local Pods whose traffic is forwarded to the Egress Node. The value is the IP of
+	// the Egress Node the flows are installed with.
+	localPodIPs map[string]string
+}
+
+// podWatch caches the Pods matching a label selector in a Namespace, or in all Namespaces if the Namespace is empty.
+type podWatch struct {
+	lister corelisters.PodLister
+	synced cache.InformerSynced
+	stopCh chan struct{}
+}
+
+// EgressController watches the Egresses and SNATs the traffic from the selected Pods to the external network with
+// their Egress IPs. Each Egress IP is assigned to one Node, which is selected among the Ready Nodes, restricted to the
+// Nodes matching the nodeSelector of the ExternalIPPool of the Egress if it has one, by rendezvous hashing of the
+// Egress name, so that all agents agree on the Egress Node without coordination and the IP fails over to another Node
+// when the Egress Node is no longer Ready. The traffic of the selected Pods running on other Nodes is forwarded to the
+// Egress Node through the tunnel and SNAT'd there. The agent of the Egress Node reports the Egress Node, the number of
+// selected Pods and the number of SNAT'd connections in the status of the Egress.
+//
+// Only the local Pods are cached by all agents. The Egress Node additionally watches the Pods selected by the
+// Egresses it holds, on all Nodes, with informers filtered by the podSelectors of the Egresses, and by Namespace for
+// the default Egresses. These informers are stopped when the Egresses move to other Nodes.
+type EgressController struct {
+	ofClient    openflow.Client
+	routeClient route.Interface
+	ipAssigner  IPAssigner
+	nodeName    string
+	// k8sClient is used to watch the Pods selected by the Egresses held by this Node.
+	k8sClient kubernetes.Interface
+	// crdClient is used to update the status of the Egresses held by this Node.
+	crdClient clientset.Interface
+	// eventRecorder reports the failures to realize the Egresses as Events on the Egresses.
+	eventRecorder *events.Recorder
+
+	egressLister       corelistersv1alpha1.EgressLister
+	egressListerSynced cache.InformerSynced
+	// podLister only lists the Pods running on this Node.
+	podLister             corelisters.PodLister
+	podListerSynced       cache.InformerSynced
+	namespaceLister       corelisters.NamespaceLister
+	namespaceListerSynced cache.InformerSynced
+	nodeLister            corelisters.NodeLister
+	nodeListerSynced      cache.InformerSynced
+	// externalIPPoolLister is used to get the nodeSelectors of the ExternalIPPools referenced by the Egresses.
+	externalIPPoolLister       corelistersv1alpha1.ExternalIPPoolLister
+	externalIPPoolListerSynced cache.InformerSynced
+	queue                      workqueue.RateLimitingInterface
+
+	// egressStates is a map of Egress name to the realized egressState. It's only accessed by the worker.
+	egressStates map[string]*egressState
+	// podEgresses is a map of Pod IP to the name of the Egress the datapath of the Pod is realized for. It's only
+	// accessed by the worker.
+	podEgresses map[string]string
+	// podWatches is a map of Egress name to the podWatches of the Pods selected by the Egress, keyed by Namespace and
+	// label selector, for the Egresses held by this Node. It's only accessed by the worker.
+	podWatches map[string]map[string]*podWatch
+
+	// countSNATConnections returns the number of connections SNAT'd with each of the provided Egress IPs on this
+	// Node. It's nil on the platforms where the connections cannot be counted.
+	countSNATConnections func(egressIPs sets.String) (map[string]int, error)
+	// snatConnections is a map of Egress IP to the number of connections SNAT'd with it, as counted at the last
+	// update. It's written by the goroutine counting the connections and read by the worker.
+	snatConnections      map[string]int
+	snatConnectionsMutex sync.RWMutex
+
+	// querier looks up the Egresses of the local Pods for the flow exporter.
+	querier *EgressQuerier
+
+	// snatPortsPerPod is the size of the blocks of source ports allocated to the Pods SNAT'd with an Egress IP held
+	// by this Node. 0 means that the Pods share the whole port range.
+	snatPortsPerPod int
+}
+
+// NewEgressController instantiates a new EgressController object. localPodInformer should only watch the Pods running
+// on this Node. If snatPortsPerPod is not 0, the source port range of each Egress IP is partitioned into blocks of
+// snatPortsPerPod ports, and each Pod SNAT'd with the Egress IP is allocated its own block, so that a Pod opening
+// many connections to the same destination cannot exhaust the ports of the other Pods.
+func NewEgressController(
+	ofClient openflow.Client,
+	routeClient route.Interface,
+	ipAssigner IPAssigner,
+	nodeName string,
+	k8sClient kubernetes.Interface,
+	crdClient clientset.Interface,
+	egressInformer coreinformersv1alpha1.EgressInformer,
+	localPodInformer coreinformers.PodInformer,
+	namespaceInformer coreinformers.NamespaceInformer,
+	nodeInformer coreinformers.NodeInformer,
+	externalIPPoolInformer coreinformersv1alpha1.ExternalIPPoolInformer,
+	eventRecorder *events.Recorder,
+	snatPortsPerPod int) *EgressController {
+	c := &EgressController{
+		ofClient:                   ofClient,
+		routeClient:                routeClient,
+		ipAssigner:                 ipAssigner,
+		nodeName:                   nodeName,
+		k8sClient:                  k8sClient,
+		crdClient:                  crdClient,
+		eventRecorder:              eventRecorder,
+		egressLister:               egressInformer.Lister(),
+		egressListerSynced:         egressInformer.Informer().HasSynced,
+		podLister:                  localPodInformer.Lister(),
+		podListerSynced:            localPodInformer.Informer().HasSynced,
+		namespaceLister:            namespaceInformer.Lister(),
+		namespaceListerSynced:      namespaceInformer.Informer().HasSynced,
+		nodeLister:                 nodeInformer.Lister(),
+		nodeListerSynced:           nodeInformer.Informer().HasSynced,
+		externalIPPoolLister:       externalIPPoolInformer.Lister(),
+		externalIPPoolListerSynced: externalIPPoolInformer.Informer().HasSynced,
+		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "egress"),
+		egressStates:               map[string]*egressState{},
+		podEgresses:                map[string]string{},
+		podWatches:                 map[string]map[string]*podWatch{},
+		countSNATConnections:       snatConnectionCounter,
+		snatPortsPerPod:            snatPortsPerPod,
+	}
+	c.querier = NewEgressQuerier(nodeName, c.egressLister, c.podLister, c.namespaceLister, c.nodeLister, c.externalIPPoolLister)
+	// A change of an Egress can change the Pods selected by the other Egresses, as a Pod is only SNAT'd by one
+	// Egress, so all Egresses are enqueued.
+	egressInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueAllEgresses() },
+			UpdateFunc: func(oldObj, curObj interface{}) { c.enqueueAllEgresses() },
+			DeleteFunc: c.deleteEgress,
+		},
+		resyncPeriod,
+	)
+	localPodInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.addPod,
+			UpdateFunc: c.updatePod,
+			DeleteFunc: c.deletePod,
+		},
+		resyncPeriod,
+	)
+	namespaceInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { c.enqueueAllEgresses() },
+			UpdateFunc: func(oldObj, curObj interface{}) {
+				oldNamespace, curNamespace := oldObj.(*corev1.Namespace), curObj.(*corev1.Namespace)
+				if !labels.Equals(oldNamespace.Labels, curNamespace.Labels) ||
+					oldNamespace.Annotations[defaultEgressAnnotation] != curNamespace.Annotations[defaultEgressAnnotation] {
+					c.enqueueAllEgresses()
+				}
+			},
+			DeleteFunc: func(obj interface{}) { c.enqueueAllEgresses() },
+		},
+		resyncPeriod,
+	)
+	nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueAllEgresses() },
+			UpdateFunc: c.updateNode,
+			DeleteFunc: func(obj interface{}) { c.enqueueAllEgresses() },
+		},
+		resyncPeriod,
+	)
+	// The Egress Nodes are selected among the Nodes matching the nodeSelectors of the ExternalIPPools.
+	externalIPPoolInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueAllEgresses() },
+			UpdateFunc: func(oldObj, curObj interface{}) { c.enqueueAllEgresses() },
+			DeleteFunc: func(obj interface{}) { c.enqueueAllEgresses() },
+		},
+		resyncPeriod,
+	)
+	return c
+}
+
+// GetEgress returns the Egress applied to the local Pod, the IP its connections are SNAT'd to and the Egress Node. It
+// implements the EgressQuerier interface of the flow exporter.
+func (c *EgressController) GetEgress(podNamespace, podName string) (string, net.IP, string, bool) {
+	return c.querier.GetEgress(podNamespace, podName)
+}
+
+func (c *EgressController) enqueueAllEgresses() {
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Egresses: %v", err)
+		return
+	}
+	for _, egress := range egresses {
+		c.queue.Add(egress.Name)
+	}
+}
+
+func (c *EgressController) deleteEgress(obj interface{}) {
+	egress, ok := obj.(*corev1alpha1.Egress)
+	if !ok {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Received unexpected object: %v", obj)
+			return
+		}
+		egress, ok = deletedState.Obj.(*corev1alpha1.Egress)
+		if !ok {
+			klog.Errorf("DeletedFinalStateUnknown contains non-Egress object: %v", deletedState.Obj)
+			return
+		}
+	}
+	// The deleted Egress is no longer listed, enqueue it explicitly to clean up its datapath.
+	c.queue.Add(egress.Name)
+	c.enqueueAllEgresses()
+}
+
+// enqueueEgressesForPod enqueues the Egresses which select the Pod, and the default Egress of its Namespace.
+func (c *EgressController) enqueueEgressesForPod(pod *corev1.Pod) {
+	namespace, err := c.namespaceLister.Get(pod.Namespace)
+	if err != nil {
+		// The Namespace is being deleted, its Pods will be handled when the Namespace is deleted.
+		return
+	}
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Egresses: %v", err)
+		return
+	}
+	for _, egress := range egresses {
+		if selected, _ := selectsPod(egress, pod, namespace); selected {
+			c.queue.Add(egress.Name)
+		}
+	}
+	if defaultEgress := namespace.Annotations[defaultEgressAnnotation]; defaultEgress != "" {
+		c.queue.Add(defaultEgress)
+	}
+}
+
+func (c *EgressController) addPod(obj interface{}) {
+	c.enqueueEgressesForPod(obj.(*corev1.Pod))
+}
+
+func (c *EgressController) updatePod(oldObj, curObj interface{}) {
+	oldPod := oldObj.(*corev1.Pod)
+	curPod := curObj.(*corev1.Pod)
+	if !isPodChanged(oldPod, curPod) {
+		return
+	}
+	c.enqueueEgressesForPod(oldPod)
+	c.enqueueEgressesForPod(curPod)
+}
+
+// isPodChanged returns whether the Pod changed in a way which can affect the Egresses.
+func isPodChanged(oldPod, curPod *corev1.Pod) bool {
+	return !labels.Equals(oldPod.Labels, curPod.Labels) || oldPod.Status.PodIP != curPod.Status.PodIP ||
+		oldPod.Spec.NodeName != curPod.Spec.NodeName || isPodTerminated(oldPod) != isPodTerminated(curPod)
+}
+
+func (c *EgressController) deletePod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Received unexpected object: %v", obj)
+			return
+		}
+		pod, ok = deletedState.Obj.(*corev1.Pod)
+		if !ok {
+			klog.Errorf("DeletedFinalStateUnknown contains non-Pod object: %v", deletedState.Obj)
+			return
+		}
+	}
+	c.enqueueEgressesForPod(pod)
+}
+
+func (c *EgressController) updateNode(oldObj, curObj interface{}) {
+	oldNode := oldObj.(*corev1.Node)
+	curNode := curObj.(*corev1.Node)
+	oldIP, _ := noderoute.GetNodeAddr(oldNode)
+	curIP, _ := noderoute.GetNodeAddr(curNode)
+	if isNodeEligible(oldNode) == isNodeEligible(curNode) && oldIP.Equal(curIP) && labels.Equals(oldNode.Labels, curNode.Labels) {
+		return
+	}
+	c.enqueueAllEgresses()
+}
+
+// Run will create defaultWorkers workers (go routines) which will process the Egress events from the workqueue.
+func (c *EgressController) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s", controllerName)
+	defer klog.Infof("Shutting down %s", controllerName)
+
+	klog.Infof("Waiting for caches to sync for %s", controllerName)
+	if !cache.WaitForCacheSync(stopCh, c.egressListerSynced, c.podListerSynced, c.namespaceListerSynced, c.nodeListerSynced, c.externalIPPoolListerSynced) {
+		klog.Errorf("Unable to sync caches for %s", controllerName)
+		return
+	}
+	klog.Infof("Caches are synced for %s", controllerName)
+
+	if err := c.installEgressTunnelFlows(); err != nil {
+		klog.Errorf("Failed to install the Egress tunnel flows: %v", err)
+		return
+	}
+
+	for i := 0; i < defaultWorkers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+	if c.countSNATConnections != nil {
+		go wait.Until(c.updateSNATConnections, snatConnectionsUpdateInterval, stopCh)
+	}
+	<-stopCh
+}
+
+// updateSNATConnections counts the connections SNAT'd with the Egress IPs, and enqueues the Egresses whose count
+// changed, so that their status and metrics are updated by the worker.
+func (c *EgressController) updateSNATConnections() {
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Egresses: %v", err)
+		return
+	}
+	egressIPs := sets.NewString()
+	for _, egress := range egresses {
+		if egress.Spec.EgressIP != "" {
+			egressIPs.Insert(egress.Spec.EgressIP)
+		}
+	}
+	counts := map[string]int{}
+	if egressIPs.Len() > 0 {
+		counts, err = c.countSNATConnections(egressIPs)
+		if err != nil {
+			klog.Errorf("Failed to count the connections SNAT'd with the Egress IPs: %v", err)
+			return
+		}
+	}
+	c.snatConnectionsMutex.Lock()
+	oldCounts := c.snatConnections
+	c.snatConnections = counts
+	c.snatConnectionsMutex.Unlock()
+	for _, egress := range egresses {
+		if counts[egress.Spec.EgressIP] != oldCounts[egress.Spec.EgressIP] {
+			c.queue.Add(egress.Name)
+		}
+	}
+}
+
+// worker is a long-running function that will continually call the processNextWorkItem function in order to read
+// and process a message on the workqueue.
+func (c *EgressController) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *EgressController) processNextWorkItem() bool {
+	obj, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(obj)
+
+	// We expect strings (Egress name) to come off the workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd go into a loop of
+		// attempting to process a work item that is invalid.
+		c.queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := c.syncEgress(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again.
+		c.queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		c.queue.AddRateLimited(key)
+		klog.Errorf("Error syncing Egress %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
+
+func (c *EgressController) syncEgress(egressName string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing Egress for %s. (%v)", egressName, time.Since(startTime))
+	}()
+
+	egress, err := c.egressLister.Get(egressName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			metrics.EgressFailoverCount.Delete(map[string]string{"egress": egressName})
+			return c.uninstallEgress(egressName)
+		}
+		return err
+	}
+
+	if egress.Spec.EgressIP == "" {
+		klog.V(2).Infof("Egress %s has no IP yet, waiting for the allocation from ExternalIPPool %s", egressName, egress.Spec.ExternalIPPool)
+		return c.uninstallEgress(egressName)
+	}
+	egressIP := net.ParseIP(egress.Spec.EgressIP)
+	if egressIP == nil || egressIP.To4() == nil {
+		klog.Errorf("Egress %s has invalid Egress IP %s", egressName, egress.Spec.EgressIP)
+		return c.uninstallEgress(egressName)
+	}
+
+	state, exists := c.egressStates[egressName]
+	if exists && state.egressIP != egress.Spec.EgressIP {
+		if err := c.uninstallEgress(egressName); err != nil {
+			return err
+		}
+		exists = false
+	}
+	if !exists {
+		state = &egressState{egressIP: egress.Spec.EgressIP, snatPodIPs: sets.NewString(), portBlocks: map[string]int{}, localPodIPs: map[string]string{}}
+		c.egressStates[egressName] = state
+	}
+
+	egressNode, egressNodeIP, err := c.selectEgressNode(egress)
+	if err != nil {
+		return err
+	}
+	if egressNode == c.nodeName {
+		if err := c.ipAssigner.AssignIP(egress.Spec.EgressIP); err != nil {
+			c.eventRecorder.EgressFailure(egress, events.ReasonEgressRealizationFailed, "Failed to assign the Egress IP", err)
+			return err
+		}
+	} else if !exists || state.egressNode == c.nodeName {
+		// The IP is also unassigned when the Egress is synced for the first time, in case it was assigned to this
+		// Node before the agent restarted.
+		if err := c.ipAssigner.UnassignIP(egress.Spec.EgressIP); err != nil {
+			return err
+		}
+		deleteEgressMetrics(egressName)
+	}
+	state.egressNode = egressNode
+
+	// The Egress Node SNATs the traffic of the selected Pods of all Nodes, while the other Nodes only forward the
+	// traffic of their local Pods.
+	podListers := []corelisters.PodLister{c.podLister}
+	if egressNode == c.nodeName {
+		if podListers, err = c.watchSelectedPods(egress); err != nil {
+			return err
+		}
+	} else {
+		c.stopPodWatches(egressName)
+	}
+	pods, err := c.getSelectedPods(egress, podListers)
+	if err != nil {
+		return err
+	}
+	desiredSNATPodIPs := sets.NewString()
+	desiredLocalPodIPs := sets.NewString()
+	for _, pod := range pods {
+		if egressNode == c.nodeName {
+			desiredSNATPodIPs.Insert(pod.Status.PodIP)
+		} else if egressNode != "" && pod.Spec.NodeName == c.nodeName {
+			desiredLocalPodIPs.Insert(pod.Status.PodIP)
+		}
+	}
+
+	// Release the Pods which are no longer selected, or whose datapath changes.
+	for podIP := range state.snatPodIPs {
+		if !desiredSNATPodIPs.Has(podIP) {
+			if err := c.releasePod(podIP, egressName); err != nil {
+				return err
+			}
+		}
+	}
+	for podIP, installedNodeIP := range state.localPodIPs {
+		if !desiredLocalPodIPs.Has(podIP) {
+			if err := c.releasePod(podIP, egressName); err != nil {
+				return err
+			}
+		} else if installedNodeIP != egressNodeIP.String() {
+			// The flows are overridden with the new Egress Node IP below.
+			delete(state.localPodIPs, podIP)
+		}
+	}
+
+	// The Pod IPs are processed in order, so that the port blocks are allocated deterministically.
+	for _, podIP := range desiredSNATPodIPs.List() {
+		_, hasPortBlock := state.portBlocks[podIP]
+		if state.snatPodIPs.Has(podIP) && (hasPortBlock || !c.hasFreePortBlock(state)) {
+			continue
+		}
+		if err := c.claimPod(podIP, egressName); err != nil {
+			return err
+		}
+		// The rule of a Pod which shares the whole port range is overridden once a block is released by another Pod.
+		portRange := c.allocatePortBlock(state, podIP)
+		if portRange == nil && c.snatPortsPerPod != 0 && !state.snatPodIPs.Has(podIP) {
+			klog.Warningf("All the SNAT port blocks of Egress IP %s are allocated, Pod IP %s shares the whole port range", egressIP, podIP)
+		}
+		if err := c.addSNATRule(net.ParseIP(podIP), egressIP, portRange); err != nil {
+			delete(state.portBlocks, podIP)
+			c.eventRecorder.EgressFailure(egress, events.ReasonEgressRealizationFailed, fmt.Sprintf("Failed to install the SNAT rule of Pod IP %s", podIP), err)
+			return err
+		}
+		state.snatPodIPs.Insert(podIP)
+	}
+	for podIP := range desiredLocalPodIPs {
+		if _, exists := state.localPodIPs[podIP]; exists {
+			continue
+		}
+		if err := c.claimPod(podIP, egressName); err != nil {
+			return err
+		}
+		if err := c.ofClient.InstallPodSNATFlows(net.ParseIP(podIP), egressNodeIP); err != nil {
+			c.eventRecorder.EgressFailure(egress, events.ReasonEgressRealizationFailed, fmt.Sprintf("Failed to install the SNAT flows of Pod IP %s", podIP), err)
+			return err
+		}
+		state.localPodIPs[podIP] = egressNodeIP.String()
+	}
+	if egressNode == c.nodeName {
+		if c.snatPortsPerPod != 0 {
+			metrics.EgressSNATPortBlockCount.WithLabelValues(egressName).Set(float64(len(state.portBlocks)))
+			metrics.EgressSNATPortBlockExhaustedPodCount.WithLabelValues(egressName).Set(float64(state.snatPodIPs.Len() - len(state.portBlocks)))
+		}
+		return c.updateEgressStatus(egress, len(pods))
+	}
+	return nil
+}
+
+// hasFreePortBlock returns whether a block of source ports of the Egress IP can be allocated to a Pod.
+func (c *EgressController) hasFreePortBlock(state *egressState) bool {
+	return c.snatPortsPerPod != 0 && len(state.portBlocks) < (snatPortRangeEnd-snatPortRangeStart+1)/c.snatPortsPerPod
+}
+
+// allocatePortBlock allocates the free block of source ports of the Egress IP with the lowest index to the Pod IP,
+// and returns its port range. It returns nil if the SNAT port range is not partitioned or if all the blocks are
+// allocated, in which case the Pod shares the whole port range: conntrack still picks a unique source port for each
+// connection, but the Pod may compete for the ports with the other Pods.
+func (c *EgressController) allocatePortBlock(state *egressState, podIP string) *binding.PortRange {
+	if !c.hasFreePortBlock(state) {
+		return nil
+	}
+	allocated := make(map[int]bool, len(state.portBlocks))
+	for _, index := range state.portBlocks {
+		allocated[index] = true
+	}
+	index := 0
+	for allocated[index] {
+		index++
+	}
+	state.portBlocks[podIP] = index
+	startPort := snatPortRangeStart + index*c.snatPortsPerPod
+	return &binding.PortRange{StartPort: uint16(startPort), EndPort: uint16(startPort + c.snatPortsPerPod - 1)}
+}
+
+// updateEgressStatus updates the status and the metrics of the Egress held by this Node. If the Egress IP was
+// previously held by another Node, a Normal Event is emitted for the Egress once the status is updated, so that the
+// failover is visible with "kubectl describe".
+func (c *EgressController) updateEgressStatus(egress *corev1alpha1.Egress, selectedPods int) error {
+	status := corev1alpha1.EgressStatus{EgressNode: c.nodeName, SelectedPods: int32(selectedPods)}
+	metrics.EgressSelectedPodCount.WithLabelValues(egress.Name).Set(float64(selectedPods))
+	if c.countSNATConnections != nil {
+		c.snatConnectionsMutex.RLock()
+		snatConnections := int32(c.snatConnections[egress.Spec.EgressIP])
+		c.snatConnectionsMutex.RUnlock()
+		status.SNATConnections = &snatConnections
+		metrics.EgressSNATConnectionCount.WithLabelValues(egress.Name).Set(float64(snatConnections))
+	}
+	if reflect.DeepEqual(egress.Status, status) {
+		return nil
+	}
+	toUpdate := egress.DeepCopy()
+	toUpdate.Status = status
+	if _, err := c.crdClient.CoreV1alpha1().Egresses().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating the status of Egress %s: %v", egress.Name, err)
+	}
+	if oldNode := egress.Status.EgressNode; oldNode != "" && oldNode != c.nodeName {
+		klog.Infof("Egress IP %s of Egress %s moved from Node %s to this Node", egress.Spec.EgressIP, egress.Name, oldNode)
+		c.eventRecorder.EgressNodeChanged(egress, oldNode, c.nodeName)
+		metrics.EgressFailoverCount.WithLabelValues(egress.Name).Inc()
+	}
+	return nil
+}
+
+// deleteEgressMetrics deletes the metrics of an Egress which is no longer held by this Node. Delete is used instead
+// of DeleteLabelValues as the metrics are not created when the Prometheus metrics are disabled.
+func deleteEgressMetrics(egressName string) {
+	metricLabels := map[string]string{"egress": egressName}
+	metrics.EgressSelectedPodCount.Delete(metricLabels)
+	metrics.EgressSNATConnectionCount.Delete(metricLabels)
+	metrics.EgressSNATPortBlockCount.Delete(metricLabels)
+	metrics.EgressSNATPortBlockExhaustedPodCount.Delete(metricLabels)
+}
+
+// uninstallEgress removes the datapath realized for the Egress, and revokes its IP if it's assigned to this Node.
+func (c *EgressController) uninstallEgress(egressName string) error {
+	state, exists := c.egressStates[egressName]
+	if !exists {
+		return nil
+	}
+	for podIP := range state.snatPodIPs {
+		if err := c.releasePod(podIP, egressName); err != nil {
+			return err
+		}
+	}
+	for podIP := range state.localPodIPs {
+		if err := c.releasePod(podIP, egressName); err != nil {
+			return err
+		}
+	}
+	if state.egressNode == c.nodeName {
+		if err := c.ipAssigner.UnassignIP(state.egressIP); err != nil {
+			return err
+		}
+		deleteEgressMetrics(egressName)
+	}
+	c.stopPodWatches(egressName)
+	delete(c.egressStates, egressName)
+	return nil
+}
+
+// watchSelectedPods makes sure the Pods which can be selected by the Egress held by this Node are watched on all
+// Nodes, and returns the listers of these Pods once they are synced. The Pods are filtered by the podSelector of the
+// Egress, in the Namespaces matching its namespaceSelector if it has one, and by Namespace for the Namespaces whose
+// default Egress is the Egress. The podWatches which are no longer needed, e.g. after the podSelector is updated, are
+// stopped.
+func (c *EgressController) watchSelectedPods(egress *corev1alpha1.Egress) ([]corelisters.PodLister, error) {
+	podSelector := labels.Everything()
+	if selector := egress.Spec.AppliedTo.PodSelector; selector != nil {
+		var err error
+		if podSelector, err = metav1.LabelSelectorAsSelector(selector); err != nil {
+			return nil, fmt.Errorf("invalid podSelector of Egress %s: %v", egress.Name, err)
+		}
+	}
+	var nsSelector labels.Selector
+	if selector := egress.Spec.AppliedTo.NamespaceSelector; selector != nil {
+		var err error
+		if nsSelector, err = metav1.LabelSelectorAsSelector(selector); err != nil {
+			return nil, fmt.Errorf("invalid namespaceSelector of Egress %s: %v", egress.Name, err)
+		}
+	}
+	namespaces, err := c.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	// The key of a podWatch is its Namespace and its label selector, separated by a slash.
+	desiredWatches := map[string]bool{}
+	if nsSelector == nil {
+		desiredWatches["/"+podSelector.String()] = true
+	}
+	for _, namespace := range namespaces {
+		if nsSelector != nil && nsSelector.Matches(labels.Set(namespace.Labels)) {
+			desiredWatches[namespace.Name+"/"+podSelector.String()] = true
+		}
+		// All the Pods of the Namespace are already watched if the Egress selects all Pods.
+		if namespace.Annotations[defaultEgressAnnotation] == egress.Name && !(nsSelector == nil && podSelector.Empty()) {
+			desiredWatches[namespace.Name+"/"] = true
+		}
+	}
+
+	watches, exists := c.podWatches[egress.Name]
+	if !exists {
+		watches = map[string]*podWatch{}
+		c.podWatches[egress.Name] = watches
+	}
+	for key, watch := range watches {
+		if !desiredWatches[key] {
+			close(watch.stopCh)
+			delete(watches, key)
+		}
+	}
+	var podListers []corelisters.PodLister
+	var podListersSynced []cache.InformerSynced
+	for key := range desiredWatches {
+		watch, exists := watches[key]
+		if !exists {
+			parts := strings.SplitN(key, "/", 2)
+			watch = c.startPodWatch(egress.Name, parts[0], parts[1])
+			watches[key] = watch
+		}
+		podListers = append(podListers, watch.lister)
+		podListersSynced = append(podListersSynced, watch.synced)
+	}
+	if err := wait.PollImmediate(100*time.Millisecond, podWatchSyncTimeout, func() (bool, error) {
+		for _, synced := range podListersSynced {
+			if !synced() {
+				return false, nil
+			}
+		}
+		return true, nil
+	}); err != nil {
+		return nil, fmt.Errorf("timed out waiting for the Pods selected by Egress %s to be synced", egress.Name)
+	}
+	return podListers, nil
+}
+
+// startPodWatch starts watching the Pods matching the label selector in the Namespace, or in all Namespaces if the
+// Namespace is empty. The Egress is enqueued when the watched Pods change.
+func (c *EgressController) startPodWatch(egressName, namespace, selector string) *podWatch {
+	informer := coreinformers.NewFilteredPodInformer(c.k8sClient, namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, func(options *metav1.ListOptions) {
+		options.LabelSelector = selector
+	})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { c.queue.Add(egressName) },
+		UpdateFunc: func(oldObj, curObj interface{}) {
+			if isPodChanged(oldObj.(*corev1.Pod), curObj.(*corev1.Pod)) {
+				c.queue.Add(egressName)
+			}
+		},
+		DeleteFunc: func(obj interface{}) { c.queue.Add(egressName) },
+	})
+	watch := &podWatch{
+		lister: corelisters.NewPodLister(informer.GetIndexer()),
+		synced: informer.HasSynced,
+		stopCh: make(chan struct{}),
+	}
+	go informer.Run(watch.stopCh)
+	klog.V(2).Infof("Started watching the Pods in Namespace %q matching %q for Egress %s", namespace, selector, egressName)
+	return watch
+}
+
+// stopPodWatches stops the podWatches of the Egress, when it's no longer held by this Node.
+func (c *EgressController) stopPodWatches(egressName string) {
+	for _, watch := range c.podWatches[egressName] {
+		close(watch.stopCh)
+	}
+	delete(c.podWatches, egressName)
+}
+
+// claimPod releases the Pod IP from the Egress it's realized for, if it's not the provided Egress, and records the
+// Pod IP as realized for the provided Egress.
+func (c *EgressController) claimPod(podIP string, egressName string) error {
+	if owner, exists := c.podEgresses[podIP]; exists && owner != egressName {
+		if err := c.releasePod(podIP, owner); err != nil {
+			return err
+		}
+	}
+	c.podEgresses[podIP] = egressName
+	return nil
+}
+
+// releasePod removes the datapath realized for the Pod IP by the Egress.
+func (c *EgressController) releasePod(podIP string, egressName string) error {
+	state, exists := c.egressStates[egressName]
+	if !exists {
+		return nil
+	}
+	if state.snatPodIPs.Has(podIP) {
+		if err := c.deleteSNATRule(net.ParseIP(podIP)); err != nil {
+			return err
+		}
+		state.snatPodIPs.Delete(podIP)
+		delete(state.portBlocks, podIP)
+	}
+	if _, exists := state.localPodIPs[podIP]; exists {
+		if err := c.ofClient.UninstallPodSNATFlows(net.ParseIP(podIP)); err != nil {
+			return err
+		}
+		delete(state.localPodIPs, podIP)
+	}
+	if c.podEgresses[podIP] == egressName {
+		delete(c.podEgresses, podIP)
+	}
+	return nil
+}
+
+// selectEgressNode returns the name and the IP of the Node the Egress IP should be assigned to, by rendezvous
+// hashing of the Egress name among the eligible Nodes: when the Egress Node is no longer eligible, only its Egress
+// IPs are moved to other Nodes, and when a Node becomes eligible, it only takes over the Egress IPs for which it
+// has the highest score. If the Egress references an ExternalIPPool, only the Nodes selected by the pool are
+// eligible. An empty name is returned if no Node is eligible.
+func (c *EgressController) selectEgressNode(egress *corev1alpha1.Egress) (string, net.IP, error) {
+	return selectEgressNode(egress, c.nodeLister, c.externalIPPoolLister)
+}
+
+func selectEgressNode(egress *corev1alpha1.Egress, nodeLister corelisters.NodeLister, externalIPPoolLister corelistersv1alpha1.ExternalIPPoolLister) (string, net.IP, error) {
+	nodeSelector := labels.Everything()
+	if egress.Spec.ExternalIPPool != "" {
+		pool, err := externalIPPoolLister.Get(egress.Spec.ExternalIPPool)
+		if err == nil {
+			nodeSelector, err = metav1.LabelSelectorAsSelector(&pool.Spec.NodeSelector)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid nodeSelector of ExternalIPPool %s: %v", pool.Name, err)
+			}
+		} else if !errors.IsNotFound(err) {
+			return "", nil, err
+		}
+	}
+	nodes, err := nodeLister.List(nodeSelector)
+	if err != nil {
+		return "", nil, err
+	}
+	egressName := egress.Name
+	var selectedNode string
+	var selectedNodeIP net.IP
+	var maxScore uint64
+	for _, node := range nodes {
+		if !isNodeEligible(node) {
+			continue
+		}
+		nodeIP, err := noderoute.GetNodeAddr(node)
+		if err != nil {
+			continue
+		}
+		score := rendezvousScore(egressName, node.Name)
+		if selectedNode == "" || score > maxScore || (score == maxScore && node.Name < selectedNode) {
+			selectedNode, selectedNodeIP, maxScore = node.Name, nodeIP, score
+		}
+	}
+	return selectedNode, selectedNodeIP, nil
+}
+
+func rendezvousScore(egressName, nodeName string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(egressName))
+	h.Write([]byte{0})
+	h.Write([]byte(nodeName))
+	return h.Sum64()
+}
+
+// isNodeEligible returns whether the Node can own Egress IPs, i.e. whether it's Ready and not being deleted.
+func isNodeEligible(node *corev1.Node) bool {
+	if node.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getSelectedPods returns the running Pods listed by the podListers which are selected by the Egress, excluding the
+// ones also selected by an Egress whose name is smaller: a Pod is only SNAT'd with the IP of the first Egress
+// selecting it. If the Egress is the default Egress of a Namespace, the Pods of the Namespace which are not selected
+// by any other Egress are also returned, so that the Egresses selecting Pods explicitly take precedence over the
+// default Egress. A Pod listed by several podListers is only returned once.
+func (c *EgressController) getSelectedPods(egress *corev1alpha1.Egress, podListers []corelisters.PodLister) ([]*corev1.Pod, error) {
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var precedingEgresses, otherEgresses []*corev1alpha1.Egress
+	for _, e := range egresses {
+		if e.Name < egress.Name {
+			precedingEgresses = append(precedingEgresses, e)
+		}
+		if e.Name != egress.Name {
+			otherEgresses = append(otherEgresses, e)
+		}
+	}
+	namespaces, err := c.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var pods []*corev1.Pod
+	for _, namespace := range namespaces {
+		isDefaultEgress := namespace.Annotations[defaultEgressAnnotation] == egress.Name
+		var nsPods []*corev1.Pod
+		for _, podLister := range podListers {
+			listedPods, err := podLister.Pods(namespace.Name).List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			nsPods = append(nsPods, listedPods...)
+		}
+		podNames := sets.NewString()
+		for _, pod := range nsPods {
+			if pod.Spec.HostNetwork || pod.Status.PodIP == "" || isPodTerminated(pod) || podNames.Has(pod.Name) {
+				continue
+			}
+			podNames.Insert(pod.Name)
+			selected, err := selectsPod(egress, pod, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if selected && !selectedByAny(precedingEgresses, pod, namespace) {
+				pods = append(pods, pod)
+			} else if !selected && isDefaultEgress && !selectedByAny(otherEgresses, pod, namespace) {
+				pods = append(pods, pod)
+			}
+		}
+	}
+	return pods, nil
+}
+
+func selectedByAny(egresses []*corev1alpha1.Egress, pod *corev1.Pod, namespace *corev1.Namespace) bool {
+	for _, egress := range egresses {
+		if selected, _ := selectsPod(egress, pod, namespace); selected {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsPod returns whether the Egress selects the Pod of the Namespace. A nil selector selects everything.
+func selectsPod(egress *corev1alpha1.Egress, pod *corev1.Pod, namespace *corev1.Namespace) (bool, error) {
+	if selector := egress.Spec.AppliedTo.NamespaceSelector; selector != nil {
+		nsSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid namespaceSelector of Egress %s: %v", egress.Name, err)
+		}
+		if !nsSelector.Matches(labels.Set(namespace.Labels)) {
+			return false, nil
+		}
+	}
+	if selector := egress.Spec.AppliedTo.PodSelector; selector != nil {
+		podSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid podSelector of Egress %s: %v", egress.Name, err)
+		}
+		if !podSelector.Matches(labels.Set(pod.Labels)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ti-mo/conntrack"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+// installEgressTunnelFlows installs the flows forwarding the packets received from the tunnel and destined to external
+// addresses to the gateway, so that the host network SNATs them.
+func (c *EgressController) installEgressTunnelFlows() error {
+	return c.ofClient.InstallEgressTunnelFlows()
+}
+
+// addSNATRule makes the host network SNAT the packets from the Pod IP to external addresses with the SNAT IP, and
+// within the source port range if it's not nil.
+func (c *EgressController) addSNATRule(podIP, snatIP net.IP, portRange *binding.PortRange) error {
+	return c.routeClient.AddSNATRule(podIP, snatIP, portRange)
+}
+
+// deleteSNATRule deletes the SNAT rule added by addSNATRule for the Pod IP.
+func (c *EgressController) deleteSNATRule(podIP net.IP) error {
+	return c.routeClient.DeleteSNATRule(podIP)
+}
+
+// snatConnectionCounter counts the connections SNAT'd with the Egress IPs in the conntrack table of the host.
+var snatConnectionCounter = countSNATConnections
+
+func countSNATConnections(egressIPs sets.String) (map[string]int, error) {
+	conn, err := conntrack.Dial(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting netlink socket: %v", err)
+	}
+	defer conn.Close()
+	flows, err := conn.Dump()
+	if err != nil {
+		return nil, fmt.Errorf("error when dumping conntrack: %v", err)
+	}
+	counts := map[string]int{}
+	for _, flow := range flows {
+		// The replies of a connection SNAT'd with an Egress IP are destined to the Egress IP, while the connection
+		// was originated from another IP.
+		replyDst := flow.TupleReply.IP.DestinationAddress
+		if flow.TupleOrig.IP.SourceAddress.Equal(replyDst) {
+			continue
+		}
+		if ip := replyDst.String(); egressIPs.Has(ip) {
+			counts[ip]++
+		}
+	}
+	return counts, nil
+}
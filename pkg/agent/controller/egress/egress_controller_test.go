@@ -0,0 +1,534 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	routetest "github.com/vmware-tanzu/antrea/pkg/agent/route/testing"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+const localNodeName = "node1"
+
+type fakeIPAssigner struct {
+	assignedIPs sets.String
+}
+
+func (a *fakeIPAssigner) AssignIP(ip string) error {
+	a.assignedIPs.Insert(ip)
+	return nil
+}
+
+func (a *fakeIPAssigner) UnassignIP(ip string) error {
+	a.assignedIPs.Delete(ip)
+	return nil
+}
+
+type fakeController struct {
+	*EgressController
+	k8sClient       *fake.Clientset
+	crdClient       *fakeversioned.Clientset
+	mockOFClient    *openflowtest.MockClient
+	mockRouteClient *routetest.MockInterface
+	ipAssigner      *fakeIPAssigner
+}
+
+func newFakeController(t *testing.T, k8sObjects []runtime.Object, egresses []runtime.Object) (*fakeController, func()) {
+	controller := gomock.NewController(t)
+	k8sClient := fake.NewSimpleClientset(k8sObjects...)
+	crdClient := fakeversioned.NewSimpleClientset(egresses...)
+	informerFactory := informers.NewSharedInformerFactory(k8sClient, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockRouteClient := routetest.NewMockInterface(controller)
+	ipAssigner := &fakeIPAssigner{assignedIPs: sets.NewString()}
+	c := NewEgressController(mockOFClient, mockRouteClient, ipAssigner, localNodeName, k8sClient, crdClient,
+		crdInformerFactory.Core().V1alpha1().Egresses(),
+		informerFactory.Core().V1().Pods(),
+		informerFactory.Core().V1().Namespaces(),
+		informerFactory.Core().V1().Nodes(),
+		crdInformerFactory.Core().V1alpha1().ExternalIPPools(),
+		nil,
+		0)
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+	crdInformerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	crdInformerFactory.WaitForCacheSync(stopCh)
+	return &fakeController{
+		EgressController: c,
+		k8sClient:        k8sClient,
+		crdClient:        crdClient,
+		mockOFClient:     mockOFClient,
+		mockRouteClient:  mockRouteClient,
+		ipAssigner:       ipAssigner,
+	}, func() {
+		close(stopCh)
+		controller.Finish()
+	}
+}
+
+func newNode(name, ip string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionUnknown
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newPod(namespace, name, nodeName, ip string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+	}
+}
+
+func newEgress(name, egressIP string, podLabels map[string]string) *corev1alpha1.Egress {
+	return &corev1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1alpha1.EgressSpec{
+			AppliedTo: corev1alpha1.AppliedTo{PodSelector: &metav1.LabelSelector{MatchLabels: podLabels}},
+			EgressIP:  egressIP,
+		},
+	}
+}
+
+// egressNodeFor returns one of the provided Node names which is selected and one which is not selected for the
+// Egress name, so that the tests don't depend on the hash values.
+func egressNodeFor(egressName string, nodeNames ...string) (string, string) {
+	selected, other := nodeNames[0], nodeNames[1]
+	if rendezvousScore(egressName, other) > rendezvousScore(egressName, selected) {
+		selected, other = other, selected
+	}
+	return selected, other
+}
+
+var (
+	namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}
+	appLabels = map[string]string{"app": "web"}
+)
+
+func TestSyncEgressLocalEgressNode(t *testing.T) {
+	// Find an Egress name selecting the local Node between node1 and node2.
+	egressName := "egress-a"
+	for i := 0; ; i++ {
+		if selected, _ := egressNodeFor(egressName, localNodeName, "node2"); selected == localNodeName {
+			break
+		}
+		egressName = egressName + "a"
+	}
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newNode("node2", "192.168.1.2", true),
+		newPod("ns1", "local", localNodeName, "10.10.0.2", appLabels),
+		newPod("ns1", "remote", "node2", "10.10.1.2", appLabels),
+		newPod("ns1", "other", localNodeName, "10.10.0.3", nil),
+	}
+	c, cleanup := newFakeController(t, k8sObjects, []runtime.Object{newEgress(egressName, "192.168.1.100", appLabels)})
+	defer cleanup()
+
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.2"), net.ParseIP("192.168.1.100"), nil)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.1.2"), net.ParseIP("192.168.1.100"), nil)
+	require.NoError(t, c.syncEgress(egressName))
+	assert.True(t, c.ipAssigner.assignedIPs.Has("192.168.1.100"))
+
+	// The Egress is deleted: the SNAT rules are deleted and the IP is unassigned.
+	require.NoError(t, c.crdClient.CoreV1alpha1().Egresses().Delete(context.TODO(), egressName, metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		_, err := c.egressLister.Get(egressName)
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	c.mockRouteClient.EXPECT().DeleteSNATRule(net.ParseIP("10.10.0.2"))
+	c.mockRouteClient.EXPECT().DeleteSNATRule(net.ParseIP("10.10.1.2"))
+	require.NoError(t, c.syncEgress(egressName))
+	assert.False(t, c.ipAssigner.assignedIPs.Has("192.168.1.100"))
+	assert.Empty(t, c.egressStates)
+	assert.Empty(t, c.podEgresses)
+}
+
+func TestSyncEgressFailover(t *testing.T) {
+	egressName := "egress-a"
+	egressNode, otherNode := egressNodeFor(egressName, "node2", "node3")
+	nodeIPs := map[string]string{"node2": "192.168.1.2", "node3": "192.168.1.3"}
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", false),
+		newNode(egressNode, nodeIPs[egressNode], true),
+		newNode(otherNode, nodeIPs[otherNode], true),
+		newPod("ns1", "local", localNodeName, "10.10.0.2", appLabels),
+		newPod("ns1", "remote", egressNode, "10.10.1.2", appLabels),
+	}
+	c, cleanup := newFakeController(t, k8sObjects, []runtime.Object{newEgress(egressName, "192.168.1.100", appLabels)})
+	defer cleanup()
+
+	// Only the traffic of the local Pod is forwarded to the Egress Node.
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(net.ParseIP("10.10.0.2"), net.ParseIP(nodeIPs[egressNode]))
+	require.NoError(t, c.syncEgress(egressName))
+	assert.False(t, c.ipAssigner.assignedIPs.Has("192.168.1.100"))
+
+	// The Egress Node is no longer Ready, the Egress IP fails over to the other Node.
+	_, err := c.k8sClient.CoreV1().Nodes().Update(context.TODO(), newNode(egressNode, nodeIPs[egressNode], false), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		node, _ := c.nodeLister.Get(egressNode)
+		return !isNodeEligible(node)
+	}, time.Second, 10*time.Millisecond)
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(net.ParseIP("10.10.0.2"), net.ParseIP(nodeIPs[otherNode]))
+	require.NoError(t, c.syncEgress(egressName))
+	assert.Equal(t, otherNode, c.egressStates[egressName].egressNode)
+}
+
+func TestSyncEgressPodWatches(t *testing.T) {
+	egressName := "egress-a"
+	for i := 0; ; i++ {
+		if selected, _ := egressNodeFor(egressName, localNodeName, "node2"); selected == localNodeName {
+			break
+		}
+		egressName = egressName + "a"
+	}
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newNode("node2", "192.168.1.2", true),
+	}
+	c, cleanup := newFakeController(t, k8sObjects, []runtime.Object{newEgress(egressName, "192.168.1.100", appLabels)})
+	defer cleanup()
+
+	// The Egress Node watches the Pods matching the podSelector of the Egress on all Nodes.
+	require.NoError(t, c.syncEgress(egressName))
+	require.Contains(t, c.podWatches[egressName], "/app=web")
+	_, err := c.k8sClient.CoreV1().Pods("ns1").Create(context.TODO(), newPod("ns1", "remote", "node2", "10.10.1.2", appLabels), metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, err := c.podWatches[egressName]["/app=web"].lister.Pods("ns1").Get("remote")
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.1.2"), net.ParseIP("192.168.1.100"), nil)
+	require.NoError(t, c.syncEgress(egressName))
+
+	// The Egress moves to the other Node, the Pods are no longer watched.
+	_, err = c.k8sClient.CoreV1().Nodes().Update(context.TODO(), newNode(localNodeName, "192.168.1.1", false), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		node, _ := c.nodeLister.Get(localNodeName)
+		return !isNodeEligible(node)
+	}, time.Second, 10*time.Millisecond)
+	c.mockRouteClient.EXPECT().DeleteSNATRule(net.ParseIP("10.10.1.2"))
+	require.NoError(t, c.syncEgress(egressName))
+	assert.False(t, c.ipAssigner.assignedIPs.Has("192.168.1.100"))
+	assert.Empty(t, c.podWatches)
+}
+
+func TestSyncEgressFailureEvent(t *testing.T) {
+	egressName := "egress-a"
+	egressNode, otherNode := egressNodeFor(egressName, "node2", "node3")
+	nodeIPs := map[string]string{"node2": "192.168.1.2", "node3": "192.168.1.3"}
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", false),
+		newNode(egressNode, nodeIPs[egressNode], true),
+		newNode(otherNode, nodeIPs[otherNode], true),
+		newPod("ns1", "local", localNodeName, "10.10.0.2", appLabels),
+	}
+	c, cleanup := newFakeController(t, k8sObjects, []runtime.Object{newEgress(egressName, "192.168.1.100", appLabels)})
+	defer cleanup()
+	fakeRecorder := record.NewFakeRecorder(10)
+	c.eventRecorder = events.NewRecorder(c.k8sClient, fakeRecorder)
+
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(net.ParseIP("10.10.0.2"), net.ParseIP(nodeIPs[egressNode])).Return(fmt.Errorf("flow error"))
+	assert.Error(t, c.syncEgress(egressName))
+	select {
+	case event := <-fakeRecorder.Events:
+		assert.Equal(t, "Warning EgressRealizationFailed Failed to install the SNAT flows of Pod IP 10.10.0.2: flow error", event)
+	case <-time.After(time.Second):
+		t.Fatal("Expected one Event, got none")
+	}
+}
+
+func TestSyncEgressStatus(t *testing.T) {
+	egressName := "egress-a"
+	for i := 0; ; i++ {
+		if selected, _ := egressNodeFor(egressName, localNodeName, "node2"); selected == localNodeName {
+			break
+		}
+		egressName = egressName + "a"
+	}
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newNode("node2", "192.168.1.2", true),
+		newPod("ns1", "local", localNodeName, "10.10.0.2", appLabels),
+		newPod("ns1", "remote", "node2", "10.10.1.2", appLabels),
+	}
+	// The Egress IP was held by node2 before.
+	egress := newEgress(egressName, "192.168.1.100", appLabels)
+	egress.Status = corev1alpha1.EgressStatus{EgressNode: "node2", SelectedPods: 2}
+	c, cleanup := newFakeController(t, k8sObjects, []runtime.Object{egress})
+	defer cleanup()
+	fakeRecorder := record.NewFakeRecorder(10)
+	c.eventRecorder = events.NewRecorder(c.k8sClient, fakeRecorder)
+	c.countSNATConnections = func(egressIPs sets.String) (map[string]int, error) {
+		assert.Equal(t, sets.NewString("192.168.1.100"), egressIPs)
+		return map[string]int{"192.168.1.100": 3}, nil
+	}
+
+	// The Egress is enqueued when its number of SNAT'd connections changes.
+	c.updateSNATConnections()
+	assert.Equal(t, 1, c.queue.Len())
+	c.updateSNATConnections()
+	assert.Equal(t, 1, c.queue.Len())
+
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.2"), net.ParseIP("192.168.1.100"), nil)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.1.2"), net.ParseIP("192.168.1.100"), nil)
+	require.NoError(t, c.syncEgress(egressName))
+	updatedEgress, err := c.crdClient.CoreV1alpha1().Egresses().Get(context.TODO(), egressName, metav1.GetOptions{})
+	require.NoError(t, err)
+	snatConnections := int32(3)
+	assert.Equal(t, corev1alpha1.EgressStatus{EgressNode: localNodeName, SelectedPods: 2, SNATConnections: &snatConnections}, updatedEgress.Status)
+	select {
+	case event := <-fakeRecorder.Events:
+		assert.Equal(t, fmt.Sprintf("Normal EgressNodeChanged Egress IP 192.168.1.100 moved from Node node2 to Node %s", localNodeName), event)
+	case <-time.After(time.Second):
+		t.Fatal("Expected one Event, got none")
+	}
+}
+
+func TestSyncEgressSNATPortBlocks(t *testing.T) {
+	egressName := "egress-a"
+	for i := 0; ; i++ {
+		if selected, _ := egressNodeFor(egressName, localNodeName, "node2"); selected == localNodeName {
+			break
+		}
+		egressName = egressName + "a"
+	}
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newNode("node2", "192.168.1.2", true),
+		newPod("ns1", "local1", localNodeName, "10.10.0.2", appLabels),
+		newPod("ns1", "local2", localNodeName, "10.10.0.3", appLabels),
+		newPod("ns1", "remote", "node2", "10.10.1.2", appLabels),
+	}
+	c, cleanup := newFakeController(t, k8sObjects, []runtime.Object{newEgress(egressName, "192.168.1.100", appLabels)})
+	defer cleanup()
+	// The port range of the Egress IP is partitioned into 2 blocks.
+	c.snatPortsPerPod = 32256
+
+	// The blocks are allocated in the order of the Pod IPs, the last Pod shares the whole port range.
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.2"), net.ParseIP("192.168.1.100"), &binding.PortRange{StartPort: 1024, EndPort: 33279})
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.3"), net.ParseIP("192.168.1.100"), &binding.PortRange{StartPort: 33280, EndPort: 65535})
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.1.2"), net.ParseIP("192.168.1.100"), nil)
+	require.NoError(t, c.syncEgress(egressName))
+	assert.Equal(t, map[string]int{"10.10.0.2": 0, "10.10.0.3": 1}, c.egressStates[egressName].portBlocks)
+
+	// The block released by a deleted Pod is allocated to the Pod sharing the whole port range.
+	require.NoError(t, c.k8sClient.CoreV1().Pods("ns1").Delete(context.TODO(), "local1", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		_, err := c.podWatches[egressName]["/app=web"].lister.Pods("ns1").Get("local1")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	c.mockRouteClient.EXPECT().DeleteSNATRule(net.ParseIP("10.10.0.2"))
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.1.2"), net.ParseIP("192.168.1.100"), &binding.PortRange{StartPort: 1024, EndPort: 33279})
+	require.NoError(t, c.syncEgress(egressName))
+	assert.Equal(t, map[string]int{"10.10.0.3": 1, "10.10.1.2": 0}, c.egressStates[egressName].portBlocks)
+
+	// Nothing changes when the Egress is synced again.
+	require.NoError(t, c.syncEgress(egressName))
+}
+
+func TestSyncEgressOverlappingSelectors(t *testing.T) {
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newPod("ns1", "web", localNodeName, "10.10.0.2", appLabels),
+		newPod("ns1", "db", localNodeName, "10.10.0.3", map[string]string{"app": "db"}),
+	}
+	egresses := []runtime.Object{
+		newEgress("egress-a", "192.168.1.100", appLabels),
+		// A nil podSelector selects all Pods, but the web Pod is selected by egress-a first.
+		&corev1alpha1.Egress{
+			ObjectMeta: metav1.ObjectMeta{Name: "egress-b"},
+			Spec:       corev1alpha1.EgressSpec{EgressIP: "192.168.1.101"},
+		},
+	}
+	c, cleanup := newFakeController(t, k8sObjects, egresses)
+	defer cleanup()
+
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.3"), net.ParseIP("192.168.1.101"), nil)
+	require.NoError(t, c.syncEgress("egress-b"))
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.2"), net.ParseIP("192.168.1.100"), nil)
+	require.NoError(t, c.syncEgress("egress-a"))
+	assert.Equal(t, map[string]string{"10.10.0.2": "egress-a", "10.10.0.3": "egress-b"}, c.podEgresses)
+
+	// egress-a is deleted, the web Pod is taken by egress-b.
+	require.NoError(t, c.crdClient.CoreV1alpha1().Egresses().Delete(context.TODO(), "egress-a", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		_, err := c.egressLister.Get("egress-a")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	c.mockRouteClient.EXPECT().DeleteSNATRule(net.ParseIP("10.10.0.2"))
+	require.NoError(t, c.syncEgress("egress-a"))
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.2"), net.ParseIP("192.168.1.101"), nil)
+	require.NoError(t, c.syncEgress("egress-b"))
+	assert.Equal(t, map[string]string{"10.10.0.2": "egress-b", "10.10.0.3": "egress-b"}, c.podEgresses)
+}
+
+func TestSyncEgressNamespaceDefault(t *testing.T) {
+	defaultNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns2", Annotations: map[string]string{defaultEgressAnnotation: "egress-z"}},
+	}
+	k8sObjects := []runtime.Object{
+		namespace,
+		defaultNamespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newPod("ns1", "web", localNodeName, "10.10.0.2", appLabels),
+		newPod("ns2", "web", localNodeName, "10.10.0.3", appLabels),
+		newPod("ns2", "db", localNodeName, "10.10.0.4", map[string]string{"app": "db"}),
+	}
+	egresses := []runtime.Object{
+		// The name of the default Egress is the largest, the Pods it selects explicitly are still SNAT'd by the
+		// Egresses selecting them first.
+		newEgress("egress-z", "192.168.1.100", map[string]string{"app": "none"}),
+		newEgress("egress-a", "192.168.1.101", appLabels),
+	}
+	c, cleanup := newFakeController(t, k8sObjects, egresses)
+	defer cleanup()
+
+	// The web Pod of ns2 is selected by egress-a, only the db Pod is SNAT'd by the default Egress. The Pods of ns1
+	// are not affected by the default Egress of ns2.
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.4"), net.ParseIP("192.168.1.100"), nil)
+	require.NoError(t, c.syncEgress("egress-z"))
+	assert.Contains(t, c.podWatches["egress-z"], "ns2/")
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.2"), net.ParseIP("192.168.1.101"), nil)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP("10.10.0.3"), net.ParseIP("192.168.1.101"), nil)
+	require.NoError(t, c.syncEgress("egress-a"))
+	assert.Equal(t, map[string]string{"10.10.0.2": "egress-a", "10.10.0.3": "egress-a", "10.10.0.4": "egress-z"}, c.podEgresses)
+
+	// The annotation is removed, the db Pod is no longer SNAT'd.
+	updatedNamespace := defaultNamespace.DeepCopy()
+	updatedNamespace.Annotations = nil
+	_, err := c.k8sClient.CoreV1().Namespaces().Update(context.TODO(), updatedNamespace, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		ns, err := c.namespaceLister.Get("ns2")
+		return err == nil && len(ns.Annotations) == 0
+	}, time.Second, 10*time.Millisecond)
+	c.mockRouteClient.EXPECT().DeleteSNATRule(net.ParseIP("10.10.0.4"))
+	require.NoError(t, c.syncEgress("egress-z"))
+	assert.Equal(t, map[string]string{"10.10.0.2": "egress-a", "10.10.0.3": "egress-a"}, c.podEgresses)
+	// The Pods of ns2 are no longer watched for the default Egress.
+	assert.NotContains(t, c.podWatches["egress-z"], "ns2/")
+}
+
+func TestSelectEgressNodeStable(t *testing.T) {
+	nodes := []runtime.Object{
+		newNode("node1", "192.168.1.1", true),
+		newNode("node2", "192.168.1.2", true),
+		newNode("node3", "192.168.1.3", true),
+		newNode("node4", "192.168.1.4", true),
+	}
+	c, cleanup := newFakeController(t, nodes, nil)
+	defer cleanup()
+
+	selected, _, err := c.selectEgressNode(newEgress("egress-a", "192.168.1.100", appLabels))
+	require.NoError(t, err)
+	// Removing a Node other than the selected one doesn't move the Egress IP.
+	for _, obj := range nodes {
+		node := obj.(*corev1.Node)
+		if node.Name == selected {
+			continue
+		}
+		require.NoError(t, c.k8sClient.CoreV1().Nodes().Delete(context.TODO(), node.Name, metav1.DeleteOptions{}))
+		assert.Eventually(t, func() bool {
+			_, err := c.nodeLister.Get(node.Name)
+			return err != nil
+		}, time.Second, 10*time.Millisecond)
+		newSelected, _, err := c.selectEgressNode(newEgress("egress-a", "192.168.1.100", appLabels))
+		require.NoError(t, err)
+		assert.Equal(t, selected, newSelected)
+	}
+}
+
+func TestSelectEgressNodeWithExternalIPPool(t *testing.T) {
+	egressNodeLabels := map[string]string{"egress": "true"}
+	var nodes []runtime.Object
+	for i := 1; i <= 4; i++ {
+		node := newNode(fmt.Sprintf("node%d", i), fmt.Sprintf("192.168.1.%d", i), true)
+		if i > 2 {
+			node.Labels = egressNodeLabels
+		}
+		nodes = append(nodes, node)
+	}
+	pool := &corev1alpha1.ExternalIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool1"},
+		Spec: corev1alpha1.ExternalIPPoolSpec{
+			IPRanges:     []corev1alpha1.IPRange{{CIDR: "192.168.1.96/28"}},
+			NodeSelector: metav1.LabelSelector{MatchLabels: egressNodeLabels},
+		},
+	}
+	egress := newEgress("egress-a", "192.168.1.100", appLabels)
+	egress.Spec.ExternalIPPool = pool.Name
+	c, cleanup := newFakeController(t, nodes, []runtime.Object{pool, egress})
+	defer cleanup()
+
+	// Only the Nodes selected by the pool are eligible.
+	selected, other := egressNodeFor(egress.Name, "node3", "node4")
+	egressNode, _, err := c.selectEgressNode(egress)
+	require.NoError(t, err)
+	assert.Equal(t, selected, egressNode)
+
+	// The Egress IP moves to the other Node once the label of the selected Node is removed.
+	node, err := c.k8sClient.CoreV1().Nodes().Get(context.TODO(), selected, metav1.GetOptions{})
+	require.NoError(t, err)
+	node.Labels = nil
+	_, err = c.k8sClient.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		node, _ := c.nodeLister.Get(selected)
+		return len(node.Labels) == 0
+	}, time.Second, 10*time.Millisecond)
+	egressNode, _, err = c.selectEgressNode(egress)
+	require.NoError(t, err)
+	assert.Equal(t, other, egressNode)
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"net"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+// On Windows, the traffic of the Pods is SNAT'd by OVS instead of the host network, so is the traffic of the Pods
+// selected by the Egresses assigned to this Node.
+
+// installEgressTunnelFlows installs the flows SNATing the packets received from the tunnel and destined to external
+// addresses in OVS.
+func (c *EgressController) installEgressTunnelFlows() error {
+	return c.ofClient.InstallEgressTunnelSNATFlows()
+}
+
+// addSNATRule makes OVS SNAT the packets from the Pod IP to external addresses with the SNAT IP. The SNAT port range
+// is not partitioned on Windows, so portRange is always nil.
+func (c *EgressController) addSNATRule(podIP, snatIP net.IP, portRange *binding.PortRange) error {
+	return c.ofClient.InstallPodSNATIPFlows(podIP, snatIP)
+}
+
+// deleteSNATRule deletes the SNAT flows installed by addSNATRule for the Pod IP.
+func (c *EgressController) deleteSNATRule(podIP net.IP) error {
+	return c.ofClient.UninstallPodSNATIPFlows(podIP)
+}
+
+// snatConnectionCounter is nil as the connections SNAT'd in the OVS conntrack zone are not counted on Windows yet.
+var snatConnectionCounter func(egressIPs sets.String) (map[string]int, error)
@@ -0,0 +1,121 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/noderoute"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	corelistersv1alpha1 "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha1"
+)
+
+// EgressQuerier looks up the Egress which SNATs the connections of a local Pod leaving the cluster, with the same
+// selection rules as the EgressController: a Pod selected by multiple Egresses is SNAT'd by the one with the smallest
+// name, and a Pod selected by no Egress is SNAT'd by the default Egress of its Namespace. The connections of the Pods
+// which are not SNAT'd by an Egress, e.g. because its IP is not allocated yet or because no Node is eligible, are
+// masqueraded by the local Node. It only relies on listers, so it can be used concurrently and by the standalone flow
+// exporter, which doesn't run the EgressController.
+type EgressQuerier struct {
+	nodeName             string
+	egressLister         corelistersv1alpha1.EgressLister
+	podLister            corelisters.PodLister
+	namespaceLister      corelisters.NamespaceLister
+	nodeLister           corelisters.NodeLister
+	externalIPPoolLister corelistersv1alpha1.ExternalIPPoolLister
+}
+
+// NewEgressQuerier returns a new *EgressQuerier. podLister should only list the Pods running on this Node.
+func NewEgressQuerier(
+	nodeName string,
+	egressLister corelistersv1alpha1.EgressLister,
+	podLister corelisters.PodLister,
+	namespaceLister corelisters.NamespaceLister,
+	nodeLister corelisters.NodeLister,
+	externalIPPoolLister corelistersv1alpha1.ExternalIPPoolLister) *EgressQuerier {
+	return &EgressQuerier{
+		nodeName:             nodeName,
+		egressLister:         egressLister,
+		podLister:            podLister,
+		namespaceLister:      namespaceLister,
+		nodeLister:           nodeLister,
+		externalIPPoolLister: externalIPPoolLister,
+	}
+}
+
+// GetEgress returns the name of the Egress applied to the local Pod, the IP its connections are SNAT'd to and the name
+// of the Node where SNAT is done. The name is empty if the Pod is masqueraded by the local Node.
+func (q *EgressQuerier) GetEgress(podNamespace, podName string) (string, net.IP, string, bool) {
+	pod, err := q.podLister.Pods(podNamespace).Get(podName)
+	if err != nil {
+		return "", nil, "", false
+	}
+	namespace, err := q.namespaceLister.Get(podNamespace)
+	if err != nil {
+		return "", nil, "", false
+	}
+	egresses, err := q.egressLister.List(labels.Everything())
+	if err != nil {
+		return "", nil, "", false
+	}
+	if egress := podEgress(egresses, pod, namespace); egress != nil {
+		egressIP := net.ParseIP(egress.Spec.EgressIP)
+		if egressIP != nil && egressIP.To4() != nil {
+			egressNode, _, err := selectEgressNode(egress, q.nodeLister, q.externalIPPoolLister)
+			if err != nil {
+				klog.V(2).Infof("Failed to select the Egress Node of Egress %s: %v", egress.Name, err)
+				return "", nil, "", false
+			}
+			if egressNode != "" {
+				return egress.Name, egressIP, egressNode, true
+			}
+		}
+	}
+	node, err := q.nodeLister.Get(q.nodeName)
+	if err != nil {
+		return "", nil, "", false
+	}
+	nodeIP, err := noderoute.GetNodeAddr(node)
+	if err != nil {
+		return "", nil, "", false
+	}
+	return "", nodeIP, q.nodeName, true
+}
+
+// podEgress returns the Egress which SNATs the traffic of the Pod, or nil if the Pod is not selected by any Egress and
+// its Namespace has no default Egress.
+func podEgress(egresses []*corev1alpha1.Egress, pod *corev1.Pod, namespace *corev1.Namespace) *corev1alpha1.Egress {
+	defaultEgressName := namespace.Annotations[defaultEgressAnnotation]
+	var selectedEgress, defaultEgress *corev1alpha1.Egress
+	for _, egress := range egresses {
+		if selected, _ := selectsPod(egress, pod, namespace); selected {
+			if selectedEgress == nil || egress.Name < selectedEgress.Name {
+				selectedEgress = egress
+			}
+		}
+		if egress.Name == defaultEgressName {
+			defaultEgress = egress
+		}
+	}
+	if selectedEgress != nil {
+		return selectedEgress
+	}
+	return defaultEgress
+}
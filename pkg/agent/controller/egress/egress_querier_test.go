@@ -0,0 +1,101 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+)
+
+func TestGetEgress(t *testing.T) {
+	defaultNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns2", Annotations: map[string]string{defaultEgressAnnotation: "egress-z"}},
+	}
+	k8sObjects := []runtime.Object{
+		namespace,
+		defaultNamespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newPod("ns1", "web", localNodeName, "10.10.0.2", appLabels),
+		newPod("ns1", "db", localNodeName, "10.10.0.3", map[string]string{"app": "db"}),
+		newPod("ns1", "cache", localNodeName, "10.10.0.4", map[string]string{"app": "cache"}),
+		newPod("ns2", "db", localNodeName, "10.10.0.5", map[string]string{"app": "db"}),
+	}
+	egresses := []runtime.Object{
+		newEgress("egress-b", "192.168.1.101", appLabels),
+		newEgress("egress-a", "192.168.1.100", appLabels),
+		// egress-c has no IP yet, so the Pods it selects are masqueraded by the Node.
+		newEgress("egress-c", "", map[string]string{"app": "cache"}),
+		newEgress("egress-z", "192.168.1.102", map[string]string{"app": "none"}),
+	}
+	c, cleanup := newFakeController(t, k8sObjects, egresses)
+	defer cleanup()
+
+	tests := []struct {
+		podNamespace string
+		podName      string
+		expName      string
+		expIP        net.IP
+		expFound     bool
+	}{
+		// The Pod is selected by both egress-a and egress-b, egress-a takes precedence.
+		{"ns1", "web", "egress-a", net.ParseIP("192.168.1.100"), true},
+		{"ns1", "db", "", net.ParseIP("192.168.1.1"), true},
+		{"ns1", "cache", "", net.ParseIP("192.168.1.1"), true},
+		// The Pod is SNAT'd by the default Egress of its Namespace.
+		{"ns2", "db", "egress-z", net.ParseIP("192.168.1.102"), true},
+		{"ns1", "unknown", "", nil, false},
+	}
+	for _, tc := range tests {
+		name, egressIP, nodeName, found := c.GetEgress(tc.podNamespace, tc.podName)
+		assert.Equal(t, tc.expFound, found, "%s/%s", tc.podNamespace, tc.podName)
+		if !tc.expFound {
+			continue
+		}
+		assert.Equal(t, tc.expName, name, "%s/%s", tc.podNamespace, tc.podName)
+		assert.True(t, tc.expIP.Equal(egressIP), "%s/%s: %s", tc.podNamespace, tc.podName, egressIP)
+		// The local Node is the only eligible Node.
+		assert.Equal(t, localNodeName, nodeName)
+	}
+}
+
+func TestGetEgressWithoutEligibleNode(t *testing.T) {
+	k8sObjects := []runtime.Object{
+		namespace,
+		newNode(localNodeName, "192.168.1.1", true),
+		newPod("ns1", "web", localNodeName, "10.10.0.2", appLabels),
+	}
+	// The ExternalIPPool of the Egress selects no Node.
+	egress := newEgress("egress-a", "192.168.1.100", appLabels)
+	egress.Spec.ExternalIPPool = "pool1"
+	pool := &corev1alpha1.ExternalIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool1"},
+		Spec:       corev1alpha1.ExternalIPPoolSpec{NodeSelector: metav1.LabelSelector{MatchLabels: map[string]string{"egress": "true"}}},
+	}
+	c, cleanup := newFakeController(t, k8sObjects, []runtime.Object{egress, pool})
+	defer cleanup()
+
+	name, egressIP, nodeName, found := c.GetEgress("ns1", "web")
+	assert.True(t, found)
+	assert.Equal(t, "", name)
+	assert.True(t, net.ParseIP("192.168.1.1").Equal(egressIP))
+	assert.Equal(t, localNodeName, nodeName)
+}
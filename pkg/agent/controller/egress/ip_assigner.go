@@ -0,0 +1,24 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+// IPAssigner provides methods to assign or unassign the Egress IPs to the Node.
+type IPAssigner interface {
+	// AssignIP should make the Node own the IP, so that the traffic to the IP is received by the Node. It should do
+	// nothing if the IP is already assigned, without error.
+	AssignIP(ip string) error
+	// UnassignIP should revoke the IP from the Node. It should do nothing if the IP is not assigned, without error.
+	UnassignIP(ip string) error
+}
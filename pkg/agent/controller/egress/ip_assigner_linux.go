@@ -0,0 +1,110 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/arping"
+)
+
+// ipAssigner assigns the Egress IPs to the interface of the Node transport IP, from which the traffic to the
+// external network is sent.
+type ipAssigner struct {
+	externalInterface *net.Interface
+}
+
+// NewIPAssigner returns an IPAssigner which assigns the IPs to the interface of the provided Node transport IP.
+func NewIPAssigner(nodeTransportIP net.IP) (IPAssigner, error) {
+	_, externalInterface, err := util.GetIPNetDeviceFromIP(nodeTransportIP)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the interface of IP %s: %v", nodeTransportIP, err)
+	}
+	return &ipAssigner{externalInterface: externalInterface}, nil
+}
+
+func (a *ipAssigner) getAddr(ip string) (*netlink.Addr, error) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil || parsedIP.To4() == nil {
+		return nil, fmt.Errorf("invalid IPv4 address %s", ip)
+	}
+	return &netlink.Addr{IPNet: &net.IPNet{IP: parsedIP, Mask: net.CIDRMask(32, 32)}}, nil
+}
+
+func (a *ipAssigner) hasAddr(link netlink.Link, addr *netlink.Addr) (bool, error) {
+	addrs, err := netlink.AddrList(link, netlink.FAMILY_V4)
+	if err != nil {
+		return false, fmt.Errorf("error when listing the addresses of interface %s: %v", a.externalInterface.Name, err)
+	}
+	for _, a := range addrs {
+		if a.IPNet.String() == addr.IPNet.String() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AssignIP adds the IP to the interface as a /32 address and announces it with a gratuitous ARP, so that the
+// neighbors update their stale mappings if the IP was previously assigned to another Node.
+func (a *ipAssigner) AssignIP(ip string) error {
+	addr, err := a.getAddr(ip)
+	if err != nil {
+		return err
+	}
+	link, err := netlink.LinkByIndex(a.externalInterface.Index)
+	if err != nil {
+		return fmt.Errorf("error when getting interface %s: %v", a.externalInterface.Name, err)
+	}
+	if found, err := a.hasAddr(link, addr); err != nil {
+		return err
+	} else if found {
+		return nil
+	}
+	if err := netlink.AddrAdd(link, addr); err != nil {
+		return fmt.Errorf("failed to add IP %s to interface %s: %v", ip, a.externalInterface.Name, err)
+	}
+	klog.Infof("Assigned IP %s to interface %s", ip, a.externalInterface.Name)
+	if err := arping.GratuitousARPOverIface(addr.IP, a.externalInterface); err != nil {
+		klog.Warningf("Failed to send gratuitous ARP for IP %s: %v", ip, err)
+	}
+	return nil
+}
+
+// UnassignIP deletes the IP from the interface if it's found.
+func (a *ipAssigner) UnassignIP(ip string) error {
+	addr, err := a.getAddr(ip)
+	if err != nil {
+		return err
+	}
+	link, err := netlink.LinkByIndex(a.externalInterface.Index)
+	if err != nil {
+		return fmt.Errorf("error when getting interface %s: %v", a.externalInterface.Name, err)
+	}
+	if found, err := a.hasAddr(link, addr); err != nil {
+		return err
+	} else if !found {
+		return nil
+	}
+	if err := netlink.AddrDel(link, addr); err != nil {
+		return fmt.Errorf("failed to delete IP %s from interface %s: %v", ip, a.externalInterface.Name, err)
+	}
+	klog.Infof("Unassigned IP %s from interface %s", ip, a.externalInterface.Name)
+	return nil
+}
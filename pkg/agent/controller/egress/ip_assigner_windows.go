@@ -0,0 +1,98 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"fmt"
+	"net"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/util"
+)
+
+// ipAssigner assigns the Egress IPs to the interface of the Node transport IP, which is the OVS bridge interface on
+// Windows after the uplink configuration is moved to it.
+type ipAssigner struct {
+	externalInterface *net.Interface
+}
+
+// NewIPAssigner returns an IPAssigner which assigns the IPs to the interface of the provided Node transport IP.
+func NewIPAssigner(nodeTransportIP net.IP) (IPAssigner, error) {
+	_, externalInterface, err := util.GetIPNetDeviceFromIP(nodeTransportIP)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the interface of IP %s: %v", nodeTransportIP, err)
+	}
+	return &ipAssigner{externalInterface: externalInterface}, nil
+}
+
+func (a *ipAssigner) hasIP(ip net.IP) (bool, error) {
+	addrs, err := a.externalInterface.Addrs()
+	if err != nil {
+		return false, fmt.Errorf("error when listing the addresses of interface %s: %v", a.externalInterface.Name, err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseIPv4(ip string) (net.IP, error) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil || parsedIP.To4() == nil {
+		return nil, fmt.Errorf("invalid IPv4 address %s", ip)
+	}
+	return parsedIP, nil
+}
+
+// AssignIP adds the IP to the interface as a /32 address. The address is skipped as source, so that the traffic of
+// the Node keeps being sent with the Node IP. Windows announces the new address with a gratuitous ARP when it is
+// added.
+func (a *ipAssigner) AssignIP(ip string) error {
+	parsedIP, err := parseIPv4(ip)
+	if err != nil {
+		return err
+	}
+	if found, err := a.hasIP(parsedIP); err != nil {
+		return err
+	} else if found {
+		return nil
+	}
+	if err := util.AddInterfaceSecondaryAddress(a.externalInterface.Name, &net.IPNet{IP: parsedIP, Mask: net.CIDRMask(32, 32)}); err != nil {
+		return fmt.Errorf("failed to add IP %s to interface %s: %v", ip, a.externalInterface.Name, err)
+	}
+	klog.Infof("Assigned IP %s to interface %s", ip, a.externalInterface.Name)
+	return nil
+}
+
+// UnassignIP deletes the IP from the interface if it's found.
+func (a *ipAssigner) UnassignIP(ip string) error {
+	parsedIP, err := parseIPv4(ip)
+	if err != nil {
+		return err
+	}
+	if found, err := a.hasIP(parsedIP); err != nil {
+		return err
+	} else if !found {
+		return nil
+	}
+	if err := util.RemoveInterfaceAddress(a.externalInterface.Name, parsedIP); err != nil {
+		return fmt.Errorf("failed to delete IP %s from interface %s: %v", ip, a.externalInterface.Name, err)
+	}
+	klog.Infof("Unassigned IP %s from interface %s", ip, a.externalInterface.Name)
+	return nil
+}
@@ -0,0 +1,268 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"golang.org/x/time/rate"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+const (
+	auditLogActionAllow = "Allow"
+	auditLogActionDrop  = "Drop"
+	auditLogActionAudit = "Audit"
+
+	auditLogDirectionIngress = "Ingress"
+	auditLogDirectionEgress  = "Egress"
+)
+
+// AuditLogConfig is the configuration of the audit logging of the Antrea-native policy rules with logging enabled.
+type AuditLogConfig struct {
+	// File is the path of the file to which the audit log entries are written.
+	File string
+	// MaxSize is the size in megabytes after which the file is rotated.
+	MaxSize int
+	// MaxBackups is the maximum number of rotated files which are kept.
+	MaxBackups int
+	// SyslogNetwork and SyslogAddress are the network ("udp", "tcp" or "tls") and the address of the syslog server to
+	// which the entries are also sent. SyslogAddress is empty if the entries are not sent to syslog.
+	SyslogNetwork string
+	SyslogAddress string
+	// SyslogTLS is the TLS configuration used when SyslogNetwork is "tls".
+	SyslogTLS *AuditLogTLSConfig
+	// WebhookURL is the URL to which the entries are also POSTed in batches. It is empty if the entries are not sent
+	// to a webhook.
+	WebhookURL string
+	// RateLimit is the maximum number of entries written per second for each rule. It is 0 if the entries are not
+	// rate limited.
+	RateLimit int
+}
+
+// auditLogEntry is the structured audit log entry of a connection matched by a policy rule. It is written as a single
+// JSON line.
+type auditLogEntry struct {
+	Timestamp       string `json:"timestamp"`
+	PolicyType      string `json:"policyType"`
+	PolicyNamespace string `json:"policyNamespace,omitempty"`
+	PolicyName      string `json:"policyName"`
+	Direction       string `json:"direction"`
+	Action          string `json:"action"`
+	SourceIP        string `json:"sourceIP"`
+	SourcePort      uint16 `json:"sourcePort,omitempty"`
+	DestinationIP   string `json:"destinationIP"`
+	DestinationPort uint16 `json:"destinationPort,omitempty"`
+	Protocol        uint8  `json:"protocol"`
+	PacketLength    uint16 `json:"packetLength"`
+	// SuppressedEntries is the number of entries of the rule which were dropped by rate limiting since the previous
+	// entry of the rule.
+	SuppressedEntries uint64 `json:"suppressedEntries,omitempty"`
+}
+
+// auditLogger writes an audit log entry for each packet-in message sent by the action flow of a policy rule with
+// logging enabled. As the allow action flows only match the first packet of a connection, there is one entry per
+// allowed connection, while there is one entry per dropped packet. The entries are written to a rotating file, and
+// optionally sent to a syslog server and to a webhook.
+type auditLogger struct {
+	ofClient  openflow.Client
+	clock     clock.Clock
+	rateLimit int
+	syslog    *syslogWriter
+	webhook   *webhookWriter
+
+	mutex   sync.Mutex
+	writers []io.Writer
+	// ruleLimiters maps the conjunction ID of a rule to the rate limiter of its entries.
+	ruleLimiters map[uint32]*ruleRateLimiter
+}
+
+// ruleRateLimiter limits the rate of the entries of a rule, and counts the entries which are dropped.
+type ruleRateLimiter struct {
+	limiter    *rate.Limiter
+	suppressed uint64
+}
+
+func newAuditLogger(ofClient openflow.Client, config *AuditLogConfig) (*auditLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(config.File), 0755); err != nil {
+		return nil, fmt.Errorf("error creating audit log directory: %v", err)
+	}
+	writers := []io.Writer{&lumberjack.Logger{
+		Filename:   config.File,
+		MaxSize:    config.MaxSize,
+		MaxBackups: config.MaxBackups,
+		Compress:   true,
+	}}
+	var syslog *syslogWriter
+	if config.SyslogAddress != "" {
+		var err error
+		syslog, err = newSyslogWriter(config.SyslogNetwork, config.SyslogAddress, config.SyslogTLS)
+		if err != nil {
+			return nil, fmt.Errorf("error creating syslog writer: %v", err)
+		}
+		writers = append(writers, syslog)
+	}
+	var webhook *webhookWriter
+	if config.WebhookURL != "" {
+		webhook = newWebhookWriter(config.WebhookURL)
+		writers = append(writers, webhook)
+	}
+	return &auditLogger{
+		ofClient:     ofClient,
+		clock:        clock.RealClock{},
+		rateLimit:    config.RateLimit,
+		syslog:       syslog,
+		webhook:      webhook,
+		writers:      writers,
+		ruleLimiters: map[uint32]*ruleRateLimiter{},
+	}, nil
+}
+
+// run sends the buffered entries to the syslog server and to the webhook until stopCh is closed. It returns
+// immediately if no webhook is configured.
+func (l *auditLogger) run(stopCh <-chan struct{}) {
+	if l.syslog != nil {
+		go l.syslog.run(stopCh)
+	}
+	if l.webhook == nil {
+		return
+	}
+	l.webhook.run(stopCh)
+}
+
+// forgetRule deletes the rate limiter of a rule whose flows were uninstalled, as its conjunction ID can be reused by
+// another rule.
+func (l *auditLogger) forgetRule(conjID uint32) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.ruleLimiters, conjID)
+}
+
+// allow returns whether an entry of the rule can be written according to the rate limit, and the number of entries
+// of the rule which were dropped since the previous allowed one.
+func (l *auditLogger) allow(conjID uint32) (bool, uint64) {
+	if l.rateLimit <= 0 {
+		return true, 0
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	ruleLimiter, exists := l.ruleLimiters[conjID]
+	if !exists {
+		ruleLimiter = &ruleRateLimiter{limiter: rate.NewLimiter(rate.Limit(l.rateLimit), l.rateLimit)}
+		l.ruleLimiters[conjID] = ruleLimiter
+	}
+	if !ruleLimiter.limiter.AllowN(l.clock.Now(), 1) {
+		ruleLimiter.suppressed++
+		return false, 0
+	}
+	suppressed := ruleLimiter.suppressed
+	ruleLimiter.suppressed = 0
+	return true, suppressed
+}
+
+// handlePacketIn writes the audit log entry of the packet-in messages sent by the action flows of policy rules. Other
+// packet-in messages are ignored.
+func (l *auditLogger) handlePacketIn(pktIn *ofctrl.PacketIn) error {
+	conjID, action, found := openflow.GetPolicyRuleFromPacketIn(pktIn)
+	if !found {
+		return nil
+	}
+	policy := l.ofClient.GetPolicyFromConjunction(conjID)
+	if policy == nil {
+		return fmt.Errorf("policy rule with conjunction ID %d not found", conjID)
+	}
+	allowed, suppressed := l.allow(conjID)
+	if !allowed {
+		return nil
+	}
+	entry, err := parseAuditLogPacket(pktIn)
+	if err != nil {
+		return err
+	}
+	entry.SuppressedEntries = suppressed
+	entry.Timestamp = l.clock.Now().UTC().Format(time.RFC3339Nano)
+	entry.PolicyType = string(policy.Type)
+	entry.PolicyNamespace = policy.Namespace
+	entry.PolicyName = policy.Name
+	entry.Direction = auditLogDirectionIngress
+	if openflow.IsEgressRuleTable(binding.TableIDType(pktIn.TableId)) {
+		entry.Direction = auditLogDirectionEgress
+	}
+	switch action {
+	case secv1alpha1.RuleActionDrop:
+		entry.Action = auditLogActionDrop
+	case secv1alpha1.RuleActionAudit:
+		entry.Action = auditLogActionAudit
+	default:
+		entry.Action = auditLogActionAllow
+	}
+	return l.write(entry)
+}
+
+// parseAuditLogPacket builds an audit log entry with the 5-tuple of the IPv4 packet in a packet-in message.
+func parseAuditLogPacket(pktIn *ofctrl.PacketIn) (*auditLogEntry, error) {
+	if pktIn.Data.Ethertype != protocol.IPv4_MSG {
+		return nil, fmt.Errorf("audit logged packet is not an IPv4 packet")
+	}
+	ipPacket, ok := pktIn.Data.Data.(*protocol.IPv4)
+	if !ok {
+		return nil, fmt.Errorf("invalid IPv4 packet")
+	}
+	entry := &auditLogEntry{
+		SourceIP:      ipPacket.NWSrc.String(),
+		DestinationIP: ipPacket.NWDst.String(),
+		Protocol:      ipPacket.Protocol,
+		PacketLength:  ipPacket.Length,
+	}
+	switch transport := ipPacket.Data.(type) {
+	case *protocol.TCP:
+		entry.SourcePort = transport.PortSrc
+		entry.DestinationPort = transport.PortDst
+	case *protocol.UDP:
+		entry.SourcePort = transport.PortSrc
+		entry.DestinationPort = transport.PortDst
+	}
+	return entry, nil
+}
+
+func (l *auditLogger) write(entry *auditLogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, w := range l.writers {
+		if _, err := w.Write(line); err != nil {
+			klog.Errorf("Error writing audit log entry: %v", err)
+		}
+	}
+	return nil
+}
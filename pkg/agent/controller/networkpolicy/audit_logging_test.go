@@ -0,0 +1,157 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+)
+
+func newAuditLogPacketIn(tableID uint8, regs map[int]uint32) *ofctrl.PacketIn {
+	pktIn := &ofctrl.PacketIn{
+		TableId: tableID,
+		Match:   openflow13.Match{},
+	}
+	for reg, value := range regs {
+		pktIn.Match.Fields = append(pktIn.Match.Fields, *openflow13.NewRegMatchField(reg, value, nil))
+	}
+	pktIn.Data = protocol.Ethernet{
+		Ethertype: protocol.IPv4_MSG,
+		Data: &protocol.IPv4{
+			Length:   60,
+			Protocol: protocol.Type_TCP,
+			NWSrc:    net.ParseIP("10.10.0.2"),
+			NWDst:    net.ParseIP("10.10.1.3"),
+			Data:     &protocol.TCP{PortSrc: 40000, PortDst: 8080},
+		},
+	}
+	return pktIn
+}
+
+func TestAuditLoggerHandlePacketIn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ofClient := openflowtest.NewMockClient(ctrl)
+	buf := &bytes.Buffer{}
+	l := &auditLogger{
+		ofClient: ofClient,
+		clock:    clock.NewFakeClock(time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)),
+		writers:  []io.Writer{buf},
+	}
+	policy := &v1beta1.NetworkPolicyReference{Type: v1beta1.AntreaNetworkPolicy, Namespace: "ns1", Name: "anp1"}
+	ofClient.EXPECT().GetPolicyFromConjunction(uint32(10)).Return(policy).Times(3)
+
+	// Packets sent from other tables or for Traceflow are ignored.
+	require.NoError(t, l.handlePacketIn(newAuditLogPacketIn(uint8(openflow.IngressDefaultTable), map[int]uint32{int(openflow.IngressReg): 10})))
+	require.NoError(t, l.handlePacketIn(newAuditLogPacketIn(uint8(openflow.IngressRuleTable), map[int]uint32{int(openflow.IngressReg): 10, int(openflow.TraceflowReg): 1})))
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, l.handlePacketIn(newAuditLogPacketIn(uint8(openflow.IngressRuleTable), map[int]uint32{int(openflow.IngressReg): 10})))
+	// The conjunction ID of drop rules is loaded to reg3 and the drop mark to reg0.
+	require.NoError(t, l.handlePacketIn(newAuditLogPacketIn(uint8(openflow.EgressRuleTable), map[int]uint32{0: 1 << 20, 3: 10})))
+	// The conjunction ID of audit rules is loaded to reg3 and the audit mark to reg0.
+	require.NoError(t, l.handlePacketIn(newAuditLogPacketIn(uint8(openflow.MultiTierIngressRuleTable), map[int]uint32{0: 1 << 21, 3: 10})))
+
+	decoder := json.NewDecoder(buf)
+	var entry auditLogEntry
+	require.NoError(t, decoder.Decode(&entry))
+	assert.Equal(t, auditLogEntry{
+		Timestamp:       "2020-12-01T10:00:00Z",
+		PolicyType:      "AntreaNetworkPolicy",
+		PolicyNamespace: "ns1",
+		PolicyName:      "anp1",
+		Direction:       auditLogDirectionIngress,
+		Action:          auditLogActionAllow,
+		SourceIP:        "10.10.0.2",
+		SourcePort:      40000,
+		DestinationIP:   "10.10.1.3",
+		DestinationPort: 8080,
+		Protocol:        6,
+		PacketLength:    60,
+	}, entry)
+	require.NoError(t, decoder.Decode(&entry))
+	assert.Equal(t, auditLogDirectionEgress, entry.Direction)
+	assert.Equal(t, auditLogActionDrop, entry.Action)
+	require.NoError(t, decoder.Decode(&entry))
+	assert.Equal(t, auditLogDirectionIngress, entry.Direction)
+	assert.Equal(t, auditLogActionAudit, entry.Action)
+	assert.False(t, decoder.More())
+}
+
+func TestAuditLoggerUnknownConjunction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ofClient := openflowtest.NewMockClient(ctrl)
+	l := &auditLogger{ofClient: ofClient, clock: clock.RealClock{}}
+	ofClient.EXPECT().GetPolicyFromConjunction(uint32(11)).Return(nil)
+	assert.Error(t, l.handlePacketIn(newAuditLogPacketIn(uint8(openflow.EgressRuleTable), map[int]uint32{int(openflow.EgressReg): 11})))
+}
+
+func TestAuditLoggerRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ofClient := openflowtest.NewMockClient(ctrl)
+	buf := &bytes.Buffer{}
+	fakeClock := clock.NewFakeClock(time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC))
+	l := &auditLogger{
+		ofClient:     ofClient,
+		clock:        fakeClock,
+		rateLimit:    2,
+		writers:      []io.Writer{buf},
+		ruleLimiters: map[uint32]*ruleRateLimiter{},
+	}
+	policy := &v1beta1.NetworkPolicyReference{Type: v1beta1.AntreaClusterNetworkPolicy, Name: "acnp1"}
+	ofClient.EXPECT().GetPolicyFromConjunction(gomock.Any()).Return(policy).AnyTimes()
+	pktIn := newAuditLogPacketIn(uint8(openflow.IngressRuleTable), map[int]uint32{int(openflow.IngressReg): 10})
+
+	// The burst of each rule is the rate limit.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, l.handlePacketIn(pktIn))
+	}
+	// Rules are rate limited independently.
+	require.NoError(t, l.handlePacketIn(newAuditLogPacketIn(uint8(openflow.IngressRuleTable), map[int]uint32{int(openflow.IngressReg): 11})))
+	fakeClock.Step(time.Second)
+	require.NoError(t, l.handlePacketIn(pktIn))
+
+	decoder := json.NewDecoder(buf)
+	var suppressed []uint64
+	for decoder.More() {
+		var entry auditLogEntry
+		require.NoError(t, decoder.Decode(&entry))
+		suppressed = append(suppressed, entry.SuppressedEntries)
+	}
+	assert.Equal(t, []uint64{0, 0, 0, 3}, suppressed)
+
+	// The rate limiter of a rule is deleted when the rule is uninstalled.
+	l.forgetRule(10)
+	assert.NotContains(t, l.ruleLimiters, uint32(10))
+	assert.Contains(t, l.ruleLimiters, uint32(11))
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/klog"
+)
+
+const (
+	syslogNetworkUDP = "udp"
+	syslogNetworkTCP = "tcp"
+	syslogNetworkTLS = "tls"
+
+	// syslogPriority is the PRI of the messages: facility local0 (16) and severity informational (6).
+	syslogPriority = 16*8 + 6
+	syslogAppName  = "antrea-agent"
+	syslogMsgID    = "networkpolicy"
+	syslogTimeout  = 5 * time.Second
+	// syslogMaxBufferedEntries is the maximum number of entries waiting to be sent to the syslog server. The entries
+	// written when the buffer is full are dropped.
+	syslogMaxBufferedEntries = 10000
+)
+
+// AuditLogTLSConfig is the configuration used to send the audit log entries to the syslog server over TLS. The files
+// are read every time the connection to the server is established, so rotated certificates are picked up when the
+// Agent reconnects.
+type AuditLogTLSConfig struct {
+	// CAFile is the path of the CA bundle used to verify the certificate of the server. The system roots are used if
+	// it is empty.
+	CAFile string
+	// CertFile and KeyFile are the paths of the client certificate and key presented to the server. They are
+	// optional.
+	CertFile string
+	KeyFile  string
+	// ServerName is used to verify the certificate of the server. The host of the server address is used if it is
+	// empty.
+	ServerName string
+}
+
+func (c *AuditLogTLSConfig) load(serverHost string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = serverHost
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error when loading client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if c.CAFile != "" {
+		caBytes, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading CA bundle: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return nil, fmt.Errorf("no valid certificate in CA bundle %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// syslogWriter sends each written audit log entry to a syslog server as a RFC 5424 message. Over TCP and TLS, the
+// messages are framed with octet counting, as described in RFC 5425. The messages are buffered and sent by run, so that
+// a slow or unreachable server never blocks the writer, and they are dropped when the buffer is full. The connection
+// is established lazily and re-established once if sending a message fails.
+type syslogWriter struct {
+	network   string
+	address   string
+	tlsConfig *AuditLogTLSConfig
+	hostname  string
+	pid       int
+	clock     clock.Clock
+	msgCh     chan []byte
+	// dropped is the number of entries dropped since the last warning, accessed atomically.
+	dropped uint64
+
+	conn net.Conn
+}
+
+func newSyslogWriter(network, address string, tlsConfig *AuditLogTLSConfig) (*syslogWriter, error) {
+	if network != syslogNetworkUDP && network != syslogNetworkTCP && network != syslogNetworkTLS {
+		return nil, fmt.Errorf("unsupported syslog network %s", network)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &AuditLogTLSConfig{}
+	}
+	return &syslogWriter{
+		network:   network,
+		address:   address,
+		tlsConfig: tlsConfig,
+		hostname:  hostname,
+		pid:       os.Getpid(),
+		clock:     clock.RealClock{},
+		msgCh:     make(chan []byte, syslogMaxBufferedEntries),
+	}, nil
+}
+
+func (w *syslogWriter) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogTimeout}
+	if w.network != syslogNetworkTLS {
+		return dialer.Dial(w.network, w.address)
+	}
+	host, _, err := net.SplitHostPort(w.address)
+	if err != nil {
+		return nil, err
+	}
+	config, err := w.tlsConfig.load(host)
+	if err != nil {
+		return nil, err
+	}
+	return tls.DialWithDialer(dialer, "tcp", w.address, config)
+}
+
+// format returns the RFC 5424 message of an audit log entry, with the framing required by the transport.
+func (w *syslogWriter) format(line []byte) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "<%d>1 %s %s %s %d %s - ", syslogPriority, w.clock.Now().UTC().Format(time.RFC3339Nano),
+		w.hostname, syslogAppName, w.pid, syslogMsgID)
+	msg.Write(bytes.TrimSuffix(line, []byte("\n")))
+	if w.network == syslogNetworkUDP {
+		return msg.Bytes()
+	}
+	return append([]byte(fmt.Sprintf("%d ", msg.Len())), msg.Bytes()...)
+}
+
+// Write buffers the audit log entry in line, or drops it if the buffer is full. It never blocks.
+func (w *syslogWriter) Write(line []byte) (int, error) {
+	select {
+	case w.msgCh <- w.format(line):
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+	return len(line), nil
+}
+
+// run sends the buffered entries to the syslog server until stopCh is closed.
+func (w *syslogWriter) run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			if w.conn != nil {
+				w.conn.Close()
+			}
+			return
+		case msg := <-w.msgCh:
+			if err := w.send(msg); err != nil {
+				klog.Errorf("Error when sending audit log entry: %v", err)
+			}
+			if dropped := atomic.SwapUint64(&w.dropped, 0); dropped > 0 {
+				klog.Warningf("Dropped %d audit log entries because the syslog server %s is slow or unreachable", dropped, w.address)
+			}
+		}
+	}
+}
+
+// send sends a formatted message to the syslog server. It must not be called concurrently.
+func (w *syslogWriter) send(msg []byte) error {
+	var err error
+	for i := 0; i < 2; i++ {
+		if w.conn == nil {
+			if w.conn, err = w.dial(); err != nil {
+				w.conn = nil
+				continue
+			}
+		}
+		w.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+		if _, err = w.conn.Write(msg); err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	return fmt.Errorf("error sending audit log entry to syslog server %s: %v", w.address, err)
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func newTestSyslogWriter(t *testing.T, network, address string) *syslogWriter {
+	w, err := newSyslogWriter(network, address, nil)
+	require.NoError(t, err)
+	w.hostname = "node1"
+	w.pid = 100
+	w.clock = clock.NewFakeClock(time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC))
+	return w
+}
+
+func TestSyslogWriterFormat(t *testing.T) {
+	line := []byte(`{"policyName":"anp1"}` + "\n")
+	expected := `<134>1 2020-12-01T10:00:00Z node1 antrea-agent 100 networkpolicy - {"policyName":"anp1"}`
+
+	w := newTestSyslogWriter(t, syslogNetworkUDP, "127.0.0.1:514")
+	assert.Equal(t, expected, string(w.format(line)))
+	// The messages are framed with octet counting over TCP and TLS.
+	w = newTestSyslogWriter(t, syslogNetworkTLS, "127.0.0.1:6514")
+	assert.Equal(t, fmt.Sprintf("%d %s", len(expected), expected), string(w.format(line)))
+
+	_, err := newSyslogWriter("sctp", "127.0.0.1:514", nil)
+	assert.Error(t, err)
+}
+
+func TestSyslogWriterTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	messages := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(reader, msg); err != nil {
+				return
+			}
+			messages <- string(msg)
+		}
+	}()
+
+	w := newTestSyslogWriter(t, syslogNetworkTCP, listener.Addr().String())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go w.run(stopCh)
+	for _, name := range []string{"anp1", "anp2"} {
+		_, err := w.Write([]byte(fmt.Sprintf(`{"policyName":"%s"}`+"\n", name)))
+		require.NoError(t, err)
+	}
+	for _, name := range []string{"anp1", "anp2"} {
+		select {
+		case msg := <-messages:
+			assert.True(t, strings.HasSuffix(msg, fmt.Sprintf(`- {"policyName":"%s"}`, name)), msg)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Syslog message not received")
+		}
+	}
+}
+
+func TestSyslogWriterDropsWhenFull(t *testing.T) {
+	// The writer is not running, so the entries are only buffered.
+	w := newTestSyslogWriter(t, syslogNetworkTCP, "127.0.0.1:601")
+	line := []byte(`{"policyName":"anp1"}` + "\n")
+	for i := 0; i < syslogMaxBufferedEntries+2; i++ {
+		n, err := w.Write(line)
+		require.NoError(t, err)
+		assert.Equal(t, len(line), n)
+	}
+	assert.Equal(t, syslogMaxBufferedEntries, len(w.msgCh))
+	assert.Equal(t, uint64(2), w.dropped)
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const (
+	webhookFlushInterval = time.Second
+	webhookBatchSize     = 500
+	// webhookMaxBufferedEntries is the maximum number of entries kept while the webhook is unreachable.
+	webhookMaxBufferedEntries = 10000
+	webhookRequestTimeout     = 10 * time.Second
+)
+
+// webhookWriter buffers the written audit log entries and POSTs them in batches to a webhook, as JSON lines. The
+// entries which failed to be sent are retried with the next batch.
+type webhookWriter struct {
+	url        string
+	httpClient *http.Client
+	flushCh    chan struct{}
+
+	mutex sync.Mutex
+	lines [][]byte
+}
+
+func newWebhookWriter(url string) *webhookWriter {
+	return &webhookWriter{
+		url:        url,
+		httpClient: &http.Client{Timeout: webhookRequestTimeout},
+		flushCh:    make(chan struct{}, 1),
+	}
+}
+
+// Write buffers the audit log entry in line. A flush is triggered when a full batch is buffered.
+func (w *webhookWriter) Write(line []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.lines = append(w.lines, append([]byte(nil), line...))
+	if len(w.lines) > webhookMaxBufferedEntries {
+		w.lines = w.lines[len(w.lines)-webhookMaxBufferedEntries:]
+	}
+	if len(w.lines) >= webhookBatchSize {
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return len(line), nil
+}
+
+func (w *webhookWriter) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(webhookFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			w.flush()
+			return
+		case <-ticker.C:
+			w.flush()
+		case <-w.flushCh:
+			w.flush()
+		}
+	}
+}
+
+func (w *webhookWriter) flush() {
+	w.mutex.Lock()
+	lines := w.lines
+	w.lines = nil
+	w.mutex.Unlock()
+	for start := 0; start < len(lines); start += webhookBatchSize {
+		end := start + webhookBatchSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if err := w.post(lines[start:end]); err != nil {
+			klog.Errorf("Error when sending audit log entries to webhook: %v", err)
+			w.requeue(lines[start:])
+			return
+		}
+	}
+}
+
+// requeue puts back the entries which failed to be sent in front of the buffer, dropping the oldest entries if the
+// buffer is full.
+func (w *webhookWriter) requeue(lines [][]byte) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.lines = append(lines, w.lines...)
+	if len(w.lines) > webhookMaxBufferedEntries {
+		klog.Warningf("Dropping %d audit log entries because the webhook is unreachable", len(w.lines)-webhookMaxBufferedEntries)
+		w.lines = w.lines[len(w.lines)-webhookMaxBufferedEntries:]
+	}
+}
+
+func (w *webhookWriter) post(lines [][]byte) error {
+	resp, err := w.httpClient.Post(w.url, "application/x-ndjson", bytes.NewReader(bytes.Join(lines, nil)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}
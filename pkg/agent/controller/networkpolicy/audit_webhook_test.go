@@ -0,0 +1,72 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWebhook records the bodies of the requests it receives.
+type fakeWebhook struct {
+	mutex  sync.Mutex
+	bodies []string
+	fail   bool
+}
+
+func (f *fakeWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.fail {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	f.bodies = append(f.bodies, string(body))
+}
+
+func TestWebhookWriterFlush(t *testing.T) {
+	fake := &fakeWebhook{fail: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	w := newWebhookWriter(server.URL)
+	w.Write([]byte("{\"policyName\":\"anp1\"}\n"))
+	w.Write([]byte("{\"policyName\":\"anp2\"}\n"))
+	// The entries are kept while the webhook is unavailable.
+	w.flush()
+	require.Len(t, w.lines, 2)
+
+	fake.mutex.Lock()
+	fake.fail = false
+	fake.mutex.Unlock()
+	w.flush()
+	assert.Empty(t, w.lines)
+	assert.Equal(t, []string{"{\"policyName\":\"anp1\"}\n{\"policyName\":\"anp2\"}\n"}, fake.bodies)
+}
+
+func TestWebhookWriterMaxBufferedEntries(t *testing.T) {
+	w := newWebhookWriter("http://127.0.0.1:1")
+	for i := 0; i < webhookMaxBufferedEntries+10; i++ {
+		w.Write([]byte("{}\n"))
+	}
+	assert.Len(t, w.lines, webhookMaxBufferedEntries)
+}
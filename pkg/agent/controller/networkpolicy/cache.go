@@ -70,6 +70,10 @@ type rule struct {
 	PolicyPriority *float64
 	// Priority of the tier that the NetworkPolicy belongs to. nil for K8s NetworkPolicy.
 	TierPriority *int32
+	// Whether the connections matched by this rule are audit logged.
+	EnableLogging bool
+	// Rate limit of the new connections matched by this rule. nil if not limited.
+	RateLimit *v1beta1.RuleRateLimit
 	// Targets of this rule.
 	AppliedToGroups []string
 	// The parent Policy ID. Used to identify rules belong to a specified
@@ -108,6 +112,9 @@ type CompletedRule struct {
 	ToAddresses v1beta1.GroupMemberSet
 	// Target Pods of this rule.
 	Pods v1beta1.GroupMemberPodSet
+	// Target Nodes of this rule, whose host network is protected by the rule.
+	// It's nil if the rule doesn't apply to any Node.
+	Nodes v1beta1.GroupMemberSet
 }
 
 // String returns the string representation of the CompletedRule.
@@ -118,8 +125,8 @@ func (r *CompletedRule) String() string {
 	} else {
 		addressString = fmt.Sprintf("ToAddressGroups: %d, ToIPBlocks: %d, ToAddresses: %d", len(r.To.AddressGroups), len(r.To.IPBlocks), len(r.ToAddresses))
 	}
-	return fmt.Sprintf("%s (Direction: %v, Pods: %d, %s, Services: %d, PolicyPriority: %v, RulePriority: %v)",
-		r.ID, r.Direction, len(r.Pods), addressString, len(r.Services), r.PolicyPriority, r.Priority)
+	return fmt.Sprintf("%s (Direction: %v, Pods: %d, Nodes: %d, %s, Services: %d, PolicyPriority: %v, RulePriority: %v)",
+		r.ID, r.Direction, len(r.Pods), len(r.Nodes), addressString, len(r.Services), r.PolicyPriority, r.Priority)
 }
 
 // isAntreaNetworkPolicyRule returns true if the rule is part of a Antrea policy.
@@ -134,6 +141,10 @@ type ruleCache struct {
 	// podSetByGroup stores the AppliedToGroup members.
 	// It is a mapping from group name to a set of Pods.
 	podSetByGroup map[string]v1beta1.GroupMemberPodSet
+	// nodeSetByGroup stores the Node members of the AppliedToGroups which
+	// select Nodes. It is a mapping from group name to a set of Nodes, and is
+	// protected by podSetLock too.
+	nodeSetByGroup map[string]v1beta1.GroupMemberSet
 
 	addressSetLock sync.RWMutex
 	// addressSetByGroup stores the AddressGroup members.
@@ -142,7 +153,7 @@ type ruleCache struct {
 
 	policyMapLock sync.RWMutex
 	// policyMap is a map using NetworkPolicy UID as the key.
-	policyMap map[string]*types.NamespacedName
+	policyMap map[string]*v1beta1.NetworkPolicy
 
 	// rules is a storage that supports listing rules using multiple indexing functions.
 	// rules is thread-safe.
@@ -216,12 +227,14 @@ func addRuleToNetworkPolicy(np *v1beta1.NetworkPolicy, rule *rule) *v1beta1.Netw
 		}
 	}
 	np.Rules = append(np.Rules, v1beta1.NetworkPolicyRule{
-		Direction: rule.Direction,
-		From:      rule.From,
-		To:        rule.To,
-		Services:  rule.Services,
-		Action:    rule.Action,
-		Priority:  rule.Priority})
+		Direction:     rule.Direction,
+		From:          rule.From,
+		To:            rule.To,
+		Services:      rule.Services,
+		Action:        rule.Action,
+		Priority:      rule.Priority,
+		EnableLogging: rule.EnableLogging,
+		RateLimit:     rule.RateLimit})
 	return np
 
 }
@@ -291,9 +304,14 @@ func (c *ruleCache) GetAppliedToGroups() []v1beta1.AppliedToGroup {
 		for _, pod := range v.Items() {
 			pods = append(pods, *pod)
 		}
+		var groupMembers []v1beta1.GroupMember
+		for _, member := range c.nodeSetByGroup[k] {
+			groupMembers = append(groupMembers, *member)
+		}
 		ret = append(ret, v1beta1.AppliedToGroup{
-			ObjectMeta: metav1.ObjectMeta{Name: k},
-			Pods:       pods,
+			ObjectMeta:   metav1.ObjectMeta{Name: k},
+			Pods:         pods,
+			GroupMembers: groupMembers,
 		})
 	}
 	return ret
@@ -337,8 +355,9 @@ func newRuleCache(dirtyRuleHandler func(string), podUpdate <-chan v1beta1.PodRef
 	)
 	cache := &ruleCache{
 		podSetByGroup:     make(map[string]v1beta1.GroupMemberPodSet),
+		nodeSetByGroup:    make(map[string]v1beta1.GroupMemberSet),
 		addressSetByGroup: make(map[string]v1beta1.GroupMemberSet),
-		policyMap:         make(map[string]*types.NamespacedName),
+		policyMap:         make(map[string]*v1beta1.NetworkPolicy),
 		rules:             rules,
 		dirtyRuleHandler:  dirtyRuleHandler,
 		podUpdates:        podUpdate,
@@ -501,6 +520,7 @@ func (c *ruleCache) ReplaceAppliedToGroups(groups []*v1beta1.AppliedToGroup) {
 
 	for key := range oldGroupKeys {
 		delete(c.podSetByGroup, key)
+		delete(c.nodeSetByGroup, key)
 	}
 	return
 }
@@ -521,11 +541,22 @@ func (c *ruleCache) addAppliedToGroupLocked(group *v1beta1.AppliedToGroup) error
 	for i := range group.Pods {
 		podSet.Insert(&group.Pods[i])
 	}
+	nodeSet := v1beta1.GroupMemberSet{}
+	for i := range group.GroupMembers {
+		if group.GroupMembers[i].Node != nil {
+			nodeSet.Insert(&group.GroupMembers[i])
+		}
+	}
 	oldPodSet, exists := c.podSetByGroup[group.Name]
-	if exists && oldPodSet.Equal(podSet) {
+	if exists && oldPodSet.Equal(podSet) && c.nodeSetByGroup[group.Name].Equal(nodeSet) {
 		return nil
 	}
 	c.podSetByGroup[group.Name] = podSet
+	if len(nodeSet) > 0 {
+		c.nodeSetByGroup[group.Name] = nodeSet
+	} else {
+		delete(c.nodeSetByGroup, group.Name)
+	}
 	c.onAppliedToGroupUpdate(group.Name)
 	return nil
 }
@@ -546,6 +577,24 @@ func (c *ruleCache) PatchAppliedToGroup(patch *v1beta1.AppliedToGroupPatch) erro
 	for i := range patch.RemovedPods {
 		podSet.Delete(&patch.RemovedPods[i])
 	}
+	nodeSet := c.nodeSetByGroup[patch.Name]
+	for i := range patch.AddedGroupMembers {
+		if patch.AddedGroupMembers[i].Node != nil {
+			if nodeSet == nil {
+				nodeSet = v1beta1.GroupMemberSet{}
+				c.nodeSetByGroup[patch.Name] = nodeSet
+			}
+			nodeSet.Insert(&patch.AddedGroupMembers[i])
+		}
+	}
+	for i := range patch.RemovedGroupMembers {
+		if patch.RemovedGroupMembers[i].Node != nil && nodeSet != nil {
+			nodeSet.Delete(&patch.RemovedGroupMembers[i])
+		}
+	}
+	if nodeSet != nil && len(nodeSet) == 0 {
+		delete(c.nodeSetByGroup, patch.Name)
+	}
 	c.onAppliedToGroupUpdate(patch.Name)
 	return nil
 }
@@ -558,6 +607,7 @@ func (c *ruleCache) DeleteAppliedToGroup(group *v1beta1.AppliedToGroup) error {
 	defer c.podSetLock.Unlock()
 
 	delete(c.podSetByGroup, group.Name)
+	delete(c.nodeSetByGroup, group.Name)
 	return nil
 }
 
@@ -575,6 +625,8 @@ func toRule(r *v1beta1.NetworkPolicyRule, policy *v1beta1.NetworkPolicy, maxPrio
 		AppliedToGroups: policy.AppliedToGroups,
 		PolicyUID:       policy.UID,
 		SourceRef:       policy.SourceRef,
+		EnableLogging:   r.EnableLogging,
+		RateLimit:       r.RateLimit,
 	}
 	rule.ID = hashRule(rule)
 	rule.PolicyNamespace = policy.Namespace
@@ -641,14 +693,21 @@ func (c *ruleCache) AddNetworkPolicy(policy *v1beta1.NetworkPolicy) error {
 }
 
 func (c *ruleCache) addNetworkPolicyLocked(policy *v1beta1.NetworkPolicy) error {
-	c.policyMap[string(policy.UID)] = &types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}
 	metrics.NetworkPolicyCount.Inc()
-	return c.UpdateNetworkPolicy(policy)
+	return c.updateNetworkPolicyLocked(policy)
 }
 
 // UpdateNetworkPolicy updates a cached *v1beta1.NetworkPolicy.
 // The added rules and removed rules will be regarded as dirty.
 func (c *ruleCache) UpdateNetworkPolicy(policy *v1beta1.NetworkPolicy) error {
+	c.policyMapLock.Lock()
+	defer c.policyMapLock.Unlock()
+
+	return c.updateNetworkPolicyLocked(policy)
+}
+
+func (c *ruleCache) updateNetworkPolicyLocked(policy *v1beta1.NetworkPolicy) error {
+	c.policyMap[string(policy.UID)] = policy
 	existingRules, _ := c.rules.ByIndex(policyIndex, string(policy.UID))
 	ruleByID := map[string]interface{}{}
 	for _, r := range existingRules {
@@ -716,6 +775,25 @@ func (c *ruleCache) deleteNetworkPolicyLocked(uid string) error {
 	return nil
 }
 
+// getNetworkPolicyRuleIDs returns the cached NetworkPolicy with the given UID
+// and the IDs of its rules. The Rules of the returned NetworkPolicy may not
+// match the cached rules if the NetworkPolicy is being updated.
+func (c *ruleCache) getNetworkPolicyRuleIDs(uid types.UID) (*v1beta1.NetworkPolicy, sets.String, bool) {
+	c.policyMapLock.RLock()
+	defer c.policyMapLock.RUnlock()
+
+	policy, exists := c.policyMap[string(uid)]
+	if !exists {
+		return nil, nil, false
+	}
+	rules, _ := c.rules.ByIndex(policyIndex, string(uid))
+	ruleIDs := make(sets.String, len(rules))
+	for _, r := range rules {
+		ruleIDs.Insert(r.(*rule).ID)
+	}
+	return policy, ruleIDs, true
+}
+
 // GetCompletedRule constructs a *CompletedRule for the provided ruleID.
 // If the rule is not found or not completed due to missing group data,
 // the return value will indicate it.
@@ -736,7 +814,7 @@ func (c *ruleCache) GetCompletedRule(ruleID string) (completedRule *CompletedRul
 		return nil, true, false
 	}
 
-	pods, completed := c.unionAppliedToGroups(r.AppliedToGroups)
+	pods, nodes, completed := c.unionAppliedToGroups(r.AppliedToGroups)
 	if !completed {
 		return nil, true, false
 	}
@@ -746,6 +824,7 @@ func (c *ruleCache) GetCompletedRule(ruleID string) (completedRule *CompletedRul
 		FromAddresses: fromAddresses,
 		ToAddresses:   toAddresses,
 		Pods:          pods,
+		Nodes:         nodes,
 	}
 	return completedRule, true, true
 }
@@ -788,20 +867,26 @@ func (c *ruleCache) unionAddressGroups(groupNames []string) (v1beta1.GroupMember
 }
 
 // unionAppliedToGroups gets the union of pods of the provided appliedTo groups.
+// The union of Nodes of the provided groups is returned too, which is nil if
+// none of them selects any Node.
 // If any group is not found, nil and false will be returned to indicate the
 // set is not complete yet.
-func (c *ruleCache) unionAppliedToGroups(groupNames []string) (v1beta1.GroupMemberPodSet, bool) {
+func (c *ruleCache) unionAppliedToGroups(groupNames []string) (v1beta1.GroupMemberPodSet, v1beta1.GroupMemberSet, bool) {
 	c.podSetLock.RLock()
 	defer c.podSetLock.RUnlock()
 
 	set := v1beta1.NewGroupMemberPodSet()
+	var nodeSet v1beta1.GroupMemberSet
 	for _, groupName := range groupNames {
 		curSet, exists := c.podSetByGroup[groupName]
 		if !exists {
 			klog.V(2).Infof("AppliedToGroup %v was not found", groupName)
-			return nil, false
+			return nil, nil, false
 		}
 		set = set.Union(curSet)
+		if curNodeSet, exists := c.nodeSetByGroup[groupName]; exists {
+			nodeSet = curNodeSet.Union(nodeSet)
+		}
 	}
-	return set, true
+	return set, nodeSet, true
 }
@@ -22,7 +22,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
@@ -506,7 +505,7 @@ func TestRuleCacheReplaceNetworkPolicies(t *testing.T) {
 			c, recorder, _ := newFakeRuleCache()
 			for _, rule := range tt.rules {
 				c.rules.Add(rule)
-				c.policyMap[string(rule.PolicyUID)] = &types.NamespacedName{Namespace: rule.PolicyNamespace, Name: rule.PolicyName}
+				c.policyMap[string(rule.PolicyUID)] = &v1beta1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{UID: rule.PolicyUID, Namespace: rule.PolicyNamespace, Name: rule.PolicyName}}
 			}
 			c.ReplaceNetworkPolicies(tt.args)
 
@@ -1128,3 +1127,22 @@ func TestRuleCacheProcessPodUpdates(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleCacheAppliedToGroupNodes(t *testing.T) {
+	c, recorder, _ := newFakeRuleCache()
+	rule1 := &rule{ID: "rule1", AppliedToGroups: []string{"group1", "group2"}}
+	c.rules.Add(rule1)
+	node := v1beta1.GroupMember{Node: &v1beta1.NodeReference{Name: "node1"}}
+
+	assert.NoError(t, c.AddAppliedToGroup(&v1beta1.AppliedToGroup{ObjectMeta: metav1.ObjectMeta{Name: "group1"}}))
+	assert.NoError(t, c.AddAppliedToGroup(&v1beta1.AppliedToGroup{ObjectMeta: metav1.ObjectMeta{Name: "group2"}, GroupMembers: []v1beta1.GroupMember{node}}))
+	assert.Equal(t, sets.NewString("rule1"), recorder.rules)
+	completedRule, _, completed := c.GetCompletedRule("rule1")
+	assert.True(t, completed)
+	assert.Equal(t, v1beta1.NewGroupMemberSet(&node), completedRule.Nodes)
+
+	assert.NoError(t, c.PatchAppliedToGroup(&v1beta1.AppliedToGroupPatch{ObjectMeta: metav1.ObjectMeta{Name: "group2"}, RemovedGroupMembers: []v1beta1.GroupMember{node}}))
+	completedRule, _, _ = c.GetCompletedRule("rule1")
+	assert.Nil(t, completedRule.Nodes)
+	assert.NotContains(t, c.nodeSetByGroup, "group2")
+}
@@ -0,0 +1,332 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"golang.org/x/net/dns/dnsmessage"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+const (
+	// fqdnCacheGCInterval is the interval at which the expired addresses are removed from the FQDN cache.
+	fqdnCacheGCInterval = 30 * time.Second
+	// wildcardPrefix is the prefix of the FQDNs matching all the subdomains of a domain.
+	wildcardPrefix = "*."
+	// dnsPort is the transport source port of the DNS responses.
+	dnsPort = 53
+	// dnsServiceNamespace and dnsServiceName identify the Service of the cluster DNS server. Only the DNS responses
+	// sent from its ClusterIP or from its Endpoints are processed.
+	dnsServiceNamespace = "kube-system"
+	dnsServiceName      = "kube-dns"
+)
+
+// fqdnController learns the IP addresses of the FQDNs referenced by the egress rules of Antrea-native policies, by
+// snooping the DNS responses sent to local Pods. The learned addresses are kept until their TTL expires. The rules
+// referencing a FQDN are marked as dirty whenever the set of addresses matched by the FQDN changes.
+//
+// As a copy of the DNS response is sent to the Agent while the original one is forwarded to the Pod, the first packets
+// sent by the Pod to a newly resolved address may be processed before the rule flows are updated.
+//
+// Only the responses sent by the cluster DNS server are trusted, so that a Pod cannot add arbitrary addresses to the
+// rules by sending spoofed DNS responses to other Pods, and only the names matched by the FQDNs of the rules are
+// cached.
+type fqdnController struct {
+	ofClient         openflow.Client
+	dirtyRuleHandler func(string)
+	clock            clock.Clock
+	// dnsServiceLister and dnsEndpointsLister are used to get the addresses of the cluster DNS server.
+	dnsServiceLister   corelisters.ServiceLister
+	dnsEndpointsLister corelisters.EndpointsLister
+
+	mutex sync.RWMutex
+	// ruleFQDNs maps the ID of a rule to the FQDNs it references.
+	ruleFQDNs map[string]sets.String
+	// fqdnRules maps a FQDN to the IDs of the rules referencing it.
+	fqdnRules map[string]sets.String
+	// dnsCache maps a domain name to its resolved IP addresses and the expiration time of each address.
+	dnsCache map[string]map[string]time.Time
+	// flowsInstalled indicates whether the flows sending the DNS responses to the Agent have been installed.
+	flowsInstalled bool
+}
+
+func newFQDNController(ofClient openflow.Client, dirtyRuleHandler func(string), dnsServiceLister corelisters.ServiceLister, dnsEndpointsLister corelisters.EndpointsLister) *fqdnController {
+	return &fqdnController{
+		ofClient:           ofClient,
+		dirtyRuleHandler:   dirtyRuleHandler,
+		clock:              clock.RealClock{},
+		dnsServiceLister:   dnsServiceLister,
+		dnsEndpointsLister: dnsEndpointsLister,
+		ruleFQDNs:          map[string]sets.String{},
+		fqdnRules:          map[string]sets.String{},
+		dnsCache:           map[string]map[string]time.Time{},
+	}
+}
+
+// normalizeDomainName returns the lower-case domain name without the trailing dot.
+func normalizeDomainName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// fqdnMatches returns true if the domain name is matched by the FQDN. A FQDN starting with "*." matches all the
+// subdomains of the domain following the wildcard, but not the domain itself.
+func fqdnMatches(fqdn, name string) bool {
+	fqdn = normalizeDomainName(fqdn)
+	if strings.HasPrefix(fqdn, wildcardPrefix) {
+		return strings.HasSuffix(name, fqdn[1:])
+	}
+	return name == fqdn
+}
+
+// setRuleFQDNs records the FQDNs referenced by the rule, replacing the previous ones. The flows sending the DNS
+// responses to the Agent are installed when the first rule referencing a FQDN is set.
+func (f *fqdnController) setRuleFQDNs(ruleID string, fqdns []string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.deleteRuleLocked(ruleID)
+	if len(fqdns) == 0 {
+		return nil
+	}
+	if !f.flowsInstalled {
+		if err := f.ofClient.InstallDNSResponseFlows(); err != nil {
+			return fmt.Errorf("error installing DNS response flows: %v", err)
+		}
+		f.flowsInstalled = true
+	}
+	f.ruleFQDNs[ruleID] = sets.NewString(fqdns...)
+	for _, fqdn := range fqdns {
+		ruleIDs, exists := f.fqdnRules[fqdn]
+		if !exists {
+			ruleIDs = sets.NewString()
+			f.fqdnRules[fqdn] = ruleIDs
+		}
+		ruleIDs.Insert(ruleID)
+	}
+	return nil
+}
+
+// deleteRule removes the FQDNs referenced by the rule.
+func (f *fqdnController) deleteRule(ruleID string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.deleteRuleLocked(ruleID)
+}
+
+func (f *fqdnController) deleteRuleLocked(ruleID string) {
+	for fqdn := range f.ruleFQDNs[ruleID] {
+		ruleIDs := f.fqdnRules[fqdn]
+		ruleIDs.Delete(ruleID)
+		if ruleIDs.Len() == 0 {
+			delete(f.fqdnRules, fqdn)
+		}
+	}
+	delete(f.ruleFQDNs, ruleID)
+}
+
+// getGroupMembers returns the GroupMembers representing the unexpired IP addresses of the domain names matched by the
+// provided FQDNs.
+func (f *fqdnController) getGroupMembers(fqdns []string) v1beta1.GroupMemberSet {
+	now := f.clock.Now()
+	members := v1beta1.NewGroupMemberSet()
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	for name, addresses := range f.dnsCache {
+		matched := false
+		for _, fqdn := range fqdns {
+			if fqdnMatches(fqdn, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		for ip, expiration := range addresses {
+			if expiration.Before(now) {
+				continue
+			}
+			members.Insert(&v1beta1.GroupMember{Endpoints: []v1beta1.Endpoint{{IP: v1beta1.IPAddress(net.ParseIP(ip))}}})
+		}
+	}
+	return members
+}
+
+// matchedByRulesLocked returns true if the domain name is matched by a FQDN referenced by a rule.
+func (f *fqdnController) matchedByRulesLocked(name string) bool {
+	for fqdn := range f.fqdnRules {
+		if fqdnMatches(fqdn, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isClusterDNSServer returns true if the IP address is the ClusterIP or the address of an Endpoint of the Service of the
+// cluster DNS server.
+func (f *fqdnController) isClusterDNSServer(ip net.IP) bool {
+	service, err := f.dnsServiceLister.Services(dnsServiceNamespace).Get(dnsServiceName)
+	if err != nil {
+		klog.V(2).Infof("Failed to get the Service of the cluster DNS server: %v", err)
+		return false
+	}
+	if net.ParseIP(service.Spec.ClusterIP).Equal(ip) {
+		return true
+	}
+	endpoints, err := f.dnsEndpointsLister.Endpoints(dnsServiceNamespace).Get(dnsServiceName)
+	if err != nil {
+		klog.V(2).Infof("Failed to get the Endpoints of the cluster DNS server: %v", err)
+		return false
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if net.ParseIP(address.IP).Equal(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// affectedRulesLocked returns the IDs of the rules referencing a FQDN which matches any of the provided names.
+func (f *fqdnController) affectedRulesLocked(names sets.String) sets.String {
+	ruleIDs := sets.NewString()
+	for fqdn, fqdnRuleIDs := range f.fqdnRules {
+		for name := range names {
+			if fqdnMatches(fqdn, name) {
+				ruleIDs = ruleIDs.Union(fqdnRuleIDs)
+				break
+			}
+		}
+	}
+	return ruleIDs
+}
+
+// onDNSResponse records the addresses of the A and AAAA records in a DNS response. The addresses are associated with
+// the owner names of the records and with the names of the questions, so that the domain names resolved through CNAME
+// records are matched by the FQDNs of the questions. Only the names matched by the FQDNs of the rules are recorded. The
+// rules referencing the names which get new addresses are marked as dirty.
+func (f *fqdnController) onDNSResponse(msg *dnsmessage.Message) {
+	now := f.clock.Now()
+	questionNames := sets.NewString()
+	for _, question := range msg.Questions {
+		questionNames.Insert(normalizeDomainName(question.Name.String()))
+	}
+	updatedNames := sets.NewString()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, answer := range msg.Answers {
+		var ip net.IP
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			ip = net.IP(body.A[:])
+		case *dnsmessage.AAAAResource:
+			ip = net.IP(body.AAAA[:])
+		default:
+			continue
+		}
+		expiration := now.Add(time.Duration(answer.Header.TTL) * time.Second)
+		names := sets.NewString(normalizeDomainName(answer.Header.Name.String())).Union(questionNames)
+		for name := range names {
+			if !f.matchedByRulesLocked(name) {
+				continue
+			}
+			addresses, exists := f.dnsCache[name]
+			if !exists {
+				addresses = map[string]time.Time{}
+				f.dnsCache[name] = addresses
+			}
+			// Expired addresses may have been excluded from the rules already.
+			if oldExpiration, exists := addresses[ip.String()]; !exists || oldExpiration.Before(now) {
+				updatedNames.Insert(name)
+			}
+			addresses[ip.String()] = expiration
+		}
+	}
+	for ruleID := range f.affectedRulesLocked(updatedNames) {
+		f.dirtyRuleHandler(ruleID)
+	}
+}
+
+// removeExpiredAddresses removes the addresses whose TTL has expired from the cache, and marks the rules referencing
+// the names of the removed addresses as dirty.
+func (f *fqdnController) removeExpiredAddresses() {
+	now := f.clock.Now()
+	updatedNames := sets.NewString()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for name, addresses := range f.dnsCache {
+		for ip, expiration := range addresses {
+			if expiration.Before(now) {
+				delete(addresses, ip)
+				updatedNames.Insert(name)
+			}
+		}
+		if len(addresses) == 0 {
+			delete(f.dnsCache, name)
+		}
+	}
+	for ruleID := range f.affectedRulesLocked(updatedNames) {
+		f.dirtyRuleHandler(ruleID)
+	}
+}
+
+// handlePacketIn handles the packet-in messages sent by the DNS response flow. Other packet-in messages are ignored.
+func (f *fqdnController) handlePacketIn(pktIn *ofctrl.PacketIn) error {
+	if binding.TableIDType(pktIn.TableId) != openflow.L2ForwardingOutTable {
+		return nil
+	}
+	if pktIn.GetMatches().GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", openflow.TraceflowReg)) != nil {
+		return nil
+	}
+	if pktIn.Data.Ethertype != protocol.IPv4_MSG {
+		return nil
+	}
+	ipPacket, ok := pktIn.Data.Data.(*protocol.IPv4)
+	if !ok {
+		return fmt.Errorf("invalid IPv4 packet")
+	}
+	udpPacket, ok := ipPacket.Data.(*protocol.UDP)
+	if !ok || udpPacket.PortSrc != dnsPort {
+		return nil
+	}
+	if !f.isClusterDNSServer(ipPacket.NWSrc) {
+		klog.V(2).Infof("Ignoring DNS response from %s which is not the cluster DNS server", ipPacket.NWSrc)
+		return nil
+	}
+	var msg dnsmessage.Message
+	if err := msg.Unpack(udpPacket.Data); err != nil {
+		return fmt.Errorf("invalid DNS message: %v", err)
+	}
+	if !msg.Response || msg.RCode != dnsmessage.RCodeSuccess {
+		return nil
+	}
+	klog.V(4).Infof("Received DNS response for %v with %d answers", msg.Questions, len(msg.Answers))
+	f.onDNSResponse(&msg)
+	return nil
+}
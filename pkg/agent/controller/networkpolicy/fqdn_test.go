@@ -0,0 +1,247 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+)
+
+func TestFQDNMatches(t *testing.T) {
+	tests := []struct {
+		fqdn     string
+		name     string
+		expected bool
+	}{
+		{"www.example.com", "www.example.com", true},
+		{"www.example.com.", "www.example.com", true},
+		{"WWW.example.com", "www.example.com", true},
+		{"www.example.com", "api.example.com", false},
+		{"*.example.com", "www.example.com", true},
+		{"*.example.com", "a.b.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "www.myexample.com", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, fqdnMatches(tt.fqdn, tt.name), "FQDN %s, name %s", tt.fqdn, tt.name)
+	}
+}
+
+const (
+	testDNSServiceIP  = "10.96.0.10"
+	testDNSEndpointIP = "10.10.1.5"
+)
+
+func newTestFQDNController(t *testing.T) (*fqdnController, *openflowtest.MockClient, *clock.FakeClock, sets.String) {
+	ctrl := gomock.NewController(t)
+	ofClient := openflowtest.NewMockClient(ctrl)
+	dirtyRules := sets.NewString()
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	objectMeta := metav1.ObjectMeta{Namespace: dnsServiceNamespace, Name: dnsServiceName}
+	require.NoError(t, serviceIndexer.Add(&corev1.Service{ObjectMeta: objectMeta, Spec: corev1.ServiceSpec{ClusterIP: testDNSServiceIP}}))
+	require.NoError(t, endpointsIndexer.Add(&corev1.Endpoints{
+		ObjectMeta: objectMeta,
+		Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: testDNSEndpointIP}}}},
+	}))
+	f := newFQDNController(ofClient, func(ruleID string) {
+		dirtyRules.Insert(ruleID)
+	}, corelisters.NewServiceLister(serviceIndexer), corelisters.NewEndpointsLister(endpointsIndexer))
+	fakeClock := clock.NewFakeClock(time.Now())
+	f.clock = fakeClock
+	return f, ofClient, fakeClock, dirtyRules
+}
+
+func newDNSResponse(t *testing.T, question string, answers ...dnsmessage.Resource) []byte {
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{Response: true, RCode: dnsmessage.RCodeSuccess},
+		Questions: []dnsmessage.Question{{Name: dnsmessage.MustNewName(question), Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+		Answers:   answers,
+	}
+	b, err := msg.Pack()
+	require.NoError(t, err)
+	return b
+}
+
+func newAResource(name string, ip string, ttl uint32) dnsmessage.Resource {
+	var a [4]byte
+	copy(a[:], net.ParseIP(ip).To4())
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: ttl},
+		Body:   &dnsmessage.AResource{A: a},
+	}
+}
+
+func newCNAMEResource(name string, target string, ttl uint32) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: ttl},
+		Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(target)},
+	}
+}
+
+func newDNSPacketIn(tableID uint8, srcIP string, srcPort uint16, payload []byte) *ofctrl.PacketIn {
+	pktIn := &ofctrl.PacketIn{
+		TableId: tableID,
+		Match:   openflow13.Match{},
+	}
+	pktIn.Data = protocol.Ethernet{
+		Ethertype: protocol.IPv4_MSG,
+		Data: &protocol.IPv4{
+			Protocol: protocol.Type_UDP,
+			NWSrc:    net.ParseIP(srcIP),
+			NWDst:    net.ParseIP("10.10.0.2"),
+			Data:     &protocol.UDP{PortSrc: srcPort, PortDst: 40000, Data: payload},
+		},
+	}
+	return pktIn
+}
+
+func fqdnGroupMembers(ips ...string) v1beta1.GroupMemberSet {
+	members := v1beta1.NewGroupMemberSet()
+	for _, ip := range ips {
+		members.Insert(&v1beta1.GroupMember{Endpoints: []v1beta1.Endpoint{{IP: v1beta1.IPAddress(net.ParseIP(ip))}}})
+	}
+	return members
+}
+
+func TestFQDNControllerRules(t *testing.T) {
+	f, ofClient, _, dirtyRules := newTestFQDNController(t)
+	// The DNS response flows are installed only once.
+	ofClient.EXPECT().InstallDNSResponseFlows().Times(1)
+
+	require.NoError(t, f.setRuleFQDNs("rule1", nil))
+	assert.Empty(t, f.fqdnRules)
+	require.NoError(t, f.setRuleFQDNs("rule1", []string{"*.example.com"}))
+	require.NoError(t, f.setRuleFQDNs("rule2", []string{"www.example.com", "www.test.com"}))
+	assert.Equal(t, map[string]sets.String{
+		"*.example.com":   sets.NewString("rule1"),
+		"www.example.com": sets.NewString("rule2"),
+		"www.test.com":    sets.NewString("rule2"),
+	}, f.fqdnRules)
+
+	var msg dnsmessage.Message
+	require.NoError(t, msg.Unpack(newDNSResponse(t, "api.example.com.", newAResource("api.example.com.", "1.1.1.1", 60))))
+	f.onDNSResponse(&msg)
+	assert.Equal(t, sets.NewString("rule1"), dirtyRules)
+	assert.Equal(t, fqdnGroupMembers("1.1.1.1"), f.getGroupMembers([]string{"*.example.com"}))
+	assert.Empty(t, f.getGroupMembers([]string{"www.example.com"}))
+
+	// The addresses of the CNAME targets are also associated with the name of the question.
+	dirtyRules.Delete(dirtyRules.List()...)
+	require.NoError(t, msg.Unpack(newDNSResponse(t, "www.test.com.",
+		newCNAMEResource("www.test.com.", "cdn.test.net.", 60),
+		newAResource("cdn.test.net.", "2.2.2.2", 60))))
+	f.onDNSResponse(&msg)
+	assert.Equal(t, sets.NewString("rule2"), dirtyRules)
+	assert.Equal(t, fqdnGroupMembers("2.2.2.2"), f.getGroupMembers([]string{"www.example.com", "www.test.com"}))
+
+	f.deleteRule("rule2")
+	assert.Equal(t, map[string]sets.String{"*.example.com": sets.NewString("rule1")}, f.fqdnRules)
+	f.deleteRule("rule1")
+	assert.Empty(t, f.fqdnRules)
+	assert.Empty(t, f.ruleFQDNs)
+}
+
+func TestFQDNControllerRemoveExpiredAddresses(t *testing.T) {
+	f, ofClient, fakeClock, dirtyRules := newTestFQDNController(t)
+	ofClient.EXPECT().InstallDNSResponseFlows().Times(1)
+	require.NoError(t, f.setRuleFQDNs("rule1", []string{"*.example.com"}))
+
+	var msg dnsmessage.Message
+	require.NoError(t, msg.Unpack(newDNSResponse(t, "www.example.com.",
+		newAResource("www.example.com.", "1.1.1.1", 30),
+		newAResource("www.example.com.", "1.1.1.2", 120))))
+	f.onDNSResponse(&msg)
+	assert.Equal(t, fqdnGroupMembers("1.1.1.1", "1.1.1.2"), f.getGroupMembers([]string{"*.example.com"}))
+
+	// Refreshing known addresses doesn't mark the rules as dirty.
+	dirtyRules.Delete(dirtyRules.List()...)
+	f.onDNSResponse(&msg)
+	assert.Empty(t, dirtyRules)
+
+	fakeClock.Step(time.Minute)
+	assert.Equal(t, fqdnGroupMembers("1.1.1.2"), f.getGroupMembers([]string{"*.example.com"}))
+	f.removeExpiredAddresses()
+	assert.Equal(t, sets.NewString("rule1"), dirtyRules)
+	assert.Len(t, f.dnsCache["www.example.com"], 1)
+
+	fakeClock.Step(2 * time.Minute)
+	f.removeExpiredAddresses()
+	assert.Empty(t, f.dnsCache)
+}
+
+func TestFQDNControllerHandlePacketIn(t *testing.T) {
+	f, ofClient, _, dirtyRules := newTestFQDNController(t)
+	ofClient.EXPECT().InstallDNSResponseFlows().Times(1)
+	require.NoError(t, f.setRuleFQDNs("rule1", []string{"www.example.com"}))
+	response := newDNSResponse(t, "www.example.com.", newAResource("www.example.com.", "1.1.1.1", 60))
+
+	// Packets sent from other tables or ports are ignored.
+	require.NoError(t, f.handlePacketIn(newDNSPacketIn(uint8(openflow.IngressDefaultTable), testDNSServiceIP, dnsPort, response)))
+	require.NoError(t, f.handlePacketIn(newDNSPacketIn(uint8(openflow.L2ForwardingOutTable), testDNSServiceIP, 5353, response)))
+	// Responses which are not sent by the cluster DNS server are ignored.
+	require.NoError(t, f.handlePacketIn(newDNSPacketIn(uint8(openflow.L2ForwardingOutTable), "10.10.0.3", dnsPort, response)))
+	assert.Empty(t, f.dnsCache)
+
+	assert.Error(t, f.handlePacketIn(newDNSPacketIn(uint8(openflow.L2ForwardingOutTable), testDNSServiceIP, dnsPort, []byte{0x1})))
+
+	require.NoError(t, f.handlePacketIn(newDNSPacketIn(uint8(openflow.L2ForwardingOutTable), testDNSServiceIP, dnsPort, response)))
+	assert.Equal(t, sets.NewString("rule1"), dirtyRules)
+	assert.Equal(t, fqdnGroupMembers("1.1.1.1"), f.getGroupMembers([]string{"www.example.com"}))
+
+	// The responses sent by the Endpoints of the cluster DNS server are also processed.
+	response = newDNSResponse(t, "www.example.com.", newAResource("www.example.com.", "1.1.1.2", 60))
+	require.NoError(t, f.handlePacketIn(newDNSPacketIn(uint8(openflow.L2ForwardingOutTable), testDNSEndpointIP, dnsPort, response)))
+	assert.Equal(t, fqdnGroupMembers("1.1.1.1", "1.1.1.2"), f.getGroupMembers([]string{"www.example.com"}))
+}
+
+func TestFQDNControllerOnlyCachesMatchedNames(t *testing.T) {
+	f, ofClient, _, dirtyRules := newTestFQDNController(t)
+	ofClient.EXPECT().InstallDNSResponseFlows().Times(1)
+	require.NoError(t, f.setRuleFQDNs("rule1", []string{"*.example.com"}))
+
+	var msg dnsmessage.Message
+	require.NoError(t, msg.Unpack(newDNSResponse(t, "www.test.com.", newAResource("www.test.com.", "1.1.1.1", 60))))
+	f.onDNSResponse(&msg)
+	assert.Empty(t, f.dnsCache)
+	assert.Empty(t, dirtyRules)
+
+	// Only the names matched by a rule are cached when the response contains other names.
+	require.NoError(t, msg.Unpack(newDNSResponse(t, "www.example.com.",
+		newCNAMEResource("www.example.com.", "cdn.test.net.", 60),
+		newAResource("cdn.test.net.", "2.2.2.2", 60))))
+	f.onDNSResponse(&msg)
+	assert.Equal(t, []string{"www.example.com"}, sets.StringKeySet(f.dnsCache).List())
+	assert.Equal(t, sets.NewString("rule1"), dirtyRules)
+}
@@ -0,0 +1,317 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/iptables"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/util/ip"
+)
+
+const (
+	// hostIngressChain holds the rules enforced on the traffic to the host
+	// network of the Node. It's jumped to from the INPUT chain.
+	hostIngressChain = "ANTREA-HOST-INGRESS"
+	// hostEgressChain holds the rules enforced on the traffic from the host
+	// network of the Node. It's jumped to from the OUTPUT chain.
+	hostEgressChain = "ANTREA-HOST-EGRESS"
+)
+
+// hostIPTables is the subset of the iptables client used by hostReconciler.
+type hostIPTables interface {
+	EnsureChain(table string, chain string) error
+	InsertRule(table string, chain string, ruleSpec []string) error
+	Restore(data []byte, flush bool) error
+}
+
+// hostReconciler implements Reconciler for the rules applied to the local
+// Node, i.e. the rules of Antrea ClusterNetworkPolicies whose AppliedTo selects
+// Nodes. Unlike the rules applied to Pods, they are enforced with iptables in
+// the host network namespace, as the host traffic doesn't go through the OVS
+// bridge. The rules are kept in priority order in a chain per direction, which
+// is rewritten entirely every time a rule changes.
+type hostReconciler struct {
+	mutex sync.Mutex
+	// ipt is created lazily when the rules are synced for the first time.
+	ipt hostIPTables
+	// rules are the rules currently applied to the local Node, keyed by rule
+	// ID.
+	rules map[string]*CompletedRule
+	// synced is false until the chains have been written once, so that the
+	// rules left by a previous run of the Agent are removed even if no rule
+	// is applied to the Node anymore.
+	synced bool
+}
+
+func newHostReconciler() Reconciler {
+	return &hostReconciler{rules: map[string]*CompletedRule{}}
+}
+
+// Reconcile installs the iptables rules of the provided rule if it applies to
+// the local Node, and removes them otherwise.
+func (r *hostReconciler) Reconcile(rule *CompletedRule) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(rule.Nodes) == 0 {
+		if _, exists := r.rules[rule.ID]; !exists {
+			return nil
+		}
+		delete(r.rules, rule.ID)
+	} else {
+		r.rules[rule.ID] = rule
+	}
+	return r.syncLocked()
+}
+
+// BatchReconcile installs the iptables rules of the provided rules which apply
+// to the local Node. It always writes the chains, so that the stale rules of a
+// previous run of the Agent are removed.
+func (r *hostReconciler) BatchReconcile(rules []*CompletedRule) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, rule := range rules {
+		if len(rule.Nodes) > 0 {
+			r.rules[rule.ID] = rule
+		}
+	}
+	return r.syncLocked()
+}
+
+// Forget removes the iptables rules of the provided rule if it applied to the
+// local Node.
+func (r *hostReconciler) Forget(ruleID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.rules[ruleID]; !exists {
+		return nil
+	}
+	delete(r.rules, ruleID)
+	return r.syncLocked()
+}
+
+// initLocked creates the Antrea managed chains and links them to the built-in
+// chains. The jump rules are inserted at the top of the built-in chains, so
+// that the rules are enforced before the rules accepting traffic added by
+// other components.
+func (r *hostReconciler) initLocked() error {
+	if r.ipt == nil {
+		ipt, err := iptables.New()
+		if err != nil {
+			return err
+		}
+		r.ipt = ipt
+	}
+	jumpRules := []struct{ srcChain, dstChain, comment string }{
+		{iptables.InputChain, hostIngressChain, "Antrea: jump to Antrea host ingress rules"},
+		{iptables.OutputChain, hostEgressChain, "Antrea: jump to Antrea host egress rules"},
+	}
+	for _, rule := range jumpRules {
+		if err := r.ipt.EnsureChain(iptables.FilterTable, rule.dstChain); err != nil {
+			return err
+		}
+		ruleSpec := []string{"-j", rule.dstChain, "-m", "comment", "--comment", rule.comment}
+		if err := r.ipt.InsertRule(iptables.FilterTable, rule.srcChain, ruleSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *hostReconciler) syncLocked() error {
+	if !r.synced {
+		if err := r.initLocked(); err != nil {
+			return fmt.Errorf("error initializing host iptables chains: %v", err)
+		}
+	}
+	// Setting --noflush to keep the previous contents (i.e. non antrea
+	// managed chains) of the table. The Antrea managed chains are flushed
+	// as they are declared in the data.
+	if err := r.ipt.Restore(r.buildIPTablesData(), false); err != nil {
+		return err
+	}
+	r.synced = true
+	return nil
+}
+
+// buildIPTablesData returns the iptables-restore data of the Antrea managed
+// host chains.
+func (r *hostReconciler) buildIPTablesData() []byte {
+	var ingressRules, egressRules []*CompletedRule
+	for _, rule := range r.rules {
+		if rule.Direction == v1beta1.DirectionIn {
+			ingressRules = append(ingressRules, rule)
+		} else {
+			egressRules = append(egressRules, rule)
+		}
+	}
+	data := bytes.NewBuffer(nil)
+	data.WriteString("*filter\n")
+	for _, chain := range []string{hostIngressChain, hostEgressChain} {
+		writeHostRule(data, iptables.MakeChainLine(chain))
+	}
+	writeHostChain(data, hostIngressChain, "-i", "-s", ingressRules)
+	writeHostChain(data, hostEgressChain, "-o", "-d", egressRules)
+	data.WriteString("COMMIT\n")
+	return data.Bytes()
+}
+
+// writeHostChain writes the iptables rules of the provided rules in the chain.
+// Loopback traffic and the packets of established connections are never
+// matched, like the rules applied to Pods only match new connections.
+func writeHostChain(data *bytes.Buffer, chain, ifaceFlag, addrFlag string, rules []*CompletedRule) {
+	writeHostRule(data, "-A", chain, "-m", "comment", "--comment", `"Antrea: skip loopback traffic"`,
+		ifaceFlag, "lo", "-j", iptables.ReturnTarget)
+	writeHostRule(data, "-A", chain, "-m", "comment", "--comment", `"Antrea: skip established connections"`,
+		"-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED", "-j", iptables.ReturnTarget)
+	sortHostRules(rules)
+	for _, rule := range rules {
+		target := []string{"-j", iptables.AcceptTarget}
+		if rule.Action != nil {
+			switch *rule.Action {
+			case secv1alpha1.RuleActionDrop:
+				target = []string{"-j", iptables.DropTarget}
+			case secv1alpha1.RuleActionAudit:
+				// A rule without target only counts the matching packets, which
+				// continue to the next rules.
+				target = nil
+			}
+		}
+		comment := fmt.Sprintf(`"Antrea: %s rule %s"`, rule.SourceRef.ToString(), rule.ID)
+		addresses, matchAny := hostRuleAddresses(rule)
+		for _, service := range hostRuleServices(rule.Services) {
+			if matchAny {
+				writeHostRule(data, append(append([]string{"-A", chain, "-m", "comment", "--comment", comment}, service...), target...)...)
+				continue
+			}
+			for _, address := range addresses {
+				writeHostRule(data, append(append([]string{"-A", chain, "-m", "comment", "--comment", comment}, service...), append([]string{addrFlag, address}, target...)...)...)
+			}
+		}
+	}
+}
+
+func writeHostRule(data *bytes.Buffer, words ...string) {
+	data.WriteString(strings.Join(words, " "))
+	data.WriteByte('\n')
+}
+
+// sortHostRules sorts the rules in the order they are evaluated: by Tier
+// priority, then by policy priority, then by rule priority. The rule ID breaks
+// ties to keep the generated data stable.
+func sortHostRules(rules []*CompletedRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		ri, rj := rules[i], rules[j]
+		if ri.TierPriority != nil && rj.TierPriority != nil && *ri.TierPriority != *rj.TierPriority {
+			return *ri.TierPriority < *rj.TierPriority
+		}
+		if ri.PolicyPriority != nil && rj.PolicyPriority != nil && *ri.PolicyPriority != *rj.PolicyPriority {
+			return *ri.PolicyPriority < *rj.PolicyPriority
+		}
+		if ri.Priority != rj.Priority {
+			return ri.Priority < rj.Priority
+		}
+		return ri.ID < rj.ID
+	})
+}
+
+// hostRuleAddresses returns the IPv4 addresses and CIDRs of the peers of the
+// rule. The bool is true if the rule has no peer, in which case it matches any
+// address.
+func hostRuleAddresses(rule *CompletedRule) ([]string, bool) {
+	peer, members := rule.From, rule.FromAddresses
+	if rule.Direction == v1beta1.DirectionOut {
+		peer, members = rule.To, rule.ToAddresses
+	}
+	if len(peer.AddressGroups) == 0 && len(peer.IPBlocks) == 0 && len(peer.FQDNs) == 0 {
+		return nil, true
+	}
+	var addresses []string
+	for _, member := range members {
+		for _, ep := range member.Endpoints {
+			// The host rules are only installed with iptables for now.
+			if ipv4 := net.IP(ep.IP).To4(); ipv4 != nil {
+				addresses = append(addresses, ipv4.String())
+			}
+		}
+	}
+	for _, b := range peer.IPBlocks {
+		exceptIPNet := make([]*net.IPNet, 0, len(b.Except))
+		for i := range b.Except {
+			exceptIPNet = append(exceptIPNet, ip.IPNetToNetIPNet(&b.Except[i]))
+		}
+		diffCIDRs, err := ip.DiffFromCIDRs(ip.IPNetToNetIPNet(&b.CIDR), exceptIPNet)
+		if err != nil {
+			klog.Errorf("Error when determining diffCIDRs: %v", err)
+			continue
+		}
+		for _, d := range diffCIDRs {
+			addresses = append(addresses, d.String())
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, false
+}
+
+// hostRuleServices returns the iptables match arguments of the services of the
+// rule. A rule without services matches any protocol and port. Services with
+// named ports are skipped, as the host network has no named port. ICMP services
+// match the ICMP type and code if they are set, and IP services match the
+// protocol by its number.
+func hostRuleServices(services []v1beta1.Service) [][]string {
+	if len(services) == 0 {
+		return [][]string{nil}
+	}
+	var matches [][]string
+	for _, service := range services {
+		protocol := v1beta1.ProtocolTCP
+		if service.Protocol != nil {
+			protocol = *service.Protocol
+		}
+		match := []string{"-p", strings.ToLower(string(protocol))}
+		if protocol == v1beta1.ProtocolIP && service.IPProtocol != nil {
+			match = []string{"-p", strconv.Itoa(int(*service.IPProtocol))}
+		}
+		if service.Port != nil {
+			if service.Port.Type == intstr.String {
+				continue
+			}
+			match = append(match, "--dport", strconv.Itoa(int(service.Port.IntVal)))
+		}
+		if protocol == v1beta1.ProtocolICMP && service.ICMPType != nil {
+			icmpType := strconv.Itoa(int(*service.ICMPType))
+			if service.ICMPCode != nil {
+				icmpType += "/" + strconv.Itoa(int(*service.ICMPCode))
+			}
+			match = append(match, "--icmp-type", icmpType)
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
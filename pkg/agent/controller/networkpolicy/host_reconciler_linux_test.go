@@ -0,0 +1,166 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+type fakeHostIPTables struct {
+	chains    []string
+	jumpRules map[string][]string
+	data      string
+}
+
+func (f *fakeHostIPTables) EnsureChain(table string, chain string) error {
+	f.chains = append(f.chains, table+"/"+chain)
+	return nil
+}
+
+func (f *fakeHostIPTables) InsertRule(table string, chain string, ruleSpec []string) error {
+	f.jumpRules[table+"/"+chain] = ruleSpec
+	return nil
+}
+
+func (f *fakeHostIPTables) Restore(data []byte, flush bool) error {
+	f.data = string(data)
+	return nil
+}
+
+func newTestHostReconciler() (*hostReconciler, *fakeHostIPTables) {
+	ipt := &fakeHostIPTables{jumpRules: map[string][]string{}}
+	return &hostReconciler{ipt: ipt, rules: map[string]*CompletedRule{}}, ipt
+}
+
+func newHostRule(id string, direction v1beta1.Direction, action secv1alpha1.RuleAction, policyPriority float64) *CompletedRule {
+	tierPriority := int32(250)
+	return &CompletedRule{
+		rule: &rule{
+			ID:             id,
+			Direction:      direction,
+			Action:         &action,
+			PolicyPriority: &policyPriority,
+			TierPriority:   &tierPriority,
+			SourceRef:      &v1beta1.NetworkPolicyReference{Type: v1beta1.AntreaClusterNetworkPolicy, Name: "host-policy"},
+		},
+		Nodes: v1beta1.NewGroupMemberSet(&v1beta1.GroupMember{Node: &v1beta1.NodeReference{Name: "node1"}}),
+	}
+}
+
+func TestHostReconcilerBatchReconcile(t *testing.T) {
+	r, ipt := newTestHostReconciler()
+	// The chains are written even if no rule is applied to the Node, to
+	// remove the stale rules of a previous run.
+	require.NoError(t, r.BatchReconcile(nil))
+	assert.Equal(t, []string{"filter/ANTREA-HOST-INGRESS", "filter/ANTREA-HOST-EGRESS"}, ipt.chains)
+	assert.Equal(t, []string{"-j", "ANTREA-HOST-INGRESS", "-m", "comment", "--comment", "Antrea: jump to Antrea host ingress rules"}, ipt.jumpRules["filter/INPUT"])
+	assert.Equal(t, []string{"-j", "ANTREA-HOST-EGRESS", "-m", "comment", "--comment", "Antrea: jump to Antrea host egress rules"}, ipt.jumpRules["filter/OUTPUT"])
+	assert.Equal(t, `*filter
+:ANTREA-HOST-INGRESS - [0:0]
+:ANTREA-HOST-EGRESS - [0:0]
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: skip loopback traffic" -i lo -j RETURN
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: skip established connections" -m conntrack --ctstate ESTABLISHED,RELATED -j RETURN
+-A ANTREA-HOST-EGRESS -m comment --comment "Antrea: skip loopback traffic" -o lo -j RETURN
+-A ANTREA-HOST-EGRESS -m comment --comment "Antrea: skip established connections" -m conntrack --ctstate ESTABLISHED,RELATED -j RETURN
+COMMIT
+`, ipt.data)
+}
+
+func TestHostReconcilerReconcile(t *testing.T) {
+	r, ipt := newTestHostReconciler()
+	protocolUDP := v1beta1.ProtocolUDP
+	protocolICMP := v1beta1.ProtocolICMP
+	icmpEchoRequest := int32(8)
+	icmpCode := int32(0)
+	protocolIP := v1beta1.ProtocolIP
+	ipProtocolVRRP := int32(112)
+	port22 := intstr.FromInt(22)
+	port53 := intstr.FromInt(53)
+	namedPort := intstr.FromString("http")
+
+	// Allow SSH from 10.0.0.0/24, except 10.0.0.128/25, and from the Pods of
+	// the AddressGroup.
+	allowSSH := newHostRule("allow-ssh", v1beta1.DirectionIn, secv1alpha1.RuleActionAllow, 1)
+	allowSSH.From = v1beta1.NetworkPolicyPeer{
+		AddressGroups: []string{"group1"},
+		IPBlocks: []v1beta1.IPBlock{{
+			CIDR:   v1beta1.IPNet{IP: v1beta1.IPAddress(net.ParseIP("10.0.0.0")), PrefixLength: 24},
+			Except: []v1beta1.IPNet{{IP: v1beta1.IPAddress(net.ParseIP("10.0.0.128")), PrefixLength: 25}},
+		}},
+	}
+	allowSSH.FromAddresses = v1beta1.NewGroupMemberSet(&v1beta1.GroupMember{
+		Pod:       &v1beta1.PodReference{Name: "pod1", Namespace: "ns1"},
+		Endpoints: []v1beta1.Endpoint{{IP: v1beta1.IPAddress(net.ParseIP("192.168.1.2"))}},
+	})
+	allowSSH.Services = []v1beta1.Service{{Port: &port22}, {Port: &namedPort}}
+	// Audit the ingress connections from 10.0.1.0/24.
+	auditSubnet := newHostRule("audit-subnet", v1beta1.DirectionIn, secv1alpha1.RuleActionAudit, 0)
+	auditSubnet.From = v1beta1.NetworkPolicyPeer{
+		IPBlocks: []v1beta1.IPBlock{{CIDR: v1beta1.IPNet{IP: v1beta1.IPAddress(net.ParseIP("10.0.1.0")), PrefixLength: 24}}},
+	}
+	// Allow ping from any address.
+	allowPing := newHostRule("allow-ping", v1beta1.DirectionIn, secv1alpha1.RuleActionAllow, 2)
+	allowPing.Services = []v1beta1.Service{{Protocol: &protocolICMP, ICMPType: &icmpEchoRequest, ICMPCode: &icmpCode}}
+	// Allow VRRP advertisements from any address.
+	allowVRRP := newHostRule("allow-vrrp", v1beta1.DirectionIn, secv1alpha1.RuleActionAllow, 3)
+	allowVRRP.Services = []v1beta1.Service{{Protocol: &protocolIP, IPProtocol: &ipProtocolVRRP}}
+	// Drop all other ingress traffic, with a lower priority policy.
+	dropAll := newHostRule("drop-all", v1beta1.DirectionIn, secv1alpha1.RuleActionDrop, 10)
+	// Allow DNS queries from the host network.
+	allowDNS := newHostRule("allow-dns", v1beta1.DirectionOut, secv1alpha1.RuleActionAllow, 1)
+	allowDNS.Services = []v1beta1.Service{{Protocol: &protocolUDP, Port: &port53}}
+	// The rules which don't apply to the Node are ignored.
+	podRule := newHostRule("pod-rule", v1beta1.DirectionIn, secv1alpha1.RuleActionDrop, 1)
+	podRule.Nodes = nil
+
+	for _, rule := range []*CompletedRule{dropAll, allowDNS, allowSSH, allowPing, allowVRRP, auditSubnet, podRule} {
+		require.NoError(t, r.Reconcile(rule))
+	}
+	assert.Equal(t, `*filter
+:ANTREA-HOST-INGRESS - [0:0]
+:ANTREA-HOST-EGRESS - [0:0]
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: skip loopback traffic" -i lo -j RETURN
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: skip established connections" -m conntrack --ctstate ESTABLISHED,RELATED -j RETURN
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: AntreaClusterNetworkPolicy:host-policy rule audit-subnet" -s 10.0.1.0/24
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: AntreaClusterNetworkPolicy:host-policy rule allow-ssh" -p tcp --dport 22 -s 10.0.0.0/25 -j ACCEPT
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: AntreaClusterNetworkPolicy:host-policy rule allow-ssh" -p tcp --dport 22 -s 192.168.1.2 -j ACCEPT
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: AntreaClusterNetworkPolicy:host-policy rule allow-ping" -p icmp --icmp-type 8/0 -j ACCEPT
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: AntreaClusterNetworkPolicy:host-policy rule allow-vrrp" -p 112 -j ACCEPT
+-A ANTREA-HOST-INGRESS -m comment --comment "Antrea: AntreaClusterNetworkPolicy:host-policy rule drop-all" -j DROP
+-A ANTREA-HOST-EGRESS -m comment --comment "Antrea: skip loopback traffic" -o lo -j RETURN
+-A ANTREA-HOST-EGRESS -m comment --comment "Antrea: skip established connections" -m conntrack --ctstate ESTABLISHED,RELATED -j RETURN
+-A ANTREA-HOST-EGRESS -m comment --comment "Antrea: AntreaClusterNetworkPolicy:host-policy rule allow-dns" -p udp --dport 53 -j ACCEPT
+COMMIT
+`, ipt.data)
+
+	// The rules are removed when they no longer apply to the Node.
+	allowSSH.Nodes = nil
+	require.NoError(t, r.Reconcile(allowSSH))
+	require.NoError(t, r.Forget("allow-ping"))
+	require.NoError(t, r.Forget("allow-vrrp"))
+	require.NoError(t, r.Forget("audit-subnet"))
+	require.NoError(t, r.Forget("drop-all"))
+	require.NoError(t, r.Forget("allow-dns"))
+	assert.Empty(t, r.rules)
+	assert.NotContains(t, ipt.data, "host-policy")
+}
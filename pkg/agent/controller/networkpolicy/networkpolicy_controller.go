@@ -20,15 +20,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/contiv/ofnet/ofctrl"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent"
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
 	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
@@ -72,32 +76,78 @@ type Controller struct {
 	// reconciler provides interfaces to reconcile the desired state of
 	// NetworkPolicy rules with the actual state of Openflow entries.
 	reconciler Reconciler
+	// hostReconciler reconciles the desired state of the NetworkPolicy rules
+	// applied to the local Node with the actual state of the iptables rules
+	// protecting its host network. It is nil if AntreaPolicy is not enabled or
+	// the Node is not a Linux Node.
+	hostReconciler Reconciler
+	// fqdnController learns the IP addresses of the FQDNs referenced by the
+	// egress rules of Antrea-native policies. It is nil if AntreaPolicy is
+	// not enabled.
+	fqdnController *fqdnController
+	// auditLogger writes the audit log entries of the connections matched by
+	// the Antrea-native policy rules with logging enabled. It is nil if
+	// AntreaPolicy is not enabled.
+	auditLogger *auditLogger
+	// statusManager reports the realization status of the Antrea-native
+	// policies to the antrea-controller. It is nil if AntreaPolicy is not
+	// enabled.
+	statusManager *statusController
+	// eventRecorder reports the failures to realize the rules as Events on
+	// their original NetworkPolicies.
+	eventRecorder *events.Recorder
 
 	networkPolicyWatcher  *watcher
 	appliedToGroupWatcher *watcher
 	addressGroupWatcher   *watcher
 	fullSyncGroup         sync.WaitGroup
+	// flowRestoreCompleteWait is notified once the flows of the initial NetworkPolicies have been installed.
+	flowRestoreCompleteWait *sync.WaitGroup
 }
 
-// NewNetworkPolicyController returns a new *Controller.
+// NewNetworkPolicyController returns a new *Controller. auditLogConfig,
+// dnsServiceInformer and dnsEndpointsInformer are ignored if
+// antreaPolicyEnabled is false. dnsServiceInformer and dnsEndpointsInformer
+// must provide the Service and the Endpoints of the cluster DNS server.
 func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 	ofClient openflow.Client,
 	ifaceStore interfacestore.InterfaceStore,
 	nodeName string,
 	podUpdates <-chan v1beta1.PodReference,
-	antreaPolicyEnabled bool) *Controller {
+	antreaPolicyEnabled bool,
+	auditLogConfig *AuditLogConfig,
+	eventRecorder *events.Recorder,
+	dnsServiceInformer coreinformers.ServiceInformer,
+	dnsEndpointsInformer coreinformers.EndpointsInformer,
+	flowRestoreCompleteWait *sync.WaitGroup) (*Controller, error) {
+	reconciler := newReconciler(ofClient, ifaceStore)
 	c := &Controller{
-		antreaClientProvider: antreaClientGetter,
-		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicyrule"),
-		reconciler:           newReconciler(ofClient, ifaceStore),
-		antreaPolicyEnabled:  antreaPolicyEnabled,
+		antreaClientProvider:    antreaClientGetter,
+		queue:                   workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicyrule"),
+		reconciler:              reconciler,
+		antreaPolicyEnabled:     antreaPolicyEnabled,
+		eventRecorder:           eventRecorder,
+		flowRestoreCompleteWait: flowRestoreCompleteWait,
 	}
 	c.ruleCache = newRuleCache(c.enqueueRule, podUpdates)
+	if antreaPolicyEnabled {
+		c.hostReconciler = newHostReconciler()
+		c.fqdnController = newFQDNController(ofClient, c.enqueueRule, dnsServiceInformer.Lister(), dnsEndpointsInformer.Lister())
+		c.statusManager = newStatusController(antreaClientGetter, nodeName, c.ruleCache)
+		if auditLogConfig != nil {
+			var err error
+			if c.auditLogger, err = newAuditLogger(ofClient, auditLogConfig); err != nil {
+				return nil, fmt.Errorf("error creating audit logger: %v", err)
+			}
+			reconciler.ofRuleUninstalledHandler = c.auditLogger.forgetRule
+		}
+	}
 	// Create a WaitGroup that is used to block network policy workers from asynchronously processing
 	// NP rules until the events preceding bookmark are synced. It can also be used as part of the
 	// solution to a deterministic mechanism for when to cleanup flows from previous round.
 	// Wait until appliedToGroupWatcher, addressGroupWatcher and networkPolicyWatcher to receive bookmark event.
 	c.fullSyncGroup.Add(3)
+	c.flowRestoreCompleteWait.Add(1)
 
 	// Use nodeName to filter resources when watching resources.
 	options := metav1.ListOptions{
@@ -124,6 +174,9 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 				return nil
 			}
 			c.ruleCache.AddNetworkPolicy(policy)
+			if c.statusManager != nil {
+				c.statusManager.enqueuePolicy(policy.UID)
+			}
 			klog.Infof("NetworkPolicy %s applied to Pods on this Node", policy.SourceRef.ToString())
 			return nil
 		},
@@ -138,6 +191,9 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 				return nil
 			}
 			c.ruleCache.UpdateNetworkPolicy(policy)
+			if c.statusManager != nil {
+				c.statusManager.enqueuePolicy(policy.UID)
+			}
 			return nil
 		},
 		DeleteFunc: func(obj runtime.Object) error {
@@ -151,6 +207,9 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 				return nil
 			}
 			c.ruleCache.DeleteNetworkPolicy(policy)
+			if c.statusManager != nil {
+				c.statusManager.enqueuePolicy(policy.UID)
+			}
 			klog.Infof("NetworkPolicy %s no longer applied to Pods on this Node", policy.SourceRef.ToString())
 			return nil
 		},
@@ -170,6 +229,16 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 				klog.Infof("NetworkPolicy %s applied to Pods on this Node", policies[i].SourceRef.ToString())
 			}
 			c.ruleCache.ReplaceNetworkPolicies(policies)
+			// The watcher restarts when the connection to the antrea-controller
+			// is lost, for example when it restarts and loses the statuses,
+			// so they are all reported again.
+			if c.statusManager != nil {
+				uids := make([]types.UID, len(policies))
+				for i := range policies {
+					uids[i] = policies[i].UID
+				}
+				c.statusManager.resync(uids)
+			}
 			return nil
 		},
 		fullSyncWaitGroup: &c.fullSyncGroup,
@@ -273,7 +342,7 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 		fullSyncWaitGroup: &c.fullSyncGroup,
 		fullSynced:        false,
 	}
-	return c
+	return c, nil
 }
 
 func (c *Controller) GetNetworkPolicyNum() int {
@@ -352,6 +421,17 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	klog.Infof("All watchers have completed full sync, installing flows for init events")
 	// Batch install all rules in queue after fullSync is finished.
 	c.processAllItemsInQueue()
+	c.flowRestoreCompleteWait.Done()
+
+	if c.fqdnController != nil {
+		go wait.Until(c.fqdnController.removeExpiredAddresses, fqdnCacheGCInterval, stopCh)
+	}
+	if c.auditLogger != nil {
+		go c.auditLogger.run(stopCh)
+	}
+	if c.statusManager != nil {
+		go c.statusManager.Run(stopCh)
+	}
 
 	klog.Infof("Starting NetworkPolicy workers now")
 	for i := 0; i < defaultWorkers; i++ {
@@ -416,9 +496,20 @@ func (c *Controller) syncRule(key string) error {
 	rule, exists, completed := c.ruleCache.GetCompletedRule(key)
 	if !exists {
 		klog.V(2).Infof("Rule %v had been deleted, removing its flows", key)
+		if c.fqdnController != nil {
+			c.fqdnController.deleteRule(key)
+		}
 		if err := c.reconciler.Forget(key); err != nil {
 			return err
 		}
+		if c.hostReconciler != nil {
+			if err := c.hostReconciler.Forget(key); err != nil {
+				return err
+			}
+		}
+		if c.statusManager != nil {
+			c.statusManager.DeleteRuleRealization(key)
+		}
 		return nil
 	}
 	// If the rule is not complete, we can simply skip it as it will be marked as dirty
@@ -427,9 +518,35 @@ func (c *Controller) syncRule(key string) error {
 		klog.V(2).Infof("Rule %v was not complete, skipping", key)
 		return nil
 	}
+	if err := c.reconcileRule(rule); err != nil {
+		c.eventRecorder.NetworkPolicyFailure(rule.SourceRef, events.ReasonNetworkPolicyRealizationFailed, fmt.Sprintf("Failed to realize rule %s", key), err)
+		// The failure is reported to the antrea-controller so that it's
+		// visible in the status of the policy, and the rule is retried.
+		if c.statusManager != nil {
+			c.statusManager.SetRuleFailure(rule, err)
+		}
+		return err
+	}
+	if c.statusManager != nil {
+		c.statusManager.SetRuleRealization(key, rule.PolicyUID)
+	}
+	return nil
+}
+
+// reconcileRule realizes the rule in the OVS flows and, if it applies to the
+// Node, in the iptables rules.
+func (c *Controller) reconcileRule(rule *CompletedRule) error {
+	if err := c.addFQDNAddresses(rule); err != nil {
+		return err
+	}
 	if err := c.reconciler.Reconcile(rule); err != nil {
 		return err
 	}
+	if c.hostReconciler != nil {
+		if err := c.hostReconciler.Reconcile(rule); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -448,12 +565,56 @@ func (c *Controller) syncRules(keys []string) error {
 		if !exists || !completed {
 			klog.Errorf("Rule %s is not complete or does not exist in cache", key)
 		} else {
+			if err := c.addFQDNAddresses(rule); err != nil {
+				return err
+			}
 			allRules = append(allRules, rule)
 		}
 	}
 	if err := c.reconciler.BatchReconcile(allRules); err != nil {
 		return err
 	}
+	if c.hostReconciler != nil {
+		if err := c.hostReconciler.BatchReconcile(allRules); err != nil {
+			return err
+		}
+	}
+	if c.statusManager != nil {
+		for _, rule := range allRules {
+			c.statusManager.SetRuleRealization(rule.ID, rule.PolicyUID)
+		}
+	}
+	return nil
+}
+
+// addFQDNAddresses registers the FQDNs referenced by the rule and adds the IP
+// addresses learned for them to the ToAddresses of the rule.
+func (c *Controller) addFQDNAddresses(rule *CompletedRule) error {
+	if c.fqdnController == nil {
+		return nil
+	}
+	if err := c.fqdnController.setRuleFQDNs(rule.ID, rule.To.FQDNs); err != nil {
+		return err
+	}
+	if len(rule.To.FQDNs) > 0 {
+		rule.ToAddresses = rule.ToAddresses.Union(c.fqdnController.getGroupMembers(rule.To.FQDNs))
+	}
+	return nil
+}
+
+// HandlePacketIn handles the packet-in messages carrying the DNS responses
+// sent to local Pods, to learn the IP addresses of the FQDNs referenced by
+// Antrea-native policy rules, and the packet-in messages sent by the rules
+// with logging enabled, to write their audit log entries.
+func (c *Controller) HandlePacketIn(pktIn *ofctrl.PacketIn) error {
+	if c.fqdnController != nil {
+		if err := c.fqdnController.handlePacketIn(pktIn); err != nil {
+			return err
+		}
+	}
+	if c.auditLogger != nil {
+		return c.auditLogger.handlePacketIn(pktIn)
+	}
 	return nil
 }
 
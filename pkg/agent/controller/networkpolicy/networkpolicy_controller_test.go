@@ -26,10 +26,14 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/component-base/metrics/testutil"
 
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
 	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
@@ -49,9 +53,13 @@ func (g *antreaClientGetter) GetAntreaClient() (versioned.Interface, error) {
 func newTestController() (*Controller, *fake.Clientset, *mockReconciler) {
 	clientset := &fake.Clientset{}
 	ch := make(chan v1beta1.PodReference, 100)
-	controller := NewNetworkPolicyController(&antreaClientGetter{clientset}, nil, nil, "node1", ch, true)
+	informerFactory := informers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	controller, _ := NewNetworkPolicyController(&antreaClientGetter{clientset}, nil, nil, "node1", ch, true, nil, nil, informerFactory.Core().V1().Services(), informerFactory.Core().V1().Endpoints(), &sync.WaitGroup{})
 	reconciler := newMockReconciler()
 	controller.reconciler = reconciler
+	// The rules applied to Nodes are enforced with iptables, which is not
+	// available in unit tests.
+	controller.hostReconciler = nil
 	return controller, clientset, reconciler
 }
 
@@ -63,6 +71,8 @@ type mockReconciler struct {
 	lastRealized map[string]*CompletedRule
 	updated      chan string
 	deleted      chan string
+	// reconcileErr is returned by Reconcile if it's not nil.
+	reconcileErr error
 }
 
 func newMockReconciler() *mockReconciler {
@@ -76,6 +86,9 @@ func newMockReconciler() *mockReconciler {
 func (r *mockReconciler) Reconcile(rule *CompletedRule) error {
 	r.Lock()
 	defer r.Unlock()
+	if r.reconcileErr != nil {
+		return r.reconcileErr
+	}
 	r.lastRealized[rule.ID] = rule
 	r.updated <- rule.ID
 	return nil
@@ -247,6 +260,18 @@ func TestAddSingleGroupRule(t *testing.T) {
 	assert.Equal(t, 1, controller.GetNetworkPolicyNum())
 	assert.Equal(t, 1, controller.GetAddressGroupNum())
 	assert.Equal(t, 1, controller.GetAppliedToGroupNum())
+
+	// The initial NetworkPolicies have been installed once all watchers have completed full sync.
+	flowRestoreCompleteCh := make(chan struct{})
+	go func() {
+		controller.flowRestoreCompleteWait.Wait()
+		close(flowRestoreCompleteCh)
+	}()
+	select {
+	case <-flowRestoreCompleteCh:
+	case <-time.After(time.Second):
+		t.Fatal("Expected flowRestoreCompleteWait to be done after full sync")
+	}
 }
 
 func TestAddMultipleGroupsRule(t *testing.T) {
@@ -573,3 +598,27 @@ func TestNetworkPolicyMetrics(t *testing.T) {
 	waitForReconcilerDeleted()
 	checkNetworkPolicyMetrics()
 }
+
+func TestSyncRuleFailureEvent(t *testing.T) {
+	controller, _, reconciler := newTestController()
+	fakeRecorder := record.NewFakeRecorder(10)
+	controller.eventRecorder = events.NewRecorder(k8sfake.NewSimpleClientset(), fakeRecorder)
+	reconciler.reconcileErr = fmt.Errorf("failed to install flows")
+
+	protocolTCP := v1beta1.ProtocolTCP
+	port := intstr.FromInt(80)
+	services := []v1beta1.Service{{Protocol: &protocolTCP, Port: &port}}
+	controller.ruleCache.AddAddressGroup(newAddressGroup("addressGroup1", []v1beta1.GroupMemberPod{*newAddressGroupMemberPod("1.1.1.1")}))
+	controller.ruleCache.AddAppliedToGroup(newAppliedToGroup("appliedToGroup1", []v1beta1.GroupMemberPod{*newAppliedToGroupMember("pod1", "ns1")}))
+	controller.ruleCache.AddNetworkPolicy(newNetworkPolicy("policy1", []string{"addressGroup1"}, []string{}, []string{"appliedToGroup1"}, services))
+
+	key, _ := controller.queue.Get()
+	err := controller.syncRule(key.(string))
+	assert.Equal(t, reconciler.reconcileErr, err)
+	select {
+	case event := <-fakeRecorder.Events:
+		assert.Equal(t, fmt.Sprintf("Warning NetworkPolicyRealizationFailed Failed to realize rule %s: failed to install flows", key), event)
+	case <-time.After(time.Second):
+		t.Fatal("Expected one Event, got none")
+	}
+}
@@ -312,6 +312,12 @@ func (pa *priorityAssigner) insertConsecutivePriorities(priorities types.ByPrior
 	pLow, pHigh := priorities[0], priorities[numPriorities-1]
 	insertionPointLow := pa.initialOFPriorityFunc(pLow, pa.isSingleTier)
 	insertionPointHigh := pa.initialOFPriorityFunc(pHigh, pa.isSingleTier)
+	// The except Priority of a rule has the same initial ofPriority as the
+	// rule, so the Priorities can span more ofPriorities than their initial
+	// ones.
+	if minInsertionPointHigh := insertionPointLow + uint16(numPriorities-1); insertionPointHigh < minInsertionPointHigh {
+		insertionPointHigh = minInsertionPointHigh
+	}
 	// get the index for inserting the lowest Priority into the registered Priorities.
 	insertionIdx := sort.Search(len(pa.sortedPriorities), func(i int) bool { return pLow.Less(pa.sortedPriorities[i]) })
 	upperBound, lowerBound := PolicyTopPriority, PolicyBottomPriority
@@ -286,6 +286,38 @@ func TestRegisterPrioritiesAndRevert(t *testing.T) {
 	assert.Equalf(t, expectedOFMapAfterRevert, pa.ofPriorityMap, "priorityMap unexpected after revert")
 }
 
+func TestRegisterExceptPriorities(t *testing.T) {
+	except := func(p types.Priority) types.Priority {
+		p.Except = true
+		return p
+	}
+	assertOrdered := func(pa *priorityAssigner, priorities ...types.Priority) {
+		for i := 1; i < len(priorities); i++ {
+			low, _ := pa.GetOFPriority(priorities[i-1])
+			high, _ := pa.GetOFPriority(priorities[i])
+			assert.Lessf(t, low, high, "ofPriority of %v should be lower than ofPriority of %v", priorities[i-1], priorities[i])
+		}
+	}
+
+	// The except Priority of a rule is registered after the Priorities of
+	// the policy, and must be inserted right above the Priority of the rule.
+	pa := newPriorityAssigner(InitialOFPriority, false)
+	_, _, err := pa.RegisterPriorities([]types.Priority{p1132, p1131, p1130})
+	assert.NoError(t, err)
+	updates, _, err := pa.RegisterPriorities([]types.Priority{except(p1131)})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, updates, "Priorities should be reassigned to make room for the except Priority")
+	assertOrdered(pa, p1132, p1131, except(p1131), p1130)
+
+	// The except Priorities registered with the Priorities of the policy are
+	// consecutive to them.
+	pa = newPriorityAssigner(InitialOFPriority, false)
+	pa.updatePriorityAssignment(InitialOFPriority(p1130, false)+1, p1120)
+	_, _, err = pa.RegisterPriorities([]types.Priority{p1132, except(p1132), p1131, p1130, except(p1130)})
+	assert.NoError(t, err)
+	assertOrdered(pa, p1132, except(p1132), p1131, p1130, except(p1130), p1120)
+}
+
 func generatePriorities(tierPriority, start, end int32, policyPriority float64) []types.Priority {
 	priorities := make([]types.Priority, end-start+1)
 	for i := start; i <= end; i++ {
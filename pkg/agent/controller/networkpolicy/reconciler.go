@@ -30,6 +30,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/agent/types"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/util/ip"
 )
@@ -123,6 +124,11 @@ type lastRealized struct {
 	// ofIDs identifies Openflow rules in Openflow implementation.
 	// It's a map of servicesKey to Openflow rule ID.
 	ofIDs map[servicesKey]uint32
+	// exceptOFIDs identifies the Openflow rules dropping the except addresses
+	// of the IPBlocks of an Antrea-native policy rule. Each of them has the
+	// same target Pods and services as the Openflow rule of ofIDs with the
+	// same servicesKey.
+	exceptOFIDs map[servicesKey]uint32
 	// The desired state of a policy rule.
 	*CompletedRule
 	// The OFPort set we have realized for target Pods. We need to record them
@@ -144,6 +150,7 @@ type lastRealized struct {
 func newLastRealized(rule *CompletedRule) *lastRealized {
 	return &lastRealized{
 		ofIDs:         map[servicesKey]uint32{},
+		exceptOFIDs:   map[servicesKey]uint32{},
 		CompletedRule: rule,
 		podOFPorts:    map[servicesKey]sets.Int32{},
 		podIPs:        nil,
@@ -177,6 +184,9 @@ type reconciler struct {
 
 	// priorityAssigners provides interfaces to manage OF priorities for each OVS table.
 	priorityAssigners map[binding.TableIDType]*tablePriorityAssigner
+
+	// ofRuleUninstalledHandler is called with the Openflow ID of a rule after its flows are uninstalled, if set.
+	ofRuleUninstalledHandler func(ofID uint32)
 }
 
 // newReconciler returns a new *reconciler.
@@ -223,15 +233,22 @@ func (r *reconciler) Reconcile(rule *CompletedRule) error {
 	if err != nil {
 		return err
 	}
+	exceptOFPriority, err := r.getExceptOFPriority(rule, ruleTable, priorityAssigner)
+	if err != nil {
+		return err
+	}
 	var ofRuleInstallErr error
 	if !exists {
-		ofRuleInstallErr = r.add(rule, ofPriority, ruleTable)
+		ofRuleInstallErr = r.add(rule, ofPriority, exceptOFPriority, ruleTable)
 	} else {
-		ofRuleInstallErr = r.update(value.(*lastRealized), rule, ofPriority, ruleTable)
+		ofRuleInstallErr = r.update(value.(*lastRealized), rule, ofPriority, exceptOFPriority, ruleTable)
 	}
 	if ofRuleInstallErr != nil && ofPriority != nil {
 		priorityAssigner.assigner.Release(*ofPriority)
 	}
+	if ofRuleInstallErr != nil && exceptOFPriority != nil {
+		priorityAssigner.assigner.Release(*exceptOFPriority)
+	}
 	return ofRuleInstallErr
 }
 
@@ -280,30 +297,63 @@ func (r *reconciler) getOFPriority(rule *CompletedRule, table binding.TableIDTyp
 				RulePriority:   i,
 			}
 		}
-		priorityUpdates, revertFunc, err := pa.assigner.RegisterPriorities(allPrioritiesInPolicy)
-		if err != nil {
+		if err := r.registerPriorities(allPrioritiesInPolicy, table, pa); err != nil {
 			return nil, err
 		}
-		// Re-assign installed priorities on OVS
-		if len(priorityUpdates) > 0 {
-			err := r.ofClient.ReassignFlowPriorities(priorityUpdates, table)
-			if err != nil {
-				revertFunc()
-				return nil, err
-			}
-		}
 		ofPriority, _ = pa.assigner.GetOFPriority(p)
 	}
 	klog.V(2).Infof("Assigning OFPriority %v for rule %v", ofPriority, rule.ID)
 	return &ofPriority, nil
 }
 
+// getExceptOFPriority retrieves the OFPriority for the Openflow rules dropping
+// the except addresses of the input CompletedRule, which is right above the
+// OFPriority of the rule. It returns nil if the rule has no except addresses.
+func (r *reconciler) getExceptOFPriority(rule *CompletedRule, table binding.TableIDType, pa *tablePriorityAssigner) (*uint16, error) {
+	if !rule.hasIPBlockExcepts() {
+		return nil, nil
+	}
+	p := types.Priority{
+		TierPriority:   *rule.TierPriority,
+		PolicyPriority: *rule.PolicyPriority,
+		RulePriority:   rule.Priority,
+		Except:         true,
+	}
+	ofPriority, registered := pa.assigner.GetOFPriority(p)
+	if !registered {
+		if err := r.registerPriorities([]types.Priority{p}, table, pa); err != nil {
+			return nil, err
+		}
+		ofPriority, _ = pa.assigner.GetOFPriority(p)
+	}
+	klog.V(2).Infof("Assigning except OFPriority %v for rule %v", ofPriority, rule.ID)
+	return &ofPriority, nil
+}
+
+// registerPriorities registers the Priorities with the tablePriorityAssigner,
+// and re-arranges installed priorities on OVS if necessary.
+func (r *reconciler) registerPriorities(priorities []types.Priority, table binding.TableIDType, pa *tablePriorityAssigner) error {
+	priorityUpdates, revertFunc, err := pa.assigner.RegisterPriorities(priorities)
+	if err != nil {
+		return err
+	}
+	// Re-assign installed priorities on OVS
+	if len(priorityUpdates) > 0 {
+		err := r.ofClient.ReassignFlowPriorities(priorityUpdates, table)
+		if err != nil {
+			revertFunc()
+			return err
+		}
+	}
+	return nil
+}
+
 // BatchReconcile reconciles the desired state of the provided CompletedRules
 // with the actual state of Openflow entries in batch. It should only be invoked
 // if all rules are newly added without last realized status.
 func (r *reconciler) BatchReconcile(rules []*CompletedRule) error {
 	var rulesToInstall []*CompletedRule
-	var priorities []*uint16
+	var priorities, exceptPriorities []*uint16
 	prioritiesByTable := map[binding.TableIDType][]*uint16{}
 	for _, rule := range rules {
 		if _, exists := r.lastRealizeds.Load(rule.ID); exists {
@@ -324,8 +374,13 @@ func (r *reconciler) BatchReconcile(rules []*CompletedRule) error {
 		if ofPriority != nil {
 			prioritiesByTable[ruleTable] = append(prioritiesByTable[ruleTable], ofPriority)
 		}
+		exceptOFPriority, _ := r.getExceptOFPriority(rule, ruleTable, priorityAssigner)
+		exceptPriorities = append(exceptPriorities, exceptOFPriority)
+		if exceptOFPriority != nil {
+			prioritiesByTable[ruleTable] = append(prioritiesByTable[ruleTable], exceptOFPriority)
+		}
 	}
-	ofRuleInstallErr := r.batchAdd(rulesToInstall, priorities)
+	ofRuleInstallErr := r.batchAdd(rulesToInstall, priorities, exceptPriorities)
 	if ofRuleInstallErr != nil {
 		for tableID, ofPriorities := range prioritiesByTable {
 			pa := r.priorityAssigners[tableID]
@@ -350,6 +405,10 @@ func (r *reconciler) registerOFPriorities(rules []*CompletedRule) error {
 				RulePriority:   rule.Priority,
 			}
 			prioritiesToRegister[ruleTable] = append(prioritiesToRegister[ruleTable], p)
+			if rule.hasIPBlockExcepts() {
+				p.Except = true
+				prioritiesToRegister[ruleTable] = append(prioritiesToRegister[ruleTable], p)
+			}
 		}
 	}
 	for tableID, priorities := range prioritiesToRegister {
@@ -361,9 +420,9 @@ func (r *reconciler) registerOFPriorities(rules []*CompletedRule) error {
 }
 
 // add converts CompletedRule to PolicyRule(s) and invokes installOFRule to install them.
-func (r *reconciler) add(rule *CompletedRule, ofPriority *uint16, table binding.TableIDType) error {
+func (r *reconciler) add(rule *CompletedRule, ofPriority, exceptOFPriority *uint16, table binding.TableIDType) error {
 	klog.V(2).Infof("Adding new rule %v", rule)
-	ofRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriority, table)
+	ofRuleByServicesMap, exceptOFRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriority, exceptOFPriority, table)
 	for svcKey, ofRule := range ofRuleByServicesMap {
 		// Each pod group gets an Openflow ID.
 		ofID, err := r.idAllocator.allocate()
@@ -377,22 +436,34 @@ func (r *reconciler) add(rule *CompletedRule, ofPriority *uint16, table binding.
 		// Record ofID only if its Openflow is installed successfully.
 		lastRealized.ofIDs[svcKey] = ofID
 	}
+	for svcKey, exceptOFRule := range exceptOFRuleByServicesMap {
+		ofID, err := r.idAllocator.allocate()
+		if err != nil {
+			return fmt.Errorf("error allocating Openflow ID")
+		}
+		exceptOFRule.FlowID = ofID
+		if err = r.installOFRule(exceptOFRule); err != nil {
+			return err
+		}
+		lastRealized.exceptOFIDs[svcKey] = ofID
+	}
 	return nil
 }
 
-func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint16, table binding.TableIDType) (
-	map[servicesKey]*types.PolicyRule, *lastRealized) {
+func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority, exceptOFPriority *uint16, table binding.TableIDType) (
+	map[servicesKey]*types.PolicyRule, map[servicesKey]*types.PolicyRule, *lastRealized) {
 	lastRealized := newLastRealized(rule)
 	// TODO: Handle the case that the following processing fails or partially succeeds.
 	r.lastRealizeds.Store(rule.ID, lastRealized)
 
 	ofRuleByServicesMap := map[servicesKey]*types.PolicyRule{}
+	exceptOFRuleByServicesMap := map[servicesKey]*types.PolicyRule{}
 
 	if rule.Direction == v1beta1.DirectionIn {
 		// Addresses got from source GroupMembers' IPs.
 		from1 := groupMembersToOFAddresses(rule.FromAddresses)
 		// Get addresses that in From IPBlock but not in Except IPBlocks.
-		from2 := ipBlocksToOFAddresses(rule.From.IPBlocks)
+		from2 := rule.ipBlocksToOFAddresses(rule.From.IPBlocks)
 
 		podsByServicesMap, servicesMap := groupPodsByServices(rule.Services, rule.Pods)
 
@@ -400,14 +471,20 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 			ofPorts := r.getPodOFPorts(pods)
 			lastRealized.podOFPorts[svcKey] = ofPorts
 			ofRuleByServicesMap[svcKey] = &types.PolicyRule{
-				Direction: v1beta1.DirectionIn,
-				From:      append(from1, from2...),
-				To:        ofPortsToOFAddresses(ofPorts),
-				Service:   filterUnresolvablePort(servicesMap[svcKey]),
-				Action:    rule.Action,
-				Priority:  ofPriority,
-				TableID:   table,
-				PolicyRef: rule.SourceRef,
+				Direction:     v1beta1.DirectionIn,
+				From:          append(from1, from2...),
+				To:            ofPortsToOFAddresses(ofPorts),
+				Service:       filterUnresolvablePort(servicesMap[svcKey]),
+				Action:        rule.Action,
+				Priority:      ofPriority,
+				TableID:       table,
+				PolicyRef:     rule.SourceRef,
+				RulePriority:  rule.Priority,
+				EnableLogging: rule.EnableLogging,
+				RateLimit:     rule.RateLimit,
+			}
+			if exceptOFPriority != nil {
+				exceptOFRuleByServicesMap[svcKey] = rule.exceptOFRule(ofRuleByServicesMap[svcKey], exceptOFPriority)
 			}
 		}
 	} else {
@@ -417,14 +494,17 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 		memberByServicesMap, servicesMap := groupMembersByServices(rule.Services, rule.ToAddresses)
 		for svcKey, members := range memberByServicesMap {
 			ofRuleByServicesMap[svcKey] = &types.PolicyRule{
-				Direction: v1beta1.DirectionOut,
-				From:      from,
-				To:        groupMembersToOFAddresses(members),
-				Service:   filterUnresolvablePort(servicesMap[svcKey]),
-				Action:    rule.Action,
-				Priority:  ofPriority,
-				TableID:   table,
-				PolicyRef: rule.SourceRef,
+				Direction:     v1beta1.DirectionOut,
+				From:          from,
+				To:            groupMembersToOFAddresses(members),
+				Service:       filterUnresolvablePort(servicesMap[svcKey]),
+				Action:        rule.Action,
+				Priority:      ofPriority,
+				TableID:       table,
+				PolicyRef:     rule.SourceRef,
+				RulePriority:  rule.Priority,
+				EnableLogging: rule.EnableLogging,
+				RateLimit:     rule.RateLimit,
 			}
 		}
 
@@ -439,37 +519,44 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 			// Create a new Openflow rule if the group doesn't exist.
 			if !exists {
 				ofRule = &types.PolicyRule{
-					Direction: v1beta1.DirectionOut,
-					From:      from,
-					To:        []types.Address{},
-					Service:   filterUnresolvablePort(rule.Services),
-					Action:    rule.Action,
-					Priority:  nil,
-					TableID:   table,
-					PolicyRef: rule.SourceRef,
+					Direction:     v1beta1.DirectionOut,
+					From:          from,
+					To:            []types.Address{},
+					Service:       filterUnresolvablePort(rule.Services),
+					Action:        rule.Action,
+					Priority:      nil,
+					TableID:       table,
+					PolicyRef:     rule.SourceRef,
+					RulePriority:  rule.Priority,
+					EnableLogging: rule.EnableLogging,
+					RateLimit:     rule.RateLimit,
 				}
 				ofRuleByServicesMap[svcKey] = ofRule
 			}
 			if len(rule.To.IPBlocks) > 0 {
 				// Diff Addresses between To and Except of IPBlocks
-				to := ipBlocksToOFAddresses(rule.To.IPBlocks)
+				to := rule.ipBlocksToOFAddresses(rule.To.IPBlocks)
 				ofRule.To = append(ofRule.To, to...)
 			}
+			if exceptOFPriority != nil {
+				exceptOFRuleByServicesMap[svcKey] = rule.exceptOFRule(ofRule, exceptOFPriority)
+			}
 		}
 	}
-	return ofRuleByServicesMap, lastRealized
+	return ofRuleByServicesMap, exceptOFRuleByServicesMap, lastRealized
 }
 
 // batchAdd converts CompletedRules to PolicyRules and invokes BatchInstallPolicyRuleFlows to install them.
-func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) error {
+func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities, exceptOFPriorities []*uint16) error {
 	lastRealizeds := make([]*lastRealized, len(rules))
 	ofIDUpdateMaps := make([]map[servicesKey]uint32, len(rules))
+	exceptOFIDUpdateMaps := make([]map[servicesKey]uint32, len(rules))
 
 	var allOFRules []*types.PolicyRule
 
 	for idx, rule := range rules {
 		ruleTable := r.getOFRuleTable(rule)
-		ofRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriorities[idx], ruleTable)
+		ofRuleByServicesMap, exceptOFRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriorities[idx], exceptOFPriorities[idx], ruleTable)
 		lastRealizeds[idx] = lastRealized
 		for svcKey, ofRule := range ofRuleByServicesMap {
 			ofID, err := r.idAllocator.allocate()
@@ -483,6 +570,18 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 			}
 			ofIDUpdateMaps[idx][svcKey] = ofID
 		}
+		for svcKey, exceptOFRule := range exceptOFRuleByServicesMap {
+			ofID, err := r.idAllocator.allocate()
+			if err != nil {
+				return fmt.Errorf("error allocating Openflow ID")
+			}
+			exceptOFRule.FlowID = ofID
+			allOFRules = append(allOFRules, exceptOFRule)
+			if exceptOFIDUpdateMaps[idx] == nil {
+				exceptOFIDUpdateMaps[idx] = make(map[servicesKey]uint32)
+			}
+			exceptOFIDUpdateMaps[idx][svcKey] = ofID
+		}
 	}
 	if err := r.ofClient.BatchInstallPolicyRuleFlows(allOFRules); err != nil {
 		for _, rule := range allOFRules {
@@ -495,13 +594,16 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 		for svcKey, ofID := range ofIDUpdatesByRule {
 			lastRealized.ofIDs[svcKey] = ofID
 		}
+		for svcKey, ofID := range exceptOFIDUpdateMaps[i] {
+			lastRealized.exceptOFIDs[svcKey] = ofID
+		}
 	}
 	return nil
 }
 
 // update calculates the difference of Addresses between oldRule and newRule,
 // and invokes Openflow client's methods to reconcile them.
-func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule, ofPriority *uint16, table binding.TableIDType) error {
+func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule, ofPriority, exceptOFPriority *uint16, table binding.TableIDType) error {
 	klog.V(2).Infof("Updating existing rule %v", newRule)
 	// staleOFIDs tracks servicesKey that are no long needed.
 	// Firstly fill it with the last realized ofIDs.
@@ -514,7 +616,7 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 	// only happen to Group members.
 	if newRule.Direction == v1beta1.DirectionIn {
 		from1 := groupMembersToOFAddresses(newRule.FromAddresses)
-		from2 := ipBlocksToOFAddresses(newRule.From.IPBlocks)
+		from2 := newRule.ipBlocksToOFAddresses(newRule.From.IPBlocks)
 		addedFrom := groupMembersToOFAddresses(newRule.FromAddresses.Difference(lastRealized.FromAddresses))
 		deletedFrom := groupMembersToOFAddresses(lastRealized.FromAddresses.Difference(newRule.FromAddresses))
 
@@ -529,26 +631,48 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 					return fmt.Errorf("error allocating Openflow ID")
 				}
 				ofRule := &types.PolicyRule{
-					Direction: v1beta1.DirectionIn,
-					From:      append(from1, from2...),
-					To:        ofPortsToOFAddresses(newOFPorts),
-					Service:   filterUnresolvablePort(servicesMap[svcKey]),
-					Action:    newRule.Action,
-					Priority:  ofPriority,
-					FlowID:    ofID,
-					TableID:   table,
-					PolicyRef: newRule.SourceRef,
+					Direction:     v1beta1.DirectionIn,
+					From:          append(from1, from2...),
+					To:            ofPortsToOFAddresses(newOFPorts),
+					Service:       filterUnresolvablePort(servicesMap[svcKey]),
+					Action:        newRule.Action,
+					Priority:      ofPriority,
+					FlowID:        ofID,
+					TableID:       table,
+					PolicyRef:     newRule.SourceRef,
+					RulePriority:  newRule.Priority,
+					EnableLogging: newRule.EnableLogging,
+					RateLimit:     newRule.RateLimit,
 				}
 				if err = r.installOFRule(ofRule); err != nil {
 					return err
 				}
 				lastRealized.ofIDs[svcKey] = ofID
+				if exceptOFPriority != nil {
+					exceptOFID, err := r.idAllocator.allocate()
+					if err != nil {
+						return fmt.Errorf("error allocating Openflow ID")
+					}
+					exceptOFRule := newRule.exceptOFRule(ofRule, exceptOFPriority)
+					exceptOFRule.FlowID = exceptOFID
+					if err = r.installOFRule(exceptOFRule); err != nil {
+						return err
+					}
+					lastRealized.exceptOFIDs[svcKey] = exceptOFID
+				}
 			} else {
 				addedTo := ofPortsToOFAddresses(newOFPorts.Difference(lastRealized.podOFPorts[svcKey]))
 				deletedTo := ofPortsToOFAddresses(lastRealized.podOFPorts[svcKey].Difference(newOFPorts))
 				if err := r.updateOFRule(ofID, addedFrom, addedTo, deletedFrom, deletedTo, ofPriority); err != nil {
 					return err
 				}
+				// The except addresses are part of the rule identifier, only
+				// the target Pods of the except Openflow rule can change.
+				if exceptOFID, exists := lastRealized.exceptOFIDs[svcKey]; exists {
+					if err := r.updateOFRule(exceptOFID, nil, addedTo, nil, deletedTo, exceptOFPriority); err != nil {
+						return err
+					}
+				}
 				// Delete valid servicesKey from staleOFIDs.
 				delete(staleOFIDs, svcKey)
 			}
@@ -577,15 +701,18 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 					return fmt.Errorf("error allocating Openflow ID")
 				}
 				ofRule := &types.PolicyRule{
-					Direction: v1beta1.DirectionOut,
-					From:      from,
-					To:        groupMembersToOFAddresses(members),
-					Service:   filterUnresolvablePort(servicesMap[svcKey]),
-					Action:    newRule.Action,
-					Priority:  ofPriority,
-					FlowID:    ofID,
-					TableID:   table,
-					PolicyRef: newRule.SourceRef,
+					Direction:     v1beta1.DirectionOut,
+					From:          from,
+					To:            groupMembersToOFAddresses(members),
+					Service:       filterUnresolvablePort(servicesMap[svcKey]),
+					Action:        newRule.Action,
+					Priority:      ofPriority,
+					FlowID:        ofID,
+					TableID:       table,
+					PolicyRef:     newRule.SourceRef,
+					RulePriority:  newRule.Priority,
+					EnableLogging: newRule.EnableLogging,
+					RateLimit:     newRule.RateLimit,
 				}
 				if err = r.installOFRule(ofRule); err != nil {
 					return err
@@ -597,6 +724,11 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 				if err := r.updateOFRule(ofID, addedFrom, addedTo, deletedFrom, deletedTo, ofPriority); err != nil {
 					return err
 				}
+				if exceptOFID, exists := lastRealized.exceptOFIDs[svcKey]; exists {
+					if err := r.updateOFRule(exceptOFID, addedFrom, nil, deletedFrom, nil, exceptOFPriority); err != nil {
+						return err
+					}
+				}
 				// Delete valid servicesKey from staleOFIDs.
 				delete(staleOFIDs, svcKey)
 			}
@@ -605,6 +737,12 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 	}
 	// Remove stale Openflow rules.
 	for svcKey, ofID := range staleOFIDs {
+		if exceptOFID, exists := lastRealized.exceptOFIDs[svcKey]; exists {
+			if err := r.uninstallOFRule(exceptOFID, table); err != nil {
+				return err
+			}
+			delete(lastRealized.exceptOFIDs, svcKey)
+		}
 		if err := r.uninstallOFRule(ofID, table); err != nil {
 			return err
 		}
@@ -675,6 +813,9 @@ func (r *reconciler) uninstallOFRule(ofID uint32, table binding.TableIDType) err
 		// This should never happen. If it does, it is a programming error.
 		klog.Errorf("Error releasing Openflow ID for ofRule %v: %v", ofID, err)
 	}
+	if r.ofRuleUninstalledHandler != nil {
+		r.ofRuleUninstalledHandler(ofID)
+	}
 	return nil
 }
 
@@ -696,6 +837,12 @@ func (r *reconciler) Forget(ruleID string) error {
 		priorityAssigner.mutex.Lock()
 		defer priorityAssigner.mutex.Unlock()
 	}
+	for svcKey, ofID := range lastRealized.exceptOFIDs {
+		if err := r.uninstallOFRule(ofID, table); err != nil {
+			return err
+		}
+		delete(lastRealized.exceptOFIDs, svcKey)
+	}
 	for svcKey, ofID := range lastRealized.ofIDs {
 		if err := r.uninstallOFRule(ofID, table); err != nil {
 			return err
@@ -865,6 +1012,75 @@ func ipBlocksToOFAddresses(ipBlocks []v1beta1.IPBlock) []types.Address {
 	return addresses
 }
 
+// ipBlocksToOFAddresses converts the IPBlocks of the rule to Openflow
+// addresses. The except addresses of the Antrea-native policy rules are
+// dropped by separate Openflow rules of higher priority, so their IPBlocks are
+// converted as is instead of being split into the CIDRs which are not excepted.
+func (r *CompletedRule) ipBlocksToOFAddresses(ipBlocks []v1beta1.IPBlock) []types.Address {
+	if !r.isAntreaNetworkPolicyRule() {
+		return ipBlocksToOFAddresses(ipBlocks)
+	}
+	// Must not return nil as it means not restricted by addresses in Openflow implementation.
+	addresses := make([]types.Address, 0, len(ipBlocks))
+	for _, b := range ipBlocks {
+		addresses = append(addresses, ipNetToOFAddress(b.CIDR))
+	}
+	return addresses
+}
+
+// hasIPBlockExcepts returns true if the rule is part of an Antrea policy and
+// has except addresses in the IPBlocks of its peer.
+func (r *CompletedRule) hasIPBlockExcepts() bool {
+	if !r.isAntreaNetworkPolicyRule() {
+		return false
+	}
+	ipBlocks := r.To.IPBlocks
+	if r.Direction == v1beta1.DirectionIn {
+		ipBlocks = r.From.IPBlocks
+	}
+	for _, b := range ipBlocks {
+		if len(b.Except) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// exceptOFRule returns the Openflow rule dropping the traffic from or to the
+// except addresses of the IPBlocks of the rule, with the same target Pods and
+// services as the provided Openflow rule of the rule. It's installed with the
+// except priority, right above the priority of the rule, so that a single flow
+// is installed per except CIDR, whatever the number of except CIDRs. The
+// except addresses of an Audit rule, which is an Allow rule of a policy in
+// Monitor mode, are audited and logged as they would be dropped.
+func (r *CompletedRule) exceptOFRule(ofRule *types.PolicyRule, exceptOFPriority *uint16) *types.PolicyRule {
+	exceptOFRule := *ofRule
+	action := secv1alpha1.RuleActionDrop
+	if r.Action != nil && *r.Action == secv1alpha1.RuleActionAudit {
+		action = secv1alpha1.RuleActionAudit
+		exceptOFRule.EnableLogging = true
+	}
+	exceptOFRule.Action = &action
+	exceptOFRule.RateLimit = nil
+	exceptOFRule.Priority = exceptOFPriority
+	if r.Direction == v1beta1.DirectionIn {
+		exceptOFRule.From = ipBlockExceptsToOFAddresses(r.From.IPBlocks)
+	} else {
+		exceptOFRule.To = ipBlockExceptsToOFAddresses(r.To.IPBlocks)
+	}
+	return &exceptOFRule
+}
+
+func ipBlockExceptsToOFAddresses(ipBlocks []v1beta1.IPBlock) []types.Address {
+	addresses := make([]types.Address, 0)
+	for _, b := range ipBlocks {
+		for _, c := range b.Except {
+			addresses = append(addresses, ipNetToOFAddress(c))
+		}
+	}
+	return addresses
+}
+
 func ipNetToOFAddress(in v1beta1.IPNet) *openflow.IPNetAddress {
 	ipNet := net.IPNet{
 		IP:   net.IP(in.IP),
@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/types"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 )
 
 var (
@@ -519,6 +521,88 @@ func TestReconcilerReconcile(t *testing.T) {
 	}
 }
 
+// newIPBlockWithExcepts returns the IPBlock 10.0.0.0/8 with numExcepts
+// scattered /32 except CIDRs.
+func newIPBlockWithExcepts(numExcepts int) v1beta1.IPBlock {
+	ipBlock := v1beta1.IPBlock{CIDR: v1beta1.IPNet{IP: v1beta1.IPAddress(net.IPv4(10, 0, 0, 0).To4()), PrefixLength: 8}}
+	for i := 0; i < numExcepts; i++ {
+		exceptIP := net.IPv4(10, byte(i/128), byte(i%128*2), 1).To4()
+		ipBlock.Except = append(ipBlock.Except, v1beta1.IPNet{IP: v1beta1.IPAddress(exceptIP), PrefixLength: 32})
+	}
+	return ipBlock
+}
+
+func TestReconcilerReconcileIPBlockExcepts(t *testing.T) {
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
+		InterfaceName:            util.GenerateContainerInterfaceName("pod1", "ns1", "container1"),
+		IP:                       net.ParseIP("2.2.2.2"),
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{PodName: "pod1", PodNamespace: "ns1", ContainerID: "container1"},
+		OVSPortConfig:            &interfacestore.OVSPortConfig{OFPort: 1},
+	})
+	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
+		InterfaceName:            util.GenerateContainerInterfaceName("pod3", "ns1", "container3"),
+		IP:                       net.ParseIP("3.3.3.3"),
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{PodName: "pod3", PodNamespace: "ns1", ContainerID: "container3"},
+		OVSPortConfig:            &interfacestore.OVSPortConfig{OFPort: 3},
+	})
+	numExcepts := 1000
+	ipBlock := newIPBlockWithExcepts(numExcepts)
+	allowAction := secv1alpha1.RuleActionAllow
+	newRule := func(pods v1beta1.GroupMemberPodSet) *CompletedRule {
+		return &CompletedRule{
+			rule: &rule{
+				ID:             "egress-rule",
+				Direction:      v1beta1.DirectionOut,
+				To:             v1beta1.NetworkPolicyPeer{IPBlocks: []v1beta1.IPBlock{ipBlock}},
+				Action:         &allowAction,
+				PolicyPriority: &policyPriority,
+				TierPriority:   &tierPriority,
+				SourceRef:      &cnp1,
+			},
+			Pods: pods,
+		}
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	var ofRules []*types.PolicyRule
+	mockOFClient.EXPECT().InstallPolicyRuleFlows(gomock.Any()).Do(func(ofRule *types.PolicyRule) {
+		ofRules = append(ofRules, ofRule)
+	}).Times(2)
+	r := newReconciler(mockOFClient, ifaceStore)
+	require.NoError(t, r.Reconcile(newRule(appliedToGroup1)))
+	require.Len(t, ofRules, 2)
+
+	// The rule is realized by an Openflow rule allowing the whole IPBlock,
+	// and an Openflow rule of higher priority dropping the except CIDRs.
+	ofRule, exceptOFRule := ofRules[0], ofRules[1]
+	assert.Equal(t, allowAction, *ofRule.Action)
+	assert.Equal(t, []types.Address{ipNetToOFAddress(ipBlock.CIDR)}, ofRule.To)
+	assert.Equal(t, secv1alpha1.RuleActionDrop, *exceptOFRule.Action)
+	assert.Len(t, exceptOFRule.To, numExcepts)
+	assert.Equal(t, ofRule.From, exceptOFRule.From)
+	assert.Equal(t, ofRule.Service, exceptOFRule.Service)
+	assert.Equal(t, ofRule.TableID, exceptOFRule.TableID)
+	assert.Greater(t, *exceptOFRule.Priority, *ofRule.Priority)
+	assert.NotEqual(t, ofRule.FlowID, exceptOFRule.FlowID)
+	// Splitting the IPBlock into the CIDRs which are not excepted, as done for
+	// K8s NetworkPolicies, would require many more flows.
+	assert.Greater(t, len(ipBlocksToOFAddresses([]v1beta1.IPBlock{ipBlock})), 5*numExcepts)
+
+	// A new target Pod is added to both Openflow rules.
+	addedFrom := ipsToOFAddresses(sets.NewString("3.3.3.3"))
+	mockOFClient.EXPECT().AddPolicyRuleAddress(ofRule.FlowID, types.SrcAddress, addedFrom, ofRule.Priority)
+	mockOFClient.EXPECT().AddPolicyRuleAddress(exceptOFRule.FlowID, types.SrcAddress, addedFrom, exceptOFRule.Priority)
+	pods := v1beta1.NewGroupMemberPodSet(newAppliedToGroupMember("pod1", "ns1"), newAppliedToGroupMember("pod3", "ns1"))
+	require.NoError(t, r.Reconcile(newRule(pods)))
+
+	mockOFClient.EXPECT().UninstallPolicyRuleFlows(ofRule.FlowID)
+	mockOFClient.EXPECT().UninstallPolicyRuleFlows(exceptOFRule.FlowID)
+	require.NoError(t, r.Forget("egress-rule"))
+}
+
 func TestReconcilerBatchReconcile(t *testing.T) {
 	ifaceStore := interfacestore.NewInterfaceStore()
 	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
@@ -1007,3 +1091,47 @@ func BenchmarkGroupPodsByServicesWithNamedPort(b *testing.B) {
 func BenchmarkGroupPodsByServicesWithoutNamedPort(b *testing.B) {
 	benchmarkGroupPodsByServices(b, false)
 }
+
+func benchmarkReconcileIPBlockWithExcepts(b *testing.B, policyRef *v1beta1.NetworkPolicyReference) {
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
+		InterfaceName:            util.GenerateContainerInterfaceName("pod1", "ns1", "container1"),
+		IP:                       net.ParseIP("2.2.2.2"),
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{PodName: "pod1", PodNamespace: "ns1", ContainerID: "container1"},
+		OVSPortConfig:            &interfacestore.OVSPortConfig{OFPort: 1},
+	})
+	allowAction := secv1alpha1.RuleActionAllow
+	// 2,000 except CIDRs in the IPBlock.
+	completedRule := &CompletedRule{
+		rule: &rule{
+			ID:             "ingress-rule",
+			Direction:      v1beta1.DirectionIn,
+			From:           v1beta1.NetworkPolicyPeer{IPBlocks: []v1beta1.IPBlock{newIPBlockWithExcepts(2000)}},
+			Action:         &allowAction,
+			PolicyPriority: &policyPriority,
+			TierPriority:   &tierPriority,
+			SourceRef:      policyRef,
+		},
+		Pods: appliedToGroup1,
+	}
+	controller := gomock.NewController(b)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockOFClient.EXPECT().InstallPolicyRuleFlows(gomock.Any()).AnyTimes()
+	mockOFClient.EXPECT().UninstallPolicyRuleFlows(gomock.Any()).AnyTimes()
+	r := newReconciler(mockOFClient, ifaceStore)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Reconcile(completedRule)
+		r.Forget(completedRule.ID)
+	}
+}
+
+func BenchmarkReconcileK8sIPBlockWithExcepts(b *testing.B) {
+	benchmarkReconcileIPBlockWithExcepts(b, &np1)
+}
+
+func BenchmarkReconcileAntreaIPBlockWithExcepts(b *testing.B) {
+	benchmarkReconcileIPBlockWithExcepts(b, &cnp1)
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+)
+
+// networkPolicyStatusControlInterface knows how to report the status of a
+// NetworkPolicy to the antrea-controller. It's an interface so that it can be
+// faked in tests.
+type networkPolicyStatusControlInterface interface {
+	UpdateNetworkPolicyStatus(name string, status *v1beta1.NetworkPolicyStatus) error
+}
+
+type networkPolicyStatusControl struct {
+	antreaClientProvider agent.AntreaClientProvider
+}
+
+func (c *networkPolicyStatusControl) UpdateNetworkPolicyStatus(name string, status *v1beta1.NetworkPolicyStatus) error {
+	antreaClient, err := c.antreaClientProvider.GetAntreaClient()
+	if err != nil {
+		return err
+	}
+	_, err = antreaClient.ControlplaneV1beta1().NetworkPolicies(status.Namespace).UpdateStatus(context.TODO(), name, status, metav1.CreateOptions{})
+	return err
+}
+
+// statusController reports the generation of the Antrea-native policies
+// realized on the Node to the antrea-controller. A generation is reported when
+// all the rules of the policy have been synced, i.e. either realized or failed
+// to be realized, and the rules it no longer has have been removed, along with
+// the rules which failed. A given status is only reported once, unless the
+// NetworkPolicy watcher restarts, as the antrea-controller may have lost the
+// statuses.
+type statusController struct {
+	nodeName               string
+	statusControlInterface networkPolicyStatusControlInterface
+	ruleCache              *ruleCache
+	// queue maintains the UIDs of the policies whose status needs to be
+	// reported.
+	queue workqueue.RateLimitingInterface
+
+	lock sync.Mutex
+	// syncedRules maps the ID of a synced rule to the UID of its policy.
+	syncedRules map[string]types.UID
+	// syncedRulesByPolicy maps the UID of a policy to the IDs of its synced
+	// rules.
+	syncedRulesByPolicy map[types.UID]sets.String
+	// failedRules maps the ID of a synced rule which failed to be realized
+	// to its failure.
+	failedRules map[string]*v1beta1.NetworkPolicyRuleFailure
+	// reportedStatuses maps the UID of a policy to the last status
+	// successfully reported.
+	reportedStatuses map[types.UID]*v1beta1.NetworkPolicyNodeStatus
+}
+
+func newStatusController(antreaClientProvider agent.AntreaClientProvider, nodeName string, ruleCache *ruleCache) *statusController {
+	return &statusController{
+		nodeName:               nodeName,
+		statusControlInterface: &networkPolicyStatusControl{antreaClientProvider: antreaClientProvider},
+		ruleCache:              ruleCache,
+		queue:                  workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicystatus"),
+		syncedRules:            map[string]types.UID{},
+		syncedRulesByPolicy:    map[types.UID]sets.String{},
+		failedRules:            map[string]*v1beta1.NetworkPolicyRuleFailure{},
+		reportedStatuses:       map[types.UID]*v1beta1.NetworkPolicyNodeStatus{},
+	}
+}
+
+// SetRuleRealization marks the rule of the policy as realized.
+func (c *statusController) SetRuleRealization(ruleID string, policyUID types.UID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.setRuleSynced(ruleID, policyUID)
+	delete(c.failedRules, ruleID)
+	c.queue.Add(policyUID)
+}
+
+// SetRuleFailure marks the rule of the policy as failed to be realized with the
+// provided error. The rule stays failed until it's realized or removed.
+func (c *statusController) SetRuleFailure(rule *CompletedRule, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.setRuleSynced(rule.ID, rule.PolicyUID)
+	c.failedRules[rule.ID] = &v1beta1.NetworkPolicyRuleFailure{
+		Direction: rule.Direction,
+		Priority:  rule.Priority,
+		Message:   err.Error(),
+	}
+	c.queue.Add(rule.PolicyUID)
+}
+
+func (c *statusController) setRuleSynced(ruleID string, policyUID types.UID) {
+	if _, exists := c.syncedRules[ruleID]; exists {
+		return
+	}
+	c.syncedRules[ruleID] = policyUID
+	if c.syncedRulesByPolicy[policyUID] == nil {
+		c.syncedRulesByPolicy[policyUID] = sets.NewString()
+	}
+	c.syncedRulesByPolicy[policyUID].Insert(ruleID)
+}
+
+// DeleteRuleRealization marks the rule as removed.
+func (c *statusController) DeleteRuleRealization(ruleID string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	policyUID, exists := c.syncedRules[ruleID]
+	if !exists {
+		return
+	}
+	delete(c.syncedRules, ruleID)
+	delete(c.failedRules, ruleID)
+	c.syncedRulesByPolicy[policyUID].Delete(ruleID)
+	if c.syncedRulesByPolicy[policyUID].Len() == 0 {
+		delete(c.syncedRulesByPolicy, policyUID)
+	}
+	c.queue.Add(policyUID)
+}
+
+// enqueuePolicy checks whether the status of the policy needs to be reported.
+// It must be called when a policy is updated, as a new generation of a policy
+// doesn't necessarily change its rules.
+func (c *statusController) enqueuePolicy(policyUID types.UID) {
+	c.queue.Add(policyUID)
+}
+
+// resync reports the status of all the given policies again.
+func (c *statusController) resync(policyUIDs []types.UID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.reportedStatuses = map[types.UID]*v1beta1.NetworkPolicyNodeStatus{}
+	for _, uid := range policyUIDs {
+		c.queue.Add(uid)
+	}
+}
+
+func (c *statusController) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+	// A single worker is enough as the statuses are small and reported once
+	// per generation, unless the failed rules change.
+	go wait.Until(c.worker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (c *statusController) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *statusController) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.syncHandler(key.(types.UID)); err != nil {
+		klog.Errorf("Failed to report status of NetworkPolicy %s: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *statusController) syncHandler(uid types.UID) error {
+	policy, ruleIDs, exists := c.ruleCache.getNetworkPolicyRuleIDs(uid)
+	c.lock.Lock()
+	if !exists {
+		delete(c.reportedStatuses, uid)
+		c.lock.Unlock()
+		return nil
+	}
+	// Only Antrea-native policies have a status.
+	if policy.SourceRef == nil || policy.SourceRef.Type == v1beta1.K8sNetworkPolicy {
+		c.lock.Unlock()
+		return nil
+	}
+	if !c.syncedRulesByPolicy[uid].Equal(ruleIDs) {
+		c.lock.Unlock()
+		return nil
+	}
+	nodeStatus := v1beta1.NetworkPolicyNodeStatus{
+		NodeName:   c.nodeName,
+		Generation: policy.Generation,
+	}
+	for ruleID := range ruleIDs {
+		if failure, failed := c.failedRules[ruleID]; failed {
+			nodeStatus.FailedRules = append(nodeStatus.FailedRules, *failure)
+		}
+	}
+	reportedStatus := c.reportedStatuses[uid]
+	c.lock.Unlock()
+	// Sort the failed rules so that an unchanged status is not reported again.
+	sort.Slice(nodeStatus.FailedRules, func(i, j int) bool {
+		if nodeStatus.FailedRules[i].Direction != nodeStatus.FailedRules[j].Direction {
+			return nodeStatus.FailedRules[i].Direction < nodeStatus.FailedRules[j].Direction
+		}
+		return nodeStatus.FailedRules[i].Priority < nodeStatus.FailedRules[j].Priority
+	})
+	if reportedStatus != nil && reflect.DeepEqual(*reportedStatus, nodeStatus) {
+		return nil
+	}
+
+	status := &v1beta1.NetworkPolicyStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policy.Name,
+			Namespace: policy.Namespace,
+		},
+		Nodes: []v1beta1.NetworkPolicyNodeStatus{nodeStatus},
+	}
+	if len(nodeStatus.FailedRules) > 0 {
+		klog.V(2).Infof("Reporting realization of generation %d of NetworkPolicy %s with %d failed rules", policy.Generation, policy.SourceRef.ToString(), len(nodeStatus.FailedRules))
+	} else {
+		klog.V(2).Infof("Reporting realization of generation %d of NetworkPolicy %s", policy.Generation, policy.SourceRef.ToString())
+	}
+	if err := c.statusControlInterface.UpdateNetworkPolicyStatus(policy.Name, status); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	c.reportedStatuses[uid] = &nodeStatus
+	c.lock.Unlock()
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+)
+
+type fakeNetworkPolicyStatusControl struct {
+	statuses []*v1beta1.NetworkPolicyStatus
+}
+
+func (c *fakeNetworkPolicyStatusControl) UpdateNetworkPolicyStatus(name string, status *v1beta1.NetworkPolicyStatus) error {
+	c.statuses = append(c.statuses, status)
+	return nil
+}
+
+func (c *fakeNetworkPolicyStatusControl) reportedGenerations() []int64 {
+	var generations []int64
+	for _, status := range c.statuses {
+		generations = append(generations, status.Nodes[0].Generation)
+	}
+	return generations
+}
+
+func newTestStatusController() (*statusController, *ruleCache, *fakeNetworkPolicyStatusControl) {
+	ruleCache := newRuleCache(func(string) {}, make(chan v1beta1.PodReference, 100))
+	statusControl := &fakeNetworkPolicyStatusControl{}
+	statusController := newStatusController(nil, "node1", ruleCache)
+	statusController.statusControlInterface = statusControl
+	return statusController, ruleCache, statusControl
+}
+
+func getPolicyRuleIDs(t *testing.T, ruleCache *ruleCache, uid types.UID) []string {
+	_, ruleIDs, exists := ruleCache.getNetworkPolicyRuleIDs(uid)
+	require.True(t, exists)
+	return ruleIDs.List()
+}
+
+func TestStatusControllerReportsRealizedGeneration(t *testing.T) {
+	statusController, ruleCache, statusControl := newTestStatusController()
+	policy := newNetworkPolicyWithMultipleRules("policy1", []string{"addressGroup1"}, []string{"addressGroup2"}, []string{"appliedToGroup1"}, nil)
+	policy.SourceRef.Type = v1beta1.AntreaNetworkPolicy
+	policy.Generation = 1
+	ruleCache.AddNetworkPolicy(policy)
+	ruleIDs := getPolicyRuleIDs(t, ruleCache, policy.UID)
+	require.Len(t, ruleIDs, 2)
+
+	// The policy is not realized until all its rules are realized.
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	statusController.SetRuleRealization(ruleIDs[0], policy.UID)
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Empty(t, statusControl.statuses)
+
+	statusController.SetRuleRealization(ruleIDs[1], policy.UID)
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	require.Len(t, statusControl.statuses, 1)
+	assert.Equal(t, "policy1", statusControl.statuses[0].Name)
+	assert.Equal(t, testNamespace, statusControl.statuses[0].Namespace)
+	assert.Equal(t, []v1beta1.NetworkPolicyNodeStatus{{NodeName: "node1", Generation: 1}}, statusControl.statuses[0].Nodes)
+
+	// A generation is only reported once.
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Equal(t, []int64{1}, statusControl.reportedGenerations())
+
+	// The new generation removes a rule, it's realized once the rule is removed.
+	updatedPolicy := newNetworkPolicy("policy1", []string{"addressGroup1"}, []string{}, []string{"appliedToGroup1"}, nil)
+	updatedPolicy.SourceRef.Type = v1beta1.AntreaNetworkPolicy
+	updatedPolicy.Generation = 2
+	ruleCache.UpdateNetworkPolicy(updatedPolicy)
+	remainingRuleIDs := getPolicyRuleIDs(t, ruleCache, policy.UID)
+	require.Len(t, remainingRuleIDs, 1)
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Equal(t, []int64{1}, statusControl.reportedGenerations())
+	for _, ruleID := range ruleIDs {
+		if ruleID != remainingRuleIDs[0] {
+			statusController.DeleteRuleRealization(ruleID)
+		}
+	}
+	statusController.SetRuleRealization(remainingRuleIDs[0], policy.UID)
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Equal(t, []int64{1, 2}, statusControl.reportedGenerations())
+
+	// The statuses are reported again after a resync.
+	statusController.resync([]types.UID{policy.UID})
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Equal(t, []int64{1, 2, 2}, statusControl.reportedGenerations())
+}
+
+func TestStatusControllerReportsFailedRules(t *testing.T) {
+	statusController, ruleCache, statusControl := newTestStatusController()
+	policy := newNetworkPolicyWithMultipleRules("policy1", []string{"addressGroup1"}, []string{"addressGroup2"}, []string{"appliedToGroup1"}, nil)
+	policy.SourceRef.Type = v1beta1.AntreaClusterNetworkPolicy
+	policy.Generation = 1
+	ruleCache.AddNetworkPolicy(policy)
+	ruleIDs := getPolicyRuleIDs(t, ruleCache, policy.UID)
+	require.Len(t, ruleIDs, 2)
+	failedRule := &CompletedRule{rule: &rule{ID: ruleIDs[0], Direction: v1beta1.DirectionOut, Priority: 1, PolicyUID: policy.UID}}
+
+	// The generation is reported once all the rules are synced, along with
+	// the rules which failed.
+	statusController.SetRuleFailure(failedRule, fmt.Errorf("unsupported match"))
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Empty(t, statusControl.statuses)
+	statusController.SetRuleRealization(ruleIDs[1], policy.UID)
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	require.Len(t, statusControl.statuses, 1)
+	assert.Equal(t, []v1beta1.NetworkPolicyNodeStatus{{
+		NodeName:   "node1",
+		Generation: 1,
+		FailedRules: []v1beta1.NetworkPolicyRuleFailure{
+			{Direction: v1beta1.DirectionOut, Priority: 1, Message: "unsupported match"},
+		},
+	}}, statusControl.statuses[0].Nodes)
+
+	// A failure which doesn't change is only reported once.
+	statusController.SetRuleFailure(failedRule, fmt.Errorf("unsupported match"))
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Len(t, statusControl.statuses, 1)
+
+	// The generation is reported again once the failed rule is realized.
+	statusController.SetRuleRealization(ruleIDs[0], policy.UID)
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	require.Len(t, statusControl.statuses, 2)
+	assert.Equal(t, []v1beta1.NetworkPolicyNodeStatus{{NodeName: "node1", Generation: 1}}, statusControl.statuses[1].Nodes)
+}
+
+func TestStatusControllerIgnoresK8sNetworkPolicy(t *testing.T) {
+	statusController, ruleCache, statusControl := newTestStatusController()
+	policy := newNetworkPolicy("policy1", []string{"addressGroup1"}, []string{}, []string{"appliedToGroup1"}, nil)
+	ruleCache.AddNetworkPolicy(policy)
+	for _, ruleID := range getPolicyRuleIDs(t, ruleCache, policy.UID) {
+		statusController.SetRuleRealization(ruleID, policy.UID)
+	}
+	require.NoError(t, statusController.syncHandler(policy.UID))
+	assert.Empty(t, statusControl.statuses)
+}
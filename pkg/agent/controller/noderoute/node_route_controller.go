@@ -17,6 +17,7 @@ package noderoute
 import (
 	"fmt"
 	"net"
+	"runtime"
 	"sync"
 	"time"
 
@@ -70,6 +71,8 @@ type Controller struct {
 	// The key is the host name of the Node, the value is the podCIDR of the Node.
 	// A node will be in the map after its flows and routes are installed successfully.
 	installedNodes *sync.Map
+	// flowRestoreCompleteWait is notified once the routes and flows of the initial Nodes have been installed.
+	flowRestoreCompleteWait *sync.WaitGroup
 }
 
 // NewNodeRouteController instantiates a new Controller object which will process Node events
@@ -82,21 +85,24 @@ func NewNodeRouteController(
 	routeClient route.Interface,
 	interfaceStore interfacestore.InterfaceStore,
 	networkConfig *config.NetworkConfig,
-	nodeConfig *config.NodeConfig) *Controller {
+	nodeConfig *config.NodeConfig,
+	flowRestoreCompleteWait *sync.WaitGroup) *Controller {
 	nodeInformer := informerFactory.Core().V1().Nodes()
 	controller := &Controller{
-		kubeClient:       kubeClient,
-		ovsBridgeClient:  ovsBridgeClient,
-		ofClient:         client,
-		routeClient:      routeClient,
-		interfaceStore:   interfaceStore,
-		networkConfig:    networkConfig,
-		nodeConfig:       nodeConfig,
-		nodeInformer:     nodeInformer,
-		nodeLister:       nodeInformer.Lister(),
-		nodeListerSynced: nodeInformer.Informer().HasSynced,
-		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "noderoute"),
-		installedNodes:   &sync.Map{}}
+		kubeClient:              kubeClient,
+		ovsBridgeClient:         ovsBridgeClient,
+		ofClient:                client,
+		routeClient:             routeClient,
+		interfaceStore:          interfaceStore,
+		networkConfig:           networkConfig,
+		nodeConfig:              nodeConfig,
+		nodeInformer:            nodeInformer,
+		nodeLister:              nodeInformer.Lister(),
+		nodeListerSynced:        nodeInformer.Informer().HasSynced,
+		queue:                   workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "noderoute"),
+		installedNodes:          &sync.Map{},
+		flowRestoreCompleteWait: flowRestoreCompleteWait}
+	flowRestoreCompleteWait.Add(1)
 	nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(cur interface{}) {
@@ -157,6 +163,12 @@ func (c *Controller) removeStaleGatewayRoutes() error {
 		}
 		desiredPodCIDRs = append(desiredPodCIDRs, node.Spec.PodCIDR)
 	}
+	// The routes to the local Pods whose IPs are allocated by Antrea IPAM from an IPPool are desired as well.
+	for _, containerConfig := range c.interfaceStore.GetInterfacesByType(interfacestore.ContainerInterface) {
+		if c.nodeConfig.PodCIDR != nil && containerConfig.IP != nil && !c.nodeConfig.PodCIDR.Contains(containerConfig.IP) {
+			desiredPodCIDRs = append(desiredPodCIDRs, (&net.IPNet{IP: containerConfig.IP, Mask: net.CIDRMask(32, 32)}).String())
+		}
+	}
 
 	// routeClient will remove orphaned routes whose destinations are not in desiredPodCIDRs.
 	if err := c.routeClient.Reconcile(desiredPodCIDRs); err != nil {
@@ -247,6 +259,30 @@ func (c *Controller) reconcile() error {
 	return nil
 }
 
+// installInitialNodes installs the routes and flows of the existing Nodes before the workers are started, and then
+// notifies flowRestoreCompleteWait. The Nodes which fail to be synced here will be retried by the workers.
+func (c *Controller) installInitialNodes() {
+	defer c.flowRestoreCompleteWait.Done()
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Error when listing Nodes: %v", err)
+		return
+	}
+	installed, failed := 0, 0
+	for _, node := range nodes {
+		if node.Name == c.nodeConfig.Name {
+			continue
+		}
+		if err := c.syncNodeRoute(node.Name); err != nil {
+			klog.Errorf("Error when installing routes and flows for Node %s: %v", node.Name, err)
+			failed++
+			continue
+		}
+		installed++
+	}
+	klog.Infof("Installed routes and flows for %d initial Nodes, %d Nodes failed and will be retried", installed, failed)
+}
+
 // Run will create defaultWorkers workers (go routines) which will process the Node events from the
 // workqueue.
 func (c *Controller) Run(stopCh <-chan struct{}) {
@@ -256,6 +292,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	// underlying network. Therefore it needs not know the routes to
 	// peer Pod CIDRs.
 	if c.networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() {
+		c.flowRestoreCompleteWait.Done()
 		<-stopCh
 		return
 	}
@@ -274,6 +311,8 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 		klog.Errorf("Error during %s reconciliation", controllerName)
 	}
 
+	c.installInitialNodes()
+
 	for i := 0; i < defaultWorkers; i++ {
 		go wait.Until(c.worker, time.Second, stopCh)
 	}
@@ -409,6 +448,9 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 		return nil
 	}
 	peerGatewayIP := ip.NextIP(peerPodCIDRAddr)
+	// In a hybrid topology, whether the traffic to the peer Node is encapsulated
+	// depends on the OS of both Nodes.
+	peerEncapMode := c.networkConfig.EncapModeToPeer(runtime.GOOS, node.Labels[corev1.LabelOSStable])
 
 	ipsecTunOFPort := int32(0)
 	if c.networkConfig.EnableIPSecTunnel {
@@ -427,18 +469,34 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 		peerGatewayIP,
 		peerNodeIP,
 		config.DefaultTunOFPort,
-		uint32(ipsecTunOFPort))
+		uint32(ipsecTunOFPort),
+		peerEncapMode)
 	if err != nil {
 		return fmt.Errorf("failed to install flows to Node %s: %v", nodeName, err)
 	}
 
-	if err := c.routeClient.AddRoutes(peerPodCIDR, peerNodeIP, peerGatewayIP); err != nil {
+	if err := c.routeClient.AddRoutes(peerPodCIDR, peerNodeIP, peerGatewayIP, peerEncapMode); err != nil {
 		return err
 	}
 	c.installedNodes.Store(nodeName, peerPodCIDR)
 	return err
 }
 
+// GetNodeNameByPodIP returns the name of the remote Node whose PodCIDR contains
+// the provided IP. Only the Nodes whose routes and flows have been installed
+// are considered.
+func (c *Controller) GetNodeNameByPodIP(podIP net.IP) (string, bool) {
+	var nodeName string
+	c.installedNodes.Range(func(k, v interface{}) bool {
+		if v.(*net.IPNet).Contains(podIP) {
+			nodeName = k.(string)
+			return false
+		}
+		return true
+	})
+	return nodeName, nodeName != ""
+}
+
 // createIPSecTunnelPort creates an IPSec tunnel port for the remote Node if the
 // tunnel does not exist, and returns the ofport number.
 func (c *Controller) createIPSecTunnelPort(nodeName string, nodeIP net.IP) (int32, error) {
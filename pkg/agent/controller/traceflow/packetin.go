@@ -34,6 +34,11 @@ import (
 )
 
 func (c *Controller) HandlePacketIn(pktIn *ofctrl.PacketIn) error {
+	// Packet-in messages are also sent for other features, e.g. for the packets dropped by NetworkPolicies when the
+	// FlowExporter feature is enabled. Only the packets tagged with a data plane tag are Traceflow packets.
+	if getMatchRegField(pktIn.GetMatches(), uint32(openflow.TraceflowReg)) == nil {
+		return nil
+	}
 	if !c.traceflowListerSynced() {
 		return errors.New("traceflow controller is not started")
 	}
@@ -102,25 +107,19 @@ func (c *Controller) parsePacketIn(pktIn *ofctrl.PacketIn) (*opsv1alpha1.Tracefl
 		obs = append(obs, *ob)
 	}
 
-	// Collect Service DNAT.
+	// Collect Service DNAT and OVS SNAT.
+	var dstIP net.IP
 	if pktIn.Data.Ethertype == 0x800 {
 		ipPacket, ok := pktIn.Data.Data.(*protocol.IPv4)
 		if !ok {
 			return nil, nil, errors.New("invalid traceflow IPv4 packet")
 		}
-		ctNwDst, err := getInfoInCtNwDstField(matchers)
+		dstIP = ipPacket.NWDst
+		ctObs, err := conntrackObservations(matchers, ipPacket)
 		if err != nil {
 			return nil, nil, err
 		}
-		ipDst := ipPacket.NWDst.String()
-		if ctNwDst != "" && ipDst != ctNwDst {
-			ob := &opsv1alpha1.Observation{
-				Component:       opsv1alpha1.LB,
-				Action:          opsv1alpha1.Forwarded,
-				TranslatedDstIP: ipDst,
-			}
-			obs = append(obs, *ob)
-		}
+		obs = append(obs, ctObs...)
 	}
 
 	// Collect egress conjunctionID and get NetworkPolicy from cache.
@@ -168,6 +167,15 @@ func (c *Controller) parsePacketIn(pktIn *ofctrl.PacketIn) (*opsv1alpha1.Tracefl
 
 	// Get output table.
 	if tableID == uint8(openflow.L2ForwardingOutTable) {
+		if isSender && dstIP != nil && c.isExternalIP(dstIP) {
+			egressObs, err := c.egressObservations(dstIP)
+			if err != nil {
+				return nil, nil, err
+			}
+			obs = append(obs, egressObs...)
+			nodeResult := opsv1alpha1.NodeResult{Node: c.nodeConfig.Name, Timestamp: time.Now().Unix(), Observations: obs}
+			return tf, &nodeResult, nil
+		}
 		ob := new(opsv1alpha1.Observation)
 		tunnelDstIP := ""
 		if match = getMatchTunnelDstField(matchers); match != nil {
@@ -191,6 +199,77 @@ func (c *Controller) parsePacketIn(pktIn *ofctrl.PacketIn) (*opsv1alpha1.Tracefl
 	return tf, &nodeResult, nil
 }
 
+// conntrackObservations returns the observation of the NAT of the packet by the ct action of the pipeline. The
+// conntrack fields of the packet-in message are the ones of the zone of the last ct action: the original destination
+// of the zone of the pipeline reveals the Service DNAT, while the original source of the SNAT zone reveals the SNAT
+// performed by OVS, as on Windows.
+func conntrackObservations(matchers *ofctrl.Matchers, ipPacket *protocol.IPv4) ([]opsv1alpha1.Observation, error) {
+	ctZone, err := getInfoInCtZoneField(matchers)
+	if err != nil {
+		return nil, err
+	}
+	if feature, _ := openflow.GetCtZoneFeature(ctZone); feature == openflow.CtZoneFeatureSNAT {
+		ctNwSrc, err := getInfoInCtNwSrcField(matchers)
+		if err != nil {
+			return nil, err
+		}
+		ipSrc := ipPacket.NWSrc.String()
+		if ctNwSrc == "" || ipSrc == ctNwSrc {
+			return nil, nil
+		}
+		return []opsv1alpha1.Observation{{
+			Component:       opsv1alpha1.Egress,
+			ComponentInfo:   openflow.GetFlowTableName(openflow.SNATConntrackCommitTable),
+			Action:          opsv1alpha1.Forwarded,
+			TranslatedSrcIP: ipSrc,
+		}}, nil
+	}
+	ctNwDst, err := getInfoInCtNwDstField(matchers)
+	if err != nil {
+		return nil, err
+	}
+	ipDst := ipPacket.NWDst.String()
+	if ctNwDst == "" || ipDst == ctNwDst {
+		return nil, nil
+	}
+	return []opsv1alpha1.Observation{{
+		Component:       opsv1alpha1.LB,
+		Action:          opsv1alpha1.Forwarded,
+		TranslatedDstIP: ipDst,
+	}}, nil
+}
+
+// egressObservations returns the observations of a packet leaving the cluster from this Node: the SNAT decision of the
+// host network and the uplink the packet is forwarded to, where the trace stops.
+func (c *Controller) egressObservations(dstIP net.IP) ([]opsv1alpha1.Observation, error) {
+	linkName, nextHop, srcIP, err := c.routeClient.LookupRoute(dstIP)
+	if err != nil {
+		return nil, err
+	}
+	var obs []opsv1alpha1.Observation
+	// In networkPolicyOnly mode, the packets to external destinations are handled by the primary CNI and are not
+	// masqueraded by Antrea.
+	if !c.networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() && srcIP != nil {
+		obs = append(obs, opsv1alpha1.Observation{
+			Component:       opsv1alpha1.Egress,
+			ComponentInfo:   "NodeMasquerade",
+			Action:          opsv1alpha1.Forwarded,
+			TranslatedSrcIP: srcIP.String(),
+		})
+	}
+	ob := opsv1alpha1.Observation{
+		Component:       opsv1alpha1.Forwarding,
+		ComponentInfo:   openflow.GetFlowTableName(openflow.L2ForwardingOutTable),
+		Action:          opsv1alpha1.ForwardedOutOfOverlay,
+		EgressInterface: linkName,
+	}
+	if nextHop != nil {
+		ob.NextHopIP = nextHop.String()
+	}
+	obs = append(obs, ob)
+	return obs, nil
+}
+
 func getMatchRegField(matchers *ofctrl.Matchers, regNum uint32) *ofctrl.MatchField {
 	return matchers.GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", regNum))
 }
@@ -218,6 +297,30 @@ func getInfoInTunnelDst(regMatch *ofctrl.MatchField) (string, error) {
 	return regValue.String(), nil
 }
 
+func getInfoInCtZoneField(matchers *ofctrl.Matchers) (uint16, error) {
+	match := matchers.GetMatchByName("NXM_NX_CT_ZONE")
+	if match == nil {
+		return 0, nil
+	}
+	zone, ok := match.GetValue().(uint16)
+	if !ok {
+		return 0, errors.New("packet-in conntrack zone value cannot be retrieved from metadata")
+	}
+	return zone, nil
+}
+
+func getInfoInCtNwSrcField(matchers *ofctrl.Matchers) (string, error) {
+	match := matchers.GetMatchByName("NXM_NX_CT_NW_SRC")
+	if match == nil {
+		return "", nil
+	}
+	regValue, ok := match.GetValue().(net.IP)
+	if !ok {
+		return "", errors.New("packet-in conntrack IP source value cannot be retrieved from metadata")
+	}
+	return regValue.String(), nil
+}
+
 func getInfoInCtNwDstField(matchers *ofctrl.Matchers) (string, error) {
 	match := matchers.GetMatchByName("NXM_NX_CT_NW_DST")
 	if match == nil {
@@ -24,9 +24,11 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -36,6 +38,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/agent/route"
 	opsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/ops/v1alpha1"
 	clientsetversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
 	opsinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/ops/v1alpha1"
@@ -70,12 +73,15 @@ type Controller struct {
 	kubeClient             clientset.Interface
 	serviceLister          corelisters.ServiceLister
 	serviceListerSynced    cache.InformerSynced
+	nodeLister             corelisters.NodeLister
+	nodeListerSynced       cache.InformerSynced
 	traceflowClient        clientsetversioned.Interface
 	traceflowInformer      opsinformers.TraceflowInformer
 	traceflowLister        opslisters.TraceflowLister
 	traceflowListerSynced  cache.InformerSynced
 	ovsBridgeClient        ovsconfig.OVSBridgeClient
 	ofClient               openflow.Client
+	routeClient            route.Interface
 	interfaceStore         interfacestore.InterfaceStore
 	networkConfig          *config.NetworkConfig
 	nodeConfig             *config.NodeConfig
@@ -92,22 +98,27 @@ type Controller struct {
 func NewTraceflowController(
 	kubeClient clientset.Interface,
 	informerFactory informers.SharedInformerFactory,
+	nodeInformer coreinformers.NodeInformer,
 	traceflowClient clientsetversioned.Interface,
 	traceflowInformer opsinformers.TraceflowInformer,
 	client openflow.Client,
 	ovsBridgeClient ovsconfig.OVSBridgeClient,
+	routeClient route.Interface,
 	interfaceStore interfacestore.InterfaceStore,
 	networkConfig *config.NetworkConfig,
 	nodeConfig *config.NodeConfig,
 	serviceCIDR *net.IPNet) *Controller {
 	c := &Controller{
 		kubeClient:            kubeClient,
+		nodeLister:            nodeInformer.Lister(),
+		nodeListerSynced:      nodeInformer.Informer().HasSynced,
 		traceflowClient:       traceflowClient,
 		traceflowInformer:     traceflowInformer,
 		traceflowLister:       traceflowInformer.Lister(),
 		traceflowListerSynced: traceflowInformer.Informer().HasSynced,
 		ovsBridgeClient:       ovsBridgeClient,
 		ofClient:              client,
+		routeClient:           routeClient,
 		interfaceStore:        interfaceStore,
 		networkConfig:         networkConfig,
 		nodeConfig:            nodeConfig,
@@ -155,7 +166,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 			return
 		}
 	}
-	if !cache.WaitForCacheSync(stopCh, c.traceflowListerSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.traceflowListerSynced, c.nodeListerSynced) {
 		klog.Errorf("Unable to sync caches for %s", controllerName)
 		return
 	}
@@ -227,7 +238,9 @@ func (c *Controller) processTraceflowItem() bool {
 }
 
 // TODO: Let controller compute which Node is the sender, and each Node watch the TF CRD with some
-//  filter to get and process only TF from the Node.
+//
+//	filter to get and process only TF from the Node.
+//
 // syncTraceflow gets Traceflow CRD by name, update cache and start syncing.
 func (c *Controller) syncTraceflow(traceflowName string) error {
 	startTime := time.Now()
@@ -353,7 +366,8 @@ func (c *Controller) injectPacket(tf *opsv1alpha1.Traceflow) error {
 		flagsTCP = 2
 	}
 	// Check encap status if no dstMAC found which means the destination is Service or the destination Pod/IP is not on local Node.
-	if dstMAC == "" {
+	// The packets to a destination outside the cluster leave through the local gateway and are only traced on this Node.
+	if dstMAC == "" && !c.isExternalIP(net.ParseIP(dstIP)) {
 		peerIP := net.ParseIP(dstNodeIP)
 		if c.networkConfig.TunnelType == ovsconfig.GeneveTunnel && (tf.Spec.Destination.Pod == "" || c.networkConfig.TrafficEncapMode.NeedsEncapToPeer(peerIP, c.nodeConfig.NodeIPAddr)) {
 			// If the destination is Service/IP or the packet will be encapsulated to remote Node, wait a small period for other Nodes.
@@ -406,6 +420,38 @@ func (c *Controller) injectPacket(tf *opsv1alpha1.Traceflow) error {
 		-1)
 }
 
+// isExternalIP returns whether the IP is outside the cluster, i.e. it is neither a Service ClusterIP, nor a Pod IP, nor
+// a Node IP.
+func (c *Controller) isExternalIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if c.serviceCIDR != nil && c.serviceCIDR.Contains(ip) {
+		return false
+	}
+	if c.nodeConfig.PodCIDR != nil && c.nodeConfig.PodCIDR.Contains(ip) {
+		return false
+	}
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Nodes: %v", err)
+		return false
+	}
+	for _, node := range nodes {
+		if node.Spec.PodCIDR != "" {
+			if _, podCIDR, err := net.ParseCIDR(node.Spec.PodCIDR); err == nil && podCIDR.Contains(ip) {
+				return false
+			}
+		}
+		for _, address := range node.Status.Addresses {
+			if address.Address == ip.String() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (c *Controller) errorTraceflowCRD(tf *opsv1alpha1.Traceflow, reason string) (*opsv1alpha1.Traceflow, error) {
 	tf.Status.Phase = opsv1alpha1.Failed
 
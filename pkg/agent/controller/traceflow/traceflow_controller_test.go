@@ -0,0 +1,161 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceflow
+
+import (
+	"net"
+	"testing"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	routetesting "github.com/vmware-tanzu/antrea/pkg/agent/route/testing"
+	opsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/ops/v1alpha1"
+)
+
+func newTestController(t *testing.T, routeClient *routetesting.MockInterface, encapMode config.TrafficEncapModeType) *Controller {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+		Spec:       corev1.NodeSpec{PodCIDR: "10.10.1.0/24"},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "192.168.1.2"}},
+		},
+	}
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	require.NoError(t, nodeInformer.Informer().GetIndexer().Add(node))
+	_, podCIDR, _ := net.ParseCIDR("10.10.0.0/24")
+	_, serviceCIDR, _ := net.ParseCIDR("10.96.0.0/12")
+	return &Controller{
+		nodeLister:    nodeInformer.Lister(),
+		routeClient:   routeClient,
+		networkConfig: &config.NetworkConfig{TrafficEncapMode: encapMode},
+		nodeConfig:    &config.NodeConfig{Name: "node1", PodCIDR: podCIDR},
+		serviceCIDR:   serviceCIDR,
+	}
+}
+
+func TestIsExternalIP(t *testing.T) {
+	c := newTestController(t, nil, config.TrafficEncapModeEncap)
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{"10.10.0.5", false},
+		{"10.10.1.5", false},
+		{"10.96.0.1", false},
+		{"192.168.1.2", false},
+		{"8.8.8.8", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, c.isExternalIP(net.ParseIP(tt.ip)), "Unexpected result for IP %s", tt.ip)
+	}
+}
+
+func TestEgressObservations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	dstIP := net.ParseIP("8.8.8.8")
+
+	routeClient := routetesting.NewMockInterface(ctrl)
+	routeClient.EXPECT().LookupRoute(dstIP).Return("eth0", net.ParseIP("192.168.1.254"), net.ParseIP("192.168.1.1"), nil).Times(2)
+
+	c := newTestController(t, routeClient, config.TrafficEncapModeEncap)
+	obs, err := c.egressObservations(dstIP)
+	require.NoError(t, err)
+	require.Len(t, obs, 2)
+	assert.Equal(t, opsv1alpha1.Egress, obs[0].Component)
+	assert.Equal(t, "192.168.1.1", obs[0].TranslatedSrcIP)
+	assert.Equal(t, opsv1alpha1.ForwardedOutOfOverlay, obs[1].Action)
+	assert.Equal(t, "eth0", obs[1].EgressInterface)
+	assert.Equal(t, "192.168.1.254", obs[1].NextHopIP)
+
+	// The packets are not masqueraded by Antrea in networkPolicyOnly mode.
+	c = newTestController(t, routeClient, config.TrafficEncapModeNetworkPolicyOnly)
+	obs, err = c.egressObservations(dstIP)
+	require.NoError(t, err)
+	require.Len(t, obs, 1)
+	assert.Equal(t, opsv1alpha1.ForwardedOutOfOverlay, obs[0].Action)
+}
+
+func TestConntrackObservations(t *testing.T) {
+	ctNwField := func(name string, ip net.IP) openflow13.MatchField {
+		field, _ := openflow13.FindFieldHeaderByName(name, false)
+		if name == "NXM_NX_CT_NW_SRC" {
+			field.Value = &openflow13.Ipv4SrcField{Ipv4Src: ip}
+		} else {
+			field.Value = &openflow13.Ipv4DstField{Ipv4Dst: ip}
+		}
+		return *field
+	}
+	podIP, nodeIP := net.ParseIP("10.10.0.2"), net.ParseIP("192.168.1.1")
+	serviceIP, endpointIP, externalIP := net.ParseIP("10.96.0.10"), net.ParseIP("10.10.1.3"), net.ParseIP("8.8.8.8")
+	tests := []struct {
+		name   string
+		fields []openflow13.MatchField
+		packet *protocol.IPv4
+		expObs []opsv1alpha1.Observation
+	}{
+		{
+			name: "Service DNAT",
+			fields: []openflow13.MatchField{
+				*openflow13.NewCTZoneMatchField(openflow.CtZone),
+				ctNwField("NXM_NX_CT_NW_SRC", podIP),
+				ctNwField("NXM_NX_CT_NW_DST", serviceIP),
+			},
+			packet: &protocol.IPv4{NWSrc: podIP, NWDst: endpointIP},
+			expObs: []opsv1alpha1.Observation{{Component: opsv1alpha1.LB, Action: opsv1alpha1.Forwarded, TranslatedDstIP: endpointIP.String()}},
+		},
+		{
+			name: "no NAT",
+			fields: []openflow13.MatchField{
+				*openflow13.NewCTZoneMatchField(openflow.CtZone),
+				ctNwField("NXM_NX_CT_NW_SRC", podIP),
+				ctNwField("NXM_NX_CT_NW_DST", endpointIP),
+			},
+			packet: &protocol.IPv4{NWSrc: podIP, NWDst: endpointIP},
+		},
+		{
+			// The original destination of the SNAT zone is the DNAT'd destination, which must not be taken as
+			// the Service IP.
+			name: "OVS SNAT",
+			fields: []openflow13.MatchField{
+				*openflow13.NewCTZoneMatchField(openflow.SNATCtZone),
+				ctNwField("NXM_NX_CT_NW_SRC", podIP),
+				ctNwField("NXM_NX_CT_NW_DST", serviceIP),
+			},
+			packet: &protocol.IPv4{NWSrc: nodeIP, NWDst: externalIP},
+			expObs: []opsv1alpha1.Observation{{Component: opsv1alpha1.Egress, ComponentInfo: "SNATConntrackCommit", Action: opsv1alpha1.Forwarded, TranslatedSrcIP: nodeIP.String()}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pktIn := &ofctrl.PacketIn{Match: openflow13.Match{Fields: tt.fields}}
+			obs, err := conntrackObservations(pktIn.GetMatches(), tt.packet)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expObs, obs)
+		})
+	}
+}
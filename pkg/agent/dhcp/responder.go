@@ -0,0 +1,327 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dhcp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	coreinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha1"
+	corelisters "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha1"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+const (
+	serverPort uint16 = 67
+	clientPort uint16 = 68
+
+	// The BOOTP operations and the DHCP message types, as defined by RFC 2131. The BOOTP operation constants of
+	// libOpenflow don't match the RFC.
+	bootRequest byte = 1
+	bootReply   byte = 2
+
+	msgDiscover = byte(protocol.DHCP_MSG_DISCOVER)
+	msgOffer    = byte(protocol.DHCP_MSG_OFFER)
+	msgRequest  = byte(protocol.DHCP_MSG_REQUEST)
+	msgAck      = byte(protocol.DHCP_MSG_ACK)
+	msgNak      = byte(protocol.DHCP_MSG_NAK)
+
+	// headerLen is the length of the fixed part of a DHCP message, including the magic cookie.
+	headerLen  = 240
+	dhcpMagic  = 0x63825363
+	hwEthernet = 1
+	// minMessageLen is the minimum length of the BOOTP messages, which some clients still require from the replies.
+	minMessageLen = 300
+
+	// leaseTime is the lease time of the offered IPs in seconds. The IPs are allocated to the Pods for their whole
+	// lifetime, so the lease only determines how often the clients renew it.
+	leaseTime uint32 = 86400
+)
+
+var broadcastIP = net.IPv4bcast
+
+// Responder answers the DHCP requests sent by the Pods whose IPs are allocated by Antrea IPAM, with the IP allocated
+// to them in the status of their IPPool, the subnet and the gateway of the pool. It lets the Pods and VMs which insist
+// on DHCP-based configuration be attached to the networks of the IPPools, without having to configure their IPs
+// statically. The responder never allocates IPs: the requests of the Pods without an IP allocated from an IPPool are
+// ignored.
+type Responder struct {
+	ofClient   openflow.Client
+	ifaceStore interfacestore.InterfaceStore
+	// gatewayMAC is the MAC of the gateway interface of the Node, which answers the ARP requests of the Pods for their
+	// IPPool gateway.
+	gatewayMAC         net.HardwareAddr
+	ipPoolLister       corelisters.IPPoolLister
+	ipPoolListerSynced cache.InformerSynced
+}
+
+// NewResponder creates a Responder and registers it as the handler of the packet-in messages carrying the DHCP
+// requests of local Pods.
+func NewResponder(
+	ofClient openflow.Client,
+	ifaceStore interfacestore.InterfaceStore,
+	gatewayMAC net.HardwareAddr,
+	ipPoolInformer coreinformers.IPPoolInformer) *Responder {
+	r := &Responder{
+		ofClient:           ofClient,
+		ifaceStore:         ifaceStore,
+		gatewayMAC:         gatewayMAC,
+		ipPoolLister:       ipPoolInformer.Lister(),
+		ipPoolListerSynced: ipPoolInformer.Informer().HasSynced,
+	}
+	ofClient.RegisterPacketInHandler("dhcp", r)
+	return r
+}
+
+// Run installs the flows which send the DHCP requests of local Pods to the Antrea Agent once the IPPool cache is
+// synced, so that the first requests are not ignored.
+func (r *Responder) Run(stopCh <-chan struct{}) {
+	klog.Info("Starting DHCP responder")
+	defer klog.Info("Shutting down DHCP responder")
+
+	if !cache.WaitForNamedCacheSync("DHCP responder", stopCh, r.ipPoolListerSynced) {
+		return
+	}
+	if err := r.ofClient.InstallDHCPRequestFlows(); err != nil {
+		klog.Errorf("Failed to install the DHCP request flows: %v", err)
+		return
+	}
+	<-stopCh
+}
+
+// request is a DHCP request sent by a Pod.
+type request struct {
+	msgType      byte
+	xid          uint32
+	flags        uint16
+	clientIP     net.IP
+	relayIP      net.IP
+	clientHWAddr net.HardwareAddr
+	// requestedIP and serverID are the requested IP and server identifier options, nil if they are not set.
+	requestedIP net.IP
+	serverID    net.IP
+}
+
+// parseRequest parses a DHCP request. It returns an error if the message is not a valid DHCP request of an Ethernet
+// client.
+func parseRequest(data []byte) (*request, error) {
+	if len(data) < headerLen {
+		return nil, fmt.Errorf("message is too short")
+	}
+	if data[0] != bootRequest || data[1] != hwEthernet || data[2] != 6 {
+		return nil, fmt.Errorf("message is not a request of an Ethernet client")
+	}
+	if binary.BigEndian.Uint32(data[236:240]) != dhcpMagic {
+		return nil, fmt.Errorf("message has an invalid magic cookie")
+	}
+	req := &request{
+		xid:          binary.BigEndian.Uint32(data[4:8]),
+		flags:        binary.BigEndian.Uint16(data[10:12]),
+		clientIP:     net.IP(data[12:16]),
+		relayIP:      net.IP(data[24:28]),
+		clientHWAddr: net.HardwareAddr(data[28:34]),
+	}
+	for options := data[headerLen:]; len(options) > 0; {
+		tag := options[0]
+		if tag == protocol.DHCP_OPT_END {
+			break
+		}
+		if tag == protocol.DHCP_OPT_PAD {
+			options = options[1:]
+			continue
+		}
+		if len(options) < 2 || len(options) < 2+int(options[1]) {
+			return nil, fmt.Errorf("option %d is truncated", tag)
+		}
+		value := options[2 : 2+int(options[1])]
+		switch {
+		case tag == protocol.DHCP_OPT_MESSAGE_TYPE && len(value) == 1:
+			req.msgType = value[0]
+		case tag == protocol.DHCP_OPT_REQUEST_IP && len(value) == net.IPv4len:
+			req.requestedIP = net.IP(value)
+		case tag == protocol.DHCP_OPT_SERVER_ID && len(value) == net.IPv4len:
+			req.serverID = net.IP(value)
+		}
+		options = options[2+len(value):]
+	}
+	if req.msgType == 0 {
+		return nil, fmt.Errorf("message type option is missing")
+	}
+	return req, nil
+}
+
+// lease is the IP configuration of a Pod, as allocated from its IPPool.
+type lease struct {
+	ip      net.IP
+	mask    net.IPMask
+	gateway net.IP
+}
+
+// HandlePacketIn handles the packet-in messages carrying the DHCP requests of local Pods.
+func (r *Responder) HandlePacketIn(pktIn *ofctrl.PacketIn) error {
+	if binding.TableIDType(pktIn.TableId) != openflow.SpoofGuardTable {
+		return nil
+	}
+	matches := pktIn.GetMatches()
+	if matches.GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", openflow.TraceflowReg)) != nil {
+		return nil
+	}
+	if pktIn.Data.Ethertype != protocol.IPv4_MSG {
+		return nil
+	}
+	ipPacket, ok := pktIn.Data.Data.(*protocol.IPv4)
+	if !ok {
+		return fmt.Errorf("invalid IPv4 packet")
+	}
+	udpPacket, ok := ipPacket.Data.(*protocol.UDP)
+	if !ok || udpPacket.PortDst != serverPort {
+		return nil
+	}
+	inPortMatch := matches.GetMatchByName("OXM_OF_IN_PORT")
+	if inPortMatch == nil {
+		return fmt.Errorf("in_port is missing from the packet-in message")
+	}
+	inPort := inPortMatch.GetValue().(uint32)
+	req, err := parseRequest(udpPacket.Data)
+	if err != nil {
+		return fmt.Errorf("invalid DHCP request: %v", err)
+	}
+	if req.msgType != msgDiscover && req.msgType != msgRequest {
+		return nil
+	}
+	iface, lease, err := r.getLease(inPort)
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		klog.V(2).Infof("Ignoring DHCP request from OVS port %d without an IP allocated by Antrea IPAM", inPort)
+		return nil
+	}
+	// The REQUEST messages with a server identifier which isn't ours select the offer of another server.
+	if req.msgType == msgRequest && req.serverID != nil && !req.serverID.Equal(lease.gateway) {
+		return nil
+	}
+
+	replyType := msgOffer
+	if req.msgType == msgRequest {
+		replyType = msgAck
+		// A client renewing its lease sets ciaddr instead of the requested IP option.
+		requestedIP := req.requestedIP
+		if requestedIP == nil {
+			requestedIP = req.clientIP
+		}
+		if !requestedIP.Equal(lease.ip) {
+			replyType = msgNak
+		}
+	}
+	klog.V(2).Infof("Answering DHCP request of Pod %s/%s with message type %d and IP %s", iface.PodNamespace, iface.PodName, replyType, lease.ip)
+	dstIP := broadcastIP
+	if !req.clientIP.Equal(net.IPv4zero) && replyType != msgNak {
+		dstIP = req.clientIP
+	}
+	return r.ofClient.SendUDPPacketOut(r.gatewayMAC, req.clientHWAddr, lease.gateway, dstIP, serverPort, clientPort, buildReply(req, replyType, lease), inPort)
+}
+
+// getLease returns the container interface attached to the OVS port and the IP configuration allocated to it from an
+// IPPool, or a nil lease if the container has no IP allocated by Antrea IPAM.
+func (r *Responder) getLease(ofPort uint32) (*interfacestore.InterfaceConfig, *lease, error) {
+	var iface *interfacestore.InterfaceConfig
+	for _, containerIface := range r.ifaceStore.GetInterfacesByType(interfacestore.ContainerInterface) {
+		if containerIface.OVSPortConfig != nil && containerIface.OFPort == int32(ofPort) {
+			iface = containerIface
+			break
+		}
+	}
+	if iface == nil || iface.IP.To4() == nil {
+		return nil, nil, nil
+	}
+	pools, err := r.ipPoolLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pool := range pools {
+		if !isAllocatedFrom(pool, iface) {
+			continue
+		}
+		gateway := net.ParseIP(pool.Spec.Gateway).To4()
+		if gateway == nil {
+			return nil, nil, fmt.Errorf("IPPool %s has an invalid IPv4 gateway %q", pool.Name, pool.Spec.Gateway)
+		}
+		return iface, &lease{
+			ip:      iface.IP.To4(),
+			mask:    net.CIDRMask(int(pool.Spec.PrefixLength), 8*net.IPv4len),
+			gateway: gateway,
+		}, nil
+	}
+	return iface, nil, nil
+}
+
+// isAllocatedFrom returns true if the IP of the container interface is allocated from the IPPool.
+func isAllocatedFrom(pool *corev1alpha1.IPPool, iface *interfacestore.InterfaceConfig) bool {
+	for _, address := range pool.Status.IPAddresses {
+		if address.Owner.ContainerID == iface.ContainerID && net.ParseIP(address.IPAddress).Equal(iface.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildReply builds the DHCP reply of the specified message type to the request. The replies carry the subnet mask and
+// the router of the IPPool, but no DNS servers: the Pods are configured with the cluster DNS through their
+// resolv.conf.
+func buildReply(req *request, msgType byte, lease *lease) []byte {
+	reply := &protocol.DHCP{
+		Operation:    protocol.DHCPOperation(bootReply),
+		HardwareType: hwEthernet,
+		HardwareLen:  uint8(len(req.clientHWAddr)),
+		Xid:          req.xid,
+		Flags:        req.flags,
+		ClientIP:     make(net.IP, net.IPv4len),
+		YourIP:       make(net.IP, net.IPv4len),
+		ServerIP:     make(net.IP, net.IPv4len),
+		GatewayIP:    make(net.IP, net.IPv4len),
+		ClientHWAddr: req.clientHWAddr,
+		Options:      []protocol.DHCPOption{protocol.DHCPNewOption(protocol.DHCP_OPT_MESSAGE_TYPE, []byte{msgType})},
+	}
+	copy(reply.GatewayIP, req.relayIP)
+	reply.Options = append(reply.Options, protocol.DHCPNewOption(protocol.DHCP_OPT_SERVER_ID, lease.gateway))
+	if msgType != msgNak {
+		copy(reply.ClientIP, req.clientIP)
+		copy(reply.YourIP, lease.ip)
+		leaseTimeValue := make([]byte, 4)
+		binary.BigEndian.PutUint32(leaseTimeValue, leaseTime)
+		reply.Options = append(reply.Options,
+			protocol.DHCPNewOption(protocol.DHCP_OPT_LEASE_TIME, leaseTimeValue),
+			protocol.DHCPNewOption(protocol.DHCP_OPT_SUBNET_MASK, []byte(lease.mask)),
+			protocol.DHCPNewOption(protocol.DHCP_OPT_DEFAULT_GATEWAY, lease.gateway))
+	}
+	data := make([]byte, reply.Len())
+	reply.Read(data)
+	// The message is padded with zeros, which are PAD options.
+	if len(data) < minMessageLen {
+		data = append(data, make([]byte, minMessageLen-len(data))...)
+	}
+	return data
+}
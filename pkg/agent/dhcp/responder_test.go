@@ -0,0 +1,259 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dhcp
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	openflowtest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+)
+
+const (
+	testPodOFPort     = 3
+	testOtherOFPort   = 4
+	testPodIP         = "192.168.240.10"
+	testPoolGateway   = "192.168.240.1"
+	testPodContainer  = "container1"
+	testOtherPodIP    = "10.10.0.5"
+	testOtherPodOwner = "container2"
+)
+
+var (
+	testGatewayMAC, _ = net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	testPodMAC, _     = net.ParseMAC("00:11:22:33:44:55")
+)
+
+func newTestResponder(t *testing.T) (*Responder, *openflowtest.MockClient) {
+	ctrl := gomock.NewController(t)
+	ofClient := openflowtest.NewMockClient(ctrl)
+	ofClient.EXPECT().RegisterPacketInHandler("dhcp", gomock.Any())
+
+	ifaceStore := interfacestore.NewInterfaceStore()
+	podIface := interfacestore.NewContainerInterface("pod1-abcd", testPodContainer, "pod1", "default", testPodMAC, net.ParseIP(testPodIP))
+	podIface.OVSPortConfig = &interfacestore.OVSPortConfig{OFPort: testPodOFPort}
+	ifaceStore.AddInterface(podIface)
+	otherIface := interfacestore.NewContainerInterface("pod2-abcd", testOtherPodOwner, "pod2", "default", testPodMAC, net.ParseIP(testOtherPodIP))
+	otherIface.OVSPortConfig = &interfacestore.OVSPortConfig{OFPort: testOtherOFPort}
+	ifaceStore.AddInterface(otherIface)
+
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(fakeversioned.NewSimpleClientset(), 0)
+	ipPoolInformer := crdInformerFactory.Core().V1alpha1().IPPools()
+	require.NoError(t, ipPoolInformer.Informer().GetIndexer().Add(&corev1alpha1.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool1"},
+		Spec: corev1alpha1.IPPoolSpec{
+			IPRanges:     []corev1alpha1.IPRange{{CIDR: "192.168.240.0/24"}},
+			Gateway:      testPoolGateway,
+			PrefixLength: 24,
+		},
+		Status: corev1alpha1.IPPoolStatus{IPAddresses: []corev1alpha1.IPAddressState{{
+			IPAddress: testPodIP,
+			Owner:     corev1alpha1.IPAddressOwner{Namespace: "default", Pod: "pod1", ContainerID: testPodContainer},
+		}}},
+	}))
+	return NewResponder(ofClient, ifaceStore, testGatewayMAC, ipPoolInformer), ofClient
+}
+
+// newRequest builds a DHCP request of the test Pod with the specified message type and options.
+func newRequest(t *testing.T, msgType byte, clientIP string, options ...protocol.DHCPOption) []byte {
+	req := &protocol.DHCP{
+		Operation:    protocol.DHCPOperation(bootRequest),
+		HardwareType: hwEthernet,
+		HardwareLen:  uint8(len(testPodMAC)),
+		Xid:          0x1234,
+		ClientIP:     net.ParseIP(clientIP).To4(),
+		YourIP:       make(net.IP, net.IPv4len),
+		ServerIP:     make(net.IP, net.IPv4len),
+		GatewayIP:    make(net.IP, net.IPv4len),
+		ClientHWAddr: testPodMAC,
+		Options:      append([]protocol.DHCPOption{protocol.DHCPNewOption(protocol.DHCP_OPT_MESSAGE_TYPE, []byte{msgType})}, options...),
+	}
+	data := make([]byte, req.Len())
+	_, err := req.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func newDHCPPacketIn(inPort uint32, payload []byte) *ofctrl.PacketIn {
+	pktIn := &ofctrl.PacketIn{
+		TableId: uint8(openflow.SpoofGuardTable),
+		Match: openflow13.Match{Fields: []openflow13.MatchField{
+			*openflow13.NewInPortField(inPort),
+		}},
+	}
+	pktIn.Data = protocol.Ethernet{
+		Ethertype: protocol.IPv4_MSG,
+		Data: &protocol.IPv4{
+			Protocol: protocol.Type_UDP,
+			NWSrc:    net.IPv4zero,
+			NWDst:    net.IPv4bcast,
+			Data:     &protocol.UDP{PortSrc: clientPort, PortDst: serverPort, Data: payload},
+		},
+	}
+	return pktIn
+}
+
+// parseReply parses the DHCP reply and returns it with its message type.
+func parseReply(t *testing.T, data []byte) (*protocol.DHCP, byte) {
+	reply := new(protocol.DHCP)
+	_, err := reply.Write(data)
+	require.NoError(t, err)
+	assert.Equal(t, bootReply, byte(reply.Operation))
+	assert.Equal(t, uint32(0x1234), reply.Xid)
+	assert.Equal(t, testPodMAC, reply.ClientHWAddr)
+	options := map[byte][]byte{}
+	for _, option := range reply.Options {
+		options[option.OptionType()] = option.Bytes()
+	}
+	assert.Equal(t, []byte(net.ParseIP(testPoolGateway).To4()), options[protocol.DHCP_OPT_SERVER_ID])
+	msgType := options[protocol.DHCP_OPT_MESSAGE_TYPE][0]
+	if msgType != msgNak {
+		assert.Equal(t, []byte{0xff, 0xff, 0xff, 0}, options[protocol.DHCP_OPT_SUBNET_MASK])
+		assert.Equal(t, []byte(net.ParseIP(testPoolGateway).To4()), options[protocol.DHCP_OPT_DEFAULT_GATEWAY])
+		assert.Equal(t, leaseTime, binary.BigEndian.Uint32(options[protocol.DHCP_OPT_LEASE_TIME]))
+	}
+	return reply, msgType
+}
+
+func ipOption(t *testing.T, tag byte, ip string) protocol.DHCPOption {
+	option, err := protocol.DHCPIP4Option(tag, net.ParseIP(ip))
+	require.NoError(t, err)
+	return option
+}
+
+func TestHandlePacketIn(t *testing.T) {
+	tests := []struct {
+		name      string
+		inPort    uint32
+		payload   func(t *testing.T) []byte
+		replyType byte
+		dstIP     net.IP
+		yourIP    string
+	}{
+		{
+			name:   "discover",
+			inPort: testPodOFPort,
+			payload: func(t *testing.T) []byte {
+				return newRequest(t, msgDiscover, "0.0.0.0")
+			},
+			replyType: msgOffer,
+			dstIP:     net.IPv4bcast,
+			yourIP:    testPodIP,
+		},
+		{
+			name:   "request",
+			inPort: testPodOFPort,
+			payload: func(t *testing.T) []byte {
+				return newRequest(t, msgRequest, "0.0.0.0", ipOption(t, protocol.DHCP_OPT_REQUEST_IP, testPodIP), ipOption(t, protocol.DHCP_OPT_SERVER_ID, testPoolGateway))
+			},
+			replyType: msgAck,
+			dstIP:     net.IPv4bcast,
+			yourIP:    testPodIP,
+		},
+		{
+			name:   "renewal",
+			inPort: testPodOFPort,
+			payload: func(t *testing.T) []byte {
+				return newRequest(t, msgRequest, testPodIP)
+			},
+			replyType: msgAck,
+			dstIP:     net.ParseIP(testPodIP).To4(),
+			yourIP:    testPodIP,
+		},
+		{
+			name:   "request of another IP",
+			inPort: testPodOFPort,
+			payload: func(t *testing.T) []byte {
+				return newRequest(t, msgRequest, "0.0.0.0", ipOption(t, protocol.DHCP_OPT_REQUEST_IP, "192.168.240.20"))
+			},
+			replyType: msgNak,
+			dstIP:     net.IPv4bcast,
+			yourIP:    "0.0.0.0",
+		},
+		{
+			name:   "request to another server",
+			inPort: testPodOFPort,
+			payload: func(t *testing.T) []byte {
+				return newRequest(t, msgRequest, "0.0.0.0", ipOption(t, protocol.DHCP_OPT_REQUEST_IP, testPodIP), ipOption(t, protocol.DHCP_OPT_SERVER_ID, "192.168.240.2"))
+			},
+		},
+		{
+			name:   "Pod without IPPool",
+			inPort: testOtherOFPort,
+			payload: func(t *testing.T) []byte {
+				return newRequest(t, msgDiscover, "0.0.0.0")
+			},
+		},
+		{
+			name:   "unknown port",
+			inPort: 10,
+			payload: func(t *testing.T) []byte {
+				return newRequest(t, msgDiscover, "0.0.0.0")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ofClient := newTestResponder(t)
+			var replyData []byte
+			if tt.replyType != 0 {
+				ofClient.EXPECT().SendUDPPacketOut(testGatewayMAC, testPodMAC, net.ParseIP(testPoolGateway).To4(), tt.dstIP, serverPort, clientPort, gomock.Any(), tt.inPort).
+					Do(func(_, _ net.HardwareAddr, _, _ net.IP, _, _ uint16, data []byte, _ uint32) {
+						replyData = data
+					})
+			}
+			require.NoError(t, r.HandlePacketIn(newDHCPPacketIn(tt.inPort, tt.payload(t))))
+			if tt.replyType == 0 {
+				return
+			}
+			assert.Len(t, replyData, minMessageLen)
+			reply, replyType := parseReply(t, replyData)
+			assert.Equal(t, tt.replyType, replyType)
+			assert.Equal(t, net.ParseIP(tt.yourIP).To4(), reply.YourIP)
+		})
+	}
+}
+
+func TestParseRequestErrors(t *testing.T) {
+	valid := newRequest(t, msgDiscover, "0.0.0.0")
+	_, err := parseRequest(valid)
+	require.NoError(t, err)
+
+	_, err = parseRequest(valid[:headerLen-1])
+	assert.Error(t, err)
+	// A truncated option.
+	truncated := append(append([]byte{}, valid[:headerLen]...), protocol.DHCP_OPT_MESSAGE_TYPE, 4, msgDiscover)
+	_, err = parseRequest(truncated)
+	assert.Error(t, err)
+	// A reply.
+	reply := append([]byte{}, valid...)
+	reply[0] = bootReply
+	_, err = parseRequest(reply)
+	assert.Error(t, err)
+}
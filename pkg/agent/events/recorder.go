@@ -0,0 +1,169 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events reports the failures of antrea-agent to realize Kubernetes
+// and Antrea objects in the datapath as Events on the affected objects, so that
+// they are visible with "kubectl describe" and not only in the agent logs.
+package events
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+const (
+	// ReasonPodNetworkSetupFailed is the reason of the Events emitted when
+	// the network of a Pod cannot be set up by the CNI server.
+	ReasonPodNetworkSetupFailed = "PodNetworkSetupFailed"
+	// ReasonNetworkPolicyRealizationFailed is the reason of the Events
+	// emitted when the rules of a NetworkPolicy cannot be realized.
+	ReasonNetworkPolicyRealizationFailed = "NetworkPolicyRealizationFailed"
+	// ReasonServiceRealizationFailed is the reason of the Events emitted
+	// when the flows of a Service cannot be installed by AntreaProxy.
+	ReasonServiceRealizationFailed = "ServiceRealizationFailed"
+	// ReasonEgressRealizationFailed is the reason of the Events emitted
+	// when the Egress IP, the SNAT rules or the SNAT flows of an Egress
+	// cannot be realized.
+	ReasonEgressRealizationFailed = "EgressRealizationFailed"
+	// ReasonEgressNodeChanged is the reason of the normal Events emitted
+	// when the Egress IP of an Egress fails over to another Node.
+	ReasonEgressNodeChanged = "EgressNodeChanged"
+
+	// getTimeout is the timeout of the requests getting the Pods and the
+	// Services the Events are emitted for.
+	getTimeout = 10 * time.Second
+)
+
+// Recorder emits warning Events for the datapath programming failures of the
+// antrea-agent modules. It's shared by the CNI server, the NetworkPolicy
+// controller, the Egress controller and AntreaProxy, so that all the Events
+// are emitted with the same source. A nil Recorder is valid and emits nothing.
+type Recorder struct {
+	kubeClient kubernetes.Interface
+	recorder   record.EventRecorder
+}
+
+// NewRecorder creates a Recorder emitting the Events with recorder. kubeClient
+// is used to get the Pods and the Services, whose UIDs are required for the
+// Events to be listed by "kubectl describe".
+func NewRecorder(kubeClient kubernetes.Interface, recorder record.EventRecorder) *Recorder {
+	return &Recorder{kubeClient: kubeClient, recorder: recorder}
+}
+
+// EventRecorder returns the underlying EventRecorder, for the modules which
+// emit other Events than failures.
+func (r *Recorder) EventRecorder() record.EventRecorder {
+	return r.recorder
+}
+
+// PodFailure emits a warning Event for the Pod. The Pod is retrieved
+// asynchronously, so that the caller is not blocked by the request.
+func (r *Recorder) PodFailure(namespace, name, reason, message string, err error) {
+	if r == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.TODO(), getTimeout)
+		defer cancel()
+		pod, getErr := r.kubeClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.Errorf("Failed to get Pod %s/%s to report the failure %q: %v", namespace, name, reason, getErr)
+			return
+		}
+		r.emit(pod, reason, message, err)
+	}()
+}
+
+// ServiceFailure emits a warning Event for the Service. The Service is
+// retrieved asynchronously, so that the caller is not blocked by the request.
+func (r *Recorder) ServiceFailure(namespace, name, reason, message string, err error) {
+	if r == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.TODO(), getTimeout)
+		defer cancel()
+		svc, getErr := r.kubeClient.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.Errorf("Failed to get Service %s/%s to report the failure %q: %v", namespace, name, reason, getErr)
+			return
+		}
+		r.emit(svc, reason, message, err)
+	}()
+}
+
+// NetworkPolicyFailure emits a warning Event for the original NetworkPolicy
+// the controlplane NetworkPolicy is created from: a K8s NetworkPolicy, an
+// Antrea NetworkPolicy or an Antrea ClusterNetworkPolicy.
+func (r *Recorder) NetworkPolicyFailure(policy *cpv1beta1.NetworkPolicyReference, reason, message string, err error) {
+	if r == nil || policy == nil {
+		return
+	}
+	ref := &corev1.ObjectReference{
+		Namespace: policy.Namespace,
+		Name:      policy.Name,
+		UID:       policy.UID,
+	}
+	switch policy.Type {
+	case cpv1beta1.K8sNetworkPolicy:
+		ref.APIVersion, ref.Kind = "networking.k8s.io/v1", "NetworkPolicy"
+	case cpv1beta1.AntreaNetworkPolicy:
+		ref.APIVersion, ref.Kind = secv1alpha1.SchemeGroupVersion.String(), "NetworkPolicy"
+	case cpv1beta1.AntreaClusterNetworkPolicy:
+		ref.APIVersion, ref.Kind = secv1alpha1.SchemeGroupVersion.String(), "ClusterNetworkPolicy"
+	}
+	r.emit(ref, reason, message, err)
+}
+
+// EgressFailure emits a warning Event for the Egress.
+func (r *Recorder) EgressFailure(egress *corev1alpha1.Egress, reason, message string, err error) {
+	if r == nil {
+		return
+	}
+	r.emit(egressReference(egress), reason, message, err)
+}
+
+// EgressNodeChanged emits a normal Event for the Egress when its IP is taken
+// over by newNode from oldNode.
+func (r *Recorder) EgressNodeChanged(egress *corev1alpha1.Egress, oldNode, newNode string) {
+	if r == nil {
+		return
+	}
+	r.recorder.Eventf(egressReference(egress), corev1.EventTypeNormal, ReasonEgressNodeChanged, "Egress IP %s moved from Node %s to Node %s", egress.Spec.EgressIP, oldNode, newNode)
+}
+
+func egressReference(egress *corev1alpha1.Egress) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion:      corev1alpha1.SchemeGroupVersion.String(),
+		Kind:            "Egress",
+		Name:            egress.Name,
+		UID:             egress.UID,
+		ResourceVersion: egress.ResourceVersion,
+	}
+}
+
+func (r *Recorder) emit(obj runtime.Object, reason, message string, err error) {
+	r.recorder.Eventf(obj, corev1.EventTypeWarning, reason, "%s: %v", message, err)
+}
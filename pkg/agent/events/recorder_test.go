@@ -0,0 +1,88 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+)
+
+func expectEvent(t *testing.T, recorder *record.FakeRecorder, expected string) {
+	select {
+	case event := <-recorder.Events:
+		assert.Equal(t, expected, event)
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected Event %q but got none", expected)
+	}
+}
+
+func expectNoEvent(t *testing.T, recorder *record.FakeRecorder) {
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("Expected no Event but got %q", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestPodFailure(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod1", UID: "uid1"}}
+	fakeRecorder := record.NewFakeRecorder(10)
+	r := NewRecorder(fake.NewSimpleClientset(pod), fakeRecorder)
+
+	r.PodFailure("ns1", "pod1", ReasonPodNetworkSetupFailed, "Failed to configure the interface", fmt.Errorf("ovs error"))
+	expectEvent(t, fakeRecorder, "Warning PodNetworkSetupFailed Failed to configure the interface: ovs error")
+
+	// No Event is emitted for a Pod which doesn't exist.
+	r.PodFailure("ns1", "pod2", ReasonPodNetworkSetupFailed, "Failed to configure the interface", fmt.Errorf("ovs error"))
+	expectNoEvent(t, fakeRecorder)
+}
+
+func TestServiceFailure(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "svc1", UID: "uid1"}}
+	fakeRecorder := record.NewFakeRecorder(10)
+	r := NewRecorder(fake.NewSimpleClientset(svc), fakeRecorder)
+
+	r.ServiceFailure("ns1", "svc1", ReasonServiceRealizationFailed, "Failed to install the Service flows", fmt.Errorf("group error"))
+	expectEvent(t, fakeRecorder, "Warning ServiceRealizationFailed Failed to install the Service flows: group error")
+}
+
+func TestNetworkPolicyAndEgressFailure(t *testing.T) {
+	fakeRecorder := record.NewFakeRecorder(10)
+	r := NewRecorder(fake.NewSimpleClientset(), fakeRecorder)
+
+	policy := &cpv1beta1.NetworkPolicyReference{Type: cpv1beta1.AntreaNetworkPolicy, Namespace: "ns1", Name: "anp1", UID: "uid1"}
+	r.NetworkPolicyFailure(policy, ReasonNetworkPolicyRealizationFailed, "Failed to realize rule", fmt.Errorf("flow error"))
+	expectEvent(t, fakeRecorder, "Warning NetworkPolicyRealizationFailed Failed to realize rule: flow error")
+
+	egress := &corev1alpha1.Egress{ObjectMeta: metav1.ObjectMeta{Name: "egress1", UID: "uid2"}}
+	r.EgressFailure(egress, ReasonEgressRealizationFailed, "Failed to assign the Egress IP", fmt.Errorf("netlink error"))
+	expectEvent(t, fakeRecorder, "Warning EgressRealizationFailed Failed to assign the Egress IP: netlink error")
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.PodFailure("ns1", "pod1", ReasonPodNetworkSetupFailed, "Failed to configure the interface", fmt.Errorf("ovs error"))
+	r.NetworkPolicyFailure(&cpv1beta1.NetworkPolicyReference{}, ReasonNetworkPolicyRealizationFailed, "Failed to realize rule", fmt.Errorf("flow error"))
+}
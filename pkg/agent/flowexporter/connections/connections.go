@@ -27,7 +27,6 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
 	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
-	"github.com/vmware-tanzu/antrea/pkg/agent/proxy"
 )
 
 var serviceProtocolMap = map[uint8]corev1.Protocol{
@@ -37,43 +36,110 @@ var serviceProtocolMap = map[uint8]corev1.Protocol{
 }
 
 type ConnectionStore struct {
-	connections   map[flowexporter.ConnectionKey]flowexporter.Connection
-	connDumper    ConnTrackDumper
-	ifaceStore    interfacestore.InterfaceStore
-	serviceCIDR   *net.IPNet
-	antreaProxier proxy.Proxier
+	connections map[flowexporter.ConnectionKey]flowexporter.Connection
+	connDumper  ConnTrackDumper
+	ifaceStore  interfacestore.InterfaceStore
+	serviceCIDR *net.IPNet
+	// serviceQuerier is used to fill the destination Services of the connections to ClusterIPs. It is nil when
+	// AntreaProxy is not enabled.
+	serviceQuerier ServiceQuerier
+	nodeName       string
+	nodeQuerier    NodeQuerier
+	// egressQuerier is used to fill the egress configuration of the connections which leave the cluster. It is nil
+	// when the Agent does not SNAT these connections, e.g. in networkPolicyOnly mode.
+	egressQuerier EgressQuerier
 	pollInterval  time.Duration
-	mutex         sync.Mutex
+	// resyncInterval is the interval at which the conntrack table is dumped when the connections are tracked with
+	// conntrack events. Conntrack events are not used if it is zero.
+	resyncInterval time.Duration
+	// pollBudget adapts the poll interval and the sampling of the polls to the CPU budget of the polls.
+	pollBudget   *pollBudget
+	exportFilter *ExportFilter
+	// rttDumper is used to get the RTT of TCP connections from the sockets of local Pods. It is nil when the RTT of
+	// connections is not collected.
+	rttDumper TCPRTTDumper
+	mutex     sync.Mutex
 }
 
-func NewConnectionStore(connTrackDumper ConnTrackDumper, ifaceStore interfacestore.InterfaceStore, serviceCIDR *net.IPNet, proxier proxy.Proxier, pollInterval time.Duration) *ConnectionStore {
+func NewConnectionStore(connTrackDumper ConnTrackDumper, ifaceStore interfacestore.InterfaceStore, serviceCIDR *net.IPNet, serviceQuerier ServiceQuerier, nodeName string, nodeQuerier NodeQuerier, egressQuerier EgressQuerier, pollInterval time.Duration, resyncInterval time.Duration, pollCPUBudget uint32, exportFilter *ExportFilter, rttDumper TCPRTTDumper) *ConnectionStore {
 	return &ConnectionStore{
-		connections:   make(map[flowexporter.ConnectionKey]flowexporter.Connection),
-		connDumper:    connTrackDumper,
-		ifaceStore:    ifaceStore,
-		serviceCIDR:   serviceCIDR,
-		antreaProxier: proxier,
-		pollInterval:  pollInterval,
+		connections:    make(map[flowexporter.ConnectionKey]flowexporter.Connection),
+		connDumper:     connTrackDumper,
+		ifaceStore:     ifaceStore,
+		serviceCIDR:    serviceCIDR,
+		serviceQuerier: serviceQuerier,
+		nodeName:       nodeName,
+		nodeQuerier:    nodeQuerier,
+		egressQuerier:  egressQuerier,
+		pollInterval:   pollInterval,
+		resyncInterval: resyncInterval,
+		pollBudget:     newPollBudget(pollCPUBudget, pollInterval),
+		exportFilter:   exportFilter,
+		rttDumper:      rttDumper,
 	}
 }
 
-// Run enables the periodical polling of conntrack connections, at the given flowPollInterval
+// Run enables the periodical polling of conntrack connections, at the given flowPollInterval. If the ConnTrackDumper
+// supports conntrack events and resyncInterval is not zero, the connections are updated incrementally with the
+// events instead, and the conntrack table is only dumped every resyncInterval to resync the connection store. When
+// the conntrack table is dumped at every poll cycle, the poll interval and the sampling of the polls are adapted to the
+// CPU budget of the polls.
 func (cs *ConnectionStore) Run(stopCh <-chan struct{}, pollDone chan struct{}) {
-	klog.Infof("Starting conntrack polling")
+	subscriber, ok := cs.connDumper.(ConnTrackEventSubscriber)
+	if ok && cs.resyncInterval != 0 {
+		klog.Infof("Starting conntrack event subscription")
+	} else {
+		subscriber = nil
+		klog.Infof("Starting conntrack polling")
+	}
 
 	pollTicker := time.NewTicker(cs.pollInterval)
-	defer pollTicker.Stop()
+	// The ticker is replaced when the poll interval is adapted to the CPU budget.
+	defer func() { pollTicker.Stop() }()
 
+	// eventCh is nil when conntrack events are not used or when the subscription has ended, in which case it is
+	// never selected.
+	var eventCh chan ConnTrackEvent
+	var lastPollTime time.Time
 	for {
 		select {
 		case <-stopCh:
-			break
+			return
+		case event, ok := <-eventCh:
+			if !ok {
+				klog.Errorf("Conntrack event subscription ended, will subscribe again in the next poll cycle")
+				eventCh = nil
+				continue
+			}
+			cs.handleConnTrackEvent(event)
 		case <-pollTicker.C:
-			_, err := cs.Poll()
-			if err != nil {
-				// Not failing here as errors can be transient and could be resolved in future poll cycles.
-				// TODO: Come up with a backoff/retry mechanism by increasing poll interval and adding retry timeout
-				klog.Errorf("Error during conntrack poll cycle: %v", err)
+			// Subscribe before dumping the connections, so that no change is missed in between.
+			if subscriber != nil && eventCh == nil {
+				eventCh = make(chan ConnTrackEvent)
+				if err := subscriber.SubscribeEvents(openflow.GetCtZone(openflow.CtZoneFeaturePipeline), eventCh, stopCh); err != nil {
+					klog.Errorf("Error when subscribing to conntrack events: %v", err)
+					eventCh = nil
+				}
+				lastPollTime = time.Time{}
+			}
+			// Connections are dumped at every poll cycle without conntrack events, and every resyncInterval with them.
+			if eventCh == nil || time.Since(lastPollTime) >= cs.resyncInterval {
+				pollStartTime := time.Now()
+				_, err := cs.Poll()
+				if eventCh == nil && cs.pollBudget.update(time.Since(pollStartTime)) {
+					pollTicker.Stop()
+					pollTicker = time.NewTicker(cs.pollBudget.interval)
+				}
+				if err != nil {
+					// Not failing here as errors can be transient and could be resolved in future poll cycles.
+					// TODO: Come up with a backoff/retry mechanism by increasing poll interval and adding retry timeout
+					klog.Errorf("Error during conntrack poll cycle: %v", err)
+				} else {
+					lastPollTime = time.Now()
+				}
+			}
+			if cs.rttDumper != nil {
+				cs.updateTCPRTTs()
 			}
 			// We need synchronization between ConnectionStore.Run and FlowExporter.Run go routines.
 			// ConnectionStore.Run (connection poll) should be done to start FlowExporter.Run (connection export); pollDone signal helps enabling this.
@@ -83,8 +149,33 @@ func (cs *ConnectionStore) Run(stopCh <-chan struct{}, pollDone chan struct{}) {
 	}
 }
 
+// handleConnTrackEvent updates the connection store with a conntrack event. A destroyed connection is marked as
+// inactive, so that its last flow record is exported and it is deleted afterwards, as if it was not found in the
+// conntrack table by Poll.
+func (cs *ConnectionStore) handleConnTrackEvent(event ConnTrackEvent) {
+	cs.addOrUpdateConn(event.Conn)
+	if event.Type != ConnTrackEventDestroy {
+		return
+	}
+	connKey := flowexporter.NewConnectionKey(event.Conn)
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	conn, exists := cs.connections[connKey]
+	if !exists {
+		return
+	}
+	if !conn.DoExport {
+		// Connections which are not exported never have flow records.
+		delete(cs.connections, connKey)
+		metrics.TotalAntreaConnectionsInConnTrackTable.Dec()
+		return
+	}
+	conn.IsActive = false
+	cs.connections[connKey] = conn
+}
+
 // addOrUpdateConn updates the connection if it is already present, i.e., update timestamp, counters etc.,
-// or adds a new Connection by 5-tuple of the flow along with local Pod and PodNameSpace.
+// or adds a new Connection by 5-tuple of the flow along with local Pod and PodNameSpace, and the Nodes of the Pods.
 func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
 	connKey := flowexporter.NewConnectionKey(conn)
 
@@ -100,6 +191,7 @@ func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
 		existingConn.OriginalPackets = conn.OriginalPackets
 		existingConn.ReverseBytes = conn.ReverseBytes
 		existingConn.ReversePackets = conn.ReversePackets
+		existingConn.TCPState = conn.TCPState
 		existingConn.IsActive = true
 		// Reassign the flow to update the map
 		cs.connections[connKey] = *existingConn
@@ -121,16 +213,24 @@ func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
 			conn.DestinationPodName = dIface.ContainerInterfaceConfig.PodName
 			conn.DestinationPodNamespace = dIface.ContainerInterfaceConfig.PodNamespace
 		}
-		// Do not export flow records of connections whose destination is local Pod and source is remote Pod.
-		// We export flow records only from "source node", where the connection is originated from. This is to avoid
-		// 2 copies of flow records at flow collector. This restriction will be removed when flow records store network policy rule ID.
-		// TODO: Remove this when network policy rule IDs are added to flow records.
-		if !srcFound && dstFound {
+		fillNodeNames(conn, cs.nodeName, cs.nodeQuerier)
+		// Do not export flow records of connections whose destination is local Pod and source is remote Pod, unless
+		// configured otherwise. By default, we export flow records only from "source node", where the connection is
+		// originated from. This is to avoid 2 copies of flow records at flow collector. When both Nodes export the
+		// connection, the 2 records can be correlated with their flow key hash.
+		if !srcFound && dstFound && !cs.exportFilter.ExportDestinationNodeFlows() {
+			conn.DoExport = false
+		}
+		// Conversely, when inter-Node connections are only exported by the destination Node, do not export the
+		// connections from a local Pod to a remote Pod. The connections to destinations which are not Pods have no
+		// destination Node and are still exported.
+		if srcFound && !dstFound && conn.DestinationNodeName != "" && conn.DestinationNodeName != cs.nodeName &&
+			!cs.exportFilter.ExportSourceNodeFlows() {
 			conn.DoExport = false
 		}
 
 		// Process Pod-to-Service flows when Antrea Proxy is enabled.
-		if cs.antreaProxier != nil {
+		if cs.serviceQuerier != nil {
 			if cs.serviceCIDR.Contains(conn.TupleOrig.DestinationAddress) {
 				clusterIP := conn.TupleOrig.DestinationAddress.String()
 				svcPort := conn.TupleOrig.DestinationPort
@@ -139,15 +239,22 @@ func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
 					klog.Warningf("Could not retrieve Service protocol: %v", err)
 				} else {
 					serviceStr := fmt.Sprintf("%s:%d/%s", clusterIP, svcPort, protocol)
-					servicePortName, exists := cs.antreaProxier.GetServiceByIP(serviceStr)
+					servicePortName, exists := cs.serviceQuerier.GetServiceByIP(serviceStr)
 					if !exists {
 						klog.Warningf("Could not retrieve the Service info from antrea-agent-proxier for the serviceStr: %s", serviceStr)
 					} else {
 						conn.DestinationServicePortName = servicePortName.String()
 					}
 				}
+				conn.IsHairpin = isHairpinConn(conn)
 			}
 		}
+		fillEgressInfo(conn, cs.nodeQuerier, cs.egressQuerier)
+		// Apply the sampling and filtering configuration of the Flow Exporter, which is done after the Pod and
+		// Service info is filled as the filter may depend on it.
+		if conn.DoExport && !cs.exportFilter.ShouldExport(conn) {
+			conn.DoExport = false
+		}
 		metrics.TotalAntreaConnectionsInConnTrackTable.Inc()
 		klog.V(4).Infof("New Antrea flow added: %v", conn)
 		// Add new antrea connection to connection store
@@ -155,6 +262,63 @@ func (cs *ConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
 	}
 }
 
+// updateTCPRTTs updates the smoothed RTT of the active IPv4 TCP connections of local Pods. The RTT is read from the
+// socket of the source Pod when it is local, and from the socket of the destination Pod otherwise. The sockets of each
+// Pod are dumped only once per call.
+func (cs *ConnectionStore) updateTCPRTTs() {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	podRTTs := make(map[string]map[flowexporter.TCPSocketKey]time.Duration)
+	for connKey, conn := range cs.connections {
+		if !conn.IsActive || conn.TupleOrig.Protocol != 6 || conn.TupleOrig.SourceAddress.To4() == nil {
+			continue
+		}
+		netNS, socketKey, found := cs.getPodSocket(&conn)
+		if !found {
+			continue
+		}
+		rtts, dumped := podRTTs[netNS]
+		if !dumped {
+			var err error
+			rtts, err = cs.rttDumper.DumpTCPRTTs(netNS)
+			if err != nil {
+				klog.Errorf("Error when getting the RTT of TCP connections: %v", err)
+			}
+			// A failed dump is not retried for the other connections of the Pod.
+			podRTTs[netNS] = rtts
+		}
+		if rtt, exists := rtts[socketKey]; exists {
+			conn.TCPRTT = rtt
+			cs.connections[connKey] = conn
+		}
+	}
+}
+
+// getPodSocket returns the network namespace of the local Pod which owns the socket of the connection, and the key of
+// the socket in that network namespace.
+func (cs *ConnectionStore) getPodSocket(conn *flowexporter.Connection) (string, flowexporter.TCPSocketKey, bool) {
+	// The socket of the client is connected to the original destination, i.e. the ClusterIP for Service connections.
+	if iface, found := cs.ifaceStore.GetInterfaceByIP(conn.TupleOrig.SourceAddress.String()); found && iface.Type == interfacestore.ContainerInterface && iface.NetNS != "" {
+		return iface.NetNS, flowexporter.TCPSocketKey{
+			LocalAddress:  conn.TupleOrig.SourceAddress.String(),
+			LocalPort:     conn.TupleOrig.SourcePort,
+			RemoteAddress: conn.TupleOrig.DestinationAddress.String(),
+			RemotePort:    conn.TupleOrig.DestinationPort,
+		}, true
+	}
+	// The socket of the server is connected to the translated source, in case the connection is SNATed.
+	if iface, found := cs.ifaceStore.GetInterfaceByIP(conn.TupleReply.SourceAddress.String()); found && iface.Type == interfacestore.ContainerInterface && iface.NetNS != "" {
+		return iface.NetNS, flowexporter.TCPSocketKey{
+			LocalAddress:  conn.TupleReply.SourceAddress.String(),
+			LocalPort:     conn.TupleReply.SourcePort,
+			RemoteAddress: conn.TupleReply.DestinationAddress.String(),
+			RemotePort:    conn.TupleReply.DestinationPort,
+		}, true
+	}
+	return "", flowexporter.TCPSocketKey{}, false
+}
+
 // GetConnByKey gets the connection in connection map given the connection key
 func (cs *ConnectionStore) GetConnByKey(flowTuple flowexporter.ConnectionKey) (*flowexporter.Connection, bool) {
 	cs.mutex.Lock()
@@ -192,14 +356,30 @@ func (cs *ConnectionStore) Poll() (int, error) {
 	// We do not expect any error as resetConn is not returning any error
 	cs.ForAllConnectionsDo(resetConn)
 
-	filteredConnsList, totalConns, err := cs.connDumper.DumpFlows(openflow.CtZone)
+	filteredConnsList, totalConns, err := cs.connDumper.DumpFlows(openflow.GetCtZone(openflow.CtZoneFeaturePipeline))
 	if err != nil {
 		return 0, err
 	}
 	// Update only the Connection store. IPFIX records are generated based on Connection store.
 	for _, conn := range filteredConnsList {
+		// New connections which are not sampled are ignored when the polls exceed their CPU budget.
+		if !cs.pollBudget.isSampled(conn) {
+			if _, exists := cs.GetConnByKey(flowexporter.NewConnectionKey(conn)); !exists {
+				continue
+			}
+		}
 		cs.addOrUpdateConn(conn)
 	}
+	// Connections which are not exported never have flow records, so they are deleted here once they are not
+	// present in conntrack table anymore.
+	deleteIfStale := func(key flowexporter.ConnectionKey, conn flowexporter.Connection) error {
+		if !conn.DoExport && !conn.IsActive {
+			delete(cs.connections, key)
+			metrics.TotalAntreaConnectionsInConnTrackTable.Dec()
+		}
+		return nil
+	}
+	cs.ForAllConnectionsDo(deleteIfStale)
 	connsLen := len(filteredConnsList)
 	filteredConnsList = nil
 	metrics.TotalConnectionsInConnTrackTable.Set(float64(totalConns))
@@ -227,6 +407,13 @@ func (cs *ConnectionStore) DeleteConnectionByKey(connKey flowexporter.Connection
 	return nil
 }
 
+// isHairpinConn returns true if the connection is from a Pod to a Service whose Endpoint selected by AntreaProxy is
+// the Pod itself. Such connections are committed with ServiceCTMark like other Service connections, and the source of
+// the reply tuple, i.e. the Endpoint, is the source of the original tuple.
+func isHairpinConn(conn *flowexporter.Connection) bool {
+	return conn.Mark == openflow.ServiceCTMark && conn.TupleOrig.SourceAddress.Equal(conn.TupleReply.SourceAddress)
+}
+
 // LookupServiceProtocol returns the corresponding Service protocol string for a given protocol identifier
 func lookupServiceProtocol(protoID uint8) (corev1.Protocol, error) {
 	serviceProto, found := serviceProtocolMap[protoID]
@@ -235,3 +422,36 @@ func lookupServiceProtocol(protoID uint8) (corev1.Protocol, error) {
 	}
 	return serviceProto, nil
 }
+
+// fillEgressInfo fills the egress configuration of the connections from a local Pod which leave the cluster, i.e. whose
+// destination, after DNAT, is not a Pod. As the destination is identified as a remote Pod by the nodeQuerier, nothing
+// is filled when it is nil.
+func fillEgressInfo(conn *flowexporter.Connection, nodeQuerier NodeQuerier, egressQuerier EgressQuerier) {
+	if egressQuerier == nil || nodeQuerier == nil || conn.SourcePodName == "" {
+		return
+	}
+	if conn.DestinationPodName != "" || conn.DestinationNodeName != "" {
+		return
+	}
+	if name, egressIP, nodeName, found := egressQuerier.GetEgress(conn.SourcePodNamespace, conn.SourcePodName); found {
+		conn.EgressName = name
+		conn.EgressIP = egressIP
+		conn.EgressNodeName = nodeName
+	}
+}
+
+// fillNodeNames fills the Node names of the source and destination Pods of the connection. Local Pods run on the
+// local Node, while the Node of a remote Pod is looked up by its IP with the nodeQuerier. The destination is the
+// reply source, which is the Endpoint selected for the connection if the destination is a Service.
+func fillNodeNames(conn *flowexporter.Connection, nodeName string, nodeQuerier NodeQuerier) {
+	if conn.SourcePodName != "" {
+		conn.SourceNodeName = nodeName
+	} else if nodeQuerier != nil {
+		conn.SourceNodeName, _ = nodeQuerier.GetNodeNameByPodIP(conn.TupleOrig.SourceAddress)
+	}
+	if conn.DestinationPodName != "" {
+		conn.DestinationNodeName = nodeName
+	} else if nodeQuerier != nil {
+		conn.DestinationNodeName, _ = nodeQuerier.GetNodeNameByPodIP(conn.TupleReply.SourceAddress)
+	}
+}
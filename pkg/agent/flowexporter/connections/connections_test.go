@@ -140,7 +140,8 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
 	mockProxier := proxytest.NewMockProxier(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, serviceCIDR, mockProxier, testPollInterval)
+	mockNodeQuerier := connectionstest.NewMockNodeQuerier(ctrl)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, serviceCIDR, mockProxier, "node1", mockNodeQuerier, nil, testPollInterval, 0, 0, nil, nil)
 
 	// Add flow1conn to the Connection map
 	testFlow1Tuple := flowexporter.NewConnectionKey(&testFlow1)
@@ -164,11 +165,16 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 		} else if i == 1 {
 			expConn.DestinationPodNamespace = "ns2"
 			expConn.DestinationPodName = "pod2"
+			expConn.SourceNodeName = "node2"
+			expConn.DestinationNodeName = "node1"
 			mockIfaceStore.EXPECT().GetInterfaceByIP(test.flow.TupleOrig.SourceAddress.String()).Return(nil, false)
 			mockIfaceStore.EXPECT().GetInterfaceByIP(test.flow.TupleReply.SourceAddress.String()).Return(interfaceFlow2, true)
+			mockNodeQuerier.EXPECT().GetNodeNameByPodIP(test.flow.TupleOrig.SourceAddress).Return("node2", true)
 		} else {
 			mockIfaceStore.EXPECT().GetInterfaceByIP(expConn.TupleOrig.SourceAddress.String()).Return(nil, false)
 			mockIfaceStore.EXPECT().GetInterfaceByIP(expConn.TupleReply.SourceAddress.String()).Return(nil, false)
+			mockNodeQuerier.EXPECT().GetNodeNameByPodIP(expConn.TupleOrig.SourceAddress).Return("", false)
+			mockNodeQuerier.EXPECT().GetNodeNameByPodIP(expConn.TupleReply.SourceAddress).Return("", false)
 
 			protocol, _ := lookupServiceProtocol(expConn.TupleOrig.Protocol)
 			serviceStr := fmt.Sprintf("%s:%d/%s", expConn.TupleOrig.DestinationAddress.String(), expConn.TupleOrig.DestinationPort, protocol)
@@ -183,6 +189,184 @@ func TestConnectionStore_addAndUpdateConn(t *testing.T) {
 	}
 }
 
+func TestConnectionStore_addEgressConn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metrics.InitializeConnectionMetrics()
+	podIP := net.IP{10, 10, 0, 2}
+	remotePodIP := net.IP{10, 10, 1, 3}
+	externalIP := net.IP{8, 8, 8, 8}
+	nodeIP := net.IP{192, 168, 1, 10}
+	podInterface := &interfacestore.InterfaceConfig{
+		InterfaceName: "interface1",
+		IP:            podIP,
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{
+			ContainerID:  "1",
+			PodName:      "pod1",
+			PodNamespace: "ns1",
+		},
+	}
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockNodeQuerier := connectionstest.NewMockNodeQuerier(ctrl)
+	connStore := NewConnectionStore(connectionstest.NewMockConnTrackDumper(ctrl), mockIfaceStore, nil, nil, "node1", mockNodeQuerier, NewNodeSNATQuerier("node1", nodeIP), testPollInterval, 0, 0, nil, nil)
+
+	// The connections leaving the cluster are SNATed by the local Node.
+	tuple, revTuple := makeTuple(&podIP, &externalIP, 6, 40000, 443)
+	externalFlow := flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true, DoExport: true}
+	mockIfaceStore.EXPECT().GetInterfaceByIP(podIP.String()).Return(podInterface, true)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(externalIP.String()).Return(nil, false)
+	mockNodeQuerier.EXPECT().GetNodeNameByPodIP(externalIP).Return("", false)
+	connStore.addOrUpdateConn(&externalFlow)
+	conn, _ := connStore.GetConnByKey(flowexporter.NewConnectionKey(&externalFlow))
+	assert.Equal(t, "", conn.EgressName)
+	assert.Equal(t, nodeIP, conn.EgressIP)
+	assert.Equal(t, "node1", conn.EgressNodeName)
+
+	// The connections to remote Pods do not leave the cluster.
+	tuple, revTuple = makeTuple(&podIP, &remotePodIP, 6, 40001, 80)
+	podFlow := flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true, DoExport: true}
+	mockIfaceStore.EXPECT().GetInterfaceByIP(podIP.String()).Return(podInterface, true)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(remotePodIP.String()).Return(nil, false)
+	mockNodeQuerier.EXPECT().GetNodeNameByPodIP(remotePodIP).Return("node2", true)
+	connStore.addOrUpdateConn(&podFlow)
+	conn, _ = connStore.GetConnByKey(flowexporter.NewConnectionKey(&podFlow))
+	assert.Nil(t, conn.EgressIP)
+	assert.Equal(t, "", conn.EgressNodeName)
+}
+
+func TestConnectionStore_addInterNodeConn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metrics.InitializeConnectionMetrics()
+	podIP := net.IP{10, 10, 0, 2}
+	remotePodIP := net.IP{10, 10, 1, 3}
+	externalIP := net.IP{8, 8, 8, 8}
+	podInterface := &interfacestore.InterfaceConfig{
+		InterfaceName: "interface1",
+		IP:            podIP,
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{
+			ContainerID:  "1",
+			PodName:      "pod1",
+			PodNamespace: "ns1",
+		},
+	}
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(podIP.String()).Return(podInterface, true).AnyTimes()
+	mockIfaceStore.EXPECT().GetInterfaceByIP(remotePodIP.String()).Return(nil, false).AnyTimes()
+	mockIfaceStore.EXPECT().GetInterfaceByIP(externalIP.String()).Return(nil, false).AnyTimes()
+	mockNodeQuerier := connectionstest.NewMockNodeQuerier(ctrl)
+	mockNodeQuerier.EXPECT().GetNodeNameByPodIP(remotePodIP).Return("node2", true).AnyTimes()
+	mockNodeQuerier.EXPECT().GetNodeNameByPodIP(externalIP).Return("", false).AnyTimes()
+
+	toRemotePod, toRemotePodReply := makeTuple(&podIP, &remotePodIP, 6, 40000, 80)
+	fromRemotePod, fromRemotePodReply := makeTuple(&remotePodIP, &podIP, 6, 40000, 80)
+	toExternal, toExternalReply := makeTuple(&podIP, &externalIP, 6, 40001, 443)
+	tests := []struct {
+		exporter          InterNodeFlowExporter
+		expToRemotePod    bool
+		expFromRemotePod  bool
+		expToExternalFlow bool
+	}{
+		{"", true, false, true},
+		{InterNodeFlowExporterSource, true, false, true},
+		{InterNodeFlowExporterDestination, false, true, true},
+		{InterNodeFlowExporterBoth, true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.exporter), func(t *testing.T) {
+			filter := NewExportFilter(0, nil, nil, nil, nil, false, tt.exporter)
+			connStore := NewConnectionStore(connectionstest.NewMockConnTrackDumper(ctrl), mockIfaceStore, nil, nil, "node1", mockNodeQuerier, nil, testPollInterval, 0, 0, filter, nil)
+			for _, c := range []struct {
+				tuple, revTuple flowexporter.Tuple
+				expExport       bool
+			}{
+				{toRemotePod, toRemotePodReply, tt.expToRemotePod},
+				{fromRemotePod, fromRemotePodReply, tt.expFromRemotePod},
+				{toExternal, toExternalReply, tt.expToExternalFlow},
+			} {
+				flow := flowexporter.Connection{TupleOrig: c.tuple, TupleReply: c.revTuple, IsActive: true, DoExport: true}
+				connStore.addOrUpdateConn(&flow)
+				conn, _ := connStore.GetConnByKey(flowexporter.NewConnectionKey(&flow))
+				assert.Equal(t, c.expExport, conn.DoExport, "Unexpected export decision for connection %s -> %s", c.tuple.SourceAddress, c.tuple.DestinationAddress)
+			}
+		})
+	}
+}
+
+func TestConnectionStore_addHairpinConn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metrics.InitializeConnectionMetrics()
+	// The Pod accesses a Service whose selected Endpoint is the Pod itself.
+	podIP := net.IP{10, 10, 10, 2}
+	clusterIP := net.IP{20, 20, 20, 30}
+	tuple, _ := makeTuple(&podIP, &clusterIP, 6, 40000, 80)
+	_, revTuple := makeTuple(&podIP, &podIP, 6, 40000, 8080)
+	hairpinFlow := flowexporter.Connection{
+		TupleOrig:  tuple,
+		TupleReply: revTuple,
+		Mark:       openflow.ServiceCTMark,
+		IsActive:   true,
+		DoExport:   true,
+	}
+	podInterface := &interfacestore.InterfaceConfig{
+		InterfaceName: "interface1",
+		IP:            podIP,
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{
+			ContainerID:  "1",
+			PodName:      "pod1",
+			PodNamespace: "ns1",
+		},
+	}
+	serviceCIDR := &net.IPNet{
+		IP:   net.IP{20, 20, 20, 0},
+		Mask: net.IPMask(net.ParseIP("255.255.255.0").To4()),
+	}
+	servicePortName := k8sproxy.ServicePortName{
+		NamespacedName: types.NamespacedName{
+			Namespace: "ns1",
+			Name:      "service1",
+		},
+		Port:     "80",
+		Protocol: v1.ProtocolTCP,
+	}
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	mockProxier := proxytest.NewMockProxier(ctrl)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, serviceCIDR, mockProxier, "node1", nil, nil, testPollInterval, 0, 0, nil, nil)
+
+	mockIfaceStore.EXPECT().GetInterfaceByIP(podIP.String()).Return(podInterface, true).Times(2)
+	mockProxier.EXPECT().GetServiceByIP("20.20.20.30:80/TCP").Return(servicePortName, true)
+	connStore.addOrUpdateConn(&hairpinFlow)
+
+	conn, ok := connStore.GetConnByKey(flowexporter.NewConnectionKey(&hairpinFlow))
+	require.True(t, ok)
+	assert.True(t, conn.IsHairpin)
+	assert.Equal(t, "pod1", conn.SourcePodName)
+	assert.Equal(t, "pod1", conn.DestinationPodName)
+	assert.Equal(t, servicePortName.String(), conn.DestinationServicePortName)
+
+	// A connection to a Service whose Endpoint is another Pod is not a hairpin connection.
+	otherPodIP := net.IP{10, 10, 10, 3}
+	_, revTuple = makeTuple(&podIP, &otherPodIP, 6, 40001, 8080)
+	tuple.SourcePort = 40001
+	serviceFlow := flowexporter.Connection{
+		TupleOrig:  tuple,
+		TupleReply: revTuple,
+		Mark:       openflow.ServiceCTMark,
+		IsActive:   true,
+		DoExport:   true,
+	}
+	mockIfaceStore.EXPECT().GetInterfaceByIP(podIP.String()).Return(podInterface, true)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(otherPodIP.String()).Return(nil, false)
+	mockProxier.EXPECT().GetServiceByIP("20.20.20.30:80/TCP").Return(servicePortName, true)
+	connStore.addOrUpdateConn(&serviceFlow)
+
+	conn, ok = connStore.GetConnByKey(flowexporter.NewConnectionKey(&serviceFlow))
+	require.True(t, ok)
+	assert.False(t, conn.IsHairpin)
+}
+
 func TestConnectionStore_ForAllConnectionsDo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -223,7 +407,7 @@ func TestConnectionStore_ForAllConnectionsDo(t *testing.T) {
 	// Create ConnectionStore
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, testPollInterval)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, "", nil, nil, testPollInterval, 0, 0, nil, nil)
 	// Add flows to the Connection store
 	for i, flow := range testFlows {
 		connStore.connections[*testFlowKeys[i]] = *flow
@@ -288,7 +472,7 @@ func TestConnectionStore_DeleteConnectionByKey(t *testing.T) {
 	// Create ConnectionStore
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, testPollInterval)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, "", nil, nil, testPollInterval, 0, 0, nil, nil)
 	// Add flows to the connection store.
 	for i, flow := range testFlows {
 		connStore.connections[*testFlowKeys[i]] = *flow
@@ -312,11 +496,11 @@ func TestConnectionStore_MetricSettingInPoll(t *testing.T) {
 	// Create ConnectionStore
 	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
 	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
-	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, testPollInterval)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, "", nil, nil, testPollInterval, 0, 0, nil, nil)
 	// Hard-coded conntrack occupancy metrics for test
 	TotalConnections := 0
 	MaxConnections := 300000
-	mockConnDumper.EXPECT().DumpFlows(uint16(openflow.CtZone)).Return(testFlows, TotalConnections, nil)
+	mockConnDumper.EXPECT().DumpFlows(openflow.GetCtZone(openflow.CtZoneFeaturePipeline)).Return(testFlows, TotalConnections, nil)
 	mockConnDumper.EXPECT().GetMaxConnections().Return(MaxConnections, nil)
 	connsLen, err := connStore.Poll()
 	require.Nil(t, err, fmt.Sprintf("Failed to add connections to connection store: %v", err))
@@ -325,6 +509,102 @@ func TestConnectionStore_MetricSettingInPoll(t *testing.T) {
 	checkMaxConnectionsMetric(t, MaxConnections)
 }
 
+func TestConnectionStore_handleConnTrackEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metrics.InitializeConnectionMetrics()
+
+	tuple, revTuple := makeTuple(&net.IP{1, 2, 3, 4}, &net.IP{4, 3, 2, 1}, 6, 65280, 255)
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(tuple.SourceAddress.String()).Return(nil, false).AnyTimes()
+	mockIfaceStore.EXPECT().GetInterfaceByIP(revTuple.SourceAddress.String()).Return(nil, false).AnyTimes()
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	connStore := NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, "", nil, nil, testPollInterval, time.Minute, 0, nil, nil)
+	connKey := flowexporter.NewConnectionKey(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple})
+
+	connStore.handleConnTrackEvent(ConnTrackEvent{
+		Type: ConnTrackEventNew,
+		Conn: &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true, DoExport: true, TCPState: "SYN_SENT"},
+	})
+	conn, exists := connStore.GetConnByKey(connKey)
+	require.True(t, exists)
+	assert.True(t, conn.IsActive)
+	assert.Equal(t, "SYN_SENT", conn.TCPState)
+
+	connStore.handleConnTrackEvent(ConnTrackEvent{
+		Type: ConnTrackEventUpdate,
+		Conn: &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true, DoExport: true, TCPState: "ESTABLISHED"},
+	})
+	conn, _ = connStore.GetConnByKey(connKey)
+	assert.True(t, conn.IsActive)
+	assert.Equal(t, "ESTABLISHED", conn.TCPState)
+
+	// The destroyed connection is kept until its last flow record is exported.
+	connStore.handleConnTrackEvent(ConnTrackEvent{
+		Type: ConnTrackEventDestroy,
+		Conn: &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true, DoExport: true, TCPState: "TIME_WAIT", OriginalPackets: 10},
+	})
+	conn, exists = connStore.GetConnByKey(connKey)
+	require.True(t, exists)
+	assert.False(t, conn.IsActive)
+	assert.Equal(t, uint64(10), conn.OriginalPackets)
+
+	// A destroyed connection which is not exported is deleted right away.
+	connStore.connections[connKey] = flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true}
+	connStore.handleConnTrackEvent(ConnTrackEvent{
+		Type: ConnTrackEventDestroy,
+		Conn: &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true, DoExport: true},
+	})
+	_, exists = connStore.GetConnByKey(connKey)
+	assert.False(t, exists)
+}
+
+// fakeEventSubscriber is a ConnTrackDumper which supports conntrack events.
+type fakeEventSubscriber struct {
+	*connectionstest.MockConnTrackDumper
+	eventCh chan<- ConnTrackEvent
+	ready   chan struct{}
+}
+
+func (f *fakeEventSubscriber) SubscribeEvents(zoneFilter uint16, eventCh chan<- ConnTrackEvent, stopCh <-chan struct{}) error {
+	f.eventCh = eventCh
+	close(f.ready)
+	return nil
+}
+
+func TestConnectionStore_RunWithConnTrackEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metrics.InitializeConnectionMetrics()
+
+	tuple, revTuple := makeTuple(&net.IP{1, 2, 3, 4}, &net.IP{4, 3, 2, 1}, 6, 65280, 255)
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(gomock.Any()).Return(nil, false).AnyTimes()
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	subscriber := &fakeEventSubscriber{MockConnTrackDumper: mockConnDumper, ready: make(chan struct{})}
+	// The conntrack table is only dumped once to resync the connection store after subscribing, as the resync
+	// interval does not elapse during the test.
+	mockConnDumper.EXPECT().DumpFlows(openflow.GetCtZone(openflow.CtZoneFeaturePipeline)).Return([]*flowexporter.Connection{}, 0, nil).Times(1)
+	mockConnDumper.EXPECT().GetMaxConnections().Return(300000, nil).Times(1)
+	connStore := NewConnectionStore(subscriber, mockIfaceStore, nil, nil, "", nil, nil, 10*time.Millisecond, time.Hour, 0, nil, nil)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	pollDone := make(chan struct{})
+	go connStore.Run(stopCh, pollDone)
+
+	<-pollDone
+	<-subscriber.ready
+	subscriber.eventCh <- ConnTrackEvent{
+		Type: ConnTrackEventNew,
+		Conn: &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, IsActive: true, DoExport: true},
+	}
+	<-pollDone
+	<-pollDone
+	_, exists := connStore.GetConnByKey(flowexporter.NewConnectionKey(&flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}))
+	assert.True(t, exists)
+}
+
 func checkAntreaConnectionMetrics(t *testing.T, numConns int) {
 	expectedAntreaConnectionCount := `
 	# HELP antrea_agent_conntrack_antrea_connection_count [ALPHA] Number of connections in the Antrea ZoneID of the conntrack table. This metric gets updated at an interval specified by flowPollInterval, a configuration parameter for the Agent.
@@ -354,3 +634,54 @@ func checkMaxConnectionsMetric(t *testing.T, maxConns int) {
 	err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expectedMaxConnectionsCount), "antrea_agent_conntrack_max_connection_count")
 	assert.NoError(t, err)
 }
+
+func TestConnectionStore_updateTCPRTTs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clientIP, serverIP, remoteIP := net.ParseIP("10.10.0.2"), net.ParseIP("10.10.0.3"), net.ParseIP("10.10.1.5")
+	clientIface := interfacestore.NewContainerInterface("pod1-abc", "pod1-abc", "pod1", "ns1", nil, clientIP)
+	clientIface.NetNS = "/var/run/netns/pod1"
+	serverIface := interfacestore.NewContainerInterface("pod2-abc", "pod2-abc", "pod2", "ns1", nil, serverIP)
+	serverIface.NetNS = "/var/run/netns/pod2"
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(clientIP.String()).Return(clientIface, true).AnyTimes()
+	mockIfaceStore.EXPECT().GetInterfaceByIP(serverIP.String()).Return(serverIface, true).AnyTimes()
+	mockIfaceStore.EXPECT().GetInterfaceByIP(remoteIP.String()).Return(nil, false).AnyTimes()
+
+	// Connections from the local client Pod, whose RTTs are read from its sockets.
+	clientTuple1, clientRevTuple1 := makeTuple(&clientIP, &remoteIP, 6, 40000, 80)
+	clientTuple2, clientRevTuple2 := makeTuple(&clientIP, &serverIP, 6, 40001, 8080)
+	// Connection from a remote Pod to the local server Pod, whose RTT is read from the socket of the server Pod.
+	serverTuple, serverRevTuple := makeTuple(&remoteIP, &serverIP, 6, 50000, 8080)
+	// UDP connections are ignored.
+	udpTuple, udpRevTuple := makeTuple(&clientIP, &remoteIP, 17, 40002, 53)
+	testFlows := []*flowexporter.Connection{
+		{TupleOrig: clientTuple1, TupleReply: clientRevTuple1, IsActive: true},
+		{TupleOrig: clientTuple2, TupleReply: clientRevTuple2, IsActive: true},
+		{TupleOrig: serverTuple, TupleReply: serverRevTuple, IsActive: true},
+		{TupleOrig: udpTuple, TupleReply: udpRevTuple, IsActive: true},
+	}
+
+	mockRTTDumper := connectionstest.NewMockTCPRTTDumper(ctrl)
+	// The sockets of each Pod are only dumped once.
+	mockRTTDumper.EXPECT().DumpTCPRTTs("/var/run/netns/pod1").Return(map[flowexporter.TCPSocketKey]time.Duration{
+		{LocalAddress: "10.10.0.2", LocalPort: 40000, RemoteAddress: "10.10.1.5", RemotePort: 80}:   2 * time.Millisecond,
+		{LocalAddress: "10.10.0.2", LocalPort: 40001, RemoteAddress: "10.10.0.3", RemotePort: 8080}: 100 * time.Microsecond,
+	}, nil)
+	mockRTTDumper.EXPECT().DumpTCPRTTs("/var/run/netns/pod2").Return(map[flowexporter.TCPSocketKey]time.Duration{
+		{LocalAddress: "10.10.0.3", LocalPort: 8080, RemoteAddress: "10.10.1.5", RemotePort: 50000}: 3 * time.Millisecond,
+	}, nil)
+	connStore := NewConnectionStore(connectionstest.NewMockConnTrackDumper(ctrl), mockIfaceStore, nil, nil, "", nil, nil, testPollInterval, 0, 0, nil, mockRTTDumper)
+	for _, flow := range testFlows {
+		connStore.connections[flowexporter.NewConnectionKey(flow)] = *flow
+	}
+	connStore.updateTCPRTTs()
+
+	expectedRTTs := []time.Duration{2 * time.Millisecond, 100 * time.Microsecond, 3 * time.Millisecond, 0}
+	for i, flow := range testFlows {
+		conn, exists := connStore.GetConnByKey(flowexporter.NewConnectionKey(flow))
+		require.True(t, exists)
+		assert.Equal(t, expectedRTTs[i], conn.TCPRTT)
+	}
+}
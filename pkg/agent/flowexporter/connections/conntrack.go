@@ -25,29 +25,44 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
 )
 
+// defaultZone is the default conntrack zone of the host, in which the connections of the Node and the hostNetwork
+// Pods are tracked.
+const defaultZone uint16 = 0
+
 // InitializeConnTrackDumper initializes the ConnTrackDumper interface for different OS and datapath types.
-func InitializeConnTrackDumper(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet, ovsctlClient ovsctl.OVSCtlClient, ovsDatapathType string) ConnTrackDumper {
+// hostNetworkFlows is true if the connections of the Node and the hostNetwork Pods should be dumped as well.
+func InitializeConnTrackDumper(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet, ovsctlClient ovsctl.OVSCtlClient, ovsDatapathType string, hostNetworkFlows bool) ConnTrackDumper {
 	var connTrackDumper ConnTrackDumper
 	if ovsDatapathType == ovsconfig.OVSDatapathSystem {
-		connTrackDumper = NewConnTrackSystem(nodeConfig, serviceCIDR)
+		connTrackDumper = NewConnTrackSystem(nodeConfig, serviceCIDR, hostNetworkFlows)
 	} else if ovsDatapathType == ovsconfig.OVSDatapathNetdev {
-		connTrackDumper = NewConnTrackOvsAppCtl(nodeConfig, serviceCIDR, ovsctlClient)
+		connTrackDumper = NewConnTrackOvsAppCtl(nodeConfig, serviceCIDR, ovsctlClient, hostNetworkFlows)
 	}
 	return connTrackDumper
 }
 
-func filterAntreaConns(conns []*flowexporter.Connection, nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet, zoneFilter uint16) []*flowexporter.Connection {
+// filterAntreaConns returns the connections in the given conntrack zone, except the connections through the gateway
+// interface. If hostNetworkFlows is true, the connections through the gateway interface are kept, and so are the
+// connections in the default zone of the host, except the ones of the local Pods which are already tracked in the
+// given zone.
+func filterAntreaConns(conns []*flowexporter.Connection, nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet, zoneFilter uint16, hostNetworkFlows bool) []*flowexporter.Connection {
 	filteredConns := conns[:0]
 	for _, conn := range conns {
-		if conn.Zone != zoneFilter {
+		if conn.Zone != zoneFilter && !(hostNetworkFlows && conn.Zone == defaultZone) {
 			continue
 		}
 		srcIP := conn.TupleOrig.SourceAddress
 		dstIP := conn.TupleReply.SourceAddress
 
-		// Only get Pod-to-Pod flows.
-		if srcIP.Equal(nodeConfig.GatewayConfig.IP) || dstIP.Equal(nodeConfig.GatewayConfig.IP) {
-			klog.V(4).Infof("Detected flow through gateway :%v", conn)
+		if conn.Zone == zoneFilter {
+			// Only get Pod-to-Pod flows, unless the flows of the Node are requested.
+			if !hostNetworkFlows && (srcIP.Equal(nodeConfig.GatewayConfig.IP) || dstIP.Equal(nodeConfig.GatewayConfig.IP)) {
+				klog.V(4).Infof("Detected flow through gateway :%v", conn)
+				continue
+			}
+		} else if nodeConfig.PodCIDR != nil && (nodeConfig.PodCIDR.Contains(srcIP) || nodeConfig.PodCIDR.Contains(dstIP)) {
+			// The connections of the local Pods which are forwarded by the host are also tracked in the default zone.
+			klog.V(4).Infof("Detected flow of local Pod in default zone :%v", conn)
 			continue
 		}
 
@@ -19,8 +19,11 @@ package connections
 import (
 	"fmt"
 	"net"
+	"time"
 
+	"github.com/mdlayher/netlink"
 	"github.com/ti-mo/conntrack"
+	"github.com/ti-mo/netfilter"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
@@ -28,16 +31,26 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/sysctl"
 )
 
-// connTrackSystem implements ConnTrackDumper. This is for linux kernel datapath.
+// connTrackEventQueueSize is the size of the queue between the netlink socket receiving the conntrack events and
+// the connection store. It absorbs the bursts of events while the connection store is locked by the Flow Exporter.
+const connTrackEventQueueSize = 4096
+
+// connTrackEventReadBuffer is the size of the receive buffer of the netlink socket subscribed to conntrack events.
+// Events are lost when it is full, and the connection store is then fixed at the next resync.
+const connTrackEventReadBuffer = 8 * 1024 * 1024
+
+// connTrackSystem implements ConnTrackDumper and ConnTrackEventSubscriber. This is for linux kernel datapath.
 var _ ConnTrackDumper = new(connTrackSystem)
+var _ ConnTrackEventSubscriber = new(connTrackSystem)
 
 type connTrackSystem struct {
-	nodeConfig  *config.NodeConfig
-	serviceCIDR *net.IPNet
-	connTrack   NetFilterConnTrack
+	nodeConfig       *config.NodeConfig
+	serviceCIDR      *net.IPNet
+	hostNetworkFlows bool
+	connTrack        NetFilterConnTrack
 }
 
-func NewConnTrackSystem(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet) *connTrackSystem {
+func NewConnTrackSystem(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet, hostNetworkFlows bool) *connTrackSystem {
 	if err := setupConntrackParameters(); err != nil {
 		// Do not fail, but continue after logging an error as we can still dump flows with missing information.
 		klog.Errorf("Error when setting up conntrack parameters, some information may be missing from exported flows: %v", err)
@@ -45,11 +58,13 @@ func NewConnTrackSystem(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet) *
 	return &connTrackSystem{
 		nodeConfig,
 		serviceCIDR,
+		hostNetworkFlows,
 		&netFilterConnTrack{},
 	}
 }
 
-// DumpFlows opens netlink connection and dumps all the flows in Antrea ZoneID of conntrack table.
+// DumpFlows opens netlink connection and dumps all the flows in Antrea ZoneID of conntrack table, and the flows in the
+// default zone if hostNetworkFlows is true.
 func (ct *connTrackSystem) DumpFlows(zoneFilter uint16) ([]*flowexporter.Connection, int, error) {
 	// Get connection to netlink socket
 	err := ct.connTrack.Dial()
@@ -57,33 +72,95 @@ func (ct *connTrackSystem) DumpFlows(zoneFilter uint16) ([]*flowexporter.Connect
 		return nil, 0, fmt.Errorf("error when getting netlink socket: %v", err)
 	}
 
-	// ZoneID filter is not supported currently in tl-mo/conntrack library.
-	// Link to issue: https://github.com/ti-mo/conntrack/issues/23
-	// Dump all flows in the conntrack table for now.
-	conns, err := ct.connTrack.DumpFilter(conntrack.Filter{})
+	// The kernel cannot filter the dump by zone, so the connections of the other zones are skipped before being
+	// decoded, which saves most of the processing of the dump on the Nodes whose conntrack table is mostly made of
+	// the connections of the host.
+	conns, totalConns, err := ct.connTrack.DumpFilter(func(zone uint16) bool {
+		return zone == zoneFilter || (ct.hostNetworkFlows && zone == defaultZone)
+	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("error when dumping flows from conntrack: %v", err)
 	}
 
-	filteredConns := filterAntreaConns(conns, ct.nodeConfig, ct.serviceCIDR, zoneFilter)
+	filteredConns := filterAntreaConns(conns, ct.nodeConfig, ct.serviceCIDR, zoneFilter, ct.hostNetworkFlows)
 	klog.V(2).Infof("No. of flow exporter considered flows in Antrea zoneID: %d", len(filteredConns))
 
-	return filteredConns, len(conns), nil
+	return filteredConns, totalConns, nil
 }
 
-// NetFilterConnTrack interface helps for testing the code that contains the third party library functions ("github.com/ti-mo/conntrack")
+// SubscribeEvents joins the conntrack multicast groups of netlink to receive the NEW, UPDATE and DESTROY events of
+// the connections, which are filtered like in DumpFlows.
+func (ct *connTrackSystem) SubscribeEvents(zoneFilter uint16, eventCh chan<- ConnTrackEvent, stopCh <-chan struct{}) error {
+	conn, err := conntrack.Dial(nil)
+	if err != nil {
+		return fmt.Errorf("error when getting netlink socket: %v", err)
+	}
+	if err := conn.SetReadBuffer(connTrackEventReadBuffer); err != nil {
+		klog.Warningf("Error when setting the receive buffer of the conntrack events socket: %v", err)
+	}
+	flowCh := make(chan conntrack.Event, connTrackEventQueueSize)
+	errCh, err := conn.Listen(flowCh, 1, netfilter.GroupsCT)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error when subscribing to conntrack events: %v", err)
+	}
+
+	go func() {
+		defer close(eventCh)
+		// Closing the socket also stops the worker receiving the events.
+		defer conn.Close()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case err := <-errCh:
+				klog.Errorf("Error when receiving conntrack events: %v", err)
+				return
+			case event := <-flowCh:
+				var eventType ConnTrackEventType
+				switch event.Type {
+				case conntrack.EventNew:
+					eventType = ConnTrackEventNew
+				case conntrack.EventUpdate:
+					eventType = ConnTrackEventUpdate
+				case conntrack.EventDestroy:
+					eventType = ConnTrackEventDestroy
+				default:
+					continue
+				}
+				if event.Flow == nil {
+					continue
+				}
+				conns := filterAntreaConns([]*flowexporter.Connection{netlinkFlowToAntreaConnection(event.Flow)}, ct.nodeConfig, ct.serviceCIDR, zoneFilter, ct.hostNetworkFlows)
+				if len(conns) == 0 {
+					continue
+				}
+				select {
+				case eventCh <- ConnTrackEvent{Type: eventType, Conn: conns[0]}:
+				case <-stopCh:
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// NetFilterConnTrack interface helps for testing the code that contains the third party library functions ("github.com/ti-mo/netfilter")
 type NetFilterConnTrack interface {
 	Dial() error
-	DumpFilter(filter conntrack.Filter) ([]*flowexporter.Connection, error)
+	// DumpFilter dumps the conntrack table and returns the connections in the zones accepted by zoneFilter, with the
+	// total number of connections in the table.
+	DumpFilter(zoneFilter func(zone uint16) bool) ([]*flowexporter.Connection, int, error)
 }
 
 type netFilterConnTrack struct {
-	netlinkConn *conntrack.Conn
+	netlinkConn *netfilter.Conn
 }
 
 func (nfct *netFilterConnTrack) Dial() error {
 	// Get netlink client in current namespace
-	conn, err := conntrack.Dial(nil)
+	conn, err := netfilter.Dial(nil)
 	if err != nil {
 		return err
 	}
@@ -91,22 +168,235 @@ func (nfct *netFilterConnTrack) Dial() error {
 	return nil
 }
 
-func (nfct *netFilterConnTrack) DumpFilter(filter conntrack.Filter) ([]*flowexporter.Connection, error) {
-	conns, err := nfct.netlinkConn.DumpFilter(filter)
+func (nfct *netFilterConnTrack) DumpFilter(zoneFilter func(zone uint16) bool) ([]*flowexporter.Connection, int, error) {
+	defer nfct.netlinkConn.Close()
+	req, err := netfilter.MarshalNetlink(netfilter.Header{
+		SubsystemID: netfilter.NFSubsysCTNetlink,
+		MessageType: ctGet,
+		// ProtoUnspec dumps both IPv4 and IPv6 connections.
+		Family: netfilter.ProtoUnspec,
+		Flags:  netlink.Request | netlink.Dump,
+	}, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	antreaConns := make([]*flowexporter.Connection, len(conns))
-	for i, conn := range conns {
-		antreaConns[i] = netlinkFlowToAntreaConnection(&conn)
+	msgs, err := nfct.netlinkConn.Query(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	antreaConns, err := decodeConnections(msgs, zoneFilter)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	klog.V(2).Infof("Finished dumping -- total no. of flows in conntrack: %d", len(antreaConns))
+	klog.V(2).Infof("Finished dumping -- total no. of flows in conntrack: %d, decoded flows: %d", len(msgs), len(antreaConns))
+	return antreaConns, len(msgs), nil
+}
+
+// ctGet is the message type of the conntrack netlink requests getting connections (IPCTNL_MSG_CT_GET).
+const ctGet netfilter.MessageType = 1
+
+// The types of the conntrack netlink attributes which are decoded, from linux/netfilter/nfnetlink_conntrack.h.
+const (
+	ctaTupleOrig     = 1
+	ctaTupleReply    = 2
+	ctaStatus        = 3
+	ctaProtoInfo     = 4
+	ctaTimeout       = 7
+	ctaMark          = 8
+	ctaCountersOrig  = 9
+	ctaCountersReply = 10
+	ctaID            = 12
+	ctaZone          = 18
+	ctaTimestamp     = 20
+
+	ctaTupleIP    = 1
+	ctaTupleProto = 2
+
+	ctaIPv4Src = 1
+	ctaIPv4Dst = 2
+	ctaIPv6Src = 3
+	ctaIPv6Dst = 4
 
-	nfct.netlinkConn.Close()
+	ctaProtoNum     = 1
+	ctaProtoSrcPort = 2
+	ctaProtoDstPort = 3
+
+	ctaProtoInfoTCP      = 1
+	ctaProtoInfoTCPState = 1
+
+	ctaCountersPackets = 1
+	ctaCountersBytes   = 2
+
+	ctaTimestampStart = 1
+	ctaTimestampStop  = 2
+)
+
+// decodeConnections decodes the connections of the conntrack dump which are in the zones accepted by zoneFilter.
+// The zone of each connection is read first, and the connections of the other zones are skipped without decoding
+// their other attributes.
+func decodeConnections(msgs []netlink.Message, zoneFilter func(zone uint16) bool) ([]*flowexporter.Connection, error) {
+	var antreaConns []*flowexporter.Connection
+	for _, msg := range msgs {
+		_, ad, err := netfilter.DecodeNetlink(msg)
+		if err != nil {
+			return nil, err
+		}
+		// The zone attribute is omitted for the connections of the default zone.
+		zone := defaultZone
+		for ad.Next() {
+			if ad.Type() == ctaZone {
+				zone = ad.Uint16()
+				break
+			}
+		}
+		if err := ad.Err(); err != nil {
+			return nil, err
+		}
+		if !zoneFilter(zone) {
+			continue
+		}
+		_, ad, err = netfilter.DecodeNetlink(msg)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := decodeConnection(ad)
+		if err != nil {
+			return nil, err
+		}
+		antreaConns = append(antreaConns, conn)
+	}
 	return antreaConns, nil
 }
 
+// decodeConnection decodes the attributes of a conntrack connection, like netlinkFlowToAntreaConnection converts the
+// connections decoded by the conntrack library.
+func decodeConnection(ad *netlink.AttributeDecoder) (*flowexporter.Connection, error) {
+	conn := &flowexporter.Connection{
+		IsActive: true,
+		DoExport: true,
+	}
+	for ad.Next() {
+		switch ad.Type() {
+		case ctaTupleOrig:
+			ad.Nested(decodeTuple(&conn.TupleOrig))
+		case ctaTupleReply:
+			ad.Nested(decodeTuple(&conn.TupleReply))
+		case ctaStatus:
+			conn.StatusFlag = ad.Uint32()
+		case ctaProtoInfo:
+			ad.Nested(func(nad *netlink.AttributeDecoder) error {
+				for nad.Next() {
+					if nad.Type() != ctaProtoInfoTCP {
+						continue
+					}
+					nad.Nested(func(tcpad *netlink.AttributeDecoder) error {
+						for tcpad.Next() {
+							if tcpad.Type() == ctaProtoInfoTCPState {
+								conn.TCPState = tcpStates[tcpad.Uint8()]
+							}
+						}
+						return nil
+					})
+				}
+				return nil
+			})
+		case ctaTimeout:
+			conn.Timeout = ad.Uint32()
+		case ctaMark:
+			conn.Mark = ad.Uint32()
+		case ctaCountersOrig:
+			ad.Nested(decodeCounters(&conn.OriginalPackets, &conn.OriginalBytes))
+		case ctaCountersReply:
+			ad.Nested(decodeCounters(&conn.ReversePackets, &conn.ReverseBytes))
+		case ctaID:
+			conn.ID = ad.Uint32()
+		case ctaZone:
+			conn.Zone = ad.Uint16()
+		case ctaTimestamp:
+			ad.Nested(func(nad *netlink.AttributeDecoder) error {
+				for nad.Next() {
+					switch nad.Type() {
+					case ctaTimestampStart:
+						conn.StartTime = time.Unix(0, int64(nad.Uint64()))
+					case ctaTimestampStop:
+						conn.StopTime = time.Unix(0, int64(nad.Uint64()))
+					}
+				}
+				return nil
+			})
+		}
+	}
+	if err := ad.Err(); err != nil {
+		return nil, fmt.Errorf("error when decoding conntrack connection: %v", err)
+	}
+	return conn, nil
+}
+
+func decodeTuple(tuple *flowexporter.Tuple) func(ad *netlink.AttributeDecoder) error {
+	return func(ad *netlink.AttributeDecoder) error {
+		for ad.Next() {
+			switch ad.Type() {
+			case ctaTupleIP:
+				ad.Nested(func(nad *netlink.AttributeDecoder) error {
+					for nad.Next() {
+						switch nad.Type() {
+						case ctaIPv4Src, ctaIPv6Src:
+							tuple.SourceAddress = net.IP(nad.Bytes())
+						case ctaIPv4Dst, ctaIPv6Dst:
+							tuple.DestinationAddress = net.IP(nad.Bytes())
+						}
+					}
+					return nil
+				})
+			case ctaTupleProto:
+				ad.Nested(func(nad *netlink.AttributeDecoder) error {
+					for nad.Next() {
+						switch nad.Type() {
+						case ctaProtoNum:
+							tuple.Protocol = nad.Uint8()
+						case ctaProtoSrcPort:
+							tuple.SourcePort = nad.Uint16()
+						case ctaProtoDstPort:
+							tuple.DestinationPort = nad.Uint16()
+						}
+					}
+					return nil
+				})
+			}
+		}
+		return nil
+	}
+}
+
+func decodeCounters(packets, bytes *uint64) func(ad *netlink.AttributeDecoder) error {
+	return func(ad *netlink.AttributeDecoder) error {
+		for ad.Next() {
+			switch ad.Type() {
+			case ctaCountersPackets:
+				*packets = ad.Uint64()
+			case ctaCountersBytes:
+				*bytes = ad.Uint64()
+			}
+		}
+		return nil
+	}
+}
+
+// tcpStates maps the TCP states of the Linux conntrack module (enum tcp_conntrack) to their names.
+var tcpStates = map[uint8]string{
+	0: "NONE",
+	1: "SYN_SENT",
+	2: "SYN_RECV",
+	3: "ESTABLISHED",
+	4: "FIN_WAIT",
+	5: "CLOSE_WAIT",
+	6: "LAST_ACK",
+	7: "TIME_WAIT",
+	8: "CLOSE",
+	9: "SYN_SENT2",
+}
+
 func netlinkFlowToAntreaConnection(conn *conntrack.Flow) *flowexporter.Connection {
 	tupleOrig := flowexporter.Tuple{
 		SourceAddress:      conn.TupleOrig.IP.SourceAddress,
@@ -132,6 +422,7 @@ func netlinkFlowToAntreaConnection(conn *conntrack.Flow) *flowexporter.Connectio
 		IsActive:                true,
 		DoExport:                true,
 		Zone:                    conn.Zone,
+		Mark:                    conn.Mark,
 		StatusFlag:              uint32(conn.Status.Value),
 		TupleOrig:               tupleOrig,
 		TupleReply:              tupleReply,
@@ -144,6 +435,9 @@ func netlinkFlowToAntreaConnection(conn *conntrack.Flow) *flowexporter.Connectio
 		DestinationPodNamespace: "",
 		DestinationPodName:      "",
 	}
+	if conn.ProtoInfo.TCP != nil {
+		newConn.TCPState = tcpStates[conn.ProtoInfo.TCP.State]
+	}
 
 	return &newConn
 }
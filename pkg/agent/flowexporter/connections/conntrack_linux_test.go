@@ -22,9 +22,11 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/mdlayher/netlink"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/ti-mo/conntrack"
+	"github.com/ti-mo/netfilter"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
@@ -80,19 +82,57 @@ func TestConnTrackSystem_DumpFlows(t *testing.T) {
 	}
 	// Test the DumpFlows implementation of connTrackSystem
 	mockNetlinkCT := connectionstest.NewMockNetFilterConnTrack(ctrl)
-	connDumperDPSystem := NewConnTrackSystem(nodeConfig, serviceCIDR)
+	connDumperDPSystem := NewConnTrackSystem(nodeConfig, serviceCIDR, false)
 
 	connDumperDPSystem.connTrack = mockNetlinkCT
 	// Set expects for mocks
 	mockNetlinkCT.EXPECT().Dial().Return(nil)
-	mockNetlinkCT.EXPECT().DumpFilter(conntrack.Filter{}).Return(testFlows, nil)
+	mockNetlinkCT.EXPECT().DumpFilter(gomock.Any()).Return(testFlows, len(testFlows), nil)
 
-	conns, totalConns, err := connDumperDPSystem.DumpFlows(openflow.CtZone)
+	conns, totalConns, err := connDumperDPSystem.DumpFlows(openflow.GetCtZone(openflow.CtZoneFeaturePipeline))
 	assert.NoErrorf(t, err, "Dump flows function returned error: %v", err)
 	assert.Equal(t, 1, len(conns), "number of filtered connections should be equal")
 	assert.Equal(t, len(testFlows), totalConns, "Number of connections in conntrack table should be equal to testFlows")
 }
 
+func TestFilterAntreaConns_HostNetworkFlows(t *testing.T) {
+	_, podCIDR, _ := net.ParseCIDR("10.10.0.0/24")
+	nodeConfig := &config.NodeConfig{
+		PodCIDR:       podCIDR,
+		GatewayConfig: &config.GatewayConfig{IP: net.IP{10, 10, 0, 1}},
+	}
+	serviceCIDR := &net.IPNet{
+		IP:   net.IP{100, 50, 25, 0},
+		Mask: net.IPMask{255, 255, 255, 0},
+	}
+	newConn := func(srcIP, dstIP net.IP, zone uint16) *flowexporter.Connection {
+		tuple, revTuple := makeTuple(&srcIP, &dstIP, 6, 65280, 80)
+		return &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple, Zone: zone}
+	}
+	podToPod := newConn(net.IP{10, 10, 0, 2}, net.IP{10, 10, 0, 3}, openflow.CtZone)
+	// The connections SNAT'd by OVS are also tracked in the SNAT zone, which must not be reported twice.
+	podToExternalInSNATZone := newConn(net.IP{192, 168, 0, 1}, net.IP{8, 8, 8, 8}, openflow.SNATCtZone)
+	nodeToPod := newConn(net.IP{10, 10, 0, 1}, net.IP{10, 10, 0, 3}, openflow.CtZone)
+	nodeToNode := newConn(net.IP{192, 168, 0, 1}, net.IP{192, 168, 0, 2}, defaultZone)
+	podToExternalInDefaultZone := newConn(net.IP{10, 10, 0, 2}, net.IP{8, 8, 8, 8}, defaultZone)
+	otherZone := newConn(net.IP{192, 168, 0, 1}, net.IP{192, 168, 0, 2}, 100)
+
+	tests := []struct {
+		name             string
+		hostNetworkFlows bool
+		expConns         []*flowexporter.Connection
+	}{
+		{"pod-flows", false, []*flowexporter.Connection{podToPod}},
+		{"host-network-flows", true, []*flowexporter.Connection{podToPod, nodeToPod, nodeToNode}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conns := []*flowexporter.Connection{podToPod, podToExternalInSNATZone, nodeToPod, nodeToNode, podToExternalInDefaultZone, otherZone}
+			assert.Equal(t, tt.expConns, filterAntreaConns(conns, nodeConfig, serviceCIDR, openflow.GetCtZone(openflow.CtZoneFeaturePipeline), tt.hostNetworkFlows))
+		})
+	}
+}
+
 func TestConnTrackOvsAppCtl_DumpFlows(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -115,7 +155,7 @@ func TestConnTrackOvsAppCtl_DumpFlows(t *testing.T) {
 		Mask: net.IPMask{255, 255, 255, 0},
 	}
 
-	connDumper := NewConnTrackOvsAppCtl(nodeConfig, serviceCIDR, mockOVSCtlClient)
+	connDumper := NewConnTrackOvsAppCtl(nodeConfig, serviceCIDR, mockOVSCtlClient, false)
 	// Set expect call for mock ovsCtlClient
 	ovsctlCmdOutput := []byte("tcp,orig=(src=127.0.0.1,dst=127.0.0.1,sport=45218,dport=2379,packets=320108,bytes=24615344),reply=(src=127.0.0.1,dst=127.0.0.1,sport=2379,dport=45218,packets=239595,bytes=24347883),start=2020-07-24T05:07:03.998,id=3750535678,status=SEEN_REPLY|ASSURED|CONFIRMED|SRC_NAT_DONE|DST_NAT_DONE,timeout=86399,protoinfo=(state_orig=ESTABLISHED,state_reply=ESTABLISHED,wscale_orig=7,wscale_reply=7,flags_orig=WINDOW_SCALE|SACK_PERM|MAXACK_SET,flags_reply=WINDOW_SCALE|SACK_PERM|MAXACK_SET)\n" +
 		"tcp,orig=(src=127.0.0.1,dst=8.7.6.5,sport=45170,dport=2379,packets=80743,bytes=5416239),reply=(src=8.7.6.5,dst=127.0.0.1,sport=2379,dport=45170,packets=63361,bytes=4811261),start=2020-07-24T05:07:01.591,id=462801621,zone=65520,status=SEEN_REPLY|ASSURED|CONFIRMED|SRC_NAT_DONE|DST_NAT_DONE,timeout=86397,protoinfo=(state_orig=ESTABLISHED,state_reply=ESTABLISHED,wscale_orig=7,wscale_reply=7,flags_orig=WINDOW_SCALE|SACK_PERM|MAXACK_SET,flags_reply=WINDOW_SCALE|SACK_PERM|MAXACK_SET)\n" +
@@ -129,7 +169,9 @@ func TestConnTrackOvsAppCtl_DumpFlows(t *testing.T) {
 		IsActive:   true,
 		DoExport:   true,
 		Zone:       65520,
+		Mark:       openflow.ServiceCTMark,
 		StatusFlag: 0,
+		TCPState:   "ESTABLISHED",
 		TupleOrig: flowexporter.Tuple{
 			SourceAddress:      net.ParseIP("100.10.0.105"),
 			DestinationAddress: net.ParseIP("10.96.0.1"),
@@ -155,7 +197,7 @@ func TestConnTrackOvsAppCtl_DumpFlows(t *testing.T) {
 	}
 	mockOVSCtlClient.EXPECT().RunAppctlCmd("dpctl/dump-conntrack", false, "-m", "-s").Return(ovsctlCmdOutput, nil)
 
-	conns, totalConns, err := connDumper.DumpFlows(uint16(openflow.CtZone))
+	conns, totalConns, err := connDumper.DumpFlows(openflow.GetCtZone(openflow.CtZoneFeaturePipeline))
 	if err != nil {
 		t.Errorf("conntrackNetdev.DumpConnections function returned error: %v", err)
 	}
@@ -179,7 +221,7 @@ func TestSetupConnTrackParameters(t *testing.T) {
 }
 
 func TestConnTrackSystem_GetMaxConnections(t *testing.T) {
-	connDumperDPSystem := NewConnTrackSystem(&config.NodeConfig{}, &net.IPNet{})
+	connDumperDPSystem := NewConnTrackSystem(&config.NodeConfig{}, &net.IPNet{}, false)
 	maxConns, err := connDumperDPSystem.GetMaxConnections()
 	assert.NoErrorf(t, err, "GetMaxConnections function returned error: %v", err)
 	expMaxConns, err := sysctl.GetSysctlNet("nf_conntrack_max")
@@ -194,8 +236,147 @@ func TestConnTrackOvsAppCtl_GetMaxConnections(t *testing.T) {
 	// Set expect call of dpctl/ct-get-maxconns for mock ovsCtlClient
 	expMaxConns := 300000
 	mockOVSCtlClient.EXPECT().RunAppctlCmd("dpctl/ct-get-maxconns", false).Return([]byte(strconv.Itoa(expMaxConns)), nil)
-	connDumper := NewConnTrackOvsAppCtl(&config.NodeConfig{}, &net.IPNet{}, mockOVSCtlClient)
+	connDumper := NewConnTrackOvsAppCtl(&config.NodeConfig{}, &net.IPNet{}, mockOVSCtlClient, false)
 	maxConns, err := connDumper.GetMaxConnections()
 	assert.NoErrorf(t, err, "GetMaxConnections function returned error: %v", err)
 	assert.Equal(t, expMaxConns, maxConns, "The return value of GetMaxConnections function should be equal to the previous hard-coded value")
 }
+
+func TestNetlinkFlowToAntreaConnection_TCPState(t *testing.T) {
+	tcpFlow := &conntrack.Flow{
+		ProtoInfo: conntrack.ProtoInfo{TCP: &conntrack.ProtoInfoTCP{State: 7}},
+	}
+	assert.Equal(t, "TIME_WAIT", netlinkFlowToAntreaConnection(tcpFlow).TCPState)
+	udpFlow := &conntrack.Flow{}
+	assert.Equal(t, "", netlinkFlowToAntreaConnection(udpFlow).TCPState)
+}
+
+// newConnTrackMessage builds the conntrack netlink message of a TCP connection, as dumped by the kernel.
+func newConnTrackMessage(t testing.TB, srcIP, dstIP net.IP, srcPort, dstPort uint16, zone uint16, start time.Time) netlink.Message {
+	tuple := func(src, dst net.IP, sport, dport uint16) []netfilter.Attribute {
+		return []netfilter.Attribute{
+			{Type: ctaTupleIP, Nested: true, Children: []netfilter.Attribute{
+				{Type: ctaIPv4Src, Data: src.To4()},
+				{Type: ctaIPv4Dst, Data: dst.To4()},
+			}},
+			{Type: ctaTupleProto, Nested: true, Children: []netfilter.Attribute{
+				{Type: ctaProtoNum, Data: []byte{6}},
+				{Type: ctaProtoSrcPort, Data: netfilter.Uint16Bytes(sport)},
+				{Type: ctaProtoDstPort, Data: netfilter.Uint16Bytes(dport)},
+			}},
+		}
+	}
+	counters := func(packets, bytes uint64) []netfilter.Attribute {
+		return []netfilter.Attribute{
+			{Type: ctaCountersPackets, Data: netfilter.Uint64Bytes(packets)},
+			{Type: ctaCountersBytes, Data: netfilter.Uint64Bytes(bytes)},
+		}
+	}
+	attrs := []netfilter.Attribute{
+		{Type: ctaTupleOrig, Nested: true, Children: tuple(srcIP, dstIP, srcPort, dstPort)},
+		{Type: ctaTupleReply, Nested: true, Children: tuple(dstIP, srcIP, dstPort, srcPort)},
+		{Type: ctaProtoInfo, Nested: true, Children: []netfilter.Attribute{
+			{Type: ctaProtoInfoTCP, Nested: true, Children: []netfilter.Attribute{
+				{Type: ctaProtoInfoTCPState, Data: []byte{3}},
+			}},
+		}},
+		{Type: ctaCountersOrig, Nested: true, Children: counters(10, 1000)},
+		{Type: ctaCountersReply, Nested: true, Children: counters(20, 2000)},
+		{Type: ctaStatus, Data: netfilter.Uint32Bytes(0xe)},
+		{Type: ctaTimeout, Data: netfilter.Uint32Bytes(86399)},
+		{Type: ctaMark, Data: netfilter.Uint32Bytes(openflow.ServiceCTMark)},
+		{Type: ctaID, Data: netfilter.Uint32Bytes(1234)},
+		{Type: ctaTimestamp, Nested: true, Children: []netfilter.Attribute{
+			{Type: ctaTimestampStart, Data: netfilter.Uint64Bytes(uint64(start.UnixNano()))},
+		}},
+	}
+	// The zone attribute is omitted for the connections of the default zone.
+	if zone != defaultZone {
+		attrs = append(attrs, netfilter.Attribute{Type: ctaZone, Data: netfilter.Uint16Bytes(zone)})
+	}
+	msg, err := netfilter.MarshalNetlink(netfilter.Header{SubsystemID: netfilter.NFSubsysCTNetlink, MessageType: ctGet}, attrs)
+	require.NoError(t, err)
+	return msg
+}
+
+func antreaZoneFilter(zone uint16) bool {
+	return zone == openflow.GetCtZone(openflow.CtZoneFeaturePipeline)
+}
+
+func TestDecodeConnections(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	podIP, otherIP := net.ParseIP("10.10.0.2"), net.ParseIP("10.10.1.2")
+	msgs := []netlink.Message{
+		newConnTrackMessage(t, podIP, otherIP, 40000, 80, openflow.CtZone, start),
+		newConnTrackMessage(t, podIP, otherIP, 40001, 80, defaultZone, start),
+		newConnTrackMessage(t, podIP, otherIP, 40002, 80, 100, start),
+	}
+	// The connections of the other zones are skipped before their attributes are decoded: a connection with invalid
+	// attributes is not reported as an error.
+	invalidConn, err := netfilter.MarshalNetlink(netfilter.Header{SubsystemID: netfilter.NFSubsysCTNetlink, MessageType: ctGet}, []netfilter.Attribute{
+		{Type: ctaTupleOrig, Nested: true, Children: []netfilter.Attribute{{Type: ctaTupleProto, Nested: true, Children: []netfilter.Attribute{
+			{Type: ctaProtoSrcPort, Data: []byte{1}},
+		}}}},
+		{Type: ctaZone, Data: netfilter.Uint16Bytes(100)},
+	})
+	require.NoError(t, err)
+	msgs = append(msgs, invalidConn)
+
+	conns, err := decodeConnections(msgs, antreaZoneFilter)
+	require.NoError(t, err)
+	tuple, revTuple := makeTuple(&podIP, &otherIP, 6, 40000, 80)
+	tuple.SourceAddress, tuple.DestinationAddress = podIP.To4(), otherIP.To4()
+	revTuple.SourceAddress, revTuple.DestinationAddress = otherIP.To4(), podIP.To4()
+	assert.Equal(t, []*flowexporter.Connection{{
+		ID:              1234,
+		Timeout:         86399,
+		StartTime:       start,
+		IsActive:        true,
+		DoExport:        true,
+		Zone:            openflow.CtZone,
+		Mark:            openflow.ServiceCTMark,
+		StatusFlag:      0xe,
+		TCPState:        "ESTABLISHED",
+		TupleOrig:       tuple,
+		TupleReply:      revTuple,
+		OriginalPackets: 10,
+		OriginalBytes:   1000,
+		ReversePackets:  20,
+		ReverseBytes:    2000,
+	}}, conns)
+
+	conns, err = decodeConnections(msgs[:2], func(zone uint16) bool { return zone == defaultZone })
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+	assert.Equal(t, uint16(40001), conns[0].TupleOrig.SourcePort)
+
+	_, err = decodeConnections(msgs, func(uint16) bool { return true })
+	assert.Error(t, err)
+}
+
+// TestDecodeConnectionsSkipCost checks that skipping the connections of the other zones lowers the cost of the polls
+// on a Node whose conntrack table is mostly made of the connections of the host.
+func TestDecodeConnectionsSkipCost(t *testing.T) {
+	var msgs []netlink.Message
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		zone := defaultZone
+		if i%10 == 0 {
+			zone = openflow.CtZone
+		}
+		msgs = append(msgs, newConnTrackMessage(t, net.ParseIP("10.10.0.2"), net.ParseIP("10.10.1.2"), uint16(40000+i), 80, zone, start))
+	}
+	benchmarkDecode := func(zoneFilter func(zone uint16) bool) testing.BenchmarkResult {
+		return testing.Benchmark(func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeConnections(msgs, zoneFilter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	allZones := benchmarkDecode(func(uint16) bool { return true })
+	antreaZones := benchmarkDecode(antreaZoneFilter)
+	t.Logf("Decoding all the connections: %s, decoding the connections of the Antrea zones: %s", allZones, antreaZones)
+	assert.Less(t, antreaZones.NsPerOp(), allZones.NsPerOp()/2)
+}
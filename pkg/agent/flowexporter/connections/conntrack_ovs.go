@@ -34,6 +34,7 @@ var protocols = map[string]uint8{
 	"tcp":       6,
 	"udp":       17,
 	"ipv6-icmp": 58,
+	"icmpv6":    58,
 }
 
 // connTrackOvsCtl implements ConnTrackDumper. This supports OVS userspace datapath scenarios.
@@ -43,9 +44,12 @@ type connTrackOvsCtl struct {
 	nodeConfig   *config.NodeConfig
 	serviceCIDR  *net.IPNet
 	ovsctlClient ovsctl.OVSCtlClient
+	// The connections of the host are not tracked by the OVS userspace datapath, so hostNetworkFlows only keeps the
+	// connections through the gateway interface.
+	hostNetworkFlows bool
 }
 
-func NewConnTrackOvsAppCtl(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet, ovsctlClient ovsctl.OVSCtlClient) *connTrackOvsCtl {
+func NewConnTrackOvsAppCtl(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet, ovsctlClient ovsctl.OVSCtlClient, hostNetworkFlows bool) *connTrackOvsCtl {
 	if ovsctlClient == nil {
 		return nil
 	}
@@ -54,6 +58,7 @@ func NewConnTrackOvsAppCtl(nodeConfig *config.NodeConfig, serviceCIDR *net.IPNet
 		nodeConfig,
 		serviceCIDR,
 		ovsctlClient,
+		hostNetworkFlows,
 	}
 }
 
@@ -64,7 +69,7 @@ func (ct *connTrackOvsCtl) DumpFlows(zoneFilter uint16) ([]*flowexporter.Connect
 		return nil, 0, fmt.Errorf("error when dumping flows from conntrack: %v", err)
 	}
 
-	filteredConns := filterAntreaConns(conns, ct.nodeConfig, ct.serviceCIDR, zoneFilter)
+	filteredConns := filterAntreaConns(conns, ct.nodeConfig, ct.serviceCIDR, zoneFilter, ct.hostNetworkFlows)
 	klog.V(2).Infof("FlowExporter considered flows: %d", len(filteredConns))
 
 	return filteredConns, totalConns, nil
@@ -174,6 +179,23 @@ func flowStringToAntreaConnection(flow string, zoneFilter uint16) (*flowexporter
 				return nil, fmt.Errorf("conversion of timeout %s to int failed", fields[len(fields)-1])
 			}
 			conn.Timeout = uint32(val)
+		} else if strings.HasPrefix(fs, "mark=") {
+			fields := strings.Split(fs, "=")
+			val, err := strconv.ParseUint(fields[len(fields)-1], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("conversion of mark %s to int failed", fields[len(fields)-1])
+			}
+			conn.Mark = uint32(val)
+		} else if strings.Contains(fs, "state") {
+			// TCP state is given by the state field for the kernel datapath, and by the state_orig and
+			// state_reply fields for the userspace datapath. We use the state of the original direction.
+			// state field could be the last protoinfo field in ovs-dpctl output format.
+			fs = strings.TrimSuffix(fs, ")")
+
+			fields := strings.Split(fs, "=")
+			if key := strings.TrimPrefix(fields[len(fields)-2], "("); key == "state" || key == "state_orig" {
+				conn.TCPState = fields[len(fields)-1]
+			}
 		} else if strings.Contains(fs, "id") {
 			fields := strings.Split(fs, "=")
 			val, err := strconv.Atoi(fields[len(fields)-1])
@@ -0,0 +1,188 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
+)
+
+// Store is the interface of the connection stores whose connections are exported by the Flow Exporter.
+type Store interface {
+	ForAllConnectionsDo(callback flowexporter.ConnectionMapCallBack) error
+	DeleteConnectionByKey(connKey flowexporter.ConnectionKey) error
+}
+
+var _ Store = new(ConnectionStore)
+var _ Store = new(DenyConnectionStore)
+
+// networkPolicyDropTables are the tables in which packets are dropped by NetworkPolicy rules: the default drop flows
+// of K8s NetworkPolicies are in the default tables, and the drop rules of Antrea-native policies are in the metric
+// tables.
+var networkPolicyDropTables = map[binding.TableIDType]bool{
+	openflow.IngressDefaultTable: true,
+	openflow.EgressDefaultTable:  true,
+	openflow.IngressMetricTable:  true,
+	openflow.EgressMetricTable:   true,
+}
+
+// DenyConnectionStore stores the connections denied by NetworkPolicies. Packets dropped by NetworkPolicies are never
+// committed to conntrack, so they are sent to the Antrea Agent by the drop flows instead, and the packets with the
+// same 5-tuple are aggregated into a pseudo-connection. The pseudo-connections are never active in the conntrack sense,
+// so their flow records are expired once no packet has been dropped for the idle flow timeout.
+type DenyConnectionStore struct {
+	connections  map[flowexporter.ConnectionKey]flowexporter.Connection
+	ifaceStore   interfacestore.InterfaceStore
+	nodeName     string
+	nodeQuerier  NodeQuerier
+	exportFilter *ExportFilter
+	clock        clock.Clock
+	mutex        sync.Mutex
+}
+
+func NewDenyConnectionStore(ifaceStore interfacestore.InterfaceStore, nodeName string, nodeQuerier NodeQuerier, exportFilter *ExportFilter) *DenyConnectionStore {
+	return &DenyConnectionStore{
+		connections:  make(map[flowexporter.ConnectionKey]flowexporter.Connection),
+		ifaceStore:   ifaceStore,
+		nodeName:     nodeName,
+		nodeQuerier:  nodeQuerier,
+		exportFilter: exportFilter,
+		clock:        clock.RealClock{},
+	}
+}
+
+// HandlePacketIn handles the packet-in messages sent by the NetworkPolicy drop flows. Other packet-in messages,
+// including the Traceflow ones which can also be sent from the drop tables, are ignored.
+func (ds *DenyConnectionStore) HandlePacketIn(pktIn *ofctrl.PacketIn) error {
+	if !networkPolicyDropTables[binding.TableIDType(pktIn.TableId)] {
+		return nil
+	}
+	if pktIn.GetMatches().GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", openflow.TraceflowReg)) != nil {
+		return nil
+	}
+	conn, err := parseDeniedPacket(pktIn)
+	if err != nil {
+		return err
+	}
+	ds.addOrUpdateConn(conn)
+	return nil
+}
+
+// parseDeniedPacket builds a pseudo-connection from the IPv4 packet in a packet-in message.
+func parseDeniedPacket(pktIn *ofctrl.PacketIn) (*flowexporter.Connection, error) {
+	if pktIn.Data.Ethertype != protocol.IPv4_MSG {
+		return nil, fmt.Errorf("denied packet is not an IPv4 packet")
+	}
+	ipPacket, ok := pktIn.Data.Data.(*protocol.IPv4)
+	if !ok {
+		return nil, fmt.Errorf("invalid IPv4 packet")
+	}
+	tuple := flowexporter.Tuple{
+		SourceAddress:      ipPacket.NWSrc,
+		DestinationAddress: ipPacket.NWDst,
+		Protocol:           ipPacket.Protocol,
+	}
+	switch transport := ipPacket.Data.(type) {
+	case *protocol.TCP:
+		tuple.SourcePort = transport.PortSrc
+		tuple.DestinationPort = transport.PortDst
+	case *protocol.UDP:
+		tuple.SourcePort = transport.PortSrc
+		tuple.DestinationPort = transport.PortDst
+	}
+	return &flowexporter.Connection{
+		TupleOrig: tuple,
+		TupleReply: flowexporter.Tuple{
+			SourceAddress:      tuple.DestinationAddress,
+			DestinationAddress: tuple.SourceAddress,
+			Protocol:           tuple.Protocol,
+			SourcePort:         tuple.DestinationPort,
+			DestinationPort:    tuple.SourcePort,
+		},
+		OriginalPackets: 1,
+		OriginalBytes:   uint64(ipPacket.Length),
+		DoExport:        true,
+		IsDenied:        true,
+	}, nil
+}
+
+// addOrUpdateConn aggregates a denied packet into the pseudo-connection with the same 5-tuple, or adds a new
+// pseudo-connection along with the local Pods and the Nodes of the Pods.
+func (ds *DenyConnectionStore) addOrUpdateConn(conn *flowexporter.Connection) {
+	now := ds.clock.Now()
+	connKey := flowexporter.NewConnectionKey(conn)
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	if existingConn, exists := ds.connections[connKey]; exists {
+		existingConn.OriginalPackets += conn.OriginalPackets
+		existingConn.OriginalBytes += conn.OriginalBytes
+		existingConn.StopTime = now
+		ds.connections[connKey] = existingConn
+		klog.V(4).Infof("Denied connection updated: %v", existingConn)
+		return
+	}
+	conn.StartTime = now
+	conn.StopTime = now
+	if sIface, found := ds.ifaceStore.GetInterfaceByIP(conn.TupleOrig.SourceAddress.String()); found && sIface.Type == interfacestore.ContainerInterface {
+		conn.SourcePodName = sIface.ContainerInterfaceConfig.PodName
+		conn.SourcePodNamespace = sIface.ContainerInterfaceConfig.PodNamespace
+	}
+	if dIface, found := ds.ifaceStore.GetInterfaceByIP(conn.TupleOrig.DestinationAddress.String()); found && dIface.Type == interfacestore.ContainerInterface {
+		conn.DestinationPodName = dIface.ContainerInterfaceConfig.PodName
+		conn.DestinationPodNamespace = dIface.ContainerInterfaceConfig.PodNamespace
+	}
+	fillNodeNames(conn, ds.nodeName, ds.nodeQuerier)
+	// Denied connections which are not exported are not stored, as they would never be expired by the flow records.
+	if !ds.exportFilter.ShouldExport(conn) {
+		return
+	}
+	klog.V(4).Infof("New denied connection added: %v", conn)
+	ds.connections[connKey] = *conn
+}
+
+// ForAllConnectionsDo execute the callback for each denied connection.
+func (ds *DenyConnectionStore) ForAllConnectionsDo(callback flowexporter.ConnectionMapCallBack) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	for k, v := range ds.connections {
+		err := callback(k, v)
+		if err != nil {
+			klog.Errorf("Callback execution failed for denied flow with key: %v, conn: %v, err: %v", k, v, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteConnectionByKey deletes the denied connection given the connection key.
+func (ds *DenyConnectionStore) DeleteConnectionByKey(connKey flowexporter.ConnectionKey) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	if _, exists := ds.connections[connKey]; !exists {
+		return fmt.Errorf("denied connection with key %v doesn't exist in map", connKey)
+	}
+	delete(ds.connections, connKey)
+	return nil
+}
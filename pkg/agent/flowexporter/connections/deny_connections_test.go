@@ -0,0 +1,111 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	connectionstest "github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections/testing"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+)
+
+func newDeniedPacketIn(tableID uint8, srcIP, dstIP net.IP, srcPort, dstPort uint16, length uint16) *ofctrl.PacketIn {
+	return &ofctrl.PacketIn{
+		TableId: tableID,
+		Match:   *openflow13.NewMatch(),
+		Data: protocol.Ethernet{
+			Ethertype: protocol.IPv4_MSG,
+			Data: &protocol.IPv4{
+				NWSrc:    srcIP,
+				NWDst:    dstIP,
+				Protocol: protocol.Type_TCP,
+				Length:   length,
+				Data:     &protocol.TCP{PortSrc: srcPort, PortDst: dstPort},
+			},
+		},
+	}
+}
+
+func TestDenyConnectionStore_HandlePacketIn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ifaceStore := interfacestore.NewInterfaceStore()
+	podIP := net.ParseIP("10.10.0.2")
+	ifaceStore.AddInterface(interfacestore.NewContainerInterface("pod1-abcd", "c1", "pod1", "ns1", nil, podIP))
+	remoteIP := net.ParseIP("10.10.1.2")
+	mockNodeQuerier := connectionstest.NewMockNodeQuerier(ctrl)
+	mockNodeQuerier.EXPECT().GetNodeNameByPodIP(remoteIP).Return("node2", true)
+	ds := NewDenyConnectionStore(ifaceStore, "node1", mockNodeQuerier, nil)
+	fakeClock := clock.NewFakeClock(time.Now())
+	ds.clock = fakeClock
+	startTime := fakeClock.Now()
+
+	// Two packets of the same connection dropped by an ingress rule.
+	require.NoError(t, ds.HandlePacketIn(newDeniedPacketIn(uint8(openflow.IngressMetricTable), remoteIP, podIP, 35000, 80, 60)))
+	fakeClock.Step(time.Second)
+	require.NoError(t, ds.HandlePacketIn(newDeniedPacketIn(uint8(openflow.IngressMetricTable), remoteIP, podIP, 35000, 80, 52)))
+	// Packet-in messages from other tables are ignored.
+	require.NoError(t, ds.HandlePacketIn(newDeniedPacketIn(uint8(openflow.L2ForwardingOutTable), remoteIP, podIP, 35001, 80, 60)))
+	// Traceflow packets are ignored.
+	traceflowPktIn := newDeniedPacketIn(uint8(openflow.EgressDefaultTable), podIP, remoteIP, 35002, 80, 60)
+	traceflowPktIn.Match.AddField(*openflow13.NewRegMatchField(int(openflow.TraceflowReg), 1<<28, openflow13.NewNXRange(28, 31)))
+	require.NoError(t, ds.HandlePacketIn(traceflowPktIn))
+
+	var conns []flowexporter.Connection
+	ds.ForAllConnectionsDo(func(key flowexporter.ConnectionKey, conn flowexporter.Connection) error {
+		conns = append(conns, conn)
+		return nil
+	})
+	require.Len(t, conns, 1)
+	conn := conns[0]
+	assert.True(t, conn.IsDenied)
+	assert.True(t, conn.DoExport)
+	assert.Equal(t, uint64(2), conn.OriginalPackets)
+	assert.Equal(t, uint64(112), conn.OriginalBytes)
+	assert.Equal(t, startTime, conn.StartTime)
+	assert.Equal(t, fakeClock.Now(), conn.StopTime)
+	assert.Equal(t, uint16(35000), conn.TupleOrig.SourcePort)
+	assert.Equal(t, uint16(80), conn.TupleReply.SourcePort)
+	assert.Equal(t, "", conn.SourcePodName)
+	assert.Equal(t, "node2", conn.SourceNodeName)
+	assert.Equal(t, "pod1", conn.DestinationPodName)
+	assert.Equal(t, "ns1", conn.DestinationPodNamespace)
+	assert.Equal(t, "node1", conn.DestinationNodeName)
+
+	require.NoError(t, ds.DeleteConnectionByKey(flowexporter.NewConnectionKey(&conn)))
+	assert.Error(t, ds.DeleteConnectionByKey(flowexporter.NewConnectionKey(&conn)))
+}
+
+func TestDenyConnectionStore_Filter(t *testing.T) {
+	ds := NewDenyConnectionStore(interfacestore.NewInterfaceStore(), "", nil, NewExportFilter(0, []string{"ns1"}, nil, nil, nil, false, ""))
+	require.NoError(t, ds.HandlePacketIn(newDeniedPacketIn(uint8(openflow.EgressDefaultTable), net.ParseIP("10.10.0.2"), net.ParseIP("10.10.1.2"), 35000, 80, 60)))
+	// Denied connections which are not exported are not stored.
+	ds.ForAllConnectionsDo(func(key flowexporter.ConnectionKey, conn flowexporter.Connection) error {
+		t.Errorf("Unexpected denied connection %v", conn)
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"net"
+)
+
+var _ EgressQuerier = new(nodeSNATQuerier)
+
+// nodeSNATQuerier is the EgressQuerier used when the connections which leave the cluster are masqueraded by the Node
+// of the source Pod, with the IP of the Node.
+type nodeSNATQuerier struct {
+	nodeName string
+	nodeIP   net.IP
+}
+
+func NewNodeSNATQuerier(nodeName string, nodeIP net.IP) *nodeSNATQuerier {
+	return &nodeSNATQuerier{nodeName: nodeName, nodeIP: nodeIP}
+}
+
+func (q *nodeSNATQuerier) GetEgress(_, _ string) (string, net.IP, string, bool) {
+	return "", q.nodeIP, q.nodeName, true
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"hash/fnv"
+	"net"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+// InterNodeFlowExporter specifies which Node exports the connections between
+// a Pod and a Pod on a remote Node.
+type InterNodeFlowExporter string
+
+const (
+	// InterNodeFlowExporterSource exports inter-Node connections only from the
+	// Node of the source Pod.
+	InterNodeFlowExporterSource InterNodeFlowExporter = "source"
+	// InterNodeFlowExporterDestination exports inter-Node connections only
+	// from the Node of the destination Pod.
+	InterNodeFlowExporterDestination InterNodeFlowExporter = "destination"
+	// InterNodeFlowExporterBoth exports inter-Node connections from both
+	// Nodes.
+	InterNodeFlowExporterBoth InterNodeFlowExporter = "both"
+)
+
+// ExportFilter decides which connections are exported by the Flow Exporter. A
+// connection must satisfy all the configured criteria to be exported, and a
+// criterion based on the endpoints of the connection is satisfied if either the
+// source or the destination matches. The decision is made once, when the
+// connection is added to the ConnectionStore.
+type ExportFilter struct {
+	// samplingRate is N when 1 out of N connections is exported. Sampling is
+	// disabled when it is 0 or 1.
+	samplingRate uint32
+	namespaces   sets.String
+	// podSelector is matched against the labels of the local Pods, which are
+	// retrieved with podLister.
+	podSelector labels.Selector
+	podLister   corelisters.PodLister
+	cidrs       []*net.IPNet
+	// podFlowsOnly is true when only Pod-to-Pod and Pod-to-Service connections
+	// are exported.
+	podFlowsOnly bool
+	// interNodeFlowExporter is the Node(s) from which inter-Node connections
+	// are exported. The source Node is used when it is empty.
+	interNodeFlowExporter InterNodeFlowExporter
+}
+
+// NewExportFilter returns an ExportFilter with the given criteria. Empty
+// criteria are ignored. podLister must be provided if podSelector is not nil.
+func NewExportFilter(samplingRate uint32, namespaces []string, podSelector labels.Selector, podLister corelisters.PodLister, cidrs []*net.IPNet, podFlowsOnly bool, interNodeFlowExporter InterNodeFlowExporter) *ExportFilter {
+	return &ExportFilter{
+		samplingRate:          samplingRate,
+		namespaces:            sets.NewString(namespaces...),
+		podSelector:           podSelector,
+		podLister:             podLister,
+		cidrs:                 cidrs,
+		podFlowsOnly:          podFlowsOnly,
+		interNodeFlowExporter: interNodeFlowExporter,
+	}
+}
+
+// isSampled returns true if the connection is selected by sampling. The
+// connection key is hashed so that the decision doesn't depend on the order
+// in which connections are polled.
+func (f *ExportFilter) isSampled(conn *flowexporter.Connection) bool {
+	if f.samplingRate <= 1 {
+		return true
+	}
+	return connectionHash(conn)%f.samplingRate == 0
+}
+
+// connectionHash returns the hash of the connection key, on which the sampling
+// of connections is based.
+func connectionHash(conn *flowexporter.Connection) uint32 {
+	h := fnv.New32a()
+	for _, s := range flowexporter.NewConnectionKey(conn) {
+		h.Write([]byte(s))
+	}
+	return h.Sum32()
+}
+
+func (f *ExportFilter) matchNamespace(conn *flowexporter.Connection) bool {
+	if f.namespaces.Len() == 0 {
+		return true
+	}
+	return (conn.SourcePodName != "" && f.namespaces.Has(conn.SourcePodNamespace)) ||
+		(conn.DestinationPodName != "" && f.namespaces.Has(conn.DestinationPodNamespace))
+}
+
+func (f *ExportFilter) matchPodLabels(namespace, name string) bool {
+	if name == "" {
+		return false
+	}
+	pod, err := f.podLister.Pods(namespace).Get(name)
+	if err != nil {
+		klog.V(4).Infof("Could not get Pod %s/%s to match its labels: %v", namespace, name, err)
+		return false
+	}
+	return f.podSelector.Matches(labels.Set(pod.Labels))
+}
+
+func (f *ExportFilter) matchPodSelector(conn *flowexporter.Connection) bool {
+	if f.podSelector == nil {
+		return true
+	}
+	return f.matchPodLabels(conn.SourcePodNamespace, conn.SourcePodName) ||
+		f.matchPodLabels(conn.DestinationPodNamespace, conn.DestinationPodName)
+}
+
+func (f *ExportFilter) matchCIDR(conn *flowexporter.Connection) bool {
+	if len(f.cidrs) == 0 {
+		return true
+	}
+	for _, cidr := range f.cidrs {
+		if cidr.Contains(conn.TupleOrig.SourceAddress) || cidr.Contains(conn.TupleOrig.DestinationAddress) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPodFlow returns true if the source of the connection is a Pod, and its
+// destination is a Pod or a Service. A remote Pod is identified by the Node
+// name, which is only set for the IPs in the PodCIDR of a remote Node.
+func (f *ExportFilter) matchPodFlow(conn *flowexporter.Connection) bool {
+	if !f.podFlowsOnly {
+		return true
+	}
+	isSourcePod := conn.SourcePodName != "" || conn.SourceNodeName != ""
+	isDestinationPod := conn.DestinationPodName != "" || conn.DestinationNodeName != ""
+	return isSourcePod && (isDestinationPod || conn.DestinationServicePortName != "")
+}
+
+// ShouldExport returns true if the connection satisfies all the criteria of
+// the filter. A nil ExportFilter exports all connections.
+func (f *ExportFilter) ShouldExport(conn *flowexporter.Connection) bool {
+	if f == nil {
+		return true
+	}
+	return f.isSampled(conn) && f.matchNamespace(conn) && f.matchPodSelector(conn) && f.matchCIDR(conn) && f.matchPodFlow(conn)
+}
+
+// ExportSourceNodeFlows returns true if the connections from local Pods to
+// remote Pods should be exported.
+func (f *ExportFilter) ExportSourceNodeFlows() bool {
+	return f == nil || f.interNodeFlowExporter != InterNodeFlowExporterDestination
+}
+
+// ExportDestinationNodeFlows returns true if the connections from remote Pods
+// to local Pods should be exported. By default, inter-Node connections are only
+// exported by the source Node.
+func (f *ExportFilter) ExportDestinationNodeFlows() bool {
+	return f != nil && (f.interNodeFlowExporter == InterNodeFlowExporterDestination || f.interNodeFlowExporter == InterNodeFlowExporterBoth)
+}
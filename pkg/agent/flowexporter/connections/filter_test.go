@@ -0,0 +1,118 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+func TestExportFilter_ShouldExport(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	indexer.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", Labels: map[string]string{"app": "web"}}})
+	indexer.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "db", Labels: map[string]string{"app": "db"}}})
+	podLister := corelisters.NewPodLister(indexer)
+	_, cidr, _ := net.ParseCIDR("192.168.0.0/16")
+
+	tuple, revTuple := makeTuple(&net.IP{10, 10, 0, 1}, &net.IP{192, 168, 1, 1}, 6, 65280, 80)
+	webToExternal := &flowexporter.Connection{
+		TupleOrig:          tuple,
+		TupleReply:         revTuple,
+		SourcePodNamespace: "ns1",
+		SourcePodName:      "web",
+	}
+	tuple, revTuple = makeTuple(&net.IP{10, 10, 0, 2}, &net.IP{10, 10, 0, 1}, 6, 65280, 80)
+	dbToWeb := &flowexporter.Connection{
+		TupleOrig:               tuple,
+		TupleReply:              revTuple,
+		SourcePodNamespace:      "ns2",
+		SourcePodName:           "db",
+		DestinationPodNamespace: "ns1",
+		DestinationPodName:      "web",
+	}
+
+	tuple, revTuple = makeTuple(&net.IP{10, 10, 0, 1}, &net.IP{10, 96, 0, 10}, 6, 65280, 53)
+	webToService := &flowexporter.Connection{
+		TupleOrig:                  tuple,
+		TupleReply:                 revTuple,
+		SourcePodNamespace:         "ns1",
+		SourcePodName:              "web",
+		DestinationServicePortName: "kube-system/kube-dns:dns-tcp",
+	}
+	tuple, revTuple = makeTuple(&net.IP{10, 10, 1, 1}, &net.IP{10, 10, 0, 1}, 6, 65280, 80)
+	remotePodToWeb := &flowexporter.Connection{
+		TupleOrig:               tuple,
+		TupleReply:              revTuple,
+		SourceNodeName:          "node2",
+		DestinationPodNamespace: "ns1",
+		DestinationPodName:      "web",
+		DestinationNodeName:     "node1",
+	}
+
+	tests := []struct {
+		name      string
+		filter    *ExportFilter
+		conn      *flowexporter.Connection
+		expExport bool
+	}{
+		{"nil-filter", nil, webToExternal, true},
+		{"empty-filter", NewExportFilter(0, nil, nil, nil, nil, false, ""), webToExternal, true},
+		{"namespace-match-source", NewExportFilter(0, []string{"ns1"}, nil, nil, nil, false, ""), webToExternal, true},
+		{"namespace-match-destination", NewExportFilter(0, []string{"ns1"}, nil, nil, nil, false, ""), dbToWeb, true},
+		{"namespace-no-match", NewExportFilter(0, []string{"ns2"}, nil, nil, nil, false, ""), webToExternal, false},
+		{"pod-selector-match", NewExportFilter(0, nil, labels.SelectorFromSet(labels.Set{"app": "db"}), podLister, nil, false, ""), dbToWeb, true},
+		{"pod-selector-no-match", NewExportFilter(0, nil, labels.SelectorFromSet(labels.Set{"app": "db"}), podLister, nil, false, ""), webToExternal, false},
+		{"cidr-match", NewExportFilter(0, nil, nil, nil, []*net.IPNet{cidr}, false, ""), webToExternal, true},
+		{"cidr-no-match", NewExportFilter(0, nil, nil, nil, []*net.IPNet{cidr}, false, ""), dbToWeb, false},
+		{"all-criteria-must-match", NewExportFilter(0, []string{"ns1"}, nil, nil, []*net.IPNet{cidr}, false, ""), dbToWeb, false},
+		{"pod-flows-only-pod-to-pod", NewExportFilter(0, nil, nil, nil, nil, true, ""), dbToWeb, true},
+		{"pod-flows-only-remote-pod-to-pod", NewExportFilter(0, nil, nil, nil, nil, true, ""), remotePodToWeb, true},
+		{"pod-flows-only-pod-to-service", NewExportFilter(0, nil, nil, nil, nil, true, ""), webToService, true},
+		{"pod-flows-only-pod-to-external", NewExportFilter(0, nil, nil, nil, nil, true, ""), webToExternal, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expExport, tt.filter.ShouldExport(tt.conn))
+		})
+	}
+}
+
+func TestExportFilter_Sampling(t *testing.T) {
+	const samplingRate = 4
+	const numConns = 4000
+	filter := NewExportFilter(samplingRate, nil, nil, nil, nil, false, "")
+	sampled := 0
+	for i := 0; i < numConns; i++ {
+		tuple, revTuple := makeTuple(&net.IP{10, 10, byte(i >> 8), byte(i)}, &net.IP{10, 10, 1, 1}, 6, uint16(10000+i), 80)
+		conn := &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple}
+		export := filter.ShouldExport(conn)
+		// The decision is consistent for the same connection.
+		assert.Equal(t, export, filter.ShouldExport(conn))
+		if export {
+			sampled++
+		}
+	}
+	// Roughly 1 out of samplingRate connections are exported.
+	assert.InDelta(t, numConns/samplingRate, sampled, numConns/samplingRate*0.2)
+}
@@ -15,7 +15,11 @@
 package connections
 
 import (
+	"net"
+	"time"
+
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 )
 
 // ConnTrackDumper is an interface that is used to dump connections from conntrack module. This supports dumping through
@@ -27,3 +31,58 @@ type ConnTrackDumper interface {
 	// GetMaxConnections returns the size of the connection tracking table.
 	GetMaxConnections() (int, error)
 }
+
+// ConnTrackEventType is the type of a change of the conntrack table.
+type ConnTrackEventType uint8
+
+const (
+	ConnTrackEventNew ConnTrackEventType = iota
+	ConnTrackEventUpdate
+	ConnTrackEventDestroy
+)
+
+// ConnTrackEvent is a change of a connection of the conntrack table.
+type ConnTrackEvent struct {
+	Type ConnTrackEventType
+	Conn *flowexporter.Connection
+}
+
+// ConnTrackEventSubscriber is implemented by the ConnTrackDumpers which can report the changes of the conntrack table
+// as they happen, so that the connection store is updated incrementally instead of dumping the whole table at every
+// poll cycle.
+type ConnTrackEventSubscriber interface {
+	// SubscribeEvents sends the events of the connections which would be returned by DumpFlows to eventCh, until
+	// stopCh is closed. eventCh is closed when the subscription ends, either because stopCh is closed or because
+	// receiving the events failed, in which case events may have been lost.
+	SubscribeEvents(zoneFilter uint16, eventCh chan<- ConnTrackEvent, stopCh <-chan struct{}) error
+}
+
+// NodeQuerier is an interface that is used to look up the remote Node on which a Pod IP is allocated, so that the
+// Node names of remote Pods can be filled in the flow records.
+type NodeQuerier interface {
+	// GetNodeNameByPodIP returns the name of the remote Node whose PodCIDR contains the provided IP.
+	GetNodeNameByPodIP(podIP net.IP) (string, bool)
+}
+
+// ServiceQuerier is an interface that is used to look up the Service port of a ClusterIP, so that the destination
+// Services of the connections can be filled in the flow records. It is implemented by AntreaProxy.
+type ServiceQuerier interface {
+	// GetServiceByIP returns the Service port matching the provided string with the format
+	// "<ClusterIP>:<port>/<protocol>".
+	GetServiceByIP(serviceStr string) (k8sproxy.ServicePortName, bool)
+}
+
+// EgressQuerier is an interface that is used to look up the egress configuration used by the connections from a local
+// Pod which leave the cluster, so that the traffic seen by external collectors can be attributed to it.
+type EgressQuerier interface {
+	// GetEgress returns the name of the Egress applied to the provided local Pod, the IP its connections are SNATed
+	// to and the name of the Node where SNAT is done. The name is empty if the default SNAT of the Node is used.
+	GetEgress(podNamespace, podName string) (name string, egressIP net.IP, nodeName string, found bool)
+}
+
+// TCPRTTDumper is an interface that is used to dump the smoothed RTT measured by the TCP stack of a Pod for each of its
+// TCP connections.
+type TCPRTTDumper interface {
+	// DumpTCPRTTs returns the smoothed RTTs of the established TCP sockets in the provided network namespace.
+	DumpTCPRTTs(netNS string) (map[flowexporter.TCPSocketKey]time.Duration, error)
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"time"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
+)
+
+const (
+	// maxPollIntervalFactor is the maximum factor by which the poll interval
+	// is increased when polls exceed the CPU budget.
+	maxPollIntervalFactor = 8
+	// maxPollSamplingRate is the maximum sampling rate of the polls, when
+	// polls still exceed the CPU budget at the maximum poll interval.
+	maxPollSamplingRate = 16
+)
+
+// pollBudget adapts the conntrack polling to a CPU budget, which is the
+// percentage of the poll interval a poll may take. When a poll exceeds it, the
+// poll interval is doubled, up to maxPollIntervalFactor times the configured
+// interval. When polls still exceed it at the maximum interval, polls switch
+// to sampling: only 1 out of every samplingRate new connections is added to
+// the ConnectionStore, which reduces the processing of busy Nodes at the cost
+// of the accuracy of the exported flows. The adjustments are reverted one by
+// one when polls take less than half of the budget.
+type pollBudget struct {
+	// budgetPercent is the CPU budget. The polling is not adapted when it's 0.
+	budgetPercent uint32
+	baseInterval  time.Duration
+	interval      time.Duration
+	// samplingRate is N when 1 out of N new connections is polled. Sampling
+	// is disabled when it is 1.
+	samplingRate uint32
+}
+
+func newPollBudget(budgetPercent uint32, pollInterval time.Duration) *pollBudget {
+	metrics.ConntrackPollInterval.Set(pollInterval.Seconds())
+	metrics.ConntrackPollSamplingRate.Set(1)
+	return &pollBudget{
+		budgetPercent: budgetPercent,
+		baseInterval:  pollInterval,
+		interval:      pollInterval,
+		samplingRate:  1,
+	}
+}
+
+// update adjusts the polling given the duration of the last poll. It returns
+// true if the poll interval is changed.
+func (b *pollBudget) update(pollDuration time.Duration) bool {
+	metrics.ConntrackPollDuration.Set(pollDuration.Seconds())
+	if b.budgetPercent == 0 {
+		return false
+	}
+	budget := b.interval * time.Duration(b.budgetPercent) / 100
+	oldInterval := b.interval
+	if pollDuration > budget {
+		if b.interval < b.baseInterval*maxPollIntervalFactor {
+			b.interval *= 2
+			b.recordAdjustment("interval_increase", pollDuration)
+		} else if b.samplingRate < maxPollSamplingRate {
+			b.samplingRate *= 2
+			b.recordAdjustment("sampling_increase", pollDuration)
+		}
+	} else if pollDuration < budget/2 {
+		if b.samplingRate > 1 {
+			b.samplingRate /= 2
+			b.recordAdjustment("sampling_decrease", pollDuration)
+		} else if b.interval > b.baseInterval {
+			b.interval /= 2
+			b.recordAdjustment("interval_decrease", pollDuration)
+		}
+	}
+	return b.interval != oldInterval
+}
+
+func (b *pollBudget) recordAdjustment(adjustment string, pollDuration time.Duration) {
+	klog.Infof("Adjusted conntrack polling (%s) after a poll taking %v with a CPU budget of %d%%: poll interval is %v and sampling rate is %d",
+		adjustment, pollDuration, b.budgetPercent, b.interval, b.samplingRate)
+	metrics.ConntrackPollAdjustmentCount.WithLabelValues(adjustment).Inc()
+	metrics.ConntrackPollInterval.Set(b.interval.Seconds())
+	metrics.ConntrackPollSamplingRate.Set(float64(b.samplingRate))
+}
+
+// isSampled returns true if the new connection is added to the
+// ConnectionStore with the current sampling rate.
+func (b *pollBudget) isSampled(conn *flowexporter.Connection) bool {
+	if b.samplingRate <= 1 {
+		return true
+	}
+	return connectionHash(conn)%b.samplingRate == 0
+}
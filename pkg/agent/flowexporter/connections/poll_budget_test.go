@@ -0,0 +1,91 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+func TestPollBudget(t *testing.T) {
+	b := newPollBudget(10, time.Second)
+
+	// Polls within the budget don't change the polling.
+	assert.False(t, b.update(60*time.Millisecond))
+	assert.Equal(t, time.Second, b.interval)
+
+	// The interval is doubled up to 8 times the configured interval, after which the sampling rate is doubled.
+	for _, expected := range []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		assert.True(t, b.update(time.Second))
+		assert.Equal(t, expected, b.interval)
+		assert.Equal(t, uint32(1), b.samplingRate)
+	}
+	for _, expected := range []uint32{2, 4, 8, 16, 16} {
+		assert.False(t, b.update(time.Second))
+		assert.Equal(t, 8*time.Second, b.interval)
+		assert.Equal(t, expected, b.samplingRate)
+	}
+
+	// Polls above half of the budget don't revert the adjustments.
+	assert.False(t, b.update(500*time.Millisecond))
+	assert.Equal(t, uint32(16), b.samplingRate)
+
+	// The sampling is reverted first, then the interval.
+	for _, expected := range []uint32{8, 4, 2, 1} {
+		assert.False(t, b.update(100*time.Millisecond))
+		assert.Equal(t, expected, b.samplingRate)
+	}
+	for _, expected := range []time.Duration{4 * time.Second, 2 * time.Second, time.Second, time.Second} {
+		b.update(10 * time.Millisecond)
+		assert.Equal(t, expected, b.interval)
+	}
+}
+
+func TestPollBudgetDisabled(t *testing.T) {
+	b := newPollBudget(0, time.Second)
+	assert.False(t, b.update(time.Minute))
+	assert.Equal(t, time.Second, b.interval)
+	assert.Equal(t, uint32(1), b.samplingRate)
+}
+
+func TestPollBudgetIsSampled(t *testing.T) {
+	b := newPollBudget(10, time.Second)
+	var conns []*flowexporter.Connection
+	for port := uint16(1000); port < 1100; port++ {
+		tuple, revTuple := makeTuple(&net.IP{10, 10, 0, 1}, &net.IP{10, 10, 0, 2}, 6, port, 80)
+		conns = append(conns, &flowexporter.Connection{TupleOrig: tuple, TupleReply: revTuple})
+	}
+	countSampled := func() int {
+		count := 0
+		for _, conn := range conns {
+			if b.isSampled(conn) {
+				count++
+			}
+		}
+		return count
+	}
+	assert.Equal(t, len(conns), countSampled())
+	b.samplingRate = 4
+	sampled := countSampled()
+	assert.Greater(t, sampled, 0)
+	assert.Less(t, sampled, len(conns))
+	// The decision only depends on the connection.
+	assert.Equal(t, sampled, countSampled())
+}
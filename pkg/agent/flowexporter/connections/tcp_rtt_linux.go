@@ -0,0 +1,118 @@
+// +build linux
+
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+const (
+	// Size of struct inet_diag_req_v2.
+	sizeofInetDiagRequest = 56
+	// Size of struct inet_diag_msg, which is followed by the attributes requested with the ext field of the request.
+	sizeofInetDiagMsg = 72
+	// inetDiagInfo is the attribute carrying the struct tcp_info of a TCP socket.
+	inetDiagInfo = 2
+	// Offset of the tcpi_rtt field of struct tcp_info, which is the smoothed RTT in microseconds.
+	tcpInfoRTTOffset = 68
+	tcpEstablished   = 1
+)
+
+// inetDiagRequest is the struct inet_diag_req_v2 used to dump all the established IPv4 TCP sockets with their
+// tcp_info.
+type inetDiagRequest struct{}
+
+func (r *inetDiagRequest) Len() int {
+	return sizeofInetDiagRequest
+}
+
+func (r *inetDiagRequest) Serialize() []byte {
+	b := make([]byte, sizeofInetDiagRequest)
+	b[0] = unix.AF_INET
+	b[1] = unix.IPPROTO_TCP
+	b[2] = 1 << (inetDiagInfo - 1)
+	nl.NativeEndian().PutUint32(b[4:8], 1<<tcpEstablished)
+	// The socket ID is left empty, as all the sockets are dumped.
+	return b
+}
+
+type netlinkTCPRTTDumper struct{}
+
+// NewTCPRTTDumper returns a TCPRTTDumper which gets the tcp_info of the sockets of a network namespace with the
+// NETLINK_SOCK_DIAG netlink family.
+func NewTCPRTTDumper() *netlinkTCPRTTDumper {
+	return &netlinkTCPRTTDumper{}
+}
+
+func (d *netlinkTCPRTTDumper) DumpTCPRTTs(netNS string) (map[flowexporter.TCPSocketKey]time.Duration, error) {
+	var msgs [][]byte
+	// The netlink socket must be created in the network namespace of the Pod.
+	if err := ns.WithNetNSPath(netNS, func(_ ns.NetNS) error {
+		req := nl.NewNetlinkRequest(nl.SOCK_DIAG_BY_FAMILY, unix.NLM_F_DUMP)
+		req.AddData(&inetDiagRequest{})
+		var err error
+		msgs, err = req.Execute(unix.NETLINK_INET_DIAG, nl.SOCK_DIAG_BY_FAMILY)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("error when dumping TCP sockets in netns %s: %v", netNS, err)
+	}
+	rtts := make(map[flowexporter.TCPSocketKey]time.Duration, len(msgs))
+	for _, msg := range msgs {
+		key, rtt, err := parseInetDiagMsg(msg)
+		if err != nil {
+			return nil, err
+		}
+		rtts[key] = rtt
+	}
+	return rtts, nil
+}
+
+// parseInetDiagMsg parses a struct inet_diag_msg followed by the INET_DIAG_INFO attribute, and returns the addresses
+// of the socket and its smoothed RTT.
+func parseInetDiagMsg(msg []byte) (flowexporter.TCPSocketKey, time.Duration, error) {
+	if len(msg) < sizeofInetDiagMsg {
+		return flowexporter.TCPSocketKey{}, 0, fmt.Errorf("inet_diag message too short: %d bytes", len(msg))
+	}
+	// The socket ID starts at offset 4: the ports are in network order, followed by the source and destination
+	// addresses which take 16 bytes each.
+	key := flowexporter.TCPSocketKey{
+		LocalPort:     binary.BigEndian.Uint16(msg[4:6]),
+		RemotePort:    binary.BigEndian.Uint16(msg[6:8]),
+		LocalAddress:  net.IP(msg[8:12]).String(),
+		RemoteAddress: net.IP(msg[24:28]).String(),
+	}
+	attrs, err := nl.ParseRouteAttr(msg[sizeofInetDiagMsg:])
+	if err != nil {
+		return flowexporter.TCPSocketKey{}, 0, fmt.Errorf("error when parsing inet_diag attributes: %v", err)
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type == inetDiagInfo && len(attr.Value) >= tcpInfoRTTOffset+4 {
+			rtt := nl.NativeEndian().Uint32(attr.Value[tcpInfoRTTOffset : tcpInfoRTTOffset+4])
+			return key, time.Duration(rtt) * time.Microsecond, nil
+		}
+	}
+	return flowexporter.TCPSocketKey{}, 0, fmt.Errorf("tcp_info not found for socket %v", key)
+}
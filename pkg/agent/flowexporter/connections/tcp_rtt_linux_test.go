@@ -0,0 +1,58 @@
+// +build linux
+
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+func makeInetDiagMsg(tcpInfo []byte) []byte {
+	msg := make([]byte, sizeofInetDiagMsg)
+	msg[0] = unix.AF_INET
+	msg[1] = tcpEstablished
+	binary.BigEndian.PutUint16(msg[4:6], 40000)
+	binary.BigEndian.PutUint16(msg[6:8], 80)
+	copy(msg[8:12], []byte{10, 10, 0, 2})
+	copy(msg[24:28], []byte{10, 96, 0, 10})
+	if tcpInfo != nil {
+		msg = append(msg, nl.NewRtAttr(inetDiagInfo, tcpInfo).Serialize()...)
+	}
+	return msg
+}
+
+func TestParseInetDiagMsg(t *testing.T) {
+	tcpInfo := make([]byte, 104)
+	nl.NativeEndian().PutUint32(tcpInfo[tcpInfoRTTOffset:], 1500)
+	key, rtt, err := parseInetDiagMsg(makeInetDiagMsg(tcpInfo))
+	require.NoError(t, err)
+	assert.Equal(t, flowexporter.TCPSocketKey{LocalAddress: "10.10.0.2", LocalPort: 40000, RemoteAddress: "10.96.0.10", RemotePort: 80}, key)
+	assert.Equal(t, 1500*time.Microsecond, rtt)
+
+	_, _, err = parseInetDiagMsg(makeInetDiagMsg(nil))
+	assert.Error(t, err)
+	_, _, err = parseInetDiagMsg(make([]byte, 20))
+	assert.Error(t, err)
+}
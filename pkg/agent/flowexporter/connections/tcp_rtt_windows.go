@@ -0,0 +1,36 @@
+// +build windows
+
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connections
+
+import (
+	"errors"
+	"time"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+type unsupportedTCPRTTDumper struct{}
+
+// NewTCPRTTDumper returns a TCPRTTDumper which always fails, as the RTT of the TCP connections of Pods cannot be
+// dumped on Windows yet.
+func NewTCPRTTDumper() *unsupportedTCPRTTDumper {
+	return &unsupportedTCPRTTDumper{}
+}
+
+func (d *unsupportedTCPRTTDumper) DumpTCPRTTs(netNS string) (map[flowexporter.TCPSocketKey]time.Duration, error) {
+	return nil, errors.New("dumping the RTT of TCP connections is not supported on Windows")
+}
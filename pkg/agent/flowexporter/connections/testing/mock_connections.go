@@ -14,16 +14,17 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections (interfaces: ConnTrackDumper,NetFilterConnTrack)
+// Source: github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections (interfaces: ConnTrackDumper,NetFilterConnTrack,NodeQuerier,TCPRTTDumper)
 
 // Package testing is a generated GoMock package.
 package testing
 
 import (
 	gomock "github.com/golang/mock/gomock"
-	conntrack "github.com/ti-mo/conntrack"
 	flowexporter "github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	net "net"
 	reflect "reflect"
+	time "time"
 )
 
 // MockConnTrackDumper is a mock of ConnTrackDumper interface
@@ -118,12 +119,13 @@ func (mr *MockNetFilterConnTrackMockRecorder) Dial() *gomock.Call {
 }
 
 // DumpFilter mocks base method
-func (m *MockNetFilterConnTrack) DumpFilter(arg0 conntrack.Filter) ([]*flowexporter.Connection, error) {
+func (m *MockNetFilterConnTrack) DumpFilter(arg0 func(uint16) bool) ([]*flowexporter.Connection, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DumpFilter", arg0)
 	ret0, _ := ret[0].([]*flowexporter.Connection)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // DumpFilter indicates an expected call of DumpFilter
@@ -131,3 +133,79 @@ func (mr *MockNetFilterConnTrackMockRecorder) DumpFilter(arg0 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DumpFilter", reflect.TypeOf((*MockNetFilterConnTrack)(nil).DumpFilter), arg0)
 }
+
+// MockNodeQuerier is a mock of NodeQuerier interface
+type MockNodeQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeQuerierMockRecorder
+}
+
+// MockNodeQuerierMockRecorder is the mock recorder for MockNodeQuerier
+type MockNodeQuerierMockRecorder struct {
+	mock *MockNodeQuerier
+}
+
+// NewMockNodeQuerier creates a new mock instance
+func NewMockNodeQuerier(ctrl *gomock.Controller) *MockNodeQuerier {
+	mock := &MockNodeQuerier{ctrl: ctrl}
+	mock.recorder = &MockNodeQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNodeQuerier) EXPECT() *MockNodeQuerierMockRecorder {
+	return m.recorder
+}
+
+// GetNodeNameByPodIP mocks base method
+func (m *MockNodeQuerier) GetNodeNameByPodIP(arg0 net.IP) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeNameByPodIP", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetNodeNameByPodIP indicates an expected call of GetNodeNameByPodIP
+func (mr *MockNodeQuerierMockRecorder) GetNodeNameByPodIP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeNameByPodIP", reflect.TypeOf((*MockNodeQuerier)(nil).GetNodeNameByPodIP), arg0)
+}
+
+// MockTCPRTTDumper is a mock of TCPRTTDumper interface
+type MockTCPRTTDumper struct {
+	ctrl     *gomock.Controller
+	recorder *MockTCPRTTDumperMockRecorder
+}
+
+// MockTCPRTTDumperMockRecorder is the mock recorder for MockTCPRTTDumper
+type MockTCPRTTDumperMockRecorder struct {
+	mock *MockTCPRTTDumper
+}
+
+// NewMockTCPRTTDumper creates a new mock instance
+func NewMockTCPRTTDumper(ctrl *gomock.Controller) *MockTCPRTTDumper {
+	mock := &MockTCPRTTDumper{ctrl: ctrl}
+	mock.recorder = &MockTCPRTTDumperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTCPRTTDumper) EXPECT() *MockTCPRTTDumperMockRecorder {
+	return m.recorder
+}
+
+// DumpTCPRTTs mocks base method
+func (m *MockTCPRTTDumper) DumpTCPRTTs(arg0 string) (map[flowexporter.TCPSocketKey]time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DumpTCPRTTs", arg0)
+	ret0, _ := ret[0].(map[flowexporter.TCPSocketKey]time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DumpTCPRTTs indicates an expected call of DumpTCPRTTs
+func (mr *MockTCPRTTDumperMockRecorder) DumpTCPRTTs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DumpTCPRTTs", reflect.TypeOf((*MockTCPRTTDumper)(nil).DumpTCPRTTs), arg0)
+}
@@ -0,0 +1,344 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
+)
+
+const (
+	archiveFilePrefix = "flows-"
+	archiveFileSuffix = ".jsonl"
+	// archiveFileTimeFormat is used to name the archive files after the time
+	// they are created at, so that sorting the names sorts the files by age.
+	archiveFileTimeFormat = "20060102T150405.000000000Z"
+	// archiveMaxBufferedRows is the number of rows which are kept in memory
+	// while the archive cannot be written. The oldest rows are dropped beyond
+	// this limit.
+	archiveMaxBufferedRows = 10000
+)
+
+// ArchiveConfig is the configuration used to write flow records to local
+// files, as an audit trail of the connections.
+type ArchiveConfig struct {
+	// Directory is the directory in which the archive files are created.
+	Directory string
+	// MaxSize is the size in bytes after which a new archive file is
+	// started.
+	MaxSize int64
+	// MaxAge is the duration after which the rotated archive files are
+	// deleted. They are never deleted if it is zero.
+	MaxAge time.Duration
+	// CommitInterval is the interval after which the buffered records are
+	// written and synced to the archive file.
+	CommitInterval time.Duration
+	// HMACKey is the key used to compute the HMAC-SHA256 of the entries. The
+	// entries are chained with plain SHA-256 digests if it is empty.
+	HMACKey []byte
+}
+
+// archiveEntry is a line of an archive file. The entries form a hash chain
+// across all the files of the archive: Hash is the hex-encoded HMAC-SHA256,
+// or SHA-256 without key, of PrevHash followed by Record, and PrevHash is the
+// Hash of the previous entry, which is empty for the first entry of a chain.
+// Modifying, inserting or removing an entry breaks the chain, and without the
+// HMAC key the chain cannot be recomputed after such a change.
+type archiveEntry struct {
+	Record   json.RawMessage `json:"record"`
+	PrevHash string          `json:"prevHash"`
+	Hash     string          `json:"hash"`
+}
+
+func archiveEntryHash(key []byte, prevHash string, record []byte) string {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write([]byte(prevHash))
+	h.Write(record)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// archiveWriter buffers the flow records and appends them to the current
+// archive file every CommitInterval. A new file is started when the current
+// one exceeds MaxSize and after every restart, at which point the files older
+// than MaxAge are deleted.
+type archiveWriter struct {
+	config  *ArchiveConfig
+	mutex   sync.Mutex
+	rows    []*clickHouseRow
+	flushCh chan struct{}
+	// file is the archive file being written, nil until the next flush
+	// after a rotation.
+	file     *os.File
+	fileSize int64
+	// lastHash is the hash of the last entry of the archive. It is
+	// recovered from the newest archive file the first time a file is
+	// created.
+	lastHash      string
+	chainRestored bool
+	// now is the function used to name the archive files, replaced in
+	// tests.
+	now func() time.Time
+}
+
+func newArchiveWriter(config *ArchiveConfig) *archiveWriter {
+	return &archiveWriter{
+		config:  config,
+		flushCh: make(chan struct{}, 1),
+		now:     time.Now,
+	}
+}
+
+// addRecord buffers the provided flow record. It never blocks on the disk.
+func (w *archiveWriter) addRecord(record *flowexporter.FlowRecord) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.rows = append(w.rows, newClickHouseRow(record))
+	if len(w.rows) >= archiveMaxBufferedRows {
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run writes the buffered rows until stopCh is closed.
+func (w *archiveWriter) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(w.config.CommitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			w.flush()
+			w.closeFile()
+			return
+		case <-ticker.C:
+			w.flush()
+		case <-w.flushCh:
+			w.flush()
+		}
+	}
+}
+
+func (w *archiveWriter) flush() {
+	w.mutex.Lock()
+	rows := w.rows
+	w.rows = nil
+	w.mutex.Unlock()
+	if len(rows) == 0 {
+		return
+	}
+
+	if err := w.write(rows); err != nil {
+		klog.Errorf("Error when writing flow records to the archive in %s: %v", w.config.Directory, err)
+		// The file may have been partially written, the next rows are
+		// written to a new file.
+		w.closeFile()
+		w.requeue(rows)
+		return
+	}
+	klog.V(2).Infof("Archived %d flow records", len(rows))
+	if w.fileSize >= w.config.MaxSize {
+		w.closeFile()
+	}
+}
+
+// write appends the entries of the provided rows to the current archive file,
+// and syncs it so that they are not lost if the Node crashes.
+func (w *archiveWriter) write(rows []*clickHouseRow) error {
+	if w.file == nil {
+		if err := w.openFile(); err != nil {
+			return err
+		}
+	}
+	var buf bytes.Buffer
+	lastHash := w.lastHash
+	for _, row := range rows {
+		record, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		entry := archiveEntry{Record: record, PrevHash: lastHash, Hash: archiveEntryHash(w.config.HMACKey, lastHash, record)}
+		line, err := json.Marshal(&entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		lastHash = entry.Hash
+	}
+	n, err := w.file.Write(buf.Bytes())
+	w.fileSize += int64(n)
+	if err != nil {
+		return err
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	w.lastHash = lastHash
+	return nil
+}
+
+// requeue puts back the rows which failed to be written in front of the
+// buffer, dropping the oldest rows if the buffer is full.
+func (w *archiveWriter) requeue(rows []*clickHouseRow) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.rows = append(rows, w.rows...)
+	if len(w.rows) > archiveMaxBufferedRows {
+		klog.Warningf("Dropping %d flow records because the archive cannot be written", len(w.rows)-archiveMaxBufferedRows)
+		w.rows = w.rows[len(w.rows)-archiveMaxBufferedRows:]
+	}
+}
+
+func (w *archiveWriter) openFile() error {
+	if err := os.MkdirAll(w.config.Directory, 0750); err != nil {
+		return err
+	}
+	if !w.chainRestored {
+		// Continue the hash chain of the entries written before restart.
+		files, err := w.listFiles()
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			lastHash, err := readLastArchiveHash(files[len(files)-1])
+			if err != nil {
+				// The archive cannot be continued, but the records
+				// must still be written: a new chain is started
+				// with an empty prevHash, which is reported so that
+				// the broken tail can be investigated.
+				klog.Warningf("Starting a new hash chain in the flow archive because the last one cannot be continued: %v", err)
+				metrics.FlowArchiveChainResetCount.Inc()
+				lastHash = ""
+			}
+			w.lastHash = lastHash
+		}
+		w.chainRestored = true
+	}
+	name := archiveFilePrefix + w.now().UTC().Format(archiveFileTimeFormat) + archiveFileSuffix
+	file, err := os.OpenFile(filepath.Join(w.config.Directory, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.fileSize = 0
+	w.deleteExpiredFiles()
+	return nil
+}
+
+func (w *archiveWriter) closeFile() {
+	if w.file == nil {
+		return
+	}
+	if err := w.file.Close(); err != nil {
+		klog.Errorf("Error when closing flow archive file %s: %v", w.file.Name(), err)
+	}
+	w.file = nil
+}
+
+// listFiles returns the paths of the archive files, from the oldest to the
+// newest.
+func (w *archiveWriter) listFiles() ([]string, error) {
+	infos, err := ioutil.ReadDir(w.config.Directory)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() && strings.HasPrefix(name, archiveFilePrefix) && strings.HasSuffix(name, archiveFileSuffix) {
+			files = append(files, filepath.Join(w.config.Directory, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// deleteExpiredFiles deletes the archive files, other than the current one,
+// which were last written more than MaxAge ago.
+func (w *archiveWriter) deleteExpiredFiles() {
+	if w.config.MaxAge == 0 {
+		return
+	}
+	files, err := w.listFiles()
+	if err != nil {
+		klog.Errorf("Error when listing flow archive files in %s: %v", w.config.Directory, err)
+		return
+	}
+	expiry := w.now().Add(-w.config.MaxAge)
+	for _, path := range files {
+		if w.file != nil && path == w.file.Name() {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Before(expiry) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			klog.Errorf("Error when deleting expired flow archive file %s: %v", path, err)
+			continue
+		}
+		klog.V(2).Infof("Deleted expired flow archive file %s", path)
+	}
+}
+
+// readLastArchiveHash returns the hash of the last complete entry of the
+// provided archive file. A trailing partial line, which is left if the Node
+// crashed while the file was written, is ignored.
+func readLastArchiveHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	var lastHash string
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// A line without a newline is either empty or partial.
+			break
+		}
+		var entry archiveEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return "", fmt.Errorf("invalid entry in flow archive file %s: %v", path, err)
+		}
+		lastHash = entry.Hash
+	}
+	return lastHash, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readArchive returns the entries of the provided archive files, checking
+// that they form a valid hash chain computed with the provided HMAC key.
+func readArchive(t *testing.T, key []byte, files []string) []archiveEntry {
+	var entries []archiveEntry
+	var lastHash string
+	for _, path := range files {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry archiveEntry
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			assert.Equal(t, lastHash, entry.PrevHash)
+			assert.Equal(t, archiveEntryHash(key, entry.PrevHash, entry.Record), entry.Hash)
+			lastHash = entry.Hash
+			entries = append(entries, entry)
+		}
+		file.Close()
+	}
+	return entries
+}
+
+func newTestArchiveWriter(dir string, maxSize int64, maxAge time.Duration, now time.Time) *archiveWriter {
+	w := newArchiveWriter(&ArchiveConfig{Directory: dir, MaxSize: maxSize, MaxAge: maxAge, CommitInterval: time.Second})
+	w.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return w
+}
+
+func TestArchiveWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	w := newTestArchiveWriter(dir, 1024*1024, 0, now)
+	w.addRecord(newTestFlowRecord(30001))
+	w.addRecord(newTestFlowRecord(30002))
+	w.flush()
+	w.addRecord(newTestFlowRecord(30003))
+	w.flush()
+	w.closeFile()
+	files, err := w.listFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "flows-20201201T100001.000000000Z.jsonl", filepath.Base(files[0]))
+
+	// After restart, the records are written to a new file which continues
+	// the hash chain, and a file is rotated after every flush as the maximum
+	// size is tiny.
+	w = newTestArchiveWriter(dir, 1, 0, now.Add(time.Minute))
+	w.addRecord(newTestFlowRecord(30004))
+	w.flush()
+	w.addRecord(newTestFlowRecord(30005))
+	w.flush()
+	files, err = w.listFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	entries := readArchive(t, nil, files)
+	require.Len(t, entries, 5)
+	for i, entry := range entries {
+		var row clickHouseRow
+		require.NoError(t, json.Unmarshal(entry.Record, &row))
+		assert.Equal(t, uint16(30001+i), row.SourceTransportPort)
+		assert.Equal(t, "ns1", row.SourcePodNamespace)
+	}
+}
+
+func TestArchiveWriterRestoreChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	w := newTestArchiveWriter(dir, 1024*1024, 0, now)
+	w.addRecord(newTestFlowRecord(30001))
+	w.flush()
+	lastHash := w.lastHash
+	// Simulate a crash in the middle of a write.
+	_, err = w.file.Write([]byte(`{"record":{"sourceIP":"10.`))
+	require.NoError(t, err)
+	w.closeFile()
+
+	hash, err := readLastArchiveHash(filepath.Join(dir, "flows-20201201T100001.000000000Z.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, lastHash, hash)
+}
+
+func TestArchiveWriterHMAC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	key := []byte("archive-key")
+
+	w := newTestArchiveWriter(dir, 1024*1024, 0, now)
+	w.config.HMACKey = key
+	w.addRecord(newTestFlowRecord(30001))
+	w.addRecord(newTestFlowRecord(30002))
+	w.flush()
+	w.closeFile()
+	files, err := w.listFiles()
+	require.NoError(t, err)
+	entries := readArchive(t, key, files)
+	require.Len(t, entries, 2)
+	// The digests cannot be recomputed without the key.
+	assert.NotEqual(t, archiveEntryHash(nil, entries[0].PrevHash, entries[0].Record), entries[0].Hash)
+}
+
+func TestArchiveWriterInvalidTail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	invalidFile := filepath.Join(dir, "flows-20201201T090000.000000000Z.jsonl")
+	require.NoError(t, ioutil.WriteFile(invalidFile, []byte("invalid entry\n"), 0640))
+
+	// The records are still written, in a new chain starting with an empty
+	// prevHash.
+	w := newTestArchiveWriter(dir, 1024*1024, 0, now)
+	w.addRecord(newTestFlowRecord(30001))
+	w.flush()
+	w.addRecord(newTestFlowRecord(30002))
+	w.flush()
+	w.closeFile()
+	assert.Empty(t, w.rows)
+	files, err := w.listFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	entries := readArchive(t, nil, files[1:])
+	require.Len(t, entries, 2)
+	assert.Equal(t, "", entries[0].PrevHash)
+}
+
+func TestArchiveWriterRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	now := time.Now()
+
+	oldFile := filepath.Join(dir, "flows-20201101T100000.000000000Z.jsonl")
+	recentFile := filepath.Join(dir, "flows-20201201T100000.000000000Z.jsonl")
+	otherFile := filepath.Join(dir, "other.log")
+	for _, path := range []string{oldFile, recentFile, otherFile} {
+		require.NoError(t, ioutil.WriteFile(path, nil, 0640))
+		require.NoError(t, os.Chtimes(path, now.Add(-2*time.Hour), now.Add(-2*time.Hour)))
+	}
+	require.NoError(t, os.Chtimes(recentFile, now, now))
+
+	w := newTestArchiveWriter(dir, 1024*1024, time.Hour, now)
+	w.addRecord(newTestFlowRecord(30001))
+	w.flush()
+	defer w.closeFile()
+
+	_, err = os.Stat(oldFile)
+	assert.True(t, os.IsNotExist(err))
+	for _, path := range []string{recentFile, otherFile, w.file.Name()} {
+		_, err = os.Stat(path)
+		assert.NoError(t, err)
+	}
+}
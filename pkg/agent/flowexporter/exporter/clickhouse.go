@@ -0,0 +1,378 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+const (
+	clickHouseRequestTimeout = 10 * time.Second
+	// clickHouseMaxBufferedBatches is the number of batches which are kept in
+	// memory while ClickHouse is unreachable. The oldest rows are dropped
+	// beyond this limit.
+	clickHouseMaxBufferedBatches = 10
+	clickHouseDateTimeFormat     = "2006-01-02 15:04:05"
+)
+
+// clickHouseColumns is the schema of the flow table. The column names follow
+// the names of the IPFIX IEs.
+var clickHouseColumns = []string{
+	"timeInserted DateTime('UTC') DEFAULT now()",
+	"flowStartSeconds DateTime('UTC')",
+	"flowEndSeconds DateTime('UTC')",
+	"flowEndReason UInt8",
+	"sourceIP String",
+	"destinationIP String",
+	"sourceTransportPort UInt16",
+	"destinationTransportPort UInt16",
+	"protocolIdentifier UInt8",
+	"packetTotalCount UInt64",
+	"octetTotalCount UInt64",
+	"packetDeltaCount UInt64",
+	"octetDeltaCount UInt64",
+	"reversePacketTotalCount UInt64",
+	"reverseOctetTotalCount UInt64",
+	"reversePacketDeltaCount UInt64",
+	"reverseOctetDeltaCount UInt64",
+	"sourcePodName String",
+	"sourcePodNamespace String",
+	"sourceNodeName String",
+	"destinationPodName String",
+	"destinationPodNamespace String",
+	"destinationNodeName String",
+	"destinationClusterIP String",
+	"destinationServicePort UInt16",
+	"destinationServicePortName String",
+	"tcpState String",
+	"flowDenied UInt8",
+	"throughput UInt64",
+	"reverseThroughput UInt64",
+	"flowDirection UInt8",
+	"flowKeyHash UInt64",
+	"flowHairpin UInt8",
+	"tcpRTT UInt32",
+	"egressName String",
+	"egressIP String",
+	"egressNodeName String",
+}
+
+// clickHouseMigrations add the columns introduced by each version of the
+// schema to the flow tables created by older versions of the Agent, as
+// "CREATE TABLE IF NOT EXISTS" doesn't update an existing table. They are
+// applied in order of version, and are idempotent so that they can be applied
+// again at every start. New columns must be appended to clickHouseColumns and
+// to a new migration.
+var clickHouseMigrations = []struct {
+	version int
+	columns []string
+}{
+	{version: 2, columns: []string{"flowHairpin UInt8"}},
+	{version: 3, columns: []string{"tcpRTT UInt32"}},
+	{version: 4, columns: []string{"egressName String", "egressIP String", "egressNodeName String"}},
+}
+
+// ClickHouseConfig is the configuration used to write flow records directly
+// to a ClickHouse database through its HTTP interface.
+type ClickHouseConfig struct {
+	// URL is the address of the HTTP interface of ClickHouse, e.g.
+	// "http://clickhouse.flow-visibility.svc:8123".
+	URL string
+	// Database and Table are the names of the database and of the table to
+	// which the flow records are written. They are created if they do not
+	// exist.
+	Database string
+	Table    string
+	Username string
+	Password string
+	// BatchSize is the number of rows after which a batch is committed,
+	// even if CommitInterval has not elapsed yet.
+	BatchSize int
+	// CommitInterval is the interval after which the buffered rows are
+	// committed.
+	CommitInterval time.Duration
+	// TTL is the duration after which the rows are deleted by ClickHouse.
+	// Rows are never deleted if it is zero.
+	TTL time.Duration
+}
+
+// clickHouseRow is a row of the flow table, encoded in the JSONEachRow format.
+type clickHouseRow struct {
+	FlowStartSeconds           string `json:"flowStartSeconds"`
+	FlowEndSeconds             string `json:"flowEndSeconds"`
+	FlowEndReason              uint8  `json:"flowEndReason"`
+	SourceIP                   string `json:"sourceIP"`
+	DestinationIP              string `json:"destinationIP"`
+	SourceTransportPort        uint16 `json:"sourceTransportPort"`
+	DestinationTransportPort   uint16 `json:"destinationTransportPort"`
+	ProtocolIdentifier         uint8  `json:"protocolIdentifier"`
+	PacketTotalCount           uint64 `json:"packetTotalCount"`
+	OctetTotalCount            uint64 `json:"octetTotalCount"`
+	PacketDeltaCount           uint64 `json:"packetDeltaCount"`
+	OctetDeltaCount            uint64 `json:"octetDeltaCount"`
+	ReversePacketTotalCount    uint64 `json:"reversePacketTotalCount"`
+	ReverseOctetTotalCount     uint64 `json:"reverseOctetTotalCount"`
+	ReversePacketDeltaCount    uint64 `json:"reversePacketDeltaCount"`
+	ReverseOctetDeltaCount     uint64 `json:"reverseOctetDeltaCount"`
+	SourcePodName              string `json:"sourcePodName"`
+	SourcePodNamespace         string `json:"sourcePodNamespace"`
+	SourceNodeName             string `json:"sourceNodeName"`
+	DestinationPodName         string `json:"destinationPodName"`
+	DestinationPodNamespace    string `json:"destinationPodNamespace"`
+	DestinationNodeName        string `json:"destinationNodeName"`
+	DestinationClusterIP       string `json:"destinationClusterIP"`
+	DestinationServicePort     uint16 `json:"destinationServicePort"`
+	DestinationServicePortName string `json:"destinationServicePortName"`
+	TCPState                   string `json:"tcpState"`
+	FlowDenied                 uint8  `json:"flowDenied"`
+	Throughput                 uint64 `json:"throughput"`
+	ReverseThroughput          uint64 `json:"reverseThroughput"`
+	FlowDirection              uint8  `json:"flowDirection"`
+	FlowKeyHash                uint64 `json:"flowKeyHash"`
+	FlowHairpin                uint8  `json:"flowHairpin"`
+	TCPRTT                     uint32 `json:"tcpRTT"`
+	EgressName                 string `json:"egressName"`
+	EgressIP                   string `json:"egressIP"`
+	EgressNodeName             string `json:"egressNodeName"`
+}
+
+func newClickHouseRow(record *flowexporter.FlowRecord) *clickHouseRow {
+	conn := record.Conn
+	row := &clickHouseRow{
+		FlowStartSeconds:           conn.StartTime.UTC().Format(clickHouseDateTimeFormat),
+		FlowEndSeconds:             conn.StopTime.UTC().Format(clickHouseDateTimeFormat),
+		FlowEndReason:              record.FlowEndReason,
+		SourceIP:                   conn.TupleOrig.SourceAddress.String(),
+		DestinationIP:              conn.TupleReply.SourceAddress.String(),
+		SourceTransportPort:        conn.TupleOrig.SourcePort,
+		DestinationTransportPort:   conn.TupleReply.SourcePort,
+		ProtocolIdentifier:         conn.TupleOrig.Protocol,
+		PacketTotalCount:           conn.OriginalPackets,
+		OctetTotalCount:            conn.OriginalBytes,
+		PacketDeltaCount:           record.DeltaPackets,
+		OctetDeltaCount:            record.DeltaBytes,
+		ReversePacketTotalCount:    conn.ReversePackets,
+		ReverseOctetTotalCount:     conn.ReverseBytes,
+		ReversePacketDeltaCount:    record.DeltaReversePackets,
+		ReverseOctetDeltaCount:     record.DeltaReverseBytes,
+		SourcePodName:              conn.SourcePodName,
+		SourcePodNamespace:         conn.SourcePodNamespace,
+		SourceNodeName:             conn.SourceNodeName,
+		DestinationPodName:         conn.DestinationPodName,
+		DestinationPodNamespace:    conn.DestinationPodNamespace,
+		DestinationNodeName:        conn.DestinationNodeName,
+		DestinationServicePortName: conn.DestinationServicePortName,
+		TCPState:                   conn.TCPState,
+		Throughput:                 record.Throughput,
+		ReverseThroughput:          record.ReverseThroughput,
+		FlowDirection:              flowexporter.GetFlowDirection(conn),
+		FlowKeyHash:                flowexporter.GetFlowKeyHash(conn),
+		TCPRTT:                     uint32(conn.TCPRTT / time.Microsecond),
+		EgressName:                 conn.EgressName,
+		EgressNodeName:             conn.EgressNodeName,
+	}
+	if conn.EgressIP != nil {
+		row.EgressIP = conn.EgressIP.String()
+	}
+	if conn.DestinationServicePortName != "" || conn.IsHairpin {
+		row.DestinationClusterIP = conn.TupleOrig.DestinationAddress.String()
+		row.DestinationServicePort = conn.TupleOrig.DestinationPort
+	}
+	if conn.IsDenied {
+		row.FlowDenied = 1
+	}
+	if conn.IsHairpin {
+		row.FlowHairpin = 1
+	}
+	return row
+}
+
+// clickHouseWriter buffers the flow records and writes them to ClickHouse in
+// batches, either every CommitInterval or as soon as BatchSize rows are
+// buffered. The database and the table are created the first time a batch is
+// committed.
+type clickHouseWriter struct {
+	config      *ClickHouseConfig
+	httpClient  *http.Client
+	mutex       sync.Mutex
+	rows        []*clickHouseRow
+	flushCh     chan struct{}
+	schemaReady bool
+}
+
+func newClickHouseWriter(config *ClickHouseConfig) *clickHouseWriter {
+	return &clickHouseWriter{
+		config:     config,
+		httpClient: &http.Client{Timeout: clickHouseRequestTimeout},
+		flushCh:    make(chan struct{}, 1),
+	}
+}
+
+// addRecord buffers the provided flow record. It never blocks on ClickHouse.
+func (w *clickHouseWriter) addRecord(record *flowexporter.FlowRecord) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.rows = append(w.rows, newClickHouseRow(record))
+	if len(w.rows) >= w.config.BatchSize {
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run commits the buffered rows until stopCh is closed.
+func (w *clickHouseWriter) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(w.config.CommitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			w.flush()
+			return
+		case <-ticker.C:
+			w.flush()
+		case <-w.flushCh:
+			w.flush()
+		}
+	}
+}
+
+func (w *clickHouseWriter) flush() {
+	w.mutex.Lock()
+	rows := w.rows
+	w.rows = nil
+	w.mutex.Unlock()
+	if len(rows) == 0 {
+		return
+	}
+
+	err := w.initSchema()
+	if err == nil {
+		for start := 0; start < len(rows); start += w.config.BatchSize {
+			end := start + w.config.BatchSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+			if err = w.insert(rows[start:end]); err != nil {
+				rows = rows[start:]
+				break
+			}
+			klog.V(2).Infof("Committed %d flow records to ClickHouse", end-start)
+		}
+	}
+	if err != nil {
+		klog.Errorf("Error when committing flow records to ClickHouse: %v", err)
+		w.requeue(rows)
+	}
+}
+
+// requeue puts back the rows which failed to be committed in front of the
+// buffer, dropping the oldest rows if the buffer is full.
+func (w *clickHouseWriter) requeue(rows []*clickHouseRow) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.rows = append(rows, w.rows...)
+	if maxRows := clickHouseMaxBufferedBatches * w.config.BatchSize; len(w.rows) > maxRows {
+		klog.Warningf("Dropping %d flow records because ClickHouse is unreachable", len(w.rows)-maxRows)
+		w.rows = w.rows[len(w.rows)-maxRows:]
+	}
+}
+
+func (w *clickHouseWriter) tableName() string {
+	return fmt.Sprintf("%s.%s", w.config.Database, w.config.Table)
+}
+
+// initSchema creates the database and the flow table if they do not exist
+// yet, migrates the table to the current schema, and updates its TTL.
+func (w *clickHouseWriter) initSchema() error {
+	if w.schemaReady {
+		return nil
+	}
+	queries := []string{
+		fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", w.config.Database),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s) ENGINE = MergeTree() ORDER BY (timeInserted, flowEndSeconds)",
+			w.tableName(), strings.Join(clickHouseColumns, ", ")),
+	}
+	for _, migration := range clickHouseMigrations {
+		var addColumns []string
+		for _, column := range migration.columns {
+			addColumns = append(addColumns, "ADD COLUMN IF NOT EXISTS "+column)
+		}
+		queries = append(queries, fmt.Sprintf("ALTER TABLE %s %s", w.tableName(), strings.Join(addColumns, ", ")))
+	}
+	if w.config.TTL > 0 {
+		queries = append(queries, fmt.Sprintf("ALTER TABLE %s MODIFY TTL timeInserted + INTERVAL %d SECOND",
+			w.tableName(), int64(w.config.TTL/time.Second)))
+	}
+	for _, query := range queries {
+		if err := w.do("", strings.NewReader(query)); err != nil {
+			return err
+		}
+	}
+	w.schemaReady = true
+	return nil
+}
+
+func (w *clickHouseWriter) insert(rows []*clickHouseRow) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return w.do(fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", w.tableName()), &body)
+}
+
+// do sends a request to the HTTP interface of ClickHouse. The query is either
+// passed as the query parameter, with body as its data, or as the body itself.
+func (w *clickHouseWriter) do(query string, body io.Reader) error {
+	reqURL := w.config.URL
+	if query != "" {
+		reqURL = fmt.Sprintf("%s/?%s", strings.TrimSuffix(w.config.URL, "/"), url.Values{"query": []string{query}}.Encode())
+	}
+	req, err := http.NewRequest(http.MethodPost, reqURL, body)
+	if err != nil {
+		return err
+	}
+	if w.config.Username != "" {
+		req.Header.Set("X-ClickHouse-User", w.config.Username)
+		req.Header.Set("X-ClickHouse-Key", w.config.Password)
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("ClickHouse returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
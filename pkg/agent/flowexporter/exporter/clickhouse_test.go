@@ -0,0 +1,203 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+// fakeClickHouse records the queries and the inserted rows received by the
+// HTTP interface.
+type fakeClickHouse struct {
+	mutex   sync.Mutex
+	queries []string
+	rows    []clickHouseRow
+	user    string
+	fail    bool
+}
+
+func (f *fakeClickHouse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.fail {
+		http.Error(w, "Code: 210. DB::NetException: Connection refused", http.StatusInternalServerError)
+		return
+	}
+	f.user = r.Header.Get("X-ClickHouse-User")
+	if query := r.URL.Query().Get("query"); query != "" {
+		f.queries = append(f.queries, query)
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var row clickHouseRow
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f.rows = append(f.rows, row)
+		}
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	f.queries = append(f.queries, string(body))
+}
+
+func newTestFlowRecord(srcPort uint16) *flowexporter.FlowRecord {
+	startTime := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	return &flowexporter.FlowRecord{
+		Conn: &flowexporter.Connection{
+			StartTime:                  startTime,
+			StopTime:                   startTime.Add(10 * time.Second),
+			TupleOrig:                  flowexporter.Tuple{SourceAddress: net.ParseIP("10.10.0.2"), DestinationAddress: net.ParseIP("10.96.0.10"), Protocol: 6, SourcePort: srcPort, DestinationPort: 80},
+			TupleReply:                 flowexporter.Tuple{SourceAddress: net.ParseIP("10.10.1.3"), DestinationAddress: net.ParseIP("10.10.0.2"), Protocol: 6, SourcePort: 8080, DestinationPort: srcPort},
+			SourcePodNamespace:         "ns1",
+			SourcePodName:              "client",
+			DestinationServicePortName: "ns1/server:http",
+			OriginalPackets:            10,
+			OriginalBytes:              1000,
+			IsDenied:                   true,
+		},
+		DeltaPackets: 5,
+		DeltaBytes:   500,
+	}
+}
+
+func TestNewClickHouseRow(t *testing.T) {
+	record := newTestFlowRecord(40000)
+	row := newClickHouseRow(record)
+	assert.Equal(t, "2020-12-01 10:00:00", row.FlowStartSeconds)
+	assert.Equal(t, "2020-12-01 10:00:10", row.FlowEndSeconds)
+	assert.Equal(t, "10.10.0.2", row.SourceIP)
+	assert.Equal(t, "10.10.1.3", row.DestinationIP)
+	assert.Equal(t, uint16(8080), row.DestinationTransportPort)
+	assert.Equal(t, "10.96.0.10", row.DestinationClusterIP)
+	assert.Equal(t, uint16(80), row.DestinationServicePort)
+	assert.Equal(t, uint64(5), row.PacketDeltaCount)
+	assert.Equal(t, uint8(1), row.FlowDenied)
+	assert.Equal(t, flowexporter.FlowDirectionEgress, row.FlowDirection)
+	assert.Equal(t, flowexporter.GetFlowKeyHash(record.Conn), row.FlowKeyHash)
+
+	assert.Equal(t, uint8(0), row.FlowHairpin)
+	assert.Equal(t, uint32(0), row.TCPRTT)
+
+	record.Conn.TCPRTT = 1500 * time.Microsecond
+	row = newClickHouseRow(record)
+	assert.Equal(t, uint32(1500), row.TCPRTT)
+
+	assert.Equal(t, "", row.EgressIP)
+	record.Conn.EgressIP = net.ParseIP("192.168.1.10")
+	record.Conn.EgressNodeName = "node1"
+	row = newClickHouseRow(record)
+	assert.Equal(t, "", row.EgressName)
+	assert.Equal(t, "192.168.1.10", row.EgressIP)
+	assert.Equal(t, "node1", row.EgressNodeName)
+
+	record.Conn.DestinationServicePortName = ""
+	row = newClickHouseRow(record)
+	assert.Equal(t, "", row.DestinationClusterIP)
+	assert.Equal(t, uint16(0), row.DestinationServicePort)
+
+	// The pre-NAT destination of hairpin connections is always written.
+	record.Conn.IsHairpin = true
+	row = newClickHouseRow(record)
+	assert.Equal(t, uint8(1), row.FlowHairpin)
+	assert.Equal(t, "10.96.0.10", row.DestinationClusterIP)
+	assert.Equal(t, uint16(80), row.DestinationServicePort)
+}
+
+func TestClickHouseWriterFlush(t *testing.T) {
+	fake := &fakeClickHouse{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	writer := newClickHouseWriter(&ClickHouseConfig{
+		URL:            server.URL,
+		Database:       "antrea",
+		Table:          "flows",
+		Username:       "antrea",
+		BatchSize:      2,
+		CommitInterval: time.Minute,
+		TTL:            12 * time.Hour,
+	})
+	for i := 0; i < 3; i++ {
+		writer.addRecord(newTestFlowRecord(uint16(40000 + i)))
+	}
+	writer.flush()
+
+	require.Len(t, fake.queries, 8)
+	assert.Equal(t, "CREATE DATABASE IF NOT EXISTS antrea", fake.queries[0])
+	assert.True(t, strings.HasPrefix(fake.queries[1], "CREATE TABLE IF NOT EXISTS antrea.flows (timeInserted DateTime('UTC') DEFAULT now(), "))
+	// Tables created by older versions are migrated to the current schema.
+	assert.Equal(t, "ALTER TABLE antrea.flows ADD COLUMN IF NOT EXISTS flowHairpin UInt8", fake.queries[2])
+	assert.Equal(t, "ALTER TABLE antrea.flows ADD COLUMN IF NOT EXISTS tcpRTT UInt32", fake.queries[3])
+	assert.Equal(t, "ALTER TABLE antrea.flows ADD COLUMN IF NOT EXISTS egressName String, ADD COLUMN IF NOT EXISTS egressIP String, ADD COLUMN IF NOT EXISTS egressNodeName String", fake.queries[4])
+	assert.Equal(t, "ALTER TABLE antrea.flows MODIFY TTL timeInserted + INTERVAL 43200 SECOND", fake.queries[5])
+	// The 3 rows are committed in 2 batches.
+	assert.Equal(t, "INSERT INTO antrea.flows FORMAT JSONEachRow", fake.queries[6])
+	assert.Equal(t, "INSERT INTO antrea.flows FORMAT JSONEachRow", fake.queries[7])
+	require.Len(t, fake.rows, 3)
+	for i, row := range fake.rows {
+		assert.Equal(t, uint16(40000+i), row.SourceTransportPort)
+	}
+	assert.Equal(t, "antrea", fake.user)
+	assert.Empty(t, writer.rows)
+
+	// The schema is only initialized once.
+	writer.addRecord(newTestFlowRecord(40003))
+	writer.flush()
+	assert.Len(t, fake.queries, 9)
+}
+
+func TestClickHouseWriterRequeue(t *testing.T) {
+	fake := &fakeClickHouse{fail: true}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	writer := newClickHouseWriter(&ClickHouseConfig{
+		URL:            server.URL,
+		Database:       "default",
+		Table:          "flows",
+		BatchSize:      1,
+		CommitInterval: time.Minute,
+	})
+	// The rows are kept while ClickHouse is unreachable, up to
+	// clickHouseMaxBufferedBatches batches.
+	for i := 0; i < clickHouseMaxBufferedBatches+2; i++ {
+		writer.addRecord(newTestFlowRecord(uint16(40000 + i)))
+	}
+	writer.flush()
+	require.Len(t, writer.rows, clickHouseMaxBufferedBatches)
+	assert.Equal(t, uint16(40002), writer.rows[0].SourceTransportPort)
+
+	fake.mutex.Lock()
+	fake.fail = false
+	fake.mutex.Unlock()
+	writer.flush()
+	assert.Empty(t, writer.rows)
+	assert.Len(t, fake.rows, clickHouseMaxBufferedBatches)
+}
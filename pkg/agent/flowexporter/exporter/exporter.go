@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"net"
+	"time"
 
 	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
 	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
@@ -42,6 +43,8 @@ var (
 		"octetTotalCount",
 		"packetDeltaCount",
 		"octetDeltaCount",
+		"flowEndReason",
+		"flowDirection",
 	}
 	// Substring "reverse" is an indication to get reverse element of go-ipfix library.
 	IANAReverseInfoElements = []string{
@@ -58,18 +61,55 @@ var (
 		"destinationPodNamespace",
 		"destinationNodeName",
 		"destinationClusterIP",
+		"destinationServicePort",
 		"destinationServicePortName",
+		"tcpState",
+		"flowDenied",
+		"throughput",
+		"reverseThroughput",
+		"flowKeyHash",
+		"flowHairpin",
+		"tcpRTT",
+		"egressName",
+		"egressIP",
+		"egressNodeName",
+		"exporterRestarted",
+	}
+	// ipv6InfoElements are the Information Elements replacing the IPv4 ones above in the template of the flow records
+	// of IPv6 connections.
+	ipv6InfoElements = map[string]string{
+		"sourceIPv4Address":      "sourceIPv6Address",
+		"destinationIPv4Address": "destinationIPv6Address",
+		"destinationClusterIP":   "destinationClusterIPv6",
+		"egressIP":               "egressIPv6",
 	}
 )
 
 type flowExporter struct {
-	flowRecords     *flowrecords.FlowRecords
+	flowRecords *flowrecords.FlowRecords
+	// denyFlowRecords are the flow records of the connections denied by NetworkPolicies.
+	denyFlowRecords *flowrecords.FlowRecords
 	process         ipfix.IPFIXExportingProcess
 	elementsList    []*ipfixentities.InfoElement
-	exportFrequency uint
-	pollCycle       uint
 	templateID      uint16
-	registry        ipfix.IPFIXRegistry
+	// elementsListIPv6 and templateIDIPv6 are used for the flow records of
+	// IPv6 connections, which have IPv6 addresses in place of the IPv4 ones.
+	elementsListIPv6 []*ipfixentities.InfoElement
+	templateIDIPv6   uint16
+	registry         ipfix.IPFIXRegistry
+	// tlsConfig is set when flow records are exported over TLS, in which case
+	// the exporting process is connected to the collector through tunnel.
+	tlsConfig *TLSConfig
+	tunnel    *tlsTunnel
+	// clickHouse is set when flow records are also written directly to
+	// ClickHouse.
+	clickHouse *clickHouseWriter
+	// archive is set when flow records are also written to local archive
+	// files.
+	archive *archiveWriter
+	// baselineFile is the file to which the state of the exported flow
+	// records is saved, so that it can be restored after restart.
+	baselineFile string
 }
 
 func genObservationID() (uint32, error) {
@@ -82,59 +122,103 @@ func genObservationID() (uint32, error) {
 	return h.Sum32(), nil
 }
 
-func NewFlowExporter(records *flowrecords.FlowRecords, exportFrequency uint) *flowExporter {
+func NewFlowExporter(records *flowrecords.FlowRecords, denyRecords *flowrecords.FlowRecords, tlsConfig *TLSConfig, clickHouseConfig *ClickHouseConfig, archiveConfig *ArchiveConfig, baselineFile string) *flowExporter {
+	if baselineFile != "" {
+		// The records of the denied connections are not restored, as they
+		// are only known from the packets received since the start.
+		if err := records.RestoreExportBaseline(baselineFile); err != nil {
+			klog.Warningf("Failed to restore the export baseline of flow records from %s: %v", baselineFile, err)
+		}
+	}
 	registry := ipfix.NewIPFIXRegistry()
 	registry.LoadRegistry()
+	var clickHouse *clickHouseWriter
+	if clickHouseConfig != nil {
+		clickHouse = newClickHouseWriter(clickHouseConfig)
+	}
+	var archive *archiveWriter
+	if archiveConfig != nil {
+		archive = newArchiveWriter(archiveConfig)
+	}
 	return &flowExporter{
 		records,
+		denyRecords,
 		nil,
 		nil,
-		exportFrequency,
 		0,
+		nil,
 		0,
 		registry,
+		tlsConfig,
+		nil,
+		clickHouse,
+		archive,
+		baselineFile,
+	}
+}
+
+// RunClickHouseWriter commits the flow records buffered for ClickHouse until stopCh is closed. It returns immediately
+// if flow records are not written to ClickHouse.
+func (exp *flowExporter) RunClickHouseWriter(stopCh <-chan struct{}) {
+	if exp.clickHouse == nil {
+		return
 	}
+	exp.clickHouse.run(stopCh)
 }
 
-// DoExport enables us to export flow records periodically at a given flow export frequency.
+// RunArchiveWriter writes the flow records buffered for the archive until stopCh is closed. It returns immediately if
+// flow records are not archived.
+func (exp *flowExporter) RunArchiveWriter(stopCh <-chan struct{}) {
+	if exp.archive == nil {
+		return
+	}
+	exp.archive.run(stopCh)
+}
+
+// GetFlowRecords returns a copy of the flow records of both the conntrack connections and the denied connections. It
+// can be called concurrently with Export.
+func (exp *flowExporter) GetFlowRecords() []flowexporter.FlowRecord {
+	return append(exp.flowRecords.GetFlowRecords(), exp.denyFlowRecords.GetFlowRecords()...)
+}
+
+// Export enables us to export flow records after every poll cycle. Only the flow records whose active or idle timeout
+// has expired are exported in a given cycle. collector is nil when flow records are only written to ClickHouse or to the archive.
 func (exp *flowExporter) Export(collector net.Addr, stopCh <-chan struct{}, pollDone <-chan struct{}) {
 	for {
 		select {
 		case <-stopCh:
 			return
 		case <-pollDone:
-			// Number of pollDone signals received or poll cycles should be equal to export frequency before starting
-			// the export cycle. This is necessary because IPFIX collector computes throughput based on flow records received interval.
-			exp.pollCycle++
-			if exp.pollCycle%exp.exportFrequency == 0 {
-				// Retry to connect to IPFIX collector if the exporting process gets reset
-				if exp.process == nil {
-					err := exp.initFlowExporter(collector)
-					if err != nil {
-						klog.Errorf("Error when initializing flow exporter: %v", err)
-						// There could be other errors while initializing flow exporter other than connecting to IPFIX collector,
-						// therefore closing the connection and resetting the process.
-						if exp.process != nil {
-							exp.process.CloseConnToCollector()
-							exp.process = nil
-						}
-						return
-					}
-				}
-				// Build and send flow records to IPFIX collector.
-				exp.flowRecords.BuildFlowRecords()
-				err := exp.sendFlowRecords()
+			// Retry to connect to IPFIX collector if the exporting process gets reset
+			if collector != nil && exp.process == nil {
+				err := exp.initFlowExporter(collector)
 				if err != nil {
-					klog.Errorf("Error when sending flow records: %v", err)
-					// If there is an error when sending flow records because of intermittent connectivity, we reset the connection
-					// to IPFIX collector and retry in the next export cycle to reinitialize the connection and send flow records.
-					exp.process.CloseConnToCollector()
-					exp.process = nil
+					klog.Errorf("Error when initializing flow exporter: %v", err)
+					// There could be other errors while initializing flow exporter other than connecting to IPFIX collector,
+					// therefore closing the connection and resetting the process.
+					exp.resetFlowExporter()
 					return
 				}
-
-				exp.pollCycle = 0
-				klog.V(2).Infof("Successfully exported IPFIX flow records")
+			}
+			// Build and send expired flow records to IPFIX collector.
+			exp.flowRecords.BuildFlowRecords()
+			exp.denyFlowRecords.BuildFlowRecords()
+			err := exp.sendFlowRecords(exp.flowRecords)
+			if err == nil {
+				err = exp.sendFlowRecords(exp.denyFlowRecords)
+			}
+			if err != nil {
+				klog.Errorf("Error when sending flow records: %v", err)
+				// If there is an error when sending flow records because of intermittent connectivity, we reset the connection
+				// to IPFIX collector and retry in the next export cycle to reinitialize the connection and send flow records.
+				exp.resetFlowExporter()
+				return
+			}
+			klog.V(2).Infof("Successfully exported flow records")
+			if exp.baselineFile != "" {
+				if err := exp.flowRecords.SaveExportBaseline(exp.baselineFile); err != nil {
+					klog.Warningf("Failed to save the export baseline of flow records to %s: %v", exp.baselineFile, err)
+				}
 			}
 		}
 	}
@@ -148,50 +232,99 @@ func (exp *flowExporter) initFlowExporter(collector net.Addr) error {
 		return fmt.Errorf("cannot generate obsID for IPFIX ipfixexport: %v", err)
 	}
 
+	if exp.tlsConfig != nil {
+		tunnel, err := newTLSTunnel(collector, exp.tlsConfig)
+		if err != nil {
+			return err
+		}
+		exp.tunnel = tunnel
+		// The exporting process sends the flow records to the collector through the tunnel.
+		collector = tunnel.localAddr()
+	}
+
 	var expProcess ipfix.IPFIXExportingProcess
-	if collector.Network() == "tcp" {
-		// TCP transport do not need any tempRefTimeout, so sending 0.
-		expProcess, err = ipfix.NewIPFIXExportingProcess(collector, obsID, 0)
-	} else {
+	if collector.Network() == "udp" {
 		// For UDP transport, hardcoding tempRefTimeout value as 1800s.
 		expProcess, err = ipfix.NewIPFIXExportingProcess(collector, obsID, 1800)
+	} else {
+		// TCP transport and the Unix socket of the TLS tunnel do not need any
+		// tempRefTimeout, so sending 0.
+		expProcess, err = ipfix.NewIPFIXExportingProcess(collector, obsID, 0)
 	}
 	if err != nil {
 		return err
 	}
 	exp.process = expProcess
 	exp.templateID = expProcess.NewTemplateID()
+	exp.templateIDIPv6 = expProcess.NewTemplateID()
 
-	templateRec := ipfix.NewIPFIXTemplateRecord(uint16(len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements)), exp.templateID)
-
-	sentBytes, err := exp.sendTemplateRecord(templateRec)
+	elementCount := uint16(len(IANAInfoElements) + len(IANAReverseInfoElements) + len(AntreaInfoElements))
+	sentBytes, err := exp.sendTemplateRecord(ipfix.NewIPFIXTemplateRecord(elementCount, exp.templateID), false)
 	if err != nil {
 		return err
 	}
-	klog.V(2).Infof("Initialized flow exporter and sent %d bytes size of template record", sentBytes)
+	sentBytesIPv6, err := exp.sendTemplateRecord(ipfix.NewIPFIXTemplateRecord(elementCount, exp.templateIDIPv6), true)
+	if err != nil {
+		return err
+	}
+	klog.V(2).Infof("Initialized flow exporter and sent %d bytes size of template records", sentBytes+sentBytesIPv6)
 
 	return nil
 }
 
-func (exp *flowExporter) sendFlowRecords() error {
+// resetFlowExporter closes the connection to the collector, so that it is
+// reinitialized in the next export cycle.
+func (exp *flowExporter) resetFlowExporter() {
+	if exp.process != nil {
+		exp.process.CloseConnToCollector()
+		exp.process = nil
+	}
+	if exp.tunnel != nil {
+		exp.tunnel.close()
+		exp.tunnel = nil
+	}
+}
+
+func (exp *flowExporter) sendFlowRecords(flowRecords *flowrecords.FlowRecords) error {
 	sendAndUpdateFlowRecord := func(key flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
-		dataRec := ipfix.NewIPFIXDataRecord(exp.templateID)
-		if err := exp.sendDataRecord(dataRec, record); err != nil {
-			return err
+		if exp.process != nil {
+			isIPv6 := record.Conn.TupleOrig.SourceAddress.To4() == nil
+			templateID := exp.templateID
+			if isIPv6 {
+				templateID = exp.templateIDIPv6
+			}
+			dataRec := ipfix.NewIPFIXDataRecord(templateID)
+			if err := exp.sendDataRecord(dataRec, record, isIPv6); err != nil {
+				return err
+			}
+		}
+		if exp.clickHouse != nil {
+			exp.clickHouse.addRecord(&record)
 		}
-		if err := exp.flowRecords.ValidateAndUpdateStats(key, record); err != nil {
+		if exp.archive != nil {
+			exp.archive.addRecord(&record)
+		}
+		if err := flowRecords.ValidateAndUpdateStats(key, record); err != nil {
 			return err
 		}
 		return nil
 	}
-	err := exp.flowRecords.ForAllFlowRecordsDo(sendAndUpdateFlowRecord)
+	err := flowRecords.ForAllExpiredFlowRecordsDo(sendAndUpdateFlowRecord)
 	if err != nil {
 		return fmt.Errorf("error when iterating flow records: %v", err)
 	}
 	return nil
 }
 
-func (exp *flowExporter) sendTemplateRecord(templateRec ipfix.IPFIXRecord) (int, error) {
+// sendTemplateRecord sends the template of the flow records of the IPv4 connections, or of the IPv6 connections if
+// isIPv6 is true.
+func (exp *flowExporter) sendTemplateRecord(templateRec ipfix.IPFIXRecord, isIPv6 bool) (int, error) {
+	getElementName := func(ie string) string {
+		if ipv6IE, exists := ipv6InfoElements[ie]; exists && isIPv6 {
+			return ipv6IE
+		}
+		return ie
+	}
 	// Add template header
 	_, err := templateRec.PrepareRecord()
 	if err != nil {
@@ -199,6 +332,7 @@ func (exp *flowExporter) sendTemplateRecord(templateRec ipfix.IPFIXRecord) (int,
 	}
 
 	for _, ie := range IANAInfoElements {
+		ie = getElementName(ie)
 		element, err := exp.registry.GetInfoElement(ie, ipfixregistry.IANAEnterpriseID)
 		if err != nil {
 			return 0, fmt.Errorf("%s not present. returned error: %v", ie, err)
@@ -217,6 +351,7 @@ func (exp *flowExporter) sendTemplateRecord(templateRec ipfix.IPFIXRecord) (int,
 		}
 	}
 	for _, ie := range AntreaInfoElements {
+		ie = getElementName(ie)
 		element, err := exp.registry.GetInfoElement(ie, ipfixregistry.AntreaEnterpriseID)
 		if err != nil {
 			return 0, fmt.Errorf("information element %s is not present in Antrea registry", ie)
@@ -232,24 +367,35 @@ func (exp *flowExporter) sendTemplateRecord(templateRec ipfix.IPFIXRecord) (int,
 	}
 
 	// Get all elements from template record.
-	exp.elementsList = templateRec.GetTemplateElements()
+	if isIPv6 {
+		exp.elementsListIPv6 = templateRec.GetTemplateElements()
+	} else {
+		exp.elementsList = templateRec.GetTemplateElements()
+	}
 
 	return sentBytes, nil
 }
 
-func (exp *flowExporter) sendDataRecord(dataRec ipfix.IPFIXRecord, record flowexporter.FlowRecord) error {
-	nodeName, _ := env.GetNodeName()
+func (exp *flowExporter) sendDataRecord(dataRec ipfix.IPFIXRecord, record flowexporter.FlowRecord, isIPv6 bool) error {
+	elementsList := exp.elementsList
+	// Sending dummy IP for the IP fields which don't apply to the connection, as IPFIX collector expects constant
+	// length of data for IP field.
+	dummyIP := net.IP{0, 0, 0, 0}
+	if isIPv6 {
+		elementsList = exp.elementsListIPv6
+		dummyIP = net.IPv6zero
+	}
 	// Iterate over all infoElements in the list
-	for _, ie := range exp.elementsList {
+	for _, ie := range elementsList {
 		var err error
 		switch ieName := ie.Name; ieName {
 		case "flowStartSeconds":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.StartTime.Unix())
 		case "flowEndSeconds":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.StopTime.Unix())
-		case "sourceIPv4Address":
+		case "sourceIPv4Address", "sourceIPv6Address":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.SourceAddress)
-		case "destinationIPv4Address":
+		case "destinationIPv4Address", "destinationIPv6Address":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleReply.SourceAddress)
 		case "sourceTransportPort":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.SourcePort)
@@ -262,75 +408,44 @@ func (exp *flowExporter) sendDataRecord(dataRec ipfix.IPFIXRecord, record flowex
 		case "octetTotalCount":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.OriginalBytes)
 		case "packetDeltaCount":
-			deltaPkts := 0
-			if record.PrevPackets != 0 {
-				deltaPkts = int(record.Conn.OriginalPackets) - int(record.PrevPackets)
-			}
-			if deltaPkts < 0 {
-				klog.Warningf("Delta packets is not expected to be negative: %d", deltaPkts)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaPkts))
+			_, err = dataRec.AddInfoElement(ie, record.DeltaPackets)
 		case "octetDeltaCount":
-			deltaBytes := 0
-			if record.PrevBytes != 0 {
-				deltaBytes = int(record.Conn.OriginalBytes) - int(record.PrevBytes)
-			}
-			if deltaBytes < 0 {
-				klog.Warningf("Delta bytes is not expected to be negative: %d", deltaBytes)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaBytes))
+			_, err = dataRec.AddInfoElement(ie, record.DeltaBytes)
 		case "reverse_PacketTotalCount":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.ReversePackets)
 		case "reverse_OctetTotalCount":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.ReverseBytes)
 		case "reverse_PacketDeltaCount":
-			deltaPkts := 0
-			if record.PrevReversePackets != 0 {
-				deltaPkts = int(record.Conn.ReversePackets) - int(record.PrevReversePackets)
-			}
-			if deltaPkts < 0 {
-				klog.Warningf("Delta packets is not expected to be negative: %d", deltaPkts)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaPkts))
+			_, err = dataRec.AddInfoElement(ie, record.DeltaReversePackets)
 		case "reverse_OctetDeltaCount":
-			deltaBytes := 0
-			if record.PrevReverseBytes != 0 {
-				deltaBytes = int(record.Conn.ReverseBytes) - int(record.PrevReverseBytes)
-			}
-			if deltaBytes < 0 {
-				klog.Warningf("Delta bytes is not expected to be negative: %d", deltaBytes)
-			}
-			_, err = dataRec.AddInfoElement(ie, uint64(deltaBytes))
+			_, err = dataRec.AddInfoElement(ie, record.DeltaReverseBytes)
 		case "sourcePodNamespace":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.SourcePodNamespace)
 		case "sourcePodName":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.SourcePodName)
 		case "sourceNodeName":
-			// Add nodeName for only local pods whose pod names are resolved.
-			if record.Conn.SourcePodName != "" {
-				_, err = dataRec.AddInfoElement(ie, nodeName)
-			} else {
-				_, err = dataRec.AddInfoElement(ie, "")
-			}
+			_, err = dataRec.AddInfoElement(ie, record.Conn.SourceNodeName)
 		case "destinationPodNamespace":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationPodNamespace)
 		case "destinationPodName":
 			_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationPodName)
 		case "destinationNodeName":
-			// Add nodeName for only local pods whose pod names are resolved.
-			if record.Conn.DestinationPodName != "" {
-				_, err = dataRec.AddInfoElement(ie, nodeName)
-			} else {
-				_, err = dataRec.AddInfoElement(ie, "")
-			}
-		case "destinationClusterIP":
-			if record.Conn.DestinationServicePortName != "" {
+			_, err = dataRec.AddInfoElement(ie, record.Conn.DestinationNodeName)
+		case "destinationClusterIP", "destinationClusterIPv6":
+			// The pre-NAT destination of hairpin connections is always exported, as the post-NAT destination is
+			// the source Pod itself.
+			if record.Conn.DestinationServicePortName != "" || record.Conn.IsHairpin {
 				_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.DestinationAddress)
 			} else {
-				// Sending dummy IP as IPFIX collector expects constant length of data for IP field.
 				// We should probably think of better approach as this involves customization of IPFIX collector to ignore
 				// this dummy IP address.
-				_, err = dataRec.AddInfoElement(ie, net.IP{0, 0, 0, 0})
+				_, err = dataRec.AddInfoElement(ie, dummyIP)
+			}
+		case "destinationServicePort":
+			if record.Conn.DestinationServicePortName != "" || record.Conn.IsHairpin {
+				_, err = dataRec.AddInfoElement(ie, record.Conn.TupleOrig.DestinationPort)
+			} else {
+				_, err = dataRec.AddInfoElement(ie, uint16(0))
 			}
 		case "destinationServicePortName":
 			if record.Conn.DestinationServicePortName != "" {
@@ -338,6 +453,43 @@ func (exp *flowExporter) sendDataRecord(dataRec ipfix.IPFIXRecord, record flowex
 			} else {
 				_, err = dataRec.AddInfoElement(ie, "")
 			}
+		case "flowEndReason":
+			_, err = dataRec.AddInfoElement(ie, record.FlowEndReason)
+		case "tcpState":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.TCPState)
+		case "flowDenied":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.IsDenied)
+		case "throughput":
+			_, err = dataRec.AddInfoElement(ie, record.Throughput)
+		case "reverseThroughput":
+			_, err = dataRec.AddInfoElement(ie, record.ReverseThroughput)
+		case "flowDirection":
+			_, err = dataRec.AddInfoElement(ie, flowexporter.GetFlowDirection(record.Conn))
+		case "flowKeyHash":
+			_, err = dataRec.AddInfoElement(ie, flowexporter.GetFlowKeyHash(record.Conn))
+		case "flowHairpin":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.IsHairpin)
+		case "tcpRTT":
+			_, err = dataRec.AddInfoElement(ie, uint32(record.Conn.TCPRTT/time.Microsecond))
+		case "egressName":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.EgressName)
+		case "egressIP":
+			if egressIP := record.Conn.EgressIP.To4(); egressIP != nil {
+				_, err = dataRec.AddInfoElement(ie, egressIP)
+			} else {
+				// Sending dummy IP as for destinationClusterIP, when the connection does not leave the cluster.
+				_, err = dataRec.AddInfoElement(ie, dummyIP)
+			}
+		case "egressIPv6":
+			if egressIP := record.Conn.EgressIP; egressIP != nil && egressIP.To4() == nil {
+				_, err = dataRec.AddInfoElement(ie, egressIP)
+			} else {
+				_, err = dataRec.AddInfoElement(ie, dummyIP)
+			}
+		case "egressNodeName":
+			_, err = dataRec.AddInfoElement(ie, record.Conn.EgressNodeName)
+		case "exporterRestarted":
+			_, err = dataRec.AddInfoElement(ie, record.Restarted)
 		}
 		if err != nil {
 			return fmt.Errorf("error while adding info element: %s to data record: %v", ie.Name, err)
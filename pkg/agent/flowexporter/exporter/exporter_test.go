@@ -29,11 +29,25 @@ import (
 )
 
 const (
-	testTemplateID          = 256
-	testFlowExportFrequency = 12
+	testTemplateID     = 256
+	testTemplateIDIPv6 = 257
 )
 
+// elementName returns the name of the Information Element in the template of the IPv4 or IPv6 connections.
+func elementName(ie string, isIPv6 bool) string {
+	if ipv6IE, exists := ipv6InfoElements[ie]; exists && isIPv6 {
+		return ipv6IE
+	}
+	return ie
+}
+
 func TestFlowExporter_sendTemplateRecord(t *testing.T) {
+	for _, isIPv6 := range []bool{false, true} {
+		testSendTemplateRecord(t, isIPv6)
+	}
+}
+
+func testSendTemplateRecord(t *testing.T, isIPv6 bool) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -41,25 +55,31 @@ func TestFlowExporter_sendTemplateRecord(t *testing.T) {
 	mockTempRec := ipfixtest.NewMockIPFIXRecord(ctrl)
 	mockIPFIXRegistry := ipfixtest.NewMockIPFIXRegistry(ctrl)
 	flowExp := &flowExporter{
+		nil,
 		nil,
 		mockIPFIXExpProc,
 		nil,
-		testFlowExportFrequency,
-		0,
 		testTemplateID,
+		nil,
+		testTemplateIDIPv6,
 		mockIPFIXRegistry,
+		nil,
+		nil,
+		nil,
+		nil,
+		"",
 	}
 	// Following consists of all elements that are in IANAInfoElements and AntreaInfoElements (globals)
 	// Only the element name is needed, other arguments have dummy values.
 	elemList := make([]*ipfixentities.InfoElement, 0)
 	for _, ie := range IANAInfoElements {
-		elemList = append(elemList, ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.IANAEnterpriseID, 0))
+		elemList = append(elemList, ipfixentities.NewInfoElement(elementName(ie, isIPv6), 0, 0, ipfixregistry.IANAEnterpriseID, 0))
 	}
 	for _, ie := range IANAReverseInfoElements {
 		elemList = append(elemList, ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.ReverseEnterpriseID, 0))
 	}
 	for _, ie := range AntreaInfoElements {
-		elemList = append(elemList, ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.AntreaEnterpriseID, 0))
+		elemList = append(elemList, ipfixentities.NewInfoElement(elementName(ie, isIPv6), 0, 0, ipfixregistry.AntreaEnterpriseID, 0))
 	}
 	// Expect calls for different mock objects
 	tempBytes := uint16(0)
@@ -67,7 +87,7 @@ func TestFlowExporter_sendTemplateRecord(t *testing.T) {
 
 	mockTempRec.EXPECT().PrepareRecord().Return(tempBytes, nil)
 	for i, ie := range IANAInfoElements {
-		mockIPFIXRegistry.EXPECT().GetInfoElement(ie, ipfixregistry.IANAEnterpriseID).Return(elemList[i], nil)
+		mockIPFIXRegistry.EXPECT().GetInfoElement(elementName(ie, isIPv6), ipfixregistry.IANAEnterpriseID).Return(elemList[i], nil)
 		mockTempRec.EXPECT().AddInfoElement(elemList[i], nil).Return(tempBytes, nil)
 	}
 	for i, ie := range IANAReverseInfoElements {
@@ -75,7 +95,7 @@ func TestFlowExporter_sendTemplateRecord(t *testing.T) {
 		mockTempRec.EXPECT().AddInfoElement(elemList[i+len(IANAInfoElements)], nil).Return(tempBytes, nil)
 	}
 	for i, ie := range AntreaInfoElements {
-		mockIPFIXRegistry.EXPECT().GetInfoElement(ie, ipfixregistry.AntreaEnterpriseID).Return(elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)], nil)
+		mockIPFIXRegistry.EXPECT().GetInfoElement(elementName(ie, isIPv6), ipfixregistry.AntreaEnterpriseID).Return(elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)], nil)
 		mockTempRec.EXPECT().AddInfoElement(elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)], nil).Return(tempBytes, nil)
 	}
 	mockTempRec.EXPECT().GetRecord().Return(templateRecord)
@@ -84,17 +104,27 @@ func TestFlowExporter_sendTemplateRecord(t *testing.T) {
 	// above elements: IANAInfoElements, IANAReverseInfoElements and AntreaInfoElements.
 	mockIPFIXExpProc.EXPECT().AddRecordAndSendMsg(ipfixentities.Template, templateRecord).Return(0, nil)
 
-	_, err := flowExp.sendTemplateRecord(mockTempRec)
+	_, err := flowExp.sendTemplateRecord(mockTempRec, isIPv6)
 	if err != nil {
 		t.Errorf("Error in sending templated record: %v", err)
 	}
 
-	assert.Equal(t, len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements), len(flowExp.elementsList), flowExp.elementsList, "flowExp.elementsList and template record should have same number of elements")
+	elementsList := flowExp.elementsList
+	if isIPv6 {
+		elementsList = flowExp.elementsListIPv6
+	}
+	assert.Equal(t, len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements), len(elementsList), elementsList, "flowExp.elementsList and template record should have same number of elements")
 }
 
 // TestFlowExporter_sendDataRecord tests essentially if element names in the switch-case matches globals
 // IANAInfoElements and AntreaInfoElements.
 func TestFlowExporter_sendDataRecord(t *testing.T) {
+	for _, isIPv6 := range []bool{false, true} {
+		testSendDataRecord(t, isIPv6)
+	}
+}
+
+func testSendDataRecord(t *testing.T, isIPv6 bool) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -136,52 +166,68 @@ func TestFlowExporter_sendDataRecord(t *testing.T) {
 	// Need only element name and other are dummys
 	elemList := make([]*ipfixentities.InfoElement, len(IANAInfoElements)+len(IANAReverseInfoElements)+len(AntreaInfoElements))
 	for i, ie := range IANAInfoElements {
-		elemList[i] = ipfixentities.NewInfoElement(ie, 0, 0, 0, 0)
+		elemList[i] = ipfixentities.NewInfoElement(elementName(ie, isIPv6), 0, 0, 0, 0)
 	}
 	for i, ie := range IANAReverseInfoElements {
 		elemList[i+len(IANAInfoElements)] = ipfixentities.NewInfoElement(ie, 0, 0, ipfixregistry.ReverseEnterpriseID, 0)
 	}
 	for i, ie := range AntreaInfoElements {
-		elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)] = ipfixentities.NewInfoElement(ie, 0, 0, 0, 0)
+		elemList[i+len(IANAInfoElements)+len(IANAReverseInfoElements)] = ipfixentities.NewInfoElement(elementName(ie, isIPv6), 0, 0, 0, 0)
 	}
 
 	mockIPFIXExpProc := ipfixtest.NewMockIPFIXExportingProcess(ctrl)
 	mockDataRec := ipfixtest.NewMockIPFIXRecord(ctrl)
 	mockIPFIXRegistry := ipfixtest.NewMockIPFIXRegistry(ctrl)
 	flowExp := &flowExporter{
+		nil,
 		nil,
 		mockIPFIXExpProc,
 		elemList,
-		testFlowExportFrequency,
-		0,
 		testTemplateID,
+		elemList,
+		testTemplateIDIPv6,
 		mockIPFIXRegistry,
+		nil,
+		nil,
+		nil,
+		nil,
+		"",
 	}
 	// Expect calls required
 	var dataRecord ipfixentities.Record
 	tempBytes := uint16(0)
-	for _, ie := range flowExp.elementsList {
+	for _, ie := range elemList {
 		switch ieName := ie.Name; ieName {
 		case "flowStartSeconds", "flowEndSeconds":
 			mockDataRec.EXPECT().AddInfoElement(ie, time.Time{}.Unix()).Return(tempBytes, nil)
-		case "sourceIPv4Address", "destinationIPv4Address":
+		case "sourceIPv4Address", "destinationIPv4Address", "sourceIPv6Address", "destinationIPv6Address":
 			mockDataRec.EXPECT().AddInfoElement(ie, nil).Return(tempBytes, nil)
-		case "destinationClusterIP":
+		case "destinationClusterIP", "egressIP":
 			mockDataRec.EXPECT().AddInfoElement(ie, net.IP{0, 0, 0, 0}).Return(tempBytes, nil)
-		case "sourceTransportPort", "destinationTransportPort":
+		case "destinationClusterIPv6", "egressIPv6":
+			mockDataRec.EXPECT().AddInfoElement(ie, net.IPv6zero).Return(tempBytes, nil)
+		case "sourceTransportPort", "destinationTransportPort", "destinationServicePort":
 			mockDataRec.EXPECT().AddInfoElement(ie, uint16(0)).Return(tempBytes, nil)
-		case "protocolIdentifier":
+		case "protocolIdentifier", "flowEndReason":
 			mockDataRec.EXPECT().AddInfoElement(ie, uint8(0)).Return(tempBytes, nil)
-		case "packetTotalCount", "octetTotalCount", "packetDeltaCount", "octetDeltaCount", "reverse_PacketTotalCount", "reverse_OctetTotalCount", "reverse_PacketDeltaCount", "reverse_OctetDeltaCount":
+		case "packetTotalCount", "octetTotalCount", "packetDeltaCount", "octetDeltaCount", "reverse_PacketTotalCount", "reverse_OctetTotalCount", "reverse_PacketDeltaCount", "reverse_OctetDeltaCount", "throughput", "reverseThroughput":
 			mockDataRec.EXPECT().AddInfoElement(ie, uint64(0)).Return(tempBytes, nil)
-		case "sourcePodName", "sourcePodNamespace", "sourceNodeName", "destinationPodName", "destinationPodNamespace", "destinationNodeName", "destinationServicePortName":
+		case "sourcePodName", "sourcePodNamespace", "sourceNodeName", "destinationPodName", "destinationPodNamespace", "destinationNodeName", "destinationServicePortName", "tcpState", "egressName", "egressNodeName":
 			mockDataRec.EXPECT().AddInfoElement(ie, "").Return(tempBytes, nil)
+		case "flowDenied", "flowHairpin", "exporterRestarted":
+			mockDataRec.EXPECT().AddInfoElement(ie, false).Return(tempBytes, nil)
+		case "flowDirection":
+			mockDataRec.EXPECT().AddInfoElement(ie, flowexporter.FlowDirectionEgress).Return(tempBytes, nil)
+		case "flowKeyHash":
+			mockDataRec.EXPECT().AddInfoElement(ie, flowexporter.GetFlowKeyHash(&flow1)).Return(tempBytes, nil)
+		case "tcpRTT":
+			mockDataRec.EXPECT().AddInfoElement(ie, uint32(0)).Return(tempBytes, nil)
 		}
 	}
 	mockDataRec.EXPECT().GetRecord().Return(dataRecord)
 	mockIPFIXExpProc.EXPECT().AddRecordAndSendMsg(ipfixentities.Data, dataRecord).Return(0, nil)
 
-	err := flowExp.sendDataRecord(mockDataRec, record1)
+	err := flowExp.sendDataRecord(mockDataRec, record1, isIPv6)
 	if err != nil {
 		t.Errorf("Error in sending data record: %v", err)
 	}
@@ -0,0 +1,193 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/klog"
+)
+
+// TLSConfig is the configuration used to export flow records to the collector
+// over mutual TLS. The CA bundle is read every time the connection to the
+// collector is established, and the client certificate is read at every TLS
+// handshake, so rotated certificates are picked up without restarting the
+// Agent.
+type TLSConfig struct {
+	// CAFile is the path of the CA bundle used to verify the certificate of
+	// the collector. The system roots are used if it is empty.
+	CAFile string
+	// CertFile and KeyFile are the paths of the client certificate and key
+	// presented to the collector.
+	CertFile string
+	KeyFile  string
+	// ServerName is used to verify the certificate of the collector. The
+	// host of the collector address is used if it is empty.
+	ServerName string
+}
+
+func (c *TLSConfig) load(collectorHost string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		GetClientCertificate: c.getClientCertificate,
+		ServerName:           c.ServerName,
+		MinVersion:           tls.VersionTLS12,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = collectorHost
+	}
+	if c.CAFile != "" {
+		caBytes, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading CA bundle: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return nil, fmt.Errorf("no valid certificate in CA bundle %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// getClientCertificate loads the client certificate from its files when the
+// collector requests it during the TLS handshake.
+func (c *TLSConfig) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error when loading client certificate: %v", err)
+	}
+	return &cert, nil
+}
+
+// tlsTunnel relays the IPFIX messages of the exporting process to the
+// collector over TLS. The go-ipfix exporting process only supports plaintext
+// transports and dials the collector address itself, so it is given the
+// address of a Unix socket of the tunnel, which forwards the messages over the
+// TLS connection. The socket is created in a private directory and is removed
+// as soon as the exporting process is connected, and only a connection from
+// this process is accepted, so that no other process can inject flow records
+// into the TLS connection.
+type tlsTunnel struct {
+	listener  net.Listener
+	socketDir string
+	tlsConn   net.Conn
+	closeOnce sync.Once
+}
+
+// newTLSTunnel establishes the TLS connection to the collector and starts
+// listening on a Unix socket for the exporting process. The handshake is
+// completed before returning, so that TLS errors are reported to the caller.
+func newTLSTunnel(collector net.Addr, config *TLSConfig) (*tlsTunnel, error) {
+	host, _, err := net.SplitHostPort(collector.String())
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := config.load(host)
+	if err != nil {
+		return nil, err
+	}
+	tlsConn, err := tls.Dial("tcp", collector.String(), tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error when connecting to the collector over TLS: %v", err)
+	}
+	// ioutil.TempDir creates the directory with permissions 0700.
+	socketDir, err := ioutil.TempDir("", "antrea-flow-exporter")
+	if err != nil {
+		tlsConn.Close()
+		return nil, fmt.Errorf("error when creating the directory of the TLS tunnel socket: %v", err)
+	}
+	listener, err := net.Listen("unix", filepath.Join(socketDir, "ipfix.sock"))
+	if err != nil {
+		tlsConn.Close()
+		os.RemoveAll(socketDir)
+		return nil, fmt.Errorf("error when creating the listener of the TLS tunnel: %v", err)
+	}
+	t := &tlsTunnel{
+		listener:  listener,
+		socketDir: socketDir,
+		tlsConn:   tlsConn,
+	}
+	go t.relay()
+	return t, nil
+}
+
+// localAddr returns the address the exporting process must connect to.
+func (t *tlsTunnel) localAddr() net.Addr {
+	return t.listener.Addr()
+}
+
+// accept returns the first connection to the tunnel which is established by
+// this process, i.e. by the exporting process. Connections from other
+// processes are rejected.
+func (t *tlsTunnel) accept() (net.Conn, error) {
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTunnelPeer(conn); err != nil {
+			klog.Warningf("Rejected connection to the TLS tunnel of the flow exporter: %v", err)
+			conn.Close()
+			continue
+		}
+		return conn, nil
+	}
+}
+
+// relay accepts a single connection from the exporting process and forwards
+// its data over the TLS connection. The tunnel is closed when either side is
+// closed, so that the exporting process gets an error on its next write and
+// the exporter reconnects.
+func (t *tlsTunnel) relay() {
+	localConn, err := t.accept()
+	// Only the exporting process connects to the tunnel.
+	t.closeListener()
+	if err != nil {
+		t.close()
+		return
+	}
+	go func() {
+		// The collector isn't supposed to send anything, but reading from
+		// the TLS connection detects its closure.
+		io.Copy(localConn, t.tlsConn)
+		localConn.Close()
+		t.close()
+	}()
+	if _, err := io.Copy(t.tlsConn, localConn); err != nil {
+		klog.Errorf("Error when relaying flow records to the collector over TLS: %v", err)
+	}
+	localConn.Close()
+	t.close()
+}
+
+func (t *tlsTunnel) closeListener() {
+	t.listener.Close()
+	os.RemoveAll(t.socketDir)
+}
+
+func (t *tlsTunnel) close() {
+	t.closeOnce.Do(func() {
+		t.closeListener()
+		t.tlsConn.Close()
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) writeFiles(t *testing.T, dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600))
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestTLSTunnel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-exporter-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "flow-aggregator-ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	serverCert := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "collector"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	clientCert := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "antrea-agent"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+	caFile, _ := ca.writeFiles(t, dir, "ca")
+	clientCertFile, clientKeyFile := clientCert.writeFiles(t, dir, "client")
+
+	// The collector requires a client certificate signed by the CA.
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca.cert)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.der}, PrivateKey: serverCert.key}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    caPool,
+	})
+	require.NoError(t, err)
+	defer listener.Close()
+	received := make(chan []byte, 3)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				data, _ := ioutil.ReadAll(conn)
+				received <- data
+			}()
+		}
+	}()
+
+	tunnel, err := newTLSTunnel(listener.Addr(), &TLSConfig{CAFile: caFile, CertFile: clientCertFile, KeyFile: clientKeyFile})
+	require.NoError(t, err)
+	defer tunnel.close()
+	assert.Equal(t, "unix", tunnel.localAddr().Network())
+
+	conn, err := net.Dial("unix", tunnel.localAddr().String())
+	require.NoError(t, err)
+	_, err = io.WriteString(conn, "ipfix message")
+	require.NoError(t, err)
+	conn.Close()
+	select {
+	case data := <-received:
+		assert.Equal(t, "ipfix message", string(data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Collector didn't receive the message sent through the tunnel")
+	}
+	// The socket is removed once the exporting process is connected.
+	_, err = net.Dial("unix", tunnel.localAddr().String())
+	assert.Error(t, err)
+
+	// The certificate of the collector is not trusted without the CA.
+	_, err = newTLSTunnel(listener.Addr(), &TLSConfig{CertFile: clientCertFile, KeyFile: clientKeyFile})
+	assert.Error(t, err)
+	// The client certificate is required.
+	_, err = newTLSTunnel(listener.Addr(), &TLSConfig{CAFile: caFile})
+	assert.Error(t, err)
+}
+
+func TestTLSConfigReloadsClientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flow-exporter-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	newClientCert := func(serial int64) *testCert {
+		return newTestCert(t, &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "antrea-agent"},
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}, nil)
+	}
+	certFile, keyFile := newClientCert(1).writeFiles(t, dir, "client")
+	tlsConfig, err := (&TLSConfig{CertFile: certFile, KeyFile: keyFile}).load("127.0.0.1")
+	require.NoError(t, err)
+	cert, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), leaf.SerialNumber.Int64())
+
+	// The rotated certificate is used by the next handshake.
+	newClientCert(2).writeFiles(t, dir, "client")
+	cert, err = tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), leaf.SerialNumber.Int64())
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowrecords
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+// exportBaseline is the state of a flow record when it was last exported. It is persisted so that the connections
+// which survive a restart of antrea-agent keep being exported with the right delta counts.
+type exportBaseline struct {
+	Key flowexporter.ConnectionKey `json:"key"`
+	// ID and StartTime identify the conntrack connection, as the 5-tuple of a connection can be reused.
+	ID                 uint32    `json:"id"`
+	StartTime          time.Time `json:"startTime"`
+	PrevPackets        uint64    `json:"prevPackets"`
+	PrevBytes          uint64    `json:"prevBytes"`
+	PrevReversePackets uint64    `json:"prevReversePackets"`
+	PrevReverseBytes   uint64    `json:"prevReverseBytes"`
+	LastExportTime     time.Time `json:"lastExportTime"`
+}
+
+// SaveExportBaseline writes the state of the flow records which have been exported to the file. The file is replaced
+// atomically, so that it is never read partially written.
+func (fr *FlowRecords) SaveExportBaseline(path string) error {
+	fr.mutex.RLock()
+	baselines := make([]exportBaseline, 0, len(fr.recordsMap))
+	for key, record := range fr.recordsMap {
+		if record.PrevPackets == 0 && record.PrevReversePackets == 0 {
+			continue
+		}
+		baselines = append(baselines, exportBaseline{
+			Key:                key,
+			ID:                 record.Conn.ID,
+			StartTime:          record.Conn.StartTime,
+			PrevPackets:        record.PrevPackets,
+			PrevBytes:          record.PrevBytes,
+			PrevReversePackets: record.PrevReversePackets,
+			PrevReverseBytes:   record.PrevReverseBytes,
+			LastExportTime:     record.LastExportTime,
+		})
+	}
+	fr.mutex.RUnlock()
+
+	data, err := json.Marshal(baselines)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// RestoreExportBaseline reads the state of the flow records saved before the restart of antrea-agent. The records of
+// the connections found in the first build of the flow records are marked as restarted, and their delta counts are
+// computed from the saved state when there is one. A missing file is not an error, e.g. on the first start.
+func (fr *FlowRecords) RestoreExportBaseline(path string) error {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+	fr.restoring = true
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var baselines []exportBaseline
+	if err := json.Unmarshal(data, &baselines); err != nil {
+		return err
+	}
+	fr.baselines = make(map[flowexporter.ConnectionKey]exportBaseline, len(baselines))
+	for _, baseline := range baselines {
+		fr.baselines[baseline.Key] = baseline
+	}
+	klog.Infof("Restored the export baseline of %d flow records", len(baselines))
+	return nil
+}
+
+// restoreRecord marks the new record of a connection found in the first build after restart, and applies the saved
+// state of the connection if it is the same connection and its counters did not go backwards.
+func (fr *FlowRecords) restoreRecord(key flowexporter.ConnectionKey, record *flowexporter.FlowRecord) {
+	record.Restarted = true
+	baseline, exists := fr.baselines[key]
+	if !exists {
+		return
+	}
+	conn := record.Conn
+	if baseline.ID != conn.ID || !baseline.StartTime.Equal(conn.StartTime) ||
+		conn.OriginalPackets < baseline.PrevPackets || conn.ReversePackets < baseline.PrevReversePackets {
+		return
+	}
+	record.PrevPackets = baseline.PrevPackets
+	record.PrevBytes = baseline.PrevBytes
+	record.PrevReversePackets = baseline.PrevReversePackets
+	record.PrevReverseBytes = baseline.PrevReverseBytes
+	record.LastExportTime = baseline.LastExportTime
+}
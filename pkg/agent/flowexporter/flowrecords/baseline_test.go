@@ -0,0 +1,107 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowrecords
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+)
+
+func newTestConn(id uint32, srcPort uint16, packets uint64) flowexporter.Connection {
+	return flowexporter.Connection{
+		ID:              id,
+		StartTime:       time.Date(2020, 10, 14, 12, 0, 0, 0, time.UTC),
+		IsActive:        true,
+		DoExport:        true,
+		TupleOrig:       flowexporter.Tuple{SourceAddress: net.IP{1, 2, 3, 4}, SourcePort: srcPort, Protocol: 6},
+		TupleReply:      flowexporter.Tuple{SourceAddress: net.IP{4, 3, 2, 1}, SourcePort: 80, Protocol: 6},
+		OriginalPackets: packets,
+		OriginalBytes:   packets * 100,
+		ReversePackets:  packets,
+		ReverseBytes:    packets * 100,
+	}
+}
+
+func TestFlowRecords_RestoreExportBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flowrecords")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	baselineFile := filepath.Join(dir, "baseline.json")
+
+	// A missing file only enables the restart indicator.
+	flowRecords := NewFlowRecords(nil, testActiveFlowTimeout, testIdleFlowTimeout)
+	require.NoError(t, flowRecords.RestoreExportBaseline(baselineFile))
+	assert.True(t, flowRecords.restoring)
+
+	exportedConn := newTestConn(1, 1000, 10)
+	reusedConn := newTestConn(2, 2000, 10)
+	lastExportTime := time.Date(2020, 10, 14, 12, 1, 0, 0, time.UTC)
+	for _, conn := range []flowexporter.Connection{exportedConn, reusedConn} {
+		conn := conn
+		flowRecords.recordsMap[flowexporter.NewConnectionKey(&conn)] = flowexporter.FlowRecord{
+			Conn:               &conn,
+			PrevPackets:        conn.OriginalPackets,
+			PrevBytes:          conn.OriginalBytes,
+			PrevReversePackets: conn.ReversePackets,
+			PrevReverseBytes:   conn.ReverseBytes,
+			LastExportTime:     lastExportTime,
+		}
+	}
+	// A record which has never been exported is not saved.
+	newConn := newTestConn(3, 3000, 10)
+	flowRecords.recordsMap[flowexporter.NewConnectionKey(&newConn)] = flowexporter.FlowRecord{Conn: &newConn}
+	require.NoError(t, flowRecords.SaveExportBaseline(baselineFile))
+
+	// After restart, the delta counts of the same connection are computed from the saved state, while the
+	// connection reusing the 5-tuple of a saved one is counted from its start.
+	flowRecords = NewFlowRecords(nil, testActiveFlowTimeout, testIdleFlowTimeout)
+	require.NoError(t, flowRecords.RestoreExportBaseline(baselineFile))
+	assert.Len(t, flowRecords.baselines, 2)
+	exportedConn.OriginalPackets = 15
+	reusedConn.ID = 4
+	for _, conn := range []flowexporter.Connection{exportedConn, reusedConn, newConn} {
+		require.NoError(t, flowRecords.addOrUpdateFlowRecord(flowexporter.NewConnectionKey(&conn), conn))
+	}
+
+	exportedKey := flowexporter.NewConnectionKey(&exportedConn)
+	record, _ := flowRecords.GetFlowRecordByConnKey(exportedKey)
+	assert.True(t, record.Restarted)
+	assert.Equal(t, uint64(10), record.PrevPackets)
+	assert.Equal(t, uint64(1000), record.PrevBytes)
+	assert.Equal(t, lastExportTime, record.LastExportTime)
+	updateDeltaStats(record, lastExportTime.Add(time.Minute))
+	assert.Equal(t, uint64(5), record.DeltaPackets)
+
+	for _, conn := range []flowexporter.Connection{reusedConn, newConn} {
+		record, _ := flowRecords.GetFlowRecordByConnKey(flowexporter.NewConnectionKey(&conn))
+		assert.True(t, record.Restarted)
+		assert.Equal(t, uint64(0), record.PrevPackets)
+	}
+
+	// The indicator is cleared once the record is exported.
+	require.NoError(t, flowRecords.ValidateAndUpdateStats(exportedKey, *record))
+	record, _ = flowRecords.GetFlowRecordByConnKey(exportedKey)
+	assert.False(t, record.Restarted)
+	assert.Equal(t, uint64(15), record.PrevPackets)
+}
@@ -15,6 +15,10 @@
 package flowrecords
 
 import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
@@ -22,37 +26,62 @@ import (
 )
 
 type FlowRecords struct {
-	// Add lock when this map is consumed by more than one entity concurrently.
+	// recordsMap is only updated by the Flow Exporter, and can be read concurrently by the agent API server. mutex
+	// protects it.
 	recordsMap map[flowexporter.ConnectionKey]flowexporter.FlowRecord
-	connStore  *connections.ConnectionStore
+	mutex      sync.RWMutex
+	connStore  connections.Store
+	// activeFlowTimeout is the interval after which a record of an active connection is exported again.
+	activeFlowTimeout time.Duration
+	// idleFlowTimeout is the interval without any update in connection stats after which a record is exported and,
+	// if the connection is not present in conntrack table anymore, expired.
+	idleFlowTimeout time.Duration
+	clock           clock.Clock
+	// restoring is true until the first build of the flow records after RestoreExportBaseline, and baselines are the
+	// saved states of the records exported before the restart of antrea-agent.
+	restoring bool
+	baselines map[flowexporter.ConnectionKey]exportBaseline
 }
 
-func NewFlowRecords(connStore *connections.ConnectionStore) *FlowRecords {
+func NewFlowRecords(connStore connections.Store, activeFlowTimeout time.Duration, idleFlowTimeout time.Duration) *FlowRecords {
 	return &FlowRecords{
-		make(map[flowexporter.ConnectionKey]flowexporter.FlowRecord),
-		connStore,
+		recordsMap:        make(map[flowexporter.ConnectionKey]flowexporter.FlowRecord),
+		connStore:         connStore,
+		activeFlowTimeout: activeFlowTimeout,
+		idleFlowTimeout:   idleFlowTimeout,
+		clock:             clock.RealClock{},
 	}
 }
 
 // BuildFlowRecords builds the flow record map from connection map in connection store
 func (fr *FlowRecords) BuildFlowRecords() error {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
 	// fr.addOrUpdateFlowRecord method does not return any error, hence no error handling required.
 	fr.connStore.ForAllConnectionsDo(fr.addOrUpdateFlowRecord)
+	// The connections which are not found in the first build have been closed during the restart.
+	fr.restoring = false
+	fr.baselines = nil
 	klog.V(2).Infof("No. of flow records built: %d", len(fr.recordsMap))
 	return nil
 }
 
 // GetFlowRecordByConnKey gets the record from the flow record map given the connection key
 func (fr *FlowRecords) GetFlowRecordByConnKey(connKey flowexporter.ConnectionKey) (*flowexporter.FlowRecord, bool) {
+	fr.mutex.RLock()
+	defer fr.mutex.RUnlock()
 	record, found := fr.recordsMap[connKey]
 	return &record, found
 }
 
-// ValidateAndUpdateStats validates and updates the flow record given the connection key
+// ValidateAndUpdateStats validates and updates the flow record given the connection key, after the record is exported.
+// The flow record is deleted if its idle timeout has expired and the corresponding connection is not active, i.e., not
+// present in conntrack table. The corresponding connection in connectionMap is deleted as well.
 func (fr *FlowRecords) ValidateAndUpdateStats(connKey flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
-	// Delete the flow record if the corresponding connection is not active, i.e., not present in conntrack table.
-	// Delete the corresponding connection in connectionMap as well.
-	if !record.Conn.IsActive {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+	now := fr.clock.Now()
+	if !record.Conn.IsActive && fr.isIdle(record, now) {
 		klog.V(2).Infof("Deleting the inactive connection with key: %v", connKey)
 		delete(fr.recordsMap, connKey)
 		if err := fr.connStore.DeleteConnectionByKey(connKey); err != nil {
@@ -64,14 +93,19 @@ func (fr *FlowRecords) ValidateAndUpdateStats(connKey flowexporter.ConnectionKey
 		record.PrevBytes = record.Conn.OriginalBytes
 		record.PrevReversePackets = record.Conn.ReversePackets
 		record.PrevReverseBytes = record.Conn.ReverseBytes
+		record.LastExportTime = now
+		record.Restarted = false
 		fr.recordsMap[connKey] = record
 	}
 
 	return nil
 }
 
-// ForAllFlowRecordsDo executes the callback for all records in the flow record map
+// ForAllFlowRecordsDo executes the callback for all records in the flow record map. The callback must not update the
+// flow records.
 func (fr *FlowRecords) ForAllFlowRecordsDo(callback flowexporter.FlowRecordCallBack) error {
+	fr.mutex.RLock()
+	defer fr.mutex.RUnlock()
 	for k, v := range fr.recordsMap {
 		err := callback(k, v)
 		if err != nil {
@@ -83,12 +117,117 @@ func (fr *FlowRecords) ForAllFlowRecordsDo(callback flowexporter.FlowRecordCallB
 	return nil
 }
 
+// ForAllExpiredFlowRecordsDo executes the callback for all records in the flow record map that are due for export:
+// records with new stats whose active timeout or idle timeout has expired, and records whose idle timeout has expired
+// and whose connection is not present in conntrack table anymore, so that the end of the flow is reported before the
+// record is deleted. The expired records are collected before executing the callbacks, so that the callback can update
+// the flow records, e.g. with ValidateAndUpdateStats.
+func (fr *FlowRecords) ForAllExpiredFlowRecordsDo(callback flowexporter.FlowRecordCallBack) error {
+	type expiredRecord struct {
+		key    flowexporter.ConnectionKey
+		record flowexporter.FlowRecord
+	}
+	var expiredRecords []expiredRecord
+	now := fr.clock.Now()
+	fr.mutex.RLock()
+	for k, v := range fr.recordsMap {
+		isIdle := fr.isIdle(v, now)
+		isActiveExpired := now.Sub(v.LastExportTime) >= fr.activeFlowTimeout
+		if (hasNewStats(v) && (isIdle || isActiveExpired)) || (isIdle && !v.Conn.IsActive) {
+			v.FlowEndReason = getFlowEndReason(v, isIdle)
+			updateDeltaStats(&v, now)
+			expiredRecords = append(expiredRecords, expiredRecord{k, v})
+		}
+	}
+	fr.mutex.RUnlock()
+
+	for _, r := range expiredRecords {
+		if err := callback(r.key, r.record); err != nil {
+			klog.Errorf("Error when executing callback for flow record")
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFlowRecords returns a copy of all the records in the flow record map. It can be called concurrently with the
+// Flow Exporter.
+func (fr *FlowRecords) GetFlowRecords() []flowexporter.FlowRecord {
+	fr.mutex.RLock()
+	defer fr.mutex.RUnlock()
+	records := make([]flowexporter.FlowRecord, 0, len(fr.recordsMap))
+	for _, record := range fr.recordsMap {
+		conn := *record.Conn
+		record.Conn = &conn
+		records = append(records, record)
+	}
+	return records
+}
+
+// isIdle returns true if the stats of the connection have not been updated for idleFlowTimeout.
+func (fr *FlowRecords) isIdle(record flowexporter.FlowRecord, now time.Time) bool {
+	return now.Sub(record.LastActiveTime) >= fr.idleFlowTimeout
+}
+
+// getFlowEndReason returns the reason why a record is exported. The end of the flow is detected when the connection
+// is not present in conntrack table anymore after being closed by one of the endpoints; otherwise the record is
+// exported because of one of the timeouts.
+func getFlowEndReason(record flowexporter.FlowRecord, isIdle bool) uint8 {
+	if !isIdle {
+		return flowexporter.ActiveTimeoutReason
+	}
+	if !record.Conn.IsActive && flowexporter.IsConnectionClosing(record.Conn) {
+		return flowexporter.EndOfFlowReason
+	}
+	return flowexporter.IdleTimeoutReason
+}
+
+// updateDeltaStats computes the stats of the connection since the record was last exported, and the throughputs
+// derived from them. For a record which has never been exported, the stats are counted from the start of the
+// connection.
+func updateDeltaStats(record *flowexporter.FlowRecord, now time.Time) {
+	record.DeltaPackets = deltaCount(record.Conn.OriginalPackets, record.PrevPackets)
+	record.DeltaBytes = deltaCount(record.Conn.OriginalBytes, record.PrevBytes)
+	record.DeltaReversePackets = deltaCount(record.Conn.ReversePackets, record.PrevReversePackets)
+	record.DeltaReverseBytes = deltaCount(record.Conn.ReverseBytes, record.PrevReverseBytes)
+
+	intervalStart := record.LastExportTime
+	if record.PrevPackets == 0 && record.PrevReversePackets == 0 && !record.Conn.StartTime.IsZero() {
+		intervalStart = record.Conn.StartTime
+	}
+	record.Throughput = 0
+	record.ReverseThroughput = 0
+	if interval := now.Sub(intervalStart).Seconds(); interval > 0 {
+		record.Throughput = uint64(float64(record.DeltaBytes*8) / interval)
+		record.ReverseThroughput = uint64(float64(record.DeltaReverseBytes*8) / interval)
+	}
+}
+
+// deltaCount returns the increase of a counter since its previous value. The counters of a connection are not
+// expected to decrease, 0 is returned if they do.
+func deltaCount(current, prev uint64) uint64 {
+	if current < prev {
+		klog.Warningf("Counter is not expected to decrease from %d to %d", prev, current)
+		return 0
+	}
+	return current - prev
+}
+
+// hasNewStats returns true if the stats of the connection have changed since the record was last exported.
+func hasNewStats(record flowexporter.FlowRecord) bool {
+	return record.Conn.OriginalPackets != record.PrevPackets ||
+		record.Conn.OriginalBytes != record.PrevBytes ||
+		record.Conn.ReversePackets != record.PrevReversePackets ||
+		record.Conn.ReverseBytes != record.PrevReverseBytes
+}
+
 func (fr *FlowRecords) addOrUpdateFlowRecord(key flowexporter.ConnectionKey, conn flowexporter.Connection) error {
 	// If DoExport flag is not set return immediately.
 	if !conn.DoExport {
 		return nil
 	}
 
+	now := fr.clock.Now()
 	record, exists := fr.recordsMap[key]
 	if !exists {
 		record = flowexporter.FlowRecord{
@@ -97,8 +236,16 @@ func (fr *FlowRecords) addOrUpdateFlowRecord(key flowexporter.ConnectionKey, con
 			PrevBytes:          0,
 			PrevReversePackets: 0,
 			PrevReverseBytes:   0,
+			LastExportTime:     now,
+			LastActiveTime:     now,
+		}
+		if fr.restoring {
+			fr.restoreRecord(key, &record)
 		}
 	} else {
+		if conn.OriginalPackets != record.Conn.OriginalPackets || conn.ReversePackets != record.Conn.ReversePackets {
+			record.LastActiveTime = now
+		}
 		record.Conn = &conn
 	}
 	fr.recordsMap[key] = record
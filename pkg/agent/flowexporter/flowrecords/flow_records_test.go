@@ -0,0 +1,159 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowrecords
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
+	connectionstest "github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections/testing"
+	interfacestoretest "github.com/vmware-tanzu/antrea/pkg/agent/interfacestore/testing"
+	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+)
+
+const (
+	testPollInterval      = 0 // Not used in these tests, hence 0.
+	testActiveFlowTimeout = 60 * time.Second
+	testIdleFlowTimeout   = 15 * time.Second
+)
+
+func TestFlowRecords_ForAllExpiredFlowRecordsDo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	startTime := time.Date(2020, 10, 14, 12, 0, 0, 0, time.UTC)
+	fakeClock := clock.NewFakeClock(startTime)
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	mockIfaceStore := interfacestoretest.NewMockInterfaceStore(ctrl)
+	mockIfaceStore.EXPECT().GetInterfaceByIP(gomock.Any()).Return(nil, false).AnyTimes()
+	mockConnDumper.EXPECT().GetMaxConnections().Return(0, nil).AnyTimes()
+	connStore := connections.NewConnectionStore(mockConnDumper, mockIfaceStore, nil, nil, "", nil, nil, testPollInterval, 0, 0, nil, nil)
+	flowRecords := NewFlowRecords(connStore, testActiveFlowTimeout, testIdleFlowTimeout)
+	flowRecords.clock = fakeClock
+
+	conn := flowexporter.Connection{
+		StartTime: startTime,
+		StopTime:  startTime,
+		DoExport:  true,
+		TCPState:  "ESTABLISHED",
+		TupleOrig: flowexporter.Tuple{
+			SourceAddress:      net.IP{1, 2, 3, 4},
+			DestinationAddress: net.IP{4, 3, 2, 1},
+			Protocol:           6,
+			SourcePort:         65280,
+			DestinationPort:    255,
+		},
+		TupleReply: flowexporter.Tuple{
+			SourceAddress:      net.IP{4, 3, 2, 1},
+			DestinationAddress: net.IP{1, 2, 3, 4},
+			Protocol:           6,
+			SourcePort:         255,
+			DestinationPort:    65280,
+		},
+		OriginalPackets: 10,
+		OriginalBytes:   1000,
+		ReversePackets:  10,
+		ReverseBytes:    1000,
+	}
+	connKey := flowexporter.NewConnectionKey(&conn)
+
+	// pollAndBuild polls the given connections from conntrack and builds the flow records, as done at every poll
+	// cycle, then returns the keys of the records that are due for export. The last exported record is stored in
+	// lastRecord.
+	var lastRecord flowexporter.FlowRecord
+	pollAndBuild := func(conns ...*flowexporter.Connection) []flowexporter.ConnectionKey {
+		mockConnDumper.EXPECT().DumpFlows(openflow.GetCtZone(openflow.CtZoneFeaturePipeline)).Return(conns, len(conns), nil)
+		_, err := connStore.Poll()
+		require.NoError(t, err)
+		require.NoError(t, flowRecords.BuildFlowRecords())
+		var expiredKeys []flowexporter.ConnectionKey
+		err = flowRecords.ForAllExpiredFlowRecordsDo(func(key flowexporter.ConnectionKey, record flowexporter.FlowRecord) error {
+			expiredKeys = append(expiredKeys, key)
+			lastRecord = record
+			return flowRecords.ValidateAndUpdateStats(key, record)
+		})
+		require.NoError(t, err)
+		return expiredKeys
+	}
+	updateConn := func() *flowexporter.Connection {
+		conn.OriginalPackets += 10
+		conn.OriginalBytes += 1000
+		conn.StopTime = fakeClock.Now()
+		newConn := conn
+		return &newConn
+	}
+
+	// A new record is not exported before any of the timeouts expires.
+	newConn := conn
+	assert.Empty(t, pollAndBuild(&newConn))
+
+	// The connection keeps being active, the record is exported once the active timeout expires.
+	fakeClock.Step(30 * time.Second)
+	assert.Empty(t, pollAndBuild(updateConn()))
+	fakeClock.Step(30 * time.Second)
+	assert.Equal(t, []flowexporter.ConnectionKey{connKey}, pollAndBuild(updateConn()))
+	assert.Equal(t, flowexporter.ActiveTimeoutReason, lastRecord.FlowEndReason)
+	// The first export counts the stats since the start of the connection.
+	assert.Equal(t, uint64(30), lastRecord.DeltaPackets)
+	assert.Equal(t, uint64(3000), lastRecord.DeltaBytes)
+	assert.Equal(t, uint64(10), lastRecord.DeltaReversePackets)
+	assert.Equal(t, uint64(1000), lastRecord.DeltaReverseBytes)
+	assert.Equal(t, uint64(3000*8/60), lastRecord.Throughput)
+	assert.Equal(t, uint64(1000*8/60), lastRecord.ReverseThroughput)
+	record, exists := flowRecords.GetFlowRecordByConnKey(connKey)
+	require.True(t, exists)
+	assert.Equal(t, conn.OriginalPackets, record.PrevPackets)
+	assert.Equal(t, fakeClock.Now(), record.LastExportTime)
+	records := flowRecords.GetFlowRecords()
+	require.Len(t, records, 1)
+	assert.Equal(t, *record, records[0])
+
+	// The connection is still in conntrack table, but its stats are not updated anymore. The record is exported once
+	// the idle timeout expires, and then kept without being exported again.
+	fakeClock.Step(5 * time.Second)
+	lastConn := updateConn()
+	assert.Empty(t, pollAndBuild(lastConn))
+	fakeClock.Step(testIdleFlowTimeout)
+	assert.Equal(t, []flowexporter.ConnectionKey{connKey}, pollAndBuild(lastConn))
+	assert.Equal(t, flowexporter.IdleTimeoutReason, lastRecord.FlowEndReason)
+	// The next export counts the stats since the last export.
+	assert.Equal(t, uint64(10), lastRecord.DeltaPackets)
+	assert.Equal(t, uint64(1000), lastRecord.DeltaBytes)
+	assert.Equal(t, uint64(0), lastRecord.DeltaReversePackets)
+	assert.Equal(t, uint64(1000*8/20), lastRecord.Throughput)
+	assert.Equal(t, uint64(0), lastRecord.ReverseThroughput)
+	fakeClock.Step(testIdleFlowTimeout)
+	lastConn.TCPState = "TIME_WAIT"
+	assert.Empty(t, pollAndBuild(lastConn))
+	_, exists = flowRecords.GetFlowRecordByConnKey(connKey)
+	assert.True(t, exists)
+
+	// The connection is no longer present in conntrack table after being closed. Its record is exported and
+	// expired, and the connection is deleted from the connection store.
+	assert.Equal(t, []flowexporter.ConnectionKey{connKey}, pollAndBuild())
+	assert.Equal(t, flowexporter.EndOfFlowReason, lastRecord.FlowEndReason)
+	_, exists = flowRecords.GetFlowRecordByConnKey(connKey)
+	assert.False(t, exists)
+	_, exists = connStore.GetConnByKey(connKey)
+	assert.False(t, exists)
+}
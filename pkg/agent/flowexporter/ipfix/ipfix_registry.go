@@ -21,6 +21,32 @@ import (
 
 var _ IPFIXRegistry = new(ipfixRegistry)
 
+// antreaInfoElements are the Antrea Information Elements which are not part of the Antrea registry of the go-ipfix
+// library yet.
+var antreaInfoElements = map[string]*ipfixentities.InfoElement{
+	// throughput and reverseThroughput are in bits per second.
+	"throughput":        ipfixentities.NewInfoElement("throughput", 138, ipfixentities.Unsigned64, ipfixregistry.AntreaEnterpriseID, 8),
+	"reverseThroughput": ipfixentities.NewInfoElement("reverseThroughput", 139, ipfixentities.Unsigned64, ipfixregistry.AntreaEnterpriseID, 8),
+	// flowKeyHash is the same in the flow records exported by the source and destination Nodes of a connection.
+	"flowKeyHash": ipfixentities.NewInfoElement("flowKeyHash", 140, ipfixentities.Unsigned64, ipfixregistry.AntreaEnterpriseID, 8),
+	// flowHairpin is true for the connections from a Pod to a Service whose selected Endpoint is the Pod itself.
+	"flowHairpin": ipfixentities.NewInfoElement("flowHairpin", 141, ipfixentities.Boolean, ipfixregistry.AntreaEnterpriseID, 1),
+	// tcpRTT is the smoothed RTT of TCP connections in microseconds, as measured by the TCP stack of the local Pod.
+	"tcpRTT": ipfixentities.NewInfoElement("tcpRTT", 142, ipfixentities.Unsigned32, ipfixregistry.AntreaEnterpriseID, 4),
+	// egressName, egressIP and egressNodeName are the egress configuration used by the connections which leave the
+	// cluster.
+	"egressName":     ipfixentities.NewInfoElement("egressName", 143, ipfixentities.String, ipfixregistry.AntreaEnterpriseID, 65535),
+	"egressIP":       ipfixentities.NewInfoElement("egressIP", 144, ipfixentities.Ipv4Address, ipfixregistry.AntreaEnterpriseID, 4),
+	"egressNodeName": ipfixentities.NewInfoElement("egressNodeName", 145, ipfixentities.String, ipfixregistry.AntreaEnterpriseID, 65535),
+	// exporterRestarted is true in the first record exported after the restart of antrea-agent for the connections
+	// which existed before the restart.
+	"exporterRestarted": ipfixentities.NewInfoElement("exporterRestarted", 146, ipfixentities.Boolean, ipfixregistry.AntreaEnterpriseID, 1),
+	// destinationClusterIPv6 and egressIPv6 replace destinationClusterIP and egressIP in the flow records of IPv6
+	// connections.
+	"destinationClusterIPv6": ipfixentities.NewInfoElement("destinationClusterIPv6", 147, ipfixentities.Ipv6Address, ipfixregistry.AntreaEnterpriseID, 16),
+	"egressIPv6":             ipfixentities.NewInfoElement("egressIPv6", 148, ipfixentities.Ipv6Address, ipfixregistry.AntreaEnterpriseID, 16),
+}
+
 // IPFIXRegistry interface is added to facilitate unit testing without involving the code from go-ipfix library.
 type IPFIXRegistry interface {
 	LoadRegistry()
@@ -38,5 +64,10 @@ func (reg *ipfixRegistry) LoadRegistry() {
 }
 
 func (reg *ipfixRegistry) GetInfoElement(name string, enterpriseID uint32) (*ipfixentities.InfoElement, error) {
+	if enterpriseID == ipfixregistry.AntreaEnterpriseID {
+		if ie, exists := antreaInfoElements[name]; exists {
+			return ie, nil
+		}
+	}
 	return ipfixregistry.GetInfoElement(name, enterpriseID)
 }
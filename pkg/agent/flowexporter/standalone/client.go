@@ -0,0 +1,93 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package standalone provides the sources of the flow exporter when it runs in
+// a separate container: the state owned by antrea-agent is read from its API,
+// while the Nodes and Services are watched from the K8s apiserver.
+package standalone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver"
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/nodeconfig"
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/podinterface"
+)
+
+// AgentClient queries the antrea-agent API running on the same Node.
+type AgentClient interface {
+	// GetNodeConfig returns the configuration of the Node discovered by antrea-agent.
+	GetNodeConfig() (*nodeconfig.Response, error)
+	// GetPodInterfaces returns the interfaces of the local Pods.
+	GetPodInterfaces() ([]podinterface.Response, error)
+}
+
+type agentClient struct {
+	restClient rest.Interface
+}
+
+// NewAgentClient creates an AgentClient for the antrea-agent API served on the
+// loopback address with apiPort. It authenticates with the loopback client
+// token of antrea-agent, so the antrea-agent run directory must be mounted.
+func NewAgentClient(apiPort int) (AgentClient, error) {
+	config := &rest.Config{
+		Host:            net.JoinHostPort("127.0.0.1", fmt.Sprint(apiPort)),
+		BearerTokenFile: apiserver.TokenPath,
+		// antrea-agent serves its API with a self-signed certificate.
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+		ContentConfig: rest.ContentConfig{
+			GroupVersion:         &schema.GroupVersion{},
+			NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		},
+	}
+	restClient, err := rest.UnversionedRESTClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating antrea-agent API client: %w", err)
+	}
+	return &agentClient{restClient: restClient}, nil
+}
+
+func (c *agentClient) get(path string, into interface{}) error {
+	data, err := c.restClient.Get().AbsPath(path).DoRaw(context.TODO())
+	if err != nil {
+		return fmt.Errorf("error querying %s from antrea-agent: %w", path, err)
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("error decoding %s response of antrea-agent: %w", path, err)
+	}
+	return nil
+}
+
+func (c *agentClient) GetNodeConfig() (*nodeconfig.Response, error) {
+	resp := new(nodeconfig.Response)
+	if err := c.get("/nodeconfig", resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *agentClient) GetPodInterfaces() ([]podinterface.Response, error) {
+	var resp []podinterface.Response
+	if err := c.get("/podinterfaces", &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standalone
+
+import (
+	"net"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+)
+
+// InterfaceSyncer keeps an InterfaceStore in sync with the Pod interfaces reported by antrea-agent, so that the
+// connections of the local Pods can be attributed to them by the standalone flow exporter.
+type InterfaceSyncer struct {
+	client     AgentClient
+	ifaceStore interfacestore.InterfaceStore
+}
+
+func NewInterfaceSyncer(client AgentClient, ifaceStore interfacestore.InterfaceStore) *InterfaceSyncer {
+	return &InterfaceSyncer{client: client, ifaceStore: ifaceStore}
+}
+
+// Sync replaces the container interfaces of the InterfaceStore with the ones currently reported by antrea-agent.
+func (s *InterfaceSyncer) Sync() error {
+	pods, err := s.client.GetPodInterfaces()
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*interfacestore.InterfaceConfig, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		mac, _ := net.ParseMAC(pod.MAC)
+		config := interfacestore.NewContainerInterface(pod.InterfaceName, pod.ContainerID, pod.PodName, pod.PodNamespace, mac, net.ParseIP(pod.IP))
+		config.OVSPortConfig = &interfacestore.OVSPortConfig{PortUUID: pod.PortUUID, OFPort: pod.OFPort}
+		config.NetNS = pod.NetNS
+		current[pod.InterfaceName] = config
+	}
+	for _, config := range s.ifaceStore.GetInterfacesByType(interfacestore.ContainerInterface) {
+		if _, ok := current[config.InterfaceName]; !ok {
+			s.ifaceStore.DeleteInterface(config)
+		}
+	}
+	for _, config := range current {
+		s.ifaceStore.AddInterface(config)
+	}
+	return nil
+}
+
+// Run syncs the InterfaceStore every syncInterval until stopCh is closed.
+func (s *InterfaceSyncer) Run(syncInterval time.Duration, stopCh <-chan struct{}) {
+	wait.Until(func() {
+		if err := s.Sync(); err != nil {
+			klog.Errorf("Failed to sync Pod interfaces from antrea-agent: %v", err)
+		}
+	}, syncInterval, stopCh)
+}
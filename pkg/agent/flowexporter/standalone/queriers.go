@@ -0,0 +1,121 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standalone
+
+import (
+	"fmt"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter/connections"
+	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
+)
+
+var (
+	_ connections.NodeQuerier    = new(nodeQuerier)
+	_ connections.ServiceQuerier = new(serviceQuerier)
+)
+
+// nodeQuerier is the NodeQuerier of the standalone flow exporter. antrea-agent learns the PodCIDRs of the remote
+// Nodes from the Node resources, so the same resources are used to look up the Node of a Pod IP.
+type nodeQuerier struct {
+	nodeName   string
+	nodeLister corelisters.NodeLister
+}
+
+func NewNodeQuerier(nodeName string, nodeLister corelisters.NodeLister) *nodeQuerier {
+	return &nodeQuerier{nodeName: nodeName, nodeLister: nodeLister}
+}
+
+func (q *nodeQuerier) GetNodeNameByPodIP(podIP net.IP) (string, bool) {
+	nodes, err := q.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Nodes: %v", err)
+		return "", false
+	}
+	for _, node := range nodes {
+		if node.Name == q.nodeName || node.Spec.PodCIDR == "" {
+			continue
+		}
+		_, podCIDR, err := net.ParseCIDR(node.Spec.PodCIDR)
+		if err != nil {
+			continue
+		}
+		if podCIDR.Contains(podIP) {
+			return node.Name, true
+		}
+	}
+	return "", false
+}
+
+const serviceIPIndex = "serviceIP"
+
+// serviceIPIndexFunc indexes the Services by the keys of their ports, with the same format as the one used by
+// AntreaProxy: "<ClusterIP>:<port>/<protocol>".
+func serviceIPIndexFunc(obj interface{}) ([]string, error) {
+	service, ok := obj.(*corev1.Service)
+	if !ok || service.Spec.ClusterIP == "" || service.Spec.ClusterIP == corev1.ClusterIPNone {
+		return []string{}, nil
+	}
+	keys := make([]string, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		keys = append(keys, serviceKey(service.Spec.ClusterIP, port))
+	}
+	return keys, nil
+}
+
+func serviceKey(clusterIP string, port corev1.ServicePort) string {
+	return fmt.Sprintf("%s:%d/%s", clusterIP, port.Port, port.Protocol)
+}
+
+// serviceQuerier is the ServiceQuerier of the standalone flow exporter, which looks up the Services in the cache of
+// a Service informer instead of querying AntreaProxy.
+type serviceQuerier struct {
+	serviceIndexer cache.Indexer
+}
+
+// NewServiceQuerier creates a serviceQuerier using the cache of serviceInformer. It must be called before the
+// informer is started.
+func NewServiceQuerier(serviceInformer coreinformers.ServiceInformer) (*serviceQuerier, error) {
+	if err := serviceInformer.Informer().AddIndexers(cache.Indexers{serviceIPIndex: serviceIPIndexFunc}); err != nil {
+		return nil, err
+	}
+	return &serviceQuerier{serviceIndexer: serviceInformer.Informer().GetIndexer()}, nil
+}
+
+func (q *serviceQuerier) GetServiceByIP(serviceStr string) (k8sproxy.ServicePortName, bool) {
+	objs, err := q.serviceIndexer.ByIndex(serviceIPIndex, serviceStr)
+	if err != nil || len(objs) == 0 {
+		return k8sproxy.ServicePortName{}, false
+	}
+	service := objs[0].(*corev1.Service)
+	for _, port := range service.Spec.Ports {
+		if serviceKey(service.Spec.ClusterIP, port) == serviceStr {
+			return k8sproxy.ServicePortName{
+				NamespacedName: types.NamespacedName{Namespace: service.Namespace, Name: service.Name},
+				Port:           port.Name,
+				Protocol:       port.Protocol,
+			}, true
+		}
+	}
+	return k8sproxy.ServicePortName{}, false
+}
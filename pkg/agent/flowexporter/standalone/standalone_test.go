@@ -0,0 +1,134 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package standalone
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/nodeconfig"
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/podinterface"
+	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
+	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
+)
+
+type fakeAgentClient struct {
+	podInterfaces []podinterface.Response
+	err           error
+}
+
+func (c *fakeAgentClient) GetNodeConfig() (*nodeconfig.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeAgentClient) GetPodInterfaces() ([]podinterface.Response, error) {
+	return c.podInterfaces, c.err
+}
+
+func TestInterfaceSyncer(t *testing.T) {
+	ifaceStore := interfacestore.NewInterfaceStore()
+	client := &fakeAgentClient{
+		podInterfaces: []podinterface.Response{
+			{PodName: "pod1", PodNamespace: "ns1", InterfaceName: "pod1-abcd", IP: "10.10.0.2", MAC: "aa:bb:cc:dd:ee:01", OFPort: 3, ContainerID: "c1", NetNS: "/var/run/netns/c1"},
+			{PodName: "pod2", PodNamespace: "ns1", InterfaceName: "pod2-abcd", IP: "10.10.0.3", MAC: "aa:bb:cc:dd:ee:02", OFPort: 4, ContainerID: "c2"},
+		},
+	}
+	syncer := NewInterfaceSyncer(client, ifaceStore)
+	require.NoError(t, syncer.Sync())
+	assert.Equal(t, 2, ifaceStore.GetContainerInterfaceNum())
+	iface, found := ifaceStore.GetInterfaceByIP("10.10.0.2")
+	require.True(t, found)
+	assert.Equal(t, "pod1", iface.PodName)
+	assert.Equal(t, "ns1", iface.PodNamespace)
+	assert.Equal(t, int32(3), iface.OFPort)
+	assert.Equal(t, "/var/run/netns/c1", iface.NetNS)
+
+	// pod1 is deleted.
+	client.podInterfaces = client.podInterfaces[1:]
+	require.NoError(t, syncer.Sync())
+	assert.Equal(t, 1, ifaceStore.GetContainerInterfaceNum())
+	_, found = ifaceStore.GetInterfaceByIP("10.10.0.2")
+	assert.False(t, found)
+
+	// The store is left unchanged when antrea-agent cannot be queried.
+	client.err = errors.New("connection refused")
+	assert.Error(t, syncer.Sync())
+	assert.Equal(t, 1, ifaceStore.GetContainerInterfaceNum())
+}
+
+func TestNodeQuerier(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for name, podCIDR := range map[string]string{"node1": "10.10.0.0/24", "node2": "10.10.1.0/24", "node3": ""} {
+		indexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1.NodeSpec{PodCIDR: podCIDR}})
+	}
+	q := NewNodeQuerier("node1", corelisters.NewNodeLister(indexer))
+
+	nodeName, found := q.GetNodeNameByPodIP(net.ParseIP("10.10.1.5"))
+	assert.True(t, found)
+	assert.Equal(t, "node2", nodeName)
+	// The local Node is not a remote Node.
+	_, found = q.GetNodeNameByPodIP(net.ParseIP("10.10.0.5"))
+	assert.False(t, found)
+	_, found = q.GetNodeNameByPodIP(net.ParseIP("192.168.0.5"))
+	assert.False(t, found)
+}
+
+func TestServiceQuerier(t *testing.T) {
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	serviceInformer := informerFactory.Core().V1().Services()
+	q, err := NewServiceQuerier(serviceInformer)
+	require.NoError(t, err)
+	indexer := serviceInformer.Informer().GetIndexer()
+	indexer.Add(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "svc1"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.96.0.10",
+			Ports: []corev1.ServicePort{
+				{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+				{Name: "dns-tcp", Port: 53, Protocol: corev1.ProtocolTCP},
+			},
+		},
+	})
+	indexer.Add(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "headless"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Ports:     []corev1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+		},
+	})
+
+	svc, found := q.GetServiceByIP("10.96.0.10:53/TCP")
+	assert.True(t, found)
+	assert.Equal(t, k8sproxy.ServicePortName{
+		NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "svc1"},
+		Port:           "dns-tcp",
+		Protocol:       corev1.ProtocolTCP,
+	}, svc)
+	_, found = q.GetServiceByIP("10.96.0.10:80/TCP")
+	assert.False(t, found)
+	_, found = q.GetServiceByIP("None:80/TCP")
+	assert.False(t, found)
+}
@@ -21,6 +21,19 @@ import (
 
 type ConnectionKey [5]string
 
+// Values of the flowEndReason IPFIX Information Element, as defined in RFC 5102.
+const (
+	IdleTimeoutReason   uint8 = 0x01
+	ActiveTimeoutReason uint8 = 0x02
+	EndOfFlowReason     uint8 = 0x03
+)
+
+// Values of the flowDirection IPFIX Information Element, as defined in RFC 5102.
+const (
+	FlowDirectionIngress uint8 = 0x00
+	FlowDirectionEgress  uint8 = 0x01
+)
+
 type ConnectionMapCallBack func(key ConnectionKey, conn Connection) error
 type FlowRecordCallBack func(key ConnectionKey, record FlowRecord) error
 
@@ -32,6 +45,15 @@ type Tuple struct {
 	DestinationPort    uint16
 }
 
+// TCPSocketKey identifies a TCP socket by its local and remote addresses, as seen in the network namespace of the
+// socket.
+type TCPSocketKey struct {
+	LocalAddress  string
+	LocalPort     uint16
+	RemoteAddress string
+	RemotePort    uint16
+}
+
 type Connection struct {
 	// Fields from conntrack flows
 	ID        uint32
@@ -43,9 +65,22 @@ type Connection struct {
 	// IsActive flag helps in cleaning up connections when they are not in conntrack any module more.
 	IsActive bool
 	// DoExport flag helps in tagging connections that can be exported by Flow Exporter
-	DoExport   bool
+	DoExport bool
+	// IsDenied flag indicates that the connection was denied by a NetworkPolicy. Such connections are not present in
+	// conntrack and are built from the packets dropped by the NetworkPolicy rules.
+	IsDenied bool
+	// IsHairpin flag indicates that the connection is from a Pod to a Service whose selected Endpoint is the Pod
+	// itself. The source and destination Pods of such connections are the same.
+	IsHairpin  bool
 	Zone       uint16
+	Mark       uint32
 	StatusFlag uint32
+	// TCPState is the state of TCP connections in conntrack, e.g. "ESTABLISHED" or "TIME_WAIT". It is empty for
+	// other protocols.
+	TCPState string
+	// TCPRTT is the smoothed RTT measured by the TCP stack of the local Pod for TCP connections. It is zero when
+	// unknown.
+	TCPRTT time.Duration
 	// TODO: Have a separate field for protocol. No need to keep it in Tuple.
 	TupleOrig, TupleReply          Tuple
 	OriginalPackets, OriginalBytes uint64
@@ -55,7 +90,16 @@ type Connection struct {
 	SourcePodName              string
 	DestinationPodNamespace    string
 	DestinationPodName         string
+	SourceNodeName             string
+	DestinationNodeName        string
 	DestinationServicePortName string
+	// EgressName, EgressIP and EgressNodeName are the egress configuration used by the connections from a local Pod
+	// which leave the cluster: the name of the Egress, the IP the connection is SNATed to and the Node where SNAT is
+	// done. EgressName is empty when the connection is SNATed by the default SNAT of the Node. They are not set for
+	// the other connections.
+	EgressName     string
+	EgressIP       net.IP
+	EgressNodeName string
 }
 
 type FlowRecord struct {
@@ -64,4 +108,25 @@ type FlowRecord struct {
 	PrevBytes          uint64
 	PrevReversePackets uint64
 	PrevReverseBytes   uint64
+	// The stats of the connection since the record was last exported, which are computed when the record is due
+	// for export.
+	DeltaPackets        uint64
+	DeltaBytes          uint64
+	DeltaReversePackets uint64
+	DeltaReverseBytes   uint64
+	// Throughput and ReverseThroughput are the average throughputs in bits per second since the record was last
+	// exported, derived from DeltaBytes and DeltaReverseBytes.
+	Throughput        uint64
+	ReverseThroughput uint64
+	// LastExportTime is the time when the record was last exported. It is initialized to the time when the record
+	// is created, so that the active timeout of a new record starts from its creation.
+	LastExportTime time.Time
+	// LastActiveTime is the last time when the stats of the connection were found to be updated.
+	LastActiveTime time.Time
+	// FlowEndReason is the reason why the record is exported, as reported by the flowEndReason IPFIX IE.
+	FlowEndReason uint8
+	// Restarted is true until the record is first exported after the restart of antrea-agent, for the connections
+	// which existed before the restart. Their delta counts are computed from the state of the record saved before the
+	// restart, if any.
+	Restarted bool
 }
@@ -15,7 +15,17 @@
 package flowexporter
 
 import (
+	"encoding/binary"
+	"hash/fnv"
 	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// closingTCPStates are the TCP states indicating that the connection has been closed by one of the endpoints, both
+// with the state names of the Linux conntrack module and the ones of the OVS userspace conntrack.
+var closingTCPStates = sets.NewString(
+	"FIN_WAIT", "FIN_WAIT_1", "FIN_WAIT_2", "CLOSING", "CLOSE_WAIT", "LAST_ACK", "TIME_WAIT", "CLOSE", "CLOSED",
 )
 
 // NewConnectionKey creates 5-tuple of flow as connection key
@@ -27,3 +37,35 @@ func NewConnectionKey(conn *Connection) ConnectionKey {
 		strconv.FormatUint(uint64(conn.TupleOrig.Protocol), 10),
 	}
 }
+
+// IsConnectionClosing returns true if the TCP connection has been closed by one of the endpoints, i.e., it ended
+// normally rather than timing out.
+func IsConnectionClosing(conn *Connection) bool {
+	return closingTCPStates.Has(conn.TCPState)
+}
+
+// GetFlowKeyHash returns a hash of the 5-tuple of the connection as seen by the destination endpoint, i.e. after any
+// DNAT or SNAT done on the source Node. It is computed from the reply tuple, which is the same in the conntrack
+// tables of the source and the destination Nodes of an inter-Node connection, so that the flow records exported by
+// both Nodes can be correlated.
+func GetFlowKeyHash(conn *Connection) uint64 {
+	h := fnv.New64a()
+	port := make([]byte, 2)
+	h.Write(conn.TupleReply.SourceAddress.To16())
+	binary.BigEndian.PutUint16(port, conn.TupleReply.SourcePort)
+	h.Write(port)
+	h.Write(conn.TupleReply.DestinationAddress.To16())
+	binary.BigEndian.PutUint16(port, conn.TupleReply.DestinationPort)
+	h.Write(port)
+	h.Write([]byte{conn.TupleOrig.Protocol})
+	return h.Sum64()
+}
+
+// GetFlowDirection returns the direction of the connection from the point of view of the Node exporting it:
+// FlowDirectionIngress if the connection is from a remote Pod to a local Pod, FlowDirectionEgress otherwise.
+func GetFlowDirection(conn *Connection) uint8 {
+	if conn.SourcePodName == "" && conn.DestinationPodName != "" {
+		return FlowDirectionIngress
+	}
+	return FlowDirectionEgress
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowexporter
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFlowKeyHash(t *testing.T) {
+	clientIP := net.ParseIP("10.10.0.2")
+	serverIP := net.ParseIP("10.10.1.2")
+	clusterIP := net.ParseIP("10.96.0.10")
+	// Pod-to-Service connection as seen on the source Node, where the ClusterIP is translated to the Endpoint IP.
+	sourceNodeConn := &Connection{
+		TupleOrig:     Tuple{SourceAddress: clientIP, DestinationAddress: clusterIP, Protocol: 6, SourcePort: 40000, DestinationPort: 80},
+		TupleReply:    Tuple{SourceAddress: serverIP, DestinationAddress: clientIP, Protocol: 6, SourcePort: 8080, DestinationPort: 40000},
+		SourcePodName: "client",
+	}
+	// The same connection as seen on the destination Node.
+	destinationNodeConn := &Connection{
+		TupleOrig:          Tuple{SourceAddress: clientIP, DestinationAddress: serverIP, Protocol: 6, SourcePort: 40000, DestinationPort: 8080},
+		TupleReply:         Tuple{SourceAddress: serverIP, DestinationAddress: clientIP, Protocol: 6, SourcePort: 8080, DestinationPort: 40000},
+		DestinationPodName: "server",
+	}
+	// Another connection from the same client Pod.
+	otherConn := &Connection{
+		TupleOrig:     Tuple{SourceAddress: clientIP, DestinationAddress: clusterIP, Protocol: 6, SourcePort: 40001, DestinationPort: 80},
+		TupleReply:    Tuple{SourceAddress: serverIP, DestinationAddress: clientIP, Protocol: 6, SourcePort: 8080, DestinationPort: 40001},
+		SourcePodName: "client",
+	}
+
+	assert.Equal(t, GetFlowKeyHash(sourceNodeConn), GetFlowKeyHash(destinationNodeConn))
+	assert.NotEqual(t, GetFlowKeyHash(sourceNodeConn), GetFlowKeyHash(otherConn))
+	assert.Equal(t, FlowDirectionEgress, GetFlowDirection(sourceNodeConn))
+	assert.Equal(t, FlowDirectionIngress, GetFlowDirection(destinationNodeConn))
+}
@@ -47,6 +47,12 @@ type ContainerInterfaceConfig struct {
 	ContainerID  string
 	PodName      string
 	PodNamespace string
+	// PCI address of the SR-IOV VF used as the Pod's primary interface, in which case the
+	// interface attached to OVS is the VF representor. Empty if a veth pair is used.
+	SriovVFDeviceID string
+	// Path of the network namespace of the Pod, as provided by the container runtime. It's used to query the
+	// state of the sockets of the Pod.
+	NetNS string
 }
 
 type TunnelInterfaceConfig struct {
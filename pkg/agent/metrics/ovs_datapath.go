@@ -0,0 +1,265 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
+)
+
+// ovsDatapathStatsInterval is the interval at which the OVS datapath stats
+// are collected.
+const ovsDatapathStatsInterval = 30 * time.Second
+
+// datapathStats holds the stats of one OVS datapath, as reported by
+// "ovs-appctl dpctl/show" and "ovs-appctl upcall/show".
+type datapathStats struct {
+	lookupHit    uint64
+	lookupMissed uint64
+	lookupLost   uint64
+	flows        uint64
+	// The fields below are reported by upcall/show.
+	flowLimit    uint64
+	dumpDuration uint64
+}
+
+// OVSDatapathStatsCollector periodically collects the stats of the OVS
+// datapaths and exposes them as Prometheus metrics. The lookup counters of the
+// datapath are cumulative, so the previous values are kept to compute the
+// deltas and the upcall rate.
+type OVSDatapathStatsCollector struct {
+	ovsCtlClient ovsctl.OVSCtlClient
+	clock        clock.Clock
+	lastCollect  time.Time
+	lastStats    map[string]*datapathStats
+}
+
+func NewOVSDatapathStatsCollector(ovsCtlClient ovsctl.OVSCtlClient) *OVSDatapathStatsCollector {
+	return &OVSDatapathStatsCollector{
+		ovsCtlClient: ovsCtlClient,
+		clock:        clock.RealClock{},
+		lastStats:    make(map[string]*datapathStats),
+	}
+}
+
+func (c *OVSDatapathStatsCollector) Run(stopCh <-chan struct{}) {
+	klog.Info("Starting OVS datapath stats collector")
+	wait.Until(func() {
+		if err := c.collect(); err != nil {
+			klog.Errorf("Failed to collect OVS datapath stats: %v", err)
+		}
+	}, ovsDatapathStatsInterval, stopCh)
+}
+
+func (c *OVSDatapathStatsCollector) collect() error {
+	out, execErr := c.ovsCtlClient.RunAppctlCmd("dpctl/show", false)
+	if execErr != nil {
+		return fmt.Errorf("error when running dpctl/show: %v", execErr)
+	}
+	stats, err := parseDpctlShow(string(out))
+	if err != nil {
+		return err
+	}
+	// upcall/show is only used for the flow limit and dump duration, so
+	// failing to get them should not prevent the datapath stats from being
+	// reported.
+	if out, execErr := c.ovsCtlClient.RunAppctlCmd("upcall/show", false); execErr != nil {
+		klog.Warningf("Failed to run upcall/show: %v", execErr)
+	} else if err := parseUpcallShow(string(out), stats); err != nil {
+		klog.Warningf("Failed to parse output of upcall/show: %v", err)
+	}
+	c.update(stats)
+	return nil
+}
+
+// counterDelta returns the increase of a cumulative datapath counter. The
+// counters are reset when the datapath is re-created, in which case the
+// current value is returned.
+func counterDelta(current, last uint64) uint64 {
+	if current < last {
+		return current
+	}
+	return current - last
+}
+
+func (c *OVSDatapathStatsCollector) update(stats map[string]*datapathStats) {
+	now := c.clock.Now()
+	elapsed := now.Sub(c.lastCollect).Seconds()
+	for dp, s := range stats {
+		OVSDatapathFlowCount.WithLabelValues(dp).Set(float64(s.flows))
+		OVSDatapathFlowLimit.WithLabelValues(dp).Set(float64(s.flowLimit))
+		OVSDatapathFlowDumpDuration.WithLabelValues(dp).Set(float64(s.dumpDuration))
+		last, exists := c.lastStats[dp]
+		if !exists {
+			// The counters are initialized with the cumulative values of the
+			// datapath the first time it is seen.
+			last = &datapathStats{}
+		}
+		OVSDatapathLookupCount.WithLabelValues(dp, "hit").Add(float64(counterDelta(s.lookupHit, last.lookupHit)))
+		OVSDatapathLookupCount.WithLabelValues(dp, "missed").Add(float64(counterDelta(s.lookupMissed, last.lookupMissed)))
+		OVSDatapathLookupCount.WithLabelValues(dp, "lost").Add(float64(counterDelta(s.lookupLost, last.lookupLost)))
+		// Every missed lookup results in an upcall to ovs-vswitchd.
+		if exists && elapsed > 0 {
+			OVSDatapathUpcallRate.WithLabelValues(dp).Set(float64(counterDelta(s.lookupMissed, last.lookupMissed)) / elapsed)
+		}
+	}
+	for dp := range c.lastStats {
+		if _, exists := stats[dp]; !exists {
+			deleteDatapathMetrics(dp)
+		}
+	}
+	c.lastStats = stats
+	c.lastCollect = now
+}
+
+func deleteDatapathMetrics(dp string) {
+	OVSDatapathFlowCount.DeleteLabelValues(dp)
+	OVSDatapathFlowLimit.DeleteLabelValues(dp)
+	OVSDatapathFlowDumpDuration.DeleteLabelValues(dp)
+	OVSDatapathUpcallRate.DeleteLabelValues(dp)
+	for _, result := range []string{"hit", "missed", "lost"} {
+		OVSDatapathLookupCount.DeleteLabelValues(dp, result)
+	}
+}
+
+// parseDatapathName returns the datapath name if the line is a datapath header,
+// e.g. "system@ovs-system:".
+func parseDatapathName(line string) (string, bool) {
+	if len(line) == 0 || line[0] == ' ' || line[0] == '\t' || !strings.HasSuffix(line, ":") {
+		return "", false
+	}
+	return strings.TrimSuffix(line, ":"), true
+}
+
+// parseKeyValues parses space-separated "key:value" pairs, e.g.
+// "hit:4166 missed:85 lost:0".
+func parseKeyValues(s string) (map[string]uint64, error) {
+	values := make(map[string]uint64)
+	for _, field := range strings.Fields(s) {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+		// Some values, like the masks hit/pkt, are not integers and are not
+		// needed.
+		if v, err := strconv.ParseUint(kv[1], 10, 64); err == nil {
+			values[kv[0]] = v
+		}
+	}
+	return values, nil
+}
+
+// parseDpctlShow parses the output of "ovs-appctl dpctl/show", which looks like:
+//
+//	system@ovs-system:
+//	  lookups: hit:4166 missed:85 lost:0
+//	  flows: 5
+//	  masks: hit:4823 total:3 hit/pkt:1.13
+//	  port 0: ovs-system (internal)
+//
+// It returns the stats indexed by datapath name.
+func parseDpctlShow(out string) (map[string]*datapathStats, error) {
+	stats := make(map[string]*datapathStats)
+	var current *datapathStats
+	for _, line := range strings.Split(strings.ReplaceAll(out, "\r", ""), "\n") {
+		if dp, ok := parseDatapathName(line); ok {
+			current = &datapathStats{}
+			stats[dp] = current
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "lookups":
+			values, err := parseKeyValues(kv[1])
+			if err != nil {
+				return nil, fmt.Errorf("error when parsing lookups %q: %v", line, err)
+			}
+			current.lookupHit = values["hit"]
+			current.lookupMissed = values["missed"]
+			current.lookupLost = values["lost"]
+		case "flows":
+			flows, err := strconv.ParseUint(strings.TrimSpace(kv[1]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error when parsing flows %q: %v", line, err)
+			}
+			current.flows = flows
+		}
+	}
+	if len(stats) == 0 {
+		return nil, fmt.Errorf("no datapath found in output of dpctl/show")
+	}
+	return stats, nil
+}
+
+// parseUpcallShow parses the output of "ovs-appctl upcall/show", which looks
+// like:
+//
+//	system@ovs-system:
+//	  flows         : (current 5) (avg 5) (max 23) (limit 200000)
+//	  dump duration : 1ms
+//
+// and sets the flow limit and dump duration of the corresponding datapaths in
+// stats. Datapaths not reported by dpctl/show are ignored.
+func parseUpcallShow(out string, stats map[string]*datapathStats) error {
+	var current *datapathStats
+	for _, line := range strings.Split(strings.ReplaceAll(out, "\r", ""), "\n") {
+		if dp, ok := parseDatapathName(line); ok {
+			current = stats[dp]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.TrimSpace(kv[0]) {
+		case "flows":
+			fields := strings.Fields(strings.NewReplacer("(", "", ")", "").Replace(kv[1]))
+			for i := 0; i+1 < len(fields); i += 2 {
+				if fields[i] != "limit" {
+					continue
+				}
+				limit, err := strconv.ParseUint(fields[i+1], 10, 64)
+				if err != nil {
+					return fmt.Errorf("error when parsing flow limit %q: %v", line, err)
+				}
+				current.flowLimit = limit
+			}
+		case "dump duration":
+			duration, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(kv[1]), "ms"), 10, 64)
+			if err != nil {
+				return fmt.Errorf("error when parsing dump duration %q: %v", line, err)
+			}
+			current.dumpDuration = duration
+		}
+	}
+	return nil
+}
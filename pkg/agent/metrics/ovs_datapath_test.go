@@ -0,0 +1,116 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+
+	ovsctltest "github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl/testing"
+)
+
+const (
+	dpctlShowOutput = `system@ovs-system:
+  lookups: hit:4166 missed:85 lost:2
+  flows: 5
+  masks: hit:4823 total:3 hit/pkt:1.13
+  port 0: ovs-system (internal)
+  port 1: br-int (internal)
+  port 2: antrea-gw0 (internal)
+`
+	upcallShowOutput = `system@ovs-system:
+  flows         : (current 5) (avg 5) (max 23) (limit 200000)
+  dump duration : 3ms
+  ufid enabled : true
+
+  14: (keys 5)
+  15: (keys 2)
+`
+)
+
+func TestParseDpctlShow(t *testing.T) {
+	stats, err := parseDpctlShow(dpctlShowOutput)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*datapathStats{
+		"system@ovs-system": {lookupHit: 4166, lookupMissed: 85, lookupLost: 2, flows: 5},
+	}, stats)
+
+	_, err = parseDpctlShow("")
+	assert.Error(t, err)
+	_, err = parseDpctlShow("system@ovs-system:\n  flows: abc\n")
+	assert.Error(t, err)
+}
+
+func TestParseUpcallShow(t *testing.T) {
+	stats := map[string]*datapathStats{"system@ovs-system": {}}
+	require.NoError(t, parseUpcallShow(upcallShowOutput, stats))
+	assert.Equal(t, uint64(200000), stats["system@ovs-system"].flowLimit)
+	assert.Equal(t, uint64(3), stats["system@ovs-system"].dumpDuration)
+
+	// Datapaths which are not reported by dpctl/show are ignored.
+	stats = map[string]*datapathStats{}
+	require.NoError(t, parseUpcallShow(upcallShowOutput, stats))
+	assert.Empty(t, stats)
+}
+
+func TestOVSDatapathStatsCollector(t *testing.T) {
+	InitializeOVSMetrics()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOVSCtlClient := ovsctltest.NewMockOVSCtlClient(ctrl)
+	fakeClock := clock.NewFakeClock(time.Now())
+	c := NewOVSDatapathStatsCollector(mockOVSCtlClient)
+	c.clock = fakeClock
+
+	mockOVSCtlClient.EXPECT().RunAppctlCmd("dpctl/show", false).Return([]byte(dpctlShowOutput), nil)
+	mockOVSCtlClient.EXPECT().RunAppctlCmd("upcall/show", false).Return([]byte(upcallShowOutput), nil)
+	require.NoError(t, c.collect())
+
+	// 100 additional upcalls in 10 seconds.
+	fakeClock.Step(10 * time.Second)
+	mockOVSCtlClient.EXPECT().RunAppctlCmd("dpctl/show", false).Return([]byte(strings.Replace(dpctlShowOutput, "missed:85", "missed:185", 1)), nil)
+	mockOVSCtlClient.EXPECT().RunAppctlCmd("upcall/show", false).Return([]byte(upcallShowOutput), nil)
+	require.NoError(t, c.collect())
+
+	expected := `
+	# HELP antrea_agent_ovs_datapath_flow_count [ALPHA] Number of flows (megaflows) cached in the OVS datapath. The datapath name is used as a label.
+	# TYPE antrea_agent_ovs_datapath_flow_count gauge
+	antrea_agent_ovs_datapath_flow_count{datapath="system@ovs-system"} 5
+	# HELP antrea_agent_ovs_datapath_flow_limit [ALPHA] Maximum number of flows which can be cached in the OVS datapath, as dynamically adjusted by ovs-vswitchd. The datapath name is used as a label.
+	# TYPE antrea_agent_ovs_datapath_flow_limit gauge
+	antrea_agent_ovs_datapath_flow_limit{datapath="system@ovs-system"} 200000
+	# HELP antrea_agent_ovs_datapath_lookup_count [ALPHA] Number of packets looked up in the OVS datapath flow cache, partitioned by datapath and result (hit, missed and lost). Missed packets are sent to ovs-vswitchd as upcalls, lost packets were dropped before reaching ovs-vswitchd.
+	# TYPE antrea_agent_ovs_datapath_lookup_count counter
+	antrea_agent_ovs_datapath_lookup_count{datapath="system@ovs-system",result="hit"} 4166
+	antrea_agent_ovs_datapath_lookup_count{datapath="system@ovs-system",result="lost"} 2
+	antrea_agent_ovs_datapath_lookup_count{datapath="system@ovs-system",result="missed"} 185
+	# HELP antrea_agent_ovs_datapath_upcall_rate [ALPHA] Number of upcalls per second from the OVS datapath to ovs-vswitchd, computed over the last collection interval. The datapath name is used as a label.
+	# TYPE antrea_agent_ovs_datapath_upcall_rate gauge
+	antrea_agent_ovs_datapath_upcall_rate{datapath="system@ovs-system"} 10
+	`
+	assert.NoError(t, testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"antrea_agent_ovs_datapath_flow_count",
+		"antrea_agent_ovs_datapath_flow_limit",
+		"antrea_agent_ovs_datapath_lookup_count",
+		"antrea_agent_ovs_datapath_upcall_rate"))
+}
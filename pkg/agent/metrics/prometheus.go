@@ -95,6 +95,45 @@ var (
 		[]string{"operation"},
 	)
 
+	OVSDatapathFlowCount = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_ovs_datapath_flow_count",
+		Help:           "Number of flows (megaflows) cached in the OVS datapath. The datapath name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"datapath"})
+
+	OVSDatapathFlowLimit = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_ovs_datapath_flow_limit",
+		Help:           "Maximum number of flows which can be cached in the OVS datapath, as dynamically adjusted by ovs-vswitchd. The datapath name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"datapath"})
+
+	OVSDatapathFlowDumpDuration = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_ovs_datapath_flow_dump_duration_milliseconds",
+		Help:           "Duration of the last dump of the OVS datapath flows by the ovs-vswitchd revalidators. The datapath name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"datapath"})
+
+	OVSDatapathLookupCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Name:           "antrea_agent_ovs_datapath_lookup_count",
+			Help:           "Number of packets looked up in the OVS datapath flow cache, partitioned by datapath and result (hit, missed and lost). Missed packets are sent to ovs-vswitchd as upcalls, lost packets were dropped before reaching ovs-vswitchd.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"datapath", "result"},
+	)
+
+	OVSDatapathUpcallRate = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_ovs_datapath_upcall_rate",
+		Help:           "Number of upcalls per second from the OVS datapath to ovs-vswitchd, computed over the last collection interval. The datapath name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"datapath"})
+
+	OVSFeatureSupported = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_ovs_feature_supported",
+		Help:           "Whether an optional OVS feature is supported by the Node (1) or not (0), as detected when the Agent starts. The feature name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"feature"})
+
 	TotalConnectionsInConnTrackTable = metrics.NewGauge(
 		&metrics.GaugeOpts{
 			Name:           "antrea_agent_conntrack_total_connection_count",
@@ -118,6 +157,102 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		},
 	)
+
+	ConntrackPollDuration = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "antrea_agent_conntrack_poll_duration_seconds",
+			Help:           "Duration of the last poll of the conntrack table by the Flow Exporter.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	ConntrackPollInterval = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "antrea_agent_conntrack_poll_interval_seconds",
+			Help:           "Current interval at which the conntrack table is polled by the Flow Exporter, which is increased from flowPollInterval when polls exceed flowPollCPUBudget.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	ConntrackPollSamplingRate = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "antrea_agent_conntrack_poll_sampling_rate",
+			Help:           "Current sampling rate N of the conntrack polls, when 1 out of every N new connections is added to the Flow Exporter because polls exceed flowPollCPUBudget at the maximum poll interval. 1 means that all connections are added.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	ConntrackPollAdjustmentCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Name:           "antrea_agent_conntrack_poll_adjustment_count",
+			Help:           "Number of adjustments of the conntrack polling of the Flow Exporter to stay within flowPollCPUBudget. The adjustment (interval_increase, interval_decrease, sampling_increase or sampling_decrease) is used as a label.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"adjustment"},
+	)
+
+	FlowArchiveChainResetCount = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Name:           "antrea_agent_flow_archive_chain_reset_count",
+			Help:           "Number of times the Flow Exporter started a new hash chain in the flow archive because the last archive file could not be continued.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	ConntrackUsageRatio = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "antrea_agent_conntrack_usage_ratio",
+			Help:           "Ratio of the number of connections in the conntrack table to its size (nf_conntrack_count / nf_conntrack_max).",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	OVSGroupCount = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Name:           "antrea_agent_ovs_group_count",
+			Help:           "Number of OVS groups.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	NodeCapacityWarning = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_node_capacity_warning",
+		Help:           "Whether the usage of a datapath resource of the Node is above its configured warning threshold (1) or not (0). The resource (conntrack, ovs_flows or ovs_groups) is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"resource"})
+
+	EgressSelectedPodCount = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_egress_selected_pod_count",
+		Help:           "Number of Pods whose traffic is SNAT'd with the IP of an Egress held by the Node. The Egress name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"egress"})
+
+	EgressSNATConnectionCount = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_egress_snat_connection_count",
+		Help:           "Number of connections SNAT'd with the IP of an Egress held by the Node. The Egress name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"egress"})
+
+	EgressSNATPortBlockCount = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_egress_snat_port_block_count",
+		Help:           "Number of blocks of source ports allocated to the Pods SNAT'd with the IP of an Egress held by the Node, when egressSNATPortsPerPod is set. The Egress name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"egress"})
+
+	EgressSNATPortBlockExhaustedPodCount = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "antrea_agent_egress_snat_port_block_exhausted_pod_count",
+		Help:           "Number of Pods SNAT'd with the IP of an Egress held by the Node which were not allocated a block of source ports, as all the blocks of the Egress IP are allocated, when egressSNATPortsPerPod is set. The Egress name is used as a label.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"egress"})
+
+	EgressFailoverCount = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Name:           "antrea_agent_egress_failover_count",
+			Help:           "Number of times the Node took over the IP of an Egress from another Node. The Egress name is used as a label.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"egress"},
+	)
 )
 
 func InitializePrometheusMetrics() {
@@ -145,6 +280,8 @@ func InitializePrometheusMetrics() {
 	InitializeNetworkPolicyMetrics()
 	InitializeOVSMetrics()
 	InitializeConnectionMetrics()
+	InitializeNodeCapacityMetrics()
+	InitializeEgressMetrics()
 }
 
 func InitializePodMetrics() {
@@ -184,6 +321,24 @@ func InitializeOVSMetrics() {
 	if err := legacyregistry.Register(OVSFlowOpsLatency); err != nil {
 		klog.Error("Failed to register antrea_agent_ovs_flow_ops_latency_milliseconds with Prometheus")
 	}
+	if err := legacyregistry.Register(OVSDatapathFlowCount); err != nil {
+		klog.Error("Failed to register antrea_agent_ovs_datapath_flow_count with Prometheus")
+	}
+	if err := legacyregistry.Register(OVSDatapathFlowLimit); err != nil {
+		klog.Error("Failed to register antrea_agent_ovs_datapath_flow_limit with Prometheus")
+	}
+	if err := legacyregistry.Register(OVSDatapathFlowDumpDuration); err != nil {
+		klog.Error("Failed to register antrea_agent_ovs_datapath_flow_dump_duration_milliseconds with Prometheus")
+	}
+	if err := legacyregistry.Register(OVSDatapathLookupCount); err != nil {
+		klog.Error("Failed to register antrea_agent_ovs_datapath_lookup_count with Prometheus")
+	}
+	if err := legacyregistry.Register(OVSDatapathUpcallRate); err != nil {
+		klog.Error("Failed to register antrea_agent_ovs_datapath_upcall_rate with Prometheus")
+	}
+	if err := legacyregistry.Register(OVSFeatureSupported); err != nil {
+		klog.Error("Failed to register antrea_agent_ovs_feature_supported with Prometheus")
+	}
 	// Initialize OpenFlow operations metrics with label add, modify and delete
 	// since those metrics won't come out until observation.
 	opsArray := [3]string{"add", "modify", "delete"}
@@ -204,4 +359,49 @@ func InitializeConnectionMetrics() {
 	if err := legacyregistry.Register(MaxConnectionsInConnTrackTable); err != nil {
 		klog.Errorf("Failed to register antrea_agent_conntrack_max_connection_count with error: %v", err)
 	}
+	if err := legacyregistry.Register(ConntrackPollDuration); err != nil {
+		klog.Errorf("Failed to register antrea_agent_conntrack_poll_duration_seconds with error: %v", err)
+	}
+	if err := legacyregistry.Register(ConntrackPollInterval); err != nil {
+		klog.Errorf("Failed to register antrea_agent_conntrack_poll_interval_seconds with error: %v", err)
+	}
+	if err := legacyregistry.Register(ConntrackPollSamplingRate); err != nil {
+		klog.Errorf("Failed to register antrea_agent_conntrack_poll_sampling_rate with error: %v", err)
+	}
+	if err := legacyregistry.Register(ConntrackPollAdjustmentCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_conntrack_poll_adjustment_count with error: %v", err)
+	}
+	if err := legacyregistry.Register(FlowArchiveChainResetCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_flow_archive_chain_reset_count with error: %v", err)
+	}
+}
+
+func InitializeNodeCapacityMetrics() {
+	if err := legacyregistry.Register(ConntrackUsageRatio); err != nil {
+		klog.Errorf("Failed to register antrea_agent_conntrack_usage_ratio with error: %v", err)
+	}
+	if err := legacyregistry.Register(OVSGroupCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_ovs_group_count with error: %v", err)
+	}
+	if err := legacyregistry.Register(NodeCapacityWarning); err != nil {
+		klog.Errorf("Failed to register antrea_agent_node_capacity_warning with error: %v", err)
+	}
+}
+
+func InitializeEgressMetrics() {
+	if err := legacyregistry.Register(EgressSelectedPodCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_egress_selected_pod_count with error: %v", err)
+	}
+	if err := legacyregistry.Register(EgressSNATConnectionCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_egress_snat_connection_count with error: %v", err)
+	}
+	if err := legacyregistry.Register(EgressSNATPortBlockCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_egress_snat_port_block_count with error: %v", err)
+	}
+	if err := legacyregistry.Register(EgressSNATPortBlockExhaustedPodCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_egress_snat_port_block_exhausted_pod_count with error: %v", err)
+	}
+	if err := legacyregistry.Register(EgressFailoverCount); err != nil {
+		klog.Errorf("Failed to register antrea_agent_egress_failover_count with error: %v", err)
+	}
 }
@@ -19,6 +19,7 @@ import (
 	"math/rand"
 	"net"
 
+	"github.com/contiv/libOpenflow/openflow13"
 	"github.com/contiv/ofnet/ofctrl"
 	"k8s.io/klog"
 
@@ -63,7 +64,8 @@ type Client interface {
 	// InstallNodeFlows should be invoked when a connection to a remote Node is going to be set
 	// up. The hostname is used to identify the added flows. When IPSec tunnel is enabled,
 	// ipsecTunOFPort must be set to the OFPort number of the IPSec tunnel port to the remote Node;
-	// otherwise ipsecTunOFPort must be set to 0.
+	// otherwise ipsecTunOFPort must be set to 0. peerEncapMode is the traffic encapsulation mode
+	// to the remote Node, which can differ from the configured mode in a hybrid topology.
 	// InstallNodeFlows has all-or-nothing semantics(call succeeds if all the flows are installed
 	// successfully, otherwise no flows will be installed). Calls to InstallNodeFlows are idempotent.
 	// Concurrent calls to InstallNodeFlows and / or UninstallNodeFlows are supported as long as they
@@ -73,7 +75,8 @@ type Client interface {
 		localGatewayMAC net.HardwareAddr,
 		peerPodCIDR net.IPNet,
 		peerGatewayIP, tunnelPeerIP net.IP,
-		tunOFPort, ipsecTunOFPort uint32) error
+		tunOFPort, ipsecTunOFPort uint32,
+		peerEncapMode config.TrafficEncapModeType) error
 
 	// UninstallNodeFlows removes the connection to the remote Node specified with the
 	// hostname. UninstallNodeFlows will do nothing if no connection to the host was established.
@@ -103,8 +106,13 @@ type Client interface {
 	// L2 forwarding should also be installed.
 	InstallEndpointFlows(protocol binding.Protocol, endpoints []proxy.Endpoint) error
 	// UninstallEndpointFlows removes flows of the Endpoint installed by
-	// InstallEndpointFlows.
+	// InstallEndpointFlows, including the ones kept by DrainEndpointFlows.
 	UninstallEndpointFlows(protocol binding.Protocol, endpoint proxy.Endpoint) error
+	// DrainEndpointFlows removes the flow which DNATs the new connections to
+	// the Endpoint, and keeps the flows required by its established
+	// connections, e.g. the hairpin flow, until UninstallEndpointFlows is
+	// called or the Endpoint is installed again.
+	DrainEndpointFlows(protocol binding.Protocol, endpoint proxy.Endpoint) error
 
 	// InstallServiceFlows installs flows for accessing Service with clusterIP.
 	// It installs the flow that uses the group/bucket to do service LB. If the
@@ -120,6 +128,20 @@ type Client interface {
 	// kube-proxy will handle the traffic.
 	// This function is only used for Windows platform.
 	InstallLoadBalancerServiceFromOutsideFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
+	// InstallLoadBalancerServiceDSRFlows installs flows for LoadBalancer Service traffic from outside the cluster in
+	// DSR mode. The traffic received from the gateway is forwarded to the Node of the selected Endpoint without DNAT
+	// if the Endpoint is remote, and the traffic forwarded this way by other Nodes is load-balanced to the local
+	// Endpoints with the group localGroupID, so that the replies are sent to the clients directly.
+	InstallLoadBalancerServiceDSRFlows(localGroupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
+	// UninstallLoadBalancerServiceDSRFlows removes flows installed by InstallLoadBalancerServiceDSRFlows.
+	UninstallLoadBalancerServiceDSRFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
+	// InstallLoadBalancerServiceLocalFlows installs flows for the traffic from outside the cluster to the LoadBalancer
+	// IP of a Service with the Local external traffic policy, which is routed to the gateway without DNAT in the host
+	// network. The traffic received from the gateway is load-balanced to the local Endpoints with the group
+	// localGroupID, so that the client IP is preserved.
+	InstallLoadBalancerServiceLocalFlows(localGroupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
+	// UninstallLoadBalancerServiceLocalFlows removes flows installed by InstallLoadBalancerServiceLocalFlows.
+	UninstallLoadBalancerServiceLocalFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error
 
 	// GetFlowTableStatus should return an array of flow table status, all existing flow tables should be included in the list.
 	GetFlowTableStatus() []binding.TableStatus
@@ -151,6 +173,34 @@ type Client interface {
 	// in the connection tracking context, and 3) SNAT the packets with Node IP.
 	InstallExternalFlows(nodeIP net.IP, localSubnet net.IPNet) error
 
+	// InstallEgressTunnelFlows sets up the flows to forward the packets received from the tunnel and destined to
+	// external addresses to the gateway, so that the packets of the remote Pods selected by the Egresses whose IPs
+	// are assigned to this Node are SNAT'd by the host network. Calls to InstallEgressTunnelFlows are idempotent.
+	InstallEgressTunnelFlows() error
+
+	// InstallPodSNATFlows sets up the flows to forward the packets from the local Pod with the provided IP to
+	// external addresses to the Node which owns the Egress IP of the Pod, through the tunnel. The flows of the Pod IP
+	// are replaced if they were installed with another Egress Node IP.
+	InstallPodSNATFlows(podIP net.IP, egressNodeIP net.IP) error
+
+	// UninstallPodSNATFlows removes the flows installed by InstallPodSNATFlows for the Pod IP. It does nothing if no
+	// flow was installed for the Pod IP.
+	UninstallPodSNATFlows(podIP net.IP) error
+
+	// InstallEgressTunnelSNATFlows sets up the flows to SNAT the packets received from the tunnel and destined to
+	// external addresses in OVS. It replaces InstallEgressTunnelFlows on Windows, where the traffic of the Pods is
+	// SNAT'd by OVS instead of the host network. Calls to InstallEgressTunnelSNATFlows are idempotent.
+	InstallEgressTunnelSNATFlows() error
+
+	// InstallPodSNATIPFlows sets up the flows to SNAT the packets from the provided Pod IP, of a local Pod or of a
+	// remote Pod received from the tunnel, to external addresses with the provided SNAT IP in OVS, instead of the Node
+	// IP. It is only used on Windows. The flows of the Pod IP are replaced if they were installed with another SNAT IP.
+	InstallPodSNATIPFlows(podIP net.IP, snatIP net.IP) error
+
+	// UninstallPodSNATIPFlows removes the flows installed by InstallPodSNATIPFlows for the Pod IP. It does nothing if
+	// no flow was installed for the Pod IP.
+	UninstallPodSNATIPFlows(podIP net.IP) error
+
 	// Disconnect disconnects the connection between client and OFSwitch.
 	Disconnect() error
 
@@ -211,6 +261,10 @@ type Client interface {
 		inPort uint32,
 		outPort int32) error
 
+	// SendUDPPacketOut sends a UDP packet with the provided payload to the OVS port outPort directly, without going
+	// through the pipeline.
+	SendUDPPacketOut(srcMAC, dstMAC net.HardwareAddr, srcIP, dstIP net.IP, srcPort, dstPort uint16, data []byte, outPort uint32) error
+
 	// InstallTraceflowFlows installs flows for specific traceflow request.
 	InstallTraceflowFlows(dataplaneTag uint8) error
 
@@ -220,6 +274,17 @@ type Client interface {
 	// Find network policy and namespace by conjunction ID.
 	GetPolicyFromConjunction(ruleID uint32) *v1beta1.NetworkPolicyReference
 
+	// Find the direction and the priority within its network policy of the rule by conjunction ID.
+	GetRuleFromConjunction(ruleID uint32) (v1beta1.Direction, int32, bool)
+
+	// InstallDNSResponseFlows installs the flows which send a copy of the DNS responses destined for local Pods to
+	// the Antrea Agent, in order to learn the IP addresses of the FQDNs referenced by Antrea-native policy rules.
+	InstallDNSResponseFlows() error
+
+	// InstallDHCPRequestFlows installs the flows which send the DHCP requests of local Pods to the Antrea Agent, in
+	// order to answer them with the IPs allocated by Antrea IPAM.
+	InstallDHCPRequestFlows() error
+
 	// RegisterPacketInHandler registers PacketIn handler to process PacketIn event.
 	RegisterPacketInHandler(packetHandlerName string, packetInHandler interface{})
 	// RegisterPacketInHandler uses SubscribePacketIn to get PacketIn message and process received
@@ -287,15 +352,19 @@ func (c *client) InstallNodeFlows(hostname string,
 	localGatewayMAC net.HardwareAddr,
 	peerPodCIDR net.IPNet,
 	peerGatewayIP, tunnelPeerIP net.IP,
-	tunOFPort, ipsecTunOFPort uint32) error {
+	tunOFPort, ipsecTunOFPort uint32,
+	peerEncapMode config.TrafficEncapModeType) error {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
 
 	flows := []binding.Flow{
 		c.arpResponderFlow(peerGatewayIP, cookie.Node),
 	}
-	if c.encapMode.NeedsEncapToPeer(tunnelPeerIP, c.nodeConfig.NodeIPAddr) {
+	if peerEncapMode.NeedsEncapToPeer(tunnelPeerIP, c.nodeConfig.NodeIPAddr) {
 		flows = append(flows, c.l3FwdFlowToRemote(localGatewayMAC, peerPodCIDR, tunnelPeerIP, tunOFPort, cookie.Node))
+		if c.enableProxy {
+			flows = append(flows, c.dsrFwdFlowToRemote(localGatewayMAC, peerPodCIDR, tunnelPeerIP, tunOFPort, cookie.Node))
+		}
 	} else {
 		flows = append(flows, c.l3FwdFlowToRemoteViaGW(localGatewayMAC, peerPodCIDR, cookie.Node))
 	}
@@ -331,6 +400,9 @@ func (c *client) InstallPodFlows(interfaceName string, podInterfaceIP net.IP, po
 		flows = append(flows,
 			c.l3ToPodFlow(podInterfaceIP, podInterfaceMAC, cookie.Pod),
 		)
+	} else if c.nodeConfig.PodCIDR != nil && !c.nodeConfig.PodCIDR.Contains(podInterfaceIP) {
+		// The Pod IP is allocated by Antrea IPAM from an IPPool, the traffic of the Pod is routed by the gateway.
+		flows = append(flows, c.arpResponderPodFlow(ofPort, gatewayMAC, cookie.Pod))
 	}
 	return c.addFlows(c.podFlowCache, interfaceName, flows)
 }
@@ -398,10 +470,17 @@ func (c *client) InstallEndpointFlows(protocol binding.Protocol, endpoints []pro
 		if err := c.addFlows(c.serviceFlowCache, cacheKey, flows); err != nil {
 			return err
 		}
+		// The flows kept for the draining Endpoint have been installed again
+		// with the cache key above.
+		c.serviceFlowCache.Delete(drainingEndpointCacheKey(cacheKey))
 	}
 	return nil
 }
 
+func drainingEndpointCacheKey(cacheKey string) string {
+	return "Draining" + cacheKey
+}
+
 func (c *client) UninstallEndpointFlows(protocol binding.Protocol, endpoint proxy.Endpoint) error {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
@@ -411,7 +490,43 @@ func (c *client) UninstallEndpointFlows(protocol binding.Protocol, endpoint prox
 		return fmt.Errorf("error when getting port: %w", err)
 	}
 	cacheKey := fmt.Sprintf("Endpoints:%s:%d:%s", endpoint.IP(), port, protocol)
-	return c.deleteFlows(c.serviceFlowCache, cacheKey)
+	if err := c.deleteFlows(c.serviceFlowCache, cacheKey); err != nil {
+		return err
+	}
+	return c.deleteFlows(c.serviceFlowCache, drainingEndpointCacheKey(cacheKey))
+}
+
+func (c *client) DrainEndpointFlows(protocol binding.Protocol, endpoint proxy.Endpoint) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+
+	port, err := endpoint.Port()
+	if err != nil {
+		return fmt.Errorf("error when getting port: %w", err)
+	}
+	cacheKey := fmt.Sprintf("Endpoints:%s:%d:%s", endpoint.IP(), port, protocol)
+	fCacheI, ok := c.serviceFlowCache.Load(cacheKey)
+	if !ok {
+		return nil
+	}
+	fCache := fCacheI.(flowCache)
+	dnatMatch := c.endpointDNATFlow(net.ParseIP(endpoint.IP()).To4(), uint16(port), protocol).MatchString()
+	if dnatFlow, ok := fCache[dnatMatch]; ok {
+		if err := c.ofEntryOperations.Delete(dnatFlow); err != nil {
+			return err
+		}
+	}
+	// The remaining flows are moved to another cache key, so that they are
+	// not removed if the Endpoint is installed again before it is uninstalled.
+	drainingCache := flowCache{}
+	for matchString, flow := range fCache {
+		if matchString != dnatMatch {
+			drainingCache[matchString] = flow
+		}
+	}
+	c.serviceFlowCache.Delete(cacheKey)
+	c.serviceFlowCache.Store(drainingEndpointCacheKey(cacheKey), drainingCache)
+	return nil
 }
 
 func (c *client) InstallServiceFlows(groupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol, affinityTimeout uint16) error {
@@ -442,6 +557,39 @@ func (c *client) InstallLoadBalancerServiceFromOutsideFlows(svcIP net.IP, svcPor
 	return c.addFlows(c.serviceFlowCache, cacheKey, flows)
 }
 
+func (c *client) InstallLoadBalancerServiceDSRFlows(localGroupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	flows := []binding.Flow{
+		c.loadBalancerServiceDSRMarkFlow(svcIP, svcPort, protocol),
+	}
+	flows = append(flows, c.loadBalancerServiceLocalLBFlows(localGroupID, svcIP, svcPort, protocol, markTrafficFromTunnel)...)
+	cacheKey := fmt.Sprintf("LoadBalancerServiceDSR:%s:%d:%s", svcIP, svcPort, protocol)
+	return c.addFlows(c.serviceFlowCache, cacheKey, flows)
+}
+
+func (c *client) UninstallLoadBalancerServiceDSRFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	cacheKey := fmt.Sprintf("LoadBalancerServiceDSR:%s:%d:%s", svcIP, svcPort, protocol)
+	return c.deleteFlows(c.serviceFlowCache, cacheKey)
+}
+
+func (c *client) InstallLoadBalancerServiceLocalFlows(localGroupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	flows := c.loadBalancerServiceLocalLBFlows(localGroupID, svcIP, svcPort, protocol, markTrafficFromGateway)
+	cacheKey := fmt.Sprintf("LoadBalancerServiceLocal:%s:%d:%s", svcIP, svcPort, protocol)
+	return c.addFlows(c.serviceFlowCache, cacheKey, flows)
+}
+
+func (c *client) UninstallLoadBalancerServiceLocalFlows(svcIP net.IP, svcPort uint16, protocol binding.Protocol) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	cacheKey := fmt.Sprintf("LoadBalancerServiceLocal:%s:%d:%s", svcIP, svcPort, protocol)
+	return c.deleteFlows(c.serviceFlowCache, cacheKey)
+}
+
 func (c *client) InstallClusterServiceFlows() error {
 	flows := []binding.Flow{
 		c.l2ForwardOutputServiceHairpinFlow(),
@@ -449,6 +597,7 @@ func (c *client) InstallClusterServiceFlows() error {
 		c.serviceNeedLBFlow(),
 		c.sessionAffinityReselectFlow(),
 		c.serviceLBBypassFlow(),
+		c.loadBalancerServiceDSRUntrackedFlow(),
 	}
 	if err := c.ofEntryOperations.AddAll(flows); err != nil {
 		return err
@@ -555,6 +704,9 @@ func (c *client) Initialize(roundInfo types.RoundInfo, nodeConfig *config.NodeCo
 	if err := c.deleteFlowsByRoundNum(roundInfo.RoundNum); err != nil {
 		return nil, fmt.Errorf("error when deleting exiting flows for current round number: %v", err)
 	}
+	if err := c.addPacketInMeters(); err != nil {
+		return nil, err
+	}
 
 	return connCh, c.initialize()
 }
@@ -573,6 +725,9 @@ func (c *client) ReplayFlows() {
 	c.replayMutex.Lock()
 	defer c.replayMutex.Unlock()
 
+	if err := c.addPacketInMeters(); err != nil {
+		klog.Errorf("Error when replaying packet-in meters: %v", err)
+	}
 	if err := c.initialize(); err != nil {
 		klog.Errorf("Error during flow replay: %v", err)
 	}
@@ -590,6 +745,9 @@ func (c *client) ReplayFlows() {
 	addFixedFlows(c.gatewayFlows)
 	addFixedFlows(c.defaultServiceFlows)
 	addFixedFlows(c.defaultTunnelFlows)
+	addFixedFlows(c.dnsResponseFlows)
+	addFixedFlows(c.dhcpRequestFlows)
+	addFixedFlows(c.egressTunnelFlows)
 	// hostNetworkingFlows is used only on Windows. Replay the flows only when there are flows in this cache.
 	if len(c.hostNetworkingFlows) > 0 {
 		addFixedFlows(c.hostNetworkingFlows)
@@ -619,6 +777,8 @@ func (c *client) ReplayFlows() {
 	c.nodeFlowCache.Range(installCachedFlows)
 	c.podFlowCache.Range(installCachedFlows)
 	c.serviceFlowCache.Range(installCachedFlows)
+	c.snatFlowCache.Range(installCachedFlows)
+	c.snatIPFlowCache.Range(installCachedFlows)
 
 	c.replayPolicyFlows()
 }
@@ -726,6 +886,23 @@ func (c *client) SendTraceflowPacket(
 	return c.bridge.SendPacketOut(packetOutObj)
 }
 
+func (c *client) SendUDPPacketOut(srcMAC, dstMAC net.HardwareAddr, srcIP, dstIP net.IP, srcPort, dstPort uint16, data []byte, outPort uint32) error {
+	packetOutObj := c.bridge.BuildPacketOut().
+		SetSrcMAC(srcMAC).
+		SetDstMAC(dstMAC).
+		SetSrcIP(srcIP).
+		SetDstIP(dstIP).
+		SetIPProtocol(binding.ProtocolUDP).
+		SetTTL(64).
+		SetUDPSrcPort(srcPort).
+		SetUDPDstPort(dstPort).
+		SetUDPData(data).
+		SetInport(openflow13.P_CONTROLLER).
+		SetOutport(outPort).
+		Done()
+	return c.bridge.SendPacketOut(packetOutObj)
+}
+
 func (c *client) InstallTraceflowFlows(dataplaneTag uint8) error {
 	flow := c.traceflowL2ForwardOutputFlow(dataplaneTag, cookie.Default)
 	if err := c.Add(flow); err != nil {
@@ -752,9 +929,113 @@ func (c *client) InstallTraceflowFlows(dataplaneTag uint8) error {
 	return c.AddAll(flows)
 }
 
+func (c *client) InstallDNSResponseFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	if len(c.dnsResponseFlows) > 0 {
+		return nil
+	}
+	flows := []binding.Flow{c.dnsResponseFlow(cookie.Policy)}
+	if err := c.ofEntryOperations.AddAll(flows); err != nil {
+		return err
+	}
+	c.dnsResponseFlows = flows
+	return nil
+}
+
+func (c *client) InstallDHCPRequestFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	if len(c.dhcpRequestFlows) > 0 {
+		return nil
+	}
+	flows := []binding.Flow{c.dhcpRequestFlow(cookie.Pod)}
+	if err := c.ofEntryOperations.AddAll(flows); err != nil {
+		return err
+	}
+	c.dhcpRequestFlows = flows
+	return nil
+}
+
+func (c *client) InstallEgressTunnelFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	if len(c.egressTunnelFlows) > 0 {
+		return nil
+	}
+	flows := []binding.Flow{c.l3FwdFlowFromTunnelToGateway(c.nodeConfig.GatewayConfig.MAC, cookie.SNAT)}
+	if err := c.ofEntryOperations.AddAll(flows); err != nil {
+		return err
+	}
+	c.egressTunnelFlows = flows
+	return nil
+}
+
+func (c *client) InstallPodSNATFlows(podIP net.IP, egressNodeIP net.IP) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	cacheKey := podIP.String()
+	flow := c.snatFwdFlowToRemote(c.nodeConfig.GatewayConfig.MAC, podIP, egressNodeIP, config.DefaultTunOFPort, cookie.SNAT)
+	if _, ok := c.snatFlowCache.Load(cacheKey); ok {
+		// The flow only matches the Pod IP, so a change of the Egress Node is a modification of the installed flow.
+		if err := c.ofEntryOperations.Modify(flow); err != nil {
+			return err
+		}
+		c.snatFlowCache.Store(cacheKey, flowCache{flow.MatchString(): flow})
+		return nil
+	}
+	return c.addFlows(c.snatFlowCache, cacheKey, []binding.Flow{flow})
+}
+
+func (c *client) UninstallPodSNATFlows(podIP net.IP) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	return c.deleteFlows(c.snatFlowCache, podIP.String())
+}
+
+func (c *client) InstallEgressTunnelSNATFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	if len(c.egressTunnelFlows) > 0 {
+		return nil
+	}
+	flows := []binding.Flow{c.snatMarkFlowFromTunnel(c.nodeConfig.GatewayConfig.MAC, cookie.SNAT)}
+	if err := c.ofEntryOperations.AddAll(flows); err != nil {
+		return err
+	}
+	c.egressTunnelFlows = flows
+	return nil
+}
+
+func (c *client) InstallPodSNATIPFlows(podIP net.IP, snatIP net.IP) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	cacheKey := podIP.String()
+	flow := c.snatIPFlow(podIP, snatIP, cookie.SNAT)
+	if _, ok := c.snatIPFlowCache.Load(cacheKey); ok {
+		// The flow only matches the Pod IP, so a change of the SNAT IP is a modification of the installed flow.
+		if err := c.ofEntryOperations.Modify(flow); err != nil {
+			return err
+		}
+		c.snatIPFlowCache.Store(cacheKey, flowCache{flow.MatchString(): flow})
+		return nil
+	}
+	return c.addFlows(c.snatIPFlowCache, cacheKey, []binding.Flow{flow})
+}
+
+func (c *client) UninstallPodSNATIPFlows(podIP net.IP) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	return c.deleteFlows(c.snatIPFlowCache, podIP.String())
+}
+
 // Add TLV map optClass 0x0104, optType 0x80 optLength 4 tunMetadataIndex 0 to store data plane tag
 // in tunnel. Data plane tag will be stored to NXM_NX_TUN_METADATA0[28..31] when packet get encapsulated
 // into geneve, and will be stored back to NXM_NX_REG9[28..31] when packet get decapsulated.
 func (c *client) InitialTLVMap() error {
-	return c.bridge.AddTLVMap(0x0104, 0x80, 4, 0)
+	if err := c.bridge.AddTLVMap(0x0104, 0x80, 4, 0); err != nil {
+		return err
+	}
+	c.tunMetadataMapped = true
+	return nil
 }
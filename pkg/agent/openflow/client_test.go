@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -30,8 +31,12 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow/cookie"
 	oftest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
+	"github.com/vmware-tanzu/antrea/pkg/agent/types"
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 	ofconfig "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	"github.com/vmware-tanzu/antrea/third_party/proxy"
 )
 
 const bridgeName = "dummy-br"
@@ -43,7 +48,7 @@ func installNodeFlows(ofClient Client, cacheKey string) (int, error) {
 	gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
 	gwIP, IPNet, _ := net.ParseCIDR("10.0.1.1/24")
 	peerNodeIP := net.ParseIP("192.168.1.1")
-	err := ofClient.InstallNodeFlows(hostName, gwMAC, *IPNet, gwIP, peerNodeIP, config.DefaultTunOFPort, 0)
+	err := ofClient.InstallNodeFlows(hostName, gwMAC, *IPNet, gwIP, peerNodeIP, config.DefaultTunOFPort, 0, config.TrafficEncapModeEncap)
 	client := ofClient.(*client)
 	fCacheI, ok := client.nodeFlowCache.Load(hostName)
 	if ok {
@@ -77,7 +82,7 @@ func TestIdempotentFlowInstallation(t *testing.T) {
 		numFlows  int
 		installFn func(ofClient Client, cacheKey string) (int, error)
 	}{
-		{"NodeFlows", "host", 2, installNodeFlows},
+		{"NodeFlows", "host", 3, installNodeFlows},
 		{"PodFlows", "aaaa-bbbb-cccc-dddd", 5, installPodFlows},
 	}
 
@@ -88,7 +93,7 @@ func TestIdempotentFlowInstallation(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			m := oftest.NewMockOFEntryOperations(ctrl)
-			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false)
+			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false, false)
 			client := ofClient.(*client)
 			client.cookieAllocator = cookie.NewAllocator(0)
 			client.nodeConfig = &config.NodeConfig{}
@@ -116,7 +121,7 @@ func TestIdempotentFlowInstallation(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			m := oftest.NewMockOFEntryOperations(ctrl)
-			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false)
+			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false, false)
 			client := ofClient.(*client)
 			client.cookieAllocator = cookie.NewAllocator(0)
 			client.nodeConfig = &config.NodeConfig{}
@@ -157,7 +162,7 @@ func TestFlowInstallationFailed(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			m := oftest.NewMockOFEntryOperations(ctrl)
-			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false)
+			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false, false)
 			client := ofClient.(*client)
 			client.cookieAllocator = cookie.NewAllocator(0)
 			client.nodeConfig = &config.NodeConfig{}
@@ -191,7 +196,7 @@ func TestConcurrentFlowInstallation(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			m := oftest.NewMockOFEntryOperations(ctrl)
-			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false)
+			ofClient := NewClient(bridgeName, bridgeMgmtAddr, true, false, false)
 			client := ofClient.(*client)
 			client.cookieAllocator = cookie.NewAllocator(0)
 			client.nodeConfig = &config.NodeConfig{}
@@ -237,3 +242,377 @@ func TestConcurrentFlowInstallation(t *testing.T) {
 	}
 
 }
+
+// TestPodFlowsWithFakeBridge checks the flows realized by InstallPodFlows and
+// UninstallPodFlows on a FakeBridge.
+func TestPodFlowsWithFakeBridge(t *testing.T) {
+	bridge := ofconfig.NewFakeBridge()
+	ofClient := NewClientWithBridge(bridge, false, false, false)
+	_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+	gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	nodeConfig := &config.NodeConfig{
+		PodCIDR:       podCIDR,
+		GatewayConfig: &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+	}
+	_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+	require.NoError(t, err)
+	assert.True(t, bridge.IsConnected())
+	assert.Equal(t, []string{"priority=0,table=10"}, bridge.TableFlows(SpoofGuardTable))
+
+	podCookie := uint64(cookie.Pod) << cookie.BitwidthReserved
+	assert.Empty(t, bridge.CookieFlows(podCookie, cookie.CategoryMask))
+	_, err = installPodFlows(ofClient, "pod1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"priority=190,table=0,in_port=10",
+		"priority=200,table=10,arp,in_port=10,arp_sha=aa:bb:cc:dd:ee:ee,arp_spa=10.0.0.2",
+		"priority=200,table=10,ip,in_port=10,dl_src=aa:bb:cc:dd:ee:ee,nw_src=10.0.0.2",
+		"priority=200,table=70,ip,dl_dst=aa:bb:cc:dd:ee:ff,nw_dst=10.0.0.2",
+		"priority=200,table=80,dl_dst=aa:bb:cc:dd:ee:ee",
+	}, bridge.CookieFlows(podCookie, cookie.CategoryMask))
+
+	require.NoError(t, ofClient.UninstallPodFlows("pod1"))
+	assert.Empty(t, bridge.CookieFlows(podCookie, cookie.CategoryMask))
+	assert.Equal(t, []string{"priority=0,table=10"}, bridge.TableFlows(SpoofGuardTable))
+}
+
+// TestAntreaIPAMPodFlowsWithFakeBridge checks that the ARP requests of a Pod whose IP is not in the PodCIDR of the Node
+// are replied with the gateway MAC.
+func TestAntreaIPAMPodFlowsWithFakeBridge(t *testing.T) {
+	bridge := ofconfig.NewFakeBridge()
+	ofClient := NewClientWithBridge(bridge, false, false, false)
+	_, podCIDR, _ := net.ParseCIDR("10.10.0.0/24")
+	gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	nodeConfig := &config.NodeConfig{
+		PodCIDR:       podCIDR,
+		GatewayConfig: &config.GatewayConfig{IP: net.ParseIP("10.10.0.1"), MAC: gwMAC},
+	}
+	_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeNoEncap, config.HostGatewayOFPort)
+	require.NoError(t, err)
+
+	podCookie := uint64(cookie.Pod) << cookie.BitwidthReserved
+	_, err = installPodFlows(ofClient, "pod1")
+	require.NoError(t, err)
+	assert.Contains(t, bridge.CookieFlows(podCookie, cookie.CategoryMask), "priority=210,table=20,arp,in_port=10,arp_op=1")
+
+	require.NoError(t, ofClient.UninstallPodFlows("pod1"))
+	assert.Empty(t, bridge.CookieFlows(podCookie, cookie.CategoryMask))
+}
+
+// TestPodSNATFlowsWithFakeBridge checks that the flow forwarding the traffic of a local Pod to the Egress Node is
+// replaced when the Egress Node changes, and that the packets received from the tunnel and destined to external
+// addresses are forwarded to the gateway.
+func TestPodSNATFlowsWithFakeBridge(t *testing.T) {
+	bridge := ofconfig.NewFakeBridge()
+	ofClient := NewClientWithBridge(bridge, false, false, false)
+	_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+	gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	nodeConfig := &config.NodeConfig{
+		PodCIDR:       podCIDR,
+		GatewayConfig: &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+	}
+	_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+	require.NoError(t, err)
+
+	snatCookie := uint64(cookie.SNAT) << cookie.BitwidthReserved
+	require.NoError(t, ofClient.InstallEgressTunnelFlows())
+	require.NoError(t, ofClient.InstallEgressTunnelFlows())
+	assert.Equal(t, []string{
+		"priority=190,table=70,ip,dl_dst=aa:bb:cc:dd:ee:ff",
+	}, bridge.CookieFlows(snatCookie, cookie.CategoryMask))
+
+	podIP := net.ParseIP("10.0.0.2")
+	require.NoError(t, ofClient.InstallPodSNATFlows(podIP, net.ParseIP("192.168.1.2")))
+	require.NoError(t, ofClient.InstallPodSNATFlows(podIP, net.ParseIP("192.168.1.3")))
+	assert.Len(t, bridge.CookieFlows(snatCookie, cookie.CategoryMask), 2)
+
+	require.NoError(t, ofClient.UninstallPodSNATFlows(podIP))
+	assert.Len(t, bridge.CookieFlows(snatCookie, cookie.CategoryMask), 1)
+}
+
+// TestPodSNATIPFlowsWithFakeBridge checks that the flow SNATing the traffic of a Pod with the Egress IP in OVS, as on
+// Windows, is replaced when the Egress IP changes, and that the packets received from the tunnel and destined to
+// external addresses are marked to be SNAT'd.
+func TestPodSNATIPFlowsWithFakeBridge(t *testing.T) {
+	bridge := ofconfig.NewFakeBridge()
+	ofClient := NewClientWithBridge(bridge, false, false, false)
+	_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+	gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	nodeConfig := &config.NodeConfig{
+		PodCIDR:       podCIDR,
+		GatewayConfig: &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+	}
+	_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+	require.NoError(t, err)
+
+	snatCookie := uint64(cookie.SNAT) << cookie.BitwidthReserved
+	require.NoError(t, ofClient.InstallEgressTunnelSNATFlows())
+	require.NoError(t, ofClient.InstallEgressTunnelSNATFlows())
+	assert.Equal(t, []string{
+		"priority=190,table=70,ip,dl_dst=aa:bb:cc:dd:ee:ff",
+	}, bridge.CookieFlows(snatCookie, cookie.CategoryMask))
+
+	podIP := net.ParseIP("10.0.1.2")
+	require.NoError(t, ofClient.InstallPodSNATIPFlows(podIP, net.ParseIP("192.168.1.100")))
+	require.NoError(t, ofClient.InstallPodSNATIPFlows(podIP, net.ParseIP("192.168.1.101")))
+	assert.Equal(t, []string{
+		"priority=190,table=70,ip,dl_dst=aa:bb:cc:dd:ee:ff",
+		"priority=210,table=107,ip,nw_src=10.0.1.2,ct_state=+new+trk",
+	}, bridge.CookieFlows(snatCookie, cookie.CategoryMask))
+
+	require.NoError(t, ofClient.UninstallPodSNATIPFlows(podIP))
+	assert.Len(t, bridge.CookieFlows(snatCookie, cookie.CategoryMask), 1)
+}
+
+// TestDrainEndpointFlowsWithFakeBridge checks that DrainEndpointFlows removes
+// the DNAT flow of a local Endpoint and keeps its hairpin flow, until the
+// Endpoint is installed again or uninstalled.
+func TestDrainEndpointFlowsWithFakeBridge(t *testing.T) {
+	bridge := ofconfig.NewFakeBridge()
+	ofClient := NewClientWithBridge(bridge, true, false, false)
+	_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+	gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	nodeConfig := &config.NodeConfig{
+		PodCIDR:       podCIDR,
+		GatewayConfig: &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+	}
+	_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+	require.NoError(t, err)
+
+	endpoint := &proxy.BaseEndpointInfo{Endpoint: "10.0.0.2:80", IsLocal: true}
+	dnatFlows := func() []string {
+		var flows []string
+		for _, flow := range bridge.TableFlows(endpointDNATTable) {
+			if strings.Contains(flow, "priority=200") {
+				flows = append(flows, flow)
+			}
+		}
+		return flows
+	}
+	hairpinFlows := func() []string {
+		var flows []string
+		for _, flow := range bridge.TableFlows(hairpinSNATTable) {
+			if strings.Contains(flow, "nw_src=10.0.0.2") {
+				flows = append(flows, flow)
+			}
+		}
+		return flows
+	}
+
+	require.NoError(t, ofClient.InstallEndpointFlows(ofconfig.ProtocolTCP, []proxy.Endpoint{endpoint}))
+	assert.Len(t, dnatFlows(), 1)
+	assert.Len(t, hairpinFlows(), 1)
+
+	require.NoError(t, ofClient.DrainEndpointFlows(ofconfig.ProtocolTCP, endpoint))
+	assert.Empty(t, dnatFlows())
+	assert.Len(t, hairpinFlows(), 1)
+
+	// The Endpoint is selected again before it is uninstalled.
+	require.NoError(t, ofClient.InstallEndpointFlows(ofconfig.ProtocolTCP, []proxy.Endpoint{endpoint}))
+	assert.Len(t, dnatFlows(), 1)
+	assert.Len(t, hairpinFlows(), 1)
+
+	require.NoError(t, ofClient.DrainEndpointFlows(ofconfig.ProtocolTCP, endpoint))
+	require.NoError(t, ofClient.UninstallEndpointFlows(ofconfig.ProtocolTCP, endpoint))
+	assert.Empty(t, dnatFlows())
+	assert.Empty(t, hairpinFlows())
+}
+
+// TestPolicyRuleRateLimitWithFakeBridge checks the meter added for the rate
+// limit of an Antrea-native policy rule on a FakeBridge.
+func TestPolicyRuleRateLimitWithFakeBridge(t *testing.T) {
+	allowAction := secv1alpha1.RuleActionAllow
+	priority := uint16(10000)
+	newRule := func(ruleID uint32) *types.PolicyRule {
+		return &types.PolicyRule{
+			Direction: v1beta1.DirectionIn,
+			From:      []types.Address{NewIPAddress(net.ParseIP("192.168.1.1"))},
+			To:        []types.Address{NewOFPortAddress(1)},
+			Action:    &allowAction,
+			Priority:  &priority,
+			FlowID:    ruleID,
+			TableID:   DefaultTierIngressRuleTable,
+			PolicyRef: &v1beta1.NetworkPolicyReference{Type: v1beta1.AntreaClusterNetworkPolicy, Name: "cnp1"},
+			RateLimit: &v1beta1.RuleRateLimit{MaxNewConnectionsPerSecond: 100, Burst: 20},
+		}
+	}
+	actionFlowMeters := func(bridge *ofconfig.FakeBridge) []uint32 {
+		var meterIDs []uint32
+		for _, f := range bridge.Flows() {
+			if f.TableID == DefaultTierIngressRuleTable && strings.Contains(f.Match, "conj_id") {
+				meterIDs = append(meterIDs, f.MeterID)
+			}
+		}
+		return meterIDs
+	}
+
+	for _, unsupportedFeatures := range [][]string{nil, {config.OVSFeatureMeters}} {
+		bridge := ofconfig.NewFakeBridge()
+		ofClient := NewClientWithBridge(bridge, false, true, false)
+		_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+		gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+		nodeConfig := &config.NodeConfig{
+			PodCIDR:                podCIDR,
+			GatewayConfig:          &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+			UnsupportedOVSFeatures: unsupportedFeatures,
+		}
+		_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+		require.NoError(t, err)
+
+		require.NoError(t, ofClient.InstallPolicyRuleFlows(newRule(10)))
+		if unsupportedFeatures == nil {
+			assert.Equal(t, ofconfig.Meter{Rate: 100, BurstSize: 20}, bridge.Meters()[10])
+			assert.Equal(t, []uint32{10}, actionFlowMeters(bridge))
+		} else {
+			// The rate limit is ignored when OVS doesn't support meters.
+			assert.Empty(t, bridge.Meters())
+			assert.Equal(t, []uint32{0}, actionFlowMeters(bridge))
+		}
+
+		_, err = ofClient.UninstallPolicyRuleFlows(10)
+		require.NoError(t, err)
+		assert.NotContains(t, bridge.Meters(), uint32(10))
+		assert.Empty(t, actionFlowMeters(bridge))
+	}
+}
+
+// TestPacketInMetersWithFakeBridge checks that the flows sending the packets
+// of the Antrea-native policy rules with logging enabled to the Agent use the
+// packet-in meter on a FakeBridge.
+func TestPacketInMetersWithFakeBridge(t *testing.T) {
+	priority := uint16(10000)
+	newRule := func(ruleID uint32, action secv1alpha1.RuleAction) *types.PolicyRule {
+		return &types.PolicyRule{
+			Direction:     v1beta1.DirectionIn,
+			From:          []types.Address{NewIPAddress(net.ParseIP("192.168.1.1"))},
+			To:            []types.Address{NewOFPortAddress(1)},
+			Action:        &action,
+			Priority:      &priority,
+			FlowID:        ruleID,
+			TableID:       DefaultTierIngressRuleTable,
+			PolicyRef:     &v1beta1.NetworkPolicyReference{Type: v1beta1.AntreaClusterNetworkPolicy, Name: "cnp1"},
+			EnableLogging: true,
+		}
+	}
+	actionFlow := func(bridge *ofconfig.FakeBridge, ruleID uint32) ofconfig.FakeFlow {
+		for _, f := range bridge.Flows() {
+			if f.TableID == DefaultTierIngressRuleTable && strings.Contains(f.Match, fmt.Sprintf("conj_id=%d", ruleID)) {
+				return f
+			}
+		}
+		t.Fatalf("Action flow of rule %d not found", ruleID)
+		return ofconfig.FakeFlow{}
+	}
+
+	for _, unsupportedFeatures := range [][]string{nil, {config.OVSFeatureMeters}} {
+		bridge := ofconfig.NewFakeBridge()
+		ofClient := NewClientWithBridge(bridge, false, true, false)
+		_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+		gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+		nodeConfig := &config.NodeConfig{
+			PodCIDR:                podCIDR,
+			GatewayConfig:          &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+			UnsupportedOVSFeatures: unsupportedFeatures,
+		}
+		_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+		require.NoError(t, err)
+
+		require.NoError(t, ofClient.InstallPolicyRuleFlows(newRule(10, secv1alpha1.RuleActionAllow)))
+		require.NoError(t, ofClient.InstallPolicyRuleFlows(newRule(11, secv1alpha1.RuleActionDrop)))
+		require.NoError(t, ofClient.InstallPolicyRuleFlows(newRule(12, secv1alpha1.RuleActionAudit)))
+		if unsupportedFeatures == nil {
+			assert.Equal(t, ofconfig.Meter{Rate: packetInMeterRate, BurstSize: packetInMeterBurst}, bridge.Meters()[packetInMeterIDNP])
+			// Only the packets sent to the Agent go through the meter, the
+			// packets allowed or resubmitted by the rules never do.
+			for _, ruleID := range []uint32{10, 11, 12} {
+				assert.Equal(t, uint32(0), actionFlow(bridge, ruleID).MeterID)
+				assert.Equal(t, packetInMeterIDNP, actionFlow(bridge, ruleID).PacketInMeterID)
+			}
+		} else {
+			assert.Empty(t, bridge.Meters())
+			for _, ruleID := range []uint32{10, 11, 12} {
+				assert.Equal(t, uint32(0), actionFlow(bridge, ruleID).MeterID)
+				assert.Equal(t, uint32(0), actionFlow(bridge, ruleID).PacketInMeterID)
+			}
+		}
+	}
+}
+
+// TestDNSAndDHCPPacketInMetersWithFakeBridge checks that the DNS response and
+// DHCP request flows only meter the packets sent to the Agent on a FakeBridge.
+func TestDNSAndDHCPPacketInMetersWithFakeBridge(t *testing.T) {
+	bridge := ofconfig.NewFakeBridge()
+	ofClient := NewClientWithBridge(bridge, false, true, false)
+	_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+	gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	nodeConfig := &config.NodeConfig{
+		PodCIDR:       podCIDR,
+		GatewayConfig: &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+	}
+	_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+	require.NoError(t, err)
+	require.NoError(t, ofClient.InstallDNSResponseFlows())
+	require.NoError(t, ofClient.InstallDHCPRequestFlows())
+
+	packetInMeters := map[string]uint32{}
+	for _, f := range bridge.Flows() {
+		if f.TableID == L2ForwardingOutTable && strings.Contains(f.Match, "tp_src=0x35") {
+			// The DNS responses output to the Pods never go through the meter.
+			assert.Equal(t, uint32(0), f.MeterID)
+			packetInMeters["dns"] = f.PacketInMeterID
+		}
+		if f.TableID == SpoofGuardTable && strings.Contains(f.Match, "tp_dst=0x43") {
+			assert.Equal(t, uint32(0), f.MeterID)
+			packetInMeters["dhcp"] = f.PacketInMeterID
+		}
+	}
+	assert.Equal(t, map[string]uint32{"dns": packetInMeterIDDNS, "dhcp": packetInMeterIDDHCP}, packetInMeters)
+}
+
+// TestDenyFlowExportWithFakeBridge checks that the default drop flows of
+// NetworkPolicies use the packet-in meter of denied connections when their
+// export is enabled.
+func TestDenyFlowExportWithFakeBridge(t *testing.T) {
+	rule := &types.PolicyRule{
+		Direction: v1beta1.DirectionIn,
+		From:      []types.Address{NewIPAddress(net.ParseIP("192.168.1.1"))},
+		To:        []types.Address{NewOFPortAddress(1)},
+		FlowID:    10,
+		TableID:   IngressRuleTable,
+		PolicyRef: &v1beta1.NetworkPolicyReference{Type: v1beta1.K8sNetworkPolicy, Namespace: "ns1", Name: "np1"},
+	}
+	for _, tc := range []struct {
+		name                 string
+		enableDenyFlowExport bool
+		unsupportedFeatures  []string
+		expectedMeterID      uint32
+	}{
+		{name: "disabled"},
+		{name: "enabled", enableDenyFlowExport: true, expectedMeterID: packetInMeterIDDenyFlow},
+		{name: "enabled without meters", enableDenyFlowExport: true, unsupportedFeatures: []string{config.OVSFeatureMeters}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bridge := ofconfig.NewFakeBridge()
+			ofClient := NewClientWithBridge(bridge, false, false, tc.enableDenyFlowExport)
+			_, podCIDR, _ := net.ParseCIDR("10.0.0.0/24")
+			gwMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+			nodeConfig := &config.NodeConfig{
+				PodCIDR:                podCIDR,
+				GatewayConfig:          &config.GatewayConfig{IP: net.ParseIP("10.0.0.1"), MAC: gwMAC},
+				UnsupportedOVSFeatures: tc.unsupportedFeatures,
+			}
+			_, err := ofClient.Initialize(types.RoundInfo{RoundNum: 1}, nodeConfig, config.TrafficEncapModeEncap, config.HostGatewayOFPort)
+			require.NoError(t, err)
+			require.NoError(t, ofClient.InstallPolicyRuleFlows(rule))
+
+			var dropFlows []ofconfig.FakeFlow
+			for _, f := range bridge.Flows() {
+				if f.TableID == IngressDefaultTable && f.Priority == priorityNormal {
+					dropFlows = append(dropFlows, f)
+				}
+			}
+			require.Len(t, dropFlows, 1)
+			assert.Equal(t, tc.expectedMeterID, dropFlows[0].PacketInMeterID)
+		})
+	}
+}
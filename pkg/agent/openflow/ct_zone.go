@@ -0,0 +1,52 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+// CtZoneFeature is a feature of the pipeline which commits connections to conntrack.
+type CtZoneFeature string
+
+const (
+	// CtZoneFeaturePipeline tracks all the connections of the pipeline. NetworkPolicy enforcement and the Service
+	// DNAT of AntreaProxy share its zone, as the NetworkPolicy tables rely on the state, mark and label of the
+	// conntrack entry which holds the DNAT state of the connection.
+	CtZoneFeaturePipeline CtZoneFeature = "Pipeline"
+	// CtZoneFeatureSNAT tracks the connections to external addresses which are SNAT'd by OVS with the Egress IPs or
+	// the Node IP, as on Windows. The connections are also committed to the zone of CtZoneFeaturePipeline with their
+	// original addresses, so that the SNAT state doesn't pollute the state used by the other features.
+	CtZoneFeatureSNAT CtZoneFeature = "SNAT"
+)
+
+// ctZones is the allocation of the conntrack zones of the pipeline per feature. The zones must not be changed once
+// released, as the conntrack entries committed by the previous version of the Agent would be orphaned by an upgrade.
+var ctZones = map[CtZoneFeature]uint16{
+	CtZoneFeaturePipeline: CtZone,
+	CtZoneFeatureSNAT:     SNATCtZone,
+}
+
+// GetCtZone returns the conntrack zone allocated to the feature.
+func GetCtZone(feature CtZoneFeature) uint16 {
+	return ctZones[feature]
+}
+
+// GetCtZoneFeature returns the feature the conntrack zone is allocated to, and false if the zone is not allocated to
+// any feature of the pipeline, like the default zone of the host.
+func GetCtZoneFeature(zone uint16) (CtZoneFeature, bool) {
+	for feature, featureZone := range ctZones {
+		if zone == featureZone {
+			return feature, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCtZones(t *testing.T) {
+	assert.Equal(t, uint16(CtZone), GetCtZone(CtZoneFeaturePipeline))
+	assert.Equal(t, uint16(SNATCtZone), GetCtZone(CtZoneFeatureSNAT))
+
+	// Each zone is allocated to a single feature, and none of them is the default zone of the host.
+	allocated := map[uint16]bool{0: true}
+	for feature, zone := range ctZones {
+		assert.False(t, allocated[zone], "zone %d is allocated twice", zone)
+		allocated[zone] = true
+		zoneFeature, ok := GetCtZoneFeature(zone)
+		assert.True(t, ok)
+		assert.Equal(t, feature, zoneFeature)
+	}
+	_, ok := GetCtZoneFeature(0)
+	assert.False(t, ok)
+}
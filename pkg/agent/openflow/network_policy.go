@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/klog"
 
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/types"
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
@@ -39,6 +40,9 @@ const (
 	MatchTCPDstPort
 	MatchUDPDstPort
 	MatchSCTPDstPort
+	MatchICMP
+	MatchIGMP
+	MatchIPProtocol
 	Unsupported
 )
 
@@ -454,8 +458,14 @@ type policyRuleConjunction struct {
 	actionFlows   []binding.Flow
 	metricFlows   []binding.Flow
 	// NetworkPolicy reference information for debugging usage.
-	npRef       *v1beta1.NetworkPolicyReference
-	ruleTableID binding.TableIDType
+	npRef *v1beta1.NetworkPolicyReference
+	// Direction and priority of the rule within the NetworkPolicy, used to report the statistics of the rule.
+	direction    v1beta1.Direction
+	rulePriority int32
+	ruleTableID  binding.TableIDType
+	// rateLimit is the rate limit of the new connections matched by the rule, enforced by the meter with the ID of
+	// the policyRuleConjunction. nil if the rule is not rate limited.
+	rateLimit *v1beta1.RuleRateLimit
 }
 
 // clause groups conjunctive match flows. Matches in a clause represent source addresses(for fromClause), or destination
@@ -554,17 +564,63 @@ func getServiceMatchType(protocol *v1beta1.Protocol) int {
 		return MatchUDPDstPort
 	case v1beta1.ProtocolSCTP:
 		return MatchSCTPDstPort
+	case v1beta1.ProtocolICMP:
+		return MatchICMP
+	case v1beta1.ProtocolIGMP:
+		return MatchIGMP
+	case v1beta1.ProtocolIP:
+		return MatchIPProtocol
 	default:
 		return MatchTCPDstPort
 	}
 }
 
+// icmpMatchValue is the match value of MatchICMP. A nil ICMP type or code
+// matches any value.
+type icmpMatchValue struct {
+	icmpType *uint8
+	icmpCode *uint8
+}
+
+func (v icmpMatchValue) String() string {
+	repr := "icmp"
+	if v.icmpType != nil {
+		repr += fmt.Sprintf(",type:%d", *v.icmpType)
+	}
+	if v.icmpCode != nil {
+		repr += fmt.Sprintf(",code:%d", *v.icmpCode)
+	}
+	return repr
+}
+
 func (c *clause) generateServicePortConjMatch(port v1beta1.Service, priority *uint16) *conjunctiveMatch {
 	matchKey := getServiceMatchType(port.Protocol)
-	// Match all ports with the given protocol type if the matchValue is not specified (value is 0).
-	matchValue := uint16(0)
-	if port.Port != nil {
-		matchValue = uint16(port.Port.IntVal)
+	var matchValue interface{}
+	switch matchKey {
+	case MatchICMP:
+		icmpValue := icmpMatchValue{}
+		if port.ICMPType != nil {
+			icmpType := uint8(*port.ICMPType)
+			icmpValue.icmpType = &icmpType
+		}
+		if port.ICMPCode != nil {
+			icmpCode := uint8(*port.ICMPCode)
+			icmpValue.icmpCode = &icmpCode
+		}
+		matchValue = icmpValue
+	case MatchIGMP:
+		// IGMP has no port, all IGMP messages are matched.
+		matchValue = uint16(0)
+	case MatchIPProtocol:
+		// The IP protocol number is validated by the Antrea Controller.
+		matchValue = uint8(*port.IPProtocol)
+	default:
+		// Match all ports with the given protocol type if the matchValue is not specified (value is 0).
+		portValue := uint16(0)
+		if port.Port != nil {
+			portValue = uint16(port.Port.IntVal)
+		}
+		matchValue = portValue
 	}
 	match := &conjunctiveMatch{
 		tableID:    c.ruleTable.GetID(),
@@ -724,6 +780,9 @@ func (c *client) InstallPolicyRuleFlows(rule *types.PolicyRule) error {
 	defer c.conjMatchFlowLock.Unlock()
 	ctxChanges := c.calculateMatchFlowChangesForRule(conj, rule, false)
 
+	if err := c.addMeter(conj); err != nil {
+		return err
+	}
 	if err := c.ofEntryOperations.AddAll(conj.metricFlows); err != nil {
 		return err
 	}
@@ -748,8 +807,10 @@ func (c *client) calculateActionFlowChangesForRule(rule *types.PolicyRule) *poli
 		return nil
 	}
 	conj = &policyRuleConjunction{
-		id:    ruleID,
-		npRef: rule.PolicyRef,
+		id:           ruleID,
+		npRef:        rule.PolicyRef,
+		direction:    rule.Direction,
+		rulePriority: rule.RulePriority,
 	}
 	nClause, ruleTable, dropTable := conj.calculateClauses(rule, c)
 	conj.ruleTableID = rule.TableID
@@ -765,10 +826,22 @@ func (c *client) calculateActionFlowChangesForRule(rule *types.PolicyRule) *poli
 		var metricFlows []binding.Flow
 		if rule.IsAntreaNetworkPolicyRule() && *rule.Action == secv1alpha1.RuleActionDrop {
 			metricFlows = append(metricFlows, c.dropRuleMetricFlow(ruleID, isIngress))
-			actionFlows = append(actionFlows, c.conjunctionActionDropFlow(ruleID, ruleTable.GetID(), rule.Priority))
+			actionFlows = append(actionFlows, c.conjunctionActionDropFlow(ruleID, ruleTable.GetID(), rule.Priority, rule.EnableLogging))
+		} else if rule.IsAntreaNetworkPolicyRule() && *rule.Action == secv1alpha1.RuleActionAudit {
+			// The metrics of Audit rules are collected from their action flows.
+			actionFlows = append(actionFlows, c.conjunctionActionAuditFlow(ruleID, ruleTable.GetID(), rule.Priority, rule.EnableLogging))
 		} else {
+			var meterID uint32
+			if rule.RateLimit != nil {
+				if c.nodeConfig.OVSFeatureSupported(config.OVSFeatureMeters) {
+					conj.rateLimit = rule.RateLimit
+					meterID = ruleID
+				} else {
+					klog.Warningf("Ignoring the rate limit of rule %d as OVS meters are not supported", ruleID)
+				}
+			}
 			metricFlows = append(metricFlows, c.allowRulesMetricFlows(ruleID, isIngress)...)
-			actionFlows = append(actionFlows, c.conjunctionActionFlow(ruleID, ruleTable.GetID(), dropTable.GetNext(), rule.Priority))
+			actionFlows = append(actionFlows, c.conjunctionActionFlow(ruleID, ruleTable.GetID(), dropTable.GetNext(), rule.Priority, rule.EnableLogging, meterID))
 		}
 		conj.actionFlows = actionFlows
 		conj.metricFlows = metricFlows
@@ -776,6 +849,17 @@ func (c *client) calculateActionFlowChangesForRule(rule *types.PolicyRule) *poli
 	return conj
 }
 
+// addMeter adds the meter enforcing the rate limit of the policyRuleConjunction if it has one.
+func (c *client) addMeter(conj *policyRuleConjunction) error {
+	if conj.rateLimit == nil {
+		return nil
+	}
+	if err := c.bridge.AddMeter(conj.id, uint32(conj.rateLimit.MaxNewConnectionsPerSecond), uint32(conj.rateLimit.Burst)); err != nil {
+		return fmt.Errorf("failed to add meter %d: %v", conj.id, err)
+	}
+	return nil
+}
+
 // calculateMatchFlowChangesForRule calculates the contextChanges for the policyRule, and updates the context status in case of batch install.
 func (c *client) calculateMatchFlowChangesForRule(conj *policyRuleConjunction, rule *types.PolicyRule, isBatchInstall bool) []*conjMatchFlowContextChange {
 	// Calculate the conjMatchFlowContext changes. The changed Openflow entries are included in the conjMatchFlowContext change.
@@ -804,6 +888,9 @@ func (c *client) BatchInstallPolicyRuleFlows(ofPolicyRules []*types.PolicyRule)
 	for _, rule := range ofPolicyRules {
 		conj := c.calculateActionFlowChangesForRule(rule)
 		ctxChanges := c.calculateMatchFlowChangesForRule(conj, rule, true)
+		if err := c.addMeter(conj); err != nil {
+			return err
+		}
 		allFlows = append(allFlows, conj.actionFlows...)
 		allFlows = append(allFlows, conj.metricFlows...)
 		allCtxChanges = append(allCtxChanges, ctxChanges...)
@@ -1015,6 +1102,14 @@ func (c *client) GetPolicyFromConjunction(ruleID uint32) *v1beta1.NetworkPolicyR
 	return conjunction.npRef
 }
 
+func (c *client) GetRuleFromConjunction(ruleID uint32) (v1beta1.Direction, int32, bool) {
+	conjunction := c.getPolicyRuleConjunction(ruleID)
+	if conjunction == nil {
+		return "", 0, false
+	}
+	return conjunction.direction, conjunction.rulePriority, true
+}
+
 // UninstallPolicyRuleFlows removes the Openflow entry relevant to the specified NetworkPolicy rule.
 // It also returns a slice of stale ofPriorities used by ClusterNetworkPolicies.
 // UninstallPolicyRuleFlows will do nothing if no Openflow entry for the rule is installed.
@@ -1035,6 +1130,11 @@ func (c *client) UninstallPolicyRuleFlows(ruleID uint32) ([]string, error) {
 	if err := c.ofEntryOperations.DeleteAll(conj.metricFlows); err != nil {
 		return nil, err
 	}
+	if conj.rateLimit != nil {
+		if err := c.bridge.DeleteMeter(conj.id); err != nil {
+			return nil, err
+		}
+	}
 
 	c.conjMatchFlowLock.Lock()
 	defer c.conjMatchFlowLock.Unlock()
@@ -1094,6 +1194,10 @@ func (c *client) replayPolicyFlows() {
 	}
 
 	for _, conj := range c.policyCache.List() {
+		// The meters must be added before the flows using them.
+		if err := c.addMeter(conj.(*policyRuleConjunction)); err != nil {
+			klog.Errorf("Error when replaying meter: %v", err)
+		}
 		addActionFlows(conj.(*policyRuleConjunction))
 		addMetricFlows(conj.(*policyRuleConjunction))
 	}
@@ -1254,6 +1358,8 @@ func (c *client) updateConjunctionActionFlows(conj *policyRuleConjunction, updat
 		serviceClause: conj.serviceClause,
 		actionFlows:   newActionFlows,
 		npRef:         conj.npRef,
+		direction:     conj.direction,
+		rulePriority:  conj.rulePriority,
 		ruleTableID:   conj.ruleTableID,
 	}
 	return newConj
@@ -1369,6 +1475,30 @@ func parseAllowFlow(flow string) (uint32, types.RuleMetric) {
 	return uint32(id), m
 }
 
+// auditFlowIdentifier is the match of the action flows of Audit rules on the audit mark, in the format of ovs-ofctl.
+var auditFlowIdentifier = fmt.Sprintf("reg%d=0/%#x", marksReg, uint32(1)<<auditMarkRange[0])
+
+func parseAuditFlow(flow string) (uint32, types.RuleMetric) {
+	// example format:
+	// table=45, n_packets=2, n_bytes=148, priority=64990,conj_id=5,ip,reg0=0/0x200000 actions=load:0x5->NXM_NX_REG3[],...
+	m := types.RuleMetric{}
+	var id uint64
+	for _, seg := range strings.Split(strings.Split(flow, " actions=")[0], ",") {
+		seg = strings.TrimSpace(seg)
+		switch {
+		case strings.HasPrefix(seg, "n_packets="):
+			m.Packets, _ = strconv.ParseUint(strings.TrimPrefix(seg, "n_packets="), 10, 64)
+			// The action flow only matches the first packet of a connection.
+			m.Sessions = m.Packets
+		case strings.HasPrefix(seg, "n_bytes="):
+			m.Bytes, _ = strconv.ParseUint(strings.TrimPrefix(seg, "n_bytes="), 10, 64)
+		case strings.HasPrefix(seg, "conj_id="):
+			id, _ = strconv.ParseUint(strings.TrimPrefix(seg, "conj_id="), 10, 32)
+		}
+	}
+	return uint32(id), m
+}
+
 func parseMetricFlow(flow string) (uint32, types.RuleMetric) {
 	dropIdentifier := "reg0"
 	if strings.Contains(flow, dropIdentifier) {
@@ -1405,5 +1535,16 @@ func (c *client) NetworkPolicyMetrics() map[uint32]*types.RuleMetric {
 	// flows to get the correct number of total packets.
 	collectMetricsFromFlows(egressFlows)
 	collectMetricsFromFlows(ingressFlows)
+	// The metrics of Audit rules are collected from their action flows in the tables of Antrea-native policy rules.
+	for _, tableID := range []binding.TableIDType{MultiTierEgressRuleTable, DefaultTierEgressRuleTable, MultiTierIngressRuleTable, DefaultTierIngressRuleTable} {
+		flows, _ := ovsctlClient.DumpTableFlows(uint8(tableID))
+		for _, flow := range flows {
+			if !strings.Contains(flow, auditFlowIdentifier) {
+				continue
+			}
+			ruleID, metric := parseAuditFlow(flow)
+			result[ruleID] = &metric
+		}
+	}
 	return result
 }
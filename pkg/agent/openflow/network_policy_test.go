@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow/cookie"
 	oftest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
 	"github.com/vmware-tanzu/antrea/pkg/agent/types"
@@ -272,6 +273,49 @@ func TestInstallPolicyRuleFlows(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestInstallAuditPolicyRuleFlows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	auditAction := secv1alpha1.RuleActionAudit
+	priority := uint16(64990)
+	ruleID := uint32(111)
+	rule := &types.PolicyRule{
+		Direction:     v1beta1.DirectionOut,
+		From:          parseAddresses([]string{"192.168.1.40"}),
+		To:            parseAddresses([]string{"10.0.0.0/8"}),
+		Action:        &auditAction,
+		EnableLogging: true,
+		Priority:      &priority,
+		FlowID:        ruleID,
+		TableID:       DefaultTierEgressRuleTable,
+		PolicyRef: &v1beta1.NetworkPolicyReference{
+			Type: v1beta1.AntreaClusterNetworkPolicy,
+			Name: "acnp1",
+			UID:  "id1",
+		},
+	}
+
+	cnpOutTable.EXPECT().BuildFlow(gomock.Any()).Return(newMockRuleFlowBuilder(ctrl)).AnyTimes()
+	// The Audit action flow resubmits the packets to the same table after sending them to the Agent through the
+	// packet-in meter, which doesn't apply to the resubmitted packets.
+	ruleAction.EXPECT().Meter(gomock.Any()).Times(0)
+	ruleAction.EXPECT().SendToControllerWithMeter(uint8(ofprAction), packetInMeterIDNP).Return(ruleFlowBuilder).Times(1)
+	ruleAction.EXPECT().ResubmitToTable(DefaultTierEgressRuleTable).Return(ruleFlowBuilder).Times(2)
+	conj := c.calculateActionFlowChangesForRule(rule)
+	require.NotNil(t, conj)
+	assert.Equal(t, 1, len(conj.actionFlows))
+	assert.Empty(t, conj.metricFlows)
+
+	// Without logging, the packets are only counted and not sent to the Agent.
+	rule.EnableLogging = false
+	rule.FlowID = ruleID + 1
+	conj = c.calculateActionFlowChangesForRule(rule)
+	require.NotNil(t, conj)
+	assert.Equal(t, 1, len(conj.actionFlows))
+}
+
 func TestBatchInstallPolicyRuleFlows(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -485,6 +529,69 @@ func expectConjunctionsCount(conjs []*expectConjunctionTimes) {
 	}
 }
 
+func TestGenerateServicePortConjMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	conj := &policyRuleConjunction{id: 21}
+	clause := conj.newClause(3, 3, outTable, outDropTable)
+
+	protocolICMP := v1beta1.ProtocolICMP
+	protocolIGMP := v1beta1.ProtocolIGMP
+	protocolIP := v1beta1.ProtocolIP
+	ipProtocolVRRP := int32(112)
+	icmpType := int32(8)
+	icmpCode := int32(0)
+	matchICMPType := uint8(8)
+	matchICMPCode := uint8(0)
+	tests := []struct {
+		name          string
+		service       v1beta1.Service
+		expectedKey   int
+		expectedValue interface{}
+		expectedStr   string
+	}{
+		{
+			name:          "icmp-any",
+			service:       v1beta1.Service{Protocol: &protocolICMP},
+			expectedKey:   MatchICMP,
+			expectedValue: icmpMatchValue{},
+			expectedStr:   "icmp",
+		},
+		{
+			name:          "icmp-type-code",
+			service:       v1beta1.Service{Protocol: &protocolICMP, ICMPType: &icmpType, ICMPCode: &icmpCode},
+			expectedKey:   MatchICMP,
+			expectedValue: icmpMatchValue{icmpType: &matchICMPType, icmpCode: &matchICMPCode},
+			expectedStr:   "icmp,type:8,code:0",
+		},
+		{
+			name:          "igmp",
+			service:       v1beta1.Service{Protocol: &protocolIGMP},
+			expectedKey:   MatchIGMP,
+			expectedValue: uint16(0),
+		},
+		{
+			name:          "ip-protocol",
+			service:       v1beta1.Service{Protocol: &protocolIP, IPProtocol: &ipProtocolVRRP},
+			expectedKey:   MatchIPProtocol,
+			expectedValue: uint8(112),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := clause.generateServicePortConjMatch(tt.service, nil)
+			assert.Equal(t, tt.expectedKey, match.matchKey)
+			assert.Equal(t, tt.expectedValue, match.matchValue)
+			if tt.expectedStr != "" {
+				expectedMatchKey := fmt.Sprintf("table:%d,priority:%s,type:%d,value:%s", EgressRuleTable, strconv.Itoa(int(priorityNormal)), tt.expectedKey, tt.expectedStr)
+				assert.Equal(t, expectedMatchKey, match.generateGlobalMapKey())
+			}
+		})
+	}
+}
+
 func newMockDropFlowBuilder(ctrl *gomock.Controller) *mocks.MockFlowBuilder {
 	dropFlowBuilder = mocks.NewMockFlowBuilder(ctrl)
 	dropFlowBuilder.EXPECT().Cookie(gomock.Any()).Return(dropFlowBuilder).AnyTimes()
@@ -604,6 +711,7 @@ func prepareClient(ctrl *gomock.Controller) *client {
 		policyCache:              policyCache,
 		globalConjMatchFlowCache: map[string]*conjMatchFlowContext{},
 		bridge:                   bridge,
+		nodeConfig:               &config.NodeConfig{},
 	}
 	c.cookieAllocator = cookie.NewAllocator(0)
 	m := oftest.NewMockOFEntryOperations(ctrl)
@@ -656,3 +764,11 @@ func TestParseMetricFlow(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAuditFlow(t *testing.T) {
+	flow := "table=45, n_packets=2, n_bytes=148, priority=64990,conj_id=5,ip,reg0=0/0x200000 actions=load:0x5->NXM_NX_REG3[],load:0x1->NXM_NX_REG0[21],CONTROLLER:65535,resubmit(,45)"
+	require.Contains(t, flow, auditFlowIdentifier)
+	rule, metric := parseAuditFlow(flow)
+	assert.Equal(t, uint32(5), rule)
+	assert.Equal(t, types.RuleMetric{Bytes: 148, Packets: 2, Sessions: 2}, metric)
+}
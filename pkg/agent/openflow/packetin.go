@@ -15,9 +15,15 @@
 package openflow
 
 import (
+	"fmt"
+
 	"github.com/contiv/ofnet/ofctrl"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 )
 
 type ofpPacketInReason uint
@@ -33,10 +39,117 @@ const (
 	packetInQueueSize int = 256
 )
 
+// The packet-in meters limit the rate of the packet-in messages sent to the Antrea Agent by the flows of each feature,
+// so that a flood of packets matching these flows cannot saturate the Agent and the packetInQueue shared by all the
+// features. Their IDs are reserved at the top of the meter ID range, as the meters enforcing the rate limits of the
+// policy rules use the conjunction IDs of the rules, which are allocated from 1.
+const (
+	// packetInMeterIDNP is the ID of the meter of the packet-in messages sent for the audit logging of NetworkPolicy
+	// rules.
+	packetInMeterIDNP uint32 = 0xffff0000
+	// packetInMeterIDDenyFlow is the ID of the meter of the packet-in messages sent for the export of the connections
+	// denied by NetworkPolicies.
+	packetInMeterIDDenyFlow uint32 = 0xfffeffff
+	// packetInMeterIDDNS is the ID of the meter of the packet-in messages sent for the DNS responses used to resolve
+	// the FQDNs of Antrea-native policy rules.
+	packetInMeterIDDNS uint32 = 0xfffefffe
+	// packetInMeterIDDHCP is the ID of the meter of the packet-in messages sent for the DHCP requests of the Pods whose
+	// IPs are allocated by Antrea IPAM.
+	packetInMeterIDDHCP uint32 = 0xfffefffd
+	// packetInMeterRate is the rate of the packet-in meters, in packets per second.
+	packetInMeterRate uint32 = 500
+	// packetInMeterBurst is the burst size of the packet-in meters, in packets.
+	packetInMeterBurst uint32 = 500
+)
+
+// packetInMeterIDs are the IDs of the packet-in meters added when OVS supports meters.
+var packetInMeterIDs = []uint32{packetInMeterIDNP, packetInMeterIDDenyFlow, packetInMeterIDDNS, packetInMeterIDDHCP}
+
+// policyRuleTables are the tables in which the conjunction action flows of NetworkPolicy rules are installed.
+var policyRuleTables = map[binding.TableIDType]bool{
+	MultiTierEgressRuleTable:    true,
+	DefaultTierEgressRuleTable:  true,
+	EgressRuleTable:             true,
+	MultiTierIngressRuleTable:   true,
+	DefaultTierIngressRuleTable: true,
+	IngressRuleTable:            true,
+}
+
+// GetPolicyRuleFromPacketIn returns the conjunction ID of the NetworkPolicy rule which sent the packet-in message for
+// audit logging, and the action of the rule. found is false if the packet-in message was not sent by the action flow of
+// a rule, e.g. if it was sent for Traceflow.
+func GetPolicyRuleFromPacketIn(pktIn *ofctrl.PacketIn) (conjID uint32, action secv1alpha1.RuleAction, found bool) {
+	tableID := binding.TableIDType(pktIn.TableId)
+	if !policyRuleTables[tableID] {
+		return 0, "", false
+	}
+	matchers := pktIn.GetMatches()
+	if matchers.GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", TraceflowReg)) != nil {
+		return 0, "", false
+	}
+	getRegValue := func(reg regType, rng binding.Range) (uint32, bool) {
+		match := matchers.GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", reg))
+		if match == nil {
+			return 0, false
+		}
+		value, ok := match.GetValue().(*ofctrl.NXRegister)
+		if !ok {
+			return 0, false
+		}
+		return ofctrl.GetUint32ValueWithRange(value.Data, rng.ToNXRange()), true
+	}
+	if mark, ok := getRegValue(marksReg, cnpDropMarkRange); ok && mark == cnpDropMark {
+		conjID, found = getRegValue(cnpDropConjunctionIDReg, binding.Range{0, 31})
+		return conjID, secv1alpha1.RuleActionDrop, found
+	}
+	// The audit mark is cleared by the allow action flows before sending the packet-in message.
+	if mark, ok := getRegValue(marksReg, auditMarkRange); ok && mark == auditMark {
+		conjID, found = getRegValue(cnpDropConjunctionIDReg, binding.Range{0, 31})
+		return conjID, secv1alpha1.RuleActionAudit, found
+	}
+	conjReg := IngressReg
+	if IsEgressRuleTable(tableID) {
+		conjReg = EgressReg
+	}
+	conjID, found = getRegValue(conjReg, binding.Range{0, 31})
+	return conjID, secv1alpha1.RuleActionAllow, found
+}
+
+// IsEgressRuleTable returns true if the table is one of the tables in which the egress NetworkPolicy rules are
+// enforced.
+func IsEgressRuleTable(tableID binding.TableIDType) bool {
+	_, ok := egressTables[tableID]
+	return ok
+}
+
+// addPacketInMeters adds the packet-in meters if OVS supports meters. The meters must be added before the flows using
+// them.
+func (c *client) addPacketInMeters() error {
+	if !c.nodeConfig.OVSFeatureSupported(config.OVSFeatureMeters) {
+		return nil
+	}
+	for _, meterID := range packetInMeterIDs {
+		if err := c.bridge.AddMeter(meterID, packetInMeterRate, packetInMeterBurst); err != nil {
+			return fmt.Errorf("failed to add packet-in meter %d: %v", meterID, err)
+		}
+	}
+	return nil
+}
+
+// sendPacketIn sends the packets matching the flow to the Antrea Agent through the packet-in meter with the specified
+// ID, if OVS supports meters. The meter only applies to the packet-in messages: the packets above its rate are not sent
+// to the Agent, but they are still processed by the other actions of the flow.
+func (c *client) sendPacketIn(fb binding.FlowBuilder, meterID uint32) binding.FlowBuilder {
+	if !c.nodeConfig.OVSFeatureSupported(config.OVSFeatureMeters) {
+		return fb.Action().SendToController(uint8(ofprAction))
+	}
+	return fb.Action().SendToControllerWithMeter(uint8(ofprAction), meterID)
+}
+
 func (c *client) RegisterPacketInHandler(packetHandlerName string, packetInHandler interface{}) {
 	handler, ok := packetInHandler.(PacketInHandler)
 	if !ok {
-		klog.Errorf("Invalid Traceflow controller.")
+		klog.Errorf("Invalid PacketIn handler %s.", packetHandlerName)
 		return
 	}
 	c.packetInHandlers[packetHandlerName] = handler
@@ -29,7 +29,6 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow/cookie"
 	"github.com/vmware-tanzu/antrea/pkg/agent/types"
-	"github.com/vmware-tanzu/antrea/pkg/features"
 	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	"github.com/vmware-tanzu/antrea/third_party/proxy"
 )
@@ -38,8 +37,9 @@ const (
 	// Flow table id index
 	ClassifierTable             binding.TableIDType = 0
 	uplinkTable                 binding.TableIDType = 5
-	spoofGuardTable             binding.TableIDType = 10
+	SpoofGuardTable             binding.TableIDType = 10
 	arpResponderTable           binding.TableIDType = 20
+	snatConntrackTable          binding.TableIDType = 25
 	serviceHairpinTable         binding.TableIDType = 29
 	conntrackTable              binding.TableIDType = 30
 	conntrackStateTable         binding.TableIDType = 31
@@ -61,6 +61,7 @@ const (
 	IngressMetricTable          binding.TableIDType = 101
 	conntrackCommitTable        binding.TableIDType = 105
 	hairpinSNATTable            binding.TableIDType = 106
+	SNATConntrackCommitTable    binding.TableIDType = 107
 	L2ForwardingOutTable        binding.TableIDType = 110
 
 	// Flow priority level
@@ -97,8 +98,9 @@ var (
 	}{
 		{ClassifierTable, "Classification"},
 		{uplinkTable, "Uplink"},
-		{spoofGuardTable, "SpoofGuard"},
+		{SpoofGuardTable, "SpoofGuard"},
 		{arpResponderTable, "ARPResponder"},
+		{snatConntrackTable, "SNATConntrackZone"},
 		{serviceHairpinTable, "ServiceHairpin"},
 		{conntrackTable, "ConntrackZone"},
 		{conntrackStateTable, "ConntrackState"},
@@ -120,6 +122,7 @@ var (
 		{IngressMetricTable, "IngressMetric"},
 		{conntrackCommitTable, "ConntrackCommit"},
 		{hairpinSNATTable, "HairpinSNATTable"},
+		{SNATConntrackCommitTable, "SNATConntrackCommit"},
 		{L2ForwardingOutTable, "Output"},
 	}
 )
@@ -202,7 +205,8 @@ const (
 	IngressReg      regType = 6
 	TraceflowReg    regType = 9 // Use reg9[28..31] to store traceflow dataplaneTag.
 	// cnpDropConjunctionIDReg reuses reg3 which will also be used for storing endpoint IP to store the rule ID. Since
-	// the service selection will finish when a packet hitting NetworkPolicy related rules, there is no conflict.
+	// the service selection will finish when a packet hitting NetworkPolicy related rules, there is no conflict. It
+	// also stores the ID of the Audit rules, which is only used with the audit mark.
 	cnpDropConjunctionIDReg regType = 3
 	// marksRegServiceNeedLB indicates a packet need to do service selection.
 	marksRegServiceNeedLB uint32 = 0b001
@@ -212,17 +216,30 @@ const (
 	// the selection result needs to be cached.
 	marksRegServiceNeedLearn uint32 = 0b011
 
-	CtZone = 0xfff0
+	// Conntrack zones allocated to the features of the pipeline, see ctZones.
+	CtZone     = 0xfff0
+	SNATCtZone = 0xfff1
 
 	portFoundMark    = 0b1
 	snatRequiredMark = 0b1
 	hairpinMark      = 0b1
 	macRewriteMark   = 0b1
 	cnpDropMark      = 0b1
+	auditMark        = 0b1
+	dsrMark          = 0b1
 
 	gatewayCTMark = 0x20
 	snatCTMark    = 0x40
-	serviceCTMark = 0x21
+	// ServiceCTMark is the ct_mark of the connections whose destination is a Service, which are DNAT'd to the
+	// selected Endpoint by AntreaProxy.
+	ServiceCTMark = 0x21
+
+	// dnsPort is the transport source port of the DNS responses snooped for FQDN policy rules.
+	dnsPort uint16 = 53
+	// dhcpServerPort and dhcpClientPort are the transport ports of the DHCP requests answered by the Antrea Agent for
+	// the Pods whose IPs are allocated by Antrea IPAM.
+	dhcpServerPort uint16 = 67
+	dhcpClientPort uint16 = 68
 )
 
 var (
@@ -243,6 +260,15 @@ var (
 	// if the packet's MAC addresses need to be rewritten. Its value is 0x1 if yes.
 	macRewriteMarkRange = binding.Range{19, 19}
 	cnpDropMarkRange    = binding.Range{20, 20}
+	// auditMarkRange takes the 21st bit of register marksReg to indicate if
+	// the packet has been matched by the action flow of an Audit rule. Its
+	// value is 0x1 if yes.
+	auditMarkRange = binding.Range{21, 21}
+	// dsrMarkRange takes the 22nd bit of register marksReg to indicate if the
+	// packet is LoadBalancer Service traffic from outside the cluster, which
+	// is forwarded to the remote Endpoints without DNAT in DSR mode. Its value
+	// is 0x1 if yes.
+	dsrMarkRange = binding.Range{22, 22}
 	// endpointIPRegRange takes a 32-bit range of register endpointIPReg to store
 	// the selected Service Endpoint IP.
 	endpointIPRegRange = binding.Range{0, 31}
@@ -282,14 +308,27 @@ type flowCategoryCache struct {
 type client struct {
 	enableProxy                                   bool
 	enableAntreaPolicy                            bool
+	enableDenyFlowExport                          bool
 	roundInfo                                     types.RoundInfo
 	cookieAllocator                               cookie.Allocator
 	bridge                                        binding.Bridge
 	pipeline                                      map[binding.TableIDType]binding.Table
 	nodeFlowCache, podFlowCache, serviceFlowCache *flowCategoryCache // cache for corresponding deletions
+	// snatFlowCache caches the flows forwarding the traffic of the local Pods selected by Egresses to the Egress
+	// Nodes. The key is the Pod IP.
+	snatFlowCache *flowCategoryCache
+	// snatIPFlowCache caches the flows SNATing the traffic of the Pods selected by the Egresses assigned to this Node
+	// with the Egress IPs on Windows. The key is the Pod IP.
+	snatIPFlowCache *flowCategoryCache
 	// "fixed" flows installed by the agent after initialization and which do not change during
 	// the lifetime of the client.
 	gatewayFlows, defaultServiceFlows, defaultTunnelFlows, hostNetworkingFlows []binding.Flow
+	// dnsResponseFlows are installed on demand, when the first Antrea-native policy rule with FQDNs is realized.
+	dnsResponseFlows []binding.Flow
+	// dhcpRequestFlows are installed on demand, when the DHCP responder for the Antrea IPAM Pods is enabled.
+	dhcpRequestFlows []binding.Flow
+	// egressTunnelFlows are installed on demand, when the Egress feature is enabled.
+	egressTunnelFlows []binding.Flow
 	// ofEntryOperations is a wrapper interface for OpenFlow entry Add / Modify / Delete operations. It
 	// enables convenient mocking in unit tests.
 	ofEntryOperations OFEntryOperations
@@ -308,6 +347,10 @@ type client struct {
 	gatewayPort uint32 // OVSOFPort number
 	// packetInHandlers stores handler to process PacketIn event
 	packetInHandlers map[string]PacketInHandler
+	// tunMetadataMapped indicates whether the Geneve option used to carry the
+	// Traceflow tag is mapped to tun_metadata0, in which case the tag is
+	// restored from the tunneled packets.
+	tunMetadataMapped bool
 }
 
 func (c *client) GetTunnelVirtualMAC() net.HardwareAddr {
@@ -437,7 +480,7 @@ func (c *client) defaultFlows() (flows []binding.Flow) {
 func (c *client) tunnelClassifierFlow(tunnelOFPort uint32, category cookie.Category) binding.Flow {
 	flowBuilder := c.pipeline[ClassifierTable].BuildFlow(priorityNormal).
 		MatchInPort(tunnelOFPort)
-	if features.DefaultFeatureGate.Enabled(features.Traceflow) {
+	if c.tunMetadataMapped {
 		regName := fmt.Sprintf("%s%d", binding.NxmFieldReg, TraceflowReg)
 		tunMetadataName := fmt.Sprintf("%s%d", binding.NxmFieldTunMetadata, 0)
 		flowBuilder = flowBuilder.Action().MoveRange(tunMetadataName, regName, OfTraceflowMarkRange, OfTraceflowMarkRange)
@@ -519,9 +562,12 @@ func (c *client) connectionTrackFlows(category cookie.Category) []binding.Flow {
 				Action().CT(false, connectionTrackTable.GetNext(), CtZone).NAT().CTDone().
 				Cookie(c.cookieAllocator.Request(category).Raw()).
 				Done(),
-			connectionTrackCommitTable.BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
+			// The Service connections have been committed with ServiceCTMark by the endpointDNATTable. They must not
+			// be committed again with gatewayCTMark when they are sent from the host gateway, e.g. the NodePort
+			// traffic, so that the following packets keep being forwarded to the selected Endpoint.
+			connectionTrackCommitTable.BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolIP).
 				MatchCTStateTrk(true).
-				MatchCTMark(serviceCTMark, nil).
+				MatchCTMark(ServiceCTMark, nil).
 				MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
 				Cookie(c.cookieAllocator.Request(category).Raw()).
 				Action().GotoTable(connectionTrackCommitTable.GetNext()).
@@ -600,7 +646,7 @@ func (c *client) ctRewriteDstMACFlow(gatewayMAC net.HardwareAddr, category cooki
 func (c *client) serviceLBBypassFlow() binding.Flow {
 	connectionTrackStateTable := c.pipeline[conntrackStateTable]
 	return connectionTrackStateTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
-		MatchCTMark(serviceCTMark, nil).
+		MatchCTMark(ServiceCTMark, nil).
 		MatchCTStateNew(false).MatchCTStateTrk(true).
 		Action().LoadRegRange(int(marksReg), macRewriteMark, macRewriteMarkRange).
 		Action().GotoTable(EgressRuleTable).
@@ -646,6 +692,38 @@ func (c *client) traceflowL2ForwardOutputFlow(dataplaneTag uint8, category cooki
 		Done()
 }
 
+// dnsResponseFlow generates the flow that outputs the DNS responses destined for local Pods to the OVS port, and
+// sends a copy of them to the Antrea Agent after L2 forwarding calculation. The Agent learns the IP addresses resolved
+// for the FQDNs of Antrea-native policy rules from these packets. Only the replies of connections tracked by conntrack
+// are sent, so that a Pod cannot inject forged responses. The packet-in messages are metered when OVS supports meters,
+// which never drops the responses output to the Pods.
+func (c *client) dnsResponseFlow(category cookie.Category) binding.Flow {
+	fb := c.pipeline[L2ForwardingOutTable].BuildFlow(priorityNormal+1).
+		MatchProtocol(binding.ProtocolUDP).
+		MatchSrcPort(dnsPort, nil).
+		MatchCTStateEst(true).
+		MatchCTStateRpl(true).
+		MatchRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
+		Action().OutputRegRange(int(portCacheReg), ofPortRegRange)
+	return c.sendPacketIn(fb, packetInMeterIDDNS).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
+// dhcpRequestFlow generates the flow that sends the DHCP requests from local Pods to the Antrea Agent instead of
+// forwarding them. It must be matched before the IP spoof guard flows, as the requests of the clients which are not
+// configured yet are sent from 0.0.0.0. The packet-in messages are metered when OVS supports meters.
+func (c *client) dhcpRequestFlow(category cookie.Category) binding.Flow {
+	fb := c.pipeline[SpoofGuardTable].BuildFlow(priorityHigh).
+		MatchProtocol(binding.ProtocolUDP).
+		MatchRegRange(int(marksReg), markTrafficFromLocal, binding.Range{0, 15}).
+		MatchSrcPort(dhcpClientPort, nil).
+		MatchDstPort(dhcpServerPort, nil)
+	return c.sendPacketIn(fb, packetInMeterIDDHCP).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
 // l2ForwardOutputServiceHairpinFlow uses in_port action for Service
 // hairpin packets to avoid packets from being dropped by OVS.
 func (c *client) l2ForwardOutputServiceHairpinFlow() binding.Flow {
@@ -739,6 +817,35 @@ func (c *client) l3FwdFlowToRemote(
 		Done()
 }
 
+// dsrFwdFlowToRemote generates the flow forwarding the LoadBalancer Service
+// traffic marked for DSR to the remote Node of the selected Endpoint, which is
+// matched by the peer subnet. The packets are not DNAT'd nor committed: the
+// remote Node load-balances them to its local Endpoints, and the replies are
+// sent to the clients from there.
+func (c *client) dsrFwdFlowToRemote(
+	localGatewayMAC net.HardwareAddr,
+	peerSubnet net.IPNet,
+	tunnelPeer net.IP,
+	tunOFPort uint32,
+	category cookie.Category) binding.Flow {
+	ones, bits := peerSubnet.Mask.Size()
+	subnetRange := binding.Range{uint32(bits - ones), uint32(bits - 1)}
+	subnetVal := binary.BigEndian.Uint32(peerSubnet.IP.To4()) >> subnetRange[0]
+	return c.pipeline[endpointDNATTable].BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolIP).
+		MatchRegRange(int(marksReg), dsrMark, dsrMarkRange).
+		MatchRegRange(int(serviceLearnReg), marksRegServiceSelected, serviceLearnRegRange).
+		MatchRegRange(int(endpointIPReg), subnetVal, subnetRange).
+		Action().DecTTL().
+		Action().SetSrcMAC(localGatewayMAC).
+		Action().SetDstMAC(globalVirtualMAC).
+		Action().LoadRegRange(int(portCacheReg), tunOFPort, ofPortRegRange).
+		Action().LoadRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
+		Action().SetTunnelDst(tunnelPeer).
+		Action().GotoTable(L2ForwardingOutTable).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
 // l3FwdFlowToRemoteViaGW generates the L3 forward flow on source node to support traffic to remote via gateway.
 func (c *client) l3FwdFlowToRemoteViaGW(
 	localGatewayMAC net.HardwareAddr,
@@ -754,6 +861,76 @@ func (c *client) l3FwdFlowToRemoteViaGW(
 		Done()
 }
 
+// snatFwdFlowToRemote generates the L3 forward flow on the source Node of the traffic from a local Pod selected by an
+// Egress to external addresses, which is sent to the Egress Node through the tunnel. Only the packets in the original
+// direction are matched, the replies of the connections initiated from outside the Pod (e.g. NodePort connections)
+// are still sent back through the local gateway.
+func (c *client) snatFwdFlowToRemote(
+	localGatewayMAC net.HardwareAddr,
+	podIP net.IP,
+	tunnelPeer net.IP,
+	tunOFPort uint32,
+	category cookie.Category) binding.Flow {
+	return c.pipeline[l3ForwardingTable].BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
+		MatchRegRange(int(marksReg), markTrafficFromLocal, binding.Range{0, 15}).
+		MatchCTStateRpl(false).MatchCTStateTrk(true).
+		MatchSrcIP(podIP).
+		Action().DecTTL().
+		Action().SetSrcMAC(localGatewayMAC).
+		Action().SetDstMAC(globalVirtualMAC).
+		Action().LoadRegRange(int(portCacheReg), tunOFPort, ofPortRegRange).
+		Action().LoadRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
+		Action().SetTunnelDst(tunnelPeer).
+		Action().GotoTable(conntrackCommitTable).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
+// l3FwdFlowFromTunnelToGateway generates the L3 forward flow on the Egress Node for the packets of remote Pods which
+// are received from the tunnel and destined to external addresses, rewriting their destination MAC to the local
+// gateway MAC. The flow has a low priority to avoid overlapping with the flows of local Pods and remote Nodes.
+func (c *client) l3FwdFlowFromTunnelToGateway(localGatewayMAC net.HardwareAddr, category cookie.Category) binding.Flow {
+	l3FwdTable := c.pipeline[l3ForwardingTable]
+	return l3FwdTable.BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
+		MatchRegRange(int(marksReg), markTrafficFromTunnel, binding.Range{0, 15}).
+		MatchDstMAC(globalVirtualMAC).
+		Action().SetDstMAC(localGatewayMAC).
+		Action().GotoTable(l3FwdTable.GetNext()).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
+// snatMarkFlowFromTunnel generates the L3 forward flow on a Windows Egress Node for the packets of remote Pods which
+// are received from the tunnel and destined to external addresses. As the traffic of local Pods, they are marked to
+// be SNAT'd by OVS, and sent to the local gateway after SNAT. The flow has a low priority to avoid overlapping with the
+// flows of local Pods and remote Nodes.
+func (c *client) snatMarkFlowFromTunnel(localGatewayMAC net.HardwareAddr, category cookie.Category) binding.Flow {
+	return c.pipeline[l3ForwardingTable].BuildFlow(priorityLow).MatchProtocol(binding.ProtocolIP).
+		MatchRegRange(int(marksReg), markTrafficFromTunnel, binding.Range{0, 15}).
+		MatchDstMAC(globalVirtualMAC).
+		Action().SetDstMAC(localGatewayMAC).
+		Action().LoadRegRange(int(marksReg), snatRequiredMark, snatMarkRange).
+		Action().GotoTable(IngressRuleTable).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
+// snatIPFlow generates the flow on a Windows Egress Node which SNATs the new connections from the Pod IP to external
+// addresses with the Egress IP in the SNAT conntrack zone. It has a higher priority than the flow SNATing the other
+// connections with the Node IP.
+func (c *client) snatIPFlow(podIP net.IP, snatIP net.IP, category cookie.Category) binding.Flow {
+	snatIPRange := &binding.IPRange{StartIP: snatIP, EndIP: snatIP}
+	return c.pipeline[SNATConntrackCommitTable].BuildFlow(priorityHigh).
+		MatchProtocol(binding.ProtocolIP).
+		MatchCTStateNew(true).MatchCTStateTrk(true).
+		MatchRegRange(int(marksReg), snatRequiredMark, snatMarkRange).
+		MatchSrcIP(podIP).
+		Action().CT(true, L2ForwardingOutTable, SNATCtZone).
+		SNAT(snatIPRange, nil).CTDone().
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
 // arpResponderFlow generates the ARP responder flow entry that replies request comes from local gateway for peer
 // gateway MAC.
 func (c *client) arpResponderFlow(peerGatewayIP net.IP, category cookie.Category) binding.Flow {
@@ -772,6 +949,26 @@ func (c *client) arpResponderFlow(peerGatewayIP net.IP, category cookie.Category
 		Done()
 }
 
+// arpResponderPodFlow generates the ARP responder flow entry that replies all the ARP requests from the Pod with the
+// local gateway MAC, so that all the traffic of the Pod is sent to the gateway, including the traffic to the IPs of its
+// subnet. It's used for the Pods whose IPs are allocated by Antrea IPAM from an IPPool.
+func (c *client) arpResponderPodFlow(ofPort uint32, gatewayMAC net.HardwareAddr, category cookie.Category) binding.Flow {
+	return c.pipeline[arpResponderTable].BuildFlow(priorityHigh).MatchProtocol(binding.ProtocolARP).
+		MatchInPort(ofPort).
+		MatchARPOp(1).
+		Action().Move(binding.NxmFieldSrcMAC, binding.NxmFieldDstMAC).
+		Action().SetSrcMAC(gatewayMAC).
+		Action().LoadARPOperation(2).
+		Action().Move(binding.NxmFieldARPSha, binding.NxmFieldARPTha).
+		Action().SetARPSha(gatewayMAC).
+		Action().Move(binding.NxmFieldARPTpa, swapReg.nxm()).
+		Action().Move(binding.NxmFieldARPSpa, binding.NxmFieldARPTpa).
+		Action().Move(swapReg.nxm(), binding.NxmFieldARPSpa).
+		Action().OutputInPort().
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
 // arpResponderStaticFlow generates ARP reply for any ARP request with the same global virtual MAC.
 // This flow is used in policy-only mode, where traffic are routed via IP not MAC.
 func (c *client) arpResponderStaticFlow(category cookie.Category) binding.Flow {
@@ -795,7 +992,7 @@ func (c *client) arpResponderStaticFlow(category cookie.Category) binding.Flow {
 // will not be checked, since it might be pod to service traffic or host namespace traffic.
 func (c *client) podIPSpoofGuardFlow(ifIP net.IP, ifMAC net.HardwareAddr, ifOFPort uint32, category cookie.Category) binding.Flow {
 	ipPipeline := c.pipeline
-	ipSpoofGuardTable := ipPipeline[spoofGuardTable]
+	ipSpoofGuardTable := ipPipeline[SpoofGuardTable]
 	return ipSpoofGuardTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
 		MatchInPort(ifOFPort).
 		MatchSrcMAC(ifMAC).
@@ -819,7 +1016,7 @@ func (c *client) serviceHairpinResponseDNATFlow() binding.Flow {
 
 // gatewayARPSpoofGuardFlow generates the flow to check ARP traffic sent out from the local gateway interface.
 func (c *client) gatewayARPSpoofGuardFlow(gatewayOFPort uint32, gatewayIP net.IP, gatewayMAC net.HardwareAddr, category cookie.Category) binding.Flow {
-	return c.pipeline[spoofGuardTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolARP).
+	return c.pipeline[SpoofGuardTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolARP).
 		MatchInPort(gatewayOFPort).
 		MatchARPSha(gatewayMAC).
 		MatchARPSpa(gatewayIP).
@@ -830,7 +1027,7 @@ func (c *client) gatewayARPSpoofGuardFlow(gatewayOFPort uint32, gatewayIP net.IP
 
 // arpSpoofGuardFlow generates the flow to check ARP traffic sent out from local pods interfaces.
 func (c *client) arpSpoofGuardFlow(ifIP net.IP, ifMAC net.HardwareAddr, ifOFPort uint32, category cookie.Category) binding.Flow {
-	return c.pipeline[spoofGuardTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolARP).
+	return c.pipeline[SpoofGuardTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolARP).
 		MatchInPort(ifOFPort).
 		MatchARPSha(ifMAC).
 		MatchARPSpa(ifIP).
@@ -842,7 +1039,7 @@ func (c *client) arpSpoofGuardFlow(ifIP net.IP, ifMAC net.HardwareAddr, ifOFPort
 // gatewayIPSpoofGuardFlow generates the flow to skip spoof guard checking for traffic sent from gateway interface.
 func (c *client) gatewayIPSpoofGuardFlow(gatewayOFPort uint32, category cookie.Category) binding.Flow {
 	ipPipeline := c.pipeline
-	ipSpoofGuardTable := ipPipeline[spoofGuardTable]
+	ipSpoofGuardTable := ipPipeline[SpoofGuardTable]
 	return ipSpoofGuardTable.BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
 		MatchInPort(gatewayOFPort).
 		Action().GotoTable(ipSpoofGuardTable.GetNext()).
@@ -928,18 +1125,32 @@ func (c *client) dropRuleMetricFlow(conjunctionID uint32, ingress bool) binding.
 	if !ingress {
 		metricTableID = EgressMetricTable
 	}
-	return c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
+	fb := c.pipeline[metricTableID].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
 		MatchPriority(priorityNormal).
 		MatchRegRange(int(marksReg), cnpDropMark, cnpDropMarkRange).
-		MatchReg(int(cnpDropConjunctionIDReg), conjunctionID).
-		Action().Drop().
+		MatchReg(int(cnpDropConjunctionIDReg), conjunctionID)
+	return c.networkPolicyDropAction(fb).
 		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
 		Done()
 }
 
+// networkPolicyDropAction sets the action of a flow which drops packets because of NetworkPolicy rules. When the export
+// of denied connections is enabled, the packets are sent to the Antrea Agent instead, through the packet-in meter of
+// denied connections. As the packets are not output to any port, they are still dropped by OVS, including the ones
+// above the rate of the meter.
+func (c *client) networkPolicyDropAction(fb binding.FlowBuilder) binding.FlowBuilder {
+	if c.enableDenyFlowExport {
+		return c.sendPacketIn(fb, packetInMeterIDDenyFlow)
+	}
+	return fb.Action().Drop()
+}
+
 // conjunctionActionFlow generates the flow to jump to a specific table if policyRuleConjunction ID is matched. Priority of
 // conjunctionActionFlow is created at priorityLow for k8s network policies, and *priority assigned by PriorityAssigner for AntreaPolicy.
-func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.TableIDType, nextTable binding.TableIDType, priority *uint16) binding.Flow {
+// If enableLogging is true, a copy of the packet is also sent to the Antrea Agent for audit logging, limited by the
+// packet-in meter of NetworkPolicies, which never drops the allowed packets. If meterID is not 0, the packet is first
+// passed to the meter, which drops the new connections above its rate.
+func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.TableIDType, nextTable binding.TableIDType, priority *uint16, enableLogging bool, meterID uint32) binding.Flow {
 	var ofPriority uint16
 	if priority == nil {
 		ofPriority = priorityLow
@@ -952,32 +1163,67 @@ func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.Tab
 		conjReg = EgressReg
 		labelRange = metricEgressRuleIDRange
 	}
-	return c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(binding.ProtocolIP).
+	fb := c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(binding.ProtocolIP).
 		MatchConjID(conjunctionID).
-		MatchPriority(ofPriority).
-		Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}). // Traceflow.
-		Action().CT(true, nextTable, CtZone).                                     // CT action requires commit flag if actions other than NAT without arguments are specified.
+		MatchPriority(ofPriority)
+	if meterID != 0 {
+		// The rule tables only see the first packet of the connections, so
+		// the meter limits the rate of new connections.
+		fb = fb.Action().Meter(meterID)
+	}
+	fb = fb.Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}). // Traceflow.
+		Action().CT(true, nextTable, CtZone). // CT action requires commit flag if actions other than NAT without arguments are specified.
 		LoadToLabelRange(uint64(conjunctionID), &labelRange).
-		CTDone().
-		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+		CTDone()
+	if enableLogging {
+		// Clear the audit mark, so that the packet-in message is not taken as sent by an Audit rule.
+		fb = c.sendPacketIn(fb.Action().LoadRegRange(int(marksReg), 0, auditMarkRange), packetInMeterIDNP)
+	}
+	return fb.Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
 		Done()
 }
 
 // conjunctionActionDropFlow generates the flow to mark the packet to be dropped if policyRuleConjunction ID is matched.
-// Any matched flow will be dropped in corresponding metric tables.
-func (c *client) conjunctionActionDropFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16) binding.Flow {
+// Any matched flow will be dropped in corresponding metric tables. If enableLogging is true, a copy of the packet is
+// also sent to the Antrea Agent for audit logging, limited by the packet-in meter of NetworkPolicies.
+func (c *client) conjunctionActionDropFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16, enableLogging bool) binding.Flow {
 	ofPriority := *priority
 	metricTableID := IngressMetricTable
 	if _, ok := egressTables[tableID]; ok {
 		metricTableID = EgressMetricTable
 	}
 	// We do not drop the packet immediately but send the packet to the metric table to update the rule metrics.
-	return c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(binding.ProtocolIP).
+	fb := c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(binding.ProtocolIP).
+		MatchConjID(conjunctionID).
+		MatchPriority(ofPriority).
+		Action().LoadRegRange(int(cnpDropConjunctionIDReg), conjunctionID, binding.Range{0, 31}).
+		Action().LoadRegRange(int(marksReg), cnpDropMark, cnpDropMarkRange)
+	if enableLogging {
+		fb = c.sendPacketIn(fb, packetInMeterIDNP)
+	}
+	return fb.Action().GotoTable(metricTableID).
+		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+		Done()
+}
+
+// conjunctionActionAuditFlow generates the flow to audit the packet if policyRuleConjunction ID is matched. The packet
+// is marked as audited, sent to the Antrea Agent for audit logging if enableLogging is true, and resubmitted to the
+// same table. As the flow only matches the packets which are not marked, the packet is then evaluated by the rules of
+// lower priority as if the Audit rule did not exist. The packet count of the flow is the number of connections matched
+// by the rule, as the rule tables only see the first packet of a connection. If enableLogging is true, the packet-in
+// messages are limited by the packet-in meter of NetworkPolicies, which never drops the resubmitted packets.
+func (c *client) conjunctionActionAuditFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16, enableLogging bool) binding.Flow {
+	ofPriority := *priority
+	fb := c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(binding.ProtocolIP).
 		MatchConjID(conjunctionID).
 		MatchPriority(ofPriority).
+		MatchRegRange(int(marksReg), 0, auditMarkRange).
 		Action().LoadRegRange(int(cnpDropConjunctionIDReg), conjunctionID, binding.Range{0, 31}).
-		Action().LoadRegRange(int(marksReg), cnpDropMark, cnpDropMarkRange).
-		Action().GotoTable(metricTableID).
+		Action().LoadRegRange(int(marksReg), auditMark, auditMarkRange)
+	if enableLogging {
+		fb = c.sendPacketIn(fb, packetInMeterIDNP)
+	}
+	return fb.Action().ResubmitToTable(tableID).
 		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
 		Done()
 }
@@ -1068,6 +1314,29 @@ func (c *client) addFlowMatch(fb binding.FlowBuilder, matchType int, matchValue
 		if portValue > 0 {
 			fb = fb.MatchDstPort(portValue, nil)
 		}
+	case MatchICMP:
+		fb = fb.MatchProtocol(binding.ProtocolICMP)
+		icmpValue := matchValue.(icmpMatchValue)
+		if icmpValue.icmpType != nil || icmpValue.icmpCode != nil {
+			// The ICMP type and code are matched from the connection tracker
+			// original direction tuple, which stores them as the transport
+			// source and destination ports. Only the new connections are
+			// evaluated by the policy rules, so matching the new state meets
+			// the prerequisite of these fields.
+			// Note that a zero type or code matches any value, as zero ports
+			// are not matched by the OpenFlow library.
+			fb = fb.MatchCTStateNew(true).MatchCTProtocol(binding.ProtocolICMP)
+			if icmpValue.icmpType != nil {
+				fb = fb.MatchCTSrcPort(uint16(*icmpValue.icmpType))
+			}
+			if icmpValue.icmpCode != nil {
+				fb = fb.MatchCTDstPort(uint16(*icmpValue.icmpCode))
+			}
+		}
+	case MatchIGMP:
+		fb = fb.MatchProtocol(binding.ProtocolIGMP)
+	case MatchIPProtocol:
+		fb = fb.MatchIPProtocolValue(matchValue.(uint8))
 	}
 	return fb
 }
@@ -1106,8 +1375,7 @@ func (c *client) conjunctiveMatchFlow(tableID binding.TableIDType, matchKey int,
 // defaultDropFlow generates the flow to drop packets if the match condition is matched.
 func (c *client) defaultDropFlow(tableID binding.TableIDType, matchKey int, matchValue interface{}) binding.Flow {
 	fb := c.pipeline[tableID].BuildFlow(priorityNormal)
-	return c.addFlowMatch(fb, matchKey, matchValue).
-		Action().Drop().
+	return c.networkPolicyDropAction(c.addFlowMatch(fb, matchKey, matchValue)).
 		Cookie(c.cookieAllocator.Request(cookie.Default).Raw()).
 		Done()
 }
@@ -1130,11 +1398,18 @@ func (c *client) bridgeAndUplinkFlows(uplinkOfport uint32, bridgeLocalPort uint3
 		ctStateNext = endpointDNATTable
 	}
 	flows := []binding.Flow{
-		// Forward the packet to conntrackTable if it enters the OVS pipeline from the uplink interface.
+		// Forward the packet to snatConntrackTable if it enters the OVS pipeline from the uplink interface.
 		c.pipeline[uplinkTable].BuildFlow(priorityNormal).
 			MatchProtocol(binding.ProtocolIP).
 			Action().LoadRegRange(int(marksReg), markTrafficFromUplink, binding.Range{0, 15}).
-			Action().GotoTable(conntrackTable).
+			Action().GotoTable(snatConntrackTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// Enforce the packet received from the uplink interface into the SNAT conntrack zone. If the connection is
+		// SNATed, the reply packet should use Pod IP as the destination, and then is tracked in the conntrack zone of
+		// the pipeline by conntrackTable.
+		c.pipeline[snatConntrackTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
+			Action().CT(false, conntrackTable, SNATCtZone).NAT().CTDone().
 			Cookie(c.cookieAllocator.Request(category).Raw()).
 			Done(),
 		// Forward the packet to conntrackTable if it enters the OVS pipeline from the bridge interface and is sent to
@@ -1148,8 +1423,9 @@ func (c *client) bridgeAndUplinkFlows(uplinkOfport uint32, bridgeLocalPort uint3
 			Action().GotoTable(conntrackTable).
 			Cookie(c.cookieAllocator.Request(category).Raw()).
 			Done(),
-		// Enforce IP packet into the conntrack zone with SNAT. If the connection is SNATed, the reply packet should use
-		// Pod IP as the destination, and then is forwarded to conntrackStateTable.
+		// Enforce IP packet into the conntrack zone of the pipeline, and then forward it to conntrackStateTable. The
+		// NAT action also translates the packets of the connections SNATed in this zone by a previous version of the
+		// Agent, so that they are not broken by an upgrade until they expire.
 		c.pipeline[conntrackTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
 			Action().CT(false, conntrackStateTable, CtZone).NAT().CTDone().
 			Cookie(c.cookieAllocator.Request(category).Raw()).
@@ -1180,18 +1456,42 @@ func (c *client) bridgeAndUplinkFlows(uplinkOfport uint32, bridgeLocalPort uint3
 			Action().Output(int(bridgeLocalPort)).
 			Cookie(c.cookieAllocator.Request(category).Raw()).
 			Done(),
-		// Enforce the packet into L2ForwardingOutput table after the packet is SNATed. The "SNAT" packet has these
-		// characteristics: 1) the ct_state is "+new+trk", 2) reg0[17] is set to 1; 3) Node IP is used as the target
-		// source IP in NAT action, 4) ct_mark is set to 0x40 in the conn_track context.
+		// Commit the new connection to be SNATed in the conntrack zone of the pipeline with its original addresses,
+		// setting ct_mark to 0x40, and then forward it to SNATConntrackCommitTable.
 		c.pipeline[conntrackCommitTable].BuildFlow(priorityNormal).
 			MatchProtocol(binding.ProtocolIP).
 			MatchCTStateNew(true).MatchCTStateTrk(true).
 			MatchRegRange(int(marksReg), snatRequiredMark, snatMarkRange).
-			Action().CT(true, L2ForwardingOutTable, CtZone).
-			SNAT(snatIPRange, nil).
+			Action().CT(true, SNATConntrackCommitTable, CtZone).
 			LoadToMark(snatCTMark).CTDone().
 			Cookie(c.cookieAllocator.Request(category).Raw()).
 			Done(),
+		c.pipeline[conntrackCommitTable].BuildFlow(priorityNormal).
+			MatchProtocol(binding.ProtocolIP).
+			MatchCTStateNew(false).MatchCTStateTrk(true).
+			MatchRegRange(int(marksReg), snatRequiredMark, snatMarkRange).
+			Action().GotoTable(SNATConntrackCommitTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// Enforce the packet into L2ForwardingOutput table after the packet is SNATed in the SNAT conntrack zone. The
+		// "SNAT" packet has these characteristics: 1) the ct_state is "+new+trk", 2) reg0[17] is set to 1; 3) Node IP
+		// is used as the target source IP in NAT action.
+		c.pipeline[SNATConntrackCommitTable].BuildFlow(priorityNormal).
+			MatchProtocol(binding.ProtocolIP).
+			MatchCTStateNew(true).MatchCTStateTrk(true).
+			MatchRegRange(int(marksReg), snatRequiredMark, snatMarkRange).
+			Action().CT(true, L2ForwardingOutTable, SNATCtZone).
+			SNAT(snatIPRange, nil).CTDone().
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		// SNAT the packets of the established connections in the SNAT conntrack zone.
+		c.pipeline[SNATConntrackCommitTable].BuildFlow(priorityLow).
+			MatchProtocol(binding.ProtocolIP).
+			MatchCTStateNew(false).MatchCTStateTrk(true).
+			MatchRegRange(int(marksReg), snatRequiredMark, snatMarkRange).
+			Action().CT(false, L2ForwardingOutTable, SNATCtZone).NAT().CTDone().
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
 	}
 	return flows
 }
@@ -1258,6 +1558,62 @@ func (c *client) loadBalancerServiceFromOutsideFlow(uplinkPort uint32, gwPort ui
 		Done()
 }
 
+// loadBalancerServiceDSRMarkFlow generates the flow which marks the traffic
+// received from the gateway for the LoadBalancer IP of a Service in DSR mode.
+func (c *client) loadBalancerServiceDSRMarkFlow(svcIP net.IP, svcPort uint16, protocol binding.Protocol) binding.Flow {
+	return c.pipeline[serviceHairpinTable].BuildFlow(priorityNormal).
+		MatchProtocol(protocol).
+		MatchRegRange(int(marksReg), markTrafficFromGateway, binding.Range{0, 15}).
+		MatchDstIP(svcIP).
+		MatchDstPort(svcPort, nil).
+		Action().LoadRegRange(int(marksReg), dsrMark, dsrMarkRange).
+		Action().GotoTable(conntrackTable).
+		Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+		Done()
+}
+
+// loadBalancerServiceLocalLBFlows generates the flows which load-balance the
+// traffic with trafficMark for the LoadBalancer IP of a Service to the local
+// Endpoints: the traffic forwarded from other Nodes in DSR mode, or the traffic
+// from outside the cluster routed through the gateway to the Services with the
+// Local external traffic policy. The learned session affinity flows are
+// skipped, as they may select remote Endpoints.
+func (c *client) loadBalancerServiceLocalLBFlows(localGroupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol, trafficMark uint32) []binding.Flow {
+	return []binding.Flow{
+		c.pipeline[sessionAffinityTable].BuildFlow(priorityHigh).
+			MatchProtocol(protocol).
+			MatchRegRange(int(marksReg), trafficMark, binding.Range{0, 15}).
+			MatchDstIP(svcIP).
+			MatchDstPort(svcPort, nil).
+			Action().LoadRegRange(int(serviceLearnReg), marksRegServiceNeedLB, serviceLearnRegRange).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+		c.pipeline[serviceLBTable].BuildFlow(priorityHigh).
+			MatchProtocol(protocol).
+			MatchRegRange(int(marksReg), trafficMark, binding.Range{0, 15}).
+			MatchDstIP(svcIP).
+			MatchDstPort(svcPort, nil).
+			MatchRegRange(int(serviceLearnReg), marksRegServiceNeedLB, serviceLearnRegRange).
+			Action().Group(localGroupID).
+			Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+			Done(),
+	}
+}
+
+// loadBalancerServiceDSRUntrackedFlow generates the flow which load-balances
+// the packets marked for DSR that are invalid for conntrack, instead of
+// dropping them. The connections forwarded to remote Endpoints are never
+// committed, and conntrack only sees one direction of them.
+func (c *client) loadBalancerServiceDSRUntrackedFlow() binding.Flow {
+	return c.pipeline[conntrackStateTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolIP).
+		MatchRegRange(int(marksReg), dsrMark, dsrMarkRange).
+		MatchCTStateInv(true).MatchCTStateTrk(true).
+		Action().ResubmitToTable(sessionAffinityTable).
+		Action().ResubmitToTable(serviceLBTable).
+		Cookie(c.cookieAllocator.Request(cookie.Service).Raw()).
+		Done()
+}
+
 // serviceLearnFlow generates the flow with learn action which adds new flows in
 // sessionAffinityTable according to the Endpoint selection decision.
 func (c *client) serviceLearnFlow(groupID binding.GroupIDType, svcIP net.IP, svcPort uint16, protocol binding.Protocol, affinityTimeout uint16) binding.Flow {
@@ -1322,7 +1678,7 @@ func (c *client) endpointDNATFlow(endpointIP net.IP, endpointPort uint16, protoc
 			&binding.IPRange{StartIP: endpointIP, EndIP: endpointIP},
 			&binding.PortRange{StartPort: endpointPort, EndPort: endpointPort},
 		).
-		LoadToMark(serviceCTMark).
+		LoadToMark(ServiceCTMark).
 		CTDone().
 		Done()
 }
@@ -1397,9 +1753,9 @@ func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP bool) m
 	var pipeline map[binding.TableIDType]binding.Table
 	if enableProxy {
 		pipeline = map[binding.TableIDType]binding.Table{
-			ClassifierTable:       bridge.CreateTable(ClassifierTable, spoofGuardTable, binding.TableMissActionDrop),
-			uplinkTable:           bridge.CreateTable(uplinkTable, spoofGuardTable, binding.TableMissActionNone),
-			spoofGuardTable:       bridge.CreateTable(spoofGuardTable, serviceHairpinTable, binding.TableMissActionDrop),
+			ClassifierTable:       bridge.CreateTable(ClassifierTable, SpoofGuardTable, binding.TableMissActionDrop),
+			uplinkTable:           bridge.CreateTable(uplinkTable, SpoofGuardTable, binding.TableMissActionNone),
+			SpoofGuardTable:       bridge.CreateTable(SpoofGuardTable, serviceHairpinTable, binding.TableMissActionDrop),
 			arpResponderTable:     bridge.CreateTable(arpResponderTable, binding.LastTableID, binding.TableMissActionDrop),
 			serviceHairpinTable:   bridge.CreateTable(serviceHairpinTable, conntrackTable, binding.TableMissActionNext),
 			conntrackTable:        bridge.CreateTable(conntrackTable, conntrackStateTable, binding.TableMissActionNone),
@@ -1418,11 +1774,12 @@ func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP bool) m
 			conntrackCommitTable:  bridge.CreateTable(conntrackCommitTable, hairpinSNATTable, binding.TableMissActionNext),
 			hairpinSNATTable:      bridge.CreateTable(hairpinSNATTable, L2ForwardingOutTable, binding.TableMissActionNext),
 			L2ForwardingOutTable:  bridge.CreateTable(L2ForwardingOutTable, binding.LastTableID, binding.TableMissActionDrop),
+			snatConntrackTable:    bridge.CreateTable(snatConntrackTable, conntrackTable, binding.TableMissActionNone),
 		}
 	} else {
 		pipeline = map[binding.TableIDType]binding.Table{
-			ClassifierTable:     bridge.CreateTable(ClassifierTable, spoofGuardTable, binding.TableMissActionDrop),
-			spoofGuardTable:     bridge.CreateTable(spoofGuardTable, conntrackTable, binding.TableMissActionDrop),
+			ClassifierTable:     bridge.CreateTable(ClassifierTable, SpoofGuardTable, binding.TableMissActionDrop),
+			SpoofGuardTable:     bridge.CreateTable(SpoofGuardTable, conntrackTable, binding.TableMissActionDrop),
 			arpResponderTable:   bridge.CreateTable(arpResponderTable, binding.LastTableID, binding.TableMissActionDrop),
 			conntrackTable:      bridge.CreateTable(conntrackTable, conntrackStateTable, binding.TableMissActionNone),
 			conntrackStateTable: bridge.CreateTable(conntrackStateTable, dnatTable, binding.TableMissActionNext),
@@ -1438,6 +1795,9 @@ func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP bool) m
 			L2ForwardingOutTable:  bridge.CreateTable(L2ForwardingOutTable, binding.LastTableID, binding.TableMissActionDrop),
 		}
 	}
+	// The connections SNATed by OVS are committed to the SNAT conntrack zone after they are committed to the conntrack
+	// zone of the pipeline.
+	pipeline[SNATConntrackCommitTable] = bridge.CreateTable(SNATConntrackCommitTable, L2ForwardingOutTable, binding.TableMissActionNext)
 	if !enableAntreaNP {
 		return pipeline
 	}
@@ -1449,8 +1809,15 @@ func generatePipeline(bridge binding.Bridge, enableProxy, enableAntreaNP bool) m
 }
 
 // NewClient is the constructor of the Client interface.
-func NewClient(bridgeName, mgmtAddr string, enableProxy, enableAntreaPolicy bool) Client {
-	bridge := binding.NewOFBridge(bridgeName, mgmtAddr)
+// If enableDenyFlowExport is true, the packets dropped by NetworkPolicies are sent to the Antrea Agent, so that the
+// denied connections can be exported by the Flow Exporter.
+func NewClient(bridgeName, mgmtAddr string, enableProxy, enableAntreaPolicy, enableDenyFlowExport bool) Client {
+	return NewClientWithBridge(binding.NewOFBridge(bridgeName, mgmtAddr), enableProxy, enableAntreaPolicy, enableDenyFlowExport)
+}
+
+// NewClientWithBridge is like NewClient, but programs the provided Bridge. It
+// can be used by unit tests to run the Client on a binding.FakeBridge.
+func NewClientWithBridge(bridge binding.Bridge, enableProxy, enableAntreaPolicy, enableDenyFlowExport bool) Client {
 	policyCache := cache.NewIndexer(
 		policyConjKeyFunc,
 		cache.Indexers{priorityIndex: priorityIndexFunc},
@@ -1461,6 +1828,8 @@ func NewClient(bridgeName, mgmtAddr string, enableProxy, enableAntreaPolicy bool
 		nodeFlowCache:            newFlowCategoryCache(),
 		podFlowCache:             newFlowCategoryCache(),
 		serviceFlowCache:         newFlowCategoryCache(),
+		snatFlowCache:            newFlowCategoryCache(),
+		snatIPFlowCache:          newFlowCategoryCache(),
 		policyCache:              policyCache,
 		groupCache:               sync.Map{},
 		globalConjMatchFlowCache: map[string]*conjMatchFlowContext{},
@@ -1469,5 +1838,6 @@ func NewClient(bridgeName, mgmtAddr string, enableProxy, enableAntreaPolicy bool
 	c.ofEntryOperations = c
 	c.enableProxy = enableProxy
 	c.enableAntreaPolicy = enableAntreaPolicy
+	c.enableDenyFlowExport = enableDenyFlowExport
 	return c
 }
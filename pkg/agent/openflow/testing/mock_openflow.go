@@ -124,6 +124,20 @@ func (mr *MockClientMockRecorder) Disconnect() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnect", reflect.TypeOf((*MockClient)(nil).Disconnect))
 }
 
+// DrainEndpointFlows mocks base method
+func (m *MockClient) DrainEndpointFlows(arg0 openflow.Protocol, arg1 proxy.Endpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainEndpointFlows", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DrainEndpointFlows indicates an expected call of DrainEndpointFlows
+func (mr *MockClientMockRecorder) DrainEndpointFlows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainEndpointFlows", reflect.TypeOf((*MockClient)(nil).DrainEndpointFlows), arg0, arg1)
+}
+
 // GetFlowTableStatus mocks base method
 func (m *MockClient) GetFlowTableStatus() []openflow.TableStatus {
 	m.ctrl.T.Helper()
@@ -180,6 +194,22 @@ func (mr *MockClientMockRecorder) GetPolicyFromConjunction(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyFromConjunction", reflect.TypeOf((*MockClient)(nil).GetPolicyFromConjunction), arg0)
 }
 
+// GetRuleFromConjunction mocks base method
+func (m *MockClient) GetRuleFromConjunction(arg0 uint32) (v1beta1.Direction, int32, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleFromConjunction", arg0)
+	ret0, _ := ret[0].(v1beta1.Direction)
+	ret1, _ := ret[1].(int32)
+	ret2, _ := ret[2].(bool)
+	return ret0, ret1, ret2
+}
+
+// GetRuleFromConjunction indicates an expected call of GetRuleFromConjunction
+func (mr *MockClientMockRecorder) GetRuleFromConjunction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleFromConjunction", reflect.TypeOf((*MockClient)(nil).GetRuleFromConjunction), arg0)
+}
+
 // GetTunnelVirtualMAC mocks base method
 func (m *MockClient) GetTunnelVirtualMAC() net.HardwareAddr {
 	m.ctrl.T.Helper()
@@ -265,6 +295,34 @@ func (mr *MockClientMockRecorder) InstallClusterServiceFlows() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallClusterServiceFlows", reflect.TypeOf((*MockClient)(nil).InstallClusterServiceFlows))
 }
 
+// InstallDHCPRequestFlows mocks base method
+func (m *MockClient) InstallDHCPRequestFlows() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallDHCPRequestFlows")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallDHCPRequestFlows indicates an expected call of InstallDHCPRequestFlows
+func (mr *MockClientMockRecorder) InstallDHCPRequestFlows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallDHCPRequestFlows", reflect.TypeOf((*MockClient)(nil).InstallDHCPRequestFlows))
+}
+
+// InstallDNSResponseFlows mocks base method
+func (m *MockClient) InstallDNSResponseFlows() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallDNSResponseFlows")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallDNSResponseFlows indicates an expected call of InstallDNSResponseFlows
+func (mr *MockClientMockRecorder) InstallDNSResponseFlows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallDNSResponseFlows", reflect.TypeOf((*MockClient)(nil).InstallDNSResponseFlows))
+}
+
 // InstallDefaultTunnelFlows mocks base method
 func (m *MockClient) InstallDefaultTunnelFlows(arg0 uint32) error {
 	m.ctrl.T.Helper()
@@ -279,6 +337,34 @@ func (mr *MockClientMockRecorder) InstallDefaultTunnelFlows(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallDefaultTunnelFlows", reflect.TypeOf((*MockClient)(nil).InstallDefaultTunnelFlows), arg0)
 }
 
+// InstallEgressTunnelFlows mocks base method
+func (m *MockClient) InstallEgressTunnelFlows() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallEgressTunnelFlows")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallEgressTunnelFlows indicates an expected call of InstallEgressTunnelFlows
+func (mr *MockClientMockRecorder) InstallEgressTunnelFlows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallEgressTunnelFlows", reflect.TypeOf((*MockClient)(nil).InstallEgressTunnelFlows))
+}
+
+// InstallEgressTunnelSNATFlows mocks base method
+func (m *MockClient) InstallEgressTunnelSNATFlows() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallEgressTunnelSNATFlows")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallEgressTunnelSNATFlows indicates an expected call of InstallEgressTunnelSNATFlows
+func (mr *MockClientMockRecorder) InstallEgressTunnelSNATFlows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallEgressTunnelSNATFlows", reflect.TypeOf((*MockClient)(nil).InstallEgressTunnelSNATFlows))
+}
+
 // InstallEndpointFlows mocks base method
 func (m *MockClient) InstallEndpointFlows(arg0 openflow.Protocol, arg1 []proxy.Endpoint) error {
 	m.ctrl.T.Helper()
@@ -321,6 +407,20 @@ func (mr *MockClientMockRecorder) InstallGatewayFlows(arg0, arg1, arg2 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallGatewayFlows", reflect.TypeOf((*MockClient)(nil).InstallGatewayFlows), arg0, arg1, arg2)
 }
 
+// InstallLoadBalancerServiceDSRFlows mocks base method
+func (m *MockClient) InstallLoadBalancerServiceDSRFlows(arg0 openflow.GroupIDType, arg1 net.IP, arg2 uint16, arg3 openflow.Protocol) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallLoadBalancerServiceDSRFlows", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallLoadBalancerServiceDSRFlows indicates an expected call of InstallLoadBalancerServiceDSRFlows
+func (mr *MockClientMockRecorder) InstallLoadBalancerServiceDSRFlows(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallLoadBalancerServiceDSRFlows", reflect.TypeOf((*MockClient)(nil).InstallLoadBalancerServiceDSRFlows), arg0, arg1, arg2, arg3)
+}
+
 // InstallLoadBalancerServiceFromOutsideFlows mocks base method
 func (m *MockClient) InstallLoadBalancerServiceFromOutsideFlows(arg0 net.IP, arg1 uint16, arg2 openflow.Protocol) error {
 	m.ctrl.T.Helper()
@@ -335,18 +435,32 @@ func (mr *MockClientMockRecorder) InstallLoadBalancerServiceFromOutsideFlows(arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallLoadBalancerServiceFromOutsideFlows", reflect.TypeOf((*MockClient)(nil).InstallLoadBalancerServiceFromOutsideFlows), arg0, arg1, arg2)
 }
 
+// InstallLoadBalancerServiceLocalFlows mocks base method
+func (m *MockClient) InstallLoadBalancerServiceLocalFlows(arg0 openflow.GroupIDType, arg1 net.IP, arg2 uint16, arg3 openflow.Protocol) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallLoadBalancerServiceLocalFlows", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallLoadBalancerServiceLocalFlows indicates an expected call of InstallLoadBalancerServiceLocalFlows
+func (mr *MockClientMockRecorder) InstallLoadBalancerServiceLocalFlows(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallLoadBalancerServiceLocalFlows", reflect.TypeOf((*MockClient)(nil).InstallLoadBalancerServiceLocalFlows), arg0, arg1, arg2, arg3)
+}
+
 // InstallNodeFlows mocks base method
-func (m *MockClient) InstallNodeFlows(arg0 string, arg1 net.HardwareAddr, arg2 net.IPNet, arg3, arg4 net.IP, arg5, arg6 uint32) error {
+func (m *MockClient) InstallNodeFlows(arg0 string, arg1 net.HardwareAddr, arg2 net.IPNet, arg3, arg4 net.IP, arg5, arg6 uint32, arg7 config.TrafficEncapModeType) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InstallNodeFlows", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "InstallNodeFlows", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InstallNodeFlows indicates an expected call of InstallNodeFlows
-func (mr *MockClientMockRecorder) InstallNodeFlows(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) InstallNodeFlows(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallNodeFlows", reflect.TypeOf((*MockClient)(nil).InstallNodeFlows), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallNodeFlows", reflect.TypeOf((*MockClient)(nil).InstallNodeFlows), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
 
 // InstallPodFlows mocks base method
@@ -363,6 +477,34 @@ func (mr *MockClientMockRecorder) InstallPodFlows(arg0, arg1, arg2, arg3, arg4 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallPodFlows", reflect.TypeOf((*MockClient)(nil).InstallPodFlows), arg0, arg1, arg2, arg3, arg4)
 }
 
+// InstallPodSNATFlows mocks base method
+func (m *MockClient) InstallPodSNATFlows(arg0, arg1 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallPodSNATFlows", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallPodSNATFlows indicates an expected call of InstallPodSNATFlows
+func (mr *MockClientMockRecorder) InstallPodSNATFlows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallPodSNATFlows", reflect.TypeOf((*MockClient)(nil).InstallPodSNATFlows), arg0, arg1)
+}
+
+// InstallPodSNATIPFlows mocks base method
+func (m *MockClient) InstallPodSNATIPFlows(arg0, arg1 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallPodSNATIPFlows", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallPodSNATIPFlows indicates an expected call of InstallPodSNATIPFlows
+func (mr *MockClientMockRecorder) InstallPodSNATIPFlows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallPodSNATIPFlows", reflect.TypeOf((*MockClient)(nil).InstallPodSNATIPFlows), arg0, arg1)
+}
+
 // InstallPolicyRuleFlows mocks base method
 func (m *MockClient) InstallPolicyRuleFlows(arg0 *types.PolicyRule) error {
 	m.ctrl.T.Helper()
@@ -499,6 +641,20 @@ func (mr *MockClientMockRecorder) SendTraceflowPacket(arg0, arg1, arg2, arg3, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTraceflowPacket", reflect.TypeOf((*MockClient)(nil).SendTraceflowPacket), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15, arg16, arg17, arg18)
 }
 
+// SendUDPPacketOut mocks base method
+func (m *MockClient) SendUDPPacketOut(arg0, arg1 net.HardwareAddr, arg2, arg3 net.IP, arg4, arg5 uint16, arg6 []byte, arg7 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendUDPPacketOut", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendUDPPacketOut indicates an expected call of SendUDPPacketOut
+func (mr *MockClientMockRecorder) SendUDPPacketOut(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendUDPPacketOut", reflect.TypeOf((*MockClient)(nil).SendUDPPacketOut), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+}
+
 // StartPacketInHandler mocks base method
 func (m *MockClient) StartPacketInHandler(arg0 <-chan struct{}) {
 	m.ctrl.T.Helper()
@@ -539,6 +695,34 @@ func (mr *MockClientMockRecorder) UninstallEndpointFlows(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallEndpointFlows", reflect.TypeOf((*MockClient)(nil).UninstallEndpointFlows), arg0, arg1)
 }
 
+// UninstallLoadBalancerServiceDSRFlows mocks base method
+func (m *MockClient) UninstallLoadBalancerServiceDSRFlows(arg0 net.IP, arg1 uint16, arg2 openflow.Protocol) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallLoadBalancerServiceDSRFlows", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallLoadBalancerServiceDSRFlows indicates an expected call of UninstallLoadBalancerServiceDSRFlows
+func (mr *MockClientMockRecorder) UninstallLoadBalancerServiceDSRFlows(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallLoadBalancerServiceDSRFlows", reflect.TypeOf((*MockClient)(nil).UninstallLoadBalancerServiceDSRFlows), arg0, arg1, arg2)
+}
+
+// UninstallLoadBalancerServiceLocalFlows mocks base method
+func (m *MockClient) UninstallLoadBalancerServiceLocalFlows(arg0 net.IP, arg1 uint16, arg2 openflow.Protocol) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallLoadBalancerServiceLocalFlows", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallLoadBalancerServiceLocalFlows indicates an expected call of UninstallLoadBalancerServiceLocalFlows
+func (mr *MockClientMockRecorder) UninstallLoadBalancerServiceLocalFlows(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallLoadBalancerServiceLocalFlows", reflect.TypeOf((*MockClient)(nil).UninstallLoadBalancerServiceLocalFlows), arg0, arg1, arg2)
+}
+
 // UninstallNodeFlows mocks base method
 func (m *MockClient) UninstallNodeFlows(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -567,6 +751,34 @@ func (mr *MockClientMockRecorder) UninstallPodFlows(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallPodFlows", reflect.TypeOf((*MockClient)(nil).UninstallPodFlows), arg0)
 }
 
+// UninstallPodSNATFlows mocks base method
+func (m *MockClient) UninstallPodSNATFlows(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallPodSNATFlows", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallPodSNATFlows indicates an expected call of UninstallPodSNATFlows
+func (mr *MockClientMockRecorder) UninstallPodSNATFlows(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallPodSNATFlows", reflect.TypeOf((*MockClient)(nil).UninstallPodSNATFlows), arg0)
+}
+
+// UninstallPodSNATIPFlows mocks base method
+func (m *MockClient) UninstallPodSNATIPFlows(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallPodSNATIPFlows", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallPodSNATIPFlows indicates an expected call of UninstallPodSNATIPFlows
+func (mr *MockClientMockRecorder) UninstallPodSNATIPFlows(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallPodSNATIPFlows", reflect.TypeOf((*MockClient)(nil).UninstallPodSNATIPFlows), arg0)
+}
+
 // UninstallPolicyRuleFlows mocks base method
 func (m *MockClient) UninstallPolicyRuleFlows(arg0 uint32) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,136 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/metrics"
+)
+
+// requiredDPFeatures are the features of the OVS datapath the Antrea pipeline
+// cannot work without, indexed by their name in the output of
+// "ovs-appctl dpif/show-dp-features".
+var requiredDPFeatures = map[string]string{
+	"Recirc":       "recirc",
+	"CT state":     "ct_state",
+	"CT zone":      "ct_zone",
+	"CT mark":      "ct_mark",
+	"CT state NAT": "ct_state_nat",
+}
+
+var maxMeterRegex = regexp.MustCompile(`max_meter:(\d+)`)
+
+// parseDPFeatures parses the boolean features in the output of
+// "ovs-appctl dpif/show-dp-features", which looks like:
+//
+//	Masked set action: Yes
+//	Conntrack clear: No
+//	Max dp_hash algorithm: 1
+//	Recirc: Yes
+//
+// The features with a numeric value are ignored.
+func parseDPFeatures(out string) map[string]bool {
+	features := make(map[string]bool)
+	for _, line := range strings.Split(strings.ReplaceAll(out, "\r", ""), "\n") {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.TrimSpace(kv[1]) {
+		case "Yes":
+			features[strings.TrimSpace(kv[0])] = true
+		case "No":
+			features[strings.TrimSpace(kv[0])] = false
+		}
+	}
+	return features
+}
+
+// parseMaxMeters returns the maximum number of meters in the output of
+// "ovs-ofctl meter-features", 0 meaning that meters are not supported by the
+// datapath.
+func parseMaxMeters(out string) (int, error) {
+	matches := maxMeterRegex.FindStringSubmatch(out)
+	if matches == nil {
+		return 0, fmt.Errorf("max_meter not found in meter features")
+	}
+	return strconv.Atoi(matches[1])
+}
+
+// detectOVSFeatures checks the features of the OVS datapath of the bridge.
+// Missing required features fail the initialization with an explicit error,
+// instead of failing later when the flows using them are installed. Missing
+// optional features are recorded in the NodeConfig. If the features cannot be
+// queried, e.g. with an OVS version which doesn't support the commands, they
+// are assumed to be supported.
+func (i *Initializer) detectOVSFeatures() error {
+	if out, execErr := i.ovsCtlClient.RunAppctlCmd("dpif/show-dp-features", true); execErr != nil {
+		klog.Warningf("Failed to get the features of the OVS datapath, assuming they are supported: %v", execErr)
+	} else {
+		dpFeatures := parseDPFeatures(string(out))
+		var missing []string
+		for dpFeature, name := range requiredDPFeatures {
+			if supported, ok := dpFeatures[dpFeature]; ok && !supported {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("the OVS datapath doesn't support the features required by Antrea: %s", strings.Join(missing, ", "))
+		}
+	}
+	if out, err := i.ovsCtlClient.RunOfctlCmd("meter-features"); err != nil {
+		klog.Warningf("Failed to get the meter features of the OVS bridge, assuming meters are supported: %v", err)
+	} else if maxMeters, err := parseMaxMeters(string(out)); err != nil {
+		klog.Warningf("Failed to parse the meter features of the OVS bridge, assuming meters are supported: %v", err)
+	} else if maxMeters == 0 {
+		if err := i.handleMissingOVSFeature(config.OVSFeatureMeters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleMissingOVSFeature records an optional OVS feature which is not
+// supported by the Node, or returns an error if the Agent is configured to
+// fail when an optional feature is missing.
+func (i *Initializer) handleMissingOVSFeature(feature string) error {
+	if i.failOnMissingOVSFeatures {
+		return fmt.Errorf("the OVS datapath doesn't support the optional feature %s", feature)
+	}
+	klog.Warningf("The OVS datapath doesn't support the optional feature %s, the functionality depending on it is disabled", feature)
+	i.nodeConfig.UnsupportedOVSFeatures = append(i.nodeConfig.UnsupportedOVSFeatures, feature)
+	return nil
+}
+
+// updateOVSFeatureMetrics reports the support of the optional OVS features in
+// the Prometheus metrics.
+func (i *Initializer) updateOVSFeatureMetrics() {
+	for _, feature := range []string{config.OVSFeatureMeters, config.OVSFeatureGeneveOptions} {
+		supported := 0.0
+		if i.nodeConfig.OVSFeatureSupported(feature) {
+			supported = 1
+		}
+		metrics.OVSFeatureSupported.WithLabelValues(feature).Set(supported)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"testing"
+
+	mock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl"
+	ovsctltest "github.com/vmware-tanzu/antrea/pkg/ovs/ovsctl/testing"
+)
+
+const (
+	allDPFeatures = `Masked set action: Yes
+Tunnel push pop: No
+Ufid: Yes
+Conntrack clear: Yes
+Max dp_hash algorithm: 1
+Recirc: Yes
+CT state: Yes
+CT zone: Yes
+CT mark: Yes
+CT label: Yes
+CT state NAT: Yes
+`
+	meterFeatures = `OFPST_METER_FEATURES reply (OF1.3) (xid=0x2):
+max_meter:%d max_bands:1 max_color:0
+band_types: drop
+capabilities: kbps pktps burst stats
+`
+)
+
+func TestParseDPFeatures(t *testing.T) {
+	features := parseDPFeatures(allDPFeatures)
+	assert.True(t, features["Conntrack clear"])
+	assert.False(t, features["Tunnel push pop"])
+	_, found := features["Max dp_hash algorithm"]
+	assert.False(t, found, "Numeric features should be ignored")
+
+	maxMeters, err := parseMaxMeters(fmt.Sprintf(meterFeatures, 200000))
+	assert.NoError(t, err)
+	assert.Equal(t, 200000, maxMeters)
+	_, err = parseMaxMeters("")
+	assert.Error(t, err)
+}
+
+func TestDetectOVSFeatures(t *testing.T) {
+	tests := []struct {
+		name                string
+		dpFeatures          string
+		dpFeaturesErr       *ovsctl.ExecError
+		maxMeters           int
+		failOnMissing       bool
+		expectedErr         bool
+		expectedUnsupported []string
+	}{
+		{
+			name:       "all-supported",
+			dpFeatures: allDPFeatures,
+			maxMeters:  200000,
+		},
+		{
+			name:                "optional-missing",
+			dpFeatures:          "Conntrack clear: No\nRecirc: Yes\n",
+			maxMeters:           0,
+			expectedUnsupported: []string{config.OVSFeatureMeters},
+		},
+		{
+			name:          "optional-missing-fail",
+			dpFeatures:    allDPFeatures,
+			maxMeters:     0,
+			failOnMissing: true,
+			expectedErr:   true,
+		},
+		{
+			name:        "required-missing",
+			dpFeatures:  "Recirc: Yes\nCT state NAT: No\n",
+			maxMeters:   200000,
+			expectedErr: true,
+		},
+		{
+			name:          "dp-features-unavailable",
+			dpFeaturesErr: &ovsctl.ExecError{},
+			maxMeters:     200000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := mock.NewController(t)
+			defer controller.Finish()
+			ovsCtlClient := ovsctltest.NewMockOVSCtlClient(controller)
+			ovsCtlClient.EXPECT().RunAppctlCmd("dpif/show-dp-features", true).Return([]byte(tt.dpFeatures), tt.dpFeaturesErr)
+			ovsCtlClient.EXPECT().RunOfctlCmd("meter-features").Return([]byte(fmt.Sprintf(meterFeatures, tt.maxMeters)), nil).MaxTimes(1)
+			initializer := &Initializer{
+				ovsCtlClient:             ovsCtlClient,
+				nodeConfig:               &config.NodeConfig{},
+				failOnMissingOVSFeatures: tt.failOnMissing,
+			}
+			err := initializer.detectOVSFeatures()
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUnsupported, initializer.nodeConfig.UnsupportedOVSFeatures)
+		})
+	}
+}
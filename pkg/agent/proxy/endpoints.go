@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
 
@@ -39,18 +40,32 @@ type endpointsChange struct {
 type endpointsChangesTracker struct {
 	// hostname is used to tell whether the Endpoint is located on current Node.
 	hostname string
+	// endpointSliceEnabled tells whether EndpointSlices are consumed. When it's
+	// true, the Endpoints of a Service are only used until an EndpointSlice of
+	// the Service is observed.
+	endpointSliceEnabled bool
 
 	sync.RWMutex
 	// initialized tells whether Endpoints have been synced.
 	initialized bool
+	// endpointSlicesInitialized tells whether EndpointSlices have been synced.
+	endpointSlicesInitialized bool
 	// changes contains endpoints changes since the last checkoutChanges call.
 	changes map[apimachinerytypes.NamespacedName]*endpointsChange
+	// endpointsCache and endpointSliceCache store the EndpointsMaps translated
+	// from the Endpoints and the EndpointSlices (indexed by name) of each
+	// Service. They are only populated when endpointSliceEnabled is true.
+	endpointsCache     map[apimachinerytypes.NamespacedName]types.EndpointsMap
+	endpointSliceCache map[apimachinerytypes.NamespacedName]map[string]types.EndpointsMap
 }
 
-func newEndpointsChangesTracker(hostname string) *endpointsChangesTracker {
+func newEndpointsChangesTracker(hostname string, endpointSliceEnabled bool) *endpointsChangesTracker {
 	return &endpointsChangesTracker{
-		hostname: hostname,
-		changes:  map[apimachinerytypes.NamespacedName]*endpointsChange{},
+		hostname:             hostname,
+		endpointSliceEnabled: endpointSliceEnabled,
+		changes:              map[apimachinerytypes.NamespacedName]*endpointsChange{},
+		endpointsCache:       map[apimachinerytypes.NamespacedName]types.EndpointsMap{},
+		endpointSliceCache:   map[apimachinerytypes.NamespacedName]map[string]types.EndpointsMap{},
 	}
 }
 
@@ -79,6 +94,16 @@ func (t *endpointsChangesTracker) OnEndpointUpdate(previous, current *corev1.End
 	t.Lock()
 	defer t.Unlock()
 
+	if t.endpointSliceEnabled {
+		return t.recordChange(namespacedName, func() {
+			if current == nil {
+				delete(t.endpointsCache, namespacedName)
+			} else {
+				t.endpointsCache[namespacedName] = t.endpointsToEndpointsMap(current)
+			}
+		})
+	}
+
 	change, exists := t.changes[namespacedName]
 	if !exists {
 		change = &endpointsChange{}
@@ -95,6 +120,88 @@ func (t *endpointsChangesTracker) OnEndpointUpdate(previous, current *corev1.End
 	return len(t.changes) > 0
 }
 
+// OnEndpointSliceUpdate updates given Service's Endpoints change map based on
+// the <previous, current> EndpointSlice pair. It returns true if items changed,
+// otherwise it returns false. It's used the same way as OnEndpointUpdate.
+func (t *endpointsChangesTracker) OnEndpointSliceUpdate(previous, current *discovery.EndpointSlice) bool {
+	endpointSlice := current
+	if endpointSlice == nil {
+		endpointSlice = previous
+	}
+	if endpointSlice == nil {
+		return false
+	}
+	serviceName, ok := endpointSlice.Labels[discovery.LabelServiceName]
+	if !ok || serviceName == "" {
+		klog.V(4).Infof("Ignoring EndpointSlice %s/%s without %s label", endpointSlice.Namespace, endpointSlice.Name, discovery.LabelServiceName)
+		return false
+	}
+	namespacedName := apimachinerytypes.NamespacedName{Namespace: endpointSlice.Namespace, Name: serviceName}
+
+	t.Lock()
+	defer t.Unlock()
+
+	return t.recordChange(namespacedName, func() {
+		endpointSlices := t.endpointSliceCache[namespacedName]
+		if current == nil {
+			delete(endpointSlices, endpointSlice.Name)
+			if len(endpointSlices) == 0 {
+				delete(t.endpointSliceCache, namespacedName)
+			}
+			return
+		}
+		if endpointSlices == nil {
+			endpointSlices = map[string]types.EndpointsMap{}
+			t.endpointSliceCache[namespacedName] = endpointSlices
+		}
+		endpointSlices[endpointSlice.Name] = t.endpointSliceToEndpointsMap(current)
+	})
+}
+
+// recordChange records the change of the given Service's EndpointsMap made by
+// updateCache, which updates endpointsCache or endpointSliceCache. It returns
+// true if there are pending changes. The caller must hold the lock.
+func (t *endpointsChangesTracker) recordChange(namespacedName apimachinerytypes.NamespacedName, updateCache func()) bool {
+	change, exists := t.changes[namespacedName]
+	if !exists {
+		change = &endpointsChange{}
+		change.previous = t.serviceEndpointsMap(namespacedName)
+		t.changes[namespacedName] = change
+	}
+
+	updateCache()
+
+	change.current = t.serviceEndpointsMap(namespacedName)
+	// If change.previous equals to change.current, it means no change.
+	if reflect.DeepEqual(change.previous, change.current) {
+		delete(t.changes, namespacedName)
+	}
+
+	return len(t.changes) > 0
+}
+
+// serviceEndpointsMap returns the EndpointsMap of the given Service from the
+// caches. The EndpointSlices of the Service take precedence over its Endpoints
+// if there is any, so that Services can be migrated progressively.
+func (t *endpointsChangesTracker) serviceEndpointsMap(namespacedName apimachinerytypes.NamespacedName) types.EndpointsMap {
+	endpointSlices, ok := t.endpointSliceCache[namespacedName]
+	if !ok {
+		return t.endpointsCache[namespacedName]
+	}
+	endpointsMap := make(types.EndpointsMap)
+	for _, sliceEndpointsMap := range endpointSlices {
+		for svcPortName, endpoints := range sliceEndpointsMap {
+			if _, ok := endpointsMap[svcPortName]; !ok {
+				endpointsMap[svcPortName] = map[string]k8sproxy.Endpoint{}
+			}
+			for key, endpoint := range endpoints {
+				endpointsMap[svcPortName][key] = endpoint
+			}
+		}
+	}
+	return endpointsMap
+}
+
 func (t *endpointsChangesTracker) checkoutChanges() []*endpointsChange {
 	t.Lock()
 	defer t.Unlock()
@@ -114,11 +221,18 @@ func (t *endpointsChangesTracker) OnEndpointsSynced() {
 	t.initialized = true
 }
 
+func (t *endpointsChangesTracker) OnEndpointSlicesSynced() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.endpointSlicesInitialized = true
+}
+
 func (t *endpointsChangesTracker) Synced() bool {
 	t.RLock()
 	defer t.RUnlock()
 
-	return t.initialized
+	return t.initialized && (!t.endpointSliceEnabled || t.endpointSlicesInitialized)
 }
 
 // endpointsToEndpointsMap translates single Endpoints object to EndpointsMap.
@@ -153,10 +267,15 @@ func (t *endpointsChangesTracker) endpointsToEndpointsMap(endpoints *corev1.Endp
 					continue
 				}
 				isLocal := addr.NodeName != nil && *addr.NodeName == t.hostname
-				ei := types.NewEndpointInfo(&k8sproxy.BaseEndpointInfo{
+				baseInfo := &k8sproxy.BaseEndpointInfo{
 					Endpoint: net.JoinHostPort(addr.IP, fmt.Sprint(port.Port)),
 					IsLocal:  isLocal,
-				})
+				}
+				// The Node of the Endpoint is used to look up its zone.
+				if addr.NodeName != nil {
+					baseInfo.Topology = map[string]string{corev1.LabelHostname: *addr.NodeName}
+				}
+				ei := types.NewEndpointInfo(baseInfo)
 				endpointsMap[svcPortName][ei.String()] = ei
 			}
 		}
@@ -164,6 +283,63 @@ func (t *endpointsChangesTracker) endpointsToEndpointsMap(endpoints *corev1.Endp
 	return endpointsMap
 }
 
+// endpointSliceToEndpointsMap translates single EndpointSlice object to
+// EndpointsMap. Only the ready endpoints are included.
+func (t *endpointsChangesTracker) endpointSliceToEndpointsMap(endpointSlice *discovery.EndpointSlice) types.EndpointsMap {
+	endpointsMap := make(types.EndpointsMap)
+	if endpointSlice.AddressType == discovery.AddressTypeFQDN {
+		klog.V(4).Infof("Ignoring EndpointSlice %s/%s with FQDN addresses", endpointSlice.Namespace, endpointSlice.Name)
+		return endpointsMap
+	}
+	serviceName := endpointSlice.Labels[discovery.LabelServiceName]
+	for i := range endpointSlice.Ports {
+		port := &endpointSlice.Ports[i]
+		portName := ""
+		if port.Name != nil {
+			portName = *port.Name
+		}
+		if port.Port == nil || *port.Port == 0 {
+			klog.Warningf("Ignoring invalid endpoint port %s", portName)
+			continue
+		}
+		protocol := corev1.ProtocolTCP
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+		svcPortName := k8sproxy.ServicePortName{
+			NamespacedName: apimachinerytypes.NamespacedName{Namespace: endpointSlice.Namespace, Name: serviceName},
+			Protocol:       protocol,
+			Port:           portName,
+		}
+		endpointsMap[svcPortName] = map[string]k8sproxy.Endpoint{}
+		for j := range endpointSlice.Endpoints {
+			endpoint := &endpointSlice.Endpoints[j]
+			if len(endpoint.Addresses) == 0 {
+				klog.Warningf("Ignoring invalid endpoint port %s with empty host", portName)
+				continue
+			}
+			// A nil Ready condition should be interpreted as "unknown" and
+			// the endpoint is considered ready.
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			// All the addresses of an endpoint are fungible, only the first
+			// one is used, as kube-proxy does.
+			nodeName := endpoint.Topology[corev1.LabelHostname]
+			baseInfo := &k8sproxy.BaseEndpointInfo{
+				Endpoint: net.JoinHostPort(endpoint.Addresses[0], fmt.Sprint(*port.Port)),
+				IsLocal:  nodeName != "" && nodeName == t.hostname,
+			}
+			if nodeName != "" {
+				baseInfo.Topology = map[string]string{corev1.LabelHostname: nodeName}
+			}
+			ei := types.NewEndpointInfo(baseInfo)
+			endpointsMap[svcPortName][ei.String()] = ei
+		}
+	}
+	return endpointsMap
+}
+
 // Update updates an EndpointsMap based on current changes and returns stale
 // Endpoints of each Service.
 func (t *endpointsChangesTracker) Update(em types.EndpointsMap) map[k8sproxy.ServicePortName]map[string]k8sproxy.Endpoint {
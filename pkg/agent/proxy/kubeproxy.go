@@ -0,0 +1,137 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+)
+
+const (
+	KubeProxyModeIPTables = "iptables"
+	KubeProxyModeIPVS     = "ipvs"
+
+	// kubeProxyIPVSInterface is the dummy interface to which kube-proxy binds the ClusterIPs in IPVS mode.
+	kubeProxyIPVSInterface = "kube-ipvs0"
+
+	reasonKubeProxyCoexistence = "KubeProxyCoexistence"
+	reasonKubeProxyConflict    = "KubeProxyConflict"
+)
+
+var (
+	// kubeProxyModeURL is the endpoint of the kube-proxy metrics server reporting its mode, which listens on
+	// 127.0.0.1:10249 by default. It can be overridden in tests.
+	kubeProxyModeURL = "http://127.0.0.1:10249/proxyMode"
+	// hasKubeProxyIPVSInterface and hasKubeProxyIPTablesChains can be overridden in tests.
+	hasKubeProxyIPVSInterface = func() bool {
+		_, err := net.InterfaceByName(kubeProxyIPVSInterface)
+		return err == nil
+	}
+	hasKubeProxyIPTablesChains = kubeProxyIPTablesChainsExist
+)
+
+// DetectKubeProxyMode returns the mode of the kube-proxy running on the Node, or an empty string if kube-proxy is not
+// detected. The mode is queried from the kube-proxy metrics server. If it cannot be reached, e.g. because it listens
+// on another address, the mode is inferred from the configuration kube-proxy leaves in the host network: the dummy
+// interface of the IPVS mode, or the iptables chains of the iptables mode.
+func DetectKubeProxyMode() string {
+	client := http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(kubeProxyModeURL)
+	if err == nil {
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			return strings.TrimSpace(string(body))
+		}
+	}
+	klog.V(2).Infof("Unable to query the mode of kube-proxy from %s, inferring it from the host network", kubeProxyModeURL)
+	if hasKubeProxyIPVSInterface() {
+		return KubeProxyModeIPVS
+	}
+	if hasKubeProxyIPTablesChains() {
+		return KubeProxyModeIPTables
+	}
+	return ""
+}
+
+type kubeProxyEvent struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+// kubeProxyCoexistenceEvents returns the Events reporting how the traffic is split between kube-proxy in the provided
+// mode and the AntreaProxy options serving the traffic kube-proxy serves otherwise.
+func kubeProxyCoexistenceEvents(mode string, nodePortEnabled, hostNetworkEnabled bool) []kubeProxyEvent {
+	var events []kubeProxyEvent
+	switch mode {
+	case "":
+	case KubeProxyModeIPTables:
+		if nodePortEnabled {
+			events = append(events, kubeProxyEvent{corev1.EventTypeNormal, reasonKubeProxyCoexistence,
+				"kube-proxy runs in iptables mode: the NodePorts are served by AntreaProxy, whose iptables rules take precedence over the ones of kube-proxy"})
+		}
+		if hostNetworkEnabled {
+			events = append(events, kubeProxyEvent{corev1.EventTypeNormal, reasonKubeProxyCoexistence,
+				"kube-proxy runs in iptables mode: the ClusterIPs accessed from the Node network namespace are served by AntreaProxy and skip the DNAT of kube-proxy"})
+		}
+	case KubeProxyModeIPVS:
+		if nodePortEnabled {
+			events = append(events, kubeProxyEvent{corev1.EventTypeNormal, reasonKubeProxyCoexistence,
+				"kube-proxy runs in IPVS mode: the NodePorts are served by AntreaProxy, whose iptables rules DNAT the traffic before it reaches IPVS"})
+		}
+		if hostNetworkEnabled {
+			events = append(events, kubeProxyEvent{corev1.EventTypeWarning, reasonKubeProxyConflict,
+				fmt.Sprintf("kube-proxy runs in IPVS mode and binds the ClusterIPs to %s: the ClusterIPs accessed from the Node network namespace are served by kube-proxy instead of AntreaProxy, whose hostNetwork option should be disabled unless kube-proxy is removed", kubeProxyIPVSInterface)})
+		}
+	default:
+		if nodePortEnabled || hostNetworkEnabled {
+			events = append(events, kubeProxyEvent{corev1.EventTypeWarning, reasonKubeProxyConflict,
+				fmt.Sprintf("kube-proxy runs in %s mode, which is not supported alongside the nodePort and hostNetwork options of AntreaProxy: the traffic may be served by either of them", mode)})
+		}
+	}
+	return events
+}
+
+// ReportKubeProxyCoexistence detects the kube-proxy running on the Node and emits Events for the Node describing how
+// it coexists with the enabled AntreaProxy options, so that the conflicts are visible instead of silently
+// mis-routing traffic. The iptables rules of AntreaProxy are reconciled by the route client to take precedence over
+// the ones of kube-proxy.
+func ReportKubeProxyCoexistence(nodeName string, recorder record.EventRecorder, nodePortEnabled, hostNetworkEnabled bool) {
+	mode := DetectKubeProxyMode()
+	if mode == "" {
+		klog.Info("kube-proxy is not detected on the Node")
+		return
+	}
+	klog.Infof("Detected kube-proxy in %s mode", mode)
+	nodeRef := &corev1.ObjectReference{Kind: "Node", Name: nodeName, UID: types.UID(nodeName)}
+	for _, e := range kubeProxyCoexistenceEvents(mode, nodePortEnabled, hostNetworkEnabled) {
+		if e.eventType == corev1.EventTypeWarning {
+			klog.Warning(e.message)
+		} else {
+			klog.Info(e.message)
+		}
+		recorder.Event(nodeRef, e.eventType, e.reason, e.message)
+	}
+}
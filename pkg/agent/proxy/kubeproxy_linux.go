@@ -0,0 +1,38 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/iptables"
+)
+
+// kubeProxyServicesChain is the iptables chain of kube-proxy matching the Service traffic.
+const kubeProxyServicesChain = "KUBE-SERVICES"
+
+func kubeProxyIPTablesChainsExist() bool {
+	ipt, err := iptables.New()
+	if err != nil {
+		klog.Errorf("Failed to create iptables client: %v", err)
+		return false
+	}
+	exists, err := ipt.ChainExists(iptables.NATTable, kubeProxyServicesChain)
+	if err != nil {
+		klog.Errorf("Failed to check the existence of chain %s: %v", kubeProxyServicesChain, err)
+		return false
+	}
+	return exists
+}
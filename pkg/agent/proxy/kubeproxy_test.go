@@ -0,0 +1,91 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDetectKubeProxyMode(t *testing.T) {
+	defer func(url string, hasIPVSInterface, hasIPTablesChains func() bool) {
+		kubeProxyModeURL = url
+		hasKubeProxyIPVSInterface = hasIPVSInterface
+		hasKubeProxyIPTablesChains = hasIPTablesChains
+	}(kubeProxyModeURL, hasKubeProxyIPVSInterface, hasKubeProxyIPTablesChains)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ipvs")
+	}))
+	defer server.Close()
+	unreachableServer := httptest.NewServer(http.NotFoundHandler())
+	defer unreachableServer.Close()
+
+	tests := []struct {
+		name              string
+		url               string
+		hasIPVSInterface  bool
+		hasIPTablesChains bool
+		expectedMode      string
+	}{
+		{"metrics server", server.URL, false, true, KubeProxyModeIPVS},
+		{"IPVS interface", unreachableServer.URL, true, true, KubeProxyModeIPVS},
+		{"iptables chains", unreachableServer.URL, false, true, KubeProxyModeIPTables},
+		{"no kube-proxy", unreachableServer.URL, false, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeProxyModeURL = tt.url
+			hasKubeProxyIPVSInterface = func() bool { return tt.hasIPVSInterface }
+			hasKubeProxyIPTablesChains = func() bool { return tt.hasIPTablesChains }
+			if mode := DetectKubeProxyMode(); mode != tt.expectedMode {
+				t.Errorf("Expected mode %q, got %q", tt.expectedMode, mode)
+			}
+		})
+	}
+}
+
+func TestKubeProxyCoexistenceEvents(t *testing.T) {
+	tests := []struct {
+		name               string
+		mode               string
+		nodePortEnabled    bool
+		hostNetworkEnabled bool
+		expectedTypes      []string
+	}{
+		{"no kube-proxy", "", true, true, nil},
+		{"iptables", KubeProxyModeIPTables, true, true, []string{corev1.EventTypeNormal, corev1.EventTypeNormal}},
+		{"ipvs with nodePort", KubeProxyModeIPVS, true, false, []string{corev1.EventTypeNormal}},
+		{"ipvs with hostNetwork", KubeProxyModeIPVS, false, true, []string{corev1.EventTypeWarning}},
+		{"userspace", "userspace", true, false, []string{corev1.EventTypeWarning}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := kubeProxyCoexistenceEvents(tt.mode, tt.nodePortEnabled, tt.hostNetworkEnabled)
+			if len(events) != len(tt.expectedTypes) {
+				t.Fatalf("Expected %d Events, got %d", len(tt.expectedTypes), len(events))
+			}
+			for i := range events {
+				if events[i].eventType != tt.expectedTypes[i] {
+					t.Errorf("Expected Event %d of type %s, got %s", i, tt.expectedTypes[i], events[i].eventType)
+				}
+			}
+		})
+	}
+}
@@ -15,29 +15,53 @@
 package proxy
 
 import (
+	"fmt"
 	"net"
+	"reflect"
 	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
+	discovery "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
-	"k8s.io/client-go/tools/record"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog"
 
+	agentconfig "github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/agent/proxy/types"
 	"github.com/vmware-tanzu/antrea/pkg/agent/querier"
+	"github.com/vmware-tanzu/antrea/pkg/agent/route"
 	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 	"github.com/vmware-tanzu/antrea/third_party/proxy/config"
+	"github.com/vmware-tanzu/antrea/third_party/proxy/healthcheck"
 )
 
 const (
 	resyncPeriod  = time.Minute
 	componentName = "antrea-agent-proxy"
+	// labelServiceProxyName is set on the Services which should be handled by
+	// an alternative proxy.
+	labelServiceProxyName = "service.kubernetes.io/service-proxy-name"
 )
 
+// TweakListOptions should be used for the informers of the Services, Endpoints
+// and EndpointSlices passed to AntreaProxy. It filters out the Services handled
+// by an alternative proxy and the Endpoints of the headless Services, so that
+// they are not cached by the agent.
+func TweakListOptions(options *metav1.ListOptions) {
+	noProxyName, _ := labels.NewRequirement(labelServiceProxyName, selection.DoesNotExist, nil)
+	noHeadlessEndpoints, _ := labels.NewRequirement(corev1.IsHeadlessService, selection.DoesNotExist, nil)
+	options.LabelSelector = labels.NewSelector().Add(*noProxyName, *noHeadlessEndpoints).String()
+}
+
 var _ Proxier = new(proxier)
 
 type Proxier interface {
@@ -50,6 +74,8 @@ type proxier struct {
 	once            sync.Once
 	endpointsConfig *config.EndpointsConfig
 	serviceConfig   *config.ServiceConfig
+	// endpointSliceConfig is only set when the EndpointSlice feature is enabled.
+	endpointSliceConfig *config.EndpointSliceConfig
 	// endpointsChanges and serviceChanges contains all changes to endpoints and
 	// services that happened since last syncProxyRules call. For a single object,
 	// changes are accumulated. Once both endpointsChanges and serviceChanges
@@ -70,10 +96,59 @@ type proxier struct {
 	// serviceStringMapMutex protects serviceStringMap object.
 	serviceStringMapMutex sync.Mutex
 
+	// flowRestoreCompleteWait is notified once the flows of the initial Services and Endpoints have been installed.
+	flowRestoreCompleteWait *sync.WaitGroup
+	initialSyncOnce         sync.Once
+
 	runner       *k8sproxy.BoundedFrequencyRunner
 	stopChan     <-chan struct{}
 	agentQuerier querier.AgentQuerier
 	ofClient     openflow.Client
+	routeClient  route.Interface
+	// nodePortAddresses are the Node addresses on which the NodePort Services
+	// are served. It is empty when AntreaProxy doesn't serve them.
+	nodePortAddresses []net.IP
+	// serviceHealthServer serves the health check NodePorts of the LoadBalancer
+	// Services with the Local external traffic policy. It's only set when the
+	// NodePort Services are served.
+	serviceHealthServer healthcheck.ServiceHealthServer
+	// loadBalancerDSR indicates whether the traffic to the LoadBalancer IPs from outside the cluster is forwarded
+	// to the remote Endpoints in DSR mode.
+	loadBalancerDSR bool
+	// loadBalancerLocalFastPath indicates whether the traffic to the LoadBalancer IPs of the Services with the Local
+	// external traffic policy from outside the cluster is routed to OVS without DNAT nor conntrack in the host network.
+	loadBalancerLocalFastPath bool
+	// hostname is the name of this Node, and nodeLister is used to look up
+	// the zones of the Nodes for the topology aware Services.
+	hostname   string
+	nodeLister corelisters.NodeLister
+	// endpointDrainTimeout is how long the Endpoints removed from a Service
+	// are drained before their flows are removed. Draining is disabled when
+	// it is zero.
+	endpointDrainTimeout time.Duration
+	// drainingEndpoints stores the Endpoints being drained, keyed by
+	// drainingEndpointKey.
+	drainingEndpoints map[string]*drainingEndpoint
+	// eventRecorder reports the failures to install the flows of the
+	// Services as Events on the Services.
+	eventRecorder *events.Recorder
+}
+
+// drainingEndpoint is an Endpoint removed from a Service, which is no longer
+// selected for new connections, but whose flows are kept until deadline so
+// that its established connections are not reset. The version of the
+// EndpointSlice API used by AntreaProxy doesn't expose the terminating
+// condition of the Endpoints, so any removed Endpoint is drained: the Pods
+// being deleted are removed from the Endpoints and EndpointSlices as soon as
+// they start terminating.
+type drainingEndpoint struct {
+	protocol binding.Protocol
+	endpoint k8sproxy.Endpoint
+	deadline time.Time
+}
+
+func drainingEndpointKey(protocol binding.Protocol, endpoint k8sproxy.Endpoint) string {
+	return fmt.Sprintf("%s/%s", endpoint.String(), protocol)
 }
 
 func (p *proxier) isInitialized() bool {
@@ -98,7 +173,29 @@ func (p *proxier) removeStaleServices() {
 				}
 			}
 		}
-		groupID, _ := p.groupCounter.Get(svcPortName)
+		if err := p.uninstallNodePortService(svcPortName, svcInfo); err != nil {
+			klog.Errorf("Failed to remove NodePort flows of Service %v: %v", svcPortName, err)
+			continue
+		}
+		if p.loadBalancerDSR {
+			if err := p.uninstallLoadBalancerServiceDSR(svcPortName, svcInfo); err != nil {
+				klog.Errorf("Failed to remove LoadBalancer DSR flows of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
+		if p.usesLoadBalancerLocalFastPath(svcInfo) {
+			if err := p.uninstallLoadBalancerServiceLocalFastPath(svcPortName, svcInfo); err != nil {
+				klog.Errorf("Failed to remove LoadBalancer fast path of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
+		if p.needLocalGroup(svcInfo) {
+			if err := p.uninstallLocalGroup(svcPortName); err != nil {
+				klog.Errorf("Failed to remove local group of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
+		groupID, _ := p.groupCounter.Get(svcPortName, false)
 		if err := p.ofClient.UninstallServiceGroup(groupID); err != nil {
 			klog.Errorf("Failed to remove flows of Service %v: %v", svcPortName, err)
 			continue
@@ -108,10 +205,11 @@ func (p *proxier) removeStaleServices() {
 				klog.Errorf("Failed to remove flows of Service Endpoints %v: %v", svcPortName, err)
 				continue
 			}
+			delete(p.drainingEndpoints, drainingEndpointKey(svcInfo.OFProtocol, endpoint))
 		}
 		delete(p.serviceInstalledMap, svcPortName)
 		p.deleteServiceByIP(svcInfo.String())
-		p.groupCounter.Recycle(svcPortName)
+		p.groupCounter.Recycle(svcPortName, false)
 	}
 }
 
@@ -124,7 +222,17 @@ func (p *proxier) removeStaleEndpoints(staleEndpoints map[k8sproxy.ServicePortNa
 			bindingProtocol = binding.ProtocolSCTP
 		}
 		for _, endpoint := range endpoints {
-			if err := p.ofClient.UninstallEndpointFlows(bindingProtocol, endpoint); err != nil {
+			if p.endpointDrainTimeout > 0 {
+				if err := p.ofClient.DrainEndpointFlows(bindingProtocol, endpoint); err != nil {
+					klog.Errorf("Error when draining Endpoint %v for %v: %v", endpoint, svcPortName, err)
+					continue
+				}
+				p.drainingEndpoints[drainingEndpointKey(bindingProtocol, endpoint)] = &drainingEndpoint{
+					protocol: bindingProtocol,
+					endpoint: endpoint,
+					deadline: time.Now().Add(p.endpointDrainTimeout),
+				}
+			} else if err := p.ofClient.UninstallEndpointFlows(bindingProtocol, endpoint); err != nil {
 				klog.Errorf("Error when removing Endpoint %v for %v", endpoint, svcPortName)
 				continue
 			}
@@ -138,31 +246,99 @@ func (p *proxier) removeStaleEndpoints(staleEndpoints map[k8sproxy.ServicePortNa
 	}
 }
 
+// removeDrainedEndpoints removes the flows of the draining Endpoints whose
+// deadline has passed. As the rules are synced at least every 30 seconds, an
+// Endpoint may be drained for up to 30 seconds more than endpointDrainTimeout.
+func (p *proxier) removeDrainedEndpoints() {
+	now := time.Now()
+	for key, drainingEndpoint := range p.drainingEndpoints {
+		if now.Before(drainingEndpoint.deadline) {
+			continue
+		}
+		if err := p.ofClient.UninstallEndpointFlows(drainingEndpoint.protocol, drainingEndpoint.endpoint); err != nil {
+			klog.Errorf("Error when removing drained Endpoint %v: %v", drainingEndpoint.endpoint, err)
+			continue
+		}
+		delete(p.drainingEndpoints, key)
+	}
+}
+
+// nodeZone returns the zone of the Node, or an empty string when it is unknown.
+func (p *proxier) nodeZone(nodeName string) string {
+	if p.nodeLister == nil || nodeName == "" {
+		return ""
+	}
+	node, err := p.nodeLister.Get(nodeName)
+	if err != nil {
+		return ""
+	}
+	if zone, ok := node.Labels[corev1.LabelZoneFailureDomainStable]; ok {
+		return zone
+	}
+	return node.Labels[corev1.LabelZoneFailureDomain]
+}
+
+// filterEndpointsByZone returns the Endpoints in the same zone as this Node.
+// All the Endpoints are returned when the zone of this Node is unknown or when
+// none of them is in the zone, so that the Service stays reachable.
+func (p *proxier) filterEndpointsByZone(endpoints []k8sproxy.Endpoint) []k8sproxy.Endpoint {
+	zone := p.nodeZone(p.hostname)
+	if zone == "" {
+		return endpoints
+	}
+	var zoneEndpoints []k8sproxy.Endpoint
+	for _, endpoint := range endpoints {
+		if p.nodeZone(endpoint.GetTopology()[corev1.LabelHostname]) == zone {
+			zoneEndpoints = append(zoneEndpoints, endpoint)
+		}
+	}
+	if len(zoneEndpoints) == 0 {
+		return endpoints
+	}
+	return zoneEndpoints
+}
+
+// reportServiceFailure logs the failure to realize the Service and reports it
+// as an Event on the Service.
+func (p *proxier) reportServiceFailure(svcPortName k8sproxy.ServicePortName, message string, err error) {
+	klog.Errorf("%s of Service %v: %v", message, svcPortName, err)
+	p.eventRecorder.ServiceFailure(svcPortName.Namespace, svcPortName.Name, events.ReasonServiceRealizationFailed, message, err)
+}
+
 func (p *proxier) installServices() {
 	for svcPortName, svcPort := range p.serviceMap {
 		svcInfo := svcPort.(*types.ServiceInfo)
-		groupID, _ := p.groupCounter.Get(svcPortName)
+		groupID, _ := p.groupCounter.Get(svcPortName, false)
 		endpoints, ok := p.endpointsMap[svcPortName]
 		if !ok || len(endpoints) == 0 {
 			continue
 		}
 
-		endpointInstalled, ok := p.endpointInstalledMap[svcPortName]
-		if !ok {
-			p.endpointInstalledMap[svcPortName] = map[string]struct{}{}
-			endpointInstalled = p.endpointInstalledMap[svcPortName]
-		}
-
-		installedSvcPort, ok := p.serviceInstalledMap[svcPortName]
-		needUpdate := !ok || !installedSvcPort.(*types.ServiceInfo).Equal(svcInfo)
+		endpointInstalled := p.endpointInstalledMap[svcPortName]
+		installedSvcPort, installed := p.serviceInstalledMap[svcPortName]
+		needUpdate := !installed || !installedSvcPort.(*types.ServiceInfo).Equal(svcInfo)
 
 		var endpointUpdateList []k8sproxy.Endpoint
 		for _, endpoint := range endpoints {
+			// With the Local internal traffic policy, only the Endpoints on
+			// this Node are selected. The group has no bucket when there is
+			// none, and the traffic is dropped.
+			if svcInfo.OnlyNodeLocalInternalEndpoints && !endpoint.GetIsLocal() {
+				continue
+			}
+			endpointUpdateList = append(endpointUpdateList, endpoint)
+		}
+		if svcInfo.TopologyAware {
+			endpointUpdateList = p.filterEndpointsByZone(endpointUpdateList)
+		}
+		for _, endpoint := range endpointUpdateList {
 			if _, ok := endpointInstalled[endpoint.String()]; !ok {
 				needUpdate = true
-				endpointInstalled[endpoint.String()] = struct{}{}
 			}
-			endpointUpdateList = append(endpointUpdateList, endpoint)
+		}
+		// Some installed Endpoints were removed or are no longer selected.
+		if len(endpointUpdateList) < len(endpointInstalled) {
+			needUpdate = true
 		}
 
 		if !needUpdate {
@@ -170,35 +346,299 @@ func (p *proxier) installServices() {
 		}
 
 		if err := p.ofClient.InstallEndpointFlows(svcInfo.OFProtocol, endpointUpdateList); err != nil {
-			klog.Errorf("Error when installing Endpoints flows: %v", err)
+			p.reportServiceFailure(svcPortName, "Failed to install the Endpoints flows", err)
 			continue
 		}
+		// The draining Endpoints which are selected again are no longer drained.
+		for _, endpoint := range endpointUpdateList {
+			delete(p.drainingEndpoints, drainingEndpointKey(svcInfo.OFProtocol, endpoint))
+		}
 		err := p.ofClient.InstallServiceGroup(groupID, svcInfo.StickyMaxAgeSeconds() != 0, endpointUpdateList)
 		if err != nil {
-			klog.Errorf("Error when installing Endpoints groups: %v", err)
-			p.endpointInstalledMap[svcPortName] = nil
+			p.reportServiceFailure(svcPortName, "Failed to install the Endpoints group", err)
+			delete(p.endpointInstalledMap, svcPortName)
 			continue
 		}
-		if err := p.ofClient.InstallServiceFlows(groupID, svcInfo.ClusterIP(), uint16(svcInfo.Port()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
-			klog.Errorf("Error when installing Service flows: %v", err)
+		endpointInstalled = make(map[string]struct{}, len(endpointUpdateList))
+		for _, endpoint := range endpointUpdateList {
+			endpointInstalled[endpoint.String()] = struct{}{}
+		}
+		p.endpointInstalledMap[svcPortName] = endpointInstalled
+		if err := p.ofClient.InstallServiceFlows(groupID, svcInfo.ClusterIP(), uint16(svcInfo.Port()), svcInfo.OFProtocol, svcInfo.AffinityTimeout()); err != nil {
+			p.reportServiceFailure(svcPortName, "Failed to install the Service flows", err)
 			continue
 		}
+		// The group of the local Endpoints is used by the NodePort and the
+		// LoadBalancer flows installed below.
+		if p.needLocalGroup(svcInfo) {
+			if err := p.installLocalGroup(svcPortName, svcInfo, endpointUpdateList); err != nil {
+				p.reportServiceFailure(svcPortName, "Failed to install the local group", err)
+				continue
+			}
+		}
 		// Install OpenFlow entries for the ingress IPs of LoadBalancer Service.
 		// The LoadBalancer Service should can be accessed from Pod, Node and
 		// external host.
 		for _, ingress := range svcInfo.LoadBalancerIPStrings() {
 			if ingress != "" {
-				if err := p.installLoadBalancerServiceFlows(groupID, net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
-					klog.Errorf("Error when installing LoadBalancer Service flows: %v", err)
+				if err := p.installLoadBalancerServiceFlows(groupID, net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol, svcInfo.AffinityTimeout()); err != nil {
+					p.reportServiceFailure(svcPortName, "Failed to install the LoadBalancer flows", err)
+					continue
+				}
+			}
+		}
+		// The DSR flows of the LoadBalancer IPs which are no longer used
+		// by the Service are removed.
+		if installed && p.loadBalancerDSR {
+			installedSvcInfo := installedSvcPort.(*types.ServiceInfo)
+			if !reflect.DeepEqual(installedSvcInfo.LoadBalancerIPStrings(), svcInfo.LoadBalancerIPStrings()) {
+				if err := p.uninstallLoadBalancerServiceDSR(svcPortName, installedSvcInfo); err != nil {
+					p.reportServiceFailure(svcPortName, "Failed to remove the LoadBalancer DSR flows", err)
+					continue
+				}
+			}
+		}
+		if p.loadBalancerDSR {
+			if err := p.installLoadBalancerServiceDSR(svcPortName, svcInfo); err != nil {
+				p.reportServiceFailure(svcPortName, "Failed to install the LoadBalancer DSR flows", err)
+				continue
+			}
+		}
+		// The fast path of the LoadBalancer IPs is removed when they, the
+		// port or the external traffic policy of the Service change.
+		if installed {
+			installedSvcInfo := installedSvcPort.(*types.ServiceInfo)
+			if p.usesLoadBalancerLocalFastPath(installedSvcInfo) && (!p.usesLoadBalancerLocalFastPath(svcInfo) ||
+				installedSvcInfo.Port() != svcInfo.Port() ||
+				!reflect.DeepEqual(installedSvcInfo.LoadBalancerIPStrings(), svcInfo.LoadBalancerIPStrings())) {
+				if err := p.uninstallLoadBalancerServiceLocalFastPath(svcPortName, installedSvcInfo); err != nil {
+					p.reportServiceFailure(svcPortName, "Failed to remove the LoadBalancer fast path", err)
 					continue
 				}
 			}
 		}
+		if p.usesLoadBalancerLocalFastPath(svcInfo) {
+			if err := p.installLoadBalancerServiceLocalFastPath(svcPortName, svcInfo); err != nil {
+				p.reportServiceFailure(svcPortName, "Failed to install the LoadBalancer fast path", err)
+				continue
+			}
+		}
+		// The NodePort flows are reinstalled when the NodePort or the
+		// external traffic policy of the Service changes.
+		if installed {
+			installedSvcInfo := installedSvcPort.(*types.ServiceInfo)
+			if installedSvcInfo.NodePort() != svcInfo.NodePort() || installedSvcInfo.OnlyNodeLocalEndpoints() != svcInfo.OnlyNodeLocalEndpoints() {
+				if err := p.uninstallNodePortService(svcPortName, installedSvcInfo); err != nil {
+					p.reportServiceFailure(svcPortName, "Failed to remove the NodePort flows", err)
+					continue
+				}
+			}
+		}
+		if err := p.installNodePortService(svcPortName, svcInfo, groupID); err != nil {
+			p.reportServiceFailure(svcPortName, "Failed to install the NodePort flows", err)
+			continue
+		}
+		// The local group is removed once it is no longer used.
+		if installed && p.needLocalGroup(installedSvcPort.(*types.ServiceInfo)) && !p.needLocalGroup(svcInfo) {
+			if err := p.uninstallLocalGroup(svcPortName); err != nil {
+				p.reportServiceFailure(svcPortName, "Failed to remove the local group", err)
+				continue
+			}
+		}
 		p.serviceInstalledMap[svcPortName] = svcPort
 		p.addServiceByIP(svcInfo.String(), svcPortName)
 	}
 }
 
+// needLocalGroup returns whether the Service needs the group selecting only the
+// Endpoints on this Node, for the NodePort and the LoadBalancer traffic with
+// the Local external traffic policy, or for the LoadBalancer traffic forwarded
+// by other Nodes in DSR mode.
+func (p *proxier) needLocalGroup(svcInfo *types.ServiceInfo) bool {
+	if len(p.nodePortAddresses) != 0 && svcInfo.NodePort() != 0 && svcInfo.OnlyNodeLocalEndpoints() {
+		return true
+	}
+	if p.loadBalancerDSR && len(loadBalancerIPs(svcInfo)) != 0 {
+		return true
+	}
+	return p.usesLoadBalancerLocalFastPath(svcInfo)
+}
+
+// usesLoadBalancerLocalFastPath returns whether the traffic to the LoadBalancer
+// IPs of the Service from outside the cluster takes the fast path installed by
+// installLoadBalancerServiceLocalFastPath.
+func (p *proxier) usesLoadBalancerLocalFastPath(svcInfo *types.ServiceInfo) bool {
+	return p.loadBalancerLocalFastPath && svcInfo.OnlyNodeLocalEndpoints() && len(loadBalancerIPs(svcInfo)) != 0
+}
+
+// installLocalGroup installs the group selecting the Endpoints on this Node.
+// The traffic load-balanced with it is dropped when there is none.
+func (p *proxier) installLocalGroup(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo, endpoints []k8sproxy.Endpoint) error {
+	groupID, _ := p.groupCounter.Get(svcPortName, true)
+	var localEndpoints []k8sproxy.Endpoint
+	for _, endpoint := range endpoints {
+		if endpoint.GetIsLocal() {
+			localEndpoints = append(localEndpoints, endpoint)
+		}
+	}
+	return p.ofClient.InstallServiceGroup(groupID, svcInfo.StickyMaxAgeSeconds() != 0, localEndpoints)
+}
+
+// uninstallLocalGroup removes the group installed by installLocalGroup.
+func (p *proxier) uninstallLocalGroup(svcPortName k8sproxy.ServicePortName) error {
+	groupID, _ := p.groupCounter.Get(svcPortName, true)
+	if err := p.ofClient.UninstallServiceGroup(groupID); err != nil {
+		return err
+	}
+	p.groupCounter.Recycle(svcPortName, true)
+	return nil
+}
+
+// loadBalancerIPs returns the LoadBalancer IPs of the Service.
+func loadBalancerIPs(svcInfo *types.ServiceInfo) []net.IP {
+	var ips []net.IP
+	for _, ingress := range svcInfo.LoadBalancerIPStrings() {
+		if ingress != "" {
+			ips = append(ips, net.ParseIP(ingress))
+		}
+	}
+	return ips
+}
+
+// installLoadBalancerServiceDSR installs the flows and the host network
+// configuration forwarding the traffic to the LoadBalancer IPs of the Service
+// from outside the cluster in DSR mode. The traffic is routed to OVS without
+// DNAT, and it is forwarded as is to the Node of the selected Endpoint if the
+// Endpoint is remote. That Node load-balances it to its local Endpoints with
+// the local group, and replies to the client directly.
+func (p *proxier) installLoadBalancerServiceDSR(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo) error {
+	localGroupID, _ := p.groupCounter.Get(svcPortName, true)
+	for _, ip := range loadBalancerIPs(svcInfo) {
+		if err := p.ofClient.InstallLoadBalancerServiceDSRFlows(localGroupID, ip, uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+			return err
+		}
+		if err := p.routeClient.AddLoadBalancer(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uninstallLoadBalancerServiceDSR removes what was installed by
+// installLoadBalancerServiceDSR for the Service. The route to a LoadBalancer IP
+// is kept while other Services use it.
+func (p *proxier) uninstallLoadBalancerServiceDSR(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo) error {
+	for _, ip := range loadBalancerIPs(svcInfo) {
+		if err := p.ofClient.UninstallLoadBalancerServiceDSRFlows(ip, uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+			return err
+		}
+		if p.isLoadBalancerIPInUse(svcPortName, ip) {
+			continue
+		}
+		if err := p.routeClient.DeleteLoadBalancer(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLoadBalancerIPInUse returns whether the LoadBalancer IP is routed to OVS
+// for another installed Service than svcPortName.
+func (p *proxier) isLoadBalancerIPInUse(svcPortName k8sproxy.ServicePortName, ip net.IP) bool {
+	for name, svcPort := range p.serviceInstalledMap {
+		if name == svcPortName {
+			continue
+		}
+		svcInfo := svcPort.(*types.ServiceInfo)
+		if !p.loadBalancerDSR && !p.usesLoadBalancerLocalFastPath(svcInfo) {
+			continue
+		}
+		for _, svcIP := range loadBalancerIPs(svcInfo) {
+			if svcIP.Equal(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// installLoadBalancerServiceLocalFastPath installs the flows and the host
+// network configuration forwarding the traffic to the LoadBalancer IPs of a
+// Service with the Local external traffic policy from outside the cluster to
+// the local Endpoints. Unlike the NodePort traffic, which must be DNAT'd to the
+// NodePort virtual IP by the host network as it is destined to the Node, the
+// LoadBalancer IPs are routed to OVS as they are, and their traffic is not
+// tracked by the host network: it is only DNAT'd once, by OVS, and the client
+// IP is preserved without any masquerading decision. The local group must have
+// been installed before.
+func (p *proxier) installLoadBalancerServiceLocalFastPath(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo) error {
+	localGroupID, _ := p.groupCounter.Get(svcPortName, true)
+	for _, ip := range loadBalancerIPs(svcInfo) {
+		if err := p.ofClient.InstallLoadBalancerServiceLocalFlows(localGroupID, ip, uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+			return err
+		}
+		if err := p.routeClient.AddLocalLoadBalancer(ip, uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uninstallLoadBalancerServiceLocalFastPath removes what was installed by
+// installLoadBalancerServiceLocalFastPath for the Service. The route to a
+// LoadBalancer IP is kept while other Services use it.
+func (p *proxier) uninstallLoadBalancerServiceLocalFastPath(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo) error {
+	for _, ip := range loadBalancerIPs(svcInfo) {
+		if err := p.ofClient.UninstallLoadBalancerServiceLocalFlows(ip, uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+			return err
+		}
+		if err := p.routeClient.DeleteLocalLoadBalancer(ip, uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
+			return err
+		}
+		if p.isLoadBalancerIPInUse(svcPortName, ip) {
+			continue
+		}
+		if err := p.routeClient.DeleteLoadBalancer(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installNodePortService installs the flows and the host network configuration
+// forwarding the traffic to the NodePort of the Service to the Endpoints. The
+// NodePort traffic is DNAT'd to the NodePort virtual IP in the host network,
+// and it is load-balanced in OVS like the traffic to the ClusterIP. With the
+// Local external traffic policy, only the Endpoints on this Node are selected
+// with the local group, which must have been installed before.
+func (p *proxier) installNodePortService(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo, groupID binding.GroupIDType) error {
+	if len(p.nodePortAddresses) == 0 || svcInfo.NodePort() == 0 {
+		return nil
+	}
+	onlyLocal := svcInfo.OnlyNodeLocalEndpoints()
+	if onlyLocal {
+		groupID, _ = p.groupCounter.Get(svcPortName, true)
+	}
+	nodePort := uint16(svcInfo.NodePort())
+	if err := p.ofClient.InstallServiceFlows(groupID, agentconfig.NodePortVirtualIP, nodePort, svcInfo.OFProtocol, svcInfo.AffinityTimeout()); err != nil {
+		return err
+	}
+	return p.routeClient.AddNodePort(p.nodePortAddresses, nodePort, svcInfo.OFProtocol, onlyLocal)
+}
+
+// uninstallNodePortService removes what was installed by installNodePortService
+// for the Service.
+func (p *proxier) uninstallNodePortService(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo) error {
+	if len(p.nodePortAddresses) == 0 || svcInfo.NodePort() == 0 {
+		return nil
+	}
+	nodePort := uint16(svcInfo.NodePort())
+	if err := p.routeClient.DeleteNodePort(p.nodePortAddresses, nodePort, svcInfo.OFProtocol); err != nil {
+		return err
+	}
+	return p.ofClient.UninstallServiceFlows(agentconfig.NodePortVirtualIP, nodePort, svcInfo.OFProtocol)
+}
+
 // syncProxyRules applies current changes in change trackers and then updates
 // flows for services and endpoints. It will abort if either endpoints or services
 // resources is not synced. syncProxyRules is only called through the Run method
@@ -216,11 +656,48 @@ func (p *proxier) syncProxyRules() {
 	}
 
 	staleEndpoints := p.endpointsChanges.Update(p.endpointsMap)
-	p.serviceChanges.Update(p.serviceMap)
+	serviceUpdateResult := p.serviceChanges.Update(p.serviceMap)
 
 	p.removeStaleServices()
 	p.installServices()
 	p.removeStaleEndpoints(staleEndpoints)
+	p.removeDrainedEndpoints()
+	if p.serviceHealthServer != nil {
+		p.syncServiceHealthServer(serviceUpdateResult.HCServiceNodePorts)
+	}
+	p.initialSyncOnce.Do(p.flowRestoreCompleteWait.Done)
+}
+
+// syncServiceHealthServer updates the health check servers of the Services with
+// the given health check NodePorts, which report the number of local Endpoints
+// so that the external load balancers only send traffic to the Nodes which have
+// some.
+func (p *proxier) syncServiceHealthServer(hcServiceNodePorts map[apimachinerytypes.NamespacedName]uint16) {
+	if err := p.serviceHealthServer.SyncServices(hcServiceNodePorts); err != nil {
+		klog.Errorf("Error syncing health check Services: %v", err)
+	}
+	localIPs := map[apimachinerytypes.NamespacedName]sets.String{}
+	for svcPortName, endpoints := range p.endpointsMap {
+		if _, ok := hcServiceNodePorts[svcPortName.NamespacedName]; !ok {
+			continue
+		}
+		for _, endpoint := range endpoints {
+			if !endpoint.GetIsLocal() {
+				continue
+			}
+			if _, ok := localIPs[svcPortName.NamespacedName]; !ok {
+				localIPs[svcPortName.NamespacedName] = sets.NewString()
+			}
+			localIPs[svcPortName.NamespacedName].Insert(endpoint.IP())
+		}
+	}
+	localEndpoints := make(map[apimachinerytypes.NamespacedName]int, len(localIPs))
+	for name, ips := range localIPs {
+		localEndpoints[name] = ips.Len()
+	}
+	if err := p.serviceHealthServer.SyncEndpoints(localEndpoints); err != nil {
+		klog.Errorf("Error syncing health check Endpoints: %v", err)
+	}
 }
 
 func (p *proxier) SyncLoop() {
@@ -248,6 +725,27 @@ func (p *proxier) OnEndpointsSynced() {
 	}
 }
 
+func (p *proxier) OnEndpointSliceAdd(endpointSlice *discovery.EndpointSlice) {
+	p.OnEndpointSliceUpdate(nil, endpointSlice)
+}
+
+func (p *proxier) OnEndpointSliceUpdate(oldEndpointSlice, endpointSlice *discovery.EndpointSlice) {
+	if p.endpointsChanges.OnEndpointSliceUpdate(oldEndpointSlice, endpointSlice) && p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
+func (p *proxier) OnEndpointSliceDelete(endpointSlice *discovery.EndpointSlice) {
+	p.OnEndpointSliceUpdate(endpointSlice, nil)
+}
+
+func (p *proxier) OnEndpointSlicesSynced() {
+	p.endpointsChanges.OnEndpointSlicesSynced()
+	if p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
 func (p *proxier) OnServiceAdd(service *corev1.Service) {
 	p.OnServiceUpdate(nil, service)
 }
@@ -295,31 +793,58 @@ func (p *proxier) Run(stopCh <-chan struct{}) {
 	p.once.Do(func() {
 		go p.serviceConfig.Run(stopCh)
 		go p.endpointsConfig.Run(stopCh)
+		if p.endpointSliceConfig != nil {
+			go p.endpointSliceConfig.Run(stopCh)
+		}
 		p.stopChan = stopCh
 		p.SyncLoop()
 	})
 }
 
-func New(hostname string, informerFactory informers.SharedInformerFactory, ofClient openflow.Client) *proxier {
-	recorder := record.NewBroadcaster().NewRecorder(
-		runtime.NewScheme(),
-		corev1.EventSource{Component: componentName, Host: hostname},
-	)
+// New creates a proxier. The Nodes are watched with informerFactory, while the
+// Services, Endpoints and EndpointSlices are watched with serviceInformerFactory,
+// which should be created with TweakListOptions. The failures to install the
+// flows of the Services are reported as Events with eventRecorder.
+func New(hostname string, informerFactory informers.SharedInformerFactory, serviceInformerFactory informers.SharedInformerFactory, ofClient openflow.Client, routeClient route.Interface, nodePortAddresses []net.IP, loadBalancerDSR bool, loadBalancerLocalFastPath bool, endpointSliceEnabled bool, endpointDrainTimeout time.Duration, eventRecorder *events.Recorder, flowRestoreCompleteWait *sync.WaitGroup) *proxier {
+	recorder := eventRecorder.EventRecorder()
 	p := &proxier{
-		endpointsConfig:      config.NewEndpointsConfig(informerFactory.Core().V1().Endpoints(), resyncPeriod),
-		serviceConfig:        config.NewServiceConfig(informerFactory.Core().V1().Services(), resyncPeriod),
-		endpointsChanges:     newEndpointsChangesTracker(hostname),
-		serviceChanges:       newServiceChangesTracker(recorder),
-		serviceMap:           k8sproxy.ServiceMap{},
-		serviceInstalledMap:  k8sproxy.ServiceMap{},
-		endpointInstalledMap: map[k8sproxy.ServicePortName]map[string]struct{}{},
-		endpointsMap:         types.EndpointsMap{},
-		serviceStringMap:     map[string]k8sproxy.ServicePortName{},
-		groupCounter:         types.NewGroupCounter(),
-		ofClient:             ofClient,
+		endpointsConfig:           config.NewEndpointsConfig(serviceInformerFactory.Core().V1().Endpoints(), resyncPeriod),
+		serviceConfig:             config.NewServiceConfig(serviceInformerFactory.Core().V1().Services(), resyncPeriod),
+		endpointsChanges:          newEndpointsChangesTracker(hostname, endpointSliceEnabled),
+		serviceChanges:            newServiceChangesTracker(recorder),
+		serviceMap:                k8sproxy.ServiceMap{},
+		serviceInstalledMap:       k8sproxy.ServiceMap{},
+		endpointInstalledMap:      map[k8sproxy.ServicePortName]map[string]struct{}{},
+		endpointsMap:              types.EndpointsMap{},
+		serviceStringMap:          map[string]k8sproxy.ServicePortName{},
+		groupCounter:              types.NewGroupCounter(),
+		ofClient:                  ofClient,
+		routeClient:               routeClient,
+		nodePortAddresses:         nodePortAddresses,
+		loadBalancerDSR:           loadBalancerDSR,
+		loadBalancerLocalFastPath: loadBalancerLocalFastPath,
+		hostname:                  hostname,
+		nodeLister:                informerFactory.Core().V1().Nodes().Lister(),
+		endpointDrainTimeout:      endpointDrainTimeout,
+		drainingEndpoints:         map[string]*drainingEndpoint{},
+		eventRecorder:             eventRecorder,
+
+		flowRestoreCompleteWait: flowRestoreCompleteWait,
 	}
+	if len(nodePortAddresses) != 0 {
+		p.serviceHealthServer = healthcheck.NewServiceHealthServer(hostname, recorder)
+	}
+	// The initial Services are considered installed after the first sync which happens once both Services and
+	// Endpoints have been synced.
+	flowRestoreCompleteWait.Add(1)
 	p.serviceConfig.RegisterEventHandler(p)
 	p.endpointsConfig.RegisterEventHandler(p)
+	// Endpoints are still watched when EndpointSlices are consumed, they are
+	// used for the Services which don't have any EndpointSlice.
+	if endpointSliceEnabled {
+		p.endpointSliceConfig = config.NewEndpointSliceConfig(serviceInformerFactory.Discovery().V1beta1().EndpointSlices(), resyncPeriod)
+		p.endpointSliceConfig.RegisterEventHandler(p)
+	}
 	p.runner = k8sproxy.NewBoundedFrequencyRunner(componentName, p.syncProxyRules, 0, 30*time.Second, -1)
 	return p
 }
@@ -16,19 +16,32 @@ package proxy
 
 import (
 	"fmt"
+	"math"
 	"net"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 
+	agentconfig "github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/events"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	ofmock "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
 	"github.com/vmware-tanzu/antrea/pkg/agent/proxy/types"
+	routetesting "github.com/vmware-tanzu/antrea/pkg/agent/route/testing"
 	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 )
@@ -84,7 +97,7 @@ func NewFakeProxier(ofClient openflow.Client) *proxier {
 		corev1.EventSource{Component: componentName, Host: hostname},
 	)
 	p := &proxier{
-		endpointsChanges:     newEndpointsChangesTracker(hostname),
+		endpointsChanges:     newEndpointsChangesTracker(hostname, false),
 		serviceChanges:       newServiceChangesTracker(recorder),
 		serviceMap:           k8sproxy.ServiceMap{},
 		serviceInstalledMap:  k8sproxy.ServiceMap{},
@@ -93,7 +106,10 @@ func NewFakeProxier(ofClient openflow.Client) *proxier {
 		groupCounter:         types.NewGroupCounter(),
 		ofClient:             ofClient,
 		serviceStringMap:     map[string]k8sproxy.ServicePortName{},
+		hostname:             hostname,
 	}
+	p.flowRestoreCompleteWait = &sync.WaitGroup{}
+	p.flowRestoreCompleteWait.Add(1)
 	return p
 }
 
@@ -137,7 +153,7 @@ func TestClusterIP(t *testing.T) {
 		}),
 	)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
@@ -145,6 +161,64 @@ func TestClusterIP(t *testing.T) {
 	fp.syncProxyRules()
 }
 
+func TestServiceFailureEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	svc := makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+		svc.Spec.ClusterIP = svcIPv4.String()
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:     svcPortName.Port,
+			Port:     int32(svcPort),
+			Protocol: corev1.ProtocolTCP,
+		}}
+	})
+	makeServiceMap(fp, svc)
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{
+					IP: "10.180.0.1",
+				}},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+	fakeRecorder := record.NewFakeRecorder(10)
+	fp.eventRecorder = events.NewRecorder(fake.NewSimpleClientset(svc), fakeRecorder)
+
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Return(fmt.Errorf("group error")).Times(1)
+
+	fp.syncProxyRules()
+	select {
+	case event := <-fakeRecorder.Events:
+		expected := "Warning ServiceRealizationFailed Failed to install the Endpoints group: group error"
+		if event != expected {
+			t.Errorf("Expected Event %q, got %q", expected, event)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected one Event, got none")
+	}
+	if _, installed := fp.serviceInstalledMap[svcPortName]; installed {
+		t.Errorf("Expected Service %v not to be installed", svcPortName)
+	}
+}
+
 func TestClusterIPRemoval(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -183,7 +257,7 @@ func TestClusterIPRemoval(t *testing.T) {
 	}
 	ep := makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, epFunc)
 	makeEndpointsMap(fp, ep)
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
@@ -292,8 +366,8 @@ func TestClusterIPRemoveSamePortEndpoint(t *testing.T) {
 	})
 	makeEndpointsMap(fp, ep, epUDP)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
-	groupIDUDP, _ := fp.groupCounter.Get(svcPortNameUDP)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	groupIDUDP, _ := fp.groupCounter.Get(svcPortNameUDP, false)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceGroup(groupIDUDP, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
@@ -345,7 +419,7 @@ func TestClusterIPRemoveEndpoints(t *testing.T) {
 		}}
 	})
 	makeEndpointsMap(fp, ep)
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
@@ -356,6 +430,559 @@ func TestClusterIPRemoveEndpoints(t *testing.T) {
 	fp.syncProxyRules()
 }
 
+func TestClusterIPDrainEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.endpointDrainTimeout = time.Minute
+	fp.drainingEndpoints = map[string]*drainingEndpoint{}
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+
+	makeEndpoints := func(ips ...string) *corev1.Endpoints {
+		return makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			var addresses []corev1.EndpointAddress
+			for _, ip := range ips {
+				addresses = append(addresses, corev1.EndpointAddress{IP: ip})
+			}
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: addresses,
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		})
+	}
+	ep1 := &k8sproxy.BaseEndpointInfo{Endpoint: "10.180.0.1:80"}
+	ep2 := &k8sproxy.BaseEndpointInfo{Endpoint: "10.180.0.2:80"}
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+
+	eps := makeEndpoints("10.180.0.1", "10.180.0.2")
+	makeEndpointsMap(fp, eps)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+
+	// The Pod of the second Endpoint is terminating: it's no longer selected
+	// for new connections, but its flows are kept for its established ones.
+	newEps := makeEndpoints("10.180.0.1")
+	fp.endpointsChanges.OnEndpointUpdate(eps, newEps)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, []k8sproxy.Endpoint{ep1}).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, []k8sproxy.Endpoint{ep1}).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().DrainEndpointFlows(binding.ProtocolTCP, ep2).Times(1)
+	fp.syncProxyRules()
+	if _, ok := fp.drainingEndpoints[drainingEndpointKey(binding.ProtocolTCP, ep2)]; !ok {
+		t.Fatalf("Endpoint %s should be draining", ep2)
+	}
+
+	// The flows are kept until the deadline.
+	fp.syncProxyRules()
+	fp.drainingEndpoints[drainingEndpointKey(binding.ProtocolTCP, ep2)].deadline = time.Now()
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, ep2).Times(1)
+	fp.syncProxyRules()
+	if len(fp.drainingEndpoints) != 0 {
+		t.Errorf("Expected no draining Endpoint, got %d", len(fp.drainingEndpoints))
+	}
+}
+
+func TestClusterIPInternalTrafficPolicyLocal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Annotations[types.InternalTrafficPolicyAnnotation] = types.InternalTrafficPolicyLocal
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+
+	localNode, remoteNode := "localhost", "node2"
+	makeEndpoints := func(addresses ...corev1.EndpointAddress) *corev1.Endpoints {
+		return makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: addresses,
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		})
+	}
+	localAddress := corev1.EndpointAddress{IP: "10.180.0.1", NodeName: &localNode}
+	remoteAddress := corev1.EndpointAddress{IP: "10.180.1.1", NodeName: &remoteNode}
+	ep := makeEndpoints(localAddress, remoteAddress)
+	makeEndpointsMap(fp, ep)
+
+	// Only the local Endpoint is selected.
+	localEndpoints := []k8sproxy.Endpoint{&k8sproxy.BaseEndpointInfo{
+		Endpoint: "10.180.0.1:80",
+		IsLocal:  true,
+		Topology: map[string]string{corev1.LabelHostname: localNode},
+	}}
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, localEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, localEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+
+	// The group has no bucket once the local Endpoint is removed, even though
+	// the Service still has a remote Endpoint.
+	updatedEp := makeEndpoints(remoteAddress)
+	fp.endpointsChanges.OnEndpointUpdate(ep, updatedEp)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, nil).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, nil).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	fp.syncProxyRules()
+
+	// Nothing is updated when the remote Endpoints change.
+	fp.endpointsChanges.OnEndpointUpdate(updatedEp, makeEndpoints(remoteAddress, corev1.EndpointAddress{IP: "10.180.2.1", NodeName: &remoteNode}))
+	fp.syncProxyRules()
+}
+
+func TestClusterIPTopologyAwareHints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	fp.nodeLister = corelisters.NewNodeLister(nodeIndexer)
+	addNode := func(name, zone string) {
+		nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{corev1.LabelZoneFailureDomainStable: zone},
+		}})
+	}
+	addNode("localhost", "zone1")
+	addNode("node2", "zone1")
+	addNode("node3", "zone2")
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Annotations[types.TopologyAwareHintsAnnotation] = "auto"
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+
+	makeEndpoints := func(nodeNames ...string) *corev1.Endpoints {
+		return makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			var addresses []corev1.EndpointAddress
+			for i := range nodeNames {
+				addresses = append(addresses, corev1.EndpointAddress{IP: fmt.Sprintf("10.180.%d.1", i), NodeName: &nodeNames[i]})
+			}
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: addresses,
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		})
+	}
+	ep := makeEndpoints("node3", "node2")
+	makeEndpointsMap(fp, ep)
+
+	// Only the Endpoint in the zone of this Node is selected.
+	zoneEndpoints := []k8sproxy.Endpoint{&k8sproxy.BaseEndpointInfo{
+		Endpoint: "10.180.1.1:80",
+		Topology: map[string]string{corev1.LabelHostname: "node2"},
+	}}
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, zoneEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, zoneEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	fp.syncProxyRules()
+
+	// All the Endpoints are selected when none is in the zone of this Node.
+	updatedEp := makeEndpoints("node3")
+	fp.endpointsChanges.OnEndpointUpdate(ep, updatedEp)
+	allEndpoints := []k8sproxy.Endpoint{&k8sproxy.BaseEndpointInfo{
+		Endpoint: "10.180.0.1:80",
+		Topology: map[string]string{corev1.LabelHostname: "node3"},
+	}}
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, allEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, allEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	fp.syncProxyRules()
+}
+
+func TestNodePort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routetesting.NewMockInterface(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	nodePortAddresses := []net.IP{net.ParseIP("192.168.0.1")}
+	fp.routeClient = mockRouteClient
+	fp.nodePortAddresses = nodePortAddresses
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcNodePort := 30001
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeService := func(policy corev1.ServiceExternalTrafficPolicyType) *corev1.Service {
+		return makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.Type = corev1.ServiceTypeNodePort
+			svc.Spec.ExternalTrafficPolicy = policy
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				NodePort: int32(svcNodePort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		})
+	}
+	svc := makeService(corev1.ServiceExternalTrafficPolicyTypeLocal)
+	makeServiceMap(fp, svc)
+
+	localNode, remoteNode := "localhost", "node2"
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "10.180.0.1", NodeName: &localNode},
+					{IP: "10.180.1.1", NodeName: &remoteNode},
+				},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	// With the Local external traffic policy, the NodePort traffic is only
+	// load-balanced to the local Endpoint with a dedicated group.
+	localEndpoints := []k8sproxy.Endpoint{&k8sproxy.BaseEndpointInfo{
+		Endpoint: "10.180.0.1:80",
+		IsLocal:  true,
+		Topology: map[string]string{corev1.LabelHostname: localNode},
+	}}
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	localGroupID, _ := fp.groupCounter.Get(svcPortName, true)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(localGroupID, false, localEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(localGroupID, agentconfig.NodePortVirtualIP, uint16(svcNodePort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().AddNodePort(nodePortAddresses, uint16(svcNodePort), binding.ProtocolTCP, true).Times(1)
+	fp.syncProxyRules()
+
+	// The NodePort is reinstalled with the group of all the Endpoints when the
+	// external traffic policy becomes Cluster.
+	updatedSvc := makeService(corev1.ServiceExternalTrafficPolicyTypeCluster)
+	fp.serviceChanges.OnServiceUpdate(svc, updatedSvc)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().DeleteNodePort(nodePortAddresses, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceFlows(agentconfig.NodePortVirtualIP, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(localGroupID).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, agentconfig.NodePortVirtualIP, uint16(svcNodePort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().AddNodePort(nodePortAddresses, uint16(svcNodePort), binding.ProtocolTCP, false).Times(1)
+	fp.syncProxyRules()
+
+	// The NodePort is removed with the Service.
+	fp.serviceChanges.OnServiceUpdate(updatedSvc, nil)
+	mockOFClient.EXPECT().UninstallServiceFlows(svcIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().DeleteNodePort(nodePortAddresses, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceFlows(agentconfig.NodePortVirtualIP, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(groupID).Times(1)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(2)
+	fp.syncProxyRules()
+}
+
+type fakeServiceHealthServer struct {
+	services  map[apimachinerytypes.NamespacedName]uint16
+	endpoints map[apimachinerytypes.NamespacedName]int
+}
+
+func (f *fakeServiceHealthServer) SyncServices(newServices map[apimachinerytypes.NamespacedName]uint16) error {
+	f.services = newServices
+	return nil
+}
+
+func (f *fakeServiceHealthServer) SyncEndpoints(newEndpoints map[apimachinerytypes.NamespacedName]int) error {
+	f.endpoints = newEndpoints
+	return nil
+}
+
+func TestServiceHealthServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routetesting.NewMockInterface(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.routeClient = mockRouteClient
+	fp.nodePortAddresses = []net.IP{net.ParseIP("192.168.0.1")}
+	healthServer := &fakeServiceHealthServer{}
+	fp.serviceHealthServer = healthServer
+
+	svcName := makeNamespaceName("ns1", "svc1")
+	svcHealthCheckNodePort := 30002
+	makeServiceMap(fp,
+		makeTestService(svcName.Namespace, svcName.Name, func(svc *corev1.Service) {
+			svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+			svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+			svc.Spec.HealthCheckNodePort = int32(svcHealthCheckNodePort)
+			svc.Spec.ClusterIP = "10.20.30.41"
+			svc.Spec.Ports = []corev1.ServicePort{
+				{Name: "http", Port: 80, NodePort: 30001, Protocol: corev1.ProtocolTCP},
+				{Name: "https", Port: 443, NodePort: 30003, Protocol: corev1.ProtocolTCP},
+			}
+		}),
+	)
+	localNode, remoteNode := "localhost", "node2"
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcName.Namespace, svcName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "10.180.0.1", NodeName: &localNode},
+					{IP: "10.180.0.2", NodeName: &localNode},
+					{IP: "10.180.1.1", NodeName: &remoteNode},
+				},
+				Ports: []corev1.EndpointPort{
+					{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP},
+					{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP},
+				},
+			}}
+		}),
+	)
+	mockOFClient.EXPECT().InstallEndpointFlows(gomock.Any(), gomock.Any()).AnyTimes()
+	mockOFClient.EXPECT().InstallServiceGroup(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockOFClient.EXPECT().InstallServiceFlows(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockRouteClient.EXPECT().AddNodePort(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	fp.syncProxyRules()
+
+	// The local Endpoints are counted by IP, regardless of the Service ports.
+	expectedServices := map[apimachinerytypes.NamespacedName]uint16{svcName: uint16(svcHealthCheckNodePort)}
+	expectedEndpoints := map[apimachinerytypes.NamespacedName]int{svcName: 2}
+	if !reflect.DeepEqual(expectedServices, healthServer.services) {
+		t.Errorf("Expected health check Services %v, got %v", expectedServices, healthServer.services)
+	}
+	if !reflect.DeepEqual(expectedEndpoints, healthServer.endpoints) {
+		t.Errorf("Expected health check Endpoints %v, got %v", expectedEndpoints, healthServer.endpoints)
+	}
+}
+
+func TestLoadBalancerDSR(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routetesting.NewMockInterface(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.routeClient = mockRouteClient
+	fp.loadBalancerDSR = true
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	lbIPv4 := net.ParseIP("169.254.1.1")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	svc := makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+		svc.Spec.ClusterIP = svcIPv4.String()
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:     svcPortName.Port,
+			Port:     int32(svcPort),
+			Protocol: corev1.ProtocolTCP,
+		}}
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: lbIPv4.String()}}
+	})
+	makeServiceMap(fp, svc)
+
+	localNode, remoteNode := "localhost", "node2"
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "10.180.0.1", NodeName: &localNode},
+					{IP: "10.180.1.1", NodeName: &remoteNode},
+				},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	// The LoadBalancer IP is load-balanced to all the Endpoints for the
+	// traffic from outside the cluster, and to the local Endpoint for the
+	// traffic forwarded by other Nodes.
+	localEndpoints := []k8sproxy.Endpoint{&k8sproxy.BaseEndpointInfo{
+		Endpoint: "10.180.0.1:80",
+		IsLocal:  true,
+		Topology: map[string]string{corev1.LabelHostname: localNode},
+	}}
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	localGroupID, _ := fp.groupCounter.Get(svcPortName, true)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(localGroupID, false, localEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, lbIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallLoadBalancerServiceDSRFlows(localGroupID, lbIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().AddLoadBalancer(lbIPv4).Times(1)
+	fp.syncProxyRules()
+
+	// The flows, the route and the local group are removed with the Service.
+	fp.serviceChanges.OnServiceUpdate(svc, nil)
+	mockOFClient.EXPECT().UninstallServiceFlows(svcIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceFlows(lbIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallLoadBalancerServiceDSRFlows(lbIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().DeleteLoadBalancer(lbIPv4).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(localGroupID).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(groupID).Times(1)
+	mockOFClient.EXPECT().UninstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(2)
+	fp.syncProxyRules()
+}
+
+func TestLoadBalancerLocalFastPath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routetesting.NewMockInterface(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+	fp.routeClient = mockRouteClient
+	fp.loadBalancerLocalFastPath = true
+
+	svcIPv4 := net.ParseIP("10.20.30.41")
+	lbIPv4 := net.ParseIP("169.254.1.1")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeService := func(policy corev1.ServiceExternalTrafficPolicyType) *corev1.Service {
+		return makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+			svc.Spec.ClusterIP = svcIPv4.String()
+			svc.Spec.ExternalTrafficPolicy = policy
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: lbIPv4.String()}}
+		})
+	}
+	svc := makeService(corev1.ServiceExternalTrafficPolicyTypeLocal)
+	makeServiceMap(fp, svc)
+
+	localNode, remoteNode := "localhost", "node2"
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "10.180.0.1", NodeName: &localNode},
+					{IP: "10.180.1.1", NodeName: &remoteNode},
+				},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	// The traffic from outside the cluster to the LoadBalancer IP is routed
+	// to OVS without conntrack in the host network, and is load-balanced to
+	// the local Endpoint.
+	localEndpoints := []k8sproxy.Endpoint{&k8sproxy.BaseEndpointInfo{
+		Endpoint: "10.180.0.1:80",
+		IsLocal:  true,
+		Topology: map[string]string{corev1.LabelHostname: localNode},
+	}}
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	localGroupID, _ := fp.groupCounter.Get(svcPortName, true)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(localGroupID, false, localEndpoints).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, lbIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallLoadBalancerServiceLocalFlows(localGroupID, lbIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().AddLocalLoadBalancer(lbIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	fp.syncProxyRules()
+
+	// The fast path, the route and the local group are removed when the
+	// external traffic policy changes to Cluster, as the LoadBalancer traffic
+	// is left to kube-proxy again.
+	updatedSvc := makeService(corev1.ServiceExternalTrafficPolicyTypeCluster)
+	fp.serviceChanges.OnServiceUpdate(svc, updatedSvc)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, lbIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().UninstallLoadBalancerServiceLocalFlows(lbIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().DeleteLocalLoadBalancer(lbIPv4, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockRouteClient.EXPECT().DeleteLoadBalancer(lbIPv4).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(localGroupID).Times(1)
+	fp.syncProxyRules()
+}
+
 func TestSessionAffinityNoEndpoint(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -408,7 +1035,7 @@ func TestSessionAffinityNoEndpoint(t *testing.T) {
 		}),
 	)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, true, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), binding.ProtocolTCP, uint16(corev1.DefaultClientIPServiceAffinitySeconds)).Times(1)
@@ -456,3 +1083,253 @@ func TestSessionAffinity(t *testing.T) {
 
 	fp.syncProxyRules()
 }
+
+func TestSessionAffinityMaxTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	svcIP := net.ParseIP("10.20.30.41")
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	timeoutSeconds := int32(86400)
+
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIP.String()
+			svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+			svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+				ClientIP: &corev1.ClientIPConfig{
+					TimeoutSeconds: &timeoutSeconds,
+				},
+			}
+			svc.Spec.Ports = []corev1.ServicePort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+	makeEndpointsMap(fp, makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+		ept.Subsets = []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.180.0.1"}},
+			Ports: []corev1.EndpointPort{{
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}},
+		}}
+	}))
+
+	// The timeout of the learned flows is capped as it can't exceed the
+	// maximum value of an OpenFlow idle timeout.
+	groupID, _ := fp.groupCounter.Get(svcPortName, false)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, true, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), binding.ProtocolTCP, uint16(math.MaxUint16)).Times(1)
+	fp.syncProxyRules()
+}
+
+func TestFlowRestoreCompleteWait(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	fp := NewFakeProxier(mockOFClient)
+
+	waitCh := make(chan struct{})
+	go func() {
+		fp.flowRestoreCompleteWait.Wait()
+		close(waitCh)
+	}()
+	// Nothing is installed until both Services and Endpoints have been synced.
+	fp.syncProxyRules()
+	select {
+	case <-waitCh:
+		t.Fatalf("flowRestoreCompleteWait should not be done before the initial sync")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	makeServiceMap(fp)
+	makeEndpointsMap(fp)
+	fp.syncProxyRules()
+	select {
+	case <-waitCh:
+	case <-time.After(time.Second):
+		t.Fatalf("flowRestoreCompleteWait should be done after the initial sync")
+	}
+	// Subsequent syncs must not notify the WaitGroup again.
+	fp.syncProxyRules()
+}
+
+func TestEndpointSliceMigration(t *testing.T) {
+	tracker := newEndpointsChangesTracker("localhost", true)
+	svcPort := 80
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeEndpoints := func(ip string) *corev1.Endpoints {
+		return makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: ip}},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		})
+	}
+	ready, notReady := true, false
+	portName, port, protocol := svcPortName.Port, int32(svcPort), corev1.ProtocolTCP
+	endpointSlice := &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc1-abcde",
+			Namespace: svcPortName.Namespace,
+			Labels:    map[string]string{discovery.LabelServiceName: svcPortName.Name},
+		},
+		AddressType: discovery.AddressTypeIPv4,
+		Endpoints: []discovery.Endpoint{
+			{
+				Addresses:  []string{"10.180.0.2"},
+				Conditions: discovery.EndpointConditions{Ready: &ready},
+				Topology:   map[string]string{corev1.LabelHostname: "localhost"},
+			},
+			{
+				Addresses:  []string{"10.180.0.3"},
+				Conditions: discovery.EndpointConditions{Ready: &notReady},
+			},
+		},
+		Ports: []discovery.EndpointPort{{Name: &portName, Port: &port, Protocol: &protocol}},
+	}
+	checkEndpoints := func(em types.EndpointsMap, expected ...string) {
+		if len(em[svcPortName]) != len(expected) {
+			t.Fatalf("Expected Endpoints %v, got %v", expected, em[svcPortName])
+		}
+		for _, ep := range expected {
+			if _, ok := em[svcPortName][ep]; !ok {
+				t.Fatalf("Expected Endpoints %v, got %v", expected, em[svcPortName])
+			}
+		}
+	}
+
+	em := types.EndpointsMap{}
+	tracker.OnEndpointUpdate(nil, makeEndpoints("10.180.0.1"))
+	tracker.OnEndpointsSynced()
+	if tracker.Synced() {
+		t.Fatalf("Tracker should not be synced before EndpointSlices are synced")
+	}
+	tracker.OnEndpointSlicesSynced()
+	if !tracker.Synced() {
+		t.Fatalf("Tracker should be synced after both Endpoints and EndpointSlices are synced")
+	}
+	// The Endpoints are used as long as the Service has no EndpointSlice.
+	tracker.Update(em)
+	checkEndpoints(em, "10.180.0.1:80")
+
+	// The EndpointSlice takes precedence over the Endpoints, and the not ready
+	// endpoint is excluded.
+	if !tracker.OnEndpointSliceUpdate(nil, endpointSlice) {
+		t.Fatalf("Adding the EndpointSlice should change the Endpoints of the Service")
+	}
+	stale := tracker.Update(em)
+	checkEndpoints(em, "10.180.0.2:80")
+	if _, ok := stale[svcPortName]["10.180.0.1:80"]; !ok {
+		t.Errorf("Endpoint 10.180.0.1:80 should be stale")
+	}
+	if !em[svcPortName]["10.180.0.2:80"].GetIsLocal() {
+		t.Errorf("Endpoint 10.180.0.2:80 should be local")
+	}
+
+	// Changes to the Endpoints are ignored while the EndpointSlice exists.
+	if tracker.OnEndpointUpdate(makeEndpoints("10.180.0.1"), makeEndpoints("10.180.0.4")) {
+		t.Fatalf("Updating the Endpoints should not change the Endpoints of the Service")
+	}
+
+	// The Endpoints are used again once the EndpointSlice is deleted.
+	tracker.OnEndpointSliceUpdate(endpointSlice, nil)
+	tracker.Update(em)
+	checkEndpoints(em, "10.180.0.4:80")
+}
+
+func newServiceInformerObjects(count int) []runtime.Object {
+	var objects []runtime.Object
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("svc%d", i)
+		svc := makeTestService("ns1", name, func(svc *corev1.Service) {})
+		ept := makeTestEndpoints("ns1", name, func(ept *corev1.Endpoints) {
+			var addresses []corev1.EndpointAddress
+			for j := 0; j < 10; j++ {
+				addresses = append(addresses, corev1.EndpointAddress{IP: fmt.Sprintf("10.%d.%d.%d", i/256, i%256, j)})
+			}
+			ept.Subsets = []corev1.EndpointSubset{{Addresses: addresses, Ports: []corev1.EndpointPort{{Port: 80}}}}
+		})
+		// One Service out of two is handled by an alternative proxy, and the
+		// Endpoints of one Service out of two are those of a headless Service.
+		switch i % 4 {
+		case 1:
+			svc.Labels = map[string]string{labelServiceProxyName: "other-proxy"}
+		case 2:
+			ept.Labels = map[string]string{corev1.IsHeadlessService: ""}
+		case 3:
+			svc.Labels = map[string]string{labelServiceProxyName: "other-proxy"}
+			ept.Labels = map[string]string{corev1.IsHeadlessService: ""}
+		}
+		objects = append(objects, svc, ept)
+	}
+	return objects
+}
+
+func syncServiceInformers(client *fake.Clientset, stopCh <-chan struct{}) (corelisters.ServiceLister, corelisters.EndpointsLister) {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(client, 0, informers.WithTweakListOptions(TweakListOptions))
+	serviceLister := informerFactory.Core().V1().Services().Lister()
+	endpointsLister := informerFactory.Core().V1().Endpoints().Lister()
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	return serviceLister, endpointsLister
+}
+
+func TestTweakListOptions(t *testing.T) {
+	client := fake.NewSimpleClientset(newServiceInformerObjects(4)...)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	serviceLister, endpointsLister := syncServiceInformers(client, stopCh)
+
+	services, _ := serviceLister.List(labels.Everything())
+	endpoints, _ := endpointsLister.List(labels.Everything())
+	if len(services) != 2 {
+		t.Errorf("Expected 2 Services to be cached, got %d", len(services))
+	}
+	for _, svc := range services {
+		if _, ok := svc.Labels[labelServiceProxyName]; ok {
+			t.Errorf("Service %s handled by an alternative proxy should not be cached", svc.Name)
+		}
+	}
+	if len(endpoints) != 2 {
+		t.Errorf("Expected 2 Endpoints to be cached, got %d", len(endpoints))
+	}
+	for _, ept := range endpoints {
+		if _, ok := ept.Labels[corev1.IsHeadlessService]; ok {
+			t.Errorf("Endpoints %s of headless Service should not be cached", ept.Name)
+		}
+	}
+}
+
+// BenchmarkServiceInformers measures the memory allocated to cache the
+// Services and Endpoints consumed by AntreaProxy.
+func BenchmarkServiceInformers(b *testing.B) {
+	client := fake.NewSimpleClientset(newServiceInformerObjects(1000)...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stopCh := make(chan struct{})
+		syncServiceInformers(client, stopCh)
+		close(stopCh)
+	}
+}
@@ -26,11 +26,17 @@ type GroupCounter interface {
 	// Get generates a global unique group ID for a specific service.
 	// If the group ID of the service has been generated, then return the
 	// prior one. The bool return value indicates whether the groupID is newly
-	// generated.
-	Get(svcPortName k8sproxy.ServicePortName) (binding.GroupIDType, bool)
+	// generated. A Service gets a distinct group for its Endpoints on the
+	// local Node when isLocal is true.
+	Get(svcPortName k8sproxy.ServicePortName, isLocal bool) (binding.GroupIDType, bool)
 	// Recycle removes a Service Group ID mapping. The recycled groupID can be
 	// reused.
-	Recycle(svcPortName k8sproxy.ServicePortName) bool
+	Recycle(svcPortName k8sproxy.ServicePortName, isLocal bool) bool
+}
+
+type groupKey struct {
+	svcPortName k8sproxy.ServicePortName
+	isLocal     bool
 }
 
 type groupCounter struct {
@@ -38,36 +44,39 @@ type groupCounter struct {
 	groupIDCounter binding.GroupIDType
 	recycled       []binding.GroupIDType
 
-	groupMap map[k8sproxy.ServicePortName]binding.GroupIDType
+	groupMap map[groupKey]binding.GroupIDType
 }
 
 func NewGroupCounter() *groupCounter {
-	return &groupCounter{groupMap: map[k8sproxy.ServicePortName]binding.GroupIDType{}}
+	return &groupCounter{groupMap: map[groupKey]binding.GroupIDType{}}
 }
 
-func (c *groupCounter) Get(svcPortName k8sproxy.ServicePortName) (binding.GroupIDType, bool) {
+func (c *groupCounter) Get(svcPortName k8sproxy.ServicePortName, isLocal bool) (binding.GroupIDType, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if id, ok := c.groupMap[svcPortName]; ok {
+	key := groupKey{svcPortName: svcPortName, isLocal: isLocal}
+	if id, ok := c.groupMap[key]; ok {
 		return id, false
 	} else if len(c.recycled) != 0 {
 		id = c.recycled[len(c.recycled)-1]
 		c.recycled = c.recycled[:len(c.recycled)-1]
+		c.groupMap[key] = id
 		return id, true
 	} else {
 		c.groupIDCounter += 1
-		c.groupMap[svcPortName] = c.groupIDCounter
+		c.groupMap[key] = c.groupIDCounter
 		return c.groupIDCounter, true
 	}
 }
 
-func (c *groupCounter) Recycle(svcPortName k8sproxy.ServicePortName) bool {
+func (c *groupCounter) Recycle(svcPortName k8sproxy.ServicePortName, isLocal bool) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if id, ok := c.groupMap[svcPortName]; ok {
-		delete(c.groupMap, svcPortName)
+	key := groupKey{svcPortName: svcPortName, isLocal: isLocal}
+	if id, ok := c.groupMap[key]; ok {
+		delete(c.groupMap, key)
 		c.recycled = append(c.recycled, id)
 		return true
 	}
@@ -15,17 +15,56 @@
 package types
 
 import (
+	"math"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	k8sproxy "github.com/vmware-tanzu/antrea/third_party/proxy"
 )
 
+const (
+	// InternalTrafficPolicyAnnotation can be set on a Service to select the
+	// Endpoints of the traffic to its ClusterIP, like the internalTrafficPolicy
+	// field of the Service spec which is not available in this K8s version.
+	InternalTrafficPolicyAnnotation = "service.antrea.tanzu.vmware.com/internal-traffic-policy"
+
+	// InternalTrafficPolicyCluster routes the traffic to all the Endpoints of
+	// the Service. It is the default.
+	InternalTrafficPolicyCluster = "Cluster"
+	// InternalTrafficPolicyLocal routes the traffic only to the Endpoints on
+	// the same Node as the client. The traffic is dropped when there is no
+	// such Endpoint.
+	InternalTrafficPolicyLocal = "Local"
+
+	// TopologyAwareHintsAnnotation can be set to "auto" on a Service to
+	// prefer the Endpoints in the same zone as the client. The zone of an
+	// Endpoint is given by the topology.kubernetes.io/zone label of its Node.
+	TopologyAwareHintsAnnotation = "service.kubernetes.io/topology-aware-hints"
+)
+
 // ServiceInfo is the internal struct for caching service information.
 type ServiceInfo struct {
 	*k8sproxy.BaseServiceInfo
 	// cache for performance
 	OFProtocol openflow.Protocol
+	// OnlyNodeLocalInternalEndpoints is true when the internal traffic policy
+	// of the Service is Local.
+	OnlyNodeLocalInternalEndpoints bool
+	// TopologyAware is true when the Endpoints in the same zone as this Node
+	// are preferred.
+	TopologyAware bool
+}
+
+// AffinityTimeout returns the idle timeout of the session affinity of the
+// Service, in seconds. The timeout of the OVS learned flows can't exceed
+// math.MaxUint16, so the longer timeouts are capped.
+func (si *ServiceInfo) AffinityTimeout() uint16 {
+	if si.StickyMaxAgeSeconds() > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return uint16(si.StickyMaxAgeSeconds())
 }
 
 func (si *ServiceInfo) Equal(bSvcInfo *ServiceInfo) bool {
@@ -33,6 +72,10 @@ func (si *ServiceInfo) Equal(bSvcInfo *ServiceInfo) bool {
 		si.StickyMaxAgeSeconds() == bSvcInfo.StickyMaxAgeSeconds() &&
 		si.OFProtocol == bSvcInfo.OFProtocol &&
 		si.Port() == bSvcInfo.Port() &&
+		si.NodePort() == bSvcInfo.NodePort() &&
+		si.OnlyNodeLocalEndpoints() == bSvcInfo.OnlyNodeLocalEndpoints() &&
+		si.OnlyNodeLocalInternalEndpoints == bSvcInfo.OnlyNodeLocalInternalEndpoints &&
+		si.TopologyAware == bSvcInfo.TopologyAware &&
 		len(si.LoadBalancerIPStrings()) == len(bSvcInfo.LoadBalancerIPStrings())
 }
 
@@ -45,6 +88,17 @@ func NewServiceInfo(port *corev1.ServicePort, service *corev1.Service, baseInfo
 	} else if port.Protocol == corev1.ProtocolSCTP {
 		info.OFProtocol = openflow.ProtocolSCTP
 	}
+	switch policy := service.Annotations[InternalTrafficPolicyAnnotation]; policy {
+	case "", InternalTrafficPolicyCluster:
+	case InternalTrafficPolicyLocal:
+		info.OnlyNodeLocalInternalEndpoints = true
+	default:
+		klog.Warningf("Ignoring invalid internal traffic policy %q of Service %s/%s", policy, service.Namespace, service.Name)
+	}
+	switch hints := service.Annotations[TopologyAwareHintsAnnotation]; hints {
+	case "auto", "Auto":
+		info.TopologyAware = true
+	}
 	return info
 }
 
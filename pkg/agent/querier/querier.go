@@ -15,7 +15,9 @@
 package querier
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +25,7 @@ import (
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
 	"github.com/vmware-tanzu/antrea/pkg/agent/interfacestore"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1"
@@ -43,6 +46,18 @@ type AgentQuerier interface {
 	GetNetworkPolicyInfoQuerier() querier.AgentNetworkPolicyInfoQuerier
 }
 
+// FlowRecordsQuerier provides read access to the in-memory flow records of the Flow Exporter.
+type FlowRecordsQuerier interface {
+	// GetFlowRecords returns a copy of the flow records of the connections which are exported, including the
+	// connections denied by NetworkPolicies.
+	GetFlowRecords() []flowexporter.FlowRecord
+}
+
+// NodeCapacityQuerier provides the usage of the datapath resources of the Node.
+type NodeCapacityQuerier interface {
+	GetNodeCapacity() v1beta1.NodeCapacityInfo
+}
+
 type agentQuerier struct {
 	nodeConfig               *config.NodeConfig
 	interfaceStore           interfacestore.InterfaceStore
@@ -50,6 +65,7 @@ type agentQuerier struct {
 	ofClient                 openflow.Client
 	ovsBridgeClient          ovsconfig.OVSBridgeClient
 	networkPolicyInfoQuerier querier.AgentNetworkPolicyInfoQuerier
+	nodeCapacityQuerier      NodeCapacityQuerier
 	apiPort                  int
 }
 
@@ -60,6 +76,7 @@ func NewAgentQuerier(
 	ofClient openflow.Client,
 	ovsBridgeClient ovsconfig.OVSBridgeClient,
 	networkPolicyInfoQuerier querier.AgentNetworkPolicyInfoQuerier,
+	nodeCapacityQuerier NodeCapacityQuerier,
 	apiPort int,
 ) *agentQuerier {
 	return &agentQuerier{
@@ -69,6 +86,7 @@ func NewAgentQuerier(
 		ofClient:                 ofClient,
 		ovsBridgeClient:          ovsBridgeClient,
 		networkPolicyInfoQuerier: networkPolicyInfoQuerier,
+		nodeCapacityQuerier:      nodeCapacityQuerier,
 		apiPort:                  apiPort}
 }
 
@@ -137,6 +155,16 @@ func (aq agentQuerier) getAgentConditions(ovsConnected bool) []v1beta1.AgentCond
 	if !aq.ofClient.IsConnected() {
 		openflowConnectionStatus = v1.ConditionFalse
 	}
+	ovsFeaturesCondition := v1beta1.AgentCondition{
+		Type:              v1beta1.OVSFeaturesSupported,
+		Status:            v1.ConditionTrue,
+		LastHeartbeatTime: lastHeartbeatTime,
+	}
+	if len(aq.nodeConfig.UnsupportedOVSFeatures) > 0 {
+		ovsFeaturesCondition.Status = v1.ConditionFalse
+		ovsFeaturesCondition.Reason = "UnsupportedFeatures"
+		ovsFeaturesCondition.Message = fmt.Sprintf("Unsupported OVS features: %s", strings.Join(aq.nodeConfig.UnsupportedOVSFeatures, ", "))
+	}
 	return []v1beta1.AgentCondition{
 		{
 			Type:              v1beta1.AgentHealthy,
@@ -158,6 +186,7 @@ func (aq agentQuerier) getAgentConditions(ovsConnected bool) []v1beta1.AgentCond
 			Status:            openflowConnectionStatus,
 			LastHeartbeatTime: lastHeartbeatTime,
 		},
+		ovsFeaturesCondition,
 	}
 }
 
@@ -173,12 +202,15 @@ func (aq agentQuerier) getNetworkPolicyControllerInfo() v1beta1.NetworkPolicyCon
 
 // GetAgentInfo gets current agent pod info.
 func (aq agentQuerier) GetAgentInfo(agentInfo *v1beta1.AntreaAgentInfo, partial bool) {
-	// LocalPodNum, FlowTable, NetworkPolicyControllerInfo, OVSVersion and AgentConditions can be changed, so reset these fields.
-	// Only these fields are updated when partial is true.
+	// LocalPodNum, FlowTable, NetworkPolicyControllerInfo, NodeCapacity, OVSVersion and AgentConditions can be changed,
+	// so reset these fields. Only these fields are updated when partial is true.
 	agentInfo.Name = aq.nodeConfig.Name
 	agentInfo.LocalPodNum = int32(aq.interfaceStore.GetContainerInterfaceNum())
 	agentInfo.OVSInfo.FlowTable = aq.getOVSFlowTable()
 	agentInfo.NetworkPolicyControllerInfo = aq.getNetworkPolicyControllerInfo()
+	if aq.nodeCapacityQuerier != nil {
+		agentInfo.NodeCapacity = aq.nodeCapacityQuerier.GetNodeCapacity()
+	}
 	ovsVersion := aq.getOVSVersion()
 	// OVS version query will fail and return empty string when OVSDB connection is down.
 	// Only change OVS version when the query gets a valid version.
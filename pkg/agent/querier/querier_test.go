@@ -112,6 +112,10 @@ func TestAgentQuerierGetAgentInfo(t *testing.T) {
 						Type:   v1beta1.OpenflowConnectionUp,
 						Status: corev1.ConditionTrue,
 					},
+					{
+						Type:   v1beta1.OVSFeaturesSupported,
+						Status: corev1.ConditionTrue,
+					},
 				},
 				APIPort: 10350,
 				Version: "UNKNOWN",
@@ -124,6 +128,9 @@ func TestAgentQuerierGetAgentInfo(t *testing.T) {
 				OVSBridge:  "br-int",
 				NodeIPAddr: getIPNet("10.10.0.10"),
 				PodCIDR:    getIPNet("20.20.20.0/24"),
+				// The unsupported OVS features are reported in the
+				// OVSFeaturesSupported condition.
+				UnsupportedOVSFeatures: []string{config.OVSFeatureMeters},
 			},
 			apiPort: 10350,
 			partial: false,
@@ -159,6 +166,10 @@ func TestAgentQuerierGetAgentInfo(t *testing.T) {
 						Type:   v1beta1.OpenflowConnectionUp,
 						Status: corev1.ConditionTrue,
 					},
+					{
+						Type:   v1beta1.OVSFeaturesSupported,
+						Status: corev1.ConditionFalse,
+					},
 				},
 				APIPort: 10350,
 				Version: "UNKNOWN",
@@ -18,6 +18,7 @@ import (
 	"net"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 )
 
 // Interface is the interface for routing container packets in host network.
@@ -29,18 +30,73 @@ type Interface interface {
 	// Reconcile should remove orphaned routes and related configuration based on the desired podCIDRs.
 	Reconcile(podCIDRs []string) error
 
-	// AddRoutes should add routes to the provided podCIDR.
-	// It should override the routes if they already exist, without error.
-	AddRoutes(podCIDR *net.IPNet, peerNodeIP, peerGwIP net.IP) error
+	// AddRoutes should add routes to the provided podCIDR, according to the traffic encapsulation
+	// mode to the peer Node. It should override the routes if they already exist, without error.
+	AddRoutes(podCIDR *net.IPNet, peerNodeIP, peerGwIP net.IP, peerEncapMode config.TrafficEncapModeType) error
 
 	// DeleteRoutes should delete routes to the provided podCIDR.
 	// It should do nothing if the routes don't exist, without error.
 	DeleteRoutes(podCIDR *net.IPNet) error
 
+	// AddLocalPodRoute should route the provided IP of a local Pod, which is not in the PodCIDR of the Node, to the
+	// local gateway. It should override the route if it already exists, without error.
+	AddLocalPodRoute(podIP net.IP) error
+
+	// DeleteLocalPodRoute should delete the route added by AddLocalPodRoute.
+	// It should do nothing if the route doesn't exist, without error.
+	DeleteLocalPodRoute(podIP net.IP) error
+
+	// AddPodNeighborProxy should answer the neighbor discovery requests received from the Node network for the
+	// provided IP of a local Pod, when the neighbor proxy is enabled. It should do nothing otherwise, without error.
+	AddPodNeighborProxy(podIP net.IP) error
+
+	// DeletePodNeighborProxy should delete the neighbor proxy added by AddPodNeighborProxy.
+	// It should do nothing if it doesn't exist, without error.
+	DeletePodNeighborProxy(podIP net.IP) error
+
 	// MigrateRoutesToGw should move routes from device linkname to local gateway.
 	MigrateRoutesToGw(linkName string) error
 
 	// UnMigrateRoutesFromGw should move routes back from local gateway to original device linkName
 	// if linkName is nil, it should remove the routes.
 	UnMigrateRoutesFromGw(route *net.IPNet, linkName string) error
+
+	// AddNodePort should make the host network forward the traffic to the NodePort on the provided Node addresses
+	// to OVS. The client IP of the traffic should be preserved if onlyLocal is true.
+	AddNodePort(nodeIPs []net.IP, port uint16, protocol binding.Protocol, onlyLocal bool) error
+
+	// DeleteNodePort should stop forwarding the traffic to the NodePort on the provided Node addresses to OVS.
+	// It should do nothing if the NodePort was not added, without error.
+	DeleteNodePort(nodeIPs []net.IP, port uint16, protocol binding.Protocol) error
+
+	// AddLoadBalancer should make the host network forward the traffic to the LoadBalancer IP to OVS without DNAT.
+	AddLoadBalancer(ip net.IP) error
+
+	// DeleteLoadBalancer should stop forwarding the traffic to the LoadBalancer IP to OVS.
+	// It should do nothing if the LoadBalancer IP was not added, without error.
+	DeleteLoadBalancer(ip net.IP) error
+
+	// AddLocalLoadBalancer should make the host network forward the traffic to the port of the LoadBalancer IP of a
+	// Service with the Local external traffic policy to OVS without DNAT, and skip connection tracking for the
+	// traffic and its replies.
+	AddLocalLoadBalancer(ip net.IP, port uint16, protocol binding.Protocol) error
+
+	// DeleteLocalLoadBalancer should stop skipping connection tracking for the traffic to the port of the
+	// LoadBalancer IP. The forwarding of the LoadBalancer IP to OVS is stopped by DeleteLoadBalancer. It should do
+	// nothing if the port was not added, without error.
+	DeleteLocalLoadBalancer(ip net.IP, port uint16, protocol binding.Protocol) error
+
+	// LookupRoute should return the name of the host interface, the next hop (nil if the destination is on-link) and
+	// the source IP the host network selects for packets sent to the provided IP.
+	LookupRoute(ip net.IP) (linkName string, nextHop net.IP, srcIP net.IP, err error)
+
+	// AddSNATRule should make the host network SNAT the packets from the provided Pod IP to external addresses with
+	// the provided SNAT IP. If portRange is not nil, the source ports of the TCP and UDP packets should be translated
+	// within the range. It should override the SNAT IP and the port range if the rule of the Pod IP already exists,
+	// without error.
+	AddSNATRule(podIP, snatIP net.IP, portRange *binding.PortRange) error
+
+	// DeleteSNATRule should stop SNATing the packets from the provided Pod IP with the SNAT IP added by AddSNATRule.
+	// It should do nothing if the rule of the Pod IP was not added, without error.
+	DeleteSNATRule(podIP net.IP) error
 }
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"os"
 	"os/exec"
 	"reflect"
 	"sync"
@@ -31,6 +32,8 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/ipset"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/iptables"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/sysctl"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	"github.com/vmware-tanzu/antrea/pkg/util/env"
 )
 
@@ -38,11 +41,23 @@ const (
 	// Antrea managed ipset.
 	// antreaPodIPSet contains all Pod CIDRs of this cluster.
 	antreaPodIPSet = "ANTREA-POD-IP"
+	// antreaNodePortIPSet contains the Node addresses and the NodePorts served by AntreaProxy.
+	antreaNodePortIPSet = "ANTREA-NODEPORT-IP"
+	// antreaNodePortLocalIPSet contains the NodePort virtual IP and the NodePorts of the Services with the Local
+	// external traffic policy, whose traffic is not masqueraded.
+	antreaNodePortLocalIPSet = "ANTREA-NODEPORT-LOCAL"
+	// antreaLoadBalancerLocalIPSet contains the LoadBalancer IPs and the ports of the Services with the Local
+	// external traffic policy, whose traffic is not tracked by the host network.
+	antreaLoadBalancerLocalIPSet = "ANTREA-LB-LOCAL"
 
 	// Antrea managed iptables chains.
 	antreaForwardChain     = "ANTREA-FORWARD"
 	antreaPostRoutingChain = "ANTREA-POSTROUTING"
 	antreaMangleChain      = "ANTREA-MANGLE"
+	antreaNodePortChain    = "ANTREA-NODEPORT"
+	antreaOutputChain      = "ANTREA-OUTPUT"
+	antreaEgressChain      = "ANTREA-EGRESS"
+	antreaRawChain         = "ANTREA-RAW"
 )
 
 // Client implements Interface.
@@ -53,22 +68,38 @@ type Client struct {
 	nodeConfig  *config.NodeConfig
 	encapMode   config.TrafficEncapModeType
 	serviceCIDR *net.IPNet
-	ipt         *iptables.Client
+	// nodePortEnabled indicates whether the NodePort Services are served by AntreaProxy.
+	nodePortEnabled bool
+	// hostNetworkEnabled indicates whether the ClusterIPs are served by AntreaProxy for the traffic originated from
+	// the Node network namespace.
+	hostNetworkEnabled bool
+	ipt                *iptables.Client
 	// nodeRoutes caches ip routes to remote Pods. It's a map of podCIDR to routes.
 	nodeRoutes sync.Map
+	// snatRules caches the SNAT rules of the Pods selected by Egresses. It's a map of Pod IP to snatRule.
+	snatRules sync.Map
+	// neighborProxyEnabled indicates whether the neighbor discovery requests received from the Node network for the
+	// local Pods are answered in noEncap and hybrid modes.
+	neighborProxyEnabled bool
+	// transportLinkIndex is the index of the transport interface, on which the proxy neighbor entries of the local Pods
+	// are added when the neighbor proxy is enabled. It is 0 otherwise.
+	transportLinkIndex int
 }
 
 // NewClient returns a route client.
-func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType) (*Client, error) {
+func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType, nodePortEnabled, hostNetworkEnabled, neighborProxyEnabled bool) (*Client, error) {
 	ipt, err := iptables.New()
 	if err != nil {
 		return nil, fmt.Errorf("error creating IPTables instance: %v", err)
 	}
 
 	return &Client{
-		serviceCIDR: serviceCIDR,
-		encapMode:   encapMode,
-		ipt:         ipt,
+		serviceCIDR:          serviceCIDR,
+		encapMode:            encapMode,
+		nodePortEnabled:      nodePortEnabled,
+		hostNetworkEnabled:   hostNetworkEnabled,
+		neighborProxyEnabled: neighborProxyEnabled,
+		ipt:                  ipt,
 	}, nil
 }
 
@@ -106,9 +137,68 @@ func (c *Client) Initialize(nodeConfig *config.NodeConfig) error {
 		return err
 	}
 
+	// Sets up the transport interface to route noEncap Pod traffic in host network.
+	if err := c.initNeighborProxy(); err != nil {
+		return fmt.Errorf("failed to initialize neighbor proxy on transport interface: %v", err)
+	}
+
 	return nil
 }
 
+// initNeighborProxy disables send_redirects on the transport interface when noEncap is supported, as noEncap traffic
+// received from it may be routed back out of it towards the Node network router, in which case the host must not
+// advertise a shorter path to the sender with an ICMP redirect.
+// When the neighbor proxy is enabled, the Node also answers the ARP requests and IPv6 neighbor solicitations received
+// from the Node network for its local Pods, e.g. when Pods are on routable VLANs, so that the east-west Pod traffic is
+// routed by the host through OVS. Only the IPs added as proxy neighbor entries on the transport interface by
+// AddPodNeighborProxy are answered: proxy_arp is never enabled, as the kernel would then answer for every address
+// routed through another interface, e.g. the Service and remote Pod routes. IPv4 proxy neighbor entries are answered
+// as long as IP forwarding is enabled, and proxy_ndp must be enabled for the IPv6 ones.
+// Proxy ARP is always disabled on the gateway, which answered for the remote Pod subnets in previous versions.
+func (c *Client) initNeighborProxy() error {
+	if err := sysctl.EnsureSysctlNetValue(fmt.Sprintf("ipv4/conf/%s/proxy_arp", c.nodeConfig.GatewayConfig.Name), 0); err != nil {
+		return err
+	}
+	if !c.encapMode.SupportsNoEncap() {
+		return nil
+	}
+	_, transportIntf, err := util.GetIPNetDeviceFromIP(c.nodeConfig.NodeIPAddr.IP)
+	if err != nil {
+		return fmt.Errorf("failed to get transport interface of Node IP %s: %v", c.nodeConfig.NodeIPAddr.IP, err)
+	}
+	if err := disableICMPSendRedirects(transportIntf.Name); err != nil {
+		return err
+	}
+	if !c.neighborProxyEnabled {
+		return nil
+	}
+	c.transportLinkIndex = transportIntf.Index
+	proxyNDP := fmt.Sprintf("ipv6/conf/%s/proxy_ndp", transportIntf.Name)
+	if _, err := sysctl.GetSysctlNet(proxyNDP); os.IsNotExist(err) {
+		klog.V(2).Infof("IPv6 is disabled on transport interface %s, skipping proxy NDP", transportIntf.Name)
+		return nil
+	}
+	return sysctl.EnsureSysctlNetValue(proxyNDP, 1)
+}
+
+// neighborProxy returns the proxy neighbor entry answering the neighbor discovery requests received from the transport
+// interface for the IP of a local Pod, or nil if the neighbor proxy is not enabled.
+func (c *Client) neighborProxy(podIP net.IP) *netlink.Neigh {
+	if c.transportLinkIndex == 0 || podIP == nil {
+		return nil
+	}
+	family := netlink.FAMILY_V4
+	if podIP.To4() == nil {
+		family = netlink.FAMILY_V6
+	}
+	return &netlink.Neigh{
+		LinkIndex: c.transportLinkIndex,
+		Family:    family,
+		Flags:     netlink.NTF_PROXY,
+		IP:        podIP,
+	}
+}
+
 // initIPSet ensures that the required ipset exists and it has the initial members.
 func (c *Client) initIPSet() error {
 	// In policy-only mode, Node Pod CIDR is undefined.
@@ -122,6 +212,24 @@ func (c *Client) initIPSet() error {
 	if err := ipset.AddEntry(antreaPodIPSet, c.nodeConfig.PodCIDR.String()); err != nil {
 		return err
 	}
+	if c.nodePortEnabled {
+		// The NodePorts are added back by AntreaProxy once it has synced the Services, the stale ones must not be
+		// kept as the external traffic policy of a NodePort may have changed.
+		for _, name := range []string{antreaNodePortIPSet, antreaNodePortLocalIPSet, antreaLoadBalancerLocalIPSet} {
+			if err := ipset.CreateIPSet(name, ipset.HashIPPort); err != nil {
+				return err
+			}
+			entries, err := ipset.ListEntries(name)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if err := ipset.DelEntry(name, entry); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -154,17 +262,39 @@ func (c *Client) initIPTables() error {
 	// Create the antrea managed chains and link them to built-in chains.
 	// We cannot use iptables-restore for these jump rules because there
 	// are non antrea managed rules in built-in chains.
-	jumpRules := []struct{ table, srcChain, dstChain, comment string }{
-		{iptables.FilterTable, iptables.ForwardChain, antreaForwardChain, "Antrea: jump to Antrea forwarding rules"},
-		{iptables.NATTable, iptables.PostRoutingChain, antreaPostRoutingChain, "Antrea: jump to Antrea postrouting rules"},
-		{iptables.MangleTable, iptables.PreRoutingChain, antreaMangleChain, "Antrea: jump to Antrea mangle rules"},
+	// The jump rules with atTop set are kept ahead of the rules of kube-proxy when it runs alongside AntreaProxy, so
+	// that the traffic served by AntreaProxy is not DNAT'd by kube-proxy first.
+	type jumpRule struct {
+		table, srcChain, dstChain, comment string
+		atTop                              bool
+	}
+	jumpRules := []jumpRule{
+		{iptables.FilterTable, iptables.ForwardChain, antreaForwardChain, "Antrea: jump to Antrea forwarding rules", false},
+		{iptables.NATTable, iptables.PostRoutingChain, antreaPostRoutingChain, "Antrea: jump to Antrea postrouting rules", false},
+		{iptables.MangleTable, iptables.PreRoutingChain, antreaMangleChain, "Antrea: jump to Antrea mangle rules", false},
+	}
+	if c.nodePortEnabled {
+		jumpRules = append(jumpRules,
+			jumpRule{iptables.NATTable, iptables.PreRoutingChain, antreaNodePortChain, "Antrea: jump to Antrea NodePort rules", true},
+			jumpRule{iptables.NATTable, iptables.OutputChain, antreaNodePortChain, "Antrea: jump to Antrea NodePort rules", true},
+			jumpRule{iptables.RawTable, iptables.PreRoutingChain, antreaRawChain, "Antrea: jump to Antrea raw rules", true},
+			jumpRule{iptables.RawTable, iptables.OutputChain, antreaRawChain, "Antrea: jump to Antrea raw rules", true},
+		)
+	}
+	if c.hostNetworkEnabled {
+		jumpRules = append(jumpRules,
+			jumpRule{iptables.NATTable, iptables.OutputChain, antreaOutputChain, "Antrea: jump to Antrea output rules", true})
 	}
 	for _, rule := range jumpRules {
 		if err := c.ipt.EnsureChain(rule.table, rule.dstChain); err != nil {
 			return err
 		}
 		ruleSpec := []string{"-j", rule.dstChain, "-m", "comment", "--comment", rule.comment}
-		if err := c.ipt.EnsureRule(rule.table, rule.srcChain, ruleSpec); err != nil {
+		ensureRule := c.ipt.EnsureRule
+		if rule.atTop {
+			ensureRule = c.ipt.InsertRuleAtTop
+		}
+		if err := ensureRule(rule.table, rule.srcChain, ruleSpec); err != nil {
 			return err
 		}
 	}
@@ -174,6 +304,28 @@ func (c *Client) initIPTables() error {
 	// with a single call, instead of string matching to clean up stale rules.
 	iptablesData := bytes.NewBuffer(nil)
 	// Write head lines anyway so the undesired rules can be deleted when noEncap -> encap.
+	writeLine(iptablesData, "*raw")
+	writeLine(iptablesData, iptables.MakeChainLine(antreaRawChain))
+	if c.nodePortEnabled {
+		// The traffic to the LoadBalancer IPs of the Services with the Local external traffic policy is routed to OVS
+		// without DNAT and is load-balanced to the local Endpoints there, so the host network doesn't need to track
+		// it. Skipping conntrack also keeps the packets away from the nat table, including the rules of kube-proxy.
+		writeLine(iptablesData, []string{
+			"-A", antreaRawChain,
+			"-m", "comment", "--comment", `"Antrea: do not track packets to LoadBalancer IPs of Services with the Local external traffic policy"`,
+			"-m", "set", "--match-set", antreaLoadBalancerLocalIPSet, "dst,dst",
+			"-j", iptables.NoTrackTarget,
+		}...)
+		writeLine(iptablesData, []string{
+			"-A", antreaRawChain,
+			"-m", "comment", "--comment", `"Antrea: do not track reply packets from LoadBalancer IPs of Services with the Local external traffic policy"`,
+			"-i", c.nodeConfig.GatewayConfig.Name,
+			"-m", "set", "--match-set", antreaLoadBalancerLocalIPSet, "src,src",
+			"-j", iptables.NoTrackTarget,
+		}...)
+	}
+	writeLine(iptablesData, "COMMIT")
+
 	writeLine(iptablesData, "*mangle")
 	writeLine(iptablesData, iptables.MakeChainLine(antreaMangleChain))
 	hostGateway := c.nodeConfig.GatewayConfig.Name
@@ -204,7 +356,15 @@ func (c *Client) initIPTables() error {
 	// Antrea should not get involved.
 	writeLine(iptablesData, "*nat")
 	writeLine(iptablesData, iptables.MakeChainLine(antreaPostRoutingChain))
+	writeLine(iptablesData, iptables.MakeChainLine(antreaEgressChain))
 	if !c.encapMode.IsNetworkPolicyOnly() {
+		// The packets from the Pods selected by Egresses are SNAT'd with the Egress IPs instead of being masqueraded
+		// with the Node IP. The rules are added to antreaEgressChain by AddSNATRule.
+		writeLine(iptablesData, []string{
+			"-A", antreaPostRoutingChain,
+			"-m", "comment", "--comment", `"Antrea: jump to Antrea Egress SNAT rules"`,
+			"-j", antreaEgressChain,
+		}...)
 		writeLine(iptablesData, []string{
 			"-A", antreaPostRoutingChain,
 			"-m", "comment", "--comment", `"Antrea: masquerade pod to external packets"`,
@@ -212,6 +372,46 @@ func (c *Client) initIPTables() error {
 			"-j", iptables.MasqueradeTarget,
 		}...)
 	}
+	writeLine(iptablesData, iptables.MakeChainLine(antreaNodePortChain))
+	writeLine(iptablesData, iptables.MakeChainLine(antreaOutputChain))
+	if c.nodePortEnabled {
+		// The NodePort traffic is DNAT'd to the virtual IP, which is routed to OVS through the host gateway. Only the
+		// destination IP is translated, the NodePort is load-balanced in OVS.
+		writeLine(iptablesData, []string{
+			"-A", antreaNodePortChain,
+			"-m", "comment", "--comment", `"Antrea: DNAT NodePort packets to the NodePort virtual IP"`,
+			"-m", "set", "--match-set", antreaNodePortIPSet, "dst,dst",
+			"-j", iptables.DNATTarget, "--to-destination", config.NodePortVirtualIP.String(),
+		}...)
+		// The client IP is only preserved for the Services with the Local external traffic policy, whose Endpoints
+		// are all on this Node so that the reply packets are sent back through the host gateway.
+		writeLine(iptablesData, []string{
+			"-A", antreaPostRoutingChain,
+			"-m", "comment", "--comment", `"Antrea: masquerade NodePort packets to Services with the Cluster external traffic policy"`,
+			"-o", hostGateway, "-d", config.NodePortVirtualIP.String(),
+			"-m", "set", "!", "--match-set", antreaNodePortLocalIPSet, "dst,dst",
+			"-j", iptables.MasqueradeTarget,
+		}...)
+	}
+	if c.hostNetworkEnabled {
+		// The host traffic to the ClusterIPs is accepted by the nat table before reaching the rules of kube-proxy,
+		// if any, so that it's not DNAT'd twice, by kube-proxy and then by AntreaProxy.
+		writeLine(iptablesData, []string{
+			"-A", antreaOutputChain,
+			"-m", "comment", "--comment", `"Antrea: skip kube-proxy DNAT for host packets to ClusterIPs"`,
+			"-d", c.serviceCIDR.String(),
+			"-j", iptables.AcceptTarget,
+		}...)
+		// The host traffic to the ClusterIPs is routed to OVS through the host gateway. It is masqueraded so that the
+		// reply packets are sent back through the host gateway, even when the source IP is another address of the
+		// Node.
+		writeLine(iptablesData, []string{
+			"-A", antreaPostRoutingChain,
+			"-m", "comment", "--comment", `"Antrea: masquerade host packets to ClusterIPs"`,
+			"-o", hostGateway, "-d", c.serviceCIDR.String(),
+			"-j", iptables.MasqueradeTarget,
+		}...)
+	}
 	writeLine(iptablesData, "COMMIT")
 
 	// Setting --noflush to keep the previous contents (i.e. non antrea managed chains) of the tables.
@@ -229,6 +429,55 @@ func (c *Client) initIPRoutes() error {
 			return fmt.Errorf("failed to add address %s to gw %s: %v", gwIP, gwLink.Attrs().Name, err)
 		}
 	}
+	if c.nodePortEnabled || c.hostNetworkEnabled {
+		if err := c.initNodePortVirtualIPRoute(); err != nil {
+			return err
+		}
+	}
+	if c.hostNetworkEnabled {
+		if err := c.initServiceCIDRRoute(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initNodePortVirtualIPRoute routes the NodePort virtual IP to the host gateway. The neighbor of the virtual IP is
+// resolved statically to the MAC of the host gateway, as the destination MAC of the NodePort packets is rewritten
+// in OVS when they are forwarded to the selected Endpoint. The virtual IP is also the next hop of the Service CIDR.
+func (c *Client) initNodePortVirtualIPRoute() error {
+	route := &netlink.Route{
+		Dst:       &net.IPNet{IP: config.NodePortVirtualIP, Mask: net.CIDRMask(32, 32)},
+		LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex,
+		Scope:     netlink.SCOPE_LINK,
+	}
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route to NodePort virtual IP %s: %v", config.NodePortVirtualIP, err)
+	}
+	neigh := &netlink.Neigh{
+		LinkIndex:    c.nodeConfig.GatewayConfig.LinkIndex,
+		Family:       netlink.FAMILY_V4,
+		State:        netlink.NUD_PERMANENT,
+		IP:           config.NodePortVirtualIP,
+		HardwareAddr: c.nodeConfig.GatewayConfig.MAC,
+	}
+	if err := netlink.NeighSet(neigh); err != nil {
+		return fmt.Errorf("failed to add neighbor for NodePort virtual IP %s: %v", config.NodePortVirtualIP, err)
+	}
+	return nil
+}
+
+// initServiceCIDRRoute routes the Service CIDR to the host gateway through the NodePort virtual IP, so that the
+// packets from the Node network namespace to the ClusterIPs are load-balanced in OVS like the ones from the Pods.
+func (c *Client) initServiceCIDRRoute() error {
+	route := &netlink.Route{
+		Dst:       c.serviceCIDR,
+		Gw:        config.NodePortVirtualIP,
+		LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex,
+	}
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route to Service CIDR %s: %v", c.serviceCIDR, err)
+	}
 	return nil
 }
 
@@ -269,6 +518,13 @@ func (c *Client) Reconcile(podCIDRs []string) error {
 		if reflect.DeepEqual(route.Dst, c.nodeConfig.PodCIDR) {
 			continue
 		}
+		if (c.nodePortEnabled || c.hostNetworkEnabled) && route.Dst != nil && route.Dst.IP.Equal(config.NodePortVirtualIP) {
+			continue
+		}
+		// The Service CIDR and the LoadBalancer IPs are routed through the NodePort virtual IP.
+		if (c.nodePortEnabled || c.hostNetworkEnabled) && route.Gw.Equal(config.NodePortVirtualIP) {
+			continue
+		}
 		if desiredPodCIDRs.Has(route.Dst.String()) {
 			continue
 		}
@@ -288,7 +544,7 @@ func (c *Client) listIPRoutesOnGW() ([]netlink.Route, error) {
 }
 
 // AddRoutes adds routes to a new podCIDR. It overrides the routes if they already exist.
-func (c *Client) AddRoutes(podCIDR *net.IPNet, nodeIP, nodeGwIP net.IP) error {
+func (c *Client) AddRoutes(podCIDR *net.IPNet, nodeIP, nodeGwIP net.IP, peerEncapMode config.TrafficEncapModeType) error {
 	podCIDRStr := podCIDR.String()
 	// Add this podCIDR to antreaPodIPSet so that packets to them won't be masqueraded when they leave the host.
 	if err := ipset.AddEntry(antreaPodIPSet, podCIDRStr); err != nil {
@@ -298,11 +554,11 @@ func (c *Client) AddRoutes(podCIDR *net.IPNet, nodeIP, nodeGwIP net.IP) error {
 	route := &netlink.Route{
 		Dst: podCIDR,
 	}
-	if c.encapMode.NeedsEncapToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
+	if peerEncapMode.NeedsEncapToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
 		route.Flags = int(netlink.FLAG_ONLINK)
 		route.LinkIndex = c.nodeConfig.GatewayConfig.LinkIndex
 		route.Gw = nodeGwIP
-	} else if !c.encapMode.NeedsRoutingToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
+	} else if !peerEncapMode.NeedsRoutingToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
 		// NoEncap traffic need routing help.
 		route.Gw = nodeIP
 	} else {
@@ -337,6 +593,223 @@ func (c *Client) DeleteRoutes(podCIDR *net.IPNet) error {
 	return nil
 }
 
+// AddLocalPodRoute routes the IP of a local Pod allocated by Antrea IPAM to the gateway. The route can be advertised
+// to the underlay network by a routing daemon.
+func (c *Client) AddLocalPodRoute(podIP net.IP) error {
+	route := localPodRoute(podIP, c.nodeConfig.GatewayConfig.LinkIndex)
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route to local Pod IP %s: %v", podIP, err)
+	}
+	return nil
+}
+
+// DeleteLocalPodRoute deletes the route to the IP of a local Pod. It does nothing if the route doesn't exist.
+func (c *Client) DeleteLocalPodRoute(podIP net.IP) error {
+	route := localPodRoute(podIP, c.nodeConfig.GatewayConfig.LinkIndex)
+	if err := netlink.RouteDel(route); err != nil && err != unix.ESRCH {
+		return fmt.Errorf("failed to delete route to local Pod IP %s: %v", podIP, err)
+	}
+	return nil
+}
+
+// AddPodNeighborProxy adds the proxy neighbor entry of the IP of a local Pod on the transport interface, when the
+// neighbor proxy is enabled.
+func (c *Client) AddPodNeighborProxy(podIP net.IP) error {
+	if neigh := c.neighborProxy(podIP); neigh != nil {
+		if err := netlink.NeighSet(neigh); err != nil {
+			return fmt.Errorf("failed to add proxy neighbor for local Pod IP %s: %v", podIP, err)
+		}
+	}
+	return nil
+}
+
+// DeletePodNeighborProxy deletes the proxy neighbor entry of the IP of a local Pod. It does nothing if the entry
+// doesn't exist.
+func (c *Client) DeletePodNeighborProxy(podIP net.IP) error {
+	if neigh := c.neighborProxy(podIP); neigh != nil {
+		if err := netlink.NeighDel(neigh); err != nil && err != unix.ENOENT {
+			return fmt.Errorf("failed to delete proxy neighbor for local Pod IP %s: %v", podIP, err)
+		}
+	}
+	return nil
+}
+
+// AddNodePort adds the NodePort on the provided Node addresses to the ipset used to DNAT the NodePort traffic to
+// the NodePort virtual IP. The client IP of the traffic is preserved when onlyLocal is true.
+func (c *Client) AddNodePort(nodeIPs []net.IP, port uint16, protocol binding.Protocol, onlyLocal bool) error {
+	for _, nodeIP := range nodeIPs {
+		if err := ipset.AddEntry(antreaNodePortIPSet, nodePortEntry(nodeIP, port, protocol)); err != nil {
+			return err
+		}
+	}
+	localEntry := nodePortEntry(config.NodePortVirtualIP, port, protocol)
+	if onlyLocal {
+		return ipset.AddEntry(antreaNodePortLocalIPSet, localEntry)
+	}
+	return ipset.DelEntry(antreaNodePortLocalIPSet, localEntry)
+}
+
+// DeleteNodePort deletes the NodePort on the provided Node addresses from the ipsets.
+func (c *Client) DeleteNodePort(nodeIPs []net.IP, port uint16, protocol binding.Protocol) error {
+	for _, nodeIP := range nodeIPs {
+		if err := ipset.DelEntry(antreaNodePortIPSet, nodePortEntry(nodeIP, port, protocol)); err != nil {
+			return err
+		}
+	}
+	return ipset.DelEntry(antreaNodePortLocalIPSet, nodePortEntry(config.NodePortVirtualIP, port, protocol))
+}
+
+// AddLoadBalancer routes the LoadBalancer IP to the host gateway through the NodePort virtual IP, so that the traffic
+// to it from outside the cluster is load-balanced in OVS without DNAT in the host network.
+func (c *Client) AddLoadBalancer(ip net.IP) error {
+	route := loadBalancerRoute(ip, c.nodeConfig.GatewayConfig.LinkIndex)
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route to LoadBalancer IP %s: %v", ip, err)
+	}
+	return nil
+}
+
+// DeleteLoadBalancer deletes the route to the LoadBalancer IP. It does nothing if the route doesn't exist.
+func (c *Client) DeleteLoadBalancer(ip net.IP) error {
+	route := loadBalancerRoute(ip, c.nodeConfig.GatewayConfig.LinkIndex)
+	if err := netlink.RouteDel(route); err != nil && err != unix.ESRCH {
+		return fmt.Errorf("failed to delete route to LoadBalancer IP %s: %v", ip, err)
+	}
+	return nil
+}
+
+// AddLocalLoadBalancer routes the LoadBalancer IP to the host gateway like AddLoadBalancer, and adds the port of the
+// LoadBalancer IP to the ipset whose traffic is not tracked by the host network.
+func (c *Client) AddLocalLoadBalancer(ip net.IP, port uint16, protocol binding.Protocol) error {
+	if err := c.AddLoadBalancer(ip); err != nil {
+		return err
+	}
+	return ipset.AddEntry(antreaLoadBalancerLocalIPSet, nodePortEntry(ip, port, protocol))
+}
+
+// DeleteLocalLoadBalancer deletes the port of the LoadBalancer IP from the ipset whose traffic is not tracked by the
+// host network. The route to the LoadBalancer IP is deleted by DeleteLoadBalancer.
+func (c *Client) DeleteLocalLoadBalancer(ip net.IP, port uint16, protocol binding.Protocol) error {
+	return ipset.DelEntry(antreaLoadBalancerLocalIPSet, nodePortEntry(ip, port, protocol))
+}
+
+// LookupRoute queries the kernel routing table for the route used to reach the provided IP.
+func (c *Client) LookupRoute(ip net.IP) (string, net.IP, net.IP, error) {
+	routes, err := netlink.RouteGet(ip)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to get route to %s: %v", ip, err)
+	}
+	if len(routes) == 0 {
+		return "", nil, nil, fmt.Errorf("no route to %s", ip)
+	}
+	route := routes[0]
+	link, err := netlink.LinkByIndex(route.LinkIndex)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to get link with index %d: %v", route.LinkIndex, err)
+	}
+	return link.Attrs().Name, route.Gw, route.Src, nil
+}
+
+// snatRule is the SNAT IP and the optional source port range the packets of a Pod are SNAT'd with.
+type snatRule struct {
+	snatIP    net.IP
+	portRange *binding.PortRange
+}
+
+func (r snatRule) equal(other snatRule) bool {
+	if !r.snatIP.Equal(other.snatIP) {
+		return false
+	}
+	if r.portRange == nil || other.portRange == nil {
+		return r.portRange == other.portRange
+	}
+	return *r.portRange == *other.portRange
+}
+
+// AddSNATRule adds the rules to SNAT the packets from the Pod IP to external addresses with the SNAT IP to
+// antreaEgressChain. The packets from remote Pods are received from the host gateway, as they are forwarded to this
+// Node through the tunnel. If portRange is not nil, the source ports of the TCP and UDP packets are translated within
+// the range, while the packets of the other protocols are SNAT'd without port translation.
+func (c *Client) AddSNATRule(podIP, snatIP net.IP, portRange *binding.PortRange) error {
+	rule := snatRule{snatIP: snatIP, portRange: portRange}
+	if oldRule, exists := c.snatRules.Load(podIP.String()); exists {
+		if oldRule.(snatRule).equal(rule) {
+			return nil
+		}
+		if err := c.deleteSNATRuleSpecs(podIP, oldRule.(snatRule)); err != nil {
+			return err
+		}
+		c.snatRules.Delete(podIP.String())
+	}
+	// The rules with a port range are added before the one without, which matches all protocols.
+	for _, ruleSpec := range snatRuleSpecs(podIP, rule) {
+		if err := c.ipt.EnsureRule(iptables.NATTable, antreaEgressChain, ruleSpec); err != nil {
+			return err
+		}
+	}
+	c.snatRules.Store(podIP.String(), rule)
+	return nil
+}
+
+// DeleteSNATRule deletes the rules added by AddSNATRule for the Pod IP.
+func (c *Client) DeleteSNATRule(podIP net.IP) error {
+	rule, exists := c.snatRules.Load(podIP.String())
+	if !exists {
+		return nil
+	}
+	if err := c.deleteSNATRuleSpecs(podIP, rule.(snatRule)); err != nil {
+		return err
+	}
+	c.snatRules.Delete(podIP.String())
+	return nil
+}
+
+func (c *Client) deleteSNATRuleSpecs(podIP net.IP, rule snatRule) error {
+	for _, ruleSpec := range snatRuleSpecs(podIP, rule) {
+		if err := c.ipt.DeleteRule(iptables.NATTable, antreaEgressChain, ruleSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snatRuleSpecs returns the iptables rules SNATing the packets from the Pod IP. A port range can only be given to the
+// SNAT target in a rule matching a protocol with ports, so the TCP and UDP packets are matched by dedicated rules.
+func snatRuleSpecs(podIP net.IP, rule snatRule) [][]string {
+	matchSpec := []string{"-s", podIP.String(), "-m", "set", "!", "--match-set", antreaPodIPSet, "dst"}
+	var ruleSpecs [][]string
+	if rule.portRange != nil {
+		toSource := fmt.Sprintf("%s:%d-%d", rule.snatIP.String(), rule.portRange.StartPort, rule.portRange.EndPort)
+		for _, protocol := range []string{"tcp", "udp"} {
+			ruleSpec := append([]string{"-p", protocol}, matchSpec...)
+			ruleSpecs = append(ruleSpecs, append(ruleSpec, "-j", iptables.SNATTarget, "--to-source", toSource))
+		}
+	}
+	ruleSpec := append([]string{}, matchSpec...)
+	return append(ruleSpecs, append(ruleSpec, "-j", iptables.SNATTarget, "--to-source", rule.snatIP.String()))
+}
+
+func localPodRoute(ip net.IP, gwLinkIndex int) *netlink.Route {
+	return &netlink.Route{
+		Dst:       &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)},
+		Scope:     netlink.SCOPE_LINK,
+		LinkIndex: gwLinkIndex,
+	}
+}
+
+func loadBalancerRoute(ip net.IP, gwLinkIndex int) *netlink.Route {
+	return &netlink.Route{
+		Dst:       &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)},
+		Gw:        config.NodePortVirtualIP,
+		LinkIndex: gwLinkIndex,
+	}
+}
+
+// nodePortEntry returns the hash:ip,port ipset entry of the port on the IP, e.g. "192.168.1.1,tcp:30000".
+func nodePortEntry(ip net.IP, port uint16, protocol binding.Protocol) string {
+	return fmt.Sprintf("%s,%s:%d", ip, protocol, port)
+}
+
 // Join all words with spaces, terminate with newline and write to buf.
 func writeLine(buf *bytes.Buffer, words ...string) {
 	// We avoid strings.Join for performance reasons.
@@ -18,6 +18,7 @@ package route
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"sync"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/config"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/winfirewall"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 )
 
 const (
@@ -44,7 +46,7 @@ type Client struct {
 }
 
 // NewClient returns a route client.
-func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType) (*Client, error) {
+func NewClient(serviceCIDR *net.IPNet, encapMode config.TrafficEncapModeType, nodePortEnabled, hostNetworkEnabled, neighborProxyEnabled bool) (*Client, error) {
 	nr := netroute.New()
 	return &Client{
 		nr:          nr,
@@ -91,34 +93,75 @@ func (c *Client) Reconcile(podCIDRs []string) error {
 			return err
 		}
 	}
+	// The routes to the peer Nodes using host-gateway routing are on the OVS bridge interface, which also has the
+	// host routes, so only the desired ones are restored in the cache.
+	return c.restoreBridgeRoutes(desiredPodCIDRs)
+}
+
+func (c *Client) restoreBridgeRoutes(desiredPodCIDRs sets.String) error {
+	brInterface, err := net.InterfaceByName(c.nodeConfig.OVSBridge)
+	if err != nil {
+		// The OVS bridge interface may not exist if the uplink is not configured yet.
+		klog.V(2).Infof("Skip restoring routes on OVS bridge interface %s: %v", c.nodeConfig.OVSBridge, err)
+		return nil
+	}
+	routes, err := c.nr.GetNetRoutesAll()
+	if err != nil {
+		return err
+	}
+	for idx := range routes {
+		rt := routes[idx]
+		if rt.LinkIndex != brInterface.Index || !desiredPodCIDRs.Has(rt.DestinationSubnet.String()) {
+			continue
+		}
+		c.hostRoutes.Store(rt.DestinationSubnet.String(), &rt)
+	}
 	return nil
 }
 
 // AddRoutes adds routes to the provided podCIDR.
 // It overrides the routes if they already exist, without error.
-func (c *Client) AddRoutes(podCIDR *net.IPNet, peerNodeIP, peerGwIP net.IP) error {
+// If the traffic to the peer Node is encapsulated, the route uses the peer gateway as the next hop on the host
+// gateway, and the packets are forwarded to the tunnel by OVS. Otherwise, the route uses the peer Node as the next hop
+// on the OVS bridge interface, which has the Node's IP configuration, to support host-gateway routing. The peer Node
+// must be in the same subnet as the local Node in the latter case.
+func (c *Client) AddRoutes(podCIDR *net.IPNet, peerNodeIP, peerGwIP net.IP, peerEncapMode config.TrafficEncapModeType) error {
+	linkIndex := c.nodeConfig.GatewayConfig.LinkIndex
+	nextHop := peerGwIP
+	if !peerEncapMode.NeedsEncapToPeer(peerNodeIP, c.nodeConfig.NodeIPAddr) {
+		if peerEncapMode.NeedsRoutingToPeer(peerNodeIP, c.nodeConfig.NodeIPAddr) {
+			// The peer Node is not in the same subnet, the traffic is handled by the host default route.
+			return nil
+		}
+		brInterface, err := net.InterfaceByName(c.nodeConfig.OVSBridge)
+		if err != nil {
+			return fmt.Errorf("failed to get OVS bridge interface %s: %v", c.nodeConfig.OVSBridge, err)
+		}
+		linkIndex = brInterface.Index
+		nextHop = peerNodeIP
+	}
 	obj, found := c.hostRoutes.Load(podCIDR.String())
 	if found {
 		rt := obj.(*netroute.Route)
-		if rt.GatewayAddress.Equal(peerGwIP) {
+		if rt.LinkIndex == linkIndex && rt.GatewayAddress.Equal(nextHop) {
 			klog.V(4).Infof("Route with destination %s already exists", podCIDR.String())
 			return nil
 		}
 		// Remove the existing route entry if the gateway address is not as expected.
 		if err := c.nr.RemoveNetRoute(rt.LinkIndex, rt.DestinationSubnet, rt.GatewayAddress); err != nil {
-			klog.Errorf("Failed to delete existing route entry with destination %s gateway %s", podCIDR.String(), peerGwIP.String())
+			klog.Errorf("Failed to delete existing route entry with destination %s gateway %s", podCIDR.String(), nextHop.String())
 			return err
 		}
 	}
-	if err := c.nr.NewNetRoute(c.nodeConfig.GatewayConfig.LinkIndex, podCIDR, peerGwIP); err != nil {
+	if err := c.nr.NewNetRoute(linkIndex, podCIDR, nextHop); err != nil {
 		return err
 	}
 	c.hostRoutes.Store(podCIDR.String(), &netroute.Route{
-		LinkIndex:         c.nodeConfig.GatewayConfig.LinkIndex,
+		LinkIndex:         linkIndex,
 		DestinationSubnet: podCIDR,
-		GatewayAddress:    peerGwIP,
+		GatewayAddress:    nextHop,
 	})
-	klog.V(2).Infof("Added route with destination %s via %s on host gateway", podCIDR.String(), peerGwIP.String())
+	klog.V(2).Infof("Added route with destination %s via %s on interface %d", podCIDR.String(), nextHop.String(), linkIndex)
 	return nil
 }
 
@@ -150,6 +193,100 @@ func (c *Client) UnMigrateRoutesFromGw(route *net.IPNet, linkName string) error
 	return errors.New("UnMigrateRoutesFromGw is unsupported on Windows")
 }
 
+// AddLocalPodRoute is not supported on Windows.
+func (c *Client) AddLocalPodRoute(podIP net.IP) error {
+	return errors.New("AddLocalPodRoute is unsupported on Windows")
+}
+
+// DeleteLocalPodRoute is not supported on Windows.
+func (c *Client) DeleteLocalPodRoute(podIP net.IP) error {
+	return errors.New("DeleteLocalPodRoute is unsupported on Windows")
+}
+
+// AddPodNeighborProxy does nothing on Windows, as the neighbor proxy is not supported.
+func (c *Client) AddPodNeighborProxy(podIP net.IP) error {
+	return nil
+}
+
+// DeletePodNeighborProxy does nothing on Windows, as the neighbor proxy is not supported.
+func (c *Client) DeletePodNeighborProxy(podIP net.IP) error {
+	return nil
+}
+
+// AddNodePort is not supported on Windows.
+func (c *Client) AddNodePort(nodeIPs []net.IP, port uint16, protocol binding.Protocol, onlyLocal bool) error {
+	return errors.New("AddNodePort is unsupported on Windows")
+}
+
+// DeleteNodePort is not supported on Windows.
+func (c *Client) DeleteNodePort(nodeIPs []net.IP, port uint16, protocol binding.Protocol) error {
+	return errors.New("DeleteNodePort is unsupported on Windows")
+}
+
+// AddLoadBalancer is not supported on Windows.
+func (c *Client) AddLoadBalancer(ip net.IP) error {
+	return errors.New("AddLoadBalancer is unsupported on Windows")
+}
+
+// DeleteLoadBalancer is not supported on Windows.
+func (c *Client) DeleteLoadBalancer(ip net.IP) error {
+	return errors.New("DeleteLoadBalancer is unsupported on Windows")
+}
+
+// AddLocalLoadBalancer is not supported on Windows.
+func (c *Client) AddLocalLoadBalancer(ip net.IP, port uint16, protocol binding.Protocol) error {
+	return errors.New("AddLocalLoadBalancer is unsupported on Windows")
+}
+
+// DeleteLocalLoadBalancer is not supported on Windows.
+func (c *Client) DeleteLocalLoadBalancer(ip net.IP, port uint16, protocol binding.Protocol) error {
+	return errors.New("DeleteLocalLoadBalancer is unsupported on Windows")
+}
+
+// AddSNATRule is not supported on Windows.
+func (c *Client) AddSNATRule(podIP, snatIP net.IP, portRange *binding.PortRange) error {
+	return errors.New("AddSNATRule is unsupported on Windows")
+}
+
+// DeleteSNATRule is not supported on Windows.
+func (c *Client) DeleteSNATRule(podIP net.IP) error {
+	return errors.New("DeleteSNATRule is unsupported on Windows")
+}
+
+// LookupRoute returns the route with the longest matching prefix and the lowest metric for the provided IP. The
+// source IP is always the Node IP, as the packets leaving the Pod network are SNAT'd to it by OVS on Windows.
+func (c *Client) LookupRoute(ip net.IP) (string, net.IP, net.IP, error) {
+	routes, err := c.nr.GetNetRoutesAll()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var best *netroute.Route
+	bestPrefixLen := -1
+	for idx := range routes {
+		rt := &routes[idx]
+		if rt.DestinationSubnet == nil || !rt.DestinationSubnet.Contains(ip) {
+			continue
+		}
+		prefixLen, _ := rt.DestinationSubnet.Mask.Size()
+		if prefixLen > bestPrefixLen || (prefixLen == bestPrefixLen && rt.RouteMetric+rt.IfMetric < best.RouteMetric+best.IfMetric) {
+			best = rt
+			bestPrefixLen = prefixLen
+		}
+	}
+	if best == nil {
+		return "", nil, nil, fmt.Errorf("no route to %s", ip)
+	}
+	link, err := net.InterfaceByIndex(best.LinkIndex)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to get interface with index %d: %v", best.LinkIndex, err)
+	}
+	var nextHop net.IP
+	if !best.GatewayAddress.IsUnspecified() {
+		nextHop = best.GatewayAddress
+	}
+	return link.Name, nextHop, c.nodeConfig.NodeIPAddr.IP, nil
+}
+
 func (c *Client) listRoutes() (map[string]*netroute.Route, error) {
 	routes, err := c.nr.GetNetRoutesAll()
 	if err != nil {
@@ -52,7 +52,7 @@ func TestRouteOperation(t *testing.T) {
 	nr := netroute.New()
 	defer nr.Exit()
 
-	client, err := NewClient(serviceCIDR, 0)
+	client, err := NewClient(serviceCIDR, 0, false, false, false)
 	require.Nil(t, err)
 	nodeConfig := &config.NodeConfig{
 		GatewayConfig: &config.GatewayConfig{
@@ -64,13 +64,13 @@ func TestRouteOperation(t *testing.T) {
 	require.Nil(t, err)
 
 	// Add initial routes.
-	err = client.AddRoutes(destCIDR1, peerNodeIP, gwIP1)
+	err = client.AddRoutes(destCIDR1, peerNodeIP, gwIP1, config.TrafficEncapModeEncap)
 	require.Nil(t, err)
 	routes1, err := nr.GetNetRoutes(gwLink, destCIDR1)
 	require.Nil(t, err)
 	assert.Equal(t, 1, len(routes1))
 
-	err = client.AddRoutes(destCIDR2, peerNodeIP, gwIP2)
+	err = client.AddRoutes(destCIDR2, peerNodeIP, gwIP2, config.TrafficEncapModeEncap)
 	require.Nil(t, err)
 	routes2, err := nr.GetNetRoutes(gwLink, destCIDR2)
 	require.Nil(t, err)
@@ -22,6 +22,7 @@ package testing
 import (
 	gomock "github.com/golang/mock/gomock"
 	config "github.com/vmware-tanzu/antrea/pkg/agent/config"
+	openflow "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 	net "net"
 	reflect "reflect"
 )
@@ -49,18 +50,172 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// AddLoadBalancer mocks base method
+func (m *MockInterface) AddLoadBalancer(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLoadBalancer", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLoadBalancer indicates an expected call of AddLoadBalancer
+func (mr *MockInterfaceMockRecorder) AddLoadBalancer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLoadBalancer", reflect.TypeOf((*MockInterface)(nil).AddLoadBalancer), arg0)
+}
+
+// AddLocalLoadBalancer mocks base method
+func (m *MockInterface) AddLocalLoadBalancer(arg0 net.IP, arg1 uint16, arg2 openflow.Protocol) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLocalLoadBalancer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLocalLoadBalancer indicates an expected call of AddLocalLoadBalancer
+func (mr *MockInterfaceMockRecorder) AddLocalLoadBalancer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLocalLoadBalancer", reflect.TypeOf((*MockInterface)(nil).AddLocalLoadBalancer), arg0, arg1, arg2)
+}
+
+// AddLocalPodRoute mocks base method
+func (m *MockInterface) AddLocalPodRoute(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLocalPodRoute", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLocalPodRoute indicates an expected call of AddLocalPodRoute
+func (mr *MockInterfaceMockRecorder) AddLocalPodRoute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLocalPodRoute", reflect.TypeOf((*MockInterface)(nil).AddLocalPodRoute), arg0)
+}
+
+// AddNodePort mocks base method
+func (m *MockInterface) AddNodePort(arg0 []net.IP, arg1 uint16, arg2 openflow.Protocol, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNodePort", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNodePort indicates an expected call of AddNodePort
+func (mr *MockInterfaceMockRecorder) AddNodePort(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNodePort", reflect.TypeOf((*MockInterface)(nil).AddNodePort), arg0, arg1, arg2, arg3)
+}
+
+// AddPodNeighborProxy mocks base method
+func (m *MockInterface) AddPodNeighborProxy(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPodNeighborProxy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPodNeighborProxy indicates an expected call of AddPodNeighborProxy
+func (mr *MockInterfaceMockRecorder) AddPodNeighborProxy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPodNeighborProxy", reflect.TypeOf((*MockInterface)(nil).AddPodNeighborProxy), arg0)
+}
+
 // AddRoutes mocks base method
-func (m *MockInterface) AddRoutes(arg0 *net.IPNet, arg1, arg2 net.IP) error {
+func (m *MockInterface) AddRoutes(arg0 *net.IPNet, arg1, arg2 net.IP, arg3 config.TrafficEncapModeType) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddRoutes", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AddRoutes", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddRoutes indicates an expected call of AddRoutes
-func (mr *MockInterfaceMockRecorder) AddRoutes(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) AddRoutes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRoutes", reflect.TypeOf((*MockInterface)(nil).AddRoutes), arg0, arg1, arg2, arg3)
+}
+
+// AddSNATRule mocks base method
+func (m *MockInterface) AddSNATRule(arg0, arg1 net.IP, arg2 *openflow.PortRange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSNATRule", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSNATRule indicates an expected call of AddSNATRule
+func (mr *MockInterfaceMockRecorder) AddSNATRule(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSNATRule", reflect.TypeOf((*MockInterface)(nil).AddSNATRule), arg0, arg1, arg2)
+}
+
+// DeleteLoadBalancer mocks base method
+func (m *MockInterface) DeleteLoadBalancer(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoadBalancer", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoadBalancer indicates an expected call of DeleteLoadBalancer
+func (mr *MockInterfaceMockRecorder) DeleteLoadBalancer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoadBalancer", reflect.TypeOf((*MockInterface)(nil).DeleteLoadBalancer), arg0)
+}
+
+// DeleteLocalLoadBalancer mocks base method
+func (m *MockInterface) DeleteLocalLoadBalancer(arg0 net.IP, arg1 uint16, arg2 openflow.Protocol) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLocalLoadBalancer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLocalLoadBalancer indicates an expected call of DeleteLocalLoadBalancer
+func (mr *MockInterfaceMockRecorder) DeleteLocalLoadBalancer(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRoutes", reflect.TypeOf((*MockInterface)(nil).AddRoutes), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLocalLoadBalancer", reflect.TypeOf((*MockInterface)(nil).DeleteLocalLoadBalancer), arg0, arg1, arg2)
+}
+
+// DeleteLocalPodRoute mocks base method
+func (m *MockInterface) DeleteLocalPodRoute(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLocalPodRoute", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLocalPodRoute indicates an expected call of DeleteLocalPodRoute
+func (mr *MockInterfaceMockRecorder) DeleteLocalPodRoute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLocalPodRoute", reflect.TypeOf((*MockInterface)(nil).DeleteLocalPodRoute), arg0)
+}
+
+// DeleteNodePort mocks base method
+func (m *MockInterface) DeleteNodePort(arg0 []net.IP, arg1 uint16, arg2 openflow.Protocol) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNodePort", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNodePort indicates an expected call of DeleteNodePort
+func (mr *MockInterfaceMockRecorder) DeleteNodePort(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodePort", reflect.TypeOf((*MockInterface)(nil).DeleteNodePort), arg0, arg1, arg2)
+}
+
+// DeletePodNeighborProxy mocks base method
+func (m *MockInterface) DeletePodNeighborProxy(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePodNeighborProxy", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePodNeighborProxy indicates an expected call of DeletePodNeighborProxy
+func (mr *MockInterfaceMockRecorder) DeletePodNeighborProxy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePodNeighborProxy", reflect.TypeOf((*MockInterface)(nil).DeletePodNeighborProxy), arg0)
 }
 
 // DeleteRoutes mocks base method
@@ -77,6 +232,20 @@ func (mr *MockInterfaceMockRecorder) DeleteRoutes(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoutes", reflect.TypeOf((*MockInterface)(nil).DeleteRoutes), arg0)
 }
 
+// DeleteSNATRule mocks base method
+func (m *MockInterface) DeleteSNATRule(arg0 net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSNATRule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSNATRule indicates an expected call of DeleteSNATRule
+func (mr *MockInterfaceMockRecorder) DeleteSNATRule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSNATRule", reflect.TypeOf((*MockInterface)(nil).DeleteSNATRule), arg0)
+}
+
 // Initialize mocks base method
 func (m *MockInterface) Initialize(arg0 *config.NodeConfig) error {
 	m.ctrl.T.Helper()
@@ -91,6 +260,23 @@ func (mr *MockInterfaceMockRecorder) Initialize(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initialize", reflect.TypeOf((*MockInterface)(nil).Initialize), arg0)
 }
 
+// LookupRoute mocks base method
+func (m *MockInterface) LookupRoute(arg0 net.IP) (string, net.IP, net.IP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupRoute", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(net.IP)
+	ret2, _ := ret[2].(net.IP)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// LookupRoute indicates an expected call of LookupRoute
+func (mr *MockInterfaceMockRecorder) LookupRoute(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupRoute", reflect.TypeOf((*MockInterface)(nil).LookupRoute), arg0)
+}
+
 // MigrateRoutesToGw mocks base method
 func (m *MockInterface) MigrateRoutesToGw(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -16,6 +16,11 @@ package stats
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,7 +28,9 @@ import (
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent"
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	agenttypes "github.com/vmware-tanzu/antrea/pkg/agent/types"
 	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
 	"github.com/vmware-tanzu/antrea/pkg/util/env"
@@ -32,6 +39,18 @@ import (
 const (
 	// Period for performing stats collection and report.
 	collectPeriod = 60 * time.Second
+	// redactedPeer is the peer of the connections with the Pods and Services of other Namespaces, which are not
+	// disclosed to the users of the Namespace.
+	redactedPeer = "<other Namespace>"
+	// otherPeers is the peer of the FlowSummary which aggregates the connections of a Pod exceeding
+	// maxFlowsPerPod.
+	otherPeers = "<others>"
+	// maxFlowsPerPod is the maximum number of FlowSummaries of a Pod in one direction. The connections with the
+	// peers which have the least traffic are aggregated into a single FlowSummary beyond it.
+	maxFlowsPerPod = 20
+	// The IP peers are aggregated by subnet of these prefix lengths.
+	ipv4PeerPrefixLen = 24
+	ipv6PeerPrefixLen = 64
 )
 
 // statsCollection is a collection of stats.
@@ -42,6 +61,34 @@ type statsCollection struct {
 	antreaClusterNetworkPolicyStats map[types.UID]*statsv1alpha1.TrafficStats
 	// antreaNetworkPolicyStats is a mapping from Antrea NetworkPolicy UIDs to their traffic stats.
 	antreaNetworkPolicyStats map[types.UID]*statsv1alpha1.TrafficStats
+	// antreaPolicyRuleStats is a mapping from the rules of Antrea-native policies to their traffic stats.
+	antreaPolicyRuleStats map[ruleKey]*statsv1alpha1.TrafficStats
+	// flows is the summaries of the connections of the local Pods. Unlike the stats of the policies, it is reported
+	// as is instead of as a delta.
+	flows []statsv1alpha1.FlowSummary
+}
+
+// flowKey identifies the FlowSummary which a connection is added to.
+type flowKey struct {
+	namespace string
+	pod       string
+	direction statsv1alpha1.RuleDirection
+	peer      string
+	protocol  string
+	port      int32
+}
+
+// ConnectionStore is the interface of the store of the connections tracked by the Node, which is used to summarize
+// the connections of the local Pods.
+type ConnectionStore interface {
+	ForAllConnectionsDo(callback flowexporter.ConnectionMapCallBack) error
+}
+
+// ruleKey identifies a rule of an Antrea-native policy.
+type ruleKey struct {
+	policyUID types.UID
+	direction statsv1alpha1.RuleDirection
+	index     int32
 }
 
 // Collector is responsible for collecting stats from the Openflow client, calculating the delta compared with the last
@@ -56,6 +103,9 @@ type Collector struct {
 	// lastStatsCollection is the last statistics that has been reported to antrea-controller successfully.
 	// It is used to calculate the delta of the statistics that will be reported.
 	lastStatsCollection *statsCollection
+	// connStore is the store of the connections tracked by the Node. It is nil when the flow exporter doesn't run in
+	// antrea-agent, in which case no FlowSummary is reported.
+	connStore ConnectionStore
 }
 
 func NewCollector(antreaClientProvider agent.AntreaClientProvider, ofClient openflow.Client) *Collector {
@@ -68,6 +118,12 @@ func NewCollector(antreaClientProvider agent.AntreaClientProvider, ofClient open
 	return manager
 }
 
+// SetConnectionStore sets the store of the connections which are summarized in the reports. It must be called before
+// Run.
+func (m *Collector) SetConnectionStore(connStore ConnectionStore) {
+	m.connStore = connStore
+}
+
 // Run runs a loop that collects statistics and reports them until the provided channel is closed.
 func (m *Collector) Run(stopCh <-chan struct{}) {
 	klog.Info("Start collecting metrics")
@@ -102,11 +158,12 @@ func (m *Collector) collect() *statsCollection {
 	// TODO: The following process is not atomic, there's a chance that the ofID is released and reused by another
 	//  NetworkPolicy rule in-between, leading to incorrect metrics. We should return relevant NetworkPolicy references
 	//  along with metrics to avoid it.
-	ruleStatsMap := m.ofClient.NetworkPolicyMetrics()
+	ruleMetricsMap := m.ofClient.NetworkPolicyMetrics()
 	npStatsMap := map[types.UID]*statsv1alpha1.TrafficStats{}
 	acnpStatsMap := map[types.UID]*statsv1alpha1.TrafficStats{}
 	anpStatsMap := map[types.UID]*statsv1alpha1.TrafficStats{}
-	for ofID, ruleStats := range ruleStatsMap {
+	ruleStatsMap := map[ruleKey]*statsv1alpha1.TrafficStats{}
+	for ofID, ruleMetric := range ruleMetricsMap {
 		policyRef := m.ofClient.GetPolicyFromConjunction(ofID)
 		// Same as above, this may be because the NetworkPolicy is removed right after the metrics are fetched.
 		if policyRef == nil {
@@ -130,15 +187,218 @@ func (m *Collector) collect() *statsCollection {
 			policyStats = new(statsv1alpha1.TrafficStats)
 			statsMap[policyRef.UID] = policyStats
 		}
-		policyStats.Bytes += int64(ruleStats.Bytes)
-		policyStats.Sessions += int64(ruleStats.Sessions)
-		policyStats.Packets += int64(ruleStats.Packets)
+		addRuleMetric(policyStats, ruleMetric)
+
+		if policyRef.Type == cpv1beta1.K8sNetworkPolicy {
+			continue
+		}
+		// The rules of K8s NetworkPolicies cannot be identified, the stats of the rules are only collected for
+		// Antrea-native policies. A rule may be realized by several conjunctions, whose stats are added up.
+		direction, rulePriority, found := m.ofClient.GetRuleFromConjunction(ofID)
+		if !found {
+			klog.Infof("Cannot find NetworkPolicy rule that has ofID %v", ofID)
+			continue
+		}
+		key := ruleKey{policyUID: policyRef.UID, direction: statsv1alpha1.RuleDirectionIngress, index: rulePriority}
+		if direction == cpv1beta1.DirectionOut {
+			key.direction = statsv1alpha1.RuleDirectionEgress
+		}
+		ruleStats, exists := ruleStatsMap[key]
+		if !exists {
+			ruleStats = new(statsv1alpha1.TrafficStats)
+			ruleStatsMap[key] = ruleStats
+		}
+		addRuleMetric(ruleStats, ruleMetric)
 	}
 	return &statsCollection{
 		networkPolicyStats:              npStatsMap,
 		antreaClusterNetworkPolicyStats: acnpStatsMap,
 		antreaNetworkPolicyStats:        anpStatsMap,
+		antreaPolicyRuleStats:           ruleStatsMap,
+		flows:                           m.collectFlows(),
+	}
+}
+
+// collectFlows summarizes the active connections of the local Pods. A connection between two local Pods is added to
+// the summaries of both Pods. As the summaries are visible to the users of the Namespace of the Pod, the Pods and
+// Services of other Namespaces are redacted, and the IPs are aggregated by subnet. The connections of a Pod beyond
+// maxFlowsPerPod peers in one direction are aggregated by capFlows.
+func (m *Collector) collectFlows() []statsv1alpha1.FlowSummary {
+	if m.connStore == nil {
+		return nil
+	}
+	flowMap := map[flowKey]*statsv1alpha1.TrafficStats{}
+	addFlow := func(key flowKey, conn *flowexporter.Connection) {
+		flowStats, exists := flowMap[key]
+		if !exists {
+			flowStats = new(statsv1alpha1.TrafficStats)
+			flowMap[key] = flowStats
+		}
+		flowStats.Sessions++
+		flowStats.Packets += int64(conn.OriginalPackets + conn.ReversePackets)
+		flowStats.Bytes += int64(conn.OriginalBytes + conn.ReverseBytes)
+	}
+	m.connStore.ForAllConnectionsDo(func(_ flowexporter.ConnectionKey, conn flowexporter.Connection) error {
+		if !conn.IsActive {
+			return nil
+		}
+		protocol := protocolName(conn.TupleOrig.Protocol)
+		port := int32(conn.TupleOrig.DestinationPort)
+		if conn.SourcePodName != "" {
+			peer := ipPeer(conn.TupleOrig.DestinationAddress)
+			if conn.DestinationServicePortName != "" {
+				peer = namespacedPeer(conn.SourcePodNamespace, conn.DestinationServicePortName)
+			} else if conn.DestinationPodName != "" {
+				peer = namespacedPeer(conn.SourcePodNamespace, fmt.Sprintf("%s/%s", conn.DestinationPodNamespace, conn.DestinationPodName))
+			}
+			addFlow(flowKey{
+				namespace: conn.SourcePodNamespace,
+				pod:       conn.SourcePodName,
+				direction: statsv1alpha1.RuleDirectionEgress,
+				peer:      peer,
+				protocol:  protocol,
+				port:      port,
+			}, &conn)
+		}
+		if conn.DestinationPodName != "" {
+			peer := ipPeer(conn.TupleOrig.SourceAddress)
+			if conn.SourcePodName != "" {
+				peer = namespacedPeer(conn.DestinationPodNamespace, fmt.Sprintf("%s/%s", conn.SourcePodNamespace, conn.SourcePodName))
+			}
+			addFlow(flowKey{
+				namespace: conn.DestinationPodNamespace,
+				pod:       conn.DestinationPodName,
+				direction: statsv1alpha1.RuleDirectionIngress,
+				peer:      peer,
+				protocol:  protocol,
+				// The connections to a Service are summarized with the port of the Endpoint.
+				port: int32(conn.TupleReply.SourcePort),
+			}, &conn)
+		}
+		return nil
+	})
+	if len(flowMap) == 0 {
+		return nil
+	}
+	flows := make([]statsv1alpha1.FlowSummary, 0, len(flowMap))
+	for key, flowStats := range flowMap {
+		flows = append(flows, statsv1alpha1.FlowSummary{
+			Namespace:    key.namespace,
+			Pod:          key.pod,
+			Direction:    key.direction,
+			Peer:         key.peer,
+			Protocol:     key.protocol,
+			Port:         key.port,
+			TrafficStats: *flowStats,
+		})
+	}
+	flows = capFlows(flows)
+	sort.Slice(flows, func(i, j int) bool {
+		return flowLess(&flows[i], &flows[j])
+	})
+	return flows
+}
+
+// namespacedPeer returns the peer "<Namespace>/<name>" of a Pod of the given Namespace, or redactedPeer if the peer
+// belongs to another Namespace.
+func namespacedPeer(namespace, peer string) string {
+	if !strings.HasPrefix(peer, namespace+"/") {
+		return redactedPeer
+	}
+	return peer
+}
+
+// ipPeer returns the subnet of the IP peer.
+func ipPeer(ip net.IP) string {
+	if ip.To4() != nil {
+		return (&net.IPNet{IP: ip.Mask(net.CIDRMask(ipv4PeerPrefixLen, 32)), Mask: net.CIDRMask(ipv4PeerPrefixLen, 32)}).String()
 	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(ipv6PeerPrefixLen, 128)), Mask: net.CIDRMask(ipv6PeerPrefixLen, 128)}).String()
+}
+
+// capFlows keeps the maxFlowsPerPod-1 FlowSummaries with the most bytes of each Pod in each direction, and
+// aggregates the other ones into a single FlowSummary whose peer is otherPeers.
+func capFlows(flows []statsv1alpha1.FlowSummary) []statsv1alpha1.FlowSummary {
+	type podDirection struct {
+		namespace string
+		pod       string
+		direction statsv1alpha1.RuleDirection
+	}
+	podFlows := map[podDirection][]statsv1alpha1.FlowSummary{}
+	for _, flow := range flows {
+		key := podDirection{namespace: flow.Namespace, pod: flow.Pod, direction: flow.Direction}
+		podFlows[key] = append(podFlows[key], flow)
+	}
+	capped := make([]statsv1alpha1.FlowSummary, 0, len(flows))
+	for key, pf := range podFlows {
+		if len(pf) <= maxFlowsPerPod {
+			capped = append(capped, pf...)
+			continue
+		}
+		sort.Slice(pf, func(i, j int) bool {
+			if pf[i].TrafficStats.Bytes != pf[j].TrafficStats.Bytes {
+				return pf[i].TrafficStats.Bytes > pf[j].TrafficStats.Bytes
+			}
+			return flowLess(&pf[i], &pf[j])
+		})
+		capped = append(capped, pf[:maxFlowsPerPod-1]...)
+		others := statsv1alpha1.FlowSummary{
+			Namespace: key.namespace,
+			Pod:       key.pod,
+			Direction: key.direction,
+			Peer:      otherPeers,
+		}
+		for _, flow := range pf[maxFlowsPerPod-1:] {
+			others.TrafficStats.Sessions += flow.TrafficStats.Sessions
+			others.TrafficStats.Packets += flow.TrafficStats.Packets
+			others.TrafficStats.Bytes += flow.TrafficStats.Bytes
+		}
+		capped = append(capped, others)
+	}
+	return capped
+}
+
+// flowLess orders FlowSummaries by their Pods, directions, peers, protocols and ports.
+func flowLess(a, b *statsv1alpha1.FlowSummary) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	if a.Pod != b.Pod {
+		return a.Pod < b.Pod
+	}
+	if a.Direction != b.Direction {
+		return a.Direction < b.Direction
+	}
+	if a.Peer != b.Peer {
+		return a.Peer < b.Peer
+	}
+	if a.Protocol != b.Protocol {
+		return a.Protocol < b.Protocol
+	}
+	return a.Port < b.Port
+}
+
+// protocolName returns the name of the IP protocol, or its number if it has no well-known name.
+func protocolName(protocol uint8) string {
+	switch protocol {
+	case 1:
+		return "ICMP"
+	case 6:
+		return "TCP"
+	case 17:
+		return "UDP"
+	case 58:
+		return "ICMPv6"
+	case 132:
+		return "SCTP"
+	}
+	return strconv.Itoa(int(protocol))
+}
+
+func addRuleMetric(stats *statsv1alpha1.TrafficStats, metric *agenttypes.RuleMetric) {
+	stats.Bytes += int64(metric.Bytes)
+	stats.Sessions += int64(metric.Sessions)
+	stats.Packets += int64(metric.Packets)
 }
 
 // report calculates the delta of the stats and pushes it to the antrea-controller summary API.
@@ -146,7 +406,16 @@ func (m *Collector) report(curStatsCollection *statsCollection) error {
 	npStats := calculateDiff(curStatsCollection.networkPolicyStats, m.lastStatsCollection.networkPolicyStats)
 	acnpStats := calculateDiff(curStatsCollection.antreaClusterNetworkPolicyStats, m.lastStatsCollection.antreaClusterNetworkPolicyStats)
 	anpStats := calculateDiff(curStatsCollection.antreaNetworkPolicyStats, m.lastStatsCollection.antreaNetworkPolicyStats)
-	if len(npStats) == 0 && len(acnpStats) == 0 && len(anpStats) == 0 {
+	ruleStats := calculateRuleDiff(curStatsCollection.antreaPolicyRuleStats, m.lastStatsCollection.antreaPolicyRuleStats)
+	for _, policyStatsList := range [][]cpv1beta1.NetworkPolicyStats{acnpStats, anpStats} {
+		for i := range policyStatsList {
+			policyStatsList[i].RuleTrafficStats = ruleStats[policyStatsList[i].NetworkPolicy.UID]
+		}
+	}
+	// The summaries of the connections are reported until they become empty, so that the antrea-controller doesn't
+	// keep the stale ones.
+	if len(npStats) == 0 && len(acnpStats) == 0 && len(anpStats) == 0 &&
+		len(curStatsCollection.flows) == 0 && len(m.lastStatsCollection.flows) == 0 {
 		klog.V(4).Info("No stats to report, skip reporting")
 		return nil
 	}
@@ -158,6 +427,7 @@ func (m *Collector) report(curStatsCollection *statsCollection) error {
 		NetworkPolicies:              npStats,
 		AntreaClusterNetworkPolicies: acnpStats,
 		AntreaNetworkPolicies:        anpStats,
+		Flows:                        curStatsCollection.flows,
 	}
 	klog.V(6).Infof("Reporting NodeStatsSummary: %v", summary)
 
@@ -178,21 +448,7 @@ func calculateDiff(curStatsMap, lastStatsMap map[types.UID]*statsv1alpha1.Traffi
 	}
 	statsList := make([]cpv1beta1.NetworkPolicyStats, 0, len(curStatsMap))
 	for uid, curStats := range curStatsMap {
-		var stats *statsv1alpha1.TrafficStats
-		lastStats, exists := lastStatsMap[uid]
-		// curStats.Bytes < lastStats.Bytes could happen if one of the following conditions happens:
-		// 1. OVS is restarted and Openflow entries are reinstalled.
-		// 2. The NetworkPolicy is removed and recreated in-between two collection.
-		// In these cases, curStats is the delta it should report.
-		if !exists || curStats.Bytes < lastStats.Bytes {
-			stats = curStats
-		} else {
-			stats = &statsv1alpha1.TrafficStats{
-				Packets:  curStats.Packets - lastStats.Packets,
-				Sessions: curStats.Sessions - lastStats.Sessions,
-				Bytes:    curStats.Bytes - lastStats.Bytes,
-			}
-		}
+		stats := diffTrafficStats(curStats, lastStatsMap[uid])
 		// If the statistics of the NetworkPolicy remain unchanged, no need to report it.
 		if stats.Bytes == 0 {
 			continue
@@ -205,3 +461,37 @@ func calculateDiff(curStatsMap, lastStatsMap map[types.UID]*statsv1alpha1.Traffi
 	}
 	return statsList
 }
+
+// calculateRuleDiff returns a mapping from the UIDs of Antrea-native policies to the delta of the stats of their
+// rules. The rules whose stats remain unchanged are omitted.
+func calculateRuleDiff(curStatsMap, lastStatsMap map[ruleKey]*statsv1alpha1.TrafficStats) map[types.UID][]statsv1alpha1.RuleTrafficStats {
+	statsMap := map[types.UID][]statsv1alpha1.RuleTrafficStats{}
+	for key, curStats := range curStatsMap {
+		stats := diffTrafficStats(curStats, lastStatsMap[key])
+		if stats.Bytes == 0 {
+			continue
+		}
+		statsMap[key.policyUID] = append(statsMap[key.policyUID], statsv1alpha1.RuleTrafficStats{
+			Direction:    key.direction,
+			Index:        key.index,
+			TrafficStats: *stats,
+		})
+	}
+	return statsMap
+}
+
+// diffTrafficStats returns the delta between curStats and lastStats, which can be nil.
+func diffTrafficStats(curStats, lastStats *statsv1alpha1.TrafficStats) *statsv1alpha1.TrafficStats {
+	// curStats.Bytes < lastStats.Bytes could happen if one of the following conditions happens:
+	// 1. OVS is restarted and Openflow entries are reinstalled.
+	// 2. The NetworkPolicy is removed and recreated in-between two collection.
+	// In these cases, curStats is the delta it should report.
+	if lastStats == nil || curStats.Bytes < lastStats.Bytes {
+		return curStats
+	}
+	return &statsv1alpha1.TrafficStats{
+		Packets:  curStats.Packets - lastStats.Packets,
+		Sessions: curStats.Sessions - lastStats.Sessions,
+		Bytes:    curStats.Bytes - lastStats.Bytes,
+	}
+}
@@ -15,12 +15,16 @@
 package stats
 
 import (
+	"fmt"
+	"net"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/vmware-tanzu/antrea/pkg/agent/flowexporter"
 	oftest "github.com/vmware-tanzu/antrea/pkg/agent/openflow/testing"
 	agenttypes "github.com/vmware-tanzu/antrea/pkg/agent/types"
 	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
@@ -62,6 +66,7 @@ func TestCollect(t *testing.T) {
 		name                    string
 		ruleStats               map[uint32]*agenttypes.RuleMetric
 		ofIDToPolicyMap         map[uint32]*cpv1beta1.NetworkPolicyReference
+		ofIDToRuleMap           map[uint32]ruleKey
 		expectedStatsCollection *statsCollection
 	}{
 		{
@@ -103,6 +108,7 @@ func TestCollect(t *testing.T) {
 				},
 				antreaClusterNetworkPolicyStats: map[types.UID]*statsv1alpha1.TrafficStats{},
 				antreaNetworkPolicyStats:        map[types.UID]*statsv1alpha1.TrafficStats{},
+				antreaPolicyRuleStats:           map[ruleKey]*statsv1alpha1.TrafficStats{},
 			},
 		},
 		{
@@ -129,6 +135,10 @@ func TestCollect(t *testing.T) {
 				2: &acnp1,
 				3: &anp1,
 			},
+			ofIDToRuleMap: map[uint32]ruleKey{
+				2: {direction: statsv1alpha1.RuleDirectionIngress, index: 0},
+				3: {direction: statsv1alpha1.RuleDirectionEgress, index: 1},
+			},
 			expectedStatsCollection: &statsCollection{
 				networkPolicyStats: map[types.UID]*statsv1alpha1.TrafficStats{
 					np1.UID: {
@@ -151,6 +161,71 @@ func TestCollect(t *testing.T) {
 						Sessions: 3,
 					},
 				},
+				antreaPolicyRuleStats: map[ruleKey]*statsv1alpha1.TrafficStats{
+					{acnp1.UID, statsv1alpha1.RuleDirectionIngress, 0}: {
+						Bytes:    15,
+						Packets:  2,
+						Sessions: 1,
+					},
+					{anp1.UID, statsv1alpha1.RuleDirectionEgress, 1}: {
+						Bytes:    30,
+						Packets:  5,
+						Sessions: 3,
+					},
+				},
+			},
+		},
+		{
+			name: "multiple conjunctions per rule",
+			ruleStats: map[uint32]*agenttypes.RuleMetric{
+				1: {
+					Bytes:    10,
+					Packets:  1,
+					Sessions: 1,
+				},
+				2: {
+					Bytes:    15,
+					Packets:  2,
+					Sessions: 1,
+				},
+				3: {
+					Bytes:    30,
+					Packets:  5,
+					Sessions: 3,
+				},
+			},
+			ofIDToPolicyMap: map[uint32]*cpv1beta1.NetworkPolicyReference{
+				1: &acnp1,
+				2: &acnp1,
+				3: &acnp1,
+			},
+			ofIDToRuleMap: map[uint32]ruleKey{
+				1: {direction: statsv1alpha1.RuleDirectionIngress, index: 0},
+				2: {direction: statsv1alpha1.RuleDirectionIngress, index: 0},
+				3: {direction: statsv1alpha1.RuleDirectionIngress, index: 1},
+			},
+			expectedStatsCollection: &statsCollection{
+				networkPolicyStats: map[types.UID]*statsv1alpha1.TrafficStats{},
+				antreaClusterNetworkPolicyStats: map[types.UID]*statsv1alpha1.TrafficStats{
+					acnp1.UID: {
+						Bytes:    55,
+						Packets:  8,
+						Sessions: 5,
+					},
+				},
+				antreaNetworkPolicyStats: map[types.UID]*statsv1alpha1.TrafficStats{},
+				antreaPolicyRuleStats: map[ruleKey]*statsv1alpha1.TrafficStats{
+					{acnp1.UID, statsv1alpha1.RuleDirectionIngress, 0}: {
+						Bytes:    25,
+						Packets:  3,
+						Sessions: 2,
+					},
+					{acnp1.UID, statsv1alpha1.RuleDirectionIngress, 1}: {
+						Bytes:    30,
+						Packets:  5,
+						Sessions: 3,
+					},
+				},
 			},
 		},
 		{
@@ -181,6 +256,7 @@ func TestCollect(t *testing.T) {
 				},
 				antreaClusterNetworkPolicyStats: map[types.UID]*statsv1alpha1.TrafficStats{},
 				antreaNetworkPolicyStats:        map[types.UID]*statsv1alpha1.TrafficStats{},
+				antreaPolicyRuleStats:           map[ruleKey]*statsv1alpha1.TrafficStats{},
 			},
 		},
 	}
@@ -191,6 +267,13 @@ func TestCollect(t *testing.T) {
 			for ofID, policy := range tt.ofIDToPolicyMap {
 				ofClient.EXPECT().GetPolicyFromConjunction(ofID).Return(policy)
 			}
+			for ofID, rule := range tt.ofIDToRuleMap {
+				direction := cpv1beta1.DirectionIn
+				if rule.direction == statsv1alpha1.RuleDirectionEgress {
+					direction = cpv1beta1.DirectionOut
+				}
+				ofClient.EXPECT().GetRuleFromConjunction(ofID).Return(direction, rule.index, true)
+			}
 
 			m := &Collector{ofClient: ofClient}
 			actualPolicyStats := m.collect()
@@ -327,3 +410,222 @@ func TestCalculateDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateRuleDiff(t *testing.T) {
+	lastStats := map[ruleKey]*statsv1alpha1.TrafficStats{
+		{"uid1", statsv1alpha1.RuleDirectionIngress, 0}: {
+			Bytes:    10,
+			Packets:  1,
+			Sessions: 1,
+		},
+		{"uid1", statsv1alpha1.RuleDirectionEgress, 0}: {
+			Bytes:    20,
+			Packets:  2,
+			Sessions: 1,
+		},
+	}
+	curStats := map[ruleKey]*statsv1alpha1.TrafficStats{
+		{"uid1", statsv1alpha1.RuleDirectionIngress, 0}: {
+			Bytes:    25,
+			Packets:  3,
+			Sessions: 2,
+		},
+		{"uid1", statsv1alpha1.RuleDirectionEgress, 0}: {
+			Bytes:    20,
+			Packets:  2,
+			Sessions: 1,
+		},
+		{"uid2", statsv1alpha1.RuleDirectionEgress, 1}: {
+			Bytes:    30,
+			Packets:  5,
+			Sessions: 3,
+		},
+	}
+	expectedStats := map[types.UID][]statsv1alpha1.RuleTrafficStats{
+		"uid1": {
+			{
+				Direction: statsv1alpha1.RuleDirectionIngress,
+				Index:     0,
+				TrafficStats: statsv1alpha1.TrafficStats{
+					Bytes:    15,
+					Packets:  2,
+					Sessions: 1,
+				},
+			},
+		},
+		"uid2": {
+			{
+				Direction: statsv1alpha1.RuleDirectionEgress,
+				Index:     1,
+				TrafficStats: statsv1alpha1.TrafficStats{
+					Bytes:    30,
+					Packets:  5,
+					Sessions: 3,
+				},
+			},
+		},
+	}
+	assert.Equal(t, expectedStats, calculateRuleDiff(curStats, lastStats))
+}
+
+type fakeConnectionStore struct {
+	conns []flowexporter.Connection
+}
+
+func (s *fakeConnectionStore) ForAllConnectionsDo(callback flowexporter.ConnectionMapCallBack) error {
+	for _, conn := range s.conns {
+		if err := callback(flowexporter.ConnectionKey{}, conn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCollectFlows(t *testing.T) {
+	tuple := func(src, dst string, protocol uint8, srcPort, dstPort uint16) flowexporter.Tuple {
+		return flowexporter.Tuple{
+			SourceAddress:      net.ParseIP(src),
+			DestinationAddress: net.ParseIP(dst),
+			Protocol:           protocol,
+			SourcePort:         srcPort,
+			DestinationPort:    dstPort,
+		}
+	}
+	conns := []flowexporter.Connection{
+		// Two connections between local Pods, through a Service.
+		{
+			IsActive:                   true,
+			TupleOrig:                  tuple("10.10.0.2", "10.96.0.10", 6, 40000, 80),
+			TupleReply:                 tuple("10.10.0.3", "10.10.0.2", 6, 8080, 40000),
+			OriginalPackets:            2,
+			OriginalBytes:              20,
+			ReversePackets:             1,
+			ReverseBytes:               10,
+			SourcePodNamespace:         "foo",
+			SourcePodName:              "client",
+			DestinationPodNamespace:    "bar",
+			DestinationPodName:         "server",
+			DestinationServicePortName: "bar/svc:http",
+		},
+		{
+			IsActive:                   true,
+			TupleOrig:                  tuple("10.10.0.2", "10.96.0.10", 6, 40001, 80),
+			TupleReply:                 tuple("10.10.0.3", "10.10.0.2", 6, 8080, 40001),
+			OriginalPackets:            2,
+			OriginalBytes:              20,
+			SourcePodNamespace:         "foo",
+			SourcePodName:              "client",
+			DestinationPodNamespace:    "bar",
+			DestinationPodName:         "server",
+			DestinationServicePortName: "bar/svc:http",
+		},
+		// A connection from a remote IP to a local Pod.
+		{
+			IsActive:                true,
+			TupleOrig:               tuple("10.10.1.2", "10.10.0.3", 17, 5000, 53),
+			TupleReply:              tuple("10.10.0.3", "10.10.1.2", 17, 53, 5000),
+			OriginalPackets:         1,
+			OriginalBytes:           100,
+			DestinationPodNamespace: "bar",
+			DestinationPodName:      "server",
+		},
+		// A connection between local Pods of the same Namespace.
+		{
+			IsActive:                true,
+			TupleOrig:               tuple("10.10.0.2", "10.10.0.4", 6, 40003, 5432),
+			TupleReply:              tuple("10.10.0.4", "10.10.0.2", 6, 5432, 40003),
+			OriginalPackets:         1,
+			OriginalBytes:           10,
+			SourcePodNamespace:      "foo",
+			SourcePodName:           "client",
+			DestinationPodNamespace: "foo",
+			DestinationPodName:      "db",
+		},
+		// An inactive connection is not summarized.
+		{
+			TupleOrig:          tuple("10.10.0.2", "8.8.8.8", 6, 40002, 443),
+			TupleReply:         tuple("8.8.8.8", "10.10.0.2", 6, 443, 40002),
+			SourcePodNamespace: "foo",
+			SourcePodName:      "client",
+		},
+	}
+	m := &Collector{connStore: &fakeConnectionStore{conns: conns}}
+	expectedFlows := []statsv1alpha1.FlowSummary{
+		{
+			Namespace:    "bar",
+			Pod:          "server",
+			Direction:    statsv1alpha1.RuleDirectionIngress,
+			Peer:         "10.10.1.0/24",
+			Protocol:     "UDP",
+			Port:         53,
+			TrafficStats: statsv1alpha1.TrafficStats{Sessions: 1, Packets: 1, Bytes: 100},
+		},
+		{
+			Namespace:    "bar",
+			Pod:          "server",
+			Direction:    statsv1alpha1.RuleDirectionIngress,
+			Peer:         redactedPeer,
+			Protocol:     "TCP",
+			Port:         8080,
+			TrafficStats: statsv1alpha1.TrafficStats{Sessions: 2, Packets: 5, Bytes: 50},
+		},
+		{
+			Namespace:    "foo",
+			Pod:          "client",
+			Direction:    statsv1alpha1.RuleDirectionEgress,
+			Peer:         redactedPeer,
+			Protocol:     "TCP",
+			Port:         80,
+			TrafficStats: statsv1alpha1.TrafficStats{Sessions: 2, Packets: 5, Bytes: 50},
+		},
+		{
+			Namespace:    "foo",
+			Pod:          "client",
+			Direction:    statsv1alpha1.RuleDirectionEgress,
+			Peer:         "foo/db",
+			Protocol:     "TCP",
+			Port:         5432,
+			TrafficStats: statsv1alpha1.TrafficStats{Sessions: 1, Packets: 1, Bytes: 10},
+		},
+		{
+			Namespace:    "foo",
+			Pod:          "db",
+			Direction:    statsv1alpha1.RuleDirectionIngress,
+			Peer:         "foo/client",
+			Protocol:     "TCP",
+			Port:         5432,
+			TrafficStats: statsv1alpha1.TrafficStats{Sessions: 1, Packets: 1, Bytes: 10},
+		},
+	}
+	assert.Equal(t, expectedFlows, m.collectFlows())
+	assert.Nil(t, (&Collector{}).collectFlows())
+}
+
+func TestCapFlows(t *testing.T) {
+	var flows []statsv1alpha1.FlowSummary
+	for i := 0; i < maxFlowsPerPod+2; i++ {
+		flows = append(flows, statsv1alpha1.FlowSummary{
+			Namespace:    "foo",
+			Pod:          "client",
+			Direction:    statsv1alpha1.RuleDirectionEgress,
+			Peer:         fmt.Sprintf("10.0.%d.0/24", i),
+			Protocol:     "TCP",
+			Port:         80,
+			TrafficStats: statsv1alpha1.TrafficStats{Sessions: 1, Packets: 1, Bytes: int64(100 + i)},
+		})
+	}
+	ingress := statsv1alpha1.FlowSummary{Namespace: "foo", Pod: "client", Direction: statsv1alpha1.RuleDirectionIngress, Peer: "10.1.0.0/24"}
+	capped := capFlows(append(flows, ingress))
+	require.Len(t, capped, maxFlowsPerPod+1)
+	assert.Contains(t, capped, ingress)
+	// The 3 flows with the least bytes are aggregated.
+	assert.Contains(t, capped, statsv1alpha1.FlowSummary{
+		Namespace:    "foo",
+		Pod:          "client",
+		Direction:    statsv1alpha1.RuleDirectionEgress,
+		Peer:         otherPeers,
+		TrafficStats: statsv1alpha1.TrafficStats{Sessions: 3, Packets: 3, Bytes: 303},
+	})
+	assert.NotContains(t, capped, flows[0])
+	assert.Contains(t, capped, flows[3])
+}
@@ -43,15 +43,19 @@ type Address interface {
 
 // PolicyRule groups configurations to set up conjunctive match for egress/ingress policy rules.
 type PolicyRule struct {
-	Direction v1beta1.Direction
-	From      []Address
-	To        []Address
-	Service   []v1beta1.Service
-	Action    *secv1alpha1.RuleAction
-	Priority  *uint16
-	FlowID    uint32
-	TableID   binding.TableIDType
-	PolicyRef *v1beta1.NetworkPolicyReference
+	Direction     v1beta1.Direction
+	From          []Address
+	To            []Address
+	Service       []v1beta1.Service
+	Action        *secv1alpha1.RuleAction
+	Priority      *uint16
+	FlowID        uint32
+	TableID       binding.TableIDType
+	PolicyRef     *v1beta1.NetworkPolicyReference
+	RulePriority  int32
+	EnableLogging bool
+	// RateLimit limits the rate of the new connections matched by the rule.
+	RateLimit *v1beta1.RuleRateLimit
 }
 
 // IsAntreaNetworkPolicyRule returns if a PolicyRule is created for Antrea NetworkPolicy types.
@@ -65,11 +69,17 @@ type Priority struct {
 	TierPriority   int32
 	PolicyPriority float64
 	RulePriority   int32
+	// Except is set for the Priority of the flows dropping the except
+	// addresses of a rule, which is right above the Priority of the rule.
+	Except bool
 }
 
 func (p *Priority) Less(p2 Priority) bool {
 	if p.TierPriority == p2.TierPriority {
 		if p.PolicyPriority == p2.PolicyPriority {
+			if p.RulePriority == p2.RulePriority {
+				return !p.Except && p2.Except
+			}
 			return p.RulePriority > p2.RulePriority
 		}
 		return p.PolicyPriority > p2.PolicyPriority
@@ -78,7 +88,8 @@ func (p *Priority) Less(p2 Priority) bool {
 }
 
 func (p *Priority) Equals(p2 Priority) bool {
-	return p.TierPriority == p2.TierPriority && p.PolicyPriority == p2.PolicyPriority && p.RulePriority == p2.RulePriority
+	return p.TierPriority == p2.TierPriority && p.PolicyPriority == p2.PolicyPriority && p.RulePriority == p2.RulePriority &&
+		p.Except == p2.Except
 }
 
 // InSamePriorityZone returns true if two Priorities are of the same Tier and same priority at policy level.
@@ -87,10 +98,14 @@ func (p *Priority) InSamePriorityZone(p2 Priority) bool {
 }
 
 // IsConsecutive returns true if two Priorties are immediately next to each other.
+// The except Priority of a rule is consecutive to the Priority of the rule.
 func (p *Priority) IsConsecutive(p2 Priority) bool {
 	if !p.InSamePriorityZone(p2) {
 		return false
 	}
+	if p.RulePriority == p2.RulePriority {
+		return p.Except != p2.Except
+	}
 	return p.RulePriority-p2.RulePriority == 1 || p2.RulePriority-p.RulePriority == 1
 }
 
@@ -0,0 +1,188 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package uninstall removes the state installed by antrea-agent on a Node,
+// e.g. before the Node is decommissioned or migrated to another CNI.
+package uninstall
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// antreaPrefix is the prefix of the names of the iptables chains and
+	// the ipsets created by antrea-agent.
+	antreaPrefix = "ANTREA-"
+	// antreaPodIPSet contains the Pod CIDRs of all the Nodes, see the
+	// route package.
+	antreaPodIPSet = "ANTREA-POD-IP"
+	// ipamDataDir is the directory in which the host-local IPAM plugin
+	// stores the IPs allocated to the Pods of the "antrea" network. It is
+	// mounted at the same path in the antrea-agent container.
+	ipamDataDir = "/var/lib/cni/networks/antrea"
+	// agentProcessName is the name of the antrea-agent process.
+	agentProcessName = "antrea-agent"
+	// agentDialTimeout is the timeout of the connection to the antrea-agent
+	// API server.
+	agentDialTimeout = time.Second
+)
+
+// procDir is the directory of the process information, which may be changed
+// in tests.
+var procDir = "/proc"
+
+// cniFiles are the files installed on the host by the install_cni scripts,
+// relative to the host path prefix.
+var cniFiles = []string{
+	"/etc/cni/net.d/10-antrea.conflist",
+	"/etc/cni/net.d/10-antrea.conf",
+	"/opt/cni/bin/antrea",
+}
+
+// Options are the options of Run.
+type Options struct {
+	// OVSBridge is the name of the OVS bridge created by antrea-agent.
+	OVSBridge string
+	// OVSRunDir is the directory of the OVSDB socket.
+	OVSRunDir string
+	// HostPathPrefix is the path at which the host filesystem is mounted,
+	// e.g. "/host" in the antrea-agent container.
+	HostPathPrefix string
+	// AgentAPIPort is the port of the antrea-agent API server, used to
+	// check whether antrea-agent is running on the Node.
+	AgentAPIPort int
+	// DryRun only reports the state which would be removed.
+	DryRun bool
+}
+
+// reporter prints the state removed from the Node.
+type reporter struct {
+	out    io.Writer
+	dryRun bool
+}
+
+func (r *reporter) report(format string, args ...interface{}) {
+	verb := "Removing"
+	if r.dryRun {
+		verb = "Would remove"
+	}
+	fmt.Fprintf(r.out, "%s %s\n", verb, fmt.Sprintf(format, args...))
+}
+
+// checkAgentStopped returns an error if antrea-agent is running on the Node, as
+// it would install its state and its OVS flows again. The process is looked up
+// when antctl runs in the same PID namespace as antrea-agent, and the API
+// server of antrea-agent is looked up otherwise.
+func (o *Options) checkAgentStopped() error {
+	entries, err := ioutil.ReadDir(procDir)
+	if err != nil {
+		return fmt.Errorf("error listing processes: %v", err)
+	}
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		// The process may have exited since the directory was listed.
+		comm, err := ioutil.ReadFile(filepath.Join(procDir, entry.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(comm)) == agentProcessName {
+			return fmt.Errorf("%s is running with PID %s, it must be stopped first", agentProcessName, entry.Name())
+		}
+	}
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(o.AgentAPIPort))
+	if conn, err := net.DialTimeout("tcp", addr, agentDialTimeout); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is running with its API server listening on %s, it must be stopped first", agentProcessName, addr)
+	}
+	return nil
+}
+
+func (o *Options) hostPaths() []string {
+	paths := make([]string, 0, len(cniFiles)+1)
+	for _, file := range cniFiles {
+		paths = append(paths, filepath.Join(o.HostPathPrefix, file))
+	}
+	return append(paths, ipamDataDir)
+}
+
+// iptablesCleanupData parses the output of iptables-save and returns the
+// iptables-restore input which deletes the Antrea chains and the rules jumping
+// to them from the other chains, along with a description of each of them. It
+// must be restored with --noflush so that the other chains are kept.
+func iptablesCleanupData(saveData []byte) ([]byte, []string) {
+	var data bytes.Buffer
+	var removed []string
+	var table string
+	var chains, rules []string
+	writeTable := func() {
+		if len(chains) == 0 && len(rules) == 0 {
+			return
+		}
+		fmt.Fprintf(&data, "*%s\n", table)
+		// Declaring the chains flushes them, so that they are no longer
+		// referenced when they are deleted.
+		for _, chain := range chains {
+			fmt.Fprintf(&data, ":%s - [0:0]\n", chain)
+		}
+		for _, rule := range rules {
+			fmt.Fprintf(&data, "-D %s\n", rule)
+			removed = append(removed, fmt.Sprintf("iptables rule in table %s: -A %s", table, rule))
+		}
+		for _, chain := range chains {
+			fmt.Fprintf(&data, "-X %s\n", chain)
+			removed = append(removed, fmt.Sprintf("iptables chain %s in table %s", chain, table))
+		}
+		data.WriteString("COMMIT\n")
+	}
+	for _, line := range strings.Split(string(saveData), "\n") {
+		// The counters are dumped before the rules with "iptables-save -c".
+		if strings.HasPrefix(line, "[") {
+			if i := strings.Index(line, "] "); i >= 0 {
+				line = line[i+2:]
+			}
+		}
+		switch {
+		case strings.HasPrefix(line, "*"):
+			table = line[1:]
+			chains, rules = nil, nil
+		case line == "COMMIT":
+			writeTable()
+		case strings.HasPrefix(line, ":"+antreaPrefix):
+			chains = append(chains, strings.Fields(line[1:])[0])
+		case strings.HasPrefix(line, "-A "):
+			rule := line[3:]
+			if strings.HasPrefix(rule, antreaPrefix) {
+				continue
+			}
+			fields := strings.Fields(rule)
+			for i := 0; i < len(fields)-1; i++ {
+				if (fields[i] == "-j" || fields[i] == "-g") && strings.HasPrefix(fields[i+1], antreaPrefix) {
+					rules = append(rules, rule)
+					break
+				}
+			}
+		}
+	}
+	return data.Bytes(), removed
+}
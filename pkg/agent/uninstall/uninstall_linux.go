@@ -0,0 +1,202 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uninstall
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/ipset"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/iptables"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+)
+
+// Run removes the state installed by antrea-agent from the Node: the routes to
+// the Pod CIDRs, the iptables chains, the ipsets, the OVS bridge with all its
+// ports, the CNI configuration and binary, and the IPAM store. Each removed
+// object is printed to out. It continues when an object cannot be removed, and
+// returns all the errors at the end. It refuses to remove anything while
+// antrea-agent is running on the Node, as it would install its state again.
+func Run(o *Options, out io.Writer) error {
+	if !o.DryRun {
+		if err := o.checkAgentStopped(); err != nil {
+			return err
+		}
+	}
+	r := &reporter{out: out, dryRun: o.DryRun}
+	var errs []error
+	// The routes are found from the Pod CIDRs in the ipset, so they must be
+	// removed first. The ipsets can only be destroyed once they are no
+	// longer referenced by iptables rules.
+	for _, f := range []func(*reporter) error{
+		removeRoutes,
+		removeIPTables,
+		removeIPSets,
+		o.removeOVSBridge,
+		o.removeHostPaths,
+	} {
+		if err := f(r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// removeRoutes removes the routes to the Pod CIDRs. The routes through the host
+// gateway are also removed with it, but the routes to the remote Pod CIDRs
+// through the transport interface in noEncap mode would remain.
+func removeRoutes(r *reporter) error {
+	sets, err := ipset.ListIPSets()
+	if err != nil {
+		return err
+	}
+	if !contains(sets, antreaPodIPSet) {
+		return nil
+	}
+	entries, err := ipset.ListEntries(antreaPodIPSet)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, entry := range entries {
+		_, cidr, err := net.ParseCIDR(entry)
+		if err != nil {
+			continue
+		}
+		routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Dst: cidr}, netlink.RT_FILTER_DST)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing routes to %s: %v", cidr, err))
+			continue
+		}
+		for i := range routes {
+			r.report("route %s", routes[i].String())
+			if r.dryRun {
+				continue
+			}
+			if err := netlink.RouteDel(&routes[i]); err != nil {
+				errs = append(errs, fmt.Errorf("error deleting route %s: %v", routes[i].String(), err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// removeIPTables removes the Antrea chains and the rules jumping to them in a
+// single iptables-restore call.
+func removeIPTables(r *reporter) error {
+	ipt, err := iptables.New()
+	if err != nil {
+		return fmt.Errorf("error creating IPTables instance: %v", err)
+	}
+	saveData, err := ipt.Save()
+	if err != nil {
+		return fmt.Errorf("error saving iptables: %v", err)
+	}
+	restoreData, removed := iptablesCleanupData(saveData)
+	for _, desc := range removed {
+		r.report(desc)
+	}
+	if r.dryRun || len(removed) == 0 {
+		return nil
+	}
+	return ipt.Restore(restoreData, false)
+}
+
+func removeIPSets(r *reporter) error {
+	sets, err := ipset.ListIPSets()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range sets {
+		if !strings.HasPrefix(name, antreaPrefix) {
+			continue
+		}
+		r.report("ipset %s", name)
+		if r.dryRun {
+			continue
+		}
+		if err := ipset.DestroyIPSet(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// removeOVSBridge deletes the OVS bridge, which deletes its ports, including
+// the host gateway and the tunnel port, and the routes through them.
+func (o *Options) removeOVSBridge(r *reporter) error {
+	ovsdbConnection, err := ovsconfig.NewOVSDBConnectionUDS(ovsconfig.GetConnAddress(o.OVSRunDir))
+	if err != nil {
+		return fmt.Errorf("error connecting OVSDB: %v", err)
+	}
+	defer ovsdbConnection.Close()
+	bridge := ovsconfig.NewOVSBridge(o.OVSBridge, ovsconfig.OVSDatapathSystem, ovsdbConnection)
+	exists, ovsErr := bridge.Exists()
+	if ovsErr != nil {
+		return fmt.Errorf("error looking up OVS bridge %s: %v", o.OVSBridge, ovsErr)
+	}
+	if !exists {
+		return nil
+	}
+	ports, ovsErr := bridge.GetPortList()
+	if ovsErr != nil {
+		return fmt.Errorf("error listing the ports of OVS bridge %s: %v", o.OVSBridge, ovsErr)
+	}
+	portNames := make([]string, 0, len(ports))
+	for _, port := range ports {
+		portNames = append(portNames, port.Name)
+	}
+	r.report("OVS bridge %s with ports [%s]", o.OVSBridge, strings.Join(portNames, ", "))
+	if r.dryRun {
+		return nil
+	}
+	if ovsErr := bridge.Delete(); ovsErr != nil {
+		return fmt.Errorf("error deleting OVS bridge %s: %v", o.OVSBridge, ovsErr)
+	}
+	return nil
+}
+
+func (o *Options) removeHostPaths(r *reporter) error {
+	var errs []error
+	for _, path := range o.hostPaths() {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		r.report("%s", path)
+		if r.dryRun {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func contains(list []string, name string) bool {
+	for _, s := range list {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
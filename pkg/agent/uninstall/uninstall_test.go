@@ -0,0 +1,96 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uninstall
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPTablesCleanupData(t *testing.T) {
+	saveData := `# Generated by iptables-save v1.8.4 on Wed Oct 14 12:00:00 2020
+*mangle
+:PREROUTING ACCEPT [10:1000]
+:ANTREA-MANGLE - [0:0]
+[10:1000] -A PREROUTING -m comment --comment "Antrea: jump to Antrea mangle rules" -j ANTREA-MANGLE
+COMMIT
+*filter
+:INPUT ACCEPT [0:0]
+:FORWARD ACCEPT [0:0]
+:KUBE-FORWARD - [0:0]
+:ANTREA-FORWARD - [0:0]
+[0:0] -A FORWARD -m comment --comment "kubernetes forwarding rules" -j KUBE-FORWARD
+[0:0] -A FORWARD -m comment --comment "Antrea: jump to Antrea forwarding rules" -j ANTREA-FORWARD
+[0:0] -A ANTREA-FORWARD -i antrea-gw0 -m comment --comment "Antrea: accept packets from local pods" -j ACCEPT
+COMMIT
+*raw
+:PREROUTING ACCEPT [0:0]
+COMMIT
+`
+	data, removed := iptablesCleanupData([]byte(saveData))
+	assert.Equal(t, `*mangle
+:ANTREA-MANGLE - [0:0]
+-D PREROUTING -m comment --comment "Antrea: jump to Antrea mangle rules" -j ANTREA-MANGLE
+-X ANTREA-MANGLE
+COMMIT
+*filter
+:ANTREA-FORWARD - [0:0]
+-D FORWARD -m comment --comment "Antrea: jump to Antrea forwarding rules" -j ANTREA-FORWARD
+-X ANTREA-FORWARD
+COMMIT
+`, string(data))
+	assert.Equal(t, []string{
+		`iptables rule in table mangle: -A PREROUTING -m comment --comment "Antrea: jump to Antrea mangle rules" -j ANTREA-MANGLE`,
+		"iptables chain ANTREA-MANGLE in table mangle",
+		`iptables rule in table filter: -A FORWARD -m comment --comment "Antrea: jump to Antrea forwarding rules" -j ANTREA-FORWARD`,
+		"iptables chain ANTREA-FORWARD in table filter",
+	}, removed)
+
+	data, removed = iptablesCleanupData([]byte("*nat\n:POSTROUTING ACCEPT [0:0]\nCOMMIT\n"))
+	assert.Empty(t, data)
+	assert.Empty(t, removed)
+}
+
+func TestCheckAgentStopped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(dir string) { procDir = dir }(procDir)
+	procDir = dir
+	writeProcess := func(pid, name string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, pid), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, pid, "comm"), []byte(name+"\n"), 0644))
+	}
+	writeProcess("1", "systemd")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "self"), 0755))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	o := &Options{AgentAPIPort: port}
+	assert.Error(t, o.checkAgentStopped(), "The agent API server is listening")
+
+	require.NoError(t, listener.Close())
+	assert.NoError(t, o.checkAgentStopped())
+
+	writeProcess("100", agentProcessName)
+	assert.Error(t, o.checkAgentStopped(), "The agent process is running")
+}
@@ -27,6 +27,8 @@ const (
 	// The hash:net set type uses a hash to store different sized IP network addresses.
 	// The lookup time grows linearly with the number of the different prefix values added to the set.
 	HashNet SetType = "hash:net"
+	// The hash:ip,port set type uses a hash to store IP address and protocol-port pairs, e.g. "10.0.0.1,tcp:80".
+	HashIPPort SetType = "hash:ip,port"
 )
 
 // memberPattern is used to match the members part of ipset list result.
@@ -41,6 +43,24 @@ func CreateIPSet(name string, setType SetType) error {
 	return nil
 }
 
+// DestroyIPSet destroys the set, it will ignore error when the set doesn't exist.
+func DestroyIPSet(name string) error {
+	output, err := exec.Command("ipset", "destroy", name).CombinedOutput()
+	if err != nil && !strings.Contains(string(output), "does not exist") {
+		return fmt.Errorf("error destroying ipset %s: %v", name, err)
+	}
+	return nil
+}
+
+// ListIPSets lists the names of all the sets.
+func ListIPSets() ([]string, error) {
+	output, err := exec.Command("ipset", "list", "-n").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing ipsets: %v", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
 // AddEntry adds a new entry to the set, it will ignore error when the entry already exists.
 func AddEntry(name string, entry string) error {
 	cmd := exec.Command("ipset", "add", name, entry, "-exist")
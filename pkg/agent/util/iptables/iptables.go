@@ -33,12 +33,19 @@ const (
 	RawTable    = "raw"
 
 	AcceptTarget     = "ACCEPT"
+	DropTarget       = "DROP"
+	ReturnTarget     = "RETURN"
 	MasqueradeTarget = "MASQUERADE"
 	MarkTarget       = "MARK"
 	ConnTrackTarget  = "CT"
+	DNATTarget       = "DNAT"
+	SNATTarget       = "SNAT"
+	NoTrackTarget    = "NOTRACK"
 
 	PreRoutingChain  = "PREROUTING"
+	InputChain       = "INPUT"
 	ForwardChain     = "FORWARD"
+	OutputChain      = "OUTPUT"
 	PostRoutingChain = "POSTROUTING"
 
 	waitSeconds              = 10
@@ -101,6 +108,68 @@ func (c *Client) EnsureRule(table string, chain string, ruleSpec []string) error
 	return nil
 }
 
+// InsertRule checks if target rule already exists, inserts it at the top of
+// the chain if not.
+func (c *Client) InsertRule(table string, chain string, ruleSpec []string) error {
+	exist, err := c.ipt.Exists(table, chain, ruleSpec...)
+	if err != nil {
+		return fmt.Errorf("error checking if rule %v exists in table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	if exist {
+		return nil
+	}
+	if err := c.ipt.Insert(table, chain, 1, ruleSpec...); err != nil {
+		return fmt.Errorf("error inserting rule %v to table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	klog.V(2).Infof("Inserted rule %v to table %s chain %s", ruleSpec, table, chain)
+	return nil
+}
+
+// InsertRuleAtTop ensures the target rule is the first rule of the chain.
+// Unlike InsertRule, the rule is moved to the top if it already exists at
+// another position, e.g. after other components inserted rules before it.
+func (c *Client) InsertRuleAtTop(table string, chain string, ruleSpec []string) error {
+	exist, err := c.ipt.Exists(table, chain, ruleSpec...)
+	if err != nil {
+		return fmt.Errorf("error checking if rule %v exists in table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	if exist {
+		if err := c.ipt.Delete(table, chain, ruleSpec...); err != nil {
+			return fmt.Errorf("error deleting rule %v from table %s chain %s: %v", ruleSpec, table, chain, err)
+		}
+	}
+	if err := c.ipt.Insert(table, chain, 1, ruleSpec...); err != nil {
+		return fmt.Errorf("error inserting rule %v to table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	klog.V(2).Infof("Inserted rule %v at the top of table %s chain %s", ruleSpec, table, chain)
+	return nil
+}
+
+// DeleteRule checks if target rule already exists, deletes the rule if found.
+func (c *Client) DeleteRule(table string, chain string, ruleSpec []string) error {
+	exist, err := c.ipt.Exists(table, chain, ruleSpec...)
+	if err != nil {
+		return fmt.Errorf("error checking if rule %v exists in table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	if !exist {
+		return nil
+	}
+	if err := c.ipt.Delete(table, chain, ruleSpec...); err != nil {
+		return fmt.Errorf("error deleting rule %v from table %s chain %s: %v", ruleSpec, table, chain, err)
+	}
+	klog.V(2).Infof("Deleted rule %v from table %s chain %s", ruleSpec, table, chain)
+	return nil
+}
+
+// ChainExists checks if target chain exists in the table.
+func (c *Client) ChainExists(table string, chain string) (bool, error) {
+	chains, err := c.ipt.ListChains(table)
+	if err != nil {
+		return false, fmt.Errorf("error listing existing chains in table %s: %v", table, err)
+	}
+	return contains(chains, chain), nil
+}
+
 // Restore calls iptable-restore to restore iptables with the provided content.
 // If flush is true, all previous contents of the respective tables will be flushed.
 // Otherwise only involved chains will be flushed.
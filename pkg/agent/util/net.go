@@ -43,6 +43,19 @@ func generateInterfaceName(key string, name string, useHead bool) string {
 	return fmt.Sprintf("%s-%s", prefix, interfaceKey[:interfaceKeyLength])
 }
 
+// GenerateStableMAC generates a unicast, locally administered MAC address by
+// hashing the provided key. The output is deterministic, which lets a MAC
+// address be re-generated identically when its persisted value is lost, e.g.
+// when the OVSDB file does not survive a Node reboot.
+func GenerateStableMAC(key string) net.HardwareAddr {
+	hash := sha1.New() // #nosec G401: not used for security purposes
+	io.WriteString(hash, key)
+	mac := net.HardwareAddr(hash.Sum(nil)[:6])
+	// Clear the multicast bit and set the locally administered bit.
+	mac[0] = (mac[0] & 0xfe) | 0x02
+	return mac
+}
+
 // GenerateContainerInterfaceKey generates a unique string for a Pod's
 // interface as: container/<Container-ID>.
 // We must use ContainerID instead of PodNamespace + PodName because there could
@@ -118,3 +131,30 @@ func GetIPNetDeviceFromIP(localIP net.IP) (*net.IPNet, *net.Interface, error) {
 	}
 	return nil, nil, fmt.Errorf("unable to find local IP and device")
 }
+
+// GetLocalIPv4Addrs returns the IPv4 addresses of the local interfaces which are in one of the provided CIDRs, or
+// all of them if no CIDR is provided. The loopback addresses are excluded.
+func GetLocalIPv4Addrs(cidrs []*net.IPNet) ([]net.IP, error) {
+	addrList, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	for _, addr := range addrList {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.To4() == nil || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if len(cidrs) == 0 {
+			ips = append(ips, ipNet.IP.To4())
+			continue
+		}
+		for _, cidr := range cidrs {
+			if cidr.Contains(ipNet.IP) {
+				ips = append(ips, ipNet.IP.To4())
+				break
+			}
+		}
+	}
+	return ips, nil
+}
@@ -47,6 +47,22 @@ func TestGenerateContainerInterfaceName(t *testing.T) {
 	}
 }
 
+func TestGenerateStableMAC(t *testing.T) {
+	mac0 := GenerateStableMAC("node0/antrea-gw0")
+	if len(mac0) != 6 {
+		t.Errorf("Failed to generate a 6-byte MAC address: %s", mac0)
+	}
+	if mac0[0]&0x01 != 0 || mac0[0]&0x02 == 0 {
+		t.Errorf("Failed to generate a unicast locally administered MAC address: %s", mac0)
+	}
+	if mac1 := GenerateStableMAC("node0/antrea-gw0"); mac0.String() != mac1.String() {
+		t.Errorf("Failed to generate the same MAC address for the same key: %s, %s", mac0, mac1)
+	}
+	if mac2 := GenerateStableMAC("node1/antrea-gw0"); mac0.String() == mac2.String() {
+		t.Errorf("Failed to differentiate MAC addresses generated for different keys: %s", mac0)
+	}
+}
+
 func TestGetDefaultLocalNodeAddr(t *testing.T) {
 	conn, err := net.Dial("udp", "8.8.8.8:80")
 	if err != nil {
@@ -61,3 +77,23 @@ func TestGetDefaultLocalNodeAddr(t *testing.T) {
 	}
 	t.Logf("IP obtained %s, %v", ip, dev)
 }
+
+func TestGetLocalIPv4Addrs(t *testing.T) {
+	_, loopbackCIDR, _ := net.ParseCIDR("127.0.0.0/8")
+	ips, err := GetLocalIPv4Addrs([]*net.IPNet{loopbackCIDR})
+	if err != nil {
+		t.Fatalf("Failed to get local IPv4 addresses: %v", err)
+	}
+	if len(ips) != 0 {
+		t.Errorf("Expected loopback addresses to be excluded, got %v", ips)
+	}
+	ips, err = GetLocalIPv4Addrs(nil)
+	if err != nil {
+		t.Fatalf("Failed to get local IPv4 addresses: %v", err)
+	}
+	for _, ip := range ips {
+		if ip.To4() == nil || ip.IsLoopback() {
+			t.Errorf("Unexpected local address %s", ip)
+		}
+	}
+}
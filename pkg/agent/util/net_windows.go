@@ -56,6 +56,19 @@ func ConfigureInterfaceAddress(ifaceName string, ipConfig *net.IPNet) error {
 	return InvokePSCommand(cmd)
 }
 
+// AddInterfaceSecondaryAddress adds an IPAddress which is not used as source address on the specified interface.
+func AddInterfaceSecondaryAddress(ifaceName string, ipConfig *net.IPNet) error {
+	ipStr := strings.Split(ipConfig.String(), "/")
+	cmd := fmt.Sprintf(`New-NetIPAddress -InterfaceAlias "%s" -IPAddress %s -PrefixLength %s -SkipAsSource $true`, ifaceName, ipStr[0], ipStr[1])
+	return InvokePSCommand(cmd)
+}
+
+// RemoveInterfaceAddress removes the IPAddress from the specified interface.
+func RemoveInterfaceAddress(ifaceName string, ip net.IP) error {
+	cmd := fmt.Sprintf(`Remove-NetIPAddress -InterfaceAlias "%s" -IPAddress %s -Confirm:$false`, ifaceName, ip.String())
+	return InvokePSCommand(cmd)
+}
+
 // ConfigureInterfaceAddressWithDefaultGateway adds IPAddress on the specified interface and sets the default gateway
 // for the host.
 func ConfigureInterfaceAddressWithDefaultGateway(ifaceName string, ipConfig *net.IPNet, gateway string) error {
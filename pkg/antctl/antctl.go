@@ -19,18 +19,25 @@ import (
 	"reflect"
 
 	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/agentinfo"
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/flowrecords"
 	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/ovsflows"
 	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/ovstracing"
 	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/podinterface"
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/raw/checkcni"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/raw/quarantine"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/raw/recommend"
 	"github.com/vmware-tanzu/antrea/pkg/antctl/raw/supportbundle"
 	"github.com/vmware-tanzu/antrea/pkg/antctl/raw/traceflow"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/raw/uninstall"
 	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/addressgroup"
 	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/appliedtogroup"
 	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/controllerinfo"
 	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/networkpolicy"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/networkpolicystats"
 	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/version"
 	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
 	systemv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/system/v1beta1"
 	controllerinforest "github.com/vmware-tanzu/antrea/pkg/apiserver/registry/system/controllerinfo"
 	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/scheme"
@@ -81,7 +88,8 @@ var CommandList = &commandList{
 							arg:   true,
 						},
 					},
-					outputType: single,
+					outputType:     single,
+					updatingParams: true,
 				},
 			},
 			agentEndpoint: &endpoint{
@@ -94,7 +102,8 @@ var CommandList = &commandList{
 							arg:   true,
 						},
 					},
-					outputType: single,
+					outputType:     single,
+					updatingParams: true,
 				},
 			},
 			transformedResponse: reflect.TypeOf(0),
@@ -200,6 +209,62 @@ var CommandList = &commandList{
 			},
 			transformedResponse: reflect.TypeOf(addressgroup.Response{}),
 		},
+		{
+			use:     "networkpolicystats",
+			aliases: []string{"netpolstats"},
+			short:   "Print the traffic stats of K8s NetworkPolicies",
+			long:    "Print the traffic stats of K8s NetworkPolicies, aggregated from all Nodes by ${component}. 'namespace' is required if 'name' is provided.",
+			example: `  Get the traffic stats of a specific K8s NetworkPolicy
+  $ antctl get networkpolicystats np1 -n ns1
+  Get the traffic stats of the K8s NetworkPolicies in a Namespace
+  $ antctl get networkpolicystats -n ns1`,
+			commandGroup: get,
+			controllerEndpoint: &endpoint{
+				resourceEndpoint: &resourceEndpoint{
+					groupVersionResource: &statsv1alpha1.NetworkPolicyStatsVersionResource,
+					namespaced:           true,
+				},
+				addonTransform: networkpolicystats.Transform,
+			},
+			transformedResponse: reflect.TypeOf(networkpolicystats.Response{}),
+		},
+		{
+			use:     "antreaclusternetworkpolicystats",
+			aliases: []string{"acnpstats"},
+			short:   "Print the traffic stats of Antrea ClusterNetworkPolicies",
+			long:    "Print the traffic stats of Antrea ClusterNetworkPolicies and of their rules, aggregated from all Nodes by ${component}. The rules which have not been hit are listed as unused.",
+			example: `  Get the traffic stats of a specific Antrea ClusterNetworkPolicy and of its rules
+  $ antctl get antreaclusternetworkpolicystats acnp1 -o yaml
+  Get the traffic stats of all Antrea ClusterNetworkPolicies
+  $ antctl get antreaclusternetworkpolicystats`,
+			commandGroup: get,
+			controllerEndpoint: &endpoint{
+				resourceEndpoint: &resourceEndpoint{
+					groupVersionResource: &statsv1alpha1.AntreaClusterNetworkPolicyStatsVersionResource,
+				},
+				addonTransform: networkpolicystats.AntreaClusterNetworkPolicyTransform,
+			},
+			transformedResponse: reflect.TypeOf(networkpolicystats.Response{}),
+		},
+		{
+			use:     "antreanetworkpolicystats",
+			aliases: []string{"anpstats"},
+			short:   "Print the traffic stats of Antrea NetworkPolicies",
+			long:    "Print the traffic stats of Antrea NetworkPolicies and of their rules, aggregated from all Nodes by ${component}. The rules which have not been hit are listed as unused. 'namespace' is required if 'name' is provided.",
+			example: `  Get the traffic stats of a specific Antrea NetworkPolicy and of its rules
+  $ antctl get antreanetworkpolicystats anp1 -n ns1 -o yaml
+  Get the traffic stats of the Antrea NetworkPolicies in a Namespace
+  $ antctl get antreanetworkpolicystats -n ns1`,
+			commandGroup: get,
+			controllerEndpoint: &endpoint{
+				resourceEndpoint: &resourceEndpoint{
+					groupVersionResource: &statsv1alpha1.AntreaNetworkPolicyStatsVersionResource,
+					namespaced:           true,
+				},
+				addonTransform: networkpolicystats.AntreaNetworkPolicyTransform,
+			},
+			transformedResponse: reflect.TypeOf(networkpolicystats.Response{}),
+		},
 		{
 			use:     "controllerinfo",
 			aliases: []string{"controllerinfos", "ci"},
@@ -308,6 +373,42 @@ var CommandList = &commandList{
 			commandGroup:        get,
 			transformedResponse: reflect.TypeOf(ovsflows.Response{}),
 		},
+		{
+			use:     "flowrecords",
+			aliases: []string{"flowrecord", "fr"},
+			short:   "Print the flow records of the Flow Exporter",
+			long:    "Print the in-memory flow records of the connections exported by the Flow Exporter of the Antrea agent, without an IPFIX collector.",
+			example: `  Get all the flow records
+  $ antctl get flowrecords
+  Get the flow records of the connections from or to a local Pod
+  $ antctl get flowrecords -p pod1 -n ns1
+  Get the flow records of the connections from a source IP
+  $ antctl get flowrecords --srcip 10.10.0.5`,
+			agentEndpoint: &endpoint{
+				nonResourceEndpoint: &nonResourceEndpoint{
+					path: "/flowrecords",
+					params: []flagInfo{
+						{
+							name:      "pod",
+							usage:     "Name of a local Pod. Only the flow records of the connections from or to the Pod are printed.",
+							shorthand: "p",
+						},
+						{
+							name:      "namespace",
+							usage:     "Namespace of the Pod",
+							shorthand: "n",
+						},
+						{
+							name:  "srcip",
+							usage: "Source IP. Only the flow records of the connections from the IP are printed.",
+						},
+					},
+					outputType: multiple,
+				},
+			},
+			commandGroup:        get,
+			transformedResponse: reflect.TypeOf(flowrecords.Response{}),
+		},
 		{
 			use:   "trace-packet",
 			short: "OVS packet tracing",
@@ -398,6 +499,26 @@ var CommandList = &commandList{
 			supportAgent:      true,
 			supportController: true,
 		},
+		{
+			cobraCommand:      quarantine.Command,
+			supportAgent:      true,
+			supportController: true,
+		},
+		{
+			cobraCommand:      recommend.Command,
+			supportAgent:      false,
+			supportController: true,
+		},
+		{
+			cobraCommand:      checkcni.Command,
+			supportAgent:      true,
+			supportController: false,
+		},
+		{
+			cobraCommand:      uninstall.Command,
+			supportAgent:      true,
+			supportController: true,
+		},
 	},
 	codec: scheme.Codecs,
 }
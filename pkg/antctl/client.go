@@ -113,8 +113,11 @@ func (c *client) nonResourceRequest(e *nonResourceEndpoint, opt *requestOption)
 		q.Set(k, v)
 	}
 	u.RawQuery = q.Encode()
-	getter := restClient.Get().RequestURI(u.RequestURI()).Timeout(opt.timeout)
-	result, err := getter.DoRaw(context.TODO())
+	request := restClient.Get()
+	if e.updatingParams && len(opt.args) > 0 {
+		request = restClient.Put()
+	}
+	result, err := request.RequestURI(u.RequestURI()).Timeout(opt.timeout).DoRaw(context.TODO())
 	if err != nil {
 		statusErr, ok := err.(*errors.StatusError)
 		if !ok {
@@ -134,6 +134,10 @@ type nonResourceEndpoint struct {
 	path       string
 	params     []flagInfo
 	outputType OutputType
+	// updatingParams is true if the endpoint updates the state of the
+	// component when parameters are provided, in which case the request is
+	// a PUT instead of a GET, e.g. to set the log level.
+	updatingParams bool
 }
 
 func (e *nonResourceEndpoint) flags() []flagInfo {
@@ -553,13 +557,13 @@ func (cd *commandDefinition) tableOutputForQueryEndpoint(obj interface{}, writer
 		// transform applied policies to string representation
 		policies := make([][]string, 0)
 		for _, policy := range endpoint.Policies {
-			policyStr := []string{policy.Name, policy.Namespace, string(policy.UID)}
+			policyStr := []string{policy.Name, policy.Namespace, string(policy.UID), strings.Join(policy.AppliedToGroups, ",")}
 			policies = append(policies, policyStr)
 		}
 		// transform egress and ingress rules to string representation
 		egress, ingress := make([][]string, 0), make([][]string, 0)
 		for _, rule := range endpoint.Rules {
-			ruleStr := []string{rule.Name, rule.Namespace, strconv.Itoa(rule.RuleIndex), string(rule.UID)}
+			ruleStr := []string{rule.Name, rule.Namespace, strconv.Itoa(rule.RuleIndex), string(rule.UID), strings.Join(rule.AddressGroups, ",")}
 			if rule.Direction == v1beta1.DirectionIn {
 				ingress = append(ingress, ruleStr)
 			} else if rule.Direction == v1beta1.DirectionOut {
@@ -576,7 +580,7 @@ func (cd *commandDefinition) tableOutputForQueryEndpoint(obj interface{}, writer
 		if nonEmpty {
 			policyLabel = []string{"Applied Policies:"}
 		}
-		if err := constructSection([][]string{policyLabel}, [][]string{{"Name", "Namespace", "UID"}}, policies, nonEmpty); err != nil {
+		if err := constructSection([][]string{policyLabel}, [][]string{{"Name", "Namespace", "UID", "AppliedToGroups"}}, policies, nonEmpty); err != nil {
 			return err
 		}
 		// egress rules
@@ -585,7 +589,7 @@ func (cd *commandDefinition) tableOutputForQueryEndpoint(obj interface{}, writer
 		if nonEmpty {
 			egressLabel = []string{"Egress Rules:"}
 		}
-		if err := constructSection([][]string{egressLabel}, [][]string{{"Name", "Namespace", "Index", "UID"}}, egress, nonEmpty); err != nil {
+		if err := constructSection([][]string{egressLabel}, [][]string{{"Name", "Namespace", "Index", "UID", "AddressGroups"}}, egress, nonEmpty); err != nil {
 			return err
 		}
 		// ingress rules
@@ -594,7 +598,7 @@ func (cd *commandDefinition) tableOutputForQueryEndpoint(obj interface{}, writer
 		if nonEmpty {
 			ingressLabel = []string{"Ingress Rules:"}
 		}
-		if err := constructSection([][]string{ingressLabel}, [][]string{{"Name", "Namespace", "Index", "UID"}}, ingress, nonEmpty); err != nil {
+		if err := constructSection([][]string{ingressLabel}, [][]string{{"Name", "Namespace", "Index", "UID", "AddressGroups"}}, ingress, nonEmpty); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,187 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkcni
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	cnipb "github.com/vmware-tanzu/antrea/pkg/apis/cni/v1beta1"
+)
+
+const (
+	// checkPodName is the name of the Pod which is reported to the CNI
+	// server for the dummy network namespace. No such Pod exists.
+	checkPodName = "antctl-check-cni"
+	// checkCNIVersion is the CNI spec version used by the requests. It is
+	// the first version which supports CHECK.
+	checkCNIVersion = "0.4.0"
+	checkIfName     = "eth0"
+	checkCNIPath    = "/opt/cni/bin"
+	checkTimeout    = 30 * time.Second
+)
+
+var (
+	Command *cobra.Command
+	option  = &struct {
+		healthOnly         bool
+		namespace          string
+		hostProcPathPrefix string
+	}{}
+)
+
+func init() {
+	Command = &cobra.Command{
+		Use:   "check-cni",
+		Short: "Check the CNI server of the local Antrea Agent",
+		Long: `Check the CNI server of the local Antrea Agent. The version and the health of the CNI server are
+queried first. Then, unless --health-only is provided, the CNI ADD, CHECK and DEL commands are sent to the CNI
+server for a dummy network namespace, in the same way as the container runtime does it when a Pod is created and
+deleted. This command can only run in the antrea-agent container.`,
+		Example: `  Check the CNI server end-to-end
+  $antctl check-cni
+  Only query the version and the health of the CNI server
+  $antctl check-cni --health-only
+`,
+		Args: cobra.NoArgs,
+		RunE: runE,
+	}
+
+	Command.Flags().BoolVar(&option.healthOnly, "health-only", false, "only query the version and the health of the CNI server")
+	Command.Flags().StringVarP(&option.namespace, "namespace", "n", "default", "Namespace reported to the CNI server for the dummy Pod")
+	Command.Flags().StringVar(&option.hostProcPathPrefix, "host-proc-path-prefix", "/host", "path prefix of the host /proc mount in the antrea-agent container")
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	conn, err := dialCNIServer()
+	if err != nil {
+		return fmt.Errorf("error when connecting to the CNI server: %w", err)
+	}
+	defer conn.Close()
+	client := cnipb.NewCniClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	out := cmd.OutOrStdout()
+	if err := checkHealth(ctx, client, out); err != nil || option.healthOnly {
+		return err
+	}
+
+	netNS, release, err := newNetNS(option.hostProcPathPrefix)
+	if err != nil {
+		return fmt.Errorf("error when creating the dummy network namespace: %w", err)
+	}
+	defer release()
+	return checkCommands(ctx, client, netNS, option.namespace, out)
+}
+
+// checkHealth prints the version of the CNI server and returns an error if
+// it is not healthy.
+func checkHealth(ctx context.Context, client cnipb.CniClient, out io.Writer) error {
+	version, err := client.Version(ctx, &cnipb.CniVersionRequest{})
+	if err != nil {
+		return fmt.Errorf("error when querying the version of the CNI server: %w", err)
+	}
+	fmt.Fprintf(out, "Agent version: %s\n", version.AgentVersion)
+	fmt.Fprintf(out, "CNI API version: %s\n", version.ApiVersion)
+	fmt.Fprintf(out, "Supported CNI versions: %s\n", strings.Join(version.SupportedCniVersions, ", "))
+
+	health, err := client.Health(ctx, &cnipb.CniHealthRequest{})
+	if err != nil {
+		return fmt.Errorf("error when querying the health of the CNI server: %w", err)
+	}
+	if !health.Healthy {
+		return fmt.Errorf("CNI server is not healthy: %s", health.Message)
+	}
+	fmt.Fprintln(out, "CNI server is healthy")
+	return nil
+}
+
+// newCmdArgs returns the arguments of a CNI request for the dummy Pod. If
+// prevResult is not nil, it is added to the network configuration, as
+// required by CHECK.
+func newCmdArgs(containerID, netNS, namespace string, prevResult []byte) (*cnipb.CniCmdArgs, error) {
+	netConf := map[string]interface{}{
+		"cniVersion": checkCNIVersion,
+		"name":       "antrea",
+		"type":       "antrea",
+		"ipam":       map[string]string{"type": "host-local"},
+	}
+	if prevResult != nil {
+		netConf["prevResult"] = json.RawMessage(prevResult)
+	}
+	netConfBytes, err := json.Marshal(netConf)
+	if err != nil {
+		return nil, err
+	}
+	return &cnipb.CniCmdArgs{
+		ContainerId:          containerID,
+		Netns:                netNS,
+		Ifname:               checkIfName,
+		Args:                 fmt.Sprintf("IgnoreUnknown=1;K8S_POD_NAMESPACE=%s;K8S_POD_NAME=%s;K8S_POD_INFRA_CONTAINER_ID=%s", namespace, checkPodName, containerID),
+		Path:                 checkCNIPath,
+		NetworkConfiguration: netConfBytes,
+	}, nil
+}
+
+type cmdFunc func(ctx context.Context, in *cnipb.CniCmdRequest, opts ...grpc.CallOption) (*cnipb.CniCmdResponse, error)
+
+func runCommand(ctx context.Context, name string, f cmdFunc, args *cnipb.CniCmdArgs, out io.Writer) ([]byte, error) {
+	resp, err := f(ctx, &cnipb.CniCmdRequest{CniArgs: args})
+	if err != nil {
+		return nil, fmt.Errorf("CNI %s failed: %w", name, err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("CNI %s failed: %s (%s)", name, resp.Error.Message, resp.Error.Code)
+	}
+	fmt.Fprintf(out, "CNI %s succeeded\n", name)
+	return resp.CniResult, nil
+}
+
+// checkCommands sends the ADD, CHECK and DEL commands for a dummy Pod in
+// netNS. DEL is always sent once ADD has been sent, so that the resources
+// allocated for the dummy Pod are released even if ADD or CHECK fails.
+func checkCommands(ctx context.Context, client cnipb.CniClient, netNS, namespace string, out io.Writer) (err error) {
+	containerID := fmt.Sprintf("%s-%s", checkPodName, rand.String(8))
+	addArgs, err := newCmdArgs(containerID, netNS, namespace, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _, delErr := runCommand(ctx, "DEL", client.CmdDel, addArgs, out); delErr != nil && err == nil {
+			err = delErr
+		}
+	}()
+
+	result, err := runCommand(ctx, "ADD", client.CmdAdd, addArgs, out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "CNI ADD result: %s\n", result)
+	checkArgs, err := newCmdArgs(containerID, netNS, namespace, result)
+	if err != nil {
+		return err
+	}
+	_, err = runCommand(ctx, "CHECK", client.CmdCheck, checkArgs, out)
+	return err
+}
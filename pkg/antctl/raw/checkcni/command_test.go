@@ -0,0 +1,96 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkcni
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	cnipb "github.com/vmware-tanzu/antrea/pkg/apis/cni/v1beta1"
+)
+
+const testResult = `{"cniVersion":"0.4.0","ips":[{"version":"4","address":"10.10.0.5/24"}]}`
+
+type fakeCniClient struct {
+	healthy  bool
+	addError *cnipb.Error
+	requests []string
+	checkArg *cnipb.CniCmdArgs
+}
+
+func (c *fakeCniClient) CmdAdd(_ context.Context, in *cnipb.CniCmdRequest, _ ...grpc.CallOption) (*cnipb.CniCmdResponse, error) {
+	c.requests = append(c.requests, "ADD")
+	if c.addError != nil {
+		return &cnipb.CniCmdResponse{Error: c.addError}, nil
+	}
+	return &cnipb.CniCmdResponse{CniResult: []byte(testResult)}, nil
+}
+
+func (c *fakeCniClient) CmdCheck(_ context.Context, in *cnipb.CniCmdRequest, _ ...grpc.CallOption) (*cnipb.CniCmdResponse, error) {
+	c.requests = append(c.requests, "CHECK")
+	c.checkArg = in.CniArgs
+	return &cnipb.CniCmdResponse{}, nil
+}
+
+func (c *fakeCniClient) CmdDel(_ context.Context, in *cnipb.CniCmdRequest, _ ...grpc.CallOption) (*cnipb.CniCmdResponse, error) {
+	c.requests = append(c.requests, "DEL")
+	return &cnipb.CniCmdResponse{}, nil
+}
+
+func (c *fakeCniClient) Version(_ context.Context, _ *cnipb.CniVersionRequest, _ ...grpc.CallOption) (*cnipb.CniVersionResponse, error) {
+	return &cnipb.CniVersionResponse{AgentVersion: "v0.11.0", ApiVersion: "1.1.0-beta.1", SupportedCniVersions: []string{"0.3.1", "0.4.0"}}, nil
+}
+
+func (c *fakeCniClient) Health(_ context.Context, _ *cnipb.CniHealthRequest, _ ...grpc.CallOption) (*cnipb.CniHealthResponse, error) {
+	if !c.healthy {
+		return &cnipb.CniHealthResponse{Healthy: false, Message: "OpenFlow connection to the OVS bridge is down"}, nil
+	}
+	return &cnipb.CniHealthResponse{Healthy: true}, nil
+}
+
+func TestCheckHealth(t *testing.T) {
+	out := &bytes.Buffer{}
+	require.NoError(t, checkHealth(context.TODO(), &fakeCniClient{healthy: true}, out))
+	assert.Contains(t, out.String(), "Agent version: v0.11.0")
+	assert.Contains(t, out.String(), "Supported CNI versions: 0.3.1, 0.4.0")
+
+	err := checkHealth(context.TODO(), &fakeCniClient{healthy: false}, &bytes.Buffer{})
+	assert.EqualError(t, err, "CNI server is not healthy: OpenFlow connection to the OVS bridge is down")
+}
+
+func TestCheckCommands(t *testing.T) {
+	client := &fakeCniClient{healthy: true}
+	require.NoError(t, checkCommands(context.TODO(), client, "/proc/100/task/101/ns/net", "default", &bytes.Buffer{}))
+	assert.Equal(t, []string{"ADD", "CHECK", "DEL"}, client.requests)
+	assert.Equal(t, "/proc/100/task/101/ns/net", client.checkArg.Netns)
+	var netConf map[string]interface{}
+	require.NoError(t, json.Unmarshal(client.checkArg.NetworkConfiguration, &netConf))
+	assert.Equal(t, "0.4.0", netConf["cniVersion"])
+	assert.Contains(t, netConf, "prevResult")
+}
+
+func TestCheckCommandsAddFailure(t *testing.T) {
+	client := &fakeCniClient{healthy: true, addError: &cnipb.Error{Code: cnipb.ErrorCode_IPAM_FAILURE, Message: "no IP addresses available"}}
+	err := checkCommands(context.TODO(), client, "/proc/100/task/101/ns/net", "default", &bytes.Buffer{})
+	assert.EqualError(t, err, "CNI ADD failed: no IP addresses available (IPAM_FAILURE)")
+	// DEL must still be sent to release the resources allocated by ADD.
+	assert.Equal(t, []string{"ADD", "DEL"}, client.requests)
+}
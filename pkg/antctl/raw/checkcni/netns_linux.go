@@ -0,0 +1,59 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkcni
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// newNetNS creates a dummy network namespace and returns its path, as seen by
+// the CNI server, along with a function which must be called to release it.
+// The CNI server prepends hostProcPathPrefix to the path of the network
+// namespace in the request, so the namespace is referenced through the host
+// /proc mount: the namespace is held by a dedicated OS thread, and
+// <hostProcPathPrefix>/proc/thread-self resolves to the ID of that thread in
+// the host PID namespace.
+func newNetNS(hostProcPathPrefix string) (string, func(), error) {
+	pathCh := make(chan string)
+	errCh := make(chan error)
+	releaseCh := make(chan struct{})
+	go func() {
+		// The thread is never unlocked, so that it is terminated when the
+		// goroutine returns instead of being reused in the new namespace.
+		runtime.LockOSThread()
+		if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+			errCh <- fmt.Errorf("error when creating network namespace: %v", err)
+			return
+		}
+		threadSelf, err := os.Readlink(filepath.Join(hostProcPathPrefix, "proc", "thread-self"))
+		if err != nil {
+			errCh <- fmt.Errorf("error when resolving thread in host /proc mount: %v", err)
+			return
+		}
+		pathCh <- filepath.Join("/proc", threadSelf, "ns", "net")
+		<-releaseCh
+	}()
+	select {
+	case path := <-pathCh:
+		return path, func() { close(releaseCh) }, nil
+	case err := <-errCh:
+		return "", nil, err
+	}
+}
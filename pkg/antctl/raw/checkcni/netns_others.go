@@ -0,0 +1,27 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !linux
+
+package checkcni
+
+import (
+	"fmt"
+)
+
+// newNetNS is not supported on this platform: only the version and the health
+// of the CNI server can be checked.
+func newNetNS(_ string) (string, func(), error) {
+	return "", nil, fmt.Errorf("dummy network namespace is not supported on this platform, use --health-only")
+}
@@ -0,0 +1,281 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quarantine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/vmware-tanzu/antrea/pkg/antctl/runtime"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	clientset "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+)
+
+const (
+	// quarantinedPodAnnotationKey is the annotation of a quarantine
+	// ClusterNetworkPolicy whose value is the Namespace/Pod it isolates, so
+	// that the policy can be found even if the Pod has been deleted.
+	quarantinedPodAnnotationKey = "quarantine.antrea.tanzu.vmware.com/pod"
+	// quarantineTier is the Tier of the quarantine ClusterNetworkPolicies,
+	// which takes precedence over all the system generated Tiers.
+	quarantineTier = "Emergency"
+	// quarantineTierName is the name of the Tier resource of quarantineTier.
+	quarantineTierName = "emergency"
+	// quarantinePriority is the priority of the quarantine
+	// ClusterNetworkPolicies within quarantineTier, which is the highest
+	// priority a ClusterNetworkPolicy can have.
+	quarantinePriority = 1
+)
+
+var (
+	Command *cobra.Command
+	option  = &struct {
+		pod     string
+		allowed []string
+		release bool
+	}{}
+)
+
+func init() {
+	Command = &cobra.Command{
+		Use:   "quarantine",
+		Short: "Quarantine a Pod",
+		Long: `Quarantine a Pod by dropping all its ingress and egress traffic, except the traffic from/to the
+given forensic endpoints. The traffic is dropped by a ClusterNetworkPolicy created with the highest priority
+in the Emergency Tier, which takes precedence over all other NetworkPolicies. The Pod is not quarantined if
+a Tier takes precedence over the Emergency Tier, or if another ClusterNetworkPolicy has the same priority in
+the Emergency Tier, as their policies could override the quarantine. The AntreaPolicy feature gate must be
+enabled.`,
+		Example: `  Quarantine Pod busybox0 in Namespace default
+  $antctl quarantine -p busybox0
+  Quarantine Pod busybox0 in Namespace ns0, only allowing traffic from/to the forensic endpoint 10.0.0.10
+  $antctl quarantine -p ns0/busybox0 --allow 10.0.0.10
+  Release Pod busybox0 in Namespace ns0 from quarantine
+  $antctl quarantine -p ns0/busybox0 --release
+`,
+		RunE: runE,
+	}
+
+	Command.Flags().StringVarP(&option.pod, "pod", "p", "", "Pod to quarantine: Namespace/Pod or Pod")
+	Command.Flags().StringSliceVarP(&option.allowed, "allow", "a", nil, "IPs or CIDRs of the forensic endpoints which can still communicate with the quarantined Pod")
+	Command.Flags().BoolVarP(&option.release, "release", "", false, "release the Pod from quarantine")
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	if len(option.pod) == 0 {
+		return fmt.Errorf("a Pod must be specified with --pod")
+	}
+	namespace, podName, err := parsePod(option.pod)
+	if err != nil {
+		return err
+	}
+
+	kubeconfigPath, err := cmd.Flags().GetString("kubeconfig")
+	if err != nil {
+		return err
+	}
+	kubeconfig, err := runtime.ResolveKubeconfig(kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	k8sClient, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error when creating kubernetes clientset: %w", err)
+	}
+	client, err := clientset.NewForConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error when creating clientset: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if option.release {
+		if err := release(ctx, client, namespace, podName); err != nil {
+			return err
+		}
+		fmt.Printf("Pod %s/%s released from quarantine\n", namespace, podName)
+		return nil
+	}
+	if err := quarantine(ctx, k8sClient, client, namespace, podName, option.allowed); err != nil {
+		return err
+	}
+	fmt.Printf("Pod %s/%s quarantined\n", namespace, podName)
+	return nil
+}
+
+func parsePod(pod string) (string, string, error) {
+	split := strings.Split(pod, "/")
+	if len(split) == 1 {
+		return "default", split[0], nil
+	} else if len(split) == 2 && len(split[0]) != 0 && len(split[1]) != 0 {
+		return split[0], split[1], nil
+	}
+	return "", "", fmt.Errorf("pod should be in the format of Namespace/Pod or Pod")
+}
+
+// getPolicyName returns the name of the quarantine ClusterNetworkPolicy of a
+// Pod. It's derived from the UID of the Pod, which is unique and short enough
+// for the name of a resource, unlike the concatenation of its Namespace and
+// name.
+func getPolicyName(podUID types.UID) string {
+	return fmt.Sprintf("quarantine-%s", podUID)
+}
+
+// parseAllowedPeers converts the forensic endpoints, which can be IPs or
+// CIDRs, to NetworkPolicyPeers.
+func parseAllowedPeers(allowed []string) ([]secv1alpha1.NetworkPolicyPeer, error) {
+	var peers []secv1alpha1.NetworkPolicyPeer
+	for _, a := range allowed {
+		cidr := a
+		if ip := net.ParseIP(a); ip != nil {
+			if ip.To4() != nil {
+				cidr = ip.String() + "/32"
+			} else {
+				cidr = ip.String() + "/128"
+			}
+		} else if _, _, err := net.ParseCIDR(a); err != nil {
+			return nil, fmt.Errorf("invalid forensic endpoint %s, it should be an IP or a CIDR", a)
+		}
+		peers = append(peers, secv1alpha1.NetworkPolicyPeer{IPBlock: &secv1alpha1.IPBlock{CIDR: cidr}})
+	}
+	return peers, nil
+}
+
+// newQuarantinePolicy returns the ClusterNetworkPolicy which isolates the Pod
+// with the given UID. The Pod is selected by its UID rather than by a label,
+// as the labels of a Pod can be changed by anyone who can update it, which
+// would release it from quarantine. Traffic from/to the allowed peers is
+// allowed, and all the other traffic is dropped.
+func newQuarantinePolicy(namespace, podName string, podUID types.UID, allowedPeers []secv1alpha1.NetworkPolicyPeer) *secv1alpha1.ClusterNetworkPolicy {
+	allowAction := secv1alpha1.RuleActionAllow
+	dropAction := secv1alpha1.RuleActionDrop
+	var ingress, egress []secv1alpha1.Rule
+	if len(allowedPeers) > 0 {
+		ingress = append(ingress, secv1alpha1.Rule{Action: &allowAction, From: allowedPeers})
+		egress = append(egress, secv1alpha1.Rule{Action: &allowAction, To: allowedPeers})
+	}
+	ingress = append(ingress, secv1alpha1.Rule{Action: &dropAction})
+	egress = append(egress, secv1alpha1.Rule{Action: &dropAction})
+	return &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        getPolicyName(podUID),
+			Annotations: map[string]string{quarantinedPodAnnotationKey: namespace + "/" + podName},
+		},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			Tier:     quarantineTier,
+			Priority: quarantinePriority,
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{secv1alpha1.PodUIDLabelKey: string(podUID)}}},
+			},
+			Ingress: ingress,
+			Egress:  egress,
+		},
+	}
+}
+
+// checkPrecedence returns an error if the policies of another Tier or the
+// other ClusterNetworkPolicies of quarantineTier could take precedence over the
+// quarantine ClusterNetworkPolicies: a Tier with a lower priority value than
+// quarantineTier, or a ClusterNetworkPolicy which has the same priority in
+// quarantineTier and isn't a quarantine ClusterNetworkPolicy.
+func checkPrecedence(ctx context.Context, client clientset.Interface) error {
+	tiers, err := client.SecurityV1alpha1().Tiers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing Tiers: %w", err)
+	}
+	var quarantineTierPriority *int32
+	for i := range tiers.Items {
+		if tiers.Items[i].Name == quarantineTierName {
+			quarantineTierPriority = &tiers.Items[i].Spec.Priority
+		}
+	}
+	if quarantineTierPriority == nil {
+		return fmt.Errorf("tier %s not found, is AntreaPolicy feature gate enabled?", quarantineTier)
+	}
+	for _, tier := range tiers.Items {
+		if tier.Spec.Priority < *quarantineTierPriority {
+			return fmt.Errorf("tier %s takes precedence over Tier %s, its policies could override the quarantine", tier.Name, quarantineTier)
+		}
+	}
+	cnps, err := client.SecurityV1alpha1().ClusterNetworkPolicies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing ClusterNetworkPolicies: %w", err)
+	}
+	for _, cnp := range cnps.Items {
+		if _, ok := cnp.Annotations[quarantinedPodAnnotationKey]; ok {
+			continue
+		}
+		if strings.ToLower(cnp.Spec.Tier) == quarantineTierName && cnp.Spec.Priority <= quarantinePriority {
+			return fmt.Errorf("ClusterNetworkPolicy %s has the same priority as the quarantine in Tier %s, it could override the quarantine", cnp.Name, quarantineTier)
+		}
+	}
+	return nil
+}
+
+func quarantine(ctx context.Context, k8sClient kubernetes.Interface, client clientset.Interface, namespace, podName string, allowed []string) error {
+	allowedPeers, err := parseAllowedPeers(allowed)
+	if err != nil {
+		return err
+	}
+	pod, err := k8sClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error when getting Pod %s/%s: %w", namespace, podName, err)
+	}
+	if err := checkPrecedence(ctx, client); err != nil {
+		return fmt.Errorf("pod %s/%s cannot be quarantined: %w", namespace, podName, err)
+	}
+	cnp := newQuarantinePolicy(namespace, podName, pod.UID, allowedPeers)
+	if _, err := client.SecurityV1alpha1().ClusterNetworkPolicies().Create(ctx, cnp, metav1.CreateOptions{}); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return fmt.Errorf("pod %s/%s is already quarantined", namespace, podName)
+		}
+		return fmt.Errorf("error when creating ClusterNetworkPolicy, is AntreaPolicy feature gate enabled? %w", err)
+	}
+	return nil
+}
+
+func release(ctx context.Context, client clientset.Interface, namespace, podName string) error {
+	// The policies are looked up by annotation rather than by the UID of the
+	// Pod, as the Pod may have been deleted, or recreated with another UID,
+	// while it was quarantined.
+	cnps, err := client.SecurityV1alpha1().ClusterNetworkPolicies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing ClusterNetworkPolicies: %w", err)
+	}
+	var policyNames []string
+	for _, cnp := range cnps.Items {
+		if cnp.Annotations[quarantinedPodAnnotationKey] == namespace+"/"+podName {
+			policyNames = append(policyNames, cnp.Name)
+		}
+	}
+	if len(policyNames) == 0 {
+		return fmt.Errorf("pod %s/%s is not quarantined", namespace, podName)
+	}
+	for _, name := range policyNames {
+		if err := client.SecurityV1alpha1().ClusterNetworkPolicies().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("error when deleting ClusterNetworkPolicy %s: %w", name, err)
+		}
+	}
+	return nil
+}
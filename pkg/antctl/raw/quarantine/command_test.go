@@ -0,0 +1,165 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quarantine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+)
+
+func TestParseAllowedPeers(t *testing.T) {
+	tcs := []struct {
+		allowed  []string
+		expected []string
+		success  bool
+	}{
+		{allowed: nil, expected: nil, success: true},
+		{allowed: []string{"10.0.0.10"}, expected: []string{"10.0.0.10/32"}, success: true},
+		{allowed: []string{"10.0.0.0/24", "fd00::10"}, expected: []string{"10.0.0.0/24", "fd00::10/128"}, success: true},
+		{allowed: []string{"10.0.0.300"}, success: false},
+	}
+	for _, tc := range tcs {
+		peers, err := parseAllowedPeers(tc.allowed)
+		if !tc.success {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		var cidrs []string
+		for _, p := range peers {
+			cidrs = append(cidrs, p.IPBlock.CIDR)
+		}
+		assert.Equal(t, tc.expected, cidrs)
+	}
+}
+
+func TestRunEWithoutPod(t *testing.T) {
+	option.pod = ""
+	assert.Error(t, runE(Command, nil))
+}
+
+func TestQuarantineAndRelease(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns0", Name: "pod0", UID: "uid0", Labels: map[string]string{"app": "web"}},
+	}
+	k8sClient := k8sfake.NewSimpleClientset(pod)
+	client := fake.NewSimpleClientset(newTiers()...)
+	ctx := context.TODO()
+
+	require.NoError(t, quarantine(ctx, k8sClient, client, "ns0", "pod0", []string{"10.0.0.10"}))
+	cnp, err := client.SecurityV1alpha1().ClusterNetworkPolicies().Get(ctx, "quarantine-uid0", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, quarantineTier, cnp.Spec.Tier)
+	assert.Equal(t, "ns0/pod0", cnp.Annotations[quarantinedPodAnnotationKey])
+	assert.Equal(t, map[string]string{secv1alpha1.PodUIDLabelKey: "uid0"}, cnp.Spec.AppliedTo[0].PodSelector.MatchLabels)
+	require.Len(t, cnp.Spec.Ingress, 2)
+	require.Len(t, cnp.Spec.Egress, 2)
+	assert.Equal(t, secv1alpha1.RuleActionAllow, *cnp.Spec.Ingress[0].Action)
+	assert.Equal(t, "10.0.0.10/32", cnp.Spec.Ingress[0].From[0].IPBlock.CIDR)
+	assert.Equal(t, secv1alpha1.RuleActionDrop, *cnp.Spec.Ingress[1].Action)
+	assert.Empty(t, cnp.Spec.Ingress[1].From)
+	assert.Equal(t, "10.0.0.10/32", cnp.Spec.Egress[0].To[0].IPBlock.CIDR)
+	assert.Equal(t, secv1alpha1.RuleActionDrop, *cnp.Spec.Egress[1].Action)
+	assert.Equal(t, float64(quarantinePriority), cnp.Spec.Priority)
+
+	assert.Error(t, quarantine(ctx, k8sClient, client, "ns0", "pod0", nil), "Quarantining a quarantined Pod should fail")
+
+	require.NoError(t, release(ctx, client, "ns0", "pod0"))
+	_, err = client.SecurityV1alpha1().ClusterNetworkPolicies().Get(ctx, "quarantine-uid0", metav1.GetOptions{})
+	assert.Error(t, err)
+
+	assert.Error(t, release(ctx, client, "ns0", "pod0"), "Releasing a Pod which is not quarantined should fail")
+}
+
+func TestReleaseDeletedPod(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns0", Name: "pod0", UID: "uid0"}}
+	k8sClient := k8sfake.NewSimpleClientset(pod)
+	client := fake.NewSimpleClientset(newTiers()...)
+	ctx := context.TODO()
+
+	require.NoError(t, quarantine(ctx, k8sClient, client, "ns0", "pod0", nil))
+	require.NoError(t, k8sClient.CoreV1().Pods("ns0").Delete(ctx, "pod0", metav1.DeleteOptions{}))
+	require.NoError(t, release(ctx, client, "ns0", "pod0"))
+	cnps, err := client.SecurityV1alpha1().ClusterNetworkPolicies().List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, cnps.Items)
+}
+
+func TestQuarantinePreempted(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns0", Name: "pod0", UID: "uid0"}}
+	ctx := context.TODO()
+	tcs := []struct {
+		name    string
+		objects []runtime.Object
+	}{
+		{
+			name:    "no-emergency-tier",
+			objects: nil,
+		},
+		{
+			name: "tier-before-emergency",
+			objects: append(newTiers(), &secv1alpha1.Tier{
+				ObjectMeta: metav1.ObjectMeta{Name: "override"},
+				Spec:       secv1alpha1.TierSpec{Priority: 4},
+			}),
+		},
+		{
+			name: "policy-same-priority",
+			objects: append(newTiers(), &secv1alpha1.ClusterNetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "allow-all"},
+				Spec:       secv1alpha1.ClusterNetworkPolicySpec{Tier: "emergency", Priority: quarantinePriority},
+			}),
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			k8sClient := k8sfake.NewSimpleClientset(pod)
+			client := fake.NewSimpleClientset(tc.objects...)
+			assert.Error(t, quarantine(ctx, k8sClient, client, "ns0", "pod0", nil))
+			_, err := client.SecurityV1alpha1().ClusterNetworkPolicies().Get(ctx, "quarantine-uid0", metav1.GetOptions{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestQuarantineAnotherPod(t *testing.T) {
+	pod0 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns0", Name: "pod0", UID: "uid0"}}
+	pod1 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns0", Name: "pod1", UID: "uid1"}}
+	k8sClient := k8sfake.NewSimpleClientset(pod0, pod1)
+	client := fake.NewSimpleClientset(newTiers()...)
+	ctx := context.TODO()
+
+	// The quarantine policy of a Pod doesn't prevent another Pod from being
+	// quarantined.
+	require.NoError(t, quarantine(ctx, k8sClient, client, "ns0", "pod0", nil))
+	require.NoError(t, quarantine(ctx, k8sClient, client, "ns0", "pod1", nil))
+}
+
+func newTiers() []runtime.Object {
+	return []runtime.Object{
+		&secv1alpha1.Tier{ObjectMeta: metav1.ObjectMeta{Name: "emergency"}, Spec: secv1alpha1.TierSpec{Priority: 5}},
+		&secv1alpha1.Tier{ObjectMeta: metav1.ObjectMeta{Name: "application"}, Spec: secv1alpha1.TierSpec{Priority: 250}},
+	}
+}
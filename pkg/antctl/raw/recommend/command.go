@@ -0,0 +1,434 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/flowrecords"
+	"github.com/vmware-tanzu/antrea/pkg/agent/controller/noderoute"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/runtime"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	systemv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/system/v1beta1"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/certificate"
+	clientset "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+)
+
+const (
+	// recommendedLabelKey is the label of the recommended NetworkPolicies.
+	// Existing NetworkPolicies without it are never overwritten.
+	recommendedLabelKey = "policy.antrea.tanzu.vmware.com/recommended"
+	// recommendedPolicyPriority is the priority of the recommended
+	// NetworkPolicies within the Application Tier.
+	recommendedPolicyPriority = 10
+)
+
+// ignoredPodLabels are the labels set by the workload controllers which differ
+// between the Pods of a workload, and cannot be used to select all of them.
+var ignoredPodLabels = []string{
+	"pod-template-hash",
+	"controller-revision-hash",
+	"pod-template-generation",
+	"statefulset.kubernetes.io/pod-name",
+	"quarantine.antrea.tanzu.vmware.com/pod-uid",
+}
+
+var (
+	Command *cobra.Command
+	option  = &struct {
+		namespace string
+		dryRun    bool
+	}{}
+)
+
+func init() {
+	Command = &cobra.Command{
+		Use:   "recommend",
+		Short: "Recommend Antrea NetworkPolicies from the observed flows",
+		Long: `Recommend Antrea NetworkPolicies matching the traffic observed by the Flow Exporter of the Antrea
+agents. For each group of Pods receiving traffic, a NetworkPolicy allowing the observed sources and ports and
+dropping the other ingress traffic is created in the Monitor enforcement mode: it is not enforced, but the
+traffic it would drop is audit logged and counted in its statistics. The recommended NetworkPolicies have the
+label ` + recommendedLabelKey + `=true, and are updated when the command is run again. Set their
+enforcement mode to Enforce to adopt them. The FlowExporter feature gate must be enabled.`,
+		Example: `  Recommend NetworkPolicies for all the Namespaces
+  $antctl recommend
+  Recommend NetworkPolicies for the Pods in Namespace ns0, and only print them
+  $antctl recommend -n ns0 --dry-run
+`,
+		RunE: runE,
+	}
+
+	Command.Flags().StringVarP(&option.namespace, "namespace", "n", "", "only recommend NetworkPolicies in this Namespace")
+	Command.Flags().BoolVar(&option.dryRun, "dry-run", false, "print the recommended NetworkPolicies without creating them")
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	kubeconfigPath, err := cmd.Flags().GetString("kubeconfig")
+	if err != nil {
+		return err
+	}
+	kubeconfig, err := runtime.ResolveKubeconfig(kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	k8sClient, err := kubernetes.NewForConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error when creating kubernetes clientset: %w", err)
+	}
+	client, err := clientset.NewForConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error when creating clientset: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	flows, err := getFlowRecords(ctx, k8sClient, client, kubeconfig)
+	if err != nil {
+		return err
+	}
+	pods, err := k8sClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return fmt.Errorf("error when listing Pods: %w", err)
+	}
+	namespaces, err := k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return fmt.Errorf("error when listing Namespaces: %w", err)
+	}
+	policies := recommendPolicies(flows, pods.Items, namespaces.Items, option.namespace)
+	if len(policies) == 0 {
+		fmt.Println("No NetworkPolicy to recommend from the observed flows.")
+		return nil
+	}
+
+	for _, policy := range policies {
+		if option.dryRun {
+			data, err := yaml.Marshal(policy)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("---\n%s", data)
+			continue
+		}
+		if err := applyPolicy(ctx, client, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getCABundle returns the CA bundle published by the Antrea controller in the
+// antrea-ca ConfigMap, which is used to verify the API servers of the agents.
+func getCABundle(ctx context.Context, k8sClient kubernetes.Interface) ([]byte, error) {
+	caConfigMap, err := k8sClient.CoreV1().ConfigMaps(certificate.GetCAConfigMapNamespace()).Get(ctx, certificate.CAConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when getting ConfigMap %s: %w", certificate.CAConfigMapName, err)
+	}
+	caBundle, ok := caConfigMap.Data[certificate.CAConfigMapKey]
+	if !ok || caBundle == "" {
+		return nil, fmt.Errorf("no CA bundle in ConfigMap %s", certificate.CAConfigMapName)
+	}
+	return []byte(caBundle), nil
+}
+
+// getFlowRecords returns the flow records of all the Antrea agents. The agents
+// whose Flow Exporter is not enabled or which cannot be reached are skipped.
+// The API servers of the agents are verified with the CA bundle of Antrea.
+func getFlowRecords(ctx context.Context, k8sClient kubernetes.Interface, client clientset.Interface, cfgTmpl *rest.Config) ([]flowrecords.Response, error) {
+	agentInfoList, err := client.ClusterinformationV1beta1().AntreaAgentInfos().List(ctx, metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing AntreaAgentInfos: %w", err)
+	}
+	caBundle, err := getCABundle(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	var flows []flowrecords.Response
+	for _, agentInfo := range agentInfoList.Items {
+		nodeName := agentInfo.NodeRef.Name
+		node, err := k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("Error when getting Node %s: %v", nodeName, err)
+			continue
+		}
+		ip, err := noderoute.GetNodeAddr(node)
+		if err != nil {
+			klog.Warningf("Error when parsing IP of Node %s", nodeName)
+			continue
+		}
+		cfg := rest.CopyConfig(cfgTmpl)
+		cfg.Host = net.JoinHostPort(ip.String(), fmt.Sprint(agentInfo.APIPort))
+		cfg.APIPath = "/apis"
+		cfg.GroupVersion = &systemv1beta1.SchemeGroupVersion
+		cfg.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+		cfg.Insecure = false
+		cfg.CAFile = ""
+		cfg.CAData = caBundle
+		agentClient, err := rest.RESTClientFor(cfg)
+		if err != nil {
+			klog.Warningf("Error when creating agent client for Node %s: %v", nodeName, err)
+			continue
+		}
+		data, err := agentClient.Get().AbsPath("/flowrecords").DoRaw(ctx)
+		if err != nil {
+			klog.Warningf("Error when getting flow records of Node %s: %v", nodeName, err)
+			continue
+		}
+		var nodeFlows []flowrecords.Response
+		if err := json.Unmarshal(data, &nodeFlows); err != nil {
+			klog.Warningf("Error when decoding flow records of Node %s: %v", nodeName, err)
+			continue
+		}
+		flows = append(flows, nodeFlows...)
+	}
+	return flows, nil
+}
+
+// applyPolicy creates the recommended NetworkPolicy, or updates it if it was
+// recommended before.
+func applyPolicy(ctx context.Context, client clientset.Interface, policy *secv1alpha1.NetworkPolicy) error {
+	policies := client.SecurityV1alpha1().NetworkPolicies(policy.Namespace)
+	existing, err := policies.Get(ctx, policy.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := policies.Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error when creating NetworkPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		fmt.Printf("NetworkPolicy %s/%s created\n", policy.Namespace, policy.Name)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error when getting NetworkPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+	}
+	if existing.Labels[recommendedLabelKey] != "true" {
+		return fmt.Errorf("NetworkPolicy %s/%s already exists and was not recommended", policy.Namespace, policy.Name)
+	}
+	// The enforcement mode is kept, as the recommended NetworkPolicy may have
+	// been adopted.
+	existing.Spec.AppliedTo = policy.Spec.AppliedTo
+	existing.Spec.Ingress = policy.Spec.Ingress
+	if _, err := policies.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error when updating NetworkPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+	}
+	fmt.Printf("NetworkPolicy %s/%s updated\n", policy.Namespace, policy.Name)
+	return nil
+}
+
+// peer is a source of the traffic received by a workload, with the ports it
+// was observed on.
+type peer struct {
+	secv1alpha1.NetworkPolicyPeer
+	ports map[string]secv1alpha1.NetworkPolicyPort
+}
+
+// workload is a group of Pods selected by the same labels in a Namespace,
+// with the sources of the traffic they received.
+type workload struct {
+	namespace string
+	selector  labels.Set
+	peers     map[string]*peer
+}
+
+// selectorLabels returns the labels selecting the Pods of the workload of the
+// given Pod.
+func selectorLabels(pod *corev1.Pod) labels.Set {
+	set := labels.Set{}
+	for k, v := range pod.Labels {
+		set[k] = v
+	}
+	for _, k := range ignoredPodLabels {
+		delete(set, k)
+	}
+	return set
+}
+
+func protocolFromNumber(protocol uint8) (corev1.Protocol, bool) {
+	switch protocol {
+	case 6:
+		return corev1.ProtocolTCP, true
+	case 17:
+		return corev1.ProtocolUDP, true
+	case 132:
+		return corev1.ProtocolSCTP, true
+	}
+	return "", false
+}
+
+// sourcePeer returns the NetworkPolicyPeer matching the source of a flow to a
+// Pod in the given Namespace, with a key identifying it. It returns false if
+// the source cannot be selected.
+func sourcePeer(flow *flowrecords.Response, namespace string, pods map[string]*corev1.Pod, namespaces map[string]*corev1.Namespace) (string, secv1alpha1.NetworkPolicyPeer, bool) {
+	if flow.SourcePod == "" {
+		ip := net.ParseIP(flow.SourceIP)
+		if ip == nil {
+			return "", secv1alpha1.NetworkPolicyPeer{}, false
+		}
+		cidr := ip.String() + "/32"
+		if ip.To4() == nil {
+			cidr = ip.String() + "/128"
+		}
+		return "ip:" + cidr, secv1alpha1.NetworkPolicyPeer{IPBlock: &secv1alpha1.IPBlock{CIDR: cidr}}, true
+	}
+	pod, ok := pods[flow.SourcePod]
+	if !ok {
+		klog.V(2).Infof("Ignoring flow from Pod %s which no longer exists", flow.SourcePod)
+		return "", secv1alpha1.NetworkPolicyPeer{}, false
+	}
+	podSelector := selectorLabels(pod)
+	if len(podSelector) == 0 {
+		klog.Warningf("Ignoring flow from Pod %s which has no label to select it", flow.SourcePod)
+		return "", secv1alpha1.NetworkPolicyPeer{}, false
+	}
+	result := secv1alpha1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: podSelector}}
+	if pod.Namespace == namespace {
+		return "pod:" + podSelector.String(), result, true
+	}
+	ns, ok := namespaces[pod.Namespace]
+	if !ok || len(ns.Labels) == 0 {
+		klog.Warningf("Ignoring flow from Pod %s whose Namespace has no label to select it", flow.SourcePod)
+		return "", secv1alpha1.NetworkPolicyPeer{}, false
+	}
+	nsSelector := labels.Set(ns.Labels)
+	result.NamespaceSelector = &metav1.LabelSelector{MatchLabels: nsSelector}
+	return "ns:" + nsSelector.String() + "/pod:" + podSelector.String(), result, true
+}
+
+// recommendPolicies returns a NetworkPolicy for each workload receiving the
+// traffic of the flows, allowing the observed sources on the observed ports
+// and dropping the other ingress traffic. Only the Pods in the given
+// Namespace are considered if it is not empty. The denied flows are ignored.
+func recommendPolicies(flows []flowrecords.Response, podList []corev1.Pod, namespaceList []corev1.Namespace, namespace string) []*secv1alpha1.NetworkPolicy {
+	pods := map[string]*corev1.Pod{}
+	for i := range podList {
+		pod := &podList[i]
+		pods[pod.Namespace+"/"+pod.Name] = pod
+	}
+	namespaces := map[string]*corev1.Namespace{}
+	for i := range namespaceList {
+		namespaces[namespaceList[i].Name] = &namespaceList[i]
+	}
+
+	workloads := map[string]*workload{}
+	for i := range flows {
+		flow := &flows[i]
+		if flow.Denied || flow.DestinationPod == "" {
+			continue
+		}
+		protocol, ok := protocolFromNumber(flow.Protocol)
+		if !ok {
+			continue
+		}
+		pod, ok := pods[flow.DestinationPod]
+		if !ok || (namespace != "" && pod.Namespace != namespace) {
+			continue
+		}
+		selector := selectorLabels(pod)
+		if len(selector) == 0 {
+			klog.Warningf("Ignoring flow to Pod %s which has no label to select it", flow.DestinationPod)
+			continue
+		}
+		peerKey, policyPeer, ok := sourcePeer(flow, pod.Namespace, pods, namespaces)
+		if !ok {
+			continue
+		}
+
+		workloadKey := pod.Namespace + "/" + selector.String()
+		w, ok := workloads[workloadKey]
+		if !ok {
+			w = &workload{namespace: pod.Namespace, selector: selector, peers: map[string]*peer{}}
+			workloads[workloadKey] = w
+		}
+		p, ok := w.peers[peerKey]
+		if !ok {
+			p = &peer{NetworkPolicyPeer: policyPeer, ports: map[string]secv1alpha1.NetworkPolicyPort{}}
+			w.peers[peerKey] = p
+		}
+		port := intstr.FromInt(int(flow.DestinationPort))
+		p.ports[fmt.Sprintf("%s/%05d", protocol, flow.DestinationPort)] = secv1alpha1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+	}
+
+	var policies []*secv1alpha1.NetworkPolicy
+	for workloadKey, w := range workloads {
+		policies = append(policies, newRecommendedPolicy(workloadKey, w))
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Namespace != policies[j].Namespace {
+			return policies[i].Namespace < policies[j].Namespace
+		}
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// newRecommendedPolicy returns the NetworkPolicy of a workload. Its name is
+// derived from the workload so that it is updated by later recommendations.
+func newRecommendedPolicy(workloadKey string, w *workload) *secv1alpha1.NetworkPolicy {
+	allowAction := secv1alpha1.RuleActionAllow
+	dropAction := secv1alpha1.RuleActionDrop
+	peerKeys := make([]string, 0, len(w.peers))
+	for k := range w.peers {
+		peerKeys = append(peerKeys, k)
+	}
+	sort.Strings(peerKeys)
+	var ingress []secv1alpha1.Rule
+	for _, k := range peerKeys {
+		p := w.peers[k]
+		portKeys := make([]string, 0, len(p.ports))
+		for portKey := range p.ports {
+			portKeys = append(portKeys, portKey)
+		}
+		sort.Strings(portKeys)
+		ports := make([]secv1alpha1.NetworkPolicyPort, 0, len(portKeys))
+		for _, portKey := range portKeys {
+			ports = append(ports, p.ports[portKey])
+		}
+		ingress = append(ingress, secv1alpha1.Rule{Action: &allowAction, From: []secv1alpha1.NetworkPolicyPeer{p.NetworkPolicyPeer}, Ports: ports})
+	}
+	ingress = append(ingress, secv1alpha1.Rule{Action: &dropAction})
+
+	h := fnv.New32a()
+	h.Write([]byte(workloadKey))
+	return &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("recommended-%x", h.Sum32()),
+			Namespace: w.namespace,
+			Labels:    map[string]string{recommendedLabelKey: "true"},
+		},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			Priority: recommendedPolicyPriority,
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: w.selector}},
+			},
+			Ingress:         ingress,
+			EnforcementMode: secv1alpha1.EnforcementModeMonitor,
+		},
+	}
+}
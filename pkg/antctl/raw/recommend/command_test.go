@@ -0,0 +1,167 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recommend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/apiserver/handlers/flowrecords"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/certificate"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+)
+
+func newPod(namespace, name string, labels map[string]string) corev1.Pod {
+	return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
+
+func newPort(protocol corev1.Protocol, port int) secv1alpha1.NetworkPolicyPort {
+	p := intstr.FromInt(port)
+	return secv1alpha1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+}
+
+func TestRecommendPolicies(t *testing.T) {
+	pods := []corev1.Pod{
+		newPod("ns0", "web-1", map[string]string{"app": "web", "pod-template-hash": "abc"}),
+		newPod("ns0", "web-2", map[string]string{"app": "web", "pod-template-hash": "def"}),
+		newPod("ns0", "client", map[string]string{"app": "client"}),
+		newPod("ns1", "db", map[string]string{"app": "db"}),
+		newPod("ns1", "nolabel", nil),
+		newPod("ns2", "client", map[string]string{"app": "client"}),
+	}
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns0", Labels: map[string]string{"env": "prod"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}},
+	}
+	flows := []flowrecords.Response{
+		// The flows to both web Pods are allowed by the same rule.
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationPod: "ns0/web-1", DestinationPort: 80, Protocol: 6},
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationPod: "ns0/web-2", DestinationPort: 80, Protocol: 6},
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationPod: "ns0/web-2", DestinationPort: 443, Protocol: 6},
+		{SourceIP: "192.168.1.1", DestinationPod: "ns0/web-1", DestinationPort: 80, Protocol: 6},
+		// A Pod in another Namespace is selected with the labels of its
+		// Namespace.
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/web-1", DestinationPod: "ns1/db", DestinationPort: 5432, Protocol: 6},
+		// Ignored flows: denied, ICMP, to a Pod without label, from Pods
+		// whose Namespace has no label, to a deleted Pod and not to a Pod.
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationPod: "ns0/web-1", DestinationPort: 22, Protocol: 6, Denied: true},
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationPod: "ns0/web-1", Protocol: 1},
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationPod: "ns1/nolabel", DestinationPort: 80, Protocol: 6},
+		{SourceIP: "10.10.0.4", SourcePod: "ns1/db", DestinationPod: "ns0/web-1", DestinationPort: 53, Protocol: 17},
+		{SourceIP: "10.10.0.5", SourcePod: "ns2/client", DestinationPod: "ns0/web-1", DestinationPort: 80, Protocol: 6},
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationPod: "ns0/deleted", DestinationPort: 80, Protocol: 6},
+		{SourceIP: "10.10.0.3", SourcePod: "ns0/client", DestinationIP: "8.8.8.8", DestinationPort: 53, Protocol: 17},
+	}
+
+	policies := recommendPolicies(flows, pods, namespaces, "")
+	require.Len(t, policies, 2)
+	web, db := policies[0], policies[1]
+
+	allowAction := secv1alpha1.RuleActionAllow
+	dropAction := secv1alpha1.RuleActionDrop
+	assert.Equal(t, "ns0", web.Namespace)
+	assert.Equal(t, map[string]string{recommendedLabelKey: "true"}, web.Labels)
+	assert.Equal(t, secv1alpha1.EnforcementModeMonitor, web.Spec.EnforcementMode)
+	assert.Equal(t, []secv1alpha1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}}, web.Spec.AppliedTo)
+	assert.Equal(t, []secv1alpha1.Rule{
+		{
+			Action: &allowAction,
+			From:   []secv1alpha1.NetworkPolicyPeer{{IPBlock: &secv1alpha1.IPBlock{CIDR: "192.168.1.1/32"}}},
+			Ports:  []secv1alpha1.NetworkPolicyPort{newPort(corev1.ProtocolTCP, 80)},
+		},
+		{
+			Action: &allowAction,
+			From:   []secv1alpha1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}}},
+			Ports:  []secv1alpha1.NetworkPolicyPort{newPort(corev1.ProtocolTCP, 80), newPort(corev1.ProtocolTCP, 443)},
+		},
+		{Action: &dropAction},
+	}, web.Spec.Ingress)
+
+	assert.Equal(t, "ns1", db.Namespace)
+	assert.Equal(t, []secv1alpha1.Rule{
+		{
+			Action: &allowAction,
+			From: []secv1alpha1.NetworkPolicyPeer{{
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			}},
+			Ports: []secv1alpha1.NetworkPolicyPort{newPort(corev1.ProtocolTCP, 5432)},
+		},
+		{Action: &dropAction},
+	}, db.Spec.Ingress)
+
+	// The name of a policy only depends on its workload.
+	policies = recommendPolicies(flows[:1], pods, namespaces, "ns0")
+	require.Len(t, policies, 1)
+	assert.Equal(t, web.Name, policies[0].Name)
+}
+
+func TestApplyPolicy(t *testing.T) {
+	ctx := context.Background()
+	existing := &secv1alpha1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns0", Name: "recommended-1"}}
+	client := fake.NewSimpleClientset(existing)
+
+	// A NetworkPolicy which was not recommended is not overwritten.
+	policy := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns0", Name: "recommended-1", Labels: map[string]string{recommendedLabelKey: "true"}},
+		Spec:       secv1alpha1.NetworkPolicySpec{EnforcementMode: secv1alpha1.EnforcementModeMonitor},
+	}
+	assert.Error(t, applyPolicy(ctx, client, policy))
+
+	policy.Name = "recommended-2"
+	require.NoError(t, applyPolicy(ctx, client, policy))
+	created, err := client.SecurityV1alpha1().NetworkPolicies("ns0").Get(ctx, "recommended-2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, policy, created)
+
+	// The enforcement mode of an adopted recommended NetworkPolicy is kept
+	// when it is updated.
+	created.Spec.EnforcementMode = secv1alpha1.EnforcementModeEnforce
+	_, err = client.SecurityV1alpha1().NetworkPolicies("ns0").Update(ctx, created, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	dropAction := secv1alpha1.RuleActionDrop
+	policy.Spec.Ingress = []secv1alpha1.Rule{{Action: &dropAction}}
+	require.NoError(t, applyPolicy(ctx, client, policy))
+	updated, err := client.SecurityV1alpha1().NetworkPolicies("ns0").Get(ctx, "recommended-2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, secv1alpha1.EnforcementModeEnforce, updated.Spec.EnforcementMode)
+	assert.Equal(t, policy.Spec.Ingress, updated.Spec.Ingress)
+}
+
+func TestGetCABundle(t *testing.T) {
+	ctx := context.Background()
+	_, err := getCABundle(ctx, k8sfake.NewSimpleClientset())
+	assert.Error(t, err)
+
+	caConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: certificate.GetCAConfigMapNamespace(), Name: certificate.CAConfigMapName},
+	}
+	_, err = getCABundle(ctx, k8sfake.NewSimpleClientset(caConfigMap))
+	assert.Error(t, err)
+
+	caConfigMap.Data = map[string]string{certificate.CAConfigMapKey: "ca"}
+	caBundle, err := getCABundle(ctx, k8sfake.NewSimpleClientset(caConfigMap))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ca"), caBundle)
+}
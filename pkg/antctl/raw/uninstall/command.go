@@ -0,0 +1,83 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uninstall
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/uninstall"
+	"github.com/vmware-tanzu/antrea/pkg/apis"
+	"github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+)
+
+var (
+	Command *cobra.Command
+	option  = &uninstall.Options{}
+	// yes skips the confirmation prompt.
+	yes bool
+)
+
+func init() {
+	nodeCommand := &cobra.Command{
+		Use:   "node",
+		Short: "Remove all the Antrea state from the local Node",
+		Long: `Remove all the Antrea state from the local Node, before the Node is decommissioned or migrated to another
+CNI: the routes to the Pod CIDRs, the Antrea iptables chains and ipsets, the OVS bridge with all its ports, the
+CNI configuration and binary, and the IPAM store. The Pods running on the Node lose their connectivity. The
+command refuses to remove the state while the antrea-agent runs on the Node, as it would install its state
+again: the antrea-agent DaemonSet must no longer run on the Node, and the command must run on the Node itself,
+as root. It asks for confirmation unless the --yes option is provided.`,
+		Example: `  List the Antrea state which would be removed from the Node
+  $antctl uninstall node --dry-run
+  Remove all the Antrea state from the Node
+  $antctl uninstall node
+  Remove all the Antrea state from the Node without confirmation
+  $antctl uninstall node --yes
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !option.DryRun && !yes && !confirm(cmd.InOrStdin(), cmd.OutOrStdout()) {
+				return fmt.Errorf("uninstall aborted")
+			}
+			return uninstall.Run(option, cmd.OutOrStdout())
+		},
+	}
+	nodeCommand.Flags().BoolVar(&option.DryRun, "dry-run", false, "only list the Antrea state which would be removed")
+	nodeCommand.Flags().BoolVar(&yes, "yes", false, "remove the Antrea state without confirmation")
+	nodeCommand.Flags().StringVar(&option.OVSBridge, "ovs-bridge", "br-int", "name of the OVS bridge created by the antrea-agent")
+	nodeCommand.Flags().StringVar(&option.OVSRunDir, "ovs-run-dir", ovsconfig.DefaultOVSRunDir, "directory of the OVSDB socket")
+	nodeCommand.Flags().StringVar(&option.HostPathPrefix, "host-path-prefix", "", "path prefix of the host filesystem, if it is mounted in a container")
+	nodeCommand.Flags().IntVar(&option.AgentAPIPort, "agent-api-port", apis.AntreaAgentAPIPort, "port of the antrea-agent API server, used to check whether the antrea-agent runs on the Node")
+
+	Command = &cobra.Command{
+		Use:   "uninstall",
+		Short: "Uninstall Antrea",
+	}
+	Command.AddCommand(nodeCommand)
+}
+
+// confirm asks the user to confirm the removal of the Antrea state, and returns
+// whether it was confirmed.
+func confirm(in io.Reader, out io.Writer) bool {
+	fmt.Fprint(out, "All the Antrea state will be removed from the Node and its Pods will lose their connectivity. Continue? [y/N]: ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicystats
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/antrea/pkg/antctl/transform"
+	"github.com/vmware-tanzu/antrea/pkg/antctl/transform/common"
+	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
+)
+
+type Response struct {
+	Namespace        string                           `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Name             string                           `json:"name" yaml:"name"`
+	CreatedAt        string                           `json:"createdAt" yaml:"createdAt"`
+	TrafficStats     statsv1alpha1.TrafficStats       `json:"trafficStats" yaml:"trafficStats"`
+	RuleTrafficStats []statsv1alpha1.RuleTrafficStats `json:"ruleTrafficStats,omitempty" yaml:"ruleTrafficStats,omitempty"`
+}
+
+func newResponse(meta *metav1.ObjectMeta, stats statsv1alpha1.TrafficStats, ruleStats []statsv1alpha1.RuleTrafficStats) Response {
+	return Response{
+		Namespace:        meta.Namespace,
+		Name:             meta.Name,
+		CreatedAt:        meta.CreationTimestamp.Format(time.RFC3339),
+		TrafficStats:     stats,
+		RuleTrafficStats: ruleStats,
+	}
+}
+
+func networkPolicyStatsTransform(o interface{}) (interface{}, error) {
+	stats := o.(*statsv1alpha1.NetworkPolicyStats)
+	return newResponse(&stats.ObjectMeta, stats.TrafficStats, nil), nil
+}
+
+func networkPolicyStatsListTransform(l interface{}) (interface{}, error) {
+	statsList := l.(*statsv1alpha1.NetworkPolicyStatsList)
+	result := []Response{}
+	for i := range statsList.Items {
+		o, _ := networkPolicyStatsTransform(&statsList.Items[i])
+		result = append(result, o.(Response))
+	}
+	return result, nil
+}
+
+func antreaClusterNetworkPolicyStatsTransform(o interface{}) (interface{}, error) {
+	stats := o.(*statsv1alpha1.AntreaClusterNetworkPolicyStats)
+	return newResponse(&stats.ObjectMeta, stats.TrafficStats, stats.RuleTrafficStats), nil
+}
+
+func antreaClusterNetworkPolicyStatsListTransform(l interface{}) (interface{}, error) {
+	statsList := l.(*statsv1alpha1.AntreaClusterNetworkPolicyStatsList)
+	result := []Response{}
+	for i := range statsList.Items {
+		o, _ := antreaClusterNetworkPolicyStatsTransform(&statsList.Items[i])
+		result = append(result, o.(Response))
+	}
+	return result, nil
+}
+
+func antreaNetworkPolicyStatsTransform(o interface{}) (interface{}, error) {
+	stats := o.(*statsv1alpha1.AntreaNetworkPolicyStats)
+	return newResponse(&stats.ObjectMeta, stats.TrafficStats, stats.RuleTrafficStats), nil
+}
+
+func antreaNetworkPolicyStatsListTransform(l interface{}) (interface{}, error) {
+	statsList := l.(*statsv1alpha1.AntreaNetworkPolicyStatsList)
+	result := []Response{}
+	for i := range statsList.Items {
+		o, _ := antreaNetworkPolicyStatsTransform(&statsList.Items[i])
+		result = append(result, o.(Response))
+	}
+	return result, nil
+}
+
+// Transform is the AddonTransform of K8s NetworkPolicy stats.
+func Transform(reader io.Reader, single bool) (interface{}, error) {
+	return transform.GenericFactory(
+		reflect.TypeOf(statsv1alpha1.NetworkPolicyStats{}),
+		reflect.TypeOf(statsv1alpha1.NetworkPolicyStatsList{}),
+		networkPolicyStatsTransform,
+		networkPolicyStatsListTransform,
+	)(reader, single)
+}
+
+// AntreaClusterNetworkPolicyTransform is the AddonTransform of Antrea ClusterNetworkPolicy stats.
+func AntreaClusterNetworkPolicyTransform(reader io.Reader, single bool) (interface{}, error) {
+	return transform.GenericFactory(
+		reflect.TypeOf(statsv1alpha1.AntreaClusterNetworkPolicyStats{}),
+		reflect.TypeOf(statsv1alpha1.AntreaClusterNetworkPolicyStatsList{}),
+		antreaClusterNetworkPolicyStatsTransform,
+		antreaClusterNetworkPolicyStatsListTransform,
+	)(reader, single)
+}
+
+// AntreaNetworkPolicyTransform is the AddonTransform of Antrea NetworkPolicy stats.
+func AntreaNetworkPolicyTransform(reader io.Reader, single bool) (interface{}, error) {
+	return transform.GenericFactory(
+		reflect.TypeOf(statsv1alpha1.AntreaNetworkPolicyStats{}),
+		reflect.TypeOf(statsv1alpha1.AntreaNetworkPolicyStatsList{}),
+		antreaNetworkPolicyStatsTransform,
+		antreaNetworkPolicyStatsListTransform,
+	)(reader, single)
+}
+
+var _ common.TableOutput = new(Response)
+
+func (r Response) GetTableHeader() []string {
+	return []string{"NAMESPACE", "NAME", "SESSIONS", "PACKETS", "BYTES", "UNUSED-RULES", "CREATED-AT"}
+}
+
+// GetUnusedRules returns the rules which have not been hit since the stats were created, e.g. "Ingress[0]".
+func (r Response) GetUnusedRules(maxColumnLength int) string {
+	var list []string
+	for _, rule := range r.RuleTrafficStats {
+		if rule.TrafficStats.Packets == 0 {
+			list = append(list, fmt.Sprintf("%s[%d]", rule.Direction, rule.Index))
+		}
+	}
+	return common.GenerateTableElementWithSummary(list, maxColumnLength)
+}
+
+func (r Response) GetTableRow(maxColumnLength int) []string {
+	return []string{
+		r.Namespace,
+		r.Name,
+		strconv.FormatInt(r.TrafficStats.Sessions, 10),
+		strconv.FormatInt(r.TrafficStats.Packets, 10),
+		strconv.FormatInt(r.TrafficStats.Bytes, 10),
+		r.GetUnusedRules(maxColumnLength),
+		r.CreatedAt,
+	}
+}
+
+func (r Response) SortRows() bool {
+	return true
+}
@@ -35,6 +35,7 @@ type AntreaAgentInfo struct {
 	LocalPodNum                 int32                       `json:"localPodNum,omitempty"`                 // The number of Pods which the agent is in charge of
 	AgentConditions             []AgentCondition            `json:"agentConditions,omitempty"`             // Agent condition contains types like AgentHealthy
 	APIPort                     int                         `json:"apiPort,omitempty"`                     // The port of antrea agent API Server
+	NodeCapacity                NodeCapacityInfo            `json:"nodeCapacity,omitempty"`                // The usage of the datapath resources of the Node
 }
 
 type OVSInfo struct {
@@ -43,6 +44,15 @@ type OVSInfo struct {
 	FlowTable  map[string]int32 `json:"flowTable,omitempty"` // Key: flow table name, Value: flow number
 }
 
+// NodeCapacityInfo reports the usage of the datapath resources of the Node, for
+// capacity planning.
+type NodeCapacityInfo struct {
+	ConntrackCount int32 `json:"conntrackCount,omitempty"` // Number of connections in the conntrack table (nf_conntrack_count)
+	ConntrackMax   int32 `json:"conntrackMax,omitempty"`   // Size of the conntrack table (nf_conntrack_max)
+	OVSFlowCount   int32 `json:"ovsFlowCount,omitempty"`   // Total number of flows in the OVS flow tables
+	OVSGroupCount  int32 `json:"ovsGroupCount,omitempty"`  // Number of OVS groups
+}
+
 type AgentConditionType string
 
 const (
@@ -50,6 +60,7 @@ const (
 	ControllerConnectionUp AgentConditionType = "ControllerConnectionUp" // Status True/False is used to mark the connection status between Agent and Controller.
 	OVSDBConnectionUp      AgentConditionType = "OVSDBConnectionUp"      // Status True/False is used to mark OVSDB connection status.
 	OpenflowConnectionUp   AgentConditionType = "OpenflowConnectionUp"   // Status True/False is used to mark Openflow connection status.
+	OVSFeaturesSupported   AgentConditionType = "OVSFeaturesSupported"   // Status True/False is used to mark whether all the optional OVS features are supported, the unsupported ones are listed in the Message.
 )
 
 type AgentCondition struct {
@@ -102,7 +113,8 @@ type AntreaControllerInfoList struct {
 type ControllerConditionType string
 
 const (
-	ControllerHealthy ControllerConditionType = "ControllerHealthy" // Status is always set to be True and LastHeartbeatTime is used to check Controller health status.
+	ControllerHealthy  ControllerConditionType = "ControllerHealthy"  // Status is always set to be True and LastHeartbeatTime is used to check Controller health status.
+	ControllerDegraded ControllerConditionType = "ControllerDegraded" // Status True is used to mark that the Controller is shedding non-critical load because a watermark is exceeded.
 )
 
 type ControllerCondition struct {
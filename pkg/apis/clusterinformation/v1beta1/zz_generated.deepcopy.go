@@ -60,6 +60,7 @@ func (in *AntreaAgentInfo) DeepCopyInto(out *AntreaAgentInfo) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.NodeCapacity = in.NodeCapacity
 	return
 }
 
@@ -217,6 +218,22 @@ func (in *NetworkPolicyControllerInfo) DeepCopy() *NetworkPolicyControllerInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCapacityInfo) DeepCopyInto(out *NodeCapacityInfo) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCapacityInfo.
+func (in *NodeCapacityInfo) DeepCopy() *NodeCapacityInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCapacityInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OVSInfo) DeepCopyInto(out *OVSInfo) {
 	*out = *in
@@ -308,62 +308,243 @@ func (m *CniCmdResponse) GetError() *Error {
 	return nil
 }
 
+type CniVersionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CniVersionRequest) Reset()         { *m = CniVersionRequest{} }
+func (m *CniVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*CniVersionRequest) ProtoMessage()    {}
+func (*CniVersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b2a032bc733ddeeb, []int{4}
+}
+
+func (m *CniVersionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CniVersionRequest.Unmarshal(m, b)
+}
+func (m *CniVersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CniVersionRequest.Marshal(b, m, deterministic)
+}
+func (m *CniVersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CniVersionRequest.Merge(m, src)
+}
+func (m *CniVersionRequest) XXX_Size() int {
+	return xxx_messageInfo_CniVersionRequest.Size(m)
+}
+func (m *CniVersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CniVersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CniVersionRequest proto.InternalMessageInfo
+
+type CniVersionResponse struct {
+	// Version of the Antrea Agent.
+	AgentVersion string `protobuf:"bytes,1,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	// Version of the CNI Protobuf / gRPC service.
+	ApiVersion string `protobuf:"bytes,2,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	// CNI spec versions supported by the server.
+	SupportedCniVersions []string `protobuf:"bytes,3,rep,name=supported_cni_versions,json=supportedCniVersions,proto3" json:"supported_cni_versions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CniVersionResponse) Reset()         { *m = CniVersionResponse{} }
+func (m *CniVersionResponse) String() string { return proto.CompactTextString(m) }
+func (*CniVersionResponse) ProtoMessage()    {}
+func (*CniVersionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b2a032bc733ddeeb, []int{5}
+}
+
+func (m *CniVersionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CniVersionResponse.Unmarshal(m, b)
+}
+func (m *CniVersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CniVersionResponse.Marshal(b, m, deterministic)
+}
+func (m *CniVersionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CniVersionResponse.Merge(m, src)
+}
+func (m *CniVersionResponse) XXX_Size() int {
+	return xxx_messageInfo_CniVersionResponse.Size(m)
+}
+func (m *CniVersionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CniVersionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CniVersionResponse proto.InternalMessageInfo
+
+func (m *CniVersionResponse) GetAgentVersion() string {
+	if m != nil {
+		return m.AgentVersion
+	}
+	return ""
+}
+
+func (m *CniVersionResponse) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *CniVersionResponse) GetSupportedCniVersions() []string {
+	if m != nil {
+		return m.SupportedCniVersions
+	}
+	return nil
+}
+
+type CniHealthRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CniHealthRequest) Reset()         { *m = CniHealthRequest{} }
+func (m *CniHealthRequest) String() string { return proto.CompactTextString(m) }
+func (*CniHealthRequest) ProtoMessage()    {}
+func (*CniHealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b2a032bc733ddeeb, []int{6}
+}
+
+func (m *CniHealthRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CniHealthRequest.Unmarshal(m, b)
+}
+func (m *CniHealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CniHealthRequest.Marshal(b, m, deterministic)
+}
+func (m *CniHealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CniHealthRequest.Merge(m, src)
+}
+func (m *CniHealthRequest) XXX_Size() int {
+	return xxx_messageInfo_CniHealthRequest.Size(m)
+}
+func (m *CniHealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CniHealthRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CniHealthRequest proto.InternalMessageInfo
+
+type CniHealthResponse struct {
+	Healthy bool `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// Reason why the server cannot serve CNI requests, if not healthy.
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CniHealthResponse) Reset()         { *m = CniHealthResponse{} }
+func (m *CniHealthResponse) String() string { return proto.CompactTextString(m) }
+func (*CniHealthResponse) ProtoMessage()    {}
+func (*CniHealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b2a032bc733ddeeb, []int{7}
+}
+
+func (m *CniHealthResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CniHealthResponse.Unmarshal(m, b)
+}
+func (m *CniHealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CniHealthResponse.Marshal(b, m, deterministic)
+}
+func (m *CniHealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CniHealthResponse.Merge(m, src)
+}
+func (m *CniHealthResponse) XXX_Size() int {
+	return xxx_messageInfo_CniHealthResponse.Size(m)
+}
+func (m *CniHealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CniHealthResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CniHealthResponse proto.InternalMessageInfo
+
+func (m *CniHealthResponse) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *CniHealthResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.ErrorCode", ErrorCode_name, ErrorCode_value)
 	proto.RegisterType((*CniCmdArgs)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.CniCmdArgs")
 	proto.RegisterType((*CniCmdRequest)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.CniCmdRequest")
 	proto.RegisterType((*Error)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.Error")
 	proto.RegisterType((*CniCmdResponse)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.CniCmdResponse")
+	proto.RegisterType((*CniVersionRequest)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.CniVersionRequest")
+	proto.RegisterType((*CniVersionResponse)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.CniVersionResponse")
+	proto.RegisterType((*CniHealthRequest)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.CniHealthRequest")
+	proto.RegisterType((*CniHealthResponse)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.CniHealthResponse")
 }
 
 func init() { proto.RegisterFile("pkg/apis/cni/v1beta1/cni.proto", fileDescriptor_b2a032bc733ddeeb) }
 
 var fileDescriptor_b2a032bc733ddeeb = []byte{
-	// 698 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x53, 0x4d, 0x52, 0xe3, 0x46,
-	0x14, 0x46, 0xf8, 0x0f, 0x3f, 0x3b, 0x44, 0xe9, 0x18, 0xa2, 0x90, 0x90, 0x02, 0xaf, 0xa8, 0x2c,
-	0xe4, 0xc2, 0xec, 0xb2, 0x48, 0x4a, 0x6e, 0xb7, 0x49, 0x17, 0xa6, 0xe5, 0x6a, 0x64, 0x53, 0x61,
-	0xa3, 0x92, 0xa5, 0xb6, 0x50, 0xd9, 0x6e, 0x39, 0x92, 0x0c, 0xc5, 0xec, 0xe6, 0x0c, 0x53, 0x73,
-	0x84, 0xb9, 0xc4, 0xdc, 0x80, 0x39, 0xc6, 0x9c, 0x64, 0x4a, 0x92, 0x6d, 0xa6, 0xa6, 0x66, 0x05,
-	0x1b, 0x76, 0xaf, 0xbf, 0x4f, 0xef, 0x7b, 0xdf, 0xfb, 0xd4, 0x0d, 0x7f, 0x2c, 0xa6, 0x7e, 0xcb,
-	0x59, 0x04, 0x71, 0xcb, 0x95, 0x41, 0xeb, 0xee, 0x74, 0x2c, 0x12, 0xe7, 0x34, 0xad, 0xf5, 0x45,
-	0x14, 0x26, 0x21, 0x3a, 0xf3, 0x83, 0xe4, 0x76, 0x39, 0xd6, 0xdd, 0x70, 0xae, 0xdf, 0xcd, 0xef,
-	0x9d, 0x48, 0xd8, 0x89, 0x23, 0xdf, 0x2c, 0x75, 0x47, 0x26, 0x91, 0x70, 0xf4, 0xc5, 0xd4, 0xd7,
-	0xd3, 0x76, 0x3d, 0x6d, 0x59, 0xb5, 0x1f, 0xfc, 0xea, 0x87, 0xa1, 0x3f, 0x13, 0xad, 0x4c, 0x62,
-	0xbc, 0x9c, 0xb4, 0x1c, 0xf9, 0x90, 0xeb, 0x35, 0x3f, 0x2a, 0x00, 0x58, 0x06, 0x78, 0xee, 0x19,
-	0x91, 0x1f, 0xa3, 0x63, 0xa8, 0xbb, 0xa1, 0x4c, 0x9c, 0x40, 0x8a, 0xc8, 0x0e, 0x3c, 0x4d, 0x39,
-	0x52, 0x4e, 0xaa, 0xbc, 0xb6, 0xc1, 0xa8, 0x87, 0x1a, 0x50, 0x92, 0x22, 0x91, 0xb1, 0xb6, 0x9d,
-	0x71, 0xf9, 0x01, 0xed, 0x43, 0x39, 0x98, 0x48, 0x67, 0x2e, 0xb4, 0x42, 0x06, 0xaf, 0x4e, 0x08,
-	0x41, 0xd1, 0x89, 0xfc, 0x58, 0x2b, 0x66, 0x68, 0x56, 0xa7, 0xd8, 0xc2, 0x49, 0x6e, 0xb5, 0x52,
-	0x8e, 0xa5, 0x35, 0x3a, 0x83, 0x3d, 0x29, 0x92, 0xfb, 0x30, 0x9a, 0xda, 0x6e, 0x28, 0x27, 0x81,
-	0xbf, 0x8c, 0x9c, 0x24, 0x08, 0xa5, 0x56, 0x3e, 0x52, 0x4e, 0xea, 0xbc, 0xb1, 0x22, 0xf1, 0xd7,
-	0x5c, 0x73, 0x0a, 0x3f, 0xe4, 0xde, 0xb9, 0xf8, 0x7f, 0x29, 0xe2, 0x04, 0xdd, 0xc0, 0x8e, 0x2b,
-	0x03, 0x3b, 0x9b, 0x98, 0x5a, 0xaf, 0xb5, 0xff, 0xd1, 0x9f, 0x11, 0x98, 0xfe, 0x94, 0x08, 0xaf,
-	0xb8, 0x32, 0x48, 0x8b, 0xe6, 0x07, 0x05, 0x4a, 0x24, 0x8a, 0xc2, 0x08, 0x71, 0x28, 0xba, 0xa1,
-	0x27, 0xb2, 0x09, 0xbb, 0xed, 0xbf, 0x9f, 0x35, 0x21, 0x53, 0xc2, 0xa1, 0x27, 0x78, 0xa6, 0x85,
-	0x34, 0xa8, 0xcc, 0x45, 0x1c, 0x3b, 0xbe, 0x58, 0xe5, 0xba, 0x3e, 0x22, 0x1d, 0x2a, 0x9e, 0x48,
-	0x9c, 0x60, 0x16, 0x6b, 0x85, 0xa3, 0xc2, 0x49, 0xad, 0xdd, 0xd0, 0xf3, 0xdf, 0xa9, 0xaf, 0x7f,
-	0xa7, 0x6e, 0xc8, 0x07, 0xbe, 0xfe, 0xa8, 0xf9, 0x56, 0x81, 0xdd, 0x75, 0x2a, 0xf1, 0x22, 0x94,
-	0xb1, 0x40, 0x87, 0x00, 0x69, 0x2c, 0x91, 0x88, 0x97, 0xb3, 0x24, 0xb3, 0x5d, 0xe7, 0x55, 0x57,
-	0x06, 0x3c, 0x03, 0xd0, 0x00, 0x4a, 0x22, 0xb5, 0x93, 0x4d, 0xae, 0xb5, 0xff, 0x7a, 0xfe, 0x42,
-	0x3c, 0x17, 0xfa, 0xf3, 0xf3, 0x36, 0x54, 0x37, 0x1b, 0xa2, 0x1a, 0x54, 0x86, 0xec, 0x82, 0x99,
-	0xd7, 0x4c, 0xdd, 0x42, 0xbf, 0x83, 0x46, 0x19, 0x36, 0x2f, 0x07, 0x86, 0x45, 0x3b, 0x7d, 0x62,
-	0x63, 0x46, 0xed, 0x11, 0xe1, 0x57, 0xd4, 0x64, 0xaa, 0x82, 0xf6, 0xe0, 0xa7, 0x21, 0xbb, 0x1a,
-	0x0e, 0x06, 0x26, 0xb7, 0x48, 0xd7, 0xee, 0x51, 0xd2, 0xef, 0xaa, 0xdb, 0x39, 0x9c, 0x29, 0xd8,
-	0xd8, 0x64, 0x96, 0x41, 0x19, 0xe1, 0x6a, 0x01, 0x1d, 0xc3, 0x21, 0x65, 0x23, 0xa3, 0x4f, 0xbb,
-	0x36, 0x61, 0x23, 0xca, 0x4d, 0x76, 0x49, 0x98, 0x65, 0x8f, 0x0c, 0x4e, 0x8d, 0x4e, 0x9f, 0x5c,
-	0xa9, 0x45, 0xb4, 0x0b, 0x40, 0x4d, 0xbb, 0x67, 0xd0, 0xfe, 0x90, 0x13, 0xb5, 0x84, 0x1a, 0xa0,
-	0x76, 0x09, 0x36, 0xbb, 0x94, 0x9d, 0x6f, 0xd0, 0x32, 0x3a, 0x80, 0xfd, 0xb5, 0x10, 0x23, 0xd6,
-	0xb5, 0xc9, 0x2f, 0xd2, 0x39, 0x3d, 0x7a, 0xae, 0x56, 0xd0, 0xcf, 0xf0, 0xa3, 0xc5, 0xff, 0xb3,
-	0x8d, 0x73, 0x83, 0x32, 0xbb, 0x6f, 0x58, 0x84, 0xab, 0x35, 0xa4, 0x42, 0x9d, 0x0e, 0x8c, 0xcb,
-	0x8d, 0x84, 0x48, 0xf7, 0xca, 0x5b, 0x6c, 0xca, 0x2c, 0xc2, 0x7b, 0x06, 0x26, 0x1b, 0x76, 0x82,
-	0x7e, 0x83, 0x5f, 0xf0, 0xbf, 0x04, 0x5f, 0x7c, 0x87, 0xf4, 0xd1, 0xfe, 0xd3, 0x76, 0x7c, 0x80,
-	0x6d, 0xc2, 0xb9, 0xc9, 0xd5, 0x47, 0x05, 0x1d, 0x7e, 0x13, 0x95, 0x31, 0x78, 0x8a, 0xea, 0x93,
-	0xd2, 0x7e, 0x2c, 0x40, 0x01, 0xcb, 0x00, 0xbd, 0x53, 0xa0, 0x9c, 0xde, 0x56, 0xcf, 0x43, 0x9d,
-	0x17, 0xdc, 0xf6, 0xd5, 0x1b, 0x3a, 0xc0, 0x2f, 0xd2, 0xc8, 0x6f, 0x5c, 0x73, 0x0b, 0xbd, 0x57,
-	0x60, 0x07, 0xcf, 0x3d, 0x7c, 0x2b, 0xdc, 0xe9, 0x6b, 0xf2, 0xb5, 0x4a, 0xab, 0x2b, 0x66, 0xaf,
-	0xc8, 0x55, 0xa7, 0x7a, 0x53, 0x59, 0x71, 0xe3, 0x72, 0xf6, 0xac, 0xcf, 0xbe, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x54, 0x43, 0xba, 0x85, 0x0a, 0x06, 0x00, 0x00,
+	// 845 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x55, 0xcf, 0x6e, 0xe3, 0x44,
+	0x1c, 0x5e, 0x37, 0x4d, 0xd2, 0xfc, 0x92, 0x2d, 0xee, 0x6c, 0xb6, 0x98, 0x40, 0xa1, 0x1b, 0x2e,
+	0x15, 0x07, 0x47, 0x9b, 0x72, 0xe2, 0x00, 0x72, 0x9d, 0x49, 0x76, 0xd4, 0xd4, 0x8e, 0xa6, 0x69,
+	0x56, 0xec, 0xc5, 0x9a, 0xda, 0x53, 0x67, 0x94, 0x64, 0x6c, 0x6c, 0xa7, 0xab, 0x72, 0x43, 0xe2,
+	0x01, 0x90, 0x10, 0x07, 0x24, 0xae, 0xbc, 0x04, 0x6f, 0x00, 0x8f, 0xc1, 0x93, 0x20, 0xff, 0x4b,
+	0xbb, 0x08, 0x2e, 0xe9, 0x65, 0x6f, 0x33, 0xdf, 0x37, 0xf3, 0xfd, 0x3e, 0x7f, 0xbf, 0x99, 0x31,
+	0x7c, 0x1a, 0x2e, 0xfc, 0x1e, 0x0b, 0x45, 0xdc, 0x73, 0xa5, 0xe8, 0xdd, 0xbe, 0xbc, 0xe6, 0x09,
+	0x7b, 0x99, 0x8e, 0xf5, 0x30, 0x0a, 0x92, 0x00, 0x9d, 0xfa, 0x22, 0x99, 0xaf, 0xaf, 0x75, 0x37,
+	0x58, 0xe9, 0xb7, 0xab, 0xb7, 0x2c, 0xe2, 0x4e, 0xc2, 0xe4, 0xf7, 0x6b, 0x9d, 0xc9, 0x24, 0xe2,
+	0x4c, 0x0f, 0x17, 0xbe, 0x9e, 0x6e, 0xd7, 0xd3, 0x2d, 0xc5, 0xf6, 0xce, 0x47, 0x7e, 0x10, 0xf8,
+	0x4b, 0xde, 0xcb, 0x24, 0xae, 0xd7, 0x37, 0x3d, 0x26, 0xef, 0x72, 0xbd, 0xee, 0x1f, 0x0a, 0x80,
+	0x29, 0x85, 0xb9, 0xf2, 0x8c, 0xc8, 0x8f, 0xd1, 0x0b, 0x68, 0xb9, 0x81, 0x4c, 0x98, 0x90, 0x3c,
+	0x72, 0x84, 0xa7, 0x29, 0xc7, 0xca, 0x49, 0x83, 0x36, 0x37, 0x18, 0xf1, 0x50, 0x1b, 0xaa, 0x92,
+	0x27, 0x32, 0xd6, 0x76, 0x32, 0x2e, 0x9f, 0xa0, 0x43, 0xa8, 0x89, 0x1b, 0xc9, 0x56, 0x5c, 0xab,
+	0x64, 0x70, 0x31, 0x43, 0x08, 0x76, 0x59, 0xe4, 0xc7, 0xda, 0x6e, 0x86, 0x66, 0xe3, 0x14, 0x0b,
+	0x59, 0x32, 0xd7, 0xaa, 0x39, 0x96, 0x8e, 0xd1, 0x29, 0x3c, 0x97, 0x3c, 0x79, 0x1b, 0x44, 0x0b,
+	0xc7, 0x0d, 0xe4, 0x8d, 0xf0, 0xd7, 0x11, 0x4b, 0x44, 0x20, 0xb5, 0xda, 0xb1, 0x72, 0xd2, 0xa2,
+	0xed, 0x82, 0x34, 0x1f, 0x72, 0xdd, 0x05, 0x3c, 0xcd, 0xbd, 0x53, 0xfe, 0xdd, 0x9a, 0xc7, 0x09,
+	0x7a, 0x03, 0x7b, 0xae, 0x14, 0x4e, 0x56, 0x31, 0xb5, 0xde, 0xec, 0x7f, 0xa3, 0x6f, 0x11, 0x98,
+	0x7e, 0x9f, 0x08, 0xad, 0xbb, 0x52, 0xa4, 0x83, 0xee, 0xef, 0x0a, 0x54, 0x71, 0x14, 0x05, 0x11,
+	0xa2, 0xb0, 0xeb, 0x06, 0x1e, 0xcf, 0x2a, 0xec, 0xf7, 0xbf, 0xde, 0xaa, 0x42, 0xa6, 0x64, 0x06,
+	0x1e, 0xa7, 0x99, 0x16, 0xd2, 0xa0, 0xbe, 0xe2, 0x71, 0xcc, 0x7c, 0x5e, 0xe4, 0x5a, 0x4e, 0x91,
+	0x0e, 0x75, 0x8f, 0x27, 0x4c, 0x2c, 0x63, 0xad, 0x72, 0x5c, 0x39, 0x69, 0xf6, 0xdb, 0x7a, 0xde,
+	0x4e, 0xbd, 0x6c, 0xa7, 0x6e, 0xc8, 0x3b, 0x5a, 0x2e, 0xea, 0xfe, 0xa0, 0xc0, 0x7e, 0x99, 0x4a,
+	0x1c, 0x06, 0x32, 0xe6, 0xe8, 0x08, 0x20, 0x8d, 0x25, 0xe2, 0xf1, 0x7a, 0x99, 0x64, 0xb6, 0x5b,
+	0xb4, 0xe1, 0x4a, 0x41, 0x33, 0x00, 0x4d, 0xa0, 0xca, 0x53, 0x3b, 0x59, 0xe5, 0x66, 0xff, 0xab,
+	0xed, 0x3f, 0x88, 0xe6, 0x42, 0xdd, 0x67, 0x70, 0x60, 0x4a, 0x31, 0xe3, 0x51, 0x2c, 0x02, 0x59,
+	0x34, 0xa7, 0xfb, 0x93, 0x02, 0xe8, 0x21, 0x5a, 0x98, 0xfb, 0x1c, 0x9e, 0x32, 0x9f, 0xcb, 0xc4,
+	0xb9, 0xcd, 0x89, 0xe2, 0xcc, 0xb5, 0x32, 0xb0, 0x58, 0x8c, 0x3e, 0x83, 0x26, 0x0b, 0xc5, 0x66,
+	0x49, 0x1e, 0x11, 0xb0, 0xb0, 0x54, 0x43, 0x5f, 0xc2, 0x61, 0xbc, 0x0e, 0xc3, 0x20, 0x4a, 0xb8,
+	0xe7, 0xb8, 0x72, 0xb3, 0x34, 0x0f, 0xad, 0x41, 0xdb, 0x1b, 0xf6, 0xde, 0x42, 0xdc, 0x45, 0xa0,
+	0x9a, 0x52, 0xbc, 0xe2, 0x6c, 0x99, 0xcc, 0x4b, 0x9b, 0x23, 0x38, 0x78, 0x80, 0x15, 0x26, 0x35,
+	0xa8, 0xcf, 0x33, 0xe4, 0x2e, 0xb3, 0xb7, 0x47, 0xcb, 0xe9, 0xff, 0x37, 0xee, 0x8b, 0xbf, 0x77,
+	0xa0, 0xb1, 0x69, 0x33, 0x6a, 0x42, 0xfd, 0xca, 0x3a, 0xb7, 0xec, 0xd7, 0x96, 0xfa, 0x04, 0x7d,
+	0x02, 0x1a, 0xb1, 0x4c, 0xfb, 0x62, 0x62, 0x4c, 0xc9, 0xd9, 0x18, 0x3b, 0xa6, 0x45, 0x9c, 0x19,
+	0xa6, 0x97, 0xc4, 0xb6, 0x54, 0x05, 0x3d, 0x87, 0x83, 0x2b, 0xeb, 0xf2, 0x6a, 0x32, 0xb1, 0xe9,
+	0x14, 0x0f, 0x9c, 0x21, 0xc1, 0xe3, 0x81, 0xba, 0x93, 0xc3, 0x99, 0x82, 0x63, 0xda, 0xd6, 0xd4,
+	0x20, 0x16, 0xa6, 0x6a, 0x05, 0xbd, 0x80, 0x23, 0x62, 0xcd, 0x8c, 0x31, 0x19, 0x38, 0xd8, 0x9a,
+	0x11, 0x6a, 0x5b, 0x17, 0xd8, 0x9a, 0x3a, 0x33, 0x83, 0x12, 0xe3, 0x6c, 0x8c, 0x2f, 0xd5, 0x5d,
+	0xb4, 0x0f, 0x40, 0x6c, 0x67, 0x68, 0x90, 0xf1, 0x15, 0xc5, 0x6a, 0x15, 0xb5, 0x41, 0x1d, 0x60,
+	0xd3, 0x1e, 0x10, 0x6b, 0xb4, 0x41, 0x6b, 0xa8, 0x03, 0x87, 0xa5, 0x90, 0x85, 0xa7, 0xaf, 0x6d,
+	0x7a, 0x9e, 0xd6, 0x19, 0x92, 0x91, 0x5a, 0x47, 0xcf, 0xe0, 0x83, 0x29, 0xfd, 0xd6, 0x31, 0x46,
+	0x06, 0xb1, 0x9c, 0xb1, 0x31, 0xc5, 0x54, 0x6d, 0x22, 0x15, 0x5a, 0x64, 0x62, 0x5c, 0x6c, 0x24,
+	0x78, 0xfa, 0x5d, 0xf9, 0x16, 0x87, 0x58, 0x53, 0x4c, 0x87, 0x86, 0x89, 0x37, 0xec, 0x0d, 0xfa,
+	0x18, 0x3e, 0x34, 0x5f, 0x61, 0xf3, 0xfc, 0x3f, 0x48, 0x1f, 0x1d, 0xde, 0x7f, 0x1d, 0x9d, 0x98,
+	0x0e, 0xa6, 0xd4, 0xa6, 0xea, 0x9f, 0x0a, 0x3a, 0xfa, 0x57, 0x54, 0xc6, 0xe4, 0x3e, 0xaa, 0xbf,
+	0x94, 0xfe, 0x8f, 0x35, 0xa8, 0x98, 0x52, 0xa0, 0x9f, 0x15, 0xa8, 0xa5, 0x57, 0xd6, 0xf3, 0xd0,
+	0xd9, 0x23, 0xae, 0x7c, 0x71, 0x08, 0x3a, 0xe6, 0xa3, 0x34, 0xf2, 0x43, 0xd3, 0x7d, 0x82, 0x7e,
+	0x51, 0x60, 0xcf, 0x5c, 0x79, 0xe6, 0x9c, 0xbb, 0x8b, 0xf7, 0xc9, 0x57, 0x91, 0xd6, 0x80, 0x2f,
+	0xdf, 0x27, 0x57, 0xbf, 0x29, 0x50, 0x2f, 0xef, 0xf3, 0x70, 0x5b, 0xc9, 0x77, 0x1f, 0x9d, 0xce,
+	0xe8, 0xd1, 0x3a, 0x1b, 0x7b, 0xbf, 0x2a, 0x50, 0xcb, 0x9f, 0x05, 0x84, 0xb7, 0x55, 0x7d, 0xe7,
+	0xa9, 0xe9, 0x0c, 0x1f, 0x2b, 0x53, 0x7a, 0x3b, 0x6b, 0xbc, 0xa9, 0x17, 0xf4, 0x75, 0x2d, 0xfb,
+	0x2d, 0x9c, 0xfe, 0x33, 0x00, 0xea, 0x91, 0x1d, 0xd1, 0x4a, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -381,6 +562,8 @@ type CniClient interface {
 	CmdAdd(ctx context.Context, in *CniCmdRequest, opts ...grpc.CallOption) (*CniCmdResponse, error)
 	CmdCheck(ctx context.Context, in *CniCmdRequest, opts ...grpc.CallOption) (*CniCmdResponse, error)
 	CmdDel(ctx context.Context, in *CniCmdRequest, opts ...grpc.CallOption) (*CniCmdResponse, error)
+	Version(ctx context.Context, in *CniVersionRequest, opts ...grpc.CallOption) (*CniVersionResponse, error)
+	Health(ctx context.Context, in *CniHealthRequest, opts ...grpc.CallOption) (*CniHealthResponse, error)
 }
 
 type cniClient struct {
@@ -418,11 +601,31 @@ func (c *cniClient) CmdDel(ctx context.Context, in *CniCmdRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *cniClient) Version(ctx context.Context, in *CniVersionRequest, opts ...grpc.CallOption) (*CniVersionResponse, error) {
+	out := new(CniVersionResponse)
+	err := c.cc.Invoke(ctx, "/github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.Cni/Version", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cniClient) Health(ctx context.Context, in *CniHealthRequest, opts ...grpc.CallOption) (*CniHealthResponse, error) {
+	out := new(CniHealthResponse)
+	err := c.cc.Invoke(ctx, "/github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.Cni/Health", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CniServer is the server API for Cni service.
 type CniServer interface {
 	CmdAdd(context.Context, *CniCmdRequest) (*CniCmdResponse, error)
 	CmdCheck(context.Context, *CniCmdRequest) (*CniCmdResponse, error)
 	CmdDel(context.Context, *CniCmdRequest) (*CniCmdResponse, error)
+	Version(context.Context, *CniVersionRequest) (*CniVersionResponse, error)
+	Health(context.Context, *CniHealthRequest) (*CniHealthResponse, error)
 }
 
 // UnimplementedCniServer can be embedded to have forward compatible implementations.
@@ -438,6 +641,12 @@ func (*UnimplementedCniServer) CmdCheck(ctx context.Context, req *CniCmdRequest)
 func (*UnimplementedCniServer) CmdDel(ctx context.Context, req *CniCmdRequest) (*CniCmdResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CmdDel not implemented")
 }
+func (*UnimplementedCniServer) Version(ctx context.Context, req *CniVersionRequest) (*CniVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+func (*UnimplementedCniServer) Health(ctx context.Context, req *CniHealthRequest) (*CniHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
 
 func RegisterCniServer(s *grpc.Server, srv CniServer) {
 	s.RegisterService(&_Cni_serviceDesc, srv)
@@ -497,6 +706,42 @@ func _Cni_CmdDel_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cni_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CniVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CniServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.Cni/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CniServer).Version(ctx, req.(*CniVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cni_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CniHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CniServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.Cni/Health",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CniServer).Health(ctx, req.(*CniHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Cni_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "github.com.vmware_tanzu.antrea.pkg.apis.cni.v1beta1.Cni",
 	HandlerType: (*CniServer)(nil),
@@ -513,6 +758,14 @@ var _Cni_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CmdDel",
 			Handler:    _Cni_CmdDel_Handler,
 		},
+		{
+			MethodName: "Version",
+			Handler:    _Cni_Version_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _Cni_Health_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/apis/cni/v1beta1/cni.proto",
@@ -129,6 +129,8 @@ func normalizeGroupMember(member *GroupMember) groupMemberKey {
 		b.WriteString(member.ExternalEntity.Namespace)
 		b.WriteString(delimiter)
 		b.WriteString(member.ExternalEntity.Name)
+	} else if member.Node != nil {
+		b.WriteString(member.Node.Name)
 	} else if len(member.Endpoints) != 0 {
 		for _, ep := range member.Endpoints {
 			b.Write(ep.IP)
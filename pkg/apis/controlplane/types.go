@@ -70,6 +70,12 @@ type ExternalEntityReference struct {
 	Namespace string
 }
 
+// NodeReference represents a Node Reference.
+type NodeReference struct {
+	// The name of this Node.
+	Name string
+}
+
 // Endpoint represents an external endpoint.
 type Endpoint struct {
 	// IP is the IP address of the Endpoint.
@@ -88,6 +94,9 @@ type GroupMember struct {
 
 	// Endpoints maintains a list of EndPoints associated with this GroupMember.
 	Endpoints []Endpoint
+
+	// Node maintains the reference to the Node.
+	Node *NodeReference
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -213,6 +222,20 @@ type NetworkPolicyRule struct {
 	// action “nil” defaults to Allow action, which would be the case for rules created for
 	// K8s NetworkPolicy.
 	Action *secv1alpha1.RuleAction
+	// EnableLogging indicates whether the connections matched by the rule
+	// should be audit logged by the agents.
+	EnableLogging bool
+	// RateLimit limits the rate of the new connections matched by the rule.
+	RateLimit *RuleRateLimit
+}
+
+// RuleRateLimit limits the rate of the new connections matched by a rule on
+// each Node.
+type RuleRateLimit struct {
+	// MaxNewConnectionsPerSecond is the maximum rate of new connections.
+	MaxNewConnectionsPerSecond int32
+	// Burst is the number of new connections which can exceed the rate at once.
+	Burst int32
 }
 
 // Protocol defines network protocols supported for things like container ports.
@@ -225,26 +248,46 @@ const (
 	ProtocolUDP Protocol = "UDP"
 	// ProtocolSCTP is the SCTP protocol.
 	ProtocolSCTP Protocol = "SCTP"
+	// ProtocolICMP is the ICMP protocol.
+	ProtocolICMP Protocol = "ICMP"
+	// ProtocolIGMP is the IGMP protocol.
+	ProtocolIGMP Protocol = "IGMP"
+	// ProtocolIP is any IP protocol, identified by its number.
+	ProtocolIP Protocol = "IP"
 )
 
 // Service describes a port to allow traffic on.
 type Service struct {
-	// The protocol (TCP, UDP, or SCTP) which traffic must match. If not specified, this
-	// field defaults to TCP.
+	// The protocol (TCP, UDP, SCTP, ICMP, IGMP or IP) which traffic must match. If
+	// not specified, this field defaults to TCP.
 	// +optional
 	Protocol *Protocol
 	// The port name or number on the given protocol. If not specified, this matches all port numbers.
 	// +optional
 	Port *intstr.IntOrString
+	// The ICMP type and code to match. They can only be set when the protocol is
+	// ICMP. If not specified, this matches all ICMP types and codes.
+	// +optional
+	ICMPType *int32
+	// +optional
+	ICMPCode *int32
+	// The IANA number of the IP protocol to match. It must be set when the
+	// protocol is IP, and cannot be set otherwise.
+	// +optional
+	IPProtocol *int32
 }
 
 // NetworkPolicyPeer describes a peer of NetworkPolicyRules.
-// It could be a list of names of AddressGroups and/or a list of IPBlock.
+// It could be a list of names of AddressGroups, a list of IPBlock and/or a
+// list of FQDNs.
 type NetworkPolicyPeer struct {
 	// A list of names of AddressGroups.
 	AddressGroups []string
 	// A list of IPBlock.
 	IPBlocks []IPBlock
+	// A list of FQDNs, e.g. "*.example.com", whose resolved IP addresses are
+	// learned by the agents from DNS responses.
+	FQDNs []string
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24"). The except entry describes CIDRs that should
@@ -266,6 +309,40 @@ type NetworkPolicyList struct {
 	Items []NetworkPolicy
 }
 
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// NetworkPolicyStatus is the status of a NetworkPolicy. It's used by the antrea-agents to report the generation of the
+// NetworkPolicy they have realized to the antrea-controller.
+type NetworkPolicyStatus struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// Nodes contains statuses produced on a list of Nodes.
+	Nodes []NetworkPolicyNodeStatus
+}
+
+// NetworkPolicyNodeStatus is the status of a NetworkPolicy on a Node.
+type NetworkPolicyNodeStatus struct {
+	// The name of the Node that produces the status.
+	NodeName string
+	// The generation realized by the Node.
+	Generation int64
+	// FailedRules are the rules of the generation which the Node failed to
+	// realize. The other rules of the generation are realized.
+	FailedRules []NetworkPolicyRuleFailure
+}
+
+// NetworkPolicyRuleFailure describes a rule of a NetworkPolicy which a Node
+// failed to realize.
+type NetworkPolicyRuleFailure struct {
+	// The direction of the rule.
+	Direction Direction
+	// The priority of the rule within the NetworkPolicy, which is its index
+	// in the Ingress or Egress rules of the original policy.
+	Priority int32
+	// The error which prevented the rule from being realized.
+	Message string
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // NodeStatsSummary contains stats produced on a Node. It's used by the antrea-agents to report stats to the antrea-controller.
 type NodeStatsSummary struct {
@@ -278,6 +355,10 @@ type NodeStatsSummary struct {
 	AntreaClusterNetworkPolicies []NetworkPolicyStats
 	// The TrafficStats of Antrea NetworkPolicies collected from the Node.
 	AntreaNetworkPolicies []NetworkPolicyStats
+	// The summaries of the connections of the Pods running on the Node. Unlike the TrafficStats of the policies,
+	// they are not a delta but a snapshot of the connections tracked by the Node. They are only collected when
+	// the flow exporter runs in antrea-agent.
+	Flows []statsv1alpha1.FlowSummary
 }
 
 // NetworkPolicyStats contains the information and traffic stats of a NetworkPolicy.
@@ -286,4 +367,6 @@ type NetworkPolicyStats struct {
 	NetworkPolicy NetworkPolicyReference
 	// The stats of the NetworkPolicy.
 	TrafficStats statsv1alpha1.TrafficStats
+	// The stats of the rules of the NetworkPolicy. It is only set for Antrea-native policies.
+	RuleTrafficStats []statsv1alpha1.RuleTrafficStats
 }
@@ -24,6 +24,7 @@ import (
 
 	proto "github.com/gogo/protobuf/proto"
 	github_com_vmware_tanzu_antrea_pkg_apis_security_v1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	v1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
 
 	math "math"
 	math_bits "math/bits"
@@ -465,10 +466,38 @@ func (m *NetworkPolicyList) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NetworkPolicyList proto.InternalMessageInfo
 
+func (m *NetworkPolicyNodeStatus) Reset()      { *m = NetworkPolicyNodeStatus{} }
+func (*NetworkPolicyNodeStatus) ProtoMessage() {}
+func (*NetworkPolicyNodeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{15}
+}
+func (m *NetworkPolicyNodeStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NetworkPolicyNodeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NetworkPolicyNodeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkPolicyNodeStatus.Merge(m, src)
+}
+func (m *NetworkPolicyNodeStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *NetworkPolicyNodeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkPolicyNodeStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkPolicyNodeStatus proto.InternalMessageInfo
+
 func (m *NetworkPolicyPeer) Reset()      { *m = NetworkPolicyPeer{} }
 func (*NetworkPolicyPeer) ProtoMessage() {}
 func (*NetworkPolicyPeer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{15}
+	return fileDescriptor_345cd0a9074e5729, []int{16}
 }
 func (m *NetworkPolicyPeer) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -496,7 +525,7 @@ var xxx_messageInfo_NetworkPolicyPeer proto.InternalMessageInfo
 func (m *NetworkPolicyReference) Reset()      { *m = NetworkPolicyReference{} }
 func (*NetworkPolicyReference) ProtoMessage() {}
 func (*NetworkPolicyReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{16}
+	return fileDescriptor_345cd0a9074e5729, []int{17}
 }
 func (m *NetworkPolicyReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -524,7 +553,7 @@ var xxx_messageInfo_NetworkPolicyReference proto.InternalMessageInfo
 func (m *NetworkPolicyRule) Reset()      { *m = NetworkPolicyRule{} }
 func (*NetworkPolicyRule) ProtoMessage() {}
 func (*NetworkPolicyRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{17}
+	return fileDescriptor_345cd0a9074e5729, []int{18}
 }
 func (m *NetworkPolicyRule) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -549,10 +578,38 @@ func (m *NetworkPolicyRule) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NetworkPolicyRule proto.InternalMessageInfo
 
+func (m *NetworkPolicyRuleFailure) Reset()      { *m = NetworkPolicyRuleFailure{} }
+func (*NetworkPolicyRuleFailure) ProtoMessage() {}
+func (*NetworkPolicyRuleFailure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{19}
+}
+func (m *NetworkPolicyRuleFailure) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NetworkPolicyRuleFailure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NetworkPolicyRuleFailure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkPolicyRuleFailure.Merge(m, src)
+}
+func (m *NetworkPolicyRuleFailure) XXX_Size() int {
+	return m.Size()
+}
+func (m *NetworkPolicyRuleFailure) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkPolicyRuleFailure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkPolicyRuleFailure proto.InternalMessageInfo
+
 func (m *NetworkPolicyStats) Reset()      { *m = NetworkPolicyStats{} }
 func (*NetworkPolicyStats) ProtoMessage() {}
 func (*NetworkPolicyStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{18}
+	return fileDescriptor_345cd0a9074e5729, []int{20}
 }
 func (m *NetworkPolicyStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -577,10 +634,66 @@ func (m *NetworkPolicyStats) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NetworkPolicyStats proto.InternalMessageInfo
 
+func (m *NetworkPolicyStatus) Reset()      { *m = NetworkPolicyStatus{} }
+func (*NetworkPolicyStatus) ProtoMessage() {}
+func (*NetworkPolicyStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{21}
+}
+func (m *NetworkPolicyStatus) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NetworkPolicyStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NetworkPolicyStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetworkPolicyStatus.Merge(m, src)
+}
+func (m *NetworkPolicyStatus) XXX_Size() int {
+	return m.Size()
+}
+func (m *NetworkPolicyStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetworkPolicyStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetworkPolicyStatus proto.InternalMessageInfo
+
+func (m *NodeReference) Reset()      { *m = NodeReference{} }
+func (*NodeReference) ProtoMessage() {}
+func (*NodeReference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{22}
+}
+func (m *NodeReference) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NodeReference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NodeReference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeReference.Merge(m, src)
+}
+func (m *NodeReference) XXX_Size() int {
+	return m.Size()
+}
+func (m *NodeReference) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeReference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeReference proto.InternalMessageInfo
+
 func (m *NodeStatsSummary) Reset()      { *m = NodeStatsSummary{} }
 func (*NodeStatsSummary) ProtoMessage() {}
 func (*NodeStatsSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{19}
+	return fileDescriptor_345cd0a9074e5729, []int{23}
 }
 func (m *NodeStatsSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -608,7 +721,7 @@ var xxx_messageInfo_NodeStatsSummary proto.InternalMessageInfo
 func (m *PodReference) Reset()      { *m = PodReference{} }
 func (*PodReference) ProtoMessage() {}
 func (*PodReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{20}
+	return fileDescriptor_345cd0a9074e5729, []int{24}
 }
 func (m *PodReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -633,10 +746,38 @@ func (m *PodReference) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PodReference proto.InternalMessageInfo
 
+func (m *RuleRateLimit) Reset()      { *m = RuleRateLimit{} }
+func (*RuleRateLimit) ProtoMessage() {}
+func (*RuleRateLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_345cd0a9074e5729, []int{25}
+}
+func (m *RuleRateLimit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RuleRateLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *RuleRateLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuleRateLimit.Merge(m, src)
+}
+func (m *RuleRateLimit) XXX_Size() int {
+	return m.Size()
+}
+func (m *RuleRateLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuleRateLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuleRateLimit proto.InternalMessageInfo
+
 func (m *Service) Reset()      { *m = Service{} }
 func (*Service) ProtoMessage() {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_345cd0a9074e5729, []int{21}
+	return fileDescriptor_345cd0a9074e5729, []int{26}
 }
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -677,12 +818,17 @@ func init() {
 	proto.RegisterType((*NamedPort)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NamedPort")
 	proto.RegisterType((*NetworkPolicy)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicy")
 	proto.RegisterType((*NetworkPolicyList)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyList")
+	proto.RegisterType((*NetworkPolicyNodeStatus)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyNodeStatus")
 	proto.RegisterType((*NetworkPolicyPeer)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyPeer")
 	proto.RegisterType((*NetworkPolicyReference)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyReference")
 	proto.RegisterType((*NetworkPolicyRule)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyRule")
+	proto.RegisterType((*NetworkPolicyRuleFailure)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyRuleFailure")
 	proto.RegisterType((*NetworkPolicyStats)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyStats")
+	proto.RegisterType((*NetworkPolicyStatus)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NetworkPolicyStatus")
+	proto.RegisterType((*NodeReference)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NodeReference")
 	proto.RegisterType((*NodeStatsSummary)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.NodeStatsSummary")
 	proto.RegisterType((*PodReference)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.PodReference")
+	proto.RegisterType((*RuleRateLimit)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.RuleRateLimit")
 	proto.RegisterType((*Service)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.controlplane.v1beta1.Service")
 }
 
@@ -691,109 +837,135 @@ func init() {
 }
 
 var fileDescriptor_345cd0a9074e5729 = []byte{
-	// 1625 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xbf, 0x73, 0xdb, 0x46,
-	0x16, 0x16, 0x40, 0x52, 0x12, 0x57, 0xd4, 0xaf, 0xd5, 0xf9, 0xcc, 0xf3, 0xf9, 0x48, 0x19, 0x77,
-	0x85, 0x8a, 0x33, 0x68, 0xf9, 0x7c, 0x89, 0x0b, 0xa7, 0x10, 0x2d, 0xd9, 0xc3, 0x44, 0x96, 0x39,
-	0x2b, 0xb9, 0xc9, 0x64, 0x26, 0x81, 0x80, 0x25, 0x05, 0x0b, 0xc0, 0xc2, 0x8b, 0xa5, 0x6c, 0x65,
-	0x26, 0x99, 0x78, 0x52, 0xc5, 0x4d, 0x7e, 0x35, 0x69, 0x52, 0x66, 0x26, 0x93, 0xc9, 0x5f, 0x90,
-	0x2a, 0xe9, 0x5c, 0xba, 0x74, 0x13, 0x4e, 0x44, 0x4f, 0x3c, 0xe9, 0xd2, 0xab, 0xca, 0xec, 0x62,
-	0x41, 0x00, 0xa4, 0x68, 0x2b, 0x43, 0x4a, 0x93, 0xc2, 0x95, 0xb4, 0xbb, 0x6f, 0xdf, 0xf7, 0xed,
-	0x7b, 0x6f, 0x3f, 0x3c, 0x80, 0x60, 0xbd, 0x69, 0xb3, 0x9d, 0xd6, 0xb6, 0x6e, 0x12, 0xb7, 0xb2,
-	0xe7, 0xde, 0x37, 0x28, 0xbe, 0xc8, 0x0c, 0xef, 0xfd, 0x56, 0xc5, 0xf0, 0x18, 0xc5, 0x46, 0xc5,
-	0xdf, 0x6d, 0x56, 0x0c, 0xdf, 0x0e, 0x2a, 0x26, 0xf1, 0x18, 0x25, 0x8e, 0xef, 0x18, 0x1e, 0xae,
-	0xec, 0x2d, 0x6f, 0x63, 0x66, 0x2c, 0x57, 0x9a, 0xd8, 0xc3, 0xd4, 0x60, 0xd8, 0xd2, 0x7d, 0x4a,
-	0x18, 0x81, 0xd7, 0x62, 0x6f, 0x7a, 0xe8, 0xed, 0x5d, 0xe1, 0x4d, 0x0f, 0xbd, 0xe9, 0xfe, 0x6e,
-	0x53, 0xe7, 0xde, 0xf4, 0xa4, 0x37, 0x5d, 0x7a, 0x3b, 0x77, 0x31, 0xc1, 0xa5, 0x49, 0x9a, 0xa4,
-	0x22, 0x9c, 0x6e, 0xb7, 0x1a, 0x62, 0x24, 0x06, 0xe2, 0xbf, 0x10, 0xec, 0xdc, 0x8d, 0xe3, 0x52,
-	0x0f, 0x98, 0xc1, 0x82, 0xca, 0xde, 0xb2, 0xe1, 0xf8, 0x3b, 0xfd, 0xa4, 0xcf, 0x5d, 0xd9, 0xbd,
-	0x1a, 0xe8, 0x36, 0xe1, 0xb6, 0xae, 0x61, 0xee, 0xd8, 0x1e, 0xa6, 0xfb, 0xf1, 0x66, 0x17, 0x33,
-	0xa3, 0xb2, 0xd7, 0xbf, 0xab, 0x32, 0x68, 0x17, 0x6d, 0x79, 0xcc, 0x76, 0x71, 0xdf, 0x86, 0xd7,
-	0x5e, 0xb6, 0x21, 0x30, 0x77, 0xb0, 0x6b, 0xf4, 0xed, 0xfb, 0xdf, 0xa0, 0x7d, 0x2d, 0x66, 0x3b,
-	0x15, 0xdb, 0x63, 0x01, 0xa3, 0xbd, 0x9b, 0xb4, 0xe7, 0x2a, 0x28, 0xac, 0x58, 0x16, 0xc5, 0x41,
-	0x70, 0x93, 0x92, 0x96, 0x0f, 0xdf, 0x03, 0x93, 0xfc, 0x24, 0x96, 0xc1, 0x8c, 0xa2, 0xb2, 0xa8,
-	0x2c, 0x4d, 0x5d, 0xbe, 0xa4, 0x87, 0x8e, 0xf5, 0xa4, 0xe3, 0x38, 0x43, 0xdc, 0x5a, 0xdf, 0x5b,
-	0xd6, 0x6f, 0x6f, 0xdf, 0xc5, 0x26, 0xbb, 0x85, 0x99, 0x51, 0x85, 0x8f, 0xdb, 0xe5, 0xb1, 0x4e,
-	0xbb, 0x0c, 0xe2, 0x39, 0xd4, 0xf5, 0x0a, 0x3d, 0x90, 0xf5, 0x89, 0x15, 0x14, 0xd5, 0xc5, 0xcc,
-	0xd2, 0xd4, 0xe5, 0x75, 0x7d, 0x98, 0x52, 0xd0, 0x05, 0xe9, 0x5b, 0xd8, 0xdd, 0xc6, 0xb4, 0x4e,
-	0xac, 0x6a, 0x41, 0x22, 0x67, 0xeb, 0xc4, 0x0a, 0x90, 0xc0, 0x81, 0x1f, 0x2b, 0xa0, 0xd0, 0x8c,
-	0xcd, 0x82, 0x62, 0x46, 0x00, 0xd7, 0x46, 0x06, 0x5c, 0xfd, 0x9b, 0x44, 0x2d, 0x24, 0x26, 0x03,
-	0x94, 0x02, 0xd5, 0x0e, 0x14, 0x30, 0x97, 0x0c, 0xf4, 0xba, 0x1d, 0x30, 0xf8, 0x4e, 0x5f, 0xb0,
-	0xf5, 0xe3, 0x05, 0x9b, 0xef, 0x16, 0xa1, 0x9e, 0x93, 0xd0, 0x93, 0xd1, 0x4c, 0x22, 0xd0, 0x04,
-	0xe4, 0x6c, 0x86, 0xdd, 0x28, 0xd2, 0x6f, 0x0e, 0x77, 0xe0, 0x24, 0xf9, 0xea, 0xb4, 0x84, 0xcd,
-	0xd5, 0x38, 0x00, 0x0a, 0x71, 0xb4, 0xef, 0x72, 0x60, 0x3e, 0x69, 0x56, 0x37, 0x98, 0xb9, 0x73,
-	0x0a, 0x15, 0xf5, 0x01, 0xc8, 0x1b, 0x96, 0x85, 0xad, 0xfa, 0x49, 0x95, 0xd5, 0xbc, 0x84, 0xcf,
-	0xaf, 0x44, 0x30, 0x28, 0x46, 0xe4, 0x05, 0x36, 0x45, 0xb1, 0x4b, 0xf6, 0x24, 0x83, 0xcc, 0x09,
-	0x30, 0x58, 0x90, 0x0c, 0xa6, 0x50, 0x0c, 0x84, 0x92, 0xa8, 0xf0, 0x0b, 0x05, 0xcc, 0x0b, 0x4e,
-	0xc9, 0x22, 0x2c, 0x66, 0x47, 0x5d, 0xeb, 0xff, 0x90, 0x44, 0xe6, 0x57, 0x7a, 0xb1, 0x50, 0x3f,
-	0x3c, 0xfc, 0x4a, 0x01, 0x0b, 0x92, 0x64, 0x8a, 0x56, 0x6e, 0xd4, 0xb4, 0xfe, 0x29, 0x69, 0x2d,
-	0xa0, 0x7e, 0x34, 0x74, 0x14, 0x05, 0xed, 0x37, 0x15, 0xcc, 0xac, 0xf8, 0xbe, 0x63, 0x63, 0x6b,
-	0x8b, 0xbc, 0xd2, 0xbe, 0x93, 0xd4, 0xbe, 0x5f, 0x15, 0x00, 0xd3, 0xa1, 0x3e, 0x05, 0xf5, 0xbb,
-	0x97, 0x56, 0xbf, 0x21, 0x63, 0x9d, 0xa6, 0x3f, 0x40, 0xff, 0xbe, 0xcf, 0x81, 0x85, 0xb4, 0xe1,
-	0x2b, 0x05, 0x7c, 0xa5, 0x80, 0x7f, 0x59, 0x05, 0xfc, 0x5a, 0x01, 0x93, 0x6b, 0x9e, 0xe5, 0x13,
-	0xdb, 0x63, 0xf0, 0xdf, 0x40, 0xb5, 0x7d, 0x51, 0x9d, 0x85, 0xea, 0x42, 0xa7, 0x5d, 0x56, 0x6b,
-	0xf5, 0xc3, 0x76, 0x39, 0x5f, 0xab, 0xcb, 0x07, 0x3a, 0x52, 0x6d, 0x1f, 0x3a, 0x20, 0xe7, 0x13,
-	0xca, 0xa2, 0x12, 0xbb, 0x39, 0x1c, 0xfb, 0x0d, 0xc3, 0xe5, 0x99, 0xa3, 0x2c, 0xbe, 0x4e, 0x7c,
-	0x14, 0xa0, 0x10, 0x44, 0x73, 0xc0, 0xd9, 0xb5, 0x07, 0x0c, 0x53, 0xcf, 0x70, 0xd6, 0x3c, 0x66,
-	0xb3, 0x7d, 0x84, 0x1b, 0x98, 0x62, 0xcf, 0xc4, 0x70, 0x11, 0x64, 0x3d, 0xc3, 0xc5, 0x82, 0x6f,
-	0x3e, 0x56, 0x3e, 0xee, 0x11, 0x89, 0x15, 0x58, 0x01, 0x79, 0xfe, 0x37, 0xf0, 0x0d, 0x13, 0x17,
-	0x55, 0x61, 0xd6, 0xad, 0xe1, 0x8d, 0x68, 0x01, 0xc5, 0x36, 0xda, 0xc3, 0x0c, 0x98, 0x4a, 0x84,
-	0x07, 0x62, 0x90, 0xf1, 0x89, 0x25, 0xef, 0xeb, 0x90, 0xbd, 0x53, 0x9d, 0x58, 0x5d, 0xee, 0xd5,
-	0x89, 0x4e, 0xbb, 0x9c, 0xe1, 0x33, 0xdc, 0x3f, 0xfc, 0x5c, 0x01, 0x33, 0x38, 0x75, 0x4a, 0xc1,
-	0x76, 0xea, 0xf2, 0x9d, 0xe1, 0x20, 0x07, 0x44, 0xae, 0x0a, 0x3b, 0xed, 0xf2, 0x4c, 0xcf, 0x62,
-	0x0f, 0x01, 0x78, 0x1f, 0xe4, 0xb1, 0xac, 0x8b, 0xe8, 0x2e, 0xdf, 0x18, 0x92, 0x8d, 0x74, 0x17,
-	0xe7, 0x20, 0x9a, 0x09, 0x50, 0x8c, 0xa5, 0x3d, 0x52, 0xc1, 0x4c, 0xfa, 0xda, 0x9f, 0x56, 0x1a,
-	0xc2, 0xf2, 0x57, 0x8f, 0x59, 0xfe, 0x99, 0xd3, 0x28, 0xff, 0x9f, 0x15, 0x30, 0x51, 0xab, 0x57,
-	0x1d, 0x62, 0xee, 0x42, 0x0c, 0xb2, 0xa6, 0x6d, 0x51, 0x19, 0x86, 0xeb, 0xc3, 0x01, 0xd7, 0xea,
-	0x1b, 0x98, 0xc5, 0x97, 0xe6, 0x7a, 0x6d, 0x15, 0x21, 0xe1, 0x1e, 0xee, 0x82, 0x71, 0xfc, 0xc0,
-	0xc4, 0x3e, 0x93, 0x17, 0x7c, 0x24, 0x40, 0x33, 0x12, 0x68, 0x7c, 0x4d, 0xb8, 0x46, 0x12, 0x42,
-	0x6b, 0x80, 0x9c, 0x30, 0x38, 0x9e, 0xf4, 0x5c, 0x05, 0x05, 0x9f, 0xe2, 0x86, 0xfd, 0x60, 0x1d,
-	0x7b, 0x4d, 0xb6, 0x23, 0x52, 0x95, 0x8b, 0xbb, 0x8f, 0x7a, 0x62, 0x0d, 0xa5, 0x2c, 0xb5, 0x4f,
-	0x14, 0x90, 0xef, 0xc6, 0x9a, 0x2b, 0x07, 0x0f, 0xaf, 0x80, 0xcb, 0x25, 0x7b, 0x26, 0xca, 0x90,
-	0x58, 0xe9, 0x6a, 0x8b, 0x3a, 0x50, 0x5b, 0xae, 0x82, 0x49, 0xf1, 0xf6, 0x6c, 0x12, 0xa7, 0x98,
-	0x11, 0x56, 0xe7, 0xa3, 0x46, 0xa4, 0x2e, 0xe7, 0x0f, 0x13, 0xff, 0xa3, 0xae, 0xb5, 0xf6, 0x28,
-	0x0b, 0xa6, 0x37, 0x30, 0xbb, 0x4f, 0xe8, 0x6e, 0x9d, 0x38, 0xb6, 0xb9, 0x7f, 0x0a, 0xbd, 0x01,
-	0x03, 0x39, 0xda, 0x72, 0x70, 0x24, 0xda, 0xb7, 0x87, 0xac, 0xda, 0x24, 0x7b, 0xd4, 0x72, 0x70,
-	0x5c, 0xbd, 0x7c, 0x14, 0xa0, 0x10, 0x0c, 0xbe, 0x01, 0x66, 0x8d, 0x54, 0x2b, 0x14, 0xde, 0x9a,
-	0xbc, 0xc8, 0xf0, 0x6c, 0xba, 0x4b, 0x0a, 0x50, 0xaf, 0x2d, 0x5c, 0xe2, 0x21, 0xb6, 0x09, 0xe5,
-	0x7a, 0x98, 0x5d, 0x54, 0x96, 0x94, 0x6a, 0x21, 0x0c, 0x6f, 0x38, 0x87, 0xba, 0xab, 0xf0, 0x0a,
-	0x28, 0x30, 0x1b, 0xd3, 0x68, 0xa5, 0x98, 0x13, 0x89, 0x9d, 0xe3, 0x45, 0xb1, 0x95, 0x98, 0x47,
-	0x29, 0x2b, 0xf8, 0x50, 0x01, 0xf9, 0x80, 0xb4, 0xa8, 0x89, 0x11, 0x6e, 0x14, 0xc7, 0x45, 0xe0,
-	0xb7, 0x46, 0x19, 0x99, 0xae, 0xce, 0x4c, 0x73, 0xb5, 0xdb, 0x8c, 0xa0, 0x50, 0x8c, 0xaa, 0x3d,
-	0x53, 0xc0, 0x7c, 0x6a, 0xd3, 0x29, 0x74, 0xc5, 0x7e, 0xba, 0x2b, 0x7e, 0x6b, 0x84, 0x47, 0x1e,
-	0xd0, 0x14, 0xff, 0xd4, 0x7b, 0xca, 0x3a, 0xc6, 0x14, 0xbe, 0x0e, 0xa6, 0x8d, 0xc4, 0x97, 0x82,
-	0xa0, 0xa8, 0x88, 0xe2, 0x98, 0xef, 0xb4, 0xcb, 0xd3, 0xc9, 0x4f, 0x08, 0x01, 0x4a, 0xdb, 0xc1,
-	0x00, 0x4c, 0xda, 0xbe, 0x10, 0xc5, 0xe8, 0x0c, 0x6b, 0xc3, 0x8a, 0x94, 0xf0, 0x16, 0x47, 0x4d,
-	0x4e, 0x04, 0xa8, 0x0b, 0xa4, 0x3d, 0x57, 0xc0, 0xdf, 0x8f, 0x4e, 0x2f, 0xfc, 0x3f, 0xc8, 0xb2,
-	0x7d, 0x3f, 0xea, 0x44, 0x2e, 0x44, 0x6a, 0xb1, 0xb5, 0xef, 0xe3, 0xc3, 0x76, 0x39, 0x7d, 0x72,
-	0x3e, 0x89, 0x84, 0xf9, 0x9f, 0x6e, 0x4f, 0xba, 0xaa, 0x94, 0x19, 0xa8, 0x4a, 0x55, 0x90, 0x69,
-	0xd9, 0x96, 0xb8, 0x2d, 0xf9, 0xea, 0x25, 0x69, 0x90, 0xb9, 0x53, 0x5b, 0x3d, 0x6c, 0x97, 0x2f,
-	0x0c, 0xfa, 0x36, 0xc8, 0xc9, 0x04, 0xfa, 0x9d, 0xda, 0x2a, 0xe2, 0x9b, 0xb5, 0x1f, 0xb3, 0x3d,
-	0xc9, 0xe2, 0x77, 0x1a, 0x5e, 0x03, 0x79, 0xcb, 0xa6, 0xd8, 0x64, 0x36, 0xf1, 0xe4, 0x41, 0x4b,
-	0x11, 0xd9, 0xd5, 0x68, 0xe1, 0x30, 0x39, 0x40, 0xf1, 0x06, 0x78, 0x0f, 0x64, 0x1b, 0x94, 0xb8,
-	0xb2, 0xad, 0x19, 0xa5, 0xfc, 0xf0, 0x4a, 0x8a, 0x43, 0x71, 0x83, 0x12, 0x17, 0x09, 0x28, 0xb8,
-	0x0b, 0x54, 0x46, 0x44, 0xa8, 0x4e, 0x00, 0x10, 0x48, 0x40, 0x75, 0x8b, 0x20, 0x95, 0x11, 0x5e,
-	0x91, 0x01, 0xa6, 0x7b, 0xb6, 0x89, 0xa3, 0x97, 0x8d, 0x21, 0x2b, 0x72, 0x33, 0xf4, 0x16, 0x57,
-	0xa4, 0x9c, 0x08, 0x50, 0x17, 0x08, 0xfe, 0x37, 0xa1, 0x8f, 0x52, 0xf1, 0xe2, 0x47, 0x50, 0x9f,
-	0x46, 0xde, 0x05, 0xe3, 0x46, 0x98, 0xbd, 0x71, 0x91, 0x3d, 0xc4, 0x1f, 0xc7, 0x2b, 0x51, 0xda,
-	0x56, 0x8f, 0xfd, 0x7d, 0x1c, 0x9b, 0x2d, 0xee, 0xaf, 0xfb, 0x89, 0x5c, 0xe7, 0xe5, 0x11, 0xfa,
-	0x41, 0x12, 0x41, 0xfb, 0x56, 0x05, 0x30, 0x15, 0xb4, 0x4d, 0x66, 0xb0, 0x80, 0xf7, 0xb9, 0xd3,
-	0x5e, 0x72, 0x5a, 0x8a, 0xdb, 0xc9, 0x88, 0xee, 0x19, 0x19, 0x8c, 0xf4, 0xc3, 0x16, 0xa5, 0x19,
-	0xc0, 0x0f, 0x41, 0x81, 0x51, 0xa3, 0xd1, 0xb0, 0x4d, 0xc1, 0x51, 0x56, 0xe8, 0xea, 0xb1, 0x19,
-	0x89, 0xdf, 0x0b, 0xf4, 0x6e, 0x30, 0xb6, 0x12, 0xbe, 0xe2, 0xce, 0x24, 0x39, 0x8b, 0x52, 0x78,
-	0xda, 0xef, 0x59, 0x30, 0xb7, 0x41, 0x2c, 0x2c, 0x46, 0x9b, 0x2d, 0xd7, 0x35, 0xe8, 0x69, 0x34,
-	0x04, 0x5f, 0x2a, 0x60, 0x36, 0x19, 0x08, 0xbb, 0xdb, 0x1b, 0xd4, 0x47, 0x98, 0x8c, 0x30, 0x0c,
-	0x67, 0x25, 0x93, 0xd9, 0x8d, 0x34, 0x20, 0xea, 0x65, 0x00, 0x7f, 0x50, 0xc0, 0xf9, 0x10, 0xe5,
-	0xba, 0xd3, 0x0a, 0x18, 0xa6, 0x3d, 0x3b, 0x64, 0xd3, 0x3d, 0x7a, 0x8a, 0xff, 0x91, 0x14, 0xcf,
-	0xaf, 0xbc, 0x00, 0x1d, 0xbd, 0x90, 0x1b, 0xfc, 0x46, 0x01, 0x67, 0x42, 0x83, 0x5e, 0xd6, 0xd9,
-	0x13, 0x62, 0xfd, 0x2f, 0xc9, 0xfa, 0xcc, 0xca, 0x51, 0xb0, 0xe8, 0x68, 0x36, 0x9a, 0x01, 0x0a,
-	0xc9, 0x97, 0xa0, 0x93, 0x78, 0x8f, 0xfe, 0x54, 0x01, 0x13, 0x52, 0xb0, 0xe0, 0x95, 0x44, 0xa3,
-	0x1c, 0x42, 0x14, 0x5f, 0xde, 0x24, 0xc3, 0x0d, 0xd9, 0xa2, 0xab, 0x2f, 0xa9, 0xfe, 0x16, 0xb3,
-	0x1d, 0x3d, 0xfc, 0x5d, 0x4b, 0xaf, 0x79, 0xec, 0x36, 0xdd, 0x64, 0xd4, 0xf6, 0x9a, 0xd5, 0xc9,
-	0x74, 0x43, 0x5f, 0xbd, 0xf8, 0xf8, 0xa0, 0x34, 0xf6, 0xe4, 0xa0, 0x34, 0xf6, 0xf4, 0xa0, 0x34,
-	0xf6, 0x51, 0xa7, 0xa4, 0x3c, 0xee, 0x94, 0x94, 0x27, 0x9d, 0x92, 0xf2, 0xb4, 0x53, 0x52, 0x7e,
-	0xe9, 0x94, 0x94, 0xcf, 0x9e, 0x95, 0xc6, 0xde, 0x9e, 0x90, 0xc1, 0xfe, 0x23, 0x00, 0x00, 0xff,
-	0xff, 0x93, 0xfc, 0x68, 0xa3, 0xea, 0x1c, 0x00, 0x00,
+	// 2037 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xcd, 0x6f, 0x23, 0x49,
+	0x15, 0x9f, 0xf6, 0x47, 0x62, 0xbf, 0xd8, 0xf9, 0xa8, 0xb0, 0x8c, 0x09, 0x83, 0x3d, 0xdb, 0xcb,
+	0x21, 0x48, 0x4c, 0x7b, 0x33, 0x0c, 0x30, 0x12, 0xcb, 0x21, 0xce, 0xc7, 0x60, 0x36, 0xf1, 0x98,
+	0x4a, 0x86, 0x03, 0x42, 0x82, 0x4e, 0x77, 0xd9, 0xe9, 0x8d, 0xfb, 0x63, 0xaa, 0xcb, 0xf9, 0x58,
+	0x09, 0x04, 0xe2, 0xc4, 0x22, 0x10, 0xb0, 0x97, 0xbd, 0x70, 0xe4, 0x82, 0xb8, 0x70, 0x84, 0xbf,
+	0x60, 0x8e, 0x7b, 0x5c, 0x0e, 0x44, 0x8c, 0x57, 0xac, 0x38, 0x70, 0xe3, 0x80, 0x94, 0x13, 0xaa,
+	0xea, 0xea, 0x2f, 0x7b, 0x3c, 0x09, 0x6b, 0x27, 0xe2, 0x30, 0x27, 0xbb, 0x5f, 0xbd, 0x7a, 0xbf,
+	0x5f, 0xbd, 0xf7, 0xea, 0xd5, 0xeb, 0xb2, 0x61, 0xa7, 0x6b, 0xb1, 0xc3, 0xfe, 0x81, 0x66, 0xb8,
+	0x76, 0xfd, 0xd8, 0x3e, 0xd1, 0x29, 0xb9, 0xc7, 0x74, 0xe7, 0xdd, 0x7e, 0x5d, 0x77, 0x18, 0x25,
+	0x7a, 0xdd, 0x3b, 0xea, 0xd6, 0x75, 0xcf, 0xf2, 0xeb, 0x86, 0xeb, 0x30, 0xea, 0xf6, 0xbc, 0x9e,
+	0xee, 0x90, 0xfa, 0xf1, 0xda, 0x01, 0x61, 0xfa, 0x5a, 0xbd, 0x4b, 0x1c, 0x42, 0x75, 0x46, 0x4c,
+	0xcd, 0xa3, 0x2e, 0x73, 0xd1, 0x5b, 0xb1, 0x35, 0x2d, 0xb0, 0xf6, 0x03, 0x61, 0x4d, 0x0b, 0xac,
+	0x69, 0xde, 0x51, 0x57, 0xe3, 0xd6, 0xb4, 0xa4, 0x35, 0x4d, 0x5a, 0x5b, 0xb9, 0x97, 0xe0, 0xd2,
+	0x75, 0xbb, 0x6e, 0x5d, 0x18, 0x3d, 0xe8, 0x77, 0xc4, 0x93, 0x78, 0x10, 0xdf, 0x02, 0xb0, 0x95,
+	0xed, 0xab, 0x52, 0xf7, 0x99, 0xce, 0xfc, 0xfa, 0xf1, 0x9a, 0xde, 0xf3, 0x0e, 0x47, 0x49, 0xaf,
+	0x3c, 0x38, 0x7a, 0xe8, 0x6b, 0x96, 0xcb, 0x75, 0x6d, 0xdd, 0x38, 0xb4, 0x1c, 0x42, 0xcf, 0xe2,
+	0xc9, 0x36, 0x61, 0x7a, 0xfd, 0x78, 0x74, 0x56, 0x7d, 0xdc, 0x2c, 0xda, 0x77, 0x98, 0x65, 0x93,
+	0x91, 0x09, 0x5f, 0xbb, 0x6c, 0x82, 0x6f, 0x1c, 0x12, 0x5b, 0x1f, 0x99, 0xf7, 0x95, 0x71, 0xf3,
+	0xfa, 0xcc, 0xea, 0xd5, 0x2d, 0x87, 0xf9, 0x8c, 0x0e, 0x4f, 0x52, 0x3f, 0xc9, 0x40, 0x69, 0xdd,
+	0x34, 0x29, 0xf1, 0xfd, 0x47, 0xd4, 0xed, 0x7b, 0xe8, 0x87, 0x50, 0xe0, 0x2b, 0x31, 0x75, 0xa6,
+	0x57, 0x94, 0xbb, 0xca, 0xea, 0xdc, 0xfd, 0x37, 0xb5, 0xc0, 0xb0, 0x96, 0x34, 0x1c, 0x47, 0x88,
+	0x6b, 0x6b, 0xc7, 0x6b, 0xda, 0xe3, 0x83, 0x77, 0x88, 0xc1, 0x76, 0x09, 0xd3, 0x1b, 0xe8, 0xd9,
+	0x79, 0xed, 0xd6, 0xe0, 0xbc, 0x06, 0xb1, 0x0c, 0x47, 0x56, 0x91, 0x03, 0x39, 0xcf, 0x35, 0xfd,
+	0x4a, 0xe6, 0x6e, 0x76, 0x75, 0xee, 0xfe, 0x8e, 0x36, 0x49, 0x2a, 0x68, 0x82, 0xf4, 0x2e, 0xb1,
+	0x0f, 0x08, 0x6d, 0xbb, 0x66, 0xa3, 0x24, 0x91, 0x73, 0x6d, 0xd7, 0xf4, 0xb1, 0xc0, 0x41, 0x3f,
+	0x53, 0xa0, 0xd4, 0x8d, 0xd5, 0xfc, 0x4a, 0x56, 0x00, 0x37, 0xa7, 0x06, 0xdc, 0xf8, 0x8c, 0x44,
+	0x2d, 0x25, 0x84, 0x3e, 0x4e, 0x81, 0xaa, 0xcf, 0x15, 0x58, 0x4c, 0x3a, 0x7a, 0xc7, 0xf2, 0x19,
+	0xfa, 0xfe, 0x88, 0xb3, 0xb5, 0xab, 0x39, 0x9b, 0xcf, 0x16, 0xae, 0x5e, 0x94, 0xd0, 0x85, 0x50,
+	0x92, 0x70, 0xb4, 0x0b, 0x79, 0x8b, 0x11, 0x3b, 0xf4, 0xf4, 0xb7, 0x27, 0x5b, 0x70, 0x92, 0x7c,
+	0xa3, 0x2c, 0x61, 0xf3, 0x4d, 0x0e, 0x80, 0x03, 0x1c, 0xf5, 0x0f, 0x79, 0x58, 0x4a, 0xaa, 0xb5,
+	0x75, 0x66, 0x1c, 0xde, 0x40, 0x46, 0xfd, 0x08, 0x8a, 0xba, 0x69, 0x12, 0xb3, 0x7d, 0x5d, 0x69,
+	0xb5, 0x24, 0xe1, 0x8b, 0xeb, 0x21, 0x0c, 0x8e, 0x11, 0x79, 0x82, 0xcd, 0x51, 0x62, 0xbb, 0xc7,
+	0x92, 0x41, 0xf6, 0x1a, 0x18, 0x2c, 0x4b, 0x06, 0x73, 0x38, 0x06, 0xc2, 0x49, 0x54, 0xf4, 0x5b,
+	0x05, 0x96, 0x04, 0xa7, 0x64, 0x12, 0x56, 0x72, 0xd3, 0xce, 0xf5, 0xcf, 0x49, 0x22, 0x4b, 0xeb,
+	0xc3, 0x58, 0x78, 0x14, 0x1e, 0x7d, 0xa0, 0xc0, 0xb2, 0x24, 0x99, 0xa2, 0x95, 0x9f, 0x36, 0xad,
+	0xcf, 0x4b, 0x5a, 0xcb, 0x78, 0x14, 0x0d, 0xbf, 0x88, 0x82, 0xfa, 0xcf, 0x0c, 0xcc, 0xaf, 0x7b,
+	0x5e, 0xcf, 0x22, 0xe6, 0xbe, 0xfb, 0xaa, 0xf6, 0x5d, 0x67, 0xed, 0xfb, 0x87, 0x02, 0x28, 0xed,
+	0xea, 0x1b, 0xa8, 0x7e, 0x4f, 0xd3, 0xd5, 0x6f, 0x42, 0x5f, 0xa7, 0xe9, 0x8f, 0xa9, 0x7f, 0x7f,
+	0xcc, 0xc3, 0x72, 0x5a, 0xf1, 0x55, 0x05, 0x7c, 0x55, 0x01, 0xff, 0x6f, 0x2b, 0xe0, 0xef, 0x14,
+	0x28, 0x6c, 0x39, 0xa6, 0xe7, 0x5a, 0x0e, 0x43, 0x6f, 0x40, 0xc6, 0xf2, 0x44, 0x76, 0x96, 0x1a,
+	0xcb, 0x83, 0xf3, 0x5a, 0xa6, 0xd9, 0xbe, 0x38, 0xaf, 0x15, 0x9b, 0x6d, 0x79, 0xa0, 0xe3, 0x8c,
+	0xe5, 0xa1, 0x1e, 0xe4, 0x3d, 0x97, 0xb2, 0x30, 0xc5, 0x1e, 0x4d, 0xc6, 0xbe, 0xa5, 0xdb, 0x3c,
+	0x72, 0x94, 0xc5, 0xdb, 0x89, 0x3f, 0xf9, 0x38, 0x00, 0x51, 0x7b, 0x70, 0x7b, 0xeb, 0x94, 0x11,
+	0xea, 0xe8, 0xbd, 0x2d, 0x87, 0x59, 0xec, 0x0c, 0x93, 0x0e, 0xa1, 0xc4, 0x31, 0x08, 0xba, 0x0b,
+	0x39, 0x47, 0xb7, 0x89, 0xe0, 0x5b, 0x8c, 0x2b, 0x1f, 0xb7, 0x88, 0xc5, 0x08, 0xaa, 0x43, 0x91,
+	0x7f, 0xfa, 0x9e, 0x6e, 0x90, 0x4a, 0x46, 0xa8, 0x45, 0x39, 0xdc, 0x0a, 0x07, 0x70, 0xac, 0xa3,
+	0xfe, 0x2b, 0x0b, 0x73, 0x09, 0xf7, 0x20, 0x02, 0x59, 0xcf, 0x35, 0xe5, 0x7e, 0x9d, 0xb0, 0x77,
+	0x6a, 0xbb, 0x66, 0xc4, 0xbd, 0x31, 0x3b, 0x38, 0xaf, 0x65, 0xb9, 0x84, 0xdb, 0x47, 0xbf, 0x51,
+	0x60, 0x9e, 0xa4, 0x56, 0x29, 0xd8, 0xce, 0xdd, 0x7f, 0x32, 0x19, 0xe4, 0x18, 0xcf, 0x35, 0xd0,
+	0xe0, 0xbc, 0x36, 0x3f, 0x34, 0x38, 0x44, 0x00, 0x9d, 0x40, 0x91, 0xc8, 0xbc, 0x08, 0xf7, 0xf2,
+	0xf6, 0x84, 0x6c, 0xa4, 0xb9, 0x38, 0x06, 0xa1, 0xc4, 0xc7, 0x31, 0x16, 0xb2, 0x20, 0xe7, 0xb8,
+	0x26, 0xa9, 0xe4, 0x84, 0x07, 0xde, 0x9e, 0x30, 0xbd, 0x5c, 0x93, 0xc4, 0xeb, 0x2e, 0x88, 0xfc,
+	0xe0, 0x22, 0x01, 0xa1, 0xbe, 0x97, 0x81, 0xf9, 0x74, 0x85, 0xb9, 0xa9, 0x88, 0x07, 0x3b, 0x2d,
+	0x73, 0xc5, 0x9d, 0x96, 0xbd, 0x89, 0x9d, 0xf6, 0x37, 0x05, 0x66, 0x9b, 0xed, 0x46, 0xcf, 0x35,
+	0x8e, 0x10, 0x81, 0x9c, 0x61, 0x99, 0x54, 0xba, 0x61, 0x63, 0x32, 0xe0, 0x66, 0xbb, 0x45, 0x58,
+	0xbc, 0x3f, 0x37, 0x9a, 0x9b, 0x18, 0x0b, 0xf3, 0xe8, 0x08, 0x66, 0xc8, 0xa9, 0x41, 0x3c, 0x26,
+	0x6b, 0xc9, 0x54, 0x80, 0xe6, 0x25, 0xd0, 0xcc, 0x96, 0x30, 0x8d, 0x25, 0x84, 0xda, 0x81, 0xbc,
+	0x50, 0xb8, 0x5a, 0x95, 0x7b, 0x08, 0x25, 0x8f, 0x92, 0x8e, 0x75, 0xba, 0x43, 0x9c, 0x2e, 0x3b,
+	0x14, 0xa1, 0xca, 0xc7, 0x8d, 0x4e, 0x3b, 0x31, 0x86, 0x53, 0x9a, 0xea, 0xcf, 0x15, 0x28, 0x46,
+	0xbe, 0xe6, 0x45, 0x8a, 0xbb, 0x57, 0xc0, 0xe5, 0x93, 0xed, 0x19, 0x65, 0x38, 0xe7, 0x49, 0x0d,
+	0x51, 0xc6, 0x32, 0x63, 0xcb, 0xd8, 0x43, 0x28, 0x88, 0x17, 0x75, 0xc3, 0xed, 0x55, 0xb2, 0x42,
+	0xeb, 0x4e, 0xd8, 0xf3, 0xb4, 0xa5, 0xfc, 0x22, 0xf1, 0x1d, 0x47, 0xda, 0xea, 0x7b, 0x39, 0x28,
+	0xb7, 0x08, 0x3b, 0x71, 0xe9, 0x51, 0xdb, 0xed, 0x59, 0xc6, 0xd9, 0x0d, 0xb4, 0x21, 0x0c, 0xf2,
+	0xb4, 0xdf, 0x23, 0xe1, 0xf9, 0xf0, 0x78, 0xc2, 0xac, 0x4d, 0xb2, 0xc7, 0xfd, 0x1e, 0x89, 0xb3,
+	0x97, 0x3f, 0xf9, 0x38, 0x00, 0x43, 0xdf, 0x84, 0x05, 0x3d, 0xd5, 0x75, 0x05, 0xbb, 0xa6, 0x28,
+	0x22, 0xbc, 0x90, 0x6e, 0xc8, 0x7c, 0x3c, 0xac, 0x8b, 0x56, 0xb9, 0x8b, 0x2d, 0x97, 0xf2, 0xd2,
+	0xcb, 0x0b, 0x8f, 0xd2, 0x28, 0x05, 0xee, 0x0d, 0x64, 0x38, 0x1a, 0x45, 0x0f, 0xa0, 0xc4, 0x2c,
+	0x42, 0xc3, 0x91, 0x4a, 0x5e, 0x04, 0x76, 0x91, 0x27, 0xc5, 0x7e, 0x42, 0x8e, 0x53, 0x5a, 0xe8,
+	0xa7, 0x0a, 0x14, 0x7d, 0xb7, 0x4f, 0x0d, 0x5e, 0x8d, 0x2a, 0x33, 0xc2, 0xf1, 0xfb, 0xd3, 0xf4,
+	0x4c, 0x54, 0x67, 0xca, 0xbc, 0xb0, 0xee, 0x85, 0x50, 0x38, 0x46, 0x55, 0x3f, 0x56, 0x60, 0x29,
+	0x35, 0xe9, 0x06, 0x1a, 0x70, 0x2f, 0xdd, 0x80, 0xbf, 0x3d, 0xc5, 0x25, 0x8f, 0xe9, 0xbf, 0x7f,
+	0x91, 0x81, 0xdb, 0x29, 0x3d, 0x5e, 0xef, 0xf7, 0x98, 0xce, 0xfa, 0x3e, 0xfa, 0x32, 0x14, 0x78,
+	0xdd, 0x6f, 0xc5, 0x5d, 0x43, 0xc4, 0xbd, 0x25, 0xe5, 0x38, 0xd2, 0x40, 0xf7, 0x01, 0xe4, 0x4d,
+	0x99, 0xe5, 0x3a, 0x62, 0x7b, 0x66, 0xe3, 0xd4, 0x7f, 0x14, 0x8d, 0xe0, 0x84, 0x16, 0xfa, 0xa5,
+	0x02, 0x73, 0x1d, 0xdd, 0xea, 0x11, 0x53, 0x64, 0xa7, 0xac, 0xdc, 0xdf, 0x9d, 0xf2, 0x1e, 0xd8,
+	0xd6, 0xad, 0x5e, 0x9f, 0x92, 0xb8, 0x1d, 0xde, 0x8e, 0x21, 0x71, 0x12, 0x5f, 0x1d, 0x0c, 0xc7,
+	0xbc, 0x4d, 0x08, 0x45, 0x5f, 0x87, 0xb2, 0x9e, 0xb8, 0xa2, 0xf1, 0x2b, 0x8a, 0xd8, 0x2a, 0x4b,
+	0x83, 0xf3, 0x5a, 0x39, 0x79, 0x77, 0xe3, 0xe3, 0xb4, 0x1e, 0xf2, 0xa1, 0x60, 0x79, 0xe2, 0x88,
+	0x08, 0x23, 0xba, 0x35, 0x69, 0xc9, 0x16, 0xd6, 0xe2, 0x38, 0x48, 0x81, 0x8f, 0x23, 0x20, 0x54,
+	0x83, 0x7c, 0xe7, 0xa9, 0xe9, 0x84, 0x1b, 0xba, 0xc8, 0x43, 0xbe, 0xfd, 0x9d, 0xcd, 0x96, 0x8f,
+	0x03, 0xb9, 0xfa, 0x89, 0x02, 0x9f, 0x7d, 0xf1, 0x6e, 0x40, 0x5f, 0x85, 0x1c, 0x3b, 0xf3, 0xc2,
+	0x68, 0xbf, 0x1e, 0x16, 0xd7, 0xfd, 0x33, 0x8f, 0x5c, 0x9c, 0xd7, 0xd2, 0xae, 0xe1, 0x42, 0x2c,
+	0xd4, 0xff, 0xe7, 0xc6, 0x31, 0x2a, 0xe2, 0xd9, 0xb1, 0x45, 0xbc, 0x01, 0xd9, 0xbe, 0x65, 0x8a,
+	0xe2, 0x52, 0x6c, 0xbc, 0x29, 0x15, 0xb2, 0x4f, 0x9a, 0x9b, 0x17, 0xe7, 0xb5, 0xd7, 0xc7, 0xdd,
+	0xda, 0x72, 0x32, 0xbe, 0xf6, 0xa4, 0xb9, 0x89, 0xf9, 0x64, 0xf5, 0x3f, 0xf9, 0xa1, 0x68, 0xf2,
+	0x20, 0xa3, 0xb7, 0xa0, 0x68, 0x5a, 0x94, 0x18, 0x22, 0x4d, 0x83, 0x85, 0x56, 0x43, 0xb2, 0x9b,
+	0xe1, 0xc0, 0x45, 0xf2, 0x01, 0xc7, 0x13, 0xd0, 0x53, 0xc8, 0x75, 0xa8, 0x6b, 0xcb, 0x86, 0x73,
+	0x9a, 0xd5, 0x9a, 0xa7, 0x5a, 0xec, 0x8a, 0x6d, 0xea, 0xda, 0x58, 0x40, 0xa1, 0x23, 0xc8, 0x30,
+	0xb7, 0x92, 0xbd, 0x1e, 0x40, 0x90, 0x80, 0x99, 0x7d, 0x17, 0x67, 0x98, 0xcb, 0x53, 0xd6, 0x27,
+	0xf4, 0xd8, 0x32, 0x48, 0xf8, 0x1a, 0x38, 0x61, 0xca, 0xee, 0x05, 0xd6, 0xe2, 0x94, 0x95, 0x02,
+	0x1f, 0x47, 0x40, 0xbc, 0xd0, 0x78, 0x43, 0x07, 0x44, 0x7c, 0x62, 0x8f, 0x1c, 0x29, 0xef, 0xc0,
+	0x8c, 0x1e, 0x44, 0x6f, 0x46, 0x44, 0x0f, 0xf3, 0xee, 0x65, 0x3d, 0x0c, 0xdb, 0xe6, 0x95, 0x7f,
+	0xb9, 0x20, 0x46, 0x9f, 0xdb, 0x8b, 0x7e, 0xbc, 0xd0, 0x78, 0x7a, 0x04, 0x76, 0xb0, 0x44, 0x40,
+	0xdf, 0x80, 0x32, 0x71, 0xf4, 0x83, 0x1e, 0xd9, 0x71, 0xbb, 0x5d, 0xcb, 0xe9, 0x56, 0x66, 0xef,
+	0x2a, 0xab, 0x85, 0xc6, 0x6b, 0x92, 0x5e, 0x79, 0x2b, 0x39, 0x88, 0xd3, 0xba, 0xe8, 0x14, 0x8a,
+	0x54, 0x67, 0x64, 0xc7, 0xb2, 0x2d, 0x56, 0x29, 0x4c, 0xa3, 0x3f, 0xe7, 0x0c, 0x71, 0x68, 0x32,
+	0x38, 0xbb, 0xa2, 0x47, 0x1c, 0x83, 0xa9, 0x7f, 0x52, 0xa0, 0x32, 0xae, 0x0c, 0x4e, 0xb8, 0x01,
+	0x92, 0xb1, 0xca, 0x5c, 0x1a, 0xab, 0x2f, 0xc1, 0xac, 0x4d, 0x7c, 0x5f, 0xef, 0x86, 0x7b, 0x7d,
+	0x41, 0x2a, 0xcf, 0xee, 0x06, 0x62, 0x1c, 0x8e, 0xab, 0x7f, 0xce, 0x02, 0x4a, 0x71, 0xe6, 0xa7,
+	0x90, 0xcf, 0x5f, 0xf6, 0xca, 0x4e, 0x52, 0x5c, 0x51, 0xae, 0xb1, 0x1d, 0x88, 0x02, 0x9b, 0x1e,
+	0x4f, 0x33, 0x40, 0x3f, 0x86, 0x12, 0xa3, 0x7a, 0xa7, 0x63, 0x19, 0x82, 0xa3, 0x2c, 0x06, 0x9b,
+	0x57, 0x66, 0x24, 0x7e, 0x34, 0xd3, 0xa2, 0xbc, 0xdb, 0x4f, 0xd8, 0x8a, 0x7b, 0xe6, 0xa4, 0x14,
+	0xa7, 0xf0, 0xd0, 0xaf, 0x14, 0x58, 0xe4, 0x7d, 0x5c, 0x52, 0x45, 0x9e, 0x9d, 0xdf, 0xfa, 0xb4,
+	0x24, 0xf0, 0x90, 0xbd, 0x46, 0x45, 0x12, 0x59, 0x1c, 0x1e, 0xc1, 0x23, 0xd8, 0xea, 0xbf, 0x15,
+	0x58, 0x1e, 0x89, 0x5d, 0xdf, 0xbf, 0x81, 0xf6, 0xf9, 0x5d, 0xc8, 0xf3, 0x0e, 0x24, 0x3c, 0x5f,
+	0x9f, 0x4c, 0x31, 0x2b, 0xe2, 0x4e, 0x28, 0xee, 0x9d, 0xb8, 0xcc, 0xc7, 0x01, 0xa4, 0xba, 0x06,
+	0xe5, 0xd4, 0x0b, 0xf3, 0xe5, 0x57, 0x2c, 0xea, 0x5f, 0xf3, 0xb0, 0x18, 0xda, 0xf5, 0xf7, 0xfa,
+	0xb6, 0xad, 0xd3, 0x9b, 0x78, 0xc9, 0x78, 0x5f, 0x81, 0x85, 0x64, 0x0a, 0x5b, 0x91, 0xc3, 0xda,
+	0x53, 0x74, 0x58, 0x90, 0x37, 0xb7, 0x25, 0x93, 0x85, 0x56, 0x1a, 0x10, 0x0f, 0x33, 0x40, 0x7f,
+	0x51, 0xe0, 0x4e, 0x80, 0xb2, 0xd1, 0xeb, 0xfb, 0x8c, 0xd0, 0xa1, 0x19, 0x95, 0xec, 0x35, 0x51,
+	0xfc, 0xa2, 0xa4, 0x78, 0x67, 0xfd, 0x25, 0xe8, 0xf8, 0xa5, 0xdc, 0xd0, 0xef, 0x15, 0x78, 0x2d,
+	0x50, 0x18, 0x66, 0x9d, 0xbb, 0x26, 0xd6, 0x5f, 0x90, 0xac, 0x5f, 0x5b, 0x7f, 0x11, 0x2c, 0x7e,
+	0x31, 0x1b, 0x74, 0x08, 0xf9, 0x4e, 0xcf, 0x3d, 0x09, 0x6f, 0x4f, 0x37, 0x3e, 0x6d, 0x7d, 0xd8,
+	0xee, 0xb9, 0x27, 0x32, 0x61, 0xe3, 0xed, 0xc0, 0x85, 0xbc, 0xaf, 0xe4, 0x1f, 0xaa, 0x0e, 0xa5,
+	0xe4, 0x15, 0xce, 0x75, 0x5c, 0x38, 0x7e, 0xa0, 0x40, 0x39, 0x75, 0x06, 0xa2, 0x03, 0x58, 0xb1,
+	0xf5, 0xd3, 0x16, 0x39, 0xd9, 0x70, 0x1d, 0x27, 0x38, 0xa2, 0xfc, 0x36, 0xa1, 0x7b, 0xc4, 0x70,
+	0x1d, 0x53, 0x5e, 0x23, 0xa8, 0xd2, 0xe6, 0xca, 0xee, 0x58, 0x4d, 0xfc, 0x12, 0x2b, 0xe8, 0x0d,
+	0xc8, 0x1f, 0xf4, 0xa9, 0xcf, 0xe4, 0x79, 0x17, 0xad, 0xbe, 0xc1, 0x85, 0x38, 0x18, 0x53, 0xdf,
+	0xcf, 0xc0, 0xac, 0x6c, 0x6d, 0xd0, 0x83, 0xc4, 0x0d, 0x44, 0xb0, 0xfa, 0xca, 0xe5, 0xb7, 0x0f,
+	0xa8, 0x25, 0xef, 0x3e, 0x32, 0x97, 0x94, 0x00, 0xfe, 0xdf, 0x04, 0x2d, 0xf8, 0x6f, 0x82, 0xd6,
+	0x74, 0xd8, 0x63, 0xba, 0xc7, 0xa8, 0xe5, 0x74, 0x1b, 0x85, 0xa1, 0x9b, 0x92, 0x55, 0x28, 0x58,
+	0x86, 0xed, 0xf1, 0x3e, 0x5d, 0x1c, 0xbe, 0xf9, 0xe0, 0x25, 0xbd, 0xb9, 0xb1, 0xdb, 0xe6, 0x32,
+	0x1c, 0x8d, 0x86, 0x9a, 0x1b, 0xe1, 0x3d, 0x62, 0x42, 0x93, 0xcb, 0x70, 0x34, 0x8a, 0x34, 0x00,
+	0xcb, 0x0b, 0xb9, 0xcb, 0x5e, 0x6d, 0x9e, 0x97, 0x9d, 0x66, 0x3b, 0x5a, 0x51, 0x42, 0xa3, 0x71,
+	0xef, 0xd9, 0xf3, 0xea, 0xad, 0x0f, 0x9f, 0x57, 0x6f, 0x7d, 0xf4, 0xbc, 0x7a, 0xeb, 0x27, 0x83,
+	0xaa, 0xf2, 0x6c, 0x50, 0x55, 0x3e, 0x1c, 0x54, 0x95, 0x8f, 0x06, 0x55, 0xe5, 0xef, 0x83, 0xaa,
+	0xf2, 0xeb, 0x8f, 0xab, 0xb7, 0xbe, 0x37, 0x2b, 0xb3, 0xfe, 0xbf, 0x03, 0x00, 0x3f, 0x7f, 0xb8,
+	0x2d, 0x32, 0x23, 0x00, 0x00,
 }
 
 func (m *AddressGroup) Marshal() (dAtA []byte, err error) {
@@ -1287,6 +1459,18 @@ func (m *GroupMember) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Node != nil {
+		{
+			size, err := m.Node.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
 	if len(m.Endpoints) > 0 {
 		for iNdEx := len(m.Endpoints) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1626,6 +1810,51 @@ func (m *NetworkPolicyList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NetworkPolicyNodeStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetworkPolicyNodeStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NetworkPolicyNodeStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.FailedRules) > 0 {
+		for iNdEx := len(m.FailedRules) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.FailedRules[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Generation))
+	i--
+	dAtA[i] = 0x10
+	i -= len(m.NodeName)
+	copy(dAtA[i:], m.NodeName)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.NodeName)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *NetworkPolicyPeer) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1646,6 +1875,15 @@ func (m *NetworkPolicyPeer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.FQDNs) > 0 {
+		for iNdEx := len(m.FQDNs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.FQDNs[iNdEx])
+			copy(dAtA[i:], m.FQDNs[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.FQDNs[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.IPBlocks) > 0 {
 		for iNdEx := len(m.IPBlocks) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1735,6 +1973,26 @@ func (m *NetworkPolicyRule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.RateLimit != nil {
+		{
+			size, err := m.RateLimit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	i--
+	if m.EnableLogging {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x38
 	if m.Action != nil {
 		i -= len(*m.Action)
 		copy(dAtA[i:], *m.Action)
@@ -1787,6 +2045,42 @@ func (m *NetworkPolicyRule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NetworkPolicyRuleFailure) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetworkPolicyRuleFailure) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NetworkPolicyRuleFailure) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.Message)
+	copy(dAtA[i:], m.Message)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Message)))
+	i--
+	dAtA[i] = 0x1a
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Priority))
+	i--
+	dAtA[i] = 0x10
+	i -= len(m.Direction)
+	copy(dAtA[i:], m.Direction)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Direction)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *NetworkPolicyStats) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1807,6 +2101,20 @@ func (m *NetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.RuleTrafficStats) > 0 {
+		for iNdEx := len(m.RuleTrafficStats) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RuleTrafficStats[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	{
 		size, err := m.TrafficStats.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -1830,7 +2138,7 @@ func (m *NetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *NodeStatsSummary) Marshal() (dAtA []byte, err error) {
+func (m *NetworkPolicyStatus) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1840,48 +2148,20 @@ func (m *NodeStatsSummary) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *NodeStatsSummary) MarshalTo(dAtA []byte) (int, error) {
+func (m *NetworkPolicyStatus) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *NodeStatsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NetworkPolicyStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.AntreaNetworkPolicies) > 0 {
-		for iNdEx := len(m.AntreaNetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.AntreaNetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintGenerated(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if len(m.AntreaClusterNetworkPolicies) > 0 {
-		for iNdEx := len(m.AntreaClusterNetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.AntreaClusterNetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintGenerated(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if len(m.NetworkPolicies) > 0 {
-		for iNdEx := len(m.NetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Nodes) > 0 {
+		for iNdEx := len(m.Nodes) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.NetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Nodes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -1905,7 +2185,7 @@ func (m *NodeStatsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *PodReference) Marshal() (dAtA []byte, err error) {
+func (m *NodeReference) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1915,21 +2195,16 @@ func (m *PodReference) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *PodReference) MarshalTo(dAtA []byte) (int, error) {
+func (m *NodeReference) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *PodReference) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NodeReference) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i -= len(m.Namespace)
-	copy(dAtA[i:], m.Namespace)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
-	i--
-	dAtA[i] = 0x12
 	i -= len(m.Name)
 	copy(dAtA[i:], m.Name)
 	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
@@ -1938,7 +2213,7 @@ func (m *PodReference) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *Service) Marshal() (dAtA []byte, err error) {
+func (m *NodeStatsSummary) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1948,24 +2223,190 @@ func (m *Service) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Service) MarshalTo(dAtA []byte) (int, error) {
+func (m *NodeStatsSummary) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Service) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NodeStatsSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Port != nil {
-		{
-			size, err := m.Port.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
+	if len(m.Flows) > 0 {
+		for iNdEx := len(m.Flows) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Flows[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
 			}
-			i -= size
-			i = encodeVarintGenerated(dAtA, i, uint64(size))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.AntreaNetworkPolicies) > 0 {
+		for iNdEx := len(m.AntreaNetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.AntreaNetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.AntreaClusterNetworkPolicies) > 0 {
+		for iNdEx := len(m.AntreaClusterNetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.AntreaClusterNetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.NetworkPolicies) > 0 {
+		for iNdEx := len(m.NetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.NetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	{
+		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *PodReference) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PodReference) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PodReference) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.Namespace)
+	copy(dAtA[i:], m.Namespace)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *RuleRateLimit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RuleRateLimit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RuleRateLimit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Burst))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxNewConnectionsPerSecond))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
+}
+
+func (m *Service) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Service) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Service) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.IPProtocol != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.IPProtocol))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.ICMPCode != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.ICMPCode))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.ICMPType != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.ICMPType))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Port != nil {
+		{
+			size, err := m.Port.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
 		}
 		i--
 		dAtA[i] = 0x12
@@ -2193,6 +2634,10 @@ func (m *GroupMember) Size() (n int) {
 			n += 1 + l + sovGenerated(uint64(l))
 		}
 	}
+	if m.Node != nil {
+		l = m.Node.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
 	return n
 }
 
@@ -2314,6 +2759,24 @@ func (m *NetworkPolicyList) Size() (n int) {
 	return n
 }
 
+func (m *NetworkPolicyNodeStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.NodeName)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.Generation))
+	if len(m.FailedRules) > 0 {
+		for _, e := range m.FailedRules {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *NetworkPolicyPeer) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2332,6 +2795,12 @@ func (m *NetworkPolicyPeer) Size() (n int) {
 			n += 1 + l + sovGenerated(uint64(l))
 		}
 	}
+	if len(m.FQDNs) > 0 {
+		for _, s := range m.FQDNs {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2375,6 +2844,25 @@ func (m *NetworkPolicyRule) Size() (n int) {
 		l = len(*m.Action)
 		n += 1 + l + sovGenerated(uint64(l))
 	}
+	n += 2
+	if m.RateLimit != nil {
+		l = m.RateLimit.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *NetworkPolicyRuleFailure) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Direction)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.Priority))
+	l = len(m.Message)
+	n += 1 + l + sovGenerated(uint64(l))
 	return n
 }
 
@@ -2388,6 +2876,40 @@ func (m *NetworkPolicyStats) Size() (n int) {
 	n += 1 + l + sovGenerated(uint64(l))
 	l = m.TrafficStats.Size()
 	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.RuleTrafficStats) > 0 {
+		for _, e := range m.RuleTrafficStats {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *NetworkPolicyStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Nodes) > 0 {
+		for _, e := range m.Nodes {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *NodeReference) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
 	return n
 }
 
@@ -2417,6 +2939,12 @@ func (m *NodeStatsSummary) Size() (n int) {
 			n += 1 + l + sovGenerated(uint64(l))
 		}
 	}
+	if len(m.Flows) > 0 {
+		for _, e := range m.Flows {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2433,6 +2961,17 @@ func (m *PodReference) Size() (n int) {
 	return n
 }
 
+func (m *RuleRateLimit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.MaxNewConnectionsPerSecond))
+	n += 1 + sovGenerated(uint64(m.Burst))
+	return n
+}
+
 func (m *Service) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2447,6 +2986,15 @@ func (m *Service) Size() (n int) {
 		l = m.Port.Size()
 		n += 1 + l + sovGenerated(uint64(l))
 	}
+	if m.ICMPType != nil {
+		n += 1 + sovGenerated(uint64(*m.ICMPType))
+	}
+	if m.ICMPCode != nil {
+		n += 1 + sovGenerated(uint64(*m.ICMPCode))
+	}
+	if m.IPProtocol != nil {
+		n += 1 + sovGenerated(uint64(*m.IPProtocol))
+	}
 	return n
 }
 
@@ -2640,6 +3188,7 @@ func (this *GroupMember) String() string {
 		`Pod:` + strings.Replace(this.Pod.String(), "PodReference", "PodReference", 1) + `,`,
 		`ExternalEntity:` + strings.Replace(this.ExternalEntity.String(), "ExternalEntityReference", "ExternalEntityReference", 1) + `,`,
 		`Endpoints:` + repeatedStringForEndpoints + `,`,
+		`Node:` + strings.Replace(this.Node.String(), "NodeReference", "NodeReference", 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2736,6 +3285,23 @@ func (this *NetworkPolicyList) String() string {
 	}, "")
 	return s
 }
+func (this *NetworkPolicyNodeStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForFailedRules := "[]NetworkPolicyRuleFailure{"
+	for _, f := range this.FailedRules {
+		repeatedStringForFailedRules += strings.Replace(strings.Replace(f.String(), "NetworkPolicyRuleFailure", "NetworkPolicyRuleFailure", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForFailedRules += "}"
+	s := strings.Join([]string{`&NetworkPolicyNodeStatus{`,
+		`NodeName:` + fmt.Sprintf("%v", this.NodeName) + `,`,
+		`Generation:` + fmt.Sprintf("%v", this.Generation) + `,`,
+		`FailedRules:` + repeatedStringForFailedRules + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *NetworkPolicyPeer) String() string {
 	if this == nil {
 		return "nil"
@@ -2748,6 +3314,7 @@ func (this *NetworkPolicyPeer) String() string {
 	s := strings.Join([]string{`&NetworkPolicyPeer{`,
 		`AddressGroups:` + fmt.Sprintf("%v", this.AddressGroups) + `,`,
 		`IPBlocks:` + repeatedStringForIPBlocks + `,`,
+		`FQDNs:` + fmt.Sprintf("%v", this.FQDNs) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2781,6 +3348,20 @@ func (this *NetworkPolicyRule) String() string {
 		`Services:` + repeatedStringForServices + `,`,
 		`Priority:` + fmt.Sprintf("%v", this.Priority) + `,`,
 		`Action:` + valueToStringGenerated(this.Action) + `,`,
+		`EnableLogging:` + fmt.Sprintf("%v", this.EnableLogging) + `,`,
+		`RateLimit:` + strings.Replace(this.RateLimit.String(), "RuleRateLimit", "RuleRateLimit", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NetworkPolicyRuleFailure) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NetworkPolicyRuleFailure{`,
+		`Direction:` + fmt.Sprintf("%v", this.Direction) + `,`,
+		`Priority:` + fmt.Sprintf("%v", this.Priority) + `,`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2789,37 +3370,75 @@ func (this *NetworkPolicyStats) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForRuleTrafficStats := "[]RuleTrafficStats{"
+	for _, f := range this.RuleTrafficStats {
+		repeatedStringForRuleTrafficStats += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForRuleTrafficStats += "}"
 	s := strings.Join([]string{`&NetworkPolicyStats{`,
 		`NetworkPolicy:` + strings.Replace(strings.Replace(this.NetworkPolicy.String(), "NetworkPolicyReference", "NetworkPolicyReference", 1), `&`, ``, 1) + `,`,
 		`TrafficStats:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.TrafficStats), "TrafficStats", "v1alpha1.TrafficStats", 1), `&`, ``, 1) + `,`,
+		`RuleTrafficStats:` + repeatedStringForRuleTrafficStats + `,`,
 		`}`,
 	}, "")
 	return s
 }
-func (this *NodeStatsSummary) String() string {
+func (this *NetworkPolicyStatus) String() string {
 	if this == nil {
 		return "nil"
 	}
-	repeatedStringForNetworkPolicies := "[]NetworkPolicyStats{"
-	for _, f := range this.NetworkPolicies {
-		repeatedStringForNetworkPolicies += strings.Replace(strings.Replace(f.String(), "NetworkPolicyStats", "NetworkPolicyStats", 1), `&`, ``, 1) + ","
-	}
-	repeatedStringForNetworkPolicies += "}"
-	repeatedStringForAntreaClusterNetworkPolicies := "[]NetworkPolicyStats{"
-	for _, f := range this.AntreaClusterNetworkPolicies {
-		repeatedStringForAntreaClusterNetworkPolicies += strings.Replace(strings.Replace(f.String(), "NetworkPolicyStats", "NetworkPolicyStats", 1), `&`, ``, 1) + ","
+	repeatedStringForNodes := "[]NetworkPolicyNodeStatus{"
+	for _, f := range this.Nodes {
+		repeatedStringForNodes += strings.Replace(strings.Replace(f.String(), "NetworkPolicyNodeStatus", "NetworkPolicyNodeStatus", 1), `&`, ``, 1) + ","
 	}
-	repeatedStringForAntreaClusterNetworkPolicies += "}"
-	repeatedStringForAntreaNetworkPolicies := "[]NetworkPolicyStats{"
+	repeatedStringForNodes += "}"
+	s := strings.Join([]string{`&NetworkPolicyStatus{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`Nodes:` + repeatedStringForNodes + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NodeReference) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NodeReference{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NodeStatsSummary) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForNetworkPolicies := "[]NetworkPolicyStats{"
+	for _, f := range this.NetworkPolicies {
+		repeatedStringForNetworkPolicies += strings.Replace(strings.Replace(f.String(), "NetworkPolicyStats", "NetworkPolicyStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForNetworkPolicies += "}"
+	repeatedStringForAntreaClusterNetworkPolicies := "[]NetworkPolicyStats{"
+	for _, f := range this.AntreaClusterNetworkPolicies {
+		repeatedStringForAntreaClusterNetworkPolicies += strings.Replace(strings.Replace(f.String(), "NetworkPolicyStats", "NetworkPolicyStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForAntreaClusterNetworkPolicies += "}"
+	repeatedStringForAntreaNetworkPolicies := "[]NetworkPolicyStats{"
 	for _, f := range this.AntreaNetworkPolicies {
 		repeatedStringForAntreaNetworkPolicies += strings.Replace(strings.Replace(f.String(), "NetworkPolicyStats", "NetworkPolicyStats", 1), `&`, ``, 1) + ","
 	}
 	repeatedStringForAntreaNetworkPolicies += "}"
+	repeatedStringForFlows := "[]FlowSummary{"
+	for _, f := range this.Flows {
+		repeatedStringForFlows += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForFlows += "}"
 	s := strings.Join([]string{`&NodeStatsSummary{`,
 		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
 		`NetworkPolicies:` + repeatedStringForNetworkPolicies + `,`,
 		`AntreaClusterNetworkPolicies:` + repeatedStringForAntreaClusterNetworkPolicies + `,`,
 		`AntreaNetworkPolicies:` + repeatedStringForAntreaNetworkPolicies + `,`,
+		`Flows:` + repeatedStringForFlows + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2835,6 +3454,17 @@ func (this *PodReference) String() string {
 	}, "")
 	return s
 }
+func (this *RuleRateLimit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RuleRateLimit{`,
+		`MaxNewConnectionsPerSecond:` + fmt.Sprintf("%v", this.MaxNewConnectionsPerSecond) + `,`,
+		`Burst:` + fmt.Sprintf("%v", this.Burst) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *Service) String() string {
 	if this == nil {
 		return "nil"
@@ -2842,6 +3472,9 @@ func (this *Service) String() string {
 	s := strings.Join([]string{`&Service{`,
 		`Protocol:` + valueToStringGenerated(this.Protocol) + `,`,
 		`Port:` + strings.Replace(fmt.Sprintf("%v", this.Port), "IntOrString", "intstr.IntOrString", 1) + `,`,
+		`ICMPType:` + valueToStringGenerated(this.ICMPType) + `,`,
+		`ICMPCode:` + valueToStringGenerated(this.ICMPCode) + `,`,
+		`IPProtocol:` + valueToStringGenerated(this.IPProtocol) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -4219,6 +4852,42 @@ func (m *GroupMember) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Node", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Node == nil {
+				m.Node = &NodeReference{}
+			}
+			if err := m.Node.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -5102,6 +5771,144 @@ func (m *NetworkPolicyList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *NetworkPolicyNodeStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetworkPolicyNodeStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetworkPolicyNodeStatus: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NodeName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NodeName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Generation", wireType)
+			}
+			m.Generation = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Generation |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FailedRules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FailedRules = append(m.FailedRules, NetworkPolicyRuleFailure{})
+			if err := m.FailedRules[len(m.FailedRules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -5197,6 +6004,38 @@ func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FQDNs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FQDNs = append(m.FQDNs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -5615,26 +6454,218 @@ func (m *NetworkPolicyRule) Unmarshal(dAtA []byte) error {
 			s := github_com_vmware_tanzu_antrea_pkg_apis_security_v1alpha1.RuleAction(dAtA[iNdEx:postIndex])
 			m.Action = &s
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthGenerated
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EnableLogging", wireType)
 			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthGenerated
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			m.EnableLogging = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RateLimit", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RateLimit == nil {
+				m.RateLimit = &RuleRateLimit{}
+			}
+			if err := m.RateLimit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NetworkPolicyRuleFailure) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetworkPolicyRuleFailure: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetworkPolicyRuleFailure: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Direction", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Direction = Direction(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
+			}
+			m.Priority = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Priority |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
 		return io.ErrUnexpectedEOF
 	}
 	return nil
@@ -5665,12 +6696,165 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: NetworkPolicyStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetworkPolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.NetworkPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuleTrafficStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RuleTrafficStats = append(m.RuleTrafficStats, v1alpha1.RuleTrafficStats{})
+			if err := m.RuleTrafficStats[len(m.RuleTrafficStats)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NetworkPolicyStatus) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetworkPolicyStatus: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetworkPolicyStatus: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NetworkPolicy", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5697,13 +6881,13 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.NetworkPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Nodes", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5730,9 +6914,95 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Nodes = append(m.Nodes, NetworkPolicyNodeStatus{})
+			if err := m.Nodes[len(m.Nodes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NodeReference) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NodeReference: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NodeReference: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5922,6 +7192,40 @@ func (m *NodeStatsSummary) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flows", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Flows = append(m.Flows, v1alpha1.FlowSummary{})
+			if err := m.Flows[len(m.Flows)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -6063,6 +7367,97 @@ func (m *PodReference) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *RuleRateLimit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RuleRateLimit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RuleRateLimit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxNewConnectionsPerSecond", wireType)
+			}
+			m.MaxNewConnectionsPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxNewConnectionsPerSecond |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
+			}
+			m.Burst = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Burst |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Service) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -6161,6 +7556,66 @@ func (m *Service) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ICMPType", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ICMPType = &v
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ICMPCode", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ICMPCode = &v
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IPProtocol", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IPProtocol = &v
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
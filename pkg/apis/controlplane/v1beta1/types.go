@@ -73,6 +73,12 @@ type ExternalEntityReference struct {
 	Namespace string `json:"namespace,omitempty" protobuf:"bytes,2,opt,name=namespace"`
 }
 
+// NodeReference represents a Node Reference.
+type NodeReference struct {
+	// The name of this Node.
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+}
+
 // Endpoint represents an external endpoint.
 type Endpoint struct {
 	// IP is the IP address of the Endpoint.
@@ -92,6 +98,9 @@ type GroupMember struct {
 
 	// Endpoints maintains a list of EndPoints associated with this groupMember.
 	Endpoints []Endpoint `json:"endpoints,omitempty" protobuf:"bytes,3,rep,name=endpoints"`
+
+	// Node maintains the reference to the Node.
+	Node *NodeReference `json:"node,omitempty" protobuf:"bytes,4,opt,name=node"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -174,6 +183,7 @@ type NetworkPolicyReference struct {
 
 // +genclient
 // +genclient:onlyVerbs=list,get,watch
+// +genclient:method=UpdateStatus,verb=create,subresource=status,input=NetworkPolicyStatus,result=NetworkPolicyStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // NetworkPolicy is the message format of antrea/pkg/controller/types.NetworkPolicy in an API response.
 type NetworkPolicy struct {
@@ -220,6 +230,20 @@ type NetworkPolicyRule struct {
 	// action “nil” defaults to Allow action, which would be the case for rules created for
 	// K8s Network Policy.
 	Action *secv1alpha1.RuleAction `json:"action,omitempty" protobuf:"bytes,6,opt,name=action,casttype=github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1.RuleAction"`
+	// EnableLogging indicates whether the connections matched by the rule
+	// should be audit logged by the agents.
+	EnableLogging bool `json:"enableLogging,omitempty" protobuf:"varint,7,opt,name=enableLogging"`
+	// RateLimit limits the rate of the new connections matched by the rule.
+	RateLimit *RuleRateLimit `json:"rateLimit,omitempty" protobuf:"bytes,8,opt,name=rateLimit"`
+}
+
+// RuleRateLimit limits the rate of the new connections matched by a rule on
+// each Node.
+type RuleRateLimit struct {
+	// MaxNewConnectionsPerSecond is the maximum rate of new connections.
+	MaxNewConnectionsPerSecond int32 `json:"maxNewConnectionsPerSecond,omitempty" protobuf:"varint,1,opt,name=maxNewConnectionsPerSecond"`
+	// Burst is the number of new connections which can exceed the rate at once.
+	Burst int32 `json:"burst,omitempty" protobuf:"varint,2,opt,name=burst"`
 }
 
 // Protocol defines network protocols supported for things like container ports.
@@ -232,26 +256,46 @@ const (
 	ProtocolUDP Protocol = "UDP"
 	// ProtocolSCTP is the SCTP protocol.
 	ProtocolSCTP Protocol = "SCTP"
+	// ProtocolICMP is the ICMP protocol.
+	ProtocolICMP Protocol = "ICMP"
+	// ProtocolIGMP is the IGMP protocol.
+	ProtocolIGMP Protocol = "IGMP"
+	// ProtocolIP is any IP protocol, identified by its number.
+	ProtocolIP Protocol = "IP"
 )
 
 // Service describes a port to allow traffic on.
 type Service struct {
-	// The protocol (TCP, UDP, or SCTP) which traffic must match. If not specified, this
-	// field defaults to TCP.
+	// The protocol (TCP, UDP, SCTP, ICMP, IGMP or IP) which traffic must match. If
+	// not specified, this field defaults to TCP.
 	// +optional
 	Protocol *Protocol `json:"protocol,omitempty" protobuf:"bytes,1,opt,name=protocol"`
 	// The port name or number on the given protocol. If not specified, this matches all port numbers.
 	// +optional
 	Port *intstr.IntOrString `json:"port,omitempty" protobuf:"bytes,2,opt,name=port"`
+	// The ICMP type and code to match. They can only be set when the protocol is
+	// ICMP. If not specified, this matches all ICMP types and codes.
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty" protobuf:"varint,3,opt,name=icmpType"`
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty" protobuf:"varint,4,opt,name=icmpCode"`
+	// The IANA number of the IP protocol to match. It must be set when the
+	// protocol is IP, and cannot be set otherwise.
+	// +optional
+	IPProtocol *int32 `json:"ipProtocol,omitempty" protobuf:"varint,5,opt,name=ipProtocol"`
 }
 
 // NetworkPolicyPeer describes a peer of NetworkPolicyRules.
-// It could be a list of names of AddressGroups and/or a list of IPBlock.
+// It could be a list of names of AddressGroups, a list of IPBlock and/or a
+// list of FQDNs.
 type NetworkPolicyPeer struct {
 	// A list of names of AddressGroups.
 	AddressGroups []string `json:"addressGroups,omitempty" protobuf:"bytes,1,rep,name=addressGroups"`
 	// A list of IPBlock.
 	IPBlocks []IPBlock `json:"ipBlocks,omitempty" protobuf:"bytes,2,rep,name=ipBlocks"`
+	// A list of FQDNs, e.g. "*.example.com", whose resolved IP addresses are
+	// learned by the agents from DNS responses.
+	FQDNs []string `json:"fqdns,omitempty" protobuf:"bytes,3,rep,name=fqdns"`
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24"). The except entry describes CIDRs that should
@@ -273,6 +317,41 @@ type NetworkPolicyList struct {
 	Items           []NetworkPolicy `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
 
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkPolicyStatus is the status of a NetworkPolicy. It's used by the antrea-agents to report the generation of the
+// NetworkPolicy they have realized to the antrea-controller.
+type NetworkPolicyStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Nodes contains statuses produced on a list of Nodes.
+	Nodes []NetworkPolicyNodeStatus `json:"nodes,omitempty" protobuf:"bytes,2,rep,name=nodes"`
+}
+
+// NetworkPolicyNodeStatus is the status of a NetworkPolicy on a Node.
+type NetworkPolicyNodeStatus struct {
+	// The name of the Node that produces the status.
+	NodeName string `json:"nodeName,omitempty" protobuf:"bytes,1,opt,name=nodeName"`
+	// The generation realized by the Node.
+	Generation int64 `json:"generation,omitempty" protobuf:"varint,2,opt,name=generation"`
+	// FailedRules are the rules of the generation which the Node failed to
+	// realize. The other rules of the generation are realized.
+	FailedRules []NetworkPolicyRuleFailure `json:"failedRules,omitempty" protobuf:"bytes,3,rep,name=failedRules"`
+}
+
+// NetworkPolicyRuleFailure describes a rule of a NetworkPolicy which a Node
+// failed to realize.
+type NetworkPolicyRuleFailure struct {
+	// The direction of the rule.
+	Direction Direction `json:"direction,omitempty" protobuf:"bytes,1,opt,name=direction"`
+	// The priority of the rule within the NetworkPolicy, which is its index
+	// in the Ingress or Egress rules of the original policy.
+	Priority int32 `json:"priority,omitempty" protobuf:"varint,2,opt,name=priority"`
+	// The error which prevented the rule from being realized.
+	Message string `json:"message,omitempty" protobuf:"bytes,3,opt,name=message"`
+}
+
 // +genclient
 // +genclient:nonNamespaced
 // +genclient:onlyVerbs=create
@@ -289,6 +368,10 @@ type NodeStatsSummary struct {
 	AntreaClusterNetworkPolicies []NetworkPolicyStats `json:"antreaClusterNetworkPolicies,omitempty" protobuf:"bytes,3,rep,name=antreaClusterNetworkPolicies"`
 	// The TrafficStats of Antrea NetworkPolicies collected from the Node.
 	AntreaNetworkPolicies []NetworkPolicyStats `json:"antreaNetworkPolicies,omitempty" protobuf:"bytes,4,rep,name=antreaNetworkPolicies"`
+	// The summaries of the connections of the Pods running on the Node. Unlike the TrafficStats of the policies,
+	// they are not a delta but a snapshot of the connections tracked by the Node. They are only collected when
+	// the flow exporter runs in antrea-agent.
+	Flows []statsv1alpha1.FlowSummary `json:"flows,omitempty" protobuf:"bytes,5,rep,name=flows"`
 }
 
 // NetworkPolicyStats contains the information and traffic stats of a NetworkPolicy.
@@ -297,4 +380,6 @@ type NetworkPolicyStats struct {
 	NetworkPolicy NetworkPolicyReference `json:"networkPolicy,omitempty" protobuf:"bytes,1,opt,name=networkPolicy"`
 	// The stats of the NetworkPolicy.
 	TrafficStats statsv1alpha1.TrafficStats `json:"trafficStats,omitempty" protobuf:"bytes,2,opt,name=trafficStats"`
+	// The stats of the rules of the NetworkPolicy. It is only set for Antrea-native policies.
+	RuleTrafficStats []statsv1alpha1.RuleTrafficStats `json:"ruleTrafficStats,omitempty" protobuf:"bytes,3,rep,name=ruleTrafficStats"`
 }
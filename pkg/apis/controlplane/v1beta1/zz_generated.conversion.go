@@ -23,6 +23,7 @@ import (
 
 	controlplane "github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
 	v1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
@@ -186,6 +187,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NetworkPolicyNodeStatus)(nil), (*controlplane.NetworkPolicyNodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(a.(*NetworkPolicyNodeStatus), b.(*controlplane.NetworkPolicyNodeStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.NetworkPolicyNodeStatus)(nil), (*NetworkPolicyNodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(a.(*controlplane.NetworkPolicyNodeStatus), b.(*NetworkPolicyNodeStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NetworkPolicyPeer)(nil), (*controlplane.NetworkPolicyPeer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_NetworkPolicyPeer_To_controlplane_NetworkPolicyPeer(a.(*NetworkPolicyPeer), b.(*controlplane.NetworkPolicyPeer), scope)
 	}); err != nil {
@@ -216,6 +227,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NetworkPolicyRuleFailure)(nil), (*controlplane.NetworkPolicyRuleFailure)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkPolicyRuleFailure_To_controlplane_NetworkPolicyRuleFailure(a.(*NetworkPolicyRuleFailure), b.(*controlplane.NetworkPolicyRuleFailure), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.NetworkPolicyRuleFailure)(nil), (*NetworkPolicyRuleFailure)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_NetworkPolicyRuleFailure_To_v1beta1_NetworkPolicyRuleFailure(a.(*controlplane.NetworkPolicyRuleFailure), b.(*NetworkPolicyRuleFailure), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NetworkPolicyStats)(nil), (*controlplane.NetworkPolicyStats)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_NetworkPolicyStats_To_controlplane_NetworkPolicyStats(a.(*NetworkPolicyStats), b.(*controlplane.NetworkPolicyStats), scope)
 	}); err != nil {
@@ -226,6 +247,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NetworkPolicyStatus)(nil), (*controlplane.NetworkPolicyStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(a.(*NetworkPolicyStatus), b.(*controlplane.NetworkPolicyStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.NetworkPolicyStatus)(nil), (*NetworkPolicyStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(a.(*controlplane.NetworkPolicyStatus), b.(*NetworkPolicyStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeReference)(nil), (*controlplane.NodeReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodeReference_To_controlplane_NodeReference(a.(*NodeReference), b.(*controlplane.NodeReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.NodeReference)(nil), (*NodeReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_NodeReference_To_v1beta1_NodeReference(a.(*controlplane.NodeReference), b.(*NodeReference), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeStatsSummary)(nil), (*controlplane.NodeStatsSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_NodeStatsSummary_To_controlplane_NodeStatsSummary(a.(*NodeStatsSummary), b.(*controlplane.NodeStatsSummary), scope)
 	}); err != nil {
@@ -246,6 +287,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RuleRateLimit)(nil), (*controlplane.RuleRateLimit)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RuleRateLimit_To_controlplane_RuleRateLimit(a.(*RuleRateLimit), b.(*controlplane.RuleRateLimit), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*controlplane.RuleRateLimit)(nil), (*RuleRateLimit)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_RuleRateLimit_To_v1beta1_RuleRateLimit(a.(*controlplane.RuleRateLimit), b.(*RuleRateLimit), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Service)(nil), (*controlplane.Service)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Service_To_controlplane_Service(a.(*Service), b.(*controlplane.Service), scope)
 	}); err != nil {
@@ -455,6 +506,7 @@ func autoConvert_v1beta1_GroupMember_To_controlplane_GroupMember(in *GroupMember
 	out.Pod = (*controlplane.PodReference)(unsafe.Pointer(in.Pod))
 	out.ExternalEntity = (*controlplane.ExternalEntityReference)(unsafe.Pointer(in.ExternalEntity))
 	out.Endpoints = *(*[]controlplane.Endpoint)(unsafe.Pointer(&in.Endpoints))
+	out.Node = (*controlplane.NodeReference)(unsafe.Pointer(in.Node))
 	return nil
 }
 
@@ -467,6 +519,7 @@ func autoConvert_controlplane_GroupMember_To_v1beta1_GroupMember(in *controlplan
 	out.Pod = (*PodReference)(unsafe.Pointer(in.Pod))
 	out.ExternalEntity = (*ExternalEntityReference)(unsafe.Pointer(in.ExternalEntity))
 	out.Endpoints = *(*[]Endpoint)(unsafe.Pointer(&in.Endpoints))
+	out.Node = (*NodeReference)(unsafe.Pointer(in.Node))
 	return nil
 }
 
@@ -623,9 +676,34 @@ func Convert_controlplane_NetworkPolicyList_To_v1beta1_NetworkPolicyList(in *con
 	return autoConvert_controlplane_NetworkPolicyList_To_v1beta1_NetworkPolicyList(in, out, s)
 }
 
+func autoConvert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(in *NetworkPolicyNodeStatus, out *controlplane.NetworkPolicyNodeStatus, s conversion.Scope) error {
+	out.NodeName = in.NodeName
+	out.Generation = in.Generation
+	out.FailedRules = *(*[]controlplane.NetworkPolicyRuleFailure)(unsafe.Pointer(&in.FailedRules))
+	return nil
+}
+
+// Convert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus is an autogenerated conversion function.
+func Convert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(in *NetworkPolicyNodeStatus, out *controlplane.NetworkPolicyNodeStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkPolicyNodeStatus_To_controlplane_NetworkPolicyNodeStatus(in, out, s)
+}
+
+func autoConvert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(in *controlplane.NetworkPolicyNodeStatus, out *NetworkPolicyNodeStatus, s conversion.Scope) error {
+	out.NodeName = in.NodeName
+	out.Generation = in.Generation
+	out.FailedRules = *(*[]NetworkPolicyRuleFailure)(unsafe.Pointer(&in.FailedRules))
+	return nil
+}
+
+// Convert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus is an autogenerated conversion function.
+func Convert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(in *controlplane.NetworkPolicyNodeStatus, out *NetworkPolicyNodeStatus, s conversion.Scope) error {
+	return autoConvert_controlplane_NetworkPolicyNodeStatus_To_v1beta1_NetworkPolicyNodeStatus(in, out, s)
+}
+
 func autoConvert_v1beta1_NetworkPolicyPeer_To_controlplane_NetworkPolicyPeer(in *NetworkPolicyPeer, out *controlplane.NetworkPolicyPeer, s conversion.Scope) error {
 	out.AddressGroups = *(*[]string)(unsafe.Pointer(&in.AddressGroups))
 	out.IPBlocks = *(*[]controlplane.IPBlock)(unsafe.Pointer(&in.IPBlocks))
+	out.FQDNs = *(*[]string)(unsafe.Pointer(&in.FQDNs))
 	return nil
 }
 
@@ -637,6 +715,7 @@ func Convert_v1beta1_NetworkPolicyPeer_To_controlplane_NetworkPolicyPeer(in *Net
 func autoConvert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(in *controlplane.NetworkPolicyPeer, out *NetworkPolicyPeer, s conversion.Scope) error {
 	out.AddressGroups = *(*[]string)(unsafe.Pointer(&in.AddressGroups))
 	out.IPBlocks = *(*[]IPBlock)(unsafe.Pointer(&in.IPBlocks))
+	out.FQDNs = *(*[]string)(unsafe.Pointer(&in.FQDNs))
 	return nil
 }
 
@@ -682,6 +761,8 @@ func autoConvert_v1beta1_NetworkPolicyRule_To_controlplane_NetworkPolicyRule(in
 	out.Services = *(*[]controlplane.Service)(unsafe.Pointer(&in.Services))
 	out.Priority = in.Priority
 	out.Action = (*v1alpha1.RuleAction)(unsafe.Pointer(in.Action))
+	out.EnableLogging = in.EnableLogging
+	out.RateLimit = (*controlplane.RuleRateLimit)(unsafe.Pointer(in.RateLimit))
 	return nil
 }
 
@@ -701,6 +782,8 @@ func autoConvert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(in
 	out.Services = *(*[]Service)(unsafe.Pointer(&in.Services))
 	out.Priority = in.Priority
 	out.Action = (*v1alpha1.RuleAction)(unsafe.Pointer(in.Action))
+	out.EnableLogging = in.EnableLogging
+	out.RateLimit = (*RuleRateLimit)(unsafe.Pointer(in.RateLimit))
 	return nil
 }
 
@@ -709,11 +792,36 @@ func Convert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(in *con
 	return autoConvert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(in, out, s)
 }
 
+func autoConvert_v1beta1_NetworkPolicyRuleFailure_To_controlplane_NetworkPolicyRuleFailure(in *NetworkPolicyRuleFailure, out *controlplane.NetworkPolicyRuleFailure, s conversion.Scope) error {
+	out.Direction = controlplane.Direction(in.Direction)
+	out.Priority = in.Priority
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta1_NetworkPolicyRuleFailure_To_controlplane_NetworkPolicyRuleFailure is an autogenerated conversion function.
+func Convert_v1beta1_NetworkPolicyRuleFailure_To_controlplane_NetworkPolicyRuleFailure(in *NetworkPolicyRuleFailure, out *controlplane.NetworkPolicyRuleFailure, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkPolicyRuleFailure_To_controlplane_NetworkPolicyRuleFailure(in, out, s)
+}
+
+func autoConvert_controlplane_NetworkPolicyRuleFailure_To_v1beta1_NetworkPolicyRuleFailure(in *controlplane.NetworkPolicyRuleFailure, out *NetworkPolicyRuleFailure, s conversion.Scope) error {
+	out.Direction = Direction(in.Direction)
+	out.Priority = in.Priority
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_controlplane_NetworkPolicyRuleFailure_To_v1beta1_NetworkPolicyRuleFailure is an autogenerated conversion function.
+func Convert_controlplane_NetworkPolicyRuleFailure_To_v1beta1_NetworkPolicyRuleFailure(in *controlplane.NetworkPolicyRuleFailure, out *NetworkPolicyRuleFailure, s conversion.Scope) error {
+	return autoConvert_controlplane_NetworkPolicyRuleFailure_To_v1beta1_NetworkPolicyRuleFailure(in, out, s)
+}
+
 func autoConvert_v1beta1_NetworkPolicyStats_To_controlplane_NetworkPolicyStats(in *NetworkPolicyStats, out *controlplane.NetworkPolicyStats, s conversion.Scope) error {
 	if err := Convert_v1beta1_NetworkPolicyReference_To_controlplane_NetworkPolicyReference(&in.NetworkPolicy, &out.NetworkPolicy, s); err != nil {
 		return err
 	}
 	out.TrafficStats = in.TrafficStats
+	out.RuleTrafficStats = *(*[]statsv1alpha1.RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
 	return nil
 }
 
@@ -727,6 +835,7 @@ func autoConvert_controlplane_NetworkPolicyStats_To_v1beta1_NetworkPolicyStats(i
 		return err
 	}
 	out.TrafficStats = in.TrafficStats
+	out.RuleTrafficStats = *(*[]statsv1alpha1.RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
 	return nil
 }
 
@@ -735,11 +844,54 @@ func Convert_controlplane_NetworkPolicyStats_To_v1beta1_NetworkPolicyStats(in *c
 	return autoConvert_controlplane_NetworkPolicyStats_To_v1beta1_NetworkPolicyStats(in, out, s)
 }
 
+func autoConvert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(in *NetworkPolicyStatus, out *controlplane.NetworkPolicyStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Nodes = *(*[]controlplane.NetworkPolicyNodeStatus)(unsafe.Pointer(&in.Nodes))
+	return nil
+}
+
+// Convert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus is an autogenerated conversion function.
+func Convert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(in *NetworkPolicyStatus, out *controlplane.NetworkPolicyStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkPolicyStatus_To_controlplane_NetworkPolicyStatus(in, out, s)
+}
+
+func autoConvert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(in *controlplane.NetworkPolicyStatus, out *NetworkPolicyStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Nodes = *(*[]NetworkPolicyNodeStatus)(unsafe.Pointer(&in.Nodes))
+	return nil
+}
+
+// Convert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus is an autogenerated conversion function.
+func Convert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(in *controlplane.NetworkPolicyStatus, out *NetworkPolicyStatus, s conversion.Scope) error {
+	return autoConvert_controlplane_NetworkPolicyStatus_To_v1beta1_NetworkPolicyStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_NodeReference_To_controlplane_NodeReference(in *NodeReference, out *controlplane.NodeReference, s conversion.Scope) error {
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1beta1_NodeReference_To_controlplane_NodeReference is an autogenerated conversion function.
+func Convert_v1beta1_NodeReference_To_controlplane_NodeReference(in *NodeReference, out *controlplane.NodeReference, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodeReference_To_controlplane_NodeReference(in, out, s)
+}
+
+func autoConvert_controlplane_NodeReference_To_v1beta1_NodeReference(in *controlplane.NodeReference, out *NodeReference, s conversion.Scope) error {
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_controlplane_NodeReference_To_v1beta1_NodeReference is an autogenerated conversion function.
+func Convert_controlplane_NodeReference_To_v1beta1_NodeReference(in *controlplane.NodeReference, out *NodeReference, s conversion.Scope) error {
+	return autoConvert_controlplane_NodeReference_To_v1beta1_NodeReference(in, out, s)
+}
+
 func autoConvert_v1beta1_NodeStatsSummary_To_controlplane_NodeStatsSummary(in *NodeStatsSummary, out *controlplane.NodeStatsSummary, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	out.NetworkPolicies = *(*[]controlplane.NetworkPolicyStats)(unsafe.Pointer(&in.NetworkPolicies))
 	out.AntreaClusterNetworkPolicies = *(*[]controlplane.NetworkPolicyStats)(unsafe.Pointer(&in.AntreaClusterNetworkPolicies))
 	out.AntreaNetworkPolicies = *(*[]controlplane.NetworkPolicyStats)(unsafe.Pointer(&in.AntreaNetworkPolicies))
+	out.Flows = *(*[]statsv1alpha1.FlowSummary)(unsafe.Pointer(&in.Flows))
 	return nil
 }
 
@@ -753,6 +905,7 @@ func autoConvert_controlplane_NodeStatsSummary_To_v1beta1_NodeStatsSummary(in *c
 	out.NetworkPolicies = *(*[]NetworkPolicyStats)(unsafe.Pointer(&in.NetworkPolicies))
 	out.AntreaClusterNetworkPolicies = *(*[]NetworkPolicyStats)(unsafe.Pointer(&in.AntreaClusterNetworkPolicies))
 	out.AntreaNetworkPolicies = *(*[]NetworkPolicyStats)(unsafe.Pointer(&in.AntreaNetworkPolicies))
+	out.Flows = *(*[]statsv1alpha1.FlowSummary)(unsafe.Pointer(&in.Flows))
 	return nil
 }
 
@@ -783,9 +936,34 @@ func Convert_controlplane_PodReference_To_v1beta1_PodReference(in *controlplane.
 	return autoConvert_controlplane_PodReference_To_v1beta1_PodReference(in, out, s)
 }
 
+func autoConvert_v1beta1_RuleRateLimit_To_controlplane_RuleRateLimit(in *RuleRateLimit, out *controlplane.RuleRateLimit, s conversion.Scope) error {
+	out.MaxNewConnectionsPerSecond = in.MaxNewConnectionsPerSecond
+	out.Burst = in.Burst
+	return nil
+}
+
+// Convert_v1beta1_RuleRateLimit_To_controlplane_RuleRateLimit is an autogenerated conversion function.
+func Convert_v1beta1_RuleRateLimit_To_controlplane_RuleRateLimit(in *RuleRateLimit, out *controlplane.RuleRateLimit, s conversion.Scope) error {
+	return autoConvert_v1beta1_RuleRateLimit_To_controlplane_RuleRateLimit(in, out, s)
+}
+
+func autoConvert_controlplane_RuleRateLimit_To_v1beta1_RuleRateLimit(in *controlplane.RuleRateLimit, out *RuleRateLimit, s conversion.Scope) error {
+	out.MaxNewConnectionsPerSecond = in.MaxNewConnectionsPerSecond
+	out.Burst = in.Burst
+	return nil
+}
+
+// Convert_controlplane_RuleRateLimit_To_v1beta1_RuleRateLimit is an autogenerated conversion function.
+func Convert_controlplane_RuleRateLimit_To_v1beta1_RuleRateLimit(in *controlplane.RuleRateLimit, out *RuleRateLimit, s conversion.Scope) error {
+	return autoConvert_controlplane_RuleRateLimit_To_v1beta1_RuleRateLimit(in, out, s)
+}
+
 func autoConvert_v1beta1_Service_To_controlplane_Service(in *Service, out *controlplane.Service, s conversion.Scope) error {
 	out.Protocol = (*controlplane.Protocol)(unsafe.Pointer(in.Protocol))
 	out.Port = (*intstr.IntOrString)(unsafe.Pointer(in.Port))
+	out.ICMPType = (*int32)(unsafe.Pointer(in.ICMPType))
+	out.ICMPCode = (*int32)(unsafe.Pointer(in.ICMPCode))
+	out.IPProtocol = (*int32)(unsafe.Pointer(in.IPProtocol))
 	return nil
 }
 
@@ -797,6 +975,9 @@ func Convert_v1beta1_Service_To_controlplane_Service(in *Service, out *controlpl
 func autoConvert_controlplane_Service_To_v1beta1_Service(in *controlplane.Service, out *Service, s conversion.Scope) error {
 	out.Protocol = (*Protocol)(unsafe.Pointer(in.Protocol))
 	out.Port = (*intstr.IntOrString)(unsafe.Pointer(in.Port))
+	out.ICMPType = (*int32)(unsafe.Pointer(in.ICMPType))
+	out.ICMPCode = (*int32)(unsafe.Pointer(in.ICMPCode))
+	out.IPProtocol = (*int32)(unsafe.Pointer(in.IPProtocol))
 	return nil
 }
 
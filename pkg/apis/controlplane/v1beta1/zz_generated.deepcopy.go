@@ -20,6 +20,7 @@ package v1beta1
 
 import (
 	v1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -340,6 +341,11 @@ func (in *GroupMember) DeepCopyInto(out *GroupMember) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Node != nil {
+		in, out := &in.Node, &out.Node
+		*out = new(NodeReference)
+		**out = **in
+	}
 	return
 }
 
@@ -581,6 +587,27 @@ func (in *NetworkPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyNodeStatus) DeepCopyInto(out *NetworkPolicyNodeStatus) {
+	*out = *in
+	if in.FailedRules != nil {
+		in, out := &in.FailedRules, &out.FailedRules
+		*out = make([]NetworkPolicyRuleFailure, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyNodeStatus.
+func (in *NetworkPolicyNodeStatus) DeepCopy() *NetworkPolicyNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkPolicyPeer) DeepCopyInto(out *NetworkPolicyPeer) {
 	*out = *in
@@ -596,6 +623,11 @@ func (in *NetworkPolicyPeer) DeepCopyInto(out *NetworkPolicyPeer) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FQDNs != nil {
+		in, out := &in.FQDNs, &out.FQDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -642,6 +674,11 @@ func (in *NetworkPolicyRule) DeepCopyInto(out *NetworkPolicyRule) {
 		*out = new(v1alpha1.RuleAction)
 		**out = **in
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RuleRateLimit)
+		**out = **in
+	}
 	return
 }
 
@@ -655,11 +692,32 @@ func (in *NetworkPolicyRule) DeepCopy() *NetworkPolicyRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyRuleFailure) DeepCopyInto(out *NetworkPolicyRuleFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyRuleFailure.
+func (in *NetworkPolicyRuleFailure) DeepCopy() *NetworkPolicyRuleFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyRuleFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkPolicyStats) DeepCopyInto(out *NetworkPolicyStats) {
 	*out = *in
 	out.NetworkPolicy = in.NetworkPolicy
 	out.TrafficStats = in.TrafficStats
+	if in.RuleTrafficStats != nil {
+		in, out := &in.RuleTrafficStats, &out.RuleTrafficStats
+		*out = make([]statsv1alpha1.RuleTrafficStats, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -673,6 +731,55 @@ func (in *NetworkPolicyStats) DeepCopy() *NetworkPolicyStats {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyStatus) DeepCopyInto(out *NetworkPolicyStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NetworkPolicyNodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyStatus.
+func (in *NetworkPolicyStatus) DeepCopy() *NetworkPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkPolicyStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeReference) DeepCopyInto(out *NodeReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeReference.
+func (in *NodeReference) DeepCopy() *NodeReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeStatsSummary) DeepCopyInto(out *NodeStatsSummary) {
 	*out = *in
@@ -681,16 +788,27 @@ func (in *NodeStatsSummary) DeepCopyInto(out *NodeStatsSummary) {
 	if in.NetworkPolicies != nil {
 		in, out := &in.NetworkPolicies, &out.NetworkPolicies
 		*out = make([]NetworkPolicyStats, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.AntreaClusterNetworkPolicies != nil {
 		in, out := &in.AntreaClusterNetworkPolicies, &out.AntreaClusterNetworkPolicies
 		*out = make([]NetworkPolicyStats, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.AntreaNetworkPolicies != nil {
 		in, out := &in.AntreaNetworkPolicies, &out.AntreaNetworkPolicies
 		*out = make([]NetworkPolicyStats, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Flows != nil {
+		in, out := &in.Flows, &out.Flows
+		*out = make([]statsv1alpha1.FlowSummary, len(*in))
 		copy(*out, *in)
 	}
 	return
@@ -730,6 +848,22 @@ func (in *PodReference) DeepCopy() *PodReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleRateLimit) DeepCopyInto(out *RuleRateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleRateLimit.
+func (in *RuleRateLimit) DeepCopy() *RuleRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -743,6 +877,21 @@ func (in *Service) DeepCopyInto(out *Service) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.ICMPType != nil {
+		in, out := &in.ICMPType, &out.ICMPType
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ICMPCode != nil {
+		in, out := &in.ICMPCode, &out.ICMPCode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IPProtocol != nil {
+		in, out := &in.IPProtocol, &out.IPProtocol
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
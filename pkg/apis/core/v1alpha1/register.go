@@ -46,6 +46,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&ExternalEntity{},
 		&ExternalEntityList{},
+		&Group{},
+		&GroupList{},
+		&Egress{},
+		&EgressList{},
+		&ExternalIPPool{},
+		&ExternalIPPoolList{},
+		&IPPool{},
+		&IPPoolList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -17,6 +17,8 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 )
 
 // +genclient
@@ -74,3 +76,246 @@ type ExternalEntityList struct {
 
 	Items []ExternalEntity `json:"items,omitempty"`
 }
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Group is a set of Pods or ExternalEntities of a Namespace, which can be
+// referenced as a peer by the Antrea NetworkPolicies of the same Namespace.
+type Group struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Desired state of the group.
+	Spec GroupSpec `json:"spec"`
+}
+
+// GroupSpec defines the members of a Group. Exactly one of PodSelector,
+// ExternalEntitySelector, ServiceReference, IPBlocks and ChildGroups must be
+// set.
+type GroupSpec struct {
+	// Select Pods from the Group's Namespace as members of the Group.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// Select ExternalEntities from the Group's Namespace as members of the
+	// Group.
+	// +optional
+	ExternalEntitySelector *metav1.LabelSelector `json:"externalEntitySelector,omitempty"`
+	// Select the Pods selected by a Service of the Group's Namespace as
+	// members of the Group. A Service without selector doesn't select any
+	// Pod.
+	// +optional
+	ServiceReference *ServiceReference `json:"serviceReference,omitempty"`
+	// Select the IP addresses of the IPBlocks as members of the Group.
+	// +optional
+	IPBlocks []secv1alpha1.IPBlock `json:"ipBlocks,omitempty"`
+	// Select the members of other Groups of the Group's Namespace as members
+	// of the Group. The child Groups cannot have child Groups themselves.
+	// +optional
+	ChildGroups []string `json:"childGroups,omitempty"`
+}
+
+// ServiceReference is a reference to a Service in the Namespace of a Group.
+type ServiceReference struct {
+	// Name of the Service.
+	Name string `json:"name"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type GroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Group `json:"items,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Egress defines which egress (SNAT) IP the traffic from the selected Pods to
+// the external network should use.
+type Egress struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the desired behavior of Egress.
+	Spec EgressSpec `json:"spec"`
+	// Most recently observed status of the Egress.
+	Status EgressStatus `json:"status,omitempty"`
+}
+
+// EgressSpec defines the desired state for Egress.
+type EgressSpec struct {
+	// AppliedTo selects Pods to which the Egress will be applied.
+	AppliedTo AppliedTo `json:"appliedTo"`
+	// EgressIP specifies the SNAT IP address for the selected workloads. If
+	// it's empty and ExternalIPPool is set, an IP is allocated from the pool.
+	// +optional
+	EgressIP string `json:"egressIP,omitempty"`
+	// ExternalIPPool is the name of the ExternalIPPool the EgressIP is
+	// allocated from, and which selects the Nodes the EgressIP can be
+	// assigned to.
+	// +optional
+	ExternalIPPool string `json:"externalIPPool,omitempty"`
+}
+
+// EgressStatus represents the current status of an Egress. It's updated by the
+// antrea-agent of the Egress Node.
+type EgressStatus struct {
+	// EgressNode is the name of the Node which holds the Egress IP.
+	EgressNode string `json:"egressNode,omitempty"`
+	// SelectedPods is the number of running Pods whose traffic is SNAT'd
+	// with the Egress IP.
+	SelectedPods int32 `json:"selectedPods"`
+	// SNATConnections is the number of connections SNAT'd with the Egress
+	// IP on the Egress Node, as counted at the last status update. It's
+	// not reported on Windows Nodes.
+	// +optional
+	SNATConnections *int32 `json:"snatConnections,omitempty"`
+}
+
+// AppliedTo selects the entities to which a policy is applied. A nil selector
+// selects all the entities of its kind, the Pods are selected by both
+// selectors.
+type AppliedTo struct {
+	// Select Pods matched by this selector. If set with NamespaceSelector,
+	// Pods are matched from Namespaces matched by the NamespaceSelector;
+	// otherwise, Pods are matched from all Namespaces.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// Select all Pods from Namespaces matched by this selector. If set with
+	// PodSelector, Pods are matched from Namespaces matched by the
+	// NamespaceSelector.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type EgressList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Egress `json:"items,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalIPPool defines a set of IPs of the external network. The IPs are
+// allocated to the Egresses referencing the pool, and assigned to the Nodes
+// selected by the pool.
+type ExternalIPPool struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the desired behavior of ExternalIPPool.
+	Spec ExternalIPPoolSpec `json:"spec"`
+}
+
+// ExternalIPPoolSpec defines the desired state for ExternalIPPool.
+type ExternalIPPoolSpec struct {
+	// IPRanges are the IP ranges of the pool.
+	IPRanges []IPRange `json:"ipRanges"`
+	// NodeSelector selects the Nodes the IPs of the pool can be assigned to.
+	// An empty selector selects all Nodes.
+	NodeSelector metav1.LabelSelector `json:"nodeSelector"`
+}
+
+// IPRange is a set of contiguous IP addresses, defined either by a CIDR or by
+// a start and an end IP.
+type IPRange struct {
+	// The CIDR of the range. The network and broadcast addresses are
+	// excluded.
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+	// The first IP of the range, included.
+	// +optional
+	Start string `json:"start,omitempty"`
+	// The last IP of the range, included.
+	// +optional
+	End string `json:"end,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ExternalIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ExternalIPPool `json:"items,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPPool defines a set of IPs of a routable subnet. When the AntreaIPAM feature
+// is enabled, the IPs of the Pods of the Namespaces annotated with the name of
+// the pool are allocated from the pool, instead of the PodCIDR of their Node.
+type IPPool struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the desired behavior of IPPool.
+	Spec IPPoolSpec `json:"spec"`
+	// Most recently observed status of the pool.
+	Status IPPoolStatus `json:"status,omitempty"`
+}
+
+// IPPoolSpec defines the desired state for IPPool.
+type IPPoolSpec struct {
+	// IPRanges are the IP ranges of the pool.
+	IPRanges []IPRange `json:"ipRanges"`
+	// Gateway is the gateway IP of the subnet of the pool, which is used
+	// as the default gateway of the Pods.
+	Gateway string `json:"gateway"`
+	// PrefixLength is the prefix length of the subnet of the pool.
+	PrefixLength int32 `json:"prefixLength"`
+}
+
+// IPPoolStatus represents the IPs allocated from an IPPool. It's updated by the
+// antrea-agents when they allocate or release IPs.
+type IPPoolStatus struct {
+	IPAddresses []IPAddressState `json:"ipAddresses,omitempty"`
+}
+
+// IPAddressState is an IP allocated from an IPPool.
+type IPAddressState struct {
+	// IPAddress is the allocated IP.
+	IPAddress string `json:"ipAddress"`
+	// Owner is the Pod container the IP is allocated to.
+	Owner IPAddressOwner `json:"owner"`
+}
+
+// IPAddressOwner identifies the Pod container an IP is allocated to.
+type IPAddressOwner struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// ContainerID is empty when the IP is reserved for a StatefulSet Pod
+	// which is not running.
+	ContainerID string `json:"containerID"`
+	// StatefulSet is the name of the StatefulSet of the Pod. The IPs of the
+	// StatefulSet Pods are reserved for the Pods when they are deleted, so
+	// that they get the same IPs when they are recreated.
+	// +optional
+	StatefulSet string `json:"statefulSet,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type IPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []IPPool `json:"items,omitempty"`
+}
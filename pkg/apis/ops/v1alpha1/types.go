@@ -35,6 +35,7 @@ const (
 	Routing       TraceflowComponent = "Routing"
 	NetworkPolicy TraceflowComponent = "NetworkPolicy"
 	Forwarding    TraceflowComponent = "Forwarding"
+	Egress        TraceflowComponent = "Egress"
 )
 
 type TraceflowAction string
@@ -44,6 +45,9 @@ const (
 	Received  TraceflowAction = "Received"
 	Forwarded TraceflowAction = "Forwarded"
 	Dropped   TraceflowAction = "Dropped"
+	// ForwardedOutOfOverlay means the packet left the Pod network through
+	// the Node's uplink towards a destination outside the cluster.
+	ForwardedOutOfOverlay TraceflowAction = "ForwardedOutOfOverlay"
 )
 
 // List the supported protocols and their codes in traceflow.
@@ -213,6 +217,12 @@ type Observation struct {
 	TranslatedDstIP string `json:"translatedDstIP,omitempty" yaml:"translatedDstIP,omitempty"`
 	// TunnelDstIP is the tunnel destination IP.
 	TunnelDstIP string `json:"tunnelDstIP,omitempty" yaml:"tunnelDstIP,omitempty"`
+	// EgressInterface is the Node interface the packet is sent out of when
+	// leaving the cluster.
+	EgressInterface string `json:"egressInterface,omitempty" yaml:"egressInterface,omitempty"`
+	// NextHopIP is the gateway selected by the Node routing table when the
+	// packet leaves the cluster. It is empty for on-link destinations.
+	NextHopIP string `json:"nextHopIP,omitempty" yaml:"nextHopIP,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -21,7 +21,6 @@ import (
 )
 
 // +genclient
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type NetworkPolicy struct {
@@ -31,6 +30,8 @@ type NetworkPolicy struct {
 
 	// Specification of the desired behavior of NetworkPolicy.
 	Spec NetworkPolicySpec `json:"spec"`
+	// Most recently observed status of the NetworkPolicy.
+	Status NetworkPolicyStatus `json:"status,omitempty"`
 }
 
 // NetworkPolicySpec defines the desired state for NetworkPolicy.
@@ -56,6 +57,10 @@ type NetworkPolicySpec struct {
 	// field within a Rule.
 	// +optional
 	Egress []Rule `json:"egress"`
+	// EnforcementMode specifies whether the rules of the policy are enforced
+	// or only monitored. Defaults to Enforce.
+	// +optional
+	EnforcementMode EnforcementMode `json:"enforcementMode,omitempty"`
 }
 
 // Rule describes the traffic allowed to/from the workloads selected by
@@ -68,6 +73,13 @@ type Rule struct {
 	// or empty, this rule matches all ports.
 	// +optional
 	Ports []NetworkPolicyPort `json:"ports"`
+	// Set of non-port protocols, i.e. ICMP, IGMP and other IP protocols
+	// identified by their number, allowed/denied by the
+	// rule. Traffic matches the rule if it matches any of Ports or
+	// Protocols. If both fields are unset or empty, this rule matches all
+	// protocols and ports.
+	// +optional
+	Protocols []NetworkPolicyProtocol `json:"protocols,omitempty"`
 	// Rule is matched if traffic originates from workloads selected by
 	// this field. If this field is empty, this rule matches all sources.
 	// +optional
@@ -77,8 +89,165 @@ type Rule struct {
 	// destinations.
 	// +optional
 	To []NetworkPolicyPeer `json:"to"`
+	// Rule is matched if traffic is intended for the ClusterIPs of the
+	// Services referenced by this field. It can only be set in egress rules,
+	// and is matched in addition to the destinations selected by To.
+	// +optional
+	ToServices []ServiceReference `json:"toServices,omitempty"`
+	// Schedule restricts the rule to be realized only during the specified
+	// time windows. If this field is not set, the rule is always active.
+	// +optional
+	Schedule *RuleSchedule `json:"schedule,omitempty"`
+	// EnableLogging indicates whether the Antrea Agents should write an
+	// audit log entry for each connection matched by the rule.
+	// +optional
+	EnableLogging bool `json:"enableLogging,omitempty"`
+	// RateLimit limits the rate of the new connections matched by the rule.
+	// It can only be set in Allow rules.
+	// +optional
+	RateLimit *RuleRateLimit `json:"rateLimit,omitempty"`
+}
+
+// RuleRateLimit limits the rate of the new connections matched by a rule. The
+// limit is enforced by each Antrea Agent for the connections it processes, so
+// it applies per Node. The new connections above the limit are dropped.
+type RuleRateLimit struct {
+	// MaxNewConnectionsPerSecond is the maximum rate of new connections
+	// matched by the rule.
+	MaxNewConnectionsPerSecond int32 `json:"maxNewConnectionsPerSecond"`
+	// Burst is the number of new connections which can exceed the rate at
+	// once. If it is not set, no burst is allowed.
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// ServiceReference is a reference to a Kubernetes Service.
+type ServiceReference struct {
+	// Name of the Service.
+	Name string `json:"name"`
+	// Namespace of the Service. It must be set in the rules of Antrea
+	// ClusterNetworkPolicies, and must be left empty in the rules of Antrea
+	// NetworkPolicies, which can only reference Services of their own
+	// Namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RuleSchedule describes the time windows during which a rule is active.
+type RuleSchedule struct {
+	// Windows is a list of time windows. The rule is active if the current
+	// time falls within any of them.
+	Windows []TimeWindow `json:"windows"`
+	// TimeZone is the IANA name of the time zone used to interpret the
+	// Windows, e.g. "America/Los_Angeles". If not specified, UTC is used.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// TimeWindow describes a daily time window, optionally restricted to some
+// days of the week.
+type TimeWindow struct {
+	// Start is the time of day at which the window opens, in "HH:MM" format.
+	Start string `json:"start"`
+	// End is the time of day at which the window closes, in "HH:MM" format.
+	// If End is earlier than Start, the window spans midnight and closes
+	// on the next day. End must differ from Start.
+	End string `json:"end"`
+	// DaysOfWeek restricts the window to the days on which it opens, using
+	// three-letter abbreviations ("Mon", "Tue", ...). If empty, the window
+	// opens every day.
+	// +optional
+	DaysOfWeek []string `json:"daysOfWeek,omitempty"`
 }
 
+// NetworkPolicyStatus represents information about the status of a
+// NetworkPolicy or ClusterNetworkPolicy.
+type NetworkPolicyStatus struct {
+	// Phase is the realization phase of the policy.
+	// +optional
+	Phase NetworkPolicyPhase `json:"phase,omitempty"`
+	// ObservedGeneration is the generation of the policy whose realization
+	// is reported by CurrentNodesRealized.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// CurrentNodesRealized is the number of Nodes which have realized
+	// ObservedGeneration of the policy.
+	// +optional
+	CurrentNodesRealized int32 `json:"currentNodesRealized,omitempty"`
+	// DesiredNodesRealized is the number of Nodes to which the policy
+	// applies.
+	// +optional
+	DesiredNodesRealized int32 `json:"desiredNodesRealized,omitempty"`
+	// ScheduledRules reports the current state of the rules which have a
+	// Schedule.
+	// +optional
+	ScheduledRules []ScheduledRuleStatus `json:"scheduledRules,omitempty"`
+	// FailedRules reports the rules of ObservedGeneration which some Nodes
+	// failed to realize.
+	// +optional
+	FailedRules []FailedRuleStatus `json:"failedRules,omitempty"`
+}
+
+// NetworkPolicyPhase defines the phase in which a policy is.
+type NetworkPolicyPhase string
+
+// These are the valid values for NetworkPolicyPhase.
+const (
+	// NetworkPolicyRealizing means the policy has been observed by Antrea
+	// and is being realized.
+	NetworkPolicyRealizing NetworkPolicyPhase = "Realizing"
+	// NetworkPolicyRealized means the policy has been enforced on all the
+	// Nodes to which it applies.
+	NetworkPolicyRealized NetworkPolicyPhase = "Realized"
+	// NetworkPolicyFailed means some Nodes to which the policy applies
+	// failed to realize some of its rules.
+	NetworkPolicyFailed NetworkPolicyPhase = "Failed"
+)
+
+// RuleDirection describes the direction of a rule within a policy.
+type RuleDirection string
+
+const (
+	// RuleDirectionIngress describes a rule in the Ingress list.
+	RuleDirectionIngress RuleDirection = "Ingress"
+	// RuleDirectionEgress describes a rule in the Egress list.
+	RuleDirectionEgress RuleDirection = "Egress"
+)
+
+// ScheduledRuleStatus describes whether a scheduled rule is currently active.
+type ScheduledRuleStatus struct {
+	// Direction is the direction of the rule.
+	Direction RuleDirection `json:"direction"`
+	// Index is the position of the rule in the Ingress or Egress list.
+	Index int32 `json:"index"`
+	// Active is true if the rule is currently realized.
+	Active bool `json:"active"`
+	// LastTransitionTime is the last time the rule became active or inactive.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// FailedRuleStatus describes a rule which some Nodes failed to realize.
+type FailedRuleStatus struct {
+	// Direction is the direction of the rule.
+	Direction RuleDirection `json:"direction"`
+	// Index is the position of the rule in the Ingress or Egress list.
+	Index int32 `json:"index"`
+	// FailedNodes is the number of Nodes which failed to realize the rule.
+	FailedNodes int32 `json:"failedNodes"`
+	// NodeName is the name of the first of these Nodes in alphabetical
+	// order.
+	NodeName string `json:"nodeName"`
+	// Message is the error reported by NodeName.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PodUIDLabelKey is the reserved label key which selects a Pod by its UID in
+// the PodSelector of a NetworkPolicyPeer. The label is not set on the Pods: it
+// is matched against their UID, which cannot be changed, unlike their labels.
+const PodUIDLabelKey = "internal.antrea.tanzu.vmware.com/pod-uid"
+
 // NetworkPolicyPeer describes the grouping selector of workloads.
 type NetworkPolicyPeer struct {
 	// IPBlock describes the IPAddresses/IPBlocks that is matched in to/from.
@@ -105,6 +274,38 @@ type NetworkPolicyPeer struct {
 	// NamespaceSelector.
 	// Cannot be set with any other selector except NamespaceSelector.
 	ExternalEntitySelector *metav1.LabelSelector `json:"externalEntitySelector,omitempty"`
+	// Select the Nodes matched by this selector as workloads in AppliedTo
+	// fields, so that the rules are enforced on the traffic to and from the
+	// host network of the Nodes. NodeSelector can only be set in the
+	// AppliedTo of Antrea ClusterNetworkPolicies.
+	// Cannot be set with any other selector or IPBlock.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	// Select the members of the Group with this name, from the Antrea
+	// NetworkPolicy's Namespace, as workloads in To/From fields. Group can
+	// only be set in the rules of Antrea NetworkPolicies.
+	// Cannot be set with any other selector or IPBlock.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Select the IP addresses resolved for the domain names matched by this
+	// FQDN, e.g. "www.example.com" or "*.example.com", as workloads in To
+	// fields. FQDN can only be set in the egress rules of Antrea
+	// ClusterNetworkPolicies.
+	// Cannot be set with any other selector or IPBlock.
+	// +optional
+	FQDN string `json:"fqdn,omitempty"`
+	// Select the Pods running with this ServiceAccount as workloads in
+	// AppliedTo/To/From fields. ServiceAccount can only be set in Antrea
+	// ClusterNetworkPolicies.
+	// Cannot be set with any other selector or IPBlock.
+	// +optional
+	ServiceAccount *NamespacedName `json:"serviceAccount,omitempty"`
+}
+
+// NamespacedName refers to a namespaced resource by its Namespace and name.
+type NamespacedName struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24") that is allowed
@@ -113,6 +314,13 @@ type IPBlock struct {
 	// CIDR is a string representing the IP Block
 	// Valid examples are "192.168.1.1/24".
 	CIDR string `json:"cidr"`
+	// Except is a slice of CIDRs that should not be included within an IP
+	// Block. Except values will be rejected if they are outside the CIDR
+	// range. Except can only be set in the rules with the Allow action: the
+	// traffic from or to the excluded CIDRs is dropped, as if a Drop rule for
+	// them was placed right above the rule.
+	// +optional
+	Except []string `json:"except,omitempty"`
 }
 
 // NetworkPolicyPort describes the port and protocol to match in a rule.
@@ -129,6 +337,42 @@ type NetworkPolicyPort struct {
 	Port *intstr.IntOrString `json:"port"`
 }
 
+// NetworkPolicyProtocol describes a protocol without ports to match in a
+// rule. Exactly one of ICMP, IGMP and IP must be set.
+type NetworkPolicyProtocol struct {
+	// ICMP matches ICMP traffic, optionally restricted to an ICMP type and
+	// code.
+	// +optional
+	ICMP *ICMPProtocol `json:"icmp,omitempty"`
+	// IGMP matches IGMP traffic.
+	// +optional
+	IGMP *IGMPProtocol `json:"igmp,omitempty"`
+	// IP matches the traffic of an IP protocol identified by its IANA
+	// number, e.g. 47 for GRE or 112 for VRRP.
+	// +optional
+	IP *IPProtocol `json:"ip,omitempty"`
+}
+
+// ICMPProtocol describes the ICMP messages to match. If ICMPType is not set,
+// all ICMP messages are matched. ICMPCode can only be set with ICMPType.
+type ICMPProtocol struct {
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty"`
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty"`
+}
+
+// IGMPProtocol describes the IGMP messages to match. All IGMP messages are
+// matched.
+type IGMPProtocol struct{}
+
+// IPProtocol describes the IP protocol to match by its IANA number. TCP, UDP
+// and SCTP must be matched with ports, and ICMP and IGMP with their own
+// fields.
+type IPProtocol struct {
+	Protocol int32 `json:"protocol"`
+}
+
 // RuleAction describes the action to be applied on traffic matching a rule.
 type RuleAction string
 
@@ -137,6 +381,26 @@ const (
 	RuleActionAllow RuleAction = "Allow"
 	// RuleActionDrop describes that rule matching traffic must be dropped.
 	RuleActionDrop RuleAction = "Drop"
+	// RuleActionAudit describes that rule matching traffic must be audit
+	// logged and counted, but not enforced: the traffic is still evaluated by
+	// the rules of lower priority. It can be used to validate rules before
+	// enforcing them.
+	RuleActionAudit RuleAction = "Audit"
+)
+
+// EnforcementMode describes how the rules of an Antrea-native policy are
+// realized.
+type EnforcementMode string
+
+const (
+	// EnforcementModeEnforce describes that the rules of the policy are
+	// enforced according to their action.
+	EnforcementModeEnforce EnforcementMode = "Enforce"
+	// EnforcementModeMonitor describes that the rules of the policy are not
+	// enforced: the traffic they match is counted in their statistics, the
+	// traffic which would be dropped is audit logged, and the traffic is then
+	// evaluated by the rules of lower priority as if the policy did not exist.
+	EnforcementModeMonitor EnforcementMode = "Monitor"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -151,7 +415,6 @@ type NetworkPolicyList struct {
 
 // +genclient
 // +genclient:nonNamespaced
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 type ClusterNetworkPolicy struct {
@@ -161,6 +424,8 @@ type ClusterNetworkPolicy struct {
 
 	// Specification of the desired behavior of ClusterNetworkPolicy.
 	Spec ClusterNetworkPolicySpec `json:"spec"`
+	// Most recently observed status of the ClusterNetworkPolicy.
+	Status NetworkPolicyStatus `json:"status,omitempty"`
 }
 
 // ClusterNetworkPolicySpec defines the desired state for ClusterNetworkPolicy.
@@ -186,6 +451,10 @@ type ClusterNetworkPolicySpec struct {
 	// field within a Rule.
 	// +optional
 	Egress []Rule `json:"egress"`
+	// EnforcementMode specifies whether the rules of the policy are enforced
+	// or only monitored. Defaults to Enforce.
+	// +optional
+	EnforcementMode EnforcementMode `json:"enforcementMode,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -27,6 +27,8 @@ type AntreaClusterNetworkPolicyStats struct {
 
 	// The traffic stats of the Antrea ClusterNetworkPolicy.
 	TrafficStats TrafficStats
+	// The traffic stats of the rules of the Antrea ClusterNetworkPolicy.
+	RuleTrafficStats []RuleTrafficStats
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -49,6 +51,8 @@ type AntreaNetworkPolicyStats struct {
 
 	// The traffic stats of the Antrea NetworkPolicy.
 	TrafficStats TrafficStats
+	// The traffic stats of the rules of the Antrea NetworkPolicy.
+	RuleTrafficStats []RuleTrafficStats
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -84,6 +88,65 @@ type NetworkPolicyStatsList struct {
 	Items []NetworkPolicyStats
 }
 
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NamespaceNetworkSummary is the network state of a Namespace, which is named after the Namespace: the traffic stats
+// of its NetworkPolicies and the connections of its Pods.
+type NamespaceNetworkSummary struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// The traffic stats of the K8s NetworkPolicies of the Namespace.
+	NetworkPolicies []PolicyTrafficStats
+	// The traffic stats of the Antrea NetworkPolicies of the Namespace.
+	AntreaNetworkPolicies []PolicyTrafficStats
+	// The summaries of the connections of the Pods of the Namespace.
+	Flows []FlowSummary
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NamespaceNetworkSummaryList is a list of NamespaceNetworkSummary.
+type NamespaceNetworkSummaryList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	// List of NamespaceNetworkSummary.
+	Items []NamespaceNetworkSummary
+}
+
+// PolicyTrafficStats contains the traffic stats of a NetworkPolicy of a Namespace.
+type PolicyTrafficStats struct {
+	// Name is the name of the NetworkPolicy.
+	Name string
+	// TrafficStats is the traffic stats of the NetworkPolicy.
+	TrafficStats TrafficStats
+	// RuleTrafficStats is the traffic stats of the rules of the NetworkPolicy. It is only set for Antrea-native
+	// policies.
+	RuleTrafficStats []RuleTrafficStats
+}
+
+// FlowSummary summarizes the connections of a Pod with a peer, on a protocol and a destination port.
+type FlowSummary struct {
+	// Namespace is the Namespace of the Pod.
+	Namespace string
+	// Pod is the name of the Pod.
+	Pod string
+	// Direction is Ingress for the connections to the Pod, and Egress for the connections from the Pod.
+	Direction RuleDirection
+	// Peer is the other end of the connections: "<Namespace>/<Pod>" for a Pod running on the same Node,
+	// "<Namespace>/<Service>:<port>" for a Service, or the subnet of the IP otherwise. The Pods and Services of
+	// other Namespaces are "<other Namespace>", and "<others>" aggregates the peers with the least traffic.
+	Peer string
+	// Protocol is the protocol of the connections, e.g. TCP.
+	Protocol string
+	// Port is the destination port of the connections. It is 0 for the protocols without ports.
+	Port int32
+	// TrafficStats is the traffic stats of the connections, in both directions. Sessions is the number of
+	// connections.
+	TrafficStats TrafficStats
+}
+
 // TrafficStats contains the traffic stats of a NetworkPolicy.
 type TrafficStats struct {
 	// Packets is the packets count hit by the NetworkPolicy.
@@ -93,3 +156,21 @@ type TrafficStats struct {
 	// Sessions is the sessions count hit by the NetworkPolicy.
 	Sessions int64
 }
+
+// RuleDirection is the direction of a rule of an Antrea-native policy.
+type RuleDirection string
+
+const (
+	RuleDirectionIngress RuleDirection = "Ingress"
+	RuleDirectionEgress  RuleDirection = "Egress"
+)
+
+// RuleTrafficStats contains the traffic stats of a rule of an Antrea-native policy.
+type RuleTrafficStats struct {
+	// Direction is the direction of the rule.
+	Direction RuleDirection
+	// Index is the index of the rule in the ingress or egress rules of the policy.
+	Index int32
+	// TrafficStats is the traffic stats of the rule.
+	TrafficStats TrafficStats
+}
@@ -152,10 +152,94 @@ func (m *AntreaNetworkPolicyStatsList) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AntreaNetworkPolicyStatsList proto.InternalMessageInfo
 
+func (m *FlowSummary) Reset()      { *m = FlowSummary{} }
+func (*FlowSummary) ProtoMessage() {}
+func (*FlowSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87568b32f9b1aa25, []int{4}
+}
+func (m *FlowSummary) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FlowSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *FlowSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FlowSummary.Merge(m, src)
+}
+func (m *FlowSummary) XXX_Size() int {
+	return m.Size()
+}
+func (m *FlowSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_FlowSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FlowSummary proto.InternalMessageInfo
+
+func (m *NamespaceNetworkSummary) Reset()      { *m = NamespaceNetworkSummary{} }
+func (*NamespaceNetworkSummary) ProtoMessage() {}
+func (*NamespaceNetworkSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87568b32f9b1aa25, []int{5}
+}
+func (m *NamespaceNetworkSummary) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamespaceNetworkSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NamespaceNetworkSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceNetworkSummary.Merge(m, src)
+}
+func (m *NamespaceNetworkSummary) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamespaceNetworkSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceNetworkSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceNetworkSummary proto.InternalMessageInfo
+
+func (m *NamespaceNetworkSummaryList) Reset()      { *m = NamespaceNetworkSummaryList{} }
+func (*NamespaceNetworkSummaryList) ProtoMessage() {}
+func (*NamespaceNetworkSummaryList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87568b32f9b1aa25, []int{6}
+}
+func (m *NamespaceNetworkSummaryList) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamespaceNetworkSummaryList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NamespaceNetworkSummaryList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceNetworkSummaryList.Merge(m, src)
+}
+func (m *NamespaceNetworkSummaryList) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamespaceNetworkSummaryList) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceNetworkSummaryList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceNetworkSummaryList proto.InternalMessageInfo
+
 func (m *NetworkPolicyStats) Reset()      { *m = NetworkPolicyStats{} }
 func (*NetworkPolicyStats) ProtoMessage() {}
 func (*NetworkPolicyStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_87568b32f9b1aa25, []int{4}
+	return fileDescriptor_87568b32f9b1aa25, []int{7}
 }
 func (m *NetworkPolicyStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -183,7 +267,7 @@ var xxx_messageInfo_NetworkPolicyStats proto.InternalMessageInfo
 func (m *NetworkPolicyStatsList) Reset()      { *m = NetworkPolicyStatsList{} }
 func (*NetworkPolicyStatsList) ProtoMessage() {}
 func (*NetworkPolicyStatsList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_87568b32f9b1aa25, []int{5}
+	return fileDescriptor_87568b32f9b1aa25, []int{8}
 }
 func (m *NetworkPolicyStatsList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -208,10 +292,66 @@ func (m *NetworkPolicyStatsList) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NetworkPolicyStatsList proto.InternalMessageInfo
 
+func (m *PolicyTrafficStats) Reset()      { *m = PolicyTrafficStats{} }
+func (*PolicyTrafficStats) ProtoMessage() {}
+func (*PolicyTrafficStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87568b32f9b1aa25, []int{9}
+}
+func (m *PolicyTrafficStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PolicyTrafficStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *PolicyTrafficStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyTrafficStats.Merge(m, src)
+}
+func (m *PolicyTrafficStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *PolicyTrafficStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyTrafficStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyTrafficStats proto.InternalMessageInfo
+
+func (m *RuleTrafficStats) Reset()      { *m = RuleTrafficStats{} }
+func (*RuleTrafficStats) ProtoMessage() {}
+func (*RuleTrafficStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_87568b32f9b1aa25, []int{10}
+}
+func (m *RuleTrafficStats) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RuleTrafficStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *RuleTrafficStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuleTrafficStats.Merge(m, src)
+}
+func (m *RuleTrafficStats) XXX_Size() int {
+	return m.Size()
+}
+func (m *RuleTrafficStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuleTrafficStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuleTrafficStats proto.InternalMessageInfo
+
 func (m *TrafficStats) Reset()      { *m = TrafficStats{} }
 func (*TrafficStats) ProtoMessage() {}
 func (*TrafficStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_87568b32f9b1aa25, []int{6}
+	return fileDescriptor_87568b32f9b1aa25, []int{11}
 }
 func (m *TrafficStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -241,8 +381,13 @@ func init() {
 	proto.RegisterType((*AntreaClusterNetworkPolicyStatsList)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.AntreaClusterNetworkPolicyStatsList")
 	proto.RegisterType((*AntreaNetworkPolicyStats)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.AntreaNetworkPolicyStats")
 	proto.RegisterType((*AntreaNetworkPolicyStatsList)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.AntreaNetworkPolicyStatsList")
+	proto.RegisterType((*FlowSummary)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.FlowSummary")
+	proto.RegisterType((*NamespaceNetworkSummary)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.NamespaceNetworkSummary")
+	proto.RegisterType((*NamespaceNetworkSummaryList)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.NamespaceNetworkSummaryList")
 	proto.RegisterType((*NetworkPolicyStats)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.NetworkPolicyStats")
 	proto.RegisterType((*NetworkPolicyStatsList)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.NetworkPolicyStatsList")
+	proto.RegisterType((*PolicyTrafficStats)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.PolicyTrafficStats")
+	proto.RegisterType((*RuleTrafficStats)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.RuleTrafficStats")
 	proto.RegisterType((*TrafficStats)(nil), "github.com.vmware_tanzu.antrea.pkg.apis.stats.v1alpha1.TrafficStats")
 }
 
@@ -251,41 +396,64 @@ func init() {
 }
 
 var fileDescriptor_87568b32f9b1aa25 = []byte{
-	// 542 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x52, 0xcf, 0x8b, 0xd3, 0x40,
-	0x18, 0xed, 0xb4, 0x94, 0x2d, 0xb3, 0x2b, 0x4a, 0x10, 0x29, 0x45, 0x52, 0xe9, 0x5e, 0x56, 0x70,
-	0x67, 0xec, 0x22, 0x8b, 0x57, 0xa3, 0x08, 0x8a, 0x3f, 0x4a, 0x56, 0x10, 0x44, 0xd0, 0x69, 0x76,
-	0x9a, 0x8e, 0x6d, 0x32, 0x21, 0xf3, 0xa5, 0x4b, 0x45, 0x44, 0xf1, 0xea, 0xc1, 0x3f, 0xab, 0xc7,
-	0x3d, 0xee, 0x69, 0xb1, 0xf1, 0xe0, 0x1f, 0x20, 0x78, 0xf2, 0x20, 0x99, 0xb4, 0x4d, 0x6a, 0x59,
-	0x5a, 0x2a, 0xe8, 0x61, 0x6f, 0x99, 0x6f, 0xe6, 0xbd, 0xf7, 0xbd, 0xf7, 0x82, 0xef, 0xbb, 0x02,
-	0xba, 0x51, 0x9b, 0x38, 0xd2, 0xa3, 0x03, 0xef, 0x88, 0x85, 0x7c, 0x17, 0x98, 0xff, 0x36, 0xa2,
-	0xcc, 0x87, 0x90, 0x33, 0x1a, 0xf4, 0x5c, 0xca, 0x02, 0xa1, 0xa8, 0x02, 0x06, 0x8a, 0x0e, 0x9a,
-	0xac, 0x1f, 0x74, 0x59, 0x93, 0xba, 0xdc, 0xe7, 0x21, 0x03, 0x7e, 0x48, 0x82, 0x50, 0x82, 0x34,
-	0xf6, 0x33, 0x1e, 0x92, 0xf2, 0xbc, 0xd2, 0x3c, 0x24, 0xe5, 0x21, 0x41, 0xcf, 0x25, 0x09, 0x0f,
-	0xd1, 0x3c, 0x64, 0xca, 0x53, 0xdb, 0xcd, 0xe9, 0xbb, 0xd2, 0x95, 0x54, 0xd3, 0xb5, 0xa3, 0x8e,
-	0x3e, 0xe9, 0x83, 0xfe, 0x4a, 0x65, 0x6a, 0xb7, 0x7a, 0xb7, 0x15, 0x11, 0x32, 0x59, 0xc9, 0x63,
-	0x4e, 0x57, 0xf8, 0x3c, 0x1c, 0x66, 0x3b, 0x7a, 0x1c, 0x18, 0x1d, 0x2c, 0x2c, 0x57, 0xa3, 0x67,
-	0xa1, 0xc2, 0xc8, 0x07, 0xe1, 0xf1, 0x05, 0xc0, 0xfe, 0x32, 0x80, 0x72, 0xba, 0xdc, 0x63, 0x7f,
-	0xe2, 0x1a, 0x9f, 0x8a, 0xb8, 0x7e, 0x47, 0x1b, 0xbe, 0xdb, 0x8f, 0x14, 0xf0, 0xf0, 0x09, 0x87,
-	0x23, 0x19, 0xf6, 0x5a, 0xb2, 0x2f, 0x9c, 0xe1, 0x41, 0x62, 0xdd, 0x78, 0x8d, 0x2b, 0xc9, 0x9e,
-	0x87, 0x0c, 0x58, 0x15, 0x5d, 0x43, 0x3b, 0x9b, 0x7b, 0x37, 0x49, 0x2a, 0x47, 0xf2, 0x72, 0x59,
-	0x62, 0xc9, 0x6b, 0x32, 0x68, 0x92, 0xa7, 0xed, 0x37, 0xdc, 0x81, 0xc7, 0x1c, 0x98, 0x65, 0x8c,
-	0x4e, 0xeb, 0x85, 0xf8, 0xb4, 0x8e, 0xb3, 0x99, 0x3d, 0x63, 0x35, 0xde, 0xe3, 0x2d, 0x08, 0x59,
-	0xa7, 0x23, 0x1c, 0xad, 0x58, 0x2d, 0x6a, 0x95, 0x7b, 0x64, 0xbd, 0x8a, 0xc8, 0xb3, 0x1c, 0x97,
-	0x75, 0x79, 0xa2, 0xbc, 0x95, 0x9f, 0xda, 0x73, 0x7a, 0x8d, 0x8f, 0x45, 0xbc, 0xbd, 0x24, 0x85,
-	0x47, 0x42, 0x81, 0xf1, 0x72, 0x21, 0x09, 0xb2, 0x5a, 0x12, 0x09, 0x5a, 0xe7, 0x70, 0x69, 0xb2,
-	0x4d, 0x65, 0x3a, 0xc9, 0xa5, 0xf0, 0x0e, 0x97, 0x05, 0x70, 0x2f, 0xb1, 0x5f, 0xda, 0xd9, 0xdc,
-	0x7b, 0xbe, 0xae, 0xfd, 0x25, 0x4e, 0xac, 0x0b, 0x93, 0x1d, 0xca, 0x0f, 0x12, 0x35, 0x3b, 0x15,
-	0x6d, 0xfc, 0x42, 0xb8, 0x9a, 0x22, 0xcf, 0xe5, 0x2f, 0xf0, 0x03, 0xe1, 0xab, 0x67, 0xd9, 0xff,
-	0x07, 0xdd, 0x47, 0xf3, 0xdd, 0xb7, 0xfe, 0xae, 0xfb, 0x95, 0x4b, 0xff, 0x89, 0xb0, 0x71, 0x2e,
-	0xeb, 0xfe, 0x8e, 0xf0, 0x95, 0xff, 0x52, 0xb4, 0x9c, 0x2f, 0xfa, 0xe1, 0xba, 0x8e, 0x57, 0xae,
-	0xf8, 0x33, 0xc2, 0x73, 0x41, 0x18, 0xd7, 0xf1, 0x46, 0xc0, 0x9c, 0x1e, 0x07, 0xa5, 0xed, 0x95,
-	0xac, 0x8b, 0x13, 0xdc, 0x46, 0x2b, 0x1d, 0xdb, 0xd3, 0x7b, 0x63, 0x1b, 0x97, 0xdb, 0x43, 0xe0,
-	0x69, 0x3d, 0xa5, 0x4c, 0xc0, 0x4a, 0x86, 0x76, 0x7a, 0x67, 0xdc, 0xc0, 0x15, 0xc5, 0x95, 0x12,
-	0xd2, 0x57, 0xd5, 0x92, 0x7e, 0x37, 0xf3, 0x7f, 0x30, 0x99, 0xdb, 0xb3, 0x17, 0x16, 0x19, 0x8d,
-	0xcd, 0xc2, 0xf1, 0xd8, 0x2c, 0x9c, 0x8c, 0xcd, 0xc2, 0x87, 0xd8, 0x44, 0xa3, 0xd8, 0x44, 0xc7,
-	0xb1, 0x89, 0x4e, 0x62, 0x13, 0x7d, 0x8d, 0x4d, 0xf4, 0xe5, 0x9b, 0x59, 0x78, 0x51, 0x99, 0xfa,
-	0xfc, 0x1d, 0x00, 0x00, 0xff, 0xff, 0x80, 0xc7, 0x0a, 0x29, 0xef, 0x07, 0x00, 0x00,
+	// 899 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x56, 0xdf, 0x6b, 0xe4, 0x44,
+	0x1c, 0xdf, 0x69, 0x76, 0x6d, 0x77, 0xda, 0xa3, 0x75, 0xf0, 0xbc, 0x50, 0xbd, 0xb4, 0xec, 0xbd,
+	0x54, 0xf0, 0x26, 0xf6, 0x90, 0xc3, 0x47, 0x2f, 0x77, 0x1c, 0x2a, 0xda, 0x5b, 0xa6, 0x82, 0x20,
+	0x82, 0x4e, 0xb3, 0xd3, 0xdd, 0xb8, 0x9b, 0x4c, 0x98, 0x4c, 0x5a, 0x57, 0x44, 0xf4, 0xdd, 0x13,
+	0x05, 0xc1, 0x47, 0xff, 0x1c, 0xfb, 0x78, 0x8f, 0xf7, 0x54, 0xec, 0xfa, 0xe0, 0xb3, 0x08, 0x82,
+	0x3e, 0xc9, 0x4c, 0x92, 0x4d, 0xb2, 0xb9, 0xa5, 0x25, 0xea, 0x2a, 0x5c, 0xdf, 0x76, 0xbf, 0x3f,
+	0x3e, 0x9f, 0xef, 0xef, 0x09, 0xbc, 0xdf, 0xf7, 0xe4, 0x20, 0x3e, 0xc0, 0x2e, 0xf7, 0xed, 0x23,
+	0xff, 0x98, 0x0a, 0x76, 0x53, 0xd2, 0xe0, 0xd3, 0xd8, 0xa6, 0x81, 0x14, 0x8c, 0xda, 0xe1, 0xb0,
+	0x6f, 0xd3, 0xd0, 0x8b, 0xec, 0x48, 0x52, 0x19, 0xd9, 0x47, 0xbb, 0x74, 0x14, 0x0e, 0xe8, 0xae,
+	0xdd, 0x67, 0x01, 0x13, 0x54, 0xb2, 0x1e, 0x0e, 0x05, 0x97, 0x1c, 0xdd, 0xce, 0x71, 0x70, 0x82,
+	0xf3, 0xa1, 0xc6, 0xc1, 0x09, 0x0e, 0x0e, 0x87, 0x7d, 0xac, 0x70, 0xb0, 0xc6, 0xc1, 0x19, 0xce,
+	0xe6, 0xcd, 0x02, 0x7f, 0x9f, 0xf7, 0xb9, 0xad, 0xe1, 0x0e, 0xe2, 0x43, 0xfd, 0x4f, 0xff, 0xd1,
+	0xbf, 0x12, 0x9a, 0xcd, 0x57, 0x87, 0xaf, 0x45, 0xd8, 0xe3, 0x2a, 0x24, 0x9f, 0xba, 0x03, 0x2f,
+	0x60, 0x62, 0x9c, 0xc7, 0xe8, 0x33, 0x49, 0xed, 0xa3, 0x4a, 0x70, 0x9b, 0xf6, 0x3c, 0x2f, 0x11,
+	0x07, 0xd2, 0xf3, 0x59, 0xc5, 0xe1, 0xf6, 0x79, 0x0e, 0x91, 0x3b, 0x60, 0x3e, 0x9d, 0xf5, 0xeb,
+	0x7c, 0x67, 0xc0, 0xad, 0x3b, 0x3a, 0xe1, 0xbb, 0xa3, 0x38, 0x92, 0x4c, 0xec, 0x31, 0x79, 0xcc,
+	0xc5, 0xb0, 0xcb, 0x47, 0x9e, 0x3b, 0xde, 0x57, 0xa9, 0xa3, 0x8f, 0xe0, 0x8a, 0x8a, 0xb3, 0x47,
+	0x25, 0x35, 0xc1, 0x36, 0xd8, 0x59, 0xbd, 0xf5, 0x0a, 0x4e, 0xe8, 0x70, 0x91, 0x2e, 0xaf, 0x98,
+	0xb2, 0xc6, 0x47, 0xbb, 0xf8, 0xc1, 0xc1, 0xc7, 0xcc, 0x95, 0xef, 0x30, 0x49, 0x1d, 0x74, 0x72,
+	0xba, 0xd5, 0x98, 0x9c, 0x6e, 0xc1, 0x5c, 0x46, 0xa6, 0xa8, 0xe8, 0x73, 0xb8, 0x26, 0x05, 0x3d,
+	0x3c, 0xf4, 0x5c, 0xcd, 0x68, 0x2e, 0x69, 0x96, 0x7b, 0xb8, 0x5e, 0x8b, 0xf0, 0xbb, 0x05, 0x2c,
+	0xe7, 0xb9, 0x94, 0x79, 0xad, 0x28, 0x25, 0x25, 0x3e, 0xf4, 0x35, 0x80, 0x1b, 0x22, 0x1e, 0xb1,
+	0xa2, 0x89, 0x69, 0x6c, 0x1b, 0x3b, 0xab, 0xb7, 0xde, 0xa8, 0x1b, 0x04, 0x99, 0xc1, 0x73, 0xcc,
+	0x34, 0x90, 0x8d, 0x59, 0x0d, 0xa9, 0x70, 0x77, 0xbe, 0x5c, 0x82, 0x37, 0xce, 0x69, 0xcb, 0xdb,
+	0x5e, 0x24, 0xd1, 0x07, 0x95, 0xd6, 0xe0, 0x8b, 0xb5, 0x46, 0x79, 0xeb, 0xc6, 0x6c, 0xa4, 0x51,
+	0xad, 0x64, 0x92, 0x42, 0x5b, 0x3e, 0x83, 0x2d, 0x4f, 0x32, 0x5f, 0xf5, 0x43, 0x95, 0xe2, 0xbd,
+	0xba, 0xa5, 0x38, 0x27, 0x13, 0xe7, 0x4a, 0x1a, 0x43, 0xeb, 0x4d, 0xc5, 0x46, 0x12, 0xd2, 0xce,
+	0x43, 0x03, 0x9a, 0x89, 0xe7, 0xe5, 0x4c, 0xfe, 0x1f, 0x66, 0xf2, 0x37, 0x00, 0x5f, 0x9c, 0xd7,
+	0x8f, 0x05, 0x0c, 0x63, 0x5c, 0x1e, 0xc6, 0xee, 0xdf, 0x1b, 0xc6, 0x0b, 0x4f, 0xe1, 0xb7, 0x06,
+	0x5c, 0xbd, 0x3f, 0xe2, 0xc7, 0xfb, 0xb1, 0xef, 0x53, 0x31, 0x46, 0x36, 0x6c, 0x07, 0xd4, 0x67,
+	0x51, 0x48, 0x5d, 0xa6, 0xb3, 0x6c, 0x3b, 0xcf, 0xa6, 0x8e, 0xed, 0xbd, 0x4c, 0x41, 0x72, 0x1b,
+	0x74, 0x1d, 0x1a, 0x21, 0xef, 0xe9, 0xf1, 0x69, 0x3b, 0xab, 0xa9, 0xa9, 0xd1, 0xe5, 0x3d, 0xa2,
+	0xe4, 0xe8, 0x75, 0xd8, 0xee, 0x79, 0x82, 0xb9, 0xd2, 0xe3, 0x81, 0x69, 0x68, 0xa3, 0x4e, 0x86,
+	0x77, 0x2f, 0x53, 0xfc, 0x79, 0xba, 0x75, 0x45, 0x75, 0x68, 0x2a, 0x20, 0xb9, 0x13, 0xda, 0x86,
+	0xcd, 0x90, 0x31, 0x61, 0x36, 0xb5, 0xf3, 0x5a, 0xea, 0xdc, 0xec, 0x32, 0x26, 0x88, 0xd6, 0xa0,
+	0x97, 0xe1, 0x8a, 0xbe, 0xf6, 0x2e, 0x1f, 0x99, 0x2d, 0x6d, 0x35, 0x2d, 0x74, 0x37, 0x95, 0x93,
+	0xa9, 0x85, 0xc6, 0xe3, 0x42, 0x9a, 0xcf, 0x6c, 0x83, 0x9d, 0x56, 0x01, 0x8f, 0x0b, 0x49, 0xb4,
+	0xa6, 0xb2, 0x1a, 0xcb, 0x8b, 0x5d, 0x8d, 0xce, 0xf7, 0x4d, 0x78, 0x6d, 0x5a, 0xeb, 0xb4, 0x93,
+	0x59, 0x7f, 0xfe, 0xfd, 0xc3, 0xf0, 0x10, 0xc0, 0xf5, 0xa0, 0x30, 0x3e, 0x1e, 0xcb, 0x66, 0xf2,
+	0xad, 0xba, 0x15, 0x48, 0xc6, 0xb0, 0x54, 0x87, 0x6b, 0x69, 0x0c, 0xeb, 0x7b, 0x65, 0x2a, 0x32,
+	0xcb, 0x8d, 0x7e, 0x00, 0xf0, 0x2a, 0xad, 0x0c, 0xb5, 0xc7, 0xb2, 0x6b, 0xf1, 0x4f, 0x46, 0x75,
+	0x3d, 0x8d, 0xea, 0xea, 0x9d, 0x27, 0x11, 0x92, 0x27, 0xc7, 0x81, 0x06, 0xb0, 0x75, 0x38, 0xe2,
+	0xc7, 0x91, 0xd9, 0xd4, 0x01, 0xdd, 0xad, 0x1b, 0x50, 0x61, 0x0f, 0xf3, 0x6d, 0x55, 0xc2, 0x88,
+	0x24, 0x04, 0x9d, 0x5f, 0x01, 0x7c, 0x61, 0xce, 0x64, 0x2c, 0xe0, 0x44, 0xc9, 0xf2, 0x89, 0x7a,
+	0x50, 0x37, 0xcf, 0x39, 0x19, 0xcc, 0xb9, 0x50, 0xbf, 0x03, 0x88, 0x9e, 0xc6, 0x17, 0xb2, 0xf3,
+	0x0b, 0x80, 0xcf, 0xff, 0x27, 0x4f, 0x11, 0x2f, 0xf7, 0xb9, 0xf6, 0x82, 0x5d, 0xf8, 0x11, 0xfa,
+	0x71, 0x09, 0xa2, 0xea, 0x36, 0xaa, 0x4b, 0xad, 0xde, 0x99, 0xf4, 0x19, 0x9a, 0x5e, 0x6a, 0x35,
+	0x3e, 0x44, 0x6b, 0x2e, 0x3f, 0x62, 0x66, 0x3f, 0x62, 0xfe, 0x00, 0xb0, 0x62, 0x56, 0x7e, 0x83,
+	0x41, 0x9d, 0x37, 0xf8, 0x06, 0x6c, 0x79, 0x41, 0x8f, 0x7d, 0xa2, 0x0b, 0xdc, 0x2a, 0x74, 0x51,
+	0x09, 0x49, 0xa2, 0xab, 0x34, 0xc3, 0x58, 0xf0, 0xbe, 0x7c, 0x05, 0x60, 0x49, 0x8d, 0x5e, 0x82,
+	0xcb, 0x21, 0x75, 0x87, 0x4c, 0x46, 0x3a, 0x6b, 0xc3, 0x59, 0x4f, 0x51, 0x96, 0xbb, 0x89, 0x98,
+	0x64, 0x7a, 0x95, 0xe0, 0xc1, 0x58, 0xb2, 0x64, 0x82, 0x8c, 0x3c, 0x41, 0x47, 0x09, 0x49, 0xa2,
+	0x53, 0xdf, 0x19, 0x11, 0x8b, 0x22, 0x8f, 0x07, 0x49, 0x72, 0x46, 0xbe, 0x45, 0xfb, 0xa9, 0x9c,
+	0x4c, 0x2d, 0x1c, 0x7c, 0x72, 0x66, 0x35, 0x1e, 0x9d, 0x59, 0x8d, 0xc7, 0x67, 0x56, 0xe3, 0x8b,
+	0x89, 0x05, 0x4e, 0x26, 0x16, 0x78, 0x34, 0xb1, 0xc0, 0xe3, 0x89, 0x05, 0x7e, 0x9a, 0x58, 0xe0,
+	0x9b, 0x9f, 0xad, 0xc6, 0xfb, 0x2b, 0x59, 0xbe, 0x7f, 0x0d, 0x00, 0x8f, 0x82, 0xef, 0x6d, 0xf9,
+	0x0f, 0x00, 0x00,
 }
 
 func (m *AntreaClusterNetworkPolicyStats) Marshal() (dAtA []byte, err error) {
@@ -308,6 +476,20 @@ func (m *AntreaClusterNetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int
 	_ = i
 	var l int
 	_ = l
+	if len(m.RuleTrafficStats) > 0 {
+		for iNdEx := len(m.RuleTrafficStats) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RuleTrafficStats[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	{
 		size, err := m.TrafficStats.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -398,6 +580,20 @@ func (m *AntreaNetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error
 	_ = i
 	var l int
 	_ = l
+	if len(m.RuleTrafficStats) > 0 {
+		for iNdEx := len(m.RuleTrafficStats) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RuleTrafficStats[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	{
 		size, err := m.TrafficStats.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -468,7 +664,7 @@ func (m *AntreaNetworkPolicyStatsList) MarshalToSizedBuffer(dAtA []byte) (int, e
 	return len(dAtA) - i, nil
 }
 
-func (m *NetworkPolicyStats) Marshal() (dAtA []byte, err error) {
+func (m *FlowSummary) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -478,12 +674,12 @@ func (m *NetworkPolicyStats) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *NetworkPolicyStats) MarshalTo(dAtA []byte) (int, error) {
+func (m *FlowSummary) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *NetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *FlowSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -497,7 +693,100 @@ func (m *NetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i = encodeVarintGenerated(dAtA, i, uint64(size))
 	}
 	i--
+	dAtA[i] = 0x3a
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Port))
+	i--
+	dAtA[i] = 0x30
+	i -= len(m.Protocol)
+	copy(dAtA[i:], m.Protocol)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Protocol)))
+	i--
+	dAtA[i] = 0x2a
+	i -= len(m.Peer)
+	copy(dAtA[i:], m.Peer)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Peer)))
+	i--
+	dAtA[i] = 0x22
+	i -= len(m.Direction)
+	copy(dAtA[i:], m.Direction)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Direction)))
+	i--
+	dAtA[i] = 0x1a
+	i -= len(m.Pod)
+	copy(dAtA[i:], m.Pod)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Pod)))
+	i--
 	dAtA[i] = 0x12
+	i -= len(m.Namespace)
+	copy(dAtA[i:], m.Namespace)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *NamespaceNetworkSummary) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NamespaceNetworkSummary) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NamespaceNetworkSummary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Flows) > 0 {
+		for iNdEx := len(m.Flows) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Flows[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.AntreaNetworkPolicies) > 0 {
+		for iNdEx := len(m.AntreaNetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.AntreaNetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.NetworkPolicies) > 0 {
+		for iNdEx := len(m.NetworkPolicies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.NetworkPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	{
 		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -511,7 +800,7 @@ func (m *NetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *NetworkPolicyStatsList) Marshal() (dAtA []byte, err error) {
+func (m *NamespaceNetworkSummaryList) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -521,12 +810,12 @@ func (m *NetworkPolicyStatsList) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *NetworkPolicyStatsList) MarshalTo(dAtA []byte) (int, error) {
+func (m *NamespaceNetworkSummaryList) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *NetworkPolicyStatsList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NamespaceNetworkSummaryList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -558,7 +847,7 @@ func (m *NetworkPolicyStatsList) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
-func (m *TrafficStats) Marshal() (dAtA []byte, err error) {
+func (m *NetworkPolicyStats) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -568,67 +857,256 @@ func (m *TrafficStats) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TrafficStats) MarshalTo(dAtA []byte) (int, error) {
+func (m *NetworkPolicyStats) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TrafficStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NetworkPolicyStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i = encodeVarintGenerated(dAtA, i, uint64(m.Sessions))
-	i--
-	dAtA[i] = 0x18
-	i = encodeVarintGenerated(dAtA, i, uint64(m.Bytes))
+	{
+		size, err := m.TrafficStats.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
 	i--
-	dAtA[i] = 0x10
-	i = encodeVarintGenerated(dAtA, i, uint64(m.Packets))
+	dAtA[i] = 0x12
+	{
+		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
 	i--
-	dAtA[i] = 0x8
+	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
-	offset -= sovGenerated(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *NetworkPolicyStatsList) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *AntreaClusterNetworkPolicyStats) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = m.ObjectMeta.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.TrafficStats.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+
+func (m *NetworkPolicyStatsList) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *AntreaClusterNetworkPolicyStatsList) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *NetworkPolicyStatsList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = m.ListMeta.Size()
-	n += 1 + l + sovGenerated(uint64(l))
 	if len(m.Items) > 0 {
-		for _, e := range m.Items {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
+		for iNdEx := len(m.Items) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Items[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	return n
+	{
+		size, err := m.ListMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *PolicyTrafficStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PolicyTrafficStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PolicyTrafficStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.RuleTrafficStats) > 0 {
+		for iNdEx := len(m.RuleTrafficStats) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RuleTrafficStats[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	{
+		size, err := m.TrafficStats.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *RuleTrafficStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RuleTrafficStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RuleTrafficStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.TrafficStats.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Index))
+	i--
+	dAtA[i] = 0x10
+	i -= len(m.Direction)
+	copy(dAtA[i:], m.Direction)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Direction)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *TrafficStats) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TrafficStats) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TrafficStats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Sessions))
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Bytes))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Packets))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
+	offset -= sovGenerated(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *AntreaClusterNetworkPolicyStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.TrafficStats.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.RuleTrafficStats) > 0 {
+		for _, e := range m.RuleTrafficStats {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AntreaClusterNetworkPolicyStatsList) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ListMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Items) > 0 {
+		for _, e := range m.Items {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
 }
 
 func (m *AntreaNetworkPolicyStats) Size() (n int) {
@@ -641,6 +1119,12 @@ func (m *AntreaNetworkPolicyStats) Size() (n int) {
 	n += 1 + l + sovGenerated(uint64(l))
 	l = m.TrafficStats.Size()
 	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.RuleTrafficStats) > 0 {
+		for _, e := range m.RuleTrafficStats {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -661,6 +1145,74 @@ func (m *AntreaNetworkPolicyStatsList) Size() (n int) {
 	return n
 }
 
+func (m *FlowSummary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Pod)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Direction)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Peer)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Protocol)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.Port))
+	l = m.TrafficStats.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *NamespaceNetworkSummary) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.NetworkPolicies) > 0 {
+		for _, e := range m.NetworkPolicies {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.AntreaNetworkPolicies) > 0 {
+		for _, e := range m.AntreaNetworkPolicies {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Flows) > 0 {
+		for _, e := range m.Flows {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *NamespaceNetworkSummaryList) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ListMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Items) > 0 {
+		for _, e := range m.Items {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *NetworkPolicyStats) Size() (n int) {
 	if m == nil {
 		return 0
@@ -691,6 +1243,39 @@ func (m *NetworkPolicyStatsList) Size() (n int) {
 	return n
 }
 
+func (m *PolicyTrafficStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.TrafficStats.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.RuleTrafficStats) > 0 {
+		for _, e := range m.RuleTrafficStats {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RuleTrafficStats) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Direction)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.Index))
+	l = m.TrafficStats.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
 func (m *TrafficStats) Size() (n int) {
 	if m == nil {
 		return 0
@@ -713,9 +1298,15 @@ func (this *AntreaClusterNetworkPolicyStats) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForRuleTrafficStats := "[]RuleTrafficStats{"
+	for _, f := range this.RuleTrafficStats {
+		repeatedStringForRuleTrafficStats += strings.Replace(strings.Replace(f.String(), "RuleTrafficStats", "RuleTrafficStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRuleTrafficStats += "}"
 	s := strings.Join([]string{`&AntreaClusterNetworkPolicyStats{`,
 		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
 		`TrafficStats:` + strings.Replace(strings.Replace(this.TrafficStats.String(), "TrafficStats", "TrafficStats", 1), `&`, ``, 1) + `,`,
+		`RuleTrafficStats:` + repeatedStringForRuleTrafficStats + `,`,
 		`}`,
 	}, "")
 	return s
@@ -740,9 +1331,15 @@ func (this *AntreaNetworkPolicyStats) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForRuleTrafficStats := "[]RuleTrafficStats{"
+	for _, f := range this.RuleTrafficStats {
+		repeatedStringForRuleTrafficStats += strings.Replace(strings.Replace(f.String(), "RuleTrafficStats", "RuleTrafficStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRuleTrafficStats += "}"
 	s := strings.Join([]string{`&AntreaNetworkPolicyStats{`,
 		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
 		`TrafficStats:` + strings.Replace(strings.Replace(this.TrafficStats.String(), "TrafficStats", "TrafficStats", 1), `&`, ``, 1) + `,`,
+		`RuleTrafficStats:` + repeatedStringForRuleTrafficStats + `,`,
 		`}`,
 	}, "")
 	return s
@@ -763,26 +1360,86 @@ func (this *AntreaNetworkPolicyStatsList) String() string {
 	}, "")
 	return s
 }
-func (this *NetworkPolicyStats) String() string {
+func (this *FlowSummary) String() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&NetworkPolicyStats{`,
-		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+	s := strings.Join([]string{`&FlowSummary{`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`Pod:` + fmt.Sprintf("%v", this.Pod) + `,`,
+		`Direction:` + fmt.Sprintf("%v", this.Direction) + `,`,
+		`Peer:` + fmt.Sprintf("%v", this.Peer) + `,`,
+		`Protocol:` + fmt.Sprintf("%v", this.Protocol) + `,`,
+		`Port:` + fmt.Sprintf("%v", this.Port) + `,`,
 		`TrafficStats:` + strings.Replace(strings.Replace(this.TrafficStats.String(), "TrafficStats", "TrafficStats", 1), `&`, ``, 1) + `,`,
 		`}`,
 	}, "")
 	return s
 }
-func (this *NetworkPolicyStatsList) String() string {
+func (this *NamespaceNetworkSummary) String() string {
 	if this == nil {
 		return "nil"
 	}
-	repeatedStringForItems := "[]NetworkPolicyStats{"
-	for _, f := range this.Items {
-		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "NetworkPolicyStats", "NetworkPolicyStats", 1), `&`, ``, 1) + ","
+	repeatedStringForNetworkPolicies := "[]PolicyTrafficStats{"
+	for _, f := range this.NetworkPolicies {
+		repeatedStringForNetworkPolicies += strings.Replace(strings.Replace(f.String(), "PolicyTrafficStats", "PolicyTrafficStats", 1), `&`, ``, 1) + ","
 	}
-	repeatedStringForItems += "}"
+	repeatedStringForNetworkPolicies += "}"
+	repeatedStringForAntreaNetworkPolicies := "[]PolicyTrafficStats{"
+	for _, f := range this.AntreaNetworkPolicies {
+		repeatedStringForAntreaNetworkPolicies += strings.Replace(strings.Replace(f.String(), "PolicyTrafficStats", "PolicyTrafficStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForAntreaNetworkPolicies += "}"
+	repeatedStringForFlows := "[]FlowSummary{"
+	for _, f := range this.Flows {
+		repeatedStringForFlows += strings.Replace(strings.Replace(f.String(), "FlowSummary", "FlowSummary", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForFlows += "}"
+	s := strings.Join([]string{`&NamespaceNetworkSummary{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`NetworkPolicies:` + repeatedStringForNetworkPolicies + `,`,
+		`AntreaNetworkPolicies:` + repeatedStringForAntreaNetworkPolicies + `,`,
+		`Flows:` + repeatedStringForFlows + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NamespaceNetworkSummaryList) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForItems := "[]NamespaceNetworkSummary{"
+	for _, f := range this.Items {
+		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "NamespaceNetworkSummary", "NamespaceNetworkSummary", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForItems += "}"
+	s := strings.Join([]string{`&NamespaceNetworkSummaryList{`,
+		`ListMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ListMeta), "ListMeta", "v1.ListMeta", 1), `&`, ``, 1) + `,`,
+		`Items:` + repeatedStringForItems + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NetworkPolicyStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NetworkPolicyStats{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`TrafficStats:` + strings.Replace(strings.Replace(this.TrafficStats.String(), "TrafficStats", "TrafficStats", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NetworkPolicyStatsList) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForItems := "[]NetworkPolicyStats{"
+	for _, f := range this.Items {
+		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "NetworkPolicyStats", "NetworkPolicyStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForItems += "}"
 	s := strings.Join([]string{`&NetworkPolicyStatsList{`,
 		`ListMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ListMeta), "ListMeta", "v1.ListMeta", 1), `&`, ``, 1) + `,`,
 		`Items:` + repeatedStringForItems + `,`,
@@ -790,27 +1447,867 @@ func (this *NetworkPolicyStatsList) String() string {
 	}, "")
 	return s
 }
+func (this *PolicyTrafficStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRuleTrafficStats := "[]RuleTrafficStats{"
+	for _, f := range this.RuleTrafficStats {
+		repeatedStringForRuleTrafficStats += strings.Replace(strings.Replace(f.String(), "RuleTrafficStats", "RuleTrafficStats", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRuleTrafficStats += "}"
+	s := strings.Join([]string{`&PolicyTrafficStats{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`TrafficStats:` + strings.Replace(strings.Replace(this.TrafficStats.String(), "TrafficStats", "TrafficStats", 1), `&`, ``, 1) + `,`,
+		`RuleTrafficStats:` + repeatedStringForRuleTrafficStats + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RuleTrafficStats) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RuleTrafficStats{`,
+		`Direction:` + fmt.Sprintf("%v", this.Direction) + `,`,
+		`Index:` + fmt.Sprintf("%v", this.Index) + `,`,
+		`TrafficStats:` + strings.Replace(strings.Replace(this.TrafficStats.String(), "TrafficStats", "TrafficStats", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *TrafficStats) String() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&TrafficStats{`,
-		`Packets:` + fmt.Sprintf("%v", this.Packets) + `,`,
-		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
-		`Sessions:` + fmt.Sprintf("%v", this.Sessions) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringGenerated(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	s := strings.Join([]string{`&TrafficStats{`,
+		`Packets:` + fmt.Sprintf("%v", this.Packets) + `,`,
+		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
+		`Sessions:` + fmt.Sprintf("%v", this.Sessions) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringGenerated(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *AntreaClusterNetworkPolicyStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuleTrafficStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RuleTrafficStats = append(m.RuleTrafficStats, RuleTrafficStats{})
+			if err := m.RuleTrafficStats[len(m.RuleTrafficStats)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AntreaClusterNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStatsList: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStatsList: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Items = append(m.Items, AntreaClusterNetworkPolicyStats{})
+			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AntreaNetworkPolicyStats) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AntreaNetworkPolicyStats: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AntreaNetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RuleTrafficStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RuleTrafficStats = append(m.RuleTrafficStats, RuleTrafficStats{})
+			if err := m.RuleTrafficStats[len(m.RuleTrafficStats)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AntreaNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AntreaNetworkPolicyStatsList: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AntreaNetworkPolicyStatsList: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Items = append(m.Items, AntreaNetworkPolicyStats{})
+			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FlowSummary) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FlowSummary: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FlowSummary: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pod", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Pod = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Direction", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Direction = RuleDirection(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Peer", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Peer = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Protocol", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Protocol = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Port", wireType)
+			}
+			m.Port = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Port |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *AntreaClusterNetworkPolicyStats) Unmarshal(dAtA []byte) error {
+func (m *NamespaceNetworkSummary) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -833,10 +2330,10 @@ func (m *AntreaClusterNetworkPolicyStats) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStats: wiretype end group for non-group")
+			return fmt.Errorf("proto: NamespaceNetworkSummary: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NamespaceNetworkSummary: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -874,7 +2371,7 @@ func (m *AntreaClusterNetworkPolicyStats) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NetworkPolicies", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -901,7 +2398,76 @@ func (m *AntreaClusterNetworkPolicyStats) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.NetworkPolicies = append(m.NetworkPolicies, PolicyTrafficStats{})
+			if err := m.NetworkPolicies[len(m.NetworkPolicies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AntreaNetworkPolicies", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AntreaNetworkPolicies = append(m.AntreaNetworkPolicies, PolicyTrafficStats{})
+			if err := m.AntreaNetworkPolicies[len(m.AntreaNetworkPolicies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flows", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Flows = append(m.Flows, FlowSummary{})
+			if err := m.Flows[len(m.Flows)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -929,7 +2495,7 @@ func (m *AntreaClusterNetworkPolicyStats) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AntreaClusterNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
+func (m *NamespaceNetworkSummaryList) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -952,10 +2518,10 @@ func (m *AntreaClusterNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStatsList: wiretype end group for non-group")
+			return fmt.Errorf("proto: NamespaceNetworkSummaryList: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AntreaClusterNetworkPolicyStatsList: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NamespaceNetworkSummaryList: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1020,7 +2586,7 @@ func (m *AntreaClusterNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Items = append(m.Items, AntreaClusterNetworkPolicyStats{})
+			m.Items = append(m.Items, NamespaceNetworkSummary{})
 			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
@@ -1049,7 +2615,7 @@ func (m *AntreaClusterNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AntreaNetworkPolicyStats) Unmarshal(dAtA []byte) error {
+func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1072,10 +2638,10 @@ func (m *AntreaNetworkPolicyStats) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AntreaNetworkPolicyStats: wiretype end group for non-group")
+			return fmt.Errorf("proto: NetworkPolicyStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AntreaNetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1168,7 +2734,7 @@ func (m *AntreaNetworkPolicyStats) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *AntreaNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
+func (m *NetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1191,10 +2757,10 @@ func (m *AntreaNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: AntreaNetworkPolicyStatsList: wiretype end group for non-group")
+			return fmt.Errorf("proto: NetworkPolicyStatsList: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: AntreaNetworkPolicyStatsList: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NetworkPolicyStatsList: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1259,7 +2825,7 @@ func (m *AntreaNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Items = append(m.Items, AntreaNetworkPolicyStats{})
+			m.Items = append(m.Items, NetworkPolicyStats{})
 			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
@@ -1288,7 +2854,7 @@ func (m *AntreaNetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
+func (m *PolicyTrafficStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1311,15 +2877,47 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NetworkPolicyStats: wiretype end group for non-group")
+			return fmt.Errorf("proto: PolicyTrafficStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NetworkPolicyStats: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PolicyTrafficStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1346,13 +2944,13 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RuleTrafficStats", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1379,7 +2977,8 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.RuleTrafficStats = append(m.RuleTrafficStats, RuleTrafficStats{})
+			if err := m.RuleTrafficStats[len(m.RuleTrafficStats)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -1407,7 +3006,7 @@ func (m *NetworkPolicyStats) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
+func (m *RuleTrafficStats) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1430,17 +3029,17 @@ func (m *NetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NetworkPolicyStatsList: wiretype end group for non-group")
+			return fmt.Errorf("proto: RuleTrafficStats: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NetworkPolicyStatsList: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RuleTrafficStats: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Direction", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -1450,28 +3049,46 @@ func (m *NetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Direction = RuleDirection(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TrafficStats", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1498,8 +3115,7 @@ func (m *NetworkPolicyStatsList) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Items = append(m.Items, NetworkPolicyStats{})
-			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.TrafficStats.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
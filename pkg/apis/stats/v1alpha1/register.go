@@ -23,8 +23,27 @@ import (
 // GroupName is the group name use in this package
 const GroupName = "stats.antrea.tanzu.vmware.com"
 
-// SchemeGroupVersion is group version used to register these objects
-var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+	AntreaClusterNetworkPolicyStatsVersionResource = schema.GroupVersionResource{
+		Group:    SchemeGroupVersion.Group,
+		Version:  SchemeGroupVersion.Version,
+		Resource: "antreaclusternetworkpolicystats"}
+	AntreaNetworkPolicyStatsVersionResource = schema.GroupVersionResource{
+		Group:    SchemeGroupVersion.Group,
+		Version:  SchemeGroupVersion.Version,
+		Resource: "antreanetworkpolicystats"}
+	NetworkPolicyStatsVersionResource = schema.GroupVersionResource{
+		Group:    SchemeGroupVersion.Group,
+		Version:  SchemeGroupVersion.Version,
+		Resource: "networkpolicystats"}
+	NamespaceNetworkSummaryVersionResource = schema.GroupVersionResource{
+		Group:    SchemeGroupVersion.Group,
+		Version:  SchemeGroupVersion.Version,
+		Resource: "namespacenetworksummaries"}
+)
 
 // Resource takes an unqualified resource and returns a Group qualified GroupResource
 func Resource(resource string) schema.GroupResource {
@@ -47,6 +66,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AntreaNetworkPolicyStatsList{},
 		&NetworkPolicyStats{},
 		&NetworkPolicyStatsList{},
+		&NamespaceNetworkSummary{},
+		&NamespaceNetworkSummaryList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
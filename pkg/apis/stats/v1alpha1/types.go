@@ -31,6 +31,8 @@ type AntreaClusterNetworkPolicyStats struct {
 
 	// The traffic stats of the Antrea ClusterNetworkPolicy.
 	TrafficStats TrafficStats `json:"trafficStats,omitempty" protobuf:"bytes,2,opt,name=trafficStats"`
+	// The traffic stats of the rules of the Antrea ClusterNetworkPolicy.
+	RuleTrafficStats []RuleTrafficStats `json:"ruleTrafficStats,omitempty" protobuf:"bytes,3,rep,name=ruleTrafficStats"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -56,6 +58,8 @@ type AntreaNetworkPolicyStats struct {
 
 	// The traffic stats of the Antrea NetworkPolicy.
 	TrafficStats TrafficStats `json:"trafficStats,omitempty" protobuf:"bytes,2,opt,name=trafficStats"`
+	// The traffic stats of the rules of the Antrea NetworkPolicy.
+	RuleTrafficStats []RuleTrafficStats `json:"ruleTrafficStats,omitempty" protobuf:"bytes,3,rep,name=ruleTrafficStats"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -94,6 +98,69 @@ type NetworkPolicyStatsList struct {
 	Items []NetworkPolicyStats `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
 
+// +genclient
+// +resourceName=namespacenetworksummaries
+// +genclient:readonly
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NamespaceNetworkSummary is the network state of a Namespace, which is named after the Namespace: the traffic stats
+// of its NetworkPolicies and the connections of its Pods. It lets the users who can view a Namespace see its network
+// state without access to the cluster-wide APIs.
+type NamespaceNetworkSummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// The traffic stats of the K8s NetworkPolicies of the Namespace.
+	NetworkPolicies []PolicyTrafficStats `json:"networkPolicies,omitempty" protobuf:"bytes,2,rep,name=networkPolicies"`
+	// The traffic stats of the Antrea NetworkPolicies of the Namespace.
+	AntreaNetworkPolicies []PolicyTrafficStats `json:"antreaNetworkPolicies,omitempty" protobuf:"bytes,3,rep,name=antreaNetworkPolicies"`
+	// The summaries of the connections of the Pods of the Namespace.
+	Flows []FlowSummary `json:"flows,omitempty" protobuf:"bytes,4,rep,name=flows"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NamespaceNetworkSummaryList is a list of NamespaceNetworkSummary.
+type NamespaceNetworkSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// List of NamespaceNetworkSummary.
+	Items []NamespaceNetworkSummary `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// PolicyTrafficStats contains the traffic stats of a NetworkPolicy of a Namespace.
+type PolicyTrafficStats struct {
+	// Name is the name of the NetworkPolicy.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// TrafficStats is the traffic stats of the NetworkPolicy.
+	TrafficStats TrafficStats `json:"trafficStats,omitempty" protobuf:"bytes,2,opt,name=trafficStats"`
+	// RuleTrafficStats is the traffic stats of the rules of the NetworkPolicy. It is only set for Antrea-native
+	// policies.
+	RuleTrafficStats []RuleTrafficStats `json:"ruleTrafficStats,omitempty" protobuf:"bytes,3,rep,name=ruleTrafficStats"`
+}
+
+// FlowSummary summarizes the connections of a Pod with a peer, on a protocol and a destination port.
+type FlowSummary struct {
+	// Namespace is the Namespace of the Pod.
+	Namespace string `json:"namespace" protobuf:"bytes,1,opt,name=namespace"`
+	// Pod is the name of the Pod.
+	Pod string `json:"pod" protobuf:"bytes,2,opt,name=pod"`
+	// Direction is Ingress for the connections to the Pod, and Egress for the connections from the Pod.
+	Direction RuleDirection `json:"direction" protobuf:"bytes,3,opt,name=direction"`
+	// Peer is the other end of the connections: "<Namespace>/<Pod>" for a Pod running on the same Node,
+	// "<Namespace>/<Service>:<port>" for a Service, or the subnet of the IP otherwise. The Pods and Services of
+	// other Namespaces are "<other Namespace>", and "<others>" aggregates the peers with the least traffic.
+	Peer string `json:"peer" protobuf:"bytes,4,opt,name=peer"`
+	// Protocol is the protocol of the connections, e.g. TCP.
+	Protocol string `json:"protocol" protobuf:"bytes,5,opt,name=protocol"`
+	// Port is the destination port of the connections. It is 0 for the protocols without ports.
+	Port int32 `json:"port,omitempty" protobuf:"varint,6,opt,name=port"`
+	// TrafficStats is the traffic stats of the connections, in both directions. Sessions is the number of
+	// connections.
+	TrafficStats TrafficStats `json:"trafficStats,omitempty" protobuf:"bytes,7,opt,name=trafficStats"`
+}
+
 // TrafficStats contains the traffic stats of a NetworkPolicy.
 type TrafficStats struct {
 	// Packets is the packets count hit by the NetworkPolicy.
@@ -103,3 +170,21 @@ type TrafficStats struct {
 	// Sessions is the sessions count hit by the NetworkPolicy.
 	Sessions int64 `json:"sessions,omitempty" protobuf:"varint,3,opt,name=sessions"`
 }
+
+// RuleDirection is the direction of a rule of an Antrea-native policy.
+type RuleDirection string
+
+const (
+	RuleDirectionIngress RuleDirection = "Ingress"
+	RuleDirectionEgress  RuleDirection = "Egress"
+)
+
+// RuleTrafficStats contains the traffic stats of a rule of an Antrea-native policy.
+type RuleTrafficStats struct {
+	// Direction is the direction of the rule.
+	Direction RuleDirection `json:"direction" protobuf:"bytes,1,opt,name=direction"`
+	// Index is the index of the rule in the ingress or egress rules of the policy.
+	Index int32 `json:"index" protobuf:"varint,2,opt,name=index"`
+	// TrafficStats is the traffic stats of the rule.
+	TrafficStats TrafficStats `json:"trafficStats,omitempty" protobuf:"bytes,3,opt,name=trafficStats"`
+}
@@ -73,6 +73,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FlowSummary)(nil), (*stats.FlowSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FlowSummary_To_stats_FlowSummary(a.(*FlowSummary), b.(*stats.FlowSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*stats.FlowSummary)(nil), (*FlowSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_stats_FlowSummary_To_v1alpha1_FlowSummary(a.(*stats.FlowSummary), b.(*FlowSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NamespaceNetworkSummary)(nil), (*stats.NamespaceNetworkSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NamespaceNetworkSummary_To_stats_NamespaceNetworkSummary(a.(*NamespaceNetworkSummary), b.(*stats.NamespaceNetworkSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*stats.NamespaceNetworkSummary)(nil), (*NamespaceNetworkSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_stats_NamespaceNetworkSummary_To_v1alpha1_NamespaceNetworkSummary(a.(*stats.NamespaceNetworkSummary), b.(*NamespaceNetworkSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NamespaceNetworkSummaryList)(nil), (*stats.NamespaceNetworkSummaryList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NamespaceNetworkSummaryList_To_stats_NamespaceNetworkSummaryList(a.(*NamespaceNetworkSummaryList), b.(*stats.NamespaceNetworkSummaryList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*stats.NamespaceNetworkSummaryList)(nil), (*NamespaceNetworkSummaryList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_stats_NamespaceNetworkSummaryList_To_v1alpha1_NamespaceNetworkSummaryList(a.(*stats.NamespaceNetworkSummaryList), b.(*NamespaceNetworkSummaryList), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NetworkPolicyStats)(nil), (*stats.NetworkPolicyStats)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_NetworkPolicyStats_To_stats_NetworkPolicyStats(a.(*NetworkPolicyStats), b.(*stats.NetworkPolicyStats), scope)
 	}); err != nil {
@@ -93,6 +123,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PolicyTrafficStats)(nil), (*stats.PolicyTrafficStats)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PolicyTrafficStats_To_stats_PolicyTrafficStats(a.(*PolicyTrafficStats), b.(*stats.PolicyTrafficStats), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*stats.PolicyTrafficStats)(nil), (*PolicyTrafficStats)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_stats_PolicyTrafficStats_To_v1alpha1_PolicyTrafficStats(a.(*stats.PolicyTrafficStats), b.(*PolicyTrafficStats), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RuleTrafficStats)(nil), (*stats.RuleTrafficStats)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RuleTrafficStats_To_stats_RuleTrafficStats(a.(*RuleTrafficStats), b.(*stats.RuleTrafficStats), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*stats.RuleTrafficStats)(nil), (*RuleTrafficStats)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_stats_RuleTrafficStats_To_v1alpha1_RuleTrafficStats(a.(*stats.RuleTrafficStats), b.(*RuleTrafficStats), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TrafficStats)(nil), (*stats.TrafficStats)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_TrafficStats_To_stats_TrafficStats(a.(*TrafficStats), b.(*stats.TrafficStats), scope)
 	}); err != nil {
@@ -111,6 +161,7 @@ func autoConvert_v1alpha1_AntreaClusterNetworkPolicyStats_To_stats_AntreaCluster
 	if err := Convert_v1alpha1_TrafficStats_To_stats_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
 		return err
 	}
+	out.RuleTrafficStats = *(*[]stats.RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
 	return nil
 }
 
@@ -124,6 +175,7 @@ func autoConvert_stats_AntreaClusterNetworkPolicyStats_To_v1alpha1_AntreaCluster
 	if err := Convert_stats_TrafficStats_To_v1alpha1_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
 		return err
 	}
+	out.RuleTrafficStats = *(*[]RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
 	return nil
 }
 
@@ -159,6 +211,7 @@ func autoConvert_v1alpha1_AntreaNetworkPolicyStats_To_stats_AntreaNetworkPolicyS
 	if err := Convert_v1alpha1_TrafficStats_To_stats_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
 		return err
 	}
+	out.RuleTrafficStats = *(*[]stats.RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
 	return nil
 }
 
@@ -172,6 +225,7 @@ func autoConvert_stats_AntreaNetworkPolicyStats_To_v1alpha1_AntreaNetworkPolicyS
 	if err := Convert_stats_TrafficStats_To_v1alpha1_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
 		return err
 	}
+	out.RuleTrafficStats = *(*[]RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
 	return nil
 }
 
@@ -202,6 +256,90 @@ func Convert_stats_AntreaNetworkPolicyStatsList_To_v1alpha1_AntreaNetworkPolicyS
 	return autoConvert_stats_AntreaNetworkPolicyStatsList_To_v1alpha1_AntreaNetworkPolicyStatsList(in, out, s)
 }
 
+func autoConvert_v1alpha1_FlowSummary_To_stats_FlowSummary(in *FlowSummary, out *stats.FlowSummary, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Pod = in.Pod
+	out.Direction = stats.RuleDirection(in.Direction)
+	out.Peer = in.Peer
+	out.Protocol = in.Protocol
+	out.Port = in.Port
+	if err := Convert_v1alpha1_TrafficStats_To_stats_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_FlowSummary_To_stats_FlowSummary is an autogenerated conversion function.
+func Convert_v1alpha1_FlowSummary_To_stats_FlowSummary(in *FlowSummary, out *stats.FlowSummary, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FlowSummary_To_stats_FlowSummary(in, out, s)
+}
+
+func autoConvert_stats_FlowSummary_To_v1alpha1_FlowSummary(in *stats.FlowSummary, out *FlowSummary, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.Pod = in.Pod
+	out.Direction = RuleDirection(in.Direction)
+	out.Peer = in.Peer
+	out.Protocol = in.Protocol
+	out.Port = in.Port
+	if err := Convert_stats_TrafficStats_To_v1alpha1_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_stats_FlowSummary_To_v1alpha1_FlowSummary is an autogenerated conversion function.
+func Convert_stats_FlowSummary_To_v1alpha1_FlowSummary(in *stats.FlowSummary, out *FlowSummary, s conversion.Scope) error {
+	return autoConvert_stats_FlowSummary_To_v1alpha1_FlowSummary(in, out, s)
+}
+
+func autoConvert_v1alpha1_NamespaceNetworkSummary_To_stats_NamespaceNetworkSummary(in *NamespaceNetworkSummary, out *stats.NamespaceNetworkSummary, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.NetworkPolicies = *(*[]stats.PolicyTrafficStats)(unsafe.Pointer(&in.NetworkPolicies))
+	out.AntreaNetworkPolicies = *(*[]stats.PolicyTrafficStats)(unsafe.Pointer(&in.AntreaNetworkPolicies))
+	out.Flows = *(*[]stats.FlowSummary)(unsafe.Pointer(&in.Flows))
+	return nil
+}
+
+// Convert_v1alpha1_NamespaceNetworkSummary_To_stats_NamespaceNetworkSummary is an autogenerated conversion function.
+func Convert_v1alpha1_NamespaceNetworkSummary_To_stats_NamespaceNetworkSummary(in *NamespaceNetworkSummary, out *stats.NamespaceNetworkSummary, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NamespaceNetworkSummary_To_stats_NamespaceNetworkSummary(in, out, s)
+}
+
+func autoConvert_stats_NamespaceNetworkSummary_To_v1alpha1_NamespaceNetworkSummary(in *stats.NamespaceNetworkSummary, out *NamespaceNetworkSummary, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.NetworkPolicies = *(*[]PolicyTrafficStats)(unsafe.Pointer(&in.NetworkPolicies))
+	out.AntreaNetworkPolicies = *(*[]PolicyTrafficStats)(unsafe.Pointer(&in.AntreaNetworkPolicies))
+	out.Flows = *(*[]FlowSummary)(unsafe.Pointer(&in.Flows))
+	return nil
+}
+
+// Convert_stats_NamespaceNetworkSummary_To_v1alpha1_NamespaceNetworkSummary is an autogenerated conversion function.
+func Convert_stats_NamespaceNetworkSummary_To_v1alpha1_NamespaceNetworkSummary(in *stats.NamespaceNetworkSummary, out *NamespaceNetworkSummary, s conversion.Scope) error {
+	return autoConvert_stats_NamespaceNetworkSummary_To_v1alpha1_NamespaceNetworkSummary(in, out, s)
+}
+
+func autoConvert_v1alpha1_NamespaceNetworkSummaryList_To_stats_NamespaceNetworkSummaryList(in *NamespaceNetworkSummaryList, out *stats.NamespaceNetworkSummaryList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]stats.NamespaceNetworkSummary)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1alpha1_NamespaceNetworkSummaryList_To_stats_NamespaceNetworkSummaryList is an autogenerated conversion function.
+func Convert_v1alpha1_NamespaceNetworkSummaryList_To_stats_NamespaceNetworkSummaryList(in *NamespaceNetworkSummaryList, out *stats.NamespaceNetworkSummaryList, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NamespaceNetworkSummaryList_To_stats_NamespaceNetworkSummaryList(in, out, s)
+}
+
+func autoConvert_stats_NamespaceNetworkSummaryList_To_v1alpha1_NamespaceNetworkSummaryList(in *stats.NamespaceNetworkSummaryList, out *NamespaceNetworkSummaryList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]NamespaceNetworkSummary)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_stats_NamespaceNetworkSummaryList_To_v1alpha1_NamespaceNetworkSummaryList is an autogenerated conversion function.
+func Convert_stats_NamespaceNetworkSummaryList_To_v1alpha1_NamespaceNetworkSummaryList(in *stats.NamespaceNetworkSummaryList, out *NamespaceNetworkSummaryList, s conversion.Scope) error {
+	return autoConvert_stats_NamespaceNetworkSummaryList_To_v1alpha1_NamespaceNetworkSummaryList(in, out, s)
+}
+
 func autoConvert_v1alpha1_NetworkPolicyStats_To_stats_NetworkPolicyStats(in *NetworkPolicyStats, out *stats.NetworkPolicyStats, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha1_TrafficStats_To_stats_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
@@ -250,6 +388,62 @@ func Convert_stats_NetworkPolicyStatsList_To_v1alpha1_NetworkPolicyStatsList(in
 	return autoConvert_stats_NetworkPolicyStatsList_To_v1alpha1_NetworkPolicyStatsList(in, out, s)
 }
 
+func autoConvert_v1alpha1_PolicyTrafficStats_To_stats_PolicyTrafficStats(in *PolicyTrafficStats, out *stats.PolicyTrafficStats, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_v1alpha1_TrafficStats_To_stats_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
+		return err
+	}
+	out.RuleTrafficStats = *(*[]stats.RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
+	return nil
+}
+
+// Convert_v1alpha1_PolicyTrafficStats_To_stats_PolicyTrafficStats is an autogenerated conversion function.
+func Convert_v1alpha1_PolicyTrafficStats_To_stats_PolicyTrafficStats(in *PolicyTrafficStats, out *stats.PolicyTrafficStats, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PolicyTrafficStats_To_stats_PolicyTrafficStats(in, out, s)
+}
+
+func autoConvert_stats_PolicyTrafficStats_To_v1alpha1_PolicyTrafficStats(in *stats.PolicyTrafficStats, out *PolicyTrafficStats, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_stats_TrafficStats_To_v1alpha1_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
+		return err
+	}
+	out.RuleTrafficStats = *(*[]RuleTrafficStats)(unsafe.Pointer(&in.RuleTrafficStats))
+	return nil
+}
+
+// Convert_stats_PolicyTrafficStats_To_v1alpha1_PolicyTrafficStats is an autogenerated conversion function.
+func Convert_stats_PolicyTrafficStats_To_v1alpha1_PolicyTrafficStats(in *stats.PolicyTrafficStats, out *PolicyTrafficStats, s conversion.Scope) error {
+	return autoConvert_stats_PolicyTrafficStats_To_v1alpha1_PolicyTrafficStats(in, out, s)
+}
+
+func autoConvert_v1alpha1_RuleTrafficStats_To_stats_RuleTrafficStats(in *RuleTrafficStats, out *stats.RuleTrafficStats, s conversion.Scope) error {
+	out.Direction = stats.RuleDirection(in.Direction)
+	out.Index = in.Index
+	if err := Convert_v1alpha1_TrafficStats_To_stats_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_RuleTrafficStats_To_stats_RuleTrafficStats is an autogenerated conversion function.
+func Convert_v1alpha1_RuleTrafficStats_To_stats_RuleTrafficStats(in *RuleTrafficStats, out *stats.RuleTrafficStats, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RuleTrafficStats_To_stats_RuleTrafficStats(in, out, s)
+}
+
+func autoConvert_stats_RuleTrafficStats_To_v1alpha1_RuleTrafficStats(in *stats.RuleTrafficStats, out *RuleTrafficStats, s conversion.Scope) error {
+	out.Direction = RuleDirection(in.Direction)
+	out.Index = in.Index
+	if err := Convert_stats_TrafficStats_To_v1alpha1_TrafficStats(&in.TrafficStats, &out.TrafficStats, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_stats_RuleTrafficStats_To_v1alpha1_RuleTrafficStats is an autogenerated conversion function.
+func Convert_stats_RuleTrafficStats_To_v1alpha1_RuleTrafficStats(in *stats.RuleTrafficStats, out *RuleTrafficStats, s conversion.Scope) error {
+	return autoConvert_stats_RuleTrafficStats_To_v1alpha1_RuleTrafficStats(in, out, s)
+}
+
 func autoConvert_v1alpha1_TrafficStats_To_stats_TrafficStats(in *TrafficStats, out *stats.TrafficStats, s conversion.Scope) error {
 	out.Packets = in.Packets
 	out.Bytes = in.Bytes
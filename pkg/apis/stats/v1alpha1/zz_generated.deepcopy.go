@@ -28,6 +28,11 @@ func (in *AntreaClusterNetworkPolicyStats) DeepCopyInto(out *AntreaClusterNetwor
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.TrafficStats = in.TrafficStats
+	if in.RuleTrafficStats != nil {
+		in, out := &in.RuleTrafficStats, &out.RuleTrafficStats
+		*out = make([]RuleTrafficStats, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -88,6 +93,11 @@ func (in *AntreaNetworkPolicyStats) DeepCopyInto(out *AntreaNetworkPolicyStats)
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.TrafficStats = in.TrafficStats
+	if in.RuleTrafficStats != nil {
+		in, out := &in.RuleTrafficStats, &out.RuleTrafficStats
+		*out = make([]RuleTrafficStats, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -142,6 +152,101 @@ func (in *AntreaNetworkPolicyStatsList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowSummary) DeepCopyInto(out *FlowSummary) {
+	*out = *in
+	out.TrafficStats = in.TrafficStats
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowSummary.
+func (in *FlowSummary) DeepCopy() *FlowSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceNetworkSummary) DeepCopyInto(out *NamespaceNetworkSummary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make([]PolicyTrafficStats, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AntreaNetworkPolicies != nil {
+		in, out := &in.AntreaNetworkPolicies, &out.AntreaNetworkPolicies
+		*out = make([]PolicyTrafficStats, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Flows != nil {
+		in, out := &in.Flows, &out.Flows
+		*out = make([]FlowSummary, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceNetworkSummary.
+func (in *NamespaceNetworkSummary) DeepCopy() *NamespaceNetworkSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceNetworkSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceNetworkSummary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceNetworkSummaryList) DeepCopyInto(out *NamespaceNetworkSummaryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceNetworkSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceNetworkSummaryList.
+func (in *NamespaceNetworkSummaryList) DeepCopy() *NamespaceNetworkSummaryList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceNetworkSummaryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceNetworkSummaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkPolicyStats) DeepCopyInto(out *NetworkPolicyStats) {
 	*out = *in
@@ -202,6 +307,45 @@ func (in *NetworkPolicyStatsList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyTrafficStats) DeepCopyInto(out *PolicyTrafficStats) {
+	*out = *in
+	out.TrafficStats = in.TrafficStats
+	if in.RuleTrafficStats != nil {
+		in, out := &in.RuleTrafficStats, &out.RuleTrafficStats
+		*out = make([]RuleTrafficStats, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyTrafficStats.
+func (in *PolicyTrafficStats) DeepCopy() *PolicyTrafficStats {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyTrafficStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTrafficStats) DeepCopyInto(out *RuleTrafficStats) {
+	*out = *in
+	out.TrafficStats = in.TrafficStats
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTrafficStats.
+func (in *RuleTrafficStats) DeepCopy() *RuleTrafficStats {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTrafficStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrafficStats) DeepCopyInto(out *TrafficStats) {
 	*out = *in
@@ -37,8 +37,10 @@ import (
 	systeminstall "github.com/vmware-tanzu/antrea/pkg/apis/system/install"
 	system "github.com/vmware-tanzu/antrea/pkg/apis/system/v1beta1"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/certificate"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/effectiverules"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/endpoint"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/loglevel"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/policysimulation"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/handlers/webhook"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/controlplane/nodestatssummary"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/networkpolicy/addressgroup"
@@ -46,6 +48,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/networkpolicy/networkpolicy"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/stats/antreaclusternetworkpolicystats"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/stats/antreanetworkpolicystats"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/stats/namespacenetworksummary"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/stats/networkpolicystats"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/system/controllerinfo"
 	"github.com/vmware-tanzu/antrea/pkg/apiserver/registry/system/supportbundle"
@@ -77,14 +80,15 @@ func init() {
 
 // ExtraConfig holds custom apiserver config.
 type ExtraConfig struct {
-	addressGroupStore       storage.Interface
-	appliedToGroupStore     storage.Interface
-	networkPolicyStore      storage.Interface
-	controllerQuerier       querier.ControllerQuerier
-	endpointQuerier         controllernetworkpolicy.EndpointQuerier
-	networkPolicyController *controllernetworkpolicy.NetworkPolicyController
-	caCertController        *certificate.CACertController
-	statsAggregator         *stats.Aggregator
+	addressGroupStore             storage.Interface
+	appliedToGroupStore           storage.Interface
+	networkPolicyStore            storage.Interface
+	controllerQuerier             querier.ControllerQuerier
+	endpointQuerier               controllernetworkpolicy.EndpointQuerier
+	networkPolicyController       *controllernetworkpolicy.NetworkPolicyController
+	caCertController              *certificate.CACertController
+	statsAggregator               *stats.Aggregator
+	networkPolicyStatusController *controllernetworkpolicy.StatusController
 }
 
 // Config defines the config for Antrea apiserver.
@@ -121,18 +125,20 @@ func NewConfig(
 	statsAggregator *stats.Aggregator,
 	controllerQuerier querier.ControllerQuerier,
 	endpointQuerier controllernetworkpolicy.EndpointQuerier,
-	npController *controllernetworkpolicy.NetworkPolicyController) *Config {
+	npController *controllernetworkpolicy.NetworkPolicyController,
+	networkPolicyStatusController *controllernetworkpolicy.StatusController) *Config {
 	return &Config{
 		genericConfig: genericConfig,
 		extraConfig: ExtraConfig{
-			addressGroupStore:       addressGroupStore,
-			appliedToGroupStore:     appliedToGroupStore,
-			networkPolicyStore:      networkPolicyStore,
-			caCertController:        caCertController,
-			statsAggregator:         statsAggregator,
-			controllerQuerier:       controllerQuerier,
-			endpointQuerier:         endpointQuerier,
-			networkPolicyController: npController,
+			addressGroupStore:             addressGroupStore,
+			appliedToGroupStore:           appliedToGroupStore,
+			networkPolicyStore:            networkPolicyStore,
+			caCertController:              caCertController,
+			statsAggregator:               statsAggregator,
+			controllerQuerier:             controllerQuerier,
+			endpointQuerier:               endpointQuerier,
+			networkPolicyController:       npController,
+			networkPolicyStatusController: networkPolicyStatusController,
 		},
 	}
 }
@@ -151,6 +157,10 @@ func installAPIGroup(s *APIServer, c completedConfig) error {
 	cpStorage["appliedtogroups"] = appliedToGroupStorage
 	cpStorage["networkpolicies"] = networkPolicyStorage
 	cpStorage["nodestatssummaries"] = nodestatssummary.NewREST(c.extraConfig.statsAggregator)
+	// The status of NetworkPolicies is only reported for Antrea-native policies.
+	if c.extraConfig.networkPolicyStatusController != nil {
+		cpStorage["networkpolicies/status"] = networkpolicy.NewStatusREST(c.extraConfig.networkPolicyStatusController)
+	}
 	cpGroup.VersionedResourcesStorageMap["v1beta1"] = cpStorage
 
 	// TODO: networkingGroup is the legacy group of controlplane NetworkPolicy APIs. To allow live upgrades from up to
@@ -175,6 +185,7 @@ func installAPIGroup(s *APIServer, c completedConfig) error {
 	statsStorage["networkpolicystats"] = networkpolicystats.NewREST(c.extraConfig.statsAggregator)
 	statsStorage["antreaclusternetworkpolicystats"] = antreaclusternetworkpolicystats.NewREST(c.extraConfig.statsAggregator)
 	statsStorage["antreanetworkpolicystats"] = antreanetworkpolicystats.NewREST(c.extraConfig.statsAggregator)
+	statsStorage["namespacenetworksummaries"] = namespacenetworksummary.NewREST(c.extraConfig.statsAggregator)
 	statsGroup.VersionedResourcesStorageMap["v1alpha1"] = statsStorage
 
 	groups := []*genericapiserver.APIGroupInfo{&cpGroup, &networkingGroup, &systemGroup, &statsGroup}
@@ -228,6 +239,8 @@ func CleanupDeprecatedAPIServices(aggregatorClient clientset.Interface) error {
 func installHandlers(c *ExtraConfig, s *genericapiserver.GenericAPIServer) {
 	s.Handler.NonGoRestfulMux.HandleFunc("/loglevel", loglevel.HandleFunc())
 	s.Handler.NonGoRestfulMux.HandleFunc("/endpoint", endpoint.HandleFunc(c.endpointQuerier))
+	s.Handler.NonGoRestfulMux.HandleFunc("/effectiverules", effectiverules.HandleFunc(c.endpointQuerier))
+	s.Handler.NonGoRestfulMux.HandleFunc("/policysimulation", policysimulation.HandleFunc(c.networkPolicyController))
 	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
 		// Get new NetworkPolicyValidator
 		v := controllernetworkpolicy.NewNetworkPolicyValidator(c.networkPolicyController)
@@ -235,6 +248,7 @@ func installHandlers(c *ExtraConfig, s *genericapiserver.GenericAPIServer) {
 		s.Handler.NonGoRestfulMux.HandleFunc("/validate/tier", webhook.HandleValidationNetworkPolicy(v))
 		s.Handler.NonGoRestfulMux.HandleFunc("/validate/acnp", webhook.HandleValidationNetworkPolicy(v))
 		s.Handler.NonGoRestfulMux.HandleFunc("/validate/anp", webhook.HandleValidationNetworkPolicy(v))
+		s.Handler.NonGoRestfulMux.HandleFunc("/validate/group", webhook.HandleValidationNetworkPolicy(v))
 		// Install a post start hook to initialize Tiers on start-up
 		s.AddPostStartHook("initialize-tiers", func(context genericapiserver.PostStartHookContext) error {
 			go c.networkPolicyController.InitializeTiers()
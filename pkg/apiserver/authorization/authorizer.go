@@ -0,0 +1,111 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	systemv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/system/v1beta1"
+)
+
+const (
+	// DebugResource is the resource of the system.antrea.tanzu.vmware.com
+	// API group the debug endpoints are mapped to. Each endpoint is a
+	// subresource of it, e.g. "debug/ovsflows".
+	DebugResource = "debug"
+)
+
+// debugEndpoints are the debug endpoints of antrea-controller and antrea-agent,
+// keyed by path, with the subresource they are mapped to.
+var debugEndpoints = map[string]string{
+	"/addressgroups":    "addressgroups",
+	"/agentinfo":        "agentinfo",
+	"/appliedtogroups":  "appliedtogroups",
+	"/effectiverules":   "effectiverules",
+	"/endpoint":         "endpoint",
+	"/flowrecords":      "flowrecords",
+	"/loglevel":         "loglevel",
+	"/networkpolicies":  "networkpolicies",
+	"/nodeconfig":       "nodeconfig",
+	"/ovsflows":         "ovsflows",
+	"/ovstracing":       "ovstracing",
+	"/podinterfaces":    "podinterfaces",
+	"/policysimulation": "policysimulation",
+}
+
+// requestVerbs maps the verbs of the non-resource requests, which are the
+// lowercase HTTP methods, to the verbs of the resource requests.
+var requestVerbs = map[string]string{
+	"get":    "get",
+	"head":   "get",
+	"post":   "create",
+	"put":    "update",
+	"patch":  "patch",
+	"delete": "delete",
+}
+
+// debugAuthorizer authorizes the requests to the debug endpoints as requests
+// to distinct subresources of the debug resource, so that the access to each
+// endpoint, and to each operation of an endpoint, can be granted separately
+// with RBAC resource rules. The requests which are not allowed this way are
+// authorized as non-resource requests, so that the access granted with
+// nonResourceURLs rules is preserved.
+type debugAuthorizer struct {
+	delegate authorizer.Authorizer
+}
+
+// NewDebugAuthorizer returns an Authorizer which authorizes the requests to
+// the debug endpoints with the debug resource before delegating them.
+func NewDebugAuthorizer(delegate authorizer.Authorizer) authorizer.Authorizer {
+	return &debugAuthorizer{delegate: delegate}
+}
+
+func (a *debugAuthorizer) Authorize(ctx context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+	if resourceAttrs, ok := debugResourceAttributes(attrs); ok {
+		decision, reason, err := a.delegate.Authorize(ctx, resourceAttrs)
+		if decision == authorizer.DecisionAllow {
+			return decision, reason, err
+		}
+	}
+	return a.delegate.Authorize(ctx, attrs)
+}
+
+// debugResourceAttributes returns the attributes of the resource request the
+// non-resource request to a debug endpoint is mapped to.
+func debugResourceAttributes(attrs authorizer.Attributes) (authorizer.Attributes, bool) {
+	if attrs.IsResourceRequest() {
+		return nil, false
+	}
+	subresource, ok := debugEndpoints[strings.TrimSuffix(attrs.GetPath(), "/")]
+	if !ok {
+		return nil, false
+	}
+	verb, ok := requestVerbs[attrs.GetVerb()]
+	if !ok {
+		return nil, false
+	}
+	return authorizer.AttributesRecord{
+		User:            attrs.GetUser(),
+		Verb:            verb,
+		APIGroup:        systemv1beta1.GroupName,
+		APIVersion:      systemv1beta1.SchemeGroupVersion.Version,
+		Resource:        DebugResource,
+		Subresource:     subresource,
+		ResourceRequest: true,
+	}, true
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// fakeAuthorizer allows the requests whose attributes are in allowed.
+type fakeAuthorizer struct {
+	allowed []authorizer.AttributesRecord
+}
+
+func (a *fakeAuthorizer) Authorize(_ context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+	record := authorizer.AttributesRecord{
+		Verb:            attrs.GetVerb(),
+		APIGroup:        attrs.GetAPIGroup(),
+		APIVersion:      attrs.GetAPIVersion(),
+		Resource:        attrs.GetResource(),
+		Subresource:     attrs.GetSubresource(),
+		ResourceRequest: attrs.IsResourceRequest(),
+		Path:            attrs.GetPath(),
+	}
+	for _, allowed := range a.allowed {
+		if allowed == record {
+			return authorizer.DecisionAllow, "", nil
+		}
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+func debugResourceRecord(verb, subresource string) authorizer.AttributesRecord {
+	return authorizer.AttributesRecord{
+		Verb:            verb,
+		APIGroup:        "system.antrea.tanzu.vmware.com",
+		APIVersion:      "v1beta1",
+		Resource:        DebugResource,
+		Subresource:     subresource,
+		ResourceRequest: true,
+	}
+}
+
+func TestDebugAuthorizer(t *testing.T) {
+	delegate := &fakeAuthorizer{allowed: []authorizer.AttributesRecord{
+		debugResourceRecord("get", "ovsflows"),
+		debugResourceRecord("get", "loglevel"),
+		{Verb: "get", Path: "/flowrecords"},
+	}}
+	a := NewDebugAuthorizer(delegate)
+	testUser := &user.DefaultInfo{Name: "support"}
+	tests := []struct {
+		name     string
+		verb     string
+		path     string
+		expected authorizer.Decision
+	}{
+		{"debug resource allowed", "get", "/ovsflows", authorizer.DecisionAllow},
+		{"debug resource with trailing slash", "get", "/ovsflows/", authorizer.DecisionAllow},
+		{"debug resource not allowed", "get", "/ovstracing", authorizer.DecisionNoOpinion},
+		{"debug verb not allowed", "put", "/loglevel", authorizer.DecisionNoOpinion},
+		{"non-resource URL allowed", "get", "/flowrecords", authorizer.DecisionAllow},
+		{"other path", "get", "/healthz", authorizer.DecisionNoOpinion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, _, err := a.Authorize(context.TODO(), authorizer.AttributesRecord{
+				User: testUser,
+				Verb: tt.verb,
+				Path: tt.path,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, decision)
+		})
+	}
+}
@@ -16,6 +16,7 @@ package certificate
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"path"
@@ -47,6 +48,12 @@ var (
 	// maxRotateDuration ensures that if a self-signed certificate has a really long expiration (N years), we still attempt to rotate it
 	// within a reasonable time, in this case one year. maxRotateDuration is also used to force certificate rotation in unit tests.
 	maxRotateDuration = time.Hour * (24 * 365)
+
+	// caBundlePropagationDelay is how long a rotated self-signed certificate is published in the CA bundle, together
+	// with the current one, before it starts being served. It gives the API clients, e.g. antrea-agent and
+	// kube-aggregator, the time to trust the new certificate, so that no connection fails during the rotation.
+	// Declaring it as a variable for testing.
+	caBundlePropagationDelay = 2 * time.Minute
 )
 
 const (
@@ -134,7 +141,17 @@ func generateSelfSignedCertificate(secureServing *options.SecureServingOptionsWi
 		return nil, fmt.Errorf("error creating self-signed certificates: %v", err)
 	}
 
-	caContentProvider, err = dynamiccertificates.NewDynamicCAContentFromFile("self-signed cert", secureServing.ServerCert.CertKey.CertFile)
+	// The CA bundle is kept in a separate file, as it contains both the current and the next certificate while the
+	// certificate is being rotated. A certificate rotation interrupted by a restart is simply started again.
+	cert, err := ioutil.ReadFile(secureServing.ServerCert.CertKey.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading self-signed certificate: %v", err)
+	}
+	if err := certutil.WriteCert(selfSignedCABundlePath(), cert); err != nil {
+		return nil, fmt.Errorf("error writing self-signed CA bundle: %v", err)
+	}
+
+	caContentProvider, err = dynamiccertificates.NewDynamicCAContentFromFile("self-signed cert", selfSignedCABundlePath())
 	if err != nil {
 		return nil, fmt.Errorf("error reading self-signed CA certificate: %v", err)
 	}
@@ -142,6 +159,11 @@ func generateSelfSignedCertificate(secureServing *options.SecureServingOptionsWi
 	return caContentProvider, nil
 }
 
+// selfSignedCABundlePath returns the path of the file containing the CA bundle of the self-signed certificates.
+func selfSignedCABundlePath() string {
+	return path.Join(selfSignedCertDir, CACertFile)
+}
+
 // Used to determine which is sooner, the provided maxRotateDuration or the expiration date
 // of the cert. Used to allow for unit testing with a far shorter rotation period.
 // Also can be used to pass a user provided rotation window.
@@ -167,6 +189,9 @@ func nextRotationDuration(secureServing *options.SecureServingOptionsWithLoopbac
 
 // rotateSelfSignedCertificates calculates the rotation duration for the current certificate.
 // Then once the duration is complete, generates a new self-signed certificate and repeats the process.
+// The new certificate is first added to the published CA bundle, and only served after caBundlePropagationDelay,
+// so that the clients never see a certificate they don't trust yet. The established connections, e.g. the
+// long-lived watches of antrea-agent, are not affected by the rotation.
 func rotateSelfSignedCertificates(c *CACertController, secureServing *options.SecureServingOptionsWithLoopback,
 	maxRotateDuration time.Duration) {
 	for {
@@ -182,21 +207,46 @@ func rotateSelfSignedCertificates(c *CACertController, secureServing *options.Se
 
 		klog.Infof("Rotating self signed certificate")
 
-		err = generateNewServingCertificate(secureServing)
+		cert, key, err := certutil.GenerateSelfSignedCertKeyWithFixtures("antrea", []net.IP{net.ParseIP("127.0.0.1")}, GetAntreaServerNames(), secureServing.ServerCert.FixtureDirectory)
+		if err != nil {
+			klog.Errorf("unable to generate self signed cert: %v", err)
+			return
+		}
+
+		err = publishNextCertificate(secureServing, cert)
 		if err != nil {
-			klog.Errorf("error generating new cert: %v", err)
+			klog.Errorf("error publishing new cert: %v", err)
 			return
 		}
 		c.UpdateCertificate()
+
+		klog.Infof("New certificate will be served at %v", time.Now().Add(caBundlePropagationDelay))
+		time.Sleep(caBundlePropagationDelay)
+
+		err = writeServingCertificate(secureServing, cert, key)
+		if err != nil {
+			klog.Errorf("error writing new cert: %v", err)
+			return
+		}
 	}
 }
 
-func generateNewServingCertificate(secureServing *options.SecureServingOptionsWithLoopback) error {
-	cert, key, err := certutil.GenerateSelfSignedCertKeyWithFixtures("antrea", []net.IP{net.ParseIP("127.0.0.1")}, GetAntreaServerNames(), secureServing.ServerCert.FixtureDirectory)
+// publishNextCertificate writes the CA bundle with the next certificate, followed by the current one.
+func publishNextCertificate(secureServing *options.SecureServingOptionsWithLoopback, nextCert []byte) error {
+	currentCert, err := ioutil.ReadFile(secureServing.ServerCert.CertKey.CertFile)
 	if err != nil {
-		return fmt.Errorf("unable to generate self signed cert: %v", err)
+		return err
+	}
+	caBundle := append(append([]byte{}, nextCert...), currentCert...)
+	if err := certutil.WriteCert(selfSignedCABundlePath(), caBundle); err != nil {
+		return err
 	}
+	klog.Infof("Published next self-signed cert in CA bundle (%s)", selfSignedCABundlePath())
+	return nil
+}
 
+// writeServingCertificate writes the certificate and the key served by the apiserver, which reloads them from disk.
+func writeServingCertificate(secureServing *options.SecureServingOptionsWithLoopback, cert, key []byte) error {
 	if err := certutil.WriteCert(secureServing.ServerCert.CertKey.CertFile, cert); err != nil {
 		return err
 	}
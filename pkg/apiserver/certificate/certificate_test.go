@@ -180,6 +180,7 @@ func TestApplyServerCert(t *testing.T) {
 
 			if tt.testRotate {
 				maxRotateDuration = 2 * time.Second
+				caBundlePropagationDelay = 500 * time.Millisecond
 			}
 
 			clientset := fakeclientset.NewSimpleClientset()
@@ -202,6 +203,25 @@ func TestApplyServerCert(t *testing.T) {
 				})
 
 				assert.Nil(t, err, "CA cert not updated")
+
+				// The CA bundle contains both the next and the current certificate during the rotation, and the next
+				// certificate is only served after caBundlePropagationDelay.
+				oldCert, err := certutil.ParseCertsPEM(oldCertKeyContent)
+				assert.NoError(t, err)
+				caCerts, err := certutil.ParseCertsPEM(got.getCertificate())
+				assert.NoError(t, err)
+				// Each self-signed certificate file contains the certificate and the CA certificate that signs it.
+				if assert.Equal(t, 2*len(oldCert), len(caCerts), "CA bundle should contain the next and the current certificates") {
+					assert.True(t, caCerts[len(oldCert)].Equal(oldCert[0]), "CA bundle should still contain the current certificate")
+					err = wait.Poll(100*time.Millisecond, 2*time.Second, func() (bool, error) {
+						servingCert, err := certutil.CertsFromFile(secureServing.ServerCert.CertKey.CertFile)
+						if err != nil {
+							return false, err
+						}
+						return servingCert[0].Equal(caCerts[0]), nil
+					})
+					assert.Nil(t, err, "Next certificate not served")
+				}
 			}
 
 			if tt.wantCertKey {
@@ -0,0 +1,112 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package effectiverules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy"
+)
+
+const (
+	formatJSON = "json"
+	formatDOT  = "dot"
+)
+
+// HandleFunc creates a http.HandlerFunc which uses an EndpointQuerier to query the
+// effective rules of a Pod or Namespace, i.e. the ordered list of the rules of all the
+// NetworkPolicies applied to it across Tiers. The response is encoded in JSON by default,
+// or as a Graphviz DOT graph with "format=dot".
+func HandleFunc(eq networkpolicy.EndpointQuerier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.URL.Query().Get("namespace")
+		podName := r.URL.Query().Get("pod")
+		format := r.URL.Query().Get("format")
+		if namespace == "" {
+			http.Error(w, "namespace must be provided", http.StatusBadRequest)
+			return
+		}
+		if format == "" {
+			format = formatJSON
+		}
+		if format != formatJSON && format != formatDOT {
+			http.Error(w, fmt.Sprintf("unsupported format %q, must be %q or %q", format, formatJSON, formatDOT), http.StatusBadRequest)
+			return
+		}
+		response, err := eq.QueryEffectiveRules(namespace, podName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if response == nil {
+			http.Error(w, "could not find any Pod or Namespace matching your selection", http.StatusNotFound)
+			return
+		}
+		if format == formatDOT {
+			w.Header().Set("Content-Type", "text/vnd.graphviz")
+			w.Write(toDOT(response))
+			return
+		}
+		if err := json.NewEncoder(w).Encode(*response); err != nil {
+			http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// toDOT renders the effective rules as a directed graph, with one chain of rules per direction
+// which follows the order of enforcement.
+func toDOT(response *networkpolicy.EffectiveRulesResponse) []byte {
+	var b bytes.Buffer
+	target := response.Namespace
+	if response.Pod != "" {
+		target = response.Namespace + "/" + response.Pod
+	}
+	fmt.Fprintf(&b, "digraph %q {\n", "effective-rules "+target)
+	b.WriteString("  rankdir=TB;\n  node [shape=box];\n")
+	writeDOTChain(&b, "ingress", "Ingress", response.Ingress)
+	writeDOTChain(&b, "egress", "Egress", response.Egress)
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeDOTChain(b *bytes.Buffer, id, label string, rules []networkpolicy.EffectiveRule) {
+	fmt.Fprintf(b, "  subgraph %q {\n    label=%q;\n", "cluster_"+id, label)
+	for i, rule := range rules {
+		policy := rule.Name
+		if rule.Namespace != "" {
+			policy = rule.Namespace + "/" + rule.Name
+		}
+		nodeLabel := fmt.Sprintf("%d. %s %s\n%s rule %d", i+1, rule.Action, policy, rule.PolicyType, rule.RuleIndex)
+		if rule.Tier != "" {
+			nodeLabel += fmt.Sprintf("\ntier %s", rule.Tier)
+		}
+		color := "red"
+		switch rule.Action {
+		case secv1alpha1.RuleActionAllow:
+			color = "darkgreen"
+		case secv1alpha1.RuleActionAudit:
+			color = "orange"
+		}
+		fmt.Fprintf(b, "    %q [label=%q, color=%s];\n", fmt.Sprintf("%s-%d", id, i), nodeLabel, color)
+		if i > 0 {
+			fmt.Fprintf(b, "    %q -> %q;\n", fmt.Sprintf("%s-%d", id, i-1), fmt.Sprintf("%s-%d", id, i))
+		}
+	}
+	b.WriteString("  }\n")
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package effectiverules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy"
+	queriermock "github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/testing"
+)
+
+var tierPriority = int32(50)
+
+var testResponse = &networkpolicy.EffectiveRulesResponse{
+	Namespace: "ns1",
+	Pod:       "pod1",
+	Ingress: []networkpolicy.EffectiveRule{
+		{
+			PolicyRef:    networkpolicy.PolicyRef{Name: "acnp1"},
+			PolicyType:   controlplane.AntreaClusterNetworkPolicy,
+			Tier:         "securityops",
+			TierPriority: &tierPriority,
+			RuleIndex:    0,
+			Action:       secv1alpha1.RuleActionDrop,
+			Direction:    controlplane.DirectionIn,
+		},
+		{
+			PolicyRef:  networkpolicy.PolicyRef{Namespace: "ns1", Name: "np1"},
+			PolicyType: controlplane.K8sNetworkPolicy,
+			RuleIndex:  0,
+			Action:     secv1alpha1.RuleActionAllow,
+			Direction:  controlplane.DirectionIn,
+		},
+	},
+	Egress: []networkpolicy.EffectiveRule{},
+}
+
+func TestEffectiveRulesHandler(t *testing.T) {
+	testCases := []struct {
+		name           string
+		request        string
+		expectQuery    bool
+		queryResponse  *networkpolicy.EffectiveRulesResponse
+		queryError     error
+		expectedStatus int
+	}{
+		{"missing-namespace", "/effectiverules?pod=pod1", false, nil, nil, http.StatusBadRequest},
+		{"invalid-format", "/effectiverules?namespace=ns1&format=yaml", false, nil, nil, http.StatusBadRequest},
+		{"not-found", "/effectiverules?namespace=ns1&pod=pod1", true, nil, nil, http.StatusNotFound},
+		{"query-error", "/effectiverules?namespace=ns1&pod=pod1", true, nil, fmt.Errorf("error"), http.StatusInternalServerError},
+		{"json", "/effectiverules?namespace=ns1&pod=pod1", true, testResponse, nil, http.StatusOK},
+		{"dot", "/effectiverules?namespace=ns1&pod=pod1&format=dot", true, testResponse, nil, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockQuerier := queriermock.NewMockEndpointQuerier(mockCtrl)
+			if tc.expectQuery {
+				mockQuerier.EXPECT().QueryEffectiveRules("ns1", "pod1").Return(tc.queryResponse, tc.queryError)
+			}
+			req, err := http.NewRequest(http.MethodGet, tc.request, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			HandleFunc(mockQuerier).ServeHTTP(recorder, req)
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestEffectiveRulesHandlerJSON(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockQuerier := queriermock.NewMockEndpointQuerier(mockCtrl)
+	mockQuerier.EXPECT().QueryEffectiveRules("ns1", "").Return(testResponse, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/effectiverules?namespace=ns1", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	HandleFunc(mockQuerier).ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var received networkpolicy.EffectiveRulesResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+	assert.Equal(t, *testResponse, received)
+}
+
+func TestToDOT(t *testing.T) {
+	expected := `digraph "effective-rules ns1/pod1" {
+  rankdir=TB;
+  node [shape=box];
+  subgraph "cluster_ingress" {
+    label="Ingress";
+    "ingress-0" [label="1. Drop acnp1\nAntreaClusterNetworkPolicy rule 0\ntier securityops", color=red];
+    "ingress-1" [label="2. Allow ns1/np1\nK8sNetworkPolicy rule 0", color=darkgreen];
+    "ingress-0" -> "ingress-1";
+  }
+  subgraph "cluster_egress" {
+    label="Egress";
+  }
+}
+`
+	assert.Equal(t, expected, string(toDOT(testResponse)))
+}
@@ -25,10 +25,16 @@ import (
 )
 
 // HandleFunc returns the function which can handle the /loglevel API request.
+// The log level is set with a PUT request, so that the permission to set it can
+// be granted separately from the permission to get it.
 func HandleFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		level := r.URL.Query().Get("level")
 		if level != "" {
+			if r.Method != http.MethodPut {
+				http.Error(w, "the log level can only be set with a PUT request", http.StatusMethodNotAllowed)
+				return
+			}
 			err := log.SetLogLevel(level)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
@@ -0,0 +1,91 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policysimulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy"
+)
+
+// Request is the body of a policy simulation request.
+type Request struct {
+	Operation networkpolicy.SimulationOperation `json:"operation"`
+	// Policy is the manifest of the K8s NetworkPolicy, Antrea NetworkPolicy
+	// or Antrea ClusterNetworkPolicy, including its apiVersion and kind.
+	Policy json.RawMessage `json:"policy"`
+}
+
+// HandleFunc creates a http.HandlerFunc which uses a PolicySimulator to compute
+// the Pods whose connectivity would change if a policy was created, updated or
+// deleted, without applying the change.
+func HandleFunc(ps networkpolicy.PolicySimulator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed, must be POST", http.StatusMethodNotAllowed)
+			return
+		}
+		var request Request
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "failed to decode request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		policy, err := decodePolicy(request.Policy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response, err := ps.SimulatePolicyChange(request.Operation, policy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(*response); err != nil {
+			http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// decodePolicy decodes a policy manifest according to its apiVersion and kind.
+func decodePolicy(data []byte) (metav1.Object, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("policy must be provided")
+	}
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %v", err)
+	}
+	var policy metav1.Object
+	switch typeMeta.GroupVersionKind() {
+	case networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"):
+		policy = &networkingv1.NetworkPolicy{}
+	case secv1alpha1.SchemeGroupVersion.WithKind("NetworkPolicy"):
+		policy = &secv1alpha1.NetworkPolicy{}
+	case secv1alpha1.SchemeGroupVersion.WithKind("ClusterNetworkPolicy"):
+		policy = &secv1alpha1.ClusterNetworkPolicy{}
+	default:
+		return nil, fmt.Errorf("unsupported policy apiVersion %q and kind %q", typeMeta.APIVersion, typeMeta.Kind)
+	}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to decode policy: %v", err)
+	}
+	return policy, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policysimulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy"
+	queriermock "github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/testing"
+)
+
+var testResponse = &networkpolicy.PolicySimulationResponse{
+	Pods: []networkpolicy.PodConnectivityChange{{
+		Namespace: "ns1",
+		Name:      "pod1",
+		Ingress: []networkpolicy.PeerConnectivityChange{
+			{Namespace: "ns1", Name: "pod2", LostPorts: []string{"*", "TCP/*"}},
+		},
+	}},
+}
+
+func TestPolicySimulationHandler(t *testing.T) {
+	k8sPolicy := `{"apiVersion":"networking.k8s.io/v1","kind":"NetworkPolicy","metadata":{"namespace":"ns1","name":"np1"}}`
+	cnpPolicy := `{"apiVersion":"security.antrea.tanzu.vmware.com/v1alpha1","kind":"ClusterNetworkPolicy","metadata":{"name":"acnp1"},"spec":{"priority":5}}`
+	testCases := []struct {
+		name             string
+		method           string
+		body             string
+		expectedOp       networkpolicy.SimulationOperation
+		expectedPolicy   metav1.Object
+		simulationError  error
+		expectedStatus   int
+		expectedResponse *networkpolicy.PolicySimulationResponse
+	}{
+		{"invalid-method", http.MethodGet, "", "", nil, nil, http.StatusMethodNotAllowed, nil},
+		{"invalid-body", http.MethodPost, "{", "", nil, nil, http.StatusBadRequest, nil},
+		{"missing-policy", http.MethodPost, `{"operation":"Create"}`, "", nil, nil, http.StatusBadRequest, nil},
+		{"unsupported-kind", http.MethodPost, `{"operation":"Create","policy":{"apiVersion":"v1","kind":"Pod"}}`, "", nil, nil, http.StatusBadRequest, nil},
+		{
+			"simulation-error",
+			http.MethodPost,
+			`{"operation":"Update","policy":` + k8sPolicy + `}`,
+			networkpolicy.SimulationUpdate,
+			&networkingv1.NetworkPolicy{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "np1"},
+			},
+			fmt.Errorf("policy does not exist"),
+			http.StatusBadRequest,
+			nil,
+		},
+		{
+			"cluster-network-policy",
+			http.MethodPost,
+			`{"operation":"Create","policy":` + cnpPolicy + `}`,
+			networkpolicy.SimulationCreate,
+			&secv1alpha1.ClusterNetworkPolicy{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "security.antrea.tanzu.vmware.com/v1alpha1", Kind: "ClusterNetworkPolicy"},
+				ObjectMeta: metav1.ObjectMeta{Name: "acnp1"},
+				Spec:       secv1alpha1.ClusterNetworkPolicySpec{Priority: 5},
+			},
+			nil,
+			http.StatusOK,
+			testResponse,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockSimulator := queriermock.NewMockPolicySimulator(mockCtrl)
+			if tc.expectedPolicy != nil {
+				mockSimulator.EXPECT().SimulatePolicyChange(tc.expectedOp, tc.expectedPolicy).Return(tc.expectedResponse, tc.simulationError)
+			}
+			handler := HandleFunc(mockSimulator)
+			req, err := http.NewRequest(tc.method, "/policysimulation", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedResponse != nil {
+				var received networkpolicy.PolicySimulationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+				assert.Equal(t, *tc.expectedResponse, received)
+			}
+		})
+	}
+}
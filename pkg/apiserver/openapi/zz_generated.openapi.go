@@ -36,6 +36,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.AntreaControllerInfoList":    schema_pkg_apis_clusterinformation_v1beta1_AntreaControllerInfoList(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.ControllerCondition":         schema_pkg_apis_clusterinformation_v1beta1_ControllerCondition(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.NetworkPolicyControllerInfo": schema_pkg_apis_clusterinformation_v1beta1_NetworkPolicyControllerInfo(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.NodeCapacityInfo":            schema_pkg_apis_clusterinformation_v1beta1_NodeCapacityInfo(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.OVSInfo":                     schema_pkg_apis_clusterinformation_v1beta1_OVSInfo(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.AddressGroup":                      schema_pkg_apis_controlplane_v1beta1_AddressGroup(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.AddressGroupList":                  schema_pkg_apis_controlplane_v1beta1_AddressGroupList(ref),
@@ -52,19 +53,29 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NamedPort":                         schema_pkg_apis_controlplane_v1beta1_NamedPort(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicy":                     schema_pkg_apis_controlplane_v1beta1_NetworkPolicy(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyList":                 schema_pkg_apis_controlplane_v1beta1_NetworkPolicyList(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyNodeStatus":           schema_pkg_apis_controlplane_v1beta1_NetworkPolicyNodeStatus(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyPeer":                 schema_pkg_apis_controlplane_v1beta1_NetworkPolicyPeer(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyReference":            schema_pkg_apis_controlplane_v1beta1_NetworkPolicyReference(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyRule":                 schema_pkg_apis_controlplane_v1beta1_NetworkPolicyRule(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyRuleFailure":          schema_pkg_apis_controlplane_v1beta1_NetworkPolicyRuleFailure(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyStats":                schema_pkg_apis_controlplane_v1beta1_NetworkPolicyStats(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyStatus":               schema_pkg_apis_controlplane_v1beta1_NetworkPolicyStatus(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NodeReference":                     schema_pkg_apis_controlplane_v1beta1_NodeReference(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NodeStatsSummary":                  schema_pkg_apis_controlplane_v1beta1_NodeStatsSummary(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.PodReference":                      schema_pkg_apis_controlplane_v1beta1_PodReference(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.RuleRateLimit":                     schema_pkg_apis_controlplane_v1beta1_RuleRateLimit(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.Service":                           schema_pkg_apis_controlplane_v1beta1_Service(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.AntreaClusterNetworkPolicyStats":         schema_pkg_apis_stats_v1alpha1_AntreaClusterNetworkPolicyStats(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.AntreaClusterNetworkPolicyStatsList":     schema_pkg_apis_stats_v1alpha1_AntreaClusterNetworkPolicyStatsList(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.AntreaNetworkPolicyStats":                schema_pkg_apis_stats_v1alpha1_AntreaNetworkPolicyStats(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.AntreaNetworkPolicyStatsList":            schema_pkg_apis_stats_v1alpha1_AntreaNetworkPolicyStatsList(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.FlowSummary":                             schema_pkg_apis_stats_v1alpha1_FlowSummary(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.NamespaceNetworkSummary":                 schema_pkg_apis_stats_v1alpha1_NamespaceNetworkSummary(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.NamespaceNetworkSummaryList":             schema_pkg_apis_stats_v1alpha1_NamespaceNetworkSummaryList(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.NetworkPolicyStats":                      schema_pkg_apis_stats_v1alpha1_NetworkPolicyStats(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.NetworkPolicyStatsList":                  schema_pkg_apis_stats_v1alpha1_NetworkPolicyStatsList(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.PolicyTrafficStats":                      schema_pkg_apis_stats_v1alpha1_PolicyTrafficStats(ref),
+		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats":                        schema_pkg_apis_stats_v1alpha1_RuleTrafficStats(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats":                            schema_pkg_apis_stats_v1alpha1_TrafficStats(ref),
 		"github.com/vmware-tanzu/antrea/pkg/apis/system/v1beta1.SupportBundle":                           schema_pkg_apis_system_v1beta1_SupportBundle(ref),
 		"k8s.io/api/core/v1.AWSElasticBlockStoreVolumeSource":                                            schema_k8sio_api_core_v1_AWSElasticBlockStoreVolumeSource(ref),
@@ -468,11 +479,17 @@ func schema_pkg_apis_clusterinformation_v1beta1_AntreaAgentInfo(ref common.Refer
 							Format:      "int32",
 						},
 					},
+					"nodeCapacity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The port of antrea agent API Server",
+							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.NodeCapacityInfo"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.AgentCondition", "github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.NetworkPolicyControllerInfo", "github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.OVSInfo", "k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.AgentCondition", "github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.NetworkPolicyControllerInfo", "github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.NodeCapacityInfo", "github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1.OVSInfo", "k8s.io/api/core/v1.ObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
@@ -736,6 +753,46 @@ func schema_pkg_apis_clusterinformation_v1beta1_NetworkPolicyControllerInfo(ref
 	}
 }
 
+func schema_pkg_apis_clusterinformation_v1beta1_NodeCapacityInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeCapacityInfo reports the usage of the datapath resources of the Node, for capacity planning.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conntrackCount": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"conntrackMax": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of connections in the conntrack table (nf_conntrack_count)",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"ovsFlowCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size of the conntrack table (nf_conntrack_max)",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"ovsGroupCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Total number of flows in the OVS flow tables",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_clusterinformation_v1beta1_OVSInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1244,11 +1301,17 @@ func schema_pkg_apis_controlplane_v1beta1_GroupMember(ref common.ReferenceCallba
 							},
 						},
 					},
+					"node": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Node maintains the reference to the Node.",
+							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NodeReference"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.Endpoint", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.ExternalEntityReference", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.PodReference"},
+			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.Endpoint", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.ExternalEntityReference", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NodeReference", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.PodReference"},
 	}
 }
 
@@ -1515,11 +1578,53 @@ func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyList(ref common.Reference
 	}
 }
 
+func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyNodeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkPolicyNodeStatus is the status of a NetworkPolicy on a Node.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"nodeName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the Node that produces the status.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"generation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The generation realized by the Node.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"failedRules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedRules are the rules of the generation which the Node failed to realize. The other rules of the generation are realized.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyRuleFailure"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyRuleFailure"},
+	}
+}
+
 func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyPeer(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NetworkPolicyPeer describes a peer of NetworkPolicyRules. It could be a list of names of AddressGroups and/or a list of IPBlock.",
+				Description: "NetworkPolicyPeer describes a peer of NetworkPolicyRules. It could be a list of names of AddressGroups, a list of IPBlock and/or a list of FQDNs.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"addressGroups": {
@@ -1549,6 +1654,20 @@ func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyPeer(ref common.Reference
 							},
 						},
 					},
+					"fqdns": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A list of FQDNs, e.g. \"*.example.com\", whose resolved IP addresses are learned by the agents from DNS responses.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -1650,11 +1769,58 @@ func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyRule(ref common.Reference
 							Format:      "",
 						},
 					},
+					"enableLogging": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EnableLogging indicates whether the connections matched by the rule should be audit logged by the agents.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"rateLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RateLimit limits the rate of the new connections matched by the rule.",
+							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.RuleRateLimit"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyPeer", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.Service"},
+			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyPeer", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.RuleRateLimit", "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.Service"},
+	}
+}
+
+func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyRuleFailure(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkPolicyRuleFailure describes a rule of a NetworkPolicy which a Node failed to realize.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"direction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The direction of the rule.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"priority": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The priority of the rule within the NetworkPolicy, which is its index in the Ingress or Egress rules of the original policy.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The error which prevented the rule from being realized.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -1677,11 +1843,91 @@ func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyStats(ref common.Referenc
 							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"),
 						},
 					},
+					"ruleTrafficStats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The stats of the rules of the NetworkPolicy. It is only set for Antrea-native policies.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyReference", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"},
+	}
+}
+
+func schema_pkg_apis_controlplane_v1beta1_NetworkPolicyStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkPolicyStatus is the status of a NetworkPolicy. It's used by the antrea-agents to report the generation of the NetworkPolicy they have realized to the antrea-controller.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"nodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Nodes contains statuses produced on a list of Nodes.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyNodeStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyReference", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"},
+			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyNodeStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_controlplane_v1beta1_NodeReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeReference represents a Node Reference.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of this Node.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -1750,11 +1996,24 @@ func schema_pkg_apis_controlplane_v1beta1_NodeStatsSummary(ref common.ReferenceC
 							},
 						},
 					},
+					"flows": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The summaries of the connections of the Pods running on the Node. Unlike the TrafficStats of the policies, they are not a delta but a snapshot of the connections tracked by the Node. They are only collected when the flow exporter runs in antrea-agent.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.FlowSummary"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyStats", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1.NetworkPolicyStats", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.FlowSummary", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
@@ -1785,6 +2044,33 @@ func schema_pkg_apis_controlplane_v1beta1_PodReference(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_controlplane_v1beta1_RuleRateLimit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RuleRateLimit limits the rate of the new connections matched by a rule on each Node.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxNewConnectionsPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxNewConnectionsPerSecond is the maximum rate of new connections.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"burst": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Burst is the number of new connections which can exceed the rate at once.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_controlplane_v1beta1_Service(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1794,7 +2080,7 @@ func schema_pkg_apis_controlplane_v1beta1_Service(ref common.ReferenceCallback)
 				Properties: map[string]spec.Schema{
 					"protocol": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The protocol (TCP, UDP, or SCTP) which traffic must match. If not specified, this field defaults to TCP.",
+							Description: "The protocol (TCP, UDP, SCTP, ICMP, IGMP or IP) which traffic must match. If not specified, this field defaults to TCP.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -1805,6 +2091,26 @@ func schema_pkg_apis_controlplane_v1beta1_Service(ref common.ReferenceCallback)
 							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
 						},
 					},
+					"icmpType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ICMP type and code to match. They can only be set when the protocol is ICMP. If not specified, this matches all ICMP types and codes.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"icmpCode": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"ipProtocol": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The IANA number of the IP protocol to match. It must be set when the protocol is IP, and cannot be set otherwise.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 			},
 		},
@@ -1845,11 +2151,24 @@ func schema_pkg_apis_stats_v1alpha1_AntreaClusterNetworkPolicyStats(ref common.R
 							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"),
 						},
 					},
+					"ruleTrafficStats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The traffic stats of the rules of the Antrea ClusterNetworkPolicy.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
@@ -1933,11 +2252,24 @@ func schema_pkg_apis_stats_v1alpha1_AntreaNetworkPolicyStats(ref common.Referenc
 							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"),
 						},
 					},
+					"ruleTrafficStats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The traffic stats of the rules of the Antrea NetworkPolicy.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
@@ -1989,6 +2321,191 @@ func schema_pkg_apis_stats_v1alpha1_AntreaNetworkPolicyStatsList(ref common.Refe
 	}
 }
 
+func schema_pkg_apis_stats_v1alpha1_FlowSummary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FlowSummary summarizes the connections of a Pod with a peer, on a protocol and a destination port.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace is the Namespace of the Pod.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pod is the name of the Pod.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"direction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Direction is Ingress for the connections to the Pod, and Egress for the connections from the Pod.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"peer": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Peer is the other end of the connections: \"<Namespace>/<Pod>\" for a Pod running on the same Node, \"<Namespace>/<Service>:<port>\" for a Service, or the subnet of the IP otherwise. The Pods and Services of other Namespaces are \"<other Namespace>\", and \"<others>\" aggregates the peers with the least traffic.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"protocol": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Protocol is the protocol of the connections, e.g. TCP.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Port is the destination port of the connections. It is 0 for the protocols without ports.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"trafficStats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TrafficStats is the traffic stats of the connections, in both directions. Sessions is the number of connections.",
+							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"),
+						},
+					},
+				},
+				Required: []string{"namespace", "pod", "direction", "peer", "protocol"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"},
+	}
+}
+
+func schema_pkg_apis_stats_v1alpha1_NamespaceNetworkSummary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NamespaceNetworkSummary is the network state of a Namespace, which is named after the Namespace: the traffic stats of its NetworkPolicies and the connections of its Pods. It lets the users who can view a Namespace see its network state without access to the cluster-wide APIs.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"networkPolicies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The traffic stats of the K8s NetworkPolicies of the Namespace.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.PolicyTrafficStats"),
+									},
+								},
+							},
+						},
+					},
+					"antreaNetworkPolicies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The traffic stats of the Antrea NetworkPolicies of the Namespace.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.PolicyTrafficStats"),
+									},
+								},
+							},
+						},
+					},
+					"flows": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The summaries of the connections of the Pods of the Namespace.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.FlowSummary"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.FlowSummary", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.PolicyTrafficStats", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_stats_v1alpha1_NamespaceNetworkSummaryList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NamespaceNetworkSummaryList is a list of NamespaceNetworkSummary.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Description: "List of NamespaceNetworkSummary.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.NamespaceNetworkSummary"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.NamespaceNetworkSummary", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
 func schema_pkg_apis_stats_v1alpha1_NetworkPolicyStats(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2077,6 +2594,84 @@ func schema_pkg_apis_stats_v1alpha1_NetworkPolicyStatsList(ref common.ReferenceC
 	}
 }
 
+func schema_pkg_apis_stats_v1alpha1_PolicyTrafficStats(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PolicyTrafficStats contains the traffic stats of a NetworkPolicy of a Namespace.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the NetworkPolicy.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"trafficStats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TrafficStats is the traffic stats of the NetworkPolicy.",
+							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"),
+						},
+					},
+					"ruleTrafficStats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RuleTrafficStats is the traffic stats of the rules of the NetworkPolicy. It is only set for Antrea-native policies.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.RuleTrafficStats", "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"},
+	}
+}
+
+func schema_pkg_apis_stats_v1alpha1_RuleTrafficStats(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RuleTrafficStats contains the traffic stats of a rule of an Antrea-native policy.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"direction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Direction is the direction of the rule.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"index": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Index is the index of the rule in the ingress or egress rules of the policy.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"trafficStats": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TrafficStats is the traffic stats of the rule.",
+							Ref:         ref("github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"),
+						},
+					},
+				},
+				Required: []string{"direction", "index"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1.TrafficStats"},
+	}
+}
+
 func schema_pkg_apis_stats_v1alpha1_TrafficStats(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
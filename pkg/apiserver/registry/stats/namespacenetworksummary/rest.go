@@ -0,0 +1,130 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacenetworksummary
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metatable "k8s.io/apimachinery/pkg/api/meta/table"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/features"
+)
+
+// REST implements the storage of NamespaceNetworkSummaries. A NamespaceNetworkSummary is named after its Namespace,
+// so that the users who can view a Namespace can get its network state with the usual namespaced RBAC.
+type REST struct {
+	summaryProvider summaryProvider
+}
+
+// NewREST returns a REST object that will work against API services.
+func NewREST(p summaryProvider) *REST {
+	return &REST{p}
+}
+
+var (
+	_ rest.Storage = &REST{}
+	_ rest.Scoper  = &REST{}
+	_ rest.Getter  = &REST{}
+	_ rest.Lister  = &REST{}
+)
+
+type summaryProvider interface {
+	ListNamespaceNetworkSummaries(namespace string) []statsv1alpha1.NamespaceNetworkSummary
+
+	GetNamespaceNetworkSummary(namespace string) *statsv1alpha1.NamespaceNetworkSummary
+}
+
+func (r *REST) New() runtime.Object {
+	return &statsv1alpha1.NamespaceNetworkSummary{}
+}
+
+func (r *REST) NewList() runtime.Object {
+	return &statsv1alpha1.NamespaceNetworkSummaryList{}
+}
+
+func (r *REST) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+	if !features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
+		return nil, errors.NewBadRequest("feature NetworkPolicyStats disabled")
+	}
+	ns, _ := request.NamespaceFrom(ctx)
+	items := r.summaryProvider.ListNamespaceNetworkSummaries(ns)
+	summaryList := &statsv1alpha1.NamespaceNetworkSummaryList{
+		Items: items,
+	}
+	return summaryList, nil
+}
+
+func (r *REST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	if !features.DefaultFeatureGate.Enabled(features.NetworkPolicyStats) {
+		return nil, errors.NewBadRequest("feature NetworkPolicyStats disabled")
+	}
+	ns, ok := request.NamespaceFrom(ctx)
+	if !ok || len(ns) == 0 {
+		return nil, errors.NewBadRequest("Namespace parameter required.")
+	}
+	// There is a single NamespaceNetworkSummary per Namespace, which is named after it.
+	if name != ns {
+		return nil, errors.NewNotFound(statsv1alpha1.Resource("namespacenetworksummaries"), name)
+	}
+	return r.summaryProvider.GetNamespaceNetworkSummary(ns), nil
+}
+
+var swaggerMetadataDescriptions = metav1.ObjectMeta{}.SwaggerDoc()
+
+func (r *REST) ConvertToTable(ctx context.Context, obj runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	table := &metav1.Table{
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name", Description: swaggerMetadataDescriptions["name"]},
+			{Name: "NetworkPolicies", Type: "integer", Description: "The number of K8s NetworkPolicies of the Namespace."},
+			{Name: "AntreaNetworkPolicies", Type: "integer", Description: "The number of Antrea NetworkPolicies of the Namespace."},
+			{Name: "Flows", Type: "integer", Description: "The number of the summaries of the connections of the Pods of the Namespace."},
+			{Name: "Sessions", Type: "integer", Description: "The number of the connections of the Pods of the Namespace."},
+		},
+	}
+	if m, err := meta.ListAccessor(obj); err == nil {
+		table.ResourceVersion = m.GetResourceVersion()
+		table.SelfLink = m.GetSelfLink()
+		table.Continue = m.GetContinue()
+		table.RemainingItemCount = m.GetRemainingItemCount()
+	} else {
+		if m, err := meta.CommonAccessor(obj); err == nil {
+			table.ResourceVersion = m.GetResourceVersion()
+			table.SelfLink = m.GetSelfLink()
+		}
+	}
+
+	var err error
+	table.Rows, err = metatable.MetaToTableRow(obj, func(obj runtime.Object, m metav1.Object, name, age string) ([]interface{}, error) {
+		summary := obj.(*statsv1alpha1.NamespaceNetworkSummary)
+		var sessions int64
+		for _, flow := range summary.Flows {
+			sessions += flow.TrafficStats.Sessions
+		}
+		return []interface{}{name, len(summary.NetworkPolicies), len(summary.AntreaNetworkPolicies), len(summary.Flows), sessions}, nil
+	})
+	return table, err
+}
+
+func (r *REST) NamespaceScoped() bool {
+	return true
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacenetworksummary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/features"
+)
+
+type fakeSummaryProvider struct {
+	summaries map[string]statsv1alpha1.NamespaceNetworkSummary
+}
+
+func (p *fakeSummaryProvider) ListNamespaceNetworkSummaries(namespace string) []statsv1alpha1.NamespaceNetworkSummary {
+	var list []statsv1alpha1.NamespaceNetworkSummary
+	for ns, summary := range p.summaries {
+		if namespace == "" || namespace == ns {
+			list = append(list, summary)
+		}
+	}
+	return list
+}
+
+func (p *fakeSummaryProvider) GetNamespaceNetworkSummary(namespace string) *statsv1alpha1.NamespaceNetworkSummary {
+	summary, exists := p.summaries[namespace]
+	if !exists {
+		return &statsv1alpha1.NamespaceNetworkSummary{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: namespace}}
+	}
+	return &summary
+}
+
+var (
+	fooSummary = statsv1alpha1.NamespaceNetworkSummary{
+		ObjectMeta:      metav1.ObjectMeta{Namespace: "foo", Name: "foo"},
+		NetworkPolicies: []statsv1alpha1.PolicyTrafficStats{{Name: "bar", TrafficStats: statsv1alpha1.TrafficStats{Sessions: 1, Packets: 2, Bytes: 3}}},
+		Flows: []statsv1alpha1.FlowSummary{{
+			Namespace:    "foo",
+			Pod:          "pod1",
+			Direction:    statsv1alpha1.RuleDirectionEgress,
+			Peer:         "10.10.0.1",
+			Protocol:     "TCP",
+			Port:         80,
+			TrafficStats: statsv1alpha1.TrafficStats{Sessions: 1, Packets: 2, Bytes: 3},
+		}},
+	}
+)
+
+func TestRESTGet(t *testing.T) {
+	tests := []struct {
+		name                      string
+		networkPolicyStatsEnabled bool
+		namespace                 string
+		summaryName               string
+		expectedObj               runtime.Object
+		expectedErr               bool
+	}{
+		{
+			name:                      "NetworkPolicyStats feature disabled",
+			networkPolicyStatsEnabled: false,
+			namespace:                 "foo",
+			summaryName:               "foo",
+			expectedErr:               true,
+		},
+		{
+			name:                      "name not matching namespace",
+			networkPolicyStatsEnabled: true,
+			namespace:                 "foo",
+			summaryName:               "bar",
+			expectedErr:               true,
+		},
+		{
+			name:                      "summary found",
+			networkPolicyStatsEnabled: true,
+			namespace:                 "foo",
+			summaryName:               "foo",
+			expectedObj:               &fooSummary,
+		},
+		{
+			name:                      "empty summary",
+			networkPolicyStatsEnabled: true,
+			namespace:                 "bar",
+			summaryName:               "bar",
+			expectedObj:               &statsv1alpha1.NamespaceNetworkSummary{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "bar"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.NetworkPolicyStats, tt.networkPolicyStatsEnabled)()
+
+			r := &REST{
+				summaryProvider: &fakeSummaryProvider{summaries: map[string]statsv1alpha1.NamespaceNetworkSummary{"foo": fooSummary}},
+			}
+			ctx := request.WithNamespace(context.TODO(), tt.namespace)
+			actualObj, err := r.Get(ctx, tt.summaryName, &metav1.GetOptions{})
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, actualObj)
+			}
+		})
+	}
+}
+
+func TestRESTList(t *testing.T) {
+	tests := []struct {
+		name                      string
+		networkPolicyStatsEnabled bool
+		namespace                 string
+		expectedObj               runtime.Object
+		expectedErr               bool
+	}{
+		{
+			name:                      "NetworkPolicyStats feature disabled",
+			networkPolicyStatsEnabled: false,
+			expectedErr:               true,
+		},
+		{
+			name:                      "one namespace",
+			networkPolicyStatsEnabled: true,
+			namespace:                 "foo",
+			expectedObj: &statsv1alpha1.NamespaceNetworkSummaryList{
+				Items: []statsv1alpha1.NamespaceNetworkSummary{fooSummary},
+			},
+		},
+		{
+			name:                      "namespace without summary",
+			networkPolicyStatsEnabled: true,
+			namespace:                 "bar",
+			expectedObj:               &statsv1alpha1.NamespaceNetworkSummaryList{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.NetworkPolicyStats, tt.networkPolicyStatsEnabled)()
+
+			r := &REST{
+				summaryProvider: &fakeSummaryProvider{summaries: map[string]statsv1alpha1.NamespaceNetworkSummary{"foo": fooSummary}},
+			}
+			ctx := request.WithNamespace(context.TODO(), tt.namespace)
+			actualObj, err := r.List(ctx, &internalversion.ListOptions{})
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedObj, actualObj)
+			}
+		})
+	}
+}
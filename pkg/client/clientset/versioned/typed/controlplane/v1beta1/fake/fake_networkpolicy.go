@@ -76,3 +76,14 @@ func (c *FakeNetworkPolicies) Watch(ctx context.Context, opts v1.ListOptions) (w
 		InvokesWatch(testing.NewWatchAction(networkpoliciesResource, c.ns, opts))
 
 }
+
+// UpdateStatus takes the representation of a networkPolicyStatus and creates it.  Returns the server's representation of the networkPolicyStatus, and an error, if there is any.
+func (c *FakeNetworkPolicies) UpdateStatus(ctx context.Context, networkPolicyName string, networkPolicyStatus *v1beta1.NetworkPolicyStatus, opts v1.CreateOptions) (result *v1beta1.NetworkPolicyStatus, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateSubresourceAction(networkpoliciesResource, networkPolicyName, "status", c.ns, networkPolicyStatus), &v1beta1.NetworkPolicyStatus{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NetworkPolicyStatus), err
+}
@@ -38,6 +38,8 @@ type NetworkPolicyInterface interface {
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.NetworkPolicy, error)
 	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.NetworkPolicyList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	UpdateStatus(ctx context.Context, networkPolicyName string, networkPolicyStatus *v1beta1.NetworkPolicyStatus, opts v1.CreateOptions) (*v1beta1.NetworkPolicyStatus, error)
+
 	NetworkPolicyExpansion
 }
 
@@ -99,3 +101,18 @@ func (c *networkPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch
 		Timeout(timeout).
 		Watch(ctx)
 }
+
+// UpdateStatus takes the representation of a networkPolicyStatus and creates it.  Returns the server's representation of the networkPolicyStatus, and an error, if there is any.
+func (c *networkPolicies) UpdateStatus(ctx context.Context, networkPolicyName string, networkPolicyStatus *v1beta1.NetworkPolicyStatus, opts v1.CreateOptions) (result *v1beta1.NetworkPolicyStatus, err error) {
+	result = &v1beta1.NetworkPolicyStatus{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("networkpolicies").
+		Name(networkPolicyName).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(networkPolicyStatus).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -26,10 +26,26 @@ type FakeCoreV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeCoreV1alpha1) Egresses() v1alpha1.EgressInterface {
+	return &FakeEgresses{c}
+}
+
 func (c *FakeCoreV1alpha1) ExternalEntities(namespace string) v1alpha1.ExternalEntityInterface {
 	return &FakeExternalEntities{c, namespace}
 }
 
+func (c *FakeCoreV1alpha1) ExternalIPPools() v1alpha1.ExternalIPPoolInterface {
+	return &FakeExternalIPPools{c}
+}
+
+func (c *FakeCoreV1alpha1) Groups(namespace string) v1alpha1.GroupInterface {
+	return &FakeGroups{c, namespace}
+}
+
+func (c *FakeCoreV1alpha1) IPPools() v1alpha1.IPPoolInterface {
+	return &FakeIPPools{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeCoreV1alpha1) RESTClient() rest.Interface {
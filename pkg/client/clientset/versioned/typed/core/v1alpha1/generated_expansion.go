@@ -16,4 +16,12 @@
 
 package v1alpha1
 
+type EgressExpansion interface{}
+
 type ExternalEntityExpansion interface{}
+
+type ExternalIPPoolExpansion interface{}
+
+type GroupExpansion interface{}
+
+type IPPoolExpansion interface{}
@@ -34,6 +34,10 @@ func (c *FakeStatsV1alpha1) AntreaNetworkPolicyStats(namespace string) v1alpha1.
 	return &FakeAntreaNetworkPolicyStats{c, namespace}
 }
 
+func (c *FakeStatsV1alpha1) NamespaceNetworkSummaries(namespace string) v1alpha1.NamespaceNetworkSummaryInterface {
+	return &FakeNamespaceNetworkSummaries{c, namespace}
+}
+
 func (c *FakeStatsV1alpha1) NetworkPolicyStats(namespace string) v1alpha1.NetworkPolicyStatsInterface {
 	return &FakeNetworkPolicyStats{c, namespace}
 }
@@ -20,4 +20,6 @@ type AntreaClusterNetworkPolicyStatsExpansion interface{}
 
 type AntreaNetworkPolicyStatsExpansion interface{}
 
+type NamespaceNetworkSummaryExpansion interface{}
+
 type NetworkPolicyStatsExpansion interface{}
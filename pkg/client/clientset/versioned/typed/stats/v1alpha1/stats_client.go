@@ -26,6 +26,7 @@ type StatsV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AntreaClusterNetworkPolicyStatsGetter
 	AntreaNetworkPolicyStatsGetter
+	NamespaceNetworkSummariesGetter
 	NetworkPolicyStatsGetter
 }
 
@@ -42,6 +43,10 @@ func (c *StatsV1alpha1Client) AntreaNetworkPolicyStats(namespace string) AntreaN
 	return newAntreaNetworkPolicyStats(c, namespace)
 }
 
+func (c *StatsV1alpha1Client) NamespaceNetworkSummaries(namespace string) NamespaceNetworkSummaryInterface {
+	return newNamespaceNetworkSummaries(c, namespace)
+}
+
 func (c *StatsV1alpha1Client) NetworkPolicyStats(namespace string) NetworkPolicyStatsInterface {
 	return newNetworkPolicyStats(c, namespace)
 }
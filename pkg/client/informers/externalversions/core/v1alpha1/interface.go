@@ -22,8 +22,16 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Egresses returns a EgressInformer.
+	Egresses() EgressInformer
 	// ExternalEntities returns a ExternalEntityInformer.
 	ExternalEntities() ExternalEntityInformer
+	// ExternalIPPools returns a ExternalIPPoolInformer.
+	ExternalIPPools() ExternalIPPoolInformer
+	// Groups returns a GroupInformer.
+	Groups() GroupInformer
+	// IPPools returns a IPPoolInformer.
+	IPPools() IPPoolInformer
 }
 
 type version struct {
@@ -37,7 +45,27 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Egresses returns a EgressInformer.
+func (v *version) Egresses() EgressInformer {
+	return &egressInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ExternalEntities returns a ExternalEntityInformer.
 func (v *version) ExternalEntities() ExternalEntityInformer {
 	return &externalEntityInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ExternalIPPools returns a ExternalIPPoolInformer.
+func (v *version) ExternalIPPools() ExternalIPPoolInformer {
+	return &externalIPPoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// Groups returns a GroupInformer.
+func (v *version) Groups() GroupInformer {
+	return &groupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// IPPools returns a IPPoolInformer.
+func (v *version) IPPools() IPPoolInformer {
+	return &iPPoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -53,8 +53,16 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=core.antrea.tanzu.vmware.com, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("egresses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().Egresses().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("externalentities"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().ExternalEntities().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("externalippools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().ExternalIPPools().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("groups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().Groups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("ippools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha1().IPPools().Informer()}, nil
 
 		// Group=ops.antrea.tanzu.vmware.com, Version=v1alpha1
 	case opsv1alpha1.SchemeGroupVersion.WithResource("traceflows"):
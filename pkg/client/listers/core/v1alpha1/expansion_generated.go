@@ -16,6 +16,10 @@
 
 package v1alpha1
 
+// EgressListerExpansion allows custom methods to be added to
+// EgressLister.
+type EgressListerExpansion interface{}
+
 // ExternalEntityListerExpansion allows custom methods to be added to
 // ExternalEntityLister.
 type ExternalEntityListerExpansion interface{}
@@ -23,3 +27,19 @@ type ExternalEntityListerExpansion interface{}
 // ExternalEntityNamespaceListerExpansion allows custom methods to be added to
 // ExternalEntityNamespaceLister.
 type ExternalEntityNamespaceListerExpansion interface{}
+
+// ExternalIPPoolListerExpansion allows custom methods to be added to
+// ExternalIPPoolLister.
+type ExternalIPPoolListerExpansion interface{}
+
+// GroupListerExpansion allows custom methods to be added to
+// GroupLister.
+type GroupListerExpansion interface{}
+
+// GroupNamespaceListerExpansion allows custom methods to be added to
+// GroupNamespaceLister.
+type GroupNamespaceListerExpansion interface{}
+
+// IPPoolListerExpansion allows custom methods to be added to
+// IPPoolLister.
+type IPPoolListerExpansion interface{}
@@ -76,7 +76,7 @@ const (
 // pre-GA releases of a major version, along with that major version release itself) in the
 // server. This is harder to do on the client side (need to fallback to a previous version when
 // getting an UNIMPLEMENTED error).
-const AntreaCNIVersion = "1.0.0-beta.1"
+const AntreaCNIVersion = "1.1.0-beta.1"
 
 // To allow for testing with a fake client.
 var withClient = rpcClient
@@ -85,6 +85,14 @@ func (c *testClient) CmdDel(ctx context.Context, requestMsg *cnipb.CniCmdRequest
 	return c.cmdHandle(c.del, ctx, requestMsg)
 }
 
+func (c *testClient) Version(ctx context.Context, requestMsg *cnipb.CniVersionRequest, opts ...grpc.CallOption) (*cnipb.CniVersionResponse, error) {
+	return &cnipb.CniVersionResponse{ApiVersion: AntreaCNIVersion}, nil
+}
+
+func (c *testClient) Health(ctx context.Context, requestMsg *cnipb.CniHealthRequest, opts ...grpc.CallOption) (*cnipb.CniHealthResponse, error) {
+	return &cnipb.CniHealthResponse{Healthy: true}, nil
+}
+
 func enableTestClient(t *testing.T, add, check, del testClientBehave) {
 	withClient = func(f func(client cnipb.CniClient) error) error {
 		return f(&testClient{t, add, check, del})
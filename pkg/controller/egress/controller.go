@@ -0,0 +1,275 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	coreinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/core/v1alpha1"
+	corelisters "github.com/vmware-tanzu/antrea/pkg/client/listers/core/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/util/ip"
+)
+
+const (
+	// Set resyncPeriod to 0 to disable resyncing.
+	resyncPeriod time.Duration = 0
+
+	// How long to wait before retrying the processing of an Egress.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+
+	// Egresses are processed by a single worker, so that an IP is never
+	// allocated to two Egresses.
+	defaultWorkers = 1
+)
+
+// Controller allocates the IPs of the Egresses referencing an ExternalIPPool
+// without specifying an IP. The allocated IP is written to the spec of the
+// Egress, and released when the Egress is deleted. The Node each Egress IP is
+// assigned to is selected by the antrea-agents among the Nodes matching the
+// nodeSelector of the ExternalIPPool.
+type Controller struct {
+	client                     versioned.Interface
+	egressLister               corelisters.EgressLister
+	egressListerSynced         cache.InformerSynced
+	externalIPPoolLister       corelisters.ExternalIPPoolLister
+	externalIPPoolListerSynced cache.InformerSynced
+	queue                      workqueue.RateLimitingInterface
+	// allocatedIPs is a map of the Egress IPs to the names of the Egresses
+	// using them, including the IPs which are not allocated from a pool.
+	// It's only accessed by the worker after the caches are synced.
+	allocatedIPs map[string]string
+}
+
+// NewEgressController creates a new Egress controller.
+func NewEgressController(client versioned.Interface, egressInformer coreinformers.EgressInformer, externalIPPoolInformer coreinformers.ExternalIPPoolInformer) *Controller {
+	c := &Controller{
+		client:                     client,
+		egressLister:               egressInformer.Lister(),
+		egressListerSynced:         egressInformer.Informer().HasSynced,
+		externalIPPoolLister:       externalIPPoolInformer.Lister(),
+		externalIPPoolListerSynced: externalIPPoolInformer.Informer().HasSynced,
+		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "egress"),
+		allocatedIPs:               map[string]string{},
+	}
+	egressInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueueEgress,
+			UpdateFunc: func(oldObj, curObj interface{}) { c.enqueueEgress(curObj) },
+			DeleteFunc: c.deleteEgress,
+		},
+		resyncPeriod,
+	)
+	externalIPPoolInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueuePendingEgresses,
+			UpdateFunc: func(oldObj, curObj interface{}) { c.enqueuePendingEgresses(curObj) },
+		},
+		resyncPeriod,
+	)
+	return c
+}
+
+func (c *Controller) enqueueEgress(obj interface{}) {
+	egress := obj.(*corev1alpha1.Egress)
+	c.queue.Add(egress.Name)
+}
+
+func (c *Controller) deleteEgress(obj interface{}) {
+	egress, ok := obj.(*corev1alpha1.Egress)
+	if !ok {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Received unexpected object: %v", obj)
+			return
+		}
+		egress, ok = deletedState.Obj.(*corev1alpha1.Egress)
+		if !ok {
+			klog.Errorf("DeletedFinalStateUnknown contains non-Egress object: %v", deletedState.Obj)
+			return
+		}
+	}
+	// The IP of the deleted Egress is released when it's synced, and can be
+	// allocated to the Egresses waiting for an IP.
+	c.queue.Add(egress.Name)
+	c.enqueuePendingEgresses(nil)
+}
+
+// enqueuePendingEgresses enqueues the Egresses waiting for an IP to be
+// allocated from a pool.
+func (c *Controller) enqueuePendingEgresses(_ interface{}) {
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Egresses: %v", err)
+		return
+	}
+	for _, egress := range egresses {
+		if egress.Spec.EgressIP == "" && egress.Spec.ExternalIPPool != "" {
+			c.queue.Add(egress.Name)
+		}
+	}
+}
+
+// Run begins watching and syncing of the Egresses.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting Egress controller")
+	defer klog.Info("Shutting down Egress controller")
+
+	klog.Info("Waiting for caches to sync for Egress controller")
+	if !cache.WaitForCacheSync(stopCh, c.egressListerSynced, c.externalIPPoolListerSynced) {
+		klog.Error("Unable to sync caches for Egress controller")
+		return
+	}
+	klog.Info("Caches are synced for Egress controller")
+
+	// Load the IPs of the existing Egresses, so that they are not allocated
+	// again.
+	egresses, err := c.egressLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Egresses: %v", err)
+	}
+	for _, egress := range egresses {
+		if egress.Spec.EgressIP != "" {
+			c.allocatedIPs[egress.Spec.EgressIP] = egress.Name
+		}
+	}
+
+	for i := 0; i < defaultWorkers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(obj)
+
+	// We expect strings (Egress name) to come off the workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd go into a loop of
+		// attempting to process a work item that is invalid.
+		c.queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := c.syncEgress(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again.
+		c.queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		c.queue.AddRateLimited(key)
+		klog.Errorf("Error syncing Egress %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
+
+func (c *Controller) syncEgress(egressName string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing Egress for %s. (%v)", egressName, time.Since(startTime))
+	}()
+
+	egress, err := c.egressLister.Get(egressName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.releaseIPs(egressName, "")
+			return nil
+		}
+		return err
+	}
+	// Release the IP previously used by the Egress if it's changed.
+	c.releaseIPs(egressName, egress.Spec.EgressIP)
+	if egress.Spec.EgressIP != "" {
+		if owner, exists := c.allocatedIPs[egress.Spec.EgressIP]; exists && owner != egressName {
+			klog.Errorf("Egress %s uses the IP %s of Egress %s", egressName, egress.Spec.EgressIP, owner)
+			return nil
+		}
+		c.allocatedIPs[egress.Spec.EgressIP] = egressName
+		return nil
+	}
+	if egress.Spec.ExternalIPPool == "" {
+		return nil
+	}
+	pool, err := c.externalIPPoolLister.Get(egress.Spec.ExternalIPPool)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The Egress is synced again when the pool is created.
+			klog.V(2).Infof("ExternalIPPool %s of Egress %s does not exist", egress.Spec.ExternalIPPool, egressName)
+			return nil
+		}
+		return err
+	}
+	ip, err := c.allocateIP(pool)
+	if err != nil {
+		return err
+	}
+	c.allocatedIPs[ip] = egressName
+	update := egress.DeepCopy()
+	update.Spec.EgressIP = ip
+	if _, err := c.client.CoreV1alpha1().Egresses().Update(context.TODO(), update, metav1.UpdateOptions{}); err != nil {
+		delete(c.allocatedIPs, ip)
+		return fmt.Errorf("error updating the IP of Egress %s: %v", egressName, err)
+	}
+	klog.Infof("Allocated IP %s from ExternalIPPool %s to Egress %s", ip, pool.Name, egressName)
+	return nil
+}
+
+// releaseIPs releases the IPs allocated to the Egress, except keepIP.
+func (c *Controller) releaseIPs(egressName string, keepIP string) {
+	for ip, owner := range c.allocatedIPs {
+		if owner == egressName && ip != keepIP {
+			delete(c.allocatedIPs, ip)
+		}
+	}
+}
+
+// allocateIP returns the first IP of the pool which is not used by any Egress.
+func (c *Controller) allocateIP(pool *corev1alpha1.ExternalIPPool) (string, error) {
+	for _, ipRange := range pool.Spec.IPRanges {
+		first, last, err := ip.ParseIPRange(ipRange)
+		if err != nil {
+			klog.Errorf("Invalid IP range of ExternalIPPool %s: %v", pool.Name, err)
+			continue
+		}
+		for i := first; i <= last && i >= first; i++ {
+			candidate := ip.Uint32ToIP(i).String()
+			if _, exists := c.allocatedIPs[candidate]; !exists {
+				return candidate, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no IP available in ExternalIPPool %s", pool.Name)
+}
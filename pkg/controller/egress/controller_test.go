@@ -0,0 +1,116 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+)
+
+type egressController struct {
+	*Controller
+	client *fake.Clientset
+}
+
+func newController(objects ...runtime.Object) (*egressController, func()) {
+	client := fake.NewSimpleClientset(objects...)
+	informerFactory := crdinformers.NewSharedInformerFactory(client, 0)
+	c := NewEgressController(client, informerFactory.Core().V1alpha1().Egresses(), informerFactory.Core().V1alpha1().ExternalIPPools())
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	return &egressController{Controller: c, client: client}, func() { close(stopCh) }
+}
+
+func newExternalIPPool(name string, ipRanges ...corev1alpha1.IPRange) *corev1alpha1.ExternalIPPool {
+	return &corev1alpha1.ExternalIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1alpha1.ExternalIPPoolSpec{IPRanges: ipRanges},
+	}
+}
+
+func newEgress(name, egressIP, externalIPPool string) *corev1alpha1.Egress {
+	return &corev1alpha1.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1alpha1.EgressSpec{EgressIP: egressIP, ExternalIPPool: externalIPPool},
+	}
+}
+
+func (c *egressController) getEgressIP(t *testing.T, name string) string {
+	egress, err := c.client.CoreV1alpha1().Egresses().Get(context.TODO(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return egress.Spec.EgressIP
+}
+
+// waitForEgressIP waits for the lister to observe the IP of the Egress, as the IPs written by the controller are
+// only visible to the next syncs through the lister.
+func (c *egressController) waitForEgressIP(t *testing.T, name, ip string) {
+	assert.Eventually(t, func() bool {
+		egress, err := c.egressLister.Get(name)
+		return err == nil && egress.Spec.EgressIP == ip
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAllocateEgressIP(t *testing.T) {
+	c, cleanup := newController(
+		newExternalIPPool("pool1", corev1alpha1.IPRange{CIDR: "10.10.10.0/30"}),
+		newEgress("egress-manual", "10.10.10.1", ""),
+		newEgress("egress1", "", "pool1"),
+		newEgress("egress2", "", "pool1"),
+	)
+	defer cleanup()
+	c.allocatedIPs["10.10.10.1"] = "egress-manual"
+
+	// 10.10.10.1 is used by another Egress, and 10.10.10.0 and 10.10.10.3 are the network and broadcast addresses.
+	require.NoError(t, c.syncEgress("egress1"))
+	assert.Equal(t, "10.10.10.2", c.getEgressIP(t, "egress1"))
+	c.waitForEgressIP(t, "egress1", "10.10.10.2")
+	require.NoError(t, c.syncEgress("egress1"))
+
+	// The pool is exhausted.
+	assert.Error(t, c.syncEgress("egress2"))
+	assert.Equal(t, "", c.getEgressIP(t, "egress2"))
+
+	// The IP of a deleted Egress is released.
+	require.NoError(t, c.client.CoreV1alpha1().Egresses().Delete(context.TODO(), "egress1", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		_, err := c.egressLister.Get("egress1")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, c.syncEgress("egress1"))
+	require.NoError(t, c.syncEgress("egress2"))
+	assert.Equal(t, "10.10.10.2", c.getEgressIP(t, "egress2"))
+	assert.Equal(t, map[string]string{"10.10.10.1": "egress-manual", "10.10.10.2": "egress2"}, c.allocatedIPs)
+}
+
+func TestAllocateEgressIPMissingPool(t *testing.T) {
+	c, cleanup := newController(newEgress("egress1", "", "pool1"))
+	defer cleanup()
+
+	// The Egress waits for the pool to be created.
+	require.NoError(t, c.syncEgress("egress1"))
+	assert.Equal(t, "", c.getEgressIP(t, "egress1"))
+	assert.Empty(t, c.allocatedIPs)
+}
@@ -123,6 +123,29 @@ func (n *NetworkPolicyController) deleteANP(old interface{}) {
 // in case of ADD event or modified and store the updated instance, in case
 // of an UPDATE event.
 func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *secv1alpha1.NetworkPolicy) *antreatypes.NetworkPolicy {
+	tierPriority := n.getTierPriority(np.Spec.Tier)
+	internalNetworkPolicy := &antreatypes.NetworkPolicy{
+		SourceRef: &controlplane.NetworkPolicyReference{
+			Type:      controlplane.AntreaNetworkPolicy,
+			Namespace: np.Namespace,
+			Name:      np.Name,
+			UID:       np.UID,
+		},
+		Name:         np.Name,
+		Namespace:    np.Namespace,
+		UID:          np.UID,
+		Generation:   np.Generation,
+		Priority:     &np.Spec.Priority,
+		TierPriority: &tierPriority,
+	}
+	// An Antrea NetworkPolicy in an exempt Namespace is kept without AppliedToGroups and rules, so
+	// that it is not enforced on any Node.
+	if n.isNamespaceExempt(np.Namespace) {
+		klog.Infof("Antrea NetworkPolicy %s/%s is not enforced as its Namespace is exempt from Antrea-native policies", np.Namespace, np.Name)
+		internalNetworkPolicy.AppliedToGroups = []string{}
+		internalNetworkPolicy.Rules = []controlplane.NetworkPolicyRule{}
+		return internalNetworkPolicy
+	}
 	appliedToGroupNames := make([]string, 0, len(np.Spec.AppliedTo))
 	// Create AppliedToGroup for each AppliedTo present in
 	// AntreaNetworkPolicy spec.
@@ -133,43 +156,36 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *secv1alpha1.Net
 	rules := make([]controlplane.NetworkPolicyRule, 0, len(np.Spec.Ingress)+len(np.Spec.Egress))
 	// Compute NetworkPolicyRule for Egress Rule.
 	for idx, ingressRule := range np.Spec.Ingress {
+		// Skip the rules which are not active according to their Schedule.
+		if !n.isRuleActive(&ingressRule) {
+			continue
+		}
 		// Set default action to ALLOW to allow traffic.
-		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports)
+		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports, ingressRule.Protocols)
 		rules = append(rules, controlplane.NetworkPolicyRule{
 			Direction: controlplane.DirectionIn,
 			From:      *n.toAntreaPeerForCRD(ingressRule.From, np, controlplane.DirectionIn, namedPortExists),
 			Services:  services,
-			Action:    ingressRule.Action,
 			Priority:  int32(idx),
 		})
+		setRuleActionForCRD(&rules[len(rules)-1], &ingressRule, np.Spec.EnforcementMode)
 	}
 	// Compute NetworkPolicyRule for Egress Rule.
 	for idx, egressRule := range np.Spec.Egress {
+		if !n.isRuleActive(&egressRule) {
+			continue
+		}
 		// Set default action to ALLOW to allow traffic.
-		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports)
+		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports, egressRule.Protocols)
 		rules = append(rules, controlplane.NetworkPolicyRule{
 			Direction: controlplane.DirectionOut,
-			To:        *n.toAntreaPeerForCRD(egressRule.To, np, controlplane.DirectionOut, namedPortExists),
+			To:        *n.toAntreaEgressPeerForCRD(&egressRule, np, namedPortExists),
 			Services:  services,
-			Action:    egressRule.Action,
 			Priority:  int32(idx),
 		})
+		setRuleActionForCRD(&rules[len(rules)-1], &egressRule, np.Spec.EnforcementMode)
 	}
-	tierPriority := n.getTierPriority(np.Spec.Tier)
-	internalNetworkPolicy := &antreatypes.NetworkPolicy{
-		SourceRef: &controlplane.NetworkPolicyReference{
-			Type:      controlplane.AntreaNetworkPolicy,
-			Namespace: np.Namespace,
-			Name:      np.Name,
-			UID:       np.UID,
-		},
-		Name:            np.Name,
-		Namespace:       np.Namespace,
-		UID:             np.UID,
-		AppliedToGroups: appliedToGroupNames,
-		Rules:           rules,
-		Priority:        &np.Spec.Priority,
-		TierPriority:    &tierPriority,
-	}
+	internalNetworkPolicy.AppliedToGroups = appliedToGroupNames
+	internalNetworkPolicy.Rules = rules
 	return internalNetworkPolicy
 }
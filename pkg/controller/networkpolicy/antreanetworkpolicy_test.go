@@ -420,3 +420,46 @@ func getEETestANP(selectorAppliedTo, selectorIn, selectorOut metav1.LabelSelecto
 		},
 	}
 }
+
+func TestProcessAntreaNetworkPolicyExemptNamespace(t *testing.T) {
+	p10 := float64(10)
+	allowAction := secv1alpha1.RuleActionAllow
+	inputPolicy := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "npA", UID: "uidA"},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{
+				{PodSelector: &selectorA},
+			},
+			Priority: p10,
+			Ingress: []secv1alpha1.Rule{
+				{
+					From: []secv1alpha1.NetworkPolicyPeer{
+						{PodSelector: &selectorB},
+					},
+					Action: &allowAction,
+				},
+			},
+		},
+	}
+	expectedPolicy := &antreatypes.NetworkPolicy{
+		UID:       "uidA",
+		Name:      "npA",
+		Namespace: "kube-system",
+		SourceRef: &controlplane.NetworkPolicyReference{
+			Type:      controlplane.AntreaNetworkPolicy,
+			Namespace: "kube-system",
+			Name:      "npA",
+			UID:       "uidA",
+		},
+		Priority:        &p10,
+		TierPriority:    &defaultTierPriority,
+		Rules:           []controlplane.NetworkPolicyRule{},
+		AppliedToGroups: []string{},
+	}
+	_, c := newController()
+	c.exemptNamespaces.Insert("kube-system")
+
+	assert.Equal(t, expectedPolicy, c.processAntreaNetworkPolicy(inputPolicy))
+	assert.Empty(t, c.addressGroupStore.List())
+	assert.Empty(t, c.appliedToGroupStore.List())
+}
@@ -127,32 +127,47 @@ func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *secv1alpha1.C
 	// Create AppliedToGroup for each AppliedTo present in
 	// ClusterNetworkPolicy spec.
 	for _, at := range cnp.Spec.AppliedTo {
+		if at.NodeSelector != nil {
+			appliedToGroupNames = append(appliedToGroupNames, n.createNodeAppliedToGroup(at.NodeSelector))
+			continue
+		}
+		if at.ServiceAccount != nil {
+			appliedToGroupNames = append(appliedToGroupNames, n.createAppliedToGroupForSelector(toServiceAccountGroupSelector(at.ServiceAccount)))
+			continue
+		}
 		appliedToGroupNames = append(appliedToGroupNames, n.createAppliedToGroup("", at.PodSelector, at.NamespaceSelector, at.ExternalEntitySelector))
 	}
 	rules := make([]controlplane.NetworkPolicyRule, 0, len(cnp.Spec.Ingress)+len(cnp.Spec.Egress))
 	// Compute NetworkPolicyRule for Egress Rule.
 	for idx, ingressRule := range cnp.Spec.Ingress {
+		// Skip the rules which are not active according to their Schedule.
+		if !n.isRuleActive(&ingressRule) {
+			continue
+		}
 		// Set default action to ALLOW to allow traffic.
-		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports)
+		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports, ingressRule.Protocols)
 		rules = append(rules, controlplane.NetworkPolicyRule{
 			Direction: controlplane.DirectionIn,
 			From:      *n.toAntreaPeerForCRD(ingressRule.From, cnp, controlplane.DirectionIn, namedPortExists),
 			Services:  services,
-			Action:    ingressRule.Action,
 			Priority:  int32(idx),
 		})
+		setRuleActionForCRD(&rules[len(rules)-1], &ingressRule, cnp.Spec.EnforcementMode)
 	}
 	// Compute NetworkPolicyRule for Egress Rule.
 	for idx, egressRule := range cnp.Spec.Egress {
+		if !n.isRuleActive(&egressRule) {
+			continue
+		}
 		// Set default action to ALLOW to allow traffic.
-		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports)
+		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports, egressRule.Protocols)
 		rules = append(rules, controlplane.NetworkPolicyRule{
 			Direction: controlplane.DirectionOut,
-			To:        *n.toAntreaPeerForCRD(egressRule.To, cnp, controlplane.DirectionOut, namedPortExists),
+			To:        *n.toAntreaEgressPeerForCRD(&egressRule, cnp, namedPortExists),
 			Services:  services,
-			Action:    egressRule.Action,
 			Priority:  int32(idx),
 		})
+		setRuleActionForCRD(&rules[len(rules)-1], &egressRule, cnp.Spec.EnforcementMode)
 	}
 	tierPriority := n.getTierPriority(cnp.Spec.Tier)
 	internalNetworkPolicy := &antreatypes.NetworkPolicy{
@@ -164,6 +179,7 @@ func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *secv1alpha1.C
 			UID:  cnp.UID,
 		},
 		UID:             cnp.UID,
+		Generation:      cnp.Generation,
 		AppliedToGroups: appliedToGroupNames,
 		Rules:           rules,
 		Priority:        &cnp.Spec.Priority,
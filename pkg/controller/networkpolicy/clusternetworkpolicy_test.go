@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -888,3 +890,38 @@ func getCNP() *secv1alpha1.ClusterNetworkPolicy {
 	return npObj
 
 }
+
+func TestSyncAppliedToGroupExemptNamespace(t *testing.T) {
+	selectorAll := metav1.LabelSelector{}
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{
+				{PodSelector: &selectorA, NamespaceSelector: &selectorAll},
+			},
+			Priority: float64(10),
+		},
+	}
+	exemptPod := getPod("p1", "kube-system", "node1", "1.1.1.1", false)
+	exemptPod.Labels = selectorA.MatchLabels
+	pod := getPod("p2", "ns1", "node2", "2.2.2.2", false)
+	pod.Labels = selectorA.MatchLabels
+	_, npc := newController()
+	npc.exemptNamespaces.Insert("kube-system")
+	npc.namespaceStore.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+	npc.namespaceStore.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}})
+	npc.podStore.Add(exemptPod)
+	npc.podStore.Add(pod)
+
+	internalNP := npc.processClusterNetworkPolicy(cnp)
+	require.Len(t, internalNP.AppliedToGroups, 1)
+	atgName := internalNP.AppliedToGroups[0]
+	require.NoError(t, npc.syncAppliedToGroup(atgName))
+	atgObj, _, _ := npc.appliedToGroupStore.Get(atgName)
+	atg := atgObj.(*antreatypes.AppliedToGroup)
+	// The Pod in the exempt Namespace is selected by the ClusterNetworkPolicy but is not a member of its
+	// AppliedToGroup.
+	assert.Len(t, atg.PodsByNode, 1)
+	assert.Len(t, atg.PodsByNode["node2"], 1)
+	assert.Equal(t, []string{"node2"}, atg.SpanMeta.NodeNames.List())
+}
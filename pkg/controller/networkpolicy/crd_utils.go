@@ -15,8 +15,10 @@
 package networkpolicy
 
 import (
+	"net"
 	"strings"
 
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -36,9 +38,10 @@ var (
 )
 
 // toAntreaServicesForCRD converts a slice of secv1alpha1.NetworkPolicyPort
-// objects to a slice of Antrea Service objects. A bool is returned along with
-// the Service objects to indicate whether any named port exists.
-func toAntreaServicesForCRD(npPorts []secv1alpha1.NetworkPolicyPort) ([]controlplane.Service, bool) {
+// objects and a slice of secv1alpha1.NetworkPolicyProtocol objects to a slice
+// of Antrea Service objects. A bool is returned along with the Service objects
+// to indicate whether any named port exists.
+func toAntreaServicesForCRD(npPorts []secv1alpha1.NetworkPolicyPort, npProtocols []secv1alpha1.NetworkPolicyProtocol) ([]controlplane.Service, bool) {
 	var antreaServices []controlplane.Service
 	var namedPortExists bool
 	for _, npPort := range npPorts {
@@ -51,22 +54,64 @@ func toAntreaServicesForCRD(npPorts []secv1alpha1.NetworkPolicyPort) ([]controlp
 		}
 		antreaServices = append(antreaServices, antreaService)
 	}
+	for _, npProtocol := range npProtocols {
+		if npProtocol.ICMP != nil {
+			protocol := controlplane.ProtocolICMP
+			antreaServices = append(antreaServices, controlplane.Service{
+				Protocol: &protocol,
+				ICMPType: npProtocol.ICMP.ICMPType,
+				ICMPCode: npProtocol.ICMP.ICMPCode,
+			})
+		}
+		if npProtocol.IGMP != nil {
+			protocol := controlplane.ProtocolIGMP
+			antreaServices = append(antreaServices, controlplane.Service{Protocol: &protocol})
+		}
+		if npProtocol.IP != nil {
+			protocol := controlplane.ProtocolIP
+			ipProtocol := npProtocol.IP.Protocol
+			antreaServices = append(antreaServices, controlplane.Service{Protocol: &protocol, IPProtocol: &ipProtocol})
+		}
+	}
 	return antreaServices, namedPortExists
 }
 
-// toAntreaIPBlockForCRD converts a secv1alpha1.IPBlock to an Antrea IPBlock.
-func toAntreaIPBlockForCRD(ipBlock *secv1alpha1.IPBlock) (*controlplane.IPBlock, error) {
-	// Convert the allowed IPBlock to networkpolicy.IPNet.
-	ipNet, err := cidrStrToIPNet(ipBlock.CIDR)
-	if err != nil {
-		return nil, err
+// setRuleActionForCRD sets the Action and EnableLogging of the internal rule
+// created for the Antrea-native policy rule. Audit rules are always logged,
+// as the Agents only log the Audit rules with EnableLogging set. The rules of
+// the policies in Monitor mode are realized as Audit rules, so that they are
+// counted without being enforced, and so that the traffic matched by a rule
+// is not matched by the rules of lower priority of the policy. Only the
+// traffic which would be dropped is logged, unless logging is enabled. The
+// rate limit of the rule is only set in Enforce mode, as nothing is dropped in
+// Monitor mode.
+func setRuleActionForCRD(rule *controlplane.NetworkPolicyRule, crdRule *secv1alpha1.Rule, mode secv1alpha1.EnforcementMode) {
+	rule.Action = crdRule.Action
+	rule.EnableLogging = crdRule.EnableLogging
+	if mode == secv1alpha1.EnforcementModeMonitor {
+		auditAction := secv1alpha1.RuleActionAudit
+		if crdRule.Action != nil && *crdRule.Action == secv1alpha1.RuleActionDrop {
+			rule.EnableLogging = true
+		}
+		rule.Action = &auditAction
+		return
 	}
-	antreaIPBlock := &controlplane.IPBlock{
-		CIDR: *ipNet,
-		// secv1alpha.IPBlock does not have the Except slices.
-		Except: []controlplane.IPNet{},
+	if crdRule.Action != nil && *crdRule.Action == secv1alpha1.RuleActionAudit {
+		rule.EnableLogging = true
+	}
+	if crdRule.RateLimit != nil {
+		rule.RateLimit = &controlplane.RuleRateLimit{
+			MaxNewConnectionsPerSecond: crdRule.RateLimit.MaxNewConnectionsPerSecond,
+			Burst:                      crdRule.RateLimit.Burst,
+		}
 	}
-	return antreaIPBlock, nil
+}
+
+// toAntreaIPBlockForCRD converts a secv1alpha1.IPBlock to an Antrea IPBlock.
+func toAntreaIPBlockForCRD(ipBlock *secv1alpha1.IPBlock) (*controlplane.IPBlock, error) {
+	// The except CIDRs have the same semantics as in K8s NetworkPolicies,
+	// only their realization by the Agents differs.
+	return toAntreaIPBlock(&networkingv1.IPBlock{CIDR: ipBlock.CIDR, Except: ipBlock.Except})
 }
 
 func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []secv1alpha1.NetworkPolicyPeer,
@@ -90,10 +135,16 @@ func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []secv1alpha1.Network
 		return &podsPeer
 	}
 	var ipBlocks []controlplane.IPBlock
-	for _, peer := range peers {
-		// A secv1alpha1.NetworkPolicyPeer will either have an IPBlock or a
-		// podSelector and/or namespaceSelector set.
-		if peer.IPBlock != nil {
+	var fqdns []string
+	// The peers referencing a Group are replaced by the peers selecting
+	// the members of the Group.
+	for _, peer := range n.expandGroupPeers(np.GetNamespace(), peers) {
+		// A secv1alpha1.NetworkPolicyPeer will either have an IPBlock, a
+		// FQDN or a podSelector and/or namespaceSelector set.
+		if peer.FQDN != "" {
+			// The addresses of FQDNs are resolved by the agents.
+			fqdns = append(fqdns, peer.FQDN)
+		} else if peer.IPBlock != nil {
 			ipBlock, err := toAntreaIPBlockForCRD(peer.IPBlock)
 			if err != nil {
 				klog.Errorf("Failure processing Antrea NetworkPolicy %s/%s IPBlock %v: %v", np.GetNamespace(), np.GetName(), peer.IPBlock, err)
@@ -105,7 +156,61 @@ func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []secv1alpha1.Network
 			addressGroups = append(addressGroups, normalizedUID)
 		}
 	}
-	return &controlplane.NetworkPolicyPeer{AddressGroups: addressGroups, IPBlocks: ipBlocks}
+	return &controlplane.NetworkPolicyPeer{AddressGroups: addressGroups, IPBlocks: ipBlocks, FQDNs: fqdns}
+}
+
+// toAntreaEgressPeerForCRD converts the destinations of an egress rule of an
+// Antrea Policy to an Antrea NetworkPolicyPeer. The ClusterIPs of the Services
+// referenced by ToServices are matched in addition to the peers of To, and a
+// rule which only sets ToServices doesn't match any other destination.
+func (n *NetworkPolicyController) toAntreaEgressPeerForCRD(rule *secv1alpha1.Rule, np metav1.Object, namedPortExists bool) *controlplane.NetworkPolicyPeer {
+	if len(rule.ToServices) == 0 {
+		return n.toAntreaPeerForCRD(rule.To, np, controlplane.DirectionOut, namedPortExists)
+	}
+	peer := &controlplane.NetworkPolicyPeer{}
+	if len(rule.To) > 0 {
+		peer = n.toAntreaPeerForCRD(rule.To, np, controlplane.DirectionOut, namedPortExists)
+	}
+	peer.IPBlocks = append(peer.IPBlocks, n.serviceIPBlocks(rule.ToServices, np.GetNamespace())...)
+	return peer
+}
+
+// serviceIPBlocks returns the IPBlocks matching the ClusterIPs of the
+// referenced Services. Services which don't exist yet and headless Services
+// don't contribute any IPBlock.
+func (n *NetworkPolicyController) serviceIPBlocks(refs []secv1alpha1.ServiceReference, namespace string) []controlplane.IPBlock {
+	var ipBlocks []controlplane.IPBlock
+	for _, ref := range refs {
+		svcNamespace := serviceReferenceNamespace(ref, namespace)
+		svc, err := n.serviceLister.Services(svcNamespace).Get(ref.Name)
+		if err != nil {
+			klog.V(2).Infof("Service %s/%s referenced by Antrea Policy does not exist", svcNamespace, ref.Name)
+			continue
+		}
+		ip := net.ParseIP(svc.Spec.ClusterIP)
+		if ip == nil {
+			continue
+		}
+		prefixLength := int32(32)
+		if ip.To4() == nil {
+			prefixLength = 128
+		}
+		ipBlocks = append(ipBlocks, controlplane.IPBlock{
+			CIDR:   controlplane.IPNet{IP: controlplane.IPAddress(ip), PrefixLength: prefixLength},
+			Except: []controlplane.IPNet{},
+		})
+	}
+	return ipBlocks
+}
+
+// serviceReferenceNamespace returns the Namespace of a Service referenced by an
+// Antrea Policy of the given Namespace. The rules of Antrea NetworkPolicies
+// reference Services of the policy's own Namespace.
+func serviceReferenceNamespace(ref secv1alpha1.ServiceReference, namespace string) string {
+	if ref.Namespace == "" {
+		return namespace
+	}
+	return ref.Namespace
 }
 
 // createAddressGroupForCRD creates an AddressGroup object corresponding to a
@@ -114,6 +219,9 @@ func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []secv1alpha1.Network
 // PodAddresses as the affected Pods are calculated during sync process.
 func (n *NetworkPolicyController) createAddressGroupForCRD(peer secv1alpha1.NetworkPolicyPeer, np metav1.Object) string {
 	groupSelector := toGroupSelector(np.GetNamespace(), peer.PodSelector, peer.NamespaceSelector, peer.ExternalEntitySelector)
+	if peer.ServiceAccount != nil {
+		groupSelector = toServiceAccountGroupSelector(peer.ServiceAccount)
+	}
 	normalizedUID := getNormalizedUID(groupSelector.NormalizedName)
 	// Get or create an AddressGroup for the generated UID.
 	_, found, _ := n.addressGroupStore.Get(normalizedUID)
@@ -21,15 +21,22 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
 )
 
 func TestToAntreaServicesForCRD(t *testing.T) {
+	icmpTypeEchoRequest, icmpCode0, ipProtocolVRRP := int32(8), int32(0), int32(112)
+	protocolICMP, protocolIGMP, protocolIP := controlplane.ProtocolICMP, controlplane.ProtocolIGMP, controlplane.ProtocolIP
 	tables := []struct {
 		ports              []secv1alpha1.NetworkPolicyPort
+		protocols          []secv1alpha1.NetworkPolicyProtocol
 		expServices        []controlplane.Service
 		expNamedPortExists bool
 	}{
@@ -63,9 +70,41 @@ func TestToAntreaServicesForCRD(t *testing.T) {
 			},
 			expNamedPortExists: true,
 		},
+		{
+			ports: []secv1alpha1.NetworkPolicyPort{
+				{
+					Protocol: &k8sProtocolTCP,
+					Port:     &int80,
+				},
+			},
+			protocols: []secv1alpha1.NetworkPolicyProtocol{
+				{ICMP: &secv1alpha1.ICMPProtocol{ICMPType: &icmpTypeEchoRequest, ICMPCode: &icmpCode0}},
+				{IGMP: &secv1alpha1.IGMPProtocol{}},
+				{IP: &secv1alpha1.IPProtocol{Protocol: 112}},
+			},
+			expServices: []controlplane.Service{
+				{
+					Protocol: toAntreaProtocol(&k8sProtocolTCP),
+					Port:     &int80,
+				},
+				{
+					Protocol: &protocolICMP,
+					ICMPType: &icmpTypeEchoRequest,
+					ICMPCode: &icmpCode0,
+				},
+				{
+					Protocol: &protocolIGMP,
+				},
+				{
+					Protocol:   &protocolIP,
+					IPProtocol: &ipProtocolVRRP,
+				},
+			},
+			expNamedPortExists: false,
+		},
 	}
 	for _, table := range tables {
-		services, namedPortExist := toAntreaServicesForCRD(table.ports)
+		services, namedPortExist := toAntreaServicesForCRD(table.ports, table.protocols)
 		assert.Equal(t, table.expServices, services)
 		assert.Equal(t, table.expNamedPortExists, namedPortExist)
 	}
@@ -90,6 +129,17 @@ func TestToAntreaIPBlockForCRD(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			&secv1alpha1.IPBlock{
+				CIDR:   "10.0.0.0/24",
+				Except: []string{"10.0.0.0/28"},
+			},
+			controlplane.IPBlock{
+				CIDR:   expIPNet,
+				Except: []controlplane.IPNet{{IP: ipStrToIPAddress("10.0.0.0"), PrefixLength: 28}},
+			},
+			nil,
+		},
 		{
 			&secv1alpha1.IPBlock{
 				CIDR: "10.0.0.0",
@@ -115,6 +165,9 @@ func TestToAntreaIPBlockForCRD(t *testing.T) {
 		if table.expValue.CIDR.PrefixLength != ipNet.PrefixLength {
 			t.Errorf("Unexpected PrefixLength in Antrea IPBlock conversion. Expected %v, got %v", table.expValue.CIDR.PrefixLength, ipNet.PrefixLength)
 		}
+		if len(table.expValue.Except) != len(antreaIPBlock.Except) {
+			t.Errorf("Unexpected Except in Antrea IPBlock conversion. Expected %v, got %v", table.expValue.Except, antreaIPBlock.Except)
+		}
 	}
 }
 
@@ -210,6 +263,24 @@ func TestToAntreaPeerForCRD(t *testing.T) {
 			},
 			direction: controlplane.DirectionOut,
 		},
+		{
+			name: "fqdn-peer-egress",
+			inPeers: []secv1alpha1.NetworkPolicyPeer{
+				{
+					FQDN: "*.example.com",
+				},
+				{
+					PodSelector: &selectorC,
+				},
+			},
+			outPeer: controlplane.NetworkPolicyPeer{
+				AddressGroups: []string{
+					getNormalizedUID(toGroupSelector("", &selectorC, nil, nil).NormalizedName),
+				},
+				FQDNs: []string{"*.example.com"},
+			},
+			direction: controlplane.DirectionOut,
+		},
 		{
 			name:      "empty-peer-ingress",
 			inPeers:   []secv1alpha1.NetworkPolicyPeer{},
@@ -237,6 +308,7 @@ func TestToAntreaPeerForCRD(t *testing.T) {
 			if !reflect.DeepEqual(tt.outPeer.AddressGroups, (*actualPeer).AddressGroups) {
 				t.Errorf("Unexpected AddressGroups in Antrea Peer conversion. Expected %v, got %v", tt.outPeer.AddressGroups, (*actualPeer).AddressGroups)
 			}
+			assert.Equal(t, tt.outPeer.FQDNs, actualPeer.FQDNs)
 			if len(tt.outPeer.IPBlocks) != len((*actualPeer).IPBlocks) {
 				t.Errorf("Unexpected number of IPBlocks in Antrea Peer conversion. Expected %v, got %v", len(tt.outPeer.IPBlocks), len((*actualPeer).IPBlocks))
 			}
@@ -248,3 +320,412 @@ func TestToAntreaPeerForCRD(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFQDNPeers(t *testing.T) {
+	tests := []struct {
+		name       string
+		ingress    []secv1alpha1.Rule
+		egress     []secv1alpha1.Rule
+		namespaced bool
+		expAllowed bool
+	}{
+		{
+			name:       "fqdn-in-acnp",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{FQDN: "www.example.com"}}}},
+			expAllowed: true,
+		},
+		{
+			name:       "wildcard-fqdn-in-acnp",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{FQDN: "*.example.com"}}}},
+			expAllowed: true,
+		},
+		{
+			name:       "fqdn-in-anp",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{FQDN: "www.example.com"}}}},
+			namespaced: true,
+			expAllowed: false,
+		},
+		{
+			name:       "fqdn-in-ingress-rule",
+			ingress:    []secv1alpha1.Rule{{From: []secv1alpha1.NetworkPolicyPeer{{FQDN: "www.example.com"}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "fqdn-with-ipblock",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{FQDN: "www.example.com", IPBlock: &secv1alpha1.IPBlock{CIDR: "10.0.0.0/8"}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "invalid-fqdn",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{FQDN: "www.*.com"}}}},
+			expAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := validateFQDNPeers(tt.ingress, tt.egress, tt.namespaced)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
+
+func TestToServicesPeers(t *testing.T) {
+	_, npc := newController()
+	npc.serviceLister = npc.informerFactory.Core().V1().Services().Lister()
+	npc.groupInformer = npc.crdInformerFactory.Core().V1alpha1().Groups()
+	npc.groupInformer.Informer().AddIndexers(cache.Indexers{ServiceIndex: serviceIndexFunc})
+	npc.cnpInformer = npc.crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
+	npc.cnpInformer.Informer().AddIndexers(cache.Indexers{ServiceIndex: policyServiceIndexFunc})
+	npc.anpInformer = npc.crdInformerFactory.Security().V1alpha1().NetworkPolicies()
+	npc.anpInformer.Informer().AddIndexers(cache.Indexers{ServiceIndex: policyServiceIndexFunc})
+	serviceStore := npc.informerFactory.Core().V1().Services().Informer().GetStore()
+
+	allowAction := secv1alpha1.RuleActionAllow
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			Priority:  1,
+			Egress: []secv1alpha1.Rule{{
+				ToServices: []secv1alpha1.ServiceReference{{Name: "db", Namespace: "backend"}},
+				Action:     &allowAction,
+			}},
+		},
+	}
+	anp := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backend", Name: "npB", UID: "uidB"},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			Priority:  1,
+			Egress: []secv1alpha1.Rule{{
+				To:         []secv1alpha1.NetworkPolicyPeer{{IPBlock: &secv1alpha1.IPBlock{CIDR: "10.0.0.0/24"}}},
+				ToServices: []secv1alpha1.ServiceReference{{Name: "db"}},
+				Action:     &allowAction,
+			}},
+		},
+	}
+	npc.cnpInformer.Informer().GetStore().Add(cnp)
+	npc.addCNP(cnp)
+	npc.anpInformer.Informer().GetStore().Add(anp)
+	npc.addANP(anp)
+
+	getToPeer := func(key string) controlplane.NetworkPolicyPeer {
+		obj, _, _ := npc.internalNetworkPolicyStore.Get(key)
+		return obj.(*antreatypes.NetworkPolicy).Rules[0].To
+	}
+	// A rule referencing a missing Service doesn't match any destination.
+	assert.Equal(t, controlplane.NetworkPolicyPeer{}, getToPeer("cnpA"))
+	require.Len(t, getToPeer("backend/npB").IPBlocks, 1)
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backend", Name: "db"},
+		Spec:       v1.ServiceSpec{ClusterIP: "10.96.0.10"},
+	}
+	serviceStore.Add(svc)
+	npc.addService(svc)
+	svcIPBlock := controlplane.IPBlock{
+		CIDR:   controlplane.IPNet{IP: ipStrToIPAddress("10.96.0.10"), PrefixLength: 32},
+		Except: []controlplane.IPNet{},
+	}
+	assert.Equal(t, []controlplane.IPBlock{svcIPBlock}, getToPeer("cnpA").IPBlocks)
+	ipBlocks := getToPeer("backend/npB").IPBlocks
+	require.Len(t, ipBlocks, 2)
+	assert.Equal(t, svcIPBlock, ipBlocks[1])
+
+	// Updates of the ClusterIP are propagated to the policies.
+	updatedSvc := svc.DeepCopy()
+	updatedSvc.Spec.ClusterIP = "fd00::10"
+	serviceStore.Update(updatedSvc)
+	npc.updateService(svc, updatedSvc)
+	ipBlocks = getToPeer("cnpA").IPBlocks
+	require.Len(t, ipBlocks, 1)
+	assert.Equal(t, int32(128), ipBlocks[0].CIDR.PrefixLength)
+
+	serviceStore.Delete(updatedSvc)
+	npc.deleteService(updatedSvc)
+	assert.Empty(t, getToPeer("cnpA").IPBlocks)
+	assert.Len(t, getToPeer("backend/npB").IPBlocks, 1)
+}
+
+func TestValidateServicePeers(t *testing.T) {
+	tests := []struct {
+		name       string
+		ingress    []secv1alpha1.Rule
+		egress     []secv1alpha1.Rule
+		namespaced bool
+		expAllowed bool
+	}{
+		{
+			name:       "to-services-in-acnp",
+			egress:     []secv1alpha1.Rule{{ToServices: []secv1alpha1.ServiceReference{{Name: "db", Namespace: "backend"}}}},
+			expAllowed: true,
+		},
+		{
+			name:       "to-services-in-anp",
+			egress:     []secv1alpha1.Rule{{ToServices: []secv1alpha1.ServiceReference{{Name: "db"}}}},
+			namespaced: true,
+			expAllowed: true,
+		},
+		{
+			name:       "to-services-without-namespace-in-acnp",
+			egress:     []secv1alpha1.Rule{{ToServices: []secv1alpha1.ServiceReference{{Name: "db"}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "to-services-with-namespace-in-anp",
+			egress:     []secv1alpha1.Rule{{ToServices: []secv1alpha1.ServiceReference{{Name: "db", Namespace: "backend"}}}},
+			namespaced: true,
+			expAllowed: false,
+		},
+		{
+			name:       "to-services-without-name",
+			egress:     []secv1alpha1.Rule{{ToServices: []secv1alpha1.ServiceReference{{Namespace: "backend"}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "to-services-in-ingress-rule",
+			ingress:    []secv1alpha1.Rule{{ToServices: []secv1alpha1.ServiceReference{{Name: "db", Namespace: "backend"}}}},
+			expAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := validateServicePeers(tt.ingress, tt.egress, tt.namespaced)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
+
+func TestValidateRuleProtocols(t *testing.T) {
+	icmpType8, icmpCode0, invalidValue := int32(8), int32(0), int32(256)
+	tests := []struct {
+		name       string
+		ingress    []secv1alpha1.Rule
+		egress     []secv1alpha1.Rule
+		expAllowed bool
+	}{
+		{
+			name:       "icmp-type-and-code",
+			ingress:    []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{ICMP: &secv1alpha1.ICMPProtocol{ICMPType: &icmpType8, ICMPCode: &icmpCode0}}}}},
+			expAllowed: true,
+		},
+		{
+			name:       "all-icmp-and-igmp",
+			egress:     []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{ICMP: &secv1alpha1.ICMPProtocol{}}, {IGMP: &secv1alpha1.IGMPProtocol{}}}}},
+			expAllowed: true,
+		},
+		{
+			name:       "empty-protocol",
+			ingress:    []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "icmp-and-igmp-in-same-protocol",
+			egress:     []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{ICMP: &secv1alpha1.ICMPProtocol{}, IGMP: &secv1alpha1.IGMPProtocol{}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "icmp-code-without-type",
+			ingress:    []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{ICMP: &secv1alpha1.ICMPProtocol{ICMPCode: &icmpCode0}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "invalid-icmp-type",
+			ingress:    []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{ICMP: &secv1alpha1.ICMPProtocol{ICMPType: &invalidValue}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "ip-protocol-vrrp",
+			ingress:    []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{IP: &secv1alpha1.IPProtocol{Protocol: 112}}}}},
+			expAllowed: true,
+		},
+		{
+			name:       "ip-protocol-and-icmp-in-same-protocol",
+			egress:     []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{ICMP: &secv1alpha1.ICMPProtocol{}, IP: &secv1alpha1.IPProtocol{Protocol: 47}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "invalid-ip-protocol",
+			egress:     []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{IP: &secv1alpha1.IPProtocol{Protocol: invalidValue}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "ip-protocol-tcp",
+			ingress:    []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{IP: &secv1alpha1.IPProtocol{Protocol: 6}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "ip-protocol-icmp",
+			ingress:    []secv1alpha1.Rule{{Protocols: []secv1alpha1.NetworkPolicyProtocol{{IP: &secv1alpha1.IPProtocol{Protocol: 1}}}}},
+			expAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := validateRuleProtocols(tt.ingress, tt.egress)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
+
+func TestValidateIPBlockExcepts(t *testing.T) {
+	allowAction := secv1alpha1.RuleActionAllow
+	dropAction := secv1alpha1.RuleActionDrop
+	ipBlockPeer := func(cidr string, except ...string) []secv1alpha1.NetworkPolicyPeer {
+		return []secv1alpha1.NetworkPolicyPeer{{IPBlock: &secv1alpha1.IPBlock{CIDR: cidr, Except: except}}}
+	}
+	tests := []struct {
+		name       string
+		ingress    []secv1alpha1.Rule
+		egress     []secv1alpha1.Rule
+		expAllowed bool
+	}{
+		{
+			name:       "allow-rule-with-excepts",
+			ingress:    []secv1alpha1.Rule{{Action: &allowAction, From: ipBlockPeer("10.0.0.0/8", "10.1.0.0/16", "10.2.3.4/32")}},
+			expAllowed: true,
+		},
+		{
+			name:       "drop-rule-without-excepts",
+			egress:     []secv1alpha1.Rule{{Action: &dropAction, To: ipBlockPeer("10.0.0.0/8")}},
+			expAllowed: true,
+		},
+		{
+			name:       "drop-rule-with-excepts",
+			egress:     []secv1alpha1.Rule{{Action: &dropAction, To: ipBlockPeer("10.0.0.0/8", "10.1.0.0/16")}},
+			expAllowed: false,
+		},
+		{
+			name:       "except-outside-cidr",
+			egress:     []secv1alpha1.Rule{{Action: &allowAction, To: ipBlockPeer("10.0.0.0/16", "10.1.0.0/24")}},
+			expAllowed: false,
+		},
+		{
+			name:       "except-larger-than-cidr",
+			ingress:    []secv1alpha1.Rule{{Action: &allowAction, From: ipBlockPeer("10.0.0.0/16", "10.0.0.0/8")}},
+			expAllowed: false,
+		},
+		{
+			name:       "except-of-other-family",
+			ingress:    []secv1alpha1.Rule{{Action: &allowAction, From: ipBlockPeer("0.0.0.0/0", "fd00::/64")}},
+			expAllowed: false,
+		},
+		{
+			name:       "invalid-except",
+			ingress:    []secv1alpha1.Rule{{Action: &allowAction, From: ipBlockPeer("10.0.0.0/8", "10.1.0.0")}},
+			expAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := validateIPBlockExcepts(tt.ingress, tt.egress)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
+
+func TestValidateRuleRateLimits(t *testing.T) {
+	allowAction := secv1alpha1.RuleActionAllow
+	dropAction := secv1alpha1.RuleActionDrop
+	tests := []struct {
+		name       string
+		ingress    []secv1alpha1.Rule
+		egress     []secv1alpha1.Rule
+		expAllowed bool
+	}{
+		{
+			name:       "allow-rule-with-rate-limit",
+			ingress:    []secv1alpha1.Rule{{Action: &allowAction, RateLimit: &secv1alpha1.RuleRateLimit{MaxNewConnectionsPerSecond: 100, Burst: 20}}},
+			expAllowed: true,
+		},
+		{
+			name:       "drop-rule-with-rate-limit",
+			egress:     []secv1alpha1.Rule{{Action: &dropAction, RateLimit: &secv1alpha1.RuleRateLimit{MaxNewConnectionsPerSecond: 100}}},
+			expAllowed: false,
+		},
+		{
+			name:       "zero-rate",
+			egress:     []secv1alpha1.Rule{{Action: &allowAction, RateLimit: &secv1alpha1.RuleRateLimit{}}},
+			expAllowed: false,
+		},
+		{
+			name:       "negative-burst",
+			ingress:    []secv1alpha1.Rule{{Action: &allowAction, RateLimit: &secv1alpha1.RuleRateLimit{MaxNewConnectionsPerSecond: 100, Burst: -1}}},
+			expAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := validateRuleRateLimits(tt.ingress, tt.egress)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
+
+func TestSetRuleActionForCRD(t *testing.T) {
+	allowAction := secv1alpha1.RuleActionAllow
+	dropAction := secv1alpha1.RuleActionDrop
+	auditAction := secv1alpha1.RuleActionAudit
+	tests := []struct {
+		name             string
+		rule             secv1alpha1.Rule
+		mode             secv1alpha1.EnforcementMode
+		expAction        *secv1alpha1.RuleAction
+		expEnableLogging bool
+		expRateLimit     *controlplane.RuleRateLimit
+	}{
+		{
+			name:      "enforce-drop",
+			rule:      secv1alpha1.Rule{Action: &dropAction},
+			expAction: &dropAction,
+		},
+		{
+			name:             "enforce-audit",
+			rule:             secv1alpha1.Rule{Action: &auditAction},
+			mode:             secv1alpha1.EnforcementModeEnforce,
+			expAction:        &auditAction,
+			expEnableLogging: true,
+		},
+		{
+			name:             "monitor-drop",
+			rule:             secv1alpha1.Rule{Action: &dropAction},
+			mode:             secv1alpha1.EnforcementModeMonitor,
+			expAction:        &auditAction,
+			expEnableLogging: true,
+		},
+		{
+			name:      "monitor-allow",
+			rule:      secv1alpha1.Rule{Action: &allowAction},
+			mode:      secv1alpha1.EnforcementModeMonitor,
+			expAction: &auditAction,
+		},
+		{
+			name:             "monitor-allow-with-logging",
+			rule:             secv1alpha1.Rule{Action: &allowAction, EnableLogging: true},
+			mode:             secv1alpha1.EnforcementModeMonitor,
+			expAction:        &auditAction,
+			expEnableLogging: true,
+		},
+		{
+			name:         "enforce-allow-with-rate-limit",
+			rule:         secv1alpha1.Rule{Action: &allowAction, RateLimit: &secv1alpha1.RuleRateLimit{MaxNewConnectionsPerSecond: 100, Burst: 10}},
+			expAction:    &allowAction,
+			expRateLimit: &controlplane.RuleRateLimit{MaxNewConnectionsPerSecond: 100, Burst: 10},
+		},
+		{
+			name:      "monitor-allow-with-rate-limit",
+			rule:      secv1alpha1.Rule{Action: &allowAction, RateLimit: &secv1alpha1.RuleRateLimit{MaxNewConnectionsPerSecond: 100}},
+			mode:      secv1alpha1.EnforcementModeMonitor,
+			expAction: &auditAction,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := controlplane.NetworkPolicyRule{}
+			setRuleActionForCRD(&rule, &tt.rule, tt.mode)
+			assert.Equal(t, tt.expAction, rule.Action)
+			assert.Equal(t, tt.expEnableLogging, rule.EnableLogging)
+			assert.Equal(t, tt.expRateLimit, rule.RateLimit)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+const (
+	// defaultDenyAnnotation enables ("true") or disables ("false") the
+	// implicit default-deny policy of a Namespace, regardless of the
+	// defaultDenyNamespaces of the controller.
+	defaultDenyAnnotation = "policy.antrea.tanzu.vmware.com/default-deny"
+	// defaultDenyPolicyName is the name of the internal NetworkPolicy
+	// implementing the implicit default-deny policy of a Namespace. It's not a
+	// valid resource name so that it never conflicts with a NetworkPolicy
+	// created by users.
+	defaultDenyPolicyName = "antrea:default-deny"
+)
+
+// defaultDenyEnabled returns whether the implicit default-deny policy applies
+// to the Namespace.
+func (n *NetworkPolicyController) defaultDenyEnabled(namespace *v1.Namespace) bool {
+	if value, exists := namespace.Annotations[defaultDenyAnnotation]; exists {
+		return value == "true"
+	}
+	return n.defaultDenyNamespaces.Has(namespace.Name)
+}
+
+// processDefaultDenyPolicy creates the internal NetworkPolicy implementing the
+// implicit default-deny policy of the Namespace. It has the semantics of a K8s
+// NetworkPolicy selecting all the Pods of the Namespace, with both the Ingress
+// and Egress policy types and no rule: it isolates the Pods, and the traffic
+// allowed by any other NetworkPolicy is still allowed.
+func (n *NetworkPolicyController) processDefaultDenyPolicy(namespace *v1.Namespace) *antreatypes.NetworkPolicy {
+	appliedToGroupKey := n.createAppliedToGroup(namespace.Name, &metav1.LabelSelector{}, nil, nil)
+	return &antreatypes.NetworkPolicy{
+		Name:      defaultDenyPolicyName,
+		Namespace: namespace.Name,
+		// The Namespace UID is used so that the policy keeps the same UID
+		// across antrea-controller restarts.
+		UID: namespace.UID,
+		SourceRef: &controlplane.NetworkPolicyReference{
+			Type:      controlplane.K8sNetworkPolicy,
+			Namespace: namespace.Name,
+			Name:      defaultDenyPolicyName,
+			UID:       namespace.UID,
+		},
+		AppliedToGroups: []string{appliedToGroupKey},
+		Rules:           []controlplane.NetworkPolicyRule{denyAllIngressRule, denyAllEgressRule},
+	}
+}
+
+// addDefaultDenyPolicy creates the internal NetworkPolicy implementing the
+// implicit default-deny policy of the Namespace if it doesn't exist yet.
+func (n *NetworkPolicyController) addDefaultDenyPolicy(namespace *v1.Namespace) {
+	key := k8s.NamespacedName(namespace.Name, defaultDenyPolicyName)
+	if _, exists, _ := n.internalNetworkPolicyStore.Get(key); exists {
+		return
+	}
+	internalNP := n.processDefaultDenyPolicy(namespace)
+	klog.Infof("Creating default-deny internal NetworkPolicy for Namespace %s", namespace.Name)
+	n.internalNetworkPolicyStore.Create(internalNP)
+	n.enqueueInternalNetworkPolicy(key)
+}
+
+// deleteDefaultDenyPolicy deletes the internal NetworkPolicy implementing the
+// implicit default-deny policy of the Namespace if it exists.
+func (n *NetworkPolicyController) deleteDefaultDenyPolicy(namespace *v1.Namespace) {
+	key := k8s.NamespacedName(namespace.Name, defaultDenyPolicyName)
+	oldInternalNPObj, exists, _ := n.internalNetworkPolicyStore.Get(key)
+	if !exists {
+		return
+	}
+	oldInternalNP := oldInternalNPObj.(*antreatypes.NetworkPolicy)
+	klog.Infof("Deleting default-deny internal NetworkPolicy for Namespace %s", namespace.Name)
+	if err := n.internalNetworkPolicyStore.Delete(key); err != nil {
+		klog.Errorf("Error deleting default-deny internal NetworkPolicy for Namespace %s: %v", namespace.Name, err)
+		return
+	}
+	n.deleteDereferencedAppliedToGroup(oldInternalNP.AppliedToGroups[0])
+}
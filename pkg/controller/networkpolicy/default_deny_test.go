@@ -0,0 +1,98 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+func TestDefaultDenyEnabled(t *testing.T) {
+	_, npc := newController()
+	npc.defaultDenyNamespaces = sets.NewString("nsA")
+	tests := []struct {
+		name        string
+		namespace   string
+		annotations map[string]string
+		expected    bool
+	}{
+		{"listed", "nsA", nil, true},
+		{"not-listed", "nsB", nil, false},
+		{"listed-annotation-disabled", "nsA", map[string]string{defaultDenyAnnotation: "false"}, false},
+		{"not-listed-annotation-enabled", "nsB", map[string]string{defaultDenyAnnotation: "true"}, true},
+		{"not-listed-annotation-invalid", "nsB", map[string]string{defaultDenyAnnotation: "yes"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tt.namespace, Annotations: tt.annotations}}
+			assert.Equal(t, tt.expected, npc.defaultDenyEnabled(namespace))
+		})
+	}
+}
+
+func TestDefaultDenyPolicy(t *testing.T) {
+	_, npc := newController()
+	npc.defaultDenyNamespaces = sets.NewString("nsA")
+	namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nsA", UID: "uidA"}}
+	key := k8s.NamespacedName("nsA", defaultDenyPolicyName)
+	appliedToGroupKey := getNormalizedUID(generateNormalizedName("nsA", labels.Everything(), nil, nil))
+
+	npc.addNamespace(namespace)
+	obj, found, _ := npc.internalNetworkPolicyStore.Get(key)
+	require.True(t, found, "expected default-deny internal NetworkPolicy to be created")
+	internalNP := obj.(*antreatypes.NetworkPolicy)
+	assert.Equal(t, &antreatypes.NetworkPolicy{
+		Name:      defaultDenyPolicyName,
+		Namespace: "nsA",
+		UID:       "uidA",
+		SourceRef: &controlplane.NetworkPolicyReference{
+			Type:      controlplane.K8sNetworkPolicy,
+			Namespace: "nsA",
+			Name:      defaultDenyPolicyName,
+			UID:       "uidA",
+		},
+		AppliedToGroups: []string{appliedToGroupKey},
+		Rules:           []controlplane.NetworkPolicyRule{denyAllIngressRule, denyAllEgressRule},
+	}, internalNP)
+	_, found, _ = npc.appliedToGroupStore.Get(appliedToGroupKey)
+	assert.True(t, found, "expected AppliedToGroup to be created")
+
+	// Disabling the policy with the annotation deletes it.
+	updatedNamespace := namespace.DeepCopy()
+	updatedNamespace.Annotations = map[string]string{defaultDenyAnnotation: "false"}
+	npc.updateNamespace(namespace, updatedNamespace)
+	_, found, _ = npc.internalNetworkPolicyStore.Get(key)
+	assert.False(t, found, "expected default-deny internal NetworkPolicy to be deleted")
+	_, found, _ = npc.appliedToGroupStore.Get(appliedToGroupKey)
+	assert.False(t, found, "expected AppliedToGroup to be deleted")
+
+	// Enabling it again recreates it, and deleting the Namespace deletes it.
+	npc.updateNamespace(updatedNamespace, namespace)
+	_, found, _ = npc.internalNetworkPolicyStore.Get(key)
+	assert.True(t, found, "expected default-deny internal NetworkPolicy to be created")
+	npc.deleteNamespace(namespace)
+	_, found, _ = npc.internalNetworkPolicyStore.Get(key)
+	assert.False(t, found, "expected default-deny internal NetworkPolicy to be deleted")
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+// EffectiveRulesResponse is the reply struct for effective rules queries. The
+// rules of each direction are listed in the order in which they are enforced:
+// the first rule matching a connection decides whether it is allowed or
+// dropped.
+type EffectiveRulesResponse struct {
+	Namespace string `json:"namespace"`
+	// Pod is empty when the rules of a whole Namespace are queried.
+	Pod     string          `json:"pod,omitempty"`
+	Ingress []EffectiveRule `json:"ingress"`
+	Egress  []EffectiveRule `json:"egress"`
+}
+
+type EffectiveRule struct {
+	PolicyRef
+	PolicyType controlplane.NetworkPolicyType `json:"policyType"`
+	// Tier and TierPriority are empty for K8s NetworkPolicies, whose rules are
+	// enforced after the rules of all Antrea-native policies.
+	Tier           string   `json:"tier,omitempty"`
+	TierPriority   *int32   `json:"tierPriority,omitempty"`
+	PolicyPriority *float64 `json:"policyPriority,omitempty"`
+	// RuleIndex is the index of the rule among the rules of the same direction
+	// in the policy.
+	RuleIndex int                    `json:"ruleIndex"`
+	Action    secv1alpha1.RuleAction `json:"action"`
+	Direction controlplane.Direction `json:"direction"`
+}
+
+// QueryEffectiveRules returns the ordered ingress and egress rules which apply to the selected
+// Pod, or to any Pod of the selected Namespace if podName is empty. It returns nil if the Pod
+// or the Namespace doesn't exist.
+func (eq *endpointQuerier) QueryEffectiveRules(namespace string, podName string) (*EffectiveRulesResponse, error) {
+	n := eq.networkPolicyController
+	var pods []*corev1.Pod
+	if podName != "" {
+		pod, err := n.podInformer.Lister().Pods(namespace).Get(podName)
+		if err != nil {
+			return nil, nil
+		}
+		pods = []*corev1.Pod{pod}
+	} else {
+		if _, err := n.namespaceLister.Get(namespace); err != nil {
+			return nil, nil
+		}
+		var err error
+		pods, err = n.podInformer.Lister().Pods(namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+	}
+	appliedToGroupKeys := sets.String{}
+	for _, pod := range pods {
+		appliedToGroupKeys = appliedToGroupKeys.Union(n.filterAppliedToGroupsForPodOrExternalEntity(pod))
+	}
+	policies := map[string]*antreatypes.NetworkPolicy{}
+	for appliedToGroupKey := range appliedToGroupKeys {
+		objs, err := n.internalNetworkPolicyStore.GetByIndex(store.AppliedToGroupIndex, appliedToGroupKey)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			policy := obj.(*antreatypes.NetworkPolicy)
+			policies[string(policy.UID)] = policy
+		}
+	}
+	tierNames := eq.getTierNamesByPriority()
+	response := &EffectiveRulesResponse{
+		Namespace: namespace,
+		Pod:       podName,
+		Ingress:   []EffectiveRule{},
+		Egress:    []EffectiveRule{},
+	}
+	for _, policy := range policies {
+		policyType := controlplane.K8sNetworkPolicy
+		if policy.SourceRef != nil {
+			policyType = policy.SourceRef.Type
+		}
+		var tier string
+		if policy.TierPriority != nil {
+			tier = tierNames[*policy.TierPriority]
+		}
+		var ingressIndex, egressIndex int
+		for _, rule := range policy.Rules {
+			rules, index := &response.Egress, &egressIndex
+			if rule.Direction == controlplane.DirectionIn {
+				rules, index = &response.Ingress, &ingressIndex
+			}
+			// An empty action defaults to Allow.
+			action := secv1alpha1.RuleActionAllow
+			if rule.Action != nil {
+				action = *rule.Action
+			}
+			*rules = append(*rules, EffectiveRule{
+				PolicyRef: PolicyRef{
+					Namespace: policy.Namespace,
+					Name:      policy.Name,
+					UID:       policy.UID,
+				},
+				PolicyType:     policyType,
+				Tier:           tier,
+				TierPriority:   policy.TierPriority,
+				PolicyPriority: policy.Priority,
+				RuleIndex:      *index,
+				Action:         action,
+				Direction:      rule.Direction,
+			})
+			*index++
+		}
+	}
+	sortEffectiveRules(response.Ingress)
+	sortEffectiveRules(response.Egress)
+	return response, nil
+}
+
+// getTierNamesByPriority returns the names of the Tiers indexed by their priorities, which are
+// unique.
+func (eq *endpointQuerier) getTierNamesByPriority() map[int32]string {
+	tierNames := map[int32]string{}
+	tiers, _ := eq.networkPolicyController.tierLister.List(labels.Everything())
+	for _, tier := range tiers {
+		tierNames[tier.Spec.Priority] = tier.Name
+	}
+	return tierNames
+}
+
+// sortEffectiveRules sorts rules in the order in which they are enforced by the Antrea Agent:
+// the rules of Antrea-native policies are sorted by Tier priority, policy priority and rule
+// index, and are followed by the rules of K8s NetworkPolicies, which all have the same
+// precedence and are sorted by name for a stable output.
+func sortEffectiveRules(rules []EffectiveRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		ri, rj := &rules[i], &rules[j]
+		if (ri.TierPriority == nil) != (rj.TierPriority == nil) {
+			return ri.TierPriority != nil
+		}
+		if ri.TierPriority != nil && *ri.TierPriority != *rj.TierPriority {
+			return *ri.TierPriority < *rj.TierPriority
+		}
+		if ri.PolicyPriority != nil && rj.PolicyPriority != nil && *ri.PolicyPriority != *rj.PolicyPriority {
+			return *ri.PolicyPriority < *rj.PolicyPriority
+		}
+		if ri.Namespace != rj.Namespace {
+			return ri.Namespace < rj.Namespace
+		}
+		if ri.Name != rj.Name {
+			return ri.Name < rj.Name
+		}
+		return ri.RuleIndex < rj.RuleIndex
+	})
+}
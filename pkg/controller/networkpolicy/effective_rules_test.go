@@ -0,0 +1,104 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+func TestSortEffectiveRules(t *testing.T) {
+	tierPriority1, tierPriority2 := int32(50), int32(250)
+	policyPriority1, policyPriority2 := float64(1), float64(5)
+	k8sRule := EffectiveRule{PolicyRef: PolicyRef{Namespace: "ns1", Name: "np1"}, RuleIndex: 0}
+	k8sRuleOtherPolicy := EffectiveRule{PolicyRef: PolicyRef{Namespace: "ns1", Name: "np0"}, RuleIndex: 1}
+	highTierRule := EffectiveRule{PolicyRef: PolicyRef{Name: "acnp1"}, TierPriority: &tierPriority2, PolicyPriority: &policyPriority1, RuleIndex: 0}
+	lowTierRule0 := EffectiveRule{PolicyRef: PolicyRef{Name: "acnp2"}, TierPriority: &tierPriority1, PolicyPriority: &policyPriority2, RuleIndex: 0}
+	lowTierRule1 := EffectiveRule{PolicyRef: PolicyRef{Name: "acnp2"}, TierPriority: &tierPriority1, PolicyPriority: &policyPriority2, RuleIndex: 1}
+	lowTierHighPriorityRule := EffectiveRule{PolicyRef: PolicyRef{Namespace: "ns1", Name: "anp1"}, TierPriority: &tierPriority1, PolicyPriority: &policyPriority1, RuleIndex: 2}
+
+	rules := []EffectiveRule{k8sRule, highTierRule, lowTierRule1, k8sRuleOtherPolicy, lowTierRule0, lowTierHighPriorityRule}
+	sortEffectiveRules(rules)
+	assert.Equal(t, []EffectiveRule{lowTierHighPriorityRule, lowTierRule0, lowTierRule1, highTierRule, k8sRuleOtherPolicy, k8sRule}, rules)
+}
+
+func TestQueryEffectiveRules(t *testing.T) {
+	policyRef0 := PolicyRef{policies[0].Namespace, policies[0].Name, policies[0].UID}
+	policyRef1 := PolicyRef{policies[1].Namespace, policies[1].Name, policies[1].UID}
+
+	testCases := []struct {
+		name             string
+		objs             []runtime.Object
+		namespace        string
+		podName          string
+		expectedResponse *EffectiveRulesResponse
+	}{
+		{
+			"NonExistingPod",
+			[]runtime.Object{namespaces[0]},
+			"testNamespace",
+			"non-existing-pod",
+			nil,
+		},
+		{
+			"NonExistingNamespace",
+			[]runtime.Object{},
+			"non-existing-namespace",
+			"",
+			nil,
+		},
+		{
+			"NoPolicy",
+			[]runtime.Object{namespaces[0], pods[0]},
+			"testNamespace",
+			"podA",
+			&EffectiveRulesResponse{Namespace: "testNamespace", Pod: "podA", Ingress: []EffectiveRule{}, Egress: []EffectiveRule{}},
+		},
+		{
+			"MultiplePolicyNamespace",
+			[]runtime.Object{namespaces[0], pods[0], pods[1], policies[0], policies[1]},
+			"testNamespace",
+			"",
+			&EffectiveRulesResponse{
+				Namespace: "testNamespace",
+				Ingress: []EffectiveRule{
+					{PolicyRef: policyRef0, PolicyType: controlplane.K8sNetworkPolicy, Action: secv1alpha1.RuleActionAllow, Direction: controlplane.DirectionIn},
+				},
+				Egress: []EffectiveRule{
+					{PolicyRef: policyRef1, PolicyType: controlplane.K8sNetworkPolicy, Action: secv1alpha1.RuleActionAllow, Direction: controlplane.DirectionOut},
+					{PolicyRef: policyRef0, PolicyType: controlplane.K8sNetworkPolicy, Action: secv1alpha1.RuleActionAllow, Direction: controlplane.DirectionOut},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			endpointQuerier := makeControllerAndEndpointQuerier(tc.objs...)
+			response, err := endpointQuerier.QueryEffectiveRules(tc.namespace, tc.podName)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResponse, response)
+		})
+	}
+}
@@ -20,7 +20,9 @@ package networkpolicy
 
 import (
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
 	cpv1beta1 "github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
 	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
 	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
@@ -32,6 +34,10 @@ type EndpointQuerier interface {
 	// along with the list NetworkPolicies which select the provided Pod in one of their policy
 	// rules (ingress or egress).
 	QueryNetworkPolicies(namespace string, podName string) (*EndpointQueryResponse, error)
+	// QueryEffectiveRules returns the rules of all the NetworkPolicies which apply to the provided
+	// Pod, or to any Pod of the provided Namespace if podName is empty, in the order in which they
+	// are enforced.
+	QueryEffectiveRules(namespace string, podName string) (*EffectiveRulesResponse, error)
 }
 
 // endpointQuerier implements the EndpointQuerier interface
@@ -59,12 +65,16 @@ type PolicyRef struct {
 
 type Policy struct {
 	PolicyRef
+	// AppliedToGroups are the AppliedToGroups of the policy which select the endpoint.
+	AppliedToGroups []string `json:"appliedtogroups,omitempty"`
 }
 
 type Rule struct {
 	PolicyRef
 	Direction cpv1beta1.Direction `json:"direction,omitempty"`
 	RuleIndex int                 `json:"ruleindex,omitempty"`
+	// AddressGroups are the AddressGroups of the rule which select the endpoint.
+	AddressGroups []string `json:"addressgroups,omitempty"`
 }
 
 // NewEndpointQuerier returns a new *endpointQuerier.
@@ -79,128 +89,152 @@ func NewEndpointQuerier(networkPolicyController *NetworkPolicyController) *endpo
 // network endpoint. Relevant policies fall into three categories: applied policies (Policies in
 // Endpoint type) are policies which directly apply to an endpoint, egress and ingress rules (Rules
 // in Endpoint type) are policies which reference the endpoint in an ingress/egress rule
-// respectively.
+// respectively. The AppliedToGroups and AddressGroups through which the endpoint is selected are
+// included, so that they can be correlated with the groups received by the Agents.
 func (eq *endpointQuerier) QueryNetworkPolicies(namespace string, podName string) (*EndpointQueryResponse, error) {
 	// check if namespace and podName select an existing pod
 	pod, err := eq.networkPolicyController.podInformer.Lister().Pods(namespace).Get(podName)
 	if err != nil {
 		return nil, nil
 	}
-	type ruleTemp struct {
-		policy *antreatypes.NetworkPolicy
-		index  int
-	}
-	// create network policies categories
-	applied := make([]*antreatypes.NetworkPolicy, 0)
-	ingress := make([]*ruleTemp, 0)
-	egress := make([]*ruleTemp, 0)
 	// get all appliedToGroups using filter, then get applied policies using appliedToGroup
 	appliedToGroupKeys := eq.networkPolicyController.filterAppliedToGroupsForPodOrExternalEntity(pod)
-	if err != nil {
-		return nil, err
-	}
 	// We iterate over all AppliedToGroups (same for AddressGroups below). This is acceptable
 	// since this implementation only supports user queries (in particular through antctl) and
 	// should resturn within a reasonable amount of time. We experimented with adding Pod
 	// Indexers to the AppliedToGroup and AddressGroup stores, but we felt that this use case
 	// did not justify the memory overhead. If we can find another use for the Indexers as part
 	// of the NetworkPolicy Controller implementation, we may consider adding them back.
-	for appliedToGroupKey := range appliedToGroupKeys {
-		policies, err := eq.networkPolicyController.internalNetworkPolicyStore.GetByIndex(
-			store.AppliedToGroupIndex,
-			appliedToGroupKey,
-		)
-		if err != nil {
-			return nil, err
-		}
-		for _, policy := range policies {
-			applied = append(applied, policy.(*antreatypes.NetworkPolicy))
-		}
+	applied, err := eq.getPoliciesByIndex(store.AppliedToGroupIndex, appliedToGroupKeys)
+	if err != nil {
+		return nil, err
 	}
 	// get all addressGroups using filter, then get ingress and egress policies using addressGroup
 	addressGroupKeys := eq.networkPolicyController.filterAddressGroupsForPodOrExternalEntity(pod)
+	referencing, err := eq.getPoliciesByIndex(store.AddressGroupIndex, addressGroupKeys)
 	if err != nil {
 		return nil, err
 	}
-	for addressGroupKey := range addressGroupKeys {
-		addressGroup, found, _ := eq.networkPolicyController.addressGroupStore.Get(addressGroupKey)
-		if !found {
-			continue
+	// make response policies
+	responsePolicies := make([]Policy, 0, len(applied))
+	for _, p := range applied {
+		responsePolicies = append(responsePolicies, Policy{
+			PolicyRef:       newPolicyRef(p.policy),
+			AppliedToGroups: p.groups,
+		})
+	}
+	// create rules based on egress and ingress policies, the index of a rule is its index among
+	// the rules of the same direction in the policy
+	egress, ingress := make([]Rule, 0), make([]Rule, 0)
+	for _, p := range referencing {
+		egressIndex, ingressIndex := 0, 0
+		for _, rule := range p.policy.Rules {
+			if rule.Direction == controlplane.DirectionOut {
+				if groups := selectedGroups(rule.To.AddressGroups, p.groups); len(groups) > 0 {
+					egress = append(egress, Rule{
+						PolicyRef:     newPolicyRef(p.policy),
+						Direction:     cpv1beta1.DirectionOut,
+						RuleIndex:     egressIndex,
+						AddressGroups: groups,
+					})
+				}
+				egressIndex++
+			} else {
+				if groups := selectedGroups(rule.From.AddressGroups, p.groups); len(groups) > 0 {
+					ingress = append(ingress, Rule{
+						PolicyRef:     newPolicyRef(p.policy),
+						Direction:     cpv1beta1.DirectionIn,
+						RuleIndex:     ingressIndex,
+						AddressGroups: groups,
+					})
+				}
+				ingressIndex++
+			}
 		}
-		policies, err := eq.networkPolicyController.internalNetworkPolicyStore.GetByIndex(
-			store.AddressGroupIndex,
-			addressGroupKey,
-		)
+	}
+	// for now, selector only selects a single endpoint (pod, namespace)
+	endpoint := Endpoint{
+		Namespace: namespace,
+		Name:      podName,
+		Policies:  responsePolicies,
+		Rules:     append(egress, ingress...),
+	}
+	return &EndpointQueryResponse{[]Endpoint{endpoint}}, nil
+}
+
+// policyGroups is an internal NetworkPolicy along with the groups selecting the endpoint through
+// which it was found.
+type policyGroups struct {
+	policy *antreatypes.NetworkPolicy
+	groups []string
+}
+
+// getPoliciesByIndex returns the internal NetworkPolicies indexed by any of the provided group
+// keys. A policy referring to multiple groups is only returned once, with all these groups.
+func (eq *endpointQuerier) getPoliciesByIndex(indexName string, groupKeys sets.String) ([]policyGroups, error) {
+	var result []policyGroups
+	// Deduplication is only needed when the endpoint is selected by multiple groups.
+	var indexes map[types.UID]int
+	if groupKeys.Len() > 1 {
+		indexes = make(map[types.UID]int)
+	}
+	for groupKey := range groupKeys {
+		policies, err := eq.networkPolicyController.internalNetworkPolicyStore.GetByIndex(indexName, groupKey)
 		if err != nil {
 			return nil, err
 		}
+		// The slice is shared by all the policies found through this group. Its capacity is 1, so
+		// appending to it below always allocates a new slice.
+		groups := []string{groupKey}
 		for _, policy := range policies {
-			egressIndex := 0
-			ingressIndex := 0
-			for _, rule := range policy.(*antreatypes.NetworkPolicy).Rules {
-				for _, addressGroupTrial := range rule.To.AddressGroups {
-					if addressGroupTrial == string(addressGroup.(*antreatypes.AddressGroup).UID) {
-						egress = append(egress, &ruleTemp{policy: policy.(*antreatypes.NetworkPolicy), index: egressIndex})
-						egressIndex++
-						// an AddressGroup can only be referenced in a rule once
-						break
-					}
-				}
-				for _, addressGroupTrial := range rule.From.AddressGroups {
-					if addressGroupTrial == string(addressGroup.(*antreatypes.AddressGroup).UID) {
-						ingress = append(ingress, &ruleTemp{policy: policy.(*antreatypes.NetworkPolicy), index: ingressIndex})
-						ingressIndex++
-						// an AddressGroup can only be referenced in a rule once
-						break
-					}
+			internalPolicy := policy.(*antreatypes.NetworkPolicy)
+			if indexes != nil {
+				if i, exists := indexes[internalPolicy.UID]; exists {
+					result[i].groups = append(result[i].groups, groupKey)
+					continue
 				}
+				indexes[internalPolicy.UID] = len(result)
 			}
+			result = append(result, policyGroups{policy: internalPolicy, groups: groups})
 		}
 	}
-	// make response policies
-	responsePolicies := make([]Policy, 0)
-	for _, internalPolicy := range applied {
-		responsePolicy := Policy{
-			PolicyRef: PolicyRef{
-				Namespace: internalPolicy.Namespace,
-				Name:      internalPolicy.Name,
-				UID:       internalPolicy.UID,
-			},
-		}
-		responsePolicies = append(responsePolicies, responsePolicy)
+	return result, nil
+}
+
+func newPolicyRef(policy *antreatypes.NetworkPolicy) PolicyRef {
+	return PolicyRef{
+		Namespace: policy.Namespace,
+		Name:      policy.Name,
+		UID:       policy.UID,
 	}
-	responseRules := make([]Rule, 0)
-	// create rules based on egress and ingress policies
-	for _, internalPolicy := range egress {
-		newRule := Rule{
-			PolicyRef: PolicyRef{
-				Namespace: internalPolicy.policy.Namespace,
-				Name:      internalPolicy.policy.Name,
-				UID:       internalPolicy.policy.UID,
-			},
-			Direction: cpv1beta1.DirectionOut,
-			RuleIndex: internalPolicy.index,
+}
+
+// selectedGroups returns the groups referenced by a rule which are in the provided groups
+// selecting the endpoint. selecting is returned as is if the rule references all of them.
+func selectedGroups(groups []string, selecting []string) []string {
+	matches := 0
+	for _, group := range groups {
+		for _, s := range selecting {
+			if group == s {
+				matches++
+				break
+			}
 		}
-		responseRules = append(responseRules, newRule)
 	}
-	for _, internalPolicy := range ingress {
-		newRule := Rule{
-			PolicyRef: PolicyRef{
-				Namespace: internalPolicy.policy.Namespace,
-				Name:      internalPolicy.policy.Name,
-				UID:       internalPolicy.policy.UID,
-			},
-			Direction: cpv1beta1.DirectionIn,
-			RuleIndex: internalPolicy.index,
-		}
-		responseRules = append(responseRules, newRule)
+	if matches == 0 {
+		return nil
 	}
-	// for now, selector only selects a single endpoint (pod, namespace)
-	endpoint := Endpoint{
-		Namespace: namespace,
-		Name:      podName,
-		Policies:  responsePolicies,
-		Rules:     responseRules,
+	if matches == len(selecting) {
+		return selecting
 	}
-	return &EndpointQueryResponse{[]Endpoint{endpoint}}, nil
+	selected := make([]string, 0, matches)
+	for _, group := range groups {
+		for _, s := range selecting {
+			if group == s {
+				selected = append(selected, group)
+				break
+			}
+		}
+	}
+	return selected
 }
@@ -0,0 +1,323 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	corev1a1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+// groupsReferencedByANP returns the keys of the Groups referenced by the peers
+// of an Antrea NetworkPolicy. The Groups are in the same Namespace as the
+// Antrea NetworkPolicy.
+func groupsReferencedByANP(anp *secv1alpha1.NetworkPolicy) []string {
+	groups := sets.NewString()
+	addPeers := func(peers []secv1alpha1.NetworkPolicyPeer) {
+		for _, peer := range peers {
+			if peer.Group != "" {
+				groups.Insert(k8s.NamespacedName(anp.Namespace, peer.Group))
+			}
+		}
+	}
+	for _, rule := range anp.Spec.Ingress {
+		addPeers(rule.From)
+	}
+	for _, rule := range anp.Spec.Egress {
+		addPeers(rule.To)
+	}
+	return groups.List()
+}
+
+// groupIndexFunc is the IndexFunc of GroupIndex, used to find the Antrea
+// NetworkPolicies referencing a Group.
+func groupIndexFunc(obj interface{}) ([]string, error) {
+	anp, ok := obj.(*secv1alpha1.NetworkPolicy)
+	if !ok {
+		return []string{}, nil
+	}
+	return groupsReferencedByANP(anp), nil
+}
+
+// childGroupIndexFunc is the IndexFunc of ChildGroupIndex, used to find the
+// parent Groups of a Group.
+func childGroupIndexFunc(obj interface{}) ([]string, error) {
+	g, ok := obj.(*corev1a1.Group)
+	if !ok {
+		return []string{}, nil
+	}
+	children := make([]string, 0, len(g.Spec.ChildGroups))
+	for _, child := range g.Spec.ChildGroups {
+		children = append(children, k8s.NamespacedName(g.Namespace, child))
+	}
+	return children, nil
+}
+
+// serviceIndexFunc is the IndexFunc of ServiceIndex, used to find the Groups
+// referencing a Service.
+func serviceIndexFunc(obj interface{}) ([]string, error) {
+	g, ok := obj.(*corev1a1.Group)
+	if !ok || g.Spec.ServiceReference == nil {
+		return []string{}, nil
+	}
+	return []string{k8s.NamespacedName(g.Namespace, g.Spec.ServiceReference.Name)}, nil
+}
+
+// servicesReferencedByRules returns the keys of the Services referenced by the
+// toServices of the egress rules of an Antrea Policy of the given Namespace.
+func servicesReferencedByRules(namespace string, egress []secv1alpha1.Rule) []string {
+	services := sets.NewString()
+	for _, rule := range egress {
+		for _, ref := range rule.ToServices {
+			services.Insert(k8s.NamespacedName(serviceReferenceNamespace(ref, namespace), ref.Name))
+		}
+	}
+	return services.List()
+}
+
+// policyServiceIndexFunc is the IndexFunc of ServiceIndex for Antrea
+// ClusterNetworkPolicies and Antrea NetworkPolicies, used to find the policies
+// referencing a Service in their egress rules.
+func policyServiceIndexFunc(obj interface{}) ([]string, error) {
+	switch np := obj.(type) {
+	case *secv1alpha1.ClusterNetworkPolicy:
+		return servicesReferencedByRules("", np.Spec.Egress), nil
+	case *secv1alpha1.NetworkPolicy:
+		return servicesReferencedByRules(np.Namespace, np.Spec.Egress), nil
+	}
+	return []string{}, nil
+}
+
+// expandGroupPeers replaces the peers of an Antrea NetworkPolicy referencing a
+// Group with the peers selecting the members of the Group. A peer referencing
+// a Group which doesn't exist yet doesn't select anything until the Group is
+// created.
+func (n *NetworkPolicyController) expandGroupPeers(namespace string, peers []secv1alpha1.NetworkPolicyPeer) []secv1alpha1.NetworkPolicyPeer {
+	expanded := make([]secv1alpha1.NetworkPolicyPeer, 0, len(peers))
+	for _, peer := range peers {
+		if peer.Group == "" {
+			expanded = append(expanded, peer)
+			continue
+		}
+		group, err := n.groupLister.Groups(namespace).Get(peer.Group)
+		if err != nil {
+			klog.V(2).Infof("Group %s/%s referenced by Antrea NetworkPolicy does not exist", namespace, peer.Group)
+			continue
+		}
+		expanded = append(expanded, n.groupMemberPeers(group, true)...)
+	}
+	return expanded
+}
+
+// groupMemberPeers returns the peers selecting the members of a Group. The
+// members of the child Groups are only included if includeChildGroups is true,
+// as child Groups cannot have child Groups themselves.
+func (n *NetworkPolicyController) groupMemberPeers(group *corev1a1.Group, includeChildGroups bool) []secv1alpha1.NetworkPolicyPeer {
+	spec := group.Spec
+	var peers []secv1alpha1.NetworkPolicyPeer
+	switch {
+	case spec.PodSelector != nil || spec.ExternalEntitySelector != nil:
+		peers = append(peers, secv1alpha1.NetworkPolicyPeer{
+			PodSelector:            spec.PodSelector,
+			ExternalEntitySelector: spec.ExternalEntitySelector,
+		})
+	case spec.ServiceReference != nil:
+		svc, err := n.serviceLister.Services(group.Namespace).Get(spec.ServiceReference.Name)
+		if err != nil {
+			klog.V(2).Infof("Service %s referenced by Group %s/%s does not exist", spec.ServiceReference.Name, group.Namespace, group.Name)
+			break
+		}
+		// A Service without selector doesn't select any Pod, while an
+		// empty PodSelector would select all Pods of the Namespace.
+		if len(svc.Spec.Selector) == 0 {
+			break
+		}
+		peers = append(peers, secv1alpha1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{MatchLabels: svc.Spec.Selector},
+		})
+	case len(spec.IPBlocks) > 0:
+		for i := range spec.IPBlocks {
+			ipBlock := spec.IPBlocks[i]
+			peers = append(peers, secv1alpha1.NetworkPolicyPeer{IPBlock: &ipBlock})
+		}
+	case len(spec.ChildGroups) > 0 && includeChildGroups:
+		for _, child := range spec.ChildGroups {
+			childGroup, err := n.groupLister.Groups(group.Namespace).Get(child)
+			if err != nil {
+				klog.V(2).Infof("Child Group %s of Group %s/%s does not exist", child, group.Namespace, group.Name)
+				continue
+			}
+			peers = append(peers, n.groupMemberPeers(childGroup, false)...)
+		}
+	}
+	return peers
+}
+
+// addGroup receives Group ADD events and re-processes the Antrea
+// NetworkPolicies referencing the Group.
+func (n *NetworkPolicyController) addGroup(obj interface{}) {
+	defer n.heartbeat("addGroup")
+	g := obj.(*corev1a1.Group)
+	klog.V(2).Infof("Processing Group %s/%s ADD event", g.Namespace, g.Name)
+	n.reprocessANPsForGroup(g)
+}
+
+// updateGroup receives Group UPDATE events and re-processes the Antrea
+// NetworkPolicies referencing the Group if its spec has changed.
+func (n *NetworkPolicyController) updateGroup(oldObj, curObj interface{}) {
+	defer n.heartbeat("updateGroup")
+	oldG := oldObj.(*corev1a1.Group)
+	curG := curObj.(*corev1a1.Group)
+	klog.V(2).Infof("Processing Group %s/%s UPDATE event", curG.Namespace, curG.Name)
+	if reflect.DeepEqual(oldG.Spec, curG.Spec) {
+		klog.V(4).Infof("No change in Group %s/%s spec. Skipping NetworkPolicy evaluation.", curG.Namespace, curG.Name)
+		return
+	}
+	n.reprocessANPsForGroup(curG)
+}
+
+// deleteGroup receives Group DELETE events and re-processes the Antrea
+// NetworkPolicies referencing the Group.
+func (n *NetworkPolicyController) deleteGroup(old interface{}) {
+	g, ok := old.(*corev1a1.Group)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Group, invalid type: %v", old)
+			return
+		}
+		g, ok = tombstone.Obj.(*corev1a1.Group)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Group, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	defer n.heartbeat("deleteGroup")
+	klog.V(2).Infof("Processing Group %s/%s DELETE event", g.Namespace, g.Name)
+	n.reprocessANPsForGroup(g)
+}
+
+// reprocessANPsForGroup re-computes the internal NetworkPolicies of the Antrea
+// NetworkPolicies referencing the Group or its parent Groups, so that their
+// peers reflect the current members of the Group.
+func (n *NetworkPolicyController) reprocessANPsForGroup(g *corev1a1.Group) {
+	n.reprocessANPsReferencingGroup(g)
+	parents, err := n.groupInformer.Informer().GetIndexer().ByIndex(ChildGroupIndex, k8s.NamespacedName(g.Namespace, g.Name))
+	if err != nil {
+		klog.Errorf("Failed to get parent Groups of Group %s/%s: %v", g.Namespace, g.Name, err)
+		return
+	}
+	for _, obj := range parents {
+		n.reprocessANPsReferencingGroup(obj.(*corev1a1.Group))
+	}
+}
+
+// reprocessANPsReferencingGroup re-computes the internal NetworkPolicies of the
+// Antrea NetworkPolicies referencing the Group.
+func (n *NetworkPolicyController) reprocessANPsReferencingGroup(g *corev1a1.Group) {
+	anps, err := n.anpInformer.Informer().GetIndexer().ByIndex(GroupIndex, k8s.NamespacedName(g.Namespace, g.Name))
+	if err != nil {
+		klog.Errorf("Failed to get Antrea NetworkPolicies referencing Group %s/%s: %v", g.Namespace, g.Name, err)
+		return
+	}
+	for _, obj := range anps {
+		anp := obj.(*secv1alpha1.NetworkPolicy)
+		key, _ := keyFunc(anp)
+		// Antrea NetworkPolicies which haven't been processed yet will
+		// get the current Group when their ADD event is processed.
+		if _, exists, _ := n.internalNetworkPolicyStore.Get(key); !exists {
+			continue
+		}
+		klog.V(2).Infof("Re-processing Antrea NetworkPolicy %s/%s referencing Group %s", anp.Namespace, anp.Name, g.Name)
+		n.updateANP(anp, anp)
+	}
+}
+
+// addService receives Service ADD events and re-processes the Antrea Policies
+// referencing the Service, directly or through a Group.
+func (n *NetworkPolicyController) addService(obj interface{}) {
+	defer n.heartbeat("addService")
+	svc := obj.(*v1.Service)
+	klog.V(2).Infof("Processing Service %s/%s ADD event", svc.Namespace, svc.Name)
+	n.reprocessPoliciesForService(svc)
+}
+
+// updateService receives Service UPDATE events and re-processes the Antrea
+// Policies referencing the Service, directly or through a Group, if its
+// selector or ClusterIP has changed.
+func (n *NetworkPolicyController) updateService(oldObj, curObj interface{}) {
+	defer n.heartbeat("updateService")
+	oldSvc := oldObj.(*v1.Service)
+	curSvc := curObj.(*v1.Service)
+	if reflect.DeepEqual(oldSvc.Spec.Selector, curSvc.Spec.Selector) && oldSvc.Spec.ClusterIP == curSvc.Spec.ClusterIP {
+		return
+	}
+	klog.V(2).Infof("Processing Service %s/%s UPDATE event", curSvc.Namespace, curSvc.Name)
+	n.reprocessPoliciesForService(curSvc)
+}
+
+// deleteService receives Service DELETE events and re-processes the Antrea
+// Policies referencing the Service, directly or through a Group.
+func (n *NetworkPolicyController) deleteService(old interface{}) {
+	svc, ok := old.(*v1.Service)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Service, invalid type: %v", old)
+			return
+		}
+		svc, ok = tombstone.Obj.(*v1.Service)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Service, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	defer n.heartbeat("deleteService")
+	klog.V(2).Infof("Processing Service %s/%s DELETE event", svc.Namespace, svc.Name)
+	n.reprocessPoliciesForService(svc)
+}
+
+// reprocessPoliciesForService re-computes the internal NetworkPolicies of the
+// Antrea Policies referencing the Service in their toServices, and of the
+// Antrea NetworkPolicies referencing the Groups which reference the Service.
+func (n *NetworkPolicyController) reprocessPoliciesForService(svc *v1.Service) {
+	key := k8s.NamespacedName(svc.Namespace, svc.Name)
+	groups, err := n.groupInformer.Informer().GetIndexer().ByIndex(ServiceIndex, key)
+	if err != nil {
+		klog.Errorf("Failed to get Groups referencing Service %s: %v", key, err)
+		return
+	}
+	for _, obj := range groups {
+		n.reprocessANPsForGroup(obj.(*corev1a1.Group))
+	}
+	n.reprocessIndexedPolicies(n.cnpInformer.Informer(), ServiceIndex, key, func(obj interface{}) {
+		cnp := obj.(*secv1alpha1.ClusterNetworkPolicy)
+		klog.V(2).Infof("Re-processing Antrea ClusterNetworkPolicy %s referencing Service %s", cnp.Name, key)
+		n.updateCNP(cnp, cnp)
+	})
+	n.reprocessIndexedPolicies(n.anpInformer.Informer(), ServiceIndex, key, func(obj interface{}) {
+		anp := obj.(*secv1alpha1.NetworkPolicy)
+		klog.V(2).Infof("Re-processing Antrea NetworkPolicy %s/%s referencing Service %s", anp.Namespace, anp.Name, key)
+		n.updateANP(anp, anp)
+	})
+}
@@ -0,0 +1,287 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	corev1a1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+func TestGroupsReferencedByANP(t *testing.T) {
+	anp := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "npA"},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			Ingress: []secv1alpha1.Rule{
+				{From: []secv1alpha1.NetworkPolicyPeer{{Group: "web"}, {PodSelector: &selectorA}}},
+			},
+			Egress: []secv1alpha1.Rule{
+				{To: []secv1alpha1.NetworkPolicyPeer{{Group: "db"}, {Group: "web"}}},
+			},
+		},
+	}
+	assert.Equal(t, []string{"ns1/db", "ns1/web"}, groupsReferencedByANP(anp))
+}
+
+func TestGroupUpdatesANP(t *testing.T) {
+	_, npc := newController()
+	groupInformer := npc.crdInformerFactory.Core().V1alpha1().Groups()
+	npc.groupInformer = groupInformer
+	npc.groupLister = groupInformer.Lister()
+	groupInformer.Informer().AddIndexers(cache.Indexers{ChildGroupIndex: childGroupIndexFunc})
+	npc.anpInformer = npc.crdInformerFactory.Security().V1alpha1().NetworkPolicies()
+	npc.anpInformer.Informer().AddIndexers(cache.Indexers{GroupIndex: groupIndexFunc})
+	groupStore := groupInformer.Informer().GetStore()
+
+	allowAction := secv1alpha1.RuleActionAllow
+	anp := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "npA", UID: "uidA"},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			Priority:  10,
+			Ingress: []secv1alpha1.Rule{
+				{
+					From:   []secv1alpha1.NetworkPolicyPeer{{Group: "web"}},
+					Action: &allowAction,
+				},
+			},
+		},
+	}
+	npc.anpInformer.Informer().GetStore().Add(anp)
+	npc.addANP(anp)
+
+	getFromAddressGroups := func() []string {
+		obj, _, _ := npc.internalNetworkPolicyStore.Get("ns1/npA")
+		rules := obj.(*antreatypes.NetworkPolicy).Rules
+		require.Len(t, rules, 1)
+		return rules[0].From.AddressGroups
+	}
+	addressGroupFor := func(selector metav1.LabelSelector) []string {
+		return []string{getNormalizedUID(toGroupSelector("ns1", &selector, nil, nil).NormalizedName)}
+	}
+
+	// The peer doesn't select anything until the Group is created.
+	assert.Empty(t, getFromAddressGroups())
+
+	group := &corev1a1.Group{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web"},
+		Spec:       corev1a1.GroupSpec{PodSelector: &selectorB},
+	}
+	groupStore.Add(group)
+	npc.addGroup(group)
+	assert.Equal(t, addressGroupFor(selectorB), getFromAddressGroups())
+
+	// Groups of other Namespaces are not referenced.
+	otherGroup := &corev1a1.Group{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "web"},
+		Spec:       corev1a1.GroupSpec{PodSelector: &selectorD},
+	}
+	groupStore.Add(otherGroup)
+	npc.addGroup(otherGroup)
+	assert.Equal(t, addressGroupFor(selectorB), getFromAddressGroups())
+
+	updatedGroup := group.DeepCopy()
+	updatedGroup.Spec.PodSelector = &selectorC
+	groupStore.Update(updatedGroup)
+	npc.updateGroup(group, updatedGroup)
+	assert.Equal(t, addressGroupFor(selectorC), getFromAddressGroups())
+	// The AddressGroup of the old selector is no longer referenced.
+	_, found, _ := npc.addressGroupStore.Get(addressGroupFor(selectorB)[0])
+	assert.False(t, found)
+
+	groupStore.Delete(updatedGroup)
+	npc.deleteGroup(updatedGroup)
+	assert.Empty(t, getFromAddressGroups())
+}
+
+func TestGroupMembersANP(t *testing.T) {
+	_, npc := newController()
+	groupInformer := npc.crdInformerFactory.Core().V1alpha1().Groups()
+	npc.groupInformer = groupInformer
+	npc.groupLister = groupInformer.Lister()
+	groupInformer.Informer().AddIndexers(cache.Indexers{ChildGroupIndex: childGroupIndexFunc, ServiceIndex: serviceIndexFunc})
+	npc.serviceLister = npc.informerFactory.Core().V1().Services().Lister()
+	npc.anpInformer = npc.crdInformerFactory.Security().V1alpha1().NetworkPolicies()
+	npc.anpInformer.Informer().AddIndexers(cache.Indexers{GroupIndex: groupIndexFunc, ServiceIndex: policyServiceIndexFunc})
+	npc.cnpInformer = npc.crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
+	npc.cnpInformer.Informer().AddIndexers(cache.Indexers{ServiceIndex: policyServiceIndexFunc})
+	groupStore := groupInformer.Informer().GetStore()
+	serviceStore := npc.informerFactory.Core().V1().Services().Informer().GetStore()
+
+	allowAction := secv1alpha1.RuleActionAllow
+	anp := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "npA", UID: "uidA"},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			Ingress: []secv1alpha1.Rule{
+				{
+					From:   []secv1alpha1.NetworkPolicyPeer{{Group: "parent"}},
+					Action: &allowAction,
+				},
+			},
+		},
+	}
+	npc.anpInformer.Informer().GetStore().Add(anp)
+	npc.addANP(anp)
+
+	getFromPeer := func() antreatypes.NetworkPolicy {
+		obj, _, _ := npc.internalNetworkPolicyStore.Get("ns1/npA")
+		return *obj.(*antreatypes.NetworkPolicy)
+	}
+	addressGroupFor := func(selector metav1.LabelSelector) string {
+		return getNormalizedUID(toGroupSelector("ns1", &selector, nil, nil).NormalizedName)
+	}
+
+	parent := &corev1a1.Group{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "parent"},
+		Spec:       corev1a1.GroupSpec{ChildGroups: []string{"svc", "cidrs"}},
+	}
+	groupStore.Add(parent)
+	npc.addGroup(parent)
+	assert.Empty(t, getFromPeer().Rules[0].From.AddressGroups)
+
+	// The members of the child Groups are members of the parent Group.
+	svcGroup := &corev1a1.Group{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "svc"},
+		Spec:       corev1a1.GroupSpec{ServiceReference: &corev1a1.ServiceReference{Name: "web"}},
+	}
+	groupStore.Add(svcGroup)
+	npc.addGroup(svcGroup)
+	cidrGroup := &corev1a1.Group{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cidrs"},
+		Spec:       corev1a1.GroupSpec{IPBlocks: []secv1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}, {CIDR: "10.0.1.0/24"}}},
+	}
+	groupStore.Add(cidrGroup)
+	npc.addGroup(cidrGroup)
+	from := getFromPeer().Rules[0].From
+	assert.Empty(t, from.AddressGroups, "Group referencing a missing Service should not select anything")
+	require.Len(t, from.IPBlocks, 2)
+	assert.Equal(t, "10.0.1.0", net.IP(from.IPBlocks[1].CIDR.IP).String())
+
+	// The Pods selected by the Service are members of the Group.
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web"},
+		Spec:       v1.ServiceSpec{Selector: selectorB.MatchLabels},
+	}
+	serviceStore.Add(svc)
+	npc.addService(svc)
+	assert.Equal(t, []string{addressGroupFor(selectorB)}, getFromPeer().Rules[0].From.AddressGroups)
+
+	updatedSvc := svc.DeepCopy()
+	updatedSvc.Spec.Selector = selectorC.MatchLabels
+	serviceStore.Update(updatedSvc)
+	npc.updateService(svc, updatedSvc)
+	assert.Equal(t, []string{addressGroupFor(selectorC)}, getFromPeer().Rules[0].From.AddressGroups)
+
+	// A Service without selector doesn't select any Pod.
+	noSelectorSvc := updatedSvc.DeepCopy()
+	noSelectorSvc.Spec.Selector = nil
+	serviceStore.Update(noSelectorSvc)
+	npc.updateService(updatedSvc, noSelectorSvc)
+	assert.Empty(t, getFromPeer().Rules[0].From.AddressGroups)
+}
+
+func TestValidateGroup(t *testing.T) {
+	_, npc := newController()
+	groupInformer := npc.crdInformerFactory.Core().V1alpha1().Groups()
+	npc.groupInformer = groupInformer
+	npc.groupLister = groupInformer.Lister()
+	groupInformer.Informer().AddIndexers(cache.Indexers{ChildGroupIndex: childGroupIndexFunc})
+	groupStore := groupInformer.Informer().GetStore()
+	groupStore.Add(&corev1a1.Group{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "parent"},
+		Spec:       corev1a1.GroupSpec{ChildGroups: []string{"child"}},
+	})
+	groupStore.Add(&corev1a1.Group{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web"},
+		Spec:       corev1a1.GroupSpec{PodSelector: &selectorA},
+	})
+	tests := []struct {
+		name       string
+		group      string
+		spec       corev1a1.GroupSpec
+		expAllowed bool
+	}{
+		{"pod-selector", "g1", corev1a1.GroupSpec{PodSelector: &selectorA}, true},
+		{"service-reference", "g1", corev1a1.GroupSpec{ServiceReference: &corev1a1.ServiceReference{Name: "web"}}, true},
+		{"ip-blocks", "g1", corev1a1.GroupSpec{IPBlocks: []secv1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}}}, true},
+		{"invalid-ip-block", "g1", corev1a1.GroupSpec{IPBlocks: []secv1alpha1.IPBlock{{CIDR: "10.0.0.0"}}}, false},
+		{"no-member", "g1", corev1a1.GroupSpec{}, false},
+		{"multiple-members", "g1", corev1a1.GroupSpec{PodSelector: &selectorA, IPBlocks: []secv1alpha1.IPBlock{{CIDR: "10.0.0.0/24"}}}, false},
+		{"child-groups", "g1", corev1a1.GroupSpec{ChildGroups: []string{"web", "db"}}, true},
+		{"self-child", "g1", corev1a1.GroupSpec{ChildGroups: []string{"g1"}}, false},
+		{"nested-child", "g1", corev1a1.GroupSpec{ChildGroups: []string{"parent"}}, false},
+		{"child-with-children", "child", corev1a1.GroupSpec{ChildGroups: []string{"web"}}, false},
+	}
+	v := NewNetworkPolicyValidator(npc.NetworkPolicyController)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := &corev1a1.Group{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: tt.group}, Spec: tt.spec}
+			_, allowed := v.validateGroup(group)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
+
+func TestValidateGroupPeers(t *testing.T) {
+	tests := []struct {
+		name       string
+		egress     []secv1alpha1.Rule
+		namespaced bool
+		expAllowed bool
+	}{
+		{
+			name:       "group-in-anp",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{Group: "web"}}}},
+			namespaced: true,
+			expAllowed: true,
+		},
+		{
+			name:       "group-in-acnp",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{Group: "web"}}}},
+			namespaced: false,
+			expAllowed: false,
+		},
+		{
+			name:       "group-with-selector",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{Group: "web", PodSelector: &selectorA}}}},
+			namespaced: true,
+			expAllowed: false,
+		},
+		{
+			name:       "no-group-in-acnp",
+			egress:     []secv1alpha1.Rule{{To: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}}}},
+			namespaced: false,
+			expAllowed: true,
+		},
+	}
+	v := NewNetworkPolicyValidator(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := v.validateAntreaPolicy(admv1.Create, "", nil, nil, tt.egress, tt.namespaced)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	"github.com/vmware-tanzu/antrea/pkg/util/ip"
+)
+
+const (
+	reasonIPBlockAggregated        = "IPBlockAggregated"
+	reasonIPBlockFlowLimitExceeded = "IPBlockFlowLimitExceeded"
+)
+
+// ipBlockFlowCount returns the number of address matches the antrea-agents
+// install for the IPBlocks of a K8s NetworkPolicy peer. The except CIDRs of an
+// IPBlock are not matched directly: the CIDR of the IPBlock is split into the
+// CIDRs which are not excepted, whose number grows with the number of except
+// CIDRs and the difference between their prefix length and the one of the CIDR.
+func ipBlockFlowCount(ipBlocks []controlplane.IPBlock) int {
+	count := 0
+	for i := range ipBlocks {
+		count += len(ipBlockToCIDRs(&ipBlocks[i]))
+	}
+	return count
+}
+
+// ipBlockToCIDRs returns the CIDRs of the IPBlock which are not excepted.
+func ipBlockToCIDRs(ipBlock *controlplane.IPBlock) []*net.IPNet {
+	cidr := controlplaneIPNetToNetIPNet(&ipBlock.CIDR)
+	if len(ipBlock.Except) == 0 {
+		return []*net.IPNet{cidr}
+	}
+	exceptCIDRs := make([]*net.IPNet, 0, len(ipBlock.Except))
+	for i := range ipBlock.Except {
+		exceptCIDRs = append(exceptCIDRs, controlplaneIPNetToNetIPNet(&ipBlock.Except[i]))
+	}
+	diffCIDRs, err := ip.DiffFromCIDRs(cidr, exceptCIDRs)
+	if err != nil {
+		// The antrea-agents ignore the IPBlocks which cannot be split either.
+		return nil
+	}
+	return diffCIDRs
+}
+
+func controlplaneIPNetToNetIPNet(ipNet *controlplane.IPNet) *net.IPNet {
+	addr := net.IP(ipNet.IP)
+	bits := 8 * net.IPv6len
+	if ip4 := addr.To4(); ip4 != nil {
+		addr = ip4
+		bits = 8 * net.IPv4len
+	}
+	return &net.IPNet{IP: addr, Mask: net.CIDRMask(int(ipNet.PrefixLength), bits)}
+}
+
+// aggregateIPBlocks compiles the IPBlocks into IPBlocks without except CIDRs,
+// whose CIDRs are the aggregated prefixes of the addresses they match.
+func aggregateIPBlocks(ipBlocks []controlplane.IPBlock) []controlplane.IPBlock {
+	var cidrs []*net.IPNet
+	for i := range ipBlocks {
+		cidrs = append(cidrs, ipBlockToCIDRs(&ipBlocks[i])...)
+	}
+	aggregated := ip.AggregateCIDRs(cidrs)
+	result := make([]controlplane.IPBlock, 0, len(aggregated))
+	for _, c := range aggregated {
+		prefixLength, _ := c.Mask.Size()
+		result = append(result, controlplane.IPBlock{
+			CIDR: controlplane.IPNet{IP: controlplane.IPAddress(c.IP), PrefixLength: int32(prefixLength)},
+		})
+	}
+	return result
+}
+
+func rulePeer(rule *controlplane.NetworkPolicyRule) *controlplane.NetworkPolicyPeer {
+	if rule.Direction == controlplane.DirectionIn {
+		return &rule.From
+	}
+	return &rule.To
+}
+
+// limitIPBlockFlows protects the OVS flow tables of the antrea-agents against
+// the expansion of the IPBlocks of a K8s NetworkPolicy. When the IPBlocks of
+// the rules would generate more flows than ipBlockFlowLimit, they are compiled
+// into aggregated prefixes. If that's not enough, the IPBlocks of the rules
+// generating the most flows are removed until the limit is met: as the rules of
+// K8s NetworkPolicies only allow traffic, the traffic from or to the removed
+// IPBlocks is dropped, as the isolation of the Pods is still enforced. Both
+// cases are reported with an Event on the NetworkPolicy. The rules are
+// modified in place.
+func (n *NetworkPolicyController) limitIPBlockFlows(np *networkingv1.NetworkPolicy, rules []controlplane.NetworkPolicyRule) {
+	if n.ipBlockFlowLimit <= 0 {
+		return
+	}
+	counts := make([]int, len(rules))
+	total := 0
+	for i := range rules {
+		counts[i] = ipBlockFlowCount(rulePeer(&rules[i]).IPBlocks)
+		total += counts[i]
+	}
+	if total <= n.ipBlockFlowLimit {
+		return
+	}
+	originalTotal := total
+	total = 0
+	for i := range rules {
+		peer := rulePeer(&rules[i])
+		if len(peer.IPBlocks) == 0 {
+			continue
+		}
+		peer.IPBlocks = aggregateIPBlocks(peer.IPBlocks)
+		counts[i] = len(peer.IPBlocks)
+		total += counts[i]
+	}
+	if total <= n.ipBlockFlowLimit {
+		klog.Infof("Aggregated the IPBlocks of NetworkPolicy %s/%s from %d to %d flows", np.Namespace, np.Name, originalTotal, total)
+		n.recordEvent(np, corev1.EventTypeNormal, reasonIPBlockAggregated,
+			"IPBlocks would generate %d flows, above the limit of %d; aggregated them into %d flows", originalTotal, n.ipBlockFlowLimit, total)
+		return
+	}
+	aggregatedTotal := total
+	indexes := make([]int, len(rules))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return counts[indexes[i]] > counts[indexes[j]]
+	})
+	denied := map[int]bool{}
+	for _, i := range indexes {
+		if total <= n.ipBlockFlowLimit {
+			break
+		}
+		rulePeer(&rules[i]).IPBlocks = nil
+		total -= counts[i]
+		denied[i] = true
+	}
+	// Name the denied rules after their position in the ingress or egress
+	// rules of the NetworkPolicy, e.g. "ingress[0]".
+	var deniedRules []string
+	var ingressIndex, egressIndex int
+	for i := range rules {
+		var name string
+		if rules[i].Direction == controlplane.DirectionIn {
+			name = fmt.Sprintf("ingress[%d]", ingressIndex)
+			ingressIndex++
+		} else {
+			name = fmt.Sprintf("egress[%d]", egressIndex)
+			egressIndex++
+		}
+		if denied[i] {
+			deniedRules = append(deniedRules, name)
+		}
+	}
+	klog.Warningf("The IPBlocks of rules %v of NetworkPolicy %s/%s are not enforced as they exceed the limit of %d flows", deniedRules, np.Namespace, np.Name, n.ipBlockFlowLimit)
+	n.recordEvent(np, corev1.EventTypeWarning, reasonIPBlockFlowLimitExceeded,
+		"IPBlocks would generate %d flows, %d after aggregation, above the limit of %d; the IPBlocks of rules %s are not enforced and the traffic they allow is dropped",
+		originalTotal, aggregatedTotal, n.ipBlockFlowLimit, strings.Join(deniedRules, ", "))
+}
+
+func (n *NetworkPolicyController) recordEvent(np *networkingv1.NetworkPolicy, eventType, reason, messageFmt string, args ...interface{}) {
+	if n.eventRecorder == nil {
+		return
+	}
+	n.eventRecorder.Eventf(np, eventType, reason, messageFmt, args...)
+}
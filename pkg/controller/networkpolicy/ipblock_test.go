@@ -0,0 +1,125 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+)
+
+func newIPBlockPolicy(ingressIPBlocks ...[]networkingv1.IPBlock) *networkingv1.NetworkPolicy {
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "nsA", Name: "npA", UID: "uidA"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+		},
+	}
+	for _, ipBlocks := range ingressIPBlocks {
+		var peers []networkingv1.NetworkPolicyPeer
+		for i := range ipBlocks {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &ipBlocks[i]})
+		}
+		np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{From: peers})
+	}
+	return np
+}
+
+func TestIPBlockFlowCount(t *testing.T) {
+	ipBlock, err := toAntreaIPBlock(&networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.0.0.1/32"}})
+	require.NoError(t, err)
+	assert.Equal(t, 24, ipBlockFlowCount([]controlplane.IPBlock{*ipBlock}))
+	ipBlock, err = toAntreaIPBlock(&networkingv1.IPBlock{CIDR: "10.0.0.0/8"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, ipBlockFlowCount([]controlplane.IPBlock{*ipBlock}))
+}
+
+func TestLimitIPBlockFlows(t *testing.T) {
+	// 64 consecutive /32 CIDRs, which are aggregated into 10.0.0.0/26.
+	var consecutiveIPBlocks []networkingv1.IPBlock
+	for i := 0; i < 64; i++ {
+		consecutiveIPBlocks = append(consecutiveIPBlocks, networkingv1.IPBlock{CIDR: fmt.Sprintf("10.0.0.%d/32", i)})
+	}
+	// 32 except /32 CIDRs scattered in a /8, which generate hundreds of CIDRs.
+	var excepts []string
+	for i := 0; i < 32; i++ {
+		excepts = append(excepts, fmt.Sprintf("20.%d.0.1/32", i*8))
+	}
+	exceptIPBlocks := []networkingv1.IPBlock{{CIDR: "20.0.0.0/8", Except: excepts}}
+
+	tests := []struct {
+		name                  string
+		policy                *networkingv1.NetworkPolicy
+		limit                 int
+		expectedIPBlockCounts []int
+		expectedEventReason   string
+	}{
+		{
+			name:                  "below limit",
+			policy:                newIPBlockPolicy(consecutiveIPBlocks),
+			limit:                 100,
+			expectedIPBlockCounts: []int{64},
+		},
+		{
+			name:                  "limit disabled",
+			policy:                newIPBlockPolicy(consecutiveIPBlocks, exceptIPBlocks),
+			limit:                 0,
+			expectedIPBlockCounts: []int{64, 1},
+		},
+		{
+			name:                  "aggregated",
+			policy:                newIPBlockPolicy(consecutiveIPBlocks),
+			limit:                 10,
+			expectedIPBlockCounts: []int{1},
+			expectedEventReason:   reasonIPBlockAggregated,
+		},
+		{
+			name:                  "denied",
+			policy:                newIPBlockPolicy(consecutiveIPBlocks, exceptIPBlocks),
+			limit:                 10,
+			expectedIPBlockCounts: []int{1, 0},
+			expectedEventReason:   reasonIPBlockFlowLimitExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newController()
+			recorder := record.NewFakeRecorder(10)
+			c.ipBlockFlowLimit = tt.limit
+			c.eventRecorder = recorder
+
+			policy := c.processNetworkPolicy(tt.policy)
+			require.Len(t, policy.Rules, len(tt.expectedIPBlockCounts))
+			for i, count := range tt.expectedIPBlockCounts {
+				assert.Len(t, policy.Rules[i].From.IPBlocks, count)
+			}
+			if tt.expectedEventReason == "" {
+				assert.Len(t, recorder.Events, 0)
+			} else {
+				require.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, tt.expectedEventReason)
+			}
+		})
+	}
+	// The shared peer matching all addresses must not be modified.
+	assert.Len(t, matchAllPeer.IPBlocks, 1)
+}
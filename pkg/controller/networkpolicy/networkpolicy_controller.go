@@ -34,6 +34,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -43,7 +44,7 @@ import (
 	corelisters "k8s.io/client-go/listers/core/v1"
 	networkinglisters "k8s.io/client-go/listers/networking/v1"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/util/workqueue"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
@@ -58,6 +59,7 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/controller/metrics"
 	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
 	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+	"github.com/vmware-tanzu/antrea/pkg/controller/watermark"
 	"github.com/vmware-tanzu/antrea/pkg/features"
 )
 
@@ -69,7 +71,8 @@ const (
 	// How long to wait before retrying the processing of a NetworkPolicy change.
 	minRetryDelay = 5 * time.Second
 	maxRetryDelay = 300 * time.Second
-	// Default number of workers processing a NetworkPolicy change.
+	// Default number of workers processing a NetworkPolicy change. Each
+	// worker consumes one shard of a work queue.
 	defaultWorkers = 4
 	// Default rule priority for K8s NetworkPolicy rules.
 	defaultRulePriority = -1
@@ -77,6 +80,15 @@ const (
 	TierIndex = "tier"
 	// PriorityIndex is used to index Tiers by their priorities.
 	PriorityIndex = "priority"
+	// GroupIndex is used to index Antrea NetworkPolicies by the keys of the
+	// Groups referenced in their rules.
+	GroupIndex = "group"
+	// ChildGroupIndex is used to index Groups by the keys of their child
+	// Groups.
+	ChildGroupIndex = "childGroup"
+	// ServiceIndex is used to index Groups and Antrea Policies by the keys of
+	// the Services they reference.
+	ServiceIndex = "service"
 )
 
 var (
@@ -127,6 +139,20 @@ type NetworkPolicyController struct {
 	// namespaceListerSynced is a function which returns true if the Namespace shared informer has been synced at least once.
 	namespaceListerSynced cache.InformerSynced
 
+	nodeInformer coreinformers.NodeInformer
+	// nodeLister is able to list/get Nodes and is populated by the shared informer passed to
+	// NewNetworkPolicyController.
+	nodeLister corelisters.NodeLister
+	// nodeListerSynced is a function which returns true if the Node shared informer has been synced at least once.
+	nodeListerSynced cache.InformerSynced
+
+	serviceInformer coreinformers.ServiceInformer
+	// serviceLister is able to list/get Services and is populated by the shared informer passed to
+	// NewNetworkPolicyController.
+	serviceLister corelisters.ServiceLister
+	// serviceListerSynced is a function which returns true if the Service shared informer has been synced at least once.
+	serviceListerSynced cache.InformerSynced
+
 	externalEntityInformer corev1a1informers.ExternalEntityInformer
 	// externalEntityLister is able to list/get ExternalEntities and is populated by the shared informer passed to
 	// NewNetworkPolicyController.
@@ -162,6 +188,13 @@ type NetworkPolicyController struct {
 	// tierListerSynced is a function which returns true if the Tiers shared informer has been synced at least once.
 	tierListerSynced cache.InformerSynced
 
+	groupInformer corev1a1informers.GroupInformer
+	// groupLister is able to list/get Groups and is populated by the shared informer passed to
+	// NewNetworkPolicyController.
+	groupLister corev1a1listers.GroupLister
+	// groupListerSynced is a function which returns true if the Groups shared informer has been synced at least once.
+	groupListerSynced cache.InformerSynced
+
 	// addressGroupStore is the storage where the populated Address Groups are stored.
 	addressGroupStore storage.Interface
 	// appliedToGroupStore is the storage where the populated AppliedTo Groups are stored.
@@ -170,19 +203,47 @@ type NetworkPolicyController struct {
 	internalNetworkPolicyStore storage.Interface
 
 	// appliedToGroupQueue maintains the networkpolicy.AppliedToGroup objects that
-	// need to be synced.
-	appliedToGroupQueue workqueue.RateLimitingInterface
+	// need to be synced, sharded by AppliedToGroup key.
+	appliedToGroupQueue *shardedQueue
 	// addressGroupQueue maintains the networkpolicy.AddressGroup objects that
-	// need to be synced.
-	addressGroupQueue workqueue.RateLimitingInterface
+	// need to be synced, sharded by AddressGroup key. It holds both full sync
+	// requests and span-only updates, so that they are serialized per group.
+	addressGroupQueue *shardedQueue
 	// internalNetworkPolicyQueue maintains the networkpolicy.NetworkPolicy objects that
-	// need to be synced.
-	internalNetworkPolicyQueue workqueue.RateLimitingInterface
+	// need to be synced, sharded by NetworkPolicy key.
+	internalNetworkPolicyQueue *shardedQueue
 
 	// internalNetworkPolicyMutex protects the internalNetworkPolicyStore from
 	// concurrent access during updates to the internal NetworkPolicy object.
 	internalNetworkPolicyMutex sync.RWMutex
 
+	// clock is used to evaluate the Schedule of Antrea Policy rules. It can be
+	// overridden in tests.
+	clock clock.Clock
+	// schedules caches the parsed Schedules of the Antrea Policy rules.
+	schedules *scheduleCache
+
+	// watermarkMonitor reports whether the controller is in degraded mode, in
+	// which the re-evaluation of rule schedules is slowed down. It can be nil.
+	watermarkMonitor *watermark.Monitor
+	// lastScheduleSync is the last time the scheduled rules were re-evaluated.
+	lastScheduleSync time.Time
+
+	// exemptNamespaces are the Namespaces whose Pods and ExternalEntities are
+	// never selected by the AppliedTo of Antrea-native policies.
+	exemptNamespaces sets.String
+	// defaultDenyNamespaces are the Namespaces to which an implicit
+	// default-deny policy is applied, unless overridden by the
+	// defaultDenyAnnotation of the Namespace.
+	defaultDenyNamespaces sets.String
+	// ipBlockFlowLimit is the number of flows the IPBlocks of a K8s
+	// NetworkPolicy may generate before being aggregated, and then denied.
+	// 0 disables the limit.
+	ipBlockFlowLimit int
+	// eventRecorder records the Events reporting the compilation of the
+	// policies. It may be nil.
+	eventRecorder record.EventRecorder
+
 	// heartbeatCh is an internal channel for testing. It's used to know whether all tasks have been
 	// processed, and to count executions of each function.
 	heartbeatCh chan heartbeat
@@ -193,19 +254,35 @@ type heartbeat struct {
 	timestamp time.Time
 }
 
+// addressGroupSpanKey is the item enqueued in addressGroupQueue when only the
+// Node span of an AddressGroup needs to be recalculated.
+type addressGroupSpanKey string
+
+func (k addressGroupSpanKey) shardKey() string {
+	return string(k)
+}
+
 // NewNetworkPolicyController returns a new *NetworkPolicyController.
 func NewNetworkPolicyController(kubeClient clientset.Interface,
 	crdClient versioned.Interface,
 	podInformer coreinformers.PodInformer,
 	namespaceInformer coreinformers.NamespaceInformer,
+	nodeInformer coreinformers.NodeInformer,
+	serviceInformer coreinformers.ServiceInformer,
 	externalEntityInformer corev1a1informers.ExternalEntityInformer,
 	networkPolicyInformer networkinginformers.NetworkPolicyInformer,
 	cnpInformer secinformers.ClusterNetworkPolicyInformer,
 	anpInformer secinformers.NetworkPolicyInformer,
 	tierInformer secinformers.TierInformer,
+	groupInformer corev1a1informers.GroupInformer,
 	addressGroupStore storage.Interface,
 	appliedToGroupStore storage.Interface,
-	internalNetworkPolicyStore storage.Interface) *NetworkPolicyController {
+	internalNetworkPolicyStore storage.Interface,
+	watermarkMonitor *watermark.Monitor,
+	exemptNamespaces []string,
+	defaultDenyNamespaces []string,
+	ipBlockFlowLimit int,
+	eventRecorder record.EventRecorder) *NetworkPolicyController {
 	n := &NetworkPolicyController{
 		kubeClient:                 kubeClient,
 		crdClient:                  crdClient,
@@ -224,9 +301,16 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 		addressGroupStore:          addressGroupStore,
 		appliedToGroupStore:        appliedToGroupStore,
 		internalNetworkPolicyStore: internalNetworkPolicyStore,
-		appliedToGroupQueue:        workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "appliedToGroup"),
-		addressGroupQueue:          workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "addressGroup"),
-		internalNetworkPolicyQueue: workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "internalNetworkPolicy"),
+		appliedToGroupQueue:        newShardedQueue("appliedToGroup", defaultWorkers),
+		addressGroupQueue:          newShardedQueue("addressGroup", defaultWorkers),
+		internalNetworkPolicyQueue: newShardedQueue("internalNetworkPolicy", defaultWorkers),
+		clock:                      clock.RealClock{},
+		schedules:                  newScheduleCache(),
+		watermarkMonitor:           watermarkMonitor,
+		exemptNamespaces:           sets.NewString(exemptNamespaces...),
+		defaultDenyNamespaces:      sets.NewString(defaultDenyNamespaces...),
+		ipBlockFlowLimit:           ipBlockFlowLimit,
+		eventRecorder:              eventRecorder,
 	}
 	// Add handlers for Pod events.
 	podInformer.Informer().AddEventHandlerWithResyncPeriod(
@@ -266,6 +350,9 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 		n.tierInformer = tierInformer
 		n.tierLister = tierInformer.Lister()
 		n.tierListerSynced = tierInformer.Informer().HasSynced
+		n.groupInformer = groupInformer
+		n.groupLister = groupInformer.Lister()
+		n.groupListerSynced = groupInformer.Informer().HasSynced
 		tierInformer.Informer().AddIndexers(
 			cache.Indexers{
 				PriorityIndex: func(obj interface{}) ([]string, error) {
@@ -277,6 +364,13 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 				},
 			},
 		)
+		tierInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    n.addTier,
+				UpdateFunc: n.updateTier,
+			},
+			resyncPeriod,
+		)
 		cnpInformer.Informer().AddIndexers(
 			cache.Indexers{
 				TierIndex: func(obj interface{}) ([]string, error) {
@@ -286,6 +380,7 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 					}
 					return []string{cnp.Spec.Tier}, nil
 				},
+				ServiceIndex: policyServiceIndexFunc,
 			},
 		)
 		cnpInformer.Informer().AddEventHandlerWithResyncPeriod(
@@ -305,6 +400,8 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 					}
 					return []string{anp.Spec.Tier}, nil
 				},
+				GroupIndex:   groupIndexFunc,
+				ServiceIndex: policyServiceIndexFunc,
 			},
 		)
 		anpInformer.Informer().AddEventHandlerWithResyncPeriod(
@@ -323,6 +420,44 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 			},
 			resyncPeriod,
 		)
+		groupInformer.Informer().AddIndexers(
+			cache.Indexers{
+				ChildGroupIndex: childGroupIndexFunc,
+				ServiceIndex:    serviceIndexFunc,
+			},
+		)
+		groupInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    n.addGroup,
+				UpdateFunc: n.updateGroup,
+				DeleteFunc: n.deleteGroup,
+			},
+			resyncPeriod,
+		)
+		// Services can only be referenced by Groups and Antrea Policies.
+		n.serviceInformer = serviceInformer
+		n.serviceLister = serviceInformer.Lister()
+		n.serviceListerSynced = serviceInformer.Informer().HasSynced
+		serviceInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    n.addService,
+				UpdateFunc: n.updateService,
+				DeleteFunc: n.deleteService,
+			},
+			resyncPeriod,
+		)
+		// Nodes can only be selected by the AppliedTo of Antrea ClusterNetworkPolicies.
+		n.nodeInformer = nodeInformer
+		n.nodeLister = nodeInformer.Lister()
+		n.nodeListerSynced = nodeInformer.Informer().HasSynced
+		nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    n.addNode,
+				UpdateFunc: n.updateNode,
+				DeleteFunc: n.deleteNode,
+			},
+			resyncPeriod,
+		)
 	}
 	return n
 }
@@ -411,7 +546,12 @@ func generateNormalizedName(namespace string, podSelector, nsSelector, eeSelecto
 
 // createAppliedToGroup creates an AppliedToGroup object in store if it is not created already.
 func (n *NetworkPolicyController) createAppliedToGroup(npNsName string, pSel, nSel, eSel *metav1.LabelSelector) string {
-	groupSelector := toGroupSelector(npNsName, pSel, nSel, eSel)
+	return n.createAppliedToGroupForSelector(toGroupSelector(npNsName, pSel, nSel, eSel))
+}
+
+// createAppliedToGroupForSelector creates an AppliedToGroup object with the
+// GroupSelector in store if it is not created already.
+func (n *NetworkPolicyController) createAppliedToGroupForSelector(groupSelector *antreatypes.GroupSelector) string {
 	appliedToGroupUID := getNormalizedUID(groupSelector.NormalizedName)
 	// Get or create a AppliedToGroup for the generated UID.
 	// Ignoring returned error (here and elsewhere in this file) as with the
@@ -437,15 +577,18 @@ func (n *NetworkPolicyController) createAppliedToGroup(npNsName string, pSel, nS
 // match any of the selector criteria present in the GroupSelector.
 func (n *NetworkPolicyController) labelsMatchGroupSelector(obj metav1.Object, ns *v1.Namespace, sel *antreatypes.GroupSelector) bool {
 	objSelector := sel.PodSelector
+	objLabels := labels.Set(obj.GetLabels())
 	if _, ok := obj.(*v1alpha1.ExternalEntity); ok {
 		objSelector = sel.ExternalEntitySelector
+	} else if pod, ok := obj.(*v1.Pod); ok {
+		objLabels = podLabels(pod)
 	}
 	if sel.Namespace != "" {
 		if sel.Namespace != obj.GetNamespace() {
 			// Pods or ExternalEntities must be matched within the same Namespace.
 			return false
 		}
-		if objSelector != nil && objSelector.Matches(labels.Set(obj.GetLabels())) {
+		if objSelector != nil && objSelector.Matches(objLabels) {
 			// podSelector or externalEntitySelector matches the ExternalEntity or Pod's labels.
 			return true
 		}
@@ -458,7 +601,7 @@ func (n *NetworkPolicyController) labelsMatchGroupSelector(obj metav1.Object, ns
 			// Pod's Namespace do not match namespaceSelector.
 			return false
 		}
-		if !objSelector.Matches(labels.Set(obj.GetLabels())) {
+		if !objSelector.Matches(objLabels) {
 			// ExternalEntity or Pod's Namespace matches namespaceSelector but
 			// labels do not match the podSelector or externalEntitySelector.
 			return false
@@ -479,7 +622,7 @@ func (n *NetworkPolicyController) labelsMatchGroupSelector(obj metav1.Object, ns
 	} else if objSelector != nil {
 		// Selector only has a PodSelector/ExternalEntitySelector and no sel.Namespace.
 		// Pods/ExternalEntities must be matched from all Namespaces.
-		if !objSelector.Matches(labels.Set(obj.GetLabels())) {
+		if !objSelector.Matches(objLabels) {
 			// pod/ee labels do not match PodSelector/ExternalEntitySelector.
 			return false
 		}
@@ -653,6 +796,8 @@ func (n *NetworkPolicyController) processNetworkPolicy(np *networkingv1.NetworkP
 		})
 	}
 
+	n.limitIPBlockFlows(np, rules)
+
 	// Traffic in a direction must be isolated if Spec.PolicyTypes specify it explicitly.
 	var ingressIsolated, egressIsolated bool
 	for _, policyType := range np.Spec.PolicyTypes {
@@ -1035,11 +1180,15 @@ func (n *NetworkPolicyController) deleteExternalEntity(old interface{}) {
 }
 
 // addNamespace retrieves all AddressGroups which match the Namespace
-// labels and enqueues the group keys for further processing.
+// labels and enqueues the group keys for further processing. It also creates
+// the implicit default-deny policy of the Namespace if it's enabled.
 func (n *NetworkPolicyController) addNamespace(obj interface{}) {
 	defer n.heartbeat("addNamespace")
 	namespace := obj.(*v1.Namespace)
 	klog.V(2).Infof("Processing Namespace %s ADD event, labels: %v", namespace.Name, namespace.Labels)
+	if n.defaultDenyEnabled(namespace) {
+		n.addDefaultDenyPolicy(namespace)
+	}
 	addressGroupKeys := n.filterAddressGroupsForNamespace(namespace)
 	for group := range addressGroupKeys {
 		n.enqueueAddressGroup(group)
@@ -1047,12 +1196,19 @@ func (n *NetworkPolicyController) addNamespace(obj interface{}) {
 }
 
 // updateNamespace retrieves all AddressGroups which match the current and old
-// Namespace labels and enqueues the group keys for further processing.
+// Namespace labels and enqueues the group keys for further processing. It also
+// creates or deletes the implicit default-deny policy of the Namespace when it's
+// enabled or disabled.
 func (n *NetworkPolicyController) updateNamespace(oldObj, curObj interface{}) {
 	defer n.heartbeat("updateNamespace")
 	oldNamespace := oldObj.(*v1.Namespace)
 	curNamespace := curObj.(*v1.Namespace)
 	klog.V(2).Infof("Processing Namespace %s UPDATE event, labels: %v", curNamespace.Name, curNamespace.Labels)
+	if n.defaultDenyEnabled(curNamespace) {
+		n.addDefaultDenyPolicy(curNamespace)
+	} else if n.defaultDenyEnabled(oldNamespace) {
+		n.deleteDefaultDenyPolicy(curNamespace)
+	}
 	// No need to trigger processing of groups if there is no change in the
 	// Namespace labels.
 	if labels.Equals(labels.Set(oldNamespace.Labels), labels.Set(curNamespace.Labels)) {
@@ -1072,7 +1228,8 @@ func (n *NetworkPolicyController) updateNamespace(oldObj, curObj interface{}) {
 }
 
 // deleteNamespace retrieves all AddressGroups which match the Namespace's
-// labels and enqueues the group keys for further processing.
+// labels and enqueues the group keys for further processing. It also deletes
+// the implicit default-deny policy of the Namespace.
 func (n *NetworkPolicyController) deleteNamespace(old interface{}) {
 	namespace, ok := old.(*v1.Namespace)
 	if !ok {
@@ -1090,6 +1247,7 @@ func (n *NetworkPolicyController) deleteNamespace(old interface{}) {
 	defer n.heartbeat("deleteNamespace")
 
 	klog.V(2).Infof("Processing Namespace %s DELETE event, labels: %v", namespace.Name, namespace.Labels)
+	n.deleteDefaultDenyPolicy(namespace)
 	// Find groups matching deleted Namespace's labels and enqueue them
 	// for further processing.
 	addressGroupKeys := n.filterAddressGroupsForNamespace(namespace)
@@ -1159,6 +1317,16 @@ func (n *NetworkPolicyController) enqueueAddressGroup(key string) {
 	metrics.LengthAddressGroupQueue.Set(float64(n.addressGroupQueue.Len()))
 }
 
+// enqueueAddressGroupSpan enqueues a recalculation of the Node span of the
+// AddressGroup. Unlike enqueueAddressGroup, the group members are not
+// recomputed, and multiple span changes of the same group which are queued
+// before it is processed are batched into a single update.
+func (n *NetworkPolicyController) enqueueAddressGroupSpan(key string) {
+	klog.V(4).Infof("Adding span update of %s to AddressGroup queue", key)
+	n.addressGroupQueue.Add(addressGroupSpanKey(key))
+	metrics.LengthAddressGroupQueue.Set(float64(n.addressGroupQueue.Len()))
+}
+
 func (n *NetworkPolicyController) enqueueInternalNetworkPolicy(key string) {
 	klog.V(4).Infof("Adding new key %s to internal NetworkPolicy queue", key)
 	n.internalNetworkPolicyQueue.Add(key)
@@ -1189,33 +1357,65 @@ func (n *NetworkPolicyController) Run(stopCh <-chan struct{}) {
 			klog.Error("Unable to sync ANP caches for NetworkPolicy controller")
 			return
 		}
+		if !cache.WaitForCacheSync(stopCh, n.groupListerSynced) {
+			klog.Error("Unable to sync Group caches for NetworkPolicy controller")
+			return
+		}
+		if !cache.WaitForCacheSync(stopCh, n.serviceListerSynced) {
+			klog.Error("Unable to sync Service caches for NetworkPolicy controller")
+			return
+		}
+		if !cache.WaitForCacheSync(stopCh, n.nodeListerSynced) {
+			klog.Error("Unable to sync Node caches for NetworkPolicy controller")
+			return
+		}
 	}
 	klog.Info("Caches are synced for NetworkPolicy controller")
 
-	for i := 0; i < defaultWorkers; i++ {
-		go wait.Until(n.appliedToGroupWorker, time.Second, stopCh)
-		go wait.Until(n.addressGroupWorker, time.Second, stopCh)
-		go wait.Until(n.internalNetworkPolicyWorker, time.Second, stopCh)
+	// Each shard is consumed by a single worker, so that the computation of a
+	// given group is never performed concurrently.
+	for i := 0; i < n.appliedToGroupQueue.NumShards(); i++ {
+		go wait.Until(func(shard int) func() {
+			return func() { n.appliedToGroupWorker(shard) }
+		}(i), time.Second, stopCh)
+	}
+	for i := 0; i < n.addressGroupQueue.NumShards(); i++ {
+		go wait.Until(func(shard int) func() {
+			return func() { n.addressGroupWorker(shard) }
+		}(i), time.Second, stopCh)
+	}
+	for i := 0; i < n.internalNetworkPolicyQueue.NumShards(); i++ {
+		go wait.Until(func(shard int) func() {
+			return func() { n.internalNetworkPolicyWorker(shard) }
+		}(i), time.Second, stopCh)
+	}
+	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
+		go wait.Until(n.syncScheduledRules, scheduleSyncPeriod, stopCh)
 	}
 	<-stopCh
 }
 
-func (n *NetworkPolicyController) appliedToGroupWorker() {
-	for n.processNextAppliedToGroupWorkItem() {
+// GetQueueLength returns the total number of items waiting in the work queues.
+func (n *NetworkPolicyController) GetQueueLength() int {
+	return n.appliedToGroupQueue.Len() + n.addressGroupQueue.Len() + n.internalNetworkPolicyQueue.Len()
+}
+
+func (n *NetworkPolicyController) appliedToGroupWorker(shard int) {
+	for n.processNextAppliedToGroupWorkItem(shard) {
 		metrics.OpsAppliedToGroupProcessed.Inc()
 		metrics.LengthAppliedToGroupQueue.Set(float64(n.appliedToGroupQueue.Len()))
 	}
 }
 
-func (n *NetworkPolicyController) addressGroupWorker() {
-	for n.processNextAddressGroupWorkItem() {
+func (n *NetworkPolicyController) addressGroupWorker(shard int) {
+	for n.processNextAddressGroupWorkItem(shard) {
 		metrics.OpsAddressGroupProcessed.Inc()
 		metrics.LengthAddressGroupQueue.Set(float64(n.addressGroupQueue.Len()))
 	}
 }
 
-func (n *NetworkPolicyController) internalNetworkPolicyWorker() {
-	for n.processNextInternalNetworkPolicyWorkItem() {
+func (n *NetworkPolicyController) internalNetworkPolicyWorker(shard int) {
+	for n.processNextInternalNetworkPolicyWorkItem(shard) {
 		metrics.OpsInternalNetworkPolicyProcessed.Inc()
 		metrics.LengthInternalNetworkPolicyQueue.Set(float64(n.internalNetworkPolicyQueue.Len()))
 	}
@@ -1229,9 +1429,9 @@ func (n *NetworkPolicyController) internalNetworkPolicyWorker() {
 // removed from the queue until we get notify of a new change. This function
 // return false if and only if the work queue was shutdown (no more items will
 // be processed).
-func (n *NetworkPolicyController) processNextInternalNetworkPolicyWorkItem() bool {
+func (n *NetworkPolicyController) processNextInternalNetworkPolicyWorkItem(shard int) bool {
 	defer n.heartbeat("processNextInternalNetworkPolicyWorkItem")
-	key, quit := n.internalNetworkPolicyQueue.Get()
+	key, quit := n.internalNetworkPolicyQueue.Get(shard)
 	if quit {
 		return false
 	}
@@ -1261,15 +1461,21 @@ func (n *NetworkPolicyController) processNextInternalNetworkPolicyWorkItem() boo
 // successful, the AddressGroup is removed from the queue until we get notify
 // of a new change. This function return false if and only if the work queue
 // was shutdown (no more items will be processed).
-func (n *NetworkPolicyController) processNextAddressGroupWorkItem() bool {
+func (n *NetworkPolicyController) processNextAddressGroupWorkItem(shard int) bool {
 	defer n.heartbeat("processNextAddressGroupWorkItem")
-	key, quit := n.addressGroupQueue.Get()
+	key, quit := n.addressGroupQueue.Get(shard)
 	if quit {
 		return false
 	}
 	defer n.addressGroupQueue.Done(key)
 
-	err := n.syncAddressGroup(key.(string))
+	var err error
+	switch k := key.(type) {
+	case addressGroupSpanKey:
+		err = n.syncAddressGroupSpan(string(k))
+	default:
+		err = n.syncAddressGroup(key.(string))
+	}
 	if err != nil {
 		// Put the item back on the workqueue to handle any transient errors.
 		n.addressGroupQueue.AddRateLimited(key)
@@ -1289,9 +1495,9 @@ func (n *NetworkPolicyController) processNextAddressGroupWorkItem() bool {
 // syncAppliedToGroup is successful, the AppliedToGroup is removed from the
 // queue until we get notify of a new change. This function return false if
 // and only if the work queue was shutdown (no more items will be processed).
-func (n *NetworkPolicyController) processNextAppliedToGroupWorkItem() bool {
+func (n *NetworkPolicyController) processNextAppliedToGroupWorkItem(shard int) bool {
 	defer n.heartbeat("processNextAppliedToGroupWorkItem")
-	key, quit := n.appliedToGroupQueue.Get()
+	key, quit := n.appliedToGroupQueue.Get(shard)
 	if quit {
 		return false
 	}
@@ -1368,6 +1574,46 @@ func (n *NetworkPolicyController) syncAddressGroup(key string) error {
 	return nil
 }
 
+// syncAddressGroupSpan recalculates the Node span of the AddressGroup from the
+// internal NetworkPolicies which refer to it, while keeping its members.
+func (n *NetworkPolicyController) syncAddressGroupSpan(key string) error {
+	startTime := time.Now()
+	defer func() {
+		d := time.Since(startTime)
+		metrics.DurationAddressGroupSyncing.Observe(float64(d.Milliseconds()))
+		klog.V(2).Infof("Finished syncing span of AddressGroup %s. (%v)", key, d)
+	}()
+	nps, err := n.internalNetworkPolicyStore.GetByIndex(store.AddressGroupIndex, key)
+	if err != nil {
+		return fmt.Errorf("unable to filter internal NetworkPolicies for AddressGroup %s: %v", key, err)
+	}
+	addressGroupObj, found, _ := n.addressGroupStore.Get(key)
+	if !found {
+		klog.V(2).Infof("AddressGroup %s not found.", key)
+		return nil
+	}
+	addressGroup := addressGroupObj.(*antreatypes.AddressGroup)
+	addrGroupNodeNames := sets.String{}
+	for _, internalNPObj := range nps {
+		internalNP := internalNPObj.(*antreatypes.NetworkPolicy)
+		addrGroupNodeNames = addrGroupNodeNames.Union(internalNP.SpanMeta.NodeNames)
+	}
+	if addrGroupNodeNames.Equal(addressGroup.SpanMeta.NodeNames) {
+		return nil
+	}
+	updatedAddressGroup := &antreatypes.AddressGroup{
+		Name:         addressGroup.Name,
+		UID:          addressGroup.UID,
+		Selector:     addressGroup.Selector,
+		Pods:         addressGroup.Pods,
+		GroupMembers: addressGroup.GroupMembers,
+		SpanMeta:     antreatypes.SpanMeta{NodeNames: addrGroupNodeNames},
+	}
+	klog.V(2).Infof("Updating span of existing AddressGroup %s to %d Nodes", key, addrGroupNodeNames.Len())
+	n.addressGroupStore.Update(updatedAddressGroup)
+	return nil
+}
+
 // podToMemberPod is util function to convert a Pod to a GroupMemberPod type.
 // A controlplane.NamedPort item will be set in the GroupMemberPod, only if the
 // Pod contains a Port with the name field set. Depending on the input, the
@@ -1432,7 +1678,7 @@ func (n *NetworkPolicyController) processSelector(groupSelector antreatypes.Grou
 	if groupSelector.Namespace != "" {
 		// Namespace presence indicates Pods and ExternalEnitities must be selected from the same Namespace.
 		if groupSelector.PodSelector != nil {
-			pods, _ = n.podLister.Pods(groupSelector.Namespace).List(groupSelector.PodSelector)
+			pods = n.listPods(groupSelector.Namespace, groupSelector.PodSelector)
 		} else if groupSelector.ExternalEntitySelector != nil {
 			externalEntities, _ = n.externalEntityLister.ExternalEntities(groupSelector.Namespace).List(groupSelector.ExternalEntitySelector)
 		}
@@ -1441,7 +1687,7 @@ func (n *NetworkPolicyController) processSelector(groupSelector antreatypes.Grou
 		namespaces, _ := n.namespaceLister.List(groupSelector.NamespaceSelector)
 		for _, ns := range namespaces {
 			if groupSelector.PodSelector != nil {
-				nsPods, _ := n.podLister.Pods(ns.Name).List(groupSelector.PodSelector)
+				nsPods := n.listPods(ns.Name, groupSelector.PodSelector)
 				pods = append(pods, nsPods...)
 			} else if groupSelector.ExternalEntitySelector != nil {
 				nsExtEntities, _ := n.externalEntityLister.ExternalEntities(ns.Name).List(groupSelector.ExternalEntitySelector)
@@ -1458,13 +1704,47 @@ func (n *NetworkPolicyController) processSelector(groupSelector antreatypes.Grou
 	} else if groupSelector.PodSelector != nil {
 		// Lack of Namespace and NamespaceSelector indicates Pods must be selected
 		// from all Namespaces.
-		pods, _ = n.podLister.Pods("").List(groupSelector.PodSelector)
+		pods = n.listPods("", groupSelector.PodSelector)
 	} else if groupSelector.ExternalEntitySelector != nil {
 		externalEntities, _ = n.externalEntityLister.ExternalEntities("").List(groupSelector.ExternalEntitySelector)
 	}
 	return pods, externalEntities
 }
 
+// isNamespaceExempt returns true if the Namespace is exempt from Antrea-native
+// policies.
+func (n *NetworkPolicyController) isNamespaceExempt(namespace string) bool {
+	return n.exemptNamespaces.Has(namespace)
+}
+
+// filterExemptMembers removes the Pods and ExternalEntities of the exempt
+// Namespaces from the members of an AppliedToGroup of Antrea-native policies.
+// The exemptions are logged at verbosity level 2, as the AppliedToGroups are
+// synced at every membership change, so that it can be audited why a policy is
+// not enforced for a Pod selected by it.
+func (n *NetworkPolicyController) filterExemptMembers(key string, pods []*v1.Pod, externalEntities []*v1alpha1.ExternalEntity) ([]*v1.Pod, []*v1alpha1.ExternalEntity) {
+	if n.exemptNamespaces.Len() == 0 {
+		return pods, externalEntities
+	}
+	filteredPods := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if n.isNamespaceExempt(pod.Namespace) {
+			klog.V(2).Infof("Pod %s/%s is exempt from AppliedToGroup %s as its Namespace is exempt from Antrea-native policies", pod.Namespace, pod.Name, key)
+			continue
+		}
+		filteredPods = append(filteredPods, pod)
+	}
+	filteredEntities := make([]*v1alpha1.ExternalEntity, 0, len(externalEntities))
+	for _, ee := range externalEntities {
+		if n.isNamespaceExempt(ee.Namespace) {
+			klog.V(2).Infof("ExternalEntity %s/%s is exempt from AppliedToGroup %s as its Namespace is exempt from Antrea-native policies", ee.Namespace, ee.Name, key)
+			continue
+		}
+		filteredEntities = append(filteredEntities, ee)
+	}
+	return filteredPods, filteredEntities
+}
+
 // syncAppliedToGroup enqueues all the internal NetworkPolicy keys that
 // refer this AppliedToGroup and update the AppliedToGroup Pod
 // references by Node to reflect the latest set of affected Pods based
@@ -1490,6 +1770,10 @@ func (n *NetworkPolicyController) syncAppliedToGroup(key string) error {
 	appliedToGroup := appliedToGroupObj.(*antreatypes.AppliedToGroup)
 	groupSelector := appliedToGroup.Selector
 	pods, externalEntities := n.processSelector(groupSelector)
+	// Cluster-scoped AppliedToGroups are only created for ClusterNetworkPolicies.
+	if groupSelector.Namespace == "" {
+		pods, externalEntities = n.filterExemptMembers(key, pods, externalEntities)
+	}
 	for _, pod := range pods {
 		if pod.Spec.NodeName == "" {
 			// No need to process Pod when it's not scheduled.
@@ -1519,6 +1803,15 @@ func (n *NetworkPolicyController) syncAppliedToGroup(key string) error {
 		memberSetByNode[extEntity.Spec.ExternalNode] = entitySet
 		appGroupNodeNames.Insert(extEntity.Spec.ExternalNode)
 	}
+	if groupSelector.NodeSelector != nil {
+		// A selected Node is the only member of the AppliedToGroup on
+		// itself, where its host network is protected by the Agent.
+		nodes, _ := n.nodeLister.List(groupSelector.NodeSelector)
+		for _, node := range nodes {
+			memberSetByNode[node.Name] = controlplane.NewGroupMemberSet(nodeToGroupMember(node))
+			appGroupNodeNames.Insert(node.Name)
+		}
+	}
 	updatedAppliedToGroup := &antreatypes.AppliedToGroup{
 		UID:               appliedToGroup.UID,
 		Name:              appliedToGroup.Name,
@@ -1606,13 +1899,14 @@ func (n *NetworkPolicyController) syncInternalNetworkPolicy(key string) error {
 		klog.V(4).Infof("Internal NetworkPolicy %s Node span remains unchanged. No need to enqueue AddressGroups.", key)
 		return nil
 	}
-	// Enqueue addressGroup keys to update their Node span.
+	// Enqueue span updates of the addressGroups. Their members are not
+	// affected by the span of the internal NetworkPolicy.
 	for _, rule := range internalNP.Rules {
 		for _, addrGroupName := range rule.From.AddressGroups {
-			n.enqueueAddressGroup(addrGroupName)
+			n.enqueueAddressGroupSpan(addrGroupName)
 		}
 		for _, addrGroupName := range rule.To.AddressGroups {
-			n.enqueueAddressGroup(addrGroupName)
+			n.enqueueAddressGroupSpan(addrGroupName)
 		}
 	}
 	return nil
@@ -81,6 +81,7 @@ var pods = []*corev1.Pod{
 //
 // policy 0: select all pods and deny default ingress
 // policy 1: select all pods and deny default egress
+// policy 2: select all pods and allow ingress from other pods, then from all pods
 
 var policies = []*networkingv1.NetworkPolicy{
 	{
@@ -136,6 +137,38 @@ var policies = []*networkingv1.NetworkPolicy{
 			},
 		},
 	},
+	{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-multiple-ingress",
+			Namespace: "testNamespace",
+			UID:       types.UID("uid-3"),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"foo": "bar"},
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"foo": "other"},
+							},
+						},
+					},
+				},
+				{
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"foo": "bar"},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
 }
 
 var namespaces = []*corev1.Namespace{
@@ -180,6 +213,11 @@ func makeControllerAndEndpointQuerier(objects ...runtime.Object) *endpointQuerie
 func TestEndpointQuery(t *testing.T) {
 	policyRef0 := PolicyRef{policies[0].Namespace, policies[0].Name, policies[0].UID}
 	policyRef1 := PolicyRef{policies[1].Namespace, policies[1].Name, policies[1].UID}
+	policyRef2 := PolicyRef{policies[2].Namespace, policies[2].Name, policies[2].UID}
+	selector, _ := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}})
+	// The AppliedToGroups and the AddressGroups selecting podA have the same keys.
+	groupKey0 := getNormalizedUID(generateNormalizedName(policies[0].Namespace, selector, nil, nil))
+	groupKey1 := getNormalizedUID(generateNormalizedName(policies[1].Namespace, selector, nil, nil))
 
 	testCases := []struct {
 		name             string
@@ -216,10 +254,10 @@ func TestEndpointQuery(t *testing.T) {
 					{
 						Namespace: "testNamespace",
 						Name:      "podA",
-						Policies:  []Policy{{policyRef0}},
+						Policies:  []Policy{{policyRef0, []string{groupKey0}}},
 						Rules: []Rule{
-							{policyRef0, v1beta1.DirectionOut, 0},
-							{policyRef0, v1beta1.DirectionIn, 0},
+							{policyRef0, v1beta1.DirectionOut, 0, []string{groupKey0}},
+							{policyRef0, v1beta1.DirectionIn, 0, []string{groupKey0}},
 						},
 					},
 				},
@@ -236,12 +274,30 @@ func TestEndpointQuery(t *testing.T) {
 						Namespace: "testNamespace",
 						Name:      "podA",
 						Policies: []Policy{
-							{policyRef0},
-							{policyRef1},
+							{policyRef0, []string{groupKey0}},
+							{policyRef1, []string{groupKey1}},
+						},
+						Rules: []Rule{
+							{policyRef0, v1beta1.DirectionOut, 0, []string{groupKey0}},
+							{policyRef0, v1beta1.DirectionIn, 0, []string{groupKey0}},
 						},
+					},
+				},
+			},
+		},
+		{
+			"RuleIndex", // Pod is only selected by the second ingress rule of a policy
+			[]runtime.Object{namespaces[0], pods[0], policies[2]},
+			"testNamespace",
+			"podA",
+			&EndpointQueryResponse{
+				[]Endpoint{
+					{
+						Namespace: "testNamespace",
+						Name:      "podA",
+						Policies:  []Policy{{policyRef2, []string{groupKey0}}},
 						Rules: []Rule{
-							{policyRef0, v1beta1.DirectionOut, 0},
-							{policyRef0, v1beta1.DirectionIn, 0},
+							{policyRef2, v1beta1.DirectionIn, 1, []string{groupKey0}},
 						},
 					},
 				},
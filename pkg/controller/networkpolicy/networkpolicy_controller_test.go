@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -90,20 +91,31 @@ func newController(objects ...runtime.Object) (*fake.Clientset, *networkPolicyCo
 		crdClient,
 		informerFactory.Core().V1().Pods(),
 		informerFactory.Core().V1().Namespaces(),
+		informerFactory.Core().V1().Nodes(),
+		informerFactory.Core().V1().Services(),
 		crdInformerFactory.Core().V1alpha1().ExternalEntities(),
 		informerFactory.Networking().V1().NetworkPolicies(),
 		crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(),
 		crdInformerFactory.Security().V1alpha1().NetworkPolicies(),
 		crdInformerFactory.Security().V1alpha1().Tiers(),
+		crdInformerFactory.Core().V1alpha1().Groups(),
 		addressGroupStore,
 		appliedToGroupStore,
-		internalNetworkPolicyStore)
+		internalNetworkPolicyStore,
+		nil,
+		nil,
+		nil,
+		0,
+		nil)
 	npController.podListerSynced = alwaysReady
 	npController.namespaceListerSynced = alwaysReady
 	npController.networkPolicyListerSynced = alwaysReady
 	npController.cnpListerSynced = alwaysReady
 	npController.tierLister = crdInformerFactory.Security().V1alpha1().Tiers().Lister()
 	npController.tierListerSynced = alwaysReady
+	npController.groupListerSynced = alwaysReady
+	npController.nodeListerSynced = alwaysReady
+	npController.serviceListerSynced = alwaysReady
 	return client, &networkPolicyController{
 		npController,
 		informerFactory.Core().V1().Pods().Informer().GetStore(),
@@ -2951,19 +2963,23 @@ func TestDeleteFinalStateUnknownNetworkPolicy(t *testing.T) {
 }
 
 func getQueuedGroups(npc *networkPolicyController) (atGroups, addrGroups sets.String) {
-	atGroups, addrGroups = sets.NewString(), sets.NewString()
-	atLen, addrLen := npc.appliedToGroupQueue.Len(), npc.addressGroupQueue.Len()
-	for i := 0; i < atLen; i++ {
-		id, _ := npc.appliedToGroupQueue.Get()
-		atGroups.Insert(id.(string))
-		npc.appliedToGroupQueue.Done(id)
-	}
-	for i := 0; i < addrLen; i++ {
-		id, _ := npc.addressGroupQueue.Get()
-		addrGroups.Insert(id.(string))
-		npc.addressGroupQueue.Done(id)
-	}
-	return
+	return drainQueue(npc.appliedToGroupQueue), drainQueue(npc.addressGroupQueue)
+}
+
+// drainQueue returns the keys of all the full sync items queued in the
+// sharded queue, and removes all items from it.
+func drainQueue(q *shardedQueue) sets.String {
+	keys := sets.NewString()
+	for shard := 0; shard < q.NumShards(); shard++ {
+		for q.shards[shard].Len() > 0 {
+			id, _ := q.Get(shard)
+			if key, ok := id.(string); ok {
+				keys.Insert(key)
+			}
+			q.Done(id)
+		}
+	}
+	return keys
 }
 
 func getK8sNetworkPolicyObj() *networkingv1.NetworkPolicy {
@@ -3081,3 +3097,49 @@ func compareIPNet(ipn1, ipn2 controlplane.IPNet) bool {
 	}
 	return true
 }
+
+func TestSyncAddressGroupSpan(t *testing.T) {
+	selectorA := metav1.LabelSelector{MatchLabels: map[string]string{"app": "a"}}
+	selectorB := metav1.LabelSelector{MatchLabels: map[string]string{"app": "b"}}
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "nsA", Name: "npA", UID: "uidA"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: selectorA,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &selectorB}}},
+			},
+		},
+	}
+	p1 := getPod("p1", "nsA", "node1", "1.1.1.1", false)
+	p1.Labels = selectorA.MatchLabels
+	p2 := getPod("p2", "nsA", "node2", "2.2.2.2", false)
+	p2.Labels = selectorB.MatchLabels
+	_, npc := newController()
+	npc.podStore.Add(p1)
+	npc.podStore.Add(p2)
+	npc.addNetworkPolicy(np)
+	addrGroupObjs := npc.addressGroupStore.List()
+	require.Len(t, addrGroupObjs, 1)
+	addrGroupKey := addrGroupObjs[0].(*antreatypes.AddressGroup).Name
+	require.NoError(t, npc.syncAddressGroup(addrGroupKey))
+
+	// The AppliedToGroup span changes the span of the policy, which enqueues a
+	// span update of the AddressGroup.
+	podSelector, _ := metav1.LabelSelectorAsSelector(&selectorA)
+	atGroupKey := getNormalizedUID(generateNormalizedName("nsA", podSelector, nil, nil))
+	require.NoError(t, npc.syncAppliedToGroup(atGroupKey))
+	npKey, _ := keyFunc(np)
+	require.NoError(t, npc.syncInternalNetworkPolicy(npKey))
+	shard := npc.addressGroupQueue.shardFor(addrGroupKey)
+	require.Equal(t, 1, shard.Len())
+	item, _ := shard.Get()
+	assert.Equal(t, addressGroupSpanKey(addrGroupKey), item)
+	shard.Done(item)
+
+	require.NoError(t, npc.syncAddressGroupSpan(addrGroupKey))
+	obj, _, _ := npc.addressGroupStore.Get(addrGroupKey)
+	addrGroup := obj.(*antreatypes.AddressGroup)
+	assert.Equal(t, sets.NewString("node1"), addrGroup.SpanMeta.NodeNames)
+	assert.True(t, addrGroup.Pods.Has(&controlplane.GroupMemberPod{IP: ipStrToIPAddress("2.2.2.2")}),
+		"Members should be kept by span updates")
+}
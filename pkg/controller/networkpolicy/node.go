@@ -0,0 +1,139 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+// toNodeGroupSelector converts the nodeSelector of an AppliedTo peer to a
+// GroupSelector object. Nodes are cluster scoped, so the GroupSelector has
+// neither Namespace nor NamespaceSelector.
+func toNodeGroupSelector(nodeSelector *metav1.LabelSelector) *antreatypes.GroupSelector {
+	nSelector, _ := metav1.LabelSelectorAsSelector(nodeSelector)
+	return &antreatypes.GroupSelector{
+		NormalizedName: fmt.Sprintf("nodeSelector=%s", nSelector.String()),
+		NodeSelector:   nSelector,
+	}
+}
+
+// createNodeAppliedToGroup creates an AppliedToGroup object selecting Nodes in
+// store if it is not created already.
+func (n *NetworkPolicyController) createNodeAppliedToGroup(nodeSelector *metav1.LabelSelector) string {
+	groupSelector := toNodeGroupSelector(nodeSelector)
+	appliedToGroupUID := getNormalizedUID(groupSelector.NormalizedName)
+	_, found, _ := n.appliedToGroupStore.Get(appliedToGroupUID)
+	if found {
+		return appliedToGroupUID
+	}
+	newAppliedToGroup := &antreatypes.AppliedToGroup{
+		Name:     appliedToGroupUID,
+		UID:      types.UID(appliedToGroupUID),
+		Selector: *groupSelector,
+	}
+	klog.V(2).Infof("Creating new AppliedToGroup %s with selector (%s)", newAppliedToGroup.Name, newAppliedToGroup.Selector.NormalizedName)
+	n.appliedToGroupStore.Create(newAppliedToGroup)
+	n.enqueueAppliedToGroup(appliedToGroupUID)
+	return appliedToGroupUID
+}
+
+// nodeToGroupMember converts a Node to a GroupMember referencing it.
+func nodeToGroupMember(node *v1.Node) *controlplane.GroupMember {
+	return &controlplane.GroupMember{Node: &controlplane.NodeReference{Name: node.Name}}
+}
+
+// filterAppliedToGroupsForNode computes a list of AppliedToGroup keys which
+// select the Node by its labels.
+func (n *NetworkPolicyController) filterAppliedToGroupsForNode(node *v1.Node) sets.String {
+	matchingKeySet := sets.String{}
+	// Only cluster scoped AppliedToGroups can possibly select this Node.
+	appliedToGroups, _ := n.appliedToGroupStore.GetByIndex(cache.NamespaceIndex, "")
+	for _, group := range appliedToGroups {
+		appGroup := group.(*antreatypes.AppliedToGroup)
+		if appGroup.Selector.NodeSelector != nil && appGroup.Selector.NodeSelector.Matches(labels.Set(node.Labels)) {
+			matchingKeySet.Insert(appGroup.Name)
+			klog.V(2).Infof("Node %s matched AppliedToGroup %s", node.Name, appGroup.Name)
+		}
+	}
+	return matchingKeySet
+}
+
+// addNode retrieves all AppliedToGroups which select the Node and enqueues
+// the group keys for further processing.
+func (n *NetworkPolicyController) addNode(obj interface{}) {
+	defer n.heartbeat("addNode")
+	node := obj.(*v1.Node)
+	klog.V(2).Infof("Processing Node %s ADD event, labels: %v", node.Name, node.Labels)
+	for group := range n.filterAppliedToGroupsForNode(node) {
+		n.enqueueAppliedToGroup(group)
+	}
+}
+
+// updateNode retrieves all AppliedToGroups which select the current or old
+// Node and enqueues the group keys for further processing.
+func (n *NetworkPolicyController) updateNode(oldObj, curObj interface{}) {
+	defer n.heartbeat("updateNode")
+	oldNode := oldObj.(*v1.Node)
+	curNode := curObj.(*v1.Node)
+	// Only the labels of a Node affect the AppliedToGroups selecting it.
+	if labels.Equals(labels.Set(oldNode.Labels), labels.Set(curNode.Labels)) {
+		klog.V(4).Infof("No change in Node %s labels", curNode.Name)
+		return
+	}
+	klog.V(2).Infof("Processing Node %s UPDATE event, labels: %v", curNode.Name, curNode.Labels)
+	curGroupKeySet := n.filterAppliedToGroupsForNode(curNode)
+	oldGroupKeySet := n.filterAppliedToGroupsForNode(oldNode)
+	// No need to enqueue the AppliedToGroups which select the Node both
+	// before and after the update.
+	groupKeys := oldGroupKeySet.Difference(curGroupKeySet).Union(curGroupKeySet.Difference(oldGroupKeySet))
+	for group := range groupKeys {
+		n.enqueueAppliedToGroup(group)
+	}
+}
+
+// deleteNode retrieves all AppliedToGroups which select the Node and enqueues
+// the group keys for further processing.
+func (n *NetworkPolicyController) deleteNode(old interface{}) {
+	node, ok := old.(*v1.Node)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Node, invalid type: %v", old)
+			return
+		}
+		node, ok = tombstone.Obj.(*v1.Node)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Node, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	defer n.heartbeat("deleteNode")
+
+	klog.V(2).Infof("Processing Node %s DELETE event, labels: %v", node.Name, node.Labels)
+	for group := range n.filterAppliedToGroupsForNode(node) {
+		n.enqueueAppliedToGroup(group)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+func TestNodeSelectorAppliedToGroup(t *testing.T) {
+	_, npc := newController()
+	nodeInformer := npc.informerFactory.Core().V1().Nodes()
+	npc.nodeLister = nodeInformer.Lister()
+	nodeStore := nodeInformer.Informer().GetStore()
+	node1 := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"role": "edge"}}}
+	node2 := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"role": "worker"}}}
+	nodeStore.Add(node1)
+	nodeStore.Add(node2)
+
+	allowAction := secv1alpha1.RuleActionAllow
+	nodeSelector := metav1.LabelSelector{MatchLabels: map[string]string{"role": "edge"}}
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{NodeSelector: &nodeSelector}},
+			Priority:  10,
+			Ingress: []secv1alpha1.Rule{
+				{
+					From:   []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+					Action: &allowAction,
+				},
+			},
+		},
+	}
+	internalNP := npc.processClusterNetworkPolicy(cnp)
+	require.Len(t, internalNP.AppliedToGroups, 1)
+	atgName := internalNP.AppliedToGroups[0]
+	assert.Equal(t, getNormalizedUID(toNodeGroupSelector(&nodeSelector).NormalizedName), atgName)
+
+	require.NoError(t, npc.syncAppliedToGroup(atgName))
+	obj, found, _ := npc.appliedToGroupStore.Get(atgName)
+	require.True(t, found)
+	atg := obj.(*antreatypes.AppliedToGroup)
+	assert.Equal(t, sets.NewString("node1"), atg.SpanMeta.NodeNames)
+	assert.Equal(t, controlplane.NewGroupMemberSet(&controlplane.GroupMember{Node: &controlplane.NodeReference{Name: "node1"}}), atg.GroupMemberByNode["node1"])
+	assert.Empty(t, atg.PodsByNode)
+
+	// The AppliedToGroup must be enqueued when a Node starts or stops
+	// matching its selector.
+	assert.Equal(t, sets.NewString(atgName), npc.filterAppliedToGroupsForNode(node1))
+	assert.Empty(t, npc.filterAppliedToGroupsForNode(node2))
+	node2Updated := node2.DeepCopy()
+	node2Updated.Labels["role"] = "edge"
+	nodeStore.Update(node2Updated)
+	npc.updateNode(node2, node2Updated)
+	require.NoError(t, npc.syncAppliedToGroup(atgName))
+	obj, _, _ = npc.appliedToGroupStore.Get(atgName)
+	assert.Equal(t, sets.NewString("node1", "node2"), obj.(*antreatypes.AppliedToGroup).SpanMeta.NodeNames)
+}
+
+func TestValidateNodeSelectorPeers(t *testing.T) {
+	nodeSelector := metav1.LabelSelector{MatchLabels: map[string]string{"role": "edge"}}
+	tests := []struct {
+		name       string
+		appliedTo  []secv1alpha1.NetworkPolicyPeer
+		ingress    []secv1alpha1.Rule
+		namespaced bool
+		expAllowed bool
+	}{
+		{
+			name:       "node-selector-in-acnp",
+			appliedTo:  []secv1alpha1.NetworkPolicyPeer{{NodeSelector: &nodeSelector}, {PodSelector: &selectorA}},
+			expAllowed: true,
+		},
+		{
+			name:       "node-selector-in-anp",
+			appliedTo:  []secv1alpha1.NetworkPolicyPeer{{NodeSelector: &nodeSelector}},
+			namespaced: true,
+			expAllowed: false,
+		},
+		{
+			name:       "node-selector-with-pod-selector",
+			appliedTo:  []secv1alpha1.NetworkPolicyPeer{{NodeSelector: &nodeSelector, PodSelector: &selectorA}},
+			expAllowed: false,
+		},
+		{
+			name:       "node-selector-in-rule",
+			appliedTo:  []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			ingress:    []secv1alpha1.Rule{{From: []secv1alpha1.NetworkPolicyPeer{{NodeSelector: &nodeSelector}}}},
+			expAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := validateNodeSelectorPeers(tt.appliedTo, tt.ingress, nil, tt.namespaced)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
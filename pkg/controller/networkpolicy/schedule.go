@@ -0,0 +1,321 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+const (
+	// scheduleSyncPeriod is the interval at which the scheduled rules of
+	// Antrea Policies are re-evaluated. Schedules have minute granularity.
+	scheduleSyncPeriod = 15 * time.Second
+	// degradedScheduleSyncPeriod is the interval at which the scheduled rules
+	// are re-evaluated when the controller is in degraded mode.
+	degradedScheduleSyncPeriod = 2 * time.Minute
+	// timeOfDayFormat is the layout of the Start and End fields of a
+	// TimeWindow.
+	timeOfDayFormat = "15:04"
+)
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// parseTimeOfDay parses a time of day in "HH:MM" format and returns the
+// number of minutes since midnight.
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse(timeOfDayFormat, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// validateRuleSchedule returns an error if the RuleSchedule is malformed.
+func validateRuleSchedule(schedule *secv1alpha1.RuleSchedule) error {
+	_, err := parseRuleSchedule(schedule)
+	return err
+}
+
+// parsedSchedule is a RuleSchedule parsed once, so that its evaluation doesn't
+// require loading the time zone and parsing the windows again.
+type parsedSchedule struct {
+	location *time.Location
+	windows  []parsedWindow
+}
+
+// parsedWindow is a TimeWindow whose Start and End are converted to minutes
+// since midnight.
+type parsedWindow struct {
+	start, end int
+	// days are the weekdays on which the window opens, empty for every day.
+	days map[time.Weekday]bool
+}
+
+// parseRuleSchedule parses the RuleSchedule, returning an error if it's
+// malformed.
+func parseRuleSchedule(schedule *secv1alpha1.RuleSchedule) (*parsedSchedule, error) {
+	if len(schedule.Windows) == 0 {
+		return nil, fmt.Errorf("schedule must have at least one window")
+	}
+	parsed := &parsedSchedule{location: time.UTC}
+	if schedule.TimeZone != "" {
+		loc, err := time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", schedule.TimeZone, err)
+		}
+		parsed.location = loc
+	}
+	for _, w := range schedule.Windows {
+		start, err := parseTimeOfDay(w.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(w.End)
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, fmt.Errorf("window start and end must differ, got %q", w.Start)
+		}
+		window := parsedWindow{start: start, end: end, days: map[time.Weekday]bool{}}
+		for _, d := range w.DaysOfWeek {
+			day, ok := weekdays[d]
+			if !ok {
+				return nil, fmt.Errorf("invalid day of week %q", d)
+			}
+			window.days[day] = true
+		}
+		parsed.windows = append(parsed.windows, window)
+	}
+	return parsed, nil
+}
+
+// opensOn returns true if the window opens on the given weekday.
+func (w *parsedWindow) opensOn(day time.Weekday) bool {
+	return len(w.days) == 0 || w.days[day]
+}
+
+// isActive returns true if the time t falls within any window of the schedule.
+func (s *parsedSchedule) isActive(t time.Time) bool {
+	t = t.In(s.location)
+	minute := t.Hour()*60 + t.Minute()
+	today := t.Weekday()
+	yesterday := (today + 6) % 7
+	for i := range s.windows {
+		w := &s.windows[i]
+		if w.start < w.end {
+			if minute >= w.start && minute < w.end && w.opensOn(today) {
+				return true
+			}
+			continue
+		}
+		// The window spans midnight: it is active from Start until midnight
+		// on the day it opens, and from midnight until End on the next day.
+		if minute >= w.start && w.opensOn(today) {
+			return true
+		}
+		if minute < w.end && w.opensOn(yesterday) {
+			return true
+		}
+	}
+	return false
+}
+
+// isScheduleActive returns true if the time t falls within any window of the
+// RuleSchedule.
+func isScheduleActive(schedule *secv1alpha1.RuleSchedule, t time.Time) (bool, error) {
+	parsed, err := parseRuleSchedule(schedule)
+	if err != nil {
+		return false, err
+	}
+	return parsed.isActive(t), nil
+}
+
+// scheduleCache caches the parsed RuleSchedules of the Antrea Policy rules,
+// keyed by their content, so that a schedule is parsed when the policy is first
+// processed rather than at every re-evaluation. The schedules which are no
+// longer used by any policy are evicted by syncScheduledRules.
+type scheduleCache struct {
+	mutex sync.Mutex
+	// schedules is a map of schedule key to the parsed schedule, which is
+	// nil if the schedule is malformed.
+	schedules map[string]*parsedSchedule
+	// used is the set of the schedule keys used since the last eviction.
+	used sets.String
+}
+
+func newScheduleCache() *scheduleCache {
+	return &scheduleCache{schedules: map[string]*parsedSchedule{}, used: sets.NewString()}
+}
+
+// scheduleKey returns a key uniquely identifying the content of a RuleSchedule.
+func scheduleKey(schedule *secv1alpha1.RuleSchedule) string {
+	return fmt.Sprintf("%+v", *schedule)
+}
+
+// get returns the parsed RuleSchedule, parsing and caching it if needed. nil is
+// returned for a malformed schedule.
+func (c *scheduleCache) get(schedule *secv1alpha1.RuleSchedule) *parsedSchedule {
+	key := scheduleKey(schedule)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.used.Insert(key)
+	parsed, exists := c.schedules[key]
+	if !exists {
+		var err error
+		if parsed, err = parseRuleSchedule(schedule); err != nil {
+			klog.Errorf("Failed to parse rule schedule %v: %v", schedule, err)
+		}
+		c.schedules[key] = parsed
+	}
+	return parsed
+}
+
+// evictUnused evicts the schedules which were not used since the last call.
+func (c *scheduleCache) evictUnused() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key := range c.schedules {
+		if !c.used.Has(key) {
+			delete(c.schedules, key)
+		}
+	}
+	c.used = sets.NewString()
+}
+
+// isRuleActive returns true if the rule should currently be realized. Rules
+// without a Schedule are always active. Rules with an invalid Schedule, which
+// should have been rejected by validation, are never active.
+func (n *NetworkPolicyController) isRuleActive(rule *secv1alpha1.Rule) bool {
+	if rule.Schedule == nil {
+		return true
+	}
+	parsed := n.schedules.get(rule.Schedule)
+	return parsed != nil && parsed.isActive(n.clock.Now())
+}
+
+// computeScheduledRuleStatus computes the status of the scheduled rules of an
+// Antrea Policy. The LastTransitionTime of a rule is carried over from the
+// existing status if its state didn't change. A bool is returned along with the
+// status to indicate whether it differs from the existing one.
+func (n *NetworkPolicyController) computeScheduledRuleStatus(ingress, egress []secv1alpha1.Rule, existing []secv1alpha1.ScheduledRuleStatus) ([]secv1alpha1.ScheduledRuleStatus, bool) {
+	type ruleKey struct {
+		direction secv1alpha1.RuleDirection
+		index     int32
+	}
+	existingStatus := make(map[ruleKey]secv1alpha1.ScheduledRuleStatus, len(existing))
+	for _, s := range existing {
+		existingStatus[ruleKey{s.Direction, s.Index}] = s
+	}
+	var status []secv1alpha1.ScheduledRuleStatus
+	changed := false
+	now := metav1.NewTime(n.clock.Now())
+	appendStatus := func(direction secv1alpha1.RuleDirection, rules []secv1alpha1.Rule) {
+		for idx := range rules {
+			if rules[idx].Schedule == nil {
+				continue
+			}
+			s := secv1alpha1.ScheduledRuleStatus{
+				Direction:          direction,
+				Index:              int32(idx),
+				Active:             n.isRuleActive(&rules[idx]),
+				LastTransitionTime: now,
+			}
+			if old, exists := existingStatus[ruleKey{direction, int32(idx)}]; exists && old.Active == s.Active {
+				s.LastTransitionTime = old.LastTransitionTime
+			} else {
+				changed = true
+			}
+			status = append(status, s)
+		}
+	}
+	appendStatus(secv1alpha1.RuleDirectionIngress, ingress)
+	appendStatus(secv1alpha1.RuleDirectionEgress, egress)
+	if len(status) != len(existing) {
+		changed = true
+	}
+	return status, changed
+}
+
+// syncScheduledRules re-evaluates the scheduled rules of all Antrea Policies.
+// When the state of any scheduled rule of a policy changes, the policy status
+// is updated to reflect the new state. The resulting UPDATE event is processed
+// by the policy event handler like any other, which re-computes the internal
+// NetworkPolicy so that the rule gets realized or withdrawn. If the status
+// update fails, it is retried at the next re-evaluation.
+func (n *NetworkPolicyController) syncScheduledRules() {
+	// The re-evaluation is not critical, schedules are allowed to take effect
+	// late when the controller is shedding load.
+	if n.watermarkMonitor.IsDegraded() && n.clock.Since(n.lastScheduleSync) < degradedScheduleSyncPeriod {
+		klog.V(2).Info("Controller is in degraded mode, delaying the re-evaluation of scheduled rules")
+		return
+	}
+	n.lastScheduleSync = n.clock.Now()
+	cnps, err := n.cnpLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list ClusterNetworkPolicies: %v", err)
+		return
+	}
+	for _, cnp := range cnps {
+		status, changed := n.computeScheduledRuleStatus(cnp.Spec.Ingress, cnp.Spec.Egress, cnp.Status.ScheduledRules)
+		if !changed {
+			continue
+		}
+		klog.Infof("Scheduled rules of ClusterNetworkPolicy %s changed state", cnp.Name)
+		toUpdate := cnp.DeepCopy()
+		toUpdate.Status.ScheduledRules = status
+		if _, err := n.crdClient.SecurityV1alpha1().ClusterNetworkPolicies().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("Failed to update status of ClusterNetworkPolicy %s: %v", cnp.Name, err)
+		}
+	}
+	anps, err := n.anpLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Antrea NetworkPolicies: %v", err)
+		return
+	}
+	// All the policies have been evaluated, the schedules which were not
+	// used since the last re-evaluation are no longer referenced.
+	defer n.schedules.evictUnused()
+	for _, anp := range anps {
+		status, changed := n.computeScheduledRuleStatus(anp.Spec.Ingress, anp.Spec.Egress, anp.Status.ScheduledRules)
+		if !changed {
+			continue
+		}
+		klog.Infof("Scheduled rules of Antrea NetworkPolicy %s/%s changed state", anp.Namespace, anp.Name)
+		toUpdate := anp.DeepCopy()
+		toUpdate.Status.ScheduledRules = status
+		if _, err := n.crdClient.SecurityV1alpha1().NetworkPolicies(anp.Namespace).UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("Failed to update status of Antrea NetworkPolicy %s/%s: %v", anp.Namespace, anp.Name, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+func TestIsScheduleActive(t *testing.T) {
+	// 2020-10-14 is a Wednesday.
+	wed2230 := time.Date(2020, 10, 14, 22, 30, 0, 0, time.UTC)
+	thu0130 := time.Date(2020, 10, 15, 1, 30, 0, 0, time.UTC)
+	wed1200 := time.Date(2020, 10, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		schedule       secv1alpha1.RuleSchedule
+		t              time.Time
+		expectedActive bool
+		expectedErr    bool
+	}{
+		{
+			name:           "within-daytime-window",
+			schedule:       secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "09:00", End: "17:00"}}},
+			t:              wed1200,
+			expectedActive: true,
+		},
+		{
+			name:           "outside-daytime-window",
+			schedule:       secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "09:00", End: "17:00"}}},
+			t:              wed2230,
+			expectedActive: false,
+		},
+		{
+			name:           "end-is-exclusive",
+			schedule:       secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "09:00", End: "12:00"}}},
+			t:              wed1200,
+			expectedActive: false,
+		},
+		{
+			name:           "overnight-window-before-midnight",
+			schedule:       secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "22:00", End: "06:00", DaysOfWeek: []string{"Wed"}}}},
+			t:              wed2230,
+			expectedActive: true,
+		},
+		{
+			name:           "overnight-window-after-midnight",
+			schedule:       secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "22:00", End: "06:00", DaysOfWeek: []string{"Wed"}}}},
+			t:              thu0130,
+			expectedActive: true,
+		},
+		{
+			name:           "overnight-window-wrong-day",
+			schedule:       secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "22:00", End: "06:00", DaysOfWeek: []string{"Thu"}}}},
+			t:              thu0130,
+			expectedActive: false,
+		},
+		{
+			name: "time-zone",
+			schedule: secv1alpha1.RuleSchedule{
+				Windows:  []secv1alpha1.TimeWindow{{Start: "09:00", End: "17:00"}},
+				TimeZone: "Asia/Tokyo",
+			},
+			// 01:30 UTC is 10:30 in Tokyo.
+			t:              thu0130,
+			expectedActive: true,
+		},
+		{
+			name:        "invalid-time",
+			schedule:    secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "25:00", End: "06:00"}}},
+			t:           wed1200,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid-day",
+			schedule:    secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "09:00", End: "17:00", DaysOfWeek: []string{"Monday"}}}},
+			t:           wed1200,
+			expectedErr: true,
+		},
+		{
+			name:        "empty-window",
+			schedule:    secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "09:00", End: "09:00"}}},
+			t:           wed1200,
+			expectedErr: true,
+		},
+		{
+			name:        "no-window",
+			schedule:    secv1alpha1.RuleSchedule{},
+			t:           wed1200,
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, err := isScheduleActive(&tt.schedule, tt.t)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedActive, active)
+		})
+	}
+}
+
+func TestScheduleCache(t *testing.T) {
+	c := newScheduleCache()
+	schedule := &secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "09:00", End: "17:00"}}, TimeZone: "Asia/Tokyo"}
+	parsed := c.get(schedule)
+	require.NotNil(t, parsed)
+	// A schedule with the same content is only parsed once.
+	assert.Same(t, parsed, c.get(schedule.DeepCopy()))
+	// Malformed schedules are cached as nil.
+	assert.Nil(t, c.get(&secv1alpha1.RuleSchedule{}))
+	assert.Len(t, c.schedules, 2)
+
+	// Only the schedules used since the last eviction are kept.
+	c.evictUnused()
+	assert.Len(t, c.schedules, 2)
+	c.get(schedule)
+	c.evictUnused()
+	assert.Len(t, c.schedules, 1)
+	assert.Same(t, parsed, c.get(schedule))
+}
+
+func TestSyncScheduledRules(t *testing.T) {
+	_, npc := newController()
+	fakeClock := clock.NewFakeClock(time.Date(2020, 10, 14, 12, 0, 0, 0, time.UTC))
+	npc.clock = fakeClock
+	npc.cnpLister = npc.crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies().Lister()
+	npc.anpLister = npc.crdInformerFactory.Security().V1alpha1().NetworkPolicies().Lister()
+
+	allowAction := secv1alpha1.RuleActionAllow
+	selectorA := metav1.LabelSelector{MatchLabels: map[string]string{"foo1": "bar1"}}
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			Priority:  10,
+			Egress: []secv1alpha1.Rule{
+				{
+					Action: &allowAction,
+				},
+				{
+					Action:   &allowAction,
+					Schedule: &secv1alpha1.RuleSchedule{Windows: []secv1alpha1.TimeWindow{{Start: "22:00", End: "06:00"}}},
+				},
+			},
+		},
+	}
+	_, err := npc.crdClient.SecurityV1alpha1().ClusterNetworkPolicies().Create(context.TODO(), cnp, metav1.CreateOptions{})
+	require.NoError(t, err)
+	npc.cnpStore.Add(cnp)
+	npc.addCNP(cnp)
+
+	getRules := func() int {
+		obj, _, _ := npc.internalNetworkPolicyStore.Get("cnpA")
+		return len(obj.(*antreatypes.NetworkPolicy).Rules)
+	}
+	getStatus := func() []secv1alpha1.ScheduledRuleStatus {
+		obj, err := npc.crdClient.SecurityV1alpha1().ClusterNetworkPolicies().Get(context.TODO(), "cnpA", metav1.GetOptions{})
+		require.NoError(t, err)
+		return obj.Status.ScheduledRules
+	}
+	// syncScheduledRules only updates the status of the policy, the rules are realized or withdrawn when the
+	// resulting UPDATE event is received. The informer is not running, so the event is delivered here.
+	syncScheduledRules := func() {
+		npc.syncScheduledRules()
+		oldObj, _, _ := npc.cnpStore.GetByKey("cnpA")
+		curObj, err := npc.crdClient.SecurityV1alpha1().ClusterNetworkPolicies().Get(context.TODO(), "cnpA", metav1.GetOptions{})
+		require.NoError(t, err)
+		if !reflect.DeepEqual(oldObj.(*secv1alpha1.ClusterNetworkPolicy).Status, curObj.Status) {
+			npc.cnpStore.Update(curObj)
+			npc.updateCNP(oldObj, curObj)
+		}
+	}
+
+	// At noon the scheduled rule is not realized.
+	assert.Equal(t, 1, getRules())
+	syncScheduledRules()
+	status := getStatus()
+	require.Len(t, status, 1)
+	assert.Equal(t, secv1alpha1.RuleDirectionEgress, status[0].Direction)
+	assert.Equal(t, int32(1), status[0].Index)
+	assert.False(t, status[0].Active)
+
+	// Nothing changes until the window opens.
+	fakeClock.SetTime(time.Date(2020, 10, 14, 21, 59, 0, 0, time.UTC))
+	syncScheduledRules()
+	assert.Equal(t, 1, getRules())
+	assert.Equal(t, status, getStatus())
+
+	fakeClock.SetTime(time.Date(2020, 10, 14, 22, 0, 0, 0, time.UTC))
+	syncScheduledRules()
+	assert.Equal(t, 2, getRules())
+	status = getStatus()
+	require.Len(t, status, 1)
+	assert.True(t, status[0].Active)
+	assert.Equal(t, fakeClock.Now().Unix(), status[0].LastTransitionTime.Unix())
+
+	fakeClock.SetTime(time.Date(2020, 10, 15, 6, 0, 0, 0, time.UTC))
+	syncScheduledRules()
+	assert.Equal(t, 1, getRules())
+	status = getStatus()
+	require.Len(t, status, 1)
+	assert.False(t, status[0].Active)
+}
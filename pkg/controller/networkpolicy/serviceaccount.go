@@ -0,0 +1,85 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+// serviceAccountLabelKey is the reserved label key used to select the Pods by
+// ServiceAccount. The label is not set on the Pods: it is added to their labels
+// when they are matched against a GroupSelector, with the name of their
+// ServiceAccount as value.
+const serviceAccountLabelKey = "internal.antrea.tanzu.vmware.com/service-account"
+
+// toServiceAccountGroupSelector converts the serviceAccount of a peer to a
+// GroupSelector object selecting the Pods of the ServiceAccount's Namespace
+// which run with it.
+func toServiceAccountGroupSelector(serviceAccount *secv1alpha1.NamespacedName) *antreatypes.GroupSelector {
+	// The name of a ServiceAccount is not always a valid label value, so
+	// the selector is built without validation.
+	pSelector := labels.SelectorFromValidatedSet(labels.Set{serviceAccountLabelKey: serviceAccount.Name})
+	return &antreatypes.GroupSelector{
+		NormalizedName: generateNormalizedName(serviceAccount.Namespace, pSelector, nil, nil),
+		Namespace:      serviceAccount.Namespace,
+		PodSelector:    pSelector,
+	}
+}
+
+// podLabels returns the labels of the Pod with the reserved labels of its
+// ServiceAccount and of its UID. The reserved labels always override the ones
+// set on the Pod, so that a Pod cannot be selected by another Pod's UID or
+// ServiceAccount.
+func podLabels(pod *v1.Pod) labels.Set {
+	if pod.Spec.ServiceAccountName == "" && pod.UID == "" {
+		return pod.Labels
+	}
+	podLabels := make(labels.Set, len(pod.Labels)+2)
+	for k, v := range pod.Labels {
+		podLabels[k] = v
+	}
+	if pod.Spec.ServiceAccountName != "" {
+		podLabels[serviceAccountLabelKey] = pod.Spec.ServiceAccountName
+	}
+	if pod.UID != "" {
+		podLabels[secv1alpha1.PodUIDLabelKey] = string(pod.UID)
+	}
+	return podLabels
+}
+
+// listPods lists the Pods of the Namespace matching the selector, or of all
+// Namespaces if it is empty. The Pods are filtered by their ServiceAccount or
+// UID when the selector requires a reserved label, as the lister only matches
+// the labels set on the Pods.
+func (n *NetworkPolicyController) listPods(namespace string, selector labels.Selector) []*v1.Pod {
+	_, requiresServiceAccount := selector.RequiresExactMatch(serviceAccountLabelKey)
+	_, requiresUID := selector.RequiresExactMatch(secv1alpha1.PodUIDLabelKey)
+	if !requiresServiceAccount && !requiresUID {
+		pods, _ := n.podLister.Pods(namespace).List(selector)
+		return pods
+	}
+	allPods, _ := n.podLister.Pods(namespace).List(labels.Everything())
+	var pods []*v1.Pod
+	for _, pod := range allPods {
+		if selector.Matches(podLabels(pod)) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+func TestServiceAccountGroups(t *testing.T) {
+	_, npc := newController()
+	newPod := func(name, namespace, serviceAccount string) *v1.Pod {
+		pod := getPod(name, namespace, "node1", "1.1.1.1", false)
+		pod.Labels = map[string]string{"app": name}
+		pod.Spec.ServiceAccountName = serviceAccount
+		npc.podStore.Add(pod)
+		return pod
+	}
+	p1 := newPod("p1", "nsA", "sa1")
+	p2 := newPod("p2", "nsA", "sa2")
+	p3 := newPod("p3", "nsB", "sa1")
+
+	allowAction := secv1alpha1.RuleActionAllow
+	saA := &secv1alpha1.NamespacedName{Namespace: "nsA", Name: "sa1"}
+	saB := &secv1alpha1.NamespacedName{Namespace: "nsB", Name: "sa1"}
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{ServiceAccount: saA}},
+			Priority:  10,
+			Ingress: []secv1alpha1.Rule{
+				{
+					From:   []secv1alpha1.NetworkPolicyPeer{{ServiceAccount: saB}},
+					Action: &allowAction,
+				},
+			},
+		},
+	}
+	internalNP := npc.processClusterNetworkPolicy(cnp)
+	require.Len(t, internalNP.AppliedToGroups, 1)
+	assert.Equal(t, getNormalizedUID(toServiceAccountGroupSelector(saA).NormalizedName), internalNP.AppliedToGroups[0])
+	require.Len(t, internalNP.Rules, 1)
+	require.Len(t, internalNP.Rules[0].From.AddressGroups, 1)
+	addrGroupName := internalNP.Rules[0].From.AddressGroups[0]
+	assert.Equal(t, getNormalizedUID(toServiceAccountGroupSelector(saB).NormalizedName), addrGroupName)
+
+	// Only the Pods of the ServiceAccount's Namespace running with it are
+	// selected, even though the Pods don't have the reserved label.
+	pods, _ := npc.processSelector(*toServiceAccountGroupSelector(saA))
+	assert.Equal(t, []*v1.Pod{p1}, pods)
+	assert.Equal(t, sets.NewString(addrGroupName), npc.filterAddressGroupsForPodOrExternalEntity(p3))
+	assert.Empty(t, npc.filterAddressGroupsForPodOrExternalEntity(p2))
+	assert.Empty(t, p1.Labels[serviceAccountLabelKey])
+}
+
+func TestPodUIDGroups(t *testing.T) {
+	_, npc := newController()
+	newPod := func(name, uid string, labels map[string]string) *v1.Pod {
+		pod := getPod(name, "nsA", "node1", "1.1.1.1", false)
+		pod.UID = types.UID(uid)
+		pod.Labels = labels
+		npc.podStore.Add(pod)
+		return pod
+	}
+	p1 := newPod("p1", "uid1", map[string]string{"app": "web"})
+	// A Pod cannot be selected by the UID of another Pod by setting the
+	// reserved label.
+	p2 := newPod("p2", "uid2", map[string]string{secv1alpha1.PodUIDLabelKey: "uid1"})
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{secv1alpha1.PodUIDLabelKey: "uid1"}}
+	groupSelector := toGroupSelector("", selector, nil, nil)
+	pods, _ := npc.processSelector(*groupSelector)
+	assert.Equal(t, []*v1.Pod{p1}, pods)
+
+	appliedToGroupName := npc.createAppliedToGroup("", selector, nil, nil)
+	assert.Equal(t, sets.NewString(appliedToGroupName), npc.filterAppliedToGroupsForPodOrExternalEntity(p1))
+	assert.Empty(t, npc.filterAppliedToGroupsForPodOrExternalEntity(p2))
+	// The selection doesn't depend on the labels of the Pod.
+	p1.Labels = nil
+	assert.Equal(t, sets.NewString(appliedToGroupName), npc.filterAppliedToGroupsForPodOrExternalEntity(p1))
+}
+
+func TestValidateServiceAccountPeers(t *testing.T) {
+	sa := &secv1alpha1.NamespacedName{Namespace: "nsA", Name: "sa1"}
+	tests := []struct {
+		name       string
+		appliedTo  []secv1alpha1.NetworkPolicyPeer
+		ingress    []secv1alpha1.Rule
+		namespaced bool
+		expAllowed bool
+	}{
+		{
+			name:       "service-account-in-acnp",
+			appliedTo:  []secv1alpha1.NetworkPolicyPeer{{ServiceAccount: sa}},
+			ingress:    []secv1alpha1.Rule{{From: []secv1alpha1.NetworkPolicyPeer{{ServiceAccount: sa}}}},
+			expAllowed: true,
+		},
+		{
+			name:       "service-account-in-anp",
+			appliedTo:  []secv1alpha1.NetworkPolicyPeer{{ServiceAccount: sa}},
+			namespaced: true,
+			expAllowed: false,
+		},
+		{
+			name:       "service-account-without-namespace",
+			ingress:    []secv1alpha1.Rule{{From: []secv1alpha1.NetworkPolicyPeer{{ServiceAccount: &secv1alpha1.NamespacedName{Name: "sa1"}}}}},
+			expAllowed: false,
+		},
+		{
+			name:       "service-account-with-pod-selector",
+			appliedTo:  []secv1alpha1.NetworkPolicyPeer{{ServiceAccount: sa, PodSelector: &selectorA}},
+			expAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := validateServiceAccountPeers(tt.appliedTo, tt.ingress, nil, tt.namespaced)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// shardedQueue is a set of rate limiting work queues, each of which is
+// consumed by a single worker. Items are assigned to a shard based on the
+// hash of their key, so that all the work for a given group (or internal
+// NetworkPolicy) is serialized on the same worker while distinct groups are
+// processed in parallel without contending on a single queue.
+type shardedQueue struct {
+	shards []workqueue.RateLimitingInterface
+}
+
+// shardedItem is implemented by queue items which are not plain string keys,
+// to expose the key used to select their shard.
+type shardedItem interface {
+	shardKey() string
+}
+
+func newShardedQueue(name string, numShards int) *shardedQueue {
+	if numShards < 1 {
+		numShards = 1
+	}
+	q := &shardedQueue{shards: make([]workqueue.RateLimitingInterface, numShards)}
+	for i := range q.shards {
+		q.shards[i] = workqueue.NewNamedRateLimitingQueue(
+			workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay),
+			fmt.Sprintf("%s-%d", name, i))
+	}
+	return q
+}
+
+func (q *shardedQueue) shardFor(item interface{}) workqueue.RateLimitingInterface {
+	var key string
+	switch i := item.(type) {
+	case string:
+		key = i
+	case shardedItem:
+		key = i.shardKey()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return q.shards[h.Sum32()%uint32(len(q.shards))]
+}
+
+// Add adds the item to the shard it belongs to.
+func (q *shardedQueue) Add(item interface{}) {
+	q.shardFor(item).Add(item)
+}
+
+// Get blocks until an item is available in the shard with the given index.
+func (q *shardedQueue) Get(shard int) (interface{}, bool) {
+	return q.shards[shard].Get()
+}
+
+// Done marks the item as done processing in the shard it belongs to.
+func (q *shardedQueue) Done(item interface{}) {
+	q.shardFor(item).Done(item)
+}
+
+// Forget stops tracking the retries of the item.
+func (q *shardedQueue) Forget(item interface{}) {
+	q.shardFor(item).Forget(item)
+}
+
+// AddRateLimited adds the item back to its shard after the rate limiter says
+// it's ok.
+func (q *shardedQueue) AddRateLimited(item interface{}) {
+	q.shardFor(item).AddRateLimited(item)
+}
+
+// Len returns the total number of items waiting in all the shards.
+func (q *shardedQueue) Len() int {
+	length := 0
+	for _, shard := range q.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+// NumShards returns the number of shards of the queue.
+func (q *shardedQueue) NumShards() int {
+	return len(q.shards)
+}
+
+// ShutDown shuts down all the shards.
+func (q *shardedQueue) ShutDown() {
+	for _, shard := range q.shards {
+		shard.ShutDown()
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedQueue(t *testing.T) {
+	q := newShardedQueue("test", 4)
+	defer q.ShutDown()
+	require.Equal(t, 4, q.NumShards())
+
+	for i := 0; i < 100; i++ {
+		q.Add(fmt.Sprintf("group-%d", i))
+	}
+	// Duplicated keys are coalesced.
+	q.Add("group-0")
+	assert.Equal(t, 100, q.Len())
+
+	// A key and its span update are always assigned to the same shard.
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("group-%d", i)
+		assert.Equal(t, q.shardFor(key), q.shardFor(addressGroupSpanKey(key)))
+	}
+
+	nonEmptyShards := 0
+	for shard := 0; shard < q.NumShards(); shard++ {
+		if q.shards[shard].Len() > 0 {
+			nonEmptyShards++
+		}
+		for q.shards[shard].Len() > 0 {
+			item, quit := q.Get(shard)
+			require.False(t, quit)
+			assert.Equal(t, q.shards[shard], q.shardFor(item))
+			q.Done(item)
+		}
+	}
+	assert.Equal(t, 4, nonEmptyShards, "Keys should be distributed across all shards")
+	assert.Equal(t, 0, q.Len())
+
+	q.Add(addressGroupSpanKey("group-0"))
+	q.Add("group-0")
+	assert.Equal(t, 2, q.Len(), "Span updates and full syncs are queued separately")
+}
@@ -0,0 +1,680 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+// SimulationOperation is the operation of a simulated policy change.
+type SimulationOperation string
+
+const (
+	SimulationCreate SimulationOperation = "Create"
+	SimulationUpdate SimulationOperation = "Update"
+	SimulationDelete SimulationOperation = "Delete"
+)
+
+// PolicySimulator simulates the impact of policy changes on the effective
+// connectivity of Pods.
+type PolicySimulator interface {
+	// SimulatePolicyChange computes the Pods whose connectivity would change if
+	// the operation was applied to the policy, which must be a K8s
+	// NetworkPolicy, an Antrea NetworkPolicy or an Antrea ClusterNetworkPolicy.
+	// Only the Namespace and the name of the policy are used for Delete.
+	SimulatePolicyChange(op SimulationOperation, policy metav1.Object) (*PolicySimulationResponse, error)
+}
+
+// PolicySimulationResponse is the reply struct for policy simulations.
+type PolicySimulationResponse struct {
+	// Pods are the Pods whose effective connectivity would change, sorted by
+	// Namespace and name.
+	Pods []PodConnectivityChange `json:"pods"`
+}
+
+// PodConnectivityChange describes how the connectivity of a Pod would change,
+// per peer Pod and per direction.
+type PodConnectivityChange struct {
+	Namespace string                   `json:"namespace"`
+	Name      string                   `json:"name"`
+	Ingress   []PeerConnectivityChange `json:"ingress,omitempty"`
+	Egress    []PeerConnectivityChange `json:"egress,omitempty"`
+}
+
+// PeerConnectivityChange describes the traffic with a peer Pod which would be
+// allowed (gained) or dropped (lost) after the change. The traffic is formatted
+// as "<protocol>/<port>", "<protocol>/*" for the other ports of the protocol,
+// or "*" for any other traffic.
+type PeerConnectivityChange struct {
+	Namespace   string   `json:"namespace"`
+	Name        string   `json:"name"`
+	GainedPorts []string `json:"gainedPorts,omitempty"`
+	LostPorts   []string `json:"lostPorts,omitempty"`
+}
+
+// simPort is a port of a rule. A nil port matches all the ports of the
+// protocol.
+type simPort struct {
+	protocol v1.Protocol
+	port     *intstr.IntOrString
+}
+
+// simRule is a rule of a simulated policy, whose peers are resolved to Pods.
+type simRule struct {
+	action secv1alpha1.RuleAction
+	// peers are the keys of the peer Pods. nil means any peer.
+	peers sets.String
+	// ports are the ports matched by the rule. Empty means any traffic.
+	ports []simPort
+}
+
+// simPolicy is a policy of the simulation, whose AppliedTo and peers are
+// resolved to Pods against the current cluster state.
+type simPolicy struct {
+	key          string
+	k8s          bool
+	tierPriority int32
+	priority     float64
+	appliedTo    sets.String
+	// isolated tells whether a K8s NetworkPolicy isolates the Pods it applies
+	// to in a direction, even if it has no rule in this direction.
+	isolated map[controlplane.Direction]bool
+	rules    map[controlplane.Direction][]simRule
+}
+
+// simTraffic is a class of traffic evaluated by the simulation. An empty
+// protocol stands for the traffic not matched by any port of the rules, a zero
+// port for the ports of the protocol not matched by any rule port.
+type simTraffic struct {
+	protocol v1.Protocol
+	port     int32
+}
+
+func (t simTraffic) String() string {
+	if t.protocol == "" {
+		return "*"
+	}
+	if t.port == 0 {
+		return fmt.Sprintf("%s/*", t.protocol)
+	}
+	return fmt.Sprintf("%s/%d", t.protocol, t.port)
+}
+
+// policySimulation evaluates policies against a snapshot of the Pods of the
+// cluster. Unlike the processing of the policy events, it doesn't create any
+// group or internal NetworkPolicy.
+type policySimulation struct {
+	n    *NetworkPolicyController
+	pods map[string]*v1.Pod
+}
+
+func podKey(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// simulationPolicyKey returns the key of a policy which is unique across the
+// policy types.
+func simulationPolicyKey(policy metav1.Object) (string, error) {
+	var policyType controlplane.NetworkPolicyType
+	switch policy.(type) {
+	case *networkingv1.NetworkPolicy:
+		policyType = controlplane.K8sNetworkPolicy
+	case *secv1alpha1.NetworkPolicy:
+		policyType = controlplane.AntreaNetworkPolicy
+	case *secv1alpha1.ClusterNetworkPolicy:
+		policyType = controlplane.AntreaClusterNetworkPolicy
+	default:
+		return "", fmt.Errorf("unsupported policy type %T", policy)
+	}
+	if policy.GetName() == "" {
+		return "", fmt.Errorf("policy name must be provided")
+	}
+	return fmt.Sprintf("%s/%s/%s", policyType, policy.GetNamespace(), policy.GetName()), nil
+}
+
+// SimulatePolicyChange implements PolicySimulator. The connectivity of each Pod
+// the policy applies to, before or after the change, is evaluated with all the
+// policies of the cluster, before and after the change, for every peer Pod and
+// for every port of the rules applied to the Pod.
+func (n *NetworkPolicyController) SimulatePolicyChange(op SimulationOperation, policy metav1.Object) (*PolicySimulationResponse, error) {
+	key, err := simulationPolicyKey(policy)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := policy.(*networkingv1.NetworkPolicy); !ok && n.anpLister == nil {
+		return nil, fmt.Errorf("Antrea-native policies cannot be simulated as the AntreaPolicy feature is disabled")
+	}
+	current, err := n.listSimulationPolicies()
+	if err != nil {
+		return nil, err
+	}
+	_, exists := current[key]
+	switch op {
+	case SimulationCreate:
+		if exists {
+			return nil, fmt.Errorf("policy %s already exists", key)
+		}
+	case SimulationUpdate, SimulationDelete:
+		if !exists {
+			return nil, fmt.Errorf("policy %s does not exist", key)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported operation %q, must be %q, %q or %q", op, SimulationCreate, SimulationUpdate, SimulationDelete)
+	}
+	proposed := make(map[string]metav1.Object, len(current)+1)
+	for k, p := range current {
+		proposed[k] = p
+	}
+	if op == SimulationDelete {
+		delete(proposed, key)
+	} else {
+		proposed[key] = policy
+	}
+
+	pods, err := n.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	s := &policySimulation{n: n, pods: make(map[string]*v1.Pod, len(pods))}
+	for _, pod := range pods {
+		// The traffic of hostNetwork Pods is not subject to the policies
+		// enforced by the Agents.
+		if pod.Spec.HostNetwork {
+			continue
+		}
+		s.pods[podKey(pod)] = pod
+	}
+	before, beforeByKey := s.buildPolicies(current)
+	after, afterByKey := s.buildPolicies(proposed)
+	// The policy change can only change the connectivity of the Pods the
+	// policy applies to.
+	affected := sets.NewString()
+	for _, policies := range []map[string]*simPolicy{beforeByKey, afterByKey} {
+		if p, ok := policies[key]; ok {
+			affected = affected.Union(p.appliedTo)
+		}
+	}
+
+	response := &PolicySimulationResponse{Pods: []PodConnectivityChange{}}
+	for _, target := range affected.List() {
+		pod, ok := s.pods[target]
+		if !ok {
+			continue
+		}
+		ingress := s.diffConnectivity(pod, controlplane.DirectionIn, before, after)
+		egress := s.diffConnectivity(pod, controlplane.DirectionOut, before, after)
+		if len(ingress) == 0 && len(egress) == 0 {
+			continue
+		}
+		response.Pods = append(response.Pods, PodConnectivityChange{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Ingress:   ingress,
+			Egress:    egress,
+		})
+	}
+	return response, nil
+}
+
+// listSimulationPolicies returns all the policies of the cluster, keyed by
+// simulationPolicyKey.
+func (n *NetworkPolicyController) listSimulationPolicies() (map[string]metav1.Object, error) {
+	var policies []metav1.Object
+	nps, err := n.networkPolicyLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, np := range nps {
+		policies = append(policies, np)
+	}
+	if n.anpLister != nil {
+		anps, err := n.anpLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, anp := range anps {
+			policies = append(policies, anp)
+		}
+		cnps, err := n.cnpLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, cnp := range cnps {
+			policies = append(policies, cnp)
+		}
+	}
+	policyByKey := make(map[string]metav1.Object, len(policies))
+	for _, policy := range policies {
+		key, _ := simulationPolicyKey(policy)
+		policyByKey[key] = policy
+	}
+	return policyByKey, nil
+}
+
+// buildPolicies resolves the policies against the Pods of the simulation. The
+// returned slice is sorted in the order in which the rules are enforced: the
+// Antrea-native policies by Tier priority and policy priority, followed by the
+// K8s NetworkPolicies.
+func (s *policySimulation) buildPolicies(policies map[string]metav1.Object) ([]*simPolicy, map[string]*simPolicy) {
+	sorted := make([]*simPolicy, 0, len(policies))
+	byKey := make(map[string]*simPolicy, len(policies))
+	for key, policy := range policies {
+		var p *simPolicy
+		switch policy := policy.(type) {
+		case *networkingv1.NetworkPolicy:
+			p = s.buildK8sPolicy(policy)
+		case *secv1alpha1.NetworkPolicy:
+			p = s.buildAntreaPolicy(policy, policy.Spec.Tier, policy.Spec.Priority, policy.Spec.AppliedTo, policy.Spec.Ingress, policy.Spec.Egress, policy.Spec.EnforcementMode)
+		case *secv1alpha1.ClusterNetworkPolicy:
+			p = s.buildAntreaPolicy(policy, policy.Spec.Tier, policy.Spec.Priority, policy.Spec.AppliedTo, policy.Spec.Ingress, policy.Spec.Egress, policy.Spec.EnforcementMode)
+		}
+		p.key = key
+		sorted = append(sorted, p)
+		byKey[key] = p
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		pi, pj := sorted[i], sorted[j]
+		if pi.k8s != pj.k8s {
+			return !pi.k8s
+		}
+		if pi.tierPriority != pj.tierPriority {
+			return pi.tierPriority < pj.tierPriority
+		}
+		if pi.priority != pj.priority {
+			return pi.priority < pj.priority
+		}
+		return pi.key < pj.key
+	})
+	return sorted, byKey
+}
+
+// selectPods returns the keys of the Pods of the simulation selected by the
+// selectors, using the same semantics as the groups of the policies.
+func (s *policySimulation) selectPods(namespace string, podSelector, nsSelector *metav1.LabelSelector) sets.String {
+	return s.selectGroupPods(toGroupSelector(namespace, podSelector, nsSelector, nil))
+}
+
+// selectGroupPods returns the keys of the Pods of the simulation selected by
+// the GroupSelector.
+func (s *policySimulation) selectGroupPods(groupSelector *antreatypes.GroupSelector) sets.String {
+	keys := sets.NewString()
+	pods, _ := s.n.processSelector(*groupSelector)
+	for _, pod := range pods {
+		if _, ok := s.pods[podKey(pod)]; ok {
+			keys.Insert(podKey(pod))
+		}
+	}
+	return keys
+}
+
+// selectPodsByCIDR returns the keys of the Pods of the simulation whose IP is
+// in the CIDR and in none of the excepted CIDRs.
+func (s *policySimulation) selectPodsByCIDR(cidr string, except []string) sets.String {
+	keys := sets.NewString()
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		klog.Errorf("Invalid CIDR %s in simulated policy: %v", cidr, err)
+		return keys
+	}
+	var exceptNets []*net.IPNet
+	for _, e := range except {
+		if _, exceptNet, err := net.ParseCIDR(e); err == nil {
+			exceptNets = append(exceptNets, exceptNet)
+		}
+	}
+	for key, pod := range s.pods {
+		podIP := net.ParseIP(pod.Status.PodIP)
+		if podIP == nil || !ipNet.Contains(podIP) {
+			continue
+		}
+		excepted := false
+		for _, exceptNet := range exceptNets {
+			if exceptNet.Contains(podIP) {
+				excepted = true
+				break
+			}
+		}
+		if !excepted {
+			keys.Insert(key)
+		}
+	}
+	return keys
+}
+
+func (s *policySimulation) buildK8sPolicy(np *networkingv1.NetworkPolicy) *simPolicy {
+	p := &simPolicy{
+		k8s:       true,
+		appliedTo: s.selectPods(np.Namespace, &np.Spec.PodSelector, nil),
+		isolated:  map[controlplane.Direction]bool{},
+		rules:     map[controlplane.Direction][]simRule{},
+	}
+	policyTypes := np.Spec.PolicyTypes
+	// The default PolicyTypes are only set by the K8s apiserver, so they must
+	// be computed for the proposed policies.
+	if len(policyTypes) == 0 {
+		policyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		if len(np.Spec.Egress) > 0 {
+			policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+		}
+	}
+	for _, policyType := range policyTypes {
+		if policyType == networkingv1.PolicyTypeIngress {
+			p.isolated[controlplane.DirectionIn] = true
+		} else if policyType == networkingv1.PolicyTypeEgress {
+			p.isolated[controlplane.DirectionOut] = true
+		}
+	}
+	for _, rule := range np.Spec.Ingress {
+		p.rules[controlplane.DirectionIn] = append(p.rules[controlplane.DirectionIn], s.buildK8sRule(np.Namespace, rule.From, rule.Ports))
+	}
+	for _, rule := range np.Spec.Egress {
+		p.rules[controlplane.DirectionOut] = append(p.rules[controlplane.DirectionOut], s.buildK8sRule(np.Namespace, rule.To, rule.Ports))
+	}
+	return p
+}
+
+func (s *policySimulation) buildK8sRule(namespace string, peers []networkingv1.NetworkPolicyPeer, ports []networkingv1.NetworkPolicyPort) simRule {
+	rule := simRule{action: secv1alpha1.RuleActionAllow}
+	for _, port := range ports {
+		rule.ports = append(rule.ports, newSimPort(port.Protocol, port.Port))
+	}
+	if len(peers) == 0 {
+		return rule
+	}
+	rule.peers = sets.NewString()
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			rule.peers = rule.peers.Union(s.selectPodsByCIDR(peer.IPBlock.CIDR, peer.IPBlock.Except))
+		} else {
+			rule.peers = rule.peers.Union(s.selectPods(namespace, peer.PodSelector, peer.NamespaceSelector))
+		}
+	}
+	return rule
+}
+
+func (s *policySimulation) buildAntreaPolicy(policy metav1.Object, tier string, priority float64, appliedTo []secv1alpha1.NetworkPolicyPeer, ingress, egress []secv1alpha1.Rule, mode secv1alpha1.EnforcementMode) *simPolicy {
+	p := &simPolicy{
+		tierPriority: s.n.getTierPriority(tier),
+		priority:     priority,
+		appliedTo:    sets.NewString(),
+		rules:        map[controlplane.Direction][]simRule{},
+	}
+	// An Antrea NetworkPolicy in an exempt Namespace is not enforced.
+	if policy.GetNamespace() != "" && s.n.isNamespaceExempt(policy.GetNamespace()) {
+		return p
+	}
+	for _, at := range appliedTo {
+		var keys sets.String
+		if at.ServiceAccount != nil {
+			keys = s.selectGroupPods(toServiceAccountGroupSelector(at.ServiceAccount))
+		} else if at.PodSelector != nil || at.NamespaceSelector != nil {
+			keys = s.selectPods(policy.GetNamespace(), at.PodSelector, at.NamespaceSelector)
+		}
+		// Nodes and ExternalEntities are not evaluated by the simulation.
+		for key := range keys {
+			if !s.n.isNamespaceExempt(s.pods[key].Namespace) {
+				p.appliedTo.Insert(key)
+			}
+		}
+	}
+	for i := range ingress {
+		if s.n.isRuleActive(&ingress[i]) {
+			p.rules[controlplane.DirectionIn] = append(p.rules[controlplane.DirectionIn], s.buildAntreaRule(policy.GetNamespace(), &ingress[i], ingress[i].From))
+		}
+	}
+	for i := range egress {
+		if s.n.isRuleActive(&egress[i]) {
+			p.rules[controlplane.DirectionOut] = append(p.rules[controlplane.DirectionOut], s.buildAntreaRule(policy.GetNamespace(), &egress[i], egress[i].To))
+		}
+	}
+	// The rules of a policy in Monitor mode are realized as Audit rules and
+	// never decide.
+	if mode == secv1alpha1.EnforcementModeMonitor {
+		for _, rules := range p.rules {
+			for i := range rules {
+				rules[i].action = secv1alpha1.RuleActionAudit
+			}
+		}
+	}
+	return p
+}
+
+func (s *policySimulation) buildAntreaRule(namespace string, r *secv1alpha1.Rule, peers []secv1alpha1.NetworkPolicyPeer) simRule {
+	rule := simRule{action: secv1alpha1.RuleActionAllow}
+	if r.Action != nil {
+		rule.action = *r.Action
+	}
+	for _, port := range r.Ports {
+		rule.ports = append(rule.ports, newSimPort(port.Protocol, port.Port))
+	}
+	// The ICMP types and codes, and the numbers of the other IP protocols, are
+	// not distinguished by the simulation.
+	for _, protocol := range r.Protocols {
+		if protocol.ICMP != nil {
+			rule.ports = append(rule.ports, simPort{protocol: v1.Protocol(controlplane.ProtocolICMP)})
+		} else if protocol.IGMP != nil {
+			rule.ports = append(rule.ports, simPort{protocol: v1.Protocol(controlplane.ProtocolIGMP)})
+		} else if protocol.IP != nil {
+			rule.ports = append(rule.ports, simPort{protocol: v1.Protocol(controlplane.ProtocolIP)})
+		}
+	}
+	// The ClusterIPs of the Services referenced by toServices don't select
+	// any Pod, but a rule with toServices doesn't match all peers either.
+	if len(peers) == 0 && len(r.ToServices) == 0 {
+		return rule
+	}
+	rule.peers = sets.NewString()
+	for _, peer := range s.n.expandGroupPeers(namespace, peers) {
+		if peer.IPBlock != nil {
+			rule.peers = rule.peers.Union(s.selectPodsByCIDR(peer.IPBlock.CIDR, nil))
+		} else if peer.ServiceAccount != nil {
+			rule.peers = rule.peers.Union(s.selectGroupPods(toServiceAccountGroupSelector(peer.ServiceAccount)))
+		} else if peer.PodSelector != nil || peer.NamespaceSelector != nil {
+			// FQDNs and ExternalEntities don't select any Pod.
+			rule.peers = rule.peers.Union(s.selectPods(namespace, peer.PodSelector, peer.NamespaceSelector))
+		}
+	}
+	return rule
+}
+
+func newSimPort(protocol *v1.Protocol, port *intstr.IntOrString) simPort {
+	p := simPort{protocol: v1.ProtocolTCP, port: port}
+	if protocol != nil {
+		p.protocol = *protocol
+	}
+	return p
+}
+
+// resolvePort returns the port number of a rule port on the destination Pod of
+// the traffic. It returns 0 if a named port cannot be resolved.
+func resolvePort(port *intstr.IntOrString, protocol v1.Protocol, dst *v1.Pod) int32 {
+	if port.Type == intstr.Int {
+		return port.IntVal
+	}
+	for _, container := range dst.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			containerProtocol := containerPort.Protocol
+			if containerProtocol == "" {
+				containerProtocol = v1.ProtocolTCP
+			}
+			if containerPort.Name == port.StrVal && containerProtocol == protocol {
+				return containerPort.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+// matches returns true if the rule matches the traffic with the peer Pod. dst is
+// the destination Pod of the traffic, used to resolve the named ports.
+func (r *simRule) matches(peer string, t simTraffic, dst *v1.Pod) bool {
+	if r.peers != nil && !r.peers.Has(peer) {
+		return false
+	}
+	if len(r.ports) == 0 {
+		return true
+	}
+	for _, port := range r.ports {
+		if port.protocol != t.protocol {
+			continue
+		}
+		if port.port == nil {
+			return true
+		}
+		if t.port != 0 && resolvePort(port.port, port.protocol, dst) == t.port {
+			return true
+		}
+	}
+	return false
+}
+
+// simRules are the rules applied to a Pod in a direction.
+type simRules struct {
+	// antrea are the rules of the Antrea-native policies, in the order in
+	// which they are enforced.
+	antrea []*simRule
+	k8s    []*simRule
+	// isolated is true if any K8s NetworkPolicy isolates the Pod.
+	isolated bool
+}
+
+func rulesForPod(policies []*simPolicy, pod string, direction controlplane.Direction) *simRules {
+	rules := &simRules{}
+	for _, p := range policies {
+		if !p.appliedTo.Has(pod) {
+			continue
+		}
+		if p.k8s {
+			if !p.isolated[direction] {
+				continue
+			}
+			rules.isolated = true
+		}
+		for i := range p.rules[direction] {
+			if p.k8s {
+				rules.k8s = append(rules.k8s, &p.rules[direction][i])
+			} else {
+				rules.antrea = append(rules.antrea, &p.rules[direction][i])
+			}
+		}
+	}
+	return rules
+}
+
+// allows returns true if the traffic with the peer Pod is allowed: the first
+// matching rule of the Antrea-native policies decides, then the K8s
+// NetworkPolicies allow the traffic if the Pod is not isolated or if any of
+// their rules matches. Audit rules are not enforced and never decide.
+func (r *simRules) allows(peer string, t simTraffic, dst *v1.Pod) bool {
+	for _, rule := range r.antrea {
+		if rule.action == secv1alpha1.RuleActionAudit {
+			continue
+		}
+		if rule.matches(peer, t, dst) {
+			return rule.action == secv1alpha1.RuleActionAllow
+		}
+	}
+	if !r.isolated {
+		return true
+	}
+	for _, rule := range r.k8s {
+		if rule.matches(peer, t, dst) {
+			return true
+		}
+	}
+	return false
+}
+
+// trafficCandidates returns the classes of traffic with the peer which can be
+// handled differently by the rules: every port of the rules, the other ports
+// of their protocols, and any other traffic.
+func trafficCandidates(dst *v1.Pod, rules ...*simRules) []simTraffic {
+	candidates := map[simTraffic]bool{{}: true}
+	for _, r := range rules {
+		for _, rule := range append(append([]*simRule{}, r.antrea...), r.k8s...) {
+			for _, port := range rule.ports {
+				candidates[simTraffic{protocol: port.protocol}] = true
+				if port.port != nil {
+					if number := resolvePort(port.port, port.protocol, dst); number != 0 {
+						candidates[simTraffic{protocol: port.protocol, port: number}] = true
+					}
+				}
+			}
+		}
+	}
+	traffic := make([]simTraffic, 0, len(candidates))
+	for t := range candidates {
+		traffic = append(traffic, t)
+	}
+	sort.Slice(traffic, func(i, j int) bool {
+		if traffic[i].protocol != traffic[j].protocol {
+			return traffic[i].protocol < traffic[j].protocol
+		}
+		return traffic[i].port < traffic[j].port
+	})
+	return traffic
+}
+
+// diffConnectivity compares the traffic allowed between the Pod and every
+// other Pod in a direction, before and after the change.
+func (s *policySimulation) diffConnectivity(pod *v1.Pod, direction controlplane.Direction, before, after []*simPolicy) []PeerConnectivityChange {
+	target := podKey(pod)
+	beforeRules := rulesForPod(before, target, direction)
+	afterRules := rulesForPod(after, target, direction)
+	peerKeys := make([]string, 0, len(s.pods))
+	for key := range s.pods {
+		if key != target {
+			peerKeys = append(peerKeys, key)
+		}
+	}
+	sort.Strings(peerKeys)
+	var changes []PeerConnectivityChange
+	for _, peerKey := range peerKeys {
+		peer := s.pods[peerKey]
+		// The named ports are resolved on the destination Pod.
+		dst := pod
+		if direction == controlplane.DirectionOut {
+			dst = peer
+		}
+		change := PeerConnectivityChange{Namespace: peer.Namespace, Name: peer.Name}
+		for _, t := range trafficCandidates(dst, beforeRules, afterRules) {
+			allowedBefore := beforeRules.allows(peerKey, t, dst)
+			allowedAfter := afterRules.allows(peerKey, t, dst)
+			if !allowedBefore && allowedAfter {
+				change.GainedPorts = append(change.GainedPorts, t.String())
+			} else if allowedBefore && !allowedAfter {
+				change.LostPorts = append(change.LostPorts, t.String())
+			}
+		}
+		if len(change.GainedPorts) > 0 || len(change.LostPorts) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
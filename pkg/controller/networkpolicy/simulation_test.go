@@ -0,0 +1,156 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+func newSimulationPod(namespace, name, ip string, labels map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:  "c1",
+				Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 80, Protocol: v1.ProtocolTCP}},
+			}},
+		},
+		Status: v1.PodStatus{PodIP: ip},
+	}
+}
+
+func newSimulationController() *networkPolicyController {
+	_, npc := newController()
+	npc.anpLister = npc.crdInformerFactory.Security().V1alpha1().NetworkPolicies().Lister()
+	npc.cnpLister = npc.crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies().Lister()
+	npc.groupLister = npc.crdInformerFactory.Core().V1alpha1().Groups().Lister()
+	npc.namespaceStore.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"env": "prod"}}})
+	npc.namespaceStore.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2", Labels: map[string]string{"env": "dev"}}})
+	npc.podStore.Add(newSimulationPod("ns1", "web", "10.0.0.1", map[string]string{"app": "web"}))
+	npc.podStore.Add(newSimulationPod("ns1", "client", "10.0.0.2", map[string]string{"app": "client"}))
+	npc.podStore.Add(newSimulationPod("ns2", "other", "10.0.1.1", map[string]string{"app": "client"}))
+	return npc
+}
+
+func TestSimulateK8sNetworkPolicyCreate(t *testing.T) {
+	npc := newSimulationController()
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "allow-client"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}}},
+				Ports: []networkingv1.NetworkPolicyPort{{Port: &strHTTP}},
+			}},
+		},
+	}
+	response, err := npc.SimulatePolicyChange(SimulationCreate, np)
+	require.NoError(t, err)
+	// The client Pod of the same Namespace is only allowed to reach the named
+	// port, and the Pod of the other Namespace is isolated.
+	assert.Equal(t, &PolicySimulationResponse{Pods: []PodConnectivityChange{{
+		Namespace: "ns1",
+		Name:      "web",
+		Ingress: []PeerConnectivityChange{
+			{Namespace: "ns1", Name: "client", LostPorts: []string{"*", "TCP/*"}},
+			{Namespace: "ns2", Name: "other", LostPorts: []string{"*", "TCP/*", "TCP/80"}},
+		},
+	}}}, response)
+
+	// Deleting the policy restores the connectivity.
+	npc.networkPolicyStore.Add(np)
+	response, err = npc.SimulatePolicyChange(SimulationDelete, &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "allow-client"}})
+	require.NoError(t, err)
+	require.Len(t, response.Pods, 1)
+	assert.Equal(t, []PeerConnectivityChange{
+		{Namespace: "ns1", Name: "client", GainedPorts: []string{"*", "TCP/*"}},
+		{Namespace: "ns2", Name: "other", GainedPorts: []string{"*", "TCP/*", "TCP/80"}},
+	}, response.Pods[0].Ingress)
+}
+
+func TestSimulateClusterNetworkPolicyUpdate(t *testing.T) {
+	npc := newSimulationController()
+	dropAction := secv1alpha1.RuleActionDrop
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "isolate-web"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}},
+			Priority:  1,
+			Egress: []secv1alpha1.Rule{{
+				Action: &dropAction,
+				To:     []secv1alpha1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}}},
+			}},
+		},
+	}
+	npc.crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies().Informer().GetStore().Add(cnp)
+
+	// The update drops the traffic to the ipBlock of the ns1 Pods instead of
+	// the dev Namespaces.
+	updatedCNP := cnp.DeepCopy()
+	updatedCNP.Spec.Egress[0].To = []secv1alpha1.NetworkPolicyPeer{{IPBlock: &secv1alpha1.IPBlock{CIDR: "10.0.0.0/24"}}}
+	response, err := npc.SimulatePolicyChange(SimulationUpdate, updatedCNP)
+	require.NoError(t, err)
+	assert.Equal(t, &PolicySimulationResponse{Pods: []PodConnectivityChange{{
+		Namespace: "ns1",
+		Name:      "web",
+		Egress: []PeerConnectivityChange{
+			{Namespace: "ns1", Name: "client", LostPorts: []string{"*"}},
+			{Namespace: "ns2", Name: "other", GainedPorts: []string{"*"}},
+		},
+	}}}, response)
+}
+
+func TestSimulateAuditRule(t *testing.T) {
+	npc := newSimulationController()
+	auditAction := secv1alpha1.RuleActionAudit
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit-web"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}},
+			Priority:  1,
+			Ingress:   []secv1alpha1.Rule{{Action: &auditAction}},
+		},
+	}
+	// Audit rules are not enforced, so they never change the connectivity.
+	response, err := npc.SimulatePolicyChange(SimulationCreate, cnp)
+	require.NoError(t, err)
+	assert.Empty(t, response.Pods)
+}
+
+func TestSimulatePolicyChangeErrors(t *testing.T) {
+	npc := newSimulationController()
+	np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "np1"}}
+	_, err := npc.SimulatePolicyChange(SimulationUpdate, np)
+	assert.Error(t, err, "Updating a non-existing policy should fail")
+	_, err = npc.SimulatePolicyChange(SimulationDelete, np)
+	assert.Error(t, err, "Deleting a non-existing policy should fail")
+	_, err = npc.SimulatePolicyChange("Patch", np)
+	assert.Error(t, err, "Unsupported operations should fail")
+	npc.networkPolicyStore.Add(np)
+	_, err = npc.SimulatePolicyChange(SimulationCreate, np)
+	assert.Error(t, err, "Creating an existing policy should fail")
+
+	npc.anpLister = nil
+	_, err = npc.SimulatePolicyChange(SimulationCreate, &secv1alpha1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "anp1"}})
+	assert.Error(t, err, "Antrea-native policies cannot be simulated without the AntreaPolicy feature")
+}
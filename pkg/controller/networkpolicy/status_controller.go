@@ -0,0 +1,413 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/apiserver/storage"
+	"github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
+	secinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/security/v1alpha1"
+	seclisters "github.com/vmware-tanzu/antrea/pkg/client/listers/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
+)
+
+const (
+	statusControllerName = "NetworkPolicyStatusController"
+	// Default number of workers updating the status of the policies.
+	defaultStatusWorkers = 2
+
+	reasonRuleRealizationFailed = "RuleRealizationFailed"
+)
+
+// StatusController aggregates the realization statuses of the internal
+// NetworkPolicies reported by the antrea-agents, and reflects them in the
+// status of the Antrea ClusterNetworkPolicies and Antrea NetworkPolicies.
+// A policy is realized when all the Nodes in the span of its internal
+// NetworkPolicy have realized its current generation. The rules which some
+// Nodes failed to realize are reported in the status and in Events of the
+// policy.
+type StatusController struct {
+	crdClient versioned.Interface
+	// queue maintains the keys of the internal NetworkPolicies whose
+	// status needs to be synced.
+	queue workqueue.RateLimitingInterface
+	// internalNetworkPolicyStore is the source of the desired state of the
+	// internal NetworkPolicies: their generation and span.
+	internalNetworkPolicyStore storage.Interface
+
+	// statuses maps the key of an internal NetworkPolicy to the statuses
+	// reported by the Nodes, keyed by Node name.
+	statuses      map[string]map[string]*controlplane.NetworkPolicyNodeStatus
+	statusesMutex sync.Mutex
+
+	cnpLister       seclisters.ClusterNetworkPolicyLister
+	cnpListerSynced cache.InformerSynced
+	anpLister       seclisters.NetworkPolicyLister
+	anpListerSynced cache.InformerSynced
+
+	// eventRecorder records the Events reporting the rules which failed to
+	// be realized. It may be nil.
+	eventRecorder record.EventRecorder
+}
+
+// NewStatusController returns a new *StatusController.
+func NewStatusController(crdClient versioned.Interface,
+	internalNetworkPolicyStore storage.Interface,
+	cnpInformer secinformers.ClusterNetworkPolicyInformer,
+	anpInformer secinformers.NetworkPolicyInformer,
+	eventRecorder record.EventRecorder) *StatusController {
+	return &StatusController{
+		crdClient:                  crdClient,
+		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicystatus"),
+		internalNetworkPolicyStore: internalNetworkPolicyStore,
+		statuses:                   map[string]map[string]*controlplane.NetworkPolicyNodeStatus{},
+		cnpLister:                  cnpInformer.Lister(),
+		cnpListerSynced:            cnpInformer.Informer().HasSynced,
+		anpLister:                  anpInformer.Lister(),
+		anpListerSynced:            anpInformer.Informer().HasSynced,
+		eventRecorder:              eventRecorder,
+	}
+}
+
+// UpdateStatus records the statuses reported by antrea-agents for an internal
+// NetworkPolicy. The statuses of a NetworkPolicy which no longer exists are
+// ignored.
+func (c *StatusController) UpdateStatus(status *controlplane.NetworkPolicyStatus) error {
+	key := k8s.NamespacedName(status.Namespace, status.Name)
+	_, exists, _ := c.internalNetworkPolicyStore.Get(key)
+	if !exists {
+		klog.V(2).Infof("Ignoring status of NetworkPolicy %s as it no longer exists", key)
+		return nil
+	}
+	func() {
+		c.statusesMutex.Lock()
+		defer c.statusesMutex.Unlock()
+		nodeStatuses, exists := c.statuses[key]
+		if !exists {
+			nodeStatuses = map[string]*controlplane.NetworkPolicyNodeStatus{}
+			c.statuses[key] = nodeStatuses
+		}
+		for i := range status.Nodes {
+			nodeStatus := status.Nodes[i]
+			nodeStatuses[nodeStatus.NodeName] = &nodeStatus
+		}
+	}()
+	c.queue.Add(key)
+	return nil
+}
+
+// Run begins watching the internal NetworkPolicies and spawns workers that
+// sync the status of the policies. Run will not return until stopCh is closed.
+func (c *StatusController) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s", statusControllerName)
+	defer klog.Infof("Shutting down %s", statusControllerName)
+
+	if !cache.WaitForNamedCacheSync(statusControllerName, stopCh, c.cnpListerSynced, c.anpListerSynced) {
+		return
+	}
+
+	go wait.Until(func() { c.watchInternalNetworkPolicies(stopCh) }, 5*time.Second, stopCh)
+
+	for i := 0; i < defaultStatusWorkers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+// watchInternalNetworkPolicies enqueues the internal NetworkPolicies whenever
+// they are created, updated or deleted, as their generation and span determine
+// their status. It returns when the watch is terminated.
+func (c *StatusController) watchInternalNetworkPolicies(stopCh <-chan struct{}) {
+	w, err := c.internalNetworkPolicyStore.Watch(context.TODO(), "", labels.Everything(), fields.Everything())
+	if err != nil {
+		klog.Errorf("Failed to watch internal NetworkPolicies: %v", err)
+		return
+	}
+	defer w.Stop()
+	for {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			if event.Type == watch.Bookmark {
+				continue
+			}
+			policy := event.Object.(*controlplane.NetworkPolicy)
+			c.queue.Add(k8s.NamespacedName(policy.Namespace, policy.Name))
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (c *StatusController) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *StatusController) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.syncHandler(key.(string)); err != nil {
+		klog.Errorf("Failed to sync status of NetworkPolicy %s: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *StatusController) syncHandler(key string) error {
+	obj, exists, _ := c.internalNetworkPolicyStore.Get(key)
+	if !exists {
+		c.statusesMutex.Lock()
+		delete(c.statuses, key)
+		c.statusesMutex.Unlock()
+		return nil
+	}
+	internalNP := obj.(*antreatypes.NetworkPolicy)
+	// K8s NetworkPolicies don't have a status.
+	if internalNP.SourceRef.Type == controlplane.K8sNetworkPolicy {
+		return nil
+	}
+
+	desiredNodes := len(internalNP.NodeNames)
+	currentNodes := 0
+	failedRules := map[failedRuleKey]*secv1alpha1.FailedRuleStatus{}
+	func() {
+		c.statusesMutex.Lock()
+		defer c.statusesMutex.Unlock()
+		for nodeName, nodeStatus := range c.statuses[key] {
+			// The statuses of the Nodes which are no longer in the span
+			// are removed, so that they are not mistaken for up-to-date
+			// statuses if the Nodes join the span again.
+			if !internalNP.NodeNames.Has(nodeName) {
+				delete(c.statuses[key], nodeName)
+				continue
+			}
+			if nodeStatus.Generation != internalNP.Generation {
+				continue
+			}
+			if len(nodeStatus.FailedRules) == 0 {
+				currentNodes++
+				continue
+			}
+			for _, failure := range nodeStatus.FailedRules {
+				addFailedRule(failedRules, nodeName, &failure)
+			}
+		}
+	}()
+
+	status := &secv1alpha1.NetworkPolicyStatus{
+		Phase:                secv1alpha1.NetworkPolicyRealizing,
+		ObservedGeneration:   internalNP.Generation,
+		CurrentNodesRealized: int32(currentNodes),
+		DesiredNodesRealized: int32(desiredNodes),
+		FailedRules:          sortedFailedRules(failedRules),
+	}
+	if len(status.FailedRules) > 0 {
+		status.Phase = secv1alpha1.NetworkPolicyFailed
+	} else if currentNodes == desiredNodes {
+		status.Phase = secv1alpha1.NetworkPolicyRealized
+	}
+
+	switch internalNP.SourceRef.Type {
+	case controlplane.AntreaClusterNetworkPolicy:
+		return c.updateCNPStatus(internalNP.SourceRef.Name, status)
+	case controlplane.AntreaNetworkPolicy:
+		return c.updateANPStatus(internalNP.SourceRef.Namespace, internalNP.SourceRef.Name, status)
+	}
+	return nil
+}
+
+type failedRuleKey struct {
+	direction secv1alpha1.RuleDirection
+	index     int32
+}
+
+// addFailedRule adds the failure of a rule reported by a Node to the failures
+// reported by the other Nodes for the same rule. The message of the first Node
+// in alphabetical order is kept so that the status is stable.
+func addFailedRule(failedRules map[failedRuleKey]*secv1alpha1.FailedRuleStatus, nodeName string, failure *controlplane.NetworkPolicyRuleFailure) {
+	key := failedRuleKey{direction: secv1alpha1.RuleDirectionIngress, index: failure.Priority}
+	if failure.Direction == controlplane.DirectionOut {
+		key.direction = secv1alpha1.RuleDirectionEgress
+	}
+	failedRule, exists := failedRules[key]
+	if !exists {
+		failedRule = &secv1alpha1.FailedRuleStatus{Direction: key.direction, Index: key.index}
+		failedRules[key] = failedRule
+	}
+	failedRule.FailedNodes++
+	if failedRule.NodeName == "" || nodeName < failedRule.NodeName {
+		failedRule.NodeName = nodeName
+		failedRule.Message = failure.Message
+	}
+}
+
+func sortedFailedRules(failedRules map[failedRuleKey]*secv1alpha1.FailedRuleStatus) []secv1alpha1.FailedRuleStatus {
+	if len(failedRules) == 0 {
+		return nil
+	}
+	sorted := make([]secv1alpha1.FailedRuleStatus, 0, len(failedRules))
+	for _, failedRule := range failedRules {
+		sorted = append(sorted, *failedRule)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Direction != sorted[j].Direction {
+			// Ingress rules first.
+			return sorted[i].Direction == secv1alpha1.RuleDirectionIngress
+		}
+		return sorted[i].Index < sorted[j].Index
+	})
+	return sorted
+}
+
+// realizationStatusEqual returns true if the realization fields of the
+// statuses are equal. The other fields are owned by other controllers.
+func realizationStatusEqual(s1, s2 *secv1alpha1.NetworkPolicyStatus) bool {
+	return s1.Phase == s2.Phase &&
+		s1.ObservedGeneration == s2.ObservedGeneration &&
+		s1.CurrentNodesRealized == s2.CurrentNodesRealized &&
+		s1.DesiredNodesRealized == s2.DesiredNodesRealized &&
+		equality.Semantic.DeepEqual(s1.FailedRules, s2.FailedRules)
+}
+
+// skipStatusUpdate returns true if the status of the policy must not be updated
+// with the computed status. The internal NetworkPolicy may be computed from a
+// newer version of the policy than the one in the informer cache, in which
+// case an error is returned so that the update is retried, or from an older
+// one, in which case the status is updated once the internal NetworkPolicy has
+// caught up.
+func skipStatusUpdate(meta *metav1.ObjectMeta, current, status *secv1alpha1.NetworkPolicyStatus) (bool, error) {
+	if meta.Generation < status.ObservedGeneration {
+		return true, fmt.Errorf("generation %d of the policy is not observed yet", status.ObservedGeneration)
+	}
+	if meta.Generation > status.ObservedGeneration {
+		return true, nil
+	}
+	return realizationStatusEqual(current, status), nil
+}
+
+func setRealizationStatus(dst, src *secv1alpha1.NetworkPolicyStatus) {
+	dst.Phase = src.Phase
+	dst.ObservedGeneration = src.ObservedGeneration
+	dst.CurrentNodesRealized = src.CurrentNodesRealized
+	dst.DesiredNodesRealized = src.DesiredNodesRealized
+	dst.FailedRules = src.FailedRules
+}
+
+// recordFailedRuleEvents records a Warning Event for each rule of the policy
+// which newly failed to be realized, or whose failure changed.
+// The references are built explicitly as the Antrea types are not registered
+// in the scheme of the EventRecorder.
+func (c *StatusController) recordFailedRuleEvents(policy *corev1.ObjectReference, oldFailedRules, newFailedRules []secv1alpha1.FailedRuleStatus) {
+	if c.eventRecorder == nil {
+		return
+	}
+	oldMessages := make(map[failedRuleKey]string, len(oldFailedRules))
+	for _, failedRule := range oldFailedRules {
+		oldMessages[failedRuleKey{failedRule.Direction, failedRule.Index}] = failedRule.Message
+	}
+	for _, failedRule := range newFailedRules {
+		if message, exists := oldMessages[failedRuleKey{failedRule.Direction, failedRule.Index}]; exists && message == failedRule.Message {
+			continue
+		}
+		c.eventRecorder.Eventf(policy, corev1.EventTypeWarning, reasonRuleRealizationFailed,
+			"%s rule %d failed to be realized on %d Nodes, error on Node %s: %s",
+			failedRule.Direction, failedRule.Index, failedRule.FailedNodes, failedRule.NodeName, failedRule.Message)
+	}
+}
+
+func (c *StatusController) updateCNPStatus(name string, status *secv1alpha1.NetworkPolicyStatus) error {
+	cnp, err := c.cnpLister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if skip, err := skipStatusUpdate(&cnp.ObjectMeta, &cnp.Status, status); skip {
+		return err
+	}
+	toUpdate := cnp.DeepCopy()
+	setRealizationStatus(&toUpdate.Status, status)
+	if _, err := c.crdClient.SecurityV1alpha1().ClusterNetworkPolicies().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating status of ClusterNetworkPolicy %s: %v", name, err)
+	}
+	ref := &corev1.ObjectReference{
+		APIVersion: secv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "ClusterNetworkPolicy",
+		Name:       cnp.Name,
+		UID:        cnp.UID,
+	}
+	c.recordFailedRuleEvents(ref, cnp.Status.FailedRules, status.FailedRules)
+	return nil
+}
+
+func (c *StatusController) updateANPStatus(namespace, name string, status *secv1alpha1.NetworkPolicyStatus) error {
+	anp, err := c.anpLister.NetworkPolicies(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if skip, err := skipStatusUpdate(&anp.ObjectMeta, &anp.Status, status); skip {
+		return err
+	}
+	toUpdate := anp.DeepCopy()
+	setRealizationStatus(&toUpdate.Status, status)
+	if _, err := c.crdClient.SecurityV1alpha1().NetworkPolicies(namespace).UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating status of Antrea NetworkPolicy %s/%s: %v", namespace, name, err)
+	}
+	ref := &corev1.ObjectReference{
+		APIVersion: secv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "NetworkPolicy",
+		Namespace:  anp.Namespace,
+		Name:       anp.Name,
+		UID:        anp.UID,
+	}
+	c.recordFailedRuleEvents(ref, anp.Status.FailedRules, status.FailedRules)
+	return nil
+}
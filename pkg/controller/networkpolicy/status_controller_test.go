@@ -0,0 +1,202 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane"
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions"
+	"github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy/store"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+func newNodeStatus(policyName, nodeName string, generation int64) *controlplane.NetworkPolicyStatus {
+	return &controlplane.NetworkPolicyStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: policyName},
+		Nodes:      []controlplane.NetworkPolicyNodeStatus{{NodeName: nodeName, Generation: generation}},
+	}
+}
+
+func TestStatusControllerSyncCNP(t *testing.T) {
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA", Generation: 2},
+		Status: secv1alpha1.NetworkPolicyStatus{
+			ScheduledRules: []secv1alpha1.ScheduledRuleStatus{{Direction: secv1alpha1.RuleDirectionEgress, Active: true}},
+		},
+	}
+	crdClient := fakeversioned.NewSimpleClientset(cnp)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, informerDefaultResync)
+	cnpInformer := crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
+	cnpInformer.Informer().GetStore().Add(cnp)
+	internalNetworkPolicyStore := store.NewNetworkPolicyStore()
+	c := NewStatusController(crdClient, internalNetworkPolicyStore, cnpInformer, crdInformerFactory.Security().V1alpha1().NetworkPolicies(), nil)
+
+	internalNetworkPolicyStore.Create(&antreatypes.NetworkPolicy{
+		SpanMeta:   antreatypes.SpanMeta{NodeNames: sets.NewString("node1", "node2")},
+		Name:       "cnpA",
+		UID:        "uidA",
+		Generation: 2,
+		SourceRef:  &controlplane.NetworkPolicyReference{Type: controlplane.AntreaClusterNetworkPolicy, Name: "cnpA", UID: "uidA"},
+	})
+	getStatus := func() secv1alpha1.NetworkPolicyStatus {
+		require.NoError(t, c.syncHandler("cnpA"))
+		obj, err := crdClient.SecurityV1alpha1().ClusterNetworkPolicies().Get(context.TODO(), "cnpA", metav1.GetOptions{})
+		require.NoError(t, err)
+		return obj.Status
+	}
+
+	// A Node which has realized a previous generation is not counted.
+	require.NoError(t, c.UpdateStatus(newNodeStatus("cnpA", "node1", 2)))
+	require.NoError(t, c.UpdateStatus(newNodeStatus("cnpA", "node2", 1)))
+	status := getStatus()
+	assert.Equal(t, secv1alpha1.NetworkPolicyRealizing, status.Phase)
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.Equal(t, int32(1), status.CurrentNodesRealized)
+	assert.Equal(t, int32(2), status.DesiredNodesRealized)
+	// The fields owned by other controllers are preserved.
+	assert.Equal(t, cnp.Status.ScheduledRules, status.ScheduledRules)
+
+	require.NoError(t, c.UpdateStatus(newNodeStatus("cnpA", "node2", 2)))
+	status = getStatus()
+	assert.Equal(t, secv1alpha1.NetworkPolicyRealized, status.Phase)
+	assert.Equal(t, int32(2), status.CurrentNodesRealized)
+
+	// The statuses of the Nodes out of the span are removed.
+	require.NoError(t, c.UpdateStatus(newNodeStatus("cnpA", "node3", 2)))
+	require.NoError(t, c.syncHandler("cnpA"))
+	assert.Len(t, c.statuses["cnpA"], 2)
+
+	// The statuses of a deleted policy are ignored and removed.
+	internalNetworkPolicyStore.Delete("cnpA")
+	require.NoError(t, c.UpdateStatus(newNodeStatus("cnpA", "node1", 2)))
+	require.NoError(t, c.syncHandler("cnpA"))
+	assert.NotContains(t, c.statuses, "cnpA")
+}
+
+func TestStatusControllerSyncANPStaleGeneration(t *testing.T) {
+	anp := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "anpA", UID: "uidA", Generation: 1},
+	}
+	crdClient := fakeversioned.NewSimpleClientset(anp)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, informerDefaultResync)
+	anpInformer := crdInformerFactory.Security().V1alpha1().NetworkPolicies()
+	anpInformer.Informer().GetStore().Add(anp)
+	internalNetworkPolicyStore := store.NewNetworkPolicyStore()
+	c := NewStatusController(crdClient, internalNetworkPolicyStore, crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(), anpInformer, nil)
+
+	internalNetworkPolicyStore.Create(&antreatypes.NetworkPolicy{
+		SpanMeta:   antreatypes.SpanMeta{NodeNames: sets.NewString("node1")},
+		Name:       "anpA",
+		Namespace:  "ns1",
+		UID:        "uidA",
+		Generation: 2,
+		SourceRef:  &controlplane.NetworkPolicyReference{Type: controlplane.AntreaNetworkPolicy, Namespace: "ns1", Name: "anpA", UID: "uidA"},
+	})
+	status := newNodeStatus("anpA", "node1", 2)
+	status.Namespace = "ns1"
+	require.NoError(t, c.UpdateStatus(status))
+	// The informer cache hasn't observed the generation yet, the update
+	// must be retried.
+	assert.Error(t, c.syncHandler("ns1/anpA"))
+
+	anp.Generation = 2
+	anpInformer.Informer().GetStore().Update(anp)
+	require.NoError(t, c.syncHandler("ns1/anpA"))
+	obj, err := crdClient.SecurityV1alpha1().NetworkPolicies("ns1").Get(context.TODO(), "anpA", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, secv1alpha1.NetworkPolicyStatus{
+		Phase:                secv1alpha1.NetworkPolicyRealized,
+		ObservedGeneration:   2,
+		CurrentNodesRealized: 1,
+		DesiredNodesRealized: 1,
+	}, obj.Status)
+}
+
+func TestStatusControllerSyncFailedRules(t *testing.T) {
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA", Generation: 1},
+	}
+	crdClient := fakeversioned.NewSimpleClientset(cnp)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, informerDefaultResync)
+	cnpInformer := crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
+	cnpInformer.Informer().GetStore().Add(cnp)
+	internalNetworkPolicyStore := store.NewNetworkPolicyStore()
+	recorder := record.NewFakeRecorder(10)
+	c := NewStatusController(crdClient, internalNetworkPolicyStore, cnpInformer, crdInformerFactory.Security().V1alpha1().NetworkPolicies(), recorder)
+
+	internalNetworkPolicyStore.Create(&antreatypes.NetworkPolicy{
+		SpanMeta:   antreatypes.SpanMeta{NodeNames: sets.NewString("node1", "node2", "node3")},
+		Name:       "cnpA",
+		UID:        "uidA",
+		Generation: 1,
+		SourceRef:  &controlplane.NetworkPolicyReference{Type: controlplane.AntreaClusterNetworkPolicy, Name: "cnpA", UID: "uidA"},
+	})
+	newFailedNodeStatus := func(nodeName, message string) *controlplane.NetworkPolicyStatus {
+		status := newNodeStatus("cnpA", nodeName, 1)
+		status.Nodes[0].FailedRules = []controlplane.NetworkPolicyRuleFailure{
+			{Direction: controlplane.DirectionOut, Priority: 1, Message: message},
+		}
+		return status
+	}
+	getStatus := func() secv1alpha1.NetworkPolicyStatus {
+		require.NoError(t, c.syncHandler("cnpA"))
+		obj, err := crdClient.SecurityV1alpha1().ClusterNetworkPolicies().Get(context.TODO(), "cnpA", metav1.GetOptions{})
+		require.NoError(t, err)
+		cnpInformer.Informer().GetStore().Update(obj)
+		return obj.Status
+	}
+
+	// The failures of the same rule are aggregated, keeping the message of
+	// the first Node in alphabetical order.
+	require.NoError(t, c.UpdateStatus(newNodeStatus("cnpA", "node1", 1)))
+	require.NoError(t, c.UpdateStatus(newFailedNodeStatus("node3", "error3")))
+	require.NoError(t, c.UpdateStatus(newFailedNodeStatus("node2", "error2")))
+	assert.Equal(t, secv1alpha1.NetworkPolicyStatus{
+		Phase:                secv1alpha1.NetworkPolicyFailed,
+		ObservedGeneration:   1,
+		CurrentNodesRealized: 1,
+		DesiredNodesRealized: 3,
+		FailedRules: []secv1alpha1.FailedRuleStatus{
+			{Direction: secv1alpha1.RuleDirectionEgress, Index: 1, FailedNodes: 2, NodeName: "node2", Message: "error2"},
+		},
+	}, getStatus())
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning RuleRealizationFailed Egress rule 1 failed to be realized on 2 Nodes, error on Node node2: error2", <-recorder.Events)
+
+	// No Event is recorded when only the number of failed Nodes changes.
+	require.NoError(t, c.UpdateStatus(newFailedNodeStatus("node1", "error2")))
+	assert.Equal(t, int32(3), getStatus().FailedRules[0].FailedNodes)
+	assert.Empty(t, recorder.Events)
+
+	// The policy is realized once the Nodes have realized the rule.
+	for _, nodeName := range []string{"node1", "node2", "node3"} {
+		require.NoError(t, c.UpdateStatus(newNodeStatus("cnpA", nodeName, 1)))
+	}
+	status := getStatus()
+	assert.Equal(t, secv1alpha1.NetworkPolicyRealized, status.Phase)
+	assert.Equal(t, int32(3), status.CurrentNodesRealized)
+	assert.Empty(t, status.FailedRules)
+	assert.Empty(t, recorder.Events)
+}
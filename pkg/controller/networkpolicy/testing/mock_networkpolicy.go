@@ -14,7 +14,7 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy (interfaces: EndpointQuerier)
+// Source: github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy (interfaces: EndpointQuerier,PolicySimulator)
 
 // Package testing is a generated GoMock package.
 package testing
@@ -22,6 +22,7 @@ package testing
 import (
 	gomock "github.com/golang/mock/gomock"
 	networkpolicy "github.com/vmware-tanzu/antrea/pkg/controller/networkpolicy"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	reflect "reflect"
 )
 
@@ -48,6 +49,21 @@ func (m *MockEndpointQuerier) EXPECT() *MockEndpointQuerierMockRecorder {
 	return m.recorder
 }
 
+// QueryEffectiveRules mocks base method
+func (m *MockEndpointQuerier) QueryEffectiveRules(arg0, arg1 string) (*networkpolicy.EffectiveRulesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryEffectiveRules", arg0, arg1)
+	ret0, _ := ret[0].(*networkpolicy.EffectiveRulesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryEffectiveRules indicates an expected call of QueryEffectiveRules
+func (mr *MockEndpointQuerierMockRecorder) QueryEffectiveRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryEffectiveRules", reflect.TypeOf((*MockEndpointQuerier)(nil).QueryEffectiveRules), arg0, arg1)
+}
+
 // QueryNetworkPolicies mocks base method
 func (m *MockEndpointQuerier) QueryNetworkPolicies(arg0, arg1 string) (*networkpolicy.EndpointQueryResponse, error) {
 	m.ctrl.T.Helper()
@@ -62,3 +78,41 @@ func (mr *MockEndpointQuerierMockRecorder) QueryNetworkPolicies(arg0, arg1 inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryNetworkPolicies", reflect.TypeOf((*MockEndpointQuerier)(nil).QueryNetworkPolicies), arg0, arg1)
 }
+
+// MockPolicySimulator is a mock of PolicySimulator interface
+type MockPolicySimulator struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicySimulatorMockRecorder
+}
+
+// MockPolicySimulatorMockRecorder is the mock recorder for MockPolicySimulator
+type MockPolicySimulatorMockRecorder struct {
+	mock *MockPolicySimulator
+}
+
+// NewMockPolicySimulator creates a new mock instance
+func NewMockPolicySimulator(ctrl *gomock.Controller) *MockPolicySimulator {
+	mock := &MockPolicySimulator{ctrl: ctrl}
+	mock.recorder = &MockPolicySimulatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPolicySimulator) EXPECT() *MockPolicySimulatorMockRecorder {
+	return m.recorder
+}
+
+// SimulatePolicyChange mocks base method
+func (m *MockPolicySimulator) SimulatePolicyChange(arg0 networkpolicy.SimulationOperation, arg1 v1.Object) (*networkpolicy.PolicySimulationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulatePolicyChange", arg0, arg1)
+	ret0, _ := ret[0].(*networkpolicy.PolicySimulationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulatePolicyChange indicates an expected call of SimulatePolicyChange
+func (mr *MockPolicySimulatorMockRecorder) SimulatePolicyChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulatePolicyChange", reflect.TypeOf((*MockPolicySimulator)(nil).SimulatePolicyChange), arg0, arg1)
+}
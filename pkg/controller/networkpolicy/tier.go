@@ -20,11 +20,13 @@ package networkpolicy
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
 
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
@@ -143,3 +145,83 @@ func (n *NetworkPolicyController) initTier(t *secv1alpha1.Tier) {
 		return
 	}
 }
+
+// addTier receives Tier ADD events and re-processes the Antrea Policies
+// associated with the Tier. The policies may have been processed before the
+// Tier was created, e.g. when antrea-controller processes existing policies
+// before the system generated Tiers are initialized.
+func (n *NetworkPolicyController) addTier(obj interface{}) {
+	defer n.heartbeat("addTier")
+	t := obj.(*secv1alpha1.Tier)
+	klog.V(2).Infof("Processing Tier %s ADD event", t.Name)
+	n.reprocessPoliciesForTier(t)
+}
+
+// updateTier receives Tier UPDATE events and re-processes the Antrea Policies
+// associated with the Tier if its priority changed, so that the new ordering
+// is propagated to the agents.
+func (n *NetworkPolicyController) updateTier(oldObj, curObj interface{}) {
+	defer n.heartbeat("updateTier")
+	oldT := oldObj.(*secv1alpha1.Tier)
+	curT := curObj.(*secv1alpha1.Tier)
+	klog.V(2).Infof("Processing Tier %s UPDATE event", curT.Name)
+	if oldT.Spec.Priority == curT.Spec.Priority {
+		klog.V(4).Infof("No change in Tier %s priority. Skipping Antrea Policy evaluation.", curT.Name)
+		return
+	}
+	klog.Infof("Priority of Tier %s changed from %d to %d", curT.Name, oldT.Spec.Priority, curT.Spec.Priority)
+	n.reprocessPoliciesForTier(curT)
+}
+
+// tierReferenceNames returns the names with which Antrea Policies can refer
+// to the Tier: its name, the name of the corresponding static tier if any, and
+// the empty name for the default Tier.
+func tierReferenceNames(name string) []string {
+	names := []string{name}
+	for staticTier := range staticTierSet {
+		if strings.ToLower(staticTier) == name {
+			names = append(names, staticTier)
+		}
+	}
+	if priorityMap[name] == defaultTierPriority {
+		names = append(names, "")
+	}
+	return names
+}
+
+// reprocessPoliciesForTier re-computes the internal NetworkPolicies of the
+// Antrea Policies associated with the Tier, so that their TierPriority
+// reflects the current priority of the Tier.
+func (n *NetworkPolicyController) reprocessPoliciesForTier(t *secv1alpha1.Tier) {
+	for _, name := range tierReferenceNames(t.Name) {
+		n.reprocessIndexedPolicies(n.cnpInformer.Informer(), TierIndex, name, func(obj interface{}) {
+			cnp := obj.(*secv1alpha1.ClusterNetworkPolicy)
+			klog.V(2).Infof("Re-processing ClusterNetworkPolicy %s in Tier %s", cnp.Name, t.Name)
+			n.updateCNP(cnp, cnp)
+		})
+		n.reprocessIndexedPolicies(n.anpInformer.Informer(), TierIndex, name, func(obj interface{}) {
+			anp := obj.(*secv1alpha1.NetworkPolicy)
+			klog.V(2).Infof("Re-processing Antrea NetworkPolicy %s/%s in Tier %s", anp.Namespace, anp.Name, t.Name)
+			n.updateANP(anp, anp)
+		})
+	}
+}
+
+// reprocessIndexedPolicies re-processes the Antrea Policies of the informer
+// whose index indexName contains indexedValue.
+func (n *NetworkPolicyController) reprocessIndexedPolicies(informer cache.SharedIndexInformer, indexName, indexedValue string, update func(obj interface{})) {
+	policies, err := informer.GetIndexer().ByIndex(indexName, indexedValue)
+	if err != nil {
+		klog.Errorf("Failed to get Antrea Policies by index %s %s: %v", indexName, indexedValue, err)
+		return
+	}
+	for _, obj := range policies {
+		key, _ := keyFunc(obj)
+		// Antrea Policies which haven't been processed yet will get the
+		// current state when their ADD event is processed.
+		if _, exists, _ := n.internalNetworkPolicyStore.Get(key); !exists {
+			continue
+		}
+		update(obj)
+	}
+}
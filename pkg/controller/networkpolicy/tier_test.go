@@ -0,0 +1,154 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	antreatypes "github.com/vmware-tanzu/antrea/pkg/controller/types"
+)
+
+func newTierTestController() *networkPolicyController {
+	_, npc := newController()
+	npc.tierInformer = npc.crdInformerFactory.Security().V1alpha1().Tiers()
+	npc.tierInformer.Informer().AddIndexers(cache.Indexers{
+		PriorityIndex: func(obj interface{}) ([]string, error) {
+			return []string{strconv.FormatInt(int64(obj.(*secv1alpha1.Tier).Spec.Priority), 10)}, nil
+		},
+	})
+	npc.cnpInformer = npc.crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies()
+	npc.cnpInformer.Informer().AddIndexers(cache.Indexers{
+		TierIndex: func(obj interface{}) ([]string, error) {
+			return []string{obj.(*secv1alpha1.ClusterNetworkPolicy).Spec.Tier}, nil
+		},
+	})
+	npc.anpInformer = npc.crdInformerFactory.Security().V1alpha1().NetworkPolicies()
+	npc.anpInformer.Informer().AddIndexers(cache.Indexers{
+		TierIndex: func(obj interface{}) ([]string, error) {
+			return []string{obj.(*secv1alpha1.NetworkPolicy).Spec.Tier}, nil
+		},
+	})
+	return npc
+}
+
+func TestTierUpdatesPolicies(t *testing.T) {
+	npc := newTierTestController()
+	tierStore := npc.tierInformer.Informer().GetStore()
+	allowAction := secv1alpha1.RuleActionAllow
+	rules := []secv1alpha1.Rule{{From: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorB}}, Action: &allowAction}}
+	cnp := &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cnpA", UID: "uidA"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			Priority:  1,
+			Tier:      "mytier",
+			Ingress:   rules,
+		},
+	}
+	anp := &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "npB", UID: "uidB"},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{PodSelector: &selectorA}},
+			Priority:  1,
+			Tier:      "mytier",
+			Ingress:   rules,
+		},
+	}
+	otherANP := anp.DeepCopy()
+	otherANP.Name, otherANP.UID, otherANP.Spec.Tier = "npC", "uidC", ""
+	npc.cnpInformer.Informer().GetStore().Add(cnp)
+	npc.addCNP(cnp)
+	for _, np := range []*secv1alpha1.NetworkPolicy{anp, otherANP} {
+		npc.anpInformer.Informer().GetStore().Add(np)
+		npc.addANP(np)
+	}
+
+	getTierPriority := func(key string) int32 {
+		obj, _, _ := npc.internalNetworkPolicyStore.Get(key)
+		return *obj.(*antreatypes.NetworkPolicy).TierPriority
+	}
+	// The policies are processed before the Tier is created.
+	assert.Equal(t, defaultTierPriority, getTierPriority("cnpA"))
+
+	tier := &secv1alpha1.Tier{
+		ObjectMeta: metav1.ObjectMeta{Name: "mytier"},
+		Spec:       secv1alpha1.TierSpec{Priority: 10},
+	}
+	tierStore.Add(tier)
+	npc.addTier(tier)
+	assert.Equal(t, int32(10), getTierPriority("cnpA"))
+	assert.Equal(t, int32(10), getTierPriority("ns1/npB"))
+
+	updatedTier := tier.DeepCopy()
+	updatedTier.Spec.Priority = 20
+	tierStore.Update(updatedTier)
+	npc.updateTier(tier, updatedTier)
+	assert.Equal(t, int32(20), getTierPriority("cnpA"))
+	assert.Equal(t, int32(20), getTierPriority("ns1/npB"))
+	// Policies of other Tiers are not affected.
+	assert.Equal(t, defaultTierPriority, getTierPriority("ns1/npC"))
+}
+
+func TestTierReferenceNames(t *testing.T) {
+	assert.ElementsMatch(t, []string{"mytier"}, tierReferenceNames("mytier"))
+	assert.ElementsMatch(t, []string{"emergency", "Emergency"}, tierReferenceNames("emergency"))
+	assert.ElementsMatch(t, []string{"application", "Application", ""}, tierReferenceNames("application"))
+}
+
+func TestValidateTier(t *testing.T) {
+	npc := newTierTestController()
+	for _, tier := range systemGeneratedTiers {
+		npc.tierInformer.Informer().GetStore().Add(tier)
+	}
+	tierA := &secv1alpha1.Tier{
+		ObjectMeta: metav1.ObjectMeta{Name: "tier-a"},
+		Spec:       secv1alpha1.TierSpec{Priority: 10},
+	}
+	npc.tierInformer.Informer().GetStore().Add(tierA)
+	newTier := func(name string, priority int32) *secv1alpha1.Tier {
+		return &secv1alpha1.Tier{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: secv1alpha1.TierSpec{Priority: priority}}
+	}
+	tests := []struct {
+		name       string
+		op         admv1.Operation
+		curTier    *secv1alpha1.Tier
+		oldTier    *secv1alpha1.Tier
+		expAllowed bool
+	}{
+		{"create", admv1.Create, newTier("tier-b", 20), &secv1alpha1.Tier{}, true},
+		{"create-reserved-priority", admv1.Create, newTier("tier-b", 251), &secv1alpha1.Tier{}, false},
+		{"create-overlapping-priority", admv1.Create, newTier("tier-b", 10), &secv1alpha1.Tier{}, false},
+		{"update-description", admv1.Update, newTier("platform", 150), newTier("platform", 150), true},
+		{"update-priority", admv1.Update, newTier("tier-a", 20), tierA, true},
+		{"update-reserved-tier-priority", admv1.Update, newTier("platform", 160), newTier("platform", 150), false},
+		{"update-reserved-priority", admv1.Update, newTier("tier-a", 255), tierA, false},
+		{"update-overlapping-priority", admv1.Update, newTier("tier-a", 5), tierA, false},
+		{"delete-reserved-tier", admv1.Delete, &secv1alpha1.Tier{}, newTier("emergency", 5), false},
+	}
+	v := NewNetworkPolicyValidator(npc.NetworkPolicyController)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, allowed := v.validateTier(tt.curTier, tt.oldTier, tt.op)
+			assert.Equal(t, tt.expAllowed, allowed)
+		})
+	}
+}
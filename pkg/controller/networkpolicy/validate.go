@@ -17,14 +17,19 @@ package networkpolicy
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"strconv"
+	"strings"
 
 	admv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog"
 
+	corev1a1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/k8s"
 )
 
 var (
@@ -48,7 +53,7 @@ func NewNetworkPolicyValidator(networkPolicyController *NetworkPolicyController)
 	}
 }
 
-// Validate function validates a Tier or Antrea Policy object
+// Validate function validates a Tier, Group or Antrea Policy object
 func (v *NetworkPolicyValidator) Validate(ar *admv1.AdmissionReview) *admv1.AdmissionResponse {
 	var result *metav1.Status
 	var msg string
@@ -88,7 +93,7 @@ func (v *NetworkPolicyValidator) Validate(ar *admv1.AdmissionReview) *admv1.Admi
 				return GetAdmissionResponseForErr(err)
 			}
 		}
-		msg, allowed = v.validateAntreaPolicy(op, curCNP.Spec.Tier)
+		msg, allowed = v.validateAntreaPolicy(op, curCNP.Spec.Tier, curCNP.Spec.AppliedTo, curCNP.Spec.Ingress, curCNP.Spec.Egress, false)
 	case "NetworkPolicy":
 		klog.V(2).Info("Validating Antrea NetworkPolicy CRD")
 		var curANP, oldANP secv1alpha1.NetworkPolicy
@@ -104,7 +109,17 @@ func (v *NetworkPolicyValidator) Validate(ar *admv1.AdmissionReview) *admv1.Admi
 				return GetAdmissionResponseForErr(err)
 			}
 		}
-		msg, allowed = v.validateAntreaPolicy(op, curANP.Spec.Tier)
+		msg, allowed = v.validateAntreaPolicy(op, curANP.Spec.Tier, curANP.Spec.AppliedTo, curANP.Spec.Ingress, curANP.Spec.Egress, true)
+	case "Group":
+		klog.V(2).Info("Validating Group CRD")
+		var curGroup corev1a1.Group
+		if curRaw != nil {
+			if err := json.Unmarshal(curRaw, &curGroup); err != nil {
+				klog.Errorf("Error de-serializing current Group")
+				return GetAdmissionResponseForErr(err)
+			}
+		}
+		msg, allowed = v.validateGroup(&curGroup)
 	}
 	if msg != "" {
 		result = &metav1.Status{
@@ -117,12 +132,42 @@ func (v *NetworkPolicyValidator) Validate(ar *admv1.AdmissionReview) *admv1.Admi
 	}
 }
 
-// validateAntreaPolicy validates the admission of a Antrea NetworkPolicy CRDs
-func (v *NetworkPolicyValidator) validateAntreaPolicy(op admv1.Operation, tier string) (string, bool) {
+// validateAntreaPolicy validates the admission of a Antrea NetworkPolicy CRDs.
+// Groups can only be referenced by the peers of namespaced policies, and Nodes
+// can only be selected by the AppliedTo of cluster scoped policies.
+func (v *NetworkPolicyValidator) validateAntreaPolicy(op admv1.Operation, tier string, appliedTo []secv1alpha1.NetworkPolicyPeer, ingress, egress []secv1alpha1.Rule, namespaced bool) (string, bool) {
 	allowed := true
 	reason := ""
 	switch op {
 	case admv1.Create, admv1.Update:
+		// Rule schedules must be well-formed.
+		if reason, allowed = validateRuleSchedules(ingress, egress); !allowed {
+			break
+		}
+		if reason, allowed = validateGroupPeers(ingress, egress, namespaced); !allowed {
+			break
+		}
+		if reason, allowed = validateFQDNPeers(ingress, egress, namespaced); !allowed {
+			break
+		}
+		if reason, allowed = validateServicePeers(ingress, egress, namespaced); !allowed {
+			break
+		}
+		if reason, allowed = validateNodeSelectorPeers(appliedTo, ingress, egress, namespaced); !allowed {
+			break
+		}
+		if reason, allowed = validateServiceAccountPeers(appliedTo, ingress, egress, namespaced); !allowed {
+			break
+		}
+		if reason, allowed = validateRuleProtocols(ingress, egress); !allowed {
+			break
+		}
+		if reason, allowed = validateIPBlockExcepts(ingress, egress); !allowed {
+			break
+		}
+		if reason, allowed = validateRuleRateLimits(ingress, egress); !allowed {
+			break
+		}
 		// "tier" must exist before referencing
 		if tier == "" || staticTierSet.Has(tier) {
 			// Empty Tier name corresponds to default Tier
@@ -159,11 +204,27 @@ func (v *NetworkPolicyValidator) validateTier(curTier, oldTier *secv1alpha1.Tier
 			return fmt.Sprintf("tier %s priority %d overlaps with existing Tier", curTier.Name, curTier.Spec.Priority), false
 		}
 	case admv1.Update:
-		// Tier priority updates are not allowed
 		klog.V(2).Info("Validating UPDATE request for Tier")
-		if curTier.Spec.Priority != oldTier.Spec.Priority {
-			allowed = false
-			reason = "update to Tier priority is not allowed"
+		if curTier.Spec.Priority == oldTier.Spec.Priority {
+			break
+		}
+		// Priority of reserved tiers cannot be updated
+		if reservedTierNames.Has(curTier.Name) {
+			return fmt.Sprintf("update to priority of reserved tier %s is not allowed", curTier.Name), false
+		}
+		// Tier priority must not overlap reserved tier's priority
+		if reservedTierPriorities.Has(curTier.Spec.Priority) {
+			return fmt.Sprintf("tier %s priority %d is reserved", curTier.Name, curTier.Spec.Priority), false
+		}
+		// Tier priority must not overlap other tier's priority
+		trs, err := v.networkPolicyController.tierInformer.Informer().GetIndexer().ByIndex(PriorityIndex, strconv.FormatInt(int64(curTier.Spec.Priority), 10))
+		if err != nil {
+			return fmt.Sprintf("failed to check priority of tier %s: %v", curTier.Name, err), false
+		}
+		for _, obj := range trs {
+			if obj.(*secv1alpha1.Tier).Name != curTier.Name {
+				return fmt.Sprintf("tier %s priority %d overlaps with existing Tier", curTier.Name, curTier.Spec.Priority), false
+			}
 		}
 	case admv1.Delete:
 		klog.V(2).Info("Validating DELETE request for Tier")
@@ -186,6 +247,392 @@ func (v *NetworkPolicyValidator) validateTier(curTier, oldTier *secv1alpha1.Tier
 	return reason, allowed
 }
 
+// validateRuleSchedules validates the Schedule of the ingress and egress rules
+// of an Antrea Policy.
+func validateRuleSchedules(ingress, egress []secv1alpha1.Rule) (string, bool) {
+	for idx, rule := range ingress {
+		if rule.Schedule == nil {
+			continue
+		}
+		if err := validateRuleSchedule(rule.Schedule); err != nil {
+			return fmt.Sprintf("invalid schedule for ingress rule %d: %v", idx, err), false
+		}
+	}
+	for idx, rule := range egress {
+		if rule.Schedule == nil {
+			continue
+		}
+		if err := validateRuleSchedule(rule.Schedule); err != nil {
+			return fmt.Sprintf("invalid schedule for egress rule %d: %v", idx, err), false
+		}
+	}
+	return "", true
+}
+
+// reservedIPProtocols maps the IP protocol numbers which cannot be matched
+// with the ip field of a rule protocol to the fields matching them.
+var reservedIPProtocols = map[int32]string{
+	1:   "icmp",
+	2:   "igmp",
+	6:   "ports",
+	17:  "ports",
+	132: "ports",
+}
+
+// validateRuleProtocols validates the non-port protocols of the ingress and
+// egress rules of an Antrea Policy. Each protocol must set exactly one of ICMP,
+// IGMP and IP, and the ICMP type and code must be valid, the code being only
+// allowed with a type. The IP protocol number must be valid, and must not be
+// one of the protocols which have their own fields: TCP, UDP and SCTP are
+// matched with ports, ICMP and IGMP with the icmp and igmp fields.
+func validateRuleProtocols(ingress, egress []secv1alpha1.Rule) (string, bool) {
+	validateProtocols := func(protocols []secv1alpha1.NetworkPolicyProtocol) string {
+		for _, protocol := range protocols {
+			set := 0
+			for _, isSet := range []bool{protocol.ICMP != nil, protocol.IGMP != nil, protocol.IP != nil} {
+				if isSet {
+					set++
+				}
+			}
+			if set != 1 {
+				return "exactly one of icmp, igmp and ip must be set"
+			}
+			if protocol.IP != nil {
+				number := protocol.IP.Protocol
+				if number < 0 || number > 255 {
+					return fmt.Sprintf("ip protocol %d must be between 0 and 255", number)
+				}
+				if field, ok := reservedIPProtocols[number]; ok {
+					return fmt.Sprintf("ip protocol %d must be matched with %s", number, field)
+				}
+				continue
+			}
+			if protocol.ICMP == nil {
+				continue
+			}
+			icmpType, icmpCode := protocol.ICMP.ICMPType, protocol.ICMP.ICMPCode
+			if icmpType != nil && (*icmpType < 0 || *icmpType > 255) {
+				return fmt.Sprintf("icmpType %d must be between 0 and 255", *icmpType)
+			}
+			if icmpCode != nil {
+				if icmpType == nil {
+					return "icmpCode cannot be set without icmpType"
+				}
+				if *icmpCode < 0 || *icmpCode > 255 {
+					return fmt.Sprintf("icmpCode %d must be between 0 and 255", *icmpCode)
+				}
+			}
+		}
+		return ""
+	}
+	for idx, rule := range ingress {
+		if msg := validateProtocols(rule.Protocols); msg != "" {
+			return fmt.Sprintf("invalid protocol for ingress rule %d: %s", idx, msg), false
+		}
+	}
+	for idx, rule := range egress {
+		if msg := validateProtocols(rule.Protocols); msg != "" {
+			return fmt.Sprintf("invalid protocol for egress rule %d: %s", idx, msg), false
+		}
+	}
+	return "", true
+}
+
+// validateIPBlockExcepts validates the except CIDRs of the IPBlock peers in the
+// ingress and egress rules of an Antrea Policy. They can only be set in the
+// rules with the Allow action, and must be within the CIDR of the IPBlock.
+func validateIPBlockExcepts(ingress, egress []secv1alpha1.Rule) (string, bool) {
+	validatePeers := func(rule *secv1alpha1.Rule, peers []secv1alpha1.NetworkPolicyPeer) string {
+		for _, peer := range peers {
+			if peer.IPBlock == nil || len(peer.IPBlock.Except) == 0 {
+				continue
+			}
+			if rule.Action == nil || *rule.Action != secv1alpha1.RuleActionAllow {
+				return "ipBlock except can only be set in the rules with the Allow action"
+			}
+			_, cidr, err := net.ParseCIDR(peer.IPBlock.CIDR)
+			if err != nil {
+				return fmt.Sprintf("invalid ipBlock cidr %s: %v", peer.IPBlock.CIDR, err)
+			}
+			cidrOnes, cidrBits := cidr.Mask.Size()
+			for _, except := range peer.IPBlock.Except {
+				_, exceptNet, err := net.ParseCIDR(except)
+				if err != nil {
+					return fmt.Sprintf("invalid ipBlock except %s: %v", except, err)
+				}
+				exceptOnes, exceptBits := exceptNet.Mask.Size()
+				if exceptBits != cidrBits || exceptOnes < cidrOnes || !cidr.Contains(exceptNet.IP) {
+					return fmt.Sprintf("ipBlock except %s must be within the cidr %s", except, peer.IPBlock.CIDR)
+				}
+			}
+		}
+		return ""
+	}
+	for idx := range ingress {
+		if msg := validatePeers(&ingress[idx], ingress[idx].From); msg != "" {
+			return fmt.Sprintf("invalid peer for ingress rule %d: %s", idx, msg), false
+		}
+	}
+	for idx := range egress {
+		if msg := validatePeers(&egress[idx], egress[idx].To); msg != "" {
+			return fmt.Sprintf("invalid peer for egress rule %d: %s", idx, msg), false
+		}
+	}
+	return "", true
+}
+
+// validateRuleRateLimits validates the rate limits of the ingress and egress
+// rules of an Antrea Policy. They can only be set in the rules with the Allow
+// action, as the connections matched by the other rules are never accepted.
+func validateRuleRateLimits(ingress, egress []secv1alpha1.Rule) (string, bool) {
+	validateRule := func(rule *secv1alpha1.Rule) string {
+		if rule.RateLimit == nil {
+			return ""
+		}
+		if rule.Action == nil || *rule.Action != secv1alpha1.RuleActionAllow {
+			return "rateLimit can only be set in the rules with the Allow action"
+		}
+		if rule.RateLimit.MaxNewConnectionsPerSecond <= 0 {
+			return "maxNewConnectionsPerSecond must be greater than 0"
+		}
+		if rule.RateLimit.Burst < 0 {
+			return "burst cannot be negative"
+		}
+		return ""
+	}
+	for idx := range ingress {
+		if msg := validateRule(&ingress[idx]); msg != "" {
+			return fmt.Sprintf("invalid rateLimit for ingress rule %d: %s", idx, msg), false
+		}
+	}
+	for idx := range egress {
+		if msg := validateRule(&egress[idx]); msg != "" {
+			return fmt.Sprintf("invalid rateLimit for egress rule %d: %s", idx, msg), false
+		}
+	}
+	return "", true
+}
+
+// validateGroupPeers validates the peers referencing a Group in the ingress and
+// egress rules of an Antrea Policy. A peer referencing a Group cannot set any
+// other field, as the members of the peer are defined by the Group.
+func validateGroupPeers(ingress, egress []secv1alpha1.Rule, namespaced bool) (string, bool) {
+	validatePeers := func(peers []secv1alpha1.NetworkPolicyPeer) string {
+		for _, peer := range peers {
+			if peer.Group == "" {
+				continue
+			}
+			if !namespaced {
+				return "group cannot be set in the rules of Antrea ClusterNetworkPolicies"
+			}
+			if peer.IPBlock != nil || peer.PodSelector != nil || peer.NamespaceSelector != nil || peer.ExternalEntitySelector != nil {
+				return fmt.Sprintf("group %s cannot be set with other peer fields", peer.Group)
+			}
+		}
+		return ""
+	}
+	for idx, rule := range ingress {
+		if msg := validatePeers(rule.From); msg != "" {
+			return fmt.Sprintf("invalid peer for ingress rule %d: %s", idx, msg), false
+		}
+	}
+	for idx, rule := range egress {
+		if msg := validatePeers(rule.To); msg != "" {
+			return fmt.Sprintf("invalid peer for egress rule %d: %s", idx, msg), false
+		}
+	}
+	return "", true
+}
+
+// validateFQDNPeers validates the peers selecting a FQDN in the ingress and
+// egress rules of an Antrea Policy. FQDNs can only be selected by the egress
+// rules of Antrea ClusterNetworkPolicies, and must be valid domain names,
+// optionally starting with the "*." wildcard.
+func validateFQDNPeers(ingress, egress []secv1alpha1.Rule, namespaced bool) (string, bool) {
+	for idx, rule := range ingress {
+		for _, peer := range rule.From {
+			if peer.FQDN != "" {
+				return fmt.Sprintf("invalid peer for ingress rule %d: fqdn can only be set in egress rules", idx), false
+			}
+		}
+	}
+	for idx, rule := range egress {
+		for _, peer := range rule.To {
+			if peer.FQDN == "" {
+				continue
+			}
+			if namespaced {
+				return fmt.Sprintf("invalid peer for egress rule %d: fqdn can only be set in the rules of Antrea ClusterNetworkPolicies", idx), false
+			}
+			if peer.IPBlock != nil || peer.PodSelector != nil || peer.NamespaceSelector != nil || peer.ExternalEntitySelector != nil || peer.Group != "" {
+				return fmt.Sprintf("invalid peer for egress rule %d: fqdn %s cannot be set with other peer fields", idx, peer.FQDN), false
+			}
+			if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(peer.FQDN, "*.")); len(errs) > 0 {
+				return fmt.Sprintf("invalid peer for egress rule %d: invalid fqdn %s: %s", idx, peer.FQDN, strings.Join(errs, ", ")), false
+			}
+		}
+	}
+	return "", true
+}
+
+// validateServicePeers validates the Services referenced by the toServices of
+// the rules of an Antrea Policy. toServices can only be set in egress rules,
+// and the rules of Antrea NetworkPolicies can only reference Services of their
+// own Namespace, while the rules of Antrea ClusterNetworkPolicies must specify
+// the Namespace of the Services.
+func validateServicePeers(ingress, egress []secv1alpha1.Rule, namespaced bool) (string, bool) {
+	for idx, rule := range ingress {
+		if len(rule.ToServices) > 0 {
+			return fmt.Sprintf("invalid ingress rule %d: toServices can only be set in egress rules", idx), false
+		}
+	}
+	for idx, rule := range egress {
+		for _, ref := range rule.ToServices {
+			if ref.Name == "" {
+				return fmt.Sprintf("invalid egress rule %d: name must be set in toServices", idx), false
+			}
+			if namespaced && ref.Namespace != "" {
+				return fmt.Sprintf("invalid egress rule %d: namespace of Service %s cannot be set in the rules of Antrea NetworkPolicies", idx, ref.Name), false
+			}
+			if !namespaced && ref.Namespace == "" {
+				return fmt.Sprintf("invalid egress rule %d: namespace of Service %s must be set in the rules of Antrea ClusterNetworkPolicies", idx, ref.Name), false
+			}
+		}
+	}
+	return "", true
+}
+
+// validateNodeSelectorPeers validates the peers selecting Nodes in an Antrea
+// Policy. Nodes can only be selected by the AppliedTo of Antrea
+// ClusterNetworkPolicies, and a peer selecting Nodes cannot set any other
+// field.
+func validateNodeSelectorPeers(appliedTo []secv1alpha1.NetworkPolicyPeer, ingress, egress []secv1alpha1.Rule, namespaced bool) (string, bool) {
+	for _, peer := range appliedTo {
+		if peer.NodeSelector == nil {
+			continue
+		}
+		if namespaced {
+			return "invalid appliedTo: nodeSelector can only be set in Antrea ClusterNetworkPolicies", false
+		}
+		if peer.IPBlock != nil || peer.PodSelector != nil || peer.NamespaceSelector != nil || peer.ExternalEntitySelector != nil || peer.Group != "" || peer.FQDN != "" {
+			return "invalid appliedTo: nodeSelector cannot be set with other peer fields", false
+		}
+	}
+	for idx, rule := range ingress {
+		for _, peer := range rule.From {
+			if peer.NodeSelector != nil {
+				return fmt.Sprintf("invalid peer for ingress rule %d: nodeSelector can only be set in appliedTo", idx), false
+			}
+		}
+	}
+	for idx, rule := range egress {
+		for _, peer := range rule.To {
+			if peer.NodeSelector != nil {
+				return fmt.Sprintf("invalid peer for egress rule %d: nodeSelector can only be set in appliedTo", idx), false
+			}
+		}
+	}
+	return "", true
+}
+
+// validateServiceAccountPeers validates the peers selecting Pods by
+// ServiceAccount in an Antrea Policy. ServiceAccounts can only be selected by
+// Antrea ClusterNetworkPolicies, and a peer selecting a ServiceAccount must set
+// both its name and Namespace, and cannot set any other field.
+func validateServiceAccountPeers(appliedTo []secv1alpha1.NetworkPolicyPeer, ingress, egress []secv1alpha1.Rule, namespaced bool) (string, bool) {
+	validatePeers := func(peers []secv1alpha1.NetworkPolicyPeer) string {
+		for _, peer := range peers {
+			sa := peer.ServiceAccount
+			if sa == nil {
+				continue
+			}
+			if namespaced {
+				return "serviceAccount can only be set in Antrea ClusterNetworkPolicies"
+			}
+			if sa.Name == "" || sa.Namespace == "" {
+				return "name and namespace must be set in serviceAccount"
+			}
+			if peer.IPBlock != nil || peer.PodSelector != nil || peer.NamespaceSelector != nil || peer.ExternalEntitySelector != nil ||
+				peer.NodeSelector != nil || peer.Group != "" || peer.FQDN != "" {
+				return fmt.Sprintf("serviceAccount %s/%s cannot be set with other peer fields", sa.Namespace, sa.Name)
+			}
+		}
+		return ""
+	}
+	if msg := validatePeers(appliedTo); msg != "" {
+		return fmt.Sprintf("invalid appliedTo: %s", msg), false
+	}
+	for idx, rule := range ingress {
+		if msg := validatePeers(rule.From); msg != "" {
+			return fmt.Sprintf("invalid peer for ingress rule %d: %s", idx, msg), false
+		}
+	}
+	for idx, rule := range egress {
+		if msg := validatePeers(rule.To); msg != "" {
+			return fmt.Sprintf("invalid peer for egress rule %d: %s", idx, msg), false
+		}
+	}
+	return "", true
+}
+
+// validateGroup validates the admission of a Group resource. Exactly one way
+// of selecting the members must be set, and Groups can only be nested on one
+// level: a child Group cannot have child Groups itself.
+func (v *NetworkPolicyValidator) validateGroup(g *corev1a1.Group) (string, bool) {
+	spec := g.Spec
+	memberFields := 0
+	if spec.PodSelector != nil || spec.ExternalEntitySelector != nil {
+		memberFields++
+	}
+	if spec.ServiceReference != nil {
+		memberFields++
+	}
+	if len(spec.IPBlocks) > 0 {
+		memberFields++
+	}
+	if len(spec.ChildGroups) > 0 {
+		memberFields++
+	}
+	if memberFields != 1 {
+		return "exactly one of podSelector/externalEntitySelector, serviceReference, ipBlocks and childGroups must be set", false
+	}
+	if spec.PodSelector != nil && spec.ExternalEntitySelector != nil {
+		return "podSelector and externalEntitySelector cannot be set together", false
+	}
+	if spec.ServiceReference != nil && spec.ServiceReference.Name == "" {
+		return "serviceReference must have a name", false
+	}
+	for _, ipBlock := range spec.IPBlocks {
+		if _, _, err := net.ParseCIDR(ipBlock.CIDR); err != nil {
+			return fmt.Sprintf("invalid ipBlock cidr %s: %v", ipBlock.CIDR, err), false
+		}
+	}
+	if len(spec.ChildGroups) == 0 {
+		return "", true
+	}
+	parents, err := v.networkPolicyController.groupInformer.Informer().GetIndexer().ByIndex(ChildGroupIndex, k8s.NamespacedName(g.Namespace, g.Name))
+	if err != nil {
+		return fmt.Sprintf("failed to get parent Groups of group %s: %v", g.Name, err), false
+	}
+	if len(parents) > 0 {
+		return fmt.Sprintf("group %s is a child Group of group %s and cannot have child Groups", g.Name, parents[0].(*corev1a1.Group).Name), false
+	}
+	for _, child := range spec.ChildGroups {
+		if child == g.Name {
+			return fmt.Sprintf("group %s cannot be a child Group of itself", g.Name), false
+		}
+		childGroup, err := v.networkPolicyController.groupLister.Groups(g.Namespace).Get(child)
+		if err != nil {
+			// Child Groups can be created after their parent.
+			continue
+		}
+		if len(childGroup.Spec.ChildGroups) > 0 {
+			return fmt.Sprintf("child group %s cannot have child Groups", child), false
+		}
+	}
+	return "", true
+}
+
 func (v *NetworkPolicyValidator) tierExists(name string) bool {
 	_, err := v.networkPolicyController.tierLister.Get(name)
 	if err != nil {
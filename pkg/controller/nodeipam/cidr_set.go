@@ -0,0 +1,127 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeipam
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+)
+
+// cidrSet allocates the PodCIDRs of a given mask size from a cluster CIDR.
+// It's not thread-safe.
+type cidrSet struct {
+	clusterCIDR     *net.IPNet
+	clusterMaskSize int
+	nodeMaskSize    int
+	bits            int
+	maxCIDRs        int
+	allocatedCIDRs  int
+	// used is a bitmap of the allocated PodCIDRs, indexed by their offset
+	// in the cluster CIDR.
+	used big.Int
+	// nextCandidate is the index the search for an available PodCIDR starts
+	// from, so that a released PodCIDR is not reused immediately.
+	nextCandidate int
+}
+
+func newCIDRSet(clusterCIDR *net.IPNet, nodeMaskSize int) (*cidrSet, error) {
+	clusterMaskSize, bits := clusterCIDR.Mask.Size()
+	if nodeMaskSize < clusterMaskSize || nodeMaskSize > bits {
+		return nil, fmt.Errorf("invalid Node CIDR mask size %d for cluster CIDR %s", nodeMaskSize, clusterCIDR)
+	}
+	if nodeMaskSize-clusterMaskSize > 16 {
+		return nil, fmt.Errorf("Node CIDR mask size %d is too large for cluster CIDR %s", nodeMaskSize, clusterCIDR)
+	}
+	if ip := clusterCIDR.IP.To4(); ip != nil {
+		clusterCIDR = &net.IPNet{IP: ip, Mask: clusterCIDR.Mask}
+	}
+	return &cidrSet{
+		clusterCIDR:     clusterCIDR,
+		clusterMaskSize: clusterMaskSize,
+		nodeMaskSize:    nodeMaskSize,
+		bits:            bits,
+		maxCIDRs:        1 << uint(nodeMaskSize-clusterMaskSize),
+	}, nil
+}
+
+func (s *cidrSet) indexToCIDR(index int) *net.IPNet {
+	ip := new(big.Int).SetBytes(s.clusterCIDR.IP)
+	ip.Add(ip, new(big.Int).Lsh(big.NewInt(int64(index)), uint(s.bits-s.nodeMaskSize)))
+	ipBytes := ip.Bytes()
+	// Pad the IP to the length of the cluster CIDR, as big.Int strips the
+	// leading zero bytes.
+	ipBytes = append(make([]byte, len(s.clusterCIDR.IP)-len(ipBytes)), ipBytes...)
+	return &net.IPNet{IP: ipBytes, Mask: net.CIDRMask(s.nodeMaskSize, s.bits)}
+}
+
+func (s *cidrSet) cidrToIndex(cidr *net.IPNet) (int, error) {
+	maskSize, bits := cidr.Mask.Size()
+	if bits != s.bits || !s.clusterCIDR.Contains(cidr.IP) {
+		return 0, fmt.Errorf("CIDR %s is not in cluster CIDR %s", cidr, s.clusterCIDR)
+	}
+	if maskSize != s.nodeMaskSize {
+		return 0, fmt.Errorf("mask size of CIDR %s does not match the Node CIDR mask size %d", cidr, s.nodeMaskSize)
+	}
+	ip := cidr.IP.To4()
+	if s.bits == 128 {
+		ip = cidr.IP.To16()
+	}
+	offset := new(big.Int).Sub(new(big.Int).SetBytes(ip), new(big.Int).SetBytes(s.clusterCIDR.IP))
+	return int(offset.Rsh(offset, uint(s.bits-s.nodeMaskSize)).Int64()), nil
+}
+
+// allocateNext allocates the next available PodCIDR.
+func (s *cidrSet) allocateNext() (*net.IPNet, error) {
+	if s.allocatedCIDRs == s.maxCIDRs {
+		return nil, fmt.Errorf("no PodCIDR available in cluster CIDR %s", s.clusterCIDR)
+	}
+	for i := 0; i < s.maxCIDRs; i++ {
+		candidate := (s.nextCandidate + i) % s.maxCIDRs
+		if s.used.Bit(candidate) == 0 {
+			s.used.SetBit(&s.used, candidate, 1)
+			s.allocatedCIDRs++
+			s.nextCandidate = (candidate + 1) % s.maxCIDRs
+			return s.indexToCIDR(candidate), nil
+		}
+	}
+	return nil, fmt.Errorf("no PodCIDR available in cluster CIDR %s", s.clusterCIDR)
+}
+
+// occupy marks a PodCIDR allocated previously as used.
+func (s *cidrSet) occupy(cidr *net.IPNet) error {
+	index, err := s.cidrToIndex(cidr)
+	if err != nil {
+		return err
+	}
+	if s.used.Bit(index) == 0 {
+		s.used.SetBit(&s.used, index, 1)
+		s.allocatedCIDRs++
+	}
+	return nil
+}
+
+// release marks a PodCIDR as available.
+func (s *cidrSet) release(cidr *net.IPNet) error {
+	index, err := s.cidrToIndex(cidr)
+	if err != nil {
+		return err
+	}
+	if s.used.Bit(index) == 1 {
+		s.used.SetBit(&s.used, index, 0)
+		s.allocatedCIDRs--
+	}
+	return nil
+}
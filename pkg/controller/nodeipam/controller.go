@@ -0,0 +1,296 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+)
+
+const (
+	// Set resyncPeriod to 0 to disable resyncing.
+	resyncPeriod time.Duration = 0
+
+	// How long to wait before retrying the processing of a Node.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+
+	// Nodes are processed by a single worker, so that a PodCIDR is never
+	// allocated to two Nodes.
+	defaultWorkers = 1
+)
+
+// Controller allocates the PodCIDRs of the Nodes from the cluster CIDRs, like
+// the NodeIPAM controller of kube-controller-manager does when it's started
+// with "--allocate-node-cidrs". It's meant for clusters in which the flags of
+// kube-controller-manager cannot be changed. A Node is allocated a PodCIDR of
+// each IP family of the cluster CIDRs, which is released when the Node is
+// deleted. The PodCIDRs of the Nodes which already have some are kept as is.
+type Controller struct {
+	client           clientset.Interface
+	nodeLister       corelisters.NodeLister
+	nodeListerSynced cache.InformerSynced
+	queue            workqueue.RateLimitingInterface
+	cidrSets         []*cidrSet
+	// nodeCIDRs is a map of the Node names to the PodCIDRs allocated to them.
+	// It's only accessed by the worker after the caches are synced.
+	nodeCIDRs map[string][]*net.IPNet
+}
+
+// NewNodeIPAMController creates a new NodeIPAM controller allocating the
+// PodCIDRs from clusterCIDRs, which contains at most one CIDR of each IP
+// family.
+func NewNodeIPAMController(client clientset.Interface, nodeInformer coreinformers.NodeInformer, clusterCIDRs []*net.IPNet, nodeCIDRMaskSizeIPv4, nodeCIDRMaskSizeIPv6 int) (*Controller, error) {
+	c := &Controller{
+		client:           client,
+		nodeLister:       nodeInformer.Lister(),
+		nodeListerSynced: nodeInformer.Informer().HasSynced,
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "nodeipam"),
+		nodeCIDRs:        map[string][]*net.IPNet{},
+	}
+	for _, clusterCIDR := range clusterCIDRs {
+		nodeMaskSize := nodeCIDRMaskSizeIPv6
+		if clusterCIDR.IP.To4() != nil {
+			nodeMaskSize = nodeCIDRMaskSizeIPv4
+		}
+		set, err := newCIDRSet(clusterCIDR, nodeMaskSize)
+		if err != nil {
+			return nil, err
+		}
+		c.cidrSets = append(c.cidrSets, set)
+	}
+	nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueueNode,
+			UpdateFunc: func(oldObj, curObj interface{}) { c.enqueueNode(curObj) },
+			DeleteFunc: c.enqueueNode,
+		},
+		resyncPeriod,
+	)
+	return c, nil
+}
+
+func (c *Controller) enqueueNode(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("Failed to get key of Node %v: %v", obj, err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run begins watching and syncing of the Nodes.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting NodeIPAM controller")
+	defer klog.Info("Shutting down NodeIPAM controller")
+
+	klog.Info("Waiting for caches to sync for NodeIPAM controller")
+	if !cache.WaitForCacheSync(stopCh, c.nodeListerSynced) {
+		klog.Error("Unable to sync caches for NodeIPAM controller")
+		return
+	}
+	klog.Info("Caches are synced for NodeIPAM controller")
+
+	// Load the PodCIDRs of the existing Nodes, so that they are not
+	// allocated again.
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list Nodes: %v", err)
+	}
+	for _, node := range nodes {
+		c.occupyNodeCIDRs(node)
+	}
+
+	for i := 0; i < defaultWorkers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(obj)
+
+	// We expect strings (Node name) to come off the workqueue.
+	if key, ok := obj.(string); !ok {
+		// As the item in the workqueue is actually invalid, we call Forget here else we'd go into a loop of
+		// attempting to process a work item that is invalid.
+		c.queue.Forget(obj)
+		klog.Errorf("Expected string in work queue but got %#v", obj)
+		return true
+	} else if err := c.syncNode(key); err == nil {
+		// If no error occurs we Forget this item so it does not get queued again.
+		c.queue.Forget(key)
+	} else {
+		// Put the item back on the workqueue to handle any transient errors.
+		c.queue.AddRateLimited(key)
+		klog.Errorf("Error syncing Node %s, requeuing. Error: %v", key, err)
+	}
+	return true
+}
+
+func (c *Controller) syncNode(nodeName string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing Node for %s. (%v)", nodeName, time.Since(startTime))
+	}()
+
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.releaseNodeCIDRs(nodeName)
+			return nil
+		}
+		return err
+	}
+	if len(node.Spec.PodCIDRs) > 0 || node.Spec.PodCIDR != "" {
+		c.occupyNodeCIDRs(node)
+		return nil
+	}
+	// The Node may have been allocated PodCIDRs already, in which case the
+	// update is not observed yet or the previous update failed.
+	cidrs, exists := c.nodeCIDRs[nodeName]
+	if !exists {
+		for _, set := range c.cidrSets {
+			cidr, err := set.allocateNext()
+			if err != nil {
+				c.releaseCIDRs(cidrs)
+				return fmt.Errorf("error allocating PodCIDR to Node %s: %v", nodeName, err)
+			}
+			cidrs = append(cidrs, cidr)
+		}
+		c.nodeCIDRs[nodeName] = cidrs
+	}
+	if err := c.updateNodePodCIDRs(nodeName, cidrs); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The Node is synced again when its deletion is observed.
+			return nil
+		}
+		return fmt.Errorf("error updating PodCIDRs of Node %s: %v", nodeName, err)
+	}
+	klog.Infof("Allocated PodCIDRs %v to Node %s", cidrs, nodeName)
+	return nil
+}
+
+func (c *Controller) updateNodePodCIDRs(nodeName string, cidrs []*net.IPNet) error {
+	podCIDRs := make([]string, len(cidrs))
+	for i := range cidrs {
+		podCIDRs[i] = cidrs[i].String()
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"podCIDR":  podCIDRs[0],
+			"podCIDRs": podCIDRs,
+		},
+	})
+	_, err := c.client.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// occupyNodeCIDRs records the PodCIDRs of the Node as allocated. PodCIDRs
+// which don't match any of the cluster CIDRs are ignored.
+func (c *Controller) occupyNodeCIDRs(node *corev1.Node) {
+	podCIDRs := node.Spec.PodCIDRs
+	if len(podCIDRs) == 0 && node.Spec.PodCIDR != "" {
+		podCIDRs = []string{node.Spec.PodCIDR}
+	}
+	var cidrs []*net.IPNet
+	for _, podCIDR := range podCIDRs {
+		_, cidr, err := net.ParseCIDR(podCIDR)
+		if err != nil {
+			klog.Errorf("Invalid PodCIDR %s of Node %s: %v", podCIDR, node.Name, err)
+			continue
+		}
+		for _, set := range c.cidrSets {
+			if set.clusterCIDR.Contains(cidr.IP) {
+				if err := set.occupy(cidr); err != nil {
+					klog.Errorf("Failed to occupy PodCIDR %s of Node %s: %v", podCIDR, node.Name, err)
+				} else {
+					cidrs = append(cidrs, cidr)
+				}
+				break
+			}
+		}
+	}
+	// Release the PodCIDRs allocated to the Node which it doesn't use.
+	for _, allocated := range c.nodeCIDRs[node.Name] {
+		if !containsCIDR(cidrs, allocated) {
+			c.releaseCIDRs([]*net.IPNet{allocated})
+		}
+	}
+	if len(cidrs) > 0 {
+		c.nodeCIDRs[node.Name] = cidrs
+	} else {
+		delete(c.nodeCIDRs, node.Name)
+	}
+}
+
+func (c *Controller) releaseNodeCIDRs(nodeName string) {
+	cidrs, exists := c.nodeCIDRs[nodeName]
+	if !exists {
+		return
+	}
+	c.releaseCIDRs(cidrs)
+	delete(c.nodeCIDRs, nodeName)
+	klog.Infof("Released PodCIDRs %v of Node %s", cidrs, nodeName)
+}
+
+func (c *Controller) releaseCIDRs(cidrs []*net.IPNet) {
+	for _, cidr := range cidrs {
+		for _, set := range c.cidrSets {
+			if set.clusterCIDR.Contains(cidr.IP) {
+				if err := set.release(cidr); err != nil {
+					klog.Errorf("Failed to release PodCIDR %s: %v", cidr, err)
+				}
+				break
+			}
+		}
+	}
+}
+
+func containsCIDR(cidrs []*net.IPNet, cidr *net.IPNet) bool {
+	for _, c := range cidrs {
+		if c.String() == cidr.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodeipam
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type nodeIPAMController struct {
+	*Controller
+	client *fake.Clientset
+}
+
+func newController(t *testing.T, clusterCIDRs []string, objects ...runtime.Object) (*nodeIPAMController, func()) {
+	client := fake.NewSimpleClientset(objects...)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	var cidrs []*net.IPNet
+	for _, cidr := range clusterCIDRs {
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		cidrs = append(cidrs, ipNet)
+	}
+	c, err := NewNodeIPAMController(client, informerFactory.Core().V1().Nodes(), cidrs, 24, 64)
+	require.NoError(t, err)
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	nodes, _ := c.nodeLister.List(labels.Everything())
+	for _, node := range nodes {
+		c.occupyNodeCIDRs(node)
+	}
+	return &nodeIPAMController{Controller: c, client: client}, func() { close(stopCh) }
+}
+
+func newNode(name string, podCIDRs ...string) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{PodCIDRs: podCIDRs},
+	}
+	if len(podCIDRs) > 0 {
+		node.Spec.PodCIDR = podCIDRs[0]
+	}
+	return node
+}
+
+func (c *nodeIPAMController) getPodCIDRs(t *testing.T, name string) []string {
+	node, err := c.client.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	if len(node.Spec.PodCIDRs) > 0 {
+		assert.Equal(t, node.Spec.PodCIDRs[0], node.Spec.PodCIDR)
+	}
+	return node.Spec.PodCIDRs
+}
+
+func TestAllocateNodeCIDRs(t *testing.T) {
+	c, cleanup := newController(t, []string{"10.10.0.0/23", "fd00:10:10::/63"},
+		newNode("node-existing", "10.10.0.0/24", "fd00:10:10::/64"),
+		newNode("node1"),
+		newNode("node2"),
+	)
+	defer cleanup()
+
+	// The PodCIDRs of the existing Node are not allocated again.
+	require.NoError(t, c.syncNode("node1"))
+	assert.Equal(t, []string{"10.10.1.0/24", "fd00:10:10:1::/64"}, c.getPodCIDRs(t, "node1"))
+	// Syncing the Node again before the update is observed doesn't allocate other PodCIDRs.
+	require.NoError(t, c.syncNode("node1"))
+	assert.Equal(t, []string{"10.10.1.0/24", "fd00:10:10:1::/64"}, c.getPodCIDRs(t, "node1"))
+
+	// The cluster CIDRs are exhausted.
+	assert.Error(t, c.syncNode("node2"))
+	assert.Empty(t, c.getPodCIDRs(t, "node2"))
+
+	// The PodCIDRs of a deleted Node are released.
+	require.NoError(t, c.client.CoreV1().Nodes().Delete(context.TODO(), "node-existing", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		_, err := c.nodeLister.Get("node-existing")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, c.syncNode("node-existing"))
+	require.NoError(t, c.syncNode("node2"))
+	assert.Equal(t, []string{"10.10.0.0/24", "fd00:10:10::/64"}, c.getPodCIDRs(t, "node2"))
+}
+
+func TestNodeCIDRsOutsideClusterCIDRs(t *testing.T) {
+	c, cleanup := newController(t, []string{"10.10.0.0/16"}, newNode("node1", "192.168.0.0/24"))
+	defer cleanup()
+
+	// PodCIDRs allocated by others are kept as is.
+	require.NoError(t, c.syncNode("node1"))
+	assert.Equal(t, []string{"192.168.0.0/24"}, c.getPodCIDRs(t, "node1"))
+	assert.Empty(t, c.nodeCIDRs)
+}
+
+func TestCIDRSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		clusterCIDR   string
+		nodeMaskSize  int
+		expectedCIDRs []string
+	}{
+		{
+			name:          "IPv4",
+			clusterCIDR:   "10.10.0.0/22",
+			nodeMaskSize:  24,
+			expectedCIDRs: []string{"10.10.0.0/24", "10.10.1.0/24", "10.10.2.0/24", "10.10.3.0/24"},
+		},
+		{
+			name:          "IPv4 same mask size",
+			clusterCIDR:   "10.10.0.0/24",
+			nodeMaskSize:  24,
+			expectedCIDRs: []string{"10.10.0.0/24"},
+		},
+		{
+			name:          "IPv6",
+			clusterCIDR:   "fd00::/63",
+			nodeMaskSize:  64,
+			expectedCIDRs: []string{"fd00::/64", "fd00:0:0:1::/64"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, clusterCIDR, _ := net.ParseCIDR(tt.clusterCIDR)
+			set, err := newCIDRSet(clusterCIDR, tt.nodeMaskSize)
+			require.NoError(t, err)
+			var cidrs []string
+			for range tt.expectedCIDRs {
+				cidr, err := set.allocateNext()
+				require.NoError(t, err)
+				cidrs = append(cidrs, cidr.String())
+			}
+			assert.Equal(t, tt.expectedCIDRs, cidrs)
+			_, err = set.allocateNext()
+			assert.Error(t, err)
+
+			_, released, _ := net.ParseCIDR(tt.expectedCIDRs[0])
+			require.NoError(t, set.release(released))
+			cidr, err := set.allocateNext()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCIDRs[0], cidr.String())
+		})
+	}
+}
+
+func TestCIDRSetInvalid(t *testing.T) {
+	_, clusterCIDR, _ := net.ParseCIDR("10.0.0.0/8")
+	_, err := newCIDRSet(clusterCIDR, 25)
+	assert.Error(t, err)
+	_, err = newCIDRSet(clusterCIDR, 7)
+	assert.Error(t, err)
+
+	set, err := newCIDRSet(clusterCIDR, 16)
+	require.NoError(t, err)
+	_, otherCIDR, _ := net.ParseCIDR("10.1.0.0/24")
+	assert.Error(t, set.occupy(otherCIDR))
+	_, otherCIDR, _ = net.ParseCIDR("11.0.0.0/16")
+	assert.Error(t, set.occupy(otherCIDR))
+}
@@ -19,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1"
+	"github.com/vmware-tanzu/antrea/pkg/controller/watermark"
 	"github.com/vmware-tanzu/antrea/pkg/querier"
 )
 
@@ -35,10 +36,12 @@ type ControllerQuerier interface {
 type controllerQuerier struct {
 	networkPolicyInfoQuerier querier.ControllerNetworkPolicyInfoQuerier
 	apiPort                  int
+	// watermarkMonitor is nil if no watermark is configured.
+	watermarkMonitor *watermark.Monitor
 }
 
-func NewControllerQuerier(networkPolicyInfoQuerier querier.ControllerNetworkPolicyInfoQuerier, apiPort int) *controllerQuerier {
-	return &controllerQuerier{networkPolicyInfoQuerier: networkPolicyInfoQuerier, apiPort: apiPort}
+func NewControllerQuerier(networkPolicyInfoQuerier querier.ControllerNetworkPolicyInfoQuerier, apiPort int, watermarkMonitor *watermark.Monitor) *controllerQuerier {
+	return &controllerQuerier{networkPolicyInfoQuerier: networkPolicyInfoQuerier, apiPort: apiPort, watermarkMonitor: watermarkMonitor}
 }
 
 // GetNodeSubnet gets current network policy info querier.
@@ -62,13 +65,28 @@ func (cq controllerQuerier) getNetworkPolicyControllerInfo() v1beta1.NetworkPoli
 }
 
 func (cq controllerQuerier) getControllerConditions() []v1beta1.ControllerCondition {
-	return []v1beta1.ControllerCondition{
+	now := metav1.Now()
+	conditions := []v1beta1.ControllerCondition{
 		{
 			Type:              v1beta1.ControllerHealthy,
 			Status:            v1.ConditionTrue,
-			LastHeartbeatTime: metav1.Now(),
+			LastHeartbeatTime: now,
 		},
 	}
+	if cq.watermarkMonitor != nil {
+		degradedCondition := v1beta1.ControllerCondition{
+			Type:              v1beta1.ControllerDegraded,
+			Status:            v1.ConditionFalse,
+			LastHeartbeatTime: now,
+		}
+		if degraded, message := cq.watermarkMonitor.GetStatus(); degraded {
+			degradedCondition.Status = v1.ConditionTrue
+			degradedCondition.Reason = "WatermarkExceeded"
+			degradedCondition.Message = message
+		}
+		conditions = append(conditions, degradedCondition)
+	}
+	return conditions
 }
 
 // GetControllerInfo gets current info of controller.
@@ -16,11 +16,14 @@ package stats
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	networkinginformers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
@@ -29,12 +32,20 @@ import (
 	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
 	statsv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/stats/v1alpha1"
 	secvinformers "github.com/vmware-tanzu/antrea/pkg/client/informers/externalversions/security/v1alpha1"
+	"github.com/vmware-tanzu/antrea/pkg/controller/watermark"
 	"github.com/vmware-tanzu/antrea/pkg/features"
 	"github.com/vmware-tanzu/antrea/pkg/k8s"
 )
 
 const (
 	uidIndex = "uid"
+	// degradedCollectInterval is the minimum interval between the collection of two stats summaries when the
+	// controller is in degraded mode, which limits the resources consumed by stats aggregation.
+	degradedCollectInterval = 100 * time.Millisecond
+	// flowExpiry is the duration after which the summaries of the connections reported by a Node are considered
+	// stale. antrea-agents report them every minute, the summaries of a Node which has stopped reporting them, e.g.
+	// because it has been removed, expire after a few missed reports.
+	flowExpiry = 5 * time.Minute
 )
 
 // Aggregator collects the stats from the antrea-agents, aggregates them, caches the result, and provides interfaces
@@ -43,6 +54,7 @@ const (
 // - pkg/apiserver/registry/stats/networkpolicystats.statsProvider
 // - pkg/apiserver/registry/stats/antreaclusternetworkpolicystats.statsProvider
 // - pkg/apiserver/registry/stats/antreanetworkpolicystats.statsProvider
+// - pkg/apiserver/registry/stats/namespacenetworksummary.summaryProvider
 type Aggregator struct {
 	// networkPolicyStats caches the statistics of K8s NetworkPolicies collected from the antrea-agents.
 	networkPolicyStats cache.Indexer
@@ -58,6 +70,19 @@ type Aggregator struct {
 	cnpListerSynced cache.InformerSynced
 	// anpListerSynced is a function which returns true if the Antrea NetworkPolicy shared informer has been synced at least once.
 	anpListerSynced cache.InformerSynced
+	// watermarkMonitor reports whether the controller is in degraded mode, in which stats aggregation is delayed.
+	// It can be nil.
+	watermarkMonitor *watermark.Monitor
+	// nodeFlows is a mapping from Node names to the latest summaries of the connections reported by the Nodes.
+	nodeFlows map[string]*nodeFlows
+	// flowsLock protects nodeFlows.
+	flowsLock sync.RWMutex
+}
+
+// nodeFlows is the summaries of the connections reported by a Node, and the time when they were reported.
+type nodeFlows struct {
+	flows      []statsv1alpha1.FlowSummary
+	updateTime time.Time
 }
 
 // uidIndexFunc is an index function that indexes based on an object's UID.
@@ -69,11 +94,13 @@ func uidIndexFunc(obj interface{}) ([]string, error) {
 	return []string{string(meta.GetUID())}, nil
 }
 
-func NewAggregator(networkPolicyInformer networkinginformers.NetworkPolicyInformer, cnpInformer secvinformers.ClusterNetworkPolicyInformer, anpInformer secvinformers.NetworkPolicyInformer) *Aggregator {
+func NewAggregator(networkPolicyInformer networkinginformers.NetworkPolicyInformer, cnpInformer secvinformers.ClusterNetworkPolicyInformer, anpInformer secvinformers.NetworkPolicyInformer, watermarkMonitor *watermark.Monitor) *Aggregator {
 	aggregator := &Aggregator{
 		networkPolicyStats: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc, uidIndex: uidIndexFunc}),
 		dataCh:             make(chan *controlplane.NodeStatsSummary, 1000),
 		npListerSynced:     networkPolicyInformer.Informer().HasSynced,
+		watermarkMonitor:   watermarkMonitor,
+		nodeFlows:          map[string]*nodeFlows{},
 	}
 	// Add handlers for NetworkPolicy events.
 	// They are the source of truth of the NetworkPolicyStats, i.e., a NetworkPolicyStats is present only if the
@@ -95,6 +122,7 @@ func NewAggregator(networkPolicyInformer networkinginformers.NetworkPolicyInform
 		cnpInformer.Informer().AddEventHandlerWithResyncPeriod(
 			cache.ResourceEventHandlerFuncs{
 				AddFunc:    aggregator.addCNP,
+				UpdateFunc: aggregator.updateCNP,
 				DeleteFunc: aggregator.deleteCNP,
 			},
 			// Set resyncPeriod to 0 to disable resyncing.
@@ -106,6 +134,7 @@ func NewAggregator(networkPolicyInformer networkinginformers.NetworkPolicyInform
 		anpInformer.Informer().AddEventHandlerWithResyncPeriod(
 			cache.ResourceEventHandlerFuncs{
 				AddFunc:    aggregator.addANP,
+				UpdateFunc: aggregator.updateANP,
 				DeleteFunc: aggregator.deleteANP,
 			},
 			// Set resyncPeriod to 0 to disable resyncing.
@@ -167,10 +196,29 @@ func (a *Aggregator) addCNP(obj interface{}) {
 			// start, instead of the CreationTimestamp of the ClusterNetworkPolicy.
 			CreationTimestamp: metav1.Time{Time: time.Now()},
 		},
+		RuleTrafficStats: buildRuleTrafficStats(len(cnp.Spec.Ingress), len(cnp.Spec.Egress), nil),
 	}
 	a.antreaClusterNetworkPolicyStats.Add(stats)
 }
 
+// updateCNP handles ClusterNetworkPolicy UPDATE events and updates the rules of corresponding
+// ClusterNetworkPolicyStats objects when rules are added or removed.
+func (a *Aggregator) updateCNP(oldObj, curObj interface{}) {
+	oldCNP := oldObj.(*secv1alpha1.ClusterNetworkPolicy)
+	curCNP := curObj.(*secv1alpha1.ClusterNetworkPolicy)
+	if len(oldCNP.Spec.Ingress) == len(curCNP.Spec.Ingress) && len(oldCNP.Spec.Egress) == len(curCNP.Spec.Egress) {
+		return
+	}
+	obj, exists, _ := a.antreaClusterNetworkPolicyStats.GetByKey(curCNP.Name)
+	if !exists {
+		return
+	}
+	// The object returned by cache is supposed to be read only, create a new object and update it.
+	stats := obj.(*statsv1alpha1.AntreaClusterNetworkPolicyStats).DeepCopy()
+	stats.RuleTrafficStats = buildRuleTrafficStats(len(curCNP.Spec.Ingress), len(curCNP.Spec.Egress), stats.RuleTrafficStats)
+	a.antreaClusterNetworkPolicyStats.Update(stats)
+}
+
 // deleteCNP handles ClusterNetworkPolicy DELETE events and deletes corresponding ClusterNetworkPolicyStats objects.
 func (a *Aggregator) deleteCNP(obj interface{}) {
 	cnp, ok := obj.(*secv1alpha1.ClusterNetworkPolicy)
@@ -207,10 +255,29 @@ func (a *Aggregator) addANP(obj interface{}) {
 			// start, instead of the CreationTimestamp of the Antrea NetworkPolicy.
 			CreationTimestamp: metav1.Time{Time: time.Now()},
 		},
+		RuleTrafficStats: buildRuleTrafficStats(len(anp.Spec.Ingress), len(anp.Spec.Egress), nil),
 	}
 	a.antreaNetworkPolicyStats.Add(stats)
 }
 
+// updateANP handles Antrea NetworkPolicy UPDATE events and updates the rules of corresponding
+// AntreaNetworkPolicyStats objects when rules are added or removed.
+func (a *Aggregator) updateANP(oldObj, curObj interface{}) {
+	oldANP := oldObj.(*secv1alpha1.NetworkPolicy)
+	curANP := curObj.(*secv1alpha1.NetworkPolicy)
+	if len(oldANP.Spec.Ingress) == len(curANP.Spec.Ingress) && len(oldANP.Spec.Egress) == len(curANP.Spec.Egress) {
+		return
+	}
+	obj, exists, _ := a.antreaNetworkPolicyStats.GetByKey(k8s.NamespacedName(curANP.Namespace, curANP.Name))
+	if !exists {
+		return
+	}
+	// The object returned by cache is supposed to be read only, create a new object and update it.
+	stats := obj.(*statsv1alpha1.AntreaNetworkPolicyStats).DeepCopy()
+	stats.RuleTrafficStats = buildRuleTrafficStats(len(curANP.Spec.Ingress), len(curANP.Spec.Egress), stats.RuleTrafficStats)
+	a.antreaNetworkPolicyStats.Update(stats)
+}
+
 // deleteANP handles Antrea NetworkPolicy DELETE events and deletes corresponding AntreaNetworkPolicyStats objects.
 func (a *Aggregator) deleteANP(obj interface{}) {
 	anp, ok := obj.(*secv1alpha1.NetworkPolicy)
@@ -299,6 +366,102 @@ func (a *Aggregator) GetNetworkPolicyStats(namespace, name string) (*statsv1alph
 	return obj.(*statsv1alpha1.NetworkPolicyStats), true
 }
 
+// GetNamespaceNetworkSummary returns the network summary of the Namespace. A summary is returned for any Namespace,
+// it's empty if the Namespace has no NetworkPolicy and no connection.
+func (a *Aggregator) GetNamespaceNetworkSummary(namespace string) *statsv1alpha1.NamespaceNetworkSummary {
+	summary := &statsv1alpha1.NamespaceNetworkSummary{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespace,
+			Namespace: namespace,
+		},
+	}
+	for _, stats := range a.ListNetworkPolicyStats(namespace) {
+		summary.NetworkPolicies = append(summary.NetworkPolicies, statsv1alpha1.PolicyTrafficStats{
+			Name:         stats.Name,
+			TrafficStats: stats.TrafficStats,
+		})
+	}
+	if a.antreaNetworkPolicyStats != nil {
+		for _, stats := range a.ListAntreaNetworkPolicyStats(namespace) {
+			summary.AntreaNetworkPolicies = append(summary.AntreaNetworkPolicies, statsv1alpha1.PolicyTrafficStats{
+				Name:             stats.Name,
+				TrafficStats:     stats.TrafficStats,
+				RuleTrafficStats: stats.RuleTrafficStats,
+			})
+		}
+	}
+	sort.Slice(summary.NetworkPolicies, func(i, j int) bool {
+		return summary.NetworkPolicies[i].Name < summary.NetworkPolicies[j].Name
+	})
+	sort.Slice(summary.AntreaNetworkPolicies, func(i, j int) bool {
+		return summary.AntreaNetworkPolicies[i].Name < summary.AntreaNetworkPolicies[j].Name
+	})
+	summary.Flows = a.listFlows(namespace)
+	return summary
+}
+
+// ListNamespaceNetworkSummaries returns the network summary of the Namespace, or the network summaries of all the
+// Namespaces which have a NetworkPolicy or a connection if namespace is empty.
+func (a *Aggregator) ListNamespaceNetworkSummaries(namespace string) []statsv1alpha1.NamespaceNetworkSummary {
+	if namespace != "" {
+		return []statsv1alpha1.NamespaceNetworkSummary{*a.GetNamespaceNetworkSummary(namespace)}
+	}
+	namespaces := sets.NewString()
+	for _, stats := range a.ListNetworkPolicyStats("") {
+		namespaces.Insert(stats.Namespace)
+	}
+	if a.antreaNetworkPolicyStats != nil {
+		for _, stats := range a.ListAntreaNetworkPolicyStats("") {
+			namespaces.Insert(stats.Namespace)
+		}
+	}
+	for _, flow := range a.listFlows("") {
+		namespaces.Insert(flow.Namespace)
+	}
+	summaries := make([]statsv1alpha1.NamespaceNetworkSummary, 0, namespaces.Len())
+	for _, ns := range namespaces.List() {
+		summaries = append(summaries, *a.GetNamespaceNetworkSummary(ns))
+	}
+	return summaries
+}
+
+// listFlows returns the summaries of the connections of the Pods of the Namespace, or of all the Pods if namespace
+// is empty, which have been reported by the Nodes and haven't expired.
+func (a *Aggregator) listFlows(namespace string) []statsv1alpha1.FlowSummary {
+	a.flowsLock.RLock()
+	defer a.flowsLock.RUnlock()
+	var flows []statsv1alpha1.FlowSummary
+	for _, nf := range a.nodeFlows {
+		if time.Since(nf.updateTime) > flowExpiry {
+			continue
+		}
+		for _, flow := range nf.flows {
+			if namespace == "" || flow.Namespace == namespace {
+				flows = append(flows, flow)
+			}
+		}
+	}
+	sort.Slice(flows, func(i, j int) bool {
+		if flows[i].Namespace != flows[j].Namespace {
+			return flows[i].Namespace < flows[j].Namespace
+		}
+		if flows[i].Pod != flows[j].Pod {
+			return flows[i].Pod < flows[j].Pod
+		}
+		if flows[i].Direction != flows[j].Direction {
+			return flows[i].Direction < flows[j].Direction
+		}
+		if flows[i].Peer != flows[j].Peer {
+			return flows[i].Peer < flows[j].Peer
+		}
+		if flows[i].Protocol != flows[j].Protocol {
+			return flows[i].Protocol < flows[j].Protocol
+		}
+		return flows[i].Port < flows[j].Port
+	})
+	return flows
+}
+
 // Collect collects the node summary asynchronously to avoid the competition for the statsLock and to save clients
 // from pending on it.
 func (a *Aggregator) Collect(summary *controlplane.NodeStatsSummary) {
@@ -331,10 +494,20 @@ func (a *Aggregator) Run(stopCh <-chan struct{}) {
 		case <-stopCh:
 			return
 		}
+		// Delay the collection of the next summary in degraded mode. The summaries are buffered in the data channel
+		// in the meantime, and antrea-agents are slowed down when it's full.
+		if a.watermarkMonitor.IsDegraded() {
+			select {
+			case <-time.After(degradedCollectInterval):
+			case <-stopCh:
+				return
+			}
+		}
 	}
 }
 
 func (a *Aggregator) doCollect(summary *controlplane.NodeStatsSummary) {
+	a.updateNodeFlows(summary.Name, summary.Flows)
 	for _, stats := range summary.NetworkPolicies {
 		// The policy might have been removed, skip processing it if missing.
 		objs, _ := a.networkPolicyStats.ByIndex(uidIndex, string(stats.NetworkPolicy.UID))
@@ -353,6 +526,7 @@ func (a *Aggregator) doCollect(summary *controlplane.NodeStatsSummary) {
 				// The object returned by cache is supposed to be read only, create a new object and update it.
 				curStats := objs[0].(*statsv1alpha1.AntreaClusterNetworkPolicyStats).DeepCopy()
 				addUp(&curStats.TrafficStats, &stats.TrafficStats)
+				addUpRules(curStats.RuleTrafficStats, stats.RuleTrafficStats)
 				a.antreaClusterNetworkPolicyStats.Update(curStats)
 			}
 		}
@@ -363,14 +537,64 @@ func (a *Aggregator) doCollect(summary *controlplane.NodeStatsSummary) {
 				// The object returned by cache is supposed to be read only, create a new object and update it.
 				curStats := objs[0].(*statsv1alpha1.AntreaNetworkPolicyStats).DeepCopy()
 				addUp(&curStats.TrafficStats, &stats.TrafficStats)
+				addUpRules(curStats.RuleTrafficStats, stats.RuleTrafficStats)
 				a.antreaNetworkPolicyStats.Update(curStats)
 			}
 		}
 	}
 }
 
+// updateNodeFlows replaces the summaries of the connections reported by the Node, and removes the expired summaries of
+// the other Nodes.
+func (a *Aggregator) updateNodeFlows(nodeName string, flows []statsv1alpha1.FlowSummary) {
+	a.flowsLock.Lock()
+	defer a.flowsLock.Unlock()
+	for name, nf := range a.nodeFlows {
+		if time.Since(nf.updateTime) > flowExpiry {
+			delete(a.nodeFlows, name)
+		}
+	}
+	if len(flows) == 0 {
+		delete(a.nodeFlows, nodeName)
+		return
+	}
+	a.nodeFlows[nodeName] = &nodeFlows{flows: flows, updateTime: time.Now()}
+}
+
 func addUp(stats *statsv1alpha1.TrafficStats, inc *statsv1alpha1.TrafficStats) {
 	stats.Sessions += inc.Sessions
 	stats.Packets += inc.Packets
 	stats.Bytes += inc.Bytes
 }
+
+// addUpRules adds the stats of the rules in inc to the stats of the same rules in stats. The stats of the rules which
+// don't exist anymore are skipped.
+func addUpRules(stats []statsv1alpha1.RuleTrafficStats, inc []statsv1alpha1.RuleTrafficStats) {
+	for i := range inc {
+		for j := range stats {
+			if stats[j].Direction == inc[i].Direction && stats[j].Index == inc[i].Index {
+				addUp(&stats[j].TrafficStats, &inc[i].TrafficStats)
+				break
+			}
+		}
+	}
+}
+
+// buildRuleTrafficStats returns the stats of the rules of an Antrea-native policy which has the provided numbers of
+// ingress and egress rules. The rules are identified by their indexes, the stats of the rules which still exist are
+// copied from oldStats and the stats of the other rules are zero, so that the rules which have never been hit are
+// reported too.
+func buildRuleTrafficStats(ingressRules, egressRules int, oldStats []statsv1alpha1.RuleTrafficStats) []statsv1alpha1.RuleTrafficStats {
+	if ingressRules+egressRules == 0 {
+		return nil
+	}
+	stats := make([]statsv1alpha1.RuleTrafficStats, 0, ingressRules+egressRules)
+	for i := 0; i < ingressRules; i++ {
+		stats = append(stats, statsv1alpha1.RuleTrafficStats{Direction: statsv1alpha1.RuleDirectionIngress, Index: int32(i)})
+	}
+	for i := 0; i < egressRules; i++ {
+		stats = append(stats, statsv1alpha1.RuleTrafficStats{Direction: statsv1alpha1.RuleDirectionEgress, Index: int32(i)})
+	}
+	addUpRules(stats, oldStats)
+	return stats
+}
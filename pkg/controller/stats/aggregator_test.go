@@ -56,6 +56,13 @@ var (
 	anp2 = &secv1alpha1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "baz", UID: "uid6"},
 	}
+	cnp3 = &secv1alpha1.ClusterNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "", Name: "qux", UID: "uid7"},
+		Spec: secv1alpha1.ClusterNetworkPolicySpec{
+			Ingress: []secv1alpha1.Rule{{}, {}},
+			Egress:  []secv1alpha1.Rule{{}},
+		},
+	}
 )
 
 func TestAggregatorCollectListGet(t *testing.T) {
@@ -298,6 +305,119 @@ func TestAggregatorCollectListGet(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Antrea-native policy rules",
+			summaries: []*controlplane.NodeStatsSummary{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node-1",
+					},
+					AntreaClusterNetworkPolicies: []controlplane.NetworkPolicyStats{
+						{
+							NetworkPolicy: controlplane.NetworkPolicyReference{UID: cnp3.UID},
+							TrafficStats: statsv1alpha1.TrafficStats{
+								Bytes:    30,
+								Packets:  3,
+								Sessions: 2,
+							},
+							RuleTrafficStats: []statsv1alpha1.RuleTrafficStats{
+								{
+									Direction: statsv1alpha1.RuleDirectionIngress,
+									Index:     1,
+									TrafficStats: statsv1alpha1.TrafficStats{
+										Bytes:    10,
+										Packets:  1,
+										Sessions: 1,
+									},
+								},
+								{
+									Direction: statsv1alpha1.RuleDirectionEgress,
+									Index:     0,
+									TrafficStats: statsv1alpha1.TrafficStats{
+										Bytes:    20,
+										Packets:  2,
+										Sessions: 1,
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node-2",
+					},
+					AntreaClusterNetworkPolicies: []controlplane.NetworkPolicyStats{
+						{
+							NetworkPolicy: controlplane.NetworkPolicyReference{UID: cnp3.UID},
+							TrafficStats: statsv1alpha1.TrafficStats{
+								Bytes:    50,
+								Packets:  5,
+								Sessions: 1,
+							},
+							RuleTrafficStats: []statsv1alpha1.RuleTrafficStats{
+								{
+									Direction: statsv1alpha1.RuleDirectionIngress,
+									Index:     1,
+									TrafficStats: statsv1alpha1.TrafficStats{
+										Bytes:    40,
+										Packets:  4,
+										Sessions: 1,
+									},
+								},
+								// The rule doesn't exist anymore and is skipped.
+								{
+									Direction: statsv1alpha1.RuleDirectionEgress,
+									Index:     1,
+									TrafficStats: statsv1alpha1.TrafficStats{
+										Bytes:    10,
+										Packets:  1,
+										Sessions: 0,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			existingAntreaClusterNetworkPolicies: []runtime.Object{cnp3},
+			expectedAntreaClusterNetworkPolicyStats: []statsv1alpha1.AntreaClusterNetworkPolicyStats{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: cnp3.Name,
+					},
+					TrafficStats: statsv1alpha1.TrafficStats{
+						Bytes:    80,
+						Packets:  8,
+						Sessions: 3,
+					},
+					RuleTrafficStats: []statsv1alpha1.RuleTrafficStats{
+						{
+							Direction: statsv1alpha1.RuleDirectionIngress,
+							Index:     0,
+						},
+						{
+							Direction: statsv1alpha1.RuleDirectionIngress,
+							Index:     1,
+							TrafficStats: statsv1alpha1.TrafficStats{
+								Bytes:    50,
+								Packets:  5,
+								Sessions: 2,
+							},
+						},
+						{
+							Direction: statsv1alpha1.RuleDirectionEgress,
+							Index:     0,
+							TrafficStats: statsv1alpha1.TrafficStats{
+								Bytes:    20,
+								Packets:  2,
+								Sessions: 1,
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -309,7 +429,7 @@ func TestAggregatorCollectListGet(t *testing.T) {
 			informerFactory := informers.NewSharedInformerFactory(client, 12*time.Hour)
 			crdClient := fakeversioned.NewSimpleClientset(append(tt.existingAntreaClusterNetworkPolicies, tt.existingAntreaNetworkPolicies...)...)
 			crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 12*time.Hour)
-			a := NewAggregator(informerFactory.Networking().V1().NetworkPolicies(), crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(), crdInformerFactory.Security().V1alpha1().NetworkPolicies())
+			a := NewAggregator(informerFactory.Networking().V1().NetworkPolicies(), crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(), crdInformerFactory.Security().V1alpha1().NetworkPolicies(), nil)
 			informerFactory.Start(stopCh)
 			crdInformerFactory.Start(stopCh)
 			go a.Run(stopCh)
@@ -333,12 +453,14 @@ func TestAggregatorCollectListGet(t *testing.T) {
 				actualStats, exists := a.GetAntreaClusterNetworkPolicyStats(Stats.Name)
 				require.True(t, exists)
 				require.Equal(t, Stats.TrafficStats, actualStats.TrafficStats)
+				require.Equal(t, Stats.RuleTrafficStats, actualStats.RuleTrafficStats)
 			}
 			assert.Equal(t, len(tt.expectedAntreaNetworkPolicyStats), len(a.ListAntreaNetworkPolicyStats("")))
 			for _, Stats := range tt.expectedAntreaNetworkPolicyStats {
 				actualStats, exists := a.GetAntreaNetworkPolicyStats(Stats.Namespace, Stats.Name)
 				require.True(t, exists)
 				require.Equal(t, Stats.TrafficStats, actualStats.TrafficStats)
+				require.Equal(t, Stats.RuleTrafficStats, actualStats.RuleTrafficStats)
 			}
 		})
 	}
@@ -353,7 +475,7 @@ func TestDeleteNetworkPolicy(t *testing.T) {
 	informerFactory := informers.NewSharedInformerFactory(client, 12*time.Hour)
 	crdClient := fakeversioned.NewSimpleClientset(cnp1, anp1)
 	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 12*time.Hour)
-	a := NewAggregator(informerFactory.Networking().V1().NetworkPolicies(), crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(), crdInformerFactory.Security().V1alpha1().NetworkPolicies())
+	a := NewAggregator(informerFactory.Networking().V1().NetworkPolicies(), crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(), crdInformerFactory.Security().V1alpha1().NetworkPolicies(), nil)
 	informerFactory.Start(stopCh)
 	crdInformerFactory.Start(stopCh)
 	go a.Run(stopCh)
@@ -411,3 +533,139 @@ func TestDeleteNetworkPolicy(t *testing.T) {
 	})
 	assert.NoError(t, err)
 }
+
+func TestUpdateAntreaPolicyRules(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.AntreaPolicy, true)()
+
+	client := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 12*time.Hour)
+	crdClient := fakeversioned.NewSimpleClientset()
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 12*time.Hour)
+	a := NewAggregator(informerFactory.Networking().V1().NetworkPolicies(), crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(), crdInformerFactory.Security().V1alpha1().NetworkPolicies(), nil)
+
+	a.addCNP(cnp3)
+	a.doCollect(&controlplane.NodeStatsSummary{
+		AntreaClusterNetworkPolicies: []controlplane.NetworkPolicyStats{
+			{
+				NetworkPolicy: controlplane.NetworkPolicyReference{UID: cnp3.UID},
+				TrafficStats: statsv1alpha1.TrafficStats{
+					Bytes:    30,
+					Packets:  3,
+					Sessions: 2,
+				},
+				RuleTrafficStats: []statsv1alpha1.RuleTrafficStats{
+					{
+						Direction: statsv1alpha1.RuleDirectionIngress,
+						Index:     0,
+						TrafficStats: statsv1alpha1.TrafficStats{
+							Bytes:    10,
+							Packets:  1,
+							Sessions: 1,
+						},
+					},
+					{
+						Direction: statsv1alpha1.RuleDirectionIngress,
+						Index:     1,
+						TrafficStats: statsv1alpha1.TrafficStats{
+							Bytes:    20,
+							Packets:  2,
+							Sessions: 1,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// The stats of the remaining rules are kept and the added rules start from zero.
+	updatedCNP := cnp3.DeepCopy()
+	updatedCNP.Spec.Ingress = updatedCNP.Spec.Ingress[:1]
+	updatedCNP.Spec.Egress = append(updatedCNP.Spec.Egress, secv1alpha1.Rule{})
+	a.updateCNP(cnp3, updatedCNP)
+	stats, exists := a.GetAntreaClusterNetworkPolicyStats(cnp3.Name)
+	require.True(t, exists)
+	assert.Equal(t, statsv1alpha1.TrafficStats{Bytes: 30, Packets: 3, Sessions: 2}, stats.TrafficStats)
+	assert.Equal(t, []statsv1alpha1.RuleTrafficStats{
+		{
+			Direction: statsv1alpha1.RuleDirectionIngress,
+			Index:     0,
+			TrafficStats: statsv1alpha1.TrafficStats{
+				Bytes:    10,
+				Packets:  1,
+				Sessions: 1,
+			},
+		},
+		{
+			Direction: statsv1alpha1.RuleDirectionEgress,
+			Index:     0,
+		},
+		{
+			Direction: statsv1alpha1.RuleDirectionEgress,
+			Index:     1,
+		},
+	}, stats.RuleTrafficStats)
+}
+
+func TestNamespaceNetworkSummary(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.AntreaPolicy, true)()
+
+	client := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 12*time.Hour)
+	crdClient := fakeversioned.NewSimpleClientset()
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 12*time.Hour)
+	a := NewAggregator(informerFactory.Networking().V1().NetworkPolicies(), crdInformerFactory.Security().V1alpha1().ClusterNetworkPolicies(), crdInformerFactory.Security().V1alpha1().NetworkPolicies(), nil)
+
+	a.addNetworkPolicy(np1)
+	a.addANP(anp1)
+	fooFlow := statsv1alpha1.FlowSummary{
+		Namespace:    "foo",
+		Pod:          "pod1",
+		Direction:    statsv1alpha1.RuleDirectionEgress,
+		Peer:         "bar/pod2",
+		Protocol:     "TCP",
+		Port:         80,
+		TrafficStats: statsv1alpha1.TrafficStats{Bytes: 10, Packets: 1, Sessions: 1},
+	}
+	barFlow := statsv1alpha1.FlowSummary{
+		Namespace:    "bar",
+		Pod:          "pod2",
+		Direction:    statsv1alpha1.RuleDirectionIngress,
+		Peer:         "foo/pod1",
+		Protocol:     "TCP",
+		Port:         80,
+		TrafficStats: statsv1alpha1.TrafficStats{Bytes: 10, Packets: 1, Sessions: 1},
+	}
+	a.doCollect(&controlplane.NodeStatsSummary{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		NetworkPolicies: []controlplane.NetworkPolicyStats{
+			{
+				NetworkPolicy: controlplane.NetworkPolicyReference{UID: np1.UID},
+				TrafficStats:  statsv1alpha1.TrafficStats{Bytes: 10, Packets: 1, Sessions: 1},
+			},
+		},
+		Flows: []statsv1alpha1.FlowSummary{fooFlow},
+	})
+	a.doCollect(&controlplane.NodeStatsSummary{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+		Flows:      []statsv1alpha1.FlowSummary{barFlow},
+	})
+
+	fooSummary := a.GetNamespaceNetworkSummary("foo")
+	assert.Equal(t, []statsv1alpha1.PolicyTrafficStats{{Name: "bar", TrafficStats: statsv1alpha1.TrafficStats{Bytes: 10, Packets: 1, Sessions: 1}}}, fooSummary.NetworkPolicies)
+	assert.Equal(t, []statsv1alpha1.PolicyTrafficStats{{Name: "bar"}}, fooSummary.AntreaNetworkPolicies)
+	assert.Equal(t, []statsv1alpha1.FlowSummary{fooFlow}, fooSummary.Flows)
+
+	summaries := a.ListNamespaceNetworkSummaries("")
+	require.Equal(t, 2, len(summaries))
+	assert.Equal(t, "bar", summaries[0].Name)
+	assert.Equal(t, []statsv1alpha1.FlowSummary{barFlow}, summaries[0].Flows)
+	assert.Equal(t, "foo", summaries[1].Name)
+
+	// The flows reported by a Node are replaced by its next report.
+	a.doCollect(&controlplane.NodeStatsSummary{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}})
+	assert.Empty(t, a.GetNamespaceNetworkSummary("bar").Flows)
+	// The flows of the Nodes which stop reporting expire.
+	a.nodeFlows["node-1"].updateTime = time.Now().Add(-flowExpiry - time.Second)
+	assert.Empty(t, a.GetNamespaceNetworkSummary("foo").Flows)
+	assert.Equal(t, 1, len(a.ListNamespaceNetworkSummaries("")))
+}
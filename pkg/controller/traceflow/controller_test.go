@@ -111,6 +111,32 @@ func TestTraceflow(t *testing.T) {
 	assert.Equal(t, numRunningTraceflows(), 0)
 	tfc.client.OpsV1alpha1().Traceflows().Delete(context.TODO(), "tf1", metav1.DeleteOptions{})
 
+	// Test Controller handling of Traceflow to an external IP, which is only reported by the sender.
+	tf2 := ops.Traceflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "tf2", UID: "uid2"},
+		Spec: ops.TraceflowSpec{
+			Source:      ops.Source{Namespace: "ns1", Pod: "pod1"},
+			Destination: ops.Destination{IP: "8.8.8.8"},
+		},
+	}
+	tfc.client.OpsV1alpha1().Traceflows().Create(context.TODO(), &tf2, metav1.CreateOptions{})
+	res, _ = tfc.waitForTraceflow("tf2", ops.Running, time.Second)
+	assert.NotNil(t, res)
+	res.Status.Results = []ops.NodeResult{
+		{
+			Observations: []ops.Observation{
+				{Component: ops.SpoofGuard},
+				{Component: ops.Egress, Action: ops.Forwarded, TranslatedSrcIP: "192.168.1.1"},
+				{Component: ops.Forwarding, Action: ops.ForwardedOutOfOverlay, EgressInterface: "eth0"},
+			},
+		},
+	}
+	tfc.client.OpsV1alpha1().Traceflows().Update(context.TODO(), res, metav1.UpdateOptions{})
+	res, _ = tfc.waitForTraceflow("tf2", ops.Succeeded, time.Second)
+	assert.NotNil(t, res)
+	assert.Equal(t, numRunningTraceflows(), 0)
+	tfc.client.OpsV1alpha1().Traceflows().Delete(context.TODO(), "tf2", metav1.DeleteOptions{})
+
 	// Test Traceflow timeout.
 	startTime := time.Now()
 	tfc.client.OpsV1alpha1().Traceflows().Create(context.TODO(), &tf1, metav1.CreateOptions{})
@@ -39,7 +39,8 @@ func (meta *SpanMeta) Has(nodeName string) bool {
 
 // GroupSelector describes how to select Pods.
 type GroupSelector struct {
-	// The normalized name is calculated from Namespace, PodSelector, ExternalEntitySelector and NamespaceSelector.
+	// The normalized name is calculated from Namespace, PodSelector, ExternalEntitySelector, NamespaceSelector and
+	// NodeSelector.
 	// If multiple policies have same selectors, they should share this group by comparing NormalizedName.
 	// It's also used to generate Name and UUID of group.
 	NormalizedName string
@@ -57,6 +58,9 @@ type GroupSelector struct {
 	// If Namespace and NamespaceSelector both are unset, it selects the ExternalEntities in all the Namespaces.
 	// TODO: Add validation in API to not allow externalEntitySelector and podSelector in the same group.
 	ExternalEntitySelector labels.Selector
+	// This is a label selector which selects Nodes. It cannot be set with any other field, and is only used by the
+	// AppliedToGroups of Antrea ClusterNetworkPolicies.
+	NodeSelector labels.Selector
 }
 
 // AppliedToGroup describes a set of Pods to apply Network Policies to.
@@ -101,6 +105,9 @@ type NetworkPolicy struct {
 	UID types.UID
 	// Name of the internal Network Policy.
 	Name string
+	// Generation of the original Network Policy the internal Network Policy
+	// is computed from. It's only set for Antrea-native policies.
+	Generation int64
 	// Namespace of the original K8s Network Policy.
 	// An empty value indicates that the Network Policy is Cluster scoped.
 	Namespace string
@@ -0,0 +1,158 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watermark
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+const (
+	// checkInterval is the interval at which the watermarks are checked.
+	checkInterval = 10 * time.Second
+	// recoveryRatio is the ratio of the watermarks which the usage must go
+	// below to leave degraded mode, so that the controller doesn't flap
+	// between modes when the usage is close to a watermark.
+	recoveryRatio = 0.9
+)
+
+// queue is a work queue whose length is checked against the queue watermark.
+type queue struct {
+	name   string
+	length func() int
+}
+
+// Monitor checks the memory usage of antrea-controller and the length of its
+// work queues against the configured watermarks. When any watermark is
+// exceeded, the controller enters degraded mode, in which non-critical work,
+// e.g. the re-evaluation of rule schedules and the aggregation of stats, is
+// slowed down, so that the controller keeps computing NetworkPolicies instead
+// of running out of memory. The controller leaves degraded mode once all the
+// usages are below 90% of the watermarks.
+// A nil Monitor is valid and never reports degraded mode.
+type Monitor struct {
+	// memoryWatermark is the heap memory in bytes, 0 means no watermark.
+	memoryWatermark uint64
+	// queueWatermark is the total length of the work queues, 0 means no
+	// watermark.
+	queueWatermark int
+	queues         []queue
+	// readMemory returns the heap memory in use in bytes. It can be
+	// overridden for testing.
+	readMemory func() uint64
+
+	mutex    sync.RWMutex
+	degraded bool
+	// message describes the exceeded watermarks in degraded mode.
+	message string
+}
+
+// NewMonitor returns a Monitor with the given watermarks. memoryWatermark is
+// in bytes. A watermark is ignored if it is 0.
+func NewMonitor(memoryWatermark uint64, queueWatermark int) *Monitor {
+	return &Monitor{
+		memoryWatermark: memoryWatermark,
+		queueWatermark:  queueWatermark,
+		readMemory:      readHeapInuse,
+	}
+}
+
+func readHeapInuse() uint64 {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return memStats.HeapInuse
+}
+
+// AddQueue adds a work queue whose length counts towards the queue watermark.
+// It must be called before Run.
+func (m *Monitor) AddQueue(name string, length func() int) {
+	if m == nil {
+		return
+	}
+	m.queues = append(m.queues, queue{name: name, length: length})
+}
+
+// Run checks the watermarks periodically until stopCh is closed.
+func (m *Monitor) Run(stopCh <-chan struct{}) {
+	klog.Infof("Starting watermark monitor with memory watermark %d bytes and queue watermark %d", m.memoryWatermark, m.queueWatermark)
+	wait.Until(m.check, checkInterval, stopCh)
+}
+
+// check updates the mode of the controller according to the current usages.
+func (m *Monitor) check() {
+	// When in degraded mode, the watermarks are lowered so that the usages
+	// must go clearly below them to recover.
+	ratio := 1.0
+	if m.IsDegraded() {
+		ratio = recoveryRatio
+	}
+	var exceeded []string
+	if m.memoryWatermark > 0 {
+		if memory := m.readMemory(); float64(memory) > float64(m.memoryWatermark)*ratio {
+			exceeded = append(exceeded, fmt.Sprintf("memory usage %d bytes exceeds watermark %d bytes", memory, m.memoryWatermark))
+		}
+	}
+	if m.queueWatermark > 0 {
+		total := 0
+		lengths := make([]string, 0, len(m.queues))
+		for _, q := range m.queues {
+			length := q.length()
+			total += length
+			lengths = append(lengths, fmt.Sprintf("%s: %d", q.name, length))
+		}
+		if float64(total) > float64(m.queueWatermark)*ratio {
+			exceeded = append(exceeded, fmt.Sprintf("queue length %d (%s) exceeds watermark %d", total, strings.Join(lengths, ", "), m.queueWatermark))
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	degraded := len(exceeded) > 0
+	if degraded && !m.degraded {
+		klog.Warningf("Entering degraded mode, shedding non-critical load: %s", strings.Join(exceeded, "; "))
+	} else if !degraded && m.degraded {
+		klog.Info("Leaving degraded mode, usages are below watermarks")
+	}
+	m.degraded = degraded
+	m.message = strings.Join(exceeded, "; ")
+}
+
+// IsDegraded returns true if the controller is in degraded mode, in which
+// non-critical work should be slowed down.
+func (m *Monitor) IsDegraded() bool {
+	if m == nil {
+		return false
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.degraded
+}
+
+// GetStatus returns whether the controller is in degraded mode and, if it is,
+// a message describing the exceeded watermarks.
+func (m *Monitor) GetStatus() (bool, string) {
+	if m == nil {
+		return false, ""
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.degraded, m.message
+}
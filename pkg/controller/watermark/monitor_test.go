@@ -0,0 +1,77 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watermark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorMemoryWatermark(t *testing.T) {
+	var memory uint64
+	m := NewMonitor(1000, 0)
+	m.readMemory = func() uint64 { return memory }
+
+	memory = 900
+	m.check()
+	assert.False(t, m.IsDegraded())
+
+	memory = 1001
+	m.check()
+	degraded, message := m.GetStatus()
+	assert.True(t, degraded)
+	assert.Equal(t, "memory usage 1001 bytes exceeds watermark 1000 bytes", message)
+
+	// The usage must go below 90% of the watermark to leave degraded mode.
+	memory = 950
+	m.check()
+	assert.True(t, m.IsDegraded())
+	memory = 899
+	m.check()
+	degraded, message = m.GetStatus()
+	assert.False(t, degraded)
+	assert.Empty(t, message)
+}
+
+func TestMonitorQueueWatermark(t *testing.T) {
+	lengthA, lengthB := 0, 0
+	m := NewMonitor(0, 100)
+	m.AddQueue("a", func() int { return lengthA })
+	m.AddQueue("b", func() int { return lengthB })
+
+	lengthA, lengthB = 60, 30
+	m.check()
+	assert.False(t, m.IsDegraded())
+
+	lengthB = 50
+	m.check()
+	degraded, message := m.GetStatus()
+	assert.True(t, degraded)
+	assert.Equal(t, "queue length 110 (a: 60, b: 50) exceeds watermark 100", message)
+
+	lengthA, lengthB = 0, 0
+	m.check()
+	assert.False(t, m.IsDegraded())
+}
+
+func TestNilMonitor(t *testing.T) {
+	var m *Monitor
+	m.AddQueue("a", func() int { return 1 })
+	assert.False(t, m.IsDegraded())
+	degraded, message := m.GetStatus()
+	assert.False(t, degraded)
+	assert.Empty(t, message)
+}
@@ -50,6 +50,21 @@ const (
 	// alpha: v0.10
 	// Enable collecting and exposing NetworkPolicy statistics.
 	NetworkPolicyStats featuregate.Feature = "NetworkPolicyStats"
+
+	// alpha: v0.11
+	// Enable AntreaProxy to consume EndpointSlices instead of Endpoints. Endpoints
+	// are still watched for Services which don't have any EndpointSlice.
+	EndpointSlice featuregate.Feature = "EndpointSlice"
+
+	// alpha: v0.11
+	// Allows to SNAT the traffic from the Pods selected by Egress CRDs to the
+	// specified Egress IPs.
+	Egress featuregate.Feature = "Egress"
+
+	// alpha: v0.11
+	// Allows to allocate the IPs of the Pods of the annotated Namespaces and
+	// Pods from IPPool CRDs, instead of the PodCIDR of their Node.
+	AntreaIPAM featuregate.Feature = "AntreaIPAM"
 )
 
 var (
@@ -69,6 +84,9 @@ var (
 		Traceflow:          {Default: false, PreRelease: featuregate.Alpha},
 		FlowExporter:       {Default: false, PreRelease: featuregate.Alpha},
 		NetworkPolicyStats: {Default: false, PreRelease: featuregate.Alpha},
+		EndpointSlice:      {Default: false, PreRelease: featuregate.Alpha},
+		Egress:             {Default: false, PreRelease: featuregate.Alpha},
+		AntreaIPAM:         {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
 
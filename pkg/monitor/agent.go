@@ -16,10 +16,12 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog"
 
@@ -28,31 +30,37 @@ import (
 	clientset "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned"
 )
 
+// agentInfoReportJitterFactor is the maximum factor of the report interval by which each update of the agent
+// monitoring CRD is delayed, so that the updates from the agents of a large cluster are spread over time.
+const agentInfoReportJitterFactor = 0.2
+
 type agentMonitor struct {
 	client  clientset.Interface
 	querier agentquerier.AgentQuerier
+	// reportInterval is the interval at which the agent monitoring CRD is updated.
+	reportInterval time.Duration
 	// agentCRD is the desired state of agent monitoring CRD which agentMonitor expects.
 	agentCRD *v1beta1.AntreaAgentInfo
 }
 
 // NewAgentMonitor creates a new agent monitor.
-func NewAgentMonitor(client clientset.Interface, querier agentquerier.AgentQuerier) *agentMonitor {
-	return &agentMonitor{client: client, querier: querier, agentCRD: nil}
+func NewAgentMonitor(client clientset.Interface, querier agentquerier.AgentQuerier, reportInterval time.Duration) *agentMonitor {
+	return &agentMonitor{client: client, querier: querier, reportInterval: reportInterval, agentCRD: nil}
 }
 
 // Run creates AntreaAgentInfo CRD first after controller is running.
-// Then updates AntreaAgentInfo CRD every 60 seconds.
+// Then updates the dynamic fields of AntreaAgentInfo CRD every reportInterval, with a jitter.
 func (monitor *agentMonitor) Run(stopCh <-chan struct{}) {
 	klog.Info("Starting Antrea Agent Monitor")
 
-	// Sync agent monitoring CRD every minute util stopCh is closed.
-	wait.Until(monitor.syncAgentCRD, time.Minute, stopCh)
+	// Sync agent monitoring CRD every reportInterval util stopCh is closed.
+	wait.JitterUntil(monitor.syncAgentCRD, monitor.reportInterval, agentInfoReportJitterFactor, true, stopCh)
 }
 
 func (monitor *agentMonitor) syncAgentCRD() {
 	var err error = nil
 	if monitor.agentCRD != nil {
-		if monitor.agentCRD, err = monitor.updateAgentCRD(true); err == nil {
+		if monitor.agentCRD, err = monitor.patchAgentCRD(); err == nil {
 			return
 		}
 		klog.Errorf("Failed to partially update agent monitoring CRD: %v", err)
@@ -76,7 +84,7 @@ func (monitor *agentMonitor) syncAgentCRD() {
 		return
 	}
 
-	monitor.agentCRD, err = monitor.updateAgentCRD(false)
+	monitor.agentCRD, err = monitor.updateAgentCRD()
 	if err != nil {
 		klog.Errorf("Failed to entirely update agent monitoring CRD: %v", err)
 		monitor.agentCRD = nil
@@ -99,9 +107,38 @@ func (monitor *agentMonitor) createAgentCRD() (*v1beta1.AntreaAgentInfo, error)
 	return monitor.client.ClusterinformationV1beta1().AntreaAgentInfos().Create(context.TODO(), agentCRD, metav1.CreateOptions{})
 }
 
-// updateAgentCRD updates the monitoring CRD.
-func (monitor *agentMonitor) updateAgentCRD(partial bool) (*v1beta1.AntreaAgentInfo, error) {
-	monitor.querier.GetAgentInfo(monitor.agentCRD, partial)
-	klog.V(2).Infof("Updating agent monitoring CRD %+v, partial: %t", monitor.agentCRD, partial)
+// updateAgentCRD updates the monitoring CRD entirely, including the static information of the agent, which
+// only changes when the agent restarts.
+func (monitor *agentMonitor) updateAgentCRD() (*v1beta1.AntreaAgentInfo, error) {
+	monitor.querier.GetAgentInfo(monitor.agentCRD, false)
+	klog.V(2).Infof("Updating agent monitoring CRD %+v", monitor.agentCRD)
 	return monitor.client.ClusterinformationV1beta1().AntreaAgentInfos().Update(context.TODO(), monitor.agentCRD, metav1.UpdateOptions{})
 }
+
+// patchAgentCRD updates the dynamic fields of the monitoring CRD, i.e. the health and the runtime information of
+// the agent. It uses a JSON patch which replaces these fields only and doesn't carry the resourceVersion, so that
+// the apiserver doesn't have to process the whole object and the update never conflicts.
+func (monitor *agentMonitor) patchAgentCRD() (*v1beta1.AntreaAgentInfo, error) {
+	monitor.querier.GetAgentInfo(monitor.agentCRD, true)
+	patch, err := json.Marshal([]jsonPatchOperation{
+		{Op: "add", Path: "/localPodNum", Value: monitor.agentCRD.LocalPodNum},
+		{Op: "add", Path: "/ovsInfo/version", Value: monitor.agentCRD.OVSInfo.Version},
+		{Op: "add", Path: "/ovsInfo/flowTable", Value: monitor.agentCRD.OVSInfo.FlowTable},
+		{Op: "add", Path: "/networkPolicyControllerInfo", Value: monitor.agentCRD.NetworkPolicyControllerInfo},
+		{Op: "add", Path: "/agentConditions", Value: monitor.agentCRD.AgentConditions},
+		{Op: "add", Path: "/nodeCapacity", Value: monitor.agentCRD.NodeCapacity},
+	})
+	if err != nil {
+		return nil, err
+	}
+	klog.V(2).Infof("Patching agent monitoring CRD %s: %s", monitor.agentCRD.Name, patch)
+	return monitor.client.ClusterinformationV1beta1().AntreaAgentInfos().Patch(context.TODO(), monitor.agentCRD.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+}
+
+// jsonPatchOperation is an operation of a JSON patch (RFC 6902). The "add" operation replaces the value of an existing
+// object member, and adds it if it doesn't exist, e.g. because it was omitted as empty.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
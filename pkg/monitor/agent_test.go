@@ -0,0 +1,104 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/antrea/pkg/agent/config"
+	queriertest "github.com/vmware-tanzu/antrea/pkg/agent/querier/testing"
+	"github.com/vmware-tanzu/antrea/pkg/apis/clusterinformation/v1beta1"
+	fakeversioned "github.com/vmware-tanzu/antrea/pkg/client/clientset/versioned/fake"
+)
+
+func TestSyncAgentCRD(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := fakeversioned.NewSimpleClientset()
+	q := queriertest.NewMockAgentQuerier(ctrl)
+	q.EXPECT().GetNodeConfig().Return(&config.NodeConfig{Name: "node1"}).AnyTimes()
+	monitor := NewAgentMonitor(client, q, time.Minute)
+
+	// The agent monitoring CRD is created with the static and the dynamic information of the agent.
+	q.EXPECT().GetAgentInfo(gomock.Any(), false).Do(func(agentInfo *v1beta1.AntreaAgentInfo, partial bool) {
+		agentInfo.Name = "node1"
+		agentInfo.Version = "v1.0.0"
+		agentInfo.OVSInfo = v1beta1.OVSInfo{Version: "2.14.0", BridgeName: "br-int", FlowTable: map[string]int32{"0": 10}}
+		agentInfo.LocalPodNum = 2
+		agentInfo.NodeCapacity.ConntrackCount = 100
+	})
+	monitor.syncAgentCRD()
+	require.NotNil(t, monitor.agentCRD)
+
+	// Then only the dynamic information is patched, including the fields which became empty.
+	client.ClearActions()
+	q.EXPECT().GetAgentInfo(gomock.Any(), true).Do(func(agentInfo *v1beta1.AntreaAgentInfo, partial bool) {
+		agentInfo.OVSInfo.FlowTable = map[string]int32{"0": 12}
+		agentInfo.LocalPodNum = 0
+		agentInfo.NodeCapacity = v1beta1.NodeCapacityInfo{}
+		agentInfo.AgentConditions = []v1beta1.AgentCondition{{Type: v1beta1.AgentHealthy, Status: "True"}}
+	})
+	monitor.syncAgentCRD()
+	require.NotNil(t, monitor.agentCRD)
+
+	actions := client.Actions()
+	require.Len(t, actions, 1)
+	patchAction, ok := actions[0].(k8stesting.PatchAction)
+	require.True(t, ok, "Expected a patch action, got %v", actions[0])
+	assert.Equal(t, types.JSONPatchType, patchAction.GetPatchType())
+
+	agentInfo, err := client.ClusterinformationV1beta1().AntreaAgentInfos().Get(context.TODO(), "node1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", agentInfo.Version)
+	assert.Equal(t, "br-int", agentInfo.OVSInfo.BridgeName)
+	assert.Equal(t, "2.14.0", agentInfo.OVSInfo.Version)
+	assert.Equal(t, map[string]int32{"0": 12}, agentInfo.OVSInfo.FlowTable)
+	assert.Equal(t, int32(0), agentInfo.LocalPodNum)
+	assert.Equal(t, v1beta1.NodeCapacityInfo{}, agentInfo.NodeCapacity)
+	require.Len(t, agentInfo.AgentConditions, 1)
+	assert.Equal(t, v1beta1.AgentHealthy, agentInfo.AgentConditions[0].Type)
+}
+
+func TestSyncAgentCRDRecreated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := fakeversioned.NewSimpleClientset()
+	q := queriertest.NewMockAgentQuerier(ctrl)
+	q.EXPECT().GetNodeConfig().Return(&config.NodeConfig{Name: "node1"}).AnyTimes()
+	monitor := NewAgentMonitor(client, q, time.Minute)
+	monitor.agentCRD = &v1beta1.AntreaAgentInfo{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+
+	// The patch fails as the agent monitoring CRD was deleted, and it is created again entirely.
+	q.EXPECT().GetAgentInfo(gomock.Any(), true)
+	q.EXPECT().GetAgentInfo(gomock.Any(), false).Do(func(agentInfo *v1beta1.AntreaAgentInfo, partial bool) {
+		agentInfo.Name = "node1"
+		agentInfo.Version = "v1.0.0"
+	})
+	monitor.syncAgentCRD()
+	require.NotNil(t, monitor.agentCRD)
+
+	agentInfo, err := client.ClusterinformationV1beta1().AntreaAgentInfos().Get(context.TODO(), "node1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", agentInfo.Version)
+}
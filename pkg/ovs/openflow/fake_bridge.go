@@ -0,0 +1,474 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/contiv/ofnet/ofctrl"
+)
+
+// FakeBridge is an in-memory implementation of Bridge for unit tests. It does not connect to any OFSwitch: the flows
+// and groups are built with the same FlowBuilder and BucketBuilder as OFBridge, and the entries installed through the
+// Bridge are kept in memory, so that tests can verify the realized flows by table or cookie without a running OVS.
+type FakeBridge struct {
+	sync.RWMutex
+	tables map[TableIDType]*ofTable
+	// flows maps the table ID to the installed flows of the table, keyed by their match and priority.
+	flows map[TableIDType]map[string]*ofFlow
+	// groups maps the group ID to the installed groups.
+	groups     map[GroupIDType]*fakeGroup
+	connected  bool
+	tlvMaps    []TLVMap
+	packetOuts []*ofctrl.PacketOut
+	// pktConsumers maps the packet-in reason to the channel of the consumer.
+	pktConsumers map[uint8]chan *ofctrl.PacketIn
+	// bundleErr is returned by the next bundle, which is then not applied.
+	bundleErr error
+	// meters maps the meter ID to the added meter.
+	meters map[uint32]Meter
+}
+
+// Meter is a meter added to a FakeBridge.
+type Meter struct {
+	Rate      uint32
+	BurstSize uint32
+}
+
+// TLVMap is a TLV mapping added to a FakeBridge.
+type TLVMap struct {
+	OptClass         uint16
+	OptType          uint8
+	OptLength        uint8
+	TunMetadataIndex uint16
+}
+
+// FakeFlow describes a flow installed in a FakeBridge.
+type FakeFlow struct {
+	TableID  TableIDType
+	Priority uint16
+	Cookie   uint64
+	// Match is the readable match string of the flow, as returned by Flow.MatchString.
+	Match string
+	// MeterID is the ID of the meter used by the flow, 0 if none.
+	MeterID uint32
+	// PacketInMeterID is the ID of the meter of the packets sent to the controller by the flow, 0 if none.
+	PacketInMeterID uint32
+}
+
+// String returns the representation of the flow used by the verification helpers of FakeBridge.
+func (f FakeFlow) String() string {
+	return fmt.Sprintf("priority=%d,%s", f.Priority, f.Match)
+}
+
+// NewFakeBridge returns a FakeBridge without any table or flow.
+func NewFakeBridge() *FakeBridge {
+	return &FakeBridge{
+		tables:       map[TableIDType]*ofTable{},
+		flows:        map[TableIDType]map[string]*ofFlow{},
+		groups:       map[GroupIDType]*fakeGroup{},
+		pktConsumers: map[uint8]chan *ofctrl.PacketIn{},
+		meters:       map[uint32]Meter{},
+	}
+}
+
+func (b *FakeBridge) CreateTable(id, next TableIDType, missAction MissActionType) Table {
+	b.Lock()
+	defer b.Unlock()
+	t := newOFTable(id, next, missAction)
+	b.tables[id] = t
+	return t
+}
+
+func (b *FakeBridge) DeleteTable(id TableIDType) bool {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.tables, id)
+	delete(b.flows, id)
+	return true
+}
+
+func (b *FakeBridge) CreateGroup(id GroupIDType) Group {
+	b.Lock()
+	defer b.Unlock()
+	if g, exists := b.groups[id]; exists {
+		return g
+	}
+	g := &fakeGroup{
+		ofGroup: &ofGroup{ofctrl: &ofctrl.Group{ID: uint32(id), GroupType: ofctrl.GroupSelect}},
+		bridge:  b,
+	}
+	g.ofGroup.owner = g
+	return g
+}
+
+func (b *FakeBridge) DeleteGroup(id GroupIDType) bool {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.groups, id)
+	return true
+}
+
+func (b *FakeBridge) DumpTableStatus() []TableStatus {
+	b.RLock()
+	defer b.RUnlock()
+	var r []TableStatus
+	for id := range b.tables {
+		r = append(r, TableStatus{ID: uint(id), FlowCount: uint(len(b.flows[id]))})
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].ID < r[j].ID })
+	return r
+}
+
+// DumpFlows returns the flows matching the cookie. As the flows are not realized, their packet count is always 0.
+func (b *FakeBridge) DumpFlows(cookieID, cookieMask uint64) (map[uint64]*FlowStates, error) {
+	b.RLock()
+	defer b.RUnlock()
+	flowStats := make(map[uint64]*FlowStates)
+	for tableID, flows := range b.flows {
+		for _, f := range flows {
+			if f.CookieID&cookieMask == cookieID&cookieMask {
+				flowStats[f.CookieID] = &FlowStates{TableID: uint8(tableID)}
+			}
+		}
+	}
+	return flowStats, nil
+}
+
+func (b *FakeBridge) DeleteFlowsByCookie(cookieID, cookieMask uint64) error {
+	b.Lock()
+	defer b.Unlock()
+	for _, flows := range b.flows {
+		for key, f := range flows {
+			if f.CookieID&cookieMask == cookieID&cookieMask {
+				delete(flows, key)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *FakeBridge) AddFlowsInBundle(addFlows []Flow, modFlows []Flow, delFlows []Flow) error {
+	var addEntries, modEntries, delEntries []OFEntry
+	for _, f := range addFlows {
+		addEntries = append(addEntries, f)
+	}
+	for _, f := range modFlows {
+		modEntries = append(modEntries, f)
+	}
+	for _, f := range delFlows {
+		delEntries = append(delEntries, f)
+	}
+	return b.AddOFEntriesInBundle(addEntries, modEntries, delEntries)
+}
+
+// AddOFEntriesInBundle applies the changes of the entries atomically: if any entry is invalid, or if an error was
+// injected with InjectBundleError, none of the changes is applied.
+func (b *FakeBridge) AddOFEntriesInBundle(addEntries []OFEntry, modEntries []OFEntry, delEntries []OFEntry) error {
+	b.Lock()
+	defer b.Unlock()
+	if err := b.bundleErr; err != nil {
+		b.bundleErr = nil
+		return err
+	}
+	for _, entries := range [][]OFEntry{addEntries, modEntries, delEntries} {
+		for _, entry := range entries {
+			if err := b.validateEntry(entry); err != nil {
+				return err
+			}
+		}
+	}
+	for _, entry := range addEntries {
+		b.addEntry(entry)
+	}
+	for _, entry := range modEntries {
+		// Like OVS, modifying an entry which doesn't exist does not add it.
+		if b.hasEntry(entry) {
+			b.addEntry(entry)
+		}
+	}
+	for _, entry := range delEntries {
+		b.deleteEntry(entry)
+	}
+	return nil
+}
+
+func (b *FakeBridge) validateEntry(entry OFEntry) error {
+	switch e := entry.(type) {
+	case *ofFlow:
+		if _, exists := b.tables[e.table.id]; !exists {
+			return fmt.Errorf("table %d of flow %s does not exist", e.table.id, e.MatchString())
+		}
+		return nil
+	case *fakeGroup:
+		return nil
+	default:
+		return fmt.Errorf("unsupported entry %s of type %T", entry.KeyString(), entry)
+	}
+}
+
+func flowKey(f *ofFlow) string {
+	return fmt.Sprintf("priority=%d,%s", f.FlowPriority(), f.MatchString())
+}
+
+func (b *FakeBridge) hasEntry(entry OFEntry) bool {
+	switch e := entry.(type) {
+	case *ofFlow:
+		_, exists := b.flows[e.table.id][flowKey(e)]
+		return exists
+	case *fakeGroup:
+		_, exists := b.groups[GroupIDType(e.ofctrl.ID)]
+		return exists
+	}
+	return false
+}
+
+func (b *FakeBridge) addEntry(entry OFEntry) {
+	switch e := entry.(type) {
+	case *ofFlow:
+		flows, exists := b.flows[e.table.id]
+		if !exists {
+			flows = map[string]*ofFlow{}
+			b.flows[e.table.id] = flows
+		}
+		flows[flowKey(e)] = e
+	case *fakeGroup:
+		b.groups[GroupIDType(e.ofctrl.ID)] = e
+	}
+}
+
+func (b *FakeBridge) deleteEntry(entry OFEntry) {
+	switch e := entry.(type) {
+	case *ofFlow:
+		delete(b.flows[e.table.id], flowKey(e))
+	case *fakeGroup:
+		delete(b.groups, GroupIDType(e.ofctrl.ID))
+	}
+}
+
+// Connect marks the FakeBridge as connected, and notifies connectCh like a successful connection to the OFSwitch.
+func (b *FakeBridge) Connect(maxRetrySec int, connectCh chan struct{}) error {
+	b.Lock()
+	b.connected = true
+	b.Unlock()
+	go func() {
+		connectCh <- struct{}{}
+	}()
+	return nil
+}
+
+func (b *FakeBridge) Disconnect() error {
+	b.Lock()
+	defer b.Unlock()
+	b.connected = false
+	return nil
+}
+
+func (b *FakeBridge) IsConnected() bool {
+	b.RLock()
+	defer b.RUnlock()
+	return b.connected
+}
+
+func (b *FakeBridge) SubscribePacketIn(reason uint8, ch chan *ofctrl.PacketIn) error {
+	b.Lock()
+	defer b.Unlock()
+	b.pktConsumers[reason] = ch
+	return nil
+}
+
+func (b *FakeBridge) AddTLVMap(optClass uint16, optType uint8, optLength uint8, tunMetadataIndex uint16) error {
+	b.Lock()
+	defer b.Unlock()
+	b.tlvMaps = append(b.tlvMaps, TLVMap{OptClass: optClass, OptType: optType, OptLength: optLength, TunMetadataIndex: tunMetadataIndex})
+	return nil
+}
+
+func (b *FakeBridge) SendPacketOut(packetOut *ofctrl.PacketOut) error {
+	b.Lock()
+	defer b.Unlock()
+	b.packetOuts = append(b.packetOuts, packetOut)
+	return nil
+}
+
+func (b *FakeBridge) AddMeter(id uint32, rate, burstSize uint32) error {
+	b.Lock()
+	defer b.Unlock()
+	if _, exists := b.meters[id]; exists {
+		return fmt.Errorf("meter %d already exists", id)
+	}
+	b.meters[id] = Meter{Rate: rate, BurstSize: burstSize}
+	return nil
+}
+
+func (b *FakeBridge) DeleteMeter(id uint32) error {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.meters, id)
+	return nil
+}
+
+func (b *FakeBridge) BuildPacketOut() PacketOutBuilder {
+	return &ofPacketOutBuilder{
+		pktOut: new(ofctrl.PacketOut),
+	}
+}
+
+// InjectBundleError makes the next bundle fail with the provided error, without applying any of its changes.
+func (b *FakeBridge) InjectBundleError(err error) {
+	b.Lock()
+	defer b.Unlock()
+	b.bundleErr = err
+}
+
+// SendPacketIn sends the packet-in message to the consumer subscribed to its reason, like a packet-in message received
+// from the OFSwitch. It returns false if there is no consumer.
+func (b *FakeBridge) SendPacketIn(packetIn *ofctrl.PacketIn) bool {
+	b.RLock()
+	ch, found := b.pktConsumers[packetIn.Reason]
+	b.RUnlock()
+	if !found {
+		return false
+	}
+	ch <- packetIn
+	return true
+}
+
+func (b *FakeBridge) fakeFlows(filter func(*ofFlow) bool) []FakeFlow {
+	b.RLock()
+	defer b.RUnlock()
+	var flows []FakeFlow
+	for tableID, tableFlows := range b.flows {
+		for _, f := range tableFlows {
+			if !filter(f) {
+				continue
+			}
+			flows = append(flows, FakeFlow{
+				TableID:         tableID,
+				Priority:        f.FlowPriority(),
+				Cookie:          f.CookieID,
+				Match:           f.MatchString(),
+				MeterID:         f.meterID,
+				PacketInMeterID: f.packetInMeterID(),
+			})
+		}
+	}
+	sort.Slice(flows, func(i, j int) bool {
+		if flows[i].TableID != flows[j].TableID {
+			return flows[i].TableID < flows[j].TableID
+		}
+		if flows[i].Priority != flows[j].Priority {
+			return flows[i].Priority > flows[j].Priority
+		}
+		return flows[i].Match < flows[j].Match
+	})
+	return flows
+}
+
+// Flows returns all the installed flows, ordered by table, by decreasing priority, and by match.
+func (b *FakeBridge) Flows() []FakeFlow {
+	return b.fakeFlows(func(*ofFlow) bool { return true })
+}
+
+// TableFlows returns the installed flows of the table, in the format "priority=<priority>,<match>", ordered by
+// decreasing priority and by match.
+func (b *FakeBridge) TableFlows(tableID TableIDType) []string {
+	return fakeFlowStrings(b.fakeFlows(func(f *ofFlow) bool { return f.table.id == tableID }))
+}
+
+// CookieFlows returns the installed flows whose cookie matches cookieID with cookieMask, in the format
+// "priority=<priority>,<match>", ordered by table, by decreasing priority and by match.
+func (b *FakeBridge) CookieFlows(cookieID, cookieMask uint64) []string {
+	return fakeFlowStrings(b.fakeFlows(func(f *ofFlow) bool { return f.CookieID&cookieMask == cookieID&cookieMask }))
+}
+
+func fakeFlowStrings(flows []FakeFlow) []string {
+	var strs []string
+	for _, f := range flows {
+		strs = append(strs, f.String())
+	}
+	return strs
+}
+
+// HasFlow returns true if a flow with the priority and the match is installed in the table.
+func (b *FakeBridge) HasFlow(tableID TableIDType, priority uint16, match string) bool {
+	b.RLock()
+	defer b.RUnlock()
+	_, exists := b.flows[tableID][fmt.Sprintf("priority=%d,%s", priority, match)]
+	return exists
+}
+
+// GroupBuckets returns the number of buckets of the installed group, and whether the group is installed.
+func (b *FakeBridge) GroupBuckets(id GroupIDType) (int, bool) {
+	b.RLock()
+	defer b.RUnlock()
+	g, exists := b.groups[id]
+	if !exists {
+		return 0, false
+	}
+	return len(g.ofctrl.Buckets), true
+}
+
+// Meters returns the meters added to the FakeBridge, keyed by their ID.
+func (b *FakeBridge) Meters() map[uint32]Meter {
+	b.RLock()
+	defer b.RUnlock()
+	meters := make(map[uint32]Meter, len(b.meters))
+	for id, m := range b.meters {
+		meters[id] = m
+	}
+	return meters
+}
+
+// TLVMaps returns the TLV mappings added to the FakeBridge.
+func (b *FakeBridge) TLVMaps() []TLVMap {
+	b.RLock()
+	defer b.RUnlock()
+	return append([]TLVMap(nil), b.tlvMaps...)
+}
+
+// PacketOuts returns the packet-out messages sent to the FakeBridge.
+func (b *FakeBridge) PacketOuts() []*ofctrl.PacketOut {
+	b.RLock()
+	defer b.RUnlock()
+	return append([]*ofctrl.PacketOut(nil), b.packetOuts...)
+}
+
+// fakeGroup is a group of FakeBridge. It is installed in the FakeBridge instead of the OFSwitch.
+type fakeGroup struct {
+	*ofGroup
+	bridge *FakeBridge
+}
+
+func (g *fakeGroup) Reset() {}
+
+func (g *fakeGroup) Add() error {
+	return g.bridge.AddOFEntriesInBundle([]OFEntry{g}, nil, nil)
+}
+
+func (g *fakeGroup) Modify() error {
+	return g.bridge.AddOFEntriesInBundle(nil, []OFEntry{g}, nil)
+}
+
+func (g *fakeGroup) Delete() error {
+	return g.bridge.AddOFEntriesInBundle(nil, nil, []OFEntry{g})
+}
+
+func (g *fakeGroup) ResetBuckets() Group {
+	g.ofGroup.ResetBuckets()
+	return g
+}
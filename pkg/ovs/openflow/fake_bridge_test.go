@@ -0,0 +1,121 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeBridgeFlows(t *testing.T) {
+	b := NewFakeBridge()
+	table10 := b.CreateTable(10, 20, TableMissActionNext)
+	table20 := b.CreateTable(20, LastTableID, TableMissActionDrop)
+
+	flow1 := table10.BuildFlow(200).MatchProtocol(ProtocolIP).MatchSrcIP(net.ParseIP("10.0.0.1")).
+		Cookie(0x1001).Action().GotoTable(20).Done()
+	flow2 := table10.BuildFlow(100).MatchProtocol(ProtocolARP).
+		Cookie(0x2001).Action().Normal().Done()
+	flow3 := table20.BuildFlow(100).MatchProtocol(ProtocolTCP).MatchDstPort(80, nil).
+		Cookie(0x1002).Action().Drop().Done()
+	require.NoError(t, b.AddFlowsInBundle([]Flow{flow1, flow2, flow3}, nil, nil))
+
+	assert.Equal(t, []string{
+		"priority=200,table=10,ip,nw_src=10.0.0.1",
+		"priority=100,table=10,arp",
+	}, b.TableFlows(10))
+	assert.Equal(t, []string{
+		"priority=200,table=10,ip,nw_src=10.0.0.1",
+		"priority=100,table=20,tcp,tp_dst=0x50",
+	}, b.CookieFlows(0x1000, 0xf000))
+	assert.True(t, b.HasFlow(20, 100, "table=20,tcp,tp_dst=0x50"))
+	assert.Equal(t, []TableStatus{{ID: 10, FlowCount: 2}, {ID: 20, FlowCount: 1}}, b.DumpTableStatus())
+	flowStats, err := b.DumpFlows(0x2000, 0xf000)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint64]*FlowStates{0x2001: {TableID: 10}}, flowStats)
+
+	// A failed bundle must not change the installed flows.
+	b.InjectBundleError(fmt.Errorf("bundle failure"))
+	assert.Error(t, b.AddFlowsInBundle(nil, nil, []Flow{flow1}))
+	assert.Len(t, b.TableFlows(10), 2)
+
+	// Flows of a table which doesn't exist are rejected.
+	unknownTable := newOFTable(30, LastTableID, TableMissActionDrop)
+	flow4 := unknownTable.BuildFlow(100).MatchProtocol(ProtocolIP).Done()
+	assert.Error(t, b.AddFlowsInBundle([]Flow{flow4}, nil, []Flow{flow1}))
+	assert.Len(t, b.TableFlows(10), 2)
+
+	require.NoError(t, b.AddFlowsInBundle(nil, nil, []Flow{flow1}))
+	assert.Equal(t, []string{"priority=100,table=10,arp"}, b.TableFlows(10))
+	require.NoError(t, b.DeleteFlowsByCookie(0x1000, 0xf000))
+	assert.Empty(t, b.TableFlows(20))
+	assert.Len(t, b.Flows(), 1)
+}
+
+func TestFakeBridgeGroups(t *testing.T) {
+	b := NewFakeBridge()
+	group := b.CreateGroup(1).
+		Bucket().Weight(100).ResubmitToTable(40).Done().
+		Bucket().Weight(100).ResubmitToTable(40).Done()
+	_, found := b.GroupBuckets(1)
+	assert.False(t, found, "Group should not be installed before it is added")
+	require.NoError(t, group.Add())
+	buckets, found := b.GroupBuckets(1)
+	assert.True(t, found)
+	assert.Equal(t, 2, buckets)
+
+	require.NoError(t, group.ResetBuckets().Bucket().Weight(100).ResubmitToTable(40).Done().Modify())
+	buckets, _ = b.GroupBuckets(1)
+	assert.Equal(t, 1, buckets)
+
+	require.NoError(t, group.Delete())
+	_, found = b.GroupBuckets(1)
+	assert.False(t, found)
+}
+
+func TestFakeBridgeConnectionAndPackets(t *testing.T) {
+	b := NewFakeBridge()
+	connCh := make(chan struct{})
+	require.NoError(t, b.Connect(1, connCh))
+	select {
+	case <-connCh:
+	case <-time.After(time.Second):
+		t.Fatal("Connection was not notified")
+	}
+	assert.True(t, b.IsConnected())
+
+	pktInCh := make(chan *ofctrl.PacketIn, 1)
+	require.NoError(t, b.SubscribePacketIn(1, pktInCh))
+	assert.False(t, b.SendPacketIn(&ofctrl.PacketIn{Reason: 0}))
+	assert.True(t, b.SendPacketIn(&ofctrl.PacketIn{Reason: 1}))
+	assert.Equal(t, uint8(1), (<-pktInCh).Reason)
+
+	pktOut := b.BuildPacketOut().SetSrcIP(net.ParseIP("10.0.0.1")).SetDstIP(net.ParseIP("10.0.0.2")).
+		SetIPProtocol(ProtocolICMP).SetICMPType(8).SetInport(1).SetOutport(2).Done()
+	require.NoError(t, b.SendPacketOut(pktOut))
+	assert.Equal(t, []*ofctrl.PacketOut{pktOut}, b.PacketOuts())
+
+	require.NoError(t, b.AddTLVMap(0x104, 0, 28, 0))
+	assert.Equal(t, []TLVMap{{OptClass: 0x104, OptLength: 28}}, b.TLVMaps())
+
+	require.NoError(t, b.Disconnect())
+	assert.False(t, b.IsConnected())
+}
@@ -41,6 +41,7 @@ const (
 	ProtocolUDP  Protocol = "udp"
 	ProtocolSCTP Protocol = "sctp"
 	ProtocolICMP Protocol = "icmp"
+	ProtocolIGMP Protocol = "igmp"
 )
 
 const (
@@ -108,6 +109,11 @@ type Bridge interface {
 	SendPacketOut(packetOut *ofctrl.PacketOut) error
 	// BuildPacketOut returns a new PacketOutBuilder.
 	BuildPacketOut() PacketOutBuilder
+	// AddMeter adds the meter with the specified ID, which drops the packets above the rate, in packets per second,
+	// allowing bursts of burstSize packets. It fails in the OFSwitch if the meter already exists.
+	AddMeter(id uint32, rate, burstSize uint32) error
+	// DeleteMeter deletes the meter with the specified ID. The flows using the meter are deleted by the OFSwitch.
+	DeleteMeter(id uint32) error
 }
 
 // TableStatus represents the status of a specific flow table. The status is useful for debugging.
@@ -187,13 +193,20 @@ type Action interface {
 	Group(id GroupIDType) FlowBuilder
 	Learn(id TableIDType, priority uint16, idleTimeout, hardTimeout uint16, cookieID uint64) LearnAction
 	GotoTable(table TableIDType) FlowBuilder
+	// Meter passes the packets to the meter with the specified ID, which drops the packets above its rate, before
+	// applying the other actions.
+	Meter(meterID uint32) FlowBuilder
 	SendToController(reason uint8) FlowBuilder
+	// SendToControllerWithMeter sends the packets to the controller through the meter with the specified ID. Unlike
+	// Meter, the meter only drops the packets sent to the controller, and the other actions apply to all the packets.
+	SendToControllerWithMeter(reason uint8, meterID uint32) FlowBuilder
 	Note(notes string) FlowBuilder
 }
 
 type FlowBuilder interface {
 	MatchPriority(uint16) FlowBuilder
 	MatchProtocol(name Protocol) FlowBuilder
+	MatchIPProtocolValue(protocol uint8) FlowBuilder
 	MatchReg(regID int, data uint32) FlowBuilder
 	MatchRegRange(regID int, data uint32, rng Range) FlowBuilder
 	MatchInPort(inPort uint32) FlowBuilder
@@ -218,6 +231,7 @@ type FlowBuilder interface {
 	MatchCTMark(value uint32, mask *uint32) FlowBuilder
 	MatchCTLabelRange(high, low uint64, bitRange Range) FlowBuilder
 	MatchConjID(value uint32) FlowBuilder
+	MatchSrcPort(port uint16, portMask *uint16) FlowBuilder
 	MatchDstPort(port uint16, portMask *uint16) FlowBuilder
 	MatchTunMetadata(index int, data uint32) FlowBuilder
 	// MatchCTSrcIP matches the source IPv4 address of the connection tracker original direction tuple.
@@ -302,6 +316,7 @@ type PacketOutBuilder interface {
 	SetTCPFlags(flags uint8) PacketOutBuilder
 	SetUDPSrcPort(port uint16) PacketOutBuilder
 	SetUDPDstPort(port uint16) PacketOutBuilder
+	SetUDPData(data []byte) PacketOutBuilder
 	SetICMPType(icmpType uint8) PacketOutBuilder
 	SetICMPCode(icmpCode uint8) PacketOutBuilder
 	SetICMPID(id uint16) PacketOutBuilder
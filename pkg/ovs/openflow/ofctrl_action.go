@@ -319,8 +319,28 @@ func (a *ofFlowAction) Note(notes string) FlowBuilder {
 
 func (a *ofFlowAction) SendToController(reason uint8) FlowBuilder {
 	controllerAct := &ofctrl.NXController{
-		ControllerID: a.builder.ofFlow.Table.Switch.GetControllerID(),
-		Reason:       reason,
+		Reason: reason,
+	}
+	// The Table is not connected to an OFSwitch when the Flow is built for a FakeBridge.
+	if a.builder.ofFlow.Table != nil && a.builder.ofFlow.Table.Switch != nil {
+		controllerAct.ControllerID = a.builder.ofFlow.Table.Switch.GetControllerID()
+	}
+	a.builder.ApplyAction(controllerAct)
+	return a.builder
+}
+
+// SendToControllerWithMeter is an action to send the packets to the controller
+// through the meter with the specified ID, which drops the packets above its
+// rate. The meter only applies to the packets sent to the controller: the
+// other actions of the flow are applied to all the packets.
+func (a *ofFlowAction) SendToControllerWithMeter(reason uint8, meterID uint32) FlowBuilder {
+	controllerAct := &meteredController{
+		reason:  reason,
+		meterID: meterID,
+	}
+	// The Table is not connected to an OFSwitch when the Flow is built for a FakeBridge.
+	if a.builder.ofFlow.Table != nil && a.builder.ofFlow.Table.Switch != nil {
+		controllerAct.controllerID = a.builder.ofFlow.Table.Switch.GetControllerID()
 	}
 	a.builder.ApplyAction(controllerAct)
 	return a.builder
@@ -457,6 +477,14 @@ func getFieldRange(name string) (*openflow13.MatchField, Range, error) {
 	return field, Range{0, uint32(field.Length)*8 - 1}, nil
 }
 
+// Meter is an action to pass the packets to the meter with the specified ID
+// before applying the other actions. The packets above the rate of the meter
+// are dropped.
+func (a *ofFlowAction) Meter(meterID uint32) FlowBuilder {
+	a.builder.setMeter(meterID)
+	return a.builder
+}
+
 // GotoTable is an action to jump to the specified table.
 func (a *ofFlowAction) GotoTable(table TableIDType) FlowBuilder {
 	a.builder.ofFlow.Goto(uint8(table))
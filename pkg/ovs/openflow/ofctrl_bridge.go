@@ -206,7 +206,7 @@ func (b *OFBridge) DumpTableStatus() []TableStatus {
 
 // PacketRcvd is a callback when a packetIn is received on ofctrl.OFSwitch.
 func (b *OFBridge) PacketRcvd(sw *ofctrl.OFSwitch, packet *ofctrl.PacketIn) {
-	klog.Infof("Received packet: %+v", packet)
+	klog.V(4).Infof("Received packet: %+v", packet)
 	reason := packet.Reason
 	ch, found := b.pktConsumers.Load(reason)
 	if found {
@@ -539,6 +539,21 @@ func (b *OFBridge) SendPacketOut(packetOut *ofctrl.PacketOut) error {
 	return b.ofSwitch.Send(packetOut.GetMessage())
 }
 
+func (b *OFBridge) AddMeter(id uint32, rate, burstSize uint32) error {
+	meterMod := newMeterMod(meterCommandAdd, id)
+	meterMod.Flags = meterFlagPktps | meterFlagStats
+	if burstSize > 0 {
+		meterMod.Flags |= meterFlagBurst
+	}
+	meterMod.Rate = rate
+	meterMod.BurstSize = burstSize
+	return b.ofSwitch.Send(meterMod)
+}
+
+func (b *OFBridge) DeleteMeter(id uint32) error {
+	return b.ofSwitch.Send(newMeterMod(meterCommandDelete, id))
+}
+
 func (b *OFBridge) BuildPacketOut() PacketOutBuilder {
 	return &ofPacketOutBuilder{
 		pktOut: new(ofctrl.PacketOut),
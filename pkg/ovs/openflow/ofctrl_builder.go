@@ -366,11 +366,37 @@ func (b *ofFlowBuilder) MatchProtocol(protocol Protocol) FlowBuilder {
 	case ProtocolICMP:
 		b.Match.Ethertype = 0x0800
 		b.Match.IpProto = 1
+	case ProtocolIGMP:
+		b.Match.Ethertype = 0x0800
+		b.Match.IpProto = 2
 	}
 	b.protocol = protocol
 	return b
 }
 
+// MatchIPProtocolValue adds match condition for matching the IANA number of the IP protocol. It is used for the
+// protocols which don't have a Protocol name.
+func (b *ofFlowBuilder) MatchIPProtocolValue(protocol uint8) FlowBuilder {
+	b.Match.Ethertype = 0x0800
+	b.Match.IpProto = protocol
+	b.protocol = ProtocolIP
+	b.matchers = append(b.matchers, fmt.Sprintf("nw_proto=%d", protocol))
+	return b
+}
+
+// MatchSrcPort adds match condition for matching source port in transport layer. OVS will match the port exactly
+// if portMask is nil.
+func (b *ofFlowBuilder) MatchSrcPort(port uint16, portMask *uint16) FlowBuilder {
+	b.Match.SrcPort = port
+	b.Match.SrcPortMask = portMask
+	matchStr := fmt.Sprintf("tp_src=0x%x", port)
+	if portMask != nil {
+		matchStr = fmt.Sprintf("%s/0x%x", matchStr, portMask)
+	}
+	b.matchers = append(b.matchers, matchStr)
+	return b
+}
+
 // MatchDstPort adds match condition for matching destination port in transport layer. OVS will match the port exactly
 // if portMask is nil.
 func (b *ofFlowBuilder) MatchDstPort(port uint16, portMask *uint16) FlowBuilder {
@@ -449,6 +475,8 @@ func (b *ofFlowBuilder) MatchCTProtocol(proto Protocol) FlowBuilder {
 		b.Match.CtIpProto = 132
 	case ProtocolICMP:
 		b.Match.CtIpProto = 1
+	case ProtocolIGMP:
+		b.Match.CtIpProto = 2
 	}
 	b.matchers = append(b.matchers, fmt.Sprintf("ct_nw_proto=%d", b.Match.CtIpProto))
 	return b
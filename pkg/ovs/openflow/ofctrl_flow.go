@@ -31,6 +31,15 @@ type ofFlow struct {
 	// ctStates is a temporary variable to maintain openflow13.CTStates. When FlowBuilder.Done is called, it is used to
 	// set the CtStates field in ofctrl.Flow.Match.
 	ctStates *openflow13.CTStates
+
+	// meterID is the ID of the meter the packets matching the Flow are passed
+	// to before its actions are applied, 0 if the Flow doesn't use a meter.
+	meterID uint32
+	// appliedActions and gotoTable record the actions of the ofctrl.Flow,
+	// which are private, to build the instructions of the Flow when it uses a
+	// meter.
+	appliedActions []ofctrl.OFAction
+	gotoTable      *uint8
 }
 
 // Reset updates the ofFlow.Flow.Table field with ofFlow.table.Table.
@@ -41,8 +50,22 @@ func (f *ofFlow) Reset() {
 	f.Flow.Table = f.table.Table
 }
 
+// send sends a FlowMod message with the command for the Flow to the OFSwitch.
+func (f *ofFlow) send(command int) error {
+	if f.meterID == 0 {
+		return f.Flow.Send(command)
+	}
+	// Only GenerateFlowModMessage builds the instructions from the next
+	// element of the ofctrl.Flow.
+	flowMod, err := f.Flow.GenerateFlowModMessage(command)
+	if err != nil {
+		return err
+	}
+	return f.Flow.Table.Switch.Send(flowMod)
+}
+
 func (f *ofFlow) Add() error {
-	err := f.Flow.Send(openflow13.FC_ADD)
+	err := f.send(openflow13.FC_ADD)
 	if err != nil {
 		return err
 	}
@@ -51,7 +74,7 @@ func (f *ofFlow) Add() error {
 }
 
 func (f *ofFlow) Modify() error {
-	err := f.Flow.Send(openflow13.FC_MODIFY_STRICT)
+	err := f.send(openflow13.FC_MODIFY_STRICT)
 	if err != nil {
 		return err
 	}
@@ -122,19 +145,61 @@ func (f *ofFlow) CopyToBuilder(priority uint16, copyActions bool) FlowBuilder {
 		CookieMask: f.Flow.CookieMask,
 		Match:      f.Flow.Match,
 	}
-	if copyActions {
-		f.Flow.CopyActionsToNewFlow(flow)
-	}
 	if priority > 0 {
 		flow.Match.Priority = priority
 	}
-	newFlow := ofFlow{
+	newFlow := &ofFlowBuilder{ofFlow{
 		table:    f.table,
 		Flow:     flow,
 		matchers: f.matchers,
 		protocol: f.protocol,
+	}}
+	if copyActions {
+		f.Flow.CopyActionsToNewFlow(flow)
+		newFlow.appliedActions = append([]ofctrl.OFAction(nil), f.appliedActions...)
+		newFlow.gotoTable = f.gotoTable
+		if f.meterID != 0 {
+			newFlow.setMeter(f.meterID)
+		}
 	}
-	return &ofFlowBuilder{newFlow}
+	return newFlow
+}
+
+// ApplyAction adds the action to the actions applied by the Flow.
+func (f *ofFlow) ApplyAction(action ofctrl.OFAction) {
+	f.Flow.ApplyAction(action)
+	f.appliedActions = append(f.appliedActions, action)
+}
+
+// Goto sets the table the packets go to after the actions of the Flow.
+func (f *ofFlow) Goto(tableID uint8) {
+	f.Flow.Goto(tableID)
+	f.gotoTable = &tableID
+}
+
+// Drop removes all the actions of the Flow, so that the packets are dropped.
+func (f *ofFlow) Drop() {
+	f.Flow.Drop()
+	f.appliedActions = nil
+	f.gotoTable = nil
+}
+
+// packetInMeterID returns the ID of the meter of the packets sent to the
+// controller by the Flow, 0 if it doesn't send packets through a meter.
+func (f *ofFlow) packetInMeterID() uint32 {
+	for _, action := range f.appliedActions {
+		if controllerAct, ok := action.(*meteredController); ok {
+			return controllerAct.meterID
+		}
+	}
+	return 0
+}
+
+// setMeter makes the Flow pass the packets to the meter before applying its
+// actions.
+func (f *ofFlow) setMeter(meterID uint32) {
+	f.meterID = meterID
+	f.Flow.NextElem = &meteredFlowElem{flow: f}
 }
 
 func (r *Range) ToNXRange() *openflow13.NXRange {
@@ -24,6 +24,9 @@ import (
 type ofGroup struct {
 	ofctrl *ofctrl.Group
 	bridge *OFBridge
+	// owner is the Group returned by the BucketBuilders of the group. It is
+	// the group itself, unless the group is wrapped, e.g. by a FakeBridge.
+	owner Group
 }
 
 func (g *ofGroup) Reset() {
@@ -73,6 +76,13 @@ func (f *ofGroup) GetBundleMessage(entryOper OFOperation) (ofctrl.OpenFlowModMes
 
 func (g *ofGroup) ResetBuckets() Group {
 	g.ofctrl.Buckets = nil
+	return g.getOwner()
+}
+
+func (g *ofGroup) getOwner() Group {
+	if g.owner != nil {
+		return g.owner
+	}
 	return g
 }
 
@@ -108,5 +118,5 @@ func (b *bucketBuilder) Weight(val uint16) BucketBuilder {
 
 func (b *bucketBuilder) Done() Group {
 	b.group.ofctrl.AddBuckets(b.bucket)
-	return b.group
+	return b.group.getOwner()
 }
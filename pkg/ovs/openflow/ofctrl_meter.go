@@ -0,0 +1,241 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/contiv/libOpenflow/common"
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/ofnet/ofctrl"
+)
+
+// The ofnet library doesn't support OpenFlow meters: the meter_mod message, the
+// meter instruction of the flows and the controller action with a meter are
+// implemented here.
+
+const (
+	meterCommandAdd    uint16 = 0
+	meterCommandDelete uint16 = 2
+
+	meterFlagPktps uint16 = 0x2
+	meterFlagBurst uint16 = 0x4
+	meterFlagStats uint16 = 0x8
+
+	meterBandTypeDrop uint16 = 1
+	meterBandLen      uint16 = 16
+
+	meterModLen   uint16 = 16
+	meterInstrLen uint16 = 8
+
+	// The controller2 action is followed by its properties, each of them
+	// padded to 8 bytes.
+	controller2Len     uint16 = 16
+	controller2PropLen uint16 = 8
+
+	controller2PropMaxLen       uint16 = 0
+	controller2PropControllerID uint16 = 1
+	controller2PropReason       uint16 = 2
+	controller2PropMeterID      uint16 = 5
+
+	// controllerMaxLen is the number of bytes of the packets sent to the
+	// controller, as with the controller action of the ofnet library.
+	controllerMaxLen uint16 = 128
+)
+
+var errUnmarshalNotSupported = errors.New("unmarshalling is not supported")
+
+// meterMod is an OpenFlow 1.3 meter_mod message, with at most one band which
+// drops the packets above its rate.
+type meterMod struct {
+	common.Header
+	Command uint16
+	Flags   uint16
+	MeterID uint32
+	// Rate and BurstSize of the drop band, in packets per second and in
+	// packets. The band is omitted when deleting the meter.
+	Rate      uint32
+	BurstSize uint32
+}
+
+func newMeterMod(command uint16, meterID uint32) *meterMod {
+	m := &meterMod{
+		Header:  openflow13.NewOfp13Header(),
+		Command: command,
+		MeterID: meterID,
+	}
+	m.Header.Type = openflow13.Type_MeterMod
+	return m
+}
+
+func (m *meterMod) hasBand() bool {
+	return m.Command != meterCommandDelete
+}
+
+func (m *meterMod) Len() uint16 {
+	if m.hasBand() {
+		return meterModLen + meterBandLen
+	}
+	return meterModLen
+}
+
+func (m *meterMod) MarshalBinary() ([]byte, error) {
+	m.Header.Length = m.Len()
+	data, err := m.Header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, m.Len()-m.Header.Len())
+	binary.BigEndian.PutUint16(b[0:], m.Command)
+	binary.BigEndian.PutUint16(b[2:], m.Flags)
+	binary.BigEndian.PutUint32(b[4:], m.MeterID)
+	if m.hasBand() {
+		binary.BigEndian.PutUint16(b[8:], meterBandTypeDrop)
+		binary.BigEndian.PutUint16(b[10:], meterBandLen)
+		binary.BigEndian.PutUint32(b[12:], m.Rate)
+		binary.BigEndian.PutUint32(b[16:], m.BurstSize)
+	}
+	return append(data, b...), nil
+}
+
+func (m *meterMod) UnmarshalBinary(data []byte) error {
+	return errUnmarshalNotSupported
+}
+
+// meteredFlowElem is set as the next element of the ofctrl.Flow of a Flow
+// using a meter. The ofnet library builds the instructions of a Flow from its
+// next element when it is set, instead of from its actions: it returns the
+// meter instruction followed by the instructions of the actions of the Flow.
+type meteredFlowElem struct {
+	flow *ofFlow
+}
+
+func (e *meteredFlowElem) Type() string {
+	// The "table" type makes the ofnet library use the instruction as is.
+	return "table"
+}
+
+func (e *meteredFlowElem) GetFlowInstr() openflow13.Instruction {
+	instr := &meterInstructions{meterID: e.flow.meterID}
+	if len(e.flow.appliedActions) > 0 {
+		applyInstr := openflow13.NewInstrApplyActions()
+		for _, action := range e.flow.appliedActions {
+			applyInstr.AddAction(action.GetActionMessage(), false)
+		}
+		instr.instructions = append(instr.instructions, applyInstr)
+	}
+	if e.flow.gotoTable != nil {
+		instr.instructions = append(instr.instructions, openflow13.NewInstrGotoTable(*e.flow.gotoTable))
+	}
+	return instr
+}
+
+// meterInstructions is encoded as a meter instruction followed by the other
+// instructions of the Flow.
+type meterInstructions struct {
+	meterID      uint32
+	instructions []openflow13.Instruction
+}
+
+func (i *meterInstructions) Len() uint16 {
+	n := meterInstrLen
+	for _, instr := range i.instructions {
+		n += instr.Len()
+	}
+	return n
+}
+
+func (i *meterInstructions) MarshalBinary() ([]byte, error) {
+	data := make([]byte, meterInstrLen)
+	binary.BigEndian.PutUint16(data[0:], openflow13.InstrType_METER)
+	binary.BigEndian.PutUint16(data[2:], meterInstrLen)
+	binary.BigEndian.PutUint32(data[4:], i.meterID)
+	for _, instr := range i.instructions {
+		b, err := instr.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+func (i *meterInstructions) UnmarshalBinary(data []byte) error {
+	return errUnmarshalNotSupported
+}
+
+func (i *meterInstructions) AddAction(act openflow13.Action, prepend bool) error {
+	return errors.New("actions cannot be added to the meter instructions")
+}
+
+// meteredController sends the packets to the controller through the meter with
+// the specified ID. Unlike the meter instruction, the meter only applies to the
+// packets sent to the controller, and the packets above its rate are still
+// processed by the other actions of the Flow.
+type meteredController struct {
+	controllerID uint16
+	reason       uint8
+	meterID      uint32
+}
+
+func (a *meteredController) GetActionMessage() openflow13.Action {
+	return &nxActionController2{
+		NXActionHeader:    openflow13.NewNxActionHeader(openflow13.NXAST_CONTROLLER2),
+		meteredController: *a,
+	}
+}
+
+func (a *meteredController) GetActionType() string {
+	return ofctrl.ActTypeController
+}
+
+// nxActionController2 is the Nicira extension controller2 action, with the
+// max_len, controller_id, reason and meter_id properties.
+type nxActionController2 struct {
+	*openflow13.NXActionHeader
+	meteredController
+}
+
+func (a *nxActionController2) Len() uint16 {
+	return controller2Len + 4*controller2PropLen
+}
+
+func (a *nxActionController2) MarshalBinary() ([]byte, error) {
+	a.Length = a.Len()
+	header, err := a.NXActionHeader.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, a.Len())
+	copy(data, header)
+	b := data[controller2Len:]
+	putProp := func(propType, valueLen uint16) []byte {
+		binary.BigEndian.PutUint16(b[0:], propType)
+		binary.BigEndian.PutUint16(b[2:], 4+valueLen)
+		value := b[4:]
+		b = b[controller2PropLen:]
+		return value
+	}
+	binary.BigEndian.PutUint16(putProp(controller2PropMaxLen, 2), controllerMaxLen)
+	binary.BigEndian.PutUint16(putProp(controller2PropControllerID, 2), a.controllerID)
+	putProp(controller2PropReason, 1)[0] = a.reason
+	binary.BigEndian.PutUint32(putProp(controller2PropMeterID, 4), a.meterID)
+	return data, nil
+}
+
+func (a *nxActionController2) UnmarshalBinary(data []byte) error {
+	return errUnmarshalNotSupported
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openflow
+
+import (
+	"testing"
+
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeterModMarshal(t *testing.T) {
+	m := newMeterMod(meterCommandAdd, 10)
+	m.Flags = meterFlagPktps | meterFlagBurst
+	m.Rate = 100
+	m.BurstSize = 20
+	data, err := m.MarshalBinary()
+	require.NoError(t, err)
+	// The header is followed by the command, the flags, the meter ID and the
+	// drop band.
+	assert.Equal(t, []byte{openflow13.VERSION, openflow13.Type_MeterMod, 0, 32}, data[:4])
+	assert.Equal(t, []byte{
+		0, 0, 0, 6, 0, 0, 0, 10,
+		0, 1, 0, 16, 0, 0, 0, 100, 0, 0, 0, 20, 0, 0, 0, 0,
+	}, data[8:])
+
+	data, err = newMeterMod(meterCommandDelete, 10).MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{openflow13.VERSION, openflow13.Type_MeterMod, 0, 16}, data[:4])
+	assert.Equal(t, []byte{0, 2, 0, 0, 0, 0, 0, 10}, data[8:])
+}
+
+func TestMeteredFlowInstructions(t *testing.T) {
+	table := &ofTable{id: 0, next: 1, Table: &ofctrl.Table{TableId: 0}}
+	flow := table.BuildFlow(uint16(100)).MatchProtocol(ProtocolIP).
+		Action().LoadRegRange(1, 0x10, Range{0, 31}).
+		Action().Meter(5).
+		Action().GotoTable(1).
+		Done()
+	flowMod, err := flow.(*ofFlow).Flow.GenerateFlowModMessage(openflow13.FC_ADD)
+	require.NoError(t, err)
+	require.Len(t, flowMod.Instructions, 1)
+	data, err := flowMod.Instructions[0].MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, int(flowMod.Instructions[0].Len()), len(data))
+	// The meter instruction is followed by the apply-actions and goto-table
+	// instructions.
+	assert.Equal(t, []byte{0, openflow13.InstrType_METER, 0, 8, 0, 0, 0, 5}, data[:8])
+	assert.Equal(t, []byte{0, openflow13.InstrType_APPLY_ACTIONS}, data[8:10])
+	assert.Equal(t, []byte{0, openflow13.InstrType_GOTO_TABLE, 0, 8, 1, 0, 0, 0}, data[len(data)-8:])
+
+	// The copy of the Flow with its actions uses the same meter.
+	copiedFlow := flow.CopyToBuilder(0, true).Done()
+	copiedFlowMod, err := copiedFlow.(*ofFlow).Flow.GenerateFlowModMessage(openflow13.FC_ADD)
+	require.NoError(t, err)
+	copiedData, err := copiedFlowMod.Instructions[0].MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, copiedData)
+}
+
+func TestMeteredControllerMarshal(t *testing.T) {
+	table := &ofTable{id: 0, next: 1, Table: &ofctrl.Table{TableId: 0}}
+	flow := table.BuildFlow(uint16(100)).MatchProtocol(ProtocolIP).
+		Action().SendToControllerWithMeter(1, 5).
+		Action().GotoTable(1).
+		Done()
+	// The meter is only used by the controller action, not by the whole Flow.
+	assert.Equal(t, uint32(0), flow.(*ofFlow).meterID)
+	assert.Equal(t, uint32(5), flow.(*ofFlow).packetInMeterID())
+
+	data, err := (&meteredController{controllerID: 2, reason: 1, meterID: 5}).GetActionMessage().MarshalBinary()
+	require.NoError(t, err)
+	// The controller2 action header is followed by the max_len, controller_id,
+	// reason and meter_id properties.
+	assert.Equal(t, []byte{0xff, 0xff, 0, 48, 0, 0, 0x23, 0x20, 0, openflow13.NXAST_CONTROLLER2, 0, 0, 0, 0, 0, 0}, data[:16])
+	assert.Equal(t, []byte{
+		0, 0, 0, 6, 0, 128, 0, 0,
+		0, 1, 0, 6, 0, 2, 0, 0,
+		0, 2, 0, 5, 1, 0, 0, 0,
+		0, 5, 0, 8, 0, 0, 0, 5,
+	}, data[16:])
+}
@@ -64,6 +64,20 @@ func (mr *MockBridgeMockRecorder) AddFlowsInBundle(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFlowsInBundle", reflect.TypeOf((*MockBridge)(nil).AddFlowsInBundle), arg0, arg1, arg2)
 }
 
+// AddMeter mocks base method
+func (m *MockBridge) AddMeter(arg0, arg1, arg2 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMeter", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMeter indicates an expected call of AddMeter
+func (mr *MockBridgeMockRecorder) AddMeter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMeter", reflect.TypeOf((*MockBridge)(nil).AddMeter), arg0, arg1, arg2)
+}
+
 // AddOFEntriesInBundle mocks base method
 func (m *MockBridge) AddOFEntriesInBundle(arg0, arg1, arg2 []openflow.OFEntry) error {
 	m.ctrl.T.Helper()
@@ -176,6 +190,20 @@ func (mr *MockBridgeMockRecorder) DeleteGroup(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockBridge)(nil).DeleteGroup), arg0)
 }
 
+// DeleteMeter mocks base method
+func (m *MockBridge) DeleteMeter(arg0 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMeter", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMeter indicates an expected call of DeleteMeter
+func (mr *MockBridgeMockRecorder) DeleteMeter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMeter", reflect.TypeOf((*MockBridge)(nil).DeleteMeter), arg0)
+}
+
 // DeleteTable mocks base method
 func (m *MockBridge) DeleteTable(arg0 openflow.TableIDType) bool {
 	m.ctrl.T.Helper()
@@ -693,6 +721,20 @@ func (mr *MockActionMockRecorder) LoadRegRange(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadRegRange", reflect.TypeOf((*MockAction)(nil).LoadRegRange), arg0, arg1, arg2)
 }
 
+// Meter mocks base method
+func (m *MockAction) Meter(arg0 uint32) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Meter", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// Meter indicates an expected call of Meter
+func (mr *MockActionMockRecorder) Meter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Meter", reflect.TypeOf((*MockAction)(nil).Meter), arg0)
+}
+
 // Move mocks base method
 func (m *MockAction) Move(arg0, arg1 string) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -847,6 +889,20 @@ func (mr *MockActionMockRecorder) SendToController(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToController", reflect.TypeOf((*MockAction)(nil).SendToController), arg0)
 }
 
+// SendToControllerWithMeter mocks base method
+func (m *MockAction) SendToControllerWithMeter(arg0 byte, arg1 uint32) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendToControllerWithMeter", arg0, arg1)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// SendToControllerWithMeter indicates an expected call of SendToControllerWithMeter
+func (mr *MockActionMockRecorder) SendToControllerWithMeter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToControllerWithMeter", reflect.TypeOf((*MockAction)(nil).SendToControllerWithMeter), arg0, arg1)
+}
+
 // SetARPSha mocks base method
 func (m *MockAction) SetARPSha(arg0 net.HardwareAddr) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1523,6 +1579,20 @@ func (mr *MockFlowBuilderMockRecorder) MatchIPDscp(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchIPDscp", reflect.TypeOf((*MockFlowBuilder)(nil).MatchIPDscp), arg0)
 }
 
+// MatchIPProtocolValue mocks base method
+func (m *MockFlowBuilder) MatchIPProtocolValue(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchIPProtocolValue", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchIPProtocolValue indicates an expected call of MatchIPProtocolValue
+func (mr *MockFlowBuilderMockRecorder) MatchIPProtocolValue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchIPProtocolValue", reflect.TypeOf((*MockFlowBuilder)(nil).MatchIPProtocolValue), arg0)
+}
+
 // MatchInPort mocks base method
 func (m *MockFlowBuilder) MatchInPort(arg0 uint32) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1635,6 +1705,20 @@ func (mr *MockFlowBuilderMockRecorder) MatchSrcMAC(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchSrcMAC", reflect.TypeOf((*MockFlowBuilder)(nil).MatchSrcMAC), arg0)
 }
 
+// MatchSrcPort mocks base method
+func (m *MockFlowBuilder) MatchSrcPort(arg0 uint16, arg1 *uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchSrcPort", arg0, arg1)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchSrcPort indicates an expected call of MatchSrcPort
+func (mr *MockFlowBuilderMockRecorder) MatchSrcPort(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchSrcPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchSrcPort), arg0, arg1)
+}
+
 // MatchTunMetadata mocks base method
 func (m *MockFlowBuilder) MatchTunMetadata(arg0 int, arg1 uint32) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -0,0 +1,428 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsconfig
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+const fakeOVSVersion = "2.14.0"
+
+// fakeInterface is an interface of FakeOVSBridge.
+type fakeInterface struct {
+	name    string
+	ifType  string
+	ofPort  int32
+	options map[string]string
+	mac     net.HardwareAddr
+	mtu     int
+}
+
+// fakePort is a port of FakeOVSBridge. Like the ports created by OVSBridge, it
+// has a single interface.
+type fakePort struct {
+	uuid        string
+	name        string
+	externalIDs map[string]string
+	intf        *fakeInterface
+}
+
+// FakeOVSBridge is an in-memory implementation of OVSBridgeClient for unit
+// tests. The ports are created immediately with an ofport allocated like OVS
+// does, and the configurations are kept in memory, so that tests can verify
+// them without a running OVSDB server.
+type FakeOVSBridge struct {
+	sync.RWMutex
+	name                     string
+	created                  bool
+	datapathID               string
+	externalIDs              map[string]string
+	bridgeOtherConfig        map[string]string
+	ovsOtherConfig           map[string]string
+	isHardwareOffloadEnabled bool
+	// ports maps the port UUID to the port.
+	ports map[string]*fakePort
+	// nextUUID is used to generate the UUIDs of the ports.
+	nextUUID uint64
+}
+
+// NewFakeOVSBridge returns a FakeOVSBridge with the provided name, which must
+// be created with Create before ports can be added to it.
+func NewFakeOVSBridge(name string) *FakeOVSBridge {
+	return &FakeOVSBridge{
+		name:              name,
+		externalIDs:       map[string]string{},
+		bridgeOtherConfig: map[string]string{},
+		ovsOtherConfig:    map[string]string{},
+		ports:             map[string]*fakePort{},
+	}
+}
+
+func buildStringMap(m map[string]interface{}) map[string]string {
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		r[k] = fmt.Sprint(v)
+	}
+	return r
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	return r
+}
+
+func (br *FakeOVSBridge) Create() Error {
+	br.Lock()
+	defer br.Unlock()
+	br.created = true
+	return nil
+}
+
+func (br *FakeOVSBridge) Delete() Error {
+	br.Lock()
+	defer br.Unlock()
+	br.created = false
+	br.ports = map[string]*fakePort{}
+	return nil
+}
+
+func (br *FakeOVSBridge) GetExternalIDs() (map[string]string, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	return copyStringMap(br.externalIDs), nil
+}
+
+func (br *FakeOVSBridge) SetExternalIDs(externalIDs map[string]interface{}) Error {
+	br.Lock()
+	defer br.Unlock()
+	for k, v := range buildStringMap(externalIDs) {
+		br.externalIDs[k] = v
+	}
+	return nil
+}
+
+func (br *FakeOVSBridge) SetDatapathID(datapathID string) Error {
+	br.Lock()
+	defer br.Unlock()
+	br.datapathID = datapathID
+	return nil
+}
+
+// GetDatapathID returns the datapath ID set with SetDatapathID.
+func (br *FakeOVSBridge) GetDatapathID() string {
+	br.RLock()
+	defer br.RUnlock()
+	return br.datapathID
+}
+
+func (br *FakeOVSBridge) AddBridgeOtherConfig(configs map[string]interface{}) Error {
+	br.Lock()
+	defer br.Unlock()
+	for k, v := range buildStringMap(configs) {
+		br.bridgeOtherConfig[k] = v
+	}
+	return nil
+}
+
+func (br *FakeOVSBridge) GetBridgeOtherConfig() (map[string]string, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	return copyStringMap(br.bridgeOtherConfig), nil
+}
+
+// getInterface returns the interface with the provided name. The caller must
+// hold the lock.
+func (br *FakeOVSBridge) getInterface(name string) (*fakeInterface, Error) {
+	for _, port := range br.ports {
+		if port.intf.name == name {
+			return port.intf, nil
+		}
+	}
+	return nil, NewTransactionError(fmt.Errorf("interface %s not found", name), false)
+}
+
+func (br *FakeOVSBridge) GetInterfaceOptions(name string) (map[string]string, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	intf, err := br.getInterface(name)
+	if err != nil {
+		return nil, err
+	}
+	return copyStringMap(intf.options), nil
+}
+
+func (br *FakeOVSBridge) SetInterfaceOptions(name string, options map[string]interface{}) Error {
+	br.Lock()
+	defer br.Unlock()
+	intf, err := br.getInterface(name)
+	if err != nil {
+		return err
+	}
+	intf.options = buildStringMap(options)
+	return nil
+}
+
+func (br *FakeOVSBridge) CreatePort(name, ifDev string, externalIDs map[string]interface{}) (string, Error) {
+	return br.createPort(name, ifDev, "", 0, externalIDs, nil)
+}
+
+func (br *FakeOVSBridge) CreateInternalPort(name string, ofPortRequest int32, externalIDs map[string]interface{}) (string, Error) {
+	return br.createPort(name, name, "internal", ofPortRequest, externalIDs, nil)
+}
+
+func (br *FakeOVSBridge) CreateTunnelPort(name string, tunnelType TunnelType, ofPortRequest int32) (string, Error) {
+	return br.CreateTunnelPortExt(name, tunnelType, ofPortRequest, false, "", "", "", nil)
+}
+
+func (br *FakeOVSBridge) CreateTunnelPortExt(
+	name string,
+	tunnelType TunnelType,
+	ofPortRequest int32,
+	csum bool,
+	localIP string,
+	remoteIP string,
+	psk string,
+	externalIDs map[string]interface{}) (string, Error) {
+	if psk != "" && remoteIP == "" {
+		return "", newInvalidArgumentsError("IPSec tunnel can not be flow based. remoteIP must be set")
+	}
+	if tunnelType != VXLANTunnel && tunnelType != GeneveTunnel && tunnelType != GRETunnel && tunnelType != STTTunnel {
+		return "", newInvalidArgumentsError("unsupported tunnel type: " + string(tunnelType))
+	}
+	options := map[string]interface{}{}
+	if remoteIP != "" {
+		options["remote_ip"] = remoteIP
+	} else {
+		// Flow based tunnel.
+		options["key"] = "flow"
+		options["remote_ip"] = "flow"
+	}
+	if localIP != "" {
+		options["local_ip"] = localIP
+	}
+	if psk != "" {
+		options["psk"] = psk
+	}
+	if csum {
+		options["csum"] = "true"
+	}
+	return br.createPort(name, name, string(tunnelType), ofPortRequest, externalIDs, options)
+}
+
+func (br *FakeOVSBridge) CreateUplinkPort(name string, ofPortRequest int32, externalIDs map[string]interface{}) (string, Error) {
+	return br.createPort(name, name, "", ofPortRequest, externalIDs, nil)
+}
+
+func (br *FakeOVSBridge) createPort(name, ifName, ifType string, ofPortRequest int32, externalIDs, options map[string]interface{}) (string, Error) {
+	if ofPortRequest < 0 || ofPortRequest > ofPortRequestMax {
+		return "", newInvalidArgumentsError(fmt.Sprint("invalid ofPortRequest value: ", ofPortRequest))
+	}
+	br.Lock()
+	defer br.Unlock()
+	if !br.created {
+		return "", NewTransactionError(fmt.Errorf("bridge %s not found", br.name), false)
+	}
+	usedOFPorts := map[int32]bool{}
+	for _, port := range br.ports {
+		if port.name == name {
+			return "", NewTransactionError(fmt.Errorf("port %s already exists", name), false)
+		}
+		usedOFPorts[port.intf.ofPort] = true
+	}
+	// Like OVS, the requested ofport is used if it is available, otherwise the
+	// lowest available ofport is allocated.
+	ofPort := ofPortRequest
+	if ofPort == 0 || usedOFPorts[ofPort] {
+		for ofPort = 1; usedOFPorts[ofPort]; ofPort++ {
+		}
+	}
+	br.nextUUID++
+	port := &fakePort{
+		uuid:        fmt.Sprintf("00000000-0000-0000-0000-%012x", br.nextUUID),
+		name:        name,
+		externalIDs: buildStringMap(externalIDs),
+		intf: &fakeInterface{
+			name:    ifName,
+			ifType:  ifType,
+			ofPort:  ofPort,
+			options: buildStringMap(options),
+		},
+	}
+	br.ports[port.uuid] = port
+	return port.uuid, nil
+}
+
+func (br *FakeOVSBridge) DeletePort(portUUID string) Error {
+	br.Lock()
+	defer br.Unlock()
+	delete(br.ports, portUUID)
+	return nil
+}
+
+func (br *FakeOVSBridge) DeletePorts(portUUIDList []string) Error {
+	br.Lock()
+	defer br.Unlock()
+	for _, uuid := range portUUIDList {
+		delete(br.ports, uuid)
+	}
+	return nil
+}
+
+func (br *FakeOVSBridge) GetOFPort(ifName string) (int32, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	intf, err := br.getInterface(ifName)
+	if err != nil {
+		return 0, err
+	}
+	return intf.ofPort, nil
+}
+
+func (p *fakePort) portData() OVSPortData {
+	return OVSPortData{
+		UUID:        p.uuid,
+		Name:        p.name,
+		IFType:      p.intf.ifType,
+		IFName:      p.intf.name,
+		OFPort:      p.intf.ofPort,
+		ExternalIDs: copyStringMap(p.externalIDs),
+		Options:     copyStringMap(p.intf.options),
+	}
+}
+
+func (br *FakeOVSBridge) GetPortData(portUUID, ifName string) (*OVSPortData, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	port, exists := br.ports[portUUID]
+	if !exists {
+		return nil, NewTransactionError(fmt.Errorf("port %s not found", portUUID), false)
+	}
+	if port.intf.name != ifName {
+		return nil, NewTransactionError(fmt.Errorf("interface %s not attached to port %s", ifName, portUUID), false)
+	}
+	portData := port.portData()
+	return &portData, nil
+}
+
+// GetPortList returns all ports on the bridge, ordered by ofport.
+func (br *FakeOVSBridge) GetPortList() ([]OVSPortData, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	portList := make([]OVSPortData, 0, len(br.ports))
+	for _, port := range br.ports {
+		portList = append(portList, port.portData())
+	}
+	sort.Slice(portList, func(i, j int) bool { return portList[i].OFPort < portList[j].OFPort })
+	return portList, nil
+}
+
+func (br *FakeOVSBridge) SetInterfaceMTU(name string, MTU int) error {
+	br.Lock()
+	defer br.Unlock()
+	intf, err := br.getInterface(name)
+	if err != nil {
+		return err
+	}
+	intf.mtu = MTU
+	return nil
+}
+
+// GetInterfaceMTU returns the MTU set with SetInterfaceMTU, or 0 if it was not
+// set.
+func (br *FakeOVSBridge) GetInterfaceMTU(name string) (int, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	intf, err := br.getInterface(name)
+	if err != nil {
+		return 0, err
+	}
+	return intf.mtu, nil
+}
+
+func (br *FakeOVSBridge) SetInterfaceMAC(name string, mac net.HardwareAddr) Error {
+	br.Lock()
+	defer br.Unlock()
+	intf, err := br.getInterface(name)
+	if err != nil {
+		return err
+	}
+	intf.mac = mac
+	return nil
+}
+
+func (br *FakeOVSBridge) GetInterfaceMAC(name string) (net.HardwareAddr, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	intf, err := br.getInterface(name)
+	if err != nil {
+		return nil, err
+	}
+	return intf.mac, nil
+}
+
+func (br *FakeOVSBridge) GetOVSVersion() (string, Error) {
+	return fakeOVSVersion, nil
+}
+
+func (br *FakeOVSBridge) AddOVSOtherConfig(configs map[string]interface{}) Error {
+	br.Lock()
+	defer br.Unlock()
+	for k, v := range buildStringMap(configs) {
+		br.ovsOtherConfig[k] = v
+	}
+	return nil
+}
+
+func (br *FakeOVSBridge) GetOVSOtherConfig() (map[string]string, Error) {
+	br.RLock()
+	defer br.RUnlock()
+	return copyStringMap(br.ovsOtherConfig), nil
+}
+
+func (br *FakeOVSBridge) DeleteOVSOtherConfig(configs map[string]interface{}) Error {
+	br.Lock()
+	defer br.Unlock()
+	for k, v := range buildStringMap(configs) {
+		// Like the OVSDB map "delete" mutation, the key is only deleted if
+		// its value matches.
+		if br.ovsOtherConfig[k] == v {
+			delete(br.ovsOtherConfig, k)
+		}
+	}
+	return nil
+}
+
+func (br *FakeOVSBridge) GetBridgeName() string {
+	return br.name
+}
+
+func (br *FakeOVSBridge) IsHardwareOffloadEnabled() bool {
+	br.RLock()
+	defer br.RUnlock()
+	return br.isHardwareOffloadEnabled
+}
+
+// SetHardwareOffloadEnabled sets the value returned by IsHardwareOffloadEnabled.
+func (br *FakeOVSBridge) SetHardwareOffloadEnabled(enabled bool) {
+	br.Lock()
+	defer br.Unlock()
+	br.isHardwareOffloadEnabled = enabled
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ovsconfig
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ OVSBridgeClient = &FakeOVSBridge{}
+
+func TestFakeOVSBridgePorts(t *testing.T) {
+	br := NewFakeOVSBridge("br-int")
+	_, err := br.CreateInternalPort("gw0", 2, nil)
+	assert.Error(t, err, "Ports cannot be created before the bridge")
+	require.NoError(t, br.Create())
+
+	gwUUID, err := br.CreateInternalPort("gw0", 2, map[string]interface{}{"antrea-type": "gateway"})
+	require.NoError(t, err)
+	tunUUID, err := br.CreateTunnelPortExt("tun0", GeneveTunnel, 0, true, "", "", "", nil)
+	require.NoError(t, err)
+	podUUID, err := br.CreatePort("pod1-eth0", "pod1-eth0", map[string]interface{}{"ip": "10.0.0.1"})
+	require.NoError(t, err)
+	_, err = br.CreatePort("pod1-eth0", "pod1-eth0", nil)
+	assert.Error(t, err, "Port names must be unique")
+	_, err = br.CreateTunnelPortExt("tun1", GeneveTunnel, 0, false, "", "", "psk", nil)
+	assert.Error(t, err, "IPSec tunnels cannot be flow based")
+
+	// The requested ofport is used when available, otherwise the lowest
+	// available ofport is allocated.
+	ofPort, err := br.GetOFPort("gw0")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), ofPort)
+	ofPort, err = br.GetOFPort("tun0")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), ofPort)
+	ofPort, err = br.GetOFPort("pod1-eth0")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), ofPort)
+
+	portData, err := br.GetPortData(tunUUID, "tun0")
+	require.NoError(t, err)
+	assert.Equal(t, &OVSPortData{
+		UUID:        tunUUID,
+		Name:        "tun0",
+		IFType:      "geneve",
+		IFName:      "tun0",
+		OFPort:      1,
+		ExternalIDs: map[string]string{},
+		Options:     map[string]string{"key": "flow", "remote_ip": "flow", "csum": "true"},
+	}, portData)
+	_, err = br.GetPortData(gwUUID, "tun0")
+	assert.Error(t, err)
+
+	require.NoError(t, br.DeletePorts([]string{gwUUID, podUUID}))
+	ports, err := br.GetPortList()
+	require.NoError(t, err)
+	require.Len(t, ports, 1)
+	assert.Equal(t, tunUUID, ports[0].UUID)
+	_, err = br.GetOFPort("gw0")
+	assert.Error(t, err)
+}
+
+func TestFakeOVSBridgeConfigs(t *testing.T) {
+	br := NewFakeOVSBridge("br-int")
+	require.NoError(t, br.Create())
+	require.NoError(t, br.SetDatapathID("0000aabbccddeeff"))
+	assert.Equal(t, "0000aabbccddeeff", br.GetDatapathID())
+	require.NoError(t, br.SetExternalIDs(map[string]interface{}{"roundNum": 2}))
+	externalIDs, _ := br.GetExternalIDs()
+	assert.Equal(t, map[string]string{"roundNum": "2"}, externalIDs)
+	require.NoError(t, br.AddBridgeOtherConfig(map[string]interface{}{"flow-restore-wait": "true"}))
+	otherConfig, _ := br.GetBridgeOtherConfig()
+	assert.Equal(t, map[string]string{"flow-restore-wait": "true"}, otherConfig)
+
+	require.NoError(t, br.AddOVSOtherConfig(map[string]interface{}{"hw-offload": "true"}))
+	require.NoError(t, br.DeleteOVSOtherConfig(map[string]interface{}{"hw-offload": "false"}))
+	otherConfig, _ = br.GetOVSOtherConfig()
+	assert.Equal(t, map[string]string{"hw-offload": "true"}, otherConfig)
+	require.NoError(t, br.DeleteOVSOtherConfig(map[string]interface{}{"hw-offload": "true"}))
+	otherConfig, _ = br.GetOVSOtherConfig()
+	assert.Empty(t, otherConfig)
+
+	_, err := br.CreateInternalPort("gw0", 0, nil)
+	require.NoError(t, err)
+	mac, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	require.NoError(t, br.SetInterfaceMAC("gw0", mac))
+	gotMAC, _ := br.GetInterfaceMAC("gw0")
+	assert.Equal(t, mac, gotMAC)
+	require.NoError(t, br.SetInterfaceMTU("gw0", 1450))
+	mtu, _ := br.GetInterfaceMTU("gw0")
+	assert.Equal(t, 1450, mtu)
+	require.NoError(t, br.SetInterfaceOptions("gw0", map[string]interface{}{"dst_port": 6081}))
+	options, _ := br.GetInterfaceOptions("gw0")
+	assert.Equal(t, map[string]string{"dst_port": "6081"}, options)
+	assert.Error(t, br.SetInterfaceMAC("gw1", mac))
+}
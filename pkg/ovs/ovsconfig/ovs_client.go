@@ -124,6 +124,12 @@ func (br *OVSBridge) Create() Error {
 	return nil
 }
 
+// Exists returns whether the bridge with name bridgeName exists. The bridge
+// can be deleted with Delete if it does.
+func (br *OVSBridge) Exists() (bool, Error) {
+	return br.lookupByName()
+}
+
 func (br *OVSBridge) lookupByName() (bool, Error) {
 	tx := br.ovsdb.Transaction(openvSwitchSchema)
 	tx.Select(dbtransaction.Select{
@@ -298,6 +304,45 @@ func (br *OVSBridge) GetDatapathID() (string, Error) {
 	}
 }
 
+// AddBridgeOtherConfig adds the given configs to the "other_config" column of the bridge.
+// Configs whose key already exists are not overwritten.
+func (br *OVSBridge) AddBridgeOtherConfig(configs map[string]interface{}) Error {
+	tx := br.ovsdb.Transaction(openvSwitchSchema)
+
+	mutateSet := helpers.MakeOVSDBMap(configs)
+	tx.Mutate(dbtransaction.Mutate{
+		Table:     "Bridge",
+		Mutations: [][]interface{}{{"other_config", "insert", mutateSet}},
+		Where:     [][]interface{}{{"name", "==", br.name}},
+	})
+
+	_, err, temporary := tx.Commit()
+	if err != nil {
+		klog.Error("Transaction failed: ", err)
+		return NewTransactionError(err, temporary)
+	}
+	return nil
+}
+
+// GetBridgeOtherConfig returns the "other_config" column of the bridge.
+func (br *OVSBridge) GetBridgeOtherConfig() (map[string]string, Error) {
+	tx := br.ovsdb.Transaction(openvSwitchSchema)
+	tx.Select(dbtransaction.Select{
+		Table:   "Bridge",
+		Columns: []string{"other_config"},
+		Where:   [][]interface{}{{"name", "==", br.name}},
+	})
+
+	res, err, temporary := tx.Commit()
+	if err != nil {
+		klog.Error("Transaction failed: ", err)
+		return nil, NewTransactionError(err, temporary)
+	}
+
+	otherConfigs := res[0].Rows[0].(map[string]interface{})["other_config"].([]interface{})
+	return buildMapFromOVSDBMap(otherConfigs), nil
+}
+
 // GetPortUUIDList returns UUIDs of all ports on the bridge.
 func (br *OVSBridge) GetPortUUIDList() ([]string, Error) {
 	tx := br.ovsdb.Transaction(openvSwitchSchema)
@@ -769,6 +814,58 @@ func (br *OVSBridge) SetInterfaceMTU(name string, MTU int) error {
 	return nil
 }
 
+// SetInterfaceMAC sets the "mac" column of the provided interface. It is only
+// honored by OVS for internal interfaces: OVS applies the MAC address to the
+// interface every time it is (re)created, e.g. after ovs-vswitchd restarts.
+func (br *OVSBridge) SetInterfaceMAC(name string, mac net.HardwareAddr) Error {
+	tx := br.ovsdb.Transaction(openvSwitchSchema)
+
+	tx.Update(dbtransaction.Update{
+		Table: "Interface",
+		Where: [][]interface{}{{"name", "==", name}},
+		Row: map[string]interface{}{
+			"mac": mac.String(),
+		},
+	})
+
+	_, err, temporary := tx.Commit()
+	if err != nil {
+		klog.Error("Transaction failed: ", err)
+		return NewTransactionError(err, temporary)
+	}
+	return nil
+}
+
+// GetInterfaceMAC returns the MAC address stored in the "mac" column of the
+// provided interface, or nil if it is not set.
+func (br *OVSBridge) GetInterfaceMAC(name string) (net.HardwareAddr, Error) {
+	tx := br.ovsdb.Transaction(openvSwitchSchema)
+	tx.Select(dbtransaction.Select{
+		Table:   "Interface",
+		Columns: []string{"mac"},
+		Where:   [][]interface{}{{"name", "==", name}},
+	})
+
+	res, err, temporary := tx.Commit()
+	if err != nil {
+		klog.Error("Transaction failed: ", err)
+		return nil, NewTransactionError(err, temporary)
+	}
+	if len(res[0].Rows) == 0 {
+		return nil, newInvalidArgumentsError(fmt.Sprintf("interface %s not found", name))
+	}
+	// An empty "mac" column is returned as an empty OVSDB set instead of a string.
+	macStr, ok := res[0].Rows[0].(map[string]interface{})["mac"].(string)
+	if !ok {
+		return nil, nil
+	}
+	mac, parseErr := net.ParseMAC(macStr)
+	if parseErr != nil {
+		return nil, newInvalidArgumentsError(fmt.Sprintf("invalid MAC address %s of interface %s", macStr, name))
+	}
+	return mac, nil
+}
+
 func (br *OVSBridge) GetOVSVersion() (string, Error) {
 	tx := br.ovsdb.Transaction(openvSwitchSchema)
 
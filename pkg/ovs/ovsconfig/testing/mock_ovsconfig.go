@@ -22,6 +22,7 @@ package testing
 import (
 	gomock "github.com/golang/mock/gomock"
 	ovsconfig "github.com/vmware-tanzu/antrea/pkg/ovs/ovsconfig"
+	net "net"
 	reflect "reflect"
 )
 
@@ -48,6 +49,20 @@ func (m *MockOVSBridgeClient) EXPECT() *MockOVSBridgeClientMockRecorder {
 	return m.recorder
 }
 
+// AddBridgeOtherConfig mocks base method
+func (m *MockOVSBridgeClient) AddBridgeOtherConfig(arg0 map[string]interface{}) ovsconfig.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBridgeOtherConfig", arg0)
+	ret0, _ := ret[0].(ovsconfig.Error)
+	return ret0
+}
+
+// AddBridgeOtherConfig indicates an expected call of AddBridgeOtherConfig
+func (mr *MockOVSBridgeClientMockRecorder) AddBridgeOtherConfig(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBridgeOtherConfig", reflect.TypeOf((*MockOVSBridgeClient)(nil).AddBridgeOtherConfig), arg0)
+}
+
 // AddOVSOtherConfig mocks base method
 func (m *MockOVSBridgeClient) AddOVSOtherConfig(arg0 map[string]interface{}) ovsconfig.Error {
 	m.ctrl.T.Helper()
@@ -221,6 +236,21 @@ func (mr *MockOVSBridgeClientMockRecorder) GetBridgeName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBridgeName", reflect.TypeOf((*MockOVSBridgeClient)(nil).GetBridgeName))
 }
 
+// GetBridgeOtherConfig mocks base method
+func (m *MockOVSBridgeClient) GetBridgeOtherConfig() (map[string]string, ovsconfig.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBridgeOtherConfig")
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(ovsconfig.Error)
+	return ret0, ret1
+}
+
+// GetBridgeOtherConfig indicates an expected call of GetBridgeOtherConfig
+func (mr *MockOVSBridgeClientMockRecorder) GetBridgeOtherConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBridgeOtherConfig", reflect.TypeOf((*MockOVSBridgeClient)(nil).GetBridgeOtherConfig))
+}
+
 // GetExternalIDs mocks base method
 func (m *MockOVSBridgeClient) GetExternalIDs() (map[string]string, ovsconfig.Error) {
 	m.ctrl.T.Helper()
@@ -236,6 +266,21 @@ func (mr *MockOVSBridgeClientMockRecorder) GetExternalIDs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalIDs", reflect.TypeOf((*MockOVSBridgeClient)(nil).GetExternalIDs))
 }
 
+// GetInterfaceMAC mocks base method
+func (m *MockOVSBridgeClient) GetInterfaceMAC(arg0 string) (net.HardwareAddr, ovsconfig.Error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterfaceMAC", arg0)
+	ret0, _ := ret[0].(net.HardwareAddr)
+	ret1, _ := ret[1].(ovsconfig.Error)
+	return ret0, ret1
+}
+
+// GetInterfaceMAC indicates an expected call of GetInterfaceMAC
+func (mr *MockOVSBridgeClientMockRecorder) GetInterfaceMAC(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterfaceMAC", reflect.TypeOf((*MockOVSBridgeClient)(nil).GetInterfaceMAC), arg0)
+}
+
 // GetInterfaceOptions mocks base method
 func (m *MockOVSBridgeClient) GetInterfaceOptions(arg0 string) (map[string]string, ovsconfig.Error) {
 	m.ctrl.T.Helper()
@@ -368,6 +413,20 @@ func (mr *MockOVSBridgeClientMockRecorder) SetExternalIDs(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExternalIDs", reflect.TypeOf((*MockOVSBridgeClient)(nil).SetExternalIDs), arg0)
 }
 
+// SetInterfaceMAC mocks base method
+func (m *MockOVSBridgeClient) SetInterfaceMAC(arg0 string, arg1 net.HardwareAddr) ovsconfig.Error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInterfaceMAC", arg0, arg1)
+	ret0, _ := ret[0].(ovsconfig.Error)
+	return ret0
+}
+
+// SetInterfaceMAC indicates an expected call of SetInterfaceMAC
+func (mr *MockOVSBridgeClientMockRecorder) SetInterfaceMAC(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInterfaceMAC", reflect.TypeOf((*MockOVSBridgeClient)(nil).SetInterfaceMAC), arg0, arg1)
+}
+
 // SetInterfaceMTU mocks base method
 func (m *MockOVSBridgeClient) SetInterfaceMTU(arg0 string, arg1 int) error {
 	m.ctrl.T.Helper()
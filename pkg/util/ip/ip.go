@@ -16,11 +16,13 @@ package ip
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"sort"
 
 	"github.com/vmware-tanzu/antrea/pkg/apis/controlplane/v1beta1"
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
 )
 
 const (
@@ -129,6 +131,60 @@ func mergeCIDRs(cidrBlocks []*net.IPNet) []*net.IPNet {
 	return cidrBlocks
 }
 
+// AggregateCIDRs returns the smallest list of CIDRs covering the same addresses
+// as the provided CIDRs: the CIDRs covered by other CIDRs are removed and the
+// sibling CIDRs are merged into their parent CIDR, recursively. The returned
+// CIDRs are sorted by IP. The input array is not modified.
+func AggregateCIDRs(cidrBlocks []*net.IPNet) []*net.IPNet {
+	cidrs := make([]*net.IPNet, 0, len(cidrBlocks))
+	for _, c := range cidrBlocks {
+		ip := c.IP.Mask(c.Mask)
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		ones, bits := c.Mask.Size()
+		cidrs = append(cidrs, &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, bits)})
+	}
+	for {
+		cidrs = mergeCIDRs(cidrs)
+		// After the redundant CIDRs are removed, a parent CIDR has at most
+		// two children in the list, which can be merged if both are present.
+		children := map[string][]*net.IPNet{}
+		for _, c := range cidrs {
+			ones, bits := c.Mask.Size()
+			if ones == 0 {
+				continue
+			}
+			parentMask := net.CIDRMask(ones-1, bits)
+			parent := net.IPNet{IP: c.IP.Mask(parentMask), Mask: parentMask}
+			children[parent.String()] = append(children[parent.String()], c)
+		}
+		merged := false
+		for _, siblings := range children {
+			if len(siblings) != 2 {
+				continue
+			}
+			ones, bits := siblings[0].Mask.Size()
+			parentMask := net.CIDRMask(ones-1, bits)
+			// Replace the first sibling with the parent CIDR, which covers the
+			// second one, removed by mergeCIDRs in the next iteration.
+			siblings[0].IP = siblings[0].IP.Mask(parentMask)
+			siblings[0].Mask = parentMask
+			merged = true
+		}
+		if !merged {
+			break
+		}
+	}
+	sort.Slice(cidrs, func(i, j int) bool {
+		if c := bytes.Compare(cidrs[i].IP, cidrs[j].IP); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(cidrs[i].Mask, cidrs[j].Mask) < 0
+	})
+	return cidrs
+}
+
 // Function to transform Antrea IPNet to net.IPNet
 func IPNetToNetIPNet(ipNet *v1beta1.IPNet) *net.IPNet {
 	ip := net.IP(ipNet.IP)
@@ -146,3 +202,46 @@ func NetIPNetToIPNet(ipNet *net.IPNet) *v1beta1.IPNet {
 	prefix, _ := ipNet.Mask.Size()
 	return &v1beta1.IPNet{IP: v1beta1.IPAddress(ipNet.IP), PrefixLength: int32(prefix)}
 }
+
+// ParseIPRange returns the first and the last IPv4 addresses of the range. The
+// network and broadcast addresses of a CIDR are excluded, unless its prefix is
+// longer than 30.
+func ParseIPRange(ipRange corev1alpha1.IPRange) (uint32, uint32, error) {
+	if ipRange.CIDR != "" {
+		_, ipNet, err := net.ParseCIDR(ipRange.CIDR)
+		if err != nil {
+			return 0, 0, err
+		}
+		if ipNet.IP.To4() == nil {
+			return 0, 0, fmt.Errorf("CIDR %s is not an IPv4 CIDR", ipRange.CIDR)
+		}
+		ones, bits := ipNet.Mask.Size()
+		first := IPToUint32(ipNet.IP)
+		last := first | (1<<uint(bits-ones) - 1)
+		if bits-ones > 1 {
+			first, last = first+1, last-1
+		}
+		return first, last, nil
+	}
+	start, end := net.ParseIP(ipRange.Start), net.ParseIP(ipRange.End)
+	if start == nil || start.To4() == nil || end == nil || end.To4() == nil {
+		return 0, 0, fmt.Errorf("range %s-%s is not an IPv4 range", ipRange.Start, ipRange.End)
+	}
+	first, last := IPToUint32(start), IPToUint32(end)
+	if first > last {
+		return 0, 0, fmt.Errorf("start %s of the range is greater than the end %s", ipRange.Start, ipRange.End)
+	}
+	return first, last, nil
+}
+
+// IPToUint32 converts an IPv4 address to an integer.
+func IPToUint32(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+// Uint32ToIP converts an integer to an IPv4 address.
+func Uint32ToIP(i uint32) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, i)
+	return ip
+}
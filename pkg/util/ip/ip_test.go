@@ -19,6 +19,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/core/v1alpha1"
 )
 
 func newCIDR(cidrStr string) *net.IPNet {
@@ -108,3 +111,94 @@ func TestMergeCIDRs(t *testing.T) {
 	ipNetList4 = mergeCIDRs(ipNetList4)
 	assert.ElementsMatch(t, correctList4, ipNetList4)
 }
+
+func TestAggregateCIDRs(t *testing.T) {
+	tests := []struct {
+		name     string
+		cidrs    []*net.IPNet
+		expected []*net.IPNet
+	}{
+		{
+			name:     "empty",
+			cidrs:    []*net.IPNet{},
+			expected: []*net.IPNet{},
+		},
+		{
+			name:     "siblings",
+			cidrs:    []*net.IPNet{newCIDR("10.10.1.0/24"), newCIDR("10.10.0.0/24")},
+			expected: []*net.IPNet{newCIDR("10.10.0.0/23")},
+		},
+		{
+			name: "recursive siblings",
+			cidrs: []*net.IPNet{newCIDR("10.10.0.0/32"), newCIDR("10.10.0.1/32"),
+				newCIDR("10.10.0.2/32"), newCIDR("10.10.0.3/32")},
+			expected: []*net.IPNet{newCIDR("10.10.0.0/30")},
+		},
+		{
+			name:     "covered and repeated",
+			cidrs:    []*net.IPNet{newCIDR("10.20.0.0/16"), newCIDR("10.20.1.2/32"), newCIDR("10.20.0.0/16"), newCIDR("10.10.0.0/16")},
+			expected: []*net.IPNet{newCIDR("10.10.0.0/16"), newCIDR("10.20.0.0/16")},
+		},
+		{
+			name:     "not siblings",
+			cidrs:    []*net.IPNet{newCIDR("10.10.1.0/24"), newCIDR("10.10.2.0/24")},
+			expected: []*net.IPNet{newCIDR("10.10.1.0/24"), newCIDR("10.10.2.0/24")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AggregateCIDRs(tt.cidrs))
+		})
+	}
+}
+
+func TestParseIPRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		ipRange       corev1alpha1.IPRange
+		expectedFirst string
+		expectedLast  string
+		expectedErr   bool
+	}{
+		{
+			name:          "cidr",
+			ipRange:       corev1alpha1.IPRange{CIDR: "10.10.10.0/24"},
+			expectedFirst: "10.10.10.1",
+			expectedLast:  "10.10.10.254",
+		},
+		{
+			name:          "cidr-32",
+			ipRange:       corev1alpha1.IPRange{CIDR: "10.10.10.5/32"},
+			expectedFirst: "10.10.10.5",
+			expectedLast:  "10.10.10.5",
+		},
+		{
+			name:          "start-end",
+			ipRange:       corev1alpha1.IPRange{Start: "10.10.10.10", End: "10.10.11.20"},
+			expectedFirst: "10.10.10.10",
+			expectedLast:  "10.10.11.20",
+		},
+		{
+			name:        "reversed",
+			ipRange:     corev1alpha1.IPRange{Start: "10.10.10.10", End: "10.10.10.1"},
+			expectedErr: true,
+		},
+		{
+			name:        "ipv6",
+			ipRange:     corev1alpha1.IPRange{CIDR: "fd00::/120"},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, err := ParseIPRange(tt.ipRange)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFirst, Uint32ToIP(first).String())
+			assert.Equal(t, tt.expectedLast, Uint32ToIP(last).String())
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	secv1alpha1 "github.com/vmware-tanzu/antrea/pkg/apis/security/v1alpha1"
+)
+
+const (
+	ipProtocolGRE  = 47
+	ipProtocolVRRP = 112
+	// vrrpAdvertisement is a VRRPv2 advertisement for virtual router 1 with
+	// priority 100, advertising 10.0.0.1.
+	vrrpAdvertisement = "21016401000100000a0000010000000000000000"
+)
+
+// TestAntreaPolicyIPProtocol verifies that Antrea-native policy rules can match
+// the traffic of an IP protocol by its number, using VRRP traffic. VRRP
+// advertisements are sent from the Node to two Pods. The policy of the first
+// Pod allows VRRP, while the policy of the second Pod only allows GRE, so that
+// the VRRP advertisements sent to it are dropped. The advertisements allowed
+// by a policy rule are committed to the conntrack zone of OVS, which is used to
+// tell which ones were allowed.
+func TestAntreaPolicyIPProtocol(t *testing.T) {
+	data, err := setupTest(t)
+	if err != nil {
+		t.Fatalf("Error when setting up test: %v", err)
+	}
+	defer teardownTest(t, data)
+	skipIfAntreaPolicyDisabled(t, data)
+
+	nodeName := masterNodeName()
+	allowedPod, droppedPod := randName("vrrp-allowed-"), randName("vrrp-dropped-")
+	vrrpPodIPs := map[string]string{}
+	for _, podName := range []string{allowedPod, droppedPod} {
+		if err := data.createBusyboxPodOnNode(podName, nodeName); err != nil {
+			t.Fatalf("Error when creating Pod '%s': %v", podName, err)
+		}
+		defer deletePodWrapper(t, data, podName)
+		podIP, err := data.podWaitForIP(defaultTimeout, podName, testNamespace)
+		if err != nil {
+			t.Fatalf("Error when waiting for IP of Pod '%s': %v", podName, err)
+		}
+		vrrpPodIPs[podName] = podIP
+	}
+
+	for podName, ipProtocol := range map[string]int32{allowedPod: ipProtocolVRRP, droppedPod: ipProtocolGRE} {
+		anp := newIPProtocolANP(podName, ipProtocol)
+		if _, err := data.securityClient.NetworkPolicies(testNamespace).Create(context.TODO(), anp, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Error when creating Antrea NetworkPolicy '%s': %v", anp.Name, err)
+		}
+		defer func() {
+			if err := data.securityClient.NetworkPolicies(testNamespace).Delete(context.TODO(), anp.Name, metav1.DeleteOptions{}); err != nil {
+				t.Errorf("Error when deleting Antrea NetworkPolicy '%s': %v", anp.Name, err)
+			}
+		}()
+		if err := wait.PollImmediate(time.Second, defaultTimeout, func() (bool, error) {
+			anp, err := data.securityClient.NetworkPolicies(testNamespace).Get(context.TODO(), anp.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return anp.Status.Phase == secv1alpha1.NetworkPolicyRealized, nil
+		}); err != nil {
+			t.Fatalf("Error when waiting for Antrea NetworkPolicy '%s' to be realized: %v", anp.Name, err)
+		}
+	}
+
+	antreaPodName, err := data.getAntreaPodOnNode(nodeName)
+	if err != nil {
+		t.Fatalf("Error when retrieving the name of the Antrea Pod running on Node '%s': %v", nodeName, err)
+	}
+	sendVRRP := func(podIP string) {
+		script := fmt.Sprintf(`import socket
+s = socket.socket(socket.AF_INET, socket.SOCK_RAW, %d)
+for _ in range(3):
+    s.sendto(bytes.fromhex("%s"), ("%s", 0))`, ipProtocolVRRP, vrrpAdvertisement, podIP)
+		cmd := []string{"python3", "-c", script}
+		if _, stderr, err := data.runCommandFromPod(antreaNamespace, antreaPodName, agentContainerName, cmd); err != nil {
+			t.Fatalf("Error when sending VRRP advertisements to '%s': %v, stderr: %s", podIP, err, stderr)
+		}
+	}
+	vrrpAllowed := func(podIP string) bool {
+		cmd := []string{"ovs-appctl", "dpctl/dump-conntrack", "zone=65520"}
+		stdout, stderr, err := data.runCommandFromPod(antreaNamespace, antreaPodName, ovsContainerName, cmd)
+		if err != nil {
+			t.Fatalf("Error when dumping conntrack entries: %v, stderr: %s", err, stderr)
+		}
+		for _, entry := range strings.Split(stdout, "\n") {
+			if strings.HasPrefix(entry, fmt.Sprintf("%d,", ipProtocolVRRP)) && strings.Contains(entry, fmt.Sprintf("dst=%s)", podIP)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The policies are realized, but the OpenFlow flows may still be being
+	// installed, so the allowed advertisements are polled.
+	if err := wait.PollImmediate(time.Second, 10*time.Second, func() (bool, error) {
+		sendVRRP(vrrpPodIPs[allowedPod])
+		return vrrpAllowed(vrrpPodIPs[allowedPod]), nil
+	}); err != nil {
+		t.Errorf("VRRP advertisements to Pod '%s' were not allowed", allowedPod)
+	}
+	sendVRRP(vrrpPodIPs[droppedPod])
+	if vrrpAllowed(vrrpPodIPs[droppedPod]) {
+		t.Errorf("VRRP advertisements to Pod '%s' were not dropped", droppedPod)
+	}
+}
+
+// newIPProtocolANP returns an Antrea NetworkPolicy applied to the Pod, which
+// allows the ingress traffic of the IP protocol and drops all the other
+// ingress traffic.
+func newIPProtocolANP(podName string, ipProtocol int32) *secv1alpha1.NetworkPolicy {
+	allowAction, dropAction := secv1alpha1.RuleActionAllow, secv1alpha1.RuleActionDrop
+	return &secv1alpha1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: fmt.Sprintf("anp-%s", podName)},
+		Spec: secv1alpha1.NetworkPolicySpec{
+			Priority: 5,
+			AppliedTo: []secv1alpha1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"antrea-e2e": podName}},
+			}},
+			Ingress: []secv1alpha1.Rule{
+				{
+					Action:    &allowAction,
+					Protocols: []secv1alpha1.NetworkPolicyProtocol{{IP: &secv1alpha1.IPProtocol{Protocol: ipProtocol}}},
+				},
+				{
+					Action: &dropAction,
+				},
+			},
+		},
+	}
+}
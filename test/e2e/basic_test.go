@@ -686,3 +686,129 @@ func TestGratuitousARP(t *testing.T) {
 	}
 	t.Logf("Got %d ARP packets after Pod was up", arpPackets)
 }
+
+// TestGatewayProxyARP verifies that in noEncap and hybrid modes proxy ARP is disabled on the gateway interface and
+// not enabled on the transport interface of every Node, so that the Node doesn't answer the ARP requests for the
+// addresses routed through other interfaces, and that ICMP redirects are disabled on both.
+func TestGatewayProxyARP(t *testing.T) {
+	data, err := setupTest(t)
+	if err != nil {
+		t.Fatalf("Error when setting up test: %v", err)
+	}
+	defer teardownTest(t, data)
+
+	encapMode, err := data.GetEncapMode()
+	if err != nil {
+		t.Fatalf("Failed to get encap mode: %v", err)
+	}
+	if !encapMode.SupportsNoEncap() {
+		t.Skipf("Skipping test as encap mode %s does not support noEncap", encapMode)
+	}
+
+	antreaGWName, err := data.GetGatewayInterfaceName(antreaNamespace)
+	if err != nil {
+		t.Fatalf("Failed to detect gateway interface name from ConfigMap: %v", err)
+	}
+	for idx := 0; idx < clusterInfo.numNodes; idx++ {
+		nodeName := nodeName(idx)
+		transportIntf, err := data.GetTransportInterfaceName(nodeName)
+		if err != nil {
+			t.Fatalf("Failed to detect transport interface of Node '%s': %v", nodeName, err)
+		}
+		expectedSysctls := map[string]string{
+			fmt.Sprintf("%s/proxy_arp", antreaGWName):       "0",
+			fmt.Sprintf("%s/send_redirects", antreaGWName):  "0",
+			fmt.Sprintf("%s/proxy_arp", transportIntf):      "0",
+			fmt.Sprintf("%s/send_redirects", transportIntf): "0",
+		}
+		for sysctl, expectedValue := range expectedSysctls {
+			cmd := fmt.Sprintf("cat /proc/sys/net/ipv4/conf/%s", sysctl)
+			rc, stdout, _, err := RunCommandOnNode(nodeName, cmd)
+			if err != nil || rc != 0 {
+				t.Fatalf("Error when reading %s on Node '%s': %v", sysctl, nodeName, err)
+			}
+			if value := strings.TrimSpace(stdout); value != expectedValue {
+				t.Errorf("Expected %s on Node '%s' to be %s, got %s", sysctl, nodeName, expectedValue, value)
+			}
+		}
+	}
+}
+
+// TestNoEncapNeighborProxy verifies that when noEncapNeighborProxy is enabled, a proxy neighbor entry is added on the
+// transport interface for the IP of each local Pod, and that a host on the Node network which resolves the Pod IP on
+// link, e.g. a host on a routable VLAN, can reach the Pod. The host is emulated by another Node with an on-link route
+// to the Pod IP.
+func TestNoEncapNeighborProxy(t *testing.T) {
+	skipIfNumNodesLessThan(t, 2)
+	data, err := setupTest(t)
+	if err != nil {
+		t.Fatalf("Error when setting up test: %v", err)
+	}
+	defer teardownTest(t, data)
+
+	encapMode, err := data.GetEncapMode()
+	if err != nil {
+		t.Fatalf("Failed to get encap mode: %v", err)
+	}
+	if !encapMode.SupportsNoEncap() {
+		t.Skipf("Skipping test as encap mode %s does not support noEncap", encapMode)
+	}
+
+	if err := data.mutateAntreaConfigMap(func(data map[string]string) {
+		antreaAgentConf, _ := data["antrea-agent.conf"]
+		antreaAgentConf = strings.Replace(antreaAgentConf, "#noEncapNeighborProxy: false", "noEncapNeighborProxy: true", 1)
+		data["antrea-agent.conf"] = antreaAgentConf
+	}, false, true); err != nil {
+		t.Fatalf("Failed to enable noEncapNeighborProxy: %v", err)
+	}
+	defer func() {
+		if err := data.mutateAntreaConfigMap(func(data map[string]string) {
+			antreaAgentConf, _ := data["antrea-agent.conf"]
+			antreaAgentConf = strings.Replace(antreaAgentConf, "noEncapNeighborProxy: true", "#noEncapNeighborProxy: false", 1)
+			data["antrea-agent.conf"] = antreaAgentConf
+		}, false, true); err != nil {
+			t.Errorf("Failed to disable noEncapNeighborProxy: %v", err)
+		}
+	}()
+
+	podNode := nodeName(0)
+	hostNode := nodeName(1)
+	_, podIP, cleanupFunc := createAndWaitForPod(t, data, data.createBusyboxPodOnNode, "test-pod-", podNode)
+	defer cleanupFunc()
+
+	transportIntf, err := data.GetTransportInterfaceName(podNode)
+	if err != nil {
+		t.Fatalf("Failed to detect transport interface of Node '%s': %v", podNode, err)
+	}
+	cmd := fmt.Sprintf("ip neigh show proxy %s dev %s", podIP, transportIntf)
+	if err := wait.Poll(time.Second, defaultTimeout, func() (bool, error) {
+		rc, stdout, _, err := RunCommandOnNode(podNode, cmd)
+		if err != nil || rc != 0 {
+			return false, fmt.Errorf("error when running '%s': %v", cmd, err)
+		}
+		return strings.Contains(stdout, podIP), nil
+	}); err != nil {
+		t.Fatalf("Proxy neighbor of Pod IP %s not found on Node '%s': %v", podIP, podNode, err)
+	}
+
+	hostIntf, err := data.GetTransportInterfaceName(hostNode)
+	if err != nil {
+		t.Fatalf("Failed to detect transport interface of Node '%s': %v", hostNode, err)
+	}
+	// The on-link route is more specific than the route to the Pod CIDR of the peer Node installed by Antrea, so
+	// the host resolves the Pod IP on the transport link, like a host on the same VLAN as the Pods.
+	cmd = fmt.Sprintf("ip route replace %s dev %s scope link", podIP, hostIntf)
+	if rc, _, stderr, err := RunCommandOnNode(hostNode, cmd); err != nil || rc != 0 {
+		t.Fatalf("Error when running '%s' on Node '%s': %v, %s", cmd, hostNode, err, stderr)
+	}
+	defer func() {
+		cmd := fmt.Sprintf("ip route del %s dev %s", podIP, hostIntf)
+		if rc, _, stderr, err := RunCommandOnNode(hostNode, cmd); err != nil || rc != 0 {
+			t.Errorf("Error when running '%s' on Node '%s': %v, %s", cmd, hostNode, err, stderr)
+		}
+	}()
+	cmd = fmt.Sprintf("ping -c 3 -W 2 %s", podIP)
+	if rc, stdout, stderr, err := RunCommandOnNode(hostNode, cmd); err != nil || rc != 0 {
+		t.Errorf("Pod IP %s is not reachable on link from Node '%s': %v, %s, %s", podIP, hostNode, err, stdout, stderr)
+	}
+}
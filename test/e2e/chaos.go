@@ -0,0 +1,150 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/vmware-tanzu/antrea/pkg/apis"
+)
+
+const (
+	// datapathRecoverySLA is the maximum time allowed for Pod connectivity to be restored after a
+	// failure has been injected.
+	datapathRecoverySLA = 30 * time.Second
+	// policyRecoverySLA is the maximum time allowed for NetworkPolicy changes made during a
+	// failure to be enforced once the failure has been recovered.
+	policyRecoverySLA = 60 * time.Second
+
+	// chaosIPTablesComment is used to identify the iptables rules installed by the failure
+	// injection functions, so that they can be removed reliably.
+	chaosIPTablesComment = "antrea-e2e-chaos"
+)
+
+// getOVSVswitchdPIDOnNode returns the PID of the ovs-vswitchd daemon running in the antrea-ovs
+// container on the provided Node. It returns an error if the daemon is not running.
+func (data *TestData) getOVSVswitchdPIDOnNode(nodeName string) (string, error) {
+	antreaPodName, err := data.getAntreaPodOnNode(nodeName)
+	if err != nil {
+		return "", err
+	}
+	cmd := []string{"/bin/sh", "-c", "pid=$(cat /var/run/openvswitch/ovs-vswitchd.pid) && kill -0 $pid && echo $pid"}
+	stdout, stderr, err := data.runCommandFromPod(antreaNamespace, antreaPodName, ovsContainerName, cmd)
+	if err != nil {
+		return "", fmt.Errorf("error when getting PID of ovs-vswitchd on Node '%s': %v - stdout: %s - stderr: %s", nodeName, err, stdout, stderr)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// killOVSVswitchdOnNode kills the ovs-vswitchd daemon running in the antrea-ovs container on the
+// provided Node. The daemon is expected to be restarted by the antrea-ovs container, which
+// periodically checks the status of the OVS daemons, and the Antrea Agent is expected to replay
+// the flows.
+func (data *TestData) killOVSVswitchdOnNode(nodeName string) error {
+	antreaPodName, err := data.getAntreaPodOnNode(nodeName)
+	if err != nil {
+		return err
+	}
+	cmd := []string{"/bin/sh", "-c", "kill -9 $(cat /var/run/openvswitch/ovs-vswitchd.pid)"}
+	if stdout, stderr, err := data.runCommandFromPod(antreaNamespace, antreaPodName, ovsContainerName, cmd); err != nil {
+		return fmt.Errorf("error when killing ovs-vswitchd on Node '%s': %v - stdout: %s - stderr: %s", nodeName, err, stdout, stderr)
+	}
+	return nil
+}
+
+// waitForOVSVswitchdRestartOnNode waits until ovs-vswitchd is running on the provided Node with
+// a PID different from oldPID, which shows that the daemon was killed and restarted. The new
+// daemon flushes the kernel datapath flows, so that the traffic is only forwarded again once the
+// Antrea Agent has replayed the flows.
+func (data *TestData) waitForOVSVswitchdRestartOnNode(nodeName string, oldPID string, timeout time.Duration) error {
+	if err := wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		pid, err := data.getOVSVswitchdPIDOnNode(nodeName)
+		return err == nil && pid != oldPID, nil
+	}); err != nil {
+		return fmt.Errorf("ovs-vswitchd was not restarted on Node '%s' within %v", nodeName, timeout)
+	}
+	return nil
+}
+
+// runIPTablesOnNode runs iptables with the provided arguments on the provided Node. iptables is
+// run in the antrea-agent container, which uses the host network and is privileged, so that we
+// do not depend on the provider to run it as root on the Node.
+func (data *TestData) runIPTablesOnNode(nodeName string, args ...string) error {
+	antreaPodName, err := data.getAntreaPodOnNode(nodeName)
+	if err != nil {
+		return err
+	}
+	cmd := append([]string{"iptables"}, args...)
+	if stdout, stderr, err := data.runCommandFromPod(antreaNamespace, antreaPodName, agentContainerName, cmd); err != nil {
+		return fmt.Errorf("error when running %v on Node '%s': %v - stdout: %s - stderr: %s", cmd, nodeName, err, stdout, stderr)
+	}
+	return nil
+}
+
+// dropControllerConnectivityOnNode installs an iptables rule on the provided Node to drop all
+// the traffic sent by the Antrea Agent to the Antrea Controller. It returns a function which
+// removes the rule to restore connectivity.
+func (data *TestData) dropControllerConnectivityOnNode(nodeName string) (restoreFn func() error, err error) {
+	ruleSpec := []string{
+		"OUTPUT", "-p", "tcp", "--dport", strconv.Itoa(apis.AntreaControllerAPIPort),
+		"-m", "comment", "--comment", chaosIPTablesComment, "-j", "DROP",
+	}
+	if err := data.runIPTablesOnNode(nodeName, append([]string{"-I"}, ruleSpec...)...); err != nil {
+		return nil, err
+	}
+	restoreFn = func() error {
+		return data.runIPTablesOnNode(nodeName, append([]string{"-D"}, ruleSpec...)...)
+	}
+	return restoreFn, nil
+}
+
+// restartKubeletOnNode restarts the kubelet service on the provided Node. It assumes that kubelet
+// is run with systemd.
+func restartKubeletOnNode(nodeName string) error {
+	cmd := "systemctl restart kubelet"
+	if testOptions.providerName != "kind" {
+		cmd = "sudo " + cmd
+	}
+	rc, stdout, stderr, err := RunCommandOnNode(nodeName, cmd)
+	if err != nil {
+		return fmt.Errorf("error when restarting kubelet on Node '%s': %v", nodeName, err)
+	}
+	if rc != 0 {
+		return fmt.Errorf("error when restarting kubelet on Node '%s', rc: %d - stdout: %s - stderr: %s", nodeName, rc, stdout, stderr)
+	}
+	return nil
+}
+
+// waitForPodConnectivity pings targetIP from the provided test Pod until the result matches
+// expectConnected or until the sla expires. It returns the time it took to reach the expected
+// state, or an error if the sla was not met.
+func (data *TestData) waitForPodConnectivity(podName string, targetIP string, expectConnected bool, sla time.Duration) (time.Duration, error) {
+	start := time.Now()
+	if err := wait.PollImmediate(time.Second, sla, func() (bool, error) {
+		err := data.runPingCommandFromTestPod(podName, targetIP, 1)
+		return (err == nil) == expectConnected, nil
+	}); err != nil {
+		if expectConnected {
+			return 0, fmt.Errorf("connectivity from Pod '%s' to '%s' was not restored within %v", podName, targetIP, sla)
+		}
+		return 0, fmt.Errorf("connectivity from Pod '%s' to '%s' was not blocked within %v", podName, targetIP, sla)
+	}
+	return time.Since(start), nil
+}
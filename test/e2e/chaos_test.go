@@ -0,0 +1,171 @@
+// Copyright 2020 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestChaosOVSVswitchdKill kills ovs-vswitchd on a Node and checks that Pod connectivity is
+// restored within datapathRecoverySLA, which requires OVS to be restarted and the flows to be
+// replayed by the Antrea Agent.
+func TestChaosOVSVswitchdKill(t *testing.T) {
+	skipIfProviderIs(t, "kind", "stopping OVS daemons create connectivity issues")
+	data, err := setupTest(t)
+	if err != nil {
+		t.Fatalf("Error when setting up test: %v", err)
+	}
+	defer teardownTest(t, data)
+
+	workerNode := workerNodeName(1)
+	podNames, podIPs, cleanupFn := createTestBusyboxPods(t, data, 2, workerNode)
+	defer cleanupFn()
+	if _, err := data.waitForPodConnectivity(podNames[0], podIPs[1], true, defaultTimeout); err != nil {
+		t.Fatalf("Error before injecting failure: %v", err)
+	}
+
+	oldPID, err := data.getOVSVswitchdPIDOnNode(workerNode)
+	if err != nil {
+		t.Fatalf("Error before injecting failure: %v", err)
+	}
+
+	t.Logf("Killing ovs-vswitchd (PID %s) on Node '%s'", oldPID, workerNode)
+	if err := data.killOVSVswitchdOnNode(workerNode); err != nil {
+		t.Fatalf("Error when injecting failure: %v", err)
+	}
+	start := time.Now()
+	// The kernel datapath flows survive the kill, so connectivity is only checked once the
+	// failure has taken effect, i.e. once ovs-vswitchd has been restarted with an empty
+	// datapath. The recovery time is measured from the kill.
+	if err := data.waitForOVSVswitchdRestartOnNode(workerNode, oldPID, datapathRecoverySLA); err != nil {
+		t.Fatalf("Error when injecting failure: %v", err)
+	}
+	restartTime := time.Since(start)
+	t.Logf("ovs-vswitchd restarted in %v", restartTime)
+	if _, err := data.waitForPodConnectivity(podNames[0], podIPs[1], true, datapathRecoverySLA-restartTime); err != nil {
+		t.Fatalf("Datapath did not recover: %v", err)
+	}
+	t.Logf("Datapath recovered in %v", time.Since(start))
+}
+
+// TestChaosControllerConnectivityLoss drops the traffic from an Antrea Agent to the Antrea
+// Controller while a NetworkPolicy is created, and checks that the NetworkPolicy is enforced
+// within policyRecoverySLA once connectivity is restored. It also checks that existing Pod
+// connectivity is not impacted while the Agent is disconnected.
+func TestChaosControllerConnectivityLoss(t *testing.T) {
+	data, err := setupTest(t)
+	if err != nil {
+		t.Fatalf("Error when setting up test: %v", err)
+	}
+	defer teardownTest(t, data)
+
+	workerNode := workerNodeName(1)
+	podNames, podIPs, cleanupFn := createTestBusyboxPods(t, data, 2, workerNode)
+	defer cleanupFn()
+	if _, err := data.waitForPodConnectivity(podNames[0], podIPs[1], true, defaultTimeout); err != nil {
+		t.Fatalf("Error before injecting failure: %v", err)
+	}
+
+	t.Logf("Dropping traffic to the Antrea Controller on Node '%s'", workerNode)
+	restoreFn, err := data.dropControllerConnectivityOnNode(workerNode)
+	if err != nil {
+		t.Fatalf("Error when injecting failure: %v", err)
+	}
+	restored := false
+	defer func() {
+		if !restored {
+			if err := restoreFn(); err != nil {
+				t.Errorf("Error when restoring connectivity to the Antrea Controller: %v", err)
+			}
+		}
+	}()
+
+	spec := &networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{"antrea-e2e": podNames[1]},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+	}
+	np, err := data.createNetworkPolicy("test-deny-ingress", spec)
+	if err != nil {
+		t.Fatalf("Error when creating NetworkPolicy: %v", err)
+	}
+	defer func() {
+		if err := data.deleteNetworkpolicy(np); err != nil {
+			t.Errorf("Error when deleting NetworkPolicy: %v", err)
+		}
+	}()
+
+	// Give some time to the NetworkPolicy to be processed by the Antrea Controller, existing
+	// connectivity should be preserved as the Agent cannot receive it.
+	time.Sleep(5 * time.Second)
+	if _, err := data.waitForPodConnectivity(podNames[0], podIPs[1], true, datapathRecoverySLA); err != nil {
+		t.Errorf("Datapath was impacted by the loss of connectivity to the Antrea Controller: %v", err)
+	}
+
+	t.Logf("Restoring traffic to the Antrea Controller on Node '%s'", workerNode)
+	if err := restoreFn(); err != nil {
+		t.Fatalf("Error when restoring connectivity to the Antrea Controller: %v", err)
+	}
+	restored = true
+	recoveryTime, err := data.waitForPodConnectivity(podNames[0], podIPs[1], false, policyRecoverySLA)
+	if err != nil {
+		t.Fatalf("NetworkPolicy state did not recover: %v", err)
+	}
+	t.Logf("NetworkPolicy state recovered in %v", recoveryTime)
+}
+
+// TestChaosKubeletRestart restarts kubelet on a Node and checks that Pod connectivity is
+// restored within datapathRecoverySLA and that the Antrea Agent is not restarted.
+func TestChaosKubeletRestart(t *testing.T) {
+	data, err := setupTest(t)
+	if err != nil {
+		t.Fatalf("Error when setting up test: %v", err)
+	}
+	defer teardownTest(t, data)
+
+	workerNode := workerNodeName(1)
+	podNames, podIPs, cleanupFn := createTestBusyboxPods(t, data, 2, workerNode)
+	defer cleanupFn()
+	if _, err := data.waitForPodConnectivity(podNames[0], podIPs[1], true, defaultTimeout); err != nil {
+		t.Fatalf("Error before injecting failure: %v", err)
+	}
+	restartCount, err := data.getAgentContainersRestartCount()
+	if err != nil {
+		t.Fatalf("Error when getting restart count of Antrea Agent containers: %v", err)
+	}
+
+	t.Logf("Restarting kubelet on Node '%s'", workerNode)
+	if err := restartKubeletOnNode(workerNode); err != nil {
+		t.Fatalf("Error when injecting failure: %v", err)
+	}
+	recoveryTime, err := data.waitForPodConnectivity(podNames[0], podIPs[1], true, datapathRecoverySLA)
+	if err != nil {
+		t.Fatalf("Datapath did not recover: %v", err)
+	}
+	t.Logf("Datapath recovered in %v", recoveryTime)
+
+	newRestartCount, err := data.getAgentContainersRestartCount()
+	if err != nil {
+		t.Fatalf("Error when getting restart count of Antrea Agent containers: %v", err)
+	}
+	if newRestartCount != restartCount {
+		t.Errorf("Antrea Agent containers were restarted after kubelet restart")
+	}
+}
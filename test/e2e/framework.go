@@ -329,7 +329,7 @@ func (data *TestData) deployAntreaFlowExporter(ipfixCollector string) error {
 		antreaAgentConf = strings.Replace(antreaAgentConf, "#  FlowExporter: false", "  FlowExporter: true", 1)
 		antreaAgentConf = strings.Replace(antreaAgentConf, "#flowCollectorAddr: \"\"", fmt.Sprintf("flowCollectorAddr: \"%s\"", ipfixCollector), 1)
 		antreaAgentConf = strings.Replace(antreaAgentConf, "#flowPollInterval: \"5s\"", "flowPollInterval: \"1s\"", 1)
-		antreaAgentConf = strings.Replace(antreaAgentConf, "#flowExportFrequency: 12", "flowExportFrequency: 5", 1)
+		antreaAgentConf = strings.Replace(antreaAgentConf, "#activeFlowExportTimeout: \"60s\"", "activeFlowExportTimeout: \"5s\"", 1)
 		data["antrea-agent.conf"] = antreaAgentConf
 	}, false, true)
 }
@@ -1178,6 +1178,35 @@ func (data *TestData) GetGatewayInterfaceName(antreaNamespace string) (string, e
 	return antreaDefaultGW, nil
 }
 
+// GetTransportInterfaceName returns the name of the interface which holds the internal IP of the Node.
+func (data *TestData) GetTransportInterfaceName(nodeName string) (string, error) {
+	node, err := data.clientset.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	var nodeIP string
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			nodeIP = address.Address
+			break
+		}
+	}
+	if nodeIP == "" {
+		return "", fmt.Errorf("internal IP of Node '%s' not found", nodeName)
+	}
+	// The output is like "2: eth0    inet 192.168.1.2/24 brd 192.168.1.255 scope global eth0 ...".
+	cmd := fmt.Sprintf("ip -o addr show to %s", nodeIP)
+	rc, stdout, stderr, err := RunCommandOnNode(nodeName, cmd)
+	if err != nil || rc != 0 {
+		return "", fmt.Errorf("error when running '%s' on Node '%s': %v, stderr: %s", cmd, nodeName, err, stderr)
+	}
+	fields := strings.Fields(stdout)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("interface of IP %s not found on Node '%s'", nodeIP, nodeName)
+	}
+	return fields[1], nil
+}
+
 func (data *TestData) mutateAntreaConfigMap(mutatingFunc func(data map[string]string), restartController, restartAgent bool) error {
 	configMap, err := data.GetAntreaConfigMap(antreaNamespace)
 	if err != nil {
@@ -570,6 +570,7 @@ func newTester() *cmdAddDelTester {
 		k8sFake.NewSimpleClientset(),
 		make(chan v1beta1.PodReference, 100),
 		false,
+		routeMock,
 		nil)
 	tester.server.Initialize(ovsServiceMock, ofServiceMock, ifaceStore, "")
 	ctx := context.Background()
@@ -597,7 +598,7 @@ func cmdAddDelCheckTest(testNS ns.NetNS, tc testCase, dataDir string) {
 	tester.setNS(testNS, targetNS)
 
 	ipamResult := ipamtest.GenerateIPAMResult("0.4.0", tc.addresses, tc.Routes, tc.DNS)
-	ipamMock.EXPECT().Add(mock.Any(), mock.Any()).Return(ipamResult, nil).AnyTimes()
+	ipamMock.EXPECT().Add(mock.Any(), mock.Any(), mock.Any()).Return(true, ipamResult, nil).AnyTimes()
 
 	// Mock ovs output while get ovs port external configuration
 	ovsPortname := util.GenerateContainerInterfaceName(testPod, testPodNamespace, ContainerID)
@@ -605,6 +606,7 @@ func cmdAddDelCheckTest(testNS ns.NetNS, tc testCase, dataDir string) {
 	ovsServiceMock.EXPECT().CreatePort(ovsPortname, ovsPortname, mock.Any()).Return(ovsPortUUID, nil).AnyTimes()
 	ovsServiceMock.EXPECT().GetOFPort(ovsPortname).Return(int32(10), nil).AnyTimes()
 	ofServiceMock.EXPECT().InstallPodFlows(ovsPortname, mock.Any(), mock.Any(), mock.Any(), mock.Any()).Return(nil)
+	routeMock.EXPECT().AddPodNeighborProxy(mock.Any()).Return(nil)
 
 	// Test ip allocation
 	prevResult, err := tester.cmdAddTest(tc, dataDir)
@@ -629,6 +631,7 @@ func cmdAddDelCheckTest(testNS ns.NetNS, tc testCase, dataDir string) {
 
 	// Test delete
 	ovsServiceMock.EXPECT().DeletePort(ovsPortUUID).Return(nil).AnyTimes()
+	routeMock.EXPECT().DeletePodNeighborProxy(mock.Any()).Return(nil)
 	ofServiceMock.EXPECT().UninstallPodFlows(ovsPortname).Return(nil)
 	tester.cmdDelTest(tc, dataDir)
 
@@ -649,6 +652,7 @@ func TestAntreaServerFunc(t *testing.T) {
 	_ = ipam.RegisterIPAMDriver("mock", ipamMock)
 	ovsServiceMock = ovsconfigtest.NewMockOVSBridgeClient(controller)
 	ofServiceMock = openflowtest.NewMockClient(controller)
+	routeMock = routetest.NewMockInterface(controller)
 
 	var originalNS ns.NetNS
 	var dataDir string
@@ -662,8 +666,8 @@ func TestAntreaServerFunc(t *testing.T) {
 		dataDir, err = ioutil.TempDir("", "antrea_server_test")
 		require.Nil(t, err)
 
-		ipamMock.EXPECT().Del(mock.Any(), mock.Any()).Return(nil).AnyTimes()
-		ipamMock.EXPECT().Check(mock.Any(), mock.Any()).Return(nil).AnyTimes()
+		ipamMock.EXPECT().Del(mock.Any(), mock.Any(), mock.Any()).Return(true, nil).AnyTimes()
+		ipamMock.EXPECT().Check(mock.Any(), mock.Any(), mock.Any()).Return(true, nil).AnyTimes()
 
 		ovsServiceMock.EXPECT().GetPortList().Return([]ovsconfig.OVSPortData{}, nil).AnyTimes()
 		ovsServiceMock.EXPECT().IsHardwareOffloadEnabled().Return(false).AnyTimes()
@@ -726,7 +730,8 @@ func setupChainTest(
 			k8sFake.NewSimpleClientset(),
 			make(chan v1beta1.PodReference, 100),
 			true,
-			routeMock)
+			routeMock,
+			nil)
 	} else {
 		server = inServer
 	}
@@ -815,6 +820,7 @@ func TestCNIServerChaining(t *testing.T) {
 			ovsServiceMock.EXPECT().CreatePort(ovsPortname, ovsPortname, mock.Any()).Return(ovsPortUUID, nil),
 			ovsServiceMock.EXPECT().GetOFPort(ovsPortname).Return(testContainerOFPort, nil),
 			ofServiceMock.EXPECT().InstallPodFlows(ovsPortname, podIP, containerIntf.HardwareAddr, gwMAC, mock.Any()),
+			routeMock.EXPECT().AddPodNeighborProxy(podIP),
 		)
 		mock.InOrder(orderedCalls...)
 		cniResp, err := server.CmdAdd(ctx, cniReq)
@@ -826,6 +832,7 @@ func TestCNIServerChaining(t *testing.T) {
 		orderedCalls = nil
 		orderedCalls = append(orderedCalls,
 			routeMock.EXPECT().UnMigrateRoutesFromGw(containterHostRt, ""),
+			routeMock.EXPECT().DeletePodNeighborProxy(podIP),
 			ofServiceMock.EXPECT().UninstallPodFlows(ovsPortname),
 			ovsServiceMock.EXPECT().DeletePort(ovsPortUUID),
 		)
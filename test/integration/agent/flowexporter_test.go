@@ -19,7 +19,11 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/openflow"
 )
 
-const testPollInterval = 0 // Not used in the test, hence 0.
+const (
+	testPollInterval      = 0 // Not used in the test, hence 0.
+	testActiveFlowTimeout = 60 * time.Second
+	testIdleFlowTimeout   = 15 * time.Second
+)
 
 func makeTuple(srcIP *net.IP, dstIP *net.IP, protoID uint8, srcPort uint16, dstPort uint16) (*flowexporter.Tuple, *flowexporter.Tuple) {
 	tuple := &flowexporter.Tuple{
@@ -126,9 +130,9 @@ func TestConnectionStoreAndFlowRecords(t *testing.T) {
 	connDumperMock := connectionstest.NewMockConnTrackDumper(ctrl)
 	ifStoreMock := interfacestoretest.NewMockInterfaceStore(ctrl)
 	// TODO: Enhance the integration test by testing service.
-	connStore := connections.NewConnectionStore(connDumperMock, ifStoreMock, nil, nil, testPollInterval)
+	connStore := connections.NewConnectionStore(connDumperMock, ifStoreMock, nil, nil, "", nil, nil, testPollInterval, 0, 0, nil, nil)
 	// Expect calls for connStore.poll and other callees
-	connDumperMock.EXPECT().DumpFlows(uint16(openflow.CtZone)).Return(testConns, 0, nil)
+	connDumperMock.EXPECT().DumpFlows(openflow.GetCtZone(openflow.CtZoneFeaturePipeline)).Return(testConns, 0, nil)
 	connDumperMock.EXPECT().GetMaxConnections().Return(0, nil)
 	for i, testConn := range testConns {
 		if i == 0 {
@@ -161,6 +165,6 @@ func TestConnectionStoreAndFlowRecords(t *testing.T) {
 	}
 
 	// Test for build flow records
-	flowRecords := flowrecords.NewFlowRecords(connStore)
+	flowRecords := flowrecords.NewFlowRecords(connStore, testActiveFlowTimeout, testIdleFlowTimeout)
 	testBuildFlowRecords(t, flowRecords, testConns, testConnKeys)
 }
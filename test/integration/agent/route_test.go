@@ -32,6 +32,8 @@ import (
 	"github.com/vmware-tanzu/antrea/pkg/agent/route"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util"
 	"github.com/vmware-tanzu/antrea/pkg/agent/util/ipset"
+	"github.com/vmware-tanzu/antrea/pkg/agent/util/sysctl"
+	binding "github.com/vmware-tanzu/antrea/pkg/ovs/openflow"
 )
 
 func ExecOutputTrim(cmd string) (string, error) {
@@ -101,7 +103,7 @@ func TestInitialize(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Logf("Running Initialize test with mode %s node config %s", tc.mode, nodeConfig)
-		routeClient, err := route.NewClient(serviceCIDR, tc.mode)
+		routeClient, err := route.NewClient(serviceCIDR, tc.mode, false, false, false)
 		if err != nil {
 			t.Error(err)
 		}
@@ -145,6 +147,19 @@ func TestInitialize(t *testing.T) {
 			assert.NoError(t, err, "error executing iptables-save")
 			assert.Equal(t, expectedData, string(actualData), "mismatch iptables data in table %s", table)
 		}
+
+		// verify proxy ARP is disabled on gateway, and never enabled on transport interface
+		proxyARP, err := sysctl.GetSysctlNet(fmt.Sprintf("ipv4/conf/%s/proxy_arp", gwName))
+		assert.NoError(t, err, "error getting proxy_arp of gateway")
+		assert.Equal(t, 0, proxyARP, "proxy_arp of gateway should be disabled")
+		proxyARP, err = sysctl.GetSysctlNet(fmt.Sprintf("ipv4/conf/%s/proxy_arp", nodeIntf.Name))
+		assert.NoError(t, err, "error getting proxy_arp of transport interface")
+		assert.Equal(t, 0, proxyARP, "proxy_arp of transport interface should not be enabled")
+		if tc.mode.SupportsNoEncap() {
+			sendRedirects, err := sysctl.GetSysctlNet(fmt.Sprintf("ipv4/conf/%s/send_redirects", nodeIntf.Name))
+			assert.NoError(t, err, "error getting send_redirects of transport interface")
+			assert.Equal(t, 0, sendRedirects, "send_redirects of transport interface should be disabled")
+		}
 	}
 }
 
@@ -174,7 +189,7 @@ func TestAddAndDeleteRoutes(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Logf("Running test with mode %s peer cidr %s peer ip %s node config %s", tc.mode, tc.peerCIDR, tc.peerIP, nodeConfig)
-		routeClient, err := route.NewClient(serviceCIDR, tc.mode)
+		routeClient, err := route.NewClient(serviceCIDR, tc.mode, false, false, false)
 		if err != nil {
 			t.Error(err)
 		}
@@ -184,7 +199,7 @@ func TestAddAndDeleteRoutes(t *testing.T) {
 
 		_, peerCIDR, _ := net.ParseCIDR(tc.peerCIDR)
 		nhCIDRIP := ip.NextIP(peerCIDR.IP)
-		if err := routeClient.AddRoutes(peerCIDR, tc.peerIP, nhCIDRIP); err != nil {
+		if err := routeClient.AddRoutes(peerCIDR, tc.peerIP, nhCIDRIP, tc.mode); err != nil {
 			t.Errorf("route add failed with err %v", err)
 		}
 
@@ -224,6 +239,98 @@ func TestAddAndDeleteRoutes(t *testing.T) {
 	}
 }
 
+func TestAddAndDeletePodNeighborProxy(t *testing.T) {
+	if _, incontainer := os.LookupEnv("INCONTAINER"); !incontainer {
+		// test changes file system, routing table. Run in contain only
+		t.Skipf("Skip test runs only in container")
+	}
+
+	gwLink := createDummyGW(t)
+	defer netlink.LinkDel(gwLink)
+
+	podIP := net.ParseIP("10.10.10.2")
+	tcs := []struct {
+		// variations
+		mode                 config.TrafficEncapModeType
+		neighborProxyEnabled bool
+		// expectations
+		expectedProxy bool
+	}{
+		{mode: config.TrafficEncapModeNoEncap, neighborProxyEnabled: true, expectedProxy: true},
+		{mode: config.TrafficEncapModeHybrid, neighborProxyEnabled: true, expectedProxy: true},
+		{mode: config.TrafficEncapModeNoEncap, neighborProxyEnabled: false, expectedProxy: false},
+		{mode: config.TrafficEncapModeEncap, neighborProxyEnabled: true, expectedProxy: false},
+	}
+
+	for _, tc := range tcs {
+		t.Logf("Running test with mode %s neighbor proxy %t", tc.mode, tc.neighborProxyEnabled)
+		routeClient, err := route.NewClient(serviceCIDR, tc.mode, false, false, tc.neighborProxyEnabled)
+		if err != nil {
+			t.Error(err)
+		}
+		if err := routeClient.Initialize(nodeConfig); err != nil {
+			t.Error(err)
+		}
+
+		// #nosec G204: ignore in test code
+		showProxy := fmt.Sprintf("ip neigh show proxy %s dev %s", podIP, nodeIntf.Name)
+		assert.NoError(t, routeClient.AddPodNeighborProxy(podIP), "adding proxy neighbor failed")
+		output, err := exec.Command("bash", "-c", showProxy).Output()
+		assert.NoError(t, err, "error executing ip neigh command")
+		if tc.expectedProxy {
+			assert.Contains(t, string(output), podIP.String(), "proxy neighbor should be added")
+		} else {
+			assert.Empty(t, string(output), "proxy neighbor should not be added")
+		}
+		proxyARP, err := sysctl.GetSysctlNet(fmt.Sprintf("ipv4/conf/%s/proxy_arp", nodeIntf.Name))
+		assert.NoError(t, err, "error getting proxy_arp of transport interface")
+		assert.Equal(t, 0, proxyARP, "proxy_arp of transport interface should not be enabled")
+
+		assert.NoError(t, routeClient.DeletePodNeighborProxy(podIP), "deleting proxy neighbor failed")
+		output, err = exec.Command("bash", "-c", showProxy).Output()
+		assert.NoError(t, err, "error executing ip neigh command")
+		assert.Empty(t, string(output), "proxy neighbor should be deleted")
+		// Deleting it again should not fail.
+		assert.NoError(t, routeClient.DeletePodNeighborProxy(podIP), "deleting proxy neighbor again failed")
+	}
+}
+
+func TestAddAndDeleteSNATRule(t *testing.T) {
+	if _, incontainer := os.LookupEnv("INCONTAINER"); !incontainer {
+		// test changes file system, routing table. Run in contain only
+		t.Skipf("Skip test runs only in container")
+	}
+
+	gwLink := createDummyGW(t)
+	defer netlink.LinkDel(gwLink)
+
+	routeClient, err := route.NewClient(serviceCIDR, config.TrafficEncapModeEncap, false, false, false)
+	assert.NoError(t, err)
+	assert.NoError(t, routeClient.Initialize(nodeConfig))
+
+	podIP := net.ParseIP("10.10.10.2")
+	snatIP := net.ParseIP("192.168.1.100")
+	getSNATRules := func() string {
+		// #nosec G204: ignore in test code
+		output, _ := exec.Command("bash", "-c", "iptables-save -t nat | grep -- '-A ANTREA-EGRESS'").Output()
+		return string(output)
+	}
+
+	assert.NoError(t, routeClient.AddSNATRule(podIP, snatIP, nil))
+	assert.Equal(t, `-A ANTREA-EGRESS -s 10.10.10.2/32 -m set ! --match-set ANTREA-POD-IP dst -j SNAT --to-source 192.168.1.100
+`, getSNATRules())
+
+	// The TCP and UDP packets are SNAT'd within the port range, before the rule matching the other protocols.
+	assert.NoError(t, routeClient.AddSNATRule(podIP, snatIP, &binding.PortRange{StartPort: 1024, EndPort: 2047}))
+	assert.Equal(t, `-A ANTREA-EGRESS -s 10.10.10.2/32 -p tcp -m set ! --match-set ANTREA-POD-IP dst -j SNAT --to-source 192.168.1.100:1024-2047
+-A ANTREA-EGRESS -s 10.10.10.2/32 -p udp -m set ! --match-set ANTREA-POD-IP dst -j SNAT --to-source 192.168.1.100:1024-2047
+-A ANTREA-EGRESS -s 10.10.10.2/32 -m set ! --match-set ANTREA-POD-IP dst -j SNAT --to-source 192.168.1.100
+`, getSNATRules())
+
+	assert.NoError(t, routeClient.DeleteSNATRule(podIP))
+	assert.Equal(t, "", getSNATRules())
+}
+
 func TestReconcile(t *testing.T) {
 	if _, incontainer := os.LookupEnv("INCONTAINER"); !incontainer {
 		// test changes file system, routing table. Run in contain only
@@ -278,7 +385,7 @@ func TestReconcile(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Logf("Running test with mode %s added routes %v desired routes %v", tc.mode, tc.addedRoutes, tc.desiredPeerCIDRs)
-		routeClient, err := route.NewClient(serviceCIDR, tc.mode)
+		routeClient, err := route.NewClient(serviceCIDR, tc.mode, false, false, false)
 		if err != nil {
 			t.Error(err)
 		}
@@ -289,7 +396,7 @@ func TestReconcile(t *testing.T) {
 		for _, route := range tc.addedRoutes {
 			_, peerNet, _ := net.ParseCIDR(route.peerCIDR)
 			peerGwIP := ip.NextIP(peerNet.IP)
-			if err := routeClient.AddRoutes(peerNet, route.peerIP, peerGwIP); err != nil {
+			if err := routeClient.AddRoutes(peerNet, route.peerIP, peerGwIP, tc.mode); err != nil {
 				t.Errorf("route add failed with err %v", err)
 			}
 		}
@@ -326,7 +433,7 @@ func TestRouteTablePolicyOnly(t *testing.T) {
 	gwLink := createDummyGW(t)
 	defer netlink.LinkDel(gwLink)
 
-	routeClient, err := route.NewClient(serviceCIDR, config.TrafficEncapModeNetworkPolicyOnly)
+	routeClient, err := route.NewClient(serviceCIDR, config.TrafficEncapModeNetworkPolicyOnly, false, false, false)
 	if err != nil {
 		t.Error(err)
 	}
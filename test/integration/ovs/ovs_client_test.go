@@ -18,6 +18,7 @@ import (
 	"crypto/rand"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"testing"
@@ -209,6 +210,50 @@ func TestOVSOtherConfig(t *testing.T) {
 	require.Equal(t, map[string]string{"foo1": "bar1", "foo2": "bar2"}, gotOtherConfigs, "other_config mismatched")
 }
 
+// TestOVSBridgeOtherConfig tests adding and getting other_config of the OVS bridge.
+func TestOVSBridgeOtherConfig(t *testing.T) {
+	data := &testData{}
+	data.setup(t)
+	defer data.teardown(t)
+
+	err := data.br.AddBridgeOtherConfig(map[string]interface{}{"hwaddr": "02:00:00:00:00:01", "foo1": "bar1"})
+	require.Nil(t, err, "Error when adding bridge other_config")
+
+	// Expect only the new config "foo2: bar2" will be added.
+	err = data.br.AddBridgeOtherConfig(map[string]interface{}{"hwaddr": "02:00:00:00:00:02", "foo2": "bar2"})
+	require.Nil(t, err, "Error when adding bridge other_config")
+
+	gotOtherConfigs, err := data.br.GetBridgeOtherConfig()
+	require.Nil(t, err, "Error when getting bridge other_config")
+	assert.Equal(t, "02:00:00:00:00:01", gotOtherConfigs["hwaddr"])
+	assert.Equal(t, "bar1", gotOtherConfigs["foo1"])
+	assert.Equal(t, "bar2", gotOtherConfigs["foo2"])
+}
+
+// TestOVSInterfaceMAC tests setting and getting the MAC address of an OVS internal interface.
+func TestOVSInterfaceMAC(t *testing.T) {
+	data := &testData{}
+	data.setup(t)
+	defer data.teardown(t)
+
+	deleteAllPorts(t, data.br)
+	testCreatePort(t, data.br, "p1", "internal")
+
+	mac, err := data.br.GetInterfaceMAC("p1")
+	require.Nil(t, err, "Error when getting interface MAC")
+	assert.Nil(t, mac)
+
+	expectedMAC, _ := net.ParseMAC("02:00:00:00:00:01")
+	err = data.br.SetInterfaceMAC("p1", expectedMAC)
+	require.Nil(t, err, "Error when setting interface MAC")
+
+	mac, err = data.br.GetInterfaceMAC("p1")
+	require.Nil(t, err, "Error when getting interface MAC")
+	assert.Equal(t, expectedMAC, mac)
+
+	deleteAllPorts(t, data.br)
+}
+
 func TestTunnelOptionCsum(t *testing.T) {
 	testCases := map[string]struct {
 		initialCsum bool
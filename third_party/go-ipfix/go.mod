@@ -0,0 +1,9 @@
+module github.com/vmware/go-ipfix
+
+go 1.13
+
+require (
+	github.com/golang/mock v1.4.3
+	github.com/stretchr/testify v1.5.1
+	k8s.io/klog v1.0.0
+)